@@ -4,7 +4,8 @@ import (
 	"github.com/rasmartins/typemux/internal/annotations"
 )
 
-// AnnotationMetadata describes a built-in TypeMUX annotation.
+// AnnotationMetadata describes a built-in TypeMUX annotation, or an annotation
+// registered with RegisterAnnotationNamespace.
 type AnnotationMetadata struct {
 	// Name of the annotation (e.g., "@required", "@deprecated")
 	Name string
@@ -105,7 +106,8 @@ func FilterAnnotationsByFormat(format string) []*AnnotationMetadata {
 	return result
 }
 
-// GetAnnotation returns metadata for a specific annotation by name.
+// GetAnnotation returns metadata for a specific annotation by name, including
+// the annotations registered with RegisterAnnotationNamespace.
 //
 // Example:
 //
@@ -123,9 +125,65 @@ func GetAnnotation(name string) (*AnnotationMetadata, bool) {
 		}
 	}
 
+	if ann, ok := annotations.GetExtension(name); ok {
+		return convertAnnotationMetadata(ann), true
+	}
+
 	return nil, false
 }
 
+// RegisterAnnotationNamespace registers the annotations of a namespace owned by a
+// plugin or custom generator, such as @mycorp.audit(level="high"). Names must
+// start with "@" and the namespace, and the namespaces of built-in annotations
+// are reserved. Registering a namespace again replaces its annotations.
+//
+// The parser then accepts the annotations on the elements of their Scope, with
+// positional or name=value arguments, and reports unknown parameters, missing
+// required parameters, values of the wrong Type ("string", "number", or
+// "boolean"), and values outside ValidValues as errors. The arguments are
+// stored in the Extensions of the annotations of the element.
+//
+// Example:
+//
+//	err := typemux.RegisterAnnotationNamespace("mycorp", []*typemux.AnnotationMetadata{{
+//	    Name:       "@mycorp.audit",
+//	    Scope:      []string{"type", "method"},
+//	    Parameters: []typemux.AnnotationParameter{{Name: "level", Type: "string", Required: true}},
+//	}})
+//
+//	// In a custom generator:
+//	if args, ok := typ.Annotations.Extension("mycorp.audit"); ok {
+//	    fmt.Println(args["level"])
+//	}
+func RegisterAnnotationNamespace(namespace string, metas []*AnnotationMetadata) error {
+	internal := make([]*annotations.AnnotationMetadata, len(metas))
+	for i, meta := range metas {
+		internal[i] = &annotations.AnnotationMetadata{
+			Name:        meta.Name,
+			Scope:       meta.Scope,
+			Formats:     meta.Formats,
+			Description: meta.Description,
+			Examples:    meta.Examples,
+			Parameters:  make([]annotations.ParameterMetadata, len(meta.Parameters)),
+		}
+		for j, param := range meta.Parameters {
+			internal[i].Parameters[j] = annotations.ParameterMetadata{
+				Name:        param.Name,
+				Type:        param.Type,
+				Required:    param.Required,
+				Description: param.Description,
+				ValidValues: param.ValidValues,
+			}
+		}
+	}
+	return annotations.RegisterNamespace(namespace, internal)
+}
+
+// UnregisterAnnotationNamespace removes a namespace registered with RegisterAnnotationNamespace.
+func UnregisterAnnotationNamespace(namespace string) {
+	annotations.UnregisterNamespace(namespace)
+}
+
 // convertAnnotationMetadata converts internal annotation metadata to public API type.
 func convertAnnotationMetadata(ann *annotations.AnnotationMetadata) *AnnotationMetadata {
 	result := &AnnotationMetadata{
@@ -0,0 +1,17 @@
+// Command typemux-lsp is a Language Server Protocol server for TypeMUX schema files.
+// It speaks JSON-RPC over stdin and stdout and logs to stderr.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rasmartins/typemux/internal/lsp"
+)
+
+func main() {
+	if err := lsp.NewServer(os.Stderr).Run(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,80 @@
+//go:build js && wasm
+
+// Command typemux-wasm is the TypeMUX compiler built for WebAssembly, for web
+// playgrounds that generate code in the browser. It registers a global function
+//
+//	typemuxCompile(schema, options)
+//
+// taking the schema content and an optional object with the options of
+// POST /v1/generate of typemux serve (files, annotations, formats, ...). It
+// returns {files, warnings} or {error, diagnostics}. Imports resolve only
+// against the files passed with the schema and the standard library.
+//
+// Build it with make wasm, and load it with the wasm_exec.js of the Go
+// distribution.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+
+	"github.com/rasmartins/typemux/internal/compile"
+	"github.com/rasmartins/typemux/internal/diagnostic"
+	"github.com/rasmartins/typemux/internal/loader"
+)
+
+// limits bounds the schemas compiled in the browser, as typemux serve does by default
+var limits = loader.Limits{
+	MaxFileSize:         1 << 20,
+	MaxImportDepth:      8,
+	MaxTypes:            2000,
+	MaxFields:           1000,
+	MaxAnnotationLength: 64 << 10,
+}
+
+// failure is the result of a failed compilation
+type failure struct {
+	Error       string                  `json:"error"`
+	Diagnostics []diagnostic.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+func main() {
+	js.Global().Set("typemuxCompile", js.FuncOf(compileSchema))
+	select {}
+}
+
+// compileSchema is typemuxCompile: it compiles the schema of its first argument
+// with the options of its second and returns the result as a JS object
+func compileSchema(_ js.Value, args []js.Value) interface{} {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return toJS(failure{Error: "typemuxCompile(schema, options): schema must be a string"})
+	}
+
+	var opts compile.Options
+	if len(args) > 1 && args[1].Type() == js.TypeObject {
+		encoded := js.Global().Get("JSON").Call("stringify", args[1]).String()
+		if err := json.Unmarshal([]byte(encoded), &opts); err != nil {
+			return toJS(failure{Error: fmt.Sprintf("invalid options: %v", err)})
+		}
+	}
+	opts.Limits = limits
+
+	result, err := compile.Compile(context.Background(), args[0].String(), opts)
+	if err != nil {
+		var f failure
+		f.Error, f.Diagnostics = compile.Failure(err)
+		return toJS(f)
+	}
+	return toJS(result)
+}
+
+// toJS converts a value to a JS object through JSON
+func toJS(value interface{}) js.Value {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		encoded, _ = json.Marshal(failure{Error: err.Error()}) //nolint:errcheck // a failure always encodes
+	}
+	return js.Global().Get("JSON").Call("parse", string(encoded))
+}
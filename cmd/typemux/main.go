@@ -1,26 +1,194 @@
 package main
 
 import (
+	"archive/tar"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log"
+	"net/http"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/rasmartins/typemux/internal/annotations"
 	"github.com/rasmartins/typemux/internal/ast"
 	"github.com/rasmartins/typemux/internal/config"
+	"github.com/rasmartins/typemux/internal/diagnostic"
 	"github.com/rasmartins/typemux/internal/diff"
 	"github.com/rasmartins/typemux/internal/docgen"
 	"github.com/rasmartins/typemux/internal/generator"
+	"github.com/rasmartins/typemux/internal/graph"
+	"github.com/rasmartins/typemux/internal/importers/fidelity"
 	"github.com/rasmartins/typemux/internal/lexer"
+	"github.com/rasmartins/typemux/internal/loader"
+	"github.com/rasmartins/typemux/internal/lockfile"
+	"github.com/rasmartins/typemux/internal/logging"
+	"github.com/rasmartins/typemux/internal/merge"
+	"github.com/rasmartins/typemux/internal/naming"
+	"github.com/rasmartins/typemux/internal/parsecache"
 	"github.com/rasmartins/typemux/internal/parser"
+	"github.com/rasmartins/typemux/internal/roundtrip"
+	"github.com/rasmartins/typemux/internal/server"
+	"github.com/rasmartins/typemux/internal/stdlib"
+	"github.com/rasmartins/typemux/internal/textdiff"
+	"github.com/rasmartins/typemux/internal/verify"
 )
 
 // CurrentTypeMUXVersion is the TypeMUX IDL version supported by this compiler.
 const CurrentTypeMUXVersion = "1.0.0"
 
+// strictMode reports warnings as errors (set by -strict)
+var strictMode bool
+
+// warningCount counts the warnings reported so far
+var warningCount int
+
+// logger prints progress messages to stderr at the verbosity set by -q, -v, and -vv
+var logger = logging.New(os.Stderr, logging.Normal)
+
+// warningsMuted silences warnings, e.g. while parsing a baseline schema
+var warningsMuted bool
+
+// errorFormat is the format of errors and warnings: text, or json for editors and CI (set by -error-format)
+var errorFormat = "text"
+
+// diagnostics collects the warnings reported so far in the json error format
+var diagnostics []diagnostic.Diagnostic
+
+// reportWarning prints a warning, labelled as an error in strict mode
+func reportWarning(format string, args ...interface{}) {
+	reportDiagnostic(diagnostic.Diagnostic{Message: fmt.Sprintf(format, args...)})
+}
+
+// reportFileWarning reports a warning of a schema file, which may start with its position
+func reportFileWarning(path, warning string) {
+	if errorFormat == "json" {
+		reportDiagnostic(diagnostic.FromText(path, warning, diagnostic.SeverityWarning))
+		return
+	}
+	reportWarning("%s: %s", path, warning)
+}
+
+// reportDiagnostic reports a warning, printed at once as text or kept for the
+// json error format, with the severity of an error in strict mode
+func reportDiagnostic(d diagnostic.Diagnostic) {
+	if warningsMuted {
+		return
+	}
+	warningCount++
+	d.Severity = diagnostic.SeverityWarning
+	label := "Warning"
+	if strictMode {
+		d.Severity = diagnostic.SeverityError
+		label = "Error"
+	}
+	if errorFormat == "json" {
+		diagnostics = append(diagnostics, d)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s: %s\n", label, d.Message)
+}
+
+// addErrorFormatFlag adds the -error-format flag to a command
+func addErrorFormatFlag(flags *flag.FlagSet) {
+	flags.StringVar(&errorFormat, "error-format", "text", "Format of errors and warnings: text, or json (written to stderr) for editors and CI")
+}
+
+// addVerbosityFlags adds the -q, -v, and -vv flags to a command and returns the
+// function that sets the level of the logger from them once the flags are parsed
+func addVerbosityFlags(flags *flag.FlagSet) func() {
+	quiet := flags.Bool("q", false, "Quiet: print errors and warnings only")
+	verbose := flags.Bool("v", false, "Verbose: also print how long parsing and each generator took")
+	debug := flags.Bool("vv", false, "Debug: also print parse cache use and every file written")
+	return func() {
+		if *quiet && (*verbose || *debug) {
+			fmt.Fprintln(os.Stderr, "Error: -q cannot be combined with -v or -vv")
+			os.Exit(1)
+		}
+		switch {
+		case *quiet:
+			logger.SetLevel(logging.Quiet)
+		case *debug:
+			logger.SetLevel(logging.Debug)
+		case *verbose:
+			logger.SetLevel(logging.Verbose)
+		}
+	}
+}
+
+// checkErrorFormat exits when -error-format is not text or json
+func checkErrorFormat() {
+	if format := errorFormat; format != "text" && format != "json" {
+		errorFormat = "text"
+		fmt.Fprintf(os.Stderr, "Error: unknown error format %q (expected text or json)\n", format)
+		os.Exit(1)
+	}
+}
+
+// exitWithError reports an error after a prefix such as "Error parsing schema", or
+// with the warnings so far in the json error format, and exits with the exit code
+// of its kind: 2 for parse errors, 3 for validation errors, 4 for generation
+// errors, and 1 otherwise
+func exitWithError(prefix string, err error) {
+	if errorFormat == "json" {
+		diagnostics = append(diagnostics, diagnostic.Of(err)...)
+		flushDiagnostics()
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", prefix, err)
+	}
+	os.Exit(diagnostic.KindOf(err).ExitCode())
+}
+
+// flushDiagnostics writes the diagnostics kept for the json error format to stderr
+func flushDiagnostics() {
+	if errorFormat != "json" {
+		return
+	}
+	_ = diagnostic.WriteJSON(os.Stderr, diagnostics) //nolint:errcheck // nothing left to report to
+	diagnostics = nil
+}
+
+// schemaSources caches the schema files read so far, so that imports shared by the
+// schemas of a batch config are read, and their warnings reported, only once
+var schemaSources = make(map[string][]byte)
+
+// parseCache keeps parse results of schema files across runs (nil when disabled by -no-cache)
+var parseCache *parsecache.Cache
+
+// schemaLimits bounds the size and complexity of the schema files read, from
+// -max-file-size and the other limit flags or the limits of -config
+var schemaLimits loader.Limits
+
+// applyLimitsConfig sets the limits of a config file that no flag set
+func applyLimitsConfig(limits *loader.Limits, cfg config.LimitsConfig) {
+	if limits.MaxFileSize == 0 {
+		limits.MaxFileSize = cfg.MaxFileSize
+	}
+	if limits.MaxImportDepth == 0 {
+		limits.MaxImportDepth = cfg.MaxImportDepth
+	}
+	if limits.MaxTypes == 0 {
+		limits.MaxTypes = cfg.MaxTypes
+	}
+	if limits.MaxFields == 0 {
+		limits.MaxFields = cfg.MaxFields
+	}
+	if limits.MaxAnnotationLength == 0 {
+		limits.MaxAnnotationLength = cfg.MaxAnnotationLength
+	}
+}
+
 // arrayFlags is a custom flag type that accumulates multiple values
 type arrayFlags []string
 
@@ -33,87 +201,111 @@ func (i *arrayFlags) Set(value string) error {
 	return nil
 }
 
-// parseSchemaWithImports recursively parses a schema file and all its imports
-func parseSchemaWithImports(filePath string, visited map[string]bool) (*ast.Schema, error) {
-	// Get absolute path to handle relative imports correctly
-	absPath, err := filepath.Abs(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve path %s: %v", filePath, err)
+// parseSchemaWithImports parses a schema file and every file it imports, directly or
+// indirectly, reporting the warnings of each file the first time it is read
+func parseSchemaWithImports(filePath string) (*ast.Schema, error) {
+	schemaLoader := &loader.Loader{
+		Sources: schemaSources,
+		Parse:   parseSchemaFile,
+		Check:   checkSchemaFile,
+		Limits:  schemaLimits,
 	}
+	return schemaLoader.Load(filePath)
+}
 
-	// Check for circular imports
-	if visited[absPath] {
-		return nil, fmt.Errorf("circular import detected: %s", absPath)
+// checkSchemaFile reports the warnings of a schema file and checks its TypeMUX version
+func checkSchemaFile(path string, schema *ast.Schema, warnings []string) error {
+	for _, warning := range warnings {
+		reportFileWarning(path, warning)
 	}
-	visited[absPath] = true
-
-	// Read the file
-	content, err := os.ReadFile(absPath)
-	if err != nil {
-		return nil, fmt.Errorf("error reading file %s: %v", absPath, err)
+	absPath := path
+	if !stdlib.IsImport(path) {
+		absPath, _ = filepath.Abs(path)
 	}
+	if err := validateTypeMUXVersion(schema.TypeMUXVersion, absPath); err != nil {
+		return diagnostic.New(diagnostic.Validation, err,
+			diagnostic.Diagnostic{File: path, Severity: diagnostic.SeverityError, Message: err.Error()})
+	}
+	return nil
+}
 
-	// Parse the file
-	l := lexer.New(string(content))
-	p := parser.New(l)
-	schema := p.Parse()
-
-	if len(p.Errors()) > 0 {
-		return nil, fmt.Errorf("parser errors in %s:\n%s", absPath, p.PrintErrors())
+// parseSchemaFile parses the content of a single schema file without its imports,
+// reusing the result of an earlier run from the parse cache when the content is unchanged
+func parseSchemaFile(path string, content []byte) (*ast.Schema, []string, error) {
+	if parseCache != nil {
+		if entry, ok := parseCache.Get(content); ok {
+			logger.Debugf("Parse cache hit: %s", path)
+			return entry.Schema, entry.Warnings, nil
+		}
 	}
+	logger.Debugf("Parsing %s", path)
 
-	// Validate TypeMUX version if specified
-	if err := validateTypeMUXVersion(schema.TypeMUXVersion, absPath); err != nil {
-		return nil, err
+	schema, warnings, err := loader.ParseFile(path, content)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Initialize type registry if not already present
-	if schema.TypeRegistry == nil {
-		schema.TypeRegistry = ast.NewTypeRegistry()
+	// The cache only speeds up later runs, so failing to write it is not an error
+	if parseCache != nil {
+		_ = parseCache.Put(content, &parsecache.Entry{Schema: schema, Warnings: warnings})
 	}
+	return schema, warnings, nil
+}
 
-	// Register all types, enums, and unions from this schema
-	for _, enum := range schema.Enums {
-		schema.TypeRegistry.RegisterEnum(enum)
+// loadSchema parses a schema file with its imports, or decodes a schema exported by typemux compile
+func loadSchema(filePath string) (*ast.Schema, error) {
+	if !strings.EqualFold(filepath.Ext(filePath), ".json") {
+		return parseSchemaWithImports(filePath)
 	}
-	for _, typ := range schema.Types {
-		schema.TypeRegistry.RegisterType(typ)
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %v", filePath, err)
 	}
-	for _, union := range schema.Unions {
-		schema.TypeRegistry.RegisterUnion(union)
+	schema, err := ast.UnmarshalSchemaJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
 	}
+	return schema, nil
+}
 
-	// Process imports
-	baseDir := filepath.Dir(absPath)
-	for _, importPath := range schema.Imports {
-		// Resolve import path relative to the current file
-		resolvedPath := filepath.Join(baseDir, importPath)
+// mergeAnnotationFiles validates YAML annotation files against the schema and merges them into it
+func mergeAnnotationFiles(schema *ast.Schema, files []string) error {
+	yamlAnnotations, err := annotations.MergeYAMLAnnotations(files)
+	if err != nil {
+		return diagnostic.New(diagnostic.Parse, fmt.Errorf("failed to load YAML annotations: %w", err))
+	}
 
-		// Parse the imported file
-		importedSchema, err := parseSchemaWithImports(resolvedPath, visited)
-		if err != nil {
-			return nil, err
+	// Validate annotations
+	validator := annotations.NewValidator(schema)
+	if validationErrors := validator.Validate(yamlAnnotations); len(validationErrors) > 0 {
+		var found []diagnostic.Diagnostic
+		for _, validationError := range validationErrors {
+			found = append(found, diagnostic.Diagnostic{Severity: diagnostic.SeverityError, Message: validationError.Error()})
 		}
+		return diagnostic.New(diagnostic.Validation, errors.New(strings.TrimSuffix(validator.FormatErrors(), "\n")), found...)
+	}
 
-		// Merge imported schema into current schema (preserving namespaces)
-		schema.Enums = append(schema.Enums, importedSchema.Enums...)
-		schema.Types = append(schema.Types, importedSchema.Types...)
-		schema.Unions = append(schema.Unions, importedSchema.Unions...)
-		schema.Services = append(schema.Services, importedSchema.Services...)
+	// Merge annotations into schema
+	merger := annotations.NewMerger(yamlAnnotations)
+	merger.Merge(schema)
 
-		// Merge type registries
-		for qualName, enum := range importedSchema.TypeRegistry.Enums {
-			schema.TypeRegistry.Enums[qualName] = enum
-		}
-		for qualName, typ := range importedSchema.TypeRegistry.Types {
-			schema.TypeRegistry.Types[qualName] = typ
-		}
-		for qualName, union := range importedSchema.TypeRegistry.Unions {
-			schema.TypeRegistry.Unions[qualName] = union
-		}
+	// Propagate annotations merged into base types to the types that extend them and their views
+	if err := schema.ResolveExtends(); err != nil {
+		return diagnostic.New(diagnostic.Validation, err)
 	}
-
-	return schema, nil
+	if err := schema.ResolveViews(); err != nil {
+		return diagnostic.New(diagnostic.Validation, err)
+	}
+	if err := schema.ResolveResources(); err != nil {
+		return diagnostic.New(diagnostic.Validation, err)
+	}
+	if err := schema.ResolveBatches(); err != nil {
+		return diagnostic.New(diagnostic.Validation, err)
+	}
+	if err := schema.ResolveLongRunning(); err != nil {
+		return diagnostic.New(diagnostic.Validation, err)
+	}
+	return nil
 }
 
 func handleAnnotationsCommand() {
@@ -163,8 +355,10 @@ func handleDiffCommand() {
 	headFile := diffFlags.String("head", "", "Head schema file (required)")
 	compact := diffFlags.Bool("compact", false, "Show compact one-line summary")
 	exitOnBreaking := diffFlags.Bool("exit-on-breaking", false, "Exit with code 1 if breaking changes detected")
+	addErrorFormatFlag(diffFlags)
 
 	_ = diffFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+	checkErrorFormat()
 
 	// Validate required flags
 	if *baseFile == "" || *headFile == "" {
@@ -176,17 +370,15 @@ func handleDiffCommand() {
 	}
 
 	// Parse base schema
-	baseSchema, err := parseSchemaWithImports(*baseFile, make(map[string]bool))
+	baseSchema, err := loadSchema(*baseFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing base schema: %v\n", err)
-		os.Exit(1)
+		exitWithError("Error parsing base schema", err)
 	}
 
 	// Parse head schema
-	headSchema, err := parseSchemaWithImports(*headFile, make(map[string]bool))
+	headSchema, err := loadSchema(*headFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing head schema: %v\n", err)
-		os.Exit(1)
+		exitWithError("Error parsing head schema", err)
 	}
 
 	// Perform diff
@@ -205,6 +397,7 @@ func handleDiffCommand() {
 	}
 
 	// Exit with error code if requested and breaking changes found
+	flushDiagnostics()
 	if *exitOnBreaking && result.HasBreakingChanges() {
 		os.Exit(1)
 	}
@@ -215,8 +408,17 @@ func handleDocsCommand() {
 	docsFlags := flag.NewFlagSet("docs", flag.ExitOnError)
 	inputFile := docsFlags.String("input", "", "Input schema file (required)")
 	outputDir := docsFlags.String("output", "./docs", "Output directory for documentation")
+	format := docsFlags.String("format", "markdown", "Documentation format: markdown or html")
+	formatViews := docsFlags.Bool("format-views", false, "Show how each service method looks in REST, gRPC, and GraphQL")
+	diagrams := docsFlags.Bool("diagrams", false, "Embed a Mermaid diagram of type dependencies")
+	locale := docsFlags.String("locale", "", "Write doc comments in this locale, such as es or pt-BR, from @lang(locale) lines")
+	int64Encoding := docsFlags.String("int64", "", "Describe the JSON encoding of int64 and uint64 fields: number, string, or scalar")
+	addErrorFormatFlag(docsFlags)
+	setVerbosity := addVerbosityFlags(docsFlags)
 
 	_ = docsFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+	checkErrorFormat()
+	setVerbosity()
 
 	// Validate required flags
 	if *inputFile == "" {
@@ -228,313 +430,1993 @@ func handleDocsCommand() {
 	}
 
 	// Parse schema
-	schema, err := parseSchemaWithImports(*inputFile, make(map[string]bool))
+	schema, err := parseSchemaWithImports(*inputFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing schema: %v\n", err)
-		os.Exit(1)
+		exitWithError("Error parsing schema", err)
 	}
 
+	opts := &docgen.Options{FormatViews: *formatViews, Diagrams: *diagrams, Locale: *locale, Int64: parseInt64Encoding(*int64Encoding)}
+
 	// Generate documentation
-	docGenerator := docgen.NewGenerator(schema, *outputDir)
-	if err := docGenerator.Generate(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating documentation: %v\n", err)
+	switch *format {
+	case "markdown", "md":
+		docGenerator := docgen.NewGeneratorWithOptions(schema, *outputDir, opts)
+		if err := docGenerator.Generate(); err != nil {
+			exitWithError("Error generating documentation", diagnostic.New(diagnostic.Generation, err))
+		}
+
+		logger.Infof("✨ Documentation generated successfully in %s", *outputDir)
+		logger.Infof("📖 Open %s/README.md to get started", *outputDir)
+	case "html":
+		if err := os.MkdirAll(*outputDir, 0o750); err != nil {
+			exitWithError("Error creating output directory", diagnostic.New(diagnostic.Generation, err))
+		}
+		for name, content := range docgen.NewHTMLGeneratorWithOptions(opts).GenerateFiles(schema) {
+			if err := os.WriteFile(filepath.Join(*outputDir, name), []byte(content), 0o600); err != nil {
+				exitWithError("Error generating documentation", diagnostic.New(diagnostic.Generation, err))
+			}
+		}
+
+		logger.Infof("✨ Documentation generated successfully in %s", *outputDir)
+		logger.Infof("📖 Open %s/index.html to get started", *outputDir)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown documentation format: %s (must be markdown or html)\n", *format)
 		os.Exit(1)
 	}
-
-	fmt.Printf("✨ Documentation generated successfully in %s\n", *outputDir)
-	fmt.Printf("📖 Open %s/README.md to get started\n", *outputDir)
 }
 
-func main() {
-	// Handle special commands
-	if len(os.Args) > 1 && os.Args[1] == "annotations" {
-		handleAnnotationsCommand()
-		return
+func handleCompileCommand() {
+	// Parse flags for compile command
+	compileFlags := flag.NewFlagSet("compile", flag.ExitOnError)
+	inputFile := compileFlags.String("input", "", "Input schema file (required)")
+	outputFile := compileFlags.String("o", "", "Output JSON file (default: stdout)")
+	var annotationFiles arrayFlags
+	compileFlags.Var(&annotationFiles, "annotations", "YAML annotations file (can be specified multiple times)")
+	addErrorFormatFlag(compileFlags)
+	setVerbosity := addVerbosityFlags(compileFlags)
+
+	_ = compileFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+	checkErrorFormat()
+	setVerbosity()
+
+	// Validate required flags
+	if *inputFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: -input is required\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: typemux compile -input <schema-file> [-annotations <file>] [-o schema.json]\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		compileFlags.PrintDefaults()
+		os.Exit(1)
 	}
 
-	if len(os.Args) > 1 && os.Args[1] == "diff" {
-		handleDiffCommand()
-		return
+	schema, err := parseSchemaWithImports(*inputFile)
+	if err != nil {
+		exitWithError("Error parsing schema", err)
+	}
+	if len(annotationFiles) > 0 {
+		if err := mergeAnnotationFiles(schema, annotationFiles); err != nil {
+			exitWithError("Error", err)
+		}
 	}
 
-	if len(os.Args) > 1 && os.Args[1] == "docs" {
-		handleDocsCommand()
-		return
+	data, err := ast.MarshalSchemaJSON(schema)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding schema: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Config file flag
-	configFile := flag.String("config", "", "Configuration file (YAML)")
+	if *outputFile == "" {
+		_, _ = os.Stdout.Write(data) //nolint:errcheck // nothing left to report to
+		return
+	}
+	if err := os.WriteFile(*outputFile, data, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *outputFile, err)
+		os.Exit(1)
+	}
+	logger.Infof("Compiled schema: %s", *outputFile)
+}
 
-	// Direct flags (used when no config file is provided)
-	inputFile := flag.String("input", "", "Input IDL schema file")
-	outputFormat := flag.String("format", "all", "Output format: graphql, protobuf, openapi, go, or all")
-	outputDir := flag.String("output", "./generated", "Output directory for generated files")
+// handleMergeCommand flattens schema files and directories, with their imports,
+// into a single schema file
+func handleMergeCommand() {
+	mergeFlags := flag.NewFlagSet("merge", flag.ExitOnError)
+	outputFile := mergeFlags.String("o", "", "Output file (default: stdout)")
+	addErrorFormatFlag(mergeFlags)
+	setVerbosity := addVerbosityFlags(mergeFlags)
+
+	_ = mergeFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+	checkErrorFormat()
+	setVerbosity()
+
+	if mergeFlags.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no schema files or directories given\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: typemux merge [-o merged.typemux] <schema-file-or-directory>...\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		mergeFlags.PrintDefaults()
+		os.Exit(1)
+	}
 
-	var annotationFiles arrayFlags
-	flag.Var(&annotationFiles, "annotations", "YAML annotations file (can be specified multiple times)")
+	result, err := merge.Merge(mergeFlags.Args())
+	if err != nil {
+		exitWithError("Error", err)
+	}
+	for _, name := range result.Duplicates {
+		logger.Verbosef("Declared identically in several files: %s", name)
+	}
 
-	flag.Parse()
+	if *outputFile == "" {
+		_, _ = os.Stdout.Write(result.Content) //nolint:errcheck // nothing left to report to
+		return
+	}
+	if err := os.WriteFile(*outputFile, result.Content, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *outputFile, err)
+		os.Exit(1)
+	}
+	logger.Infof("Merged %d file(s) into %s (%d duplicate declaration(s) written once)", len(result.Files), *outputFile, len(result.Duplicates))
+}
 
-	var (
-		schemaFile       string
-		formats          []string
-		outputDirectory  string
-		annotationFiles2 []string
-	)
+// handleDescriptorCommand writes the serialized FileDescriptorSet of the Protobuf
+// output of a schema, for gRPC reflection and other descriptor-based tools
+func handleDescriptorCommand() {
+	descriptorFlags := flag.NewFlagSet("descriptor", flag.ExitOnError)
+	inputFile := descriptorFlags.String("input", "", "Input schema file (required)")
+	outputFile := descriptorFlags.String("o", "", "Output file (default: stdout)")
+	protoLayout := descriptorFlags.String("proto-layout", "", "Layout of the protobuf files: namespace (the default), type, or single")
+	lockFile := descriptorFlags.String("lock-file", "", "Number fields from this lock file, without updating it")
+	var annotationFiles arrayFlags
+	descriptorFlags.Var(&annotationFiles, "annotations", "YAML annotations file (can be specified multiple times)")
+	addErrorFormatFlag(descriptorFlags)
+	setVerbosity := addVerbosityFlags(descriptorFlags)
 
-	// Load configuration
-	if *configFile != "" {
-		// Load from config file
-		cfg, err := config.Load(*configFile)
-		if err != nil {
-			fmt.Printf("Error loading config file: %v\n", err)
-			os.Exit(1)
-		}
+	_ = descriptorFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+	checkErrorFormat()
+	setVerbosity()
 
-		schemaFile = cfg.Input.Schema
-		outputDirectory = cfg.Output.Directory
-		annotationFiles2 = cfg.Input.Annotations
+	// Validate required flags
+	if *inputFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: -input is required\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: typemux descriptor -input <schema-file> [-annotations <file>] [-o schema.binpb]\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		descriptorFlags.PrintDefaults()
+		os.Exit(1)
+	}
 
-		// Convert formats
-		if cfg.ShouldGenerateFormat("all") {
-			formats = []string{"all"}
-		} else {
-			if cfg.ShouldGenerateFormat("graphql") {
-				formats = append(formats, "graphql")
-			}
-			if cfg.ShouldGenerateFormat("protobuf") {
-				formats = append(formats, "protobuf")
-			}
-			if cfg.ShouldGenerateFormat("openapi") {
-				formats = append(formats, "openapi")
-			}
-			if cfg.ShouldGenerateFormat("go") {
-				formats = append(formats, "go")
-			}
+	schema, err := loadSchema(*inputFile)
+	if err != nil {
+		exitWithError("Error parsing schema", err)
+	}
+	if len(annotationFiles) > 0 {
+		if err := mergeAnnotationFiles(schema, annotationFiles); err != nil {
+			exitWithError("Error", err)
 		}
-
-		// Clean output directory if requested
-		if cfg.Output.Clean {
-			if err := os.RemoveAll(outputDirectory); err != nil {
-				fmt.Printf("Error cleaning output directory: %v\n", err)
-				os.Exit(1)
-			}
+	}
+	if *lockFile != "" {
+		lock, err := lockfile.Load(*lockFile)
+		if err != nil {
+			exitWithError("Error", err)
 		}
-
-		fmt.Printf("Loaded configuration from: %s\n", *configFile)
-	} else {
-		// Use command-line flags
-		if *inputFile == "" {
-			fmt.Println("Error: -input flag or -config flag is required")
-			flag.Usage()
-			os.Exit(1)
+		if err := lock.Apply(schema); err != nil {
+			exitWithError("Error", diagnostic.New(diagnostic.Validation, fmt.Errorf("field numbers conflict with %s:\n%v", *lockFile, err)))
 		}
-
-		schemaFile = *inputFile
-		outputDirectory = *outputDir
-		annotationFiles2 = annotationFiles
-		formats = []string{*outputFormat}
 	}
 
-	// Parse the schema with imports
-	schema, err := parseSchemaWithImports(schemaFile, make(map[string]bool))
+	opts := generator.Options{Protobuf: &generator.ProtobufOptions{Layout: generator.ProtobufLayout(*protoLayout)}}
+	data, err := generator.DescriptorSet(context.Background(), schema, opts)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		exitWithError("Error", diagnostic.New(diagnostic.Generation, err))
 	}
 
-	// Load and merge YAML annotations if provided
-	if len(annotationFiles2) > 0 {
-		yamlAnnotations, err := annotations.MergeYAMLAnnotations(annotationFiles2)
-		if err != nil {
-			fmt.Printf("Error loading YAML annotations: %v\n", err)
-			os.Exit(1)
-		}
-
-		// Validate annotations
-		validator := annotations.NewValidator(schema)
-		validationErrors := validator.Validate(yamlAnnotations)
-		if len(validationErrors) > 0 {
-			fmt.Print(validator.FormatErrors())
-			os.Exit(1)
-		}
+	if *outputFile == "" {
+		_, _ = os.Stdout.Write(data) //nolint:errcheck // nothing left to report to
+		return
+	}
+	if err := os.WriteFile(*outputFile, data, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *outputFile, err)
+		os.Exit(1)
+	}
+	logger.Infof("Wrote descriptor set: %s", *outputFile)
+}
 
-		// Merge annotations into schema
-		merger := annotations.NewMerger(yamlAnnotations)
-		merger.Merge(schema)
+// handlePresenceCommand reports how the presence of each field is expressed per output format
+func handlePresenceCommand() {
+	presenceFlags := flag.NewFlagSet("presence", flag.ExitOnError)
+	inputFile := presenceFlags.String("input", "", "Input schema file (required)")
+	format := presenceFlags.String("format", "text", "Output format: text or json")
+	var annotationFiles arrayFlags
+	presenceFlags.Var(&annotationFiles, "annotations", "YAML annotations file (can be specified multiple times)")
+	addErrorFormatFlag(presenceFlags)
 
-		fmt.Printf("Loaded annotations from %d file(s)\n", len(annotationFiles2))
-	}
+	_ = presenceFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+	checkErrorFormat()
 
-	// Create output directory
-	if err := os.MkdirAll(outputDirectory, 0o750); err != nil {
-		fmt.Printf("Error creating output directory: %v\n", err)
+	// Validate required flags
+	if *inputFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: -input is required\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: typemux presence -input <schema-file> [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		presenceFlags.PrintDefaults()
 		os.Exit(1)
 	}
 
-	// Generate output based on formats
-	for _, format := range formats {
-		switch format {
-		case "graphql":
-			generateGraphQL(schema, outputDirectory)
-		case "protobuf", "proto":
-			generateProtobuf(schema, outputDirectory)
-		case "openapi":
-			generateOpenAPI(schema, outputDirectory)
-		case "go", "golang":
-			generateGo(schema, outputDirectory)
-		case "docs", "markdown", "md":
-			generateMarkdownDocs(schema, outputDirectory)
-		case "all":
-			generateGraphQL(schema, outputDirectory)
-			generateProtobuf(schema, outputDirectory)
-			generateOpenAPI(schema, outputDirectory)
-			generateGo(schema, outputDirectory)
-			generateMarkdownDocs(schema, outputDirectory)
-		default:
-			fmt.Printf("Unknown format: %s\n", format)
-			os.Exit(1)
+	schema, err := loadSchema(*inputFile)
+	if err != nil {
+		exitWithError("Error parsing schema", err)
+	}
+	if len(annotationFiles) > 0 {
+		if err := mergeAnnotationFiles(schema, annotationFiles); err != nil {
+			exitWithError("Error", err)
 		}
 	}
 
-	fmt.Println("Code generation completed successfully!")
-}
-
-func generateGraphQL(schema *ast.Schema, outputDir string) {
-	gen := generator.NewGraphQLGenerator()
-	output := gen.Generate(schema)
-
-	outputPath := filepath.Join(outputDir, "schema.graphql")
-	if err := os.WriteFile(outputPath, []byte(output), 0o600); err != nil {
-		fmt.Printf("Error writing GraphQL schema: %v\n", err)
-		return
+	report := generator.PresenceReport(schema)
+	switch *format {
+	case "text":
+		fmt.Print(generator.FormatPresenceReport(report))
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (expected text or json)\n", *format)
+		os.Exit(1)
 	}
-	fmt.Printf("Generated GraphQL schema: %s\n", outputPath)
 }
 
-func generateProtobuf(schema *ast.Schema, outputDir string) {
-	gen := generator.NewProtobufGenerator()
+// handleSensitiveCommand lists the fields marked @sensitive per type and the methods that expose them
+func handleSensitiveCommand() {
+	sensitiveFlags := flag.NewFlagSet("sensitive", flag.ExitOnError)
+	inputFile := sensitiveFlags.String("input", "", "Input schema file (required)")
+	format := sensitiveFlags.String("format", "text", "Output format: text or json")
+	var annotationFiles arrayFlags
+	sensitiveFlags.Var(&annotationFiles, "annotations", "YAML annotations file (can be specified multiple times)")
+	addErrorFormatFlag(sensitiveFlags)
 
-	// Check if we have multiple namespaces
-	namespaces := collectNamespaces(schema)
+	_ = sensitiveFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+	checkErrorFormat()
 
-	if len(namespaces) > 1 {
-		// Generate separate proto files per namespace
-		protoFiles := gen.GenerateByNamespace(schema)
+	// Validate required flags
+	if *inputFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: -input is required\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: typemux sensitive -input <schema-file> [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		sensitiveFlags.PrintDefaults()
+		os.Exit(1)
+	}
 
-		for ns, content := range protoFiles {
-			// Create namespace directory structure (e.g., com/example/users/)
-			nsPath := strings.ReplaceAll(ns, ".", "/")
-			nsDir := filepath.Join(outputDir, filepath.Dir(nsPath))
-			if err := os.MkdirAll(nsDir, 0o750); err != nil {
-				fmt.Printf("Error creating namespace directory: %v\n", err)
-				continue
-			}
+	schema, err := loadSchema(*inputFile)
+	if err != nil {
+		exitWithError("Error parsing schema", err)
+	}
+	if len(annotationFiles) > 0 {
+		if err := mergeAnnotationFiles(schema, annotationFiles); err != nil {
+			exitWithError("Error", err)
+		}
+	}
 
-			// Write proto file (e.g., com/example/users.proto)
-			outputPath := filepath.Join(outputDir, nsPath+".proto")
-			if err := os.WriteFile(outputPath, []byte(content), 0o600); err != nil {
-				fmt.Printf("Error writing Protobuf schema for %s: %v\n", ns, err)
-				continue
-			}
-			fmt.Printf("Generated Protobuf schema: %s\n", outputPath)
+	report := graph.SensitiveReport(schema)
+	switch *format {
+	case "text":
+		fmt.Print(graph.FormatSensitiveReport(report))
+	case "json":
+		if report == nil {
+			report = []graph.SensitiveField{}
 		}
-	} else {
-		// Single namespace - generate single file
-		output := gen.Generate(schema)
-		outputPath := filepath.Join(outputDir, "schema.proto")
-		if err := os.WriteFile(outputPath, []byte(output), 0o600); err != nil {
-			fmt.Printf("Error writing Protobuf schema: %v\n", err)
-			return
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
 		}
-		fmt.Printf("Generated Protobuf schema: %s\n", outputPath)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (expected text or json)\n", *format)
+		os.Exit(1)
 	}
 }
 
-// collectNamespaces returns all unique namespaces in the schema
-func collectNamespaces(schema *ast.Schema) []string {
-	nsMap := make(map[string]bool)
+// handleOwnersCommand lists the owner, SLA, and tier of each namespace, type, and service
+func handleOwnersCommand() {
+	ownersFlags := flag.NewFlagSet("owners", flag.ExitOnError)
+	inputFile := ownersFlags.String("input", "", "Input schema file (required)")
+	format := ownersFlags.String("format", "text", "Output format: text or json")
+	var annotationFiles arrayFlags
+	ownersFlags.Var(&annotationFiles, "annotations", "YAML annotations file (can be specified multiple times)")
+	addErrorFormatFlag(ownersFlags)
 
-	for _, enum := range schema.Enums {
-		ns := enum.Namespace
-		if ns == "" {
-			ns = "api"
-		}
-		nsMap[ns] = true
-	}
+	_ = ownersFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+	checkErrorFormat()
 
-	for _, typ := range schema.Types {
-		ns := typ.Namespace
-		if ns == "" {
-			ns = "api"
-		}
-		nsMap[ns] = true
+	// Validate required flags
+	if *inputFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: -input is required\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: typemux owners -input <schema-file> [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		ownersFlags.PrintDefaults()
+		os.Exit(1)
 	}
 
-	for _, union := range schema.Unions {
-		ns := union.Namespace
-		if ns == "" {
-			ns = "api"
+	schema, err := loadSchema(*inputFile)
+	if err != nil {
+		exitWithError("Error parsing schema", err)
+	}
+	if len(annotationFiles) > 0 {
+		if err := mergeAnnotationFiles(schema, annotationFiles); err != nil {
+			exitWithError("Error", err)
 		}
-		nsMap[ns] = true
 	}
 
-	for _, service := range schema.Services {
-		ns := service.Namespace
-		if ns == "" {
-			ns = "api"
+	report := graph.OwnershipReport(schema)
+	switch *format {
+	case "text":
+		fmt.Print(graph.FormatOwnershipReport(report))
+	case "json":
+		if report == nil {
+			report = []graph.OwnedElement{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
 		}
-		nsMap[ns] = true
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (expected text or json)\n", *format)
+		os.Exit(1)
 	}
+}
 
-	result := make([]string, 0, len(nsMap))
-	for ns := range nsMap {
-		result = append(result, ns)
+// handleNamesCommand prints the name of every schema element in each output format
+// and fails when names collide
+func handleNamesCommand() {
+	namesFlags := flag.NewFlagSet("names", flag.ExitOnError)
+	inputFile := namesFlags.String("input", "", "Input schema file (required)")
+	format := namesFlags.String("format", "text", "Output format: text or json")
+	configFile := namesFlags.String("config", "", "Configuration file whose generators.naming policy applies")
+	namingPolicy := namesFlags.String("naming", "", "Naming policy of generated field names: standard")
+	var annotationFiles arrayFlags
+	namesFlags.Var(&annotationFiles, "annotations", "YAML annotations file (can be specified multiple times)")
+	addErrorFormatFlag(namesFlags)
+
+	_ = namesFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+	checkErrorFormat()
+
+	// Validate required flags
+	if *inputFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: -input is required\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: typemux names -input <schema-file> [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		namesFlags.PrintDefaults()
+		os.Exit(1)
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (expected text or json)\n", *format)
+		os.Exit(1)
+	}
+
+	var policy naming.Policy
+	switch *namingPolicy {
+	case "":
+	case "standard":
+		policy = naming.Standard
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown naming policy %q (valid: standard)\n", *namingPolicy)
+		os.Exit(1)
+	}
+	if *configFile != "" && *namingPolicy == "" {
+		cfg, err := config.Load(*configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		if policy, err = cfg.Generators.Naming.Policy(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	schema, err := loadSchema(*inputFile)
+	if err != nil {
+		exitWithError("Error parsing schema", err)
+	}
+	if len(annotationFiles) > 0 {
+		if err := mergeAnnotationFiles(schema, annotationFiles); err != nil {
+			exitWithError("Error", err)
+		}
+	}
+	policy.Apply(schema)
+
+	report := generator.NameReport(schema)
+	collisions := generator.NameCollisions(report)
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		output := struct {
+			Names      []generator.ElementNames `json:"names"`
+			Collisions []string                 `json:"collisions,omitempty"`
+		}{report, collisions}
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		fmt.Print(generator.FormatNameReport(report))
+	}
+
+	if len(collisions) > 0 {
+		found := make([]diagnostic.Diagnostic, len(collisions))
+		for i, collision := range collisions {
+			found[i] = diagnostic.Diagnostic{File: *inputFile, Severity: diagnostic.SeverityError, Message: collision}
+		}
+		err := diagnostic.New(diagnostic.Validation, errors.New(strings.Join(collisions, "\nError: ")), found...)
+		exitWithError("Error", err)
+	}
+}
+
+// handleRoundtripCommand imports a Protobuf, GraphQL, or OpenAPI schema, generates
+// the same format again and reports what did not survive the trip
+func handleRoundtripCommand() {
+	roundtripFlags := flag.NewFlagSet("roundtrip", flag.ExitOnError)
+	inputFile := roundtripFlags.String("input", "", "Input .proto, .graphql, or OpenAPI file (required)")
+	from := roundtripFlags.String("from", "", "Input format: protobuf, graphql, or openapi (default: from the file extension)")
+	format := roundtripFlags.String("format", "text", "Report format: text or json")
+	keep := roundtripFlags.String("keep", "", "Keep the imported TypeMUX files and the regenerated schema in this directory")
+	minFidelity := roundtripFlags.Float64("min-fidelity", 0, "Exit with code 1 if the fidelity is below this percentage")
+
+	_ = roundtripFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+
+	// Validate required flags
+	if *inputFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: -input is required\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: typemux roundtrip -input <schema-file> [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		roundtripFlags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	var schemaFormat roundtrip.Format
+	var err error
+	if *from != "" {
+		schemaFormat, err = roundtrip.ParseFormat(*from)
+	} else {
+		schemaFormat, err = roundtrip.DetectFormat(*inputFile)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	dir := *keep
+	if dir == "" {
+		dir, err = os.MkdirTemp("", "typemux-roundtrip-")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating temporary directory: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	result, err := roundtrip.Run(*inputFile, schemaFormat, dir, parseSchemaWithImports)
+	if *keep == "" {
+		os.RemoveAll(dir)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *keep != "" {
+		generatedFile := filepath.Join(dir, "regenerated"+roundtripExtensions[schemaFormat])
+		if err := os.WriteFile(generatedFile, []byte(result.Generated), 0o600); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", generatedFile, err)
+			os.Exit(1)
+		}
+	}
+
+	switch *format {
+	case "text":
+		fmt.Printf("Round trip of %s (%s)\n", *inputFile, result.Format)
+		fmt.Print(fidelity.Format(result.Report))
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		output := struct {
+			Input    string           `json:"input"`
+			Format   roundtrip.Format `json:"format"`
+			Fidelity float64          `json:"fidelity"`
+			*fidelity.Report
+		}{*inputFile, result.Format, result.Report.Fidelity(), result.Report}
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (expected text or json)\n", *format)
+		os.Exit(1)
+	}
+
+	if result.Report.Fidelity()*100 < *minFidelity {
+		os.Exit(1)
+	}
+}
+
+// roundtripExtensions names the regenerated schema kept by typemux roundtrip -keep
+var roundtripExtensions = map[roundtrip.Format]string{
+	roundtrip.Protobuf: ".proto",
+	roundtrip.GraphQL:  ".graphql",
+	roundtrip.OpenAPI:  ".yaml",
+}
+
+func handleGraphCommand() {
+	// Parse flags for graph command
+	graphFlags := flag.NewFlagSet("graph", flag.ExitOnError)
+	inputFile := graphFlags.String("input", "", "Input schema file (required)")
+	format := graphFlags.String("format", "dot", "Output format: dot or json")
+	why := graphFlags.String("why", "", "Explain why a type is part of the API (which service uses it and how)")
+	unused := graphFlags.Bool("unused", false, "List types, enums, and unions not reachable from any service")
+	addErrorFormatFlag(graphFlags)
+
+	_ = graphFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+	checkErrorFormat()
+
+	// Validate required flags
+	if *inputFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: -input is required\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: typemux graph -input <schema-file> [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		graphFlags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	// Parse schema
+	schema, err := parseSchemaWithImports(*inputFile)
+	if err != nil {
+		exitWithError("Error parsing schema", err)
+	}
+
+	deps := graph.Build(schema)
+
+	if *why != "" {
+		node, ok := deps.Lookup(*why)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown or ambiguous type: %s\n", *why)
+			os.Exit(1)
+		}
+		printWhy(deps, node)
+		return
+	}
+
+	if *unused {
+		unusedNodes := deps.Unused()
+		if len(unusedNodes) == 0 {
+			fmt.Println("All types are reachable from a service")
+			return
+		}
+		for _, node := range unusedNodes {
+			fmt.Printf("%s (%s)\n", node.ID, node.Kind)
+		}
+		return
+	}
+
+	imports := make(map[string][]string)
+	if err := collectImports(*inputFile, filepath.Dir(*inputFile), imports); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading imports: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch *format {
+	case "dot":
+		fmt.Print(graph.ImportDOT(imports))
+		fmt.Println()
+		fmt.Print(deps.DOT())
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		output := struct {
+			Imports map[string][]string `json:"imports"`
+			Types   *graph.Graph        `json:"types"`
+		}{imports, deps}
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown output format: %s\n", *format)
+		os.Exit(1)
+	}
+}
+
+// printWhy prints the chain of references that makes a node part of the API, and its direct dependents
+func printWhy(deps *graph.Graph, node *graph.Node) {
+	if node.Kind == graph.KindService {
+		fmt.Printf("%s is a service\n", node.ID)
+		return
+	}
+
+	path := deps.Why(node.ID)
+	if path == nil {
+		fmt.Printf("%s is not reachable from any service\n", node.ID)
+	} else {
+		fmt.Printf("%s is reachable from service %s:\n", node.ID, path[0].From)
+		for _, edge := range path {
+			fmt.Printf("  %s -> %s (%s)\n", edge.From, edge.To, describeEdge(edge))
+		}
+	}
+
+	incoming := deps.Incoming(node.ID)
+	if len(incoming) > 0 {
+		fmt.Println("\nReferenced by:")
+		for _, edge := range incoming {
+			fmt.Printf("  %s (%s)\n", edge.From, describeEdge(edge))
+		}
+	}
+}
+
+// describeEdge names the kind of a dependency and the fields or methods behind it
+func describeEdge(edge *graph.Edge) string {
+	if len(edge.Labels) == 0 {
+		return string(edge.Kind)
+	}
+	return fmt.Sprintf("%s: %s", edge.Kind, strings.Join(edge.Labels, ", "))
+}
+
+// collectImports records the imports of a schema file and its imported files,
+// using paths relative to rootDir
+func collectImports(filePath, rootDir string, imports map[string][]string) error {
+	name := importName(rootDir, filePath)
+	if _, seen := imports[name]; seen {
+		return nil
+	}
+
+	content, err := loader.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	schema := parser.New(lexer.New(string(content))).Parse()
+	imports[name] = []string{}
+
+	for _, importPath := range schema.Imports {
+		resolvedPath := loader.ResolveImport(filepath.Dir(filePath), importPath)
+		imports[name] = append(imports[name], importName(rootDir, resolvedPath))
+
+		if err := collectImports(resolvedPath, rootDir, imports); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importName names a schema file in the import graph by its path relative to rootDir,
+// or by its import path for standard library files
+func importName(rootDir, path string) string {
+	if stdlib.IsImport(path) {
+		return path
+	}
+	name, err := filepath.Rel(rootDir, path)
+	if err != nil {
+		name = path
+	}
+	return filepath.ToSlash(name)
+}
+
+// handleVerifyBuildCommand runs the compilers and linters of each format on the
+// generated files and reports the checks that failed
+func handleVerifyBuildCommand() {
+	verifyFlags := flag.NewFlagSet("verify-build", flag.ExitOnError)
+	outputDir := verifyFlags.String("output", "", "Directory of the generated files (default: the output directories of -config, or ./generated)")
+	configFile := verifyFlags.String("config", "", "Configuration file whose verify checks and output directories apply")
+	format := verifyFlags.String("format", "text", "Report format: text or json")
+	strict := verifyFlags.Bool("strict", false, "Fail when the program of a check with matching files is not installed")
+
+	_ = verifyFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (expected text or json)\n", *format)
+		os.Exit(1)
+	}
+
+	checks := verify.DefaultChecks()
+	var dirs []string
+	if *configFile != "" {
+		cfg, err := config.Load(*configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		if len(cfg.Verify) > 0 {
+			checks = nil
+			for _, check := range cfg.Verify {
+				checks = append(checks, verify.Check{
+					Name:         check.Name,
+					Files:        check.Files,
+					Command:      check.Command,
+					PerDirectory: check.PerDirectory,
+				})
+			}
+		}
+		for _, entry := range cfg.Entries() {
+			dirs = append(dirs, entry.Output.Directory)
+		}
+	}
+	if *outputDir != "" {
+		dirs = []string{*outputDir}
+	} else if len(dirs) == 0 {
+		dirs = []string{"./generated"}
+	}
+
+	type report struct {
+		Directory string          `json:"directory"`
+		Results   []verify.Result `json:"results"`
+	}
+	var reports []report
+	failed := false
+	for _, dir := range dirs {
+		results, err := verify.Run(context.Background(), dir, checks)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		reports = append(reports, report{dir, results})
+		failed = failed || verify.Failing(results, *strict)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		for _, r := range reports {
+			fmt.Printf("Verifying %s\n", r.Directory)
+			fmt.Print(verify.Format(r.Results))
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// handleServeCommand serves the compiler over HTTP until interrupted
+func handleServeCommand() {
+	serveFlags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := serveFlags.String("addr", ":8080", "Address to listen on")
+	maxRequestSize := serveFlags.Int64("max-request-size", server.DefaultMaxRequestSize, "Reject request bodies larger than this many bytes")
+	timeout := serveFlags.Duration("timeout", server.DefaultTimeout, "Time limit of a request")
+	maxFileSize := serveFlags.Int64("max-file-size", 1<<20, "Reject schema files larger than this many bytes (0: unlimited)")
+	maxImportDepth := serveFlags.Int("max-import-depth", 8, "Reject import chains longer than this (0: unlimited)")
+	maxTypes := serveFlags.Int("max-types", 2000, "Reject schemas with more types, enums, unions, and services than this (0: unlimited)")
+	maxFields := serveFlags.Int("max-fields", 1000, "Reject types with more fields than this (0: unlimited)")
+	maxAnnotationLength := serveFlags.Int("max-annotation-length", 64<<10, "Reject annotations with more bytes of content than this (0: unlimited)")
+
+	_ = serveFlags.Parse(os.Args[2:]) //nolint:errcheck // ExitOnError flag set
+
+	handler := server.New(server.Options{
+		Limits: loader.Limits{
+			MaxFileSize:         *maxFileSize,
+			MaxImportDepth:      *maxImportDepth,
+			MaxTypes:            *maxTypes,
+			MaxFields:           *maxFields,
+			MaxAnnotationLength: *maxAnnotationLength,
+		},
+		MaxRequestSize: *maxRequestSize,
+		Timeout:        *timeout,
+		Version:        CurrentTypeMUXVersion,
+		Logger:         log.New(os.Stderr, "typemux serve: ", log.LstdFlags),
+	})
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       *timeout,
+		WriteTimeout:      *timeout + 10*time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx) //nolint:errcheck // exiting anyway
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving TypeMUX %s on %s\n", CurrentTypeMUXVersion, *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	// Warnings kept for -error-format json are written when a command succeeds
+	defer flushDiagnostics()
+
+	// Handle special commands
+	if len(os.Args) > 1 && os.Args[1] == "annotations" {
+		handleAnnotationsCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		handleDiffCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "docs" {
+		handleDocsCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "graph" {
+		handleGraphCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "compile" {
+		handleCompileCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "descriptor" {
+		handleDescriptorCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "presence" {
+		handlePresenceCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "sensitive" {
+		handleSensitiveCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "owners" {
+		handleOwnersCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "names" {
+		handleNamesCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "roundtrip" {
+		handleRoundtripCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "merge" {
+		handleMergeCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		handleServeCommand()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "verify-build" {
+		handleVerifyBuildCommand()
+		return
+	}
+
+	// Config file flag
+	configFile := flag.String("config", "", "Configuration file (YAML)")
+
+	// Direct flags (used when no config file is provided)
+	inputFile := flag.String("input", "", "Input IDL schema file")
+	outputFormat := flag.String("format", "all", "Output format: graphql, protobuf, openapi, go, grpc, connect, descriptor, schema-endpoint, java, csharp, mock, contract, markdown, html, or all")
+	outputDir := flag.String("output", "./generated", "Output directory for generated files")
+	outputFile := flag.String("o", "", "Write only this file, in the format of its extension (.proto, .graphql, .yaml, .pb, .md, ...) unless -format is given")
+
+	var annotationFiles arrayFlags
+	flag.Var(&annotationFiles, "annotations", "YAML annotations file (can be specified multiple times)")
+
+	var onlyServices, rootTypes arrayFlags
+	flag.Var(&onlyServices, "only-service", "Only generate this service and the types it references (can be specified multiple times)")
+	flag.Var(&rootTypes, "root-type", "Keep this type and the types it references when pruning (can be specified multiple times)")
+
+	var excludeServices, excludeTypes, profiles arrayFlags
+	flag.Var(&excludeServices, "exclude-service", "Leave out this service and the types only it references (can be specified multiple times)")
+	flag.Var(&excludeTypes, "exclude-type", "Leave out this type, which nothing else may reference (can be specified multiple times)")
+	flag.Var(&profiles, "profile", "Only generate this profile of the config file (can be specified multiple times)")
+
+	var apiVersions arrayFlags
+	flag.Var(&apiVersions, "api-version", "Generate this API version of the schema, from @since and @until, into a subdirectory of the output named after it (can be specified multiple times, or all)")
+
+	lockFile := flag.String("lock-file", "", "Keep field numbers stable in this lock file (e.g., "+lockfile.DefaultPath+")")
+	against := flag.String("against", "", "Fail on incompatible changes against this baseline schema file or Git ref")
+	compatPolicy := flag.String("policy", string(diff.PolicySource), "Compatibility policy for -against: WIRE, JSON, or SOURCE")
+	strict := flag.Bool("strict", false, "Treat warnings as errors (missing @typemux version, unknown annotations, missing field numbers, inferred HTTP methods)")
+	cacheDir := flag.String("cache-dir", "", "Directory of the parse cache (default: typemux/parse in the user cache directory)")
+	noCache := flag.Bool("no-cache", false, "Parse every schema file instead of reusing parse results of earlier runs")
+	templatesDir := flag.String("templates", "", "Directory of text/template overrides for generated files, with a subdirectory per format")
+	headerFile := flag.String("header-file", "", "File prepended as a comment to every generated file, e.g. a license header")
+	stamp := flag.Bool("stamp", false, "Stamp the schema version, Git commit, and content hash into generated files")
+	namingPolicy := flag.String("naming", "", "Naming policy of generated field names: standard (snake_case Protobuf, camelCase GraphQL and JSON, PascalCase Go fields)")
+	int64Encoding := flag.String("int64", "", "JSON encoding of int64 and uint64 values: number (the default), string (as in proto3 JSON), or scalar (a BigInt GraphQL scalar, strings elsewhere)")
+	protoLayout := flag.String("proto-layout", "", "Layout of the protobuf output: namespace (a file per namespace, the default), type (a file per declaration), or single (one file)")
+	goModule := flag.String("go-module", "", "Generate the go format as a Go module with this module path: go.mod, and a package with doc.go per namespace")
+	javaStyle := flag.String("java-style", "", "Class style of the java output: record (Java 16+ records, the default) or pojo (classes with getters, setters, and a builder)")
+	javaPackage := flag.String("java-package", "", "Prefix of the package names of the java output, e.g. org.acme")
+	csharpStyle := flag.String("csharp-style", "", "Type style of the csharp output: record (sealed records with init-only properties, the default) or class (mutable classes)")
+	csharpNamespace := flag.String("csharp-namespace", "", "Prefix of the namespaces of the csharp output, e.g. Acme")
+	scaffold := flag.Bool("scaffold", false, "Add the gRPC health service, server reflection, and /healthz, /readyz, and /version handlers to the grpc and connect output")
+	maxFileSize := flag.Int64("max-file-size", 0, "Reject schema files larger than this many bytes (0: unlimited)")
+	maxImportDepth := flag.Int("max-import-depth", 0, "Reject import chains longer than this (0: unlimited)")
+	maxTypes := flag.Int("max-types", 0, "Reject schemas with more types, enums, unions, and services than this, imports included (0: unlimited)")
+	maxFields := flag.Int("max-fields", 0, "Reject types with more fields than this (0: unlimited)")
+	maxAnnotationLength := flag.Int("max-annotation-length", 0, "Reject annotations with more bytes of content than this (0: unlimited)")
+	dryRun := flag.Bool("dry-run", false, "Print a unified diff of the generated files against the output directory instead of writing them")
+	check := flag.Bool("check", false, "Exit with code 1 if any generated file would change, listing those files, instead of writing them (for CI)")
+	addErrorFormatFlag(flag.CommandLine)
+	setVerbosity := addVerbosityFlags(flag.CommandLine)
+
+	flag.Parse()
+	checkErrorFormat()
+	setVerbosity()
+	started := time.Now()
+	strictMode = *strict
+
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	var (
+		policy        naming.Policy
+		unionEncoding *ast.UnionEncoding
+	)
+	switch *namingPolicy {
+	case "":
+	case "standard":
+		policy = naming.Standard
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown naming policy %q (valid: standard)\n", *namingPolicy)
+		os.Exit(1)
+	}
+	if !*noCache {
+		parseCache = openParseCache(*cacheDir)
+	}
+	schemaLimits = loader.Limits{
+		MaxFileSize:         *maxFileSize,
+		MaxImportDepth:      *maxImportDepth,
+		MaxTypes:            *maxTypes,
+		MaxFields:           *maxFields,
+		MaxAnnotationLength: *maxAnnotationLength,
+	}
+
+	var (
+		jobs    []compileJob
+		genOpts = generator.Options{
+			GraphQL:   &generator.GraphQLOptions{},
+			Protobuf:  &generator.ProtobufOptions{Layout: generator.ProtobufLayout(*protoLayout)},
+			OpenAPI:   &generator.OpenAPIOptions{},
+			Go:        &generator.GoOptions{Scaffold: *scaffold, Module: *goModule},
+			Java:      &generator.JavaOptions{Style: *javaStyle, PackagePrefix: *javaPackage},
+			CSharp:    &generator.CSharpOptions{Style: *csharpStyle, NamespacePrefix: *csharpNamespace},
+			Templates: *templatesDir,
+			Int64:     parseInt64Encoding(*int64Encoding),
+		}
+		compiled = "Code generation completed successfully!"
+	)
+
+	// Load configuration
+	if *configFile != "" && *outputFile != "" {
+		fmt.Fprintln(os.Stderr, "Error: -o cannot be used with -config; set output.directory and output.formats instead")
+		os.Exit(1)
+	}
+	if *configFile != "" {
+		// Load from config file
+		cfg, err := config.Load(*configFile)
+		if err != nil {
+			exitWithError("Error loading config file", err)
+		}
+
+		entries := cfg.Entries()
+		if len(profiles) > 0 {
+			if entries, err = selectProfiles(entries, profiles); err != nil {
+				exitWithError("Error", err)
+			}
+		}
+		if len(cfg.Schemas) > 1 && *lockFile != "" {
+			fmt.Fprintln(os.Stderr, "Error: -lock-file cannot be used with a config that lists several schemas; set input.lock_file per schema")
+			os.Exit(1)
+		}
+		for _, entry := range entries {
+			job := compileJob{
+				schemaFile:      entry.Input.Schema,
+				annotationFiles: entry.Input.Annotations,
+				onlyServices:    append(append([]string(nil), onlyServices...), entry.Input.OnlyServices...),
+				rootTypes:       append(append([]string(nil), rootTypes...), entry.Input.RootTypes...),
+				excludeServices: append(append([]string(nil), excludeServices...), entry.Input.ExcludeServices...),
+				excludeTypes:    append(append([]string(nil), excludeTypes...), entry.Input.ExcludeTypes...),
+				profile:         entry.Name,
+				lockFile:        *lockFile,
+				outputDirectory: entry.Output.Directory,
+				formats:         configFormats(&entry),
+				apiVersions:     entry.Output.APIVersions,
+				clean:           entry.Output.Clean,
+				headerFile:      *headerFile,
+				stamp:           *stamp || entry.Output.Stamp,
+			}
+			if job.lockFile == "" {
+				job.lockFile = entry.Input.LockFile
+			}
+			if job.headerFile == "" {
+				job.headerFile = entry.Output.HeaderFile
+			}
+			if len(apiVersions) > 0 {
+				job.apiVersions = apiVersions
+			}
+			jobs = append(jobs, job)
+		}
+
+		if cfg.Generators.GraphQL != nil {
+			genOpts.GraphQL.ScalarMappings = cfg.Generators.GraphQL.Scalars
+			genOpts.GraphQL.InputSuffix = cfg.Generators.GraphQL.InputSuffix
+			genOpts.GraphQL.SuffixAllInputs = cfg.Generators.GraphQL.SuffixAllInputs
+			genOpts.GraphQL.Relay = cfg.Generators.GraphQL.Relay
+			genOpts.GraphQL.TypePrefix = cfg.Generators.GraphQL.TypePrefix
+			genOpts.GraphQL.SkipRootTypes = cfg.Generators.GraphQL.SkipRootTypes
+			genOpts.GraphQL.MapScalar = cfg.Generators.GraphQL.MapScalar
+			genOpts.GraphQL.MapEntrySuffix = cfg.Generators.GraphQL.MapEntrySuffix
+		}
+		if cfg.Generators.Protobuf != nil {
+			genOpts.Protobuf.UseWrapperTypes = cfg.Generators.Protobuf.UseWrapperTypes
+			if *protoLayout == "" {
+				genOpts.Protobuf.Layout = generator.ProtobufLayout(cfg.Generators.Protobuf.Layout)
+			}
+		}
+		if cfg.Generators.OpenAPI != nil {
+			genOpts.OpenAPI.ProblemDetails = cfg.Generators.OpenAPI.ProblemDetails
+			genOpts.OpenAPI.ErrorSchemaName = cfg.Generators.OpenAPI.ErrorSchema
+			genOpts.OpenAPI.BytesFormat = generator.OpenAPIBytesFormat(cfg.Generators.OpenAPI.BytesFormat)
+			genOpts.OpenAPI.ProtoJSON = cfg.Generators.OpenAPI.ProtoJSON
+			applyOpenAPISpecConfig(genOpts.OpenAPI, cfg.Generators.OpenAPI)
+		}
+		if cfg.Generators.Go != nil {
+			genOpts.Go.ProtoPackage = cfg.Generators.Go.ProtoPackage
+			genOpts.Go.OptionalFields = generator.GoOptionalFields(cfg.Generators.Go.OptionalFields)
+			if len(cfg.Generators.Go.Types) > 0 {
+				genOpts.Go.TypeMapper = generator.GoTypeMap(cfg.Generators.Go.Types)
+			}
+			genOpts.Go.Scaffold = *scaffold || cfg.Generators.Go.Scaffold
+			if *goModule == "" {
+				genOpts.Go.Module = cfg.Generators.Go.Module
+			}
+			genOpts.Go.GoVersion = cfg.Generators.Go.GoVersion
+		}
+		if cfg.Generators.Java != nil {
+			if *javaStyle == "" {
+				genOpts.Java.Style = cfg.Generators.Java.Style
+			}
+			if *javaPackage == "" {
+				genOpts.Java.PackagePrefix = cfg.Generators.Java.PackagePrefix
+			}
+		}
+		if cfg.Generators.CSharp != nil {
+			if *csharpStyle == "" {
+				genOpts.CSharp.Style = cfg.Generators.CSharp.Style
+			}
+			if *csharpNamespace == "" {
+				genOpts.CSharp.NamespacePrefix = cfg.Generators.CSharp.NamespacePrefix
+			}
+		}
+		if *templatesDir == "" {
+			genOpts.Templates = cfg.Generators.Templates
+		}
+		if *int64Encoding == "" {
+			genOpts.Int64 = generator.Int64Encoding(cfg.Generators.Int64)
+		}
+		applyLimitsConfig(&schemaLimits, cfg.Limits)
+		if unionEncoding, err = cfg.Generators.Unions.UnionEncoding(); err != nil {
+			exitWithError("Error loading config file", err)
+		}
+		if *namingPolicy == "" {
+			if policy, err = cfg.Generators.Naming.Policy(); err != nil {
+				exitWithError("Error loading config file", err)
+			}
+		}
+
+		logger.Infof("Loaded configuration from: %s", *configFile)
+		if len(cfg.Profiles) > 0 {
+			compiled = fmt.Sprintf("Code generation completed successfully for %d profile(s)!", len(jobs))
+		} else if len(jobs) > 1 {
+			compiled = fmt.Sprintf("Code generation completed successfully for %d schemas!", len(jobs))
+		}
+	} else {
+		if len(profiles) > 0 {
+			fmt.Fprintln(os.Stderr, "Error: -profile requires a config file with profiles")
+			os.Exit(1)
+		}
+		// Use command-line flags
+		if *inputFile == "" {
+			fmt.Fprintln(os.Stderr, "Error: -input flag or -config flag is required")
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		job := compileJob{
+			schemaFile:      *inputFile,
+			annotationFiles: annotationFiles,
+			onlyServices:    onlyServices,
+			rootTypes:       rootTypes,
+			excludeServices: excludeServices,
+			excludeTypes:    excludeTypes,
+			lockFile:        *lockFile,
+			outputDirectory: *outputDir,
+			formats:         []string{*outputFormat},
+			apiVersions:     apiVersions,
+			headerFile:      *headerFile,
+			stamp:           *stamp,
+		}
+
+		// Write one file in the format of its extension, or of -format
+		if *outputFile != "" {
+			if explicit["output"] || len(apiVersions) > 0 {
+				fmt.Fprintln(os.Stderr, "Error: -o writes a single file and cannot be used with -output or -api-version")
+				os.Exit(1)
+			}
+			format := *outputFormat
+			if !explicit["format"] {
+				var err error
+				if format, err = fileFormat(*outputFile); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			if format == "all" {
+				fmt.Fprintln(os.Stderr, "Error: -o writes a single file; choose one -format")
+				os.Exit(1)
+			}
+			job.outputFile = *outputFile
+			job.outputDirectory = filepath.Dir(*outputFile)
+			job.formats = []string{format}
+			// A single proto file holds every namespace
+			if *protoLayout == "" {
+				genOpts.Protobuf.Layout = generator.ProtobufLayoutSingle
+			}
+		}
+		jobs = append(jobs, job)
+	}
+
+	if *dryRun {
+		compiled = "Dry run completed; no files were written"
+	}
+	for _, job := range jobs {
+		if job.profile != "" {
+			logger.Infof("Compiling profile %s", job.profile)
+		} else if len(jobs) > 1 {
+			logger.Infof("Compiling %s", job.schemaFile)
+		}
+		job.naming = policy
+		job.unionEncoding = unionEncoding
+		job.dryRun = *dryRun || *check
+		job.check = *check
+		runCompileJob(job, genOpts, *against, *compatPolicy)
+	}
+
+	if *check {
+		if outOfDate > 0 {
+			fmt.Fprintf(os.Stderr, "Error: %d generated file(s) are out of date; run typemux without -check to regenerate them\n", outOfDate)
+			os.Exit(1)
+		}
+		compiled = "Generated files are up to date"
+	}
+
+	logger.Infof("%s", compiled)
+	logger.Verbosef("Finished in %s", logging.Duration(time.Since(started)))
+}
+
+// parseInt64Encoding returns the int64 encoding of a flag value, exiting on unknown encodings
+func parseInt64Encoding(value string) generator.Int64Encoding {
+	switch encoding := generator.Int64Encoding(value); encoding {
+	case "", generator.Int64Number, generator.Int64String, generator.Int64Scalar:
+		return encoding
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown int64 encoding %q (valid: number, string, scalar)\n", value)
+		os.Exit(1)
+		return ""
+	}
+}
+
+// openParseCache opens the parse cache in dir, or in the default directory when dir is empty.
+// Entries are tied to the compiler executable, so rebuilding the compiler invalidates them.
+// It returns nil, which disables the cache, when the directory or executable is unknown.
+func openParseCache(dir string) *parsecache.Cache {
+	if dir == "" {
+		var err error
+		if dir, err = parsecache.DefaultDir(); err != nil {
+			return nil
+		}
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return nil
+	}
+	info, err := os.Stat(executable)
+	if err != nil {
+		return nil
+	}
+	return parsecache.New(dir, fmt.Sprintf("%s %d %d", CurrentTypeMUXVersion, info.Size(), info.ModTime().UnixNano()))
+}
+
+// compileJob is one schema to compile with its annotations, pruning, and output settings
+type compileJob struct {
+	schemaFile      string
+	annotationFiles []string
+	onlyServices    []string
+	rootTypes       []string
+	excludeServices []string
+	excludeTypes    []string
+	profile         string // Name of the config profile, if any
+	lockFile        string
+	outputDirectory string
+	outputFile      string // The single file written by -o, instead of the files of the output directory
+	formats         []string
+	apiVersions     []string // API versions generated into subdirectories, or all
+	clean           bool
+	headerFile      string
+	stamp           bool
+	naming          naming.Policy
+	unionEncoding   *ast.UnionEncoding
+	dryRun          bool // Diff the generated files against the output directory instead of writing them
+	check           bool // With dryRun, list the files that would change instead of their diffs
+}
+
+// selectProfiles returns the entries of the named profiles, in config order
+func selectProfiles(entries []config.SchemaConfig, names []string) ([]config.SchemaConfig, error) {
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted[name] = true
 	}
-	return result
+	var selected []config.SchemaConfig
+	for _, entry := range entries {
+		if wanted[entry.Name] {
+			selected = append(selected, entry)
+			delete(wanted, entry.Name)
+		}
+	}
+	for _, name := range names {
+		if wanted[name] {
+			return nil, fmt.Errorf("unknown profile: %s", name)
+		}
+	}
+	return selected, nil
 }
 
-func generateOpenAPI(schema *ast.Schema, outputDir string) {
-	gen := generator.NewOpenAPIGenerator()
-	output := gen.Generate(schema)
+// applyOpenAPISpecConfig sets the servers, security, and shared parameters of the
+// OpenAPI config on the generator options
+func applyOpenAPISpecConfig(opts *generator.OpenAPIOptions, cfg *config.OpenAPIConfig) {
+	for _, server := range cfg.Servers {
+		opts.Servers = append(opts.Servers, generator.OpenAPIServer{URL: server.URL, Description: server.Description})
+	}
+	opts.Security = cfg.Security
+	if len(cfg.SecuritySchemes) > 0 {
+		opts.SecuritySchemes = make(map[string]generator.OpenAPISecurityScheme)
+	}
+	for name, scheme := range cfg.SecuritySchemes {
+		opts.SecuritySchemes[name] = generator.OpenAPISecurityScheme{
+			Type:             scheme.Type,
+			Description:      scheme.Description,
+			Name:             scheme.Name,
+			In:               scheme.In,
+			Scheme:           scheme.Scheme,
+			BearerFormat:     scheme.BearerFormat,
+			Flows:            scheme.Flows,
+			OpenIDConnectURL: scheme.OpenIDConnectURL,
+		}
+	}
+	if len(cfg.Parameters) > 0 {
+		opts.Parameters = make(map[string]generator.OpenAPIParameter)
+	}
+	for name, param := range cfg.Parameters {
+		schemaType := param.Type
+		if schemaType == "" {
+			schemaType = "string"
+		}
+		opts.Parameters[name] = generator.OpenAPIParameter{
+			Name:        param.Name,
+			In:          param.In,
+			Required:    param.Required,
+			Description: param.Description,
+			Schema:      &generator.OpenAPIParameterSchema{Type: schemaType, Format: param.Format},
+		}
+	}
+}
 
-	outputPath := filepath.Join(outputDir, "openapi.yaml")
-	if err := os.WriteFile(outputPath, []byte(output), 0o600); err != nil {
-		fmt.Printf("Error writing OpenAPI schema: %v\n", err)
-		return
+// configFormats converts the formats of a config entry to -format values
+func configFormats(entry *config.SchemaConfig) []string {
+	if entry.ShouldGenerateFormat("all") {
+		return []string{"all"}
+	}
+	var formats []string
+	for _, format := range []string{"graphql", "protobuf", "openapi", "go", "grpc", "connect", "descriptor", "schema-endpoint", "java", "csharp", "mock", "contract"} {
+		if entry.ShouldGenerateFormat(format) {
+			formats = append(formats, format)
+		}
 	}
-	fmt.Printf("Generated OpenAPI schema: %s\n", outputPath)
+	return formats
 }
 
-func generateGo(schema *ast.Schema, outputDir string) {
-	gen := generator.NewGoGenerator()
-	output := gen.Generate(schema)
+// runCompileJob loads, checks, and prunes a schema and generates its output, exiting on errors
+func runCompileJob(job compileJob, opts generator.Options, against, compatPolicy string) {
+	// Clean output directory if requested
+	if job.clean && !job.dryRun {
+		if err := os.RemoveAll(job.outputDirectory); err != nil {
+			exitWithError("Error cleaning output directory", err)
+		}
+	}
+
+	// Parse the schema with imports
+	parseStart := time.Now()
+	schema, err := loadSchema(job.schemaFile)
+	if err != nil {
+		exitWithError("Error", err)
+	}
+	logger.Verbosef("Parsed %s in %s", job.schemaFile, logging.Duration(time.Since(parseStart)))
+
+	// Load and merge YAML annotations if provided
+	if len(job.annotationFiles) > 0 {
+		if err := mergeAnnotationFiles(schema, job.annotationFiles); err != nil {
+			exitWithError("Error", err)
+		}
+		logger.Infof("Loaded annotations from %d file(s)", len(job.annotationFiles))
+	}
+
+	// Reject names that collide in case or are reserved in an output format
+	if errs := schema.IdentifierErrors(); len(errs) > 0 {
+		found := make([]diagnostic.Diagnostic, len(errs))
+		for i, msg := range errs {
+			found[i] = diagnostic.Diagnostic{File: job.schemaFile, Severity: diagnostic.SeverityError, Message: msg}
+		}
+		exitWithError("Error", diagnostic.New(diagnostic.Validation, fmt.Errorf("invalid identifiers:\n%s", strings.Join(errs, "\n")), found...))
+	}
+
+	// Reject methods whose OpenAPI operations would replace one another or whose
+	// path parameters no request field fills
+	if usesHTTPRoutes(jobFormats(job)) {
+		if errs := append(schema.RouteErrors(), schema.PathParameterErrors()...); len(errs) > 0 {
+			found := make([]diagnostic.Diagnostic, len(errs))
+			for i, msg := range errs {
+				found[i] = diagnostic.Diagnostic{File: job.schemaFile, Severity: diagnostic.SeverityError, Message: msg}
+			}
+			exitWithError("Error", diagnostic.New(diagnostic.Validation, fmt.Errorf("invalid HTTP routes:\n%s", strings.Join(errs, "\n")), found...))
+		}
+	}
+
+	// Recursive types are fine unless every field of the cycle is required
+	for _, cycle := range schema.RequiredCycles() {
+		reportWarning("%s", cycle)
+	}
+
+	// Number fields from the lock file and record new assignments
+	if job.lockFile != "" {
+		lock, err := lockfile.Load(job.lockFile)
+		if err != nil {
+			exitWithError("Error", err)
+		}
+		if err := lock.Apply(schema); err != nil {
+			exitWithError("Error", diagnostic.New(diagnostic.Validation, fmt.Errorf("field numbers conflict with %s:\n%v", job.lockFile, err),
+				diagnostic.Diagnostic{File: job.lockFile, Severity: diagnostic.SeverityError, Message: err.Error()}))
+		}
+		if lock.Changed() && job.check {
+			fmt.Println(filepath.ToSlash(job.lockFile))
+			outOfDate++
+		} else if lock.Changed() && job.dryRun {
+			logger.Infof("Would update lock file: %s", job.lockFile)
+		} else if lock.Changed() {
+			if err := lock.Save(job.lockFile); err != nil {
+				exitWithError("Error", err)
+			}
+			logger.Infof("Updated lock file: %s", job.lockFile)
+		}
+	}
+
+	// Fail on changes that are incompatible with the baseline
+	if against != "" {
+		if err := checkCompatibility(schema, job.schemaFile, against, compatPolicy); err != nil {
+			exitWithError("Error", diagnostic.New(diagnostic.Validation, err))
+		}
+	}
+
+	// Report naming problems, reject names that would collide, then convert
+	// field names to the naming policy
+	if !job.naming.IsZero() {
+		for _, warning := range job.naming.Lint(schema) {
+			reportWarning("%s", warning)
+		}
+		if errs := job.naming.Collisions(schema); len(errs) > 0 {
+			found := make([]diagnostic.Diagnostic, len(errs))
+			for i, msg := range errs {
+				found[i] = diagnostic.Diagnostic{File: job.schemaFile, Severity: diagnostic.SeverityError, Message: msg}
+			}
+			exitWithError("Error", diagnostic.New(diagnostic.Validation, fmt.Errorf("naming collisions:\n%s", strings.Join(errs, "\n")), found...))
+		}
+		job.naming.Apply(schema)
+	}
+
+	// Encode the unions that do not set an encoding as the config file sets
+	if job.unionEncoding != nil {
+		schema.DefaultUnionEncoding(*job.unionEncoding)
+	}
+
+	// Fail on warnings and schema hygiene problems in strict mode
+	if strictMode {
+		for _, warning := range schema.HygieneWarnings() {
+			reportWarning("%s", warning)
+		}
+		if warningCount > 0 {
+			exitWithError("Error", diagnostic.New(diagnostic.Validation, fmt.Errorf("%d warning(s) reported in strict mode", warningCount)))
+		}
+	}
 
-	outputPath := filepath.Join(outputDir, "types.go")
-	if err := os.WriteFile(outputPath, []byte(output), 0o600); err != nil {
-		fmt.Printf("Error writing Go code: %v\n", err)
+	if len(job.apiVersions) == 0 {
+		generateJob(job, pruneJob(job, schema), opts, job.outputDirectory)
 		return
 	}
-	fmt.Printf("Generated Go code: %s\n", outputPath)
+
+	// Generate each API version into its own directory
+	versions := job.apiVersions
+	if len(versions) == 1 && versions[0] == "all" {
+		versions = schema.Versions()
+		if len(versions) == 0 {
+			exitWithError("Error", diagnostic.New(diagnostic.Validation, errors.New("-api-version all: the schema has no @since or @until annotations")))
+		}
+	}
+
+	// Check every version before writing any, so a broken version leaves no
+	// partial output behind
+	versioned := make([]*ast.Schema, len(versions))
+	for i, version := range versions {
+		at, err := graph.AtVersion(schema, version)
+		if err != nil {
+			exitWithError("Error", diagnostic.New(diagnostic.Validation, err))
+		}
+		versioned[i] = pruneJob(job, at)
+	}
+	for i, version := range versions {
+		logger.Infof("Generating API version %s", version)
+		generateJob(job, versioned[i], opts, filepath.Join(job.outputDirectory, version))
+	}
 }
 
-func generateMarkdownDocs(schema *ast.Schema, outputDir string) {
-	gen := docgen.NewMarkdownGenerator()
-	output := gen.Generate(schema)
+// pruneJob prunes a loaded schema to the services and types a job requests,
+// exiting on errors
+func pruneJob(job compileJob, schema *ast.Schema) *ast.Schema {
+	var err error
 
-	outputPath := filepath.Join(outputDir, "API.md")
-	if err := os.WriteFile(outputPath, []byte(output), 0o600); err != nil {
-		fmt.Printf("Error writing Markdown documentation: %v\n", err)
+	// Leave out the excluded services and types, then prune to the requested ones
+	if len(job.excludeServices) > 0 || len(job.excludeTypes) > 0 {
+		schema, err = graph.Exclude(schema, job.excludeServices, job.excludeTypes)
+		if err != nil {
+			exitWithError("Error", diagnostic.New(diagnostic.Validation, err))
+		}
+	}
+	if len(job.onlyServices) > 0 || len(job.rootTypes) > 0 {
+		schema, err = graph.Trim(schema, job.onlyServices, job.rootTypes)
+		if err != nil {
+			exitWithError("Error", diagnostic.New(diagnostic.Validation, err))
+		}
+	}
+	if len(job.excludeServices) > 0 || len(job.excludeTypes) > 0 || len(job.onlyServices) > 0 || len(job.rootTypes) > 0 {
+		logger.Infof("Pruned schema to %d type(s), %d enum(s), %d union(s), and %d service(s)",
+			len(schema.Types), len(schema.Enums), len(schema.Unions), len(schema.Services))
+	}
+	return schema
+}
+
+// generateJob generates the formats of a job from a pruned schema into an
+// output directory, exiting on errors
+func generateJob(job compileJob, schema *ast.Schema, opts generator.Options, outputDirectory string) {
+
+	// Stamp the provenance of the schema into the generated files
+	if job.stamp {
+		metadata, err := generator.NewMetadata(schema)
+		if err != nil {
+			exitWithError("Error", err)
+		}
+		metadata.Commit = gitCommit(job.schemaFile)
+		if source, err := relativePath(outputDirectory, job.schemaFile); err == nil {
+			metadata.Source = filepath.ToSlash(source)
+		}
+		opts.Metadata = metadata
+	}
+
+	// Read the header prepended to the generated files
+	if job.headerFile != "" {
+		header, err := os.ReadFile(job.headerFile)
+		if err != nil {
+			exitWithError("Error reading header file", err)
+		}
+		opts.Header = string(header)
+		opts.HeaderVariables = map[string]string{"typemux_version": CurrentTypeMUXVersion}
+	}
+
+	if job.outputFile != "" {
+		if err := generateOutputFile(context.Background(), schema, job, opts); err != nil {
+			exitWithError("Error", diagnostic.New(diagnostic.Generation, err))
+		}
+		return
+	}
+
+	// Show what would change on disk without writing anything
+	if job.dryRun {
+		files := make(map[string][]byte)
+		for _, name := range jobFormats(job) {
+			rendered, err := renderFormat(context.Background(), schema, name, opts)
+			if err != nil {
+				exitWithError("Error", diagnostic.New(diagnostic.Generation, err))
+			}
+			for path, content := range rendered {
+				files[path] = content
+			}
+		}
+		changed, err := printDryRun(outputDirectory, files, job.clean, job.check)
+		if err != nil {
+			exitWithError("Error", err)
+		}
+		switch {
+		case !job.check:
+			logger.Infof("Dry run: %d file(s) in %s would change", changed, outputDirectory)
+		case changed > 0:
+			logger.Infof("Check: %d file(s) in %s are out of date", changed, outputDirectory)
+		default:
+			logger.Infof("Check: files in %s are up to date", outputDirectory)
+		}
+		outOfDate += changed
 		return
 	}
-	fmt.Printf("Generated Markdown documentation: %s\n", outputPath)
+
+	// Create output directory
+	if err := os.MkdirAll(outputDirectory, 0o750); err != nil {
+		exitWithError("Error creating output directory", diagnostic.New(diagnostic.Generation, err))
+	}
+
+	// Generate output based on formats
+	for _, name := range jobFormats(job) {
+		if err := generateFormat(context.Background(), schema, outputDirectory, name, opts); err != nil {
+			exitWithError("Error", diagnostic.New(diagnostic.Generation, err))
+		}
+	}
+}
+
+// jobFormats returns the formats a job generates, expanding all
+func jobFormats(job compileJob) []string {
+	var formats []string
+	for _, format := range job.formats {
+		if format == "all" {
+			formats = append(formats, allFormats...)
+		} else {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// usesHTTPRoutes reports whether any of the formats serves methods at HTTP routes
+func usesHTTPRoutes(formats []string) bool {
+	for _, format := range formats {
+		switch strings.ToLower(format) {
+		case "openapi", "mock", "contract":
+			return true
+		}
+	}
+	return false
+}
+
+// outOfDate counts the generated files and lock files that -check found would change
+var outOfDate int
+
+// printDryRun prints a unified diff of the generated files against the files in
+// the output directory, including the files a clean output directory would lose,
+// or only their paths with namesOnly, and returns the number of files that would
+// change
+func printDryRun(outputDir string, files map[string][]byte, clean, namesOnly bool) (int, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	changed := 0
+	for _, path := range paths {
+		label := filepath.ToSlash(filepath.Join(outputDir, filepath.FromSlash(path)))
+		oldLabel := label
+		existing, err := os.ReadFile(filepath.Join(outputDir, filepath.FromSlash(path)))
+		if errors.Is(err, os.ErrNotExist) {
+			oldLabel = "/dev/null"
+		} else if err != nil {
+			return changed, err
+		}
+		if diff := textdiff.Unified(oldLabel, label, existing, files[path]); diff != "" {
+			if namesOnly {
+				diff = label + "\n"
+			}
+			fmt.Print(diff)
+			changed++
+		}
+	}
+
+	if !clean {
+		return changed, nil
+	}
+	err := filepath.WalkDir(outputDir, func(file string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(outputDir, file)
+		if err != nil {
+			return err
+		}
+		if _, generated := files[filepath.ToSlash(rel)]; generated {
+			return nil
+		}
+		existing, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if namesOnly {
+			fmt.Println(filepath.ToSlash(file))
+		} else {
+			fmt.Print(textdiff.Unified(filepath.ToSlash(file), "/dev/null", existing, nil))
+		}
+		changed++
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	return changed, err
+}
+
+// allFormats are the formats generated by -format all
+var allFormats = []string{"graphql", "protobuf", "openapi", "go", "markdown"}
+
+// formatDescriptions describe the output of each format, and its aliases, in progress messages
+var formatDescriptions = map[string]string{
+	"graphql":         "GraphQL schema",
+	"protobuf":        "Protobuf schema",
+	"proto":           "Protobuf schema",
+	"openapi":         "OpenAPI schema",
+	"go":              "Go code",
+	"golang":          "Go code",
+	"grpc":            "Go gRPC stubs",
+	"connect":         "Go Connect handlers",
+	"descriptor":      "Protobuf descriptor set",
+	"schema-endpoint": "Go schema endpoint",
+	"java":            "Java code",
+	"csharp":          "C# code",
+	"cs":              "C# code",
+	"mock":            "mock server",
+	"contract":        "contract tests",
+	"markdown":        "Markdown documentation",
+	"md":              "Markdown documentation",
+	"docs":            "Markdown documentation",
+	"html":            "HTML documentation",
+}
+
+// outputGenerator returns the generator of a code or documentation format
+func outputGenerator(format string) (generator.Generator, error) {
+	if _, ok := formatDescriptions[format]; !ok {
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+	if gen, err := docgen.Lookup(format); err == nil {
+		return gen, nil
+	}
+	return generator.Lookup(format)
+}
+
+// renderFormat generates the files of a format in memory, keyed by slash-separated path
+func renderFormat(ctx context.Context, schema *ast.Schema, format string, opts generator.Options) (map[string][]byte, error) {
+	gen, err := outputGenerator(format)
+	if err != nil {
+		return nil, err
+	}
+	files, err := gen.Generate(ctx, schema, opts)
+	if err != nil {
+		return nil, fmt.Errorf("generating %s: %w", format, err)
+	}
+	return files, nil
+}
+
+// generateFormat generates the files of a format and writes them to the output directory
+func generateFormat(ctx context.Context, schema *ast.Schema, outputDir, format string, opts generator.Options) error {
+	start := time.Now()
+	files, err := renderFormat(ctx, schema, format, opts)
+	if err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	size, unchanged := 0, 0
+	for _, path := range paths {
+		outputPath := filepath.Join(outputDir, filepath.FromSlash(path))
+		written, err := writeIfChanged(outputPath, files[path])
+		if err != nil {
+			return err
+		}
+		if written {
+			logger.Debugf("Wrote %s (%d bytes)", outputPath, len(files[path]))
+		} else {
+			logger.Debugf("Unchanged %s", outputPath)
+			unchanged++
+		}
+		size += len(files[path])
+	}
+
+	description := formatDescriptions[format]
+	switch {
+	case len(paths) == 1 && unchanged == 1:
+		logger.Infof("Generated %s: %s (unchanged)", description, filepath.Join(outputDir, filepath.FromSlash(paths[0])))
+	case len(paths) == 1:
+		logger.Infof("Generated %s: %s", description, filepath.Join(outputDir, filepath.FromSlash(paths[0])))
+	case unchanged > 0:
+		logger.Infof("Generated %s: %d file(s) in %s (%d unchanged)", description, len(paths), filepath.Join(outputDir, filepath.FromSlash(commonDir(paths))), unchanged)
+	default:
+		logger.Infof("Generated %s: %d file(s) in %s", description, len(paths), filepath.Join(outputDir, filepath.FromSlash(commonDir(paths))))
+	}
+	logger.Verbosef("Generator %s took %s (%d file(s), %d bytes)", format, logging.Duration(elapsed), len(paths), size)
+	return nil
+}
+
+// fileFormats are the formats of the file extensions of -o, whose output is a
+// single file
+var fileFormats = map[string]string{
+	".graphql": "graphql",
+	".gql":     "graphql",
+	".proto":   "protobuf",
+	".yaml":    "openapi",
+	".yml":     "openapi",
+	".pb":      "descriptor",
+	".binpb":   "descriptor",
+	".desc":    "descriptor",
+	".cs":      "csharp",
+	".md":      "markdown",
+}
+
+// fileFormat returns the format of an -o file from its extension
+func fileFormat(file string) (string, error) {
+	extension := strings.ToLower(filepath.Ext(file))
+	if format, ok := fileFormats[extension]; ok {
+		return format, nil
+	}
+	extensions := make([]string, 0, len(fileFormats))
+	for extension := range fileFormats {
+		extensions = append(extensions, extension)
+	}
+	sort.Strings(extensions)
+	return "", fmt.Errorf("cannot infer the format of %s from its extension (known: %s); set -format", file, strings.Join(extensions, ", "))
+}
+
+// generateOutputFile generates the single file of the format of a job and
+// writes it to the -o file of the job, or prints how it would change it
+func generateOutputFile(ctx context.Context, schema *ast.Schema, job compileJob, opts generator.Options) error {
+	format := job.formats[0]
+	files, err := renderFormat(ctx, schema, format, opts)
+	if err != nil {
+		return err
+	}
+	if len(files) != 1 {
+		return fmt.Errorf("the %s format generates %d files, which -o cannot write to %s; use -output", format, len(files), job.outputFile)
+	}
+	var content []byte
+	for _, file := range files {
+		content = file
+	}
+
+	if job.dryRun {
+		changed, err := printDryRun(filepath.Dir(job.outputFile), map[string][]byte{filepath.Base(job.outputFile): content}, false, job.check)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !job.check:
+			logger.Infof("Dry run: %d file(s) would change", changed)
+		case changed > 0:
+			logger.Infof("Check: %s is out of date", job.outputFile)
+		default:
+			logger.Infof("Check: %s is up to date", job.outputFile)
+		}
+		outOfDate += changed
+		return nil
+	}
+
+	written, err := writeIfChanged(job.outputFile, content)
+	if err != nil {
+		return err
+	}
+	if !written {
+		logger.Infof("Generated %s: %s (unchanged)", formatDescriptions[format], job.outputFile)
+		return nil
+	}
+	logger.Infof("Generated %s: %s", formatDescriptions[format], job.outputFile)
+	return nil
+}
+
+// writeIfChanged writes a generated file unless the file on disk already has
+// its content, so that unchanged files keep their modification times, and
+// reports whether it wrote the file
+func writeIfChanged(path string, content []byte) (bool, error) {
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return false, fmt.Errorf("creating directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return false, fmt.Errorf("writing %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// commonDir returns the deepest directory that contains every slash-separated path
+func commonDir(paths []string) string {
+	dir := path.Dir(paths[0])
+	for _, p := range paths[1:] {
+		for dir != "." && !strings.HasPrefix(p, dir+"/") {
+			dir = path.Dir(dir)
+		}
+	}
+	return dir
+}
+
+// checkCompatibility compares the schema with a baseline and fails on changes the policy rejects
+func checkCompatibility(schema *ast.Schema, schemaFile, against, policyName string) error {
+	policy, err := diff.ParsePolicy(policyName)
+	if err != nil {
+		return err
+	}
+
+	baseline, err := loadBaselineSchema(schemaFile, against)
+	if err != nil {
+		return fmt.Errorf("failed to load baseline %s: %w", against, err)
+	}
+
+	violations := policy.Violations(diff.NewDiffer(baseline, schema).Compare())
+	if len(violations) == 0 {
+		logger.Infof("No incompatible changes against %s (policy %s)", against, policy)
+		return nil
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Path != violations[j].Path {
+			return violations[i].Path < violations[j].Path
+		}
+		return violations[i].Type < violations[j].Type
+	})
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d incompatible change(s) against %s (policy %s):", len(violations), against, policy))
+	for _, change := range violations {
+		sb.WriteString(fmt.Sprintf("\n  %s: %s", change.Path, change.Description))
+		if change.OldValue != "" && change.NewValue != "" {
+			sb.WriteString(fmt.Sprintf(" (%s → %s)", change.OldValue, change.NewValue))
+		}
+	}
+	return errors.New(sb.String())
+}
+
+// relativePath returns the path of target relative to the directory base
+func relativePath(base, target string) (string, error) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(absBase, absTarget)
+}
+
+// gitCommit returns the commit checked out in the Git repository of a file, or
+// an empty string when it is not in one
+func gitCommit(file string) string {
+	out, err := exec.Command("git", "-C", filepath.Dir(file), "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// loadBaselineSchema parses the baseline schema from a file, or from the schema file
+// as it was at a Git ref
+func loadBaselineSchema(schemaFile, against string) (*ast.Schema, error) {
+	warningsMuted = true
+	defer func() { warningsMuted = false }()
+
+	if info, err := os.Stat(against); err == nil && !info.IsDir() {
+		return loadSchema(against)
+	}
+
+	absPath, err := filepath.Abs(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+	if absPath, err = filepath.EvalSymlinks(absPath); err != nil {
+		return nil, err
+	}
+	out, err := exec.Command("git", "-C", filepath.Dir(absPath), "rev-parse", "--show-toplevel").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("not a file and not in a Git repository: %s", strings.TrimSpace(string(out)))
+	}
+	root := strings.TrimSpace(string(out))
+	relPath, err := filepath.Rel(root, absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract the whole tree so that imports outside the schema directory resolve too
+	archive, err := exec.Command("git", "-C", root, "archive", "--format=tar", against).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("git archive failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "typemux-baseline-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := extractTar(archive, dir); err != nil {
+		return nil, err
+	}
+
+	return parseSchemaWithImports(filepath.Join(dir, relPath))
+}
+
+// extractTar writes the directories and regular files of a tar archive below dir
+func extractTar(archive []byte, dir string) error {
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !filepath.IsLocal(header.Name) {
+			continue
+		}
+		target := filepath.Join(dir, header.Name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o750); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+				return err
+			}
+			content, err := io.ReadAll(reader)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(target, content, 0o600); err != nil {
+				return err
+			}
+		}
+	}
 }
 
 // validateTypeMUXVersion validates that the schema's TypeMUX version is compatible
 func validateTypeMUXVersion(schemaVersion, filePath string) error {
 	// If no version is specified, accept it (backward compatibility)
 	if schemaVersion == "" {
-		fmt.Printf("Warning: No @typemux version specified in %s\n", filePath)
+		reportDiagnostic(diagnostic.Diagnostic{File: filePath, Message: fmt.Sprintf("No @typemux version specified in %s", filePath)})
 		return nil
 	}
 
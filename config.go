@@ -36,6 +36,12 @@ type InputConfig struct {
 
 	// BaseDir is the base directory for resolving relative imports
 	BaseDir string
+
+	// OnlyServices prunes the schema to these services and the types they reference
+	OnlyServices []string
+
+	// RootTypes keeps these types and everything they reference when pruning the schema
+	RootTypes []string
 }
 
 // OutputConfig defines output settings for generated code.
@@ -59,12 +65,24 @@ type GeneratorConfig struct {
 	Protobuf *ProtobufConfig
 	OpenAPI  *OpenAPIConfig
 	Go       *GoConfig
+
+	// Int64 is the JSON encoding of int64 and uint64 values in the GraphQL,
+	// OpenAPI, and Go output: number (default), string, or scalar.
+	Int64 string
 }
 
 // GraphQLConfig configures the GraphQL generator.
 type GraphQLConfig struct {
 	Filename          string
 	IncludeDeprecated bool
+	Scalars           map[string]string // Map builtin types to custom GraphQL scalars
+	InputSuffix       string            // Suffix for input variants of types (default: Input)
+	SuffixAllInputs   bool              // Suffix every input type, not only those also used as outputs
+	Relay             bool              // Add the Relay Node interface and node query
+	TypePrefix        string            // Prefix every generated type name, for schema stitching
+	SkipRootTypes     bool              // Omit the Query, Mutation, and Subscription types
+	MapScalar         string            // Render maps as this scalar instead of lists of entry types
+	MapEntrySuffix    string            // Suffix of the names of map entry types (default: Entry)
 }
 
 // ProtobufConfig configures the Protobuf generator.
@@ -72,12 +90,17 @@ type ProtobufConfig struct {
 	Filename          string
 	ImportBufValidate bool
 	PackagePrefix     string
+	UseWrapperTypes   bool // Map optional scalars to google.protobuf wrapper types
 }
 
 // OpenAPIConfig configures the OpenAPI generator.
 type OpenAPIConfig struct {
-	Filename string
-	Version  string // e.g., "3.0.0", "3.1.0"
+	Filename       string
+	Version        string // e.g., "3.0.0", "3.1.0"
+	ProblemDetails bool   // Use RFC 7807 application/problem+json error responses
+	ErrorSchema    string // Name of the shared error schema component
+	BytesFormat    string // Format of bytes fields: byte (base64, default) or binary
+	ProtoJSON      bool   // Describe the proto3 JSON mapping, as served by grpc-gateway
 }
 
 // GoConfig configures the Go generator.
@@ -86,6 +109,7 @@ type GoConfig struct {
 	PackageName  string
 	JSONTags     bool
 	ValidateTags bool
+	ProtoPackage string // Go import path of the protoc-gen-go output, enables ToProto/FromProto conversions
 }
 
 // NewConfig creates a new configuration with default values.
@@ -225,17 +249,30 @@ func (c *Config) getGeneratorConfig(format string) map[string]interface{} {
 		if c.Generators.GraphQL != nil {
 			config["filename"] = c.Generators.GraphQL.Filename
 			config["include_deprecated"] = c.Generators.GraphQL.IncludeDeprecated
+			config["scalars"] = c.Generators.GraphQL.Scalars
+			config["input_suffix"] = c.Generators.GraphQL.InputSuffix
+			config["suffix_all_inputs"] = c.Generators.GraphQL.SuffixAllInputs
+			config["relay"] = c.Generators.GraphQL.Relay
+			config["type_prefix"] = c.Generators.GraphQL.TypePrefix
+			config["skip_root_types"] = c.Generators.GraphQL.SkipRootTypes
+			config["map_scalar"] = c.Generators.GraphQL.MapScalar
+			config["map_entry_suffix"] = c.Generators.GraphQL.MapEntrySuffix
 		}
 	case "protobuf", "proto":
 		if c.Generators.Protobuf != nil {
 			config["filename"] = c.Generators.Protobuf.Filename
 			config["import_buf_validate"] = c.Generators.Protobuf.ImportBufValidate
 			config["package_prefix"] = c.Generators.Protobuf.PackagePrefix
+			config["use_wrapper_types"] = c.Generators.Protobuf.UseWrapperTypes
 		}
 	case "openapi":
 		if c.Generators.OpenAPI != nil {
 			config["filename"] = c.Generators.OpenAPI.Filename
 			config["version"] = c.Generators.OpenAPI.Version
+			config["problem_details"] = c.Generators.OpenAPI.ProblemDetails
+			config["error_schema"] = c.Generators.OpenAPI.ErrorSchema
+			config["bytes_format"] = c.Generators.OpenAPI.BytesFormat
+			config["proto_json"] = c.Generators.OpenAPI.ProtoJSON
 		}
 	case "go", "golang":
 		if c.Generators.Go != nil {
@@ -243,6 +280,15 @@ func (c *Config) getGeneratorConfig(format string) map[string]interface{} {
 			config["package_name"] = c.Generators.Go.PackageName
 			config["json_tags"] = c.Generators.Go.JSONTags
 			config["validate_tags"] = c.Generators.Go.ValidateTags
+			config["proto_package"] = c.Generators.Go.ProtoPackage
+		}
+	}
+
+	// The int64 encoding applies to every JSON-facing format
+	switch format {
+	case "graphql", "openapi", "go", "golang":
+		if c.Generators.Int64 != "" {
+			config["int64"] = c.Generators.Int64
 		}
 	}
 
@@ -286,6 +332,18 @@ func (b *ConfigBuilder) WithBaseDir(dir string) *ConfigBuilder {
 	return b
 }
 
+// WithOnlyServices prunes the schema to the given services and the types they reference.
+func (b *ConfigBuilder) WithOnlyServices(services ...string) *ConfigBuilder {
+	b.config.Input.OnlyServices = append(b.config.Input.OnlyServices, services...)
+	return b
+}
+
+// WithRootTypes keeps the given types and everything they reference when pruning the schema.
+func (b *ConfigBuilder) WithRootTypes(types ...string) *ConfigBuilder {
+	b.config.Input.RootTypes = append(b.config.Input.RootTypes, types...)
+	return b
+}
+
 // WithOutputDir sets the output directory for generated files.
 func (b *ConfigBuilder) WithOutputDir(dir string) *ConfigBuilder {
 	b.config.Output.Directory = dir
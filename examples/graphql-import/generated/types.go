@@ -2,89 +2,248 @@
 package graphql
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 )
 
-type UserStatus int
+// User status enumeration
+type UserStatus int32
 
 const (
-	UserStatusACTIVE    UserStatus = 0
-	UserStatusINACTIVE  UserStatus = 1
+	// User account is active
+	UserStatusACTIVE UserStatus = 0
+	// User account is inactive
+	UserStatusINACTIVE UserStatus = 1
+	// User account is suspended
 	UserStatusSUSPENDED UserStatus = 2
 )
 
+// userStatusNames maps UserStatus values to their names.
+var userStatusNames = map[UserStatus]string{
+	UserStatusACTIVE: "ACTIVE",
+	UserStatusINACTIVE: "INACTIVE",
+	UserStatusSUSPENDED: "SUSPENDED",
+}
+
+// userStatusValues maps names to UserStatus values.
+var userStatusValues = map[string]UserStatus{
+	"ACTIVE": UserStatusACTIVE,
+	"INACTIVE": UserStatusINACTIVE,
+	"SUSPENDED": UserStatusSUSPENDED,
+}
+
+// String returns the name of the value, or UserStatus(n) for unknown values.
+func (x UserStatus) String() string {
+	if name, ok := userStatusNames[x]; ok {
+		return name
+	}
+	return fmt.Sprintf("UserStatus(%d)", int32(x))
+}
+
+// ParseUserStatus returns the UserStatus with the given name.
+func ParseUserStatus(name string) (UserStatus, error) {
+	if value, ok := userStatusValues[name]; ok {
+		return value, nil
+	}
+	return UserStatusACTIVE, fmt.Errorf("unknown UserStatus %q", name)
+}
+
+// MarshalJSON encodes the value as its name, or as a number for unknown values.
+func (x UserStatus) MarshalJSON() ([]byte, error) {
+	if name, ok := userStatusNames[x]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int32(x))
+}
+
+// UnmarshalJSON decodes a name or a number. Unknown names decode to UserStatusACTIVE.
+func (x *UserStatus) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		value, ok := userStatusValues[name]
+		if !ok {
+			value = UserStatusACTIVE
+		}
+		*x = value
+		return nil
+	}
+
+	var number int32
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid UserStatus: %s", data)
+	}
+	*x = UserStatus(number)
+	return nil
+}
+
+// Input for creating a new user
 type CreateUserInput struct {
-	Name  string   `json:"name"`
-	Email string   `json:"email"`
-	Age   int32    `json:"age"`
-	Tags  []string `json:"tags"`
+	// User's display name
+	Name string `json:"name"`
+	// User's email address
+	Email string `json:"email"`
+	// User's age (optional)
+	Age int32 `json:"age"`
+	// Initial tags for the user
+	Tags []string `json:"tags"`
 }
 
+// Input for updating an existing user
 type UpdateUserInput struct {
-	Name   string     `json:"name"`
-	Email  string     `json:"email"`
-	Age    int32      `json:"age"`
+	// User's display name
+	Name string `json:"name"`
+	// User's email address
+	Email string `json:"email"`
+	// User's age
+	Age int32 `json:"age"`
+	// Current status of the user account
 	Status UserStatus `json:"status"`
 }
 
+// User represents a user account in the system
 type User struct {
-	Id         string     `json:"id"`
-	Name       string     `json:"name"`
-	Email      string     `json:"email"`
-	Age        int32      `json:"age"`
-	Status     UserStatus `json:"status"`
-	CreatedAt  time.Time  `json:"createdAt"`
-	Tags       []string   `json:"tags"`
-	IsVerified bool       `json:"isVerified"`
+	// Unique identifier for the user
+	Id string `json:"id"`
+	// User's display name
+	Name string `json:"name"`
+	// User's email address
+	Email string `json:"email"`
+	// User's age (optional)
+	Age int32 `json:"age"`
+	// Current status of the user account
+	Status UserStatus `json:"status"`
+	// Timestamp when the user was created
+	CreatedAt time.Time `json:"createdAt"`
+	// List of tags associated with the user
+	Tags []string `json:"tags"`
+	// Whether the user account is verified
+	IsVerified bool `json:"isVerified"`
 }
 
+// Result of user creation
 type CreateUserResult struct {
-	User    User   `json:"user"`
+	// The newly created user
+	User User `json:"user"`
+	// Success message
 	Message string `json:"message"`
 }
 
-type Boolean struct {
-	Value bool `json:"value"`
-}
-
 type GetUserRequest struct {
 	Id string `json:"id"`
 }
 
+// Validate checks GetUserRequest against the constraints declared in the schema.
+func (m *GetUserRequest) Validate() error {
+	var errs []error
+	if m.Id == "" {
+		errs = append(errs, errors.New("id: is required"))
+	}
+	return errors.Join(errs...)
+}
+
 type ListUsersRequest struct {
-	Limit  int32      `json:"limit"`
-	Offset int32      `json:"offset"`
+	// Maximum number of users to return
+	Limit int32 `json:"limit"`
+	// Offset for pagination
+	Offset int32 `json:"offset"`
+	// Filter by user status
 	Status UserStatus `json:"status"`
 }
 
+type ListUsersResponse struct {
+	Items []User `json:"items"`
+}
+
 type SearchUsersRequest struct {
 	Query string `json:"query"`
 }
 
-type CreateUserRequest struct {
-	Input CreateUserInput `json:"input"`
+// Validate checks SearchUsersRequest against the constraints declared in the schema.
+func (m *SearchUsersRequest) Validate() error {
+	var errs []error
+	if m.Query == "" {
+		errs = append(errs, errors.New("query: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type SearchUsersResponse struct {
+	Items []User `json:"items"`
 }
 
 type UpdateUserRequest struct {
-	Id    string          `json:"id"`
+	// ID of the user to update
+	Id string `json:"id"`
+	// Fields to update
 	Input UpdateUserInput `json:"input"`
 }
 
+// Validate checks UpdateUserRequest against the constraints declared in the schema.
+func (m *UpdateUserRequest) Validate() error {
+	var errs []error
+	if m.Id == "" {
+		errs = append(errs, errors.New("id: is required"))
+	}
+	return errors.Join(errs...)
+}
+
 type DeleteUserRequest struct {
+	// ID of the user to delete
 	Id string `json:"id"`
 }
 
+// Validate checks DeleteUserRequest against the constraints declared in the schema.
+func (m *DeleteUserRequest) Validate() error {
+	var errs []error
+	if m.Id == "" {
+		errs = append(errs, errors.New("id: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type DeleteUserResponse struct {
+	Value bool `json:"value"`
+}
+
 type VerifyUserEmailRequest struct {
+	// User ID
 	UserId string `json:"userId"`
-	Token  string `json:"token"`
+	// Verification token
+	Token string `json:"token"`
+}
+
+// Validate checks VerifyUserEmailRequest against the constraints declared in the schema.
+func (m *VerifyUserEmailRequest) Validate() error {
+	var errs []error
+	if m.UserId == "" {
+		errs = append(errs, errors.New("userId: is required"))
+	}
+	if m.Token == "" {
+		errs = append(errs, errors.New("token: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type VerifyUserEmailResponse struct {
+	Value bool `json:"value"`
 }
 
 type GraphQLService interface {
+	// Get a user by their ID
 	GetUser(input *GetUserRequest) (*User, error)
-	ListUsers(input *ListUsersRequest) (*User, error)
-	SearchUsers(input *SearchUsersRequest) (*User, error)
-	CreateUser(input *CreateUserRequest) (*CreateUserResult, error)
+	// List all users with optional filtering
+	ListUsers(input *ListUsersRequest) (*ListUsersResponse, error)
+	// Search users by name or email
+	SearchUsers(input *SearchUsersRequest) (*SearchUsersResponse, error)
+	// Create a new user account
+	CreateUser(input *CreateUserInput) (*CreateUserResult, error)
+	// Update an existing user account
 	UpdateUser(input *UpdateUserRequest) (*User, error)
-	DeleteUser(input *DeleteUserRequest) (*Boolean, error)
-	VerifyUserEmail(input *VerifyUserEmailRequest) (*Boolean, error)
+	// Delete a user account
+	DeleteUser(input *DeleteUserRequest) (*DeleteUserResponse, error)
+	// Verify a user's email address
+	VerifyUserEmail(input *VerifyUserEmailRequest) (*VerifyUserEmailResponse, error)
 }
+
@@ -2,86 +2,179 @@
 package petstoreapi
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 )
 
-type PetStatus int
+type PetStatus int32
 
 const (
 	PetStatusAVAILABLE PetStatus = 0
-	PetStatusPENDING   PetStatus = 1
-	PetStatusSOLD      PetStatus = 2
+	PetStatusPENDING PetStatus = 1
+	PetStatusSOLD PetStatus = 2
 )
 
-type Error struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details"`
+// petStatusNames maps PetStatus values to their names.
+var petStatusNames = map[PetStatus]string{
+	PetStatusAVAILABLE: "AVAILABLE",
+	PetStatusPENDING: "PENDING",
+	PetStatusSOLD: "SOLD",
 }
 
-type Pet struct {
-	Id        string    `json:"id"`
-	Name      string    `json:"name"`
-	Status    string    `json:"status"`
-	Species   string    `json:"species"`
-	Breed     string    `json:"breed"`
-	Age       int32     `json:"age"`
-	Tags      []string  `json:"tags"`
-	CreatedAt time.Time `json:"createdAt"`
+// petStatusValues maps names to PetStatus values.
+var petStatusValues = map[string]PetStatus{
+	"AVAILABLE": PetStatusAVAILABLE,
+	"PENDING": PetStatusPENDING,
+	"SOLD": PetStatusSOLD,
+}
+
+// String returns the name of the value, or PetStatus(n) for unknown values.
+func (x PetStatus) String() string {
+	if name, ok := petStatusNames[x]; ok {
+		return name
+	}
+	return fmt.Sprintf("PetStatus(%d)", int32(x))
+}
+
+// ParsePetStatus returns the PetStatus with the given name.
+func ParsePetStatus(name string) (PetStatus, error) {
+	if value, ok := petStatusValues[name]; ok {
+		return value, nil
+	}
+	return PetStatusAVAILABLE, fmt.Errorf("unknown PetStatus %q", name)
+}
+
+// MarshalJSON encodes the value as its name, or as a number for unknown values.
+func (x PetStatus) MarshalJSON() ([]byte, error) {
+	if name, ok := petStatusNames[x]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int32(x))
+}
+
+// UnmarshalJSON decodes a name or a number. Unknown names decode to PetStatusAVAILABLE.
+func (x *PetStatus) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		value, ok := petStatusValues[name]
+		if !ok {
+			value = PetStatusAVAILABLE
+		}
+		*x = value
+		return nil
+	}
+
+	var number int32
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid PetStatus: %s", data)
+	}
+	*x = PetStatus(number)
+	return nil
+}
+
+type Error struct {
+	Code string `json:"code"`
+	Details string `json:"details"`
+	Message string `json:"message"`
 }
 
 type NewPet struct {
-	Name    string   `json:"name"`
-	Status  string   `json:"status"`
-	Species string   `json:"species"`
-	Breed   string   `json:"breed"`
-	Age     int32    `json:"age"`
-	Tags    []string `json:"tags"`
+	Age int32 `json:"age"`
+	Breed string `json:"breed"`
+	Name string `json:"name"`
+	Species string `json:"species"`
+	Status string `json:"status"`
+	Tags []string `json:"tags"`
 }
 
-type UpdatePet struct {
-	Age    int32    `json:"age"`
-	Tags   []string `json:"tags"`
-	Name   string   `json:"name"`
-	Status string   `json:"status"`
-	Breed  string   `json:"breed"`
+type Pet struct {
+	Age int32 `json:"age"`
+	Breed string `json:"breed"`
+	CreatedAt time.Time `json:"createdAt"`
+	Id string `json:"id"`
+	Name string `json:"name"`
+	Species string `json:"species"`
+	Status string `json:"status"`
+	Tags []string `json:"tags"`
 }
 
 type PetList struct {
-	Total      int32 `json:"total"`
 	NextOffset int32 `json:"nextOffset"`
-	Pets       []Pet `json:"pets"`
+	Pets []Pet `json:"pets"`
+	Total int32 `json:"total"`
 }
 
-type Empty struct {
+type UpdatePet struct {
+	Age int32 `json:"age"`
+	Breed string `json:"breed"`
+	Name string `json:"name"`
+	Status string `json:"status"`
+	Tags []string `json:"tags"`
 }
 
 type ListPetsRequest struct {
-	Limit  int32 `json:"limit"`
+	// Maximum number of pets to return
+	Limit int32 `json:"limit"`
+	// Number of pets to skip
 	Offset int32 `json:"offset"`
 }
 
-type CreatePetRequest struct {
-	Pet NewPet `json:"pet"`
-}
-
 type GetPetByIdRequest struct {
+	// ID of the pet to retrieve
 	PetId string `json:"petId"`
 }
 
+// Validate checks GetPetByIdRequest against the constraints declared in the schema.
+func (m *GetPetByIdRequest) Validate() error {
+	var errs []error
+	if m.PetId == "" {
+		errs = append(errs, errors.New("petId: is required"))
+	}
+	return errors.Join(errs...)
+}
+
 type UpdatePetRequest struct {
-	PetId string    `json:"petId"`
-	Pet   UpdatePet `json:"pet"`
+	// ID of the pet to update
+	PetId string `json:"petId"`
+	Body UpdatePet `json:"body"`
+}
+
+// Validate checks UpdatePetRequest against the constraints declared in the schema.
+func (m *UpdatePetRequest) Validate() error {
+	var errs []error
+	if m.PetId == "" {
+		errs = append(errs, errors.New("petId: is required"))
+	}
+	return errors.Join(errs...)
 }
 
 type DeletePetRequest struct {
+	// ID of the pet to delete
 	PetId string `json:"petId"`
 }
 
-type PetStoreAPIService interface {
+// Validate checks DeletePetRequest against the constraints declared in the schema.
+func (m *DeletePetRequest) Validate() error {
+	var errs []error
+	if m.PetId == "" {
+		errs = append(errs, errors.New("petId: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+// Operations related to pets
+type PetsService interface {
+	// Returns a list of all pets in the store
 	ListPets(input *ListPetsRequest) (*PetList, error)
-	CreatePet(input *CreatePetRequest) (*Pet, error)
+	// Creates a new pet in the store
+	CreatePet(input *NewPet) (*Pet, error)
+	// Returns details for a specific pet
 	GetPetById(input *GetPetByIdRequest) (*Pet, error)
+	// Updates an existing pet
 	UpdatePet(input *UpdatePetRequest) (*Pet, error)
-	DeletePet(input *DeletePetRequest) (*Empty, error)
+	// Deletes a pet from the store
+	DeletePet(input *DeletePetRequest) error
 }
+
@@ -173,11 +173,23 @@ func (f *GeneratorFactory) HasFormat(format string) bool {
 // This is the main method used when processing from a Config object.
 func (f *GeneratorFactory) GenerateWithConfig(config *Config) (map[string]string, error) {
 	// Parse schema with annotations
-	schema, err := ParseWithAnnotations(config.Input.Schema, config.Input.Annotations...)
+	schema, err := Parse(ParseOptions{
+		Schema:      config.Input.Schema,
+		Annotations: config.Input.Annotations,
+		BaseDir:     config.Input.BaseDir,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse schema: %w", err)
 	}
 
+	// Prune the schema to the requested services and root types
+	if len(config.Input.OnlyServices) > 0 || len(config.Input.RootTypes) > 0 {
+		schema, err = TrimSchema(schema, config.Input.OnlyServices, config.Input.RootTypes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to trim schema: %w", err)
+		}
+	}
+
 	outputs := make(map[string]string)
 
 	// Generate requested formats
@@ -228,6 +240,39 @@ func (g *builtinGraphQLGenerator) Generate(schema *Schema) (string, error) {
 	return gen.Generate(schema), nil
 }
 
+func (g *builtinGraphQLGenerator) GenerateWithConfig(schema *Schema, config map[string]interface{}) (string, error) {
+	opts := &generator.GraphQLOptions{}
+	if scalars, ok := config["scalars"].(map[string]string); ok {
+		opts.ScalarMappings = scalars
+	}
+	if suffix, ok := config["input_suffix"].(string); ok {
+		opts.InputSuffix = suffix
+	}
+	if suffixAll, ok := config["suffix_all_inputs"].(bool); ok {
+		opts.SuffixAllInputs = suffixAll
+	}
+	if relay, ok := config["relay"].(bool); ok {
+		opts.Relay = relay
+	}
+	if prefix, ok := config["type_prefix"].(string); ok {
+		opts.TypePrefix = prefix
+	}
+	if skip, ok := config["skip_root_types"].(bool); ok {
+		opts.SkipRootTypes = skip
+	}
+	if scalar, ok := config["map_scalar"].(string); ok {
+		opts.MapScalar = scalar
+	}
+	if suffix, ok := config["map_entry_suffix"].(string); ok {
+		opts.MapEntrySuffix = suffix
+	}
+	if encoding, ok := config["int64"].(string); ok {
+		opts.Int64 = generator.Int64Encoding(encoding)
+	}
+	gen := generator.NewGraphQLGeneratorWithOptions(opts)
+	return gen.Generate(schema), nil
+}
+
 func (g *builtinGraphQLGenerator) Format() string {
 	return "graphql"
 }
@@ -243,6 +288,18 @@ func (g *builtinProtobufGenerator) Generate(schema *Schema) (string, error) {
 	return gen.Generate(schema), nil
 }
 
+func (g *builtinProtobufGenerator) GenerateWithConfig(schema *Schema, config map[string]interface{}) (string, error) {
+	opts := &generator.ProtobufOptions{}
+	if useWrappers, ok := config["use_wrapper_types"].(bool); ok {
+		opts.UseWrapperTypes = useWrappers
+	}
+	if layout, ok := config["layout"].(string); ok {
+		opts.Layout = generator.ProtobufLayout(layout)
+	}
+	gen := generator.NewProtobufGeneratorWithOptions(opts)
+	return gen.Generate(schema), nil
+}
+
 func (g *builtinProtobufGenerator) Format() string {
 	return "protobuf"
 }
@@ -258,6 +315,27 @@ func (g *builtinOpenAPIGenerator) Generate(schema *Schema) (string, error) {
 	return gen.Generate(schema), nil
 }
 
+func (g *builtinOpenAPIGenerator) GenerateWithConfig(schema *Schema, config map[string]interface{}) (string, error) {
+	opts := &generator.OpenAPIOptions{}
+	if problemDetails, ok := config["problem_details"].(bool); ok {
+		opts.ProblemDetails = problemDetails
+	}
+	if errorSchema, ok := config["error_schema"].(string); ok {
+		opts.ErrorSchemaName = errorSchema
+	}
+	if bytesFormat, ok := config["bytes_format"].(string); ok {
+		opts.BytesFormat = generator.OpenAPIBytesFormat(bytesFormat)
+	}
+	if protoJSON, ok := config["proto_json"].(bool); ok {
+		opts.ProtoJSON = protoJSON
+	}
+	if encoding, ok := config["int64"].(string); ok {
+		opts.Int64 = generator.Int64Encoding(encoding)
+	}
+	gen := generator.NewOpenAPIGeneratorWithOptions(opts)
+	return gen.Generate(schema), nil
+}
+
 func (g *builtinOpenAPIGenerator) Format() string {
 	return "openapi"
 }
@@ -273,6 +351,18 @@ func (g *builtinGoGenerator) Generate(schema *Schema) (string, error) {
 	return gen.Generate(schema), nil
 }
 
+func (g *builtinGoGenerator) GenerateWithConfig(schema *Schema, config map[string]interface{}) (string, error) {
+	opts := &generator.GoOptions{}
+	if protoPackage, ok := config["proto_package"].(string); ok {
+		opts.ProtoPackage = protoPackage
+	}
+	if encoding, ok := config["int64"].(string); ok {
+		opts.Int64 = generator.Int64Encoding(encoding)
+	}
+	gen := generator.NewGoGeneratorWithOptions(opts)
+	return gen.Generate(schema), nil
+}
+
 func (g *builtinGoGenerator) Format() string {
 	return "go"
 }
@@ -2,4 +2,9 @@ module github.com/rasmartins/typemux
 
 go 1.21
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	github.com/vektah/gqlparser/v2 v2.5.19
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/agnivade/levenshtein v1.1.1 // indirect
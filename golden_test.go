@@ -0,0 +1,18 @@
+package typemux_test
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/rasmartins/typemux/typemuxtest"
+)
+
+var update = flag.Bool("update", false, "Rewrite the expected files of testdata/golden")
+
+// TestGolden generates every schema of testdata/golden in each built-in format
+// and compares the outputs with the expected files. Run go test -update after a
+// deliberate change to the output and review the diff of the expected files.
+func TestGolden(t *testing.T) {
+	typemuxtest.Run(t, filepath.Join("testdata", "golden"), typemuxtest.Options{Update: *update})
+}
@@ -0,0 +1,169 @@
+package annotations
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Annotation namespaces registered by plugins and custom generators, by namespace
+var (
+	extensionsMu sync.RWMutex
+	extensions   = make(map[string]*AnnotationRegistry)
+)
+
+// namespacePattern matches the names of annotation namespaces
+var namespacePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedNamespaces are the first name parts of the builtin annotations, such as
+// proto in @proto.name, which plugins cannot take over
+var reservedNamespaces = func() map[string]bool {
+	reserved := map[string]bool{"json": true}
+	for _, meta := range GetBuiltinAnnotations().GetAll() {
+		namespace, _, _ := strings.Cut(strings.TrimPrefix(meta.Name, "@"), ".")
+		reserved[namespace] = true
+	}
+	return reserved
+}()
+
+// RegisterNamespace registers the annotations of a namespace owned by a plugin or
+// custom generator, such as @mycorp.audit(level="high"). The parser accepts them
+// on the elements of their scope, checks their arguments against their
+// parameters, and stores the arguments in FormatAnnotations.Extensions.
+// Registering a namespace again replaces its annotations.
+func RegisterNamespace(namespace string, metas []*AnnotationMetadata) error {
+	if !namespacePattern.MatchString(namespace) {
+		return fmt.Errorf("invalid annotation namespace %q", namespace)
+	}
+	if reservedNamespaces[namespace] {
+		return fmt.Errorf("annotation namespace %q is reserved for builtin annotations", namespace)
+	}
+
+	registry := NewAnnotationRegistry()
+	prefix := "@" + namespace + "."
+	for _, meta := range metas {
+		if !strings.HasPrefix(meta.Name, prefix) || !namespacePattern.MatchString(strings.TrimPrefix(meta.Name, prefix)) {
+			return fmt.Errorf("annotation %s is not named %s<name>", meta.Name, prefix)
+		}
+		if len(meta.Scope) == 0 {
+			return fmt.Errorf("annotation %s has no scope", meta.Name)
+		}
+		for _, param := range meta.Parameters {
+			if param.Name == "" {
+				return fmt.Errorf("annotation %s has a parameter without a name", meta.Name)
+			}
+		}
+		registry.Register(meta)
+	}
+
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	extensions[namespace] = registry
+	return nil
+}
+
+// UnregisterNamespace removes a namespace registered with RegisterNamespace.
+func UnregisterNamespace(namespace string) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	delete(extensions, namespace)
+}
+
+// IsExtensionNamespace reports whether a namespace was registered with RegisterNamespace.
+func IsExtensionNamespace(namespace string) bool {
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+	_, ok := extensions[namespace]
+	return ok
+}
+
+// GetExtension returns the registered annotation with the given name (e.g., "@mycorp.audit").
+func GetExtension(name string) (*AnnotationMetadata, bool) {
+	namespace, _, _ := strings.Cut(strings.TrimPrefix(name, "@"), ".")
+
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+	registry, ok := extensions[namespace]
+	if !ok {
+		return nil, false
+	}
+	return registry.Get(name)
+}
+
+// SuggestExtension returns the annotation of a registered namespace closest to
+// a misspelled one, preferring annotations allowed in the given scope, or "".
+func SuggestExtension(name, scope string) string {
+	namespace, _, _ := strings.Cut(strings.TrimPrefix(name, "@"), ".")
+
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+	registry, ok := extensions[namespace]
+	if !ok {
+		return ""
+	}
+	return registry.Suggest(name, scope)
+}
+
+// CheckArguments returns the problems of the arguments of an annotation, by
+// parameter name, against its parameters: unknown and missing parameters,
+// values of the wrong type, and values outside the valid values. Each argument
+// has the kind of literal it was written as: string, number, boolean, or
+// identifier.
+func (m *AnnotationMetadata) CheckArguments(args map[string]string, kinds map[string]string) []string {
+	var problems []string
+	params := make(map[string]ParameterMetadata, len(m.Parameters))
+	for _, param := range m.Parameters {
+		params[param.Name] = param
+		if _, ok := args[param.Name]; !ok && param.Required {
+			problems = append(problems, fmt.Sprintf("%s requires %s", m.Name, param.Name))
+		}
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		param, ok := params[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s has no parameter %s", m.Name, name))
+			continue
+		}
+		value, kind := args[name], kinds[name]
+		switch param.Type {
+		case "number":
+			if kind != "number" {
+				problems = append(problems, fmt.Sprintf("%s: %s must be a number, got %s", m.Name, name, value))
+				continue
+			}
+		case "boolean":
+			if kind != "boolean" {
+				problems = append(problems, fmt.Sprintf("%s: %s must be true or false, got %s", m.Name, name, value))
+				continue
+			}
+		case "string", "":
+			if kind != "string" && kind != "identifier" {
+				problems = append(problems, fmt.Sprintf("%s: %s must be a string, got %s", m.Name, name, value))
+				continue
+			}
+		}
+		if len(param.ValidValues) > 0 && !contains(param.ValidValues, value) {
+			problems = append(problems, fmt.Sprintf("%s: %s must be one of %s, got %s", m.Name, name, strings.Join(param.ValidValues, ", "), value))
+		}
+	}
+	return problems
+}
+
+// contains reports whether a list holds a value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
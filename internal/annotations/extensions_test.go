@@ -0,0 +1,88 @@
+package annotations
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterNamespace(t *testing.T) {
+	audit := &AnnotationMetadata{Name: "@mycorp.audit", Scope: []string{"type"}}
+	if err := RegisterNamespace("mycorp", []*AnnotationMetadata{audit}); err != nil {
+		t.Fatalf("RegisterNamespace failed: %v", err)
+	}
+	defer UnregisterNamespace("mycorp")
+
+	if !IsExtensionNamespace("mycorp") {
+		t.Error("Expected mycorp to be registered")
+	}
+	if meta, ok := GetExtension("@mycorp.audit"); !ok || meta != audit {
+		t.Errorf("Expected @mycorp.audit to be registered, got %v", meta)
+	}
+	if _, ok := GetExtension("@mycorp.other"); ok {
+		t.Error("Expected @mycorp.other not to be registered")
+	}
+	if got := SuggestExtension("@mycorp.audti", "type"); got != "@mycorp.audit" {
+		t.Errorf("Expected suggestion @mycorp.audit, got %q", got)
+	}
+
+	UnregisterNamespace("mycorp")
+	if _, ok := GetExtension("@mycorp.audit"); ok {
+		t.Error("Expected @mycorp.audit to be unregistered")
+	}
+}
+
+func TestRegisterNamespace_Errors(t *testing.T) {
+	tests := []struct {
+		namespace string
+		metas     []*AnnotationMetadata
+		want      string
+	}{
+		{"my-corp", nil, "invalid annotation namespace"},
+		{"proto", nil, "reserved for builtin annotations"},
+		{"json", nil, "reserved for builtin annotations"},
+		{"mycorp", []*AnnotationMetadata{{Name: "@other.audit", Scope: []string{"type"}}}, "is not named @mycorp.<name>"},
+		{"mycorp", []*AnnotationMetadata{{Name: "@mycorp.audit"}}, "has no scope"},
+		{"mycorp", []*AnnotationMetadata{{Name: "@mycorp.audit", Scope: []string{"type"}, Parameters: []ParameterMetadata{{}}}}, "parameter without a name"},
+	}
+	for _, tt := range tests {
+		err := RegisterNamespace(tt.namespace, tt.metas)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.namespace, tt.want, err)
+		}
+	}
+	if IsExtensionNamespace("mycorp") {
+		t.Error("Expected a rejected namespace not to be registered")
+	}
+}
+
+func TestAnnotationMetadata_CheckArguments(t *testing.T) {
+	meta := &AnnotationMetadata{Name: "@mycorp.audit", Parameters: []ParameterMetadata{
+		{Name: "level", Type: "string", Required: true, ValidValues: []string{"low", "high"}},
+		{Name: "retain", Type: "number"},
+		{Name: "strict", Type: "boolean"},
+	}}
+
+	tests := []struct {
+		args  map[string]string
+		kinds map[string]string
+		want  string
+	}{
+		{map[string]string{"level": "low"}, map[string]string{"level": "identifier"}, ""},
+		{map[string]string{"level": "high", "retain": "30", "strict": "true"}, map[string]string{"level": "string", "retain": "number", "strict": "boolean"}, ""},
+		{map[string]string{}, map[string]string{}, "@mycorp.audit requires level"},
+		{map[string]string{"level": "medium"}, map[string]string{"level": "string"}, "level must be one of low, high, got medium"},
+		{map[string]string{"level": "low", "retain": "forever"}, map[string]string{"level": "string", "retain": "identifier"}, "retain must be a number"},
+		{map[string]string{"level": "low", "strict": "yes"}, map[string]string{"level": "string", "strict": "string"}, "strict must be true or false"},
+		{map[string]string{"level": "1"}, map[string]string{"level": "number"}, "level must be a string"},
+		{map[string]string{"level": "low", "owner": "ops"}, map[string]string{"level": "string", "owner": "string"}, "has no parameter owner"},
+	}
+	for _, tt := range tests {
+		problems := strings.Join(meta.CheckArguments(tt.args, tt.kinds), "\n")
+		if tt.want == "" && problems != "" {
+			t.Errorf("%v: expected no problems, got %q", tt.args, problems)
+		}
+		if tt.want != "" && !strings.Contains(problems, tt.want) {
+			t.Errorf("%v: expected a problem containing %q, got %q", tt.args, tt.want, problems)
+		}
+	}
+}
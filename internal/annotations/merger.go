@@ -1,7 +1,11 @@
 package annotations
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/rasmartins/typemux/internal/ast"
 )
@@ -26,9 +30,22 @@ func (m *Merger) Merge(schema *ast.Schema) {
 		schema.Version = m.annotations.Version
 	}
 
-	// Merge namespace annotations
-	if namespaceAnnotations, ok := m.annotations.Namespaces[schema.Namespace]; ok {
-		m.mergeNamespaceAnnotations(schema, namespaceAnnotations)
+	// Merge namespace annotations (imported namespaces are tracked separately)
+	for namespace, namespaceAnnotations := range m.annotations.Namespaces {
+		if namespace == schema.Namespace {
+			if schema.NamespaceAnnotations == nil {
+				schema.NamespaceAnnotations = ast.NewFormatAnnotations()
+			}
+			m.mergeNamespaceAnnotations(schema.NamespaceAnnotations, namespaceAnnotations)
+			continue
+		}
+		if schema.ImportedNamespaceAnnotations == nil {
+			schema.ImportedNamespaceAnnotations = make(map[string]*ast.FormatAnnotations)
+		}
+		if schema.ImportedNamespaceAnnotations[namespace] == nil {
+			schema.ImportedNamespaceAnnotations[namespace] = ast.NewFormatAnnotations()
+		}
+		m.mergeNamespaceAnnotations(schema.ImportedNamespaceAnnotations[namespace], namespaceAnnotations)
 	}
 
 	// Merge type annotations (support both simple and qualified names)
@@ -202,6 +219,16 @@ func (m *Merger) mergeFieldAnnotations(field *ast.Field, annotations *FieldAnnot
 		field.Since = annotations.Since
 	}
 
+	// Merge the kind of sensitive data
+	if annotations.Sensitive != "" {
+		field.Sensitivity = annotations.Sensitive
+	}
+
+	// Merge the views the field is part of
+	if len(annotations.Views) > 0 {
+		field.Views = mergeLists(field.Views, annotations.Views)
+	}
+
 	// Initialize field annotations if nil
 	if field.Annotations == nil {
 		field.Annotations = ast.NewFormatAnnotations()
@@ -273,10 +300,80 @@ func (m *Merger) mergeMethodAnnotations(method *ast.Method, annotations *MethodA
 		method.ErrorCodes = mergeLists(method.ErrorCodes, errorStrs)
 	}
 
+	if annotations.Timeout != "" {
+		method.Timeout = annotations.Timeout
+	}
+	if annotations.Idempotent {
+		method.Idempotent = true
+	}
+	if annotations.LongRunning && method.LongRunning == nil {
+		method.LongRunning = &ast.LongRunning{}
+	}
+	if annotations.Batch {
+		method.Batch = true
+	}
+	if annotations.RateLimit != nil {
+		method.RateLimit = annotations.RateLimit.toAST()
+	}
+	if annotations.Auth != nil {
+		method.Auth = &ast.Auth{Scopes: annotations.Auth.Scopes}
+	}
+	if annotations.Webhook != nil {
+		method.Webhook = annotations.Webhook.toAST(method.Name)
+	}
+	if annotations.View != "" {
+		method.View = annotations.View
+	}
+	for _, example := range annotations.Examples {
+		converted := example.toAST()
+		if existing := method.Example(converted.Name); existing != nil {
+			*existing = *converted
+		} else {
+			method.Examples = append(method.Examples, converted)
+		}
+	}
+	for _, sample := range annotations.CodeSamples {
+		method.CodeSamples = append(method.CodeSamples, &ast.CodeSample{Lang: sample.Lang, Label: sample.Label, Source: sample.Source})
+	}
+
 	// Note: Method doesn't have Annotations field for ProtoOption in current AST
 	// This would need to be added if proto options on methods are needed
 }
 
+// toAST converts the rate limit to its AST form, defaulting the window to a second
+func (r *RateLimitAnnotations) toAST() *ast.RateLimit {
+	limit := &ast.RateLimit{Requests: r.Requests, Per: r.Per}
+	if limit.Per == "" {
+		limit.Per = "second"
+	}
+	return limit
+}
+
+// toAST converts the example to its AST form, with its request and response as
+// JSON and its name defaulting to default
+func (e *ExampleAnnotations) toAST() *ast.Example {
+	example := &ast.Example{Name: e.Name, Summary: e.Summary}
+	if example.Name == "" {
+		example.Name = "default"
+	}
+	if e.Request != nil {
+		example.Request, _ = json.Marshal(e.Request)
+	}
+	if e.Response != nil {
+		example.Response, _ = json.Marshal(e.Response)
+	}
+	return example
+}
+
+// toAST converts the webhook to its AST form, naming it after the method by default
+func (w *WebhookAnnotations) toAST(methodName string) *ast.Webhook {
+	webhook := &ast.Webhook{Name: w.Name, On: w.On, URL: w.URL}
+	if webhook.Name == "" && methodName != "" {
+		webhook.Name = strings.ToLower(methodName[:1]) + methodName[1:]
+	}
+	return webhook
+}
+
 // applyFormatAnnotations applies format-specific annotations to an AST FormatAnnotations struct
 func (m *Merger) applyFormatAnnotations(target *ast.FormatAnnotations, proto, graphql, openapi *FormatSpecificAnnotations) {
 	// Apply proto annotations
@@ -332,25 +429,26 @@ func mergeLists(a, b []string) []string {
 	return result
 }
 
-func (m *Merger) mergeNamespaceAnnotations(schema *ast.Schema, annotations *NamespaceAnnotations) {
-	// Initialize if nil
-	if schema.NamespaceAnnotations == nil {
-		schema.NamespaceAnnotations = ast.NewFormatAnnotations()
-	}
-
-	// Merge protobuf options
+func (m *Merger) mergeNamespaceAnnotations(target *ast.FormatAnnotations, annotations *NamespaceAnnotations) {
+	// Merge protobuf options in a stable order
 	if annotations.Proto != nil && annotations.Proto.Options != nil {
-		for optionName, optionValue := range annotations.Proto.Options {
-			// Format as protobuf option: go_package="value" or java_package="value"
-			optionStr := fmt.Sprintf("%s=%q", optionName, optionValue)
-			schema.NamespaceAnnotations.Proto = append(schema.NamespaceAnnotations.Proto, optionStr)
+		optionNames := make([]string, 0, len(annotations.Proto.Options))
+		for optionName := range annotations.Proto.Options {
+			optionNames = append(optionNames, optionName)
+		}
+		sort.Strings(optionNames)
+
+		for _, optionName := range optionNames {
+			// Format as protobuf option: go_package="value" or java_multiple_files=true
+			optionStr := fmt.Sprintf("%s=%s", optionName, formatProtoOptionValue(annotations.Proto.Options[optionName]))
+			target.Proto = append(target.Proto, optionStr)
 		}
 	}
 
 	// Merge GraphQL directives
 	if annotations.GraphQL != nil {
 		if annotations.GraphQL.Directive != "" {
-			schema.NamespaceAnnotations.GraphQL = append(schema.NamespaceAnnotations.GraphQL, annotations.GraphQL.Directive)
+			target.GraphQL = append(target.GraphQL, annotations.GraphQL.Directive)
 		}
 	}
 
@@ -360,15 +458,37 @@ func (m *Merger) mergeNamespaceAnnotations(schema *ast.Schema, annotations *Name
 			for key, value := range annotations.OpenAPI.Info {
 				// Store as key:value for OpenAPI info section
 				infoStr := fmt.Sprintf("%s:%s", key, value)
-				schema.NamespaceAnnotations.OpenAPI = append(schema.NamespaceAnnotations.OpenAPI, infoStr)
+				target.OpenAPI = append(target.OpenAPI, infoStr)
 			}
 		}
 		if annotations.OpenAPI.Extensions != nil {
 			for key, value := range annotations.OpenAPI.Extensions {
 				// Store as x-key:value for OpenAPI extensions
 				extStr := fmt.Sprintf("%s:%s", key, value)
-				schema.NamespaceAnnotations.OpenAPI = append(schema.NamespaceAnnotations.OpenAPI, extStr)
+				target.OpenAPI = append(target.OpenAPI, extStr)
 			}
 		}
 	}
 }
+
+// formatProtoOptionValue quotes string option values while leaving booleans, numbers,
+// and enum constants (e.g., optimize_for: SPEED) bare
+func formatProtoOptionValue(value string) string {
+	if value == "true" || value == "false" {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	isEnumConstant := value != ""
+	for _, r := range value {
+		if !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') && r != '_' {
+			isEnumConstant = false
+			break
+		}
+	}
+	if isEnumConstant {
+		return value
+	}
+	return strconv.Quote(value)
+}
@@ -46,7 +46,7 @@ func TestMerger_NamespaceAnnotations_Proto(t *testing.T) {
 	expectedOptions := map[string]bool{
 		"go_package=\"github.com/example/proto\"": true,
 		"java_package=\"com.example.proto\"":      true,
-		"java_multiple_files=\"true\"":            true,
+		"java_multiple_files=true":                true,
 	}
 
 	for expected := range expectedOptions {
@@ -305,3 +305,34 @@ func TestMerger_NamespaceAnnotations_WithTypeAnnotations(t *testing.T) {
 		t.Error("expected field 'id' to be required")
 	}
 }
+
+func TestMerger_NamespaceAnnotations_ImportedNamespace(t *testing.T) {
+	yamlAnnotations := &YAMLAnnotations{
+		Namespaces: map[string]*NamespaceAnnotations{
+			"com.example.common": {
+				Proto: &NamespaceProtoAnnotations{
+					Options: map[string]string{
+						"go_package": "github.com/example/common",
+					},
+				},
+			},
+		},
+	}
+
+	schema := &ast.Schema{
+		Namespace: "com.example.users",
+		Types:     []*ast.Type{},
+	}
+
+	merger := NewMerger(yamlAnnotations)
+	merger.Merge(schema)
+
+	annotations := schema.GetNamespaceAnnotations("com.example.common")
+	if annotations == nil || len(annotations.Proto) != 1 {
+		t.Fatalf("expected imported namespace annotations, got %+v", annotations)
+	}
+
+	if annotations.Proto[0] != `go_package="github.com/example/common"` {
+		t.Errorf("unexpected proto option: %s", annotations.Proto[0])
+	}
+}
@@ -358,6 +358,176 @@ func TestMerger_ServiceMethodAnnotations(t *testing.T) {
 	}
 }
 
+func TestMerger_MethodPolicies(t *testing.T) {
+	schema := createTestSchemaForMerger()
+
+	annotations := &YAMLAnnotations{
+		Services: map[string]*ServiceAnnotations{
+			"UserService": {
+				Methods: map[string]*MethodAnnotations{
+					"GetUser": {
+						Timeout:    "5s",
+						Idempotent: true,
+						RateLimit:  &RateLimitAnnotations{Requests: 10},
+						Auth:       &AuthAnnotations{Scopes: []string{"users:read"}},
+					},
+				},
+			},
+		},
+	}
+
+	merger := NewMerger(annotations)
+	merger.Merge(schema)
+
+	method := schema.Services[0].Methods[0]
+	if method.Timeout != "5s" || !method.Idempotent {
+		t.Errorf("Expected timeout 5s and idempotent, got %q and %v", method.Timeout, method.Idempotent)
+	}
+	if method.RateLimit == nil || method.RateLimit.Requests != 10 || method.RateLimit.Per != "second" {
+		t.Errorf("Expected 10 requests per second, got %+v", method.RateLimit)
+	}
+	if method.Auth == nil || len(method.Auth.Scopes) != 1 || method.Auth.Scopes[0] != "users:read" {
+		t.Errorf("Expected the users:read scope, got %+v", method.Auth)
+	}
+}
+
+func TestMerger_Webhook(t *testing.T) {
+	schema := createTestSchemaForMerger()
+
+	annotations := &YAMLAnnotations{
+		Services: map[string]*ServiceAnnotations{
+			"UserService": {
+				Methods: map[string]*MethodAnnotations{
+					"GetUser": {Webhook: &WebhookAnnotations{}},
+				},
+			},
+		},
+	}
+
+	merger := NewMerger(annotations)
+	merger.Merge(schema)
+
+	method := schema.Services[0].Methods[0]
+	if method.Webhook == nil || method.Webhook.Name != "getUser" || method.Webhook.IsCallback() {
+		t.Errorf("Expected a webhook named after the method, got %+v", method.Webhook)
+	}
+}
+
+func TestMerger_Views(t *testing.T) {
+	schema := createTestSchemaForMerger()
+	schema.Types[0].Fields[1].Views = []string{"summary"}
+
+	annotations := &YAMLAnnotations{
+		Types: map[string]*TypeAnnotations{
+			"com.example.api.User": {
+				Fields: map[string]*FieldAnnotations{
+					"username": {Views: []string{"summary", "card"}},
+				},
+			},
+		},
+		Services: map[string]*ServiceAnnotations{
+			"UserService": {
+				Methods: map[string]*MethodAnnotations{
+					"GetUser": {View: "card"},
+				},
+			},
+		},
+	}
+
+	merger := NewMerger(annotations)
+	merger.Merge(schema)
+
+	if views := schema.Types[0].Fields[1].Views; len(views) != 2 || views[0] != "summary" || views[1] != "card" {
+		t.Errorf("Expected views summary and card, got %v", views)
+	}
+	if view := schema.Services[0].Methods[0].View; view != "card" {
+		t.Errorf("Expected GetUser to select the card view, got %q", view)
+	}
+}
+
+func TestMerger_LongRunning(t *testing.T) {
+	schema := createTestSchemaForMerger()
+
+	annotations := &YAMLAnnotations{
+		Services: map[string]*ServiceAnnotations{
+			"UserService": {
+				Methods: map[string]*MethodAnnotations{
+					"GetUser": {LongRunning: true},
+				},
+			},
+		},
+	}
+
+	merger := NewMerger(annotations)
+	merger.Merge(schema)
+
+	if schema.Services[0].Methods[0].LongRunning == nil {
+		t.Error("Expected GetUser to be long-running")
+	}
+}
+
+func TestMerger_Batch(t *testing.T) {
+	schema := createTestSchemaForMerger()
+
+	annotations := &YAMLAnnotations{
+		Services: map[string]*ServiceAnnotations{
+			"UserService": {
+				Methods: map[string]*MethodAnnotations{
+					"GetUser": {Batch: true},
+				},
+			},
+		},
+	}
+
+	merger := NewMerger(annotations)
+	merger.Merge(schema)
+
+	if !schema.Services[0].Methods[0].Batch {
+		t.Error("Expected GetUser to be batched")
+	}
+}
+
+func TestMerger_ExamplesAndCodeSamples(t *testing.T) {
+	schema := createTestSchemaForMerger()
+	schema.Services[0].Methods[0].Examples = []*ast.Example{{Name: "found", Response: []byte(`{"id":"old"}`)}}
+
+	annotations, err := ParseYAMLAnnotations(`
+services:
+  UserService:
+    methods:
+      GetUser:
+        examples:
+          - name: found
+            summary: An existing user
+            response:
+              id: u1
+              tags: [admin]
+          - request:
+              id: u2
+        code_samples:
+          - lang: curl
+            source: curl https://api.example.com/users/u1
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse annotations: %v", err)
+	}
+	NewMerger(annotations).Merge(schema)
+
+	method := schema.Services[0].Methods[0]
+	if len(method.Examples) != 2 {
+		t.Fatalf("Expected the found example to be replaced and a default one added, got %d examples", len(method.Examples))
+	}
+	if found := method.Example("found"); found.Summary != "An existing user" || string(found.Response) != `{"id":"u1","tags":["admin"]}` {
+		t.Errorf("Unexpected found example: %+v", found)
+	}
+	if example := method.Example("default"); example == nil || string(example.Request) != `{"id":"u2"}` {
+		t.Errorf("Expected an unnamed example to be named default, got %+v", example)
+	}
+	if len(method.CodeSamples) != 1 || method.CodeSamples[0].Lang != "curl" {
+		t.Errorf("Expected a curl code sample, got %+v", method.CodeSamples)
+	}
+}
+
 func TestMerger_QualifiedServiceName(t *testing.T) {
 	schema := createTestSchemaForMerger()
 
@@ -6,10 +6,10 @@ type AnnotationMetadata struct {
 	Name string `json:"name"`
 
 	// Scope indicates where this annotation can be used
-	Scope []string `json:"scope"` // ["method", "field", "type", "enum", "union", "namespace", "schema"]
+	Scope []string `json:"scope"` // ["method", "field", "argument", "type", "enum", "union", "namespace", "schema"]
 
 	// Formats indicates which output formats this annotation affects
-	Formats []string `json:"formats"` // ["proto", "graphql", "openapi", "go", "all"]
+	Formats []string `json:"formats"` // ["proto", "graphql", "openapi", "go", "csharp", "java", "all"]
 
 	// Parameters describes the parameters this annotation accepts
 	Parameters []ParameterMetadata `json:"parameters,omitempty"`
@@ -97,3 +97,63 @@ func (r *AnnotationRegistry) GetByFormat(format string) []*AnnotationMetadata {
 	}
 	return result
 }
+
+// Get returns the annotation with the given name (e.g., "@required")
+func (r *AnnotationRegistry) Get(name string) (*AnnotationMetadata, bool) {
+	for _, meta := range r.annotations {
+		if meta.Name == name {
+			return meta, true
+		}
+	}
+	return nil, false
+}
+
+// HasScope reports whether the annotation can be used in a given scope
+func (m *AnnotationMetadata) HasScope(scope string) bool {
+	for _, s := range m.Scope {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggest returns the registered annotation name closest to a misspelled one,
+// preferring annotations allowed in the given scope. It returns "" when no
+// name is close enough to be a likely typo.
+func (r *AnnotationRegistry) Suggest(name, scope string) string {
+	best, bestDistance := "", 0
+	for _, candidates := range [][]*AnnotationMetadata{r.GetByScope(scope), r.annotations} {
+		for _, meta := range candidates {
+			distance := editDistance(name, meta.Name)
+			if distance <= 2 && distance < len(name)-1 && (best == "" || distance < bestDistance) {
+				best, bestDistance = meta.Name, distance
+			}
+		}
+		if best != "" {
+			return best
+		}
+	}
+	return ""
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,42 @@
+package annotations
+
+import "testing"
+
+func TestAnnotationRegistry_Get(t *testing.T) {
+	registry := GetBuiltinAnnotations()
+
+	meta, ok := registry.Get("@required")
+	if !ok {
+		t.Fatal("Expected @required to be registered")
+	}
+	if !meta.HasScope("field") || meta.HasScope("type") {
+		t.Errorf("Expected @required to be allowed on fields only, got %v", meta.Scope)
+	}
+
+	if _, ok := registry.Get("@requird"); ok {
+		t.Error("Expected misspelled annotation not to be registered")
+	}
+}
+
+func TestAnnotationRegistry_Suggest(t *testing.T) {
+	registry := GetBuiltinAnnotations()
+
+	tests := []struct {
+		name  string
+		scope string
+		want  string
+	}{
+		{"@requird", "field", "@required"},
+		{"@defualt", "field", "@default"},
+		{"@http.mehtod", "method", "@http.method"},
+		{"@proto.nmae", "type", "@proto.name"},
+		{"@graphql", "type", "@graphql"},
+		{"@somethingelse", "field", ""},
+		{"@x", "field", ""},
+	}
+	for _, tt := range tests {
+		if got := registry.Suggest(tt.name, tt.scope); got != tt.want {
+			t.Errorf("Suggest(%q, %q) = %q, want %q", tt.name, tt.scope, got, tt.want)
+		}
+	}
+}
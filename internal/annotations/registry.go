@@ -40,9 +40,9 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 	// Namespace-level annotations
 	registry.Register(&AnnotationMetadata{
 		Name:        "@proto.option",
-		Scope:       []string{"namespace", "type", "enum", "union"},
+		Scope:       []string{"namespace", "type", "enum", "union", "service", "field"},
 		Formats:     []string{"proto"},
-		Description: "Adds Protobuf file-level or message-level options",
+		Description: "Adds Protobuf file-level, message-level, enum-level, or service-level options",
 		Parameters: []ParameterMetadata{
 			{
 				Name:        "option",
@@ -57,9 +57,32 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 		},
 	})
 
+	for _, option := range []struct{ name, example string }{
+		{"go_package", "github.com/example/api;api"},
+		{"java_package", "com.example.api"},
+		{"csharp_namespace", "Example.Api"},
+		{"objc_class_prefix", "EXA"},
+	} {
+		registry.Register(&AnnotationMetadata{
+			Name:        "@proto." + option.name,
+			Scope:       []string{"namespace"},
+			Formats:     []string{"proto"},
+			Description: "Shorthand for @proto.option(" + option.name + "=\"...\")",
+			Parameters: []ParameterMetadata{
+				{
+					Name:        "value",
+					Type:        "string",
+					Required:    true,
+					Description: "Value of the " + option.name + " file option",
+				},
+			},
+			Examples: []string{"@proto." + option.name + "(\"" + option.example + "\")"},
+		})
+	}
+
 	registry.Register(&AnnotationMetadata{
 		Name:        "@graphql.directive",
-		Scope:       []string{"namespace", "type", "enum", "union", "field"},
+		Scope:       []string{"namespace", "type", "enum", "union", "field", "method"},
 		Formats:     []string{"graphql"},
 		Description: "Adds GraphQL directives to schema elements",
 		Parameters: []ParameterMetadata{
@@ -73,6 +96,7 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 		Examples: []string{
 			`@graphql.directive(@key(fields: "id"))`,
 			`@graphql.directive(@external)`,
+			`@graphql.directive("@auth(requires: ADMIN)")`,
 		},
 	})
 
@@ -95,7 +119,7 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 	// Type/Enum/Union-level annotations
 	registry.Register(&AnnotationMetadata{
 		Name:        "@proto.name",
-		Scope:       []string{"type", "enum", "union", "field"},
+		Scope:       []string{"type", "enum", "union", "field", "argument"},
 		Formats:     []string{"proto"},
 		Description: "Overrides the Protobuf name for the element",
 		Parameters: []ParameterMetadata{
@@ -114,7 +138,7 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 
 	registry.Register(&AnnotationMetadata{
 		Name:        "@graphql.name",
-		Scope:       []string{"type", "enum", "union", "field"},
+		Scope:       []string{"type", "enum", "union", "field", "argument"},
 		Formats:     []string{"graphql"},
 		Description: "Overrides the GraphQL name for the element",
 		Parameters: []ParameterMetadata{
@@ -131,9 +155,34 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 		},
 	})
 
+	registry.Register(&AnnotationMetadata{
+		Name:        "@graphql.map",
+		Scope:       []string{"field"},
+		Formats:     []string{"graphql"},
+		Description: "Sets how GraphQL renders a map field: a list of key-value entry types, or a JSON scalar",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "as",
+				Type:        "string",
+				Required:    false,
+				Description: "entries (a list of entry types) or json (the JSON scalar, or generators.graphql.map_scalar)",
+			},
+			{
+				Name:        "entry",
+				Type:        "string",
+				Required:    false,
+				Description: "Name of the entry type of the map (default: key and value types with the Entry suffix, such as StringIntEntry)",
+			},
+		},
+		Examples: []string{
+			`labels: map<string, string> @graphql.map(entry="LabelEntry")`,
+			`metadata: map<string, string> @graphql.map(json)`,
+		},
+	})
+
 	registry.Register(&AnnotationMetadata{
 		Name:        "@openapi.name",
-		Scope:       []string{"type", "enum", "union", "field"},
+		Scope:       []string{"type", "enum", "union", "field", "argument"},
 		Formats:     []string{"openapi"},
 		Description: "Overrides the OpenAPI schema or property name",
 		Parameters: []ParameterMetadata{
@@ -166,10 +215,65 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 		Examples: []string{`@openapi.extension({"x-internal": true, "x-format": "currency"})`},
 	})
 
+	// Ownership annotations, inherited from the namespace by its types and services
+	registry.Register(&AnnotationMetadata{
+		Name:        "@owner",
+		Scope:       []string{"namespace", "type", "service"},
+		Formats:     []string{"openapi"},
+		Description: "Names the team or person owning a namespace, type, or service, for the x-owner OpenAPI extension and the ownership report of typemux owners",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "owner",
+				Type:        "string",
+				Required:    true,
+				Description: "Owning team or person",
+			},
+		},
+		Examples: []string{
+			`namespace payments @owner("team-payments")`,
+			`service RefundService @owner("team-refunds") {`,
+		},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@sla",
+		Scope:       []string{"namespace", "type", "service"},
+		Formats:     []string{"openapi"},
+		Description: "Sets the service level objective of a namespace, type, or service, for the x-sla OpenAPI extension and the ownership report",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "sla",
+				Type:        "string",
+				Required:    true,
+				Description: "Service level objective, such as an availability",
+			},
+		},
+		Examples: []string{`service PaymentService @sla("99.95%") {`},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@tier",
+		Scope:       []string{"namespace", "type", "service"},
+		Formats:     []string{"openapi"},
+		Description: "Sets the criticality tier of a namespace, type, or service, for the x-tier OpenAPI extension and the ownership report",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "tier",
+				Type:        "string",
+				Required:    true,
+				Description: "Criticality tier, such as 1 or critical",
+			},
+		},
+		Examples: []string{
+			`service PaymentService @tier(1) {`,
+			`type Invoice @tier(critical) {`,
+		},
+	})
+
 	// Field-level annotations
 	registry.Register(&AnnotationMetadata{
 		Name:        "@required",
-		Scope:       []string{"field"},
+		Scope:       []string{"field", "argument"},
 		Formats:     []string{"all"},
 		Description: "Marks a field as required/non-nullable",
 		Parameters:  []ParameterMetadata{},
@@ -178,7 +282,7 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 
 	registry.Register(&AnnotationMetadata{
 		Name:        "@default",
-		Scope:       []string{"field"},
+		Scope:       []string{"field", "argument"},
 		Formats:     []string{"all"},
 		Description: "Sets a default value for the field",
 		Parameters: []ParameterMetadata{
@@ -267,9 +371,9 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 
 	registry.Register(&AnnotationMetadata{
 		Name:        "@since",
-		Scope:       []string{"field", "type", "enum", "method"},
+		Scope:       []string{"field", "type", "enum", "union", "service", "method"},
 		Formats:     []string{"all"},
-		Description: "Marks when an element was added to the schema",
+		Description: "Marks the API version an element was added in; -api-version leaves it out of older versions",
 		Parameters: []ParameterMetadata{
 			{
 				Name:        "version",
@@ -278,13 +382,76 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 				Description: "Version when element was added",
 			},
 		},
-		Examples: []string{`@since("2.0.0")`},
+		Examples: []string{`@since("2.0.0")`, `@since(v2)`},
 	})
 
 	registry.Register(&AnnotationMetadata{
-		Name:        "@validate",
+		Name:        "@until",
+		Scope:       []string{"field", "type", "enum", "union", "service", "method"},
+		Formats:     []string{"all"},
+		Description: "Marks the last API version an element is part of; -api-version leaves it out of newer versions",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "version",
+				Type:        "string",
+				Required:    true,
+				Description: "Last version that has the element",
+			},
+		},
+		Examples: []string{`@until("1.9.0")`, `@until(v1)`},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@flags",
+		Scope:       []string{"enum"},
+		Formats:     []string{"proto", "openapi", "go", "graphql", "csharp", "java"},
+		Description: "Marks an enum as a bitmask whose values are powers of two, numbered 1, 2, 4... by default, and combine with bitwise OR",
+		Examples:    []string{`enum Permission @flags { NONE = 0 READ WRITE }`},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@sensitive",
 		Scope:       []string{"field"},
 		Formats:     []string{"all"},
+		Description: "Marks a field as holding sensitive data, for the x-sensitive OpenAPI extension, Go struct tags, and the compliance report of typemux sensitive",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "kind",
+				Type:        "string",
+				Required:    true,
+				Description: "Kind of sensitive data",
+				ValidValues: []string{"pii", "secret", "phi"},
+			},
+		},
+		Examples: []string{
+			`email: string @sensitive(pii)`,
+			`apiKey: string @sensitive(secret)`,
+		},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@view",
+		Scope:       []string{"field", "method"},
+		Formats:     []string{"all"},
+		Description: "On fields, adds the field to views of its type, each generated as a type named after the type and the view (UserSummary); on methods, responds with a view of the output type",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "views",
+				Type:        "string",
+				Required:    true,
+				Description: "Names of the views; methods select a single view",
+			},
+		},
+		Examples: []string{
+			`name: string @view(summary, detail)`,
+			`rpc ListUsers(ListUsersRequest) returns (User) @view(summary)`,
+		},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@validate",
+		Scope:       []string{"field", "argument"},
+		Formats:     []string{"all"},
 		Description: "Defines validation rules for the field",
 		Parameters: []ParameterMetadata{
 			{
@@ -414,6 +581,25 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 		},
 	})
 
+	registry.Register(&AnnotationMetadata{
+		Name:        "@http.base_path",
+		Scope:       []string{"service"},
+		Formats:     []string{"openapi"},
+		Description: "Prefixes the URL paths of all methods of a service, given with @http.path or inferred from the method names",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "path",
+				Type:        "string",
+				Required:    true,
+				Description: "URL path prefix, starting with /",
+			},
+		},
+		Examples: []string{
+			`@http.base_path("/v1/users")`,
+			`@http.base_path("/v1/orgs/{orgId}")`,
+		},
+	})
+
 	registry.Register(&AnnotationMetadata{
 		Name:        "@http.success",
 		Scope:       []string{"method"},
@@ -473,6 +659,232 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 		},
 	})
 
+	registry.Register(&AnnotationMetadata{
+		Name:        "@timeout",
+		Scope:       []string{"method"},
+		Formats:     []string{"all"},
+		Description: "Sets the deadline of a method call",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "duration",
+				Type:        "string",
+				Required:    true,
+				Description: "Positive Go duration such as 5s, 500ms, or 1m30s",
+			},
+		},
+		Examples: []string{
+			`@timeout("5s")`,
+			`@timeout("500ms")`,
+		},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@idempotent",
+		Scope:       []string{"method"},
+		Formats:     []string{"all"},
+		Description: "Marks a method as safe to retry: repeating a call has the same effect as making it once",
+		Examples:    []string{`@idempotent`},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@longrunning",
+		Scope:       []string{"method"},
+		Formats:     []string{"all"},
+		Description: "Makes a method long-running: it responds at once with an operation that clients poll until it holds the response or an error",
+		Examples:    []string{`@longrunning`},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@batch",
+		Scope:       []string{"method"},
+		Formats:     []string{"all"},
+		Description: "Derives a Batch method that makes many calls of a method at once, reporting the calls that failed",
+		Examples:    []string{`@batch`},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@crud",
+		Scope:       []string{"namespace", "type"},
+		Formats:     []string{"all"},
+		Description: "Makes a type a resource, deriving its standard Create, Get, List, Update, and Delete methods with their requests, responses, paths, and verbs; on a namespace, sets the defaults of its resources",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "methods",
+				Type:        "string",
+				Required:    false,
+				Description: "Methods to derive, as positional names (default: all)",
+				ValidValues: []string{"create", "get", "list", "update", "delete"},
+			},
+			{
+				Name:        "path",
+				Type:        "string",
+				Required:    false,
+				Description: "HTTP path of the collection, with the {Type}, {type}, and {types} placeholders (default: /{types})",
+			},
+			{
+				Name:        "service",
+				Type:        "string",
+				Required:    false,
+				Description: "Service holding the methods, added unless declared, with the same placeholders (default: {Type}Service)",
+			},
+			{
+				Name:        "id",
+				Type:        "string",
+				Required:    false,
+				Description: "Field identifying a resource in paths and requests (default: id)",
+			},
+		},
+		Examples: []string{
+			`type User @crud {`,
+			`@crud(get, list, path="/v1/users")`,
+			`namespace shop @crud(path="/v1/{types}", service="ShopService")`,
+		},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@ratelimit",
+		Scope:       []string{"method"},
+		Formats:     []string{"all"},
+		Description: "Declares the request quota of a method",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "requests",
+				Type:        "number",
+				Required:    true,
+				Description: "Number of requests allowed per window",
+			},
+			{
+				Name:        "per",
+				Type:        "string",
+				Required:    false,
+				Description: "Window of the quota (default: second)",
+				ValidValues: []string{"second", "minute", "hour", "day"},
+			},
+		},
+		Examples: []string{
+			`@ratelimit(100, per="minute")`,
+			`@ratelimit(10)`,
+		},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@auth",
+		Scope:       []string{"service", "method"},
+		Formats:     []string{"all"},
+		Description: "Declares the authentication a method requires of its callers; on a service, of the methods that declare none",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "scopes",
+				Type:        "string",
+				Required:    false,
+				Description: "Scopes the caller needs, all of them, as positional values (default: any authenticated caller)",
+			},
+		},
+		Examples: []string{
+			`@auth`,
+			`@auth("orders:read", "orders:write")`,
+			`service OrderService @auth("orders:read") {`,
+		},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@webhook",
+		Scope:       []string{"method"},
+		Formats:     []string{"openapi"},
+		Description: "Marks a method as a request the API sends to its clients: an OpenAPI webhook, or a callback of another method with on= and url=",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "name",
+				Type:        "string",
+				Required:    false,
+				Description: "Name of the webhook or callback (default: the method name in lower camel case)",
+			},
+			{
+				Name:        "on",
+				Type:        "string",
+				Required:    false,
+				Description: "Method of the same service whose calls register the callback",
+			},
+			{
+				Name:        "url",
+				Type:        "string",
+				Required:    false,
+				Description: "Runtime expression of the callback URL, such as {$request.body#/callbackUrl}; required with on=",
+			},
+		},
+		Examples: []string{
+			`@webhook("paymentCompleted")`,
+			`@webhook("paymentSettled", on=CreatePayment, url="{$request.body#/callbackUrl}")`,
+		},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@openapi.example",
+		Scope:       []string{"method"},
+		Formats:     []string{"openapi"},
+		Description: "Adds a sample call of a method to the examples of its OpenAPI request body, parameters, and success response",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "name",
+				Type:        "string",
+				Required:    false,
+				Description: "Name of the example, unique within the method (default: default)",
+			},
+			{
+				Name:        "request",
+				Type:        "object",
+				Required:    false,
+				Description: "JSON value of the request",
+			},
+			{
+				Name:        "response",
+				Type:        "object",
+				Required:    false,
+				Description: "JSON value of the response",
+			},
+			{
+				Name:        "summary",
+				Type:        "string",
+				Required:    false,
+				Description: "Short description of the example",
+			},
+		},
+		Examples: []string{
+			`@openapi.example("ada", request={"name": "Ada"}, response={"id": "u1", "name": "Ada"})`,
+			`@openapi.example(response={"id": "u1"}, summary="An existing user")`,
+		},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@openapi.code_sample",
+		Scope:       []string{"method"},
+		Formats:     []string{"openapi"},
+		Description: "Adds a code sample of a method to the x-codeSamples of its OpenAPI operation, which Redoc displays",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "lang",
+				Type:        "string",
+				Required:    true,
+				Description: "Language of the sample, such as curl, Go, or TypeScript",
+			},
+			{
+				Name:        "source",
+				Type:        "string",
+				Required:    true,
+				Description: "Source code of the sample",
+			},
+			{
+				Name:        "label",
+				Type:        "string",
+				Required:    false,
+				Description: "Label shown instead of the language",
+			},
+		},
+		Examples: []string{
+			`@openapi.code_sample(lang="curl", source="curl -X POST https://api.example.com/users -d '{\"name\": \"Ada\"}'")`,
+		},
+	})
+
 	// JSON serialization annotations
 	registry.Register(&AnnotationMetadata{
 		Name:        "@json.name",
@@ -493,6 +905,55 @@ func GetBuiltinAnnotations() *AnnotationRegistry {
 		},
 	})
 
+	registry.Register(&AnnotationMetadata{
+		Name:        "@json.union",
+		Scope:       []string{"union"},
+		Formats:     []string{"go", "openapi"},
+		Description: "Sets the JSON encoding of a union: internally tagged, adjacently tagged, or untagged",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "strategy",
+				Type:        "string",
+				Required:    true,
+				Description: "internal ({\"type\": \"Circle\", ...}), adjacent ({\"type\": \"Circle\", \"value\": {...}}), or untagged",
+			},
+			{
+				Name:        "tag",
+				Type:        "string",
+				Required:    false,
+				Description: "Property naming the option of tagged unions (default: type)",
+			},
+			{
+				Name:        "content",
+				Type:        "string",
+				Required:    false,
+				Description: "Property holding the option of adjacently tagged unions (default: value)",
+			},
+		},
+		Examples: []string{
+			`@json.union(internal)`,
+			`@json.union(adjacent, tag="kind", content="data")`,
+		},
+	})
+
+	registry.Register(&AnnotationMetadata{
+		Name:        "@go.name",
+		Scope:       []string{"field"},
+		Formats:     []string{"go"},
+		Description: "Overrides the Go struct field name",
+		Parameters: []ParameterMetadata{
+			{
+				Name:        "name",
+				Type:        "string",
+				Required:    true,
+				Description: "Go field name",
+			},
+		},
+		Examples: []string{
+			`userId: string @go.name("UserID")`,
+		},
+	})
+
 	registry.Register(&AnnotationMetadata{
 		Name:        "@json.nullable",
 		Scope:       []string{"field"},
@@ -128,16 +128,20 @@ func (v *Validator) validateFieldAnnotations(schemaType *ast.Type, fieldAnnotati
 		path := fmt.Sprintf("%s.fields.%s", basePath, fieldName)
 
 		// Check if field exists
-		fieldExists := false
+		var schemaField *ast.Field
 		for _, field := range schemaType.Fields {
 			if field.Name == fieldName {
-				fieldExists = true
+				schemaField = field
 				break
 			}
 		}
 
-		if !fieldExists {
+		if schemaField == nil {
 			v.addError(path, fmt.Sprintf("references non-existent field '%s.%s'", schemaType.Name, fieldName))
+		} else if schemaField.InheritedFrom != "" {
+			v.addError(path, fmt.Sprintf("field '%s.%s' is inherited from '%s' (annotate it on the declaring type)", schemaType.Name, fieldName, schemaField.InheritedFrom))
+		} else if annotations.Required && schemaField.Type != nil && schemaField.Type.Optional {
+			v.addError(path, fmt.Sprintf("'required' contradicts the optional type of '%s.%s' (remove the ? suffix or the annotation)", schemaType.Name, fieldName))
 		}
 
 		// Validate annotation values
@@ -145,6 +149,10 @@ func (v *Validator) validateFieldAnnotations(schemaType *ast.Type, fieldAnnotati
 			v.addError(path, "cannot specify both 'exclude' and 'only' annotations")
 		}
 
+		if annotations.Sensitive != "" && !ast.IsSensitivityKind(annotations.Sensitive) {
+			v.addError(path, fmt.Sprintf("invalid sensitive kind: '%s' (must be %s)", annotations.Sensitive, strings.Join(ast.SensitivityKinds, ", ")))
+		}
+
 		// Validate generator names
 		validGenerators := map[string]bool{"proto": true, "graphql": true, "openapi": true}
 		for _, gen := range annotations.Exclude {
@@ -209,6 +217,21 @@ func (v *Validator) validateMethodAnnotations(schemaService *ast.Service, method
 				v.addError(path, fmt.Sprintf("invalid HTTP status code in errors: %d", code))
 			}
 		}
+
+		// Validate call policies
+		if annotations.Timeout != "" {
+			if _, err := ast.ParseTimeout(annotations.Timeout); err != nil {
+				v.addError(path, err.Error())
+			}
+		}
+		if annotations.RateLimit != nil {
+			if err := annotations.RateLimit.toAST().Validate(); err != nil {
+				v.addError(path, err.Error())
+			}
+		}
+		if annotations.Webhook != nil && (annotations.Webhook.On == "") != (annotations.Webhook.URL == "") {
+			v.addError(path, "a callback webhook needs both on and url")
+		}
 	}
 }
 
@@ -1,6 +1,7 @@
 package annotations
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/rasmartins/typemux/internal/ast"
@@ -164,6 +165,56 @@ func TestValidator_ConflictingFieldAnnotations(t *testing.T) {
 	}
 }
 
+func TestValidator_RequiredOptionalField(t *testing.T) {
+	schema := createTestSchema()
+	schema.Types[1].Fields[1].Type.Optional = true
+	validator := NewValidator(schema)
+
+	annotations := &YAMLAnnotations{
+		Types: map[string]*TypeAnnotations{
+			"Product": {
+				Fields: map[string]*FieldAnnotations{
+					"id":   {Required: true},
+					"name": {Required: true},
+				},
+			},
+		},
+	}
+
+	errors := validator.Validate(annotations)
+	if len(errors) != 1 {
+		t.Fatalf("Expected 1 validation error, got %d", len(errors))
+	}
+
+	if errors[0].Path != "types.Product.fields.name" {
+		t.Errorf("Unexpected error path: %s", errors[0].Path)
+	}
+}
+
+func TestValidator_InheritedField(t *testing.T) {
+	schema := createTestSchema()
+	schema.Types[1].Fields[0].InheritedFrom = "com.example.Entity"
+	validator := NewValidator(schema)
+
+	annotations := &YAMLAnnotations{
+		Types: map[string]*TypeAnnotations{
+			"Product": {
+				Fields: map[string]*FieldAnnotations{
+					"id": {Required: true},
+				},
+			},
+		},
+	}
+
+	errors := validator.Validate(annotations)
+	if len(errors) != 1 {
+		t.Fatalf("Expected 1 validation error, got %d", len(errors))
+	}
+	if !strings.Contains(errors[0].Message, "inherited from 'com.example.Entity'") {
+		t.Errorf("Unexpected error message: %s", errors[0].Message)
+	}
+}
+
 func TestValidator_InvalidGeneratorName(t *testing.T) {
 	schema := createTestSchema()
 	validator := NewValidator(schema)
@@ -216,6 +267,39 @@ func TestValidator_InvalidHTTPMethod(t *testing.T) {
 	}
 }
 
+func TestValidator_InvalidMethodPolicies(t *testing.T) {
+	schema := createTestSchema()
+	validator := NewValidator(schema)
+
+	annotations := &YAMLAnnotations{
+		Services: map[string]*ServiceAnnotations{
+			"UserService": {
+				Methods: map[string]*MethodAnnotations{
+					"GetUser": {
+						Timeout:   "soon",
+						RateLimit: &RateLimitAnnotations{Requests: 10, Per: "week"},
+						Webhook:   &WebhookAnnotations{On: "CreateUser"},
+					},
+				},
+			},
+		},
+	}
+
+	errors := validator.Validate(annotations)
+	if len(errors) != 3 {
+		t.Fatalf("Expected 3 validation errors, got %d: %v", len(errors), errors)
+	}
+	if !strings.Contains(errors[0].Message, `invalid timeout "soon"`) {
+		t.Errorf("Unexpected timeout error: %s", errors[0].Message)
+	}
+	if !strings.Contains(errors[1].Message, `unknown rate limit window "week"`) {
+		t.Errorf("Unexpected rate limit error: %s", errors[1].Message)
+	}
+	if !strings.Contains(errors[2].Message, "a callback webhook needs both on and url") {
+		t.Errorf("Unexpected webhook error: %s", errors[2].Message)
+	}
+}
+
 func TestValidator_InvalidGraphQLOperationType(t *testing.T) {
 	schema := createTestSchema()
 	validator := NewValidator(schema)
@@ -412,3 +496,28 @@ func TestValidator_FormatErrors(t *testing.T) {
 		t.Errorf("Expected formatted errors to be substantial, got: %s", formatted)
 	}
 }
+
+func TestValidator_InvalidSensitiveKind(t *testing.T) {
+	schema := createTestSchema()
+	validator := NewValidator(schema)
+
+	annotations := &YAMLAnnotations{
+		Types: map[string]*TypeAnnotations{
+			"User": {
+				Fields: map[string]*FieldAnnotations{
+					"email":    {Sensitive: "pii"},
+					"username": {Sensitive: "private"},
+				},
+			},
+		},
+	}
+
+	errors := validator.Validate(annotations)
+	if len(errors) != 1 {
+		t.Fatalf("Expected 1 validation error, got %d", len(errors))
+	}
+
+	if errors[0].Message != "invalid sensitive kind: 'private' (must be pii, secret, phi)" {
+		t.Errorf("Unexpected error message: %s", errors[0].Message)
+	}
+}
@@ -94,6 +94,8 @@ type FieldAnnotations struct {
 	Deprecated *DeprecationAnnotations    `yaml:"deprecated"`
 	Validation *ValidationAnnotations     `yaml:"validation"`
 	Since      string                     `yaml:"since"`
+	Sensitive  string                     `yaml:"sensitive"`
+	Views      []string                   `yaml:"views"`
 }
 
 // EnumAnnotations represents annotations for an enum
@@ -120,12 +122,56 @@ type ServiceAnnotations struct {
 
 // MethodAnnotations represents annotations for an RPC method
 type MethodAnnotations struct {
-	HTTP    string                     `yaml:"http"`
-	Path    string                     `yaml:"path"`
-	GraphQL string                     `yaml:"graphql"`
-	Success []int                      `yaml:"success"`
-	Errors  []int                      `yaml:"errors"`
-	Proto   *FormatSpecificAnnotations `yaml:"proto"`
+	HTTP        string                     `yaml:"http"`
+	Path        string                     `yaml:"path"`
+	GraphQL     string                     `yaml:"graphql"`
+	Success     []int                      `yaml:"success"`
+	Errors      []int                      `yaml:"errors"`
+	Timeout     string                     `yaml:"timeout"`
+	Idempotent  bool                       `yaml:"idempotent"`
+	LongRunning bool                       `yaml:"longrunning"`
+	Batch       bool                       `yaml:"batch"`
+	RateLimit   *RateLimitAnnotations      `yaml:"ratelimit"`
+	Auth        *AuthAnnotations           `yaml:"auth"`
+	Webhook     *WebhookAnnotations        `yaml:"webhook"`
+	View        string                     `yaml:"view"`
+	Examples    []*ExampleAnnotations      `yaml:"examples"`
+	CodeSamples []*CodeSampleAnnotations   `yaml:"code_samples"`
+	Proto       *FormatSpecificAnnotations `yaml:"proto"`
+}
+
+// ExampleAnnotations is a sample call of a method, with its request and response
+// written in YAML
+type ExampleAnnotations struct {
+	Name     string      `yaml:"name"` // Defaults to default
+	Summary  string      `yaml:"summary"`
+	Request  interface{} `yaml:"request"`
+	Response interface{} `yaml:"response"`
+}
+
+// CodeSampleAnnotations shows how to call a method in a language
+type CodeSampleAnnotations struct {
+	Lang   string `yaml:"lang"`
+	Label  string `yaml:"label"`
+	Source string `yaml:"source"`
+}
+
+// RateLimitAnnotations represents the request quota of a method
+type RateLimitAnnotations struct {
+	Requests int    `yaml:"requests"`
+	Per      string `yaml:"per"` // Defaults to second
+}
+
+// AuthAnnotations is the authentication a method requires of its callers
+type AuthAnnotations struct {
+	Scopes []string `yaml:"scopes"` // None when any authenticated caller may call
+}
+
+// WebhookAnnotations marks a method as a webhook, or as a callback of another method
+type WebhookAnnotations struct {
+	Name string `yaml:"name"` // Defaults to the method name in lower camel case
+	On   string `yaml:"on"`
+	URL  string `yaml:"url"`
 }
 
 // LoadYAMLAnnotations loads annotations from a YAML file
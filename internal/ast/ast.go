@@ -1,88 +1,191 @@
 package ast
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // Schema represents the entire IDL schema
 type Schema struct {
-	Namespace            string             // Optional namespace (e.g., "com.example.api")
-	TypeMUXVersion       string             // TypeMUX IDL format version (e.g., "1.0.0")
-	Version              string             // Schema version (e.g., "1.0.0", "2.1.3")
-	NamespaceAnnotations *FormatAnnotations // Namespace-level annotations
-	Imports              []string           // Imported file paths
-	Enums                []*Enum
-	Types                []*Type
-	Unions               []*Union
-	Services             []*Service
-	TypeRegistry         *TypeRegistry // Registry for resolving qualified type names
+	Namespace            string             `json:"namespace,omitempty"`            // Optional namespace (e.g., "com.example.api")
+	TypeMUXVersion       string             `json:"typemuxVersion,omitempty"`       // TypeMUX IDL format version (e.g., "1.0.0")
+	Version              string             `json:"version,omitempty"`              // Schema version (e.g., "1.0.0", "2.1.3")
+	NamespaceAnnotations *FormatAnnotations `json:"namespaceAnnotations,omitempty"` // Namespace-level annotations
+	Imports              []string           `json:"imports,omitempty"`              // Imported file paths
+	Enums                []*Enum            `json:"enums,omitempty"`
+	Types                []*Type            `json:"types,omitempty"`
+	Unions               []*Union           `json:"unions,omitempty"`
+	Services             []*Service         `json:"services,omitempty"`
+	TypeRegistry         *TypeRegistry      `json:"-"` // Registry for resolving qualified type names
+
+	// Namespace-level annotations of other namespaces (e.g., from imported files), keyed by namespace
+	ImportedNamespaceAnnotations map[string]*FormatAnnotations `json:"importedNamespaceAnnotations,omitempty"`
+}
+
+// GetNamespaceAnnotations returns the namespace-level annotations for the given namespace,
+// looking in imported namespaces when it is not the schema's own namespace
+func (s *Schema) GetNamespaceAnnotations(namespace string) *FormatAnnotations {
+	if namespace == s.Namespace {
+		return s.NamespaceAnnotations
+	}
+	return s.ImportedNamespaceAnnotations[namespace]
+}
+
+// HygieneWarnings returns schema hygiene problems that do not prevent generation:
+// Protobuf fields without an explicit field number and service methods whose HTTP
+// method is inferred from their name
+func (s *Schema) HygieneWarnings() []string {
+	var warnings []string
+	for _, typ := range s.Types {
+		for _, field := range typ.Fields {
+			// Inherited fields are reported on their declaring type
+			if field.InheritedFrom != "" {
+				continue
+			}
+			if !field.HasNumber && field.ShouldIncludeInGenerator("proto") && len(field.Arguments) == 0 {
+				warnings = append(warnings, fmt.Sprintf("field %s.%s has no field number", typ.Name, field.Name))
+			}
+		}
+	}
+	for _, service := range s.Services {
+		for _, method := range service.Methods {
+			if method.HTTPMethod == "" {
+				warnings = append(warnings, fmt.Sprintf("method %s.%s has no @http method, %s is inferred from its name",
+					service.Name, method.Name, strings.ToUpper(method.GetHTTPMethod())))
+			}
+		}
+	}
+	return warnings
 }
 
 // Enum represents an enumeration type
 type Enum struct {
-	Name        string
-	Namespace   string // Namespace this enum belongs to
-	Values      []*EnumValue
-	Doc         *Documentation
-	Annotations *FormatAnnotations // Format-specific annotations
+	Name        string             `json:"name"`
+	Namespace   string             `json:"namespace,omitempty"` // Namespace this enum belongs to
+	Values      []*EnumValue       `json:"values,omitempty"`
+	Doc         *Documentation     `json:"doc,omitempty"`
+	Annotations *FormatAnnotations `json:"annotations,omitempty"` // Format-specific annotations
+	Flags       bool               `json:"flags,omitempty"`       // Bitmask whose values are combined, from @flags
+}
+
+// MaxFlag is the largest value of a flags enum, the highest bit of an int32.
+const MaxFlag = 1 << 30
+
+// ValidFlag reports whether a number can be a value of a flags enum: zero, for
+// no flags, or a power of two up to MaxFlag.
+func ValidFlag(number int) bool {
+	return number >= 0 && number <= MaxFlag && number&(number-1) == 0
+}
+
+// ValueNumbers returns the number of each value of the enum, in order. Values
+// without a number follow the largest number before them, starting at 1: the
+// next integer, or the next power of two in a flags enum.
+func (e *Enum) ValueNumbers() []int {
+	numbers := make([]int, len(e.Values))
+	next := 1
+	for i, value := range e.Values {
+		number := next
+		if value.HasNumber {
+			number = value.Number
+		}
+		numbers[i] = number
+		if number < next {
+			continue
+		}
+		next = number + 1
+		if e.Flags {
+			next = 1
+			for next <= number && next <= MaxFlag {
+				next <<= 1
+			}
+		}
+	}
+	return numbers
 }
 
 // EnumValue represents a single enum value with optional number
 type EnumValue struct {
-	Name      string
-	Number    int  // Protobuf field number
-	HasNumber bool // Whether a custom number was specified
-	Doc       *Documentation
+	Name      string         `json:"name"`
+	Number    int            `json:"number,omitempty"`    // Protobuf field number
+	HasNumber bool           `json:"hasNumber,omitempty"` // Whether a custom number was specified
+	Doc       *Documentation `json:"doc,omitempty"`
 }
 
 // Type represents a data type definition
 type Type struct {
-	Name        string
-	Namespace   string // Namespace this type belongs to
-	Fields      []*Field
-	Doc         *Documentation
-	Annotations *FormatAnnotations // Format-specific annotations
+	Name        string             `json:"name"`
+	Namespace   string             `json:"namespace,omitempty"` // Namespace this type belongs to
+	Extends     []string           `json:"extends,omitempty"`   // Base types whose fields this type inherits
+	Fields      []*Field           `json:"fields,omitempty"`    // Inherited fields first, then declared ones
+	Doc         *Documentation     `json:"doc,omitempty"`
+	Annotations *FormatAnnotations `json:"annotations,omitempty"` // Format-specific annotations
+	ViewOf      string             `json:"viewOf,omitempty"`      // Qualified name of the type a view type is derived from
+	View        string             `json:"view,omitempty"`        // View a view type holds the fields of
+	LongRunning bool               `json:"longRunning,omitempty"` // Derived by ResolveLongRunning for the operations of @longrunning methods
+	Batch       bool               `json:"batch,omitempty"`       // Derived by ResolveBatches for the requests and responses of @batch methods
+	Resource    bool               `json:"resource,omitempty"`    // Derived by ResolveResources for the requests and responses of @crud types
 }
 
 // Union represents a union/oneOf type (can be one of several types)
 type Union struct {
-	Name        string
-	Namespace   string   // Namespace this union belongs to
-	Options     []string // Names of the types that can be in this union
-	Doc         *Documentation
-	Annotations *FormatAnnotations // Format-specific annotations
+	Name        string             `json:"name"`
+	Namespace   string             `json:"namespace,omitempty"` // Namespace this union belongs to
+	Options     []string           `json:"options,omitempty"`   // Names of the types that can be in this union
+	Doc         *Documentation     `json:"doc,omitempty"`
+	Annotations *FormatAnnotations `json:"annotations,omitempty"` // Format-specific annotations
+	Encoding    *UnionEncoding     `json:"encoding,omitempty"`    // JSON encoding of values (from @json.union annotation)
 }
 
 // Field represents a field in a type
 type Field struct {
-	Name          string
-	Type          *FieldType
-	Arguments     []*FieldArgument // Field arguments (for parameterized queries like GraphQL)
-	Required      bool
-	Default       string
-	Attributes    map[string]string
-	Doc           *Documentation
-	ExcludeFrom   []string           // List of generators to exclude this field from
-	OnlyFor       []string           // If set, only include in these generators
-	Number        int                // Protobuf field number
-	HasNumber     bool               // Whether a custom number was specified
-	Annotations   *FormatAnnotations // Format-specific annotations
-	Deprecated    *DeprecationInfo   // Deprecation information
-	Validation    *ValidationRules   // Validation rules
-	Since         string             // Version when this field was added (e.g., "2.0.0")
-	JSONName      string             // JSON field name override (from @json.name annotation)
-	JSONNullable  bool               // Whether field is explicitly nullable in JSON (from @json.nullable annotation)
-	JSONOmitEmpty bool               // Whether to omit field if empty in JSON (from @json.omitempty annotation)
+	Name          string             `json:"name"`
+	Type          *FieldType         `json:"type"`
+	Arguments     []*FieldArgument   `json:"arguments,omitempty"` // Field arguments (for parameterized queries like GraphQL)
+	Required      bool               `json:"required,omitempty"`
+	Default       string             `json:"default,omitempty"`
+	Attributes    map[string]string  `json:"attributes,omitempty"`
+	Doc           *Documentation     `json:"doc,omitempty"`
+	ExcludeFrom   []string           `json:"excludeFrom,omitempty"`   // List of generators to exclude this field from
+	OnlyFor       []string           `json:"onlyFor,omitempty"`       // If set, only include in these generators
+	Number        int                `json:"number,omitempty"`        // Protobuf field number
+	HasNumber     bool               `json:"hasNumber,omitempty"`     // Whether a custom number was specified
+	Annotations   *FormatAnnotations `json:"annotations,omitempty"`   // Format-specific annotations
+	Deprecated    *DeprecationInfo   `json:"deprecated,omitempty"`    // Deprecation information
+	Validation    *ValidationRules   `json:"validation,omitempty"`    // Validation rules
+	Since         string             `json:"since,omitempty"`         // Version when this field was added (e.g., "2.0.0")
+	Until         string             `json:"until,omitempty"`         // Last version this field is part of, from @until (e.g., "2.0.0")
+	JSONName      string             `json:"jsonName,omitempty"`      // JSON field name override (from @json.name annotation)
+	JSONNullable  bool               `json:"jsonNullable,omitempty"`  // Whether field is explicitly nullable in JSON (from @json.nullable annotation)
+	JSONOmitEmpty bool               `json:"jsonOmitEmpty,omitempty"` // Whether to omit field if empty in JSON (from @json.omitempty annotation)
+	Sensitivity   string             `json:"sensitivity,omitempty"`   // Kind of sensitive data the field holds: pii, secret, or phi (from @sensitive)
+	Views         []string           `json:"views,omitempty"`         // Views of the type the field is part of (from @view)
+	InheritedFrom string             `json:"inheritedFrom,omitempty"` // Qualified name of the declaring type, for fields copied from a base type
+}
+
+// SensitivityKinds are the kinds of sensitive data a field can hold: personal
+// data (pii), credentials and keys (secret), and health information (phi).
+var SensitivityKinds = []string{"pii", "secret", "phi"}
+
+// IsSensitivityKind reports whether kind is one of SensitivityKinds.
+func IsSensitivityKind(kind string) bool {
+	for _, k := range SensitivityKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
 }
 
 // FieldArgument represents an argument/parameter to a field (like GraphQL field arguments)
 type FieldArgument struct {
-	Name        string
-	Type        *FieldType
-	Required    bool
-	Default     string
-	Attributes  map[string]string
-	Doc         *Documentation
-	Validation  *ValidationRules   // Validation rules for the argument
-	Annotations *FormatAnnotations // Format-specific annotations for the argument
+	Name        string             `json:"name"`
+	Type        *FieldType         `json:"type"`
+	Required    bool               `json:"required,omitempty"`
+	Default     string             `json:"default,omitempty"`
+	Attributes  map[string]string  `json:"attributes,omitempty"`
+	Doc         *Documentation     `json:"doc,omitempty"`
+	Validation  *ValidationRules   `json:"validation,omitempty"`  // Validation rules for the argument
+	Annotations *FormatAnnotations `json:"annotations,omitempty"` // Format-specific annotations for the argument
 }
 
 // ShouldIncludeInGenerator checks if a field should be included in a specific generator
@@ -107,16 +210,77 @@ func (f *Field) ShouldIncludeInGenerator(generator string) bool {
 	return true
 }
 
+// NameFor returns the name of the field in a generator ("proto", "graphql",
+// "openapi", or "go"): its @proto.name, @graphql.name, @openapi.name, or @go.name
+// override, or else its name. OpenAPI properties fall back to the @json.name.
+func (f *Field) NameFor(generator string) string {
+	if a := f.Annotations; a != nil {
+		switch {
+		case generator == "proto" && a.ProtoName != "":
+			return a.ProtoName
+		case generator == "graphql" && a.GraphQLName != "":
+			return a.GraphQLName
+		case generator == "openapi" && a.OpenAPIName != "":
+			return a.OpenAPIName
+		case generator == "go" && a.GoName != "":
+			return a.GoName
+		}
+	}
+	if generator == "openapi" && f.JSONName != "" {
+		return f.JSONName
+	}
+	return f.Name
+}
+
+// Presence describes whether a field must be set. It unifies @required and the ? type suffix.
+type Presence int
+
+const (
+	// PresenceImplicit is a field with neither @required nor ?: each format applies its default.
+	PresenceImplicit Presence = iota
+	// PresenceRequired is a field marked @required: it must be set.
+	PresenceRequired
+	// PresenceOptional is a field typed with ?: it may be absent, and its absence is observable.
+	PresenceOptional
+)
+
+// String returns the presence in schema notation: "required!", "optional?", or "implicit".
+func (p Presence) String() string {
+	switch p {
+	case PresenceRequired:
+		return "required!"
+	case PresenceOptional:
+		return "optional?"
+	default:
+		return "implicit"
+	}
+}
+
+// Presence resolves the presence of the field. The ? suffix takes precedence over
+// @required, which generators have always ignored on optional fields; the parser
+// and YAML annotation validator report the combination as a contradiction.
+func (f *Field) Presence() Presence {
+	switch {
+	case f.Type != nil && f.Type.Optional:
+		return PresenceOptional
+	case f.Required:
+		return PresenceRequired
+	default:
+		return PresenceImplicit
+	}
+}
+
 // FieldType represents the type of a field
 type FieldType struct {
-	Name         string // base type name (set to "map" for map types)
-	IsArray      bool
-	IsMap        bool
-	MapKey       string     // for map types - the key type (must be string or int)
-	MapValue     string     // for simple map value types (deprecated - use MapValueType for new code)
-	MapValueType *FieldType // for complex map value types (supports nested maps, arrays, etc.)
-	IsBuiltin    bool
-	Optional     bool // true if the type has a ? suffix (e.g., string?)
+	Name         string     `json:"name"` // base type name (set to "map" for map types)
+	IsArray      bool       `json:"isArray,omitempty"`
+	IsMap        bool       `json:"isMap,omitempty"`
+	MapKey       string     `json:"mapKey,omitempty"`       // for map types - the key type (must be string or int)
+	MapValue     string     `json:"mapValue,omitempty"`     // for simple map value types (deprecated - use MapValueType for new code)
+	MapValueType *FieldType `json:"mapValueType,omitempty"` // for complex map value types (supports nested maps, arrays, etc.)
+	IsBuiltin    bool       `json:"isBuiltin,omitempty"`
+	Optional     bool       `json:"optional,omitempty"`     // true if the type has a ? suffix (e.g., string?)
+	NonNullItems bool       `json:"nonNullItems,omitempty"` // true if the elements of an array cannot be null, from a ! after the element type (e.g., []string!)
 }
 
 // GetMapValueType returns the map value type, supporting both simple string values and complex FieldType values
@@ -134,6 +298,49 @@ func (ft *FieldType) GetMapValueType() *FieldType {
 	return nil
 }
 
+// ElementType returns the type of the elements of an array, which can itself be
+// an array (for [][]T) or a map (for []map<K, V>), or nil if the type is not an array.
+func (ft *FieldType) ElementType() *FieldType {
+	if !ft.IsArray {
+		return nil
+	}
+	if ft.Name == "map" && ft.MapKey != "" {
+		return &FieldType{
+			Name:         "map",
+			IsMap:        true,
+			MapKey:       ft.MapKey,
+			MapValue:     ft.MapValue,
+			MapValueType: ft.MapValueType,
+		}
+	}
+	if strings.HasPrefix(ft.Name, "[]") {
+		name := strings.TrimPrefix(ft.Name, "[]")
+		return &FieldType{Name: name, IsArray: true, IsBuiltin: IsBuiltinType(strings.TrimLeft(name, "[]"))}
+	}
+	return &FieldType{Name: ft.Name, IsBuiltin: ft.IsBuiltin}
+}
+
+// String returns the type in schema syntax, such as []string!, []map<string, int32>,
+// or map<string, []Item>, without the ? of optional fields.
+func (ft *FieldType) String() string {
+	switch {
+	case ft.IsMap:
+		value := ft.MapValue
+		if valueType := ft.GetMapValueType(); valueType != nil {
+			value = valueType.String()
+			if valueType.Optional {
+				value += "?"
+			}
+		}
+		return fmt.Sprintf("map<%s, %s>", ft.MapKey, value)
+	case ft.IsArray && ft.NonNullItems:
+		return "[]" + ft.ElementType().String() + "!"
+	case ft.IsArray:
+		return "[]" + ft.ElementType().String()
+	}
+	return ft.Name
+}
+
 // GetMapValueTypeName returns the type name for simple cases (backward compatibility)
 func (ft *FieldType) GetMapValueTypeName() string {
 	if ft.MapValueType != nil {
@@ -144,26 +351,53 @@ func (ft *FieldType) GetMapValueTypeName() string {
 
 // Service represents a service definition
 type Service struct {
-	Name        string
-	Namespace   string // Namespace this service belongs to
-	Methods     []*Method
-	Doc         *Documentation
-	Annotations *FormatAnnotations // Format-specific annotations
+	Name        string             `json:"name"`
+	Namespace   string             `json:"namespace,omitempty"` // Namespace this service belongs to
+	Methods     []*Method          `json:"methods,omitempty"`
+	Doc         *Documentation     `json:"doc,omitempty"`
+	Annotations *FormatAnnotations `json:"annotations,omitempty"` // Format-specific annotations
+	Resources   bool               `json:"resources,omitempty"`   // Added by ResolveResources for the methods of @crud types
+	BasePath    string             `json:"basePath,omitempty"`    // Prefix of the HTTP paths of its methods (e.g., "/v1/users"), from @http.base_path
 }
 
 // Method represents an RPC method
 type Method struct {
-	Name         string
-	InputType    string
-	OutputType   string
-	InputStream  bool // Client-side streaming
-	OutputStream bool // Server-side streaming
-	Doc          *Documentation
-	HTTPMethod   string   // HTTP method for OpenAPI (GET, POST, PUT, DELETE, PATCH)
-	GraphQLType  string   // GraphQL operation type (query, mutation, subscription)
-	PathTemplate string   // URL path template for OpenAPI (e.g., "/users/{id}")
-	SuccessCodes []string // Additional success HTTP codes beyond 200 (e.g., "201", "204")
-	ErrorCodes   []string // Expected HTTP error codes (e.g., "400", "404", "500")
+	Name         string         `json:"name"`
+	InputType    string         `json:"inputType,omitempty"`    // Empty for methods declared with ()
+	OutputType   string         `json:"outputType,omitempty"`   // Empty for methods declared with returns ()
+	InputStream  bool           `json:"inputStream,omitempty"`  // Client-side streaming
+	OutputStream bool           `json:"outputStream,omitempty"` // Server-side streaming
+	Doc          *Documentation `json:"doc,omitempty"`
+	HTTPMethod   string         `json:"httpMethod,omitempty"`   // HTTP method for OpenAPI (GET, POST, PUT, DELETE, PATCH)
+	GraphQLType  string         `json:"graphqlType,omitempty"`  // GraphQL operation type (query, mutation, subscription)
+	PathTemplate string         `json:"pathTemplate,omitempty"` // URL path template for OpenAPI (e.g., "/users/{id}")
+	SuccessCodes []string       `json:"successCodes,omitempty"` // Additional success HTTP codes beyond 200 (e.g., "201", "204")
+	ErrorCodes   []string       `json:"errorCodes,omitempty"`   // Expected HTTP error codes (e.g., "400", "404", "500")
+	Timeout      string         `json:"timeout,omitempty"`      // Deadline of a call (e.g., "5s"), from @timeout
+	Idempotent   bool           `json:"idempotent,omitempty"`   // Safe to retry, from @idempotent
+	RateLimit    *RateLimit     `json:"rateLimit,omitempty"`    // Request quota, from @ratelimit
+	Auth         *Auth          `json:"auth,omitempty"`         // Authentication of callers, from @auth on the method or its service
+	Webhook      *Webhook       `json:"webhook,omitempty"`      // Sent by the API rather than served, from @webhook
+	View         string         `json:"view,omitempty"`         // View of the output type the method responds with, from @view
+	LongRunning  *LongRunning   `json:"longRunning,omitempty"`  // Responds with an operation to poll, from @longrunning
+	Polls        string         `json:"polls,omitempty"`        // Long-running method whose operations a derived method returns
+	Batch        bool           `json:"batch,omitempty"`        // Has a derived method that makes many calls at once, from @batch
+	Batches      string         `json:"batches,omitempty"`      // Method whose calls a derived method makes many of at once
+	Resource     string         `json:"resource,omitempty"`     // @crud type whose standard method a derived method is
+	Examples     []*Example     `json:"examples,omitempty"`     // Sample calls, from @openapi.example
+	CodeSamples  []*CodeSample  `json:"codeSamples,omitempty"`  // How to call the method in other languages, from @openapi.code_sample
+
+	Annotations *FormatAnnotations `json:"annotations,omitempty"` // Format-specific annotations
+}
+
+// HasInput reports whether the method takes a request message; rpc Ping() has none
+func (m *Method) HasInput() bool {
+	return m.InputType != ""
+}
+
+// HasOutput reports whether the method returns a response message; returns () has none
+func (m *Method) HasOutput() bool {
+	return m.OutputType != ""
 }
 
 // GetHTTPMethod returns the HTTP method, using heuristics if not explicitly set
@@ -178,6 +412,26 @@ func (m *Method) GetHTTPMethod() string {
 	return "post"
 }
 
+// HTTPPath returns the HTTP path of a method of a service: the @http.base_path
+// of the service followed by the @http.path of the method. Without @http.path
+// the method maps to /<method> under the base path, or to /<service>/<method>
+// in lower case when the service has none.
+func (m *Method) HTTPPath(service *Service) string {
+	return JoinPaths(service.BasePath, m.RelativeHTTPPath(service))
+}
+
+// RelativeHTTPPath returns the HTTP path of a method of a service without the
+// @http.base_path of the service.
+func (m *Method) RelativeHTTPPath(service *Service) string {
+	if m.PathTemplate != "" {
+		return m.PathTemplate
+	}
+	if service.BasePath != "" {
+		return "/" + strings.ToLower(m.Name)
+	}
+	return fmt.Sprintf("/%s/%s", strings.ToLower(service.Name), strings.ToLower(m.Name))
+}
+
 // GetGraphQLType returns the GraphQL operation type, using heuristics if not explicitly set
 func (m *Method) GetGraphQLType() string {
 	if m.GraphQLType != "" {
@@ -196,8 +450,11 @@ func (m *Method) GetGraphQLType() string {
 
 // Documentation represents documentation comments
 type Documentation struct {
-	General  string            // General documentation for all languages
-	Specific map[string]string // Language-specific documentation (proto, graphql, openapi)
+	General  string            `json:"general,omitempty"`  // General documentation for all languages
+	Specific map[string]string `json:"specific,omitempty"` // Language-specific documentation (proto, graphql, openapi)
+	Locales  map[string]string `json:"locales,omitempty"`  // Translations by locale, from @lang(locale) lines
+	File     string            `json:"file,omitempty"`     // Markdown file with extended documentation, from an @doc-file line
+	Extended string            `json:"extended,omitempty"` // Content of File, read by the loader relative to the schema file
 }
 
 // GetDoc returns the documentation for a specific language, falling back to general doc
@@ -211,6 +468,33 @@ func (d *Documentation) GetDoc(lang string) string {
 	return d.General
 }
 
+// GetLocaleDoc returns the documentation in a locale such as es or pt-BR, falling
+// back to its parent locales and then to the general doc. Locales are matched
+// case-insensitively, and underscores match hyphens.
+func (d *Documentation) GetLocaleDoc(locale string) string {
+	if d == nil {
+		return ""
+	}
+	locale = NormalizeLocale(locale)
+	for locale != "" {
+		if text, ok := d.Locales[locale]; ok && text != "" {
+			return text
+		}
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return d.General
+}
+
+// NormalizeLocale returns the form locales are stored in: lowercase, with
+// hyphens between subtags, such as pt-br for pt_BR.
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
 // BuiltinTypes maps primitive type names to their existence in the type system.
 var BuiltinTypes = map[string]bool{
 	"string":    true,
@@ -234,14 +518,55 @@ func IsBuiltinType(typeName string) bool {
 
 // FormatAnnotations holds format-specific annotations for types and fields
 type FormatAnnotations struct {
-	Proto       []string // Protobuf options: ["packed = false", "retention = RETENTION_SOURCE"]
-	GraphQL     []string // GraphQL directives: ["@key(fields: \"id\")", "@external"]
-	OpenAPI     []string // OpenAPI extensions: ["x-internal-id: prod", "x-format: currency"]
-	Go          []string // Go options: ["package = \"mypackage\""]
-	ProtoName   string   // Override name for Protobuf generation (from @proto.name annotation)
-	GraphQLName string   // Override name for GraphQL generation (from @graphql.name annotation)
-	OpenAPIName string   // Override name for OpenAPI generation (from @openapi.name annotation)
-	GoName      string   // Override name for Go generation (from @go.name annotation)
+	Proto       []string `json:"proto,omitempty"`       // Protobuf options: ["packed = false", "retention = RETENTION_SOURCE"]
+	GraphQL     []string `json:"graphql,omitempty"`     // GraphQL directives: ["@key(fields: \"id\")", "@external"]
+	OpenAPI     []string `json:"openapi,omitempty"`     // OpenAPI extensions: ["x-internal-id: prod", "x-format: currency"]
+	Go          []string `json:"go,omitempty"`          // Go options: ["package = \"mypackage\""]
+	ProtoName   string   `json:"protoName,omitempty"`   // Override name for Protobuf generation (from @proto.name annotation)
+	GraphQLName string   `json:"graphqlName,omitempty"` // Override name for GraphQL generation (from @graphql.name annotation)
+	OpenAPIName string   `json:"openapiName,omitempty"` // Override name for OpenAPI generation (from @openapi.name annotation)
+	GoName      string   `json:"goName,omitempty"`      // Override name for Go generation (from @go.name annotation)
+
+	UnionEncoding *UnionEncoding `json:"-"` // JSON encoding of a union (from @json.union annotation), moved to Union.Encoding by the parser
+	BasePath      string         `json:"-"` // Prefix of the HTTP paths of a service (from @http.base_path), moved to Service.BasePath by the parser
+
+	GraphQLMap *GraphQLMap `json:"graphqlMap,omitempty"` // GraphQL rendering of a map field (from @graphql.map annotation)
+
+	Ownership *Ownership `json:"ownership,omitempty"` // Owner, SLA, and tier of a namespace, type, or service (from @owner, @sla, and @tier)
+
+	Crud *Crud `json:"crud,omitempty"` // Standard methods of a resource type, or their template on a namespace (from @crud)
+
+	Auth *Auth `json:"auth,omitempty"` // Authentication of the callers of the methods of a service that declare none (from @auth)
+
+	Since string `json:"since,omitempty"` // API version a type, enum, union, service, or method was added in (from @since)
+	Until string `json:"until,omitempty"` // Last API version a type, enum, union, service, or method is part of (from @until)
+
+	// Arguments of the annotations of namespaces registered by plugins, by
+	// annotation name without the @ (e.g., "mycorp.audit") and parameter name
+	Extensions map[string]map[string]string `json:"extensions,omitempty"`
+}
+
+// Extension returns the arguments of an annotation of a namespace registered by
+// a plugin (e.g., "mycorp.audit"), and whether the element has it.
+func (a *FormatAnnotations) Extension(name string) (map[string]string, bool) {
+	if a == nil {
+		return nil, false
+	}
+	args, ok := a.Extensions[strings.TrimPrefix(name, "@")]
+	return args, ok
+}
+
+// GraphQL renderings of a map field
+const (
+	GraphQLMapEntries = "entries" // A list of key-value entry types
+	GraphQLMapJSON    = "json"    // A JSON scalar
+)
+
+// GraphQLMap is how the GraphQL output renders a map field, set with
+// @graphql.map(json) or @graphql.map(entry="LabelEntry").
+type GraphQLMap struct {
+	As    string `json:"as,omitempty"`    // GraphQLMapEntries or GraphQLMapJSON; empty follows the generator options
+	Entry string `json:"entry,omitempty"` // Name of the entry type of the map
 }
 
 // NewFormatAnnotations creates a new FormatAnnotations instance
@@ -253,98 +578,110 @@ func NewFormatAnnotations() *FormatAnnotations {
 	}
 }
 
-// TypeRegistry maintains a registry of all types, enums, and unions for namespace resolution
+// TypeRegistry maintains a registry of all types, enums, and unions for namespace resolution.
+// Declarations must be added with the Register methods, which keep the name index up to date.
 type TypeRegistry struct {
 	// Map from qualified name (namespace.TypeName) to the definition
 	Types  map[string]*Type
 	Enums  map[string]*Enum
 	Unions map[string]*Union
+
+	// Interned qualified names by namespace and name, so that each name is built once
+	qualified map[string]map[string]string
+
+	// Qualified names of the registered declarations by unqualified name, once per kind
+	byName map[string][]string
 }
 
 // NewTypeRegistry creates a new empty type registry
 func NewTypeRegistry() *TypeRegistry {
 	return &TypeRegistry{
-		Types:  make(map[string]*Type),
-		Enums:  make(map[string]*Enum),
-		Unions: make(map[string]*Union),
+		Types:     make(map[string]*Type),
+		Enums:     make(map[string]*Enum),
+		Unions:    make(map[string]*Union),
+		qualified: make(map[string]map[string]string),
+		byName:    make(map[string][]string),
+	}
+}
+
+// qualify returns the interned qualified name of a declaration
+func (tr *TypeRegistry) qualify(namespace, name string) string {
+	names, ok := tr.qualified[namespace]
+	if !ok {
+		names = make(map[string]string)
+		tr.qualified[namespace] = names
 	}
+	qualifiedName, ok := names[name]
+	if !ok {
+		qualifiedName = namespace + "." + name
+		names[name] = qualifiedName
+	}
+	return qualifiedName
+}
+
+// index records the qualified name of a newly registered declaration under its unqualified name
+func (tr *TypeRegistry) index(name, qualifiedName string) {
+	tr.byName[name] = append(tr.byName[name], qualifiedName)
 }
 
 // RegisterType registers a type in the registry
 func (tr *TypeRegistry) RegisterType(typ *Type) {
-	qualifiedName := typ.Namespace + "." + typ.Name
+	qualifiedName := tr.qualify(typ.Namespace, typ.Name)
+	if _, exists := tr.Types[qualifiedName]; !exists {
+		tr.index(typ.Name, qualifiedName)
+	}
 	tr.Types[qualifiedName] = typ
 }
 
 // RegisterEnum registers an enum in the registry
 func (tr *TypeRegistry) RegisterEnum(enum *Enum) {
-	qualifiedName := enum.Namespace + "." + enum.Name
+	qualifiedName := tr.qualify(enum.Namespace, enum.Name)
+	if _, exists := tr.Enums[qualifiedName]; !exists {
+		tr.index(enum.Name, qualifiedName)
+	}
 	tr.Enums[qualifiedName] = enum
 }
 
 // RegisterUnion registers a union in the registry
 func (tr *TypeRegistry) RegisterUnion(union *Union) {
-	qualifiedName := union.Namespace + "." + union.Name
+	qualifiedName := tr.qualify(union.Namespace, union.Name)
+	if _, exists := tr.Unions[qualifiedName]; !exists {
+		tr.index(union.Name, qualifiedName)
+	}
 	tr.Unions[qualifiedName] = union
 }
 
+// has reports whether a declaration of any kind is registered under a qualified name
+func (tr *TypeRegistry) has(qualifiedName string) bool {
+	if _, ok := tr.Types[qualifiedName]; ok {
+		return true
+	}
+	if _, ok := tr.Enums[qualifiedName]; ok {
+		return true
+	}
+	_, ok := tr.Unions[qualifiedName]
+	return ok
+}
+
 // ResolveType resolves a type name (qualified or unqualified) to its qualified name
 // If the name is already qualified (contains a dot), it returns it as-is
 // Otherwise, it tries to find it in the given namespace
 func (tr *TypeRegistry) ResolveType(name string, currentNamespace string) (string, bool) {
 	// If already qualified (contains dot), return as-is
 	if strings.Contains(name, ".") {
-		// Check if it exists
-		if _, ok := tr.Types[name]; ok {
-			return name, true
-		}
-		if _, ok := tr.Enums[name]; ok {
-			return name, true
-		}
-		if _, ok := tr.Unions[name]; ok {
-			return name, true
-		}
-		return name, false
+		return name, tr.has(name)
 	}
 
 	// Try current namespace first
-	qualifiedName := currentNamespace + "." + name
-	if _, ok := tr.Types[qualifiedName]; ok {
-		return qualifiedName, true
-	}
-	if _, ok := tr.Enums[qualifiedName]; ok {
+	if qualifiedName, ok := tr.qualified[currentNamespace][name]; ok && tr.has(qualifiedName) {
 		return qualifiedName, true
 	}
-	if _, ok := tr.Unions[qualifiedName]; ok {
-		return qualifiedName, true
-	}
-
-	// Try all namespaces (for unqualified lookups)
-	// Collect all matches
-	var matches []string
-	for qualName := range tr.Types {
-		if strings.HasSuffix(qualName, "."+name) {
-			matches = append(matches, qualName)
-		}
-	}
-	for qualName := range tr.Enums {
-		if strings.HasSuffix(qualName, "."+name) {
-			matches = append(matches, qualName)
-		}
-	}
-	for qualName := range tr.Unions {
-		if strings.HasSuffix(qualName, "."+name) {
-			matches = append(matches, qualName)
-		}
-	}
 
-	// If exactly one match, return it
-	if len(matches) == 1 {
+	// Try all namespaces (for unqualified lookups); a name declared in
+	// several namespaces, or as several kinds, is ambiguous
+	if matches := tr.byName[name]; len(matches) == 1 {
 		return matches[0], true
 	}
-
-	// If multiple matches, it's ambiguous - return false
-	// If no matches, return false
 	return name, false
 }
 
@@ -356,9 +693,9 @@ func GetUnqualifiedName(qualifiedName string) string {
 
 // DeprecationInfo holds information about deprecated fields/types
 type DeprecationInfo struct {
-	Reason  string // Why it's deprecated and what to use instead
-	Since   string // Version when it was deprecated (e.g., "2.0.0")
-	Removed string // Version when it will be removed (optional, e.g., "3.0.0")
+	Reason  string `json:"reason,omitempty"`  // Why it's deprecated and what to use instead
+	Since   string `json:"since,omitempty"`   // Version when it was deprecated (e.g., "2.0.0")
+	Removed string `json:"removed,omitempty"` // Version when it will be removed (optional, e.g., "3.0.0")
 }
 
 // ValidationRules holds validation constraints for a field
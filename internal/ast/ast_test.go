@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -71,6 +72,39 @@ func TestEnum(t *testing.T) {
 	}
 }
 
+func TestEnum_ValueNumbers(t *testing.T) {
+	values := []*EnumValue{
+		{Name: "A"},
+		{Name: "B"},
+		{Name: "C", Number: 8, HasNumber: true},
+		{Name: "D"},
+		{Name: "E", Number: 0, HasNumber: true},
+		{Name: "F"},
+	}
+
+	tests := []struct {
+		flags    bool
+		expected []int
+	}{
+		{false, []int{1, 2, 8, 9, 0, 10}},
+		{true, []int{1, 2, 8, 16, 0, 32}},
+	}
+	for _, tt := range tests {
+		enum := &Enum{Name: "Bits", Values: values, Flags: tt.flags}
+		if got := enum.ValueNumbers(); fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+			t.Errorf("Flags %v: expected %v, got %v", tt.flags, tt.expected, got)
+		}
+	}
+}
+
+func TestValidFlag(t *testing.T) {
+	for number, valid := range map[int]bool{0: true, 1: true, 64: true, MaxFlag: true, 3: false, -2: false, MaxFlag * 2: false} {
+		if got := ValidFlag(number); got != valid {
+			t.Errorf("ValidFlag(%d) = %v, want %v", number, got, valid)
+		}
+	}
+}
+
 func TestType(t *testing.T) {
 	typ := &Type{
 		Name: "User",
@@ -192,6 +226,33 @@ func TestFieldType(t *testing.T) {
 	})
 }
 
+func TestFieldType_ElementTypeAndString(t *testing.T) {
+	item := &FieldType{Name: "Item"}
+	tests := []struct {
+		fieldType *FieldType
+		want      string
+		element   string
+	}{
+		{&FieldType{Name: "string", IsBuiltin: true, IsArray: true, NonNullItems: true}, "[]string!", "string"},
+		{&FieldType{Name: "[]string", IsBuiltin: true, IsArray: true}, "[][]string", "[]string"},
+		{&FieldType{Name: "map", IsArray: true, MapKey: "string", MapValueType: item}, "[]map<string, Item>", "map<string, Item>"},
+		{&FieldType{Name: "map", IsMap: true, MapKey: "string", MapValueType: &FieldType{Name: "Item", IsArray: true}}, "map<string, []Item>", ""},
+		{&FieldType{Name: "map", IsMap: true, MapKey: "string", MapValue: "int32"}, "map<string, int32>", ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.fieldType.String(); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+		element := tt.fieldType.ElementType()
+		if (element == nil) != (tt.element == "") {
+			t.Errorf("%s: unexpected element type %v", tt.want, element)
+		} else if element != nil && element.String() != tt.element {
+			t.Errorf("%s: expected element type %s, got %s", tt.want, tt.element, element)
+		}
+	}
+}
+
 func TestService(t *testing.T) {
 	service := &Service{
 		Name: "UserService",
@@ -318,6 +379,53 @@ func TestDocumentation_GetDoc(t *testing.T) {
 	}
 }
 
+func TestDocumentation_GetLocaleDoc(t *testing.T) {
+	doc := &Documentation{
+		General: "A user",
+		Locales: map[string]string{"es": "Un usuario", "pt-br": "Um usuário"},
+	}
+
+	tests := []struct {
+		locale   string
+		expected string
+	}{
+		{"", "A user"},
+		{"es", "Un usuario"},
+		{"es-MX", "Un usuario"},
+		{"pt_BR", "Um usuário"},
+		{"pt", "A user"},
+		{"fr", "A user"},
+	}
+
+	for _, tt := range tests {
+		if result := doc.GetLocaleDoc(tt.locale); result != tt.expected {
+			t.Errorf("GetLocaleDoc(%q) = %q, want %q", tt.locale, result, tt.expected)
+		}
+	}
+	if result := (*Documentation)(nil).GetLocaleDoc("es"); result != "" {
+		t.Errorf("GetLocaleDoc on nil documentation = %q, want empty", result)
+	}
+}
+
+func TestDocumentation_GetLongDoc(t *testing.T) {
+	tests := []struct {
+		doc      *Documentation
+		expected string
+	}{
+		{nil, ""},
+		{&Documentation{General: "A user"}, "A user"},
+		{&Documentation{General: "A user", Extended: "# User\n\nDetails.\n"}, "A user\n\n# User\n\nDetails."},
+		{&Documentation{Extended: "Details."}, "Details."},
+		{&Documentation{General: "A user", Specific: map[string]string{"openapi": "A user resource"}, Extended: "Details."}, "A user resource\n\nDetails."},
+	}
+
+	for _, tt := range tests {
+		if result := tt.doc.GetLongDoc("openapi"); result != tt.expected {
+			t.Errorf("GetLongDoc(%+v) = %q, want %q", tt.doc, result, tt.expected)
+		}
+	}
+}
+
 func TestField_ShouldIncludeInGenerator(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -706,6 +814,38 @@ func TestTypeRegistry_ResolveType(t *testing.T) {
 	}
 }
 
+func TestTypeRegistry_ResolveTypeAmbiguous(t *testing.T) {
+	registry := NewTypeRegistry()
+	registry.RegisterType(&Type{Name: "Status", Namespace: "com.example.users"})
+	registry.RegisterEnum(&Enum{Name: "Status", Namespace: "com.example.orders"})
+
+	if resolved, found := registry.ResolveType("Status", "com.example.billing"); found {
+		t.Errorf("Expected Status to be ambiguous, got %q", resolved)
+	}
+
+	// The current namespace wins over other namespaces
+	if resolved, found := registry.ResolveType("Status", "com.example.orders"); !found || resolved != "com.example.orders.Status" {
+		t.Errorf("Expected com.example.orders.Status, got %q (found=%v)", resolved, found)
+	}
+}
+
+func TestTypeRegistry_RegisterTwice(t *testing.T) {
+	registry := NewTypeRegistry()
+	first := &Type{Name: "User", Namespace: "com.example"}
+	second := &Type{Name: "User", Namespace: "com.example"}
+
+	// A declaration registered again, e.g. imported through two paths, stays unambiguous
+	registry.RegisterType(first)
+	registry.RegisterType(second)
+
+	if registry.Types["com.example.User"] != second {
+		t.Error("Expected the last registration to replace the first")
+	}
+	if resolved, found := registry.ResolveType("User", "com.example.other"); !found || resolved != "com.example.User" {
+		t.Errorf("Expected com.example.User, got %q (found=%v)", resolved, found)
+	}
+}
+
 func TestGetUnqualifiedName(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -743,3 +883,67 @@ func TestGetUnqualifiedName(t *testing.T) {
 		})
 	}
 }
+
+func TestSchema_HygieneWarnings(t *testing.T) {
+	schema := &Schema{
+		Types: []*Type{
+			{
+				Name: "User",
+				Fields: []*Field{
+					{Name: "id", Number: 1, HasNumber: true},
+					{Name: "email"},
+					{Name: "password", ExcludeFrom: []string{"proto"}},
+				},
+			},
+		},
+		Services: []*Service{
+			{
+				Name: "UserService",
+				Methods: []*Method{
+					{Name: "GetUser"},
+					{Name: "DeleteUser", HTTPMethod: "DELETE"},
+				},
+			},
+		},
+	}
+
+	warnings := schema.HygieneWarnings()
+	expected := []string{
+		"field User.email has no field number",
+		"method UserService.GetUser has no @http method, GET is inferred from its name",
+	}
+	if len(warnings) != len(expected) {
+		t.Fatalf("Expected %d warnings, got %d: %v", len(expected), len(warnings), warnings)
+	}
+	for i, want := range expected {
+		if warnings[i] != want {
+			t.Errorf("Warning %d: expected %q, got %q", i, want, warnings[i])
+		}
+	}
+}
+
+func TestField_Presence(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    *Field
+		expected Presence
+		str      string
+	}{
+		{"implicit", &Field{Type: &FieldType{Name: "string"}}, PresenceImplicit, "implicit"},
+		{"required", &Field{Required: true, Type: &FieldType{Name: "string"}}, PresenceRequired, "required!"},
+		{"optional", &Field{Type: &FieldType{Name: "string", Optional: true}}, PresenceOptional, "optional?"},
+		{"optional wins over required", &Field{Required: true, Type: &FieldType{Name: "string", Optional: true}}, PresenceOptional, "optional?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			presence := tt.field.Presence()
+			if presence != tt.expected {
+				t.Errorf("Expected presence %v, got %v", tt.expected, presence)
+			}
+			if presence.String() != tt.str {
+				t.Errorf("Expected %q, got %q", tt.str, presence.String())
+			}
+		})
+	}
+}
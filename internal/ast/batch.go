@@ -0,0 +1,115 @@
+package ast
+
+import "fmt"
+
+// BatchFailureTypeName is the name of the type shared by the batch methods of a
+// namespace to describe a call that failed
+const BatchFailureTypeName = "BatchFailure"
+
+// BatchMethodName returns the name of the method that makes many calls of a
+// method at once: BatchCreateUser for CreateUser
+func BatchMethodName(methodName string) string {
+	return "Batch" + methodName
+}
+
+// ResolveBatches derives a method for every method marked @batch that makes many
+// calls of it at once. BatchCreateUser takes a BatchCreateUserRequest holding the
+// requests of CreateUser as requests, and responds with a BatchCreateUserResponse
+// holding the responses to the calls that succeeded as responses, in order, and a
+// BatchFailure for each call that failed as failures: the index of its request
+// and why it failed. A batch method is served with POST at the path of the method
+// followed by :batch, or at the default path when that path has parameters. It is
+// idempotent and long-running when the method is. It can be called again after
+// the schema changes: previously derived types and methods are replaced.
+func (s *Schema) ResolveBatches() error {
+	deriver := newTypeDeriver(s, "batch type", func(typ *Type) bool { return typ.Batch })
+
+	for _, service := range s.Services {
+		var methods []*Method
+		names := make(map[string]bool)
+		for _, method := range service.Methods {
+			if method.Batches == "" {
+				methods = append(methods, method)
+				names[method.Name] = true
+			}
+		}
+
+		var resolved []*Method
+		for _, method := range methods {
+			resolved = append(resolved, method)
+			if !method.Batch {
+				continue
+			}
+			name := service.Name + "." + method.Name
+			batchName := BatchMethodName(method.Name)
+			switch {
+			case !method.HasInput():
+				return fmt.Errorf("method %s is batched, but takes no input", name)
+			case method.InputStream || method.OutputStream:
+				return fmt.Errorf("method %s is batched and cannot stream", name)
+			case method.IsWebhook():
+				return fmt.Errorf("method %s is batched and cannot be a webhook", name)
+			case names[batchName]:
+				return fmt.Errorf("method %s is batched, but its batch method %s is declared already", name, batchName)
+			}
+
+			failureType := &Type{
+				Name:      BatchFailureTypeName,
+				Namespace: service.Namespace,
+				Doc:       &Documentation{General: "Why a call of a batch failed."},
+				Fields: []*Field{
+					derivedField("index", "int32", 1, "Position of the request of the call in the batch"),
+					derivedField("code", "int32", 2, "Error code"),
+					derivedField("message", "string", 3, "Error message"),
+				},
+			}
+			failureType.Fields[0].Required = true
+			requestType := &Type{
+				Name:      batchName + "Request",
+				Namespace: service.Namespace,
+				Doc:       &Documentation{General: fmt.Sprintf("Many %s calls made at once.", method.Name)},
+				Fields:    []*Field{derivedField("requests", method.InputType, 1, "Requests of the calls, in order")},
+			}
+			requestType.Fields[0].Type.IsArray = true
+			responseType := &Type{
+				Name:      batchName + "Response",
+				Namespace: service.Namespace,
+				Doc:       &Documentation{General: fmt.Sprintf("The outcome of many %s calls made at once.", method.Name)},
+			}
+			if response := method.responseType(); response != "" {
+				responseType.Fields = append(responseType.Fields, derivedField("responses", response, 1, "Responses to the calls that succeeded, in order"))
+			}
+			responseType.Fields = append(responseType.Fields, derivedField("failures", BatchFailureTypeName, 2, "Calls that failed"))
+			for _, field := range responseType.Fields {
+				field.Type.IsArray = true
+			}
+			for _, typ := range []*Type{failureType, requestType, responseType} {
+				typ.Batch = true
+				if err := deriver.add(typ, typ == failureType); err != nil {
+					return fmt.Errorf("method %s: %w", name, err)
+				}
+			}
+
+			batch := &Method{
+				Name:        batchName,
+				InputType:   requestType.Name,
+				OutputType:  responseType.Name,
+				Doc:         &Documentation{General: fmt.Sprintf("Makes many %s calls at once.", method.Name)},
+				HTTPMethod:  "POST",
+				GraphQLType: method.GetGraphQLType(),
+				Idempotent:  method.Idempotent,
+				Auth:        method.Auth,
+				Batches:     method.Name,
+			}
+			if len(PathParameters(method.HTTPPath(service))) == 0 {
+				batch.PathTemplate = method.RelativeHTTPPath(service) + ":batch"
+			}
+			if method.LongRunning != nil {
+				batch.LongRunning = &LongRunning{}
+			}
+			resolved = append(resolved, batch)
+		}
+		service.Methods = resolved
+	}
+	return nil
+}
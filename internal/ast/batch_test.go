@@ -0,0 +1,143 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+// batchSchema returns a schema where CreateUser and DeleteUser are batched
+func batchSchema() *Schema {
+	return &Schema{
+		Namespace: "users",
+		Types: []*Type{
+			{Name: "User", Namespace: "users"},
+			{Name: "CreateUserRequest", Namespace: "users"},
+		},
+		Services: []*Service{
+			{
+				Name:      "UserService",
+				Namespace: "users",
+				Methods: []*Method{
+					{Name: "CreateUser", InputType: "CreateUserRequest", OutputType: "User", PathTemplate: "/v1/users", Idempotent: true, Batch: true},
+					{Name: "DeleteUser", InputType: "CreateUserRequest", PathTemplate: "/v1/users/{id}", HTTPMethod: "DELETE", Batch: true},
+				},
+			},
+		},
+	}
+}
+
+func TestSchema_ResolveBatches(t *testing.T) {
+	schema := batchSchema()
+	for i := 0; i < 2; i++ {
+		// Resolving again replaces the derived types and methods
+		if err := schema.ResolveBatches(); err != nil {
+			t.Fatalf("ResolveBatches failed: %v", err)
+		}
+
+		var types, methods []string
+		for _, typ := range schema.Types {
+			types = append(types, typ.Name)
+		}
+		for _, method := range schema.Services[0].Methods {
+			methods = append(methods, method.Name)
+		}
+		if got := strings.Join(types, ","); got != "User,CreateUserRequest,BatchFailure,BatchCreateUserRequest,BatchCreateUserResponse,BatchDeleteUserRequest,BatchDeleteUserResponse" {
+			t.Fatalf("Unexpected types %s", got)
+		}
+		if got := strings.Join(methods, ","); got != "CreateUser,BatchCreateUser,DeleteUser,BatchDeleteUser" {
+			t.Fatalf("Unexpected methods %s", got)
+		}
+	}
+
+	request, response := schema.Types[3], schema.Types[4]
+	if field := request.Fields[0]; field.Name != "requests" || field.Type.Name != "CreateUserRequest" || !field.Type.IsArray {
+		t.Errorf("Unexpected request field %+v", field)
+	}
+	if got := fieldNames(response); got != "responses,failures" || response.Fields[0].Type.Name != "User" || !response.Fields[1].Type.IsArray {
+		t.Errorf("Unexpected response fields %s", got)
+	}
+	if got := fieldNames(schema.Types[6]); got != "failures" {
+		t.Errorf("Expected a method without output to report only failures, got %s", got)
+	}
+
+	methods := schema.Services[0].Methods
+	if batch := methods[1]; batch.Batches != "CreateUser" || batch.GetHTTPMethod() != "post" || batch.PathTemplate != "/v1/users:batch" || !batch.Idempotent {
+		t.Errorf("Unexpected batch method %+v", batch)
+	}
+	if batch := methods[3]; batch.PathTemplate != "" || batch.Idempotent {
+		t.Errorf("Expected the default path for a path with parameters, got %+v", batch)
+	}
+}
+
+func TestSchema_ResolveBatchesLongRunning(t *testing.T) {
+	schema := batchSchema()
+	schema.Services[0].Methods[0].LongRunning = &LongRunning{}
+	for i := 0; i < 2; i++ {
+		if err := schema.ResolveBatches(); err != nil {
+			t.Fatalf("ResolveBatches failed: %v", err)
+		}
+		if err := schema.ResolveLongRunning(); err != nil {
+			t.Fatalf("ResolveLongRunning failed: %v", err)
+		}
+	}
+
+	var methods []string
+	for _, method := range schema.Services[0].Methods {
+		methods = append(methods, method.Name+":"+method.OutputType)
+	}
+	want := "CreateUser:CreateUserOperation,GetCreateUserOperation:CreateUserOperation," +
+		"BatchCreateUser:BatchCreateUserOperation,GetBatchCreateUserOperation:BatchCreateUserOperation," +
+		"DeleteUser:,BatchDeleteUser:BatchDeleteUserResponse"
+	if got := strings.Join(methods, ","); got != want {
+		t.Fatalf("Unexpected methods %s", got)
+	}
+	for _, typ := range schema.Types {
+		if typ.Name == "BatchCreateUserResponse" && typ.Fields[0].Type.Name != "User" {
+			t.Errorf("Expected batch responses to hold the response of CreateUser, got %s", typ.Fields[0].Type.Name)
+		}
+	}
+}
+
+func TestSchema_ResolveBatchesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *Schema)
+		want   string
+	}{
+		{
+			name:   "no input",
+			modify: func(s *Schema) { s.Services[0].Methods[0].InputType = "" },
+			want:   "method UserService.CreateUser is batched, but takes no input",
+		},
+		{
+			name:   "streaming",
+			modify: func(s *Schema) { s.Services[0].Methods[0].InputStream = true },
+			want:   "method UserService.CreateUser is batched and cannot stream",
+		},
+		{
+			name: "batch method declared",
+			modify: func(s *Schema) {
+				s.Services[0].Methods = append(s.Services[0].Methods, &Method{Name: "BatchCreateUser"})
+			},
+			want: "its batch method BatchCreateUser is declared already",
+		},
+		{
+			name: "type collision",
+			modify: func(s *Schema) {
+				s.Types = append(s.Types, &Type{Name: "BatchCreateUserResponse", Namespace: "users"})
+			},
+			want: "method UserService.CreateUser: batch type BatchCreateUserResponse collides with another type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := batchSchema()
+			tt.modify(schema)
+			err := schema.ResolveBatches()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
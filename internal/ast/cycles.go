@@ -0,0 +1,65 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RequiredCycles returns the cycles of required fields of a schema. Types may
+// refer to themselves, directly as in Tree { children: []Tree } or through other
+// types, as long as an optional field, list, or map ends the chain: a cycle in
+// which every field is @required, such as Employee.department and Department.head,
+// has no finite value, and GraphQL rejects it in input types.
+func (s *Schema) RequiredCycles() []string {
+	registry := NewTypeRegistry()
+	for _, typ := range s.Types {
+		registry.RegisterType(typ)
+	}
+
+	// requires returns the type a field requires a value of, or nil
+	requires := func(typ *Type, field *Field) *Type {
+		ft := field.Type
+		if !field.Required || ft == nil || ft.IsArray || ft.IsMap || ft.Optional || ft.IsBuiltin {
+			return nil
+		}
+		qualifiedName, ok := registry.ResolveType(ft.Name, typ.Namespace)
+		if !ok {
+			return nil
+		}
+		return registry.Types[qualifiedName]
+	}
+
+	order := make(map[*Type]int, len(s.Types))
+	for i, typ := range s.Types {
+		order[typ] = i
+	}
+
+	// Each cycle is reported once, from its first type in declaration order
+	var cycles []string
+	for _, start := range s.Types {
+		visited := make(map[*Type]bool)
+		var path []string
+		var walk func(typ *Type) bool
+		walk = func(typ *Type) bool {
+			visited[typ] = true
+			for _, field := range typ.Fields {
+				next := requires(typ, field)
+				if next == nil || order[next] < order[start] {
+					continue
+				}
+				path = append(path, typ.Name+"."+field.Name)
+				if next == start {
+					cycles = append(cycles, fmt.Sprintf("required fields %s form a cycle that no finite value satisfies; make one of them optional", strings.Join(path, " -> ")))
+					return true
+				}
+				if !visited[next] && walk(next) {
+					return true
+				}
+				path = path[:len(path)-1]
+			}
+			return false
+		}
+		walk(start)
+	}
+	return cycles
+}
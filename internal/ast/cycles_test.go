@@ -0,0 +1,43 @@
+package ast
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchema_RequiredCycles(t *testing.T) {
+	field := func(name, typeName string, required bool) *Field {
+		return &Field{Name: name, Type: &FieldType{Name: typeName}, Required: required}
+	}
+	schema := &Schema{Types: []*Type{
+		{Name: "Tree", Fields: []*Field{
+			{Name: "children", Type: &FieldType{Name: "Tree", IsArray: true}, Required: true},
+			field("parent", "Tree", false),
+		}},
+		{Name: "Employee", Fields: []*Field{
+			field("manager", "Employee", false),
+			field("department", "Department", true),
+		}},
+		{Name: "Department", Fields: []*Field{
+			field("head", "Employee", true),
+		}},
+		{Name: "Node", Fields: []*Field{
+			field("next", "Node", true),
+		}},
+	}}
+
+	want := []string{
+		"required fields Employee.department -> Department.head form a cycle that no finite value satisfies; make one of them optional",
+		"required fields Node.next form a cycle that no finite value satisfies; make one of them optional",
+	}
+	if got := schema.RequiredCycles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RequiredCycles() =\n%q\nwant\n%q", got, want)
+	}
+
+	// Optional fields, lists, and maps end the recursion
+	schema.Types = schema.Types[:2]
+	schema.Types[1].Fields[1].Required = false
+	if cycles := schema.RequiredCycles(); len(cycles) != 0 {
+		t.Errorf("Expected no cycles, got %q", cycles)
+	}
+}
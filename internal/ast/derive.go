@@ -0,0 +1,64 @@
+package ast
+
+import "fmt"
+
+// typeDeriver adds the types derived for annotated methods to a schema
+type typeDeriver struct {
+	schema   *Schema
+	kind     string // What the derived types are, such as "batch type", for errors
+	declared *TypeRegistry
+	derived  map[string]bool // Qualified names of the types derived so far
+}
+
+// newTypeDeriver drops the types that a previous run derived, those isDerived
+// holds for, and returns a deriver of new ones
+func newTypeDeriver(s *Schema, kind string, isDerived func(*Type) bool) *typeDeriver {
+	var types []*Type
+	for _, typ := range s.Types {
+		if !isDerived(typ) {
+			types = append(types, typ)
+		}
+	}
+	s.Types = types
+
+	declared := NewTypeRegistry()
+	for _, typ := range s.Types {
+		declared.RegisterType(typ)
+	}
+	for _, enum := range s.Enums {
+		declared.RegisterEnum(enum)
+	}
+	for _, union := range s.Unions {
+		declared.RegisterUnion(union)
+	}
+	return &typeDeriver{schema: s, kind: kind, declared: declared, derived: make(map[string]bool)}
+}
+
+// add appends a derived type to the schema unless its namespace declares one of
+// that name; a shared type is added once per namespace
+func (d *typeDeriver) add(typ *Type, shared bool) error {
+	qualifiedName := typ.Namespace + "." + typ.Name
+	if d.derived[qualifiedName] && shared {
+		return nil
+	}
+	if d.declared.has(qualifiedName) || d.derived[qualifiedName] {
+		return fmt.Errorf("%s %s collides with another type", d.kind, typ.Name)
+	}
+	d.derived[qualifiedName] = true
+	d.schema.Types = append(d.schema.Types, typ)
+	if d.schema.TypeRegistry != nil {
+		d.schema.TypeRegistry.RegisterType(typ)
+	}
+	return nil
+}
+
+// derivedField returns a documented field of a derived type with a fixed number
+func derivedField(name, typeName string, number int, doc string) *Field {
+	return &Field{
+		Name:      name,
+		Type:      &FieldType{Name: typeName, IsBuiltin: IsBuiltinType(typeName)},
+		Number:    number,
+		HasNumber: true,
+		Doc:       &Documentation{General: doc},
+	}
+}
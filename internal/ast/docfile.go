@@ -0,0 +1,62 @@
+package ast
+
+import "strings"
+
+// GetLongDoc returns the documentation for a specific language followed by the
+// extended documentation read from its @doc-file, separated by a blank line.
+func (d *Documentation) GetLongDoc(lang string) string {
+	if d == nil {
+		return ""
+	}
+	return joinDocs(d.GetDoc(lang), d.Extended)
+}
+
+// joinDocs appends extended documentation to a documentation text, if any
+func joinDocs(doc, extended string) string {
+	extended = strings.TrimSpace(extended)
+	if extended == "" {
+		return doc
+	}
+	if doc == "" {
+		return extended
+	}
+	return doc + "\n\n" + extended
+}
+
+// Docs returns the documentation of every element of the schema that has
+// some: types and their fields and field arguments, enums and their values,
+// unions, services, and methods.
+func (s *Schema) Docs() []*Documentation {
+	var docs []*Documentation
+	add := func(doc *Documentation) {
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+	}
+
+	for _, typ := range s.Types {
+		add(typ.Doc)
+		for _, field := range typ.Fields {
+			add(field.Doc)
+			for _, arg := range field.Arguments {
+				add(arg.Doc)
+			}
+		}
+	}
+	for _, enum := range s.Enums {
+		add(enum.Doc)
+		for _, value := range enum.Values {
+			add(value.Doc)
+		}
+	}
+	for _, union := range s.Unions {
+		add(union.Doc)
+	}
+	for _, service := range s.Services {
+		add(service.Doc)
+		for _, method := range service.Methods {
+			add(method.Doc)
+		}
+	}
+	return docs
+}
@@ -0,0 +1,31 @@
+package ast
+
+import "encoding/json"
+
+// Example is a sample call of a method, declared with
+// @openapi.example("created", request={...}, response={...}). Its request and
+// response are JSON values.
+type Example struct {
+	Name     string          `json:"name"`
+	Summary  string          `json:"summary,omitempty"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// CodeSample shows how to call a method in a language, declared with
+// @openapi.code_sample(lang="curl", source="curl ...").
+type CodeSample struct {
+	Lang   string `json:"lang"`
+	Label  string `json:"label,omitempty"` // Shown instead of the language when set
+	Source string `json:"source"`
+}
+
+// Example returns the example of a method with a name, or nil.
+func (m *Method) Example(name string) *Example {
+	for _, example := range m.Examples {
+		if example.Name == name {
+			return example
+		}
+	}
+	return nil
+}
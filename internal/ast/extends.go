@@ -0,0 +1,152 @@
+package ast
+
+import (
+	"fmt"
+)
+
+// ResolveExtends copies the fields of base types into every type that extends them.
+// Inherited fields come first, in the order of the extends list, and keep the field
+// numbers of their declaring type; Extends entries are rewritten to qualified names.
+// It can be called again after the schema changes: previously inherited fields are replaced.
+func (s *Schema) ResolveExtends() error {
+	registry := NewTypeRegistry()
+	for _, typ := range s.Types {
+		registry.RegisterType(typ)
+	}
+
+	// Drop the fields copied by a previous resolution
+	for _, typ := range s.Types {
+		own := typ.Fields[:0:0]
+		for _, field := range typ.Fields {
+			if field.InheritedFrom == "" {
+				own = append(own, field)
+			}
+		}
+		typ.Fields = own
+	}
+
+	bases := make(map[*Type][]*Type)
+	for _, typ := range s.Types {
+		for i, name := range typ.Extends {
+			qualifiedName, _ := registry.ResolveType(name, typ.Namespace)
+			base, ok := registry.Types[qualifiedName]
+			if !ok {
+				return fmt.Errorf("type %s extends unknown type %s", typ.Name, name)
+			}
+			typ.Extends[i] = qualifiedName
+			bases[typ] = append(bases[typ], base)
+		}
+	}
+
+	const (
+		visiting = 1
+		resolved = 2
+	)
+	state := make(map[*Type]int)
+	var resolve func(typ *Type) error
+	resolve = func(typ *Type) error {
+		switch state[typ] {
+		case visiting:
+			return fmt.Errorf("circular extends involving type %s", typ.Name)
+		case resolved:
+			return nil
+		}
+		state[typ] = visiting
+
+		var inherited []*Field
+		origins := make(map[string]string) // Field name -> declaring type
+		numbers := make(map[int]*Field)    // Explicit field number -> field
+		for _, base := range bases[typ] {
+			if err := resolve(base); err != nil {
+				return err
+			}
+			for _, field := range base.Fields {
+				origin := field.InheritedFrom
+				if origin == "" {
+					origin = base.Namespace + "." + base.Name
+				}
+				if previous, ok := origins[field.Name]; ok {
+					return fmt.Errorf("type %s inherits field %s from both %s and %s", typ.Name, field.Name, previous, origin)
+				}
+				if err := checkFieldNumber(typ, field, numbers); err != nil {
+					return err
+				}
+				origins[field.Name] = origin
+				copied := *field
+				copied.InheritedFrom = origin
+				inherited = append(inherited, &copied)
+			}
+		}
+
+		for _, field := range typ.Fields {
+			if origin, ok := origins[field.Name]; ok {
+				return fmt.Errorf("field %s.%s redeclares the field inherited from %s", typ.Name, field.Name, origin)
+			}
+			if len(inherited) == 0 {
+				continue
+			}
+			if err := checkFieldNumber(typ, field, numbers); err != nil {
+				return err
+			}
+		}
+
+		if len(inherited) > 0 {
+			typ.Fields = append(inherited, typ.Fields...)
+		}
+		state[typ] = resolved
+		return nil
+	}
+
+	for _, typ := range s.Types {
+		if err := resolve(typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFieldNumber reports an explicit field number of a type that is already taken
+// by another inherited or declared field, and records it otherwise
+func checkFieldNumber(typ *Type, field *Field, numbers map[int]*Field) error {
+	if !field.HasNumber {
+		return nil
+	}
+	if other, ok := numbers[field.Number]; ok {
+		return fmt.Errorf("field %s.%s reuses field number %d of field %s", typ.Name, field.Name, field.Number, other.Name)
+	}
+	numbers[field.Number] = field
+	return nil
+}
+
+// Bases returns the types a type directly extends, in declaration order.
+// Extends entries must have been resolved with ResolveExtends.
+func (s *Schema) Bases(typ *Type) []*Type {
+	var bases []*Type
+	for _, name := range typ.Extends {
+		for _, candidate := range s.Types {
+			if candidate.Namespace+"."+candidate.Name == name {
+				bases = append(bases, candidate)
+				break
+			}
+		}
+	}
+	return bases
+}
+
+// Ancestors returns every type a type extends directly or indirectly, nearest first.
+func (s *Schema) Ancestors(typ *Type) []*Type {
+	var ancestors []*Type
+	seen := map[*Type]bool{typ: true}
+	queue := []*Type{typ}
+	for len(queue) > 0 {
+		for _, base := range s.Bases(queue[0]) {
+			if !seen[base] {
+				seen[base] = true
+				ancestors = append(ancestors, base)
+				queue = append(queue, base)
+			}
+		}
+		queue = queue[1:]
+	}
+	return ancestors
+}
@@ -0,0 +1,187 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+// extendsSchema returns a schema where Admin extends User and Auditable
+func extendsSchema() *Schema {
+	return &Schema{
+		Namespace: "com.example",
+		Types: []*Type{
+			{
+				Name:      "Admin",
+				Namespace: "com.example",
+				Extends:   []string{"User", "audit.Auditable"},
+				Fields: []*Field{
+					{Name: "role", Type: &FieldType{Name: "string"}},
+				},
+			},
+			{
+				Name:      "User",
+				Namespace: "com.example",
+				Fields: []*Field{
+					{Name: "id", Type: &FieldType{Name: "string"}, Number: 1, HasNumber: true},
+					{Name: "email", Type: &FieldType{Name: "string"}, Number: 2, HasNumber: true},
+				},
+			},
+			{
+				Name:      "Auditable",
+				Namespace: "audit",
+				Fields: []*Field{
+					{Name: "createdAt", Type: &FieldType{Name: "timestamp"}, Number: 10, HasNumber: true},
+				},
+			},
+		},
+	}
+}
+
+func fieldNames(typ *Type) string {
+	var names []string
+	for _, field := range typ.Fields {
+		names = append(names, field.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSchema_ResolveExtends(t *testing.T) {
+	schema := extendsSchema()
+	if err := schema.ResolveExtends(); err != nil {
+		t.Fatalf("ResolveExtends failed: %v", err)
+	}
+
+	admin := schema.Types[0]
+	if got := fieldNames(admin); got != "id,email,createdAt,role" {
+		t.Errorf("Expected inherited fields first, got %s", got)
+	}
+	if admin.Fields[0].InheritedFrom != "com.example.User" || admin.Fields[2].InheritedFrom != "audit.Auditable" {
+		t.Errorf("Unexpected declaring types: %s, %s", admin.Fields[0].InheritedFrom, admin.Fields[2].InheritedFrom)
+	}
+	if admin.Fields[3].InheritedFrom != "" {
+		t.Error("Expected declared field not to be marked as inherited")
+	}
+	if admin.Fields[2].Number != 10 {
+		t.Errorf("Expected inherited field to keep its number, got %d", admin.Fields[2].Number)
+	}
+	if strings.Join(admin.Extends, ",") != "com.example.User,audit.Auditable" {
+		t.Errorf("Expected qualified base names, got %v", admin.Extends)
+	}
+	if admin.Fields[0] == schema.Types[1].Fields[0] {
+		t.Error("Expected inherited fields to be copies")
+	}
+
+	// Changes to base types are picked up by resolving again
+	schema.Types[1].Fields[1].Required = true
+	if err := schema.ResolveExtends(); err != nil {
+		t.Fatalf("Second ResolveExtends failed: %v", err)
+	}
+	if got := fieldNames(admin); got != "id,email,createdAt,role" {
+		t.Errorf("Expected resolution to be idempotent, got %s", got)
+	}
+	if !admin.Fields[1].Required {
+		t.Error("Expected inherited field to reflect its updated declaration")
+	}
+
+	bases := schema.Bases(admin)
+	if len(bases) != 2 || bases[0].Name != "User" || bases[1].Name != "Auditable" {
+		t.Errorf("Unexpected bases: %v", bases)
+	}
+}
+
+func TestSchema_ResolveExtendsTransitive(t *testing.T) {
+	schema := extendsSchema()
+	schema.Types = append(schema.Types, &Type{
+		Name:      "SuperAdmin",
+		Namespace: "com.example",
+		Extends:   []string{"Admin"},
+		Fields:    []*Field{{Name: "scope", Type: &FieldType{Name: "string"}}},
+	})
+	if err := schema.ResolveExtends(); err != nil {
+		t.Fatalf("ResolveExtends failed: %v", err)
+	}
+
+	superAdmin := schema.Types[3]
+	if got := fieldNames(superAdmin); got != "id,email,createdAt,role,scope" {
+		t.Errorf("Expected fields of all ancestors, got %s", got)
+	}
+	if superAdmin.Fields[0].InheritedFrom != "com.example.User" || superAdmin.Fields[3].InheritedFrom != "com.example.Admin" {
+		t.Error("Expected inherited fields to name the type that declares them")
+	}
+
+	var ancestors []string
+	for _, ancestor := range schema.Ancestors(superAdmin) {
+		ancestors = append(ancestors, ancestor.Name)
+	}
+	if strings.Join(ancestors, ",") != "Admin,User,Auditable" {
+		t.Errorf("Expected ancestors nearest first, got %v", ancestors)
+	}
+}
+
+func TestSchema_ResolveExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *Schema)
+		want   string
+	}{
+		{
+			name:   "unknown base",
+			modify: func(s *Schema) { s.Types[0].Extends = []string{"Missing"} },
+			want:   "type Admin extends unknown type Missing",
+		},
+		{
+			name:   "circular",
+			modify: func(s *Schema) { s.Types[1].Extends = []string{"Admin"} },
+			want:   "circular extends",
+		},
+		{
+			name: "redeclared field",
+			modify: func(s *Schema) {
+				s.Types[0].Fields = append(s.Types[0].Fields, &Field{Name: "email", Type: &FieldType{Name: "string"}})
+			},
+			want: "field Admin.email redeclares the field inherited from com.example.User",
+		},
+		{
+			name: "field number collision",
+			modify: func(s *Schema) {
+				s.Types[0].Fields[0].Number = 2
+				s.Types[0].Fields[0].HasNumber = true
+			},
+			want: "field Admin.role reuses field number 2 of field email",
+		},
+		{
+			name: "field inherited twice",
+			modify: func(s *Schema) {
+				s.Types[2].Fields = append(s.Types[2].Fields, &Field{Name: "id", Type: &FieldType{Name: "string"}})
+			},
+			want: "type Admin inherits field id from both com.example.User and audit.Auditable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := extendsSchema()
+			tt.modify(schema)
+			err := schema.ResolveExtends()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSchema_HygieneWarningsSkipInheritedFields(t *testing.T) {
+	schema := extendsSchema()
+	schema.Types[1].Fields[0].HasNumber = false
+	if err := schema.ResolveExtends(); err != nil {
+		t.Fatalf("ResolveExtends failed: %v", err)
+	}
+
+	warnings := strings.Join(schema.HygieneWarnings(), "\n")
+	if !strings.Contains(warnings, "field User.id has no field number") {
+		t.Errorf("Expected warning on the declaring type, got:\n%s", warnings)
+	}
+	if strings.Contains(warnings, "field Admin.id") {
+		t.Errorf("Expected no warning on the inheriting type, got:\n%s", warnings)
+	}
+}
@@ -0,0 +1,128 @@
+package ast
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+// graphQLScalars are the built-in GraphQL scalars, which declarations cannot be named
+var graphQLScalars = map[string]bool{
+	"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true,
+}
+
+// graphQLLiterals are the GraphQL literals, which enum values cannot be named
+var graphQLLiterals = map[string]bool{
+	"true": true, "false": true, "null": true,
+}
+
+// protobufScalars are the Protobuf scalar types, which declarations cannot be named
+var protobufScalars = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true,
+	"uint64": true, "sint32": true, "sint64": true, "fixed32": true, "fixed64": true,
+	"sfixed32": true, "sfixed64": true, "bool": true, "string": true, "bytes": true,
+}
+
+// IdentifierErrors returns the names of a schema that break an output format:
+// declarations, fields, enum values, and methods that differ only in case, which
+// collide in generators that change the case of names and on case-insensitive
+// file systems, and names that are reserved words of a target language. Names
+// that a generator escapes safely, such as Go package names that are keywords
+// or Java fields that are keywords, are not reported.
+func (s *Schema) IdentifierErrors() []string {
+	var errs []string
+	report := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	// checkCase reports a name that differs only in case from an earlier name of
+	// the same scope. Names inherited by both fields are reported on their parent.
+	type named struct {
+		name, description string
+		inherited         bool
+	}
+	seen := make(map[string]named)
+	checkCase := func(scope, name, description string, inherited bool) {
+		key := scope + "\x00" + strings.ToLower(name)
+		other, ok := seen[key]
+		switch {
+		case !ok:
+			seen[key] = named{name, description, inherited}
+		case other.name != name && !(inherited && other.inherited):
+			report("%s differs only in case from %s", description, other.description)
+		}
+	}
+
+	// checkGraphQLName reports a name that GraphQL reserves for introspection
+	checkGraphQLName := func(name, description string) {
+		if strings.HasPrefix(name, "__") {
+			report("%s starts with __, which GraphQL reserves for introspection", description)
+		}
+	}
+
+	// checkDeclaration reports a declaration named like a keyword or built-in type
+	checkDeclaration := func(kind, namespace, name string) {
+		description := kind + " " + name
+		checkCase("declaration "+namespace, name, description, false)
+		checkGraphQLName(name, description)
+		switch {
+		case token.IsKeyword(name):
+			report("%s is a reserved word in Go", description)
+		case graphQLScalars[name]:
+			report("%s is a built-in scalar in GraphQL", description)
+		case protobufScalars[name]:
+			report("%s is a scalar type in Protobuf", description)
+		}
+	}
+	for _, enum := range s.Enums {
+		checkDeclaration("enum", enum.Namespace, enum.Name)
+	}
+	for _, typ := range s.Types {
+		checkDeclaration("type", typ.Namespace, typ.Name)
+	}
+	for _, union := range s.Unions {
+		checkDeclaration("union", union.Namespace, union.Name)
+	}
+
+	for _, enum := range s.Enums {
+		scope := "enum " + enum.Namespace + "." + enum.Name
+		for _, value := range enum.Values {
+			description := fmt.Sprintf("enum value %s.%s", enum.Name, value.Name)
+			checkCase(scope, value.Name, description, false)
+			checkGraphQLName(value.Name, description)
+			if graphQLLiterals[value.Name] {
+				report("%s is a reserved word in GraphQL", description)
+			}
+		}
+	}
+
+	for _, typ := range s.Types {
+		scope := "type " + typ.Namespace + "." + typ.Name
+		for _, field := range typ.Fields {
+			description := fmt.Sprintf("field %s.%s", typ.Name, field.Name)
+			inherited := field.InheritedFrom != ""
+			checkCase(scope, field.Name, description, inherited)
+			if inherited {
+				continue
+			}
+			checkGraphQLName(field.NameFor("graphql"), description)
+			if field.Annotations != nil && token.IsKeyword(field.Annotations.GoName) {
+				report("%s is named %s in Go, which is a reserved word", description, field.Annotations.GoName)
+			}
+			for _, arg := range field.Arguments {
+				checkGraphQLName(arg.Name, fmt.Sprintf("argument %s of %s", arg.Name, description))
+			}
+		}
+	}
+
+	for _, service := range s.Services {
+		scope := "service " + service.Namespace + "." + service.Name
+		for _, method := range service.Methods {
+			description := fmt.Sprintf("method %s.%s", service.Name, method.Name)
+			checkCase(scope, method.Name, description, false)
+			checkGraphQLName(method.Name, description)
+		}
+	}
+
+	return errs
+}
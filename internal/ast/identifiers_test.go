@@ -0,0 +1,60 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchema_IdentifierErrors(t *testing.T) {
+	schema := &Schema{
+		Namespace: "api",
+		Enums: []*Enum{
+			{Name: "Status", Namespace: "api", Values: []*EnumValue{{Name: "ACTIVE"}, {Name: "active"}, {Name: "null"}}},
+			{Name: "ID", Namespace: "api"},
+		},
+		Types: []*Type{
+			{Name: "User", Namespace: "api", Fields: []*Field{
+				{Name: "id", Type: &FieldType{Name: "string"}},
+				{Name: "Id", Type: &FieldType{Name: "string"}},
+				{Name: "kind", Type: &FieldType{Name: "string"}, Annotations: &FormatAnnotations{GraphQLName: "__kind", GoName: "type"}},
+				{Name: "posts", Type: &FieldType{Name: "string"}, Arguments: []*FieldArgument{{Name: "__after", Type: &FieldType{Name: "string"}}}},
+			}},
+			{Name: "Admin", Namespace: "api", Fields: []*Field{
+				{Name: "id", Type: &FieldType{Name: "string"}, InheritedFrom: "User"},
+				{Name: "Id", Type: &FieldType{Name: "string"}, InheritedFrom: "User"},
+				{Name: "ID", Type: &FieldType{Name: "string"}},
+			}},
+			{Name: "user", Namespace: "api"},
+			{Name: "User", Namespace: "other"},
+			{Name: "func", Namespace: "api"},
+			{Name: "bytes", Namespace: "api"},
+		},
+		Services: []*Service{
+			{Name: "UserService", Namespace: "api", Methods: []*Method{{Name: "GetUser"}, {Name: "getUser"}, {Name: "__schema"}}},
+		},
+	}
+
+	expected := []string{
+		"enum ID is a built-in scalar in GraphQL",
+		"type user differs only in case from type User",
+		"type func is a reserved word in Go",
+		"type bytes is a scalar type in Protobuf",
+		"enum value Status.active differs only in case from enum value Status.ACTIVE",
+		"enum value Status.null is a reserved word in GraphQL",
+		"field User.Id differs only in case from field User.id",
+		"field User.kind starts with __, which GraphQL reserves for introspection",
+		"field User.kind is named type in Go, which is a reserved word",
+		"argument __after of field User.posts starts with __, which GraphQL reserves for introspection",
+		"field Admin.ID differs only in case from field Admin.id",
+		"method UserService.getUser differs only in case from method UserService.GetUser",
+		"method UserService.__schema starts with __, which GraphQL reserves for introspection",
+	}
+	if errs := schema.IdentifierErrors(); strings.Join(errs, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected errors:\n%s\nwant:\n%s", strings.Join(errs, "\n"), strings.Join(expected, "\n"))
+	}
+
+	// Validate reports identifier errors with the other semantic errors
+	if errs := schema.Validate(); len(errs) < len(expected) {
+		t.Errorf("Expected Validate to report identifier errors, got %v", errs)
+	}
+}
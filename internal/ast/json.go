@@ -0,0 +1,68 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JSONFormatVersion is the version of the JSON interchange format written by MarshalSchemaJSON.
+// It changes only when existing keys change meaning or are removed; new keys may be added.
+const JSONFormatVersion = 1
+
+// schemaDocument is the top-level object of the JSON interchange format
+type schemaDocument struct {
+	FormatVersion int     `json:"formatVersion"`
+	Schema        *Schema `json:"schema"`
+}
+
+// MarshalSchemaJSON encodes a schema in the JSON interchange format read by
+// UnmarshalSchemaJSON, for external tools and generators.
+func MarshalSchemaJSON(schema *Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(schemaDocument{FormatVersion: JSONFormatVersion, Schema: schema}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// UnmarshalSchemaJSON decodes a schema written by MarshalSchemaJSON and rebuilds its type registry.
+func UnmarshalSchemaJSON(data []byte) (*Schema, error) {
+	var doc schemaDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	if doc.FormatVersion == 0 || doc.Schema == nil {
+		return nil, fmt.Errorf("invalid schema JSON: missing formatVersion or schema")
+	}
+	if doc.FormatVersion > JSONFormatVersion {
+		return nil, fmt.Errorf("unsupported schema JSON format version %d (supported: %d)", doc.FormatVersion, JSONFormatVersion)
+	}
+
+	schema := doc.Schema
+	schema.TypeRegistry = NewTypeRegistry()
+	for _, enum := range schema.Enums {
+		schema.TypeRegistry.RegisterEnum(enum)
+	}
+	for _, typ := range schema.Types {
+		schema.TypeRegistry.RegisterType(typ)
+	}
+	for _, union := range schema.Unions {
+		schema.TypeRegistry.RegisterUnion(union)
+	}
+	if err := schema.ResolveExtends(); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	if err := schema.ResolveViews(); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	if err := schema.ResolveResources(); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	if err := schema.ResolveBatches(); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	if err := schema.ResolveLongRunning(); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	return schema, nil
+}
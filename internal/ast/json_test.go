@@ -0,0 +1,82 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarshalSchemaJSON(t *testing.T) {
+	schema := &Schema{
+		Namespace: "com.example",
+		Types: []*Type{
+			{
+				Name:      "User",
+				Namespace: "com.example",
+				Fields: []*Field{
+					{Name: "id", Type: &FieldType{Name: "string", IsBuiltin: true}, Required: true},
+				},
+			},
+		},
+		TypeRegistry: NewTypeRegistry(),
+	}
+
+	data, err := MarshalSchemaJSON(schema)
+	if err != nil {
+		t.Fatalf("MarshalSchemaJSON failed: %v", err)
+	}
+
+	json := string(data)
+	for _, want := range []string{`"formatVersion": 1`, `"namespace": "com.example"`, `"required": true`, `"isBuiltin": true`} {
+		if !strings.Contains(json, want) {
+			t.Errorf("Expected JSON to contain %s, got:\n%s", want, json)
+		}
+	}
+	for _, unwanted := range []string{"TypeRegistry", "typeRegistry", `"isArray"`} {
+		if strings.Contains(json, unwanted) {
+			t.Errorf("Expected JSON not to contain %s", unwanted)
+		}
+	}
+}
+
+func TestUnmarshalSchemaJSON(t *testing.T) {
+	data := `{
+  "formatVersion": 1,
+  "schema": {
+    "namespace": "com.example",
+    "types": [{"name": "User", "namespace": "com.example", "fields": [{"name": "id", "type": {"name": "string"}}]}],
+    "enums": [{"name": "Status", "namespace": "com.example", "values": [{"name": "ACTIVE"}]}]
+  }
+}`
+
+	schema, err := UnmarshalSchemaJSON([]byte(data))
+	if err != nil {
+		t.Fatalf("UnmarshalSchemaJSON failed: %v", err)
+	}
+	if len(schema.Types) != 1 || schema.Types[0].Fields[0].Type.Name != "string" {
+		t.Fatalf("Unexpected types: %+v", schema.Types)
+	}
+	if _, ok := schema.TypeRegistry.ResolveType("Status", "com.example"); !ok {
+		t.Error("Expected the type registry to be rebuilt")
+	}
+}
+
+func TestUnmarshalSchemaJSON_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"not JSON", `schema`, "invalid schema JSON"},
+		{"missing version", `{"schema": {}}`, "missing formatVersion"},
+		{"newer version", `{"formatVersion": 99, "schema": {}}`, "unsupported schema JSON format version 99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UnmarshalSchemaJSON([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Names of the types shared by the long-running methods of a namespace
+const (
+	OperationErrorTypeName      = "OperationError"
+	GetOperationRequestTypeName = "GetOperationRequest"
+)
+
+// LongRunning marks a method that starts work it does not wait for: it responds
+// at once with an operation, which clients poll until it is done.
+type LongRunning struct {
+	Response string `json:"response,omitempty"` // Type the operation holds once it succeeds; empty for methods that return ()
+}
+
+// OperationTypeName returns the name of the operation type of a long-running
+// method: ImportUsersOperation for ImportUsers
+func OperationTypeName(methodName string) string {
+	return methodName + "Operation"
+}
+
+// PollMethodName returns the name of the method that polls the operations of a
+// long-running method: GetImportUsersOperation for ImportUsers
+func PollMethodName(methodName string) string {
+	return "Get" + methodName + "Operation"
+}
+
+// responseType returns the type a method responds with, which the operation of a
+// resolved long-running method holds
+func (m *Method) responseType() string {
+	if m.LongRunning != nil && m.OutputType == OperationTypeName(m.Name) {
+		return m.LongRunning.Response
+	}
+	return m.OutputType
+}
+
+// ResolveLongRunning turns the methods marked @longrunning into the long-running
+// operations of AIP-151. Such a method responds with an operation type derived
+// for it, holding the name of the operation, whether it is done, and then either
+// the response of the method or an error. A derived method polls an operation by
+// name, with GET at the path of the method followed by /operations/{name}. The
+// error and request types of the operations are shared by the namespace.
+// It can be called again after the schema changes: previously derived types and
+// methods are replaced.
+func (s *Schema) ResolveLongRunning() error {
+	deriver := newTypeDeriver(s, "long-running operation type", func(typ *Type) bool { return typ.LongRunning })
+	for _, service := range s.Services {
+		var methods []*Method
+		names := make(map[string]bool)
+		for _, method := range service.Methods {
+			if method.Polls == "" {
+				methods = append(methods, method)
+				names[method.Name] = true
+			}
+		}
+
+		var resolved []*Method
+		for _, method := range methods {
+			resolved = append(resolved, method)
+			if method.LongRunning == nil {
+				continue
+			}
+			name := service.Name + "." + method.Name
+			switch {
+			case method.InputStream || method.OutputStream:
+				return fmt.Errorf("method %s is long-running and cannot stream", name)
+			case method.IsWebhook():
+				return fmt.Errorf("method %s is long-running and cannot be a webhook", name)
+			case names[PollMethodName(method.Name)]:
+				return fmt.Errorf("method %s is long-running, but its polling method %s is declared already", name, PollMethodName(method.Name))
+			}
+
+			method.LongRunning.Response = method.responseType()
+
+			errorType := &Type{
+				Name:      OperationErrorTypeName,
+				Namespace: service.Namespace,
+				Doc:       &Documentation{General: "Why a long-running operation failed."},
+				Fields: []*Field{
+					derivedField("code", "int32", 1, "Error code"),
+					derivedField("message", "string", 2, "Error message"),
+				},
+			}
+			requestType := &Type{
+				Name:      GetOperationRequestTypeName,
+				Namespace: service.Namespace,
+				Doc:       &Documentation{General: "Names the long-running operation to poll."},
+				Fields:    []*Field{derivedField("name", "string", 1, "Name of the operation")},
+			}
+			operationType := &Type{
+				Name:      OperationTypeName(method.Name),
+				Namespace: service.Namespace,
+				Doc:       &Documentation{General: fmt.Sprintf("A long-running %s operation.", method.Name)},
+				Fields: []*Field{
+					derivedField("name", "string", 1, "Name of the operation, to poll it with"),
+					derivedField("done", "bool", 2, "Whether the operation has finished, with a response or an error"),
+				},
+			}
+			requestType.Fields[0].Required = true
+			operationType.Fields[0].Required = true
+			operationType.Fields[1].Required = true
+			if method.LongRunning.Response != "" {
+				operationType.Fields = append(operationType.Fields, derivedField("response", method.LongRunning.Response, 3, "Result of the operation, once it succeeds"))
+			}
+			operationType.Fields = append(operationType.Fields, derivedField("error", OperationErrorTypeName, 4, "Why the operation failed, once it fails"))
+			for _, typ := range []*Type{errorType, requestType, operationType} {
+				typ.LongRunning = true
+				if err := deriver.add(typ, typ != operationType); err != nil {
+					return fmt.Errorf("method %s: %w", name, err)
+				}
+			}
+			method.OutputType = operationType.Name
+
+			resolved = append(resolved, &Method{
+				Name:         PollMethodName(method.Name),
+				InputType:    requestType.Name,
+				OutputType:   operationType.Name,
+				Doc:          &Documentation{General: fmt.Sprintf("Polls a long-running %s operation.", method.Name)},
+				HTTPMethod:   "GET",
+				GraphQLType:  "query",
+				PathTemplate: strings.TrimSuffix(method.RelativeHTTPPath(service), "/") + "/operations/{name}",
+				ErrorCodes:   []string{"404"},
+				Idempotent:   true,
+				Auth:         method.Auth,
+				Polls:        method.Name,
+			})
+		}
+		service.Methods = resolved
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+// longRunningSchema returns a schema where ImportUsers and PurgeUsers are long-running
+func longRunningSchema() *Schema {
+	return &Schema{
+		Namespace: "batch",
+		Types: []*Type{
+			{
+				Name:      "ImportResult",
+				Namespace: "batch",
+				Fields: []*Field{
+					{Name: "imported", Type: &FieldType{Name: "int32"}, Number: 1, HasNumber: true, Views: []string{"count"}},
+					{Name: "failures", Type: &FieldType{Name: "string", IsArray: true}, Number: 2, HasNumber: true},
+				},
+			},
+			{Name: "ImportRequest", Namespace: "batch"},
+		},
+		Services: []*Service{
+			{
+				Name:      "BatchService",
+				Namespace: "batch",
+				Methods: []*Method{
+					{Name: "ImportUsers", InputType: "ImportRequest", OutputType: "ImportResult", PathTemplate: "/v1/imports", LongRunning: &LongRunning{}},
+					{Name: "PurgeUsers", InputType: "ImportRequest", LongRunning: &LongRunning{}},
+					{Name: "GetImport", InputType: "ImportRequest", OutputType: "ImportResult"},
+				},
+			},
+		},
+	}
+}
+
+func TestSchema_ResolveLongRunning(t *testing.T) {
+	schema := longRunningSchema()
+	for i := 0; i < 2; i++ {
+		// Resolving again replaces the derived types and methods
+		if err := schema.ResolveLongRunning(); err != nil {
+			t.Fatalf("ResolveLongRunning failed: %v", err)
+		}
+
+		var types, methods []string
+		for _, typ := range schema.Types {
+			types = append(types, typ.Name)
+		}
+		for _, method := range schema.Services[0].Methods {
+			methods = append(methods, method.Name+":"+method.OutputType)
+		}
+		if got := strings.Join(types, ","); got != "ImportResult,ImportRequest,OperationError,GetOperationRequest,ImportUsersOperation,PurgeUsersOperation" {
+			t.Fatalf("Unexpected types %s", got)
+		}
+		want := "ImportUsers:ImportUsersOperation,GetImportUsersOperation:ImportUsersOperation," +
+			"PurgeUsers:PurgeUsersOperation,GetPurgeUsersOperation:PurgeUsersOperation,GetImport:ImportResult"
+		if got := strings.Join(methods, ","); got != want {
+			t.Fatalf("Unexpected methods %s", got)
+		}
+	}
+
+	if got := fieldNames(schema.Types[4]); got != "name,done,response,error" || schema.Types[4].Fields[2].Type.Name != "ImportResult" {
+		t.Errorf("Unexpected ImportUsersOperation fields %s", got)
+	}
+	if got := fieldNames(schema.Types[5]); got != "name,done,error" {
+		t.Errorf("Expected PurgeUsersOperation to hold no response, got %s", got)
+	}
+
+	methods := schema.Services[0].Methods
+	if response := methods[0].LongRunning.Response; response != "ImportResult" {
+		t.Errorf("Expected ImportUsers to keep its response type, got %s", response)
+	}
+	poll := methods[1]
+	if poll.Polls != "ImportUsers" || poll.InputType != GetOperationRequestTypeName || poll.GetHTTPMethod() != "get" || poll.PathTemplate != "/v1/imports/operations/{name}" {
+		t.Errorf("Unexpected polling method %+v", poll)
+	}
+	if path := methods[3].PathTemplate; path != "/batchservice/purgeusers/operations/{name}" {
+		t.Errorf("Expected the polling path to follow the inferred path, got %s", path)
+	}
+}
+
+func TestSchema_ResolveLongRunningBasePath(t *testing.T) {
+	schema := longRunningSchema()
+	service := schema.Services[0]
+	service.BasePath = "/v2"
+	if err := schema.ResolveLongRunning(); err != nil {
+		t.Fatalf("ResolveLongRunning failed: %v", err)
+	}
+
+	// The polling paths are prefixed with the base path once, like those they follow
+	for i, expected := range map[int]string{1: "/v2/v1/imports/operations/{name}", 3: "/v2/purgeusers/operations/{name}"} {
+		if path := service.Methods[i].HTTPPath(service); path != expected {
+			t.Errorf("Expected %s, got %s", expected, path)
+		}
+	}
+}
+
+func TestSchema_ResolveLongRunningWithView(t *testing.T) {
+	schema := longRunningSchema()
+	schema.Services[0].Methods[0].View = "count"
+	for i := 0; i < 2; i++ {
+		if err := schema.ResolveViews(); err != nil {
+			t.Fatalf("ResolveViews failed: %v", err)
+		}
+		if err := schema.ResolveLongRunning(); err != nil {
+			t.Fatalf("ResolveLongRunning failed: %v", err)
+		}
+	}
+
+	method := schema.Services[0].Methods[0]
+	if method.OutputType != "ImportUsersOperation" || method.LongRunning.Response != "ImportResultCount" {
+		t.Errorf("Expected the operation to hold the count view, got %s holding %s", method.OutputType, method.LongRunning.Response)
+	}
+}
+
+func TestSchema_ResolveLongRunningErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *Schema)
+		want   string
+	}{
+		{
+			name:   "streaming",
+			modify: func(s *Schema) { s.Services[0].Methods[0].OutputStream = true },
+			want:   "method BatchService.ImportUsers is long-running and cannot stream",
+		},
+		{
+			name: "polling method declared",
+			modify: func(s *Schema) {
+				s.Services[0].Methods = append(s.Services[0].Methods, &Method{Name: "GetImportUsersOperation"})
+			},
+			want: "its polling method GetImportUsersOperation is declared already",
+		},
+		{
+			name: "type collision",
+			modify: func(s *Schema) {
+				s.Types = append(s.Types, &Type{Name: "OperationError", Namespace: "batch"})
+			},
+			want: "method BatchService.ImportUsers: long-running operation type OperationError collides with another type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := longRunningSchema()
+			tt.modify(schema)
+			err := schema.ResolveLongRunning()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
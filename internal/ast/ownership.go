@@ -0,0 +1,48 @@
+package ast
+
+// Ownership is who owns a schema element and the service level it is held to,
+// from the @owner, @sla, and @tier annotations of namespaces, types, and
+// services, so that platform teams can route schema questions and breakages.
+type Ownership struct {
+	Owner string `json:"owner,omitempty"` // Team or person owning the element, from @owner (e.g., "team-payments")
+	SLA   string `json:"sla,omitempty"`   // Service level objective, from @sla (e.g., "99.9%")
+	Tier  string `json:"tier,omitempty"`  // Criticality tier, from @tier (e.g., "1" or "critical")
+}
+
+// Merge returns a copy of the ownership with the fields set in other replacing
+// its own. Either may be nil; the result is nil when both are.
+func (o *Ownership) Merge(other *Ownership) *Ownership {
+	if o == nil && other == nil {
+		return nil
+	}
+	merged := &Ownership{}
+	for _, source := range []*Ownership{o, other} {
+		if source == nil {
+			continue
+		}
+		if source.Owner != "" {
+			merged.Owner = source.Owner
+		}
+		if source.SLA != "" {
+			merged.SLA = source.SLA
+		}
+		if source.Tier != "" {
+			merged.Tier = source.Tier
+		}
+	}
+	return merged
+}
+
+// OwnershipOf returns the effective ownership of an element of a namespace with
+// the given annotations: its own @owner, @sla, and @tier, each falling back to
+// those of its namespace, or nil when neither has any.
+func (s *Schema) OwnershipOf(namespace string, annotations *FormatAnnotations) *Ownership {
+	var own, inherited *Ownership
+	if annotations != nil {
+		own = annotations.Ownership
+	}
+	if nsAnnotations := s.GetNamespaceAnnotations(namespace); nsAnnotations != nil {
+		inherited = nsAnnotations.Ownership
+	}
+	return inherited.Merge(own)
+}
@@ -0,0 +1,94 @@
+package ast
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RateLimit is the request quota of a method, declared with @ratelimit(100, per="minute").
+type RateLimit struct {
+	Requests int    `json:"requests"`
+	Per      string `json:"per"` // Window unit: second, minute, hour, or day
+}
+
+// rateLimitWindows maps the window units of @ratelimit to their duration
+var rateLimitWindows = map[string]time.Duration{
+	"second": time.Second,
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+}
+
+// RateLimitWindows returns the window units accepted by @ratelimit, sorted by duration.
+func RateLimitWindows() []string {
+	units := make([]string, 0, len(rateLimitWindows))
+	for unit := range rateLimitWindows {
+		units = append(units, unit)
+	}
+	sort.Slice(units, func(i, j int) bool {
+		return rateLimitWindows[units[i]] < rateLimitWindows[units[j]]
+	})
+	return units
+}
+
+// Validate checks that the rate limit allows at least one request per known window.
+func (r *RateLimit) Validate() error {
+	if r.Requests <= 0 {
+		return fmt.Errorf("rate limit must allow at least one request, got %d", r.Requests)
+	}
+	if _, ok := rateLimitWindows[r.Per]; !ok {
+		return fmt.Errorf("unknown rate limit window %q (expected %s)", r.Per, strings.Join(RateLimitWindows(), ", "))
+	}
+	return nil
+}
+
+// Window returns the duration of the rate limit window.
+func (r *RateLimit) Window() time.Duration {
+	return rateLimitWindows[r.Per]
+}
+
+// String describes the rate limit, such as "100 requests per minute".
+func (r *RateLimit) String() string {
+	noun := "requests"
+	if r.Requests == 1 {
+		noun = "request"
+	}
+	return fmt.Sprintf("%d %s per %s", r.Requests, noun, r.Per)
+}
+
+// Auth is the authentication a method requires of its callers, declared with
+// @auth or @auth("orders:read", "orders:write") on the method or its service.
+type Auth struct {
+	Scopes []string `json:"scopes,omitempty"` // Scopes the caller needs, all of them; none when any authenticated caller may call
+}
+
+// String describes the authentication, such as "scopes orders:read, orders:write".
+func (a *Auth) String() string {
+	if len(a.Scopes) == 0 {
+		return "authenticated caller"
+	}
+	return "scopes " + strings.Join(a.Scopes, ", ")
+}
+
+// ParseTimeout parses the duration of @timeout, such as "5s" or "1m30s".
+func ParseTimeout(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q (expected a positive duration such as \"5s\" or \"500ms\")", value)
+	}
+	return d, nil
+}
+
+// TimeoutDuration returns the timeout of the method, or zero when it declares none.
+func (m *Method) TimeoutDuration() time.Duration {
+	d, _ := ParseTimeout(m.Timeout)
+	return d
+}
+
+// HasPolicy reports whether the method declares a timeout, rate limit,
+// idempotency, or authentication.
+func (m *Method) HasPolicy() bool {
+	return m.Timeout != "" || m.Idempotent || m.RateLimit != nil || m.Auth != nil
+}
@@ -0,0 +1,76 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimeout(t *testing.T) {
+	if d, err := ParseTimeout("1m30s"); err != nil || d != 90*time.Second {
+		t.Errorf("Expected 1m30s to parse as 90s, got %v, %v", d, err)
+	}
+	for _, value := range []string{"", "5", "soon", "0s", "-1s"} {
+		if _, err := ParseTimeout(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	limit := &RateLimit{Requests: 100, Per: "minute"}
+	if err := limit.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if limit.Window() != time.Minute {
+		t.Errorf("Expected a minute window, got %v", limit.Window())
+	}
+	if limit.String() != "100 requests per minute" {
+		t.Errorf("Unexpected description %q", limit.String())
+	}
+	if s := (&RateLimit{Requests: 1, Per: "day"}).String(); s != "1 request per day" {
+		t.Errorf("Unexpected description %q", s)
+	}
+
+	err := (&RateLimit{Requests: 10, Per: "week"}).Validate()
+	if err == nil || !strings.Contains(err.Error(), "second, minute, hour, day") {
+		t.Errorf("Expected unknown window error listing the windows by duration, got %v", err)
+	}
+	if err := (&RateLimit{Requests: 0, Per: "second"}).Validate(); err == nil {
+		t.Error("Expected a rate limit without requests to be rejected")
+	}
+}
+
+func TestMethod_HasPolicy(t *testing.T) {
+	if (&Method{Name: "Get"}).HasPolicy() {
+		t.Error("Expected a method without annotations to have no policy")
+	}
+	method := &Method{Name: "Get", Timeout: "5s"}
+	if !method.HasPolicy() || method.TimeoutDuration() != 5*time.Second {
+		t.Errorf("Expected a 5s timeout policy, got %v", method.TimeoutDuration())
+	}
+	if !(&Method{Name: "Get", Idempotent: true}).HasPolicy() {
+		t.Error("Expected an idempotent method to have a policy")
+	}
+}
+
+func TestOwnershipOf(t *testing.T) {
+	schema := &Schema{
+		Namespace:            "payments",
+		NamespaceAnnotations: &FormatAnnotations{Ownership: &Ownership{Owner: "team-payments", Tier: "2"}},
+	}
+
+	got := schema.OwnershipOf("payments", &FormatAnnotations{Ownership: &Ownership{SLA: "99.9%", Tier: "1"}})
+	if want := (Ownership{Owner: "team-payments", SLA: "99.9%", Tier: "1"}); got == nil || *got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if got := schema.OwnershipOf("payments", nil); got == nil || got.Owner != "team-payments" {
+		t.Errorf("Expected the namespace ownership, got %+v", got)
+	}
+	if got := schema.OwnershipOf("billing", nil); got != nil {
+		t.Errorf("Expected no ownership, got %+v", got)
+	}
+	if schema.NamespaceAnnotations.Ownership.SLA != "" {
+		t.Error("Expected merging not to change the namespace ownership")
+	}
+}
@@ -0,0 +1,360 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Standard methods of a resource, from @crud
+const (
+	CrudCreate = "create"
+	CrudGet    = "get"
+	CrudList   = "list"
+	CrudUpdate = "update"
+	CrudDelete = "delete"
+)
+
+// CrudMethods are the standard methods of a resource, in the order they are derived
+var CrudMethods = []string{CrudCreate, CrudGet, CrudList, CrudUpdate, CrudDelete}
+
+// Defaults of the settings of @crud, as templates
+const (
+	DefaultCrudService = "{Type}Service"
+	DefaultCrudPath    = "/{types}"
+	DefaultCrudID      = "id"
+)
+
+// Crud marks a type as a resource whose standard methods are derived for it,
+// from @crud. On a namespace it is the template of the resources of the
+// namespace: the settings they leave empty default to its own. Service and
+// path templates may hold the placeholders {Type} (OrderItem), {type}
+// (order-item), and {types} (order-items).
+type Crud struct {
+	Methods []string `json:"methods,omitempty"` // Methods to derive among create, get, list, update, and delete; all when empty
+	Service string   `json:"service,omitempty"` // Service holding the methods (default: {Type}Service)
+	Path    string   `json:"path,omitempty"`    // HTTP path of the collection (default: /{types})
+	ID      string   `json:"id,omitempty"`      // Field identifying a resource in paths and requests (default: id)
+}
+
+// Validate checks the methods of a @crud annotation.
+func (c *Crud) Validate() error {
+	seen := make(map[string]bool)
+	for _, method := range c.Methods {
+		if !contains(CrudMethods, method) {
+			return fmt.Errorf("unknown method %q (expected %s)", method, strings.Join(CrudMethods, ", "))
+		}
+		if seen[method] {
+			return fmt.Errorf("duplicate method %q", method)
+		}
+		seen[method] = true
+	}
+	return nil
+}
+
+// resolve returns the settings of a resource type with those it leaves empty
+// taken from a template, which may be nil, and then from the defaults, with
+// the placeholders replaced
+func (c *Crud) resolve(template *Crud, typeName string) *Crud {
+	if template == nil {
+		template = &Crud{}
+	}
+	pick := func(values ...string) string {
+		for _, value := range values {
+			if value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	resolved := &Crud{
+		Methods: c.Methods,
+		Service: pick(c.Service, template.Service, DefaultCrudService),
+		Path:    pick(c.Path, template.Path, DefaultCrudPath),
+		ID:      pick(c.ID, template.ID, DefaultCrudID),
+	}
+	if len(resolved.Methods) == 0 {
+		resolved.Methods = template.Methods
+	}
+	if len(resolved.Methods) == 0 {
+		resolved.Methods = CrudMethods
+	}
+
+	placeholders := strings.NewReplacer(
+		"{Type}", typeName,
+		"{type}", kebabCase(typeName),
+		"{types}", kebabCase(Plural(typeName)),
+	)
+	resolved.Service = placeholders.Replace(resolved.Service)
+	resolved.Path = "/" + strings.Trim(placeholders.Replace(resolved.Path), "/")
+	return resolved
+}
+
+// Plural returns the English plural of a name, by its last word: Users for
+// User, Addresses for Address, Categories for Category.
+func Plural(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
+
+// kebabCase converts a type name to lower-case words joined by hyphens:
+// order-item for OrderItem
+func kebabCase(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			sb.WriteByte('-')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// lowerFirst lower-cases the first letter of a name: orderItem for OrderItem
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// contains reports whether a list holds a value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveResources derives the standard methods of every type marked @crud, in
+// the service its @crud names, which is added to the namespace unless declared:
+//
+//	CreateUser(CreateUserRequest) returns (User)      POST   /users
+//	GetUser(GetUserRequest) returns (User)            GET    /users/{id}
+//	ListUsers(ListUsersRequest) returns (ListUsersResponse) GET /users
+//	UpdateUser(UpdateUserRequest) returns (User)      PATCH  /users/{id}
+//	DeleteUser(DeleteUserRequest) returns ()          DELETE /users/{id}
+//
+// Create and update requests hold the resource; get, update, and delete requests
+// its identifying field; list requests a page size and token, and list
+// responses the resources of the page and the token of the next one. It can be
+// called again after the schema changes: previously derived types, methods,
+// and services are replaced.
+func (s *Schema) ResolveResources() error {
+	deriver := newTypeDeriver(s, "derived type", func(typ *Type) bool { return typ.Resource })
+
+	var services []*Service
+	for _, service := range s.Services {
+		if service.Resources {
+			continue
+		}
+		var methods []*Method
+		for _, method := range service.Methods {
+			if method.Resource == "" {
+				methods = append(methods, method)
+			}
+		}
+		service.Methods = methods
+		services = append(services, service)
+	}
+	s.Services = services
+
+	types := s.Types
+	for _, typ := range types {
+		if typ.Annotations == nil || typ.Annotations.Crud == nil || typ.ViewOf != "" {
+			continue
+		}
+		var template *Crud
+		if nsAnnotations := s.GetNamespaceAnnotations(typ.Namespace); nsAnnotations != nil {
+			template = nsAnnotations.Crud
+		}
+		if err := s.deriveResource(deriver, typ, typ.Annotations.Crud.resolve(template, typ.Name)); err != nil {
+			return fmt.Errorf("resource %s: %w", typ.Name, err)
+		}
+	}
+
+	// Added services hold the methods of one or more resources
+	for _, service := range s.Services {
+		if !service.Resources {
+			continue
+		}
+		var resources []string
+		for _, method := range service.Methods {
+			if !contains(resources, method.Resource) {
+				resources = append(resources, method.Resource)
+			}
+		}
+		if len(resources) == 1 {
+			service.Doc = &Documentation{General: fmt.Sprintf("Standard methods of the %s resource.", resources[0])}
+		} else {
+			last := len(resources) - 1
+			service.Doc = &Documentation{General: fmt.Sprintf("Standard methods of the %s and %s resources.", strings.Join(resources[:last], ", "), resources[last])}
+		}
+	}
+	return nil
+}
+
+// deriveResource adds the methods of a resource type, and the types of their
+// requests and responses
+func (s *Schema) deriveResource(deriver *typeDeriver, typ *Type, crud *Crud) error {
+	var id *Field
+	for _, field := range typ.Fields {
+		if field.Name == crud.ID {
+			id = field
+		}
+	}
+	needsID := false
+	for _, method := range crud.Methods {
+		needsID = needsID || method == CrudGet || method == CrudUpdate || method == CrudDelete
+	}
+	if needsID && (id == nil || id.Type.IsArray || id.Type.IsMap || !IsBuiltinType(id.Type.Name)) {
+		return fmt.Errorf("type has no scalar field %s to identify resources by", crud.ID)
+	}
+
+	var service *Service
+	for _, candidate := range s.Services {
+		if candidate.Name == crud.Service && candidate.Namespace == typ.Namespace {
+			service = candidate
+		}
+	}
+	if service == nil {
+		service = &Service{
+			Name:      crud.Service,
+			Namespace: typ.Namespace,
+			Resources: true,
+		}
+		s.Services = append(s.Services, service)
+	}
+	declared := make(map[string]bool)
+	for _, method := range service.Methods {
+		declared[method.Name] = true
+	}
+
+	plural := Plural(typ.Name)
+	resourceField := lowerFirst(typ.Name)
+	idField := func(number int) *Field {
+		field := derivedField(id.Name, id.Type.Name, number, fmt.Sprintf("%s of the %s", id.Name, typ.Name))
+		field.Required = true
+		return field
+	}
+	itemPath := crud.Path + "/{" + crud.ID + "}"
+
+	for _, kind := range CrudMethods {
+		if !contains(crud.Methods, kind) {
+			continue
+		}
+
+		var method *Method
+		var request, response *Type
+		switch kind {
+		case CrudCreate:
+			request = &Type{Name: "Create" + typ.Name + "Request", Fields: []*Field{
+				derivedField(resourceField, typ.Name, 1, fmt.Sprintf("%s to create", typ.Name)),
+			}}
+			request.Fields[0].Required = true
+			method = &Method{
+				Name:         "Create" + typ.Name,
+				OutputType:   typ.Name,
+				Doc:          &Documentation{General: fmt.Sprintf("Creates a %s.", typ.Name)},
+				HTTPMethod:   "POST",
+				PathTemplate: crud.Path,
+			}
+		case CrudGet:
+			request = &Type{Name: "Get" + typ.Name + "Request", Fields: []*Field{idField(1)}}
+			method = &Method{
+				Name:         "Get" + typ.Name,
+				OutputType:   typ.Name,
+				Doc:          &Documentation{General: fmt.Sprintf("Gets a %s by %s.", typ.Name, id.Name)},
+				HTTPMethod:   "GET",
+				PathTemplate: itemPath,
+				ErrorCodes:   []string{"404"},
+				Idempotent:   true,
+			}
+		case CrudList:
+			request = &Type{Name: "List" + plural + "Request", Fields: []*Field{
+				derivedField("pageSize", "int32", 1, fmt.Sprintf("Maximum number of %s to return", plural)),
+				derivedField("pageToken", "string", 2, "Token of the page to return, from nextPageToken; the first page when empty"),
+			}}
+			response = &Type{Name: "List" + plural + "Response", Fields: []*Field{
+				derivedField(lowerFirst(plural), typ.Name, 1, fmt.Sprintf("%s of the page", plural)),
+				derivedField("nextPageToken", "string", 2, "Token of the next page; empty on the last page"),
+			}}
+			response.Fields[0].Type.IsArray = true
+			method = &Method{
+				Name:         "List" + plural,
+				OutputType:   response.Name,
+				Doc:          &Documentation{General: fmt.Sprintf("Lists %s, a page at a time.", plural)},
+				HTTPMethod:   "GET",
+				PathTemplate: crud.Path,
+				Idempotent:   true,
+			}
+		case CrudUpdate:
+			request = &Type{Name: "Update" + typ.Name + "Request", Fields: []*Field{
+				idField(1),
+				derivedField(resourceField, typ.Name, 2, fmt.Sprintf("%s with the new values", typ.Name)),
+				derivedField("updateMask", "string", 3, "Fields to update; all when empty"),
+			}}
+			request.Fields[1].Required = true
+			request.Fields[2].Type.IsArray = true
+			method = &Method{
+				Name:         "Update" + typ.Name,
+				OutputType:   typ.Name,
+				Doc:          &Documentation{General: fmt.Sprintf("Updates a %s.", typ.Name)},
+				HTTPMethod:   "PATCH",
+				PathTemplate: itemPath,
+				ErrorCodes:   []string{"404"},
+			}
+		case CrudDelete:
+			request = &Type{Name: "Delete" + typ.Name + "Request", Fields: []*Field{idField(1)}}
+			method = &Method{
+				Name:         "Delete" + typ.Name,
+				Doc:          &Documentation{General: fmt.Sprintf("Deletes a %s.", typ.Name)},
+				HTTPMethod:   "DELETE",
+				PathTemplate: itemPath,
+				ErrorCodes:   []string{"404"},
+				Idempotent:   true,
+			}
+		}
+
+		if declared[method.Name] {
+			return fmt.Errorf("method %s is declared already in service %s", method.Name, service.Name)
+		}
+		request.Doc = &Documentation{General: fmt.Sprintf("The request of %s.", method.Name)}
+		if response != nil {
+			response.Doc = &Documentation{General: fmt.Sprintf("The response of %s.", method.Name)}
+		}
+		for _, derived := range []*Type{request, response} {
+			if derived == nil {
+				continue
+			}
+			derived.Namespace = typ.Namespace
+			derived.Resource = true
+			if err := deriver.add(derived, false); err != nil {
+				return err
+			}
+		}
+		method.InputType = request.Name
+		method.GraphQLType = "mutation"
+		if kind == CrudGet || kind == CrudList {
+			method.GraphQLType = "query"
+		}
+		method.Resource = typ.Name
+		if service.Annotations != nil {
+			method.Auth = service.Annotations.Auth
+		}
+		service.Methods = append(service.Methods, method)
+	}
+	return nil
+}
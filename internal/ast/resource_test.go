@@ -0,0 +1,187 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+// resourceSchema returns a schema where User is a resource with all standard methods
+func resourceSchema() *Schema {
+	return &Schema{
+		Namespace: "users",
+		Types: []*Type{
+			{Name: "User", Namespace: "users", Annotations: &FormatAnnotations{Crud: &Crud{}}, Fields: []*Field{
+				{Name: "id", Type: &FieldType{Name: "string", IsBuiltin: true}},
+				{Name: "name", Type: &FieldType{Name: "string", IsBuiltin: true}},
+			}},
+		},
+	}
+}
+
+func TestSchema_ResolveResources(t *testing.T) {
+	schema := resourceSchema()
+	for i := 0; i < 2; i++ {
+		// Resolving again replaces the derived types, methods, and services
+		if err := schema.ResolveResources(); err != nil {
+			t.Fatalf("ResolveResources failed: %v", err)
+		}
+
+		var types []string
+		for _, typ := range schema.Types {
+			types = append(types, typ.Name)
+		}
+		want := "User,CreateUserRequest,GetUserRequest,ListUsersRequest,ListUsersResponse,UpdateUserRequest,DeleteUserRequest"
+		if got := strings.Join(types, ","); got != want {
+			t.Fatalf("Unexpected types %s", got)
+		}
+		if len(schema.Services) != 1 || schema.Services[0].Name != "UserService" || !schema.Services[0].Resources {
+			t.Fatalf("Expected a derived UserService, got %+v", schema.Services)
+		}
+	}
+
+	var methods []string
+	for _, method := range schema.Services[0].Methods {
+		methods = append(methods, method.Name+" "+method.HTTPMethod+" "+method.PathTemplate+" "+method.InputType+":"+method.OutputType)
+	}
+	want := []string{
+		"CreateUser POST /users CreateUserRequest:User",
+		"GetUser GET /users/{id} GetUserRequest:User",
+		"ListUsers GET /users ListUsersRequest:ListUsersResponse",
+		"UpdateUser PATCH /users/{id} UpdateUserRequest:User",
+		"DeleteUser DELETE /users/{id} DeleteUserRequest:",
+	}
+	if got := strings.Join(methods, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("Unexpected methods:\n%s", got)
+	}
+
+	if got := fieldNames(schema.Types[5]); got != "id,user,updateMask" {
+		t.Errorf("Unexpected update request fields %s", got)
+	}
+	if got := fieldNames(schema.Types[4]); got != "users,nextPageToken" || !schema.Types[4].Fields[0].Type.IsArray {
+		t.Errorf("Unexpected list response fields %s", got)
+	}
+	if doc := schema.Services[0].Doc; doc == nil || doc.General != "Standard methods of the User resource." {
+		t.Errorf("Unexpected service documentation %+v", doc)
+	}
+	if get := schema.Services[0].Methods[1]; get.GraphQLType != "query" || !get.Idempotent || get.Resource != "User" {
+		t.Errorf("Unexpected get method %+v", get)
+	}
+}
+
+func TestSchema_ResolveResourcesTemplates(t *testing.T) {
+	schema := resourceSchema()
+	schema.NamespaceAnnotations = &FormatAnnotations{Crud: &Crud{Path: "/v1/{types}", Service: "Directory"}}
+	schema.Types = append(schema.Types, &Type{
+		Name:        "OrderItem",
+		Namespace:   "users",
+		Annotations: &FormatAnnotations{Crud: &Crud{Methods: []string{CrudGet, CrudList}, ID: "sku"}},
+		Fields:      []*Field{{Name: "sku", Type: &FieldType{Name: "string", IsBuiltin: true}}},
+	})
+	schema.Services = []*Service{{Name: "Directory", Namespace: "users", Methods: []*Method{{Name: "Ping"}}}}
+
+	if err := schema.ResolveResources(); err != nil {
+		t.Fatalf("ResolveResources failed: %v", err)
+	}
+	if len(schema.Services) != 1 || schema.Services[0].Resources {
+		t.Fatalf("Expected the methods to be added to the declared service, got %+v", schema.Services)
+	}
+
+	var methods []string
+	for _, method := range schema.Services[0].Methods {
+		methods = append(methods, method.Name+" "+method.PathTemplate)
+	}
+	want := "Ping ,CreateUser /v1/users,GetUser /v1/users/{id},ListUsers /v1/users,UpdateUser /v1/users/{id},DeleteUser /v1/users/{id}," +
+		"GetOrderItem /v1/order-items/{sku},ListOrderItems /v1/order-items"
+	if got := strings.Join(methods, ","); got != want {
+		t.Errorf("Unexpected methods %s", got)
+	}
+}
+
+func TestSchema_ResolveResourcesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *Schema)
+		want   string
+	}{
+		{
+			name:   "no id field",
+			modify: func(s *Schema) { s.Types[0].Annotations.Crud.ID = "uuid" },
+			want:   "resource User: type has no scalar field uuid to identify resources by",
+		},
+		{
+			name: "method declared",
+			modify: func(s *Schema) {
+				s.Services = []*Service{{Name: "UserService", Namespace: "users", Methods: []*Method{{Name: "GetUser"}}}}
+			},
+			want: "resource User: method GetUser is declared already in service UserService",
+		},
+		{
+			name: "type declared",
+			modify: func(s *Schema) {
+				s.Types = append(s.Types, &Type{Name: "ListUsersRequest", Namespace: "users"})
+			},
+			want: "ListUsersRequest",
+		},
+	}
+
+	for _, tt := range tests {
+		schema := resourceSchema()
+		tt.modify(schema)
+		err := schema.ResolveResources()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// Methods without an identifier do not need an id field
+	schema := resourceSchema()
+	schema.Types[0].Annotations.Crud = &Crud{Methods: []string{CrudCreate, CrudList}, ID: "uuid"}
+	if err := schema.ResolveResources(); err != nil {
+		t.Errorf("Expected create and list to need no id field, got %v", err)
+	}
+}
+
+func TestCrud_Validate(t *testing.T) {
+	tests := []struct {
+		methods []string
+		want    string
+	}{
+		{nil, ""},
+		{[]string{CrudGet, CrudList}, ""},
+		{[]string{"patch"}, `unknown method "patch"`},
+		{[]string{CrudGet, CrudGet}, `duplicate method "get"`},
+	}
+	for _, tt := range tests {
+		err := (&Crud{Methods: tt.methods}).Validate()
+		if tt.want == "" && err != nil {
+			t.Errorf("%v: expected no error, got %v", tt.methods, err)
+		}
+		if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("%v: expected an error containing %q, got %v", tt.methods, tt.want, err)
+		}
+	}
+}
+
+func TestPluralAndKebabCase(t *testing.T) {
+	tests := []struct {
+		name   string
+		plural string
+		kebab  string
+	}{
+		{"User", "Users", "user"},
+		{"Address", "Addresses", "address"},
+		{"Category", "Categories", "category"},
+		{"Key", "Keys", "key"},
+		{"OrderItem", "OrderItems", "order-item"},
+		{"HTTPRoute", "HTTPRoutes", "http-route"},
+		{"Box", "Boxes", "box"},
+	}
+	for _, tt := range tests {
+		if got := Plural(tt.name); got != tt.plural {
+			t.Errorf("Expected plural %s of %s, got %s", tt.plural, tt.name, got)
+		}
+		if got := kebabCase(tt.name); got != tt.kebab {
+			t.Errorf("Expected kebab case %s of %s, got %s", tt.kebab, tt.name, got)
+		}
+	}
+}
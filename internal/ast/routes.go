@@ -0,0 +1,132 @@
+package ast
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pathParamRegex matches the {name} parameters of a path template
+var pathParamRegex = regexp.MustCompile(`\{([^}]*)\}`)
+
+// PathParameters returns the names of the {name} parameters of a path template, in order.
+func PathParameters(path string) []string {
+	var names []string
+	for _, match := range pathParamRegex.FindAllStringSubmatch(path, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// JoinPaths prefixes an HTTP path with a base path, such as the
+// @http.base_path of a service, with a single slash between them. An empty
+// base path leaves the path as it is.
+func JoinPaths(base, path string) string {
+	base = strings.TrimSuffix(base, "/")
+	if base == "" {
+		return path
+	}
+	if path == "" || path == "/" {
+		return base
+	}
+	return base + "/" + strings.TrimPrefix(path, "/")
+}
+
+// PathField returns the field of a request type that fills a path parameter:
+// the field with the parameter as its name or OpenAPI name, or nil.
+func PathField(typ *Type, param string) *Field {
+	for _, field := range typ.Fields {
+		if field.Name == param || field.NameFor("openapi") == param {
+			return field
+		}
+	}
+	return nil
+}
+
+// RouteErrors reports methods that map to the same HTTP route, the HTTP method
+// and path of their OpenAPI operation, whether set with @http.method and
+// @http.path or inferred from the method and service names, under the
+// @http.base_path of their service. Paths that differ
+// only in the names of their parameters, such as /users/{id} and
+// /users/{userId}, are the same route. Webhooks have no route. Validate does
+// not call it, since only outputs that serve HTTP routes, such as OpenAPI, care.
+func (s *Schema) RouteErrors() []string {
+	var errs []string
+	type route struct {
+		method, path string
+	}
+	seen := make(map[string]route)
+	for _, service := range s.Services {
+		for _, method := range service.Methods {
+			if method.IsWebhook() {
+				continue
+			}
+			httpMethod, path := strings.ToUpper(method.GetHTTPMethod()), method.HTTPPath(service)
+			current := route{method: service.Name + "." + method.Name, path: httpMethod + " " + path}
+			key := httpMethod + " " + pathParamRegex.ReplaceAllString(path, "{}")
+			if other, ok := seen[key]; ok {
+				if other.method == current.method {
+					continue // A method declared twice, which Validate reports
+				}
+				errs = append(errs, fmt.Sprintf("methods %s (%s) and %s (%s) map to the same HTTP route; give one of them a different @http.method, @http.path, or @http.base_path",
+					other.method, other.path, current.method, current.path))
+				continue
+			}
+			seen[key] = current
+		}
+	}
+	return errs
+}
+
+// PathParameterErrors reports the {name} parameters of @http.path templates and
+// @http.base_path prefixes that no field of the input type of their method fills, and those whose field cannot
+// be written in a path: lists, maps, and messages. Enums and scalars can. Like
+// RouteErrors, Validate does not call it.
+func (s *Schema) PathParameterErrors() []string {
+	registry := NewTypeRegistry()
+	for _, typ := range s.Types {
+		registry.RegisterType(typ)
+	}
+	for _, enum := range s.Enums {
+		registry.RegisterEnum(enum)
+	}
+	for _, union := range s.Unions {
+		registry.RegisterUnion(union)
+	}
+
+	var errs []string
+	for _, service := range s.Services {
+		for _, method := range service.Methods {
+			params := PathParameters(method.HTTPPath(service))
+			if len(params) == 0 || method.IsWebhook() {
+				continue
+			}
+			name := service.Name + "." + method.Name
+			if !method.HasInput() {
+				errs = append(errs, fmt.Sprintf("path parameter {%s} of method %s has no input type to fill it", params[0], name))
+				continue
+			}
+			qualifiedName, _ := registry.ResolveType(method.InputType, service.Namespace)
+			input, ok := registry.Types[qualifiedName]
+			if !ok {
+				continue // Unknown input types are reported by Validate
+			}
+			for _, param := range params {
+				field := PathField(input, param)
+				switch {
+				case field == nil:
+					errs = append(errs, fmt.Sprintf("path parameter {%s} of method %s matches no field of %s", param, name, input.Name))
+				case field.Type == nil:
+				case field.Type.IsArray || field.Type.IsMap:
+					errs = append(errs, fmt.Sprintf("path parameter {%s} of method %s is field %s.%s, a list or map, which a path cannot hold", param, name, input.Name, field.Name))
+				case !IsBuiltinType(field.Type.Name):
+					qualifiedName, _ := registry.ResolveType(field.Type.Name, input.Namespace)
+					if _, isEnum := registry.Enums[qualifiedName]; !isEnum {
+						errs = append(errs, fmt.Sprintf("path parameter {%s} of method %s is field %s.%s of type %s, which a path cannot hold; use a scalar or enum field", param, name, input.Name, field.Name, field.Type.Name))
+					}
+				}
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,169 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMethod_HTTPPath(t *testing.T) {
+	service := &Service{Name: "UserService"}
+	if got := (&Method{Name: "GetUser"}).HTTPPath(service); got != "/userservice/getuser" {
+		t.Errorf("Expected a path from the names, got %s", got)
+	}
+	if got := (&Method{Name: "GetUser", PathTemplate: "/users/{id}"}).HTTPPath(service); got != "/users/{id}" {
+		t.Errorf("Expected the @http.path, got %s", got)
+	}
+
+	service = &Service{Name: "UserService", BasePath: "/v1/users/"}
+	tests := []struct {
+		method   *Method
+		expected string
+	}{
+		{&Method{Name: "GetUser"}, "/v1/users/getuser"},
+		{&Method{Name: "GetUser", PathTemplate: "/{id}"}, "/v1/users/{id}"},
+		{&Method{Name: "ListUsers", PathTemplate: "/"}, "/v1/users"},
+	}
+	for _, tt := range tests {
+		if got := tt.method.HTTPPath(service); got != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, got)
+		}
+	}
+}
+
+func TestJoinPaths(t *testing.T) {
+	tests := []struct {
+		base, path, expected string
+	}{
+		{"", "/users/{id}", "/users/{id}"},
+		{"/v1", "/users", "/v1/users"},
+		{"/v1/", "/users", "/v1/users"},
+		{"/v1", "users", "/v1/users"},
+		{"/v1", "/", "/v1"},
+		{"/", "/users", "/users"},
+	}
+	for _, tt := range tests {
+		if got := JoinPaths(tt.base, tt.path); got != tt.expected {
+			t.Errorf("JoinPaths(%q, %q): expected %s, got %s", tt.base, tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestSchema_RouteErrors(t *testing.T) {
+	schema := &Schema{
+		Services: []*Service{
+			{
+				Name: "UserService",
+				Methods: []*Method{
+					{Name: "GetUser", HTTPMethod: "GET", PathTemplate: "/users/{id}"},
+					{Name: "DeleteUser", HTTPMethod: "DELETE", PathTemplate: "/users/{id}"},
+					{Name: "ListUsers"},
+					{Name: "UserCreated", PathTemplate: "/users/{id}", Webhook: &Webhook{Name: "userCreated"}},
+				},
+			},
+			{
+				Name: "AdminService",
+				Methods: []*Method{
+					// The HTTP method is inferred from the name
+					{Name: "GetAccount", PathTemplate: "/users/{userId}"},
+				},
+			},
+			{
+				Name: "userservice",
+				Methods: []*Method{
+					{Name: "listusers", HTTPMethod: "GET"},
+				},
+			},
+			{
+				Name:     "AccountService",
+				BasePath: "/users",
+				Methods: []*Method{
+					{Name: "UpdateAccount", HTTPMethod: "DELETE", PathTemplate: "/{userId}"},
+				},
+			},
+		},
+	}
+
+	errs := schema.RouteErrors()
+	expected := []string{
+		"methods UserService.GetUser (GET /users/{id}) and AdminService.GetAccount (GET /users/{userId}) map to the same HTTP route",
+		"methods UserService.ListUsers (GET /userservice/listusers) and userservice.listusers (GET /userservice/listusers) map to the same HTTP route",
+		"methods UserService.DeleteUser (DELETE /users/{id}) and AccountService.UpdateAccount (DELETE /users/{userId}) map to the same HTTP route",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %v", len(expected), errs)
+	}
+	for i, want := range expected {
+		if !strings.HasPrefix(errs[i], want) {
+			t.Errorf("Expected error %q, got %q", want, errs[i])
+		}
+	}
+}
+
+func TestSchema_ValidateIgnoresRoutes(t *testing.T) {
+	schema := &Schema{
+		Services: []*Service{
+			{Name: "UserService", Methods: []*Method{{Name: "GetUser", HTTPMethod: "GET", PathTemplate: "/users"}}},
+			{Name: "AdminService", Methods: []*Method{{Name: "GetAdmin", HTTPMethod: "GET", PathTemplate: "/users"}}},
+			{Name: "TeamService", Methods: []*Method{{Name: "GetTeam", HTTPMethod: "GET", PathTemplate: "/teams/{id}"}}},
+		},
+	}
+
+	if errs := schema.Validate(); len(errs) != 0 {
+		t.Errorf("Expected Validate to leave HTTP routes to RouteErrors and PathParameterErrors, got %v", errs)
+	}
+	if errs := schema.PathParameterErrors(); len(errs) != 1 {
+		t.Errorf("Expected 1 path parameter error, got %v", errs)
+	}
+	if errs := schema.RouteErrors(); len(errs) != 1 {
+		t.Errorf("Expected 1 route error, got %v", errs)
+	}
+}
+
+func TestSchema_PathParameterErrors(t *testing.T) {
+	schema := &Schema{
+		Enums: []*Enum{{Name: "Region", Values: []*EnumValue{{Name: "EU"}}}},
+		Types: []*Type{
+			{
+				Name: "OrderRequest",
+				Fields: []*Field{
+					{Name: "order_id", Type: &FieldType{Name: "int64"}},
+					{Name: "region", Type: &FieldType{Name: "Region"}},
+					{Name: "tags", Type: &FieldType{Name: "string", IsArray: true}},
+					{Name: "item", Type: &FieldType{Name: "Item"}},
+				},
+			},
+			{Name: "Item"},
+		},
+		Services: []*Service{
+			{
+				Name: "OrderService",
+				Methods: []*Method{
+					{Name: "GetOrder", InputType: "OrderRequest", OutputType: "Item", PathTemplate: "/regions/{region}/orders/{order_id}"},
+					{Name: "Tagged", InputType: "OrderRequest", OutputType: "Item", PathTemplate: "/orders/tags/{tags}"},
+					{Name: "ByItem", InputType: "OrderRequest", OutputType: "Item", PathTemplate: "/orders/items/{item}"},
+					{Name: "Missing", InputType: "OrderRequest", OutputType: "Item", PathTemplate: "/orders/{orderId}/missing"},
+					{Name: "NoInput", OutputType: "Item", PathTemplate: "/orders/{order_id}/none"},
+				},
+			},
+			{
+				Name:     "ShopService",
+				BasePath: "/shops/{shop}",
+				Methods: []*Method{
+					{Name: "GetOrder", InputType: "OrderRequest", OutputType: "Item", PathTemplate: "/orders/{order_id}"},
+				},
+			},
+		},
+	}
+
+	want := []string{
+		"path parameter {tags} of method OrderService.Tagged is field OrderRequest.tags, a list or map, which a path cannot hold",
+		"path parameter {item} of method OrderService.ByItem is field OrderRequest.item of type Item, which a path cannot hold; use a scalar or enum field",
+		"path parameter {orderId} of method OrderService.Missing matches no field of OrderRequest",
+		"path parameter {order_id} of method OrderService.NoInput has no input type to fill it",
+		"path parameter {shop} of method ShopService.GetOrder matches no field of OrderRequest",
+	}
+	errs := schema.PathParameterErrors()
+	if strings.Join(errs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected path parameter errors:\n%s", strings.Join(errs, "\n"))
+	}
+}
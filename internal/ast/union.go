@@ -0,0 +1,105 @@
+package ast
+
+import "fmt"
+
+// UnionStrategy is how the JSON encoding of a union tells its options apart.
+type UnionStrategy string
+
+const (
+	// UnionInternal adds a tag property naming the option to the object of the
+	// option: {"type": "Circle", "radius": 1}
+	UnionInternal UnionStrategy = "internal"
+	// UnionAdjacent holds the option in a content property next to the tag:
+	// {"type": "Circle", "value": {"radius": 1}}
+	UnionAdjacent UnionStrategy = "adjacent"
+	// UnionUntagged encodes the option alone, which decoders match against the
+	// options in order: {"radius": 1}
+	UnionUntagged UnionStrategy = "untagged"
+)
+
+// Default property names of tagged union encodings
+const (
+	DefaultUnionTag     = "type"
+	DefaultUnionContent = "value"
+)
+
+// UnionEncoding is the JSON encoding of the values of a union, set with
+// @json.union(adjacent, tag="kind", content="data") or the generators.unions
+// setting of the config file.
+type UnionEncoding struct {
+	Strategy UnionStrategy `json:"strategy"`
+	Tag      string        `json:"tag,omitempty"`     // Property naming the option (default: DefaultUnionTag)
+	Content  string        `json:"content,omitempty"` // Property holding adjacently tagged options (default: DefaultUnionContent)
+}
+
+// Validate reports an unknown strategy and properties the strategy does not use.
+func (e *UnionEncoding) Validate() error {
+	switch e.Strategy {
+	case UnionInternal, UnionAdjacent:
+	case UnionUntagged:
+		if e.Tag != "" {
+			return fmt.Errorf("untagged unions have no tag property")
+		}
+	default:
+		return fmt.Errorf("unknown union encoding %q (valid: internal, adjacent, untagged)", e.Strategy)
+	}
+	if e.Content != "" && e.Strategy != UnionAdjacent {
+		return fmt.Errorf("only adjacently tagged unions have a content property")
+	}
+	if e.Tag != "" && e.Tag == e.ContentProperty() && e.Strategy == UnionAdjacent {
+		return fmt.Errorf("the tag and content properties are both %q", e.Tag)
+	}
+	return nil
+}
+
+// TagProperty returns the property naming the option of a tagged union.
+func (e *UnionEncoding) TagProperty() string {
+	if e.Tag == "" {
+		return DefaultUnionTag
+	}
+	return e.Tag
+}
+
+// ContentProperty returns the property holding the option of an adjacently
+// tagged union.
+func (e *UnionEncoding) ContentProperty() string {
+	if e.Content == "" {
+		return DefaultUnionContent
+	}
+	return e.Content
+}
+
+// Describe returns the name of a strategy in prose, such as internally tagged.
+func (s UnionStrategy) Describe() string {
+	switch s {
+	case UnionInternal:
+		return "internally tagged"
+	case UnionAdjacent:
+		return "adjacently tagged"
+	default:
+		return "untagged"
+	}
+}
+
+// Example returns the shape of the JSON of a value of the union holding option,
+// such as {"type": "Circle", ...}, for documentation.
+func (e *UnionEncoding) Example(option string) string {
+	switch e.Strategy {
+	case UnionInternal:
+		return fmt.Sprintf("{%q: %q, ...}", e.TagProperty(), option)
+	case UnionAdjacent:
+		return fmt.Sprintf("{%q: %q, %q: {...}}", e.TagProperty(), option, e.ContentProperty())
+	default:
+		return "{...}"
+	}
+}
+
+// DefaultUnionEncoding sets the JSON encoding of the unions that do not set one.
+func (s *Schema) DefaultUnionEncoding(encoding UnionEncoding) {
+	for _, union := range s.Unions {
+		if union.Encoding == nil {
+			copied := encoding
+			union.Encoding = &copied
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnionEncoding_Validate(t *testing.T) {
+	tests := []struct {
+		encoding UnionEncoding
+		wantErr  string
+	}{
+		{UnionEncoding{Strategy: UnionInternal, Tag: "kind"}, ""},
+		{UnionEncoding{Strategy: UnionAdjacent, Tag: "kind", Content: "data"}, ""},
+		{UnionEncoding{Strategy: UnionUntagged}, ""},
+		{UnionEncoding{Strategy: "flat"}, `unknown union encoding "flat"`},
+		{UnionEncoding{Strategy: UnionUntagged, Tag: "kind"}, "no tag property"},
+		{UnionEncoding{Strategy: UnionInternal, Content: "data"}, "only adjacently tagged unions"},
+		{UnionEncoding{Strategy: UnionAdjacent, Tag: "value"}, `both "value"`},
+	}
+	for _, tt := range tests {
+		err := tt.encoding.Validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%+v: expected no error, got %v", tt.encoding, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%+v: expected an error containing %q, got %v", tt.encoding, tt.wantErr, err)
+		}
+	}
+}
+
+func TestUnionEncoding_Example(t *testing.T) {
+	adjacent := &UnionEncoding{Strategy: UnionAdjacent, Content: "data"}
+	if got, want := adjacent.Example("Circle"), `{"type": "Circle", "data": {...}}`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	internal := &UnionEncoding{Strategy: UnionInternal, Tag: "kind"}
+	if got, want := internal.Example("Circle"), `{"kind": "Circle", ...}`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestSchema_DefaultUnionEncoding(t *testing.T) {
+	own := &UnionEncoding{Strategy: UnionUntagged}
+	schema := &Schema{Unions: []*Union{{Name: "Shape"}, {Name: "Event", Encoding: own}, {Name: "Result"}}}
+	schema.DefaultUnionEncoding(UnionEncoding{Strategy: UnionAdjacent})
+
+	if schema.Unions[1].Encoding != own {
+		t.Errorf("Expected the encoding set with @json.union to be kept, got %+v", schema.Unions[1].Encoding)
+	}
+	for _, i := range []int{0, 2} {
+		if encoding := schema.Unions[i].Encoding; encoding == nil || encoding.Strategy != UnionAdjacent {
+			t.Errorf("%s: expected the default encoding, got %+v", schema.Unions[i].Name, encoding)
+		}
+	}
+	if schema.Unions[0].Encoding == schema.Unions[2].Encoding {
+		t.Error("Expected every union to get its own copy of the default encoding")
+	}
+}
@@ -0,0 +1,155 @@
+package ast
+
+import (
+	"fmt"
+)
+
+// Validate returns the semantic errors of a schema that parsing alone does not
+// catch, such as for schemas built or modified in code: declarations and members
+// defined twice, field and enum numbers used twice, flags enum values that are
+// not powers of two, references to unknown types, the identifier errors of
+// IdentifierErrors, and the cycles of RequiredCycles. HTTP routes are left to
+// RouteErrors and PathParameterErrors
+func (s *Schema) Validate() []string {
+	var errs []string
+	report := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	registry := NewTypeRegistry()
+	declared := make(map[string]string) // Qualified name -> kind of the first declaration
+	declare := func(kind, namespace, name string) {
+		qualifiedName := registry.qualify(namespace, name)
+		switch other, ok := declared[qualifiedName]; {
+		case ok && other == kind:
+			report("%s %s is declared twice", kind, name)
+			return
+		case ok:
+			report("%s %s is also declared as %s %s", kind, name, other, name)
+			return
+		}
+		declared[qualifiedName] = kind
+	}
+	for _, enum := range s.Enums {
+		declare("enum", enum.Namespace, enum.Name)
+		registry.RegisterEnum(enum)
+	}
+	for _, typ := range s.Types {
+		declare("type", typ.Namespace, typ.Name)
+		registry.RegisterType(typ)
+	}
+	for _, union := range s.Unions {
+		declare("union", union.Namespace, union.Name)
+		registry.RegisterUnion(union)
+	}
+
+	// checkReference reports a reference to a type that is not declared
+	checkReference := func(context, name, namespace string) {
+		if name == "" || IsBuiltinType(name) {
+			return
+		}
+		if _, ok := registry.ResolveType(name, namespace); !ok {
+			report("%s refers to unknown type %s", context, name)
+		}
+	}
+	var checkFieldType func(context string, fieldType *FieldType, namespace string)
+	checkFieldType = func(context string, fieldType *FieldType, namespace string) {
+		if fieldType == nil {
+			report("%s has no type", context)
+			return
+		}
+		if fieldType.IsMap {
+			checkFieldType(context, fieldType.GetMapValueType(), namespace)
+			return
+		}
+		checkReference(context, fieldType.Name, namespace)
+	}
+
+	for _, enum := range s.Enums {
+		names := make(map[string]bool)
+		numbers := make(map[int]string)
+		for _, value := range enum.Values {
+			if names[value.Name] {
+				report("enum value %s.%s is declared twice", enum.Name, value.Name)
+			}
+			names[value.Name] = true
+			if !value.HasNumber {
+				continue
+			}
+			if other, ok := numbers[value.Number]; ok {
+				report("enum value %s.%s reuses number %d of %s", enum.Name, value.Name, value.Number, other)
+				continue
+			}
+			numbers[value.Number] = value.Name
+		}
+		if enum.Flags {
+			for i, number := range enum.ValueNumbers() {
+				if !ValidFlag(number) {
+					report("flags enum value %s.%s must be 0 or a power of two up to %d, got %d", enum.Name, enum.Values[i].Name, MaxFlag, number)
+				}
+			}
+		}
+	}
+
+	for _, typ := range s.Types {
+		names := make(map[string]bool)
+		numbers := make(map[int]*Field)
+		for _, field := range typ.Fields {
+			context := fmt.Sprintf("field %s.%s", typ.Name, field.Name)
+			if names[field.Name] {
+				report("%s is declared twice", context)
+			}
+			names[field.Name] = true
+			if err := checkFieldNumber(typ, field, numbers); err != nil {
+				report("%v", err)
+			}
+
+			checkFieldType(context, field.Type, typ.Namespace)
+			for _, arg := range field.Arguments {
+				checkFieldType(fmt.Sprintf("argument %s of %s", arg.Name, context), arg.Type, typ.Namespace)
+			}
+		}
+	}
+
+	for _, union := range s.Unions {
+		for _, option := range union.Options {
+			checkReference("union "+union.Name, option, union.Namespace)
+		}
+	}
+
+	services := make(map[string]bool)
+	for _, service := range s.Services {
+		qualifiedName := registry.qualify(service.Namespace, service.Name)
+		if services[qualifiedName] {
+			report("service %s is declared twice", service.Name)
+		}
+		services[qualifiedName] = true
+
+		methods := make(map[string]*Method)
+		for _, method := range service.Methods {
+			context := fmt.Sprintf("method %s.%s", service.Name, method.Name)
+			if methods[method.Name] != nil {
+				report("%s is declared twice", context)
+			}
+			methods[method.Name] = method
+			checkReference(context, method.InputType, service.Namespace)
+			checkReference(context, method.OutputType, service.Namespace)
+		}
+
+		// A callback belongs to a method of its service that the API serves
+		for _, method := range service.Methods {
+			if !method.Webhook.IsCallback() {
+				continue
+			}
+			switch on := methods[method.Webhook.On]; {
+			case on == nil:
+				report("method %s.%s is a callback of unknown method %s", service.Name, method.Name, method.Webhook.On)
+			case on.IsWebhook():
+				report("method %s.%s is a callback of webhook %s, which the API does not serve", service.Name, method.Name, on.Name)
+			}
+		}
+	}
+
+	errs = append(errs, s.IdentifierErrors()...)
+	return append(errs, s.RequiredCycles()...)
+}
@@ -0,0 +1,85 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	schema := extendsSchema()
+	if err := schema.ResolveExtends(); err != nil {
+		t.Fatalf("ResolveExtends failed: %v", err)
+	}
+	schema.Enums = []*Enum{{Name: "Role", Namespace: "com.example", Values: []*EnumValue{{Name: "ADMIN"}}}}
+	schema.Unions = []*Union{{Name: "Principal", Namespace: "com.example", Options: []string{"User", "Admin"}}}
+	schema.Services = []*Service{{
+		Name:      "UserService",
+		Namespace: "com.example",
+		Methods:   []*Method{{Name: "GetUser", InputType: "User", OutputType: "audit.Auditable"}, {Name: "Ping"}},
+	}}
+
+	if errs := schema.Validate(); len(errs) != 0 {
+		t.Errorf("Expected a valid schema, got %v", errs)
+	}
+}
+
+func TestSchema_Validate_Errors(t *testing.T) {
+	schema := &Schema{
+		Namespace: "api",
+		Enums: []*Enum{
+			{Name: "Status", Namespace: "api", Values: []*EnumValue{
+				{Name: "ACTIVE", Number: 1, HasNumber: true},
+				{Name: "ACTIVE"},
+				{Name: "CLOSED", Number: 1, HasNumber: true},
+			}},
+			{Name: "Permission", Namespace: "api", Flags: true, Values: []*EnumValue{
+				{Name: "READ"},
+				{Name: "ALL", Number: 3, HasNumber: true},
+			}},
+		},
+		Types: []*Type{
+			{Name: "Status", Namespace: "api"},
+			{Name: "User", Namespace: "api", Fields: []*Field{
+				{Name: "id", Type: &FieldType{Name: "string"}, Number: 1, HasNumber: true},
+				{Name: "id", Type: &FieldType{Name: "string"}},
+				{Name: "team", Type: &FieldType{Name: "Team"}, Number: 1, HasNumber: true},
+				{Name: "labels", Type: &FieldType{Name: "map", IsMap: true, MapKey: "string", MapValueType: &FieldType{Name: "Label"}}},
+				{Name: "posts", Type: &FieldType{Name: "string"}, Arguments: []*FieldArgument{{Name: "after", Type: &FieldType{Name: "Cursor"}}}},
+				{Name: "broken"},
+			}},
+		},
+		Unions: []*Union{{Name: "Result", Namespace: "api", Options: []string{"User", "Error"}}},
+		Services: []*Service{
+			{Name: "UserService", Namespace: "api", Methods: []*Method{
+				{Name: "GetUser", InputType: "GetUserRequest", OutputType: "User"},
+				{Name: "GetUser", InputType: "User", OutputType: "User"},
+				{Name: "UserSettled", Webhook: &Webhook{Name: "userSettled", On: "SaveUser", URL: "{$request.body#/callbackUrl}"}},
+				{Name: "UserDeleted", Webhook: &Webhook{Name: "userDeleted"}},
+				{Name: "UserRestored", Webhook: &Webhook{Name: "userRestored", On: "UserDeleted", URL: "{$request.body#/callbackUrl}"}},
+			}},
+			{Name: "UserService", Namespace: "api"},
+		},
+	}
+
+	expected := []string{
+		"type Status is also declared as enum Status",
+		"enum value Status.ACTIVE is declared twice",
+		"enum value Status.CLOSED reuses number 1 of ACTIVE",
+		"flags enum value Permission.ALL must be 0 or a power of two up to 1073741824, got 3",
+		"field User.id is declared twice",
+		"field User.team reuses field number 1 of field id",
+		"field User.team refers to unknown type Team",
+		"field User.labels refers to unknown type Label",
+		"argument after of field User.posts refers to unknown type Cursor",
+		"field User.broken has no type",
+		"union Result refers to unknown type Error",
+		"method UserService.GetUser refers to unknown type GetUserRequest",
+		"method UserService.GetUser is declared twice",
+		"method UserService.UserSettled is a callback of unknown method SaveUser",
+		"method UserService.UserRestored is a callback of webhook UserDeleted, which the API does not serve",
+		"service UserService is declared twice",
+	}
+	if errs := schema.Validate(); strings.Join(errs, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected errors:\n%s\nwant:\n%s", strings.Join(errs, "\n"), strings.Join(expected, "\n"))
+	}
+}
@@ -0,0 +1,99 @@
+package ast
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares two API versions such as v2, 2.1, or "2.1.0",
+// returning -1, 0, or +1 when a is older than, the same as, or newer than b.
+// A leading v is ignored and missing parts count as 0, so v2 equals 2.0.0.
+// Parts that are not numbers, as in v1beta1, are compared as text.
+func CompareVersions(a, b string) int {
+	aParts := versionParts(a)
+	bParts := versionParts(b)
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		aPart, bPart := "0", "0"
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+		aNumber, aErr := strconv.Atoi(aPart)
+		bNumber, bErr := strconv.Atoi(bPart)
+		switch {
+		case aErr == nil && bErr == nil && aNumber != bNumber:
+			if aNumber < bNumber {
+				return -1
+			}
+			return 1
+		case (aErr != nil || bErr != nil) && aPart != bPart:
+			return strings.Compare(aPart, bPart)
+		}
+	}
+	return 0
+}
+
+// versionParts splits a version at its dots, without a leading v
+func versionParts(version string) []string {
+	version = strings.TrimSpace(version)
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	return strings.Split(version, ".")
+}
+
+// ActiveIn reports whether an element added in since, from @since, and last
+// part of the API in until, from @until, is part of an API version. Empty
+// bounds are open.
+func ActiveIn(since, until, version string) bool {
+	if since != "" && CompareVersions(version, since) < 0 {
+		return false
+	}
+	return until == "" || CompareVersions(version, until) <= 0
+}
+
+// Versions returns the API versions named by the @since and @until
+// annotations of a schema, oldest first.
+func (s *Schema) Versions() []string {
+	seen := make(map[string]bool)
+	var versions []string
+	add := func(names ...string) {
+		for _, name := range names {
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			versions = append(versions, name)
+		}
+	}
+	addAnnotations := func(annotations *FormatAnnotations) {
+		if annotations != nil {
+			add(annotations.Since, annotations.Until)
+		}
+	}
+
+	for _, typ := range s.Types {
+		addAnnotations(typ.Annotations)
+		for _, field := range typ.Fields {
+			add(field.Since, field.Until)
+		}
+	}
+	for _, enum := range s.Enums {
+		addAnnotations(enum.Annotations)
+	}
+	for _, union := range s.Unions {
+		addAnnotations(union.Annotations)
+	}
+	for _, service := range s.Services {
+		addAnnotations(service.Annotations)
+		for _, method := range service.Methods {
+			addAnnotations(method.Annotations)
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return CompareVersions(versions[i], versions[j]) < 0
+	})
+	return versions
+}
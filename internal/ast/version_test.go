@@ -0,0 +1,72 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"v1", "v2", -1},
+		{"v2", "v1", 1},
+		{"v2", "2.0.0", 0},
+		{"V2", "v2", 0},
+		{"1.9.0", "1.10.0", -1},
+		{"2.1", "2", 1},
+		{"v1beta1", "v1", 1},
+		{"v1alpha1", "v1beta1", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := CompareVersions(tt.a, tt.b); got != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestActiveIn(t *testing.T) {
+	tests := []struct {
+		since, until, version string
+		expected              bool
+	}{
+		{"", "", "v1", true},
+		{"v2", "", "v1", false},
+		{"v2", "", "v2", true},
+		{"v2", "", "v3", true},
+		{"", "v2", "v2", true},
+		{"", "v2", "v3", false},
+		{"v2", "v3", "2.5", true},
+		{"v2", "v3", "v4", false},
+	}
+
+	for _, tt := range tests {
+		if got := ActiveIn(tt.since, tt.until, tt.version); got != tt.expected {
+			t.Errorf("ActiveIn(%q, %q, %q): expected %v, got %v", tt.since, tt.until, tt.version, tt.expected, got)
+		}
+	}
+}
+
+func TestSchema_Versions(t *testing.T) {
+	schema := &Schema{
+		Types: []*Type{
+			{Name: "User", Annotations: &FormatAnnotations{Since: "v3"}, Fields: []*Field{
+				{Name: "name", Until: "v1"},
+				{Name: "displayName", Since: "v2"},
+			}},
+		},
+		Services: []*Service{
+			{Name: "UserService", Methods: []*Method{
+				{Name: "GetUser", Annotations: &FormatAnnotations{Since: "v2", Until: "v10"}},
+			}},
+		},
+	}
+
+	if got := strings.Join(schema.Versions(), ","); got != "v1,v2,v3,v10" {
+		t.Errorf("Expected v1,v2,v3,v10, got %s", got)
+	}
+}
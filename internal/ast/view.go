@@ -0,0 +1,128 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ViewTypeName returns the name of the type derived for a view of a type:
+// UserSummary for the summary view of User, UserPublicCard for public_card.
+func ViewTypeName(typeName, view string) string {
+	var sb strings.Builder
+	sb.WriteString(typeName)
+	for _, part := range strings.Split(view, "_") {
+		if part != "" {
+			sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return sb.String()
+}
+
+// ResolveViews derives a type for every view named by the @view annotations of
+// the fields of a type, holding the fields of that view in declaration order,
+// and points the output type of methods that select a view at the derived type.
+// View types follow the type they are derived from. Inherited fields must have
+// been copied with ResolveExtends. It can be called again after the schema
+// changes: previously derived view types are replaced.
+func (s *Schema) ResolveViews() error {
+	registry := NewTypeRegistry()
+	var types []*Type
+	for _, typ := range s.Types {
+		if typ.ViewOf == "" {
+			registry.RegisterType(typ)
+			types = append(types, typ)
+		}
+	}
+	for _, enum := range s.Enums {
+		registry.RegisterEnum(enum)
+	}
+	for _, union := range s.Unions {
+		registry.RegisterUnion(union)
+	}
+
+	derived := make(map[string]map[string]*Type) // Qualified type name -> view -> view type
+	var resolved []*Type
+	for _, typ := range types {
+		resolved = append(resolved, typ)
+		baseName := typ.Namespace + "." + typ.Name
+		for _, field := range typ.Fields {
+			for _, view := range field.Views {
+				viewType := derived[baseName][view]
+				if viewType == nil {
+					name := ViewTypeName(typ.Name, view)
+					if registry.has(typ.Namespace + "." + name) {
+						return fmt.Errorf("view %s of type %s collides with %s", view, typ.Name, name)
+					}
+					viewType = &Type{
+						Name:      name,
+						Namespace: typ.Namespace,
+						Doc:       &Documentation{General: fmt.Sprintf("The %s view of %s.", view, typ.Name)},
+						ViewOf:    baseName,
+						View:      view,
+					}
+					if derived[baseName] == nil {
+						derived[baseName] = make(map[string]*Type)
+					}
+					derived[baseName][view] = viewType
+					resolved = append(resolved, viewType)
+				}
+				copied := *field
+				copied.Views = nil
+				copied.InheritedFrom = ""
+				viewType.Fields = append(viewType.Fields, &copied)
+			}
+		}
+	}
+	s.Types = resolved
+
+	viewTypes := make(map[string]*Type) // Qualified view type name -> view type
+	for _, typ := range resolved {
+		if typ.ViewOf != "" {
+			viewTypes[typ.Namespace+"."+typ.Name] = typ
+			if s.TypeRegistry != nil {
+				s.TypeRegistry.RegisterType(typ)
+			}
+		}
+	}
+
+	for _, service := range s.Services {
+		for _, method := range service.Methods {
+			if method.View == "" {
+				continue
+			}
+			// A long-running method selects the view of the response its operation holds
+			output := &method.OutputType
+			if method.LongRunning != nil && method.OutputType == OperationTypeName(method.Name) {
+				output = &method.LongRunning.Response
+			}
+			if *output == "" {
+				return fmt.Errorf("method %s.%s selects view %s, but returns nothing", service.Name, method.Name, method.View)
+			}
+			baseName, ok := registry.ResolveType(*output, service.Namespace)
+			if !ok {
+				// A method resolved before responds with a view type already; select from its base
+				qualifiedName := *output
+				if !strings.Contains(qualifiedName, ".") {
+					qualifiedName = service.Namespace + "." + qualifiedName
+				}
+				if viewType, isView := viewTypes[qualifiedName]; isView {
+					baseName, ok = viewType.ViewOf, true
+				}
+			}
+			base := registry.Types[baseName]
+			if !ok || base == nil {
+				return fmt.Errorf("method %s.%s selects view %s, but %s is not a type", service.Name, method.Name, method.View, *output)
+			}
+			viewType := derived[baseName][method.View]
+			if viewType == nil {
+				return fmt.Errorf("method %s.%s selects view %s, but no field of %s is in it", service.Name, method.Name, method.View, base.Name)
+			}
+			prefix := ""
+			if i := strings.LastIndex(*output, "."); i >= 0 {
+				prefix = (*output)[:i+1]
+			}
+			*output = prefix + viewType.Name
+		}
+	}
+	return nil
+}
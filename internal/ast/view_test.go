@@ -0,0 +1,126 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+// viewSchema returns a schema where User has summary and card views and ListUsers selects the summary
+func viewSchema() *Schema {
+	return &Schema{
+		Namespace: "shop",
+		Types: []*Type{
+			{
+				Name:      "User",
+				Namespace: "shop",
+				Fields: []*Field{
+					{Name: "id", Type: &FieldType{Name: "string"}, Number: 1, HasNumber: true, Views: []string{"summary"}},
+					{Name: "name", Type: &FieldType{Name: "string"}, Number: 2, HasNumber: true, Views: []string{"summary", "card"}},
+					{Name: "bio", Type: &FieldType{Name: "string"}, Number: 3, HasNumber: true},
+				},
+			},
+			{Name: "ListUsersRequest", Namespace: "shop"},
+		},
+		Services: []*Service{
+			{
+				Name:      "UserService",
+				Namespace: "shop",
+				Methods: []*Method{
+					{Name: "GetUser", InputType: "ListUsersRequest", OutputType: "User"},
+					{Name: "ListUsers", InputType: "ListUsersRequest", OutputType: "shop.User", View: "summary"},
+				},
+			},
+		},
+	}
+}
+
+func TestViewTypeName(t *testing.T) {
+	if got := ViewTypeName("User", "summary"); got != "UserSummary" {
+		t.Errorf("Expected UserSummary, got %s", got)
+	}
+	if got := ViewTypeName("User", "public_card"); got != "UserPublicCard" {
+		t.Errorf("Expected UserPublicCard, got %s", got)
+	}
+}
+
+func TestSchema_ResolveViews(t *testing.T) {
+	schema := viewSchema()
+	if err := schema.ResolveViews(); err != nil {
+		t.Fatalf("ResolveViews failed: %v", err)
+	}
+
+	var names []string
+	for _, typ := range schema.Types {
+		names = append(names, typ.Name)
+	}
+	if got := strings.Join(names, ","); got != "User,UserSummary,UserCard,ListUsersRequest" {
+		t.Fatalf("Expected view types after User, got %s", got)
+	}
+
+	summary, card := schema.Types[1], schema.Types[2]
+	if got := fieldNames(summary); got != "id,name" || summary.ViewOf != "shop.User" || summary.View != "summary" {
+		t.Errorf("Unexpected summary view %s of %s (%s)", got, summary.ViewOf, summary.View)
+	}
+	if got := fieldNames(card); got != "name" || card.Fields[0].Number != 2 || card.Fields[0].Views != nil {
+		t.Errorf("Expected card to keep the number of name without views, got %s %+v", got, card.Fields[0])
+	}
+
+	methods := schema.Services[0].Methods
+	if methods[0].OutputType != "User" || methods[1].OutputType != "shop.UserSummary" {
+		t.Errorf("Unexpected output types %s, %s", methods[0].OutputType, methods[1].OutputType)
+	}
+
+	// Resolving again replaces the view types and selects from the base type of a view
+	schema.Types[0].Fields[2].Views = []string{"summary"}
+	if err := schema.ResolveViews(); err != nil {
+		t.Fatalf("Second ResolveViews failed: %v", err)
+	}
+	if len(schema.Types) != 4 || fieldNames(schema.Types[1]) != "id,name,bio" {
+		t.Errorf("Expected the summary view to be replaced, got %d types", len(schema.Types))
+	}
+	if methods[1].OutputType != "shop.UserSummary" {
+		t.Errorf("Expected ListUsers to keep the summary view, got %s", methods[1].OutputType)
+	}
+}
+
+func TestSchema_ResolveViewsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *Schema)
+		want   string
+	}{
+		{
+			name:   "unknown view",
+			modify: func(s *Schema) { s.Services[0].Methods[1].View = "detail" },
+			want:   "method UserService.ListUsers selects view detail, but no field of User is in it",
+		},
+		{
+			name:   "not a type",
+			modify: func(s *Schema) { s.Services[0].Methods[1].OutputType = "Missing" },
+			want:   "method UserService.ListUsers selects view summary, but Missing is not a type",
+		},
+		{
+			name:   "no output",
+			modify: func(s *Schema) { s.Services[0].Methods[1].OutputType = "" },
+			want:   "method UserService.ListUsers selects view summary, but returns nothing",
+		},
+		{
+			name: "name collision",
+			modify: func(s *Schema) {
+				s.Types = append(s.Types, &Type{Name: "UserCard", Namespace: "shop"})
+			},
+			want: "view card of type User collides with UserCard",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := viewSchema()
+			tt.modify(schema)
+			err := schema.ResolveViews()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
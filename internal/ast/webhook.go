@@ -0,0 +1,32 @@
+package ast
+
+import "strings"
+
+// Webhook marks a method as a request the API sends to its clients, declared with
+// @webhook("paymentCompleted"). The input of the method is the payload the API
+// sends, and its output is what the receiver answers.
+type Webhook struct {
+	Name string `json:"name"`
+	On   string `json:"on,omitempty"`  // Method that registers the callback, from on=
+	URL  string `json:"url,omitempty"` // Runtime expression of the callback URL, from url=
+}
+
+// IsCallback reports whether the webhook is a callback registered by a call to
+// another method of the service, rather than a subscription of the API.
+func (w *Webhook) IsCallback() bool {
+	return w != nil && w.On != ""
+}
+
+// IsWebhook reports whether the API sends the method to its clients rather than serving it.
+func (m *Method) IsWebhook() bool {
+	return m.Webhook != nil
+}
+
+// WebhookHTTPMethod returns the HTTP method the API sends a webhook with:
+// @http.method, or POST.
+func (m *Method) WebhookHTTPMethod() string {
+	if m.HTTPMethod != "" {
+		return strings.ToLower(m.HTTPMethod)
+	}
+	return "post"
+}
@@ -0,0 +1,233 @@
+// Package compile compiles a schema held in memory into the files of its output
+// formats, without touching the file system: imports resolve against files
+// passed with the schema and the standard library only. It is the core of
+// typemux serve and of the WebAssembly build.
+package compile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/annotations"
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/diagnostic"
+	"github.com/rasmartins/typemux/internal/docgen"
+	"github.com/rasmartins/typemux/internal/generator"
+	"github.com/rasmartins/typemux/internal/graph"
+	"github.com/rasmartins/typemux/internal/loader"
+	"github.com/rasmartins/typemux/internal/naming"
+	"github.com/rasmartins/typemux/internal/stdlib"
+)
+
+// DefaultFormats are the formats generated when the options name none.
+var DefaultFormats = []string{"graphql", "protobuf", "openapi", "go"}
+
+// rootPath is the path the schema is loaded as; the files passed with it are
+// placed next to it
+const rootPath = "/typemux/schema.typemux"
+
+// Options configures a compilation.
+type Options struct {
+	// Files holds the content of the files the schema imports, by path relative
+	// to the schema, such as common/types.typemux
+	Files map[string]string `json:"files,omitempty"`
+
+	// Annotations are YAML annotation documents merged into the schema, in order
+	Annotations []string `json:"annotations,omitempty"`
+
+	// Formats to generate, as for -format (default: DefaultFormats)
+	Formats []string `json:"formats,omitempty"`
+
+	// Pruning of the schema, as for -only-service, -root-type,
+	// -exclude-service, and -exclude-type
+	OnlyServices    []string `json:"only_services,omitempty"`
+	RootTypes       []string `json:"root_types,omitempty"`
+	ExcludeServices []string `json:"exclude_services,omitempty"`
+	ExcludeTypes    []string `json:"exclude_types,omitempty"`
+
+	// Naming is the naming policy of field names, as for -naming
+	Naming string `json:"naming,omitempty"`
+
+	// Limits bounds the size and complexity of the schema and its imports
+	Limits loader.Limits `json:"-"`
+}
+
+// Result holds the files generated from a schema.
+type Result struct {
+	// Files holds the generated files by slash-separated path
+	Files map[string]string `json:"files"`
+
+	// Warnings of the parser and the naming policy
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Compile parses a schema with its imports and annotations, validates and
+// prunes it, and generates its formats. Errors are diagnostic errors whose
+// kind tells invalid options (Failure), schemas (Parse, Validation), and
+// generator failures (Generation) apart.
+func Compile(ctx context.Context, schemaContent string, opts Options) (*Result, error) {
+	policy, err := namingPolicy(opts.Naming)
+	if err != nil {
+		return nil, diagnostic.New(diagnostic.Failure, err)
+	}
+	formats := opts.Formats
+	if len(formats) == 0 {
+		formats = DefaultFormats
+	}
+	generators := make([]generator.Generator, 0, len(formats))
+	for _, format := range formats {
+		gen, err := lookup(format)
+		if err != nil {
+			return nil, diagnostic.New(diagnostic.Failure, err)
+		}
+		generators = append(generators, gen)
+	}
+
+	result := &Result{Files: make(map[string]string)}
+	schemaLoader := &loader.Loader{
+		Read:   readFiles(opts.Files),
+		Limits: opts.Limits,
+		Check: func(path string, _ *ast.Schema, warnings []string) error {
+			for _, warning := range warnings {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", RelativePath(path), warning))
+			}
+			return nil
+		},
+	}
+	schema, err := schemaLoader.LoadContent(rootPath, []byte(schemaContent))
+	if err != nil {
+		return nil, err
+	}
+	if err := mergeAnnotations(schema, opts.Annotations); err != nil {
+		return nil, err
+	}
+	if errs := schema.Validate(); len(errs) > 0 {
+		found := make([]diagnostic.Diagnostic, len(errs))
+		for i, msg := range errs {
+			found[i] = diagnostic.Diagnostic{Severity: diagnostic.SeverityError, Message: msg}
+		}
+		return nil, diagnostic.New(diagnostic.Validation, fmt.Errorf("validation errors:\n%s", strings.Join(errs, "\n")), found...)
+	}
+
+	if !policy.IsZero() {
+		result.Warnings = append(result.Warnings, policy.Lint(schema)...)
+		if errs := policy.Collisions(schema); len(errs) > 0 {
+			return nil, diagnostic.New(diagnostic.Validation, fmt.Errorf("naming collisions:\n%s", strings.Join(errs, "\n")))
+		}
+		policy.Apply(schema)
+	}
+	if len(opts.ExcludeServices) > 0 || len(opts.ExcludeTypes) > 0 {
+		if schema, err = graph.Exclude(schema, opts.ExcludeServices, opts.ExcludeTypes); err != nil {
+			return nil, diagnostic.New(diagnostic.Validation, err)
+		}
+	}
+	if len(opts.OnlyServices) > 0 || len(opts.RootTypes) > 0 {
+		if schema, err = graph.Trim(schema, opts.OnlyServices, opts.RootTypes); err != nil {
+			return nil, diagnostic.New(diagnostic.Validation, err)
+		}
+	}
+
+	for i, gen := range generators {
+		files, err := gen.Generate(ctx, schema, generator.Options{})
+		if err != nil {
+			return nil, diagnostic.New(diagnostic.Generation, fmt.Errorf("generating %s: %w", formats[i], err))
+		}
+		for name, content := range files {
+			result.Files[name] = string(content)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, diagnostic.New(diagnostic.Generation, fmt.Errorf("generation did not finish in time: %w", err))
+	}
+	return result, nil
+}
+
+// RelativePath returns a path of a compiled file relative to the schema.
+func RelativePath(file string) string {
+	return strings.TrimPrefix(strings.ReplaceAll(file, "\\", "/"), path.Dir(rootPath)+"/")
+}
+
+// Failure returns the message and diagnostics of a compile error, with the
+// paths of files relative to the schema.
+func Failure(err error) (string, []diagnostic.Diagnostic) {
+	message := strings.ReplaceAll(err.Error(), path.Dir(rootPath)+"/", "")
+	var diagErr *diagnostic.Error
+	if !errors.As(err, &diagErr) || len(diagErr.Diagnostics) == 0 {
+		return message, nil
+	}
+	diagnostics := make([]diagnostic.Diagnostic, len(diagErr.Diagnostics))
+	for i, found := range diagErr.Diagnostics {
+		found.File = RelativePath(found.File)
+		diagnostics[i] = found
+	}
+	return message, diagnostics
+}
+
+// lookup returns the generator of a format, including the documentation formats
+func lookup(format string) (generator.Generator, error) {
+	if gen, err := docgen.Lookup(format); err == nil {
+		return gen, nil
+	}
+	return generator.Lookup(format)
+}
+
+// namingPolicy returns the naming policy of a name
+func namingPolicy(name string) (naming.Policy, error) {
+	switch name {
+	case "":
+		return naming.Policy{}, nil
+	case "standard":
+		return naming.Standard, nil
+	default:
+		return naming.Policy{}, fmt.Errorf("unknown naming policy %q (valid: standard)", name)
+	}
+}
+
+// readFiles returns a loader Read function serving the standard library and
+// the given files, and nothing else
+func readFiles(files map[string]string) func(string) ([]byte, error) {
+	byPath := make(map[string][]byte, len(files))
+	for name, content := range files {
+		byPath[path.Join(path.Dir(rootPath), name)] = []byte(content)
+	}
+	return func(file string) ([]byte, error) {
+		if stdlib.IsImport(file) {
+			return stdlib.Read(file)
+		}
+		if content, ok := byPath[path.Clean(strings.ReplaceAll(file, "\\", "/"))]; ok {
+			return content, nil
+		}
+		return nil, diagnostic.New(diagnostic.Parse, fmt.Errorf("imported file %s was not passed with the schema", RelativePath(file)))
+	}
+}
+
+// mergeAnnotations validates YAML annotation documents against a schema and
+// merges them into it
+func mergeAnnotations(schema *ast.Schema, documents []string) error {
+	if len(documents) == 0 {
+		return nil
+	}
+	merged, err := annotations.MergeYAMLAnnotationsFromContent(documents)
+	if err != nil {
+		return diagnostic.New(diagnostic.Parse, fmt.Errorf("failed to merge annotations: %w", err))
+	}
+	validator := annotations.NewValidator(schema)
+	if errs := validator.Validate(merged); len(errs) > 0 {
+		found := make([]diagnostic.Diagnostic, len(errs))
+		for i, err := range errs {
+			found[i] = diagnostic.Diagnostic{Severity: diagnostic.SeverityError, Message: err.Error()}
+		}
+		return diagnostic.New(diagnostic.Validation, errors.New(strings.TrimSuffix(validator.FormatErrors(), "\n")), found...)
+	}
+	annotations.NewMerger(merged).Merge(schema)
+
+	for _, resolve := range []func() error{schema.ResolveExtends, schema.ResolveViews, schema.ResolveResources, schema.ResolveBatches, schema.ResolveLongRunning} {
+		if err := resolve(); err != nil {
+			return diagnostic.New(diagnostic.Validation, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package compile
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/diagnostic"
+	"github.com/rasmartins/typemux/internal/loader"
+)
+
+func TestCompile(t *testing.T) {
+	result, err := Compile(context.Background(), `namespace api
+import "common/audit.typemux"
+
+type User {
+  user_id: string = 1 @required
+  audit: common.Audit = 2
+}`, Options{
+		Files:       map[string]string{"common/audit.typemux": "namespace common\n\ntype Audit {\n  at: timestamp = 1\n}\n"},
+		Annotations: []string{"types:\n  User:\n    graphql:\n      name: Account\n"},
+		Formats:     []string{"graphql"},
+		Naming:      "standard",
+	})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	graphql := result.Files["schema.graphql"]
+	if !strings.Contains(graphql, "type Account {") || !strings.Contains(graphql, "userId: String!") || !strings.Contains(graphql, "type Audit {") {
+		t.Errorf("Expected the annotated and renamed GraphQL schema with the imported type, got:\n%s", graphql)
+	}
+	if len(result.Files) != 1 {
+		t.Errorf("Expected only the GraphQL file, got %d file(s)", len(result.Files))
+	}
+}
+
+func TestCompile_DefaultFormats(t *testing.T) {
+	result, err := Compile(context.Background(), "type User {\n  id: string = 1\n}\n", Options{})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	for _, name := range []string{"schema.graphql", "schema.proto", "openapi.yaml", "types.go"} {
+		if _, ok := result.Files[name]; !ok {
+			t.Errorf("Expected %s to be generated", name)
+		}
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		opts   Options
+		kind   diagnostic.Kind
+		want   string
+	}{
+		{"unknown format", "type A {}", Options{Formats: []string{"cobol"}}, diagnostic.Failure, "cobol"},
+		{"unknown naming", "type A {}", Options{Naming: "kebab"}, diagnostic.Failure, "unknown naming policy"},
+		{"missing import", "import \"other.typemux\"\ntype A {}", Options{}, diagnostic.Parse, "other.typemux was not passed with the schema"},
+		{"escaping import", "import \"../../etc/passwd\"\ntype A {}", Options{}, diagnostic.Parse, "was not passed with the schema"},
+		{"invalid schema", "type A {\n  b: Missing = 1\n}", Options{}, diagnostic.Validation, "Missing"},
+		{"naming collision", "type A {\n  totalAmount: string = 1\n  total_amount: string = 2\n}", Options{Naming: "standard"}, diagnostic.Validation, "have the same proto name total_amount"},
+		{"invalid annotations", "type A {\n  b: string = 1\n}", Options{Annotations: []string{"types:\n  Nope:\n    graphql:\n      name: X\n"}}, diagnostic.Validation, "Nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(context.Background(), tt.schema, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.want, err)
+			}
+			if diagnostic.KindOf(err) != tt.kind {
+				t.Errorf("Expected kind %d, got %d", tt.kind, diagnostic.KindOf(err))
+			}
+			if message, _ := Failure(err); strings.Contains(message, "/typemux/") {
+				t.Errorf("Expected paths relative to the schema, got %s", message)
+			}
+		})
+	}
+}
+
+func TestCompile_Limits(t *testing.T) {
+	_, err := Compile(context.Background(), "type A {\n  b: string = 1\n  c: string = 2\n}\n", Options{Limits: loader.Limits{MaxFields: 1}})
+	if !errors.Is(err, loader.ErrLimitExceeded) {
+		t.Fatalf("Expected the schema to exceed the field limit, got %v", err)
+	}
+	message, diagnostics := Failure(err)
+	if !strings.HasPrefix(message, "schema.typemux: limit exceeded") {
+		t.Errorf("Expected the message to name the schema file, got %s", message)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].File != "schema.typemux" {
+		t.Errorf("Expected one diagnostic of schema.typemux, got %+v", diagnostics)
+	}
+}
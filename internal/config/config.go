@@ -3,8 +3,13 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
 
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/naming"
 	"gopkg.in/yaml.v3"
 )
 
@@ -21,6 +26,59 @@ type Config struct {
 
 	// Generator-specific settings
 	Generators GeneratorConfig `yaml:"generators,omitempty"`
+
+	// Schemas compiled in one invocation, each with its own input and output
+	// (instead of input.schema); they share the generator settings
+	Schemas []SchemaConfig `yaml:"schemas,omitempty"`
+
+	// Profiles generated from input.schema in one invocation, such as a public
+	// OpenAPI spec and an internal Protobuf set, each with its own selection of
+	// services and types, annotation overlays, and output
+	Profiles []ProfileConfig `yaml:"profiles,omitempty"`
+
+	// Commands typemux verify-build runs on the generated files, instead of
+	// the default go vet, protoc, graphql-schema-linter, and Spectral checks
+	Verify []VerifyCheckConfig `yaml:"verify,omitempty"`
+
+	// Size and complexity limits of the schema files, for untrusted input
+	Limits LimitsConfig `yaml:"limits,omitempty"`
+}
+
+// SchemaConfig is one schema of a batch configuration
+type SchemaConfig struct {
+	// Name of the profile the entry was made from, if any
+	Name string `yaml:"-"`
+
+	// Input configuration of this schema
+	Input InputConfig `yaml:"input"`
+
+	// Output configuration of this schema; formats default to output.formats
+	Output OutputConfig `yaml:"output"`
+}
+
+// ProfileConfig is a named variant of the schema of a configuration. Its lists
+// add to those of input.
+type ProfileConfig struct {
+	// Name of the profile (required), used to select it with -profile
+	Name string `yaml:"name"`
+
+	// Annotation files applied after input.annotations
+	Annotations []string `yaml:"annotations,omitempty"`
+
+	// Only generate these services and the types they reference
+	OnlyServices []string `yaml:"only_services,omitempty"`
+
+	// Keep these types and everything they reference when pruning the schema
+	RootTypes []string `yaml:"root_types,omitempty"`
+
+	// Leave out these services and the types only they reference
+	ExcludeServices []string `yaml:"exclude_services,omitempty"`
+
+	// Leave out these types, which nothing else may reference
+	ExcludeTypes []string `yaml:"exclude_types,omitempty"`
+
+	// Output configuration of this profile; formats default to output.formats
+	Output OutputConfig `yaml:"output"`
 }
 
 // InputConfig defines input sources
@@ -30,6 +88,21 @@ type InputConfig struct {
 
 	// Additional annotation files
 	Annotations []string `yaml:"annotations,omitempty"`
+
+	// Only generate these services and the types they reference
+	OnlyServices []string `yaml:"only_services,omitempty"`
+
+	// Keep these types and everything they reference when pruning the schema
+	RootTypes []string `yaml:"root_types,omitempty"`
+
+	// Leave out these services and the types only they reference
+	ExcludeServices []string `yaml:"exclude_services,omitempty"`
+
+	// Leave out these types, which nothing else may reference
+	ExcludeTypes []string `yaml:"exclude_types,omitempty"`
+
+	// Lock file that keeps field numbers stable (e.g., typemux.lock)
+	LockFile string `yaml:"lock_file,omitempty"`
 }
 
 // OutputConfig defines output settings
@@ -40,8 +113,20 @@ type OutputConfig struct {
 	// Formats to generate (graphql, protobuf, openapi, or all)
 	Formats []string `yaml:"formats"`
 
+	// API versions to generate, each into a subdirectory named after it, from
+	// the @since and @until annotations of the schema, or [all] for every
+	// version they name
+	APIVersions []string `yaml:"api_versions,omitempty"`
+
 	// Clean output directory before generation
 	Clean bool `yaml:"clean,omitempty"`
+
+	// File whose contents are prepended as a comment to every generated file,
+	// e.g. a license header
+	HeaderFile string `yaml:"header_file,omitempty"`
+
+	// Stamp the schema version, Git commit, and content hash into generated files
+	Stamp bool `yaml:"stamp,omitempty"`
 }
 
 // GeneratorConfig holds generator-specific configurations
@@ -54,12 +139,78 @@ type GeneratorConfig struct {
 
 	// OpenAPI-specific settings
 	OpenAPI *OpenAPIConfig `yaml:"openapi,omitempty"`
+
+	// Go-specific settings
+	Go *GoConfig `yaml:"go,omitempty"`
+
+	// Java-specific settings
+	Java *JavaConfig `yaml:"java,omitempty"`
+
+	// C#-specific settings
+	CSharp *CSharpConfig `yaml:"csharp,omitempty"`
+
+	// Directory of text/template overrides, with a subdirectory per format
+	Templates string `yaml:"templates,omitempty"`
+
+	// Naming conventions of generated field names
+	Naming *NamingConfig `yaml:"naming,omitempty"`
+
+	// JSON encoding of the unions that do not set one with @json.union
+	Unions *UnionsConfig `yaml:"unions,omitempty"`
+
+	// JSON encoding of int64 and uint64 values in the GraphQL, OpenAPI, Go, and
+	// documentation output: number (default), string, or scalar
+	Int64 string `yaml:"int64,omitempty"`
 }
 
 // GraphQLConfig holds GraphQL generator settings
 type GraphQLConfig struct {
 	// Output filename (default: schema.graphql)
 	Filename string `yaml:"filename,omitempty"`
+
+	// Map builtin types to custom GraphQL scalars (e.g., timestamp: DateTime)
+	Scalars map[string]string `yaml:"scalars,omitempty"`
+
+	// Suffix for input variants of types (default: Input)
+	InputSuffix string `yaml:"input_suffix,omitempty"`
+
+	// Apply the input suffix to every input type, not only types also used as outputs
+	SuffixAllInputs bool `yaml:"suffix_all_inputs,omitempty"`
+
+	// Implement Relay object identification: a Node interface and a node query
+	Relay bool `yaml:"relay,omitempty"`
+
+	// Prefix for every generated type name (e.g., Billing_), for stitching into a gateway schema
+	TypePrefix string `yaml:"type_prefix,omitempty"`
+
+	// Omit the Query, Mutation, and Subscription types, for merging into a gateway schema
+	SkipRootTypes bool `yaml:"skip_root_types,omitempty"`
+
+	// Render maps as this scalar (e.g., JSON) instead of lists of key-value entry types
+	MapScalar string `yaml:"map_scalar,omitempty"`
+
+	// Suffix of the names of map entry types (default: Entry)
+	MapEntrySuffix string `yaml:"map_entry_suffix,omitempty"`
+}
+
+// graphQLNameRegex matches the names GraphQL allows
+var graphQLNameRegex = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// validate checks the GraphQL settings
+func (g *GraphQLConfig) validate() error {
+	if g == nil {
+		return nil
+	}
+	if g.TypePrefix != "" && !graphQLNameRegex.MatchString(g.TypePrefix) {
+		return fmt.Errorf("generators.graphql.type_prefix: must start with a letter or underscore and hold only letters, digits, and underscores, got %q", g.TypePrefix)
+	}
+	if g.MapScalar != "" && !graphQLNameRegex.MatchString(g.MapScalar) {
+		return fmt.Errorf("generators.graphql.map_scalar: must start with a letter or underscore and hold only letters, digits, and underscores, got %q", g.MapScalar)
+	}
+	if g.MapEntrySuffix != "" && !graphQLNameRegex.MatchString("_"+g.MapEntrySuffix) {
+		return fmt.Errorf("generators.graphql.map_entry_suffix: must hold only letters, digits, and underscores, got %q", g.MapEntrySuffix)
+	}
+	return nil
 }
 
 // ProtobufConfig holds Protobuf generator settings
@@ -69,6 +220,26 @@ type ProtobufConfig struct {
 
 	// Import buf validate for validation rules
 	ImportBufValidate bool `yaml:"import_buf_validate,omitempty"`
+
+	// Map optional scalars to google.protobuf wrapper types instead of proto3 optional
+	UseWrapperTypes bool `yaml:"use_wrapper_types,omitempty"`
+
+	// File layout: namespace (a file per namespace, the default), type (a file
+	// per declaration), or single (one file with prefixed declaration names)
+	Layout string `yaml:"layout,omitempty"`
+}
+
+// validate checks the settings of the Protobuf generator
+func (p *ProtobufConfig) validate() error {
+	if p == nil {
+		return nil
+	}
+	switch p.Layout {
+	case "", "namespace", "type", "single":
+	default:
+		return fmt.Errorf("generators.protobuf.layout: must be namespace, type, or single, got %q", p.Layout)
+	}
+	return nil
 }
 
 // OpenAPIConfig holds OpenAPI generator settings
@@ -78,6 +249,342 @@ type OpenAPIConfig struct {
 
 	// OpenAPI version (default: 3.0.0)
 	Version string `yaml:"version,omitempty"`
+
+	// Describe error responses as RFC 7807 problem details (application/problem+json)
+	ProblemDetails bool `yaml:"problem_details,omitempty"`
+
+	// Name of the shared error schema component (default: Error, or Problem)
+	ErrorSchema string `yaml:"error_schema,omitempty"`
+
+	// Format of bytes fields: byte, base64 strings (default), or binary, raw octets
+	BytesFormat string `yaml:"bytes_format,omitempty"`
+
+	// Describe the proto3 JSON mapping of the Protobuf output, as served by REST gateways
+	ProtoJSON bool `yaml:"proto_json,omitempty"`
+
+	// Servers of the API, listed in the servers section of the spec
+	Servers []OpenAPIServerConfig `yaml:"servers,omitempty"`
+
+	// Security schemes by name, added to components.securitySchemes
+	SecuritySchemes map[string]OpenAPISecuritySchemeConfig `yaml:"security_schemes,omitempty"`
+
+	// Security requirements of every operation: scheme names mapped to scopes
+	Security []map[string][]string `yaml:"security,omitempty"`
+
+	// Shared parameters by component name, added to components.parameters and
+	// referenced by every operation (e.g., an X-Request-ID header)
+	Parameters map[string]OpenAPIParameterConfig `yaml:"parameters,omitempty"`
+}
+
+// OpenAPIServerConfig is a server of the API
+type OpenAPIServerConfig struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// OpenAPISecuritySchemeConfig is a way of authenticating to the API
+type OpenAPISecuritySchemeConfig struct {
+	// Scheme type: apiKey, http, oauth2, or openIdConnect
+	Type        string `yaml:"type"`
+	Description string `yaml:"description,omitempty"`
+
+	// Name and location (header, query, or cookie) of an apiKey
+	Name string `yaml:"name,omitempty"`
+	In   string `yaml:"in,omitempty"`
+
+	// HTTP authorization scheme (e.g., bearer) and bearer token format (e.g., JWT)
+	Scheme       string `yaml:"scheme,omitempty"`
+	BearerFormat string `yaml:"bearer_format,omitempty"`
+
+	// OAuth2 flows, written as in the OpenAPI specification
+	Flows map[string]interface{} `yaml:"flows,omitempty"`
+
+	// OpenID Connect discovery URL
+	OpenIDConnectURL string `yaml:"open_id_connect_url,omitempty"`
+}
+
+// OpenAPIParameterConfig is a parameter shared by every operation
+type OpenAPIParameterConfig struct {
+	Name        string `yaml:"name"`
+	In          string `yaml:"in"` // header, query, or cookie
+	Required    bool   `yaml:"required,omitempty"`
+	Description string `yaml:"description,omitempty"`
+
+	// Schema type (default: string) and format of the value
+	Type   string `yaml:"type,omitempty"`
+	Format string `yaml:"format,omitempty"`
+}
+
+// openAPISecuritySchemeTypes are the valid types of security schemes
+var openAPISecuritySchemeTypes = map[string]bool{"apiKey": true, "http": true, "oauth2": true, "openIdConnect": true}
+
+// openAPISharedParameterLocations are the locations of parameters that every operation can take
+var openAPISharedParameterLocations = map[string]bool{"header": true, "query": true, "cookie": true}
+
+// validate checks the servers, security, and shared parameters of the OpenAPI settings
+func (o *OpenAPIConfig) validate() error {
+	if o == nil {
+		return nil
+	}
+	switch o.BytesFormat {
+	case "", "byte", "binary":
+	default:
+		return fmt.Errorf("generators.openapi.bytes_format: must be byte or binary, got %q", o.BytesFormat)
+	}
+	for i, server := range o.Servers {
+		if server.URL == "" {
+			return fmt.Errorf("generators.openapi.servers[%d].url is required", i)
+		}
+	}
+	for name, scheme := range o.SecuritySchemes {
+		if !openAPISecuritySchemeTypes[scheme.Type] {
+			return fmt.Errorf("generators.openapi.security_schemes.%s.type: unknown type %q (valid: apiKey, http, oauth2, openIdConnect)", name, scheme.Type)
+		}
+	}
+	for i, requirement := range o.Security {
+		for name := range requirement {
+			if _, ok := o.SecuritySchemes[name]; !ok {
+				return fmt.Errorf("generators.openapi.security[%d]: unknown security scheme %q", i, name)
+			}
+		}
+	}
+	for name, param := range o.Parameters {
+		if param.Name == "" {
+			return fmt.Errorf("generators.openapi.parameters.%s.name is required", name)
+		}
+		if !openAPISharedParameterLocations[param.In] {
+			return fmt.Errorf("generators.openapi.parameters.%s.in: must be header, query, or cookie, got %q", name, param.In)
+		}
+	}
+	return nil
+}
+
+// GoConfig holds Go generator settings
+type GoConfig struct {
+	// Go import path of the protoc-gen-go output; enables ToProto/FromProto conversions
+	ProtoPackage string `yaml:"proto_package,omitempty"`
+	// Go type of optional (?) fields: pointer (*T), value (T with omitempty), or
+	// wrapper (Null[T]); unset makes messages pointers and keeps scalars values
+	OptionalFields string `yaml:"optional_fields,omitempty"`
+	// External Go types of TypeMUX types by type name, as an import path and type
+	// name (e.g. uuid: github.com/google/uuid.UUID); mapped declarations are not generated
+	Types map[string]string `yaml:"types,omitempty"`
+	// Add the gRPC health service, server reflection, and /healthz, /readyz, and
+	// /version handlers to the grpc and connect output
+	Scaffold bool `yaml:"scaffold,omitempty"`
+	// Generate a Go module with this module path: go.mod, and a package with
+	// doc.go per namespace
+	Module string `yaml:"module,omitempty"`
+	// go directive of the go.mod of the module (default: 1.21)
+	GoVersion string `yaml:"go_version,omitempty"`
+}
+
+// validate checks the optional field style and type mappings of the Go settings
+func (g *GoConfig) validate() error {
+	if g == nil {
+		return nil
+	}
+	switch g.OptionalFields {
+	case "", "pointer", "value", "wrapper":
+	default:
+		return fmt.Errorf("generators.go.optional_fields: must be pointer, value, or wrapper, got %q", g.OptionalFields)
+	}
+	if g.GoVersion != "" && g.Module == "" {
+		return fmt.Errorf("generators.go.go_version requires generators.go.module")
+	}
+	for name, goType := range g.Types {
+		if goType == "" || strings.HasSuffix(goType, ".") || strings.HasSuffix(goType, "/") {
+			return fmt.Errorf("generators.go.types.%s: must be a Go type such as github.com/google/uuid.UUID, got %q", name, goType)
+		}
+	}
+	return nil
+}
+
+// JavaConfig holds Java generator settings
+type JavaConfig struct {
+	// Class style: record (Java 16+ records, the default) or pojo (classes with
+	// getters, setters, and a builder)
+	Style string `yaml:"style,omitempty"`
+	// Prefix of every package name derived from a namespace (e.g. org.acme)
+	PackagePrefix string `yaml:"package_prefix,omitempty"`
+}
+
+// javaPackageRegex matches dotted Java package names, and C# namespaces
+var javaPackageRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// validate checks the class style and package prefix of the Java settings
+func (j *JavaConfig) validate() error {
+	if j == nil {
+		return nil
+	}
+	switch j.Style {
+	case "", "record", "pojo":
+	default:
+		return fmt.Errorf("generators.java.style: must be record or pojo, got %q", j.Style)
+	}
+	if j.PackagePrefix != "" && !javaPackageRegex.MatchString(j.PackagePrefix) {
+		return fmt.Errorf("generators.java.package_prefix: must be a dotted Java package name such as org.acme, got %q", j.PackagePrefix)
+	}
+	return nil
+}
+
+// CSharpConfig holds C# generator settings
+type CSharpConfig struct {
+	// Type style: record (sealed records with init-only properties, the
+	// default) or class (mutable classes)
+	Style string `yaml:"style,omitempty"`
+	// Prefix of every C# namespace derived from a namespace (e.g. Acme)
+	NamespacePrefix string `yaml:"namespace_prefix,omitempty"`
+}
+
+// validate checks the type style and namespace prefix of the C# settings
+func (c *CSharpConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Style {
+	case "", "record", "class":
+	default:
+		return fmt.Errorf("generators.csharp.style: must be record or class, got %q", c.Style)
+	}
+	if c.NamespacePrefix != "" && !javaPackageRegex.MatchString(c.NamespacePrefix) {
+		return fmt.Errorf("generators.csharp.namespace_prefix: must be a dotted C# namespace such as Acme.Api, got %q", c.NamespacePrefix)
+	}
+	return nil
+}
+
+// LimitsConfig bounds the size and complexity of schema files; zero is unlimited
+type LimitsConfig struct {
+	// Largest schema file, in bytes
+	MaxFileSize int64 `yaml:"max_file_size,omitempty"`
+
+	// Longest chain of imports from the root schema
+	MaxImportDepth int `yaml:"max_import_depth,omitempty"`
+
+	// Most types, enums, unions, and services of a schema and its imports
+	MaxTypes int `yaml:"max_types,omitempty"`
+
+	// Most fields of a type
+	MaxFields int `yaml:"max_fields,omitempty"`
+
+	// Longest content of an annotation, in bytes
+	MaxAnnotationLength int `yaml:"max_annotation_length,omitempty"`
+}
+
+// validate checks that no limit is negative
+func (l LimitsConfig) validate() error {
+	limits := []struct {
+		name  string
+		value int64
+	}{
+		{"max_file_size", l.MaxFileSize},
+		{"max_import_depth", int64(l.MaxImportDepth)},
+		{"max_types", int64(l.MaxTypes)},
+		{"max_fields", int64(l.MaxFields)},
+		{"max_annotation_length", int64(l.MaxAnnotationLength)},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			return fmt.Errorf("limits.%s cannot be negative", limit.name)
+		}
+	}
+	return nil
+}
+
+// VerifyCheckConfig is a command that verifies generated files
+type VerifyCheckConfig struct {
+	// Name of the check in reports (required)
+	Name string `yaml:"name"`
+
+	// Glob of the files to verify, relative to the output directory; a
+	// pattern without a slash matches file names in every directory (required)
+	Files string `yaml:"files"`
+
+	// Program and arguments to run, where {files} expands to the matched
+	// files (required)
+	Command []string `yaml:"command"`
+
+	// Run the command once per directory of matched files, in that directory
+	PerDirectory bool `yaml:"per_directory,omitempty"`
+}
+
+// validateVerify checks the verification commands
+func (c *Config) validateVerify() error {
+	names := make(map[string]bool)
+	for i, check := range c.Verify {
+		switch {
+		case check.Name == "":
+			return fmt.Errorf("verify[%d].name is required", i)
+		case names[check.Name]:
+			return fmt.Errorf("verify[%d].name: duplicate check %q", i, check.Name)
+		case check.Files == "":
+			return fmt.Errorf("verify[%d].files is required", i)
+		case len(check.Command) == 0:
+			return fmt.Errorf("verify[%d].command is required", i)
+		}
+		if _, err := path.Match(check.Files, ""); err != nil {
+			return fmt.Errorf("verify[%d].files: invalid pattern %q", i, check.Files)
+		}
+		names[check.Name] = true
+	}
+	return nil
+}
+
+// UnionsConfig holds the default JSON encoding of unions
+type UnionsConfig struct {
+	// internal ({"type": "Circle", ...}), adjacent ({"type": "Circle", "value": {...}}), or untagged
+	Encoding string `yaml:"encoding,omitempty"`
+	// Property naming the option of tagged unions (default: type)
+	Tag string `yaml:"tag,omitempty"`
+	// Property holding the option of adjacently tagged unions (default: value)
+	Content string `yaml:"content,omitempty"`
+}
+
+// UnionEncoding returns the default union encoding of the configuration; a nil
+// configuration sets none
+func (u *UnionsConfig) UnionEncoding() (*ast.UnionEncoding, error) {
+	if u == nil {
+		return nil, nil
+	}
+	encoding := &ast.UnionEncoding{Strategy: ast.UnionStrategy(u.Encoding), Tag: u.Tag, Content: u.Content}
+	if err := encoding.Validate(); err != nil {
+		return nil, fmt.Errorf("generators.unions: %w", err)
+	}
+	return encoding, nil
+}
+
+// NamingConfig holds the naming convention of field names per format:
+// snake_case, camelCase, or PascalCase; unset formats keep the schema names
+type NamingConfig struct {
+	Proto   string `yaml:"proto,omitempty"`
+	GraphQL string `yaml:"graphql,omitempty"`
+	JSON    string `yaml:"json,omitempty"`
+	Go      string `yaml:"go,omitempty"`
+}
+
+// Policy returns the naming policy of the configuration; a nil configuration keeps all names
+func (n *NamingConfig) Policy() (naming.Policy, error) {
+	var policy naming.Policy
+	if n == nil {
+		return policy, nil
+	}
+	for _, setting := range []struct {
+		key        string
+		value      string
+		convention *naming.Convention
+	}{
+		{"proto", n.Proto, &policy.Proto},
+		{"graphql", n.GraphQL, &policy.GraphQL},
+		{"json", n.JSON, &policy.JSON},
+		{"go", n.Go, &policy.Go},
+	} {
+		convention, err := naming.ParseConvention(setting.value)
+		if err != nil {
+			return policy, fmt.Errorf("generators.naming.%s: %w", setting.key, err)
+		}
+		*setting.convention = convention
+	}
+	return policy, nil
 }
 
 // Load reads and parses a configuration file
@@ -113,6 +620,49 @@ func Load(path string) (*Config, error) {
 
 // Validate checks that the configuration is valid
 func (c *Config) Validate() error {
+	if _, err := c.Generators.Naming.Policy(); err != nil {
+		return err
+	}
+	if err := c.Generators.GraphQL.validate(); err != nil {
+		return err
+	}
+	if err := c.Generators.Protobuf.validate(); err != nil {
+		return err
+	}
+	if err := c.Generators.OpenAPI.validate(); err != nil {
+		return err
+	}
+	if err := c.Generators.Go.validate(); err != nil {
+		return err
+	}
+	if err := c.Generators.Java.validate(); err != nil {
+		return err
+	}
+	if err := c.Generators.CSharp.validate(); err != nil {
+		return err
+	}
+	if _, err := c.Generators.Unions.UnionEncoding(); err != nil {
+		return err
+	}
+	switch c.Generators.Int64 {
+	case "", "number", "string", "scalar":
+	default:
+		return fmt.Errorf("generators.int64: must be number, string, or scalar, got %q", c.Generators.Int64)
+	}
+	if err := c.validateVerify(); err != nil {
+		return err
+	}
+	if err := c.Limits.validate(); err != nil {
+		return err
+	}
+
+	if len(c.Schemas) > 0 {
+		return c.validateSchemas()
+	}
+	if len(c.Profiles) > 0 {
+		return c.validateProfiles()
+	}
+
 	// Check required fields
 	if c.Input.Schema == "" {
 		return fmt.Errorf("input.schema is required")
@@ -122,18 +672,114 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("output.formats must specify at least one format")
 	}
 
-	// Validate format names
+	return validateFormats(c.Output.Formats)
+}
+
+// validateSchemas checks the entries of a batch configuration
+func (c *Config) validateSchemas() error {
+	if c.Input.Schema != "" {
+		return fmt.Errorf("input.schema and schemas cannot be used together")
+	}
+	if len(c.Profiles) > 0 {
+		return fmt.Errorf("schemas and profiles cannot be used together")
+	}
+
+	if err := validateFormats(c.Output.Formats); err != nil {
+		return err
+	}
+
+	directories := make(map[string]int)
+	for i, entry := range c.Schemas {
+		if entry.Input.Schema == "" {
+			return fmt.Errorf("schemas[%d].input.schema is required", i)
+		}
+
+		// Entries would overwrite each other's files in a shared directory
+		if entry.Output.Directory == "" {
+			return fmt.Errorf("schemas[%d].output.directory is required", i)
+		}
+		directory := filepath.Clean(entry.Output.Directory)
+		if other, exists := directories[directory]; exists {
+			return fmt.Errorf("schemas[%d].output.directory %s is already used by schemas[%d]", i, entry.Output.Directory, other)
+		}
+		directories[directory] = i
+
+		if len(entry.Output.Formats) == 0 && len(c.Output.Formats) == 0 {
+			return fmt.Errorf("schemas[%d].output.formats must specify at least one format", i)
+		}
+		if err := validateFormats(entry.Output.Formats); err != nil {
+			return fmt.Errorf("schemas[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// validateProfiles checks the profiles of a configuration
+func (c *Config) validateProfiles() error {
+	if c.Input.Schema == "" {
+		return fmt.Errorf("input.schema is required")
+	}
+
+	if err := validateFormats(c.Output.Formats); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool)
+	directories := make(map[string]string)
+	for i, profile := range c.Profiles {
+		if profile.Name == "" {
+			return fmt.Errorf("profiles[%d].name is required", i)
+		}
+		if names[profile.Name] {
+			return fmt.Errorf("profile %s is defined twice", profile.Name)
+		}
+		names[profile.Name] = true
+
+		// Profiles would overwrite each other's files in a shared directory
+		if profile.Output.Directory == "" {
+			return fmt.Errorf("profile %s: output.directory is required", profile.Name)
+		}
+		directory := filepath.Clean(profile.Output.Directory)
+		if other, exists := directories[directory]; exists {
+			return fmt.Errorf("profile %s: output.directory %s is already used by profile %s", profile.Name, profile.Output.Directory, other)
+		}
+		directories[directory] = profile.Name
+
+		if len(profile.Output.Formats) == 0 && len(c.Output.Formats) == 0 {
+			return fmt.Errorf("profile %s: output.formats must specify at least one format", profile.Name)
+		}
+		if err := validateFormats(profile.Output.Formats); err != nil {
+			return fmt.Errorf("profile %s: %w", profile.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateFormats checks the names of output formats
+func validateFormats(formats []string) error {
 	validFormats := map[string]bool{
-		"graphql":  true,
-		"protobuf": true,
-		"proto":    true,
-		"openapi":  true,
-		"all":      true,
+		"graphql":         true,
+		"protobuf":        true,
+		"proto":           true,
+		"openapi":         true,
+		"java":            true,
+		"csharp":          true,
+		"go":              true,
+		"golang":          true,
+		"grpc":            true,
+		"connect":         true,
+		"descriptor":      true,
+		"schema-endpoint": true,
+		"mock":            true,
+		"contract":        true,
+		"all":             true,
 	}
 
-	for _, format := range c.Output.Formats {
+	for _, format := range formats {
 		if !validFormats[format] {
-			return fmt.Errorf("invalid format: %s (must be graphql, protobuf, openapi, or all)", format)
+			return fmt.Errorf("invalid format: %s (must be graphql, protobuf, openapi, java, csharp, go, grpc, connect, descriptor, schema-endpoint, mock, contract, or all)", format)
 		}
 	}
 
@@ -142,24 +788,73 @@ func (c *Config) Validate() error {
 
 // ResolvePaths converts relative paths to absolute paths based on config file location
 func (c *Config) ResolvePaths(configDir string) error {
+	c.Input.resolvePaths(configDir)
+	c.Output.resolvePaths(configDir)
+
+	for i := range c.Schemas {
+		c.Schemas[i].Input.resolvePaths(configDir)
+		c.Schemas[i].Output.resolvePaths(configDir)
+	}
+
+	for i := range c.Profiles {
+		c.Profiles[i].Output.resolvePaths(configDir)
+		for j, ann := range c.Profiles[i].Annotations {
+			if !filepath.IsAbs(ann) {
+				c.Profiles[i].Annotations[j] = filepath.Join(configDir, ann)
+			}
+		}
+	}
+
+	if c.Generators.Templates != "" && !filepath.IsAbs(c.Generators.Templates) {
+		c.Generators.Templates = filepath.Join(configDir, c.Generators.Templates)
+	}
+
+	return nil
+}
+
+// resolvePaths makes the schema, annotation, and lock file paths relative to configDir
+func (in *InputConfig) resolvePaths(configDir string) {
 	// Resolve schema path
-	if !filepath.IsAbs(c.Input.Schema) {
-		c.Input.Schema = filepath.Join(configDir, c.Input.Schema)
+	if in.Schema != "" && !filepath.IsAbs(in.Schema) {
+		in.Schema = filepath.Join(configDir, in.Schema)
 	}
 
 	// Resolve annotation paths
-	for i, ann := range c.Input.Annotations {
+	for i, ann := range in.Annotations {
 		if !filepath.IsAbs(ann) {
-			c.Input.Annotations[i] = filepath.Join(configDir, ann)
+			in.Annotations[i] = filepath.Join(configDir, ann)
 		}
 	}
 
-	// Resolve output directory
-	if c.Output.Directory != "" && !filepath.IsAbs(c.Output.Directory) {
-		c.Output.Directory = filepath.Join(configDir, c.Output.Directory)
+	// Resolve lock file path
+	if in.LockFile != "" && !filepath.IsAbs(in.LockFile) {
+		in.LockFile = filepath.Join(configDir, in.LockFile)
 	}
+}
 
-	return nil
+// resolvePaths makes the output directory and header file relative to configDir
+func (out *OutputConfig) resolvePaths(configDir string) {
+	if out.Directory != "" && !filepath.IsAbs(out.Directory) {
+		out.Directory = filepath.Join(configDir, out.Directory)
+	}
+	if out.HeaderFile != "" && !filepath.IsAbs(out.HeaderFile) {
+		out.HeaderFile = filepath.Join(configDir, out.HeaderFile)
+	}
+}
+
+// inherit takes the formats, API versions, header, and stamping of the
+// top-level output where the output does not set them
+func (out *OutputConfig) inherit(top OutputConfig) {
+	if len(out.Formats) == 0 {
+		out.Formats = top.Formats
+	}
+	if len(out.APIVersions) == 0 {
+		out.APIVersions = top.APIVersions
+	}
+	if out.HeaderFile == "" {
+		out.HeaderFile = top.HeaderFile
+	}
+	out.Stamp = out.Stamp || top.Stamp
 }
 
 // ApplyDefaults sets default values for optional fields
@@ -169,6 +864,14 @@ func (c *Config) ApplyDefaults() {
 		c.Output.Directory = "./generated"
 	}
 
+	// Batch entries and profiles inherit the top-level formats, header, and stamping
+	for i := range c.Schemas {
+		c.Schemas[i].Output.inherit(c.Output)
+	}
+	for i := range c.Profiles {
+		c.Profiles[i].Output.inherit(c.Output)
+	}
+
 	// Generator defaults
 	if c.Generators.GraphQL != nil && c.Generators.GraphQL.Filename == "" {
 		c.Generators.GraphQL.Filename = "schema.graphql"
@@ -190,7 +893,44 @@ func (c *Config) ApplyDefaults() {
 
 // ShouldGenerateFormat checks if a specific format should be generated
 func (c *Config) ShouldGenerateFormat(format string) bool {
-	for _, f := range c.Output.Formats {
+	return shouldGenerateFormat(c.Output.Formats, format)
+}
+
+// Entries returns the schemas to compile: the batch entries, the schema of
+// input once per profile, or the single schema of input and output.
+func (c *Config) Entries() []SchemaConfig {
+	if len(c.Schemas) > 0 {
+		return c.Schemas
+	}
+	if len(c.Profiles) > 0 {
+		entries := make([]SchemaConfig, len(c.Profiles))
+		for i, profile := range c.Profiles {
+			input := c.Input
+			input.Annotations = concat(c.Input.Annotations, profile.Annotations)
+			input.OnlyServices = concat(c.Input.OnlyServices, profile.OnlyServices)
+			input.RootTypes = concat(c.Input.RootTypes, profile.RootTypes)
+			input.ExcludeServices = concat(c.Input.ExcludeServices, profile.ExcludeServices)
+			input.ExcludeTypes = concat(c.Input.ExcludeTypes, profile.ExcludeTypes)
+			entries[i] = SchemaConfig{Name: profile.Name, Input: input, Output: profile.Output}
+		}
+		return entries
+	}
+	return []SchemaConfig{{Input: c.Input, Output: c.Output}}
+}
+
+// concat returns a new list of the elements of a followed by those of b
+func concat(a, b []string) []string {
+	return append(append([]string(nil), a...), b...)
+}
+
+// ShouldGenerateFormat checks if a specific format should be generated for the schema
+func (s *SchemaConfig) ShouldGenerateFormat(format string) bool {
+	return shouldGenerateFormat(s.Output.Formats, format)
+}
+
+// shouldGenerateFormat checks if formats include a specific format
+func shouldGenerateFormat(formats []string, format string) bool {
+	for _, f := range formats {
 		if f == "all" || f == format || (f == "proto" && format == "protobuf") || (f == "golang" && format == "go") {
 			return true
 		}
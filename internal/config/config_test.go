@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/rasmartins/typemux/internal/naming"
 )
 
 func TestLoad(t *testing.T) {
@@ -16,6 +19,11 @@ input:
   schema: schema.typemux
   annotations:
     - annotations.yaml
+  only_services:
+    - UserService
+  root_types:
+    - com.example.Event
+  lock_file: typemux.lock
 output:
   directory: ./generated
   formats:
@@ -25,12 +33,47 @@ output:
 generators:
   graphql:
     filename: custom.graphql
+    scalars:
+      timestamp: DateTime
+      int64: BigInt
+    input_suffix: Payload
+    suffix_all_inputs: true
+    relay: true
+    type_prefix: Billing_
+    skip_root_types: true
   protobuf:
     filename: custom.proto
     import_buf_validate: true
+    use_wrapper_types: true
+    layout: single
   openapi:
     filename: custom.yaml
     version: "3.1.0"
+    problem_details: true
+    servers:
+      - url: https://api.example.com
+        description: Production
+    security_schemes:
+      bearerAuth:
+        type: http
+        scheme: bearer
+        bearer_format: JWT
+    security:
+      - bearerAuth: []
+    parameters:
+      RequestId:
+        name: X-Request-ID
+        in: header
+  go:
+    proto_package: github.com/example/api/pb
+    optional_fields: wrapper
+    types:
+      uuid: github.com/google/uuid.UUID
+    scaffold: true
+  templates: ./templates
+  naming:
+    proto: snake_case
+    json: camelCase
 `
 
 	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
@@ -58,6 +101,20 @@ generators:
 		t.Errorf("Expected 1 annotation file, got %d", len(cfg.Input.Annotations))
 	}
 
+	if len(cfg.Input.OnlyServices) != 1 || cfg.Input.OnlyServices[0] != "UserService" {
+		t.Errorf("Expected only_services [UserService], got %v", cfg.Input.OnlyServices)
+	}
+
+	if len(cfg.Input.RootTypes) != 1 || cfg.Input.RootTypes[0] != "com.example.Event" {
+		t.Errorf("Expected root_types [com.example.Event], got %v", cfg.Input.RootTypes)
+	}
+	if cfg.Input.LockFile != filepath.Join(tmpDir, "typemux.lock") {
+		t.Errorf("Expected lock file resolved relative to the config, got %s", cfg.Input.LockFile)
+	}
+	if cfg.Generators.Templates != filepath.Join(tmpDir, "templates") {
+		t.Errorf("Expected templates resolved relative to the config, got %s", cfg.Generators.Templates)
+	}
+
 	// Verify output
 	expectedDir := filepath.Join(tmpDir, "generated")
 	if cfg.Output.Directory != expectedDir {
@@ -79,6 +136,18 @@ generators:
 	if cfg.Generators.GraphQL.Filename != "custom.graphql" {
 		t.Errorf("Expected GraphQL filename custom.graphql, got %s", cfg.Generators.GraphQL.Filename)
 	}
+	if cfg.Generators.GraphQL.Scalars["timestamp"] != "DateTime" || cfg.Generators.GraphQL.Scalars["int64"] != "BigInt" {
+		t.Errorf("Expected GraphQL scalar mappings, got %v", cfg.Generators.GraphQL.Scalars)
+	}
+	if cfg.Generators.GraphQL.InputSuffix != "Payload" || !cfg.Generators.GraphQL.SuffixAllInputs {
+		t.Errorf("Expected GraphQL input suffix settings, got %q/%v", cfg.Generators.GraphQL.InputSuffix, cfg.Generators.GraphQL.SuffixAllInputs)
+	}
+	if !cfg.Generators.GraphQL.Relay {
+		t.Error("Expected GraphQL relay to be enabled")
+	}
+	if cfg.Generators.GraphQL.TypePrefix != "Billing_" || !cfg.Generators.GraphQL.SkipRootTypes {
+		t.Errorf("Expected GraphQL stitching settings, got %q/%v", cfg.Generators.GraphQL.TypePrefix, cfg.Generators.GraphQL.SkipRootTypes)
+	}
 
 	if cfg.Generators.Protobuf == nil {
 		t.Fatal("Protobuf generator config is nil")
@@ -89,6 +158,12 @@ generators:
 	if !cfg.Generators.Protobuf.ImportBufValidate {
 		t.Error("Expected ImportBufValidate to be true")
 	}
+	if !cfg.Generators.Protobuf.UseWrapperTypes {
+		t.Error("Expected UseWrapperTypes to be true")
+	}
+	if cfg.Generators.Protobuf.Layout != "single" {
+		t.Errorf("Expected Protobuf layout single, got %s", cfg.Generators.Protobuf.Layout)
+	}
 
 	if cfg.Generators.OpenAPI == nil {
 		t.Fatal("OpenAPI generator config is nil")
@@ -96,9 +171,48 @@ generators:
 	if cfg.Generators.OpenAPI.Filename != "custom.yaml" {
 		t.Errorf("Expected OpenAPI filename custom.yaml, got %s", cfg.Generators.OpenAPI.Filename)
 	}
+	if !cfg.Generators.OpenAPI.ProblemDetails {
+		t.Error("Expected ProblemDetails to be true")
+	}
 	if cfg.Generators.OpenAPI.Version != "3.1.0" {
 		t.Errorf("Expected OpenAPI version 3.1.0, got %s", cfg.Generators.OpenAPI.Version)
 	}
+	if servers := cfg.Generators.OpenAPI.Servers; len(servers) != 1 || servers[0].URL != "https://api.example.com" {
+		t.Errorf("Expected one OpenAPI server, got %+v", servers)
+	}
+	if scheme := cfg.Generators.OpenAPI.SecuritySchemes["bearerAuth"]; scheme.Type != "http" || scheme.BearerFormat != "JWT" {
+		t.Errorf("Expected a bearerAuth security scheme, got %+v", scheme)
+	}
+	if security := cfg.Generators.OpenAPI.Security; len(security) != 1 || security[0]["bearerAuth"] == nil {
+		t.Errorf("Expected a bearerAuth security requirement, got %+v", security)
+	}
+	if param := cfg.Generators.OpenAPI.Parameters["RequestId"]; param.Name != "X-Request-ID" || param.In != "header" {
+		t.Errorf("Expected a RequestId parameter, got %+v", param)
+	}
+
+	if cfg.Generators.Go == nil {
+		t.Fatal("Go generator config is nil")
+	}
+	if cfg.Generators.Go.ProtoPackage != "github.com/example/api/pb" {
+		t.Errorf("Expected Go proto package, got %s", cfg.Generators.Go.ProtoPackage)
+	}
+	if cfg.Generators.Go.OptionalFields != "wrapper" {
+		t.Errorf("Expected Go optional fields wrapper, got %s", cfg.Generators.Go.OptionalFields)
+	}
+	if !cfg.Generators.Go.Scaffold {
+		t.Error("Expected Go scaffold to be true")
+	}
+	if goType := cfg.Generators.Go.Types["uuid"]; goType != "github.com/google/uuid.UUID" {
+		t.Errorf("Expected uuid mapped to github.com/google/uuid.UUID, got %s", goType)
+	}
+
+	policy, err := cfg.Generators.Naming.Policy()
+	if err != nil {
+		t.Fatalf("Expected a valid naming policy: %v", err)
+	}
+	if policy != (naming.Policy{Proto: naming.SnakeCase, JSON: naming.CamelCase}) {
+		t.Errorf("Unexpected naming policy: %+v", policy)
+	}
 }
 
 func TestValidate_MissingSchema(t *testing.T) {
@@ -146,6 +260,148 @@ func TestValidate_InvalidFormat(t *testing.T) {
 	}
 }
 
+func TestValidate_MockFormat(t *testing.T) {
+	cfg := &Config{
+		Input:  InputConfig{Schema: "schema.typemux"},
+		Output: OutputConfig{Formats: []string{"openapi", "mock"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected the mock format to be valid, got %v", err)
+	}
+	if !cfg.ShouldGenerateFormat("mock") {
+		t.Error("Expected the mock format to be generated")
+	}
+}
+
+func TestValidate_ContractFormat(t *testing.T) {
+	cfg := &Config{
+		Input:  InputConfig{Schema: "schema.typemux"},
+		Output: OutputConfig{Formats: []string{"contract"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected the contract format to be valid, got %v", err)
+	}
+	if !cfg.ShouldGenerateFormat("contract") {
+		t.Error("Expected the contract format to be generated")
+	}
+}
+
+func TestValidate_InvalidNaming(t *testing.T) {
+	cfg := &Config{
+		Input:      InputConfig{Schema: "schema.typemux"},
+		Output:     OutputConfig{Formats: []string{"graphql"}},
+		Generators: GeneratorConfig{Naming: &NamingConfig{GraphQL: "kebab-case"}},
+	}
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "generators.naming.graphql") {
+		t.Errorf("Expected an error for the invalid GraphQL naming convention, got %v", err)
+	}
+}
+
+func TestValidate_InvalidOpenAPI(t *testing.T) {
+	tests := map[string]*OpenAPIConfig{
+		"servers[0].url": {Servers: []OpenAPIServerConfig{{Description: "Production"}}},
+		"security_schemes.auth.type": {
+			SecuritySchemes: map[string]OpenAPISecuritySchemeConfig{"auth": {Type: "basic"}},
+		},
+		`unknown security scheme "auth"`: {Security: []map[string][]string{{"auth": nil}}},
+		"parameters.RequestId.in": {
+			Parameters: map[string]OpenAPIParameterConfig{"RequestId": {Name: "id", In: "path"}},
+		},
+	}
+	for want, openAPI := range tests {
+		cfg := &Config{
+			Input:      InputConfig{Schema: "schema.typemux"},
+			Output:     OutputConfig{Formats: []string{"openapi"}},
+			Generators: GeneratorConfig{OpenAPI: openAPI},
+		}
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected an error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func TestValidate_InvalidGraphQLTypePrefix(t *testing.T) {
+	cfg := &Config{
+		Input:      InputConfig{Schema: "schema.typemux"},
+		Output:     OutputConfig{Formats: []string{"graphql"}},
+		Generators: GeneratorConfig{GraphQL: &GraphQLConfig{TypePrefix: "billing-"}},
+	}
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "generators.graphql.type_prefix") {
+		t.Errorf("Expected an error for the invalid type prefix, got %v", err)
+	}
+}
+
+func TestValidate_InvalidProtobufLayout(t *testing.T) {
+	cfg := &Config{
+		Input:      InputConfig{Schema: "schema.typemux"},
+		Output:     OutputConfig{Formats: []string{"protobuf"}},
+		Generators: GeneratorConfig{Protobuf: &ProtobufConfig{Layout: "nested"}},
+	}
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "generators.protobuf.layout") {
+		t.Errorf("Expected an error for the unknown layout, got %v", err)
+	}
+}
+
+func TestValidate_InvalidGoOptionalFields(t *testing.T) {
+	cfg := &Config{
+		Input:      InputConfig{Schema: "schema.typemux"},
+		Output:     OutputConfig{Formats: []string{"go"}},
+		Generators: GeneratorConfig{Go: &GoConfig{OptionalFields: "nullable"}},
+	}
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "generators.go.optional_fields") {
+		t.Errorf("Expected an error for the unknown optional field style, got %v", err)
+	}
+}
+
+func TestValidate_GoVersionWithoutModule(t *testing.T) {
+	cfg := &Config{
+		Input:      InputConfig{Schema: "schema.typemux"},
+		Output:     OutputConfig{Formats: []string{"go"}},
+		Generators: GeneratorConfig{Go: &GoConfig{GoVersion: "1.22"}},
+	}
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "generators.go.go_version requires generators.go.module") {
+		t.Errorf("Expected an error for the go version without a module, got %v", err)
+	}
+}
+
+func TestValidate_InvalidGoTypes(t *testing.T) {
+	cfg := &Config{
+		Input:      InputConfig{Schema: "schema.typemux"},
+		Output:     OutputConfig{Formats: []string{"go"}},
+		Generators: GeneratorConfig{Go: &GoConfig{Types: map[string]string{"uuid": "github.com/google/uuid."}}},
+	}
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "generators.go.types.uuid") {
+		t.Errorf("Expected an error for the invalid Go type, got %v", err)
+	}
+}
+
+func TestValidate_GoFormat(t *testing.T) {
+	cfg := &Config{
+		Input: InputConfig{
+			Schema: "schema.typemux",
+		},
+		Output: OutputConfig{
+			Formats: []string{"go"},
+		},
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected go format to be valid, got %v", err)
+	}
+}
+
 func TestShouldGenerateFormat(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -304,3 +560,558 @@ func TestLoad_NonExistentFile(t *testing.T) {
 		t.Error("Expected error for non-existent file")
 	}
 }
+
+func TestLoad_Schemas(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "batch.config.yaml")
+
+	configContent := `version: "1.0.0"
+output:
+  formats:
+    - protobuf
+  api_versions: [v1, v2]
+  header_file: LICENSE.txt
+  stamp: true
+schemas:
+  - input:
+      schema: users/users.typemux
+      annotations:
+        - users/annotations.yaml
+      lock_file: users/typemux.lock
+    output:
+      directory: ./gen/users
+  - input:
+      schema: orders/orders.typemux
+    output:
+      directory: ./gen/orders
+      formats:
+        - graphql
+        - go
+      api_versions: [all]
+      clean: true
+      header_file: orders/HEADER.txt
+generators:
+  protobuf:
+    use_wrapper_types: true
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	entries := cfg.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	users, orders := entries[0], entries[1]
+	if users.Input.Schema != filepath.Join(tmpDir, "users", "users.typemux") {
+		t.Errorf("Expected resolved users schema, got %s", users.Input.Schema)
+	}
+	if len(users.Input.Annotations) != 1 || users.Input.Annotations[0] != filepath.Join(tmpDir, "users", "annotations.yaml") {
+		t.Errorf("Expected resolved users annotations, got %v", users.Input.Annotations)
+	}
+	if users.Input.LockFile != filepath.Join(tmpDir, "users", "typemux.lock") {
+		t.Errorf("Expected resolved users lock file, got %s", users.Input.LockFile)
+	}
+	if users.Output.Directory != filepath.Join(tmpDir, "gen", "users") {
+		t.Errorf("Expected resolved users directory, got %s", users.Output.Directory)
+	}
+	if !users.ShouldGenerateFormat("protobuf") || users.ShouldGenerateFormat("graphql") {
+		t.Errorf("Expected users to inherit the top-level formats, got %v", users.Output.Formats)
+	}
+	if !orders.ShouldGenerateFormat("graphql") || !orders.ShouldGenerateFormat("go") || orders.ShouldGenerateFormat("protobuf") {
+		t.Errorf("Expected orders to use its own formats, got %v", orders.Output.Formats)
+	}
+	if !orders.Output.Clean || users.Output.Clean {
+		t.Error("Expected only orders to clean its output directory")
+	}
+	if users.Output.HeaderFile != filepath.Join(tmpDir, "LICENSE.txt") {
+		t.Errorf("Expected users to inherit the resolved top-level header file, got %s", users.Output.HeaderFile)
+	}
+	if orders.Output.HeaderFile != filepath.Join(tmpDir, "orders", "HEADER.txt") {
+		t.Errorf("Expected orders to use its own resolved header file, got %s", orders.Output.HeaderFile)
+	}
+	if !users.Output.Stamp || !orders.Output.Stamp {
+		t.Error("Expected entries to inherit top-level stamping")
+	}
+	if strings.Join(users.Output.APIVersions, ",") != "v1,v2" {
+		t.Errorf("Expected users to inherit the top-level API versions, got %v", users.Output.APIVersions)
+	}
+	if strings.Join(orders.Output.APIVersions, ",") != "all" {
+		t.Errorf("Expected orders to use its own API versions, got %v", orders.Output.APIVersions)
+	}
+	if cfg.Generators.Protobuf == nil || !cfg.Generators.Protobuf.UseWrapperTypes {
+		t.Error("Expected shared protobuf generator settings")
+	}
+}
+
+func TestEntries_SingleSchema(t *testing.T) {
+	cfg := &Config{
+		Input:  InputConfig{Schema: "schema.typemux"},
+		Output: OutputConfig{Directory: "./generated", Formats: []string{"go"}},
+	}
+
+	entries := cfg.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Input.Schema != "schema.typemux" || entries[0].Output.Directory != "./generated" {
+		t.Errorf("Expected the entry to use input and output, got %+v", entries[0])
+	}
+	if !entries[0].ShouldGenerateFormat("go") {
+		t.Error("Expected the entry to generate go")
+	}
+}
+
+func TestValidate_Schemas(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{
+			name: "input schema with schemas",
+			config: Config{
+				Input:   InputConfig{Schema: "schema.typemux"},
+				Output:  OutputConfig{Formats: []string{"go"}},
+				Schemas: []SchemaConfig{{Input: InputConfig{Schema: "a.typemux"}, Output: OutputConfig{Directory: "a"}}},
+			},
+			wantErr: "input.schema and schemas cannot be used together",
+		},
+		{
+			name: "missing schema",
+			config: Config{
+				Output:  OutputConfig{Formats: []string{"go"}},
+				Schemas: []SchemaConfig{{Output: OutputConfig{Directory: "a"}}},
+			},
+			wantErr: "schemas[0].input.schema is required",
+		},
+		{
+			name: "missing directory",
+			config: Config{
+				Output:  OutputConfig{Formats: []string{"go"}},
+				Schemas: []SchemaConfig{{Input: InputConfig{Schema: "a.typemux"}}},
+			},
+			wantErr: "schemas[0].output.directory is required",
+		},
+		{
+			name: "shared directory",
+			config: Config{
+				Output: OutputConfig{Formats: []string{"go"}},
+				Schemas: []SchemaConfig{
+					{Input: InputConfig{Schema: "a.typemux"}, Output: OutputConfig{Directory: "gen"}},
+					{Input: InputConfig{Schema: "b.typemux"}, Output: OutputConfig{Directory: "./gen/"}},
+				},
+			},
+			wantErr: "schemas[1].output.directory ./gen/ is already used by schemas[0]",
+		},
+		{
+			name: "missing formats",
+			config: Config{
+				Schemas: []SchemaConfig{{Input: InputConfig{Schema: "a.typemux"}, Output: OutputConfig{Directory: "a"}}},
+			},
+			wantErr: "schemas[0].output.formats must specify at least one format",
+		},
+		{
+			name: "invalid format",
+			config: Config{
+				Schemas: []SchemaConfig{{Input: InputConfig{Schema: "a.typemux"}, Output: OutputConfig{Directory: "a", Formats: []string{"invalid"}}}},
+			},
+			wantErr: "schemas[0]: invalid format: invalid (must be graphql, protobuf, openapi, java, csharp, go, grpc, connect, descriptor, schema-endpoint, mock, contract, or all)",
+		},
+		{
+			name: "valid",
+			config: Config{
+				Schemas: []SchemaConfig{
+					{Input: InputConfig{Schema: "a.typemux"}, Output: OutputConfig{Directory: "a", Formats: []string{"go"}}},
+					{Input: InputConfig{Schema: "b.typemux"}, Output: OutputConfig{Directory: "b", Formats: []string{"all"}}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_Profiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "profiles.config.yaml")
+
+	configContent := `version: "1.0.0"
+input:
+  schema: api.typemux
+  annotations:
+    - base.yaml
+  exclude_types:
+    - Debug
+output:
+  formats:
+    - openapi
+  stamp: true
+profiles:
+  - name: public
+    exclude_services:
+      - AdminService
+    output:
+      directory: ./gen/public
+  - name: internal
+    annotations:
+      - internal.yaml
+    only_services:
+      - AdminService
+    output:
+      directory: ./gen/internal
+      formats:
+        - protobuf
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	entries := cfg.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	public, internal := entries[0], entries[1]
+	if public.Name != "public" || internal.Name != "internal" {
+		t.Errorf("Expected entries named after their profiles, got %q and %q", public.Name, internal.Name)
+	}
+	if public.Input.Schema != filepath.Join(tmpDir, "api.typemux") || internal.Input.Schema != public.Input.Schema {
+		t.Errorf("Expected both profiles to use the resolved input schema, got %s and %s", public.Input.Schema, internal.Input.Schema)
+	}
+	if len(internal.Input.Annotations) != 2 || internal.Input.Annotations[1] != filepath.Join(tmpDir, "internal.yaml") {
+		t.Errorf("Expected the internal overlay after the base annotations, got %v", internal.Input.Annotations)
+	}
+	if len(public.Input.Annotations) != 1 {
+		t.Errorf("Expected the overlay to stay out of other profiles, got %v", public.Input.Annotations)
+	}
+	if len(public.Input.ExcludeServices) != 1 || len(public.Input.ExcludeTypes) != 1 || len(internal.Input.OnlyServices) != 1 {
+		t.Errorf("Unexpected selections: %+v and %+v", public.Input, internal.Input)
+	}
+	if public.Output.Directory != filepath.Join(tmpDir, "gen", "public") {
+		t.Errorf("Expected resolved public directory, got %s", public.Output.Directory)
+	}
+	if !public.ShouldGenerateFormat("openapi") || !internal.ShouldGenerateFormat("protobuf") || internal.ShouldGenerateFormat("openapi") {
+		t.Errorf("Unexpected formats %v and %v", public.Output.Formats, internal.Output.Formats)
+	}
+	if !public.Output.Stamp || !internal.Output.Stamp {
+		t.Error("Expected profiles to inherit top-level stamping")
+	}
+}
+
+func TestValidate_Profiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{
+			name: "missing input schema",
+			config: Config{
+				Output:   OutputConfig{Formats: []string{"go"}},
+				Profiles: []ProfileConfig{{Name: "public", Output: OutputConfig{Directory: "a"}}},
+			},
+			wantErr: "input.schema is required",
+		},
+		{
+			name: "with schemas",
+			config: Config{
+				Output:   OutputConfig{Formats: []string{"go"}},
+				Schemas:  []SchemaConfig{{Input: InputConfig{Schema: "a.typemux"}, Output: OutputConfig{Directory: "a"}}},
+				Profiles: []ProfileConfig{{Name: "public", Output: OutputConfig{Directory: "b"}}},
+			},
+			wantErr: "schemas and profiles cannot be used together",
+		},
+		{
+			name: "missing name",
+			config: Config{
+				Input:    InputConfig{Schema: "schema.typemux"},
+				Output:   OutputConfig{Formats: []string{"go"}},
+				Profiles: []ProfileConfig{{Output: OutputConfig{Directory: "a"}}},
+			},
+			wantErr: "profiles[0].name is required",
+		},
+		{
+			name: "duplicate name",
+			config: Config{
+				Input:  InputConfig{Schema: "schema.typemux"},
+				Output: OutputConfig{Formats: []string{"go"}},
+				Profiles: []ProfileConfig{
+					{Name: "public", Output: OutputConfig{Directory: "a"}},
+					{Name: "public", Output: OutputConfig{Directory: "b"}},
+				},
+			},
+			wantErr: "profile public is defined twice",
+		},
+		{
+			name: "shared directory",
+			config: Config{
+				Input:  InputConfig{Schema: "schema.typemux"},
+				Output: OutputConfig{Formats: []string{"go"}},
+				Profiles: []ProfileConfig{
+					{Name: "public", Output: OutputConfig{Directory: "gen"}},
+					{Name: "internal", Output: OutputConfig{Directory: "./gen/"}},
+				},
+			},
+			wantErr: "profile internal: output.directory ./gen/ is already used by profile public",
+		},
+		{
+			name: "missing formats",
+			config: Config{
+				Input:    InputConfig{Schema: "schema.typemux"},
+				Profiles: []ProfileConfig{{Name: "public", Output: OutputConfig{Directory: "a"}}},
+			},
+			wantErr: "profile public: output.formats must specify at least one format",
+		},
+		{
+			name: "valid",
+			config: Config{
+				Input: InputConfig{Schema: "schema.typemux"},
+				Profiles: []ProfileConfig{
+					{Name: "public", Output: OutputConfig{Directory: "a", Formats: []string{"openapi"}}},
+					{Name: "internal", Output: OutputConfig{Directory: "b", Formats: []string{"protobuf"}}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_Verify(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "verify.config.yaml")
+
+	configContent := `version: "1.0.0"
+input:
+  schema: api.typemux
+output:
+  formats:
+    - protobuf
+verify:
+  - name: buf
+    files: "*.proto"
+    command: [buf, lint]
+  - name: go
+    files: "*.go"
+    command: [go, build, "{files}"]
+    per_directory: true
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if len(cfg.Verify) != 2 {
+		t.Fatalf("Expected 2 checks, got %d", len(cfg.Verify))
+	}
+	if check := cfg.Verify[0]; check.Name != "buf" || check.Files != "*.proto" || len(check.Command) != 2 || check.PerDirectory {
+		t.Errorf("Unexpected buf check: %+v", check)
+	}
+	if check := cfg.Verify[1]; check.Command[2] != "{files}" || !check.PerDirectory {
+		t.Errorf("Unexpected go check: %+v", check)
+	}
+}
+
+func TestValidate_Verify(t *testing.T) {
+	tests := []struct {
+		name  string
+		check VerifyCheckConfig
+		want  string
+	}{
+		{"missing name", VerifyCheckConfig{Files: "*.go", Command: []string{"go", "vet"}}, "verify[1].name is required"},
+		{"duplicate name", VerifyCheckConfig{Name: "go", Files: "*.go", Command: []string{"go", "vet"}}, "duplicate check"},
+		{"missing files", VerifyCheckConfig{Name: "lint", Command: []string{"lint"}}, "verify[1].files is required"},
+		{"missing command", VerifyCheckConfig{Name: "lint", Files: "*.go"}, "verify[1].command is required"},
+		{"invalid pattern", VerifyCheckConfig{Name: "lint", Files: "[", Command: []string{"lint"}}, "verify[1].files"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Input:  InputConfig{Schema: "schema.typemux"},
+				Output: OutputConfig{Formats: []string{"go"}},
+				Verify: []VerifyCheckConfig{{Name: "go", Files: "*.go", Command: []string{"go", "vet"}}, tt.check},
+			}
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidate_Limits(t *testing.T) {
+	cfg := &Config{
+		Input:  InputConfig{Schema: "schema.typemux"},
+		Output: OutputConfig{Formats: []string{"go"}},
+		Limits: LimitsConfig{MaxFileSize: 1 << 20, MaxTypes: 500},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected valid limits, got %v", err)
+	}
+
+	cfg.Limits.MaxFields = -1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "limits.max_fields cannot be negative") {
+		t.Errorf("Expected an error for a negative limit, got %v", err)
+	}
+}
+
+func TestValidate_Unions(t *testing.T) {
+	cfg := &Config{
+		Input:      InputConfig{Schema: "schema.typemux"},
+		Output:     OutputConfig{Formats: []string{"go"}},
+		Generators: GeneratorConfig{Unions: &UnionsConfig{Encoding: "adjacent", Tag: "kind", Content: "data"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected a valid union encoding, got %v", err)
+	}
+	encoding, err := cfg.Generators.Unions.UnionEncoding()
+	if err != nil || encoding.Strategy != "adjacent" || encoding.TagProperty() != "kind" || encoding.ContentProperty() != "data" {
+		t.Errorf("Unexpected union encoding %+v, %v", encoding, err)
+	}
+
+	cfg.Generators.Unions = &UnionsConfig{Encoding: "untagged", Tag: "kind"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "generators.unions: untagged unions have no tag property") {
+		t.Errorf("Expected an error for a tag on untagged unions, got %v", err)
+	}
+}
+
+func TestValidate_GraphQLMaps(t *testing.T) {
+	cfg := &Config{
+		Input:      InputConfig{Schema: "schema.typemux"},
+		Output:     OutputConfig{Formats: []string{"graphql"}},
+		Generators: GeneratorConfig{GraphQL: &GraphQLConfig{MapScalar: "JSON", MapEntrySuffix: "Pair"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected valid map settings, got %v", err)
+	}
+
+	cfg.Generators.GraphQL.MapScalar = "JSON!"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "generators.graphql.map_scalar") {
+		t.Errorf("Expected an error for an invalid map scalar, got %v", err)
+	}
+	cfg.Generators.GraphQL.MapScalar = ""
+	cfg.Generators.GraphQL.MapEntrySuffix = "-Entry"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "generators.graphql.map_entry_suffix") {
+		t.Errorf("Expected an error for an invalid entry suffix, got %v", err)
+	}
+}
+
+func TestValidate_Int64(t *testing.T) {
+	cfg := &Config{
+		Input:  InputConfig{Schema: "schema.typemux"},
+		Output: OutputConfig{Formats: []string{"openapi"}},
+	}
+	for _, encoding := range []string{"", "number", "string", "scalar"} {
+		cfg.Generators.Int64 = encoding
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Expected int64 encoding %q to be valid, got %v", encoding, err)
+		}
+	}
+
+	cfg.Generators.Int64 = "bigint"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "generators.int64") {
+		t.Errorf("Expected an error for an unknown int64 encoding, got %v", err)
+	}
+}
+
+func TestValidate_Java(t *testing.T) {
+	cfg := &Config{
+		Input:      InputConfig{Schema: "schema.typemux"},
+		Output:     OutputConfig{Formats: []string{"java"}},
+		Generators: GeneratorConfig{Java: &JavaConfig{Style: "pojo", PackagePrefix: "org.acme"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected valid Java settings, got %v", err)
+	}
+
+	cfg.Generators.Java.Style = "bean"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "generators.java.style") {
+		t.Errorf("Expected an error for an unknown Java style, got %v", err)
+	}
+	cfg.Generators.Java.Style = ""
+	cfg.Generators.Java.PackagePrefix = "org.acme."
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "generators.java.package_prefix") {
+		t.Errorf("Expected an error for an invalid package prefix, got %v", err)
+	}
+}
+
+func TestValidate_CSharp(t *testing.T) {
+	cfg := &Config{
+		Input:      InputConfig{Schema: "schema.typemux"},
+		Output:     OutputConfig{Formats: []string{"csharp"}},
+		Generators: GeneratorConfig{CSharp: &CSharpConfig{Style: "class", NamespacePrefix: "Acme.Api"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected valid C# settings, got %v", err)
+	}
+
+	cfg.Generators.CSharp.Style = "struct"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "generators.csharp.style") {
+		t.Errorf("Expected an error for an unknown C# style, got %v", err)
+	}
+	cfg.Generators.CSharp.Style = ""
+	cfg.Generators.CSharp.NamespacePrefix = "Acme-Api"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "generators.csharp.namespace_prefix") {
+		t.Errorf("Expected an error for an invalid namespace prefix, got %v", err)
+	}
+}
+
+func TestValidate_OpenAPIBytesFormat(t *testing.T) {
+	cfg := &Config{
+		Input:      InputConfig{Schema: "schema.typemux"},
+		Output:     OutputConfig{Formats: []string{"openapi"}},
+		Generators: GeneratorConfig{OpenAPI: &OpenAPIConfig{BytesFormat: "binary"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected a valid bytes format, got %v", err)
+	}
+
+	cfg.Generators.OpenAPI.BytesFormat = "base64"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "generators.openapi.bytes_format") {
+		t.Errorf("Expected an error for an unknown bytes format, got %v", err)
+	}
+}
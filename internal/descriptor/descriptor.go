@@ -0,0 +1,695 @@
+// Package descriptor compiles the Protobuf files generated by TypeMUX to a
+// serialized google.protobuf.FileDescriptorSet, as protoc --descriptor_set_out
+// --include_imports would, without protoc. gRPC reflection services, grpcurl,
+// and schema registries read descriptor sets instead of .proto files.
+//
+// The files are read with the Protobuf importer, so Build understands the
+// subset of Protobuf the generator writes: messages with nested messages,
+// enums, maps, oneofs, and optional fields; enums; services; and the standard
+// file, message, field, enum, and method options, with the json_name of fields.
+// Custom options are left out.
+package descriptor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/importers/protobuf"
+)
+
+// Numbers of the fields of descriptor.proto that Build writes
+const (
+	fileSetFile = 1
+
+	fileName       = 1
+	filePackage    = 2
+	fileDependency = 3
+	fileMessage    = 4
+	fileEnum       = 5
+	fileService    = 6
+	fileOptions    = 8
+	fileSyntax     = 12
+
+	messageName          = 1
+	messageField         = 2
+	messageNested        = 3
+	messageEnum          = 4
+	messageOptions       = 7
+	messageOneof         = 8
+	messageReservedRange = 9
+	messageReservedName  = 10
+
+	fieldName           = 1
+	fieldNumber         = 3
+	fieldLabel          = 4
+	fieldType           = 5
+	fieldTypeName       = 6
+	fieldOptions        = 8
+	fieldOneofIndex     = 9
+	fieldJSONName       = 10
+	fieldProto3Optional = 17
+
+	enumName    = 1
+	enumValue   = 2
+	enumOptions = 3
+
+	serviceName    = 1
+	serviceMethod  = 2
+	serviceOptions = 3
+
+	methodName            = 1
+	methodInput           = 2
+	methodOutput          = 3
+	methodOptions         = 4
+	methodClientStreaming = 5
+	methodServerStreaming = 6
+)
+
+// Labels and types of FieldDescriptorProto
+const (
+	labelOptional = 1
+	labelRepeated = 3
+
+	typeMessage = 11
+	typeEnum    = 14
+)
+
+// scalarTypes maps Protobuf scalar types to their FieldDescriptorProto.Type
+var scalarTypes = map[string]int{
+	"double":   1,
+	"float":    2,
+	"int64":    3,
+	"uint64":   4,
+	"int32":    5,
+	"fixed64":  6,
+	"fixed32":  7,
+	"bool":     8,
+	"string":   9,
+	"bytes":    12,
+	"uint32":   13,
+	"sfixed32": 15,
+	"sfixed64": 16,
+	"sint32":   17,
+	"sint64":   18,
+}
+
+// File options Build writes, by option name and FileOptions field number
+var (
+	fileStringOptions = map[string]int{
+		"java_package":           1,
+		"java_outer_classname":   8,
+		"go_package":             11,
+		"objc_class_prefix":      36,
+		"csharp_namespace":       37,
+		"swift_prefix":           39,
+		"php_class_prefix":       40,
+		"php_namespace":          41,
+		"php_metadata_namespace": 44,
+		"ruby_package":           45,
+	}
+	fileBoolOptions = map[string]int{
+		"java_multiple_files": 10,
+		"deprecated":          23,
+		"cc_enable_arenas":    31,
+	}
+	optimizeModes = map[string]uint64{"SPEED": 1, "CODE_SIZE": 2, "LITE_RUNTIME": 3}
+)
+
+// Field options Build writes, by option name and FieldOptions field number, and
+// the numbers of the values of the enum options
+var (
+	fieldBoolOptions = map[string]int{
+		"packed":          2,
+		"deprecated":      3,
+		"lazy":            5,
+		"weak":            10,
+		"unverified_lazy": 15,
+		"debug_redact":    16,
+	}
+	fieldEnumOptions = map[string]int{
+		"ctype":     1,
+		"jstype":    6,
+		"retention": 17,
+	}
+	fieldEnumValues = map[string]map[string]uint64{
+		"ctype":     {"STRING": 0, "CORD": 1, "STRING_PIECE": 2},
+		"jstype":    {"JS_NORMAL": 0, "JS_STRING": 1, "JS_NUMBER": 2},
+		"retention": {"RETENTION_UNKNOWN": 0, "RETENTION_RUNTIME": 1, "RETENTION_SOURCE": 2},
+	}
+)
+
+// idempotencyLevels maps the values of the idempotency_level method option to their numbers
+var idempotencyLevels = map[string]uint64{"IDEMPOTENCY_UNKNOWN": 0, "NO_SIDE_EFFECTS": 1, "IDEMPOTENT": 2}
+
+// Build compiles Protobuf files, keyed by import path, to a serialized
+// FileDescriptorSet. The set includes the well-known files they import, and
+// every file comes after the files it imports.
+func Build(files map[string][]byte) ([]byte, error) {
+	b := &builder{
+		schemas:  make(map[string]*protobuf.ProtoSchema),
+		symbols:  make(map[string]int),
+		declared: make(map[string]string),
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := b.parse(path, string(files[path])); err != nil {
+			return nil, err
+		}
+	}
+	// Well-known files are added as they are imported, which may add more imports
+	for i := 0; i < len(b.order); i++ {
+		for _, imported := range b.schemas[b.order[i]].Imports {
+			if _, ok := b.schemas[imported]; ok {
+				continue
+			}
+			source, ok := wellKnownFiles[imported]
+			if !ok {
+				return nil, fmt.Errorf("%s: unknown import %q", b.order[i], imported)
+			}
+			if err := b.parse(imported, source); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var set message
+	visited := make(map[string]bool)
+	var visit func(path string) error
+	visit = func(path string) error {
+		if visited[path] {
+			return nil
+		}
+		visited[path] = true
+		for _, imported := range b.schemas[path].Imports {
+			if err := visit(imported); err != nil {
+				return err
+			}
+		}
+		file, err := b.file(path, b.schemas[path])
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		set.message(fileSetFile, file)
+		return nil
+	}
+	sort.Strings(b.order)
+	for _, path := range b.order {
+		if err := visit(path); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// builder holds the parsed files and the messages and enums they declare
+type builder struct {
+	schemas map[string]*protobuf.ProtoSchema
+	order   []string       // Paths of the files, in the order they were parsed
+	symbols map[string]int // typeMessage or typeEnum, by fully qualified name
+
+	// declared holds the file of every declaration by fully qualified name,
+	// including services and enum values, which are scoped like their enum
+	declared map[string]string
+}
+
+// parse parses a file and records its declarations
+func (b *builder) parse(path, source string) error {
+	schema, err := protobuf.NewParser(source).Parse()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	b.schemas[path] = schema
+	b.order = append(b.order, path)
+
+	var errs []string
+	add := func(name string) {
+		if other, ok := b.declared[name]; ok {
+			errs = append(errs, fmt.Sprintf("%s: %s is already declared in %s", path, name, other))
+		}
+		b.declared[name] = path
+	}
+	var declare func(scope string, messages []*protobuf.ProtoMessage, enums []*protobuf.ProtoEnum)
+	declare = func(scope string, messages []*protobuf.ProtoMessage, enums []*protobuf.ProtoEnum) {
+		for _, enum := range enums {
+			add(qualify(scope, enum.Name))
+			b.symbols[qualify(scope, enum.Name)] = typeEnum
+			for _, value := range enum.Values {
+				add(qualify(scope, value.Name))
+			}
+		}
+		for _, msg := range messages {
+			name := qualify(scope, msg.Name)
+			add(name)
+			b.symbols[name] = typeMessage
+			declare(name, msg.Messages, msg.Enums)
+		}
+	}
+	declare(schema.Package, schema.Messages, schema.Enums)
+	for _, service := range schema.Services {
+		add(qualify(schema.Package, service.Name))
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// resolve returns the fully qualified name and type of a message or enum
+// referenced from a scope, searching from the innermost scope outwards
+func (b *builder) resolve(name, scope string) (string, int, error) {
+	if strings.HasPrefix(name, ".") {
+		if kind, ok := b.symbols[name[1:]]; ok {
+			return name, kind, nil
+		}
+		return "", 0, fmt.Errorf("unknown type %s", name)
+	}
+	for {
+		candidate := qualify(scope, name)
+		if kind, ok := b.symbols[candidate]; ok {
+			return "." + candidate, kind, nil
+		}
+		if scope == "" {
+			return "", 0, fmt.Errorf("unknown type %s", name)
+		}
+		if i := strings.LastIndex(scope, "."); i >= 0 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+}
+
+// file encodes a FileDescriptorProto
+func (b *builder) file(path string, schema *protobuf.ProtoSchema) (message, error) {
+	var m message
+	m.string(fileName, path)
+	if schema.Package != "" {
+		m.string(filePackage, schema.Package)
+	}
+	for _, imported := range schema.Imports {
+		m.string(fileDependency, imported)
+	}
+	for _, msg := range schema.Messages {
+		encoded, err := b.message(msg, schema.Package)
+		if err != nil {
+			return nil, err
+		}
+		m.message(fileMessage, encoded)
+	}
+	for _, enum := range schema.Enums {
+		m.message(fileEnum, encodeEnum(enum))
+	}
+	for _, service := range schema.Services {
+		encoded, err := b.service(service, schema.Package)
+		if err != nil {
+			return nil, err
+		}
+		m.message(fileService, encoded)
+	}
+	if options := encodeFileOptions(schema.Options); len(options) > 0 {
+		m.message(fileOptions, options)
+	}
+	syntax := schema.Syntax
+	if syntax == "" {
+		syntax = "proto3"
+	}
+	m.string(fileSyntax, syntax)
+	return m, nil
+}
+
+// message encodes a DescriptorProto of a message declared in a scope
+func (b *builder) message(msg *protobuf.ProtoMessage, scope string) (message, error) {
+	name := qualify(scope, msg.Name)
+
+	var m message
+	m.string(messageName, msg.Name)
+
+	// Optional fields are in synthetic oneofs, which follow the declared ones
+	oneofs := make([]string, 0, len(msg.OneOfs))
+	for _, oneof := range msg.OneOfs {
+		oneofs = append(oneofs, oneof.Name)
+	}
+	var entries []message
+	addField := func(field *protobuf.ProtoField, oneofIndex int) error {
+		if field.Optional {
+			oneofIndex = len(oneofs)
+			oneofs = append(oneofs, "_"+field.Name)
+		}
+		encoded, entry, err := b.field(field, name, oneofIndex)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", name, field.Name, err)
+		}
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+		m.message(messageField, encoded)
+		return nil
+	}
+	for _, field := range msg.Fields {
+		if err := addField(field, -1); err != nil {
+			return nil, err
+		}
+	}
+	for i, oneof := range msg.OneOfs {
+		for _, field := range oneof.Fields {
+			if err := addField(field, i); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		m.message(messageNested, entry)
+	}
+	for _, nested := range msg.Messages {
+		encoded, err := b.message(nested, name)
+		if err != nil {
+			return nil, err
+		}
+		m.message(messageNested, encoded)
+	}
+	for _, enum := range msg.Enums {
+		m.message(messageEnum, encodeEnum(enum))
+	}
+	if msg.Options["deprecated"] == "true" {
+		var options message
+		options.bool(3, true)
+		m.message(messageOptions, options)
+	}
+	for _, oneof := range oneofs {
+		var decl message
+		decl.string(1, oneof)
+		m.message(messageOneof, decl)
+	}
+	if err := encodeReserved(&m, msg.Reserved); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return m, nil
+}
+
+// field encodes a FieldDescriptorProto of a message, and the map entry message
+// of a map field. oneofIndex is -1 for fields outside oneofs.
+func (b *builder) field(field *protobuf.ProtoField, scope string, oneofIndex int) (message, message, error) {
+	var m, entry message
+	m.string(fieldName, field.Name)
+	m.int32(fieldNumber, int32(field.Number))
+
+	if keyType, valueType, ok := mapTypes(field.Type); ok {
+		entryName := mapEntryName(field.Name)
+		var err error
+		if entry, err = b.mapEntry(entryName, keyType, valueType, scope); err != nil {
+			return nil, nil, err
+		}
+		m.varint(fieldLabel, labelRepeated)
+		m.varint(fieldType, typeMessage)
+		m.string(fieldTypeName, "."+qualify(scope, entryName))
+	} else {
+		label := labelOptional
+		if field.Repeated {
+			label = labelRepeated
+		}
+		m.varint(fieldLabel, uint64(label))
+		if err := b.fieldType(&m, field.Type, scope); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if options := encodeFieldOptions(field); len(options) > 0 {
+		m.message(fieldOptions, options)
+	}
+	if oneofIndex >= 0 {
+		m.int32(fieldOneofIndex, int32(oneofIndex))
+	}
+	if name, ok := field.Options["json_name"]; ok {
+		m.string(fieldJSONName, name)
+	} else {
+		m.string(fieldJSONName, jsonName(field.Name))
+	}
+	if field.Optional {
+		m.bool(fieldProto3Optional, true)
+	}
+	return m, entry, nil
+}
+
+// fieldType writes the type, and the type name of messages and enums, of a field
+func (b *builder) fieldType(m *message, typeName, scope string) error {
+	if kind, ok := scalarTypes[typeName]; ok {
+		m.varint(fieldType, uint64(kind))
+		return nil
+	}
+	name, kind, err := b.resolve(typeName, scope)
+	if err != nil {
+		return err
+	}
+	m.varint(fieldType, uint64(kind))
+	m.string(fieldTypeName, name)
+	return nil
+}
+
+// mapEntry encodes the nested message protoc generates for a map field
+func (b *builder) mapEntry(name, keyType, valueType, scope string) (message, error) {
+	var m message
+	m.string(messageName, name)
+	for i, entryField := range []struct{ name, typeName string }{{"key", keyType}, {"value", valueType}} {
+		var f message
+		f.string(fieldName, entryField.name)
+		f.int32(fieldNumber, int32(i+1))
+		f.varint(fieldLabel, labelOptional)
+		if err := b.fieldType(&f, entryField.typeName, scope); err != nil {
+			return nil, err
+		}
+		f.string(fieldJSONName, entryField.name)
+		m.message(messageField, f)
+	}
+	var options message
+	options.bool(7, true) // map_entry
+	m.message(messageOptions, options)
+	return m, nil
+}
+
+// service encodes a ServiceDescriptorProto
+func (b *builder) service(service *protobuf.ProtoService, scope string) (message, error) {
+	var m message
+	m.string(serviceName, service.Name)
+	for _, method := range service.Methods {
+		var mm message
+		mm.string(methodName, method.Name)
+		for _, t := range []struct {
+			field    int
+			typeName string
+		}{{methodInput, method.InputType}, {methodOutput, method.OutputType}} {
+			name, kind, err := b.resolve(t.typeName, scope)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", service.Name, method.Name, err)
+			}
+			if kind != typeMessage {
+				return nil, fmt.Errorf("%s.%s: %s is not a message", service.Name, method.Name, t.typeName)
+			}
+			mm.string(t.field, name)
+		}
+
+		var options message
+		if method.Options["deprecated"] == "true" {
+			options.bool(33, true)
+		}
+		if level, ok := idempotencyLevels[method.Options["idempotency_level"]]; ok && level > 0 {
+			options.varint(34, level)
+		}
+		if len(options) > 0 {
+			mm.message(methodOptions, options)
+		}
+		if method.ClientStream {
+			mm.bool(methodClientStreaming, true)
+		}
+		if method.ServerStream {
+			mm.bool(methodServerStreaming, true)
+		}
+		m.message(serviceMethod, mm)
+	}
+	if service.Options["deprecated"] == "true" {
+		var options message
+		options.bool(33, true)
+		m.message(serviceOptions, options)
+	}
+	return m, nil
+}
+
+// encodeEnum encodes an EnumDescriptorProto
+func encodeEnum(enum *protobuf.ProtoEnum) message {
+	var m message
+	m.string(enumName, enum.Name)
+	for _, value := range enum.Values {
+		var v message
+		v.string(1, value.Name)
+		v.int32(2, int32(value.Number))
+		m.message(enumValue, v)
+	}
+	var options message
+	if enum.Options["allow_alias"] == "true" {
+		options.bool(2, true)
+	}
+	if enum.Options["deprecated"] == "true" {
+		options.bool(3, true)
+	}
+	if len(options) > 0 {
+		m.message(enumOptions, options)
+	}
+	return m
+}
+
+// encodeFileOptions encodes the FileOptions of the standard file options
+func encodeFileOptions(options map[string]string) message {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return fileOptionNumber(names[i]) < fileOptionNumber(names[j]) })
+
+	var m message
+	for _, name := range names {
+		value := options[name]
+		if field, ok := fileStringOptions[name]; ok {
+			m.string(field, value)
+		} else if field, ok := fileBoolOptions[name]; ok {
+			m.bool(field, value == "true")
+		} else if mode, ok := optimizeModes[value]; ok && name == "optimize_for" {
+			m.varint(9, mode)
+		}
+	}
+	return m
+}
+
+// encodeFieldOptions encodes the FieldOptions of the standard field options
+func encodeFieldOptions(field *protobuf.ProtoField) message {
+	options := make(map[string]string, len(field.Options)+1)
+	for name, value := range field.Options {
+		options[name] = value
+	}
+	if field.Deprecated {
+		options["deprecated"] = "true"
+	}
+
+	names := make([]string, 0, len(options))
+	for name := range options {
+		if fieldOptionNumber(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return fieldOptionNumber(names[i]) < fieldOptionNumber(names[j]) })
+
+	var m message
+	for _, name := range names {
+		value := options[name]
+		if number, ok := fieldBoolOptions[name]; ok {
+			m.bool(number, value == "true")
+		} else if enumValue, ok := fieldEnumValues[name][value]; ok {
+			m.varint(fieldEnumOptions[name], enumValue)
+		}
+	}
+	return m
+}
+
+// fieldOptionNumber returns the field number of a field option, so options are
+// written in field order as protoc writes them
+func fieldOptionNumber(name string) int {
+	if number, ok := fieldBoolOptions[name]; ok {
+		return number
+	}
+	return fieldEnumOptions[name]
+}
+
+// fileOptionNumber returns the field number of a file option, so options are
+// written in field order as protoc writes them
+func fileOptionNumber(name string) int {
+	if field, ok := fileStringOptions[name]; ok {
+		return field
+	}
+	if field, ok := fileBoolOptions[name]; ok {
+		return field
+	}
+	if name == "optimize_for" {
+		return 9
+	}
+	return 0
+}
+
+// encodeReserved writes the reserved ranges and names of a message: numbers
+// such as 5, ranges such as 5 to 10 or 5 to max, and quoted names
+func encodeReserved(m *message, reserved []string) error {
+	const maxFieldNumber = 536870911
+	for _, entry := range reserved {
+		if strings.HasPrefix(entry, `"`) {
+			m.string(messageReservedName, strings.Trim(entry, `"`))
+			continue
+		}
+		bounds := strings.SplitN(entry, " to ", 2)
+		start, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return fmt.Errorf("invalid reserved range %q", entry)
+		}
+		end := start
+		if len(bounds) == 2 {
+			if strings.TrimSpace(bounds[1]) == "max" {
+				end = maxFieldNumber
+			} else if end, err = strconv.Atoi(strings.TrimSpace(bounds[1])); err != nil {
+				return fmt.Errorf("invalid reserved range %q", entry)
+			}
+		}
+		var r message
+		r.int32(1, int32(start))
+		r.int32(2, int32(end+1)) // Exclusive
+		m.message(messageReservedRange, r)
+	}
+	return nil
+}
+
+// mapTypes returns the key and value types of a map<K, V> type
+func mapTypes(typeName string) (string, string, bool) {
+	if !strings.HasPrefix(typeName, "map<") || !strings.HasSuffix(typeName, ">") {
+		return "", "", false
+	}
+	key, value, ok := strings.Cut(typeName[len("map<"):len(typeName)-1], ",")
+	return strings.TrimSpace(key), strings.TrimSpace(value), ok
+}
+
+// mapEntryName returns the name protoc gives the entry message of a map field:
+// its name in PascalCase followed by Entry
+func mapEntryName(fieldName string) string {
+	name := jsonName(fieldName)
+	return strings.ToUpper(name[:1]) + name[1:] + "Entry"
+}
+
+// jsonName returns the JSON name protoc gives a field: its name with the letter
+// after every underscore capitalized and the underscores removed
+func jsonName(fieldName string) string {
+	var sb strings.Builder
+	upper := false
+	for _, r := range fieldName {
+		switch {
+		case r == '_':
+			upper = true
+		case upper:
+			sb.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// qualify joins a scope and a name with a dot
+func qualify(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
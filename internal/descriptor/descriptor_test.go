@@ -0,0 +1,274 @@
+package descriptor
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// decoded holds the fields of a decoded message: varints as uint64 and
+// length-delimited fields as []byte
+type decoded map[int][]interface{}
+
+// decode decodes the fields of a message of varint and length-delimited fields
+func decode(t *testing.T, data []byte) decoded {
+	t.Helper()
+	fields := make(decoded)
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			t.Fatalf("Invalid key at %d bytes from the end", len(data))
+		}
+		data = data[n:]
+		field := int(key >> 3)
+		switch key & 7 {
+		case wireVarint:
+			v, n := binary.Uvarint(data)
+			if n <= 0 {
+				t.Fatalf("Invalid varint of field %d", field)
+			}
+			data = data[n:]
+			fields[field] = append(fields[field], v)
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < length {
+				t.Fatalf("Invalid length of field %d", field)
+			}
+			fields[field] = append(fields[field], data[n:n+int(length)])
+			data = data[n+int(length):]
+		default:
+			t.Fatalf("Unexpected wire type %d of field %d", key&7, field)
+		}
+	}
+	return fields
+}
+
+// str returns the string value of a field, or "" if it is absent
+func (d decoded) str(field int) string {
+	if len(d[field]) == 0 {
+		return ""
+	}
+	return string(d[field][0].([]byte))
+}
+
+// num returns the varint value of a field, or 0 if it is absent
+func (d decoded) num(field int) uint64 {
+	if len(d[field]) == 0 {
+		return 0
+	}
+	return d[field][0].(uint64)
+}
+
+// messages decodes the values of a repeated message field
+func (d decoded) messages(t *testing.T, field int) []decoded {
+	var result []decoded
+	for _, v := range d[field] {
+		result = append(result, decode(t, v.([]byte)))
+	}
+	return result
+}
+
+// named returns the message of a repeated field with a name
+func named(t *testing.T, msgs []decoded, name string) decoded {
+	t.Helper()
+	for _, msg := range msgs {
+		if msg.str(1) == name {
+			return msg
+		}
+	}
+	t.Fatalf("Expected %s to be present", name)
+	return nil
+}
+
+const shopProto = `syntax = "proto3";
+
+package shop;
+
+import "google/protobuf/timestamp.proto";
+
+option go_package = "example.com/shop";
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_ACTIVE = 1;
+}
+
+message Product {
+  string id = 1;
+  optional string display_name = 2;
+  repeated string tags = 3;
+  map<string, int32> stock = 4;
+  Status status = 5;
+  google.protobuf.Timestamp created_at = 6;
+  repeated int32 counts = 7 [packed = false, json_name = "n", retention = RETENTION_SOURCE, (my.opt) = 1];
+  reserved 9, 10 to 12;
+}
+
+message GetProductRequest {
+  string id = 1;
+}
+
+service ProductService {
+  rpc GetProduct(GetProductRequest) returns (Product);
+  rpc WatchProducts(GetProductRequest) returns (stream Product);
+}
+`
+
+func TestBuild(t *testing.T) {
+	data, err := Build(map[string][]byte{"shop.proto": []byte(shopProto)})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	files := decode(t, data).messages(t, fileSetFile)
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(files))
+	}
+	if got := files[0].str(fileName); got != "google/protobuf/timestamp.proto" {
+		t.Errorf("Expected the imported file first, got %s", got)
+	}
+	file := files[1]
+	if got := file.str(fileName); got != "shop.proto" {
+		t.Errorf("Expected shop.proto, got %s", got)
+	}
+	if got := file.str(filePackage); got != "shop" {
+		t.Errorf("Expected package shop, got %s", got)
+	}
+	if got := file.str(fileSyntax); got != "proto3" {
+		t.Errorf("Expected syntax proto3, got %s", got)
+	}
+	if got := file.str(fileDependency); got != "google/protobuf/timestamp.proto" {
+		t.Errorf("Expected the timestamp dependency, got %s", got)
+	}
+	if got := decode(t, file[fileOptions][0].([]byte)).str(11); got != "example.com/shop" {
+		t.Errorf("Expected go_package example.com/shop, got %s", got)
+	}
+
+	product := named(t, file.messages(t, fileMessage), "Product")
+	fields := product.messages(t, messageField)
+	tests := []struct {
+		name     string
+		label    uint64
+		typ      uint64
+		typeName string
+		jsonName string
+	}{
+		{"id", labelOptional, 9, "", "id"},
+		{"display_name", labelOptional, 9, "", "displayName"},
+		{"tags", labelRepeated, 9, "", "tags"},
+		{"stock", labelRepeated, typeMessage, ".shop.Product.StockEntry", "stock"},
+		{"status", labelOptional, typeEnum, ".shop.Status", "status"},
+		{"created_at", labelOptional, typeMessage, ".google.protobuf.Timestamp", "createdAt"},
+		{"counts", labelRepeated, 5, "", "n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := named(t, fields, tt.name)
+			if got := field.num(fieldLabel); got != tt.label {
+				t.Errorf("Expected label %d, got %d", tt.label, got)
+			}
+			if got := field.num(fieldType); got != tt.typ {
+				t.Errorf("Expected type %d, got %d", tt.typ, got)
+			}
+			if got := field.str(fieldTypeName); got != tt.typeName {
+				t.Errorf("Expected type name %q, got %q", tt.typeName, got)
+			}
+			if got := field.str(fieldJSONName); got != tt.jsonName {
+				t.Errorf("Expected JSON name %q, got %q", tt.jsonName, got)
+			}
+		})
+	}
+
+	displayName := named(t, fields, "display_name")
+	if got := displayName.num(fieldProto3Optional); got != 1 {
+		t.Errorf("Expected display_name to be proto3 optional, got %d", got)
+	}
+	if got := displayName[fieldOneofIndex]; len(got) != 1 || got[0].(uint64) != 0 {
+		t.Errorf("Expected display_name in oneof 0, got %v", got)
+	}
+	if oneofs := product.messages(t, messageOneof); len(oneofs) != 1 || oneofs[0].str(1) != "_display_name" {
+		t.Errorf("Expected the synthetic oneof _display_name, got %v", oneofs)
+	}
+
+	counts := decode(t, named(t, fields, "counts")[fieldOptions][0].([]byte))
+	if got := counts[2]; len(got) != 1 || got[0].(uint64) != 0 {
+		t.Errorf("Expected counts to set packed to false, got %v", got)
+	}
+	if got := counts.num(17); got != 2 {
+		t.Errorf("Expected counts to have source retention, got %d", got)
+	}
+	if len(counts) != 2 {
+		t.Errorf("Expected only the standard options of counts, got %v", counts)
+	}
+
+	entry := named(t, product.messages(t, messageNested), "StockEntry")
+	if got := decode(t, entry[messageOptions][0].([]byte)).num(7); got != 1 {
+		t.Errorf("Expected StockEntry to be a map entry, got map_entry %d", got)
+	}
+	if got := len(product[messageReservedRange]); got != 2 {
+		t.Errorf("Expected 2 reserved ranges, got %d", got)
+	}
+
+	service := named(t, file.messages(t, fileService), "ProductService")
+	watch := named(t, service.messages(t, serviceMethod), "WatchProducts")
+	if got := watch.str(methodInput); got != ".shop.GetProductRequest" {
+		t.Errorf("Expected input .shop.GetProductRequest, got %s", got)
+	}
+	if got := watch.num(methodServerStreaming); got != 1 {
+		t.Errorf("Expected WatchProducts to stream responses, got %d", got)
+	}
+	if got := watch.num(methodClientStreaming); got != 0 {
+		t.Errorf("Expected WatchProducts not to stream requests, got %d", got)
+	}
+}
+
+func TestBuild_DependencyOrder(t *testing.T) {
+	data, err := Build(map[string][]byte{
+		"a/orders.proto":  []byte("syntax = \"proto3\";\n\npackage a;\n\nimport \"b/catalog.proto\";\n\nmessage Order {\n  b.Item item = 1;\n}\n"),
+		"b/catalog.proto": []byte("syntax = \"proto3\";\n\npackage b;\n\nmessage Item {\n  string id = 1;\n}\n"),
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	var names []string
+	for _, file := range decode(t, data).messages(t, fileSetFile) {
+		names = append(names, file.str(fileName))
+	}
+	if got := strings.Join(names, ","); got != "b/catalog.proto,a/orders.proto" {
+		t.Errorf("Expected b/catalog.proto,a/orders.proto, got %s", got)
+	}
+}
+
+func TestBuild_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{
+			name:    "unknown type",
+			source:  "syntax = \"proto3\";\n\npackage shop;\n\nmessage Order {\n  Missing item = 1;\n}\n",
+			wantErr: "Missing",
+		},
+		{
+			name:    "unknown import",
+			source:  "syntax = \"proto3\";\n\npackage shop;\n\nimport \"missing.proto\";\n",
+			wantErr: "missing.proto",
+		},
+		{
+			name:    "duplicate declaration",
+			source:  "syntax = \"proto3\";\n\npackage shop;\n\nmessage Order {\n}\n\nenum Order {\n  ORDER_UNSPECIFIED = 0;\n}\n",
+			wantErr: "shop.Order is already declared",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Build(map[string][]byte{"shop.proto": []byte(tt.source)})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package descriptor
+
+import (
+	"encoding/binary"
+)
+
+// Wire types of the Protobuf encoding
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// message accumulates the Protobuf wire encoding of a message
+type message []byte
+
+// tag appends the key of a field
+func (m *message) tag(field, wireType int) {
+	*m = binary.AppendUvarint(*m, uint64(field)<<3|uint64(wireType))
+}
+
+// varint appends an integer field
+func (m *message) varint(field int, v uint64) {
+	m.tag(field, wireVarint)
+	*m = binary.AppendUvarint(*m, v)
+}
+
+// int32 appends an int32 field; negative values take ten bytes, as in protoc
+func (m *message) int32(field int, v int32) {
+	m.varint(field, uint64(int64(v)))
+}
+
+// bool appends a bool field
+func (m *message) bool(field int, v bool) {
+	if v {
+		m.varint(field, 1)
+	} else {
+		m.varint(field, 0)
+	}
+}
+
+// string appends a string field
+func (m *message) string(field int, s string) {
+	m.bytes(field, []byte(s))
+}
+
+// bytes appends a length-delimited field
+func (m *message) bytes(field int, b []byte) {
+	m.tag(field, wireBytes)
+	*m = binary.AppendUvarint(*m, uint64(len(b)))
+	*m = append(*m, b...)
+}
+
+// message appends an embedded message field
+func (m *message) message(field int, sub message) {
+	m.bytes(field, sub)
+}
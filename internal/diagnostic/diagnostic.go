@@ -0,0 +1,130 @@
+// Package diagnostic describes the problems the compiler reports, with the file,
+// position, and severity of each, and maps each kind of failure to an exit code
+// so that editors and CI can tell a syntax error from a failed generator.
+package diagnostic
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// Severity is how serious a diagnostic is.
+type Severity string
+
+const (
+	// SeverityError is a problem that stops compilation.
+	SeverityError Severity = "error"
+	// SeverityWarning is a problem compilation goes on despite, unless -strict is set.
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a single problem, located in a file when it is known.
+type Diagnostic struct {
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// positionRegex matches the "Line 3:14 - " prefix of parser errors and warnings
+var positionRegex = regexp.MustCompile(`^Line (\d+):(\d+) - (?s)(.*)$`)
+
+// FromText returns the diagnostic of an error or warning message of a file,
+// taking its line and column from a "Line 3:14 - " prefix, as the parser
+// reports them.
+func FromText(file, text string, severity Severity) Diagnostic {
+	d := Diagnostic{File: file, Severity: severity, Message: text}
+	if m := positionRegex.FindStringSubmatch(text); m != nil {
+		d.Line, _ = strconv.Atoi(m[1])
+		d.Column, _ = strconv.Atoi(m[2])
+		d.Message = m[3]
+	}
+	return d
+}
+
+// Kind classifies a failure by the stage of compilation it stopped.
+type Kind int
+
+const (
+	// Failure is any other failure, such as bad flags or an unreadable file.
+	Failure Kind = iota
+	// Parse is a schema or annotation file that does not parse.
+	Parse
+	// Validation is a schema that parses but is invalid, incompatible, or, in
+	// strict mode, has warnings.
+	Validation
+	// Generation is a generator that failed or outputs that could not be written.
+	Generation
+)
+
+// ExitCode returns the exit status of the compiler for a kind of failure.
+func (k Kind) ExitCode() int {
+	switch k {
+	case Parse:
+		return 2
+	case Validation:
+		return 3
+	case Generation:
+		return 4
+	default:
+		return 1
+	}
+}
+
+// Error is a failure of a kind, with the diagnostics behind it. Its message is
+// the message of the wrapped error.
+type Error struct {
+	Kind        Kind
+	Err         error
+	Diagnostics []Diagnostic
+}
+
+// New returns an error of a kind that wraps err. Without diagnostics, the
+// message of err is its only diagnostic.
+func New(kind Kind, err error, diagnostics ...Diagnostic) *Error {
+	return &Error{Kind: kind, Err: err, Diagnostics: diagnostics}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of the first Error in the chain of err, or Failure.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return Failure
+}
+
+// Of returns the diagnostics of the first Error in the chain of err, or a
+// single diagnostic with the message of err.
+func Of(err error) []Diagnostic {
+	var e *Error
+	if errors.As(err, &e) && len(e.Diagnostics) > 0 {
+		return e.Diagnostics
+	}
+	return []Diagnostic{{Severity: SeverityError, Message: err.Error()}}
+}
+
+// WriteJSON writes diagnostics as a JSON object with a diagnostics list, which
+// is empty rather than null when there are none.
+func WriteJSON(w io.Writer, diagnostics []Diagnostic) error {
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Diagnostics []Diagnostic `json:"diagnostics"`
+	}{diagnostics})
+}
@@ -0,0 +1,91 @@
+package diagnostic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFromText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected Diagnostic
+	}{
+		{
+			text:     "Line 3:14 - expected :, got @",
+			expected: Diagnostic{File: "a.typemux", Line: 3, Column: 14, Severity: SeverityError, Message: "expected :, got @"},
+		},
+		{
+			text:     "circular import detected",
+			expected: Diagnostic{File: "a.typemux", Severity: SeverityError, Message: "circular import detected"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := FromText("a.typemux", tt.text, SeverityError); got != tt.expected {
+			t.Errorf("FromText(%q) = %+v, want %+v", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	for kind, code := range map[Kind]int{Failure: 1, Parse: 2, Validation: 3, Generation: 4} {
+		if got := kind.ExitCode(); got != code {
+			t.Errorf("Kind %d: expected exit code %d, got %d", kind, code, got)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	found := Diagnostic{File: "a.typemux", Line: 1, Column: 2, Severity: SeverityError, Message: "bad"}
+	err := fmt.Errorf("loading: %w", New(Parse, errors.New("parser errors"), found))
+
+	if err.Error() != "loading: parser errors" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+	if KindOf(err) != Parse {
+		t.Errorf("Expected a parse error, got kind %d", KindOf(err))
+	}
+	if got := Of(err); len(got) != 1 || got[0] != found {
+		t.Errorf("Unexpected diagnostics %+v", got)
+	}
+
+	plain := errors.New("no such file")
+	if KindOf(plain) != Failure {
+		t.Errorf("Expected a plain error to be a failure")
+	}
+	if got := Of(plain); len(got) != 1 || got[0].Message != "no such file" || got[0].Severity != SeverityError {
+		t.Errorf("Unexpected diagnostics of a plain error %+v", got)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "{\n  \"diagnostics\": []\n}\n" {
+		t.Errorf("Unexpected JSON:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteJSON(&buf, []Diagnostic{{File: "a.typemux", Line: 2, Column: 5, Severity: SeverityWarning, Message: "unknown annotation"}}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "diagnostics": [
+    {
+      "file": "a.typemux",
+      "line": 2,
+      "column": 5,
+      "severity": "warning",
+      "message": "unknown annotation"
+    }
+  ]
+}
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected JSON:\n%s", buf.String())
+	}
+}
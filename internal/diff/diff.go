@@ -1,6 +1,8 @@
 package diff
 
 import (
+	"strconv"
+
 	"github.com/rasmartins/typemux/internal/ast"
 )
 
@@ -228,6 +230,31 @@ func (d *Differ) compareFieldChanges(typeName string, baseField, headField *ast.
 		})
 	}
 
+	// Check for element nullability changes of arrays, which only GraphQL expresses
+	if baseField.Type.IsArray && headField.Type.IsArray && fieldTypesEqual(baseField.Type, headField.Type) {
+		if !baseField.Type.NonNullItems && headField.Type.NonNullItems {
+			d.addChange(&Change{
+				Type:        ChangeTypeFieldItemsMadeNonNull,
+				Severity:    SeverityBreaking,
+				Protocol:    ProtocolGraphQL,
+				Path:        path,
+				Description: "Array elements made non-null",
+				OldValue:    formatFieldType(baseField.Type),
+				NewValue:    formatFieldType(headField.Type),
+			})
+		} else if baseField.Type.NonNullItems && !headField.Type.NonNullItems {
+			d.addChange(&Change{
+				Type:        ChangeTypeFieldItemsMadeNullable,
+				Severity:    SeverityDangerous,
+				Protocol:    ProtocolGraphQL,
+				Path:        path,
+				Description: "Array elements made nullable (clients may receive null elements)",
+				OldValue:    formatFieldType(baseField.Type),
+				NewValue:    formatFieldType(headField.Type),
+			})
+		}
+	}
+
 	// Check for Protobuf field number changes (critical!)
 	if baseField.Number > 0 && headField.Number > 0 && baseField.Number != headField.Number {
 		d.addChange(&Change{
@@ -236,8 +263,8 @@ func (d *Differ) compareFieldChanges(typeName string, baseField, headField *ast.
 			Protocol:    ProtocolProto,
 			Path:        path,
 			Description: "Protobuf field number changed",
-			OldValue:    string(rune(baseField.Number)),
-			NewValue:    string(rune(headField.Number)),
+			OldValue:    strconv.Itoa(baseField.Number),
+			NewValue:    strconv.Itoa(headField.Number),
 		})
 	}
 
@@ -640,6 +667,9 @@ func fieldTypesEqual(t1, t2 *ast.FieldType) bool {
 }
 
 func formatFieldType(t *ast.FieldType) string {
+	if t.IsArray && t.NonNullItems {
+		return "[]" + t.Name + "!"
+	}
 	if t.IsArray {
 		return "[]" + t.Name
 	}
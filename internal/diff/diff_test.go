@@ -186,6 +186,45 @@ func TestDiffer_FieldMadeRequired(t *testing.T) {
 	}
 }
 
+func TestDiffer_FieldItemsNullability(t *testing.T) {
+	schema := func(nonNullItems bool) *ast.Schema {
+		return &ast.Schema{
+			Types: []*ast.Type{
+				{
+					Name: "User",
+					Fields: []*ast.Field{
+						{
+							Name: "tags",
+							Type: &ast.FieldType{Name: "string", IsArray: true, NonNullItems: nonNullItems},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		base, head bool
+		want       ChangeType
+		severity   Severity
+		newValue   string
+	}{
+		{false, true, ChangeTypeFieldItemsMadeNonNull, SeverityBreaking, "[]string!"},
+		{true, false, ChangeTypeFieldItemsMadeNullable, SeverityDangerous, "[]string"},
+	}
+
+	for _, tt := range tests {
+		result := NewDiffer(schema(tt.base), schema(tt.head)).Compare()
+		if len(result.Changes) != 1 {
+			t.Fatalf("Expected 1 change, got %d", len(result.Changes))
+		}
+		change := result.Changes[0]
+		if change.Type != tt.want || change.Severity != tt.severity || change.NewValue != tt.newValue {
+			t.Errorf("Expected %s (%s) to %s, got %s (%s) to %s", tt.want, tt.severity, tt.newValue, change.Type, change.Severity, change.NewValue)
+		}
+	}
+}
+
 func TestDiffer_EnumValueRemoved(t *testing.T) {
 	base := &ast.Schema{
 		Enums: []*ast.Enum{
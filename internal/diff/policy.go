@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy selects which changes count as incompatible when enforcing backward compatibility
+type Policy string
+
+const (
+	// PolicyWire rejects changes that break Protobuf binary clients: field numbers and
+	// types, removed fields without reserved numbers, and RPC signatures
+	PolicyWire Policy = "WIRE"
+	// PolicyJSON also rejects changes that break JSON clients, which rely on names:
+	// removed fields and enum values, and new required fields or arguments
+	PolicyJSON Policy = "JSON"
+	// PolicySource also rejects changes that break code generated from the schema,
+	// such as removed types or fields that became optional
+	PolicySource Policy = "SOURCE"
+)
+
+// policyLevels orders policies from the most to the least permissive
+var policyLevels = map[Policy]int{
+	PolicyWire:   1,
+	PolicyJSON:   2,
+	PolicySource: 3,
+}
+
+// changePolicies maps change types to the most permissive policy that rejects them.
+// Breaking changes that are not listed are only rejected by the SOURCE policy.
+var changePolicies = map[ChangeType]Policy{
+	ChangeTypeFieldTypeChanged:      PolicyWire,
+	ChangeTypeProtoFieldNumChanged:  PolicyWire,
+	ChangeTypeFieldRemovedNoReserve: PolicyWire,
+	ChangeTypeMethodRemoved:         PolicyWire,
+	ChangeTypeMethodParamChanged:    PolicyWire,
+	ChangeTypeMethodReturnChanged:   PolicyWire,
+
+	ChangeTypeFieldRemoved:          PolicyJSON,
+	ChangeTypeEnumValueRemoved:      PolicyJSON,
+	ChangeTypeFieldMadeRequired:     PolicyJSON,
+	ChangeTypeRequiredParamAdded:    PolicyJSON,
+	ChangeTypeFieldArgRemoved:       PolicyJSON,
+	ChangeTypeFieldArgTypeChanged:   PolicyJSON,
+	ChangeTypeFieldArgMadeRequired:  PolicyJSON,
+	ChangeTypeRequiredFieldArgAdded: PolicyJSON,
+	ChangeTypeFieldItemsMadeNonNull: PolicyJSON,
+
+	ChangeTypeFieldMadeOptional:      PolicySource,
+	ChangeTypeFieldItemsMadeNullable: PolicySource,
+}
+
+// ParsePolicy parses a policy name (WIRE, JSON, or SOURCE), ignoring case
+func ParsePolicy(name string) (Policy, error) {
+	policy := Policy(strings.ToUpper(name))
+	if _, ok := policyLevels[policy]; !ok {
+		return "", fmt.Errorf("unknown compatibility policy %q (must be WIRE, JSON, or SOURCE)", name)
+	}
+	return policy, nil
+}
+
+// Rejects reports whether a change is incompatible under the policy
+func (p Policy) Rejects(change *Change) bool {
+	required, ok := changePolicies[change.Type]
+	if !ok {
+		if change.Severity != SeverityBreaking {
+			return false
+		}
+		required = PolicySource
+	}
+	return policyLevels[required] <= policyLevels[p]
+}
+
+// Violations returns the changes of a diff result that are incompatible under the policy
+func (p Policy) Violations(result *Result) []*Change {
+	var violations []*Change
+	for _, change := range result.Changes {
+		if p.Rejects(change) {
+			violations = append(violations, change)
+		}
+	}
+	return violations
+}
@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Policy
+		wantErr bool
+	}{
+		{"WIRE", PolicyWire, false},
+		{"json", PolicyJSON, false},
+		{"Source", PolicySource, false},
+		{"FILE", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolicy(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicy(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePolicy(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_Rejects(t *testing.T) {
+	tests := []struct {
+		changeType ChangeType
+		severity   Severity
+		wire       bool
+		json       bool
+		source     bool
+	}{
+		{ChangeTypeProtoFieldNumChanged, SeverityBreaking, true, true, true},
+		{ChangeTypeFieldRemovedNoReserve, SeverityDangerous, true, true, true},
+		{ChangeTypeFieldRemoved, SeverityBreaking, false, true, true},
+		{ChangeTypeEnumValueRemoved, SeverityBreaking, false, true, true},
+		{ChangeTypeTypeRemoved, SeverityBreaking, false, false, true},
+		{ChangeTypeFieldMadeOptional, SeverityDangerous, false, false, true},
+		{ChangeTypeFieldAdded, SeverityNonBreaking, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.changeType), func(t *testing.T) {
+			change := &Change{Type: tt.changeType, Severity: tt.severity}
+			if got := PolicyWire.Rejects(change); got != tt.wire {
+				t.Errorf("WIRE rejects = %v, want %v", got, tt.wire)
+			}
+			if got := PolicyJSON.Rejects(change); got != tt.json {
+				t.Errorf("JSON rejects = %v, want %v", got, tt.json)
+			}
+			if got := PolicySource.Rejects(change); got != tt.source {
+				t.Errorf("SOURCE rejects = %v, want %v", got, tt.source)
+			}
+		})
+	}
+}
+
+func TestPolicy_Violations(t *testing.T) {
+	base := &ast.Schema{
+		Types: []*ast.Type{
+			{
+				Name: "User",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string"}, Number: 1, HasNumber: true},
+					{Name: "nickname", Type: &ast.FieldType{Name: "string"}},
+				},
+			},
+		},
+	}
+	head := &ast.Schema{
+		Types: []*ast.Type{
+			{
+				Name: "User",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string"}, Number: 2, HasNumber: true},
+					{Name: "email", Type: &ast.FieldType{Name: "string"}},
+				},
+			},
+		},
+	}
+
+	result := NewDiffer(base, head).Compare()
+
+	wire := PolicyWire.Violations(result)
+	if len(wire) != 1 || wire[0].Type != ChangeTypeProtoFieldNumChanged {
+		t.Fatalf("Expected only the field number change under WIRE, got %v", wire)
+	}
+	if wire[0].OldValue != "1" || wire[0].NewValue != "2" {
+		t.Errorf("Expected field number 1→2, got %s→%s", wire[0].OldValue, wire[0].NewValue)
+	}
+
+	if json := PolicyJSON.Violations(result); len(json) != 2 {
+		t.Errorf("Expected the field number change and the removed field under JSON, got %d", len(json))
+	}
+}
@@ -34,11 +34,15 @@ const (
 	ChangeTypeFieldArgMadeRequired ChangeType = "field_arg_made_required"
 	// ChangeTypeRequiredFieldArgAdded indicates a required field argument was added (breaking change)
 	ChangeTypeRequiredFieldArgAdded ChangeType = "required_field_arg_added"
+	// ChangeTypeFieldItemsMadeNonNull indicates the elements of an array field became non-null (breaking change)
+	ChangeTypeFieldItemsMadeNonNull ChangeType = "field_items_made_non_null"
 
 	// ChangeTypeFieldRemovedNoReserve indicates a field was removed without reserving the field number (dangerous change)
 	ChangeTypeFieldRemovedNoReserve ChangeType = "field_removed_no_reserve"
 	// ChangeTypeFieldMadeOptional indicates a required field became optional (dangerous change)
 	ChangeTypeFieldMadeOptional ChangeType = "field_made_optional"
+	// ChangeTypeFieldItemsMadeNullable indicates the elements of an array field became nullable (dangerous change)
+	ChangeTypeFieldItemsMadeNullable ChangeType = "field_items_made_nullable"
 
 	// ChangeTypeFieldAdded indicates a new field was added (non-breaking change)
 	ChangeTypeFieldAdded ChangeType = "field_added"
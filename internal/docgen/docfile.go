@@ -0,0 +1,197 @@
+package docgen
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// headingPattern matches an ATX heading line of Markdown
+var headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+
+// extendedMarkdown returns the extended documentation of an element, read from
+// its @doc-file, with its headings moved down to start at the given level and
+// followed by a blank line, or "" when it has none
+func extendedMarkdown(doc *ast.Documentation, level int) string {
+	if doc == nil || strings.TrimSpace(doc.Extended) == "" {
+		return ""
+	}
+	return shiftHeadings(strings.TrimSpace(doc.Extended), level) + "\n\n"
+}
+
+// writeMarkdownDetails writes the extended documentation of a member listed in
+// a table, such as a field, under a heading of the given level with its name
+func writeMarkdownDetails(sb *strings.Builder, level int, name string, doc *ast.Documentation) {
+	if extended := extendedMarkdown(doc, min(level+1, 6)); extended != "" {
+		sb.WriteString(fmt.Sprintf("%s `%s`\n\n", strings.Repeat("#", level), name))
+		sb.WriteString(extended)
+	}
+}
+
+// shiftHeadings moves the headings of Markdown text outside code blocks so that
+// its highest headings are at the given level, keeping their relative levels
+// and stopping at level 6
+func shiftHeadings(text string, level int) string {
+	lines := strings.Split(text, "\n")
+
+	top := 0
+	forEachHeading(lines, func(i, depth int) {
+		if top == 0 || depth < top {
+			top = depth
+		}
+	})
+	if top == 0 || top == level {
+		return text
+	}
+
+	forEachHeading(lines, func(i, depth int) {
+		lines[i] = strings.Repeat("#", min(depth-top+level, 6)) + strings.TrimLeft(lines[i], "#")
+	})
+	return strings.Join(lines, "\n")
+}
+
+// forEachHeading calls fn with the index and level of each heading line
+// outside fenced code blocks
+func forEachHeading(lines []string, fn func(i, depth int)) {
+	fenced := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if matches := headingPattern.FindStringSubmatch(line); matches != nil && !fenced {
+			fn(i, len(matches[1]))
+		}
+	}
+}
+
+// inlinePatterns are the inline Markdown spans rendered as HTML, applied to
+// escaped text outside code spans
+var inlinePatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\*\*([^*]+)\*\*`), `<strong>$1</strong>`},
+	{regexp.MustCompile(`__([^_]+)__`), `<strong>$1</strong>`},
+	{regexp.MustCompile(`\*([^*]+)\*`), `<em>$1</em>`},
+	{regexp.MustCompile(`\b_([^_]+)_\b`), `<em>$1</em>`},
+}
+
+// linkPattern matches a Markdown link in escaped text
+var linkPattern = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+
+// listItemPattern matches an item of a bulleted or numbered Markdown list
+var listItemPattern = regexp.MustCompile(`^\s*(?:([-*+])|\d+[.)])\s+(.*)$`)
+
+// markdownToHTML renders the extended documentation of an element as HTML,
+// with its headings starting at the given level. It supports the common
+// subset of Markdown: headings, paragraphs, bulleted and numbered lists,
+// fenced code blocks, code spans, emphasis, and links; everything else is
+// escaped text.
+func markdownToHTML(text string, level int) string {
+	var sb strings.Builder
+	var paragraph []string
+	listTag := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			sb.WriteString("<p>" + renderInline(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			sb.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+
+	lines := strings.Split(shiftHeadings(strings.TrimSpace(text), level), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flushParagraph()
+			closeList()
+			language := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			if language != "" {
+				sb.WriteString(fmt.Sprintf("<pre><code class=\"language-%s\">", html.EscapeString(language)))
+			} else {
+				sb.WriteString("<pre><code>")
+			}
+			sb.WriteString(html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+		case headingPattern.MatchString(line):
+			flushParagraph()
+			closeList()
+			matches := headingPattern.FindStringSubmatch(line)
+			depth := len(matches[1])
+			sb.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", depth, renderInline(matches[2]), depth))
+		case listItemPattern.MatchString(line):
+			flushParagraph()
+			matches := listItemPattern.FindStringSubmatch(line)
+			tag := "ol"
+			if matches[1] != "" {
+				tag = "ul"
+			}
+			if tag != listTag {
+				closeList()
+				sb.WriteString("<" + tag + ">\n")
+				listTag = tag
+			}
+			sb.WriteString("<li>" + renderInline(matches[2]) + "</li>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flushParagraph()
+	closeList()
+
+	return sb.String()
+}
+
+// renderInline renders the code spans, emphasis, and links of a line of
+// Markdown text, escaping the rest
+func renderInline(text string) string {
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		escaped := html.EscapeString(part)
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = "<code>" + escaped + "</code>"
+			continue
+		}
+		escaped = linkPattern.ReplaceAllStringFunc(escaped, func(link string) string {
+			matches := linkPattern.FindStringSubmatch(link)
+			if strings.HasPrefix(strings.ToLower(matches[2]), "javascript:") {
+				return matches[1]
+			}
+			return fmt.Sprintf(`<a href="%s">%s</a>`, matches[2], matches[1])
+		})
+		for _, inline := range inlinePatterns {
+			escaped = inline.pattern.ReplaceAllString(escaped, inline.replacement)
+		}
+		parts[i] = escaped
+	}
+
+	// An unmatched backtick is kept as text
+	var sb strings.Builder
+	for i, part := range parts {
+		if i > 0 && i == len(parts)-1 && i%2 == 1 {
+			sb.WriteString("`")
+		}
+		sb.WriteString(part)
+	}
+	return sb.String()
+}
@@ -0,0 +1,43 @@
+package docgen
+
+import "testing"
+
+func TestShiftHeadings(t *testing.T) {
+	tests := []struct {
+		text     string
+		level    int
+		expected string
+	}{
+		{"# Title\n\n## Section", 4, "#### Title\n\n##### Section"},
+		{"## Section\n\n### Sub", 3, "### Section\n\n#### Sub"},
+		{"# Title\n\n### Deep", 5, "##### Title\n\n###### Deep"},
+		{"# Title\n\n```sh\n# comment\n```", 2, "## Title\n\n```sh\n# comment\n```"},
+		{"No headings", 4, "No headings"},
+	}
+
+	for _, tt := range tests {
+		if result := shiftHeadings(tt.text, tt.level); result != tt.expected {
+			t.Errorf("shiftHeadings(%q, %d) = %q, want %q", tt.text, tt.level, result, tt.expected)
+		}
+	}
+}
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"# Title\n\nSome **bold** and *em* text\nacross lines.", "<h4>Title</h4>\n<p>Some <strong>bold</strong> and <em>em</em> text\nacross lines.</p>\n"},
+		{"- one\n- `two`\n\n1. first", "<ul>\n<li>one</li>\n<li><code>two</code></li>\n</ul>\n<ol>\n<li>first</li>\n</ol>\n"},
+		{"```go\nif a < b {}\n```", "<pre><code class=\"language-go\">if a &lt; b {}</code></pre>\n"},
+		{"See [the guide](https://example.com/a_b?x=1&y=2).", "<p>See <a href=\"https://example.com/a_b?x=1&amp;y=2\">the guide</a>.</p>\n"},
+		{"[click](javascript:void) <script>", "<p>click &lt;script&gt;</p>\n"},
+		{"Use `*ptr*` and a stray ` tick", "<p>Use <code>*ptr*</code> and a stray ` tick</p>\n"},
+	}
+
+	for _, tt := range tests {
+		if result := markdownToHTML(tt.text, 4); result != tt.expected {
+			t.Errorf("markdownToHTML(%q) = %q, want %q", tt.text, result, tt.expected)
+		}
+	}
+}
@@ -8,8 +8,12 @@ import (
 
 	"github.com/rasmartins/typemux/internal/ast"
 	"github.com/rasmartins/typemux/internal/generator"
+	"github.com/rasmartins/typemux/internal/graph"
 )
 
+// Options configures the documentation generators.
+type Options = generator.DocsOptions
+
 // Generator generates documentation from schemas
 type Generator struct {
 	schema     *ast.Schema
@@ -17,6 +21,7 @@ type Generator struct {
 	graphqlGen *generator.GraphQLGenerator
 	protoGen   *generator.ProtobufGenerator
 	openapiGen *generator.OpenAPIGenerator
+	opts       Options
 }
 
 // NewGenerator creates a new documentation generator
@@ -30,6 +35,15 @@ func NewGenerator(schema *ast.Schema, outputDir string) *Generator {
 	}
 }
 
+// NewGeneratorWithOptions creates a new documentation generator with the given options
+func NewGeneratorWithOptions(schema *ast.Schema, outputDir string, opts *Options) *Generator {
+	g := NewGenerator(schema, outputDir)
+	if opts != nil {
+		g.opts = *opts
+	}
+	return g
+}
+
 // Generate creates all documentation files
 func (g *Generator) Generate() error {
 	// Create output directory
@@ -108,6 +122,11 @@ func (g *Generator) generateIndex() error {
 	sb.WriteString("### Guides\n\n")
 	sb.WriteString("- [Cross-Format Usage Guide](cross-format-guide.md) - How to use the same API across different formats\n")
 
+	if g.opts.Diagrams {
+		sb.WriteString("\n## Type Dependencies\n\n")
+		sb.WriteString(fmt.Sprintf("```mermaid\n%s```\n", graph.Build(g.schema).Mermaid()))
+	}
+
 	return g.writeFile("README.md", sb.String())
 }
 
@@ -132,9 +151,10 @@ func (g *Generator) generateTypeDoc(typ *ast.Type, outputDir string) error {
 	sb.WriteString(fmt.Sprintf("# %s\n\n", typ.Name))
 
 	// Add documentation if present
-	if doc := typ.Doc.GetDoc(""); doc != "" {
+	if doc := typ.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 		sb.WriteString(fmt.Sprintf("%s\n\n", doc))
 	}
+	sb.WriteString(extendedMarkdown(typ.Doc, 2))
 
 	sb.WriteString("## Format Representations\n\n")
 
@@ -174,17 +194,14 @@ func (g *Generator) generateFieldDoc(typ *ast.Type, field *ast.Field) string {
 	sb.WriteString(fmt.Sprintf("### %s\n\n", field.Name))
 
 	// Field documentation
-	if doc := field.Doc.GetDoc(""); doc != "" {
+	if doc := field.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 		sb.WriteString(fmt.Sprintf("%s\n\n", doc))
 	}
+	sb.WriteString(extendedMarkdown(field.Doc, 4))
 
 	// Type information
-	typeStr := field.Type.Name
-	if field.Type.IsArray {
-		typeStr = "[]" + typeStr
-	}
-	sb.WriteString(fmt.Sprintf("**Type:** `%s`", typeStr))
-	if field.Required {
+	sb.WriteString(fmt.Sprintf("**Type:** `%s`", field.Type))
+	if field.Presence() == ast.PresenceRequired {
 		sb.WriteString(" (required)")
 	}
 	sb.WriteString("\n\n")
@@ -205,7 +222,7 @@ func (g *Generator) generateFieldDoc(typ *ast.Type, field *ast.Field) string {
 				defaultVal = "-"
 			}
 			description := ""
-			if doc := arg.Doc.GetDoc(""); doc != "" {
+			if doc := arg.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 				description = strings.ReplaceAll(doc, "\n", " ")
 			}
 
@@ -323,9 +340,10 @@ func (g *Generator) generateEnumDoc(enum *ast.Enum, outputDir string) error {
 
 	sb.WriteString(fmt.Sprintf("# %s\n\n", enum.Name))
 
-	if doc := enum.Doc.GetDoc(""); doc != "" {
+	if doc := enum.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 		sb.WriteString(fmt.Sprintf("%s\n\n", doc))
 	}
+	sb.WriteString(extendedMarkdown(enum.Doc, 2))
 
 	sb.WriteString("## Values\n\n")
 	sb.WriteString("| Value | Description |\n")
@@ -334,7 +352,7 @@ func (g *Generator) generateEnumDoc(enum *ast.Enum, outputDir string) error {
 	for _, val := range enum.Values {
 		description := ""
 		if val.Doc != nil {
-			if doc := val.Doc.GetDoc(""); doc != "" {
+			if doc := val.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 				description = strings.ReplaceAll(doc, "\n", " ")
 			}
 		}
@@ -343,6 +361,10 @@ func (g *Generator) generateEnumDoc(enum *ast.Enum, outputDir string) error {
 
 	sb.WriteString("\n")
 
+	for _, val := range enum.Values {
+		writeMarkdownDetails(&sb, 3, val.Name, val.Doc)
+	}
+
 	fileName := filepath.Join(outputDir, strings.ToLower(enum.Name)+".md")
 	return g.writeFile(fileName, sb.String())
 }
@@ -371,21 +393,28 @@ func (g *Generator) generateServiceDoc(svc *ast.Service, outputDir string) error
 
 	sb.WriteString(fmt.Sprintf("# %s\n\n", svc.Name))
 
-	if doc := svc.Doc.GetDoc(""); doc != "" {
+	if doc := svc.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 		sb.WriteString(fmt.Sprintf("%s\n\n", doc))
 	}
+	sb.WriteString(extendedMarkdown(svc.Doc, 2))
 
 	sb.WriteString("## Methods\n\n")
 
 	for _, method := range svc.Methods {
 		sb.WriteString(fmt.Sprintf("### %s\n\n", method.Name))
 
-		if doc := method.Doc.GetDoc(""); doc != "" {
+		if doc := method.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 			sb.WriteString(fmt.Sprintf("%s\n\n", doc))
 		}
+		sb.WriteString(extendedMarkdown(method.Doc, 4))
+
+		sb.WriteString(fmt.Sprintf("**Input:** %s\n\n", markdownMethodType(method.InputType)))
+		sb.WriteString(fmt.Sprintf("**Output:** %s\n\n", markdownMethodType(method.OutputType)))
+		writeMarkdownPolicies(&sb, method)
 
-		sb.WriteString(fmt.Sprintf("**Input:** `%s`\n\n", method.InputType))
-		sb.WriteString(fmt.Sprintf("**Output:** `%s`\n\n", method.OutputType))
+		if g.opts.FormatViews {
+			writeMarkdownMethodViews(&sb, svc, method, 4)
+		}
 	}
 
 	fileName := filepath.Join(outputDir, strings.ToLower(svc.Name)+".md")
@@ -458,7 +487,7 @@ func (g *Generator) generateGraphQLType(typ *ast.Type) string {
 		if field.Type.IsArray {
 			fieldType = "[" + fieldType + "]"
 		}
-		if field.Required {
+		if field.Presence() == ast.PresenceRequired {
 			fieldType += "!"
 		}
 
@@ -516,7 +545,7 @@ func (g *Generator) generateProtobufType(typ *ast.Type) string {
 		}
 
 		optional := ""
-		if !field.Required {
+		if field.Presence() == ast.PresenceOptional {
 			optional = "optional "
 		}
 
@@ -634,3 +663,51 @@ func (g *Generator) writeFile(filePath string, content string) error {
 
 	return os.WriteFile(filePath, []byte(content), 0o600)
 }
+
+// int64Note describes the JSON encoding of 64-bit integers of an explicit Int64
+// option, or returns "" when the option is unset or the types have none
+func int64Note(encoding generator.Int64Encoding, types []*ast.Type) string {
+	if encoding == "" || !uses64BitIntegers(types) {
+		return ""
+	}
+	switch encoding {
+	case generator.Int64String:
+		return "int64 and uint64 fields are encoded as JSON strings of digits (such as \"9007199254740993\"), as in proto3 JSON, and as String in GraphQL. Clients should not parse them as numbers, which lose precision above 2^53."
+	case generator.Int64Scalar:
+		return "int64 and uint64 fields are encoded as JSON strings of digits (such as \"9007199254740993\"), as in proto3 JSON, and as a custom scalar in GraphQL. Clients should not parse them as numbers, which lose precision above 2^53."
+	default:
+		return "int64 and uint64 fields are encoded as JSON numbers. Clients that read JSON numbers as doubles, such as JavaScript, lose precision above 2^53."
+	}
+}
+
+// bytesNote describes the JSON encoding of bytes fields, or returns "" when the
+// types have none
+func bytesNote(types []*ast.Type) string {
+	if !usesType(types, "bytes") {
+		return ""
+	}
+	return "bytes fields are base64-encoded strings in JSON (RFC 4648, standard alphabet, with padding), as in proto3 JSON, and raw bytes in Protobuf."
+}
+
+// uses64BitIntegers reports whether a field of the types holds int64 or uint64
+// values, directly, in a list, or in a map
+func uses64BitIntegers(types []*ast.Type) bool {
+	return usesType(types, "int64") || usesType(types, "uint64")
+}
+
+// usesType reports whether a field of the types holds values of a type,
+// directly, in a list, or in a map
+func usesType(types []*ast.Type, typeName string) bool {
+	for _, typ := range types {
+		for _, field := range typ.Fields {
+			ft := field.Type
+			if ft.IsMap {
+				ft = ft.GetMapValueType()
+			}
+			if ft != nil && ft.Name == typeName {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package docgen
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/generator"
+)
+
+// Generators of the documentation formats, by format name
+var generators = map[string]generator.Generator{
+	"markdown": generator.SingleFile("API.md", func(schema *ast.Schema, opts generator.Options) string {
+		return NewMarkdownGeneratorWithOptions(docsOptions(opts)).Generate(schema)
+	}),
+	"html": generator.GeneratorFunc(generateHTMLFiles),
+}
+
+// formatAliases maps alternative format names to the names in generators
+var formatAliases = map[string]string{
+	"md":   "markdown",
+	"docs": "markdown",
+}
+
+// Lookup returns the generator of a documentation format: markdown (md, docs) or html.
+func Lookup(format string) (generator.Generator, error) {
+	format = strings.ToLower(format)
+	if name, ok := formatAliases[format]; ok {
+		format = name
+	}
+	gen, ok := generators[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+	return generator.Wrap(format, gen), nil
+}
+
+// generateHTMLFiles generates the HTML documentation site under html/
+func generateHTMLFiles(ctx context.Context, schema *ast.Schema, opts generator.Options) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	for name, content := range NewHTMLGeneratorWithOptions(docsOptions(opts)).GenerateFiles(schema) {
+		files["html/"+name] = []byte(content)
+	}
+	return files, nil
+}
+
+// docsOptions returns the documentation options, with the shared Int64 encoding
+// unless they set their own
+func docsOptions(opts generator.Options) *Options {
+	var docsOpts Options
+	if opts.Docs != nil {
+		docsOpts = *opts.Docs
+	}
+	if docsOpts.Int64 == "" {
+		docsOpts.Int64 = opts.Int64
+	}
+	return &docsOpts
+}
@@ -0,0 +1,41 @@
+package docgen
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/generator"
+)
+
+func TestLookup(t *testing.T) {
+	markdown, err := Lookup("md")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	files, err := markdown.Generate(context.Background(), viewsTestSchema(), generator.Options{
+		Docs: &Options{FormatViews: true},
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(files) != 1 || !strings.Contains(string(files["API.md"]), "REST") {
+		t.Errorf("Expected API.md with format views, got %d file(s)", len(files))
+	}
+
+	html, err := Lookup("html")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	files, err = html.Generate(context.Background(), viewsTestSchema(), generator.Options{})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if _, ok := files["html/index.html"]; !ok {
+		t.Errorf("Expected html/index.html, got %d file(s)", len(files))
+	}
+
+	if _, err := Lookup("graphql"); err == nil {
+		t.Error("Expected an error for a format that is not documentation")
+	}
+}
@@ -0,0 +1,786 @@
+package docgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/graph"
+)
+
+// HTMLGenerator generates a static HTML documentation site from TypeMUX schemas.
+// The site has an index page, one page per namespace, cross-links between types,
+// and a client-side search box.
+type HTMLGenerator struct {
+	opts  Options
+	links map[string]string // Type, enum, and union names -> page#anchor
+}
+
+// NewHTMLGenerator creates a new HTML documentation generator.
+func NewHTMLGenerator() *HTMLGenerator {
+	return &HTMLGenerator{}
+}
+
+// NewHTMLGeneratorWithOptions creates an HTML documentation generator with the given options.
+func NewHTMLGeneratorWithOptions(opts *Options) *HTMLGenerator {
+	g := &HTMLGenerator{}
+	if opts != nil {
+		g.opts = *opts
+	}
+	return g
+}
+
+// htmlNamespace groups the schema elements declared in one namespace
+type htmlNamespace struct {
+	name     string
+	types    []*ast.Type
+	enums    []*ast.Enum
+	unions   []*ast.Union
+	services []*ast.Service
+}
+
+// htmlSearchEntry is a single entry of the client-side search index
+type htmlSearchEntry struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	URL       string `json:"url"`
+}
+
+// GenerateFiles creates the documentation site, keyed by relative file path.
+func (g *HTMLGenerator) GenerateFiles(schema *ast.Schema) map[string]string {
+	namespaces := g.groupByNamespace(schema)
+	g.links = make(map[string]string)
+	for _, ns := range namespaces {
+		page := g.namespacePage(ns.name)
+		for _, typ := range ns.types {
+			g.addLink(ns.name, typ.Name, page)
+		}
+		for _, enum := range ns.enums {
+			g.addLink(ns.name, enum.Name, page)
+		}
+		for _, union := range ns.unions {
+			g.addLink(ns.name, union.Name, page)
+		}
+	}
+
+	files := map[string]string{
+		"index.html": g.generateIndex(schema, namespaces),
+		"style.css":  htmlStylesheet,
+		"search.js":  g.generateSearchScript(namespaces),
+	}
+	for _, ns := range namespaces {
+		files[g.namespacePage(ns.name)] = g.generateNamespacePage(ns, namespaces)
+	}
+	return files
+}
+
+// groupByNamespace collects schema elements per namespace, sorted by namespace name
+func (g *HTMLGenerator) groupByNamespace(schema *ast.Schema) []*htmlNamespace {
+	byName := make(map[string]*htmlNamespace)
+	get := func(namespace string) *htmlNamespace {
+		if namespace == "" {
+			namespace = schema.Namespace
+		}
+		if namespace == "" {
+			namespace = "default"
+		}
+		ns, ok := byName[namespace]
+		if !ok {
+			ns = &htmlNamespace{name: namespace}
+			byName[namespace] = ns
+		}
+		return ns
+	}
+
+	for _, typ := range schema.Types {
+		ns := get(typ.Namespace)
+		ns.types = append(ns.types, typ)
+	}
+	for _, enum := range schema.Enums {
+		ns := get(enum.Namespace)
+		ns.enums = append(ns.enums, enum)
+	}
+	for _, union := range schema.Unions {
+		ns := get(union.Namespace)
+		ns.unions = append(ns.unions, union)
+	}
+	for _, service := range schema.Services {
+		ns := get(service.Namespace)
+		ns.services = append(ns.services, service)
+	}
+
+	namespaces := make([]*htmlNamespace, 0, len(byName))
+	for _, ns := range byName {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].name < namespaces[j].name
+	})
+	return namespaces
+}
+
+// addLink registers the page anchor of a named element under its simple and qualified names
+func (g *HTMLGenerator) addLink(namespace, name, page string) {
+	target := page + "#" + g.anchor(name)
+	g.links[namespace+"."+name] = target
+	if _, exists := g.links[name]; !exists {
+		g.links[name] = target
+	}
+}
+
+// namespacePage returns the file name of a namespace page
+func (g *HTMLGenerator) namespacePage(namespace string) string {
+	return namespace + ".html"
+}
+
+// anchor returns the element id used for a named element
+func (g *HTMLGenerator) anchor(name string) string {
+	return strings.ToLower(name)
+}
+
+// generateIndex creates the landing page listing all namespaces
+func (g *HTMLGenerator) generateIndex(schema *ast.Schema, namespaces []*htmlNamespace) string {
+	var sb strings.Builder
+
+	title := "API Documentation"
+	if schema.Namespace != "" {
+		title = schema.Namespace + " API Documentation"
+	}
+
+	sb.WriteString(g.pageHeader(title, namespaces, nil))
+	sb.WriteString(fmt.Sprintf("<h1>%s</h1>\n", html.EscapeString(title)))
+
+	sb.WriteString("<table>\n")
+	sb.WriteString("<thead><tr><th>Namespace</th><th>Types</th><th>Enums</th><th>Unions</th><th>Services</th></tr></thead>\n")
+	sb.WriteString("<tbody>\n")
+	for _, ns := range namespaces {
+		sb.WriteString(fmt.Sprintf("<tr><td><a href=\"%s\">%s</a></td><td>%d</td><td>%d</td><td>%d</td><td>%d</td></tr>\n",
+			html.EscapeString(g.namespacePage(ns.name)), html.EscapeString(ns.name),
+			len(ns.types), len(ns.enums), len(ns.unions), len(ns.services)))
+	}
+	sb.WriteString("</tbody>\n")
+	sb.WriteString("</table>\n")
+
+	if g.opts.Diagrams {
+		sb.WriteString("<h2>Type Dependencies</h2>\n")
+		sb.WriteString(fmt.Sprintf("<pre class=\"mermaid\">\n%s</pre>\n", html.EscapeString(graph.Build(schema).Mermaid())))
+		sb.WriteString(htmlMermaidScript)
+	}
+
+	sb.WriteString(htmlPageFooter)
+	return sb.String()
+}
+
+// generateNamespacePage creates the page documenting every element of a namespace
+func (g *HTMLGenerator) generateNamespacePage(ns *htmlNamespace, namespaces []*htmlNamespace) string {
+	var sb strings.Builder
+
+	sb.WriteString(g.pageHeader(ns.name, namespaces, ns))
+	sb.WriteString(fmt.Sprintf("<h1>%s</h1>\n", html.EscapeString(ns.name)))
+
+	if len(ns.services) > 0 {
+		sb.WriteString("<h2>Services</h2>\n")
+		for _, service := range ns.services {
+			sb.WriteString(g.generateService(service))
+		}
+	}
+
+	if len(ns.types) > 0 {
+		sb.WriteString("<h2>Types</h2>\n")
+		if note := int64Note(g.opts.Int64, ns.types); note != "" {
+			sb.WriteString(fmt.Sprintf("<p><strong>64-bit integers:</strong> %s</p>\n", html.EscapeString(note)))
+		}
+		if note := bytesNote(ns.types); note != "" {
+			sb.WriteString(fmt.Sprintf("<p><strong>Binary data:</strong> %s</p>\n", html.EscapeString(note)))
+		}
+		for _, typ := range ns.types {
+			sb.WriteString(g.generateType(typ))
+		}
+	}
+
+	if len(ns.enums) > 0 {
+		sb.WriteString("<h2>Enums</h2>\n")
+		for _, enum := range ns.enums {
+			sb.WriteString(g.generateEnum(enum))
+		}
+	}
+
+	if len(ns.unions) > 0 {
+		sb.WriteString("<h2>Unions</h2>\n")
+		for _, union := range ns.unions {
+			sb.WriteString(g.generateUnion(union))
+		}
+	}
+
+	sb.WriteString(htmlPageFooter)
+	return sb.String()
+}
+
+// pageHeader writes the document head, search box, and navigation sidebar
+func (g *HTMLGenerator) pageHeader(title string, namespaces []*htmlNamespace, current *htmlNamespace) string {
+	var sb strings.Builder
+
+	sb.WriteString("<!DOCTYPE html>\n")
+	lang := "en"
+	if g.opts.Locale != "" {
+		lang = g.opts.Locale
+	}
+	sb.WriteString(fmt.Sprintf("<html lang=\"%s\">\n", html.EscapeString(lang)))
+	sb.WriteString("<head>\n")
+	sb.WriteString("<meta charset=\"utf-8\">\n")
+	sb.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	sb.WriteString(fmt.Sprintf("<title>%s</title>\n", html.EscapeString(title)))
+	sb.WriteString("<link rel=\"stylesheet\" href=\"style.css\">\n")
+	sb.WriteString("<script src=\"search.js\" defer></script>\n")
+	sb.WriteString("</head>\n")
+	sb.WriteString("<body>\n")
+
+	sb.WriteString("<header>\n")
+	sb.WriteString("<a class=\"home\" href=\"index.html\">API Documentation</a>\n")
+	sb.WriteString("<div class=\"search\">\n")
+	sb.WriteString("<input id=\"search\" type=\"search\" placeholder=\"Search types, fields, methods...\" autocomplete=\"off\">\n")
+	sb.WriteString("<ul id=\"search-results\" hidden></ul>\n")
+	sb.WriteString("</div>\n")
+	sb.WriteString("</header>\n")
+
+	sb.WriteString("<nav>\n")
+	sb.WriteString("<h2>Namespaces</h2>\n")
+	sb.WriteString("<ul>\n")
+	for _, ns := range namespaces {
+		class := ""
+		if ns == current {
+			class = " class=\"current\""
+		}
+		sb.WriteString(fmt.Sprintf("<li%s><a href=\"%s\">%s</a></li>\n",
+			class, html.EscapeString(g.namespacePage(ns.name)), html.EscapeString(ns.name)))
+	}
+	sb.WriteString("</ul>\n")
+
+	if current != nil {
+		sb.WriteString(g.navSection("Services", serviceNames(current.services)))
+		sb.WriteString(g.navSection("Types", typeNames(current.types)))
+		sb.WriteString(g.navSection("Enums", enumNames(current.enums)))
+		sb.WriteString(g.navSection("Unions", unionNames(current.unions)))
+	}
+	sb.WriteString("</nav>\n")
+
+	sb.WriteString("<main>\n")
+	return sb.String()
+}
+
+// navSection writes a sidebar list of in-page links
+func (g *HTMLGenerator) navSection(title string, names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<h3>%s</h3>\n", title))
+	sb.WriteString("<ul>\n")
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("<li><a href=\"#%s\">%s</a></li>\n", g.anchor(name), html.EscapeString(name)))
+	}
+	sb.WriteString("</ul>\n")
+	return sb.String()
+}
+
+// generateType documents a type and its fields
+func (g *HTMLGenerator) generateType(typ *ast.Type) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("<section id=\"%s\">\n", g.anchor(typ.Name)))
+	sb.WriteString(fmt.Sprintf("<h3>%s <span class=\"kind\">type</span></h3>\n", html.EscapeString(typ.Name)))
+	sb.WriteString(g.docParagraph(typ.Doc))
+
+	if len(typ.Extends) > 0 {
+		bases := make([]string, len(typ.Extends))
+		for i, base := range typ.Extends {
+			bases[i] = "<code>" + g.typeLink(base) + "</code>"
+		}
+		sb.WriteString(fmt.Sprintf("<p class=\"extends\">Extends %s</p>\n", strings.Join(bases, ", ")))
+	}
+
+	if len(typ.Fields) > 0 {
+		sb.WriteString("<table>\n")
+		sb.WriteString("<thead><tr><th>Field</th><th>Type</th><th>Required</th><th>Description</th></tr></thead>\n")
+		sb.WriteString("<tbody>\n")
+		for _, field := range typ.Fields {
+			required := "No"
+			if field.Presence() == ast.PresenceRequired {
+				required = "Yes"
+			}
+
+			description := html.EscapeString(field.Doc.GetLocaleDoc(g.opts.Locale))
+			if field.Deprecated != nil {
+				notice := "<strong class=\"deprecated\">Deprecated</strong>"
+				if field.Deprecated.Reason != "" {
+					notice += ": " + html.EscapeString(field.Deprecated.Reason)
+				}
+				description = strings.TrimSpace(notice + " " + description)
+			}
+
+			sb.WriteString(fmt.Sprintf("<tr id=\"%s.%s\"><td><code>%s</code></td><td><code>%s</code></td><td>%s</td><td>%s</td></tr>\n",
+				g.anchor(typ.Name), strings.ToLower(field.Name), html.EscapeString(field.Name),
+				g.formatFieldType(field.Type), required, description))
+		}
+		sb.WriteString("</tbody>\n")
+		sb.WriteString("</table>\n")
+
+		for _, field := range typ.Fields {
+			sb.WriteString(g.memberDetails(typ.Name, field.Name, field.Doc))
+		}
+	}
+
+	sb.WriteString("</section>\n")
+	return sb.String()
+}
+
+// generateEnum documents an enum and its values
+func (g *HTMLGenerator) generateEnum(enum *ast.Enum) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("<section id=\"%s\">\n", g.anchor(enum.Name)))
+	sb.WriteString(fmt.Sprintf("<h3>%s <span class=\"kind\">enum</span></h3>\n", html.EscapeString(enum.Name)))
+	sb.WriteString(g.docParagraph(enum.Doc))
+
+	if len(enum.Values) > 0 {
+		sb.WriteString("<table>\n")
+		sb.WriteString("<thead><tr><th>Value</th><th>Number</th><th>Description</th></tr></thead>\n")
+		sb.WriteString("<tbody>\n")
+		for _, value := range enum.Values {
+			sb.WriteString(fmt.Sprintf("<tr><td><code>%s</code></td><td>%d</td><td>%s</td></tr>\n",
+				html.EscapeString(value.Name), value.Number, html.EscapeString(value.Doc.GetLocaleDoc(g.opts.Locale))))
+		}
+		sb.WriteString("</tbody>\n")
+		sb.WriteString("</table>\n")
+
+		for _, value := range enum.Values {
+			sb.WriteString(g.memberDetails(enum.Name, value.Name, value.Doc))
+		}
+	}
+
+	sb.WriteString("</section>\n")
+	return sb.String()
+}
+
+// generateUnion documents a union and links its options
+func (g *HTMLGenerator) generateUnion(union *ast.Union) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("<section id=\"%s\">\n", g.anchor(union.Name)))
+	sb.WriteString(fmt.Sprintf("<h3>%s <span class=\"kind\">union</span></h3>\n", html.EscapeString(union.Name)))
+	sb.WriteString(g.docParagraph(union.Doc))
+
+	sb.WriteString("<p>One of:</p>\n")
+	sb.WriteString("<ul>\n")
+	for _, option := range union.Options {
+		sb.WriteString(fmt.Sprintf("<li><code>%s</code></li>\n", g.typeLink(option)))
+	}
+	sb.WriteString("</ul>\n")
+	if encoding := union.Encoding; encoding != nil && len(union.Options) > 0 {
+		sb.WriteString(fmt.Sprintf("<p>JSON encoding: %s, such as <code>%s</code></p>\n",
+			encoding.Strategy.Describe(), html.EscapeString(encoding.Example(ast.GetUnqualifiedName(union.Options[0])))))
+	}
+
+	sb.WriteString("</section>\n")
+	return sb.String()
+}
+
+// generateService documents a service and its methods
+func (g *HTMLGenerator) generateService(service *ast.Service) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("<section id=\"%s\">\n", g.anchor(service.Name)))
+	sb.WriteString(fmt.Sprintf("<h3>%s <span class=\"kind\">service</span></h3>\n", html.EscapeString(service.Name)))
+	sb.WriteString(g.docParagraph(service.Doc))
+
+	if len(service.Methods) > 0 {
+		sb.WriteString("<table>\n")
+		sb.WriteString("<thead><tr><th>Method</th><th>Request</th><th>Response</th><th>HTTP</th><th>Description</th></tr></thead>\n")
+		sb.WriteString("<tbody>\n")
+		for _, method := range service.Methods {
+			request := "<em>none</em>"
+			if method.HasInput() {
+				request = g.typeLink(method.InputType)
+			}
+			if method.InputStream {
+				request = "stream " + request
+			}
+			response := "<em>none</em>"
+			if method.HasOutput() {
+				response = g.typeLink(method.OutputType)
+			}
+			if method.OutputStream {
+				response = "stream " + response
+			}
+
+			httpMapping := ""
+			if method.PathTemplate != "" {
+				httpMapping = fmt.Sprintf("<code>%s %s</code>",
+					strings.ToUpper(method.GetHTTPMethod()), html.EscapeString(method.HTTPPath(service)))
+			}
+
+			description := html.EscapeString(method.Doc.GetLocaleDoc(g.opts.Locale))
+			if facts := policyFacts(method); len(facts) > 0 {
+				description += "<ul class=\"policies\">"
+				for _, fact := range facts {
+					description += "<li>" + html.EscapeString(fact) + "</li>"
+				}
+				description += "</ul>"
+			}
+
+			sb.WriteString(fmt.Sprintf("<tr id=\"%s.%s\"><td><code>%s</code></td><td><code>%s</code></td><td><code>%s</code></td><td>%s</td><td>%s</td></tr>\n",
+				g.anchor(service.Name), strings.ToLower(method.Name), html.EscapeString(method.Name),
+				request, response, httpMapping, description))
+		}
+		sb.WriteString("</tbody>\n")
+		sb.WriteString("</table>\n")
+
+		for _, method := range service.Methods {
+			sb.WriteString(g.memberDetails(service.Name, method.Name, method.Doc))
+		}
+
+		if g.opts.FormatViews {
+			for _, method := range service.Methods {
+				writeHTMLMethodViews(&sb, service, method)
+			}
+		}
+	}
+
+	sb.WriteString("</section>\n")
+	return sb.String()
+}
+
+// docParagraph renders element documentation as a paragraph, followed by the
+// extended documentation read from its @doc-file
+func (g *HTMLGenerator) docParagraph(doc *ast.Documentation) string {
+	var extended string
+	if doc != nil && strings.TrimSpace(doc.Extended) != "" {
+		extended = markdownToHTML(doc.Extended, 4)
+	}
+	text := doc.GetLocaleDoc(g.opts.Locale)
+	if text == "" {
+		return extended
+	}
+	return fmt.Sprintf("<p>%s</p>\n", strings.ReplaceAll(html.EscapeString(text), "\n", "<br>\n")) + extended
+}
+
+// memberDetails renders the extended documentation of a member listed in a
+// table, such as a field, read from its @doc-file, or ""
+func (g *HTMLGenerator) memberDetails(owner, name string, doc *ast.Documentation) string {
+	if doc == nil || strings.TrimSpace(doc.Extended) == "" {
+		return ""
+	}
+	return fmt.Sprintf("<div class=\"details\" id=\"%s.%s-details\">\n<h4><code>%s</code></h4>\n%s</div>\n",
+		g.anchor(owner), strings.ToLower(name), html.EscapeString(name), markdownToHTML(doc.Extended, 5))
+}
+
+// formatFieldType renders a field type with links to referenced schema elements
+func (g *HTMLGenerator) formatFieldType(fieldType *ast.FieldType) string {
+	var typeName string
+
+	if fieldType.IsMap {
+		valueType := html.EscapeString(fieldType.MapValue)
+		if valueFieldType := fieldType.GetMapValueType(); valueFieldType != nil {
+			valueType = g.formatFieldType(valueFieldType)
+		}
+		typeName = fmt.Sprintf("map&lt;%s, %s&gt;", html.EscapeString(fieldType.MapKey), valueType)
+	} else if fieldType.IsArray {
+		typeName = "[]" + g.formatFieldType(fieldType.ElementType())
+		if fieldType.NonNullItems {
+			typeName += "!"
+		}
+	} else {
+		typeName = g.typeLink(fieldType.Name)
+	}
+
+	if fieldType.Optional {
+		typeName += "?"
+	}
+
+	return typeName
+}
+
+// typeLink links a type name to its documentation, or returns it escaped for builtins
+func (g *HTMLGenerator) typeLink(name string) string {
+	target, ok := g.links[name]
+	if !ok {
+		target, ok = g.links[ast.GetUnqualifiedName(name)]
+	}
+	if !ok {
+		return html.EscapeString(name)
+	}
+	return fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(target), html.EscapeString(name))
+}
+
+// generateSearchScript creates the search script with an embedded index
+func (g *HTMLGenerator) generateSearchScript(namespaces []*htmlNamespace) string {
+	var entries []htmlSearchEntry
+	for _, ns := range namespaces {
+		page := g.namespacePage(ns.name)
+		add := func(name, kind, anchor string) {
+			entries = append(entries, htmlSearchEntry{Name: name, Kind: kind, Namespace: ns.name, URL: page + "#" + anchor})
+		}
+
+		for _, service := range ns.services {
+			add(service.Name, "service", g.anchor(service.Name))
+			for _, method := range service.Methods {
+				add(service.Name+"."+method.Name, "method", g.anchor(service.Name)+"."+strings.ToLower(method.Name))
+			}
+		}
+		for _, typ := range ns.types {
+			add(typ.Name, "type", g.anchor(typ.Name))
+			for _, field := range typ.Fields {
+				add(typ.Name+"."+field.Name, "field", g.anchor(typ.Name)+"."+strings.ToLower(field.Name))
+			}
+		}
+		for _, enum := range ns.enums {
+			add(enum.Name, "enum", g.anchor(enum.Name))
+		}
+		for _, union := range ns.unions {
+			add(union.Name, "union", g.anchor(union.Name))
+		}
+	}
+
+	index, err := json.Marshal(entries)
+	if err != nil || entries == nil {
+		index = []byte("[]")
+	}
+
+	return "// Code generated by TypeMUX. DO NOT EDIT.\n\nconst searchIndex = " + string(index) + ";\n" + htmlSearchRuntime
+}
+
+func serviceNames(services []*ast.Service) []string {
+	names := make([]string, len(services))
+	for i, service := range services {
+		names[i] = service.Name
+	}
+	return names
+}
+
+func typeNames(types []*ast.Type) []string {
+	names := make([]string, len(types))
+	for i, typ := range types {
+		names[i] = typ.Name
+	}
+	return names
+}
+
+func enumNames(enums []*ast.Enum) []string {
+	names := make([]string, len(enums))
+	for i, enum := range enums {
+		names[i] = enum.Name
+	}
+	return names
+}
+
+func unionNames(unions []*ast.Union) []string {
+	names := make([]string, len(unions))
+	for i, union := range unions {
+		names[i] = union.Name
+	}
+	return names
+}
+
+const htmlPageFooter = `</main>
+</body>
+</html>
+`
+
+const htmlMermaidScript = `<script type="module">
+import mermaid from "https://cdn.jsdelivr.net/npm/mermaid@10/dist/mermaid.esm.min.mjs";
+mermaid.initialize({ startOnLoad: true });
+</script>
+`
+
+const htmlSearchRuntime = `
+document.addEventListener("DOMContentLoaded", function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+
+  input.addEventListener("input", function () {
+    var query = input.value.trim().toLowerCase();
+    results.innerHTML = "";
+    if (!query) {
+      results.hidden = true;
+      return;
+    }
+
+    var matches = searchIndex.filter(function (entry) {
+      return entry.name.toLowerCase().indexOf(query) !== -1;
+    }).slice(0, 25);
+
+    matches.forEach(function (entry) {
+      var item = document.createElement("li");
+      var link = document.createElement("a");
+      link.href = entry.url;
+      link.textContent = entry.name;
+      var kind = document.createElement("span");
+      kind.className = "kind";
+      kind.textContent = entry.kind + " · " + entry.namespace;
+      link.appendChild(kind);
+      item.appendChild(link);
+      results.appendChild(item);
+    });
+    results.hidden = matches.length === 0;
+  });
+
+  input.addEventListener("keydown", function (event) {
+    if (event.key === "Enter" && results.firstChild) {
+      window.location.href = results.firstChild.firstChild.href;
+    }
+  });
+});
+`
+
+const htmlStylesheet = `body {
+  margin: 0;
+  font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif;
+  color: #24292f;
+  display: grid;
+  grid-template-columns: 16rem 1fr;
+  grid-template-rows: auto 1fr;
+  min-height: 100vh;
+}
+
+header {
+  grid-column: 1 / 3;
+  display: flex;
+  align-items: center;
+  gap: 2rem;
+  padding: 0.75rem 1.5rem;
+  background: #24292f;
+}
+
+header .home {
+  color: #fff;
+  font-weight: 600;
+  text-decoration: none;
+}
+
+.search {
+  position: relative;
+  flex: 1;
+  max-width: 32rem;
+}
+
+#search {
+  width: 100%;
+  padding: 0.4rem 0.6rem;
+  border: 0;
+  border-radius: 4px;
+}
+
+#search-results {
+  position: absolute;
+  z-index: 10;
+  left: 0;
+  right: 0;
+  margin: 0.25rem 0 0;
+  padding: 0;
+  list-style: none;
+  background: #fff;
+  border: 1px solid #d0d7de;
+  border-radius: 4px;
+  max-height: 24rem;
+  overflow-y: auto;
+}
+
+#search-results a {
+  display: flex;
+  justify-content: space-between;
+  padding: 0.4rem 0.6rem;
+  color: #24292f;
+  text-decoration: none;
+}
+
+#search-results a:hover {
+  background: #f6f8fa;
+}
+
+nav {
+  padding: 1rem 1.5rem;
+  border-right: 1px solid #d0d7de;
+  background: #f6f8fa;
+  overflow-y: auto;
+}
+
+nav h2,
+nav h3 {
+  font-size: 0.8rem;
+  text-transform: uppercase;
+  color: #57606a;
+}
+
+nav ul {
+  margin: 0;
+  padding: 0;
+  list-style: none;
+}
+
+nav li.current a {
+  font-weight: 600;
+}
+
+main {
+  padding: 1rem 2rem 3rem;
+  max-width: 64rem;
+}
+
+a {
+  color: #0969da;
+}
+
+section {
+  margin-bottom: 2.5rem;
+}
+
+.kind {
+  font-size: 0.75rem;
+  font-weight: normal;
+  color: #57606a;
+  margin-left: 0.5rem;
+}
+
+details.views {
+  margin-top: 1rem;
+}
+
+details.views summary {
+  cursor: pointer;
+  font-weight: 600;
+}
+
+pre {
+  padding: 0.75rem;
+  background: #f6f8fa;
+  border-radius: 4px;
+  overflow-x: auto;
+}
+
+.deprecated {
+  color: #cf222e;
+}
+
+table {
+  border-collapse: collapse;
+  width: 100%;
+}
+
+th,
+td {
+  text-align: left;
+  padding: 0.4rem 0.6rem;
+  border-bottom: 1px solid #d0d7de;
+  vertical-align: top;
+}
+
+code {
+  font-family: SFMono-Regular, Consolas, "Liberation Mono", Menlo, monospace;
+  font-size: 0.875rem;
+}
+`
@@ -0,0 +1,173 @@
+package docgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func htmlTestSchema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "com.example.users",
+		Types: []*ast.Type{
+			{
+				Name:      "User",
+				Namespace: "com.example.users",
+				Doc:       &ast.Documentation{General: "A <registered> user"},
+				Fields: []*ast.Field{
+					{Name: "id", Required: true, Type: &ast.FieldType{Name: "string"}},
+					{Name: "role", Type: &ast.FieldType{Name: "Role"}},
+					{Name: "address", Type: &ast.FieldType{Name: "com.example.common.Address", Optional: true}},
+					{Name: "tags", Type: &ast.FieldType{Name: "string", IsArray: true}},
+				},
+			},
+			{
+				Name:      "Address",
+				Namespace: "com.example.common",
+				Fields: []*ast.Field{
+					{Name: "city", Type: &ast.FieldType{Name: "string"}},
+				},
+			},
+		},
+		Enums: []*ast.Enum{
+			{
+				Name:      "Role",
+				Namespace: "com.example.users",
+				Values: []*ast.EnumValue{
+					{Name: "ADMIN", Number: 1},
+				},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name:      "UserService",
+				Namespace: "com.example.users",
+				Methods: []*ast.Method{
+					{
+						Name:         "GetUser",
+						InputType:    "User",
+						OutputType:   "User",
+						OutputStream: true,
+						HTTPMethod:   "GET",
+						PathTemplate: "/users/{id}",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestHTMLGenerator_Pages(t *testing.T) {
+	files := NewHTMLGenerator().GenerateFiles(htmlTestSchema())
+
+	for _, name := range []string{"index.html", "style.css", "search.js", "com.example.users.html", "com.example.common.html"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("Expected file %s to be generated", name)
+		}
+	}
+
+	index := files["index.html"]
+	if !strings.Contains(index, "<title>com.example.users API Documentation</title>") {
+		t.Error("Expected index title with schema namespace")
+	}
+	if !strings.Contains(index, `<a href="com.example.common.html">com.example.common</a>`) {
+		t.Error("Expected index to link to namespace pages")
+	}
+	if !strings.Contains(index, `<input id="search"`) {
+		t.Error("Expected search box on index page")
+	}
+}
+
+func TestHTMLGenerator_CrossLinks(t *testing.T) {
+	files := NewHTMLGenerator().GenerateFiles(htmlTestSchema())
+	page := files["com.example.users.html"]
+
+	if !strings.Contains(page, `<section id="user">`) {
+		t.Error("Expected anchored section for User")
+	}
+	if !strings.Contains(page, `<a href="com.example.users.html#role">Role</a>`) {
+		t.Error("Expected same-namespace link to Role enum")
+	}
+	if !strings.Contains(page, `<a href="com.example.common.html#address">com.example.common.Address</a>?`) {
+		t.Error("Expected cross-namespace link to Address")
+	}
+	if !strings.Contains(page, "<code>[]string</code>") {
+		t.Error("Expected builtin array type without link")
+	}
+	if !strings.Contains(page, "stream <a href=\"com.example.users.html#user\">User</a>") {
+		t.Error("Expected streaming response link")
+	}
+	if !strings.Contains(page, "<code>GET /users/{id}</code>") {
+		t.Error("Expected HTTP mapping for method")
+	}
+	if !strings.Contains(page, `<li class="current"><a href="com.example.users.html">`) {
+		t.Error("Expected current namespace to be highlighted in navigation")
+	}
+}
+
+func TestHTMLGenerator_EscapesDocumentation(t *testing.T) {
+	files := NewHTMLGenerator().GenerateFiles(htmlTestSchema())
+	page := files["com.example.users.html"]
+
+	if strings.Contains(page, "<registered>") {
+		t.Error("Expected documentation to be HTML-escaped")
+	}
+	if !strings.Contains(page, "A &lt;registered&gt; user") {
+		t.Error("Expected escaped documentation text")
+	}
+}
+
+func TestHTMLGenerator_SearchIndex(t *testing.T) {
+	files := NewHTMLGenerator().GenerateFiles(htmlTestSchema())
+	script := files["search.js"]
+
+	expected := []string{
+		`{"name":"User","kind":"type","namespace":"com.example.users","url":"com.example.users.html#user"}`,
+		`{"name":"User.role","kind":"field","namespace":"com.example.users","url":"com.example.users.html#user.role"}`,
+		`{"name":"UserService.GetUser","kind":"method","namespace":"com.example.users","url":"com.example.users.html#userservice.getuser"}`,
+		`{"name":"Address","kind":"type","namespace":"com.example.common","url":"com.example.common.html#address"}`,
+	}
+	for _, entry := range expected {
+		if !strings.Contains(script, entry) {
+			t.Errorf("Expected search index entry %s", entry)
+		}
+	}
+}
+
+func TestHTMLGenerator_DefaultNamespace(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{{Name: "Thing"}},
+	}
+
+	files := NewHTMLGenerator().GenerateFiles(schema)
+	if _, ok := files["default.html"]; !ok {
+		t.Error("Expected elements without namespace on default.html")
+	}
+	if !strings.Contains(files["search.js"], "const searchIndex = [") {
+		t.Error("Expected search index array")
+	}
+}
+
+func TestHTMLGenerator_DocFiles(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "shop",
+		Types: []*ast.Type{{
+			Name: "Order",
+			Doc:  &ast.Documentation{General: "An order", Extended: "# Order\n\nOrders are **immutable**."},
+			Fields: []*ast.Field{
+				{Name: "total", Type: &ast.FieldType{Name: "int64"}, Doc: &ast.Documentation{Extended: "In cents."}},
+			},
+		}},
+	}
+
+	page := NewHTMLGenerator().GenerateFiles(schema)["shop.html"]
+	for _, expected := range []string{
+		"<p>An order</p>\n<h4>Order</h4>\n<p>Orders are <strong>immutable</strong>.</p>\n",
+		"<div class=\"details\" id=\"order.total-details\">\n<h4><code>total</code></h4>\n<p>In cents.</p>\n</div>\n",
+	} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("Expected page to contain %q, got:\n%s", expected, page)
+		}
+	}
+}
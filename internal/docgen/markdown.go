@@ -5,16 +5,28 @@ import (
 	"strings"
 
 	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/graph"
 )
 
 // MarkdownGenerator generates Markdown API documentation from TypeMUX schemas.
-type MarkdownGenerator struct{}
+type MarkdownGenerator struct {
+	opts Options
+}
 
 // NewMarkdownGenerator creates a new Markdown documentation generator.
 func NewMarkdownGenerator() *MarkdownGenerator {
 	return &MarkdownGenerator{}
 }
 
+// NewMarkdownGeneratorWithOptions creates a Markdown documentation generator with the given options.
+func NewMarkdownGeneratorWithOptions(opts *Options) *MarkdownGenerator {
+	g := &MarkdownGenerator{}
+	if opts != nil {
+		g.opts = *opts
+	}
+	return g
+}
+
 // Generate creates a Markdown documentation string from the given schema.
 func (g *MarkdownGenerator) Generate(schema *ast.Schema) string {
 	var sb strings.Builder
@@ -28,6 +40,9 @@ func (g *MarkdownGenerator) Generate(schema *ast.Schema) string {
 
 	// Table of Contents
 	sb.WriteString("## Table of Contents\n\n")
+	if g.opts.Diagrams {
+		sb.WriteString("- [Diagram](#diagram)\n")
+	}
 	if len(schema.Types) > 0 {
 		sb.WriteString("- [Types](#types)\n")
 	}
@@ -42,9 +57,21 @@ func (g *MarkdownGenerator) Generate(schema *ast.Schema) string {
 	}
 	sb.WriteString("\n")
 
+	// Dependency diagram
+	if g.opts.Diagrams {
+		sb.WriteString("## Diagram\n\n")
+		sb.WriteString(fmt.Sprintf("```mermaid\n%s```\n\n", graph.Build(schema).Mermaid()))
+	}
+
 	// Types Section
 	if len(schema.Types) > 0 {
 		sb.WriteString("## Types\n\n")
+		if note := int64Note(g.opts.Int64, schema.Types); note != "" {
+			sb.WriteString(fmt.Sprintf("**64-bit integers:** %s\n\n", note))
+		}
+		if note := bytesNote(schema.Types); note != "" {
+			sb.WriteString(fmt.Sprintf("**Binary data:** %s\n\n", note))
+		}
 		for _, typ := range schema.Types {
 			sb.WriteString(g.generateTypeDoc(typ))
 			sb.WriteString("\n")
@@ -89,10 +116,16 @@ func (g *MarkdownGenerator) generateTypeDoc(typ *ast.Type) string {
 
 	// Documentation
 	if typ.Doc != nil {
-		if doc := typ.Doc.GetDoc(""); doc != "" {
+		if doc := typ.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 			sb.WriteString(fmt.Sprintf("%s\n\n", doc))
 		}
 	}
+	sb.WriteString(extendedMarkdown(typ.Doc, 4))
+
+	// Base types, whose fields are listed first
+	if len(typ.Extends) > 0 {
+		sb.WriteString(fmt.Sprintf("**Extends:** `%s`\n\n", strings.Join(typ.Extends, "`, `")))
+	}
 
 	// Fields table
 	if len(typ.Fields) > 0 {
@@ -102,15 +135,13 @@ func (g *MarkdownGenerator) generateTypeDoc(typ *ast.Type) string {
 		for _, field := range typ.Fields {
 			typeName := g.formatFieldType(field.Type)
 			required := "No"
-			if field.Required && !field.Type.Optional {
+			if field.Presence() == ast.PresenceRequired {
 				required = "Yes"
-			} else if field.Type.Optional {
-				required = "No"
 			}
 
 			description := ""
 			if field.Doc != nil {
-				description = strings.ReplaceAll(field.Doc.GetDoc(""), "\n", " ")
+				description = strings.ReplaceAll(field.Doc.GetLocaleDoc(g.opts.Locale), "\n", " ")
 			}
 
 			// Add deprecation notice
@@ -133,6 +164,11 @@ func (g *MarkdownGenerator) generateTypeDoc(typ *ast.Type) string {
 				description))
 		}
 		sb.WriteString("\n")
+
+		// Extended field documentation from @doc-file
+		for _, field := range typ.Fields {
+			writeMarkdownDetails(&sb, 4, field.Name, field.Doc)
+		}
 	}
 
 	return sb.String()
@@ -146,10 +182,11 @@ func (g *MarkdownGenerator) generateEnumDoc(enum *ast.Enum) string {
 
 	// Documentation
 	if enum.Doc != nil {
-		if doc := enum.Doc.GetDoc(""); doc != "" {
+		if doc := enum.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 			sb.WriteString(fmt.Sprintf("%s\n\n", doc))
 		}
 	}
+	sb.WriteString(extendedMarkdown(enum.Doc, 4))
 
 	// Values table
 	if len(enum.Values) > 0 {
@@ -159,7 +196,7 @@ func (g *MarkdownGenerator) generateEnumDoc(enum *ast.Enum) string {
 		for _, value := range enum.Values {
 			description := ""
 			if value.Doc != nil {
-				description = strings.ReplaceAll(value.Doc.GetDoc(""), "\n", " ")
+				description = strings.ReplaceAll(value.Doc.GetLocaleDoc(g.opts.Locale), "\n", " ")
 			}
 
 			sb.WriteString(fmt.Sprintf("| `%s` | %d | %s |\n",
@@ -168,6 +205,11 @@ func (g *MarkdownGenerator) generateEnumDoc(enum *ast.Enum) string {
 				description))
 		}
 		sb.WriteString("\n")
+
+		// Extended value documentation from @doc-file
+		for _, value := range enum.Values {
+			writeMarkdownDetails(&sb, 4, value.Name, value.Doc)
+		}
 	}
 
 	return sb.String()
@@ -181,10 +223,11 @@ func (g *MarkdownGenerator) generateUnionDoc(union *ast.Union) string {
 
 	// Documentation
 	if union.Doc != nil {
-		if doc := union.Doc.GetDoc(""); doc != "" {
+		if doc := union.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 			sb.WriteString(fmt.Sprintf("%s\n\n", doc))
 		}
 	}
+	sb.WriteString(extendedMarkdown(union.Doc, 4))
 
 	// Options
 	sb.WriteString("**Possible types:**\n\n")
@@ -193,6 +236,11 @@ func (g *MarkdownGenerator) generateUnionDoc(union *ast.Union) string {
 	}
 	sb.WriteString("\n")
 
+	// JSON encoding
+	if encoding := union.Encoding; encoding != nil && len(union.Options) > 0 {
+		sb.WriteString(fmt.Sprintf("**JSON encoding:** %s, such as `%s`\n\n", encoding.Strategy.Describe(), encoding.Example(ast.GetUnqualifiedName(union.Options[0]))))
+	}
+
 	return sb.String()
 }
 
@@ -204,24 +252,25 @@ func (g *MarkdownGenerator) generateServiceDoc(service *ast.Service) string {
 
 	// Documentation
 	if service.Doc != nil {
-		if doc := service.Doc.GetDoc(""); doc != "" {
+		if doc := service.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 			sb.WriteString(fmt.Sprintf("%s\n\n", doc))
 		}
 	}
+	sb.WriteString(extendedMarkdown(service.Doc, 4))
 
 	// Methods
 	if len(service.Methods) > 0 {
 		sb.WriteString("#### Methods\n\n")
 
 		for _, method := range service.Methods {
-			sb.WriteString(g.generateMethodDoc(method))
+			sb.WriteString(g.generateMethodDoc(service, method))
 		}
 	}
 
 	return sb.String()
 }
 
-func (g *MarkdownGenerator) generateMethodDoc(method *ast.Method) string {
+func (g *MarkdownGenerator) generateMethodDoc(service *ast.Service, method *ast.Method) string {
 	var sb strings.Builder
 
 	// Method signature
@@ -238,39 +287,44 @@ func (g *MarkdownGenerator) generateMethodDoc(method *ast.Method) string {
 
 	// Documentation
 	if method.Doc != nil {
-		if doc := method.Doc.GetDoc(""); doc != "" {
+		if doc := method.Doc.GetLocaleDoc(g.opts.Locale); doc != "" {
 			sb.WriteString(fmt.Sprintf("%s\n\n", doc))
 		}
 	}
+	sb.WriteString(extendedMarkdown(method.Doc, 6))
 
 	// Request/Response
-	sb.WriteString(fmt.Sprintf("**Request:** `%s`\n\n", method.InputType))
-	sb.WriteString(fmt.Sprintf("**Response:** `%s`\n\n", method.OutputType))
+	sb.WriteString(fmt.Sprintf("**Request:** %s\n\n", markdownMethodType(method.InputType)))
+	sb.WriteString(fmt.Sprintf("**Response:** %s\n\n", markdownMethodType(method.OutputType)))
 
 	// HTTP mapping (if available)
 	if method.HTTPMethod != "" && method.PathTemplate != "" {
-		sb.WriteString(fmt.Sprintf("**HTTP:** `%s %s`\n\n", method.HTTPMethod, method.PathTemplate))
+		sb.WriteString(fmt.Sprintf("**HTTP:** `%s %s`\n\n", method.HTTPMethod, method.HTTPPath(service)))
 	}
 
-	return sb.String()
-}
-
-func (g *MarkdownGenerator) formatFieldType(fieldType *ast.FieldType) string {
-	var typeName string
+	writeMarkdownPolicies(&sb, method)
 
-	if fieldType.IsMap {
-		typeName = fmt.Sprintf("map<%s, %s>", fieldType.MapKey, fieldType.MapValue)
-	} else {
-		typeName = fieldType.Name
+	// Format-specific views
+	if g.opts.FormatViews {
+		writeMarkdownMethodViews(&sb, service, method, 6)
 	}
 
-	if fieldType.IsArray {
-		typeName = "[]" + typeName
-	}
+	return sb.String()
+}
 
+func (g *MarkdownGenerator) formatFieldType(fieldType *ast.FieldType) string {
+	typeName := fieldType.String()
 	if fieldType.Optional {
 		typeName += "?"
 	}
-
 	return typeName
 }
+
+// markdownMethodType formats the input or output type of a method, which is
+// empty for methods declared with ()
+func markdownMethodType(typeName string) string {
+	if typeName == "" {
+		return "none"
+	}
+	return fmt.Sprintf("`%s`", typeName)
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/generator"
 )
 
 func TestGenerateBasicMarkdown(t *testing.T) {
@@ -286,3 +287,174 @@ func TestGenerateDeprecatedFieldMarkdown(t *testing.T) {
 		t.Error("Expected deprecation reason")
 	}
 }
+
+func TestGenerateExtendsMarkdown(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "test",
+		Types: []*ast.Type{
+			{Name: "Entity", Namespace: "test", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string"}}}},
+			{Name: "User", Namespace: "test", Extends: []string{"test.Entity"}},
+		},
+	}
+	if err := schema.ResolveExtends(); err != nil {
+		t.Fatalf("ResolveExtends failed: %v", err)
+	}
+
+	output := NewMarkdownGenerator().Generate(schema)
+	if !strings.Contains(output, "### User\n\n**Extends:** `test.Entity`\n\n| Field |") {
+		t.Errorf("Expected base types before the fields table, got:\n%s", output)
+	}
+	if strings.Count(output, "| `id` | `string` |") != 2 {
+		t.Error("Expected inherited field to be listed on the extending type")
+	}
+}
+
+func TestGeneratePoliciesMarkdown(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "test",
+		Services: []*ast.Service{
+			{
+				Name: "OrderService",
+				Methods: []*ast.Method{
+					{
+						Name:       "GetOrder",
+						InputType:  "GetOrderRequest",
+						OutputType: "Order",
+						Timeout:    "5s",
+						Idempotent: true,
+						RateLimit:  &ast.RateLimit{Requests: 100, Per: "minute"},
+					},
+					{Name: "CreateOrder", InputType: "CreateOrderRequest", OutputType: "Order"},
+				},
+			},
+		},
+	}
+
+	output := NewMarkdownGenerator().Generate(schema)
+
+	expected := "**Policies:**\n\n- Timeout: 5s\n- Idempotent: safe to retry\n- Rate limit: 100 requests per minute\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+	}
+	if strings.Count(output, "**Policies:**") != 1 {
+		t.Error("Expected only methods with policies to list them")
+	}
+}
+
+func TestGenerateLocalizedMarkdown(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "test",
+		Types: []*ast.Type{
+			{
+				Name: "User",
+				Doc:  &ast.Documentation{General: "A user", Locales: map[string]string{"es": "Un usuario"}},
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string"}, Doc: &ast.Documentation{General: "Unique identifier"}},
+				},
+			},
+		},
+	}
+
+	output := NewMarkdownGeneratorWithOptions(&Options{Locale: "es-MX"}).Generate(schema)
+	if !strings.Contains(output, "Un usuario") {
+		t.Errorf("Expected the es translation for es-MX, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Unique identifier") {
+		t.Errorf("Expected untranslated docs to fall back to the general text, got:\n%s", output)
+	}
+
+	if output := NewMarkdownGenerator().Generate(schema); strings.Contains(output, "Un usuario") {
+		t.Errorf("Expected no translation without a locale, got:\n%s", output)
+	}
+}
+
+func TestGenerateMarkdown_UnionEncoding(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "test",
+		Unions: []*ast.Union{
+			{Name: "Shape", Options: []string{"Circle", "Square"}, Encoding: &ast.UnionEncoding{Strategy: ast.UnionAdjacent, Tag: "kind"}},
+			{Name: "Result", Options: []string{"Ok", "Failed"}},
+		},
+	}
+
+	output := NewMarkdownGenerator().Generate(schema)
+	if !strings.Contains(output, "**JSON encoding:** adjacently tagged, such as `{\"kind\": \"Circle\", \"value\": {...}}`") {
+		t.Errorf("Expected the JSON encoding of Shape, got:\n%s", output)
+	}
+	if strings.Count(output, "**JSON encoding:**") != 1 {
+		t.Errorf("Expected no JSON encoding for a union without one, got:\n%s", output)
+	}
+}
+
+func TestGenerateMarkdown_Int64Encoding(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "test",
+		Types: []*ast.Type{{Name: "Account", Fields: []*ast.Field{
+			{Name: "totals", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "int64"}},
+		}}},
+	}
+
+	output := NewMarkdownGenerator().Generate(schema)
+	if strings.Contains(output, "**64-bit integers:**") {
+		t.Errorf("Expected no int64 note without an encoding, got:\n%s", output)
+	}
+
+	output = NewMarkdownGeneratorWithOptions(&Options{Int64: generator.Int64String}).Generate(schema)
+	if !strings.Contains(output, "**64-bit integers:** int64 and uint64 fields are encoded as JSON strings") {
+		t.Errorf("Expected the int64 encoding note, got:\n%s", output)
+	}
+
+	schema.Types[0].Fields[0].Type.MapValue = "int32"
+	output = NewMarkdownGeneratorWithOptions(&Options{Int64: generator.Int64String}).Generate(schema)
+	if strings.Contains(output, "**64-bit integers:**") {
+		t.Errorf("Expected no int64 note for a schema without 64-bit integers, got:\n%s", output)
+	}
+}
+
+func TestGenerateMarkdown_BytesNote(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "test",
+		Types: []*ast.Type{{Name: "File", Fields: []*ast.Field{
+			{Name: "content", Type: &ast.FieldType{Name: "bytes", IsBuiltin: true}},
+		}}},
+	}
+
+	output := NewMarkdownGenerator().Generate(schema)
+	if !strings.Contains(output, "**Binary data:** bytes fields are base64-encoded strings in JSON") {
+		t.Errorf("Expected the bytes encoding note, got:\n%s", output)
+	}
+
+	schema.Types[0].Fields[0].Type.Name = "string"
+	output = NewMarkdownGenerator().Generate(schema)
+	if strings.Contains(output, "**Binary data:**") {
+		t.Errorf("Expected no bytes note for a schema without bytes, got:\n%s", output)
+	}
+}
+
+func TestGenerateMarkdown_DocFiles(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "test",
+		Types: []*ast.Type{{
+			Name: "User",
+			Doc:  &ast.Documentation{General: "A user", File: "docs/user.md", Extended: "# User\n\n## Lifecycle\n\nUsers are created on sign-up.\n"},
+			Fields: []*ast.Field{
+				{Name: "email", Type: &ast.FieldType{Name: "string"}, Doc: &ast.Documentation{General: "Email address", Extended: "Must be verified."}},
+				{Name: "name", Type: &ast.FieldType{Name: "string"}},
+			},
+		}},
+	}
+
+	output := NewMarkdownGenerator().Generate(schema)
+	for _, expected := range []string{
+		"A user\n\n#### User\n\n##### Lifecycle\n\nUsers are created on sign-up.\n\n",
+		"| `email` | `string` | No | Email address |",
+		"#### `email`\n\nMust be verified.\n\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "#### `name`") {
+		t.Errorf("Expected no details for a field without a doc file, got:\n%s", output)
+	}
+}
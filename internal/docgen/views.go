@@ -0,0 +1,188 @@
+package docgen
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// methodView describes how a service method appears in one output format
+type methodView struct {
+	title     string   // Format name shown as heading
+	language  string   // Code block language
+	signature string   // How the method is invoked or declared in the format
+	details   []string // Additional facts, such as response codes
+	doc       string   // Format-specific documentation
+}
+
+// methodViews returns the REST, gRPC, and GraphQL views of a service method
+func methodViews(service *ast.Service, method *ast.Method) []methodView {
+	return []methodView{
+		restView(service, method),
+		grpcView(service, method),
+		graphqlView(method),
+	}
+}
+
+// restView describes the HTTP endpoint of a method
+func restView(service *ast.Service, method *ast.Method) methodView {
+	path := method.HTTPPath(service)
+
+	view := methodView{
+		title:     "REST",
+		language:  "http",
+		signature: fmt.Sprintf("%s %s", strings.ToUpper(method.GetHTTPMethod()), path),
+		doc:       formatDoc(method.Doc, "openapi"),
+	}
+
+	successCodes := method.SuccessCodes
+	if len(successCodes) == 0 {
+		successCodes = []string{"200"}
+		if method.LongRunning != nil {
+			successCodes = []string{"202"}
+		} else if !method.HasOutput() {
+			successCodes = []string{"204"}
+		}
+	}
+	view.details = append(view.details, "Success: "+strings.Join(successCodes, ", "))
+	if len(method.ErrorCodes) > 0 {
+		view.details = append(view.details, "Errors: "+strings.Join(method.ErrorCodes, ", "))
+	}
+
+	return view
+}
+
+// grpcView describes the RPC declaration of a method
+func grpcView(service *ast.Service, method *ast.Method) methodView {
+	input := ast.GetUnqualifiedName(method.InputType)
+	if !method.HasInput() {
+		input = "google.protobuf.Empty"
+	}
+	if method.InputStream {
+		input = "stream " + input
+	}
+	output := ast.GetUnqualifiedName(method.OutputType)
+	if !method.HasOutput() {
+		output = "google.protobuf.Empty"
+	}
+	if method.OutputStream {
+		output = "stream " + output
+	}
+
+	serviceName := service.Name
+	if service.Namespace != "" {
+		serviceName = service.Namespace + "." + service.Name
+	}
+
+	return methodView{
+		title:     "gRPC",
+		language:  "protobuf",
+		signature: fmt.Sprintf("rpc %s(%s) returns (%s);", method.Name, input, output),
+		details:   []string{fmt.Sprintf("Full method: /%s/%s", serviceName, method.Name)},
+		doc:       formatDoc(method.Doc, "proto"),
+	}
+}
+
+// graphqlView describes the GraphQL operation field of a method
+func graphqlView(method *ast.Method) methodView {
+	operation := method.GetGraphQLType()
+	fieldName := strings.ToLower(method.Name[:1]) + method.Name[1:]
+
+	arguments := ""
+	if method.HasInput() {
+		arguments = fmt.Sprintf("(input: %s)", ast.GetUnqualifiedName(method.InputType))
+	}
+	output := "Boolean"
+	if method.HasOutput() {
+		output = ast.GetUnqualifiedName(method.OutputType)
+	}
+
+	return methodView{
+		title:    "GraphQL",
+		language: "graphql",
+		signature: fmt.Sprintf("type %s {\n  %s%s: %s\n}",
+			strings.ToUpper(operation[:1])+operation[1:], fieldName, arguments, output),
+		details: []string{"Operation: " + operation},
+		doc:     formatDoc(method.Doc, "graphql"),
+	}
+}
+
+// formatDoc returns documentation written specifically for one format
+func formatDoc(doc *ast.Documentation, lang string) string {
+	if doc == nil {
+		return ""
+	}
+	return doc.Specific[lang]
+}
+
+// writeMarkdownMethodViews renders the format views of a method as Markdown
+func writeMarkdownMethodViews(sb *strings.Builder, service *ast.Service, method *ast.Method, headingLevel int) {
+	heading := strings.Repeat("#", headingLevel)
+	for _, view := range methodViews(service, method) {
+		sb.WriteString(fmt.Sprintf("%s %s\n\n", heading, view.title))
+		if view.doc != "" {
+			sb.WriteString(fmt.Sprintf("%s\n\n", view.doc))
+		}
+		sb.WriteString(fmt.Sprintf("```%s\n%s\n```\n\n", view.language, view.signature))
+		for _, detail := range view.details {
+			sb.WriteString(fmt.Sprintf("- %s\n", detail))
+		}
+		if len(view.details) > 0 {
+			sb.WriteString("\n")
+		}
+	}
+}
+
+// writeHTMLMethodViews renders the format views of a method as HTML
+func writeHTMLMethodViews(sb *strings.Builder, service *ast.Service, method *ast.Method) {
+	sb.WriteString("<details class=\"views\">\n")
+	sb.WriteString(fmt.Sprintf("<summary>%s in each format</summary>\n", html.EscapeString(method.Name)))
+	for _, view := range methodViews(service, method) {
+		sb.WriteString(fmt.Sprintf("<h4>%s</h4>\n", view.title))
+		if view.doc != "" {
+			sb.WriteString(fmt.Sprintf("<p>%s</p>\n", html.EscapeString(view.doc)))
+		}
+		sb.WriteString(fmt.Sprintf("<pre><code class=\"language-%s\">%s</code></pre>\n", view.language, html.EscapeString(view.signature)))
+		if len(view.details) > 0 {
+			sb.WriteString("<ul>\n")
+			for _, detail := range view.details {
+				sb.WriteString(fmt.Sprintf("<li>%s</li>\n", html.EscapeString(detail)))
+			}
+			sb.WriteString("</ul>\n")
+		}
+	}
+	sb.WriteString("</details>\n")
+}
+
+// policyFacts describes the timeout, idempotency, rate limit, and authentication of a method
+func policyFacts(method *ast.Method) []string {
+	var facts []string
+	if method.Timeout != "" {
+		facts = append(facts, "Timeout: "+method.Timeout)
+	}
+	if method.Idempotent {
+		facts = append(facts, "Idempotent: safe to retry")
+	}
+	if method.RateLimit != nil {
+		facts = append(facts, "Rate limit: "+method.RateLimit.String())
+	}
+	if method.Auth != nil {
+		facts = append(facts, "Auth: "+method.Auth.String())
+	}
+	return facts
+}
+
+// writeMarkdownPolicies writes the call policies of a method as a list
+func writeMarkdownPolicies(sb *strings.Builder, method *ast.Method) {
+	facts := policyFacts(method)
+	if len(facts) == 0 {
+		return
+	}
+	sb.WriteString("**Policies:**\n\n")
+	for _, fact := range facts {
+		sb.WriteString(fmt.Sprintf("- %s\n", fact))
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,144 @@
+package docgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func viewsTestSchema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "users.v1",
+		Services: []*ast.Service{
+			{
+				Name:      "UserService",
+				Namespace: "users.v1",
+				Methods: []*ast.Method{
+					{
+						Name:         "CreateUser",
+						InputType:    "CreateUserRequest",
+						OutputType:   "User",
+						HTTPMethod:   "POST",
+						PathTemplate: "/users",
+						SuccessCodes: []string{"201"},
+						ErrorCodes:   []string{"400", "409"},
+						Doc: &ast.Documentation{
+							General: "Creates a user",
+							Specific: map[string]string{
+								"proto":   "Idempotent when request_id is set",
+								"graphql": "Returns the created user",
+								"openapi": "Responds with a Location header",
+							},
+						},
+					},
+					{
+						Name:         "WatchUsers",
+						InputType:    "WatchUsersRequest",
+						OutputType:   "User",
+						OutputStream: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestMarkdownGenerator_FormatViews(t *testing.T) {
+	output := NewMarkdownGeneratorWithOptions(&Options{FormatViews: true}).Generate(viewsTestSchema())
+
+	expected := []string{
+		"###### REST\n\nResponds with a Location header\n\n```http\nPOST /users\n```",
+		"- Success: 201\n- Errors: 400, 409",
+		"###### gRPC\n\nIdempotent when request_id is set\n\n```protobuf\nrpc CreateUser(CreateUserRequest) returns (User);\n```",
+		"- Full method: /users.v1.UserService/CreateUser",
+		"###### GraphQL\n\nReturns the created user\n\n```graphql\ntype Mutation {\n  createUser(input: CreateUserRequest): User\n}\n```",
+		"rpc WatchUsers(WatchUsersRequest) returns (stream User);",
+		"type Subscription {\n  watchUsers(input: WatchUsersRequest): User\n}",
+		"POST /userservice/watchusers",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain:\n%s\n\nGot:\n%s", want, output)
+		}
+	}
+}
+
+func TestMarkdownGenerator_FormatViewsDisabled(t *testing.T) {
+	output := NewMarkdownGenerator().Generate(viewsTestSchema())
+
+	if strings.Contains(output, "gRPC") || strings.Contains(output, "Idempotent") {
+		t.Error("Expected no format views by default")
+	}
+}
+
+func TestHTMLGenerator_FormatViews(t *testing.T) {
+	files := NewHTMLGeneratorWithOptions(&Options{FormatViews: true}).GenerateFiles(viewsTestSchema())
+	page := files["users.v1.html"]
+
+	if !strings.Contains(page, "<summary>CreateUser in each format</summary>") {
+		t.Error("Expected collapsible format views for CreateUser")
+	}
+	if !strings.Contains(page, `<pre><code class="language-protobuf">rpc CreateUser(CreateUserRequest) returns (User);</code></pre>`) {
+		t.Error("Expected gRPC signature")
+	}
+	if !strings.Contains(page, "<p>Returns the created user</p>") {
+		t.Error("Expected GraphQL-specific documentation")
+	}
+}
+
+func TestGenerator_FormatViews(t *testing.T) {
+	outputDir := t.TempDir()
+
+	gen := NewGeneratorWithOptions(viewsTestSchema(), outputDir, &Options{FormatViews: true})
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "services", "userservice.md"))
+	if err != nil {
+		t.Fatalf("Failed to read service doc: %v", err)
+	}
+	if !strings.Contains(string(content), "#### REST\n\nResponds with a Location header") {
+		t.Errorf("Expected REST view in service doc, got:\n%s", content)
+	}
+}
+
+func TestMarkdownGenerator_Diagrams(t *testing.T) {
+	schema := viewsTestSchema()
+	schema.Types = []*ast.Type{
+		{Name: "User", Namespace: "users.v1"},
+		{Name: "CreateUserRequest", Namespace: "users.v1"},
+	}
+
+	output := NewMarkdownGeneratorWithOptions(&Options{Diagrams: true}).Generate(schema)
+
+	if !strings.Contains(output, "- [Diagram](#diagram)") {
+		t.Error("Expected diagram entry in table of contents")
+	}
+	if !strings.Contains(output, "## Diagram\n\n```mermaid\nflowchart LR\n") {
+		t.Errorf("Expected Mermaid diagram block, got:\n%s", output)
+	}
+	if !strings.Contains(output, "users_v1_UserService -->|CreateUser| users_v1_CreateUserRequest") {
+		t.Error("Expected service to request type edge")
+	}
+
+	plain := NewMarkdownGenerator().Generate(schema)
+	if strings.Contains(plain, "mermaid") {
+		t.Error("Expected no diagram by default")
+	}
+}
+
+func TestHTMLGenerator_Diagrams(t *testing.T) {
+	files := NewHTMLGeneratorWithOptions(&Options{Diagrams: true}).GenerateFiles(viewsTestSchema())
+	index := files["index.html"]
+
+	if !strings.Contains(index, "<pre class=\"mermaid\">\nflowchart LR\n") {
+		t.Error("Expected Mermaid diagram on index page")
+	}
+	if !strings.Contains(index, "mermaid.initialize") {
+		t.Error("Expected Mermaid script on index page")
+	}
+}
@@ -0,0 +1,566 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// ContractTestGenerator generates Go contract tests that exercise every service
+// method against a live provider and validate responses against the schema.
+type ContractTestGenerator struct {
+	examples  *exampleBuilder
+	flagEnums map[string]bool // Flags enums, checked as integer bitmasks
+}
+
+// NewContractTestGenerator creates a new contract test generator.
+func NewContractTestGenerator() *ContractTestGenerator {
+	return &ContractTestGenerator{}
+}
+
+// Generate produces the source of a Go test file (package contract).
+func (g *ContractTestGenerator) Generate(schema *ast.Schema) string {
+	g.examples = newExampleBuilder(schema)
+	g.flagEnums = flagEnumNames(schema)
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by TypeMUX. DO NOT EDIT.\n\n")
+	sb.WriteString("// Package contract verifies a running provider against the TypeMUX schema.\n")
+	sb.WriteString("//\n")
+	sb.WriteString("// Set TYPEMUX_CONTRACT_BASE_URL to the provider's base URL and run go test; the tests\n")
+	sb.WriteString("// are skipped when it is unset. Request bodies default to generated examples and can be\n")
+	sb.WriteString("// replaced by <Service>.<Method>.json files in the TYPEMUX_CONTRACT_FIXTURES directory.\n")
+	sb.WriteString("package contract\n\n")
+	sb.WriteString(contractTestImports)
+
+	g.writeEnumValues(&sb, schema)
+	g.writeUnionOptions(&sb, schema)
+	g.writeUnionEncodings(&sb, schema)
+	g.writeTypeSpecs(&sb, schema)
+
+	for _, service := range schema.Services {
+		for _, method := range service.Methods {
+			// The provider sends webhooks rather than serving them
+			if !method.IsWebhook() {
+				g.writeMethodTest(&sb, service, method)
+			}
+		}
+	}
+
+	sb.WriteString(contractTestRuntime)
+
+	// Align composite literals the way gofmt would; fall back to the raw source on error
+	formatted, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return sb.String()
+	}
+	return string(formatted)
+}
+
+// writeEnumValues emits the allowed values for every enum; flags enums are
+// checked as integers instead
+func (g *ContractTestGenerator) writeEnumValues(sb *strings.Builder, schema *ast.Schema) {
+	sb.WriteString("// enumValues lists the allowed values of every enum.\n")
+	sb.WriteString("var enumValues = map[string][]string{\n")
+	for _, enum := range schema.Enums {
+		if enum.Flags {
+			continue
+		}
+		values := make([]string, len(enum.Values))
+		for i, value := range enum.Values {
+			values[i] = strconv.Quote(value.Name)
+		}
+		sb.WriteString(fmt.Sprintf("\t%q: {%s},\n", enum.Name, strings.Join(values, ", ")))
+	}
+	sb.WriteString("}\n\n")
+}
+
+// writeUnionOptions emits the option types of every union
+func (g *ContractTestGenerator) writeUnionOptions(sb *strings.Builder, schema *ast.Schema) {
+	sb.WriteString("// unionOptions lists the option types of every union.\n")
+	sb.WriteString("var unionOptions = map[string][]string{\n")
+	for _, union := range schema.Unions {
+		options := make([]string, len(union.Options))
+		for i, option := range union.Options {
+			options[i] = strconv.Quote(g.jsonKind(option))
+		}
+		sb.WriteString(fmt.Sprintf("\t%q: {%s},\n", union.Name, strings.Join(options, ", ")))
+	}
+	sb.WriteString("}\n\n")
+}
+
+// writeUnionEncodings emits the JSON encoding of every tagged union
+func (g *ContractTestGenerator) writeUnionEncodings(sb *strings.Builder, schema *ast.Schema) {
+	sb.WriteString("// unionEncodings lists the JSON encoding of every tagged union; the others match\n")
+	sb.WriteString("// their options in order.\n")
+	sb.WriteString("var unionEncodings = map[string]unionEncoding{\n")
+	for _, union := range schema.Unions {
+		encoding := union.Encoding
+		if encoding == nil || encoding.Strategy == ast.UnionUntagged {
+			continue
+		}
+		options := make([]string, len(union.Options))
+		for i, option := range union.Options {
+			options[i] = fmt.Sprintf("%q: %q", ast.GetUnqualifiedName(option), g.jsonKind(option))
+		}
+		sb.WriteString(fmt.Sprintf("\t%q: {strategy: %q, tag: %q, content: %q, options: map[string]string{%s}},\n",
+			union.Name, encoding.Strategy, encoding.TagProperty(), encoding.ContentProperty(), strings.Join(options, ", ")))
+	}
+	sb.WriteString("}\n\n")
+}
+
+// writeTypeSpecs emits the expected JSON shape of every type
+func (g *ContractTestGenerator) writeTypeSpecs(sb *strings.Builder, schema *ast.Schema) {
+	sb.WriteString("// typeSpecs lists the expected JSON fields of every type.\n")
+	sb.WriteString("var typeSpecs = map[string]map[string]fieldSpec{\n")
+	for _, typ := range schema.Types {
+		var fields []string
+		for _, field := range typ.Fields {
+			if !field.ShouldIncludeInGenerator("openapi") || len(field.Arguments) > 0 {
+				continue
+			}
+			name := field.Name
+			if field.JSONName != "" {
+				name = field.JSONName
+			}
+			fields = append(fields, fmt.Sprintf("\t\t%q: %s,\n", name, g.fieldSpecLiteral(field)))
+		}
+		if len(fields) == 0 {
+			sb.WriteString(fmt.Sprintf("\t%q: {},\n", typ.Name))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\t%q: {\n", typ.Name))
+		for _, field := range fields {
+			sb.WriteString(field)
+		}
+		sb.WriteString("\t},\n")
+	}
+	sb.WriteString("}\n\n")
+}
+
+// fieldSpecLiteral renders the fieldSpec composite literal for a field
+func (g *ContractTestGenerator) fieldSpecLiteral(field *ast.Field) string {
+	fieldType := field.Type
+	parts := []string{}
+
+	switch {
+	case fieldType.IsMap:
+		kind := "any"
+		if valueType := fieldType.GetMapValueType(); valueType != nil && !valueType.IsArray && !valueType.IsMap {
+			kind = g.jsonKind(valueType.Name)
+		}
+		parts = append(parts, fmt.Sprintf("kind: %q", kind), "mapOf: true")
+		if fieldType.IsArray {
+			parts = append(parts, "array: true")
+		}
+	case fieldType.IsArray && strings.HasPrefix(fieldType.Name, "[]"):
+		// Nested arrays are only checked at the outer level
+		parts = append(parts, `kind: "any"`, "array: true")
+	case fieldType.IsArray:
+		parts = append(parts, fmt.Sprintf("kind: %q", g.jsonKind(fieldType.Name)), "array: true")
+	default:
+		parts = append(parts, fmt.Sprintf("kind: %q", g.jsonKind(fieldType.Name)))
+	}
+
+	if field.Presence() == ast.PresenceRequired {
+		parts = append(parts, "required: true")
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// jsonKind maps a TypeMUX type name to the kind checked by the generated validator
+func (g *ContractTestGenerator) jsonKind(typeName string) string {
+	switch typeName {
+	case "string", "timestamp", "bytes":
+		return "string"
+	case "int32", "uint8", "uint16", "uint32":
+		return "integer"
+	case "int64", "uint64":
+		// Accept both JSON numbers and proto3-style decimal strings
+		return "int64"
+	case "float32", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	}
+	if g.flagEnums[typeName] {
+		return "integer"
+	}
+	return ast.GetUnqualifiedName(typeName)
+}
+
+// writeMethodTest emits the test function for one service method
+func (g *ContractTestGenerator) writeMethodTest(sb *strings.Builder, service *ast.Service, method *ast.Method) {
+	testName := fmt.Sprintf("Test%s_%s", service.Name, method.Name)
+	sb.WriteString(fmt.Sprintf("func %s(t *testing.T) {\n", testName))
+
+	if method.InputStream || method.OutputStream {
+		sb.WriteString("\tt.Skip(\"streaming methods are not verified over HTTP\")\n")
+		sb.WriteString("}\n\n")
+		return
+	}
+
+	success := []int{}
+	for _, code := range method.SuccessCodes {
+		if n, err := strconv.Atoi(code); err == nil {
+			success = append(success, n)
+		}
+	}
+	if len(success) == 0 {
+		// Methods without output answer with no content; long-running ones accept the work
+		status := 200
+		if method.LongRunning != nil {
+			status = 202
+		} else if !method.HasOutput() {
+			status = 204
+		}
+		success = append(success, status)
+	}
+	var failures []int
+	for _, code := range method.ErrorCodes {
+		if n, err := strconv.Atoi(code); err == nil {
+			failures = append(failures, n)
+		}
+	}
+
+	example := g.examples.forType(method.InputType, 0)
+	encoded, err := json.Marshal(example)
+	if err != nil {
+		encoded = []byte("{}")
+	}
+
+	sb.WriteString(fmt.Sprintf("\tresp := call(t, %q, %q, %q, %s)\n",
+		strings.ToUpper(method.GetHTTPMethod()), method.HTTPPath(service),
+		service.Name+"."+method.Name, strconv.Quote(string(encoded))))
+	sb.WriteString(fmt.Sprintf("\tcheckResponse(t, resp, %s, %s, %q)\n",
+		g.intSliceLiteral(success), g.intSliceLiteral(failures), ast.GetUnqualifiedName(method.OutputType)))
+	sb.WriteString("}\n\n")
+}
+
+// intSliceLiteral renders a sorted []int literal
+func (g *ContractTestGenerator) intSliceLiteral(values []int) string {
+	if len(values) == 0 {
+		return "nil"
+	}
+	sort.Ints(values)
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return "[]int{" + strings.Join(parts, ", ") + "}"
+}
+
+const contractTestImports = `import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fieldSpec describes the expected JSON shape of a field. Kind is one of string,
+// integer, int64, number, boolean, any, or the name of a schema type, enum, or union.
+type fieldSpec struct {
+	kind     string
+	array    bool
+	mapOf    bool
+	required bool
+}
+
+// unionEncoding describes how a tagged union encodes its options. Strategy is
+// internal, with the tag among the fields of the option, or adjacent, with the
+// option in the content property. Options maps tag values to kinds.
+type unionEncoding struct {
+	strategy string
+	tag      string
+	content  string
+	options  map[string]string
+}
+
+`
+
+const contractTestRuntime = `// response is a decoded provider response.
+type response struct {
+	status int
+	body   []byte
+}
+
+// call sends the example (or fixture) request for an operation to the provider.
+func call(t *testing.T, method, pattern, operation, example string) response {
+	t.Helper()
+
+	baseURL := os.Getenv("TYPEMUX_CONTRACT_BASE_URL")
+	if baseURL == "" {
+		t.Skip("TYPEMUX_CONTRACT_BASE_URL is not set")
+	}
+
+	body := []byte(example)
+	if dir := os.Getenv("TYPEMUX_CONTRACT_FIXTURES"); dir != "" {
+		if fixture, err := os.ReadFile(filepath.Join(dir, operation+".json")); err == nil {
+			body = fixture
+		}
+	}
+
+	var fields map[string]interface{}
+	_ = json.Unmarshal(body, &fields)
+
+	path := expandPath(pattern, fields)
+	var reader io.Reader
+	if method == http.MethodGet || method == http.MethodDelete {
+		query := url.Values{}
+		for name, value := range fields {
+			if isScalar(value) && !strings.Contains(pattern, "{"+name+"}") {
+				query.Set(name, fmt.Sprint(value))
+			}
+		}
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+	} else {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(baseURL, "/")+path, reader)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading response: %v", err)
+	}
+	return response{status: resp.StatusCode, body: data}
+}
+
+// expandPath substitutes {param} segments with values from the request body.
+func expandPath(pattern string, fields map[string]interface{}) string {
+	var sb strings.Builder
+	for {
+		start := strings.Index(pattern, "{")
+		end := strings.Index(pattern, "}")
+		if start == -1 || end < start {
+			sb.WriteString(pattern)
+			return sb.String()
+		}
+		name := pattern[start+1 : end]
+		value := "example"
+		if v, ok := fields[name]; ok && isScalar(v) {
+			value = fmt.Sprint(v)
+		}
+		sb.WriteString(pattern[:start])
+		sb.WriteString(url.PathEscape(value))
+		pattern = pattern[end+1:]
+	}
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, float64, bool:
+		return true
+	}
+	return false
+}
+
+// checkResponse verifies the status code is declared by the schema and that
+// successful responses match the output type.
+func checkResponse(t *testing.T, resp response, success, failures []int, outputType string) {
+	t.Helper()
+
+	if contains(failures, resp.status) {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(resp.body, &payload); err != nil {
+			t.Errorf("declared error %d returned a non-JSON body: %s", resp.status, resp.body)
+		}
+		return
+	}
+	if !contains(success, resp.status) {
+		t.Fatalf("undeclared status %d (success: %v, errors: %v): %s", resp.status, success, failures, resp.body)
+	}
+	if resp.status == http.StatusNoContent || len(bytes.TrimSpace(resp.body)) == 0 {
+		return
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(resp.body, &payload); err != nil {
+		t.Fatalf("response is not valid JSON: %v", err)
+	}
+	for _, problem := range validate("$", fieldSpec{kind: outputType}, payload) {
+		t.Error(problem)
+	}
+}
+
+func contains(values []int, v int) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks a decoded JSON value against a field spec and returns any problems.
+func validate(path string, spec fieldSpec, value interface{}) []string {
+	if value == nil {
+		return nil
+	}
+
+	if spec.array {
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{path + ": expected array"}
+		}
+		var problems []string
+		element := spec
+		element.array = false
+		for i, item := range items {
+			problems = append(problems, validate(path+"["+strconv.Itoa(i)+"]", element, item)...)
+		}
+		return problems
+	}
+
+	if spec.mapOf {
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{path + ": expected object"}
+		}
+		var problems []string
+		element := spec
+		element.mapOf = false
+		for key, entry := range entries {
+			problems = append(problems, validate(path+"."+key, element, entry)...)
+		}
+		return problems
+	}
+
+	switch spec.kind {
+	case "any":
+		return nil
+	case "string":
+		if _, ok := value.(string); !ok {
+			return []string{path + ": expected string"}
+		}
+		return nil
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{path + ": expected boolean"}
+		}
+		return nil
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return []string{path + ": expected number"}
+		}
+		return nil
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			return []string{path + ": expected integer"}
+		}
+		return nil
+	case "int64":
+		switch v := value.(type) {
+		case float64:
+			if v == math.Trunc(v) {
+				return nil
+			}
+		case string:
+			if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return nil
+			}
+			if _, err := strconv.ParseUint(v, 10, 64); err == nil {
+				return nil
+			}
+		}
+		return []string{path + ": expected 64-bit integer"}
+	}
+
+	if values, ok := enumValues[spec.kind]; ok {
+		s, isString := value.(string)
+		if !isString {
+			return []string{path + ": expected " + spec.kind + " enum string"}
+		}
+		for _, allowed := range values {
+			if s == allowed {
+				return nil
+			}
+		}
+		return []string{fmt.Sprintf("%s: %q is not a valid %s", path, s, spec.kind)}
+	}
+
+	if encoding, ok := unionEncodings[spec.kind]; ok {
+		return validateTagged(path, spec.kind, encoding, value)
+	}
+
+	if options, ok := unionOptions[spec.kind]; ok {
+		for _, option := range options {
+			if len(validate(path, fieldSpec{kind: option}, value)) == 0 {
+				return nil
+			}
+		}
+		return []string{path + ": does not match any option of " + spec.kind}
+	}
+
+	fields, ok := typeSpecs[spec.kind]
+	if !ok {
+		return nil
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{path + ": expected " + spec.kind + " object"}
+	}
+	var problems []string
+	for name, field := range fields {
+		fieldValue, present := object[name]
+		if field.required && (!present || fieldValue == nil) {
+			problems = append(problems, path+"."+name+": required field is missing")
+			continue
+		}
+		problems = append(problems, validate(path+"."+name, field, fieldValue)...)
+	}
+	return problems
+}
+
+// validateTagged checks a value of a tagged union against the option its tag names.
+func validateTagged(path, union string, encoding unionEncoding, value interface{}) []string {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{path + ": expected " + union + " object"}
+	}
+	tag, _ := object[encoding.tag].(string)
+	kind, ok := encoding.options[tag]
+	if !ok {
+		return []string{fmt.Sprintf("%s.%s: %q is not an option of %s", path, encoding.tag, tag, union)}
+	}
+	if encoding.strategy != "adjacent" {
+		// Type specs only check the fields they list, so the tag is ignored
+		return validate(path, fieldSpec{kind: kind}, value)
+	}
+	content, present := object[encoding.content]
+	if !present || content == nil {
+		return []string{path + "." + encoding.content + ": required field is missing"}
+	}
+	return validate(path+"."+encoding.content, fieldSpec{kind: kind}, content)
+}
+`
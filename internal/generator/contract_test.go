@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func TestContractTestGenerator_Generate(t *testing.T) {
+	schema := mockServerTestSchema()
+	schema.Services[0].Methods = append(schema.Services[0].Methods, &ast.Method{
+		Name: "WatchUsers", InputType: "GetUserRequest", OutputType: "User", OutputStream: true,
+	})
+
+	gen := NewContractTestGenerator()
+	output := gen.Generate(schema)
+
+	expected := []string{
+		"package contract",
+		`"Role": {"ADMIN", "USER"},`,
+		`"id":      {kind: "string"},`,
+		`"friends": {kind: "User", array: true},`,
+		"func TestUserService_GetUser(t *testing.T) {",
+		`resp := call(t, "GET", "/users/{id}", "UserService.GetUser", "{\"id\":\"string\"}")`,
+		`checkResponse(t, resp, []int{200}, []int{404}, "User")`,
+		`checkResponse(t, resp, []int{204}, nil, "Empty")`,
+		"func TestUserService_WatchUsers(t *testing.T) {\n\tt.Skip(",
+		"TYPEMUX_CONTRACT_BASE_URL",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+
+	if strings.Contains(output, `"secret"`) {
+		t.Error("Expected fields excluded from OpenAPI to be omitted from type specs")
+	}
+
+	formatted, err := format.Source([]byte(output))
+	if err != nil {
+		t.Fatalf("Generated contract tests are not valid Go: %v", err)
+	}
+	if string(formatted) != output {
+		t.Error("Generated contract tests are not gofmt-formatted")
+	}
+}
+
+func TestContractTestGenerator_FieldSpecs(t *testing.T) {
+	gen := NewContractTestGenerator()
+
+	tests := []struct {
+		field *ast.Field
+		want  string
+	}{
+		{&ast.Field{Type: &ast.FieldType{Name: "int64"}, Required: true}, `{kind: "int64", required: true}`},
+		{&ast.Field{Type: &ast.FieldType{Name: "int32", Optional: true}, Required: true}, `{kind: "integer"}`},
+		{&ast.Field{Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "float64"}}, `{kind: "number", mapOf: true}`},
+		{&ast.Field{Type: &ast.FieldType{Name: "com.example.Order"}}, `{kind: "Order"}`},
+	}
+	for _, tt := range tests {
+		if got := gen.fieldSpecLiteral(tt.field); got != tt.want {
+			t.Errorf("fieldSpecLiteral() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestContractTestGenerator_FlagsEnums(t *testing.T) {
+	schema := mockServerTestSchema()
+	schema.Enums = append(schema.Enums, &ast.Enum{
+		Name: "Permission", Flags: true,
+		Values: []*ast.EnumValue{{Name: "NONE", Number: 0, HasNumber: true}, {Name: "READ"}, {Name: "WRITE"}},
+	})
+	schema.Types[0].Fields = append(schema.Types[0].Fields, &ast.Field{Name: "permissions", Type: &ast.FieldType{Name: "Permission"}})
+
+	output := NewContractTestGenerator().Generate(schema)
+	if !strings.Contains(output, `"permissions": {kind: "integer"},`) {
+		t.Errorf("Expected flags enum fields to be checked as integers, got:\n%s", output)
+	}
+	if strings.Contains(output, `"Permission": {`) {
+		t.Errorf("Expected flags enums to have no allowed names, got:\n%s", output)
+	}
+}
+
+func TestContractTestGenerator_UnionEncodings(t *testing.T) {
+	schema := mockServerTestSchema()
+	schema.Unions = []*ast.Union{
+		{Name: "Principal", Options: []string{"User", "string"}, Encoding: &ast.UnionEncoding{Strategy: ast.UnionAdjacent, Tag: "kind", Content: "data"}},
+		{Name: "Owner", Options: []string{"User"}, Encoding: &ast.UnionEncoding{Strategy: ast.UnionInternal}},
+		{Name: "Any", Options: []string{"User"}},
+	}
+
+	output := NewContractTestGenerator().Generate(schema)
+	expected := []string{
+		`"Principal": {strategy: "adjacent", tag: "kind", content: "data", options: map[string]string{"User": "User", "string": "string"}},`,
+		`"Owner":     {strategy: "internal", tag: "type", content: "value", options: map[string]string{"User": "User"}},`,
+		"func validateTagged(",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+	if strings.Contains(output, `"Any":       {strategy`) || strings.Contains(output, `"Any": {strategy`) {
+		t.Error("Expected unions without an encoding to match their options in order")
+	}
+}
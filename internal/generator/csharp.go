@@ -0,0 +1,445 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// CSharpOptions configures the C# generator.
+type CSharpOptions struct {
+	// Style selects the type style: "record" (default) emits sealed records with
+	// init-only properties, "class" emits mutable classes.
+	Style string
+
+	// NamespacePrefix is prepended to every namespace-derived C# namespace
+	// (e.g., "Acme" turns namespace "users" into "Acme.Users").
+	NamespacePrefix string
+}
+
+// CSharpGenerator generates C# DTOs with System.Text.Json attributes from TypeMUX schemas.
+type CSharpGenerator struct {
+	opts  CSharpOptions
+	enums map[string]bool
+}
+
+// NewCSharpGenerator creates a new C# code generator using records.
+func NewCSharpGenerator() *CSharpGenerator {
+	return NewCSharpGeneratorWithOptions(nil)
+}
+
+// NewCSharpGeneratorWithOptions creates a new C# code generator with the given options.
+func NewCSharpGeneratorWithOptions(opts *CSharpOptions) *CSharpGenerator {
+	g := &CSharpGenerator{}
+	if opts != nil {
+		g.opts = *opts
+	}
+	if g.opts.Style == "" {
+		g.opts.Style = "record"
+	}
+	return g
+}
+
+// csharpNamespace groups the definitions that belong to one C# namespace
+type csharpNamespace struct {
+	name     string
+	enums    []*ast.Enum
+	types    []*ast.Type
+	unions   []*ast.Union
+	services []*ast.Service
+}
+
+// Generate produces a single C# source file containing all definitions.
+func (g *CSharpGenerator) Generate(schema *ast.Schema) string {
+	g.enums = make(map[string]bool)
+	for _, enum := range schema.Enums {
+		g.enums[enum.Name] = true
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("// <auto-generated>\n")
+	sb.WriteString("// Code generated by TypeMUX. DO NOT EDIT.\n")
+	sb.WriteString("// </auto-generated>\n")
+	sb.WriteString("#nullable enable\n\n")
+	sb.WriteString("using System;\n")
+	sb.WriteString("using System.Collections.Generic;\n")
+	if len(schema.Services) > 0 {
+		sb.WriteString("using System.Threading;\n")
+		sb.WriteString("using System.Threading.Tasks;\n")
+	}
+	sb.WriteString("using System.Text.Json.Serialization;\n")
+
+	for _, ns := range g.groupByNamespace(schema) {
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("namespace %s\n{\n", ns.name))
+
+		first := true
+		separator := func() {
+			if !first {
+				sb.WriteString("\n")
+			}
+			first = false
+		}
+
+		for _, enum := range ns.enums {
+			separator()
+			sb.WriteString(g.generateEnum(enum))
+		}
+		for _, typ := range ns.types {
+			separator()
+			sb.WriteString(g.generateType(typ))
+		}
+		for _, union := range ns.unions {
+			separator()
+			sb.WriteString(g.generateUnion(union))
+		}
+		for _, service := range ns.services {
+			separator()
+			sb.WriteString(g.generateService(service))
+		}
+
+		sb.WriteString("}\n")
+	}
+
+	return sb.String()
+}
+
+// groupByNamespace buckets definitions by C# namespace, preserving first-seen order
+func (g *CSharpGenerator) groupByNamespace(schema *ast.Schema) []*csharpNamespace {
+	var order []*csharpNamespace
+	index := make(map[string]*csharpNamespace)
+
+	get := func(namespace string) *csharpNamespace {
+		if namespace == "" {
+			namespace = schema.Namespace
+		}
+		if namespace == "" {
+			namespace = "api"
+		}
+		name := g.namespaceName(namespace)
+		if ns, ok := index[name]; ok {
+			return ns
+		}
+		ns := &csharpNamespace{name: name}
+		index[name] = ns
+		order = append(order, ns)
+		return ns
+	}
+
+	for _, enum := range schema.Enums {
+		ns := get(enum.Namespace)
+		ns.enums = append(ns.enums, enum)
+	}
+	for _, typ := range schema.Types {
+		ns := get(typ.Namespace)
+		ns.types = append(ns.types, typ)
+	}
+	for _, union := range schema.Unions {
+		ns := get(union.Namespace)
+		ns.unions = append(ns.unions, union)
+	}
+	for _, service := range schema.Services {
+		ns := get(service.Namespace)
+		ns.services = append(ns.services, service)
+	}
+
+	return order
+}
+
+// namespaceName converts a TypeMUX namespace into a PascalCase C# namespace
+func (g *CSharpGenerator) namespaceName(namespace string) string {
+	parts := strings.Split(namespace, ".")
+	for i, part := range parts {
+		parts[i] = g.pascalCase(part)
+	}
+	name := strings.Join(parts, ".")
+	if g.opts.NamespacePrefix != "" {
+		name = g.opts.NamespacePrefix + "." + name
+	}
+	return name
+}
+
+// generateEnum generates a C# enum serialized by name, with the values numbered
+// like the protobuf output. Flags enums are [Flags] enums serialized as numbers,
+// so that a value can combine several flags.
+func (g *CSharpGenerator) generateEnum(enum *ast.Enum) string {
+	var sb strings.Builder
+	sb.WriteString(g.formatDoc(enum.Doc.GetDoc("csharp"), "    "))
+	if enum.Flags {
+		sb.WriteString("    [Flags]\n")
+	} else {
+		sb.WriteString("    [JsonConverter(typeof(JsonStringEnumConverter))]\n")
+	}
+	sb.WriteString(fmt.Sprintf("    public enum %s\n    {\n", enum.Name))
+	numbers := enum.ValueNumbers()
+	for i, value := range enum.Values {
+		sb.WriteString(g.formatDoc(value.Doc.GetDoc("csharp"), "        "))
+		sb.WriteString(fmt.Sprintf("        %s = %d,\n", value.Name, numbers[i]))
+	}
+	sb.WriteString("    }\n")
+	return sb.String()
+}
+
+// generateType generates a C# record or class for a type
+func (g *CSharpGenerator) generateType(typ *ast.Type) string {
+	var sb strings.Builder
+	sb.WriteString(g.formatDoc(typ.Doc.GetDoc("csharp"), "    "))
+
+	accessor := "init"
+	if g.opts.Style == "class" {
+		accessor = "set"
+		sb.WriteString(fmt.Sprintf("    public class %s\n    {\n", typ.Name))
+	} else {
+		sb.WriteString(fmt.Sprintf("    public sealed record %s\n    {\n", typ.Name))
+	}
+
+	first := true
+	for _, field := range typ.Fields {
+		if !field.ShouldIncludeInGenerator("csharp") {
+			continue
+		}
+		// Fields with arguments are resolvers, not data
+		if len(field.Arguments) > 0 {
+			continue
+		}
+
+		if !first {
+			sb.WriteString("\n")
+		}
+		first = false
+		sb.WriteString(g.generateProperty(field, typ.Name, accessor))
+	}
+
+	sb.WriteString("    }\n")
+	return sb.String()
+}
+
+// generateProperty generates a single C# property with its JSON attributes
+func (g *CSharpGenerator) generateProperty(field *ast.Field, typeName, accessor string) string {
+	var sb strings.Builder
+	indent := "        "
+
+	sb.WriteString(g.formatDoc(field.Doc.GetDoc("csharp"), indent))
+
+	jsonName := field.Name
+	if field.JSONName != "" {
+		jsonName = field.JSONName
+	}
+	sb.WriteString(fmt.Sprintf("%s[JsonPropertyName(\"%s\")]\n", indent, jsonName))
+
+	nullable := field.Type.Optional || field.JSONNullable
+	required := field.Presence() == ast.PresenceRequired
+
+	if field.JSONOmitEmpty {
+		condition := "WhenWritingDefault"
+		if nullable {
+			condition = "WhenWritingNull"
+		}
+		sb.WriteString(fmt.Sprintf("%s[JsonIgnore(Condition = JsonIgnoreCondition.%s)]\n", indent, condition))
+	}
+
+	if field.Deprecated != nil {
+		if field.Deprecated.Reason != "" {
+			sb.WriteString(fmt.Sprintf("%s[Obsolete(\"%s\")]\n", indent, strings.ReplaceAll(field.Deprecated.Reason, "\"", "\\\"")))
+		} else {
+			sb.WriteString(fmt.Sprintf("%s[Obsolete]\n", indent))
+		}
+	}
+
+	csType := g.mapTypeToCSharp(field.Type)
+	if nullable {
+		csType += "?"
+	}
+
+	propertyName := g.pascalCase(field.Name)
+	// C# forbids members named after their enclosing type
+	if propertyName == typeName {
+		propertyName += "Value"
+	}
+
+	modifier := "public"
+	if required {
+		modifier = "public required"
+	}
+
+	initializer := ""
+	if !required && !nullable {
+		initializer = g.defaultInitializer(field.Type)
+	}
+
+	sb.WriteString(fmt.Sprintf("%s%s %s %s { get; %s; }%s\n", indent, modifier, csType, propertyName, accessor, initializer))
+	return sb.String()
+}
+
+// defaultInitializer returns a non-null initializer for reference types so the
+// generated code compiles cleanly with nullable reference types enabled
+func (g *CSharpGenerator) defaultInitializer(fieldType *ast.FieldType) string {
+	if fieldType.IsArray || fieldType.IsMap {
+		return " = new();"
+	}
+	switch fieldType.Name {
+	case "string":
+		return " = string.Empty;"
+	case "bytes":
+		return " = Array.Empty<byte>();"
+	}
+	if !ast.IsBuiltinType(fieldType.Name) && !g.isEnum(fieldType.Name) {
+		return " = default!;"
+	}
+	return ""
+}
+
+// isEnum reports whether a type name refers to an enum in the schema being generated
+func (g *CSharpGenerator) isEnum(typeName string) bool {
+	return g.enums[ast.GetUnqualifiedName(typeName)]
+}
+
+// generateUnion generates an abstract record with one derived record per option
+func (g *CSharpGenerator) generateUnion(union *ast.Union) string {
+	var sb strings.Builder
+	sb.WriteString(g.formatDoc(union.Doc.GetDoc("csharp"), "    "))
+	sb.WriteString("    [JsonPolymorphic(TypeDiscriminatorPropertyName = \"type\")]\n")
+	for _, option := range union.Options {
+		optionName := ast.GetUnqualifiedName(option)
+		sb.WriteString(fmt.Sprintf("    [JsonDerivedType(typeof(%s%s), \"%s\")]\n", union.Name, optionName, optionName))
+	}
+	sb.WriteString(fmt.Sprintf("    public abstract record %s;\n", union.Name))
+
+	for _, option := range union.Options {
+		optionName := ast.GetUnqualifiedName(option)
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("    public sealed record %s%s : %s\n    {\n", union.Name, optionName, union.Name))
+		sb.WriteString("        [JsonPropertyName(\"value\")]\n")
+		sb.WriteString(fmt.Sprintf("        public required %s Value { get; init; }\n", g.typeReference(option)))
+		sb.WriteString("    }\n")
+	}
+	return sb.String()
+}
+
+// generateService generates an async C# interface for a service
+func (g *CSharpGenerator) generateService(service *ast.Service) string {
+	var sb strings.Builder
+	sb.WriteString(g.formatDoc(service.Doc.GetDoc("csharp"), "    "))
+	sb.WriteString(fmt.Sprintf("    public interface I%s\n    {\n", service.Name))
+	for i, method := range service.Methods {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(g.formatDoc(method.Doc.GetDoc("csharp"), "        "))
+
+		// Methods without input take only the cancellation token
+		params := "CancellationToken cancellationToken = default"
+		if method.HasInput() {
+			inputType := g.typeReference(method.InputType)
+			if method.InputStream {
+				inputType = fmt.Sprintf("IAsyncEnumerable<%s>", inputType)
+			}
+			params = fmt.Sprintf("%s request, %s", inputType, params)
+		}
+
+		// Methods without output complete a plain Task
+		outputType := "Task"
+		if method.OutputStream {
+			outputType = fmt.Sprintf("IAsyncEnumerable<%s>", g.typeReference(method.OutputType))
+		} else if method.HasOutput() {
+			outputType = fmt.Sprintf("Task<%s>", g.typeReference(method.OutputType))
+		}
+
+		sb.WriteString(fmt.Sprintf("        %s %sAsync(%s);\n", outputType, g.pascalCase(method.Name), params))
+	}
+	sb.WriteString("    }\n")
+	return sb.String()
+}
+
+// mapTypeToCSharp maps a TypeMUX field type to a C# type
+func (g *CSharpGenerator) mapTypeToCSharp(fieldType *ast.FieldType) string {
+	if fieldType.IsMap {
+		valueType := "object"
+		if valueFieldType := fieldType.GetMapValueType(); valueFieldType != nil {
+			valueType = g.mapTypeToCSharp(valueFieldType)
+		}
+		csType := fmt.Sprintf("Dictionary<%s, %s>", g.mapScalarToCSharp(fieldType.MapKey), valueType)
+		if fieldType.IsArray {
+			csType = fmt.Sprintf("List<%s>", csType)
+		}
+		return csType
+	}
+
+	if fieldType.IsArray {
+		// Elements may be lists or maps themselves, as in [][]string or []map<string, int32>
+		return fmt.Sprintf("List<%s>", g.mapTypeToCSharp(fieldType.ElementType()))
+	}
+
+	return g.mapScalarToCSharp(fieldType.Name)
+}
+
+// mapScalarToCSharp maps a scalar or named TypeMUX type to a C# type
+func (g *CSharpGenerator) mapScalarToCSharp(typeName string) string {
+	switch typeName {
+	case "string":
+		return "string"
+	case "int32":
+		return "int"
+	case "int64":
+		return "long"
+	case "uint8":
+		return "byte"
+	case "uint16":
+		return "ushort"
+	case "uint32":
+		return "uint"
+	case "uint64":
+		return "ulong"
+	case "float32":
+		return "float"
+	case "float64":
+		return "double"
+	case "bool":
+		return "bool"
+	case "timestamp":
+		return "DateTimeOffset"
+	case "bytes":
+		return "byte[]"
+	default:
+		return g.typeReference(typeName)
+	}
+}
+
+// typeReference returns the C# reference for a (possibly qualified) TypeMUX type name
+func (g *CSharpGenerator) typeReference(typeName string) string {
+	if strings.Contains(typeName, ".") {
+		parts := strings.Split(typeName, ".")
+		return "global::" + g.namespaceName(strings.Join(parts[:len(parts)-1], ".")) + "." + parts[len(parts)-1]
+	}
+	return typeName
+}
+
+// pascalCase converts snake_case or camelCase identifiers into PascalCase
+func (g *CSharpGenerator) pascalCase(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-'
+	})
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// formatDoc formats documentation as a C# XML doc comment with the given indentation
+func (g *CSharpGenerator) formatDoc(doc, indent string) string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return ""
+	}
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+	var sb strings.Builder
+	sb.WriteString(indent + "/// <summary>\n")
+	for _, line := range strings.Split(doc, "\n") {
+		sb.WriteString(fmt.Sprintf("%s/// %s\n", indent, replacer.Replace(strings.TrimSpace(line))))
+	}
+	sb.WriteString(indent + "/// </summary>\n")
+	return sb.String()
+}
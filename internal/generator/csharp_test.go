@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func csharpTestSchema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "com.example.users",
+		Enums: []*ast.Enum{
+			{
+				Name:      "Status",
+				Namespace: "com.example.users",
+				Values: []*ast.EnumValue{
+					{Name: "ACTIVE", Number: 0, HasNumber: true},
+					{Name: "DISABLED", Number: 1, HasNumber: true},
+				},
+			},
+		},
+		Types: []*ast.Type{
+			{
+				Name:      "User",
+				Namespace: "com.example.users",
+				Doc:       &ast.Documentation{General: "A registered user"},
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string"}, Required: true},
+					{Name: "display_name", Type: &ast.FieldType{Name: "string"}, JSONName: "displayName", JSONOmitEmpty: true},
+					{Name: "nickname", Type: &ast.FieldType{Name: "string", Optional: true}},
+					{Name: "age", Type: &ast.FieldType{Name: "int32", Optional: true}},
+					{Name: "created_at", Type: &ast.FieldType{Name: "timestamp"}},
+					{Name: "tags", Type: &ast.FieldType{Name: "string", IsArray: true}},
+					{Name: "metadata", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "int64"}},
+					{Name: "status", Type: &ast.FieldType{Name: "Status"}},
+					{Name: "order", Type: &ast.FieldType{Name: "com.example.orders.Order"}},
+					{Name: "legacy", Type: &ast.FieldType{Name: "string"}, Deprecated: &ast.DeprecationInfo{Reason: "Use id"}},
+					{Name: "secret", Type: &ast.FieldType{Name: "string"}, ExcludeFrom: []string{"csharp"}},
+				},
+			},
+			{
+				Name:      "Order",
+				Namespace: "com.example.orders",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string"}, Required: true},
+				},
+			},
+		},
+		Unions: []*ast.Union{
+			{
+				Name:      "Result",
+				Namespace: "com.example.users",
+				Options:   []string{"User", "Status"},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name:      "UserService",
+				Namespace: "com.example.users",
+				Methods: []*ast.Method{
+					{Name: "GetUser", InputType: "GetUserRequest", OutputType: "User"},
+					{Name: "WatchUsers", InputType: "WatchRequest", OutputType: "User", OutputStream: true},
+				},
+			},
+		},
+	}
+}
+
+func TestCSharpGenerator_Record(t *testing.T) {
+	gen := NewCSharpGenerator()
+	output := gen.Generate(csharpTestSchema())
+
+	expected := []string{
+		"#nullable enable",
+		"using System.Text.Json.Serialization;",
+		"namespace Com.Example.Users\n{",
+		"namespace Com.Example.Orders\n{",
+		"    /// <summary>\n    /// A registered user\n    /// </summary>",
+		"    public sealed record User\n    {",
+		"        [JsonPropertyName(\"id\")]\n        public required string Id { get; init; }",
+		"        [JsonPropertyName(\"displayName\")]\n        [JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingDefault)]\n        public string DisplayName { get; init; } = string.Empty;",
+		"public string? Nickname { get; init; }",
+		"public int? Age { get; init; }",
+		"public DateTimeOffset CreatedAt { get; init; }",
+		"public List<string> Tags { get; init; } = new();",
+		"public Dictionary<string, long> Metadata { get; init; } = new();",
+		"public Status Status { get; init; }\n",
+		"public global::Com.Example.Orders.Order Order { get; init; } = default!;",
+		"[Obsolete(\"Use id\")]",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+
+	if strings.Contains(output, "Secret") {
+		t.Errorf("Expected excluded field to be omitted, got:\n%s", output)
+	}
+}
+
+func TestCSharpGenerator_ClassStyle(t *testing.T) {
+	gen := NewCSharpGeneratorWithOptions(&CSharpOptions{Style: "class", NamespacePrefix: "Acme"})
+	output := gen.Generate(csharpTestSchema())
+
+	expected := []string{
+		"namespace Acme.Com.Example.Users",
+		"    public class User\n    {",
+		"public required string Id { get; set; }",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+}
+
+func TestCSharpGenerator_EnumUnionService(t *testing.T) {
+	gen := NewCSharpGenerator()
+	output := gen.Generate(csharpTestSchema())
+
+	expected := []string{
+		"    [JsonConverter(typeof(JsonStringEnumConverter))]\n    public enum Status\n    {\n        ACTIVE = 0,\n        DISABLED = 1,\n    }",
+		"[JsonPolymorphic(TypeDiscriminatorPropertyName = \"type\")]",
+		"[JsonDerivedType(typeof(ResultUser), \"User\")]",
+		"public abstract record Result;",
+		"public sealed record ResultUser : Result",
+		"public required User Value { get; init; }",
+		"public interface IUserService",
+		"Task<User> GetUserAsync(GetUserRequest request, CancellationToken cancellationToken = default);",
+		"IAsyncEnumerable<User> WatchUsersAsync(WatchRequest request, CancellationToken cancellationToken = default);",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+}
+
+func TestCSharpGenerator_EmptyMethodTypes(t *testing.T) {
+	output := NewCSharpGenerator().Generate(emptyMethodTestSchema())
+
+	expected := []string{
+		"Task PingAsync(CancellationToken cancellationToken = default);",
+		"Task ResetAsync(ResetRequest request, CancellationToken cancellationToken = default);",
+		"Task<Status> GetStatusAsync(CancellationToken cancellationToken = default);",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+}
+
+func TestCSharpGenerator_EnumNumbers(t *testing.T) {
+	schema := &ast.Schema{Enums: []*ast.Enum{{
+		Name:      "Color",
+		Namespace: "api",
+		Values:    []*ast.EnumValue{{Name: "RED"}, {Name: "GREEN"}, {Name: "BLUE", Number: 5, HasNumber: true}},
+	}}}
+	output := NewCSharpGenerator().Generate(schema)
+
+	expected := "    public enum Color\n    {\n        RED = 1,\n        GREEN = 2,\n        BLUE = 5,\n    }"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+	}
+}
+
+func TestCSharpGenerator_NestedCollections(t *testing.T) {
+	schema := &ast.Schema{Types: []*ast.Type{{
+		Name:      "Report",
+		Namespace: "api",
+		Fields: []*ast.Field{
+			{Name: "rows", Type: &ast.FieldType{Name: "map", IsArray: true, MapKey: "string", MapValue: "int32"}},
+			{Name: "matrix", Type: &ast.FieldType{Name: "[]float64", IsArray: true}},
+		},
+	}}}
+	output := NewCSharpGenerator().Generate(schema)
+
+	expected := []string{
+		"public List<Dictionary<string, int>> Rows { get; init; } = new();",
+		"public List<List<double>> Matrix { get; init; } = new();",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+}
+
+func TestCSharpGenerator_Flags(t *testing.T) {
+	schema := &ast.Schema{Enums: []*ast.Enum{{
+		Name:      "Permission",
+		Namespace: "acme",
+		Flags:     true,
+		Values:    []*ast.EnumValue{{Name: "NONE", HasNumber: true}, {Name: "READ"}, {Name: "WRITE"}, {Name: "ADMIN", Number: 16, HasNumber: true}},
+	}}}
+	output := NewCSharpGenerator().Generate(schema)
+
+	expected := "    [Flags]\n    public enum Permission\n    {\n        NONE = 0,\n        READ = 1,\n        WRITE = 2,\n        ADMIN = 16,\n    }"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+	}
+	if strings.Contains(output, "JsonStringEnumConverter") {
+		t.Errorf("Expected flags to be serialized as numbers, got:\n%s", output)
+	}
+}
+
+func TestGenerateCSharpFile_UnknownStyle(t *testing.T) {
+	_, err := generators["csharp"].Generate(context.Background(), csharpTestSchema(), Options{CSharp: &CSharpOptions{Style: "struct"}})
+	if err == nil || !strings.Contains(err.Error(), `unknown C# style "struct"`) {
+		t.Errorf("Expected an error for an unknown C# style, got %v", err)
+	}
+}
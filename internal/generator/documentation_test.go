@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// documentedSchema returns a schema with doc comments on every element, some
+// multi-line and some specific to a format
+func documentedSchema() *ast.Schema {
+	doc := func(general string) *ast.Documentation {
+		return &ast.Documentation{General: general, Specific: map[string]string{}}
+	}
+	status := doc("Status of a user")
+	status.Specific["graphql"] = "Status of a GraphQL user"
+	return &ast.Schema{
+		Namespace: "api",
+		Enums: []*ast.Enum{{Name: "Status", Namespace: "api", Doc: status, Values: []*ast.EnumValue{
+			{Name: "ACTIVE", Doc: doc("The user can sign in")},
+			{Name: "LOCKED", Doc: doc("The user is locked out")},
+		}}},
+		Types: []*ast.Type{
+			{Name: "User", Namespace: "api", Doc: doc("A registered user"), Fields: []*ast.Field{
+				{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}, Required: true, Doc: doc("Unique identifier")},
+				{Name: "name", Type: &ast.FieldType{Name: "string", IsBuiltin: true}, Doc: doc("Display name\nshown in the UI")},
+				{Name: "status", Type: &ast.FieldType{Name: "Status"}, Doc: doc("Current status")},
+			}},
+			{Name: "Post", Namespace: "api", Fields: []*ast.Field{
+				{Name: "title", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+			}},
+		},
+		Unions: []*ast.Union{{Name: "SearchResult", Namespace: "api", Doc: doc("Something that can be searched"), Options: []string{"User", "Post"}}},
+		Services: []*ast.Service{{Name: "UserService", Namespace: "api", Doc: doc("Manages users"), Methods: []*ast.Method{
+			{Name: "GetUser", InputType: "User", OutputType: "User", HTTPMethod: "GET", Doc: doc("Gets a user by ID")},
+			{Name: "UpdateUser", InputType: "User", OutputType: "User", Doc: doc("Updates a user\nand returns it")},
+		}}},
+	}
+}
+
+func TestDocumentation_Go(t *testing.T) {
+	output := NewGoGenerator().Generate(documentedSchema())
+	formatted, err := format.Source([]byte(output))
+	if err != nil {
+		t.Fatalf("Generated Go does not parse: %v\n%s", err, output)
+	}
+	output = string(formatted)
+
+	for _, want := range []string{
+		"// Status of a user\ntype Status int32",
+		"\t// The user can sign in\n\tStatusACTIVE Status = 1",
+		"// A registered user\ntype User struct",
+		"\t// Unique identifier\n\tId string",
+		"\t// Display name\n\t// shown in the UI\n",
+		"// SearchResultUser holds the User option of SearchResult.\ntype SearchResultUser struct",
+		"// Manages users\ntype UserService interface",
+		"\t// Gets a user by ID\n\tGetUser(",
+		"\t// Updates a user\n\t// and returns it\n\tUpdateUser(",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestDocumentation_GraphQL(t *testing.T) {
+	output := NewGraphQLGenerator().Generate(documentedSchema())
+
+	for _, want := range []string{
+		"\"Status of a GraphQL user\"\nenum Status {",
+		"  \"The user can sign in\"\n  ACTIVE\n",
+		"\"A registered user\"\ntype User {",
+		"  \"Unique identifier\"\n  id: String!\n",
+		"  \"\"\"\n  Display name\n  shown in the UI\n  \"\"\"\n  name: String\n",
+		"\"Something that can be searched\"\nunion SearchResult",
+		"  \"Gets a user by ID\"\n  getUser(",
+		"  \"\"\"\n  Updates a user\n  and returns it\n  \"\"\"\n  updateUser(",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "\"Status of a user\"") {
+		t.Errorf("Expected the GraphQL-specific doc to replace the general one:\n%s", output)
+	}
+}
+
+func TestDocumentation_OpenAPI(t *testing.T) {
+	output := NewOpenAPIGenerator().Generate(documentedSchema())
+
+	for _, want := range []string{
+		"description: Gets a user by ID",
+		"description: |-\n                Updates a user\n                and returns it",
+		"description: Unique identifier",
+		"description: A registered user",
+		"description: Something that can be searched",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+}
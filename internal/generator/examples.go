@@ -0,0 +1,290 @@
+package generator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// exampleMaxDepth bounds example generation for recursive types.
+const exampleMaxDepth = 3
+
+// exampleBuilder builds representative JSON values for schema types. It is shared
+// by generators that need sample payloads (mock servers, contract tests).
+type exampleBuilder struct {
+	types  map[string]*ast.Type
+	enums  map[string]*ast.Enum
+	unions map[string]*ast.Union
+}
+
+// newExampleBuilder indexes the schema definitions used to build examples
+func newExampleBuilder(schema *ast.Schema) *exampleBuilder {
+	b := &exampleBuilder{
+		types:  make(map[string]*ast.Type),
+		enums:  make(map[string]*ast.Enum),
+		unions: make(map[string]*ast.Union),
+	}
+
+	for _, typ := range schema.Types {
+		b.types[typ.Name] = typ
+		if typ.Namespace != "" {
+			b.types[typ.Namespace+"."+typ.Name] = typ
+		}
+	}
+	for _, enum := range schema.Enums {
+		b.enums[enum.Name] = enum
+		if enum.Namespace != "" {
+			b.enums[enum.Namespace+"."+enum.Name] = enum
+		}
+	}
+	for _, union := range schema.Unions {
+		b.unions[union.Name] = union
+		if union.Namespace != "" {
+			b.unions[union.Namespace+"."+union.Name] = union
+		}
+	}
+	return b
+}
+
+// forType builds an example value for a named type
+func (b *exampleBuilder) forType(typeName string, depth int) interface{} {
+	if ast.IsBuiltinType(typeName) {
+		return b.forBuiltin(typeName)
+	}
+
+	if enum := b.lookupEnum(typeName); enum != nil {
+		// Flags are encoded as bitmask integers rather than value names
+		if enum.Flags {
+			if len(enum.Values) > 0 {
+				return enum.ValueNumbers()[0]
+			}
+			return 0
+		}
+		if len(enum.Values) > 0 {
+			return enum.Values[0].Name
+		}
+		return ""
+	}
+
+	if union := b.lookupUnion(typeName); union != nil {
+		if len(union.Options) > 0 {
+			return b.encodeUnion(union, b.forType(union.Options[0], depth))
+		}
+		return map[string]interface{}{}
+	}
+
+	typ := b.lookupType(typeName)
+	if typ == nil {
+		return map[string]interface{}{}
+	}
+	if depth >= exampleMaxDepth {
+		return b.requiredOnly(typ, map[string]bool{})
+	}
+
+	obj := make(map[string]interface{})
+	for _, field := range typ.Fields {
+		if !field.ShouldIncludeInGenerator("openapi") || len(field.Arguments) > 0 {
+			continue
+		}
+		obj[exampleFieldName(field)] = b.forField(field, depth+1)
+	}
+	return obj
+}
+
+// requiredOnly builds the example of a type past exampleMaxDepth: only its
+// required fields, with empty arrays and maps, and without the fields that
+// refer back to a type being built
+func (b *exampleBuilder) requiredOnly(typ *ast.Type, building map[string]bool) map[string]interface{} {
+	building[typ.Name] = true
+	defer delete(building, typ.Name)
+
+	obj := make(map[string]interface{})
+	for _, field := range typ.Fields {
+		if !field.ShouldIncludeInGenerator("openapi") || len(field.Arguments) > 0 || field.Presence() != ast.PresenceRequired {
+			continue
+		}
+		name := exampleFieldName(field)
+		switch {
+		case field.Type.IsMap:
+			obj[name] = map[string]interface{}{}
+		case field.Type.IsArray:
+			obj[name] = []interface{}{}
+		default:
+			if value, ok := b.requiredValue(field, building); ok {
+				obj[name] = value
+			}
+		}
+	}
+	return obj
+}
+
+// requiredValue builds the value of a required field past exampleMaxDepth,
+// reporting false for a field that refers back to a type being built
+func (b *exampleBuilder) requiredValue(field *ast.Field, building map[string]bool) (interface{}, bool) {
+	typeName := field.Type.Name
+	union := b.lookupUnion(typeName)
+	if union != nil && len(union.Options) > 0 {
+		typeName = union.Options[0]
+	}
+
+	var value interface{}
+	switch typ := b.lookupType(typeName); {
+	case typ != nil && building[typ.Name]:
+		return nil, false
+	case typ != nil:
+		value = b.requiredOnly(typ, building)
+	case union != nil:
+		value = b.forType(typeName, exampleMaxDepth)
+	default:
+		return b.forField(field, exampleMaxDepth), true
+	}
+	if union != nil {
+		value = b.encodeUnion(union, value)
+	}
+	return value, true
+}
+
+// encodeUnion encodes the example of the first option of a union in the JSON
+// encoding of the union
+func (b *exampleBuilder) encodeUnion(union *ast.Union, value interface{}) interface{} {
+	encoding := union.Encoding
+	if encoding == nil || len(union.Options) == 0 {
+		return value
+	}
+	tag := ast.GetUnqualifiedName(union.Options[0])
+	switch encoding.Strategy {
+	case ast.UnionInternal:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		tagged := map[string]interface{}{encoding.TagProperty(): tag}
+		for name, fieldValue := range object {
+			tagged[name] = fieldValue
+		}
+		return tagged
+	case ast.UnionAdjacent:
+		return map[string]interface{}{encoding.TagProperty(): tag, encoding.ContentProperty(): value}
+	default:
+		return value
+	}
+}
+
+// exampleFieldName returns the JSON property of a field
+func exampleFieldName(field *ast.Field) string {
+	if field.JSONName != "" {
+		return field.JSONName
+	}
+	return field.Name
+}
+
+// forField builds an example value for a field, honoring defaults
+func (b *exampleBuilder) forField(field *ast.Field, depth int) interface{} {
+	if field.Default != "" && !field.Type.IsArray && !field.Type.IsMap {
+		if enum := b.lookupEnum(field.Type.Name); enum != nil && enum.Flags {
+			return b.flagsDefault(enum, field.Default)
+		}
+		return b.convertDefault(field.Default, field.Type.Name)
+	}
+	return b.forFieldType(field.Type, depth)
+}
+
+// forFieldType builds an example value for a field type
+func (b *exampleBuilder) forFieldType(fieldType *ast.FieldType, depth int) interface{} {
+	if fieldType.IsMap {
+		var value interface{}
+		if valueType := fieldType.GetMapValueType(); valueType != nil {
+			value = b.forFieldType(valueType, depth)
+		}
+		return map[string]interface{}{"key": value}
+	}
+
+	// Elements can be arrays and maps too, as in [][]string and []map<string, int32>
+	if fieldType.IsArray {
+		return []interface{}{b.forFieldType(fieldType.ElementType(), depth)}
+	}
+
+	return b.forType(fieldType.Name, depth)
+}
+
+// forBuiltin returns a representative value for a builtin type
+func (b *exampleBuilder) forBuiltin(typeName string) interface{} {
+	switch typeName {
+	case "string":
+		return "string"
+	case "int32", "int64", "uint8", "uint16", "uint32", "uint64":
+		return 1
+	case "float32", "float64":
+		return 1.5
+	case "bool":
+		return true
+	case "timestamp":
+		return "2024-01-01T00:00:00Z"
+	case "bytes":
+		return "ZXhhbXBsZQ=="
+	default:
+		return nil
+	}
+}
+
+// convertDefault converts a schema default value string into a JSON value
+func (b *exampleBuilder) convertDefault(value, typeName string) interface{} {
+	switch typeName {
+	case "int32", "int64", "uint8", "uint16", "uint32", "uint64":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "float32", "float64":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "bool":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// flagsDefault converts the default of a flags enum field, a number or value
+// names joined by |, into its bitmask
+func (b *exampleBuilder) flagsDefault(enum *ast.Enum, value string) int {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	numbers := enum.ValueNumbers()
+	mask := 0
+	for _, name := range strings.Split(value, "|") {
+		for i, enumValue := range enum.Values {
+			if enumValue.Name == strings.TrimSpace(name) {
+				mask |= numbers[i]
+			}
+		}
+	}
+	return mask
+}
+
+// lookupType resolves a type by qualified or unqualified name
+func (b *exampleBuilder) lookupType(name string) *ast.Type {
+	if typ, ok := b.types[name]; ok {
+		return typ
+	}
+	return b.types[ast.GetUnqualifiedName(name)]
+}
+
+// lookupEnum resolves an enum by qualified or unqualified name
+func (b *exampleBuilder) lookupEnum(name string) *ast.Enum {
+	if enum, ok := b.enums[name]; ok {
+		return enum
+	}
+	return b.enums[ast.GetUnqualifiedName(name)]
+}
+
+// lookupUnion resolves a union by qualified or unqualified name
+func (b *exampleBuilder) lookupUnion(name string) *ast.Union {
+	if union, ok := b.unions[name]; ok {
+		return union
+	}
+	return b.unions[ast.GetUnqualifiedName(name)]
+}
@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func TestExampleBuilder_FlagsEnums(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{{
+			Name: "Permission", Flags: true,
+			Values: []*ast.EnumValue{{Name: "NONE", Number: 0, HasNumber: true}, {Name: "READ"}, {Name: "WRITE"}},
+		}},
+		Types: []*ast.Type{{
+			Name: "Grant",
+			Fields: []*ast.Field{
+				{Name: "permissions", Type: &ast.FieldType{Name: "Permission"}},
+				{Name: "defaults", Type: &ast.FieldType{Name: "Permission"}, Default: "READ|WRITE"},
+			},
+		}},
+	}
+
+	got := newExampleBuilder(schema).forType("Grant", 0)
+	want := map[string]interface{}{"permissions": 0, "defaults": 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestExampleBuilder_DepthLimit(t *testing.T) {
+	schema := &ast.Schema{Types: []*ast.Type{
+		{
+			Name: "Node",
+			Fields: []*ast.Field{
+				{Name: "id", Type: &ast.FieldType{Name: "string"}, Required: true},
+				{Name: "label", Type: &ast.FieldType{Name: "string"}},
+				{Name: "owner", Type: &ast.FieldType{Name: "Owner"}, Required: true},
+				{Name: "children", Type: &ast.FieldType{Name: "Node", IsArray: true}, Required: true},
+				{Name: "parent", Type: &ast.FieldType{Name: "Node", Optional: true}},
+			},
+		},
+		{
+			Name: "Owner",
+			Fields: []*ast.Field{
+				{Name: "name", Type: &ast.FieldType{Name: "string"}, Required: true},
+				{Name: "home", Type: &ast.FieldType{Name: "Node"}, Required: true},
+			},
+		},
+	}}
+
+	// Past the limit only required fields are filled, and the ones referring
+	// back to a type being built are left out
+	got := newExampleBuilder(schema).forType("Node", exampleMaxDepth)
+	want := map[string]interface{}{
+		"id":       "string",
+		"owner":    map[string]interface{}{"name": "string"},
+		"children": []interface{}{},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestExampleBuilder_UnionEncodings(t *testing.T) {
+	circle := map[string]interface{}{"radius": 1.5}
+	tests := []struct {
+		encoding *ast.UnionEncoding
+		want     interface{}
+	}{
+		{nil, circle},
+		{&ast.UnionEncoding{Strategy: ast.UnionUntagged}, circle},
+		{&ast.UnionEncoding{Strategy: ast.UnionInternal}, map[string]interface{}{"type": "Circle", "radius": 1.5}},
+		{&ast.UnionEncoding{Strategy: ast.UnionAdjacent, Tag: "kind", Content: "data"}, map[string]interface{}{"kind": "Circle", "data": circle}},
+	}
+	for _, tt := range tests {
+		schema := &ast.Schema{
+			Types: []*ast.Type{
+				{Name: "Circle", Fields: []*ast.Field{{Name: "radius", Type: &ast.FieldType{Name: "float64"}}}},
+				{Name: "Square", Fields: []*ast.Field{{Name: "side", Type: &ast.FieldType{Name: "float64"}}}},
+			},
+			Unions: []*ast.Union{{Name: "Shape", Options: []string{"Circle", "Square"}, Encoding: tt.encoding}},
+		}
+		if got := newExampleBuilder(schema).forType("Shape", 0); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Encoding %v: expected %v, got %v", tt.encoding, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/descriptor"
+)
+
+// Generator generates the files of an output format from a schema. The files are
+// keyed by slash-separated path relative to the output directory, so generators
+// can be run in parallel, chained, or tested without writing to disk.
+type Generator interface {
+	Generate(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error)
+}
+
+// GeneratorFunc adapts a function to the Generator interface.
+type GeneratorFunc func(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error)
+
+// Generate calls f.
+func (f GeneratorFunc) Generate(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error) {
+	return f(ctx, schema, opts)
+}
+
+// Options holds the options of every generator; each generator reads its own.
+// Nil options select the defaults.
+type Options struct {
+	GraphQL  *GraphQLOptions
+	Protobuf *ProtobufOptions
+	OpenAPI  *OpenAPIOptions
+	Go       *GoOptions
+	Java     *JavaOptions
+	CSharp   *CSharpOptions
+	Docs     *DocsOptions
+
+	// Templates is a directory of template overrides for the generated files; see Templated
+	Templates string
+	// TemplatesFS holds the template overrides instead of Templates, such as an
+	// embed.FS or in-memory files where there is no file system
+	TemplatesFS fs.FS
+
+	// Header is prepended to every generated file as a comment; see WithHeader
+	Header string
+	// HeaderVariables are substituted in Header, in addition to the built-in variables
+	HeaderVariables map[string]string
+
+	// Metadata is stamped into the GraphQL, Protobuf, OpenAPI, and Go output,
+	// unless their own options set it
+	Metadata *Metadata
+
+	// Int64 is the encoding of 64-bit integers in the GraphQL, OpenAPI, Go, and
+	// documentation output, unless their own options set it
+	Int64 Int64Encoding
+}
+
+// DocsOptions configures the documentation generators.
+type DocsOptions struct {
+	// FormatViews adds REST, gRPC, and GraphQL sections to every service method,
+	// showing how the method is exposed in each format along with the
+	// format-specific documentation (@proto, @graphql, @openapi doc comments).
+	FormatViews bool
+
+	// Diagrams embeds a Mermaid diagram of type references, union memberships,
+	// and service-to-type usage.
+	Diagrams bool
+
+	// Locale selects the translation of doc comments written with @lang(locale),
+	// such as es or pt-BR. Comments without one fall back to a parent locale and
+	// then to the untranslated text.
+	Locale string
+
+	// Int64 is the encoding of 64-bit integers in JSON, which is described in
+	// the documentation when set.
+	Int64 Int64Encoding
+}
+
+// Generators of the formats of this package, by format name
+var generators = map[string]Generator{
+	"graphql": SingleFile("schema.graphql", func(schema *ast.Schema, opts Options) string {
+		return NewGraphQLGeneratorWithOptions(graphqlOptions(opts)).Generate(schema)
+	}),
+	"protobuf": GeneratorFunc(generateProtobufFiles),
+	"openapi": SingleFile("openapi.yaml", func(schema *ast.Schema, opts Options) string {
+		return NewOpenAPIGeneratorWithOptions(openapiOptions(opts)).Generate(schema)
+	}),
+	"go": GeneratorFunc(generateGoFiles),
+	"grpc": SingleFile("grpc.go", func(schema *ast.Schema, opts Options) string {
+		return NewGoGRPCGeneratorWithOptions(opts.Go).Generate(schema)
+	}),
+	"connect": SingleFile("connect.go", func(schema *ast.Schema, opts Options) string {
+		return NewGoConnectGeneratorWithOptions(opts.Go).Generate(schema)
+	}),
+	"java":            GeneratorFunc(generateJavaFiles),
+	"csharp":          GeneratorFunc(generateCSharpFile),
+	"descriptor":      GeneratorFunc(generateDescriptorSetFile),
+	"schema-endpoint": GeneratorFunc(generateGoSchemaEndpoint),
+	"mock": SingleFile("mockserver/main.go", func(schema *ast.Schema, _ Options) string {
+		return NewMockServerGenerator().Generate(schema)
+	}),
+	"contract": SingleFile("contract/contract_test.go", func(schema *ast.Schema, _ Options) string {
+		return NewContractTestGenerator().Generate(schema)
+	}),
+}
+
+// formatAliases maps alternative format names to the names in generators
+var formatAliases = map[string]string{
+	"proto":  "protobuf",
+	"golang": "go",
+	"cs":     "csharp",
+}
+
+// Lookup returns the generator of a format of this package: graphql, protobuf
+// (proto), openapi, go (golang), grpc, connect, descriptor, schema-endpoint,
+// java, csharp (cs), mock, or contract.
+func Lookup(format string) (Generator, error) {
+	format = strings.ToLower(format)
+	if name, ok := formatAliases[format]; ok {
+		format = name
+	}
+	gen, ok := generators[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+	return Wrap(format, gen), nil
+}
+
+// SingleFile returns a generator of a single file at path from a function that renders it.
+func SingleFile(path string, render func(schema *ast.Schema, opts Options) string) Generator {
+	return GeneratorFunc(func(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return map[string][]byte{path: []byte(render(schema, opts))}, nil
+	})
+}
+
+// generateProtobufFiles generates the proto files of the layout of the options:
+// schema.proto, or one file per namespace (e.g., com/example/users.proto) when
+// the schema has several, by default
+func generateProtobufFiles(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	protobufOpts := optionsOf(opts.Protobuf)
+	if protobufOpts.Metadata == nil {
+		protobufOpts.Metadata = opts.Metadata
+	}
+	gen := NewProtobufGeneratorWithOptions(&protobufOpts)
+
+	files := make(map[string][]byte)
+	switch protobufOpts.Layout {
+	case "", ProtobufLayoutNamespace:
+		if len(collectNamespaces(schema)) <= 1 {
+			return map[string][]byte{"schema.proto": []byte(gen.Generate(schema))}, nil
+		}
+		for namespace, content := range gen.GenerateByNamespace(schema) {
+			files[strings.ReplaceAll(namespace, ".", "/")+".proto"] = []byte(content)
+		}
+	case ProtobufLayoutType:
+		for path, content := range gen.GenerateByType(schema) {
+			files[path] = []byte(content)
+		}
+	case ProtobufLayoutSingle:
+		files["schema.proto"] = []byte(gen.Generate(schema))
+	default:
+		return nil, fmt.Errorf("unknown protobuf layout %q (valid: namespace, type, single)", protobufOpts.Layout)
+	}
+	return files, nil
+}
+
+// graphqlOptions returns the GraphQL options of a generation, with the shared
+// metadata and int64 encoding unless they set their own
+func graphqlOptions(opts Options) *GraphQLOptions {
+	graphqlOpts := optionsOf(opts.GraphQL)
+	if graphqlOpts.Metadata == nil {
+		graphqlOpts.Metadata = opts.Metadata
+	}
+	if graphqlOpts.Int64 == "" {
+		graphqlOpts.Int64 = opts.Int64
+	}
+	return &graphqlOpts
+}
+
+// openapiOptions returns the OpenAPI options of a generation, with the shared
+// metadata and int64 encoding unless they set their own
+func openapiOptions(opts Options) *OpenAPIOptions {
+	openapiOpts := optionsOf(opts.OpenAPI)
+	if openapiOpts.Metadata == nil {
+		openapiOpts.Metadata = opts.Metadata
+	}
+	if openapiOpts.Int64 == "" {
+		openapiOpts.Int64 = opts.Int64
+	}
+	return &openapiOpts
+}
+
+// DescriptorSet generates the Protobuf files of a schema, as the protobuf
+// format does with the same options, and compiles them to a serialized
+// google.protobuf.FileDescriptorSet.
+func DescriptorSet(ctx context.Context, schema *ast.Schema, opts Options) ([]byte, error) {
+	files, err := generateProtobufFiles(ctx, schema, opts)
+	if err != nil {
+		return nil, err
+	}
+	return descriptor.Build(files)
+}
+
+// generateDescriptorSetFile generates descriptor.pb, the descriptor set of the
+// Protobuf files of a schema
+func generateDescriptorSetFile(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error) {
+	data, err := DescriptorSet(ctx, schema, opts)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{"descriptor.pb": data}, nil
+}
+
+// generateJavaFiles generates one Java source file per declaration under java/
+func generateJavaFiles(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if opts.Java != nil {
+		switch opts.Java.Style {
+		case "", "record", "pojo":
+		default:
+			return nil, fmt.Errorf("unknown Java style %q (valid: record, pojo)", opts.Java.Style)
+		}
+	}
+
+	files := make(map[string][]byte)
+	for path, content := range NewJavaGeneratorWithOptions(opts.Java).GenerateFiles(schema) {
+		files["java/"+path] = []byte(content)
+	}
+	return files, nil
+}
+
+// generateCSharpFile generates the C# types of a schema into Types.cs
+func generateCSharpFile(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if opts.CSharp != nil {
+		switch opts.CSharp.Style {
+		case "", "record", "class":
+		default:
+			return nil, fmt.Errorf("unknown C# style %q (valid: record, class)", opts.CSharp.Style)
+		}
+	}
+	return map[string][]byte{"Types.cs": []byte(NewCSharpGeneratorWithOptions(opts.CSharp).Generate(schema))}, nil
+}
+
+// flagEnumNames returns the flags enums of a schema, by name and qualified name.
+// Fields of these types hold a combination of values, so generators encode them
+// as integers rather than as a single enum value.
+func flagEnumNames(schema *ast.Schema) map[string]bool {
+	names := make(map[string]bool)
+	for _, enum := range schema.Enums {
+		if enum.Flags {
+			names[enum.Name] = true
+			if enum.Namespace != "" {
+				names[enum.Namespace+"."+enum.Name] = true
+			}
+		}
+	}
+	return names
+}
+
+// collectNamespaces returns all unique namespaces in the schema, counting
+// declarations without a namespace as the default "api" namespace
+func collectNamespaces(schema *ast.Schema) []string {
+	nsMap := make(map[string]bool)
+	add := func(ns string) {
+		if ns == "" {
+			ns = "api"
+		}
+		nsMap[ns] = true
+	}
+
+	for _, enum := range schema.Enums {
+		add(enum.Namespace)
+	}
+	for _, typ := range schema.Types {
+		add(typ.Namespace)
+	}
+	for _, union := range schema.Unions {
+		add(union.Namespace)
+	}
+	for _, service := range schema.Services {
+		add(service.Namespace)
+	}
+
+	result := make([]string, 0, len(nsMap))
+	for ns := range nsMap {
+		result = append(result, ns)
+	}
+	return result
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// generatorTestSchema returns a schema with a User type in each namespace
+func generatorTestSchema(namespaces ...string) *ast.Schema {
+	schema := &ast.Schema{Namespace: namespaces[0]}
+	for _, ns := range namespaces {
+		schema.Types = append(schema.Types, &ast.Type{
+			Name:      "User",
+			Namespace: ns,
+			Fields:    []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}},
+		})
+	}
+	return schema
+}
+
+// filePaths returns the paths of generated files in order
+func filePaths(files map[string][]byte) string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return strings.Join(paths, ",")
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		format string
+		schema *ast.Schema
+		paths  string
+	}{
+		{format: "graphql", schema: generatorTestSchema("api"), paths: "schema.graphql"},
+		{format: "proto", schema: generatorTestSchema("api"), paths: "schema.proto"},
+		{format: "protobuf", schema: generatorTestSchema("com.example.users", "billing"), paths: "billing.proto,com/example/users.proto"},
+		{format: "openapi", schema: generatorTestSchema("api"), paths: "openapi.yaml"},
+		{format: "golang", schema: generatorTestSchema("api"), paths: "types.go"},
+		{format: "grpc", schema: generatorTestSchema("api"), paths: "grpc.go"},
+		{format: "connect", schema: generatorTestSchema("api"), paths: "connect.go"},
+		{format: "descriptor", schema: generatorTestSchema("com.example.users", "billing"), paths: "descriptor.pb"},
+		{format: "schema-endpoint", schema: generatorTestSchema("api"), paths: "schema_endpoint.go"},
+		{format: "java", schema: generatorTestSchema("api"), paths: "java/api/User.java"},
+		{format: "CS", schema: generatorTestSchema("api"), paths: "Types.cs"},
+		{format: "mock", schema: generatorTestSchema("api"), paths: "mockserver/main.go"},
+		{format: "contract", schema: generatorTestSchema("api"), paths: "contract/contract_test.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			gen, err := Lookup(tt.format)
+			if err != nil {
+				t.Fatalf("Lookup failed: %v", err)
+			}
+			files, err := gen.Generate(context.Background(), tt.schema, Options{})
+			if err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			if paths := filePaths(files); paths != tt.paths {
+				t.Errorf("Expected files %s, got %s", tt.paths, paths)
+			}
+		})
+	}
+
+	if _, err := Lookup("thrift"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestGenerate_Options(t *testing.T) {
+	gen, err := Lookup("java")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := gen.Generate(context.Background(), generatorTestSchema("api"), Options{
+		Java: &JavaOptions{PackagePrefix: "org.acme"},
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if paths := filePaths(files); paths != "java/org/acme/api/User.java" {
+		t.Errorf("Expected the package prefix in the path, got %s", paths)
+	}
+}
+
+func TestGenerate_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, format := range []string{"graphql", "protobuf", "java"} {
+		gen, err := Lookup(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gen.Generate(ctx, generatorTestSchema("api"), Options{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", format, err)
+		}
+	}
+}
+
+func TestGenerate_FieldNames(t *testing.T) {
+	schema := generatorTestSchema("api")
+	schema.Types[0].Fields = append(schema.Types[0].Fields, &ast.Field{
+		Name:        "displayName",
+		Type:        &ast.FieldType{Name: "string", IsBuiltin: true},
+		JSONName:    "display_name",
+		Annotations: &ast.FormatAnnotations{ProtoName: "display_name", GraphQLName: "name", GoName: "DisplayNameText"},
+	})
+
+	tests := []struct {
+		format, path, want string
+	}{
+		{"protobuf", "schema.proto", "string display_name = 2;"},
+		{"graphql", "schema.graphql", "  name: String\n"},
+		{"openapi", "openapi.yaml", "display_name:"},
+		{"go", "types.go", "DisplayNameText string `json:\"display_name\"`"},
+	}
+	for _, tt := range tests {
+		gen, err := Lookup(tt.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err := gen.Generate(context.Background(), schema, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(files[tt.path]), tt.want) {
+			t.Errorf("%s: expected %q in:\n%s", tt.format, tt.want, files[tt.path])
+		}
+	}
+}
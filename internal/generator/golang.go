@@ -2,77 +2,317 @@ package generator
 
 import (
 	"fmt"
+	"go/token"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/rasmartins/typemux/internal/ast"
 )
 
+// GoOptions configures the Go generator.
+type GoOptions struct {
+	// ProtoPackage is the Go import path of the protoc-gen-go output for the
+	// schema, in go_package form ("example.com/api/pb" or "example.com/api/pb;apipb").
+	// When set, types, enums, and unions get ToProto and FromProto conversion
+	// functions to and from the generated protobuf types.
+	ProtoPackage string
+
+	// Metadata is stamped into the comment after the generated-code notice
+	// when set.
+	Metadata *Metadata
+
+	// OptionalFields selects the Go type of optional (?) fields.
+	OptionalFields GoOptionalFields
+
+	// TypeMapper maps TypeMUX types to external Go types, which are imported
+	// and used instead of the generated ones. Declarations it maps are not
+	// generated.
+	TypeMapper GoTypeMapper
+
+	// Scaffold adds the standard endpoints of deployable servers to the gRPC and
+	// Connect output: RegisterStandardServices registers the gRPC health service
+	// and server reflection, and HandleStandardEndpoints serves /healthz,
+	// /readyz, and /version over HTTP.
+	Scaffold bool
+
+	// Module makes the go format a Go module with this module path, ready to be
+	// versioned and imported by other services: go.mod, and a package with
+	// doc.go and types.go per namespace.
+	Module string
+
+	// GoVersion is the go directive of the go.mod of a module
+	// (default: DefaultGoModuleVersion).
+	GoVersion string
+
+	// Int64 encodes int64 and uint64 fields as JSON strings with the string and
+	// scalar encodings: scalar fields get the ,string tag option, and lists and
+	// map values the generated StringInt64 and StringUint64 types.
+	Int64 Int64Encoding
+}
+
+// GoOptionalFields selects how optional (?) fields are represented in Go. In the
+// pointer, value, and wrapper styles, optional lists, maps, and bytes stay as
+// they are, since nil already marks them unset.
+type GoOptionalFields string
+
+const (
+	// GoOptionalDefault makes optional messages, enums, and timestamps pointers,
+	// and keeps optional scalars values with omitempty.
+	GoOptionalDefault GoOptionalFields = ""
+	// GoOptionalPointer makes every optional field a pointer, *T.
+	GoOptionalPointer GoOptionalFields = "pointer"
+	// GoOptionalValue keeps every optional field a value, T with omitempty, so an
+	// unset field cannot be told from its zero value.
+	GoOptionalValue GoOptionalFields = "value"
+	// GoOptionalWrapper wraps optional scalars, enums, and timestamps in a
+	// generated Null[T] type in the style of null.String, which is null in JSON
+	// when unset, and makes optional messages pointers.
+	GoOptionalWrapper GoOptionalFields = "wrapper"
+)
+
 // GoGenerator generates Go code from TypeMUX schemas.
-type GoGenerator struct{}
+type GoGenerator struct {
+	opts           GoOptions
+	validated      map[string]bool   // Types that get a Validate method
+	enums          map[string]bool   // Enum names
+	imports        map[string]bool   // Packages used by the generated code
+	importNames    map[string]string // Names of imports that need one, by package path
+	formatPatterns map[string]bool   // @validate formats checked with a shared regular expression
+
+	nestedValidation bool // Whether Validate methods call Validate on nested types
+
+	types          map[string]*ast.Type  // Types by name, for protobuf conversions
+	unions         map[string]*ast.Union // Unions by name, for protobuf conversions
+	protoHelpers   bool                  // Whether protobuf conversions use the pointer helpers
+	nullHelper     bool                  // Whether optional fields use the Null wrapper
+	int64Helpers   map[string]bool       // StringInt64 and StringUint64 types used by lists and maps
+	taggedHelper   bool                  // Whether unions are encoded with marshalInternallyTagged
+	untaggedHelper bool                  // Whether untagged unions are decoded with decodeStrict
+	recursive      map[*ast.Field]bool   // Fields that would embed their own type, emitted as pointers
+}
 
 // NewGoGenerator creates a new Go code generator.
 func NewGoGenerator() *GoGenerator {
 	return &GoGenerator{}
 }
 
+// NewGoGeneratorWithOptions creates a new Go code generator with the given options.
+func NewGoGeneratorWithOptions(opts *GoOptions) *GoGenerator {
+	g := &GoGenerator{}
+	if opts != nil {
+		g.opts = *opts
+	}
+	return g
+}
+
 // Generate creates Go code from the given schema.
 func (g *GoGenerator) Generate(schema *ast.Schema) string {
 	var sb strings.Builder
 
-	// Package declaration
-	packageName := g.getPackageName(schema.Namespace)
+	packageName := g.packageName(schema)
 
-	// Check for @go.package annotation at namespace level
-	if schema.NamespaceAnnotations != nil && len(schema.NamespaceAnnotations.Go) > 0 {
-		for _, goAnnotation := range schema.NamespaceAnnotations.Go {
-			if strings.HasPrefix(goAnnotation, "package") {
-				// Extract package name from 'package = "mypackage"' format
-				parts := strings.Split(goAnnotation, "=")
-				if len(parts) == 2 {
-					packageName = strings.Trim(strings.TrimSpace(parts[1]), "\"")
-				}
-			}
-		}
+	g.imports = make(map[string]bool)
+	g.importNames = make(map[string]string)
+	g.formatPatterns = make(map[string]bool)
+	g.nestedValidation = false
+	g.enums = make(map[string]bool)
+	for _, enum := range schema.Enums {
+		g.enums[enum.Name] = true
 	}
+	g.protoHelpers = false
+	g.nullHelper = false
+	g.int64Helpers = make(map[string]bool)
+	g.taggedHelper = false
+	g.untaggedHelper = false
+	g.types = make(map[string]*ast.Type)
+	for _, typ := range schema.Types {
+		g.types[typ.Name] = typ
+	}
+	g.unions = make(map[string]*ast.Union)
+	for _, union := range schema.Unions {
+		g.unions[union.Name] = union
+	}
+	g.recursive = g.findRecursiveFields(schema)
+	g.validated = g.collectValidatedTypes(schema)
 
-	sb.WriteString("// Code generated by TypeMUX. DO NOT EDIT.\n")
-	sb.WriteString(fmt.Sprintf("package %s\n\n", packageName))
-
-	// Imports
-	needsTime := g.needsTimeImport(schema)
-	if needsTime {
-		sb.WriteString("import (\n")
-		sb.WriteString("\t\"time\"\n")
-		sb.WriteString(")\n\n")
+	if g.needsTimeImport(schema) {
+		g.imports["time"] = true
 	}
 
+	var body strings.Builder
+
 	// Generate enums
 	for _, enum := range schema.Enums {
-		sb.WriteString(g.generateEnum(enum))
-		sb.WriteString("\n")
+		if g.isExternal(enum.Name) {
+			continue
+		}
+		body.WriteString(g.generateEnum(enum))
+		body.WriteString("\n")
+		if g.opts.ProtoPackage != "" {
+			body.WriteString(g.generateEnumProtoConversion(enum))
+			body.WriteString("\n")
+		}
 	}
 
 	// Generate types
 	for _, typ := range schema.Types {
-		sb.WriteString(g.generateType(typ))
-		sb.WriteString("\n")
+		if g.isExternal(typ.Name) {
+			continue
+		}
+		body.WriteString(g.generateType(typ))
+		body.WriteString("\n")
+		if isStdMoney(typ) {
+			body.WriteString(g.generateMoneyHelpers())
+			body.WriteString("\n")
+		}
+		if decoding := g.generateUnionFieldsDecoding(typ); decoding != "" {
+			body.WriteString(decoding)
+			body.WriteString("\n")
+		}
+		if g.validated[typ.Name] {
+			body.WriteString(g.generateValidate(typ))
+			body.WriteString("\n")
+		}
+		if g.opts.ProtoPackage != "" {
+			body.WriteString(g.generateTypeProtoConversion(typ))
+			body.WriteString("\n")
+		}
 	}
 
 	// Generate unions
 	for _, union := range schema.Unions {
-		sb.WriteString(g.generateUnion(union))
-		sb.WriteString("\n")
+		if g.isExternal(union.Name) {
+			continue
+		}
+		body.WriteString(g.generateUnion(union))
+		body.WriteString("\n")
+		if union.Encoding != nil {
+			body.WriteString(g.generateUnionEncoding(union))
+			body.WriteString("\n")
+		} else if g.isMethodMessage(schema, union) {
+			body.WriteString(g.generateUnionDecoding(union))
+			body.WriteString("\n")
+		}
+		if g.opts.ProtoPackage != "" {
+			if conversion := g.generateUnionProtoConversion(union); conversion != "" {
+				body.WriteString(conversion)
+				body.WriteString("\n")
+			}
+		}
 	}
 
-	// Generate service interfaces
+	// Generate service interfaces, with middleware hooks for services that declare call policies
+	policies := false
 	for _, service := range schema.Services {
-		sb.WriteString(g.generateService(service))
+		body.WriteString(g.generateService(service))
+		body.WriteString("\n")
+		if g.hasPolicies(service) {
+			body.WriteString(g.generateServicePolicies(service))
+			body.WriteString("\n")
+			policies = true
+		}
+	}
+	if policies {
+		body.WriteString(goPolicyTypes)
+		body.WriteString("\n")
+		g.imports["time"] = true
+	}
+
+	if helper := g.generateValidationHelper(); helper != "" {
+		body.WriteString(helper)
+		body.WriteString("\n")
+	}
+
+	if helper := g.generateProtoHelpers(); helper != "" {
+		body.WriteString(helper)
+		body.WriteString("\n")
+	}
+
+	if helper := g.generateNullHelper(); helper != "" {
+		body.WriteString(helper)
+		body.WriteString("\n")
+	}
+
+	if helper := g.generateInt64Helpers(); helper != "" {
+		body.WriteString(helper)
+		body.WriteString("\n")
+	}
+
+	if helper := g.generateUnionHelpers(); helper != "" {
+		body.WriteString(helper)
+		body.WriteString("\n")
+	}
+
+	sb.WriteString("// Code generated by TypeMUX. DO NOT EDIT.\n")
+	if metadata := g.opts.Metadata; metadata != nil {
+		for _, line := range metadata.lines() {
+			sb.WriteString("// " + line + "\n")
+		}
+		if metadata.Source != "" {
+			sb.WriteString(fmt.Sprintf("//go:generate typemux -input %s -format go -output . -stamp\n", goGenerateArg(metadata.Source)))
+		}
 		sb.WriteString("\n")
 	}
+	sb.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+
+	// Imports
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for pkg := range g.imports {
+			imports = append(imports, pkg)
+		}
+		sort.Strings(imports)
+
+		sb.WriteString("import (\n")
+		for _, pkg := range imports {
+			if name := g.importNames[pkg]; name != "" {
+				sb.WriteString(fmt.Sprintf("\t%s %q\n", name, pkg))
+			} else {
+				sb.WriteString(fmt.Sprintf("\t%q\n", pkg))
+			}
+		}
+		sb.WriteString(")\n\n")
+	}
+
+	if patterns := g.generateFormatPatterns(); patterns != "" {
+		sb.WriteString(patterns)
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(body.String())
 
 	return sb.String()
 }
 
+// goGenerateArg quotes an argument of a //go:generate directive when it contains spaces or quotes
+func goGenerateArg(arg string) string {
+	if strings.ContainsAny(arg, " \t\"") {
+		return strconv.Quote(arg)
+	}
+	return arg
+}
+
+// packageName returns the Go package of a schema: its @go.package, or the
+// package of its namespace
+func (g *GoGenerator) packageName(schema *ast.Schema) string {
+	packageName := g.getPackageName(schema.Namespace)
+
+	// Check for @go.package annotation at namespace level
+	if schema.NamespaceAnnotations != nil && len(schema.NamespaceAnnotations.Go) > 0 {
+		for _, goAnnotation := range schema.NamespaceAnnotations.Go {
+			if strings.HasPrefix(goAnnotation, "package") {
+				// Extract package name from 'package = "mypackage"' format
+				parts := strings.Split(goAnnotation, "=")
+				if len(parts) == 2 {
+					packageName = strings.Trim(strings.TrimSpace(parts[1]), "\"")
+				}
+			}
+		}
+	}
+	return packageName
+}
+
 // getPackageName converts a namespace to a valid Go package name
 func (g *GoGenerator) getPackageName(namespace string) string {
 	if namespace == "" {
@@ -89,6 +329,11 @@ func (g *GoGenerator) getPackageName(namespace string) string {
 	pkgName = strings.ReplaceAll(pkgName, "-", "")
 	pkgName = strings.ReplaceAll(pkgName, "_", "")
 
+	// Escape keywords, e.g. "com.example.type" -> "type_"
+	if token.IsKeyword(pkgName) {
+		pkgName += "_"
+	}
+
 	return pkgName
 }
 
@@ -96,7 +341,7 @@ func (g *GoGenerator) getPackageName(namespace string) string {
 func (g *GoGenerator) needsTimeImport(schema *ast.Schema) bool {
 	for _, typ := range schema.Types {
 		for _, field := range typ.Fields {
-			if field.Type.Name == "timestamp" {
+			if field.Type.Name == "timestamp" && !g.isExternal("timestamp") {
 				return true
 			}
 		}
@@ -120,36 +365,173 @@ func (g *GoGenerator) generateEnum(enum *ast.Enum) string {
 	}
 
 	// Type definition
-	sb.WriteString(fmt.Sprintf("type %s int\n\n", enum.Name))
+	sb.WriteString(fmt.Sprintf("type %s int32\n\n", enum.Name))
+
+	// Values are numbered like the protobuf output: an UNSPECIFIED zero value is
+	// added unless one is declared, and automatic numbers start at 1
+	zeroName := ""
+	for _, value := range enum.Values {
+		if value.HasNumber && value.Number == 0 {
+			zeroName = enum.Name + value.Name
+			break
+		}
+	}
+
+	type enumConst struct {
+		constName string
+		name      string
+		number    int
+	}
+	var consts []enumConst
+	if zeroName == "" {
+		zeroName = enum.Name + "Unspecified"
+		consts = append(consts, enumConst{zeroName, strings.ToUpper(enum.Name) + "_UNSPECIFIED", 0})
+	}
 
 	// Const block
 	sb.WriteString("const (\n")
+	if len(consts) > 0 {
+		sb.WriteString(fmt.Sprintf("\t// %s is the zero value, used for unset and unknown values.\n", zeroName))
+		sb.WriteString(fmt.Sprintf("\t%s %s = 0\n", zeroName, enum.Name))
+	}
+
+	numbers := enum.ValueNumbers()
 	for i, value := range enum.Values {
 		// Value documentation
-		if value.Doc != nil && value.Doc.General != "" {
-			sb.WriteString(fmt.Sprintf("\t// %s\n", strings.TrimSpace(value.Doc.General)))
+		if doc := value.Doc.GetDoc("go"); doc != "" {
+			sb.WriteString(g.formatIndentedComment(doc))
 		}
 
-		// Enum value
-		if i == 0 {
-			if value.HasNumber {
-				sb.WriteString(fmt.Sprintf("\t%s%s %s = %d\n", enum.Name, value.Name, enum.Name, value.Number))
-			} else {
-				sb.WriteString(fmt.Sprintf("\t%s%s %s = iota\n", enum.Name, value.Name, enum.Name))
-			}
-		} else {
-			if value.HasNumber {
-				sb.WriteString(fmt.Sprintf("\t%s%s %s = %d\n", enum.Name, value.Name, enum.Name, value.Number))
-			} else {
-				sb.WriteString(fmt.Sprintf("\t%s%s\n", enum.Name, value.Name))
+		constName := enum.Name + value.Name
+		consts = append(consts, enumConst{constName, value.Name, numbers[i]})
+		sb.WriteString(fmt.Sprintf("\t%s %s = %d\n", constName, enum.Name, numbers[i]))
+	}
+	sb.WriteString(")\n\n")
+
+	// Name lookup tables
+	varPrefix := strings.ToLower(enum.Name[:1]) + enum.Name[1:]
+	sb.WriteString(fmt.Sprintf("// %sNames maps %s values to their names.\n", varPrefix, enum.Name))
+	sb.WriteString(fmt.Sprintf("var %sNames = map[%s]string{\n", varPrefix, enum.Name))
+	for _, c := range consts {
+		sb.WriteString(fmt.Sprintf("\t%s: %q,\n", c.constName, c.name))
+	}
+	sb.WriteString("}\n\n")
+
+	// Flags are encoded as numbers, so only the bits are listed for String
+	if enum.Flags {
+		sb.WriteString(fmt.Sprintf("// %sBits lists the %s flags in order.\n", varPrefix, enum.Name))
+		sb.WriteString(fmt.Sprintf("var %sBits = []%s{\n", varPrefix, enum.Name))
+		for _, c := range consts {
+			if c.number != 0 {
+				sb.WriteString(fmt.Sprintf("\t%s,\n", c.constName))
 			}
 		}
+		sb.WriteString("}\n\n")
+
+		g.imports["fmt"] = true
+		g.imports["strings"] = true
+		sb.WriteString(fmt.Sprintf(goFlagsMethods, enum.Name, varPrefix))
+		return sb.String()
 	}
-	sb.WriteString(")\n")
+
+	sb.WriteString(fmt.Sprintf("// %sValues maps names to %s values.\n", varPrefix, enum.Name))
+	sb.WriteString(fmt.Sprintf("var %sValues = map[string]%s{\n", varPrefix, enum.Name))
+	for _, c := range consts {
+		sb.WriteString(fmt.Sprintf("\t%q: %s,\n", c.name, c.constName))
+	}
+	sb.WriteString("}\n\n")
+
+	g.imports["encoding/json"] = true
+	g.imports["fmt"] = true
+	sb.WriteString(fmt.Sprintf(goEnumMethods, enum.Name, varPrefix, zeroName))
 
 	return sb.String()
 }
 
+// goEnumMethods is the String, Parse, and JSON methods of an enum type
+// (arguments: type name, lookup table prefix, zero value)
+const goEnumMethods = `// String returns the name of the value, or %[1]s(n) for unknown values.
+func (x %[1]s) String() string {
+	if name, ok := %[2]sNames[x]; ok {
+		return name
+	}
+	return fmt.Sprintf("%[1]s(%%d)", int32(x))
+}
+
+// Parse%[1]s returns the %[1]s with the given name.
+func Parse%[1]s(name string) (%[1]s, error) {
+	if value, ok := %[2]sValues[name]; ok {
+		return value, nil
+	}
+	return %[3]s, fmt.Errorf("unknown %[1]s %%q", name)
+}
+
+// MarshalJSON encodes the value as its name, or as a number for unknown values.
+func (x %[1]s) MarshalJSON() ([]byte, error) {
+	if name, ok := %[2]sNames[x]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int32(x))
+}
+
+// UnmarshalJSON decodes a name or a number. Unknown names decode to %[3]s.
+func (x *%[1]s) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		value, ok := %[2]sValues[name]
+		if !ok {
+			value = %[3]s
+		}
+		*x = value
+		return nil
+	}
+
+	var number int32
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid %[1]s: %%s", data)
+	}
+	*x = %[1]s(number)
+	return nil
+}
+`
+
+// goFlagsMethods is the String and bit methods of a flags enum type, which is
+// encoded in JSON as its number (arguments: type name, lookup table prefix)
+const goFlagsMethods = `// String returns the names of the flags that are set, joined by |.
+func (x %[1]s) String() string {
+	if name, ok := %[2]sNames[x]; ok {
+		return name
+	}
+	var names []string
+	rest := x
+	for _, bit := range %[2]sBits {
+		if x&bit == bit {
+			names = append(names, %[2]sNames[bit])
+			rest &^= bit
+		}
+	}
+	if rest != 0 {
+		names = append(names, fmt.Sprintf("%[1]s(%%d)", int32(rest)))
+	}
+	return strings.Join(names, "|")
+}
+
+// Has reports whether all the flags of flag are set.
+func (x %[1]s) Has(flag %[1]s) bool {
+	return x&flag == flag
+}
+
+// Set returns the value with the flags of flag set.
+func (x %[1]s) Set(flag %[1]s) %[1]s {
+	return x | flag
+}
+
+// Clear returns the value with the flags of flag cleared.
+func (x %[1]s) Clear(flag %[1]s) %[1]s {
+	return x &^ flag
+}
+`
+
 // generateType generates Go code for a struct type
 func (g *GoGenerator) generateType(typ *ast.Type) string {
 	var sb strings.Builder
@@ -168,32 +550,31 @@ func (g *GoGenerator) generateType(typ *ast.Type) string {
 	// Struct definition
 	sb.WriteString(fmt.Sprintf("type %s struct {\n", typ.Name))
 
+	// Base types are embedded, so their fields are promoted and flattened in JSON
+	for _, base := range typ.Extends {
+		sb.WriteString(fmt.Sprintf("\t%s\n", g.cleanTypeName(base)))
+	}
+
 	for _, field := range typ.Fields {
+		if field.InheritedFrom != "" {
+			continue
+		}
+
 		// Field documentation
-		if field.Doc != nil && field.Doc.General != "" {
-			doc := field.Doc.GetDoc("go")
-			if doc == "" {
-				doc = field.Doc.General
-			}
-			// Format multi-line comments properly
-			lines := strings.Split(strings.TrimSpace(doc), "\n")
-			for _, line := range lines {
-				sb.WriteString(fmt.Sprintf("\t// %s\n", strings.TrimSpace(line)))
-			}
+		if doc := field.Doc.GetDoc("go"); doc != "" {
+			sb.WriteString(g.formatIndentedComment(doc))
 		}
 
 		// Field definition
-		fieldName := g.exportFieldName(field.Name)
-		fieldType := g.mapTypeToGo(field.Type)
+		fieldName := g.goFieldName(field)
+		fieldType := g.goFieldType(field)
 
-		// Handle @json.nullable - make the field a pointer type
-		if field.JSONNullable && !strings.HasPrefix(fieldType, "*") && !strings.HasPrefix(fieldType, "[]") && !strings.HasPrefix(fieldType, "map[") {
-			fieldType = "*" + fieldType
+		tags := fmt.Sprintf("json:%q", g.getJSONTag(field))
+		if field.Sensitivity != "" {
+			tags += fmt.Sprintf(" sensitive:%q", field.Sensitivity)
 		}
 
-		jsonTag := g.getJSONTag(field)
-
-		sb.WriteString(fmt.Sprintf("\t%s %s `json:\"%s\"`\n", fieldName, fieldType, jsonTag))
+		sb.WriteString(fmt.Sprintf("\t%s %s `%s`\n", fieldName, fieldType, tags))
 	}
 
 	sb.WriteString("}\n")
@@ -201,6 +582,63 @@ func (g *GoGenerator) generateType(typ *ast.Type) string {
 	return sb.String()
 }
 
+// goFieldType returns the Go type of a struct field
+func (g *GoGenerator) goFieldType(field *ast.Field) string {
+	fieldType := g.mapTypeToGo(field.Type)
+
+	// Handle @json.nullable - make the field a pointer type. Recursive fields are
+	// pointers too, since a struct cannot contain itself.
+	if (field.JSONNullable || g.recursive[field]) && !strings.HasPrefix(fieldType, "*") && !strings.HasPrefix(fieldType, "[]") && !strings.HasPrefix(fieldType, "map[") && !strings.HasPrefix(fieldType, "Null[") {
+		fieldType = "*" + fieldType
+	}
+
+	return fieldType
+}
+
+// findRecursiveFields returns the fields that would embed their own type by
+// value, directly as in Tree.parent or through other types as in Employee.manager
+// and Department.head. Arrays, maps, and optional fields already break the cycle,
+// unless optional fields are values.
+func (g *GoGenerator) findRecursiveFields(schema *ast.Schema) map[*ast.Field]bool {
+	// embedded returns the type a field holds by value, or nil
+	embedded := func(field *ast.Field) *ast.Type {
+		ft := field.Type
+		optional := ft != nil && ft.Optional && g.opts.OptionalFields != GoOptionalValue
+		if ft == nil || ft.IsArray || ft.IsMap || optional || field.JSONNullable || g.isExternal(ft.Name) {
+			return nil
+		}
+		return g.types[g.cleanTypeName(ft.Name)]
+	}
+
+	// reaches reports whether a type embeds the target by value
+	var reaches func(typ, target *ast.Type, visited map[*ast.Type]bool) bool
+	reaches = func(typ, target *ast.Type, visited map[*ast.Type]bool) bool {
+		if typ == target {
+			return true
+		}
+		if visited[typ] {
+			return false
+		}
+		visited[typ] = true
+		for _, field := range typ.Fields {
+			if next := embedded(field); next != nil && reaches(next, target, visited) {
+				return true
+			}
+		}
+		return false
+	}
+
+	recursive := make(map[*ast.Field]bool)
+	for _, typ := range schema.Types {
+		for _, field := range typ.Fields {
+			if next := embedded(field); next != nil && reaches(next, typ, make(map[*ast.Type]bool)) {
+				recursive[field] = true
+			}
+		}
+	}
+	return recursive
+}
+
 // generateUnion generates Go code for a union type
 func (g *GoGenerator) generateUnion(union *ast.Union) string {
 	var sb strings.Builder
@@ -224,8 +662,9 @@ func (g *GoGenerator) generateUnion(union *ast.Union) string {
 	// Generate concrete types for each option
 	for _, option := range union.Options {
 		typeName := fmt.Sprintf("%s%s", union.Name, option)
+		sb.WriteString(fmt.Sprintf("// %s holds the %s option of %s.\n", typeName, option, union.Name))
 		sb.WriteString(fmt.Sprintf("type %s struct {\n", typeName))
-		sb.WriteString(fmt.Sprintf("\tValue %s `json:\"value\"`\n", option))
+		sb.WriteString(fmt.Sprintf("\tValue %s `json:\"value\"`\n", g.messageType(option)))
 		sb.WriteString("}\n\n")
 		sb.WriteString(fmt.Sprintf("func (%s) is%s() {}\n\n", typeName, union.Name))
 	}
@@ -249,23 +688,17 @@ func (g *GoGenerator) generateService(service *ast.Service) string {
 	}
 
 	// Service interface
+	g.imports["context"] = true
 	sb.WriteString(fmt.Sprintf("type %s interface {\n", service.Name))
 
 	for _, method := range service.Methods {
 		// Method documentation
-		if method.Doc != nil && method.Doc.General != "" {
-			sb.WriteString(fmt.Sprintf("\t// %s\n", strings.TrimSpace(method.Doc.General)))
+		if doc := method.Doc.GetDoc("go"); doc != "" {
+			sb.WriteString(g.formatIndentedComment(doc))
 		}
 
-		// Method signature
-		inputType := g.cleanTypeName(method.InputType)
-		outputType := g.cleanTypeName(method.OutputType)
-
-		if method.OutputStream {
-			sb.WriteString(fmt.Sprintf("\t%s(input *%s, stream chan *%s) error\n", method.Name, inputType, outputType))
-		} else {
-			sb.WriteString(fmt.Sprintf("\t%s(input *%s) (*%s, error)\n", method.Name, inputType, outputType))
-		}
+		params, _, results := g.methodSignature(method)
+		sb.WriteString(fmt.Sprintf("\t%s(%s) %s\n", method.Name, strings.Join(params, ", "), results))
 	}
 
 	sb.WriteString("}\n")
@@ -273,6 +706,38 @@ func (g *GoGenerator) generateService(service *ast.Service) string {
 	return sb.String()
 }
 
+// methodSignature returns the parameters, their names, and the results of a service
+// method. Every method takes the context of the call first. Methods without input
+// take no input parameter, and methods without output or with streamed output only
+// return an error.
+func (g *GoGenerator) methodSignature(method *ast.Method) (params, args []string, results string) {
+	params, args = []string{"ctx context.Context"}, []string{"ctx"}
+	if method.HasInput() {
+		params = append(params, fmt.Sprintf("input *%s", g.messageType(method.InputType)))
+		args = append(args, "input")
+	}
+	outputType := g.messageType(method.OutputType)
+
+	switch {
+	case method.OutputStream:
+		params = append(params, fmt.Sprintf("stream chan *%s", outputType))
+		args = append(args, "stream")
+		return params, args, "error"
+	case method.HasOutput():
+		return params, args, fmt.Sprintf("(*%s, error)", outputType)
+	default:
+		return params, args, "error"
+	}
+}
+
+// messageType returns the Go type of a method input or output
+func (g *GoGenerator) messageType(typeName string) string {
+	if external, ok := g.externalType(typeName); ok {
+		return external
+	}
+	return g.cleanTypeName(typeName)
+}
+
 // mapTypeToGo maps TypeMUX types to Go types
 func (g *GoGenerator) mapTypeToGo(fieldType *ast.FieldType) string {
 	var goType string
@@ -308,11 +773,18 @@ func (g *GoGenerator) mapTypeToGo(fieldType *ast.FieldType) string {
 		goType = g.cleanTypeName(fieldType.Name)
 	}
 
+	// Handle types mapped to external Go types
+	if external, ok := g.externalType(fieldType.Name); ok {
+		goType = external
+	}
+
 	// Handle map type
 	if fieldType.MapKey != "" {
 		keyType := g.mapScalarTypeToGo(fieldType.MapKey)
 		var valueType string
-		if fieldType.MapValueType != nil {
+		if elem := g.int64ElementType(fieldType.GetMapValueType()); elem != "" {
+			valueType = elem
+		} else if fieldType.MapValueType != nil {
 			// Complex map value (array, nested map, etc.)
 			valueType = g.mapTypeToGo(fieldType.MapValueType)
 		} else {
@@ -324,22 +796,102 @@ func (g *GoGenerator) mapTypeToGo(fieldType *ast.FieldType) string {
 
 	// Handle array
 	if fieldType.IsArray {
+		if elem := g.int64ElementType(&ast.FieldType{Name: fieldType.Name}); elem != "" {
+			goType = elem
+		}
 		goType = "[]" + goType
 	}
 
-	// Handle optional (pointer for structs, not for primitives in Go)
+	// Handle optional
 	if fieldType.Optional {
-		// Only use pointer for non-primitive types
-		if !g.isPrimitiveType(fieldType.Name) {
-			goType = "*" + goType
-		}
+		goType = g.optionalType(fieldType, goType)
 	}
 
 	return goType
 }
 
+// optionalType returns the Go type of an optional field of type goType, in the
+// style of the OptionalFields option
+func (g *GoGenerator) optionalType(fieldType *ast.FieldType, goType string) string {
+	// A nil interface already marks unset unions that are decoded by their Unmarshal function
+	if !fieldType.IsArray && !fieldType.IsMap && g.encodedUnion(fieldType.Name) != nil {
+		return goType
+	}
+
+	// By default, only use pointers for non-primitive types
+	if g.opts.OptionalFields == GoOptionalDefault {
+		if g.isPrimitiveType(fieldType.Name) {
+			return goType
+		}
+		return "*" + goType
+	}
+
+	// nil already marks unset lists, maps, and bytes
+	if fieldType.IsArray || fieldType.IsMap || fieldType.Name == "bytes" {
+		return goType
+	}
+
+	switch g.opts.OptionalFields {
+	case GoOptionalValue:
+		return goType
+	case GoOptionalWrapper:
+		// The ,string tag option of quoted 64-bit integers does not reach into Null
+		if g.quotedInt64(fieldType) {
+			return "*" + goType
+		}
+		if g.isPrimitiveType(fieldType.Name) || fieldType.Name == "timestamp" || g.enums[g.cleanTypeName(fieldType.Name)] {
+			g.nullHelper = true
+			return "Null[" + goType + "]"
+		}
+	}
+	return "*" + goType
+}
+
+// generateNullHelper generates the Null wrapper of optional fields, if used
+func (g *GoGenerator) generateNullHelper() string {
+	if !g.nullHelper {
+		return ""
+	}
+	g.imports["encoding/json"] = true
+	return `// Null is an optional value; it is null in JSON when Valid is false.
+type Null[T any] struct {
+	Value T
+	Valid bool
+}
+
+// NullOf returns a set Null holding value.
+func NullOf[T any](value T) Null[T] {
+	return Null[T]{Value: value, Valid: true}
+}
+
+// MarshalJSON encodes the value, or null when it is not set.
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// UnmarshalJSON decodes the value; null leaves it unset.
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = Null[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.Value); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+`
+}
+
 // mapScalarTypeToGo maps scalar types for maps
 func (g *GoGenerator) mapScalarTypeToGo(typeName string) string {
+	if goType, ok := g.externalType(typeName); ok {
+		return goType
+	}
 	switch typeName {
 	case "string":
 		return "string"
@@ -361,6 +913,8 @@ func (g *GoGenerator) mapScalarTypeToGo(typeName string) string {
 		return "float64"
 	case "bool":
 		return "bool"
+	case "bytes":
+		return "[]byte"
 	default:
 		return g.cleanTypeName(typeName)
 	}
@@ -407,6 +961,14 @@ func (g *GoGenerator) exportFieldName(name string) string {
 	return strings.Join(parts, "")
 }
 
+// goFieldName returns the Go name of a field: its @go.name, or its name in PascalCase
+func (g *GoGenerator) goFieldName(field *ast.Field) string {
+	if field.Annotations != nil && field.Annotations.GoName != "" {
+		return field.Annotations.GoName
+	}
+	return g.exportFieldName(field.Name)
+}
+
 // getJSONTag generates the JSON tag for a field
 func (g *GoGenerator) getJSONTag(field *ast.Field) string {
 	// Use JSONName if specified, otherwise use field name
@@ -420,6 +982,11 @@ func (g *GoGenerator) getJSONTag(field *ast.Field) string {
 		tag += ",omitempty"
 	}
 
+	// Encode 64-bit integers as strings under a quoting Int64 encoding
+	if g.quotedInt64(field.Type) {
+		tag += ",string"
+	}
+
 	return tag
 }
 
@@ -428,9 +995,24 @@ func (g *GoGenerator) formatComment(comment string) string {
 	lines := strings.Split(strings.TrimSpace(comment), "\n")
 	var result strings.Builder
 	for _, line := range lines {
+		if line = strings.TrimSpace(line); line == "" {
+			result.WriteString("//\n")
+			continue
+		}
 		result.WriteString("// ")
-		result.WriteString(strings.TrimSpace(line))
+		result.WriteString(line)
 		result.WriteString("\n")
 	}
 	return result.String()
 }
+
+// formatIndentedComment formats a comment for a struct field, constant, or method
+func (g *GoGenerator) formatIndentedComment(comment string) string {
+	var result strings.Builder
+	for _, line := range strings.Split(g.formatComment(comment), "\n") {
+		if line != "" {
+			result.WriteString("\t" + line + "\n")
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,564 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// GoConnectGenerator generates net/http handlers and clients that speak the
+// Connect protocol (https://connectrpc.com) with JSON messages, for the Go
+// service interfaces of GoGenerator. The file belongs in the same package as
+// types.go and needs only the standard library, and Connect clients and servers
+// in other languages interoperate with it over JSON.
+type GoConnectGenerator struct {
+	goGen    *GoGenerator
+	scaffold bool
+}
+
+// NewGoConnectGenerator creates a new Go Connect handler and client generator.
+func NewGoConnectGenerator() *GoConnectGenerator {
+	return &GoConnectGenerator{goGen: NewGoGenerator()}
+}
+
+// NewGoConnectGeneratorWithOptions creates a new Go Connect handler and client
+// generator that reads the Scaffold option; nil options select the defaults.
+func NewGoConnectGeneratorWithOptions(opts *GoOptions) *GoConnectGenerator {
+	g := NewGoConnectGenerator()
+	if opts != nil {
+		g.scaffold = opts.Scaffold
+	}
+	return g
+}
+
+// goConnectStandardEndpoints serves the endpoints deployments probe
+const goConnectStandardEndpoints = `// ReadinessCheck reports why the server cannot take traffic yet, or nil once it can.
+type ReadinessCheck func(ctx context.Context) error
+
+// HandleStandardEndpoints registers the endpoints deployments probe with mux:
+// /healthz answers while the process serves requests, /readyz answers once
+// every check passes and 503 with the first failure before, and /version
+// answers {"version": ServerVersion}.
+func HandleStandardEndpoints(mux *http.ServeMux, checks ...ReadinessCheck) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, "ok\n")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, check := range checks {
+			if err := check(r.Context()); err != nil {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = io.WriteString(w, err.Error()+"\n")
+				return
+			}
+		}
+		_, _ = io.WriteString(w, "ok\n")
+	})
+	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"version": ServerVersion})
+	})
+}
+`
+
+// goConnectRuntime is the protocol code shared by the generated handlers and clients
+const goConnectRuntime = `// ConnectError is the error of a failed Connect call, with a code such as
+// "not_found". Service implementations return it to choose the code of a
+// failure; other errors fail with "unknown".
+type ConnectError struct {
+	Code    string ` + "`json:\"code\"`" + `
+	Message string ` + "`json:\"message,omitempty\"`" + `
+}
+
+func (e *ConnectError) Error() string {
+	if e.Message == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Message
+}
+
+// connectStatus maps Connect error codes to the HTTP status of unary responses.
+var connectStatus = map[string]int{
+	"canceled":            499,
+	"unknown":             http.StatusInternalServerError,
+	"invalid_argument":    http.StatusBadRequest,
+	"deadline_exceeded":   http.StatusGatewayTimeout,
+	"not_found":           http.StatusNotFound,
+	"already_exists":      http.StatusConflict,
+	"permission_denied":   http.StatusForbidden,
+	"resource_exhausted":  http.StatusTooManyRequests,
+	"failed_precondition": http.StatusBadRequest,
+	"aborted":             http.StatusConflict,
+	"out_of_range":        http.StatusBadRequest,
+	"unimplemented":       http.StatusNotImplemented,
+	"internal":            http.StatusInternalServerError,
+	"unavailable":         http.StatusServiceUnavailable,
+	"data_loss":           http.StatusInternalServerError,
+	"unauthenticated":     http.StatusUnauthorized,
+}
+
+// connectEmpty is the message of methods without input or output.
+type connectEmpty struct{}
+
+// connectEndStream is the last message of a stream, with the error of a failed call.
+type connectEndStream struct {
+	Error *ConnectError ` + "`json:\"error,omitempty\"`" + `
+}
+
+// connectEndStreamFlag marks the envelope of the end-of-stream message.
+const connectEndStreamFlag = 0x02
+
+// connectErrorOf returns err as a ConnectError: "deadline_exceeded" and "canceled"
+// for the errors of an ended context, and "unknown" for other errors.
+func connectErrorOf(err error) *ConnectError {
+	var connectErr *ConnectError
+	switch {
+	case errors.As(err, &connectErr):
+		return connectErr
+	case errors.Is(err, context.DeadlineExceeded):
+		return &ConnectError{Code: "deadline_exceeded", Message: err.Error()}
+	case errors.Is(err, context.Canceled):
+		return &ConnectError{Code: "canceled", Message: err.Error()}
+	}
+	return &ConnectError{Code: "unknown", Message: err.Error()}
+}
+
+// connectContext returns the context of a call: that of the request, with the
+// deadline of its Connect-Timeout-Ms header.
+func connectContext(r *http.Request) (context.Context, context.CancelFunc) {
+	if ms, err := strconv.ParseInt(r.Header.Get("Connect-Timeout-Ms"), 10, 64); err == nil && ms > 0 {
+		return context.WithTimeout(r.Context(), time.Duration(ms)*time.Millisecond)
+	}
+	return context.WithCancel(r.Context())
+}
+
+// connectCheckRequest rejects requests that are not Connect POSTs of contentType.
+func connectCheckRequest(w http.ResponseWriter, r *http.Request, contentType string) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Connect calls must use POST", http.StatusMethodNotAllowed)
+		return false
+	}
+	if mediaType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";"); strings.TrimSpace(mediaType) != contentType {
+		w.Header().Set("Accept-Post", contentType)
+		http.Error(w, "unsupported content type, expected "+contentType, http.StatusUnsupportedMediaType)
+		return false
+	}
+	return true
+}
+
+// connectWriteError writes the error response of a unary call.
+func connectWriteError(w http.ResponseWriter, err error) {
+	connectErr := connectErrorOf(err)
+	status, ok := connectStatus[connectErr.Code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(connectErr)
+}
+
+// connectServeUnary decodes the request of a unary call into in, calls the
+// method with the context of the call, and writes its output or error.
+func connectServeUnary(w http.ResponseWriter, r *http.Request, in interface{}, call func(ctx context.Context) (interface{}, error)) {
+	if !connectCheckRequest(w, r, "application/json") {
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(in); err != nil && err != io.EOF {
+		connectWriteError(w, &ConnectError{Code: "invalid_argument", Message: err.Error()})
+		return
+	}
+	ctx, cancel := connectContext(r)
+	defer cancel()
+	out, err := call(ctx)
+	if err != nil {
+		connectWriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// connectServeStream decodes the single request message of a streaming call
+// into in and calls the method with the context of the call, which sends its
+// messages with send. Errors end the stream.
+func connectServeStream(w http.ResponseWriter, r *http.Request, in interface{}, call func(ctx context.Context, send func(msg interface{}) error) error) {
+	if !connectCheckRequest(w, r, "application/connect+json") {
+		return
+	}
+	ctx, cancel := connectContext(r)
+	defer cancel()
+	w.Header().Set("Content-Type", "application/connect+json")
+	_, data, err := connectReadEnvelope(r.Body)
+	if err == nil {
+		err = json.Unmarshal(data, in)
+	}
+	if err != nil {
+		err = &ConnectError{Code: "invalid_argument", Message: err.Error()}
+	} else {
+		err = call(ctx, func(msg interface{}) error {
+			return connectWriteEnvelope(w, 0, msg)
+		})
+	}
+	var end connectEndStream
+	if err != nil {
+		end.Error = connectErrorOf(err)
+	}
+	_ = connectWriteEnvelope(w, connectEndStreamFlag, end)
+}
+
+// connectReadEnvelope reads an enveloped stream message: its flags and data.
+func connectReadEnvelope(r io.Reader) (byte, []byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return 0, nil, err
+	}
+	data := make([]byte, binary.BigEndian.Uint32(prefix[1:]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+	return prefix[0], data, nil
+}
+
+// connectWriteEnvelope writes msg as an enveloped stream message and flushes it.
+func connectWriteEnvelope(w io.Writer, flags byte, msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	var prefix [5]byte
+	prefix[0] = flags
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(data)))
+	if _, err := w.Write(append(prefix[:], data...)); err != nil {
+		return err
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// connectPost sends a Connect request that ends with ctx, with an optional
+// deadline. Callers close the response body and then call the cancel function.
+func connectPost(ctx context.Context, client *http.Client, url, contentType string, timeout time.Duration, body []byte) (*http.Response, context.CancelFunc, error) {
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Connect-Protocol-Version", "1")
+	if deadline, ok := ctx.Deadline(); ok {
+		// The server gets the deadline of ctx, which may be earlier than timeout
+		ms := time.Until(deadline).Milliseconds()
+		if ms < 1 {
+			ms = 1
+		}
+		req.Header.Set("Connect-Timeout-Ms", strconv.FormatInt(ms, 10))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return resp, cancel, nil
+}
+
+// connectCallUnary calls a unary method and decodes its output into out.
+func connectCallUnary(ctx context.Context, client *http.Client, url string, timeout time.Duration, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	resp, cancel, err := connectPost(ctx, client, url, "application/json", timeout, body)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		connectErr := &ConnectError{}
+		if err := json.NewDecoder(resp.Body).Decode(connectErr); err != nil || connectErr.Code == "" {
+			return &ConnectError{Code: "unknown", Message: resp.Status}
+		}
+		return connectErr
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// connectCallStream calls a streaming method and passes each message it sends to receive.
+func connectCallStream(ctx context.Context, client *http.Client, url string, timeout time.Duration, in interface{}, receive func(data []byte) error) error {
+	var body bytes.Buffer
+	if err := connectWriteEnvelope(&body, 0, in); err != nil {
+		return err
+	}
+	resp, cancel, err := connectPost(ctx, client, url, "application/connect+json", timeout, body.Bytes())
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &ConnectError{Code: "unknown", Message: resp.Status}
+	}
+	for {
+		flags, data, err := connectReadEnvelope(resp.Body)
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+		if flags&connectEndStreamFlag != 0 {
+			var end connectEndStream
+			if err := json.Unmarshal(data, &end); err != nil {
+				return err
+			}
+			if end.Error != nil {
+				return end.Error
+			}
+			return nil
+		}
+		if err := receive(data); err != nil {
+			return err
+		}
+	}
+}
+`
+
+// Generate creates the Connect handlers and clients of the services of the given schema.
+func (g *GoConnectGenerator) Generate(schema *ast.Schema) string {
+	var sb strings.Builder
+	sb.WriteString("// Code generated by TypeMUX. DO NOT EDIT.\n\n")
+	sb.WriteString(fmt.Sprintf("package %s\n", g.goGen.packageName(schema)))
+	if len(schema.Services) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("\nimport (\n")
+	for _, imp := range []string{"bytes", "context", "encoding/binary", "encoding/json", "errors", "io", "net/http", "strconv", "strings", "time"} {
+		sb.WriteString(fmt.Sprintf("\t%q\n", imp))
+	}
+	sb.WriteString(")\n\n")
+	sb.WriteString(goConnectRuntime)
+
+	for _, service := range schema.Services {
+		sb.WriteString("\n")
+		sb.WriteString(g.generateHandler(schema, service))
+		sb.WriteString("\n")
+		sb.WriteString(g.generateClient(schema, service))
+	}
+	if g.scaffold {
+		version := schema.Version
+		if version == "" {
+			version = "dev"
+		}
+		sb.WriteString("\n// ServerVersion is the version /version reports, the schema version unless set\n")
+		sb.WriteString("// when building the server with -ldflags \"-X <package path>.ServerVersion=1.2.3\".\n")
+		sb.WriteString(fmt.Sprintf("var ServerVersion = %q\n\n", version))
+		sb.WriteString(goConnectStandardEndpoints)
+	}
+	return sb.String()
+}
+
+// isStream reports whether a method is a Connect stream rather than a unary call
+func (g *GoConnectGenerator) isStream(method *ast.Method) bool {
+	return method.InputStream || method.OutputStream
+}
+
+// messageTypes returns the Go types of the request and response messages of a method
+func (g *GoConnectGenerator) messageTypes(method *ast.Method) (input, output string) {
+	input, output = "connectEmpty", "connectEmpty"
+	if method.HasInput() {
+		input = g.goGen.cleanTypeName(method.InputType)
+	}
+	if method.HasOutput() {
+		output = g.goGen.cleanTypeName(method.OutputType)
+	}
+	return input, output
+}
+
+// generateHandler generates the HTTP handler that serves the methods of a service
+func (g *GoConnectGenerator) generateHandler(schema *ast.Schema, service *ast.Service) string {
+	var sb strings.Builder
+	pathConst := service.Name + "ConnectPath"
+
+	sb.WriteString(fmt.Sprintf("// %s is the path prefix of the Connect procedures of %s.\n", pathConst, service.Name))
+	sb.WriteString(fmt.Sprintf("const %s = %q\n\n", pathConst, "/"+rpcServiceName(schema, service)+"/"))
+
+	sb.WriteString(fmt.Sprintf("// New%sConnectHandler returns an HTTP handler that serves the methods of svc\n", service.Name))
+	sb.WriteString("// with the Connect protocol, and the path to mount it on:\n")
+	sb.WriteString("//\n")
+	sb.WriteString(fmt.Sprintf("//\tmux.Handle(New%sConnectHandler(svc))\n", service.Name))
+	sb.WriteString(fmt.Sprintf("func New%sConnectHandler(svc %s) (string, http.Handler) {\n", service.Name, service.Name))
+	sb.WriteString("\tmux := http.NewServeMux()\n")
+	for _, method := range service.Methods {
+		input, output := g.messageTypes(method)
+		args := "ctx"
+		if method.HasInput() {
+			args += ", in"
+		}
+		// Unions are decoded from raw JSON once the call has its context
+		union := g.goGen.messageUnion(schema, method.InputType)
+		target := "in"
+		if union != nil {
+			target = "raw"
+		}
+
+		sb.WriteString(fmt.Sprintf("\tmux.HandleFunc(%s+%q, func(w http.ResponseWriter, r *http.Request) {\n", pathConst, method.Name))
+		if union != nil {
+			sb.WriteString("\t\traw := new(json.RawMessage)\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("\t\tin := new(%s)\n", input))
+		}
+		switch {
+		case !g.isStream(method):
+			sb.WriteString(fmt.Sprintf("\t\tconnectServeUnary(w, r, %s, func(ctx context.Context) (interface{}, error) {\n", target))
+			sb.WriteString(g.decodeUnionInput(union, "nil, "))
+			if method.HasOutput() {
+				sb.WriteString(fmt.Sprintf("\t\t\treturn svc.%s(%s)\n", method.Name, args))
+			} else {
+				sb.WriteString(fmt.Sprintf("\t\t\treturn &connectEmpty{}, svc.%s(%s)\n", method.Name, args))
+			}
+			sb.WriteString("\t\t})\n")
+		case method.OutputStream:
+			sb.WriteString(fmt.Sprintf("\t\tconnectServeStream(w, r, %s, func(ctx context.Context, send func(msg interface{}) error) error {\n", target))
+			sb.WriteString(g.decodeUnionInput(union, ""))
+			sb.WriteString(fmt.Sprintf("\t\t\tstream := make(chan *%s)\n", output))
+			sb.WriteString("\t\t\tdone := make(chan error, 1)\n")
+			sb.WriteString("\t\t\tgo func() {\n")
+			sb.WriteString(fmt.Sprintf("\t\t\t\tdone <- svc.%s(%s, stream)\n", method.Name, args))
+			sb.WriteString("\t\t\t\tclose(stream)\n")
+			sb.WriteString("\t\t\t}()\n")
+			sb.WriteString("\t\t\t// Keep draining after a failed send so the implementation can return\n")
+			sb.WriteString("\t\t\tvar sendErr error\n")
+			sb.WriteString("\t\t\tfor msg := range stream {\n")
+			sb.WriteString("\t\t\t\tif sendErr == nil {\n\t\t\t\t\tsendErr = send(msg)\n\t\t\t\t}\n")
+			sb.WriteString("\t\t\t}\n")
+			sb.WriteString("\t\t\tif err := <-done; err != nil {\n\t\t\t\treturn err\n\t\t\t}\n")
+			sb.WriteString("\t\t\treturn sendErr\n")
+			sb.WriteString("\t\t})\n")
+		default:
+			// Client streams carry the single input of the service interface
+			sb.WriteString(fmt.Sprintf("\t\tconnectServeStream(w, r, %s, func(ctx context.Context, send func(msg interface{}) error) error {\n", target))
+			sb.WriteString(g.decodeUnionInput(union, ""))
+			if method.HasOutput() {
+				sb.WriteString(fmt.Sprintf("\t\t\tout, err := svc.%s(%s)\n", method.Name, args))
+				sb.WriteString("\t\t\tif err != nil {\n\t\t\t\treturn err\n\t\t\t}\n")
+				sb.WriteString("\t\t\treturn send(out)\n")
+			} else {
+				sb.WriteString(fmt.Sprintf("\t\t\tif err := svc.%s(%s); err != nil {\n\t\t\t\treturn err\n\t\t\t}\n", method.Name, args))
+				sb.WriteString("\t\t\treturn send(&connectEmpty{})\n")
+			}
+			sb.WriteString("\t\t})\n")
+		}
+		sb.WriteString("\t})\n")
+	}
+	sb.WriteString(fmt.Sprintf("\treturn %s, mux\n", pathConst))
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// decodeUnionInput generates the statements of a handler that decode the raw
+// JSON input of a union method into in, rejecting it as an invalid argument.
+// results precedes the error of the return statement, such as "nil, ".
+func (g *GoConnectGenerator) decodeUnionInput(union *ast.Union, results string) string {
+	if union == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\t\t\tvalue, err := Unmarshal%s(*raw)\n", union.Name))
+	sb.WriteString(fmt.Sprintf("\t\t\tif err != nil {\n\t\t\t\treturn %s&ConnectError{Code: \"invalid_argument\", Message: err.Error()}\n\t\t\t}\n", results))
+	sb.WriteString("\t\t\tin := &value\n")
+	return sb.String()
+}
+
+// generateClient generates a client that implements the service interface by
+// calling a Connect server
+func (g *GoConnectGenerator) generateClient(schema *ast.Schema, service *ast.Service) string {
+	var sb strings.Builder
+	client := uncapitalize(service.Name) + "ConnectClient"
+
+	sb.WriteString(fmt.Sprintf("// New%sConnectClient returns a %s that calls its methods on the Connect\n", service.Name, service.Name))
+	sb.WriteString("// server at baseURL, such as \"https://api.example.com\". A nil client uses\n")
+	sb.WriteString("// http.DefaultClient. Calls end with their context; methods with a declared\n")
+	sb.WriteString("// @timeout also get it as their deadline.\n")
+	sb.WriteString(fmt.Sprintf("func New%sConnectClient(client *http.Client, baseURL string) %s {\n", service.Name, service.Name))
+	sb.WriteString("\tif client == nil {\n\t\tclient = http.DefaultClient\n\t}\n")
+	sb.WriteString(fmt.Sprintf("\treturn &%s{client: client, url: strings.TrimSuffix(baseURL, \"/\") + %sConnectPath}\n}\n\n", client, service.Name))
+	sb.WriteString(fmt.Sprintf("type %s struct {\n\tclient *http.Client\n\turl    string\n}\n", client))
+
+	for _, method := range service.Methods {
+		params, _, results := g.goGen.methodSignature(method)
+		_, output := g.messageTypes(method)
+		in := "input"
+		if !method.HasInput() {
+			in = "&connectEmpty{}"
+		}
+		timeout := "0"
+		if d := method.TimeoutDuration(); d > 0 {
+			// gofmt drops the spaces around * in an argument list
+			timeout = strings.ReplaceAll(goDurationLiteral(d), " * ", "*")
+		}
+		url := fmt.Sprintf("c.url+%q", method.Name)
+
+		sb.WriteString(fmt.Sprintf("\nfunc (c *%s) %s(%s) %s {\n", client, method.Name, strings.Join(params, ", "), results))
+		union := g.goGen.messageUnion(schema, method.OutputType)
+		switch {
+		case !g.isStream(method) && method.HasOutput():
+			call := func(ptr string) string {
+				return fmt.Sprintf("connectCallUnary(ctx, c.client, %s, %s, %s, %s)", url, timeout, in, ptr)
+			}
+			sb.WriteString(g.goGen.receiveMessage(schema, method.OutputType, output, "out", "\t", call, "return nil, err"))
+			sb.WriteString("\treturn out, nil\n")
+		case !g.isStream(method):
+			sb.WriteString(fmt.Sprintf("\treturn connectCallUnary(ctx, c.client, %s, %s, %s, &connectEmpty{})\n", url, timeout, in))
+		case method.OutputStream:
+			sb.WriteString(fmt.Sprintf("\treturn connectCallStream(ctx, c.client, %s, %s, %s, func(data []byte) error {\n", url, timeout, in))
+			if union != nil {
+				sb.WriteString(fmt.Sprintf("\t\tvalue, err := Unmarshal%s(data)\n", union.Name))
+				sb.WriteString("\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n")
+				sb.WriteString("\t\tstream <- &value\n")
+			} else {
+				sb.WriteString(fmt.Sprintf("\t\tmsg := new(%s)\n", output))
+				sb.WriteString("\t\tif err := json.Unmarshal(data, msg); err != nil {\n\t\t\treturn err\n\t\t}\n")
+				sb.WriteString("\t\tstream <- msg\n")
+			}
+			sb.WriteString("\t\treturn nil\n")
+			sb.WriteString("\t})\n")
+		case method.HasOutput():
+			target := "out"
+			if union != nil {
+				target = "raw"
+				sb.WriteString("\traw := new(json.RawMessage)\n")
+			} else {
+				sb.WriteString(fmt.Sprintf("\tout := new(%s)\n", output))
+			}
+			sb.WriteString(fmt.Sprintf("\tif err := connectCallStream(ctx, c.client, %s, %s, %s, func(data []byte) error {\n", url, timeout, in))
+			sb.WriteString(fmt.Sprintf("\t\treturn json.Unmarshal(data, %s)\n", target))
+			sb.WriteString("\t}); err != nil {\n\t\treturn nil, err\n\t}\n")
+			if union != nil {
+				sb.WriteString(fmt.Sprintf("\tvalue, err := Unmarshal%s(*raw)\n", union.Name))
+				sb.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+				sb.WriteString("\treturn &value, nil\n")
+			} else {
+				sb.WriteString("\treturn out, nil\n")
+			}
+		default:
+			sb.WriteString(fmt.Sprintf("\treturn connectCallStream(ctx, c.client, %s, %s, %s, func([]byte) error {\n", url, timeout, in))
+			sb.WriteString("\t\treturn nil\n")
+			sb.WriteString("\t})\n")
+		}
+		sb.WriteString("}\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func TestGoConnectGenerator_Generate(t *testing.T) {
+	output := NewGoConnectGenerator().Generate(grpcTestSchema())
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "connect.go", output, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, output)
+	}
+
+	expected := []string{
+		"package users\n",
+		"type ConnectError struct {",
+		"const UserServiceConnectPath = \"/com.example.users.UserService/\"",
+		"func NewUserServiceConnectHandler(svc UserService) (string, http.Handler) {",
+		"mux.HandleFunc(UserServiceConnectPath+\"GetUser\", func(w http.ResponseWriter, r *http.Request) {",
+		"\t\t\treturn svc.GetUser(ctx, in)\n",
+		"\t\t\treturn &connectEmpty{}, svc.Ping(ctx)\n",
+		"\t\t\t\tdone <- svc.WatchUsers(ctx, in, stream)\n",
+		"connectServeUnary(w, r, in, func(ctx context.Context) (interface{}, error) {",
+		"func NewUserServiceConnectClient(client *http.Client, baseURL string) UserService {",
+		"func (c *userServiceConnectClient) GetUser(ctx context.Context, input *User) (*User, error) {",
+		"connectCallUnary(ctx, c.client, c.url+\"GetUser\", 5*time.Second, input, out)",
+		"return connectCallUnary(ctx, c.client, c.url+\"Ping\", 0, &connectEmpty{}, &connectEmpty{})",
+		"return connectCallStream(ctx, c.client, c.url+\"WatchUsers\", 0, input, func(data []byte) error {",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q\n%s", exp, output)
+		}
+	}
+}
+
+func TestGoConnectGenerator_Scaffold(t *testing.T) {
+	schema := grpcTestSchema()
+	schema.Version = "2.1.0"
+	output := NewGoConnectGeneratorWithOptions(&GoOptions{Scaffold: true}).Generate(schema)
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "connect.go", output, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, output)
+	}
+
+	expected := []string{
+		"var ServerVersion = \"2.1.0\"\n",
+		"func HandleStandardEndpoints(mux *http.ServeMux, checks ...ReadinessCheck) {",
+		"mux.HandleFunc(\"/healthz\",",
+		"mux.HandleFunc(\"/readyz\",",
+		"w.WriteHeader(http.StatusServiceUnavailable)",
+		"mux.HandleFunc(\"/version\",",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q\n%s", exp, output)
+		}
+	}
+
+	if plain := NewGoConnectGenerator().Generate(grpcTestSchema()); strings.Contains(plain, "HandleStandardEndpoints") {
+		t.Errorf("Expected no standard endpoints without Scaffold")
+	}
+}
+
+func TestGoConnectGenerator_NoServices(t *testing.T) {
+	output := NewGoConnectGenerator().Generate(generatorTestSchema("api"))
+	if output != "// Code generated by TypeMUX. DO NOT EDIT.\n\npackage api\n" {
+		t.Errorf("Expected only a package clause, got:\n%s", output)
+	}
+}
+
+func TestGoConnectGenerator_UnionMessages(t *testing.T) {
+	schema := grpcTestSchema()
+	schema.Unions = []*ast.Union{{Name: "Shape", Namespace: "com.example.users", Options: []string{"User"}}}
+	schema.Services[0].Methods = []*ast.Method{
+		{Name: "Echo", InputType: "Shape", OutputType: "Shape"},
+		{Name: "WatchShapes", InputType: "User", OutputType: "Shape", OutputStream: true},
+	}
+	output := NewGoConnectGenerator().Generate(schema)
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "connect.go", output, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, output)
+	}
+
+	expected := []string{
+		"\t\traw := new(json.RawMessage)\n\t\tconnectServeUnary(w, r, raw, func(ctx context.Context) (interface{}, error) {\n" +
+			"\t\t\tvalue, err := UnmarshalShape(*raw)\n" +
+			"\t\t\tif err != nil {\n\t\t\t\treturn nil, &ConnectError{Code: \"invalid_argument\", Message: err.Error()}\n\t\t\t}\n" +
+			"\t\t\tin := &value\n\t\t\treturn svc.Echo(ctx, in)\n",
+		"\tif err := connectCallUnary(ctx, c.client, c.url+\"Echo\", 0, input, raw); err != nil {\n",
+		"\t\tvalue, err := UnmarshalShape(data)\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n\t\tstream <- &value\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q\n%s", exp, output)
+		}
+	}
+	if strings.Contains(output, "new(Shape)") {
+		t.Errorf("Expected no decoding into a pointer to the Shape interface\n%s", output)
+	}
+}
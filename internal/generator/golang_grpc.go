@@ -0,0 +1,364 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// GoGRPCGenerator generates gRPC servers and clients for the Go service
+// interfaces of GoGenerator, without protoc. Messages are the structs of the Go
+// output encoded as JSON by a gRPC codec, so the file belongs in the same package
+// as types.go and needs only google.golang.org/grpc.
+type GoGRPCGenerator struct {
+	goGen    *GoGenerator
+	scaffold bool
+}
+
+// NewGoGRPCGenerator creates a new Go gRPC stub generator.
+func NewGoGRPCGenerator() *GoGRPCGenerator {
+	return &GoGRPCGenerator{goGen: NewGoGenerator()}
+}
+
+// NewGoGRPCGeneratorWithOptions creates a new Go gRPC stub generator that reads
+// the Scaffold option; nil options select the defaults.
+func NewGoGRPCGeneratorWithOptions(opts *GoOptions) *GoGRPCGenerator {
+	g := NewGoGRPCGenerator()
+	if opts != nil {
+		g.scaffold = opts.Scaffold
+	}
+	return g
+}
+
+// goGRPCCodec is the JSON codec of the generated stubs and the message of methods
+// without input or output
+const goGRPCCodec = `// grpcJSONCodec encodes gRPC messages as JSON, so the types of this package can
+// be sent without code generated by protoc. Stub calls select it with the "json"
+// content subtype.
+type grpcJSONCodec struct{}
+
+func (grpcJSONCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (grpcJSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (grpcJSONCodec) Name() string                               { return "json" }
+
+func init() {
+	encoding.RegisterCodec(grpcJSONCodec{})
+}
+
+// grpcEmpty is the message of methods without input or output.
+type grpcEmpty struct{}
+`
+
+// Generate creates the gRPC stubs of the services of the given schema.
+func (g *GoGRPCGenerator) Generate(schema *ast.Schema) string {
+	var sb strings.Builder
+	sb.WriteString("// Code generated by TypeMUX. DO NOT EDIT.\n\n")
+	sb.WriteString(fmt.Sprintf("package %s\n", g.goGen.packageName(schema)))
+	if len(schema.Services) == 0 {
+		return sb.String()
+	}
+
+	imports := map[string]bool{"context": true, "encoding/json": true}
+	for _, service := range schema.Services {
+		for _, method := range service.Methods {
+			if method.OutputStream {
+				imports["io"] = true
+			}
+			if method.TimeoutDuration() > 0 {
+				imports["time"] = true
+			}
+		}
+	}
+	stdlib := make([]string, 0, len(imports))
+	for imp := range imports {
+		stdlib = append(stdlib, imp)
+	}
+	sort.Strings(stdlib)
+	sb.WriteString("\nimport (\n")
+	for _, imp := range stdlib {
+		sb.WriteString(fmt.Sprintf("\t%q\n", imp))
+	}
+	sb.WriteString("\n\t\"google.golang.org/grpc\"\n\t\"google.golang.org/grpc/encoding\"\n")
+	if g.scaffold {
+		sb.WriteString("\t\"google.golang.org/grpc/health\"\n\thealthpb \"google.golang.org/grpc/health/grpc_health_v1\"\n\t\"google.golang.org/grpc/reflection\"\n")
+	}
+	sb.WriteString(")\n\n")
+	sb.WriteString(goGRPCCodec)
+
+	for _, service := range schema.Services {
+		sb.WriteString("\n")
+		sb.WriteString(g.generateServer(schema, service))
+		sb.WriteString("\n")
+		sb.WriteString(g.generateClient(schema, service))
+	}
+	if g.scaffold {
+		sb.WriteString("\n")
+		sb.WriteString(g.generateStandardServices(schema))
+	}
+	return sb.String()
+}
+
+// generateStandardServices generates RegisterStandardServices, which registers
+// the health service, reporting every service of the schema as serving, and
+// server reflection
+func (g *GoGRPCGenerator) generateStandardServices(schema *ast.Schema) string {
+	var sb strings.Builder
+	sb.WriteString("// RegisterStandardServices registers the grpc.health.v1 health service and\n")
+	sb.WriteString("// server reflection with a gRPC server. Every service of this file starts out\n")
+	sb.WriteString("// SERVING; the returned health server changes their status, such as while the\n")
+	sb.WriteString("// server drains. Reflection lists the services, but cannot describe their\n")
+	sb.WriteString("// messages, which are JSON rather than Protobuf.\n")
+	sb.WriteString("func RegisterStandardServices(s *grpc.Server) *health.Server {\n")
+	sb.WriteString("\thealthServer := health.NewServer()\n")
+	sb.WriteString("\tfor _, service := range []string{\n")
+	for _, service := range schema.Services {
+		sb.WriteString(fmt.Sprintf("\t\t%q,\n", rpcServiceName(schema, service)))
+	}
+	sb.WriteString("\t} {\n")
+	sb.WriteString("\t\thealthServer.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("\thealthpb.RegisterHealthServer(s, healthServer)\n")
+	sb.WriteString("\treflection.Register(s)\n")
+	sb.WriteString("\treturn healthServer\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// rpcServiceName returns the full RPC name of a service, as in the Protobuf output
+func rpcServiceName(schema *ast.Schema, service *ast.Service) string {
+	namespace := service.Namespace
+	if namespace == "" {
+		namespace = schema.Namespace
+	}
+	if namespace == "" {
+		namespace = "api"
+	}
+	return namespace + "." + service.Name
+}
+
+// isStream reports whether a method is a gRPC stream rather than a unary call
+func (g *GoGRPCGenerator) isStream(method *ast.Method) bool {
+	return method.InputStream || method.OutputStream
+}
+
+// messageTypes returns the Go types of the request and response messages of a method
+func (g *GoGRPCGenerator) messageTypes(method *ast.Method) (input, output string) {
+	input, output = "grpcEmpty", "grpcEmpty"
+	if method.HasInput() {
+		input = g.goGen.cleanTypeName(method.InputType)
+	}
+	if method.HasOutput() {
+		output = g.goGen.cleanTypeName(method.OutputType)
+	}
+	return input, output
+}
+
+// generateServer generates the service descriptor, the registration function,
+// and the method handlers of a service
+func (g *GoGRPCGenerator) generateServer(schema *ast.Schema, service *ast.Service) string {
+	var sb strings.Builder
+	desc := uncapitalize(service.Name) + "Desc"
+	fullName := rpcServiceName(schema, service)
+
+	sb.WriteString(fmt.Sprintf("// Register%sServer registers an implementation of %s with a gRPC server.\n", service.Name, service.Name))
+	sb.WriteString(fmt.Sprintf("func Register%sServer(s grpc.ServiceRegistrar, srv %s) {\n", service.Name, service.Name))
+	sb.WriteString(fmt.Sprintf("\ts.RegisterService(&%s, srv)\n}\n\n", desc))
+
+	sb.WriteString(fmt.Sprintf("var %s = grpc.ServiceDesc{\n", desc))
+	sb.WriteString(fmt.Sprintf("\tServiceName: %q,\n", fullName))
+	sb.WriteString(fmt.Sprintf("\tHandlerType: (*%s)(nil),\n", service.Name))
+	sb.WriteString("\tMethods: []grpc.MethodDesc{\n")
+	for _, method := range service.Methods {
+		if !g.isStream(method) {
+			sb.WriteString(fmt.Sprintf("\t\t{MethodName: %q, Handler: %s},\n", method.Name, g.handlerName(service, method)))
+		}
+	}
+	sb.WriteString("\t},\n")
+	sb.WriteString("\tStreams: []grpc.StreamDesc{\n")
+	for _, method := range service.Methods {
+		if g.isStream(method) {
+			sb.WriteString(fmt.Sprintf("\t\t{StreamName: %q, Handler: %s, ServerStreams: %t, ClientStreams: %t},\n",
+				method.Name, g.handlerName(service, method), method.OutputStream, method.InputStream))
+		}
+	}
+	sb.WriteString("\t},\n")
+	sb.WriteString("}\n")
+
+	for _, method := range service.Methods {
+		sb.WriteString("\n")
+		if g.isStream(method) {
+			sb.WriteString(g.generateStreamHandler(schema, service, method))
+		} else {
+			sb.WriteString(g.generateUnaryHandler(schema, fullName, service, method))
+		}
+	}
+	return sb.String()
+}
+
+// handlerName returns the name of the server handler of a method
+func (g *GoGRPCGenerator) handlerName(service *ast.Service, method *ast.Method) string {
+	return uncapitalize(service.Name) + method.Name + "Handler"
+}
+
+// callArgs returns the arguments that pass the context of the call and the
+// request message in to a method of the service interface
+func (g *GoGRPCGenerator) callArgs(method *ast.Method, ctx, in string) string {
+	if method.HasInput() {
+		return ctx + ", " + in
+	}
+	return ctx
+}
+
+// generateUnaryHandler generates the handler of a unary method, which passes the
+// context of the call on to the service
+func (g *GoGRPCGenerator) generateUnaryHandler(schema *ast.Schema, fullName string, service *ast.Service, method *ast.Method) string {
+	var sb strings.Builder
+	input, _ := g.messageTypes(method)
+
+	sb.WriteString(fmt.Sprintf("func %s(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {\n", g.handlerName(service, method)))
+	sb.WriteString(g.goGen.receiveMessage(schema, method.InputType, input, "in", "\t", func(ptr string) string { return "dec(" + ptr + ")" }, "return nil, err"))
+	sb.WriteString("\thandler := func(ctx context.Context, req interface{}) (interface{}, error) {\n")
+	call := fmt.Sprintf("srv.(%s).%s(%s)", service.Name, method.Name, g.callArgs(method, "ctx", fmt.Sprintf("req.(*%s)", input)))
+	if method.HasOutput() {
+		sb.WriteString(fmt.Sprintf("\t\treturn %s\n", call))
+	} else {
+		sb.WriteString(fmt.Sprintf("\t\treturn &grpcEmpty{}, %s\n", call))
+	}
+	sb.WriteString("\t}\n")
+	sb.WriteString("\tif interceptor == nil {\n\t\treturn handler(ctx, in)\n\t}\n")
+	sb.WriteString(fmt.Sprintf("\tinfo := &grpc.UnaryServerInfo{Server: srv, FullMethod: %q}\n", "/"+fullName+"/"+method.Name))
+	sb.WriteString("\treturn interceptor(ctx, in, info, handler)\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// generateStreamHandler generates the handler of a streaming method. The service
+// interface takes a single input, so client streams carry one message; server
+// streams send every message the implementation writes to its channel, which the
+// handler closes once the method returns. The service gets the context of the
+// stream.
+func (g *GoGRPCGenerator) generateStreamHandler(schema *ast.Schema, service *ast.Service, method *ast.Method) string {
+	var sb strings.Builder
+	input, output := g.messageTypes(method)
+
+	sb.WriteString(fmt.Sprintf("func %s(srv interface{}, stream grpc.ServerStream) error {\n", g.handlerName(service, method)))
+	sb.WriteString(g.goGen.receiveMessage(schema, method.InputType, input, "in", "\t", func(ptr string) string { return "stream.RecvMsg(" + ptr + ")" }, "return err"))
+
+	if !method.OutputStream {
+		call := fmt.Sprintf("srv.(%s).%s(%s)", service.Name, method.Name, g.callArgs(method, "stream.Context()", "in"))
+		if method.HasOutput() {
+			sb.WriteString(fmt.Sprintf("\tout, err := %s\n", call))
+			sb.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n")
+			sb.WriteString("\treturn stream.SendMsg(out)\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("\tif err := %s; err != nil {\n\t\treturn err\n\t}\n", call))
+			sb.WriteString("\treturn stream.SendMsg(&grpcEmpty{})\n")
+		}
+		sb.WriteString("}\n")
+		return sb.String()
+	}
+
+	args := g.callArgs(method, "stream.Context()", "in") + ", out"
+	sb.WriteString(fmt.Sprintf("\tout := make(chan *%s)\n", output))
+	sb.WriteString("\tdone := make(chan error, 1)\n")
+	sb.WriteString("\tgo func() {\n")
+	sb.WriteString(fmt.Sprintf("\t\tdone <- srv.(%s).%s(%s)\n", service.Name, method.Name, args))
+	sb.WriteString("\t\tclose(out)\n")
+	sb.WriteString("\t}()\n")
+	sb.WriteString("\t// Keep draining after a failed send so the implementation can return\n")
+	sb.WriteString("\tvar sendErr error\n")
+	sb.WriteString("\tfor msg := range out {\n")
+	sb.WriteString("\t\tif sendErr == nil {\n\t\t\tsendErr = stream.SendMsg(msg)\n\t\t}\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("\tif err := <-done; err != nil {\n\t\treturn err\n\t}\n")
+	sb.WriteString("\treturn sendErr\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// generateClient generates a client that implements the service interface over a
+// gRPC connection
+func (g *GoGRPCGenerator) generateClient(schema *ast.Schema, service *ast.Service) string {
+	var sb strings.Builder
+	client := uncapitalize(service.Name) + "Client"
+	desc := uncapitalize(service.Name) + "Desc"
+	fullName := rpcServiceName(schema, service)
+
+	sb.WriteString(fmt.Sprintf("// New%sClient returns a %s that calls its methods over a gRPC connection.\n", service.Name, service.Name))
+	sb.WriteString("// Calls end with their context; methods with a declared @timeout also get it as\n")
+	sb.WriteString("// their deadline.\n")
+	sb.WriteString(fmt.Sprintf("func New%sClient(cc grpc.ClientConnInterface, opts ...grpc.CallOption) %s {\n", service.Name, service.Name))
+	sb.WriteString(fmt.Sprintf("\treturn &%s{cc: cc, opts: append([]grpc.CallOption{grpc.CallContentSubtype(\"json\")}, opts...)}\n}\n\n", client))
+	sb.WriteString(fmt.Sprintf("type %s struct {\n\tcc   grpc.ClientConnInterface\n\topts []grpc.CallOption\n}\n", client))
+
+	streams := 0
+	for _, method := range service.Methods {
+		params, _, results := g.goGen.methodSignature(method)
+		_, output := g.messageTypes(method)
+		path := "/" + fullName + "/" + method.Name
+		in := "input"
+		if !method.HasInput() {
+			in = "&grpcEmpty{}"
+		}
+		failure := "return err"
+		if method.HasOutput() && !method.OutputStream {
+			failure = "return nil, err"
+		}
+
+		sb.WriteString(fmt.Sprintf("\nfunc (c *%s) %s(%s) %s {\n", client, method.Name, strings.Join(params, ", "), results))
+		if timeout := method.TimeoutDuration(); timeout > 0 {
+			// gofmt drops the spaces around * in an argument list
+			deadline := strings.ReplaceAll(goDurationLiteral(timeout), " * ", "*")
+			sb.WriteString(fmt.Sprintf("\tctx, cancel := context.WithTimeout(ctx, %s)\n", deadline))
+			sb.WriteString("\tdefer cancel()\n")
+		}
+
+		if !g.isStream(method) {
+			invoke := func(ptr string) string {
+				return fmt.Sprintf("c.cc.Invoke(ctx, %q, %s, %s, c.opts...)", path, in, ptr)
+			}
+			if method.HasOutput() {
+				sb.WriteString(g.goGen.receiveMessage(schema, method.OutputType, output, "out", "\t", invoke, failure))
+				sb.WriteString("\treturn out, nil\n}\n")
+			} else {
+				sb.WriteString(fmt.Sprintf("\tif err := %s; err != nil {\n\t\t%s\n\t}\n", invoke("new(grpcEmpty)"), failure))
+				sb.WriteString("\treturn nil\n}\n")
+			}
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("\ts, err := c.cc.NewStream(ctx, &%s.Streams[%d], %q, c.opts...)\n", desc, streams, path))
+		sb.WriteString(fmt.Sprintf("\tif err != nil {\n\t\t%s\n\t}\n", failure))
+		sb.WriteString(fmt.Sprintf("\tif err := s.SendMsg(%s); err != nil {\n\t\t%s\n\t}\n", in, failure))
+		sb.WriteString(fmt.Sprintf("\tif err := s.CloseSend(); err != nil {\n\t\t%s\n\t}\n", failure))
+		streams++
+
+		if method.OutputStream {
+			sb.WriteString("\tfor {\n")
+			if union := g.goGen.messageUnion(schema, method.OutputType); union != nil {
+				sb.WriteString("\t\traw := new(json.RawMessage)\n")
+				sb.WriteString("\t\tif err := s.RecvMsg(raw); err == io.EOF {\n\t\t\treturn nil\n\t\t} else if err != nil {\n\t\t\treturn err\n\t\t}\n")
+				sb.WriteString(fmt.Sprintf("\t\tvalue, err := Unmarshal%s(*raw)\n", union.Name))
+				sb.WriteString("\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n")
+				sb.WriteString("\t\tstream <- &value\n")
+			} else {
+				sb.WriteString(fmt.Sprintf("\t\tmsg := new(%s)\n", output))
+				sb.WriteString("\t\tif err := s.RecvMsg(msg); err == io.EOF {\n\t\t\treturn nil\n\t\t} else if err != nil {\n\t\t\treturn err\n\t\t}\n")
+				sb.WriteString("\t\tstream <- msg\n")
+			}
+			sb.WriteString("\t}\n}\n")
+			continue
+		}
+		recv := func(ptr string) string { return "s.RecvMsg(" + ptr + ")" }
+		if method.HasOutput() {
+			sb.WriteString(g.goGen.receiveMessage(schema, method.OutputType, output, "out", "\t", recv, failure))
+			sb.WriteString("\treturn out, nil\n}\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("\tif err := %s; err != nil {\n\t\t%s\n\t}\n", recv("new(grpcEmpty)"), failure))
+			sb.WriteString("\treturn nil\n}\n")
+		}
+	}
+	return sb.String()
+}
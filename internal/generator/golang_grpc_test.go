@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// grpcTestSchema returns a UserService with a unary, an empty, and a streaming method
+func grpcTestSchema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "com.example.users",
+		Types: []*ast.Type{
+			{Name: "User", Namespace: "com.example.users", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+		},
+		Services: []*ast.Service{{
+			Name:      "UserService",
+			Namespace: "com.example.users",
+			Methods: []*ast.Method{
+				{Name: "GetUser", InputType: "User", OutputType: "User", Timeout: "5s"},
+				{Name: "Ping"},
+				{Name: "WatchUsers", InputType: "User", OutputType: "User", OutputStream: true},
+			},
+		}},
+	}
+}
+
+func TestGoGRPCGenerator_Generate(t *testing.T) {
+	output := NewGoGRPCGenerator().Generate(grpcTestSchema())
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "grpc.go", output, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, output)
+	}
+
+	expected := []string{
+		"package users\n",
+		"\"google.golang.org/grpc/encoding\"",
+		"encoding.RegisterCodec(grpcJSONCodec{})",
+		"func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserService) {",
+		"ServiceName: \"com.example.users.UserService\",",
+		"{MethodName: \"GetUser\", Handler: userServiceGetUserHandler},",
+		"{MethodName: \"Ping\", Handler: userServicePingHandler},",
+		"{StreamName: \"WatchUsers\", Handler: userServiceWatchUsersHandler, ServerStreams: true, ClientStreams: false},",
+		"return srv.(UserService).GetUser(ctx, req.(*User))",
+		"return &grpcEmpty{}, srv.(UserService).Ping(ctx)",
+		"done <- srv.(UserService).WatchUsers(stream.Context(), in, out)",
+		"func NewUserServiceClient(cc grpc.ClientConnInterface, opts ...grpc.CallOption) UserService {",
+		"grpc.CallContentSubtype(\"json\")",
+		"func (c *userServiceClient) GetUser(ctx context.Context, input *User) (*User, error) {\n\tctx, cancel := context.WithTimeout(ctx, 5*time.Second)",
+		"c.cc.Invoke(ctx, \"/com.example.users.UserService/GetUser\", input, out, c.opts...)",
+		"c.cc.Invoke(ctx, \"/com.example.users.UserService/Ping\", &grpcEmpty{}, new(grpcEmpty), c.opts...)",
+		"c.cc.NewStream(ctx, &userServiceDesc.Streams[0], \"/com.example.users.UserService/WatchUsers\", c.opts...)",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q\n%s", exp, output)
+		}
+	}
+}
+
+func TestGoGRPCGenerator_Scaffold(t *testing.T) {
+	output := NewGoGRPCGeneratorWithOptions(&GoOptions{Scaffold: true}).Generate(grpcTestSchema())
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "grpc.go", output, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, output)
+	}
+
+	expected := []string{
+		"\thealthpb \"google.golang.org/grpc/health/grpc_health_v1\"\n",
+		"\t\"google.golang.org/grpc/reflection\"\n",
+		"func RegisterStandardServices(s *grpc.Server) *health.Server {",
+		"\t\t\"com.example.users.UserService\",\n",
+		"healthpb.RegisterHealthServer(s, healthServer)",
+		"reflection.Register(s)",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q\n%s", exp, output)
+		}
+	}
+
+	if plain := NewGoGRPCGenerator().Generate(grpcTestSchema()); strings.Contains(plain, "health") {
+		t.Errorf("Expected no standard services without Scaffold")
+	}
+}
+
+func TestGoGRPCGenerator_NoServices(t *testing.T) {
+	output := NewGoGRPCGenerator().Generate(generatorTestSchema("api"))
+	if output != "// Code generated by TypeMUX. DO NOT EDIT.\n\npackage api\n" {
+		t.Errorf("Expected only a package clause, got:\n%s", output)
+	}
+}
+
+func TestGoGRPCGenerator_UnionMessages(t *testing.T) {
+	schema := grpcTestSchema()
+	schema.Unions = []*ast.Union{{Name: "Shape", Namespace: "com.example.users", Options: []string{"User"}}}
+	schema.Services[0].Methods = []*ast.Method{
+		{Name: "Echo", InputType: "Shape", OutputType: "Shape"},
+		{Name: "WatchShapes", InputType: "User", OutputType: "Shape", OutputStream: true},
+	}
+	output := NewGoGRPCGenerator().Generate(schema)
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "grpc.go", output, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, output)
+	}
+
+	expected := []string{
+		"\traw := new(json.RawMessage)\n\tif err := dec(raw); err != nil {\n\t\treturn nil, err\n\t}\n" +
+			"\tvalue, err := UnmarshalShape(*raw)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\tin := &value\n",
+		"return srv.(UserService).Echo(ctx, req.(*Shape))",
+		"\tif err := c.cc.Invoke(ctx, \"/com.example.users.UserService/Echo\", input, raw, c.opts...); err != nil {\n",
+		"\t\tvalue, err := UnmarshalShape(*raw)\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n\t\tstream <- &value\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q\n%s", exp, output)
+		}
+	}
+	if strings.Contains(output, "new(Shape)") {
+		t.Errorf("Expected no decoding into a pointer to the Shape interface\n%s", output)
+	}
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// quotedInt64 reports whether a scalar field type is a 64-bit integer encoded
+// as a JSON string, which the ,string tag option takes care of
+func (g *GoGenerator) quotedInt64(fieldType *ast.FieldType) bool {
+	return g.opts.Int64.quoted() && !fieldType.IsArray && !fieldType.IsMap &&
+		is64Bit(fieldType.Name) && !g.isExternal(fieldType.Name)
+}
+
+// int64ElementType returns the generated type of list items and map values of
+// a 64-bit integer type encoded as a JSON string, which the ,string tag option
+// does not reach, or "" for other types
+func (g *GoGenerator) int64ElementType(fieldType *ast.FieldType) string {
+	if !g.quotedInt64(fieldType) {
+		return ""
+	}
+	name := "StringInt64"
+	if fieldType.Name == "uint64" {
+		name = "StringUint64"
+	}
+	g.int64Helpers[name] = true
+	return name
+}
+
+// toProtoElement converts a list item or map value to its protobuf representation
+func (g *GoGenerator) toProtoElement(kind protoKind, typeName, expr string) string {
+	if kind == protoScalar && g.int64ElementType(&ast.FieldType{Name: typeName}) != "" {
+		return fmt.Sprintf("%s(%s)", protoScalarTypes[typeName], expr)
+	}
+	return g.toProtoValue(kind, typeName, expr)
+}
+
+// fromProtoElement converts a protobuf list item or map value to its Go representation
+func (g *GoGenerator) fromProtoElement(kind protoKind, typeName, expr string) string {
+	if elem := g.int64ElementType(&ast.FieldType{Name: typeName}); kind == protoScalar && elem != "" {
+		return fmt.Sprintf("%s(%s)", elem, expr)
+	}
+	return g.fromProtoValue(kind, typeName, expr)
+}
+
+// goInt64Helper is the template of the StringInt64 and StringUint64 types:
+// the type name, Go type, format expression, and parse expression
+const goInt64Helper = `// %[1]s encodes %[2]s values as JSON strings, as in proto3 JSON, so that
+// clients reading JSON numbers as doubles do not lose precision above 2^53.
+// It also decodes JSON numbers.
+type %[1]s %[2]s
+
+// MarshalJSON encodes the value as a string of digits.
+func (v %[1]s) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(%[3]s)), nil
+}
+
+// UnmarshalJSON decodes a string of digits or a number; null leaves the value unchanged.
+func (v *%[1]s) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	n, err := %[4]s
+	if err != nil {
+		return err
+	}
+	*v = %[1]s(n)
+	return nil
+}
+`
+
+// generateInt64Helpers generates the StringInt64 and StringUint64 types of
+// lists and maps, if used
+func (g *GoGenerator) generateInt64Helpers() string {
+	if len(g.int64Helpers) == 0 {
+		return ""
+	}
+	g.imports["strconv"] = true
+
+	names := make([]string, 0, len(g.int64Helpers))
+	for name := range g.int64Helpers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var helpers []string
+	for _, name := range names {
+		if name == "StringUint64" {
+			helpers = append(helpers, fmt.Sprintf(goInt64Helper, name, "uint64",
+				"strconv.FormatUint(uint64(v), 10)", "strconv.ParseUint(s, 10, 64)"))
+		} else {
+			helpers = append(helpers, fmt.Sprintf(goInt64Helper, name, "int64",
+				"strconv.FormatInt(int64(v), 10)", "strconv.ParseInt(s, 10, 64)"))
+		}
+	}
+	return strings.Join(helpers, "\n")
+}
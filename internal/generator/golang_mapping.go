@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"strings"
+)
+
+// GoTypeMapper maps TypeMUX types to Go types defined outside the generated code,
+// such as domain types or third-party types.
+type GoTypeMapper interface {
+	// GoType returns the Go type of a TypeMUX builtin or declared type as an import
+	// path and type name, such as "github.com/google/uuid.UUID", or false to keep
+	// the generated type. Types of the universe scope, such as "int64", have no
+	// import path.
+	GoType(typeName string) (string, bool)
+}
+
+// GoTypeMap is a GoTypeMapper that maps type names with a map, as set by the
+// generators.go.types setting of the config file.
+type GoTypeMap map[string]string
+
+// GoType returns the Go type the map holds for a type name.
+func (m GoTypeMap) GoType(typeName string) (string, bool) {
+	goType, ok := m[typeName]
+	return goType, ok && goType != ""
+}
+
+// externalType returns the Go type the TypeMapper option maps a type to, as
+// written in the generated code, and imports its package
+func (g *GoGenerator) externalType(typeName string) (string, bool) {
+	if g.opts.TypeMapper == nil || typeName == "" {
+		return "", false
+	}
+	qualified, ok := g.opts.TypeMapper.GoType(typeName)
+	if !ok {
+		qualified, ok = g.opts.TypeMapper.GoType(g.cleanTypeName(typeName))
+	}
+	if !ok {
+		return "", false
+	}
+
+	dot := strings.LastIndex(qualified, ".")
+	if dot < 0 || dot < strings.LastIndex(qualified, "/") {
+		return qualified, true
+	}
+	path, name := qualified[:dot], qualified[dot+1:]
+	pkg := goImportName(path)
+	if g.imports != nil {
+		g.imports[path] = true
+		if pkg != path[strings.LastIndex(path, "/")+1:] {
+			g.importNames[path] = pkg
+		}
+	}
+	return pkg + "." + name, true
+}
+
+// isExternal reports whether the TypeMapper option maps a type to an external Go type
+func (g *GoGenerator) isExternal(typeName string) bool {
+	if g.opts.TypeMapper == nil || typeName == "" {
+		return false
+	}
+	if _, ok := g.opts.TypeMapper.GoType(typeName); ok {
+		return true
+	}
+	_, ok := g.opts.TypeMapper.GoType(g.cleanTypeName(typeName))
+	return ok
+}
+
+// goImportName returns the package name Go code refers to an import path by: its
+// last element, without a major version element or suffix and a go- prefix, such
+// as decimal for github.com/shopspring/decimal and yaml for gopkg.in/yaml.v3
+func goImportName(path string) string {
+	elements := strings.Split(path, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && isMajorVersion(name) {
+		name = elements[len(elements)-2]
+	}
+	if i := strings.LastIndex(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// isMajorVersion reports whether an import path element is a major version, like v2
+func isMajorVersion(element string) bool {
+	if len(element) < 2 || element[0] != 'v' {
+		return false
+	}
+	for _, r := range element[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// DefaultGoModuleVersion is the go directive of generated go.mod files.
+const DefaultGoModuleVersion = "1.21"
+
+// generateGoFiles generates types.go, or a Go module when the Module option is
+// set: go.mod, and a package with doc.go and types.go at the module root for a
+// schema with one namespace, or in a directory per namespace for a schema with
+// several, such as users/ for com.example.users
+func generateGoFiles(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	goOpts := optionsOf(opts.Go)
+	if goOpts.Metadata == nil {
+		goOpts.Metadata = opts.Metadata
+	}
+	if goOpts.Int64 == "" {
+		goOpts.Int64 = opts.Int64
+	}
+	if goOpts.Module == "" {
+		return map[string][]byte{"types.go": []byte(NewGoGeneratorWithOptions(&goOpts).Generate(schema))}, nil
+	}
+	if err := checkGoModulePath(goOpts.Module); err != nil {
+		return nil, err
+	}
+
+	version := goOpts.GoVersion
+	if version == "" {
+		version = DefaultGoModuleVersion
+	}
+	files := map[string][]byte{
+		"go.mod": []byte(fmt.Sprintf("module %s\n\ngo %s\n", goOpts.Module, version)),
+	}
+
+	namespaces := collectNamespaces(schema)
+	sort.Strings(namespaces)
+	if len(namespaces) <= 1 {
+		gen := NewGoGeneratorWithOptions(&goOpts)
+		files["doc.go"] = []byte(goPackageDoc(gen.packageName(schema), protoNamespace(schema.Namespace), schema.Version))
+		files["types.go"] = []byte(gen.Generate(schema))
+		return files, nil
+	}
+
+	registry := declarationRegistry(schema)
+	schemas := namespaceSchemas(schema)
+	dirs := goPackageDirs(schemas)
+	for _, namespace := range namespaces {
+		nsOpts := goOpts
+		nsOpts.TypeMapper = goModuleMapper{
+			namespace: namespace,
+			module:    goOpts.Module,
+			dirs:      dirs,
+			registry:  registry,
+			next:      goOpts.TypeMapper,
+		}
+		gen := NewGoGeneratorWithOptions(&nsOpts)
+		nsSchema := schemas[namespace]
+		files[dirs[namespace]+"/doc.go"] = []byte(goPackageDoc(gen.packageName(nsSchema), namespace, schema.Version))
+		files[dirs[namespace]+"/types.go"] = []byte(gen.Generate(nsSchema))
+	}
+	return files, nil
+}
+
+// checkGoModulePath reports a module path go.mod cannot hold
+func checkGoModulePath(path string) error {
+	if strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") || strings.ContainsAny(path, " \t\n\"'`\\") {
+		return fmt.Errorf("invalid Go module path %q", path)
+	}
+	return nil
+}
+
+// goPackageDoc returns the doc.go of the package of a namespace
+func goPackageDoc(packageName, namespace, version string) string {
+	var sb strings.Builder
+	sb.WriteString("// Code generated by TypeMUX. DO NOT EDIT.\n\n")
+	sb.WriteString(fmt.Sprintf("// Package %s holds the types of the %s namespace", packageName, namespace))
+	if version != "" {
+		sb.WriteString(fmt.Sprintf(", version %s of the schema", version))
+	}
+	sb.WriteString(".\n")
+	sb.WriteString(fmt.Sprintf("package %s\n", packageName))
+	return sb.String()
+}
+
+// namespaceSchemas splits a schema into a schema per namespace, with the
+// namespace annotations of each
+func namespaceSchemas(schema *ast.Schema) map[string]*ast.Schema {
+	schemas := make(map[string]*ast.Schema)
+	schemaOf := func(namespace string) *ast.Schema {
+		namespace = protoNamespace(namespace)
+		if schemas[namespace] == nil {
+			schemas[namespace] = &ast.Schema{
+				Namespace:            namespace,
+				Version:              schema.Version,
+				NamespaceAnnotations: schema.GetNamespaceAnnotations(namespace),
+			}
+		}
+		return schemas[namespace]
+	}
+
+	for _, enum := range schema.Enums {
+		nsSchema := schemaOf(enum.Namespace)
+		nsSchema.Enums = append(nsSchema.Enums, enum)
+	}
+	for _, typ := range schema.Types {
+		nsSchema := schemaOf(typ.Namespace)
+		nsSchema.Types = append(nsSchema.Types, typ)
+	}
+	for _, union := range schema.Unions {
+		nsSchema := schemaOf(union.Namespace)
+		nsSchema.Unions = append(nsSchema.Unions, union)
+	}
+	for _, service := range schema.Services {
+		nsSchema := schemaOf(service.Namespace)
+		nsSchema.Services = append(nsSchema.Services, service)
+	}
+	return schemas
+}
+
+// goPackageDirs returns the directory of the package of every namespace: its
+// package name, or the namespace as a path when several namespaces share a
+// package name, such as com/example/users
+func goPackageDirs(schemas map[string]*ast.Schema) map[string]string {
+	gen := NewGoGenerator()
+	names := make(map[string]string, len(schemas))
+	shared := make(map[string]int)
+	for namespace, nsSchema := range schemas {
+		names[namespace] = gen.packageName(nsSchema)
+		shared[names[namespace]]++
+	}
+
+	dirs := make(map[string]string, len(schemas))
+	for namespace, name := range names {
+		if shared[name] > 1 {
+			dirs[namespace] = strings.ReplaceAll(namespace, ".", "/")
+		} else {
+			dirs[namespace] = name
+		}
+	}
+	return dirs
+}
+
+// goModuleMapper is the GoTypeMapper of the package of a namespace in a Go
+// module: it maps the declarations of other namespaces to the types of their
+// packages, and other types with the mapper of the options
+type goModuleMapper struct {
+	namespace string
+	module    string
+	dirs      map[string]string
+	registry  *ast.TypeRegistry
+	next      GoTypeMapper
+}
+
+// GoType returns the Go type of a type name used in the namespace of the mapper.
+func (m goModuleMapper) GoType(typeName string) (string, bool) {
+	if m.next != nil {
+		if goType, ok := m.next.GoType(typeName); ok {
+			return goType, true
+		}
+	}
+	qualifiedName, ok := m.registry.ResolveType(typeName, m.namespace)
+	if !ok {
+		return "", false
+	}
+	i := strings.LastIndex(qualifiedName, ".")
+	namespace := protoNamespace(qualifiedName[:i])
+	if namespace == m.namespace {
+		return "", false
+	}
+	return m.module + "/" + m.dirs[namespace] + "." + qualifiedName[i+1:], true
+}
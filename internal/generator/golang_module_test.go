@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func TestGoModule_Namespaces(t *testing.T) {
+	files, err := generateGoFiles(context.Background(), layoutTestSchema(), Options{Go: &GoOptions{Module: "example.com/types"}})
+	if err != nil {
+		t.Fatalf("generateGoFiles failed: %v", err)
+	}
+	if got, want := filePaths(files), "go.mod,orders/doc.go,orders/types.go,users/doc.go,users/types.go"; got != want {
+		t.Fatalf("Expected a package per namespace, got %s", got)
+	}
+	if got := string(files["go.mod"]); got != "module example.com/types\n\ngo "+DefaultGoModuleVersion+"\n" {
+		t.Errorf("Unexpected go.mod:\n%s", got)
+	}
+	if doc := string(files["users/doc.go"]); !strings.Contains(doc, "// Package users holds the types of the com.example.users namespace.\npackage users\n") {
+		t.Errorf("Expected the package doc of users, got:\n%s", doc)
+	}
+
+	orders := string(files["orders/types.go"])
+	for _, want := range []string{"package orders", `"example.com/types/users"`, "Customer users.User", "ProcessedBy User"} {
+		if !strings.Contains(orders, want) {
+			t.Errorf("Expected orders/types.go to contain %q, got:\n%s", want, orders)
+		}
+	}
+	users := string(files["users/types.go"])
+	if strings.Contains(users, "example.com/types") || !strings.Contains(users, "Status UserStatus") {
+		t.Errorf("Expected users/types.go to use its own types only, got:\n%s", users)
+	}
+
+	for path, content := range files {
+		if strings.HasSuffix(path, ".go") {
+			if _, err := parser.ParseFile(token.NewFileSet(), path, content, parser.AllErrors); err != nil {
+				t.Errorf("%s does not parse: %v", path, err)
+			}
+		}
+	}
+}
+
+func TestGoModule_SingleNamespace(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "com.example.users",
+		Version:   "2.1.0",
+		Types:     []*ast.Type{{Name: "User", Namespace: "com.example.users"}},
+	}
+	files, err := generateGoFiles(context.Background(), schema, Options{Go: &GoOptions{Module: "example.com/users/v2", GoVersion: "1.22"}})
+	if err != nil {
+		t.Fatalf("generateGoFiles failed: %v", err)
+	}
+	if got := filePaths(files); got != "doc.go,go.mod,types.go" {
+		t.Fatalf("Expected the package at the module root, got %s", got)
+	}
+	if got := string(files["go.mod"]); got != "module example.com/users/v2\n\ngo 1.22\n" {
+		t.Errorf("Unexpected go.mod:\n%s", got)
+	}
+	if doc := string(files["doc.go"]); !strings.Contains(doc, "namespace, version 2.1.0 of the schema.") {
+		t.Errorf("Expected the schema version in the package doc, got:\n%s", doc)
+	}
+}
+
+func TestGoModule_SharedPackageNames(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "com.example.users",
+		Types: []*ast.Type{
+			{Name: "User", Namespace: "com.example.users"},
+			{Name: "Account", Namespace: "com.legacy.users"},
+		},
+	}
+	files, err := generateGoFiles(context.Background(), schema, Options{Go: &GoOptions{Module: "example.com/types"}})
+	if err != nil {
+		t.Fatalf("generateGoFiles failed: %v", err)
+	}
+	if _, ok := files["com/legacy/users/types.go"]; !ok {
+		t.Errorf("Expected namespaces sharing a package name to use their namespace path, got %s", filePaths(files))
+	}
+}
+
+func TestGoModule_InvalidPath(t *testing.T) {
+	_, err := generateGoFiles(context.Background(), layoutTestSchema(), Options{Go: &GoOptions{Module: "example.com/my types"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid Go module path") {
+		t.Errorf("Expected an invalid module path error, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// stdMoneyType is the qualified name of the Money type of the standard library
+const stdMoneyType = "typemux.std.Money"
+
+// isStdMoney reports whether a type is the Money type of the standard library
+func isStdMoney(typ *ast.Type) bool {
+	return typ.Namespace+"."+typ.Name == stdMoneyType
+}
+
+// goMoneyHelpers are the methods generated for the standard library Money
+// type. Amounts are converted to nano units in a big.Int, so arithmetic is
+// exact and overflow is detected instead of wrapping around.
+const goMoneyHelpers = `// moneyNanosPerUnit is the number of nano units in a whole unit.
+const moneyNanosPerUnit = 1000000000
+
+// ErrCurrencyMismatch is returned by Money operations on amounts in different currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// ParseMoney parses a decimal amount such as "-1.75" in a currency. The amount
+// may have at most nine fractional digits.
+func ParseMoney(currencyCode, amount string) (Money, error) {
+	r, ok := new(big.Rat).SetString(amount)
+	if !ok || strings.ContainsAny(amount, "/eE") {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(moneyNanosPerUnit))
+	if !r.IsInt() {
+		return Money{}, fmt.Errorf("amount %q has more than 9 fractional digits", amount)
+	}
+	return moneyFromNanos(currencyCode, r.Num())
+}
+
+// moneyFromNanos returns the Money of an amount in nano units, or an error if
+// the whole units do not fit in an int64.
+func moneyFromNanos(currencyCode string, nanos *big.Int) (Money, error) {
+	units, rest := new(big.Int).QuoRem(nanos, big.NewInt(moneyNanosPerUnit), new(big.Int))
+	if !units.IsInt64() {
+		return Money{}, fmt.Errorf("amount overflows %s units", currencyCode)
+	}
+	return Money{CurrencyCode: currencyCode, Units: units.Int64(), Nanos: int32(rest.Int64())}, nil
+}
+
+// totalNanos returns the amount in nano units.
+func (m Money) totalNanos() *big.Int {
+	n := new(big.Int).Mul(big.NewInt(m.Units), big.NewInt(moneyNanosPerUnit))
+	return n.Add(n, big.NewInt(int64(m.Nanos)))
+}
+
+// Decimal returns the amount as a decimal without trailing zeros, such as "-1.75".
+func (m Money) Decimal() string {
+	n := m.totalNanos()
+	sign := ""
+	if n.Sign() < 0 {
+		sign = "-"
+		n.Neg(n)
+	}
+	units, nanos := new(big.Int).QuoRem(n, big.NewInt(moneyNanosPerUnit), new(big.Int))
+	if nanos.Sign() == 0 {
+		return sign + units.String()
+	}
+	return sign + units.String() + "." + strings.TrimRight(fmt.Sprintf("%09d", nanos.Int64()), "0")
+}
+
+// String returns the amount followed by the currency, such as "1.75 USD".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.CurrencyCode
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Units == 0 && m.Nanos == 0
+}
+
+// Add returns the sum of two amounts in the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if m.CurrencyCode != other.CurrencyCode {
+		return Money{}, fmt.Errorf("%w: cannot add %s to %s", ErrCurrencyMismatch, other.CurrencyCode, m.CurrencyCode)
+	}
+	sum := m.totalNanos()
+	return moneyFromNanos(m.CurrencyCode, sum.Add(sum, other.totalNanos()))
+}
+
+// Sub returns the difference of two amounts in the same currency.
+func (m Money) Sub(other Money) (Money, error) {
+	if m.CurrencyCode != other.CurrencyCode {
+		return Money{}, fmt.Errorf("%w: cannot subtract %s from %s", ErrCurrencyMismatch, other.CurrencyCode, m.CurrencyCode)
+	}
+	difference := m.totalNanos()
+	return moneyFromNanos(m.CurrencyCode, difference.Sub(difference, other.totalNanos()))
+}
+
+// Cmp compares two amounts in the same currency, returning -1, 0, or +1 when
+// the amount is less than, equal to, or greater than the other.
+func (m Money) Cmp(other Money) (int, error) {
+	if m.CurrencyCode != other.CurrencyCode {
+		return 0, fmt.Errorf("%w: cannot compare %s to %s", ErrCurrencyMismatch, m.CurrencyCode, other.CurrencyCode)
+	}
+	return m.totalNanos().Cmp(other.totalNanos()), nil
+}
+`
+
+// generateMoneyHelpers generates the parsing, formatting, and arithmetic
+// methods of the standard library Money type
+func (g *GoGenerator) generateMoneyHelpers() string {
+	for _, pkg := range []string{"errors", "fmt", "math/big", "strings"} {
+		g.imports[pkg] = true
+	}
+	return goMoneyHelpers
+}
@@ -0,0 +1,263 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// goPolicyTypes describes the call policies of methods and the middleware hook that enforces them
+const goPolicyTypes = `// MethodPolicy is the timeout, idempotency, rate limit, and authentication
+// declared for a service method.
+type MethodPolicy struct {
+	Timeout    time.Duration    // Deadline of a call; zero when none is declared
+	Idempotent bool             // Whether a call is safe to retry
+	RateLimit  *MethodRateLimit // Request quota; nil when none is declared
+	Auth       *MethodAuth      // Authentication of callers; nil when none is declared
+}
+
+// MethodRateLimit is a number of requests allowed per time window.
+type MethodRateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// MethodAuth is the authentication a service method requires of its callers.
+type MethodAuth struct {
+	Scopes []string // Scopes the caller needs, all of them; none when any authenticated caller may call
+}
+
+// MethodMiddleware runs a call of a service method, such as enforcing its policy
+// before, around, or instead of making the call. ctx is the context of the call;
+// middleware passes call the context to continue with, such as one with the
+// deadline of policy.Timeout.
+type MethodMiddleware func(ctx context.Context, method string, policy MethodPolicy, call func(ctx context.Context) error) error
+
+// ChainMiddleware returns a middleware that runs a call through each of the
+// given middleware in order, the first one outermost.
+func ChainMiddleware(middleware ...MethodMiddleware) MethodMiddleware {
+	return func(ctx context.Context, method string, policy MethodPolicy, call func(ctx context.Context) error) error {
+		next := call
+		for i := len(middleware) - 1; i >= 0; i-- {
+			outer, inner := middleware[i], next
+			next = func(ctx context.Context) error { return outer(ctx, method, policy, inner) }
+		}
+		return next(ctx)
+	}
+}
+`
+
+// hasPolicies reports whether any method of the service declares a call policy
+func (g *GoGenerator) hasPolicies(service *ast.Service) bool {
+	for _, method := range service.Methods {
+		if method.HasPolicy() {
+			return true
+		}
+	}
+	return false
+}
+
+// generateServicePolicies generates the policies of the methods of a service, the
+// typed call its hooks see, and a wrapper that runs every call through the hooks
+// and a chain of MethodMiddleware with the context of the call
+func (g *GoGenerator) generateServicePolicies(service *ast.Service) string {
+	var sb strings.Builder
+
+	policies := service.Name + "Policies"
+	sb.WriteString(fmt.Sprintf("// %s holds the declared policy of each %s method, by method name.\n", policies, service.Name))
+	sb.WriteString(fmt.Sprintf("var %s = map[string]MethodPolicy{\n", policies))
+	width := 0
+	for _, method := range service.Methods {
+		if method.HasPolicy() {
+			width = max(width, len(method.Name))
+		}
+	}
+	for _, method := range service.Methods {
+		if method.HasPolicy() {
+			// Align the values like gofmt
+			key := fmt.Sprintf("%q:", method.Name)
+			sb.WriteString(fmt.Sprintf("\t%-*s %s,\n", width+3, key, g.policyLiteral(method)))
+		}
+	}
+	sb.WriteString("}\n\n")
+
+	// Typed method names, calls, and hooks
+	methodType := service.Name + "Method"
+	callType := service.Name + "Call"
+	hooksType := service.Name + "Hooks"
+	sb.WriteString(fmt.Sprintf("// %s names a method of %s.\n", methodType, service.Name))
+	sb.WriteString(fmt.Sprintf("type %s string\n\n", methodType))
+	sb.WriteString(fmt.Sprintf("// Methods of %s\nconst (\n", service.Name))
+	width = 0
+	for _, method := range service.Methods {
+		width = max(width, len(service.Name+method.Name))
+	}
+	for _, method := range service.Methods {
+		sb.WriteString(fmt.Sprintf("\t%-*s %s = %q\n", width, service.Name+method.Name, methodType, method.Name))
+	}
+	sb.WriteString(")\n\n")
+
+	// A call holds the typed call of its method, so hooks see the concrete input
+	// and output types
+	var typed []*ast.Method
+	for _, method := range service.Methods {
+		if method.HasInput() || (method.HasOutput() && !method.OutputStream) {
+			typed = append(typed, method)
+		}
+	}
+	sb.WriteString(fmt.Sprintf("// %s is a call of a method of %s, as its hooks see it. The field named\n", callType, service.Name))
+	sb.WriteString("// after the method holds its input and output; it is nil for calls of other\n")
+	sb.WriteString("// methods and for methods without either.\n")
+	sb.WriteString(fmt.Sprintf("type %s struct {\n", callType))
+	width = len("Method")
+	for _, method := range typed {
+		width = max(width, len(g.typedCallField(method)))
+	}
+	sb.WriteString(fmt.Sprintf("\t%-*s %s\n", width, "Method", methodType))
+	sb.WriteString(fmt.Sprintf("\t%-*s MethodPolicy\n", width, "Policy"))
+	for _, method := range typed {
+		sb.WriteString(fmt.Sprintf("\t%-*s *%s\n", width, g.typedCallField(method), service.Name+method.Name+"Call"))
+	}
+	sb.WriteString("}\n\n")
+
+	for _, method := range typed {
+		sb.WriteString(fmt.Sprintf("// %s%sCall is a call of %s.\n", service.Name, method.Name, method.Name))
+		sb.WriteString(fmt.Sprintf("type %s%sCall struct {\n", service.Name, method.Name))
+		// Align the fields and their comments like gofmt
+		var fields [][3]string
+		if method.HasInput() {
+			fields = append(fields, [3]string{"Input", "*" + g.messageType(method.InputType), "Request of the call"})
+		}
+		if method.HasOutput() && !method.OutputStream {
+			fields = append(fields, [3]string{"Output", "*" + g.messageType(method.OutputType), "Response of a successful call, once it returned"})
+		}
+		nameWidth, typeWidth := 0, 0
+		for _, field := range fields {
+			nameWidth, typeWidth = max(nameWidth, len(field[0])), max(typeWidth, len(field[1]))
+		}
+		for _, field := range fields {
+			sb.WriteString(fmt.Sprintf("\t%-*s %-*s // %s\n", nameWidth, field[0], typeWidth, field[1], field[2]))
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("// %s run before and after every call of a method of %s.\n", hooksType, service.Name))
+	sb.WriteString("// They get the context of the call. A Before hook rejects a call by returning\n")
+	sb.WriteString("// an error, which the caller gets instead; the After hooks run with the error\n")
+	sb.WriteString("// of every call.\n")
+	sb.WriteString(fmt.Sprintf("type %s struct {\n", hooksType))
+	sb.WriteString(fmt.Sprintf("\tBefore []func(ctx context.Context, call *%s) error\n", callType))
+	sb.WriteString(fmt.Sprintf("\tAfter  []func(ctx context.Context, call *%s, err error)\n", callType))
+	sb.WriteString("}\n\n")
+
+	wrapper := uncapitalize(service.Name) + "WithMiddleware"
+	sb.WriteString(fmt.Sprintf("// New%sWithMiddleware wraps next so every call runs through middleware\n", service.Name))
+	sb.WriteString(fmt.Sprintf("// with the policy of the method from %s.\n", policies))
+	sb.WriteString(fmt.Sprintf("func New%sWithMiddleware(next %s, middleware MethodMiddleware) %s {\n", service.Name, service.Name, service.Name))
+	sb.WriteString(fmt.Sprintf("\treturn New%sWithHooks(next, %s{}, middleware)\n}\n\n", service.Name, hooksType))
+	sb.WriteString(fmt.Sprintf("// New%sWithHooks wraps next so every call runs the Before hooks, then the\n", service.Name))
+	sb.WriteString("// middleware, the first one outermost, and then the After hooks.\n")
+	sb.WriteString(fmt.Sprintf("func New%sWithHooks(next %s, hooks %s, middleware ...MethodMiddleware) %s {\n", service.Name, service.Name, hooksType, service.Name))
+	sb.WriteString(fmt.Sprintf("\treturn &%s{next: next, hooks: hooks, middleware: ChainMiddleware(middleware...)}\n}\n\n", wrapper))
+	sb.WriteString(fmt.Sprintf("type %s struct {\n\tnext       %s\n\thooks      %s\n\tmiddleware MethodMiddleware\n}\n\n", wrapper, service.Name, hooksType))
+
+	sb.WriteString("// run makes a call through the Before hooks, the middleware, and the After hooks\n")
+	sb.WriteString(fmt.Sprintf("func (s *%s) run(ctx context.Context, call *%s, next func(ctx context.Context) error) error {\n", wrapper, callType))
+	sb.WriteString("\tvar err error\n")
+	sb.WriteString("\tfor _, before := range s.hooks.Before {\n\t\tif err = before(ctx, call); err != nil {\n\t\t\tbreak\n\t\t}\n\t}\n")
+	sb.WriteString("\tif err == nil {\n\t\terr = s.middleware(ctx, string(call.Method), call.Policy, next)\n\t}\n")
+	sb.WriteString("\tfor _, after := range s.hooks.After {\n\t\tafter(ctx, call, err)\n\t}\n")
+	sb.WriteString("\treturn err\n}\n")
+
+	for _, method := range service.Methods {
+		params, args, results := g.methodSignature(method)
+		call := fmt.Sprintf("s.next.%s(%s)", method.Name, strings.Join(args, ", "))
+		fields := []string{"Method: " + service.Name + method.Name, fmt.Sprintf("Policy: %s[%q]", policies, method.Name)}
+		hasTyped := method.HasInput() || (method.HasOutput() && !method.OutputStream)
+
+		sb.WriteString(fmt.Sprintf("\nfunc (s *%s) %s(%s) %s {\n", wrapper, method.Name, strings.Join(params, ", "), results))
+		if hasTyped {
+			input := ""
+			if method.HasInput() {
+				input = "Input: input"
+			}
+			sb.WriteString(fmt.Sprintf("\ttyped := &%s%sCall{%s}\n", service.Name, method.Name, input))
+			fields = append(fields, g.typedCallField(method)+": typed")
+		}
+		sb.WriteString(fmt.Sprintf("\tcall := &%s{%s}\n", callType, strings.Join(fields, ", ")))
+		if results == "error" {
+			sb.WriteString("\treturn s.run(ctx, call, func(ctx context.Context) error {\n")
+			sb.WriteString(fmt.Sprintf("\t\treturn %s\n\t})\n}\n", call))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\tvar output *%s\n", g.messageType(method.OutputType)))
+		sb.WriteString("\terr := s.run(ctx, call, func(ctx context.Context) error {\n")
+		sb.WriteString(fmt.Sprintf("\t\tvar err error\n\t\tif output, err = %s; err == nil {\n\t\t\ttyped.Output = output\n\t\t}\n\t\treturn err\n\t})\n", call))
+		sb.WriteString("\treturn output, err\n}\n")
+	}
+
+	return sb.String()
+}
+
+// typedCallField returns the name of the field of a service call that holds the
+// typed call of a method: the method name, unless it is one of the other fields
+func (g *GoGenerator) typedCallField(method *ast.Method) string {
+	if method.Name == "Method" || method.Name == "Policy" {
+		return method.Name + "Call"
+	}
+	return method.Name
+}
+
+// policyLiteral renders the MethodPolicy of a method as a composite literal
+func (g *GoGenerator) policyLiteral(method *ast.Method) string {
+	var fields []string
+	if timeout := method.TimeoutDuration(); timeout > 0 {
+		fields = append(fields, "Timeout: "+goDurationLiteral(timeout))
+	}
+	if method.Idempotent {
+		fields = append(fields, "Idempotent: true")
+	}
+	if method.RateLimit != nil {
+		fields = append(fields, fmt.Sprintf("RateLimit: &MethodRateLimit{Requests: %d, Per: %s}",
+			method.RateLimit.Requests, goDurationLiteral(method.RateLimit.Window())))
+	}
+	if method.Auth != nil {
+		var scopes []string
+		for _, scope := range method.Auth.Scopes {
+			scopes = append(scopes, strconv.Quote(scope))
+		}
+		if len(scopes) > 0 {
+			fields = append(fields, fmt.Sprintf("Auth: &MethodAuth{Scopes: []string{%s}}", strings.Join(scopes, ", ")))
+		} else {
+			fields = append(fields, "Auth: &MethodAuth{}")
+		}
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// goDurationLiteral renders a duration as a Go expression in the largest unit that
+// divides it, such as 90 * time.Second for 1m30s
+func goDurationLiteral(d time.Duration) string {
+	units := []struct {
+		size time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, unit := range units {
+		if d%unit.size == 0 {
+			if d == unit.size {
+				return unit.name
+			}
+			return fmt.Sprintf("%d * %s", d/unit.size, unit.name)
+		}
+	}
+	return fmt.Sprintf("%d * time.Nanosecond", d)
+}
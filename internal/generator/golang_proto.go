@@ -0,0 +1,426 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// timestamppbPackage is the import path of the protobuf well-known Timestamp type
+const timestamppbPackage = "google.golang.org/protobuf/types/known/timestamppb"
+
+// protoKind classifies how a TypeMUX type converts to its protoc-gen-go representation
+type protoKind int
+
+const (
+	protoUnsupported protoKind = iota // No conversion is generated
+	protoScalar                       // Builtin scalar, copied or cast
+	protoEnum                         // Enum, converted with ToProto/FromProto
+	protoMessage                      // Type, converted with ToProto/FromProto
+	protoTimestamp                    // timestamp, converted with timestamppb
+	protoUnion                        // Union, converted with XToProto/XFromProto
+)
+
+// protoScalarTypes maps TypeMUX scalars to the Go types protoc-gen-go uses for them
+var protoScalarTypes = map[string]string{
+	"string":  "string",
+	"int32":   "int32",
+	"int64":   "int64",
+	"uint8":   "uint32",
+	"uint16":  "uint32",
+	"uint32":  "uint32",
+	"uint64":  "uint64",
+	"float32": "float32",
+	"float64": "float64",
+	"bool":    "bool",
+	"bytes":   "[]byte",
+}
+
+// protoPackageName returns the import path and package name of the protobuf types
+func (g *GoGenerator) protoPackageName() (string, string) {
+	path := g.opts.ProtoPackage
+	name := ""
+	if i := strings.Index(path, ";"); i >= 0 {
+		path, name = path[:i], path[i+1:]
+	}
+	if name == "" {
+		name = strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+				return r
+			}
+			return '_'
+		}, path[strings.LastIndex(path, "/")+1:])
+	}
+	return path, name
+}
+
+// protoQualifier imports the protobuf types and returns the qualifier for their names
+func (g *GoGenerator) protoQualifier() string {
+	path, name := g.protoPackageName()
+	g.imports[path] = true
+	g.importNames[path] = name
+	return name + "."
+}
+
+// protoGoName converts a protobuf field name to the Go name protoc-gen-go gives it
+func protoGoName(name string) string {
+	isLower := func(c byte) bool { return c >= 'a' && c <= 'z' }
+	var b []byte
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_' && i == 0:
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(name) && isLower(name[i+1]):
+			// Dropped; the next letter is capitalized
+		case c >= '0' && c <= '9':
+			b = append(b, c)
+		default:
+			if isLower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(name) && isLower(name[i+1]); i++ {
+				b = append(b, name[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+// protoMessageName returns the protobuf message name of a type
+func protoMessageName(typ *ast.Type) string {
+	if typ.Annotations != nil && typ.Annotations.ProtoName != "" {
+		return typ.Annotations.ProtoName
+	}
+	return typ.Name
+}
+
+// protoMessageType imports the protoc-gen-go message of a type and returns its
+// name, which is a well-known message for some standard library types
+func (g *GoGenerator) protoMessageType(typ *ast.Type) string {
+	if message, ok := wellKnownType(typ); ok {
+		g.imports[message.GoPackage] = true
+		g.importNames[message.GoPackage] = message.GoName
+		return message.GoName + "." + g.cleanTypeName(message.Name)
+	}
+	return g.protoQualifier() + protoMessageName(typ)
+}
+
+// protoKindOf classifies a type name for protobuf conversion
+func (g *GoGenerator) protoKindOf(typeName string) protoKind {
+	if g.isExternal(typeName) {
+		return protoUnsupported
+	}
+	if typeName == "timestamp" {
+		return protoTimestamp
+	}
+	if _, ok := protoScalarTypes[typeName]; ok {
+		return protoScalar
+	}
+
+	name := g.cleanTypeName(typeName)
+	switch {
+	case g.enums[name]:
+		return protoEnum
+	case g.types[name] != nil:
+		return protoMessage
+	case g.unions[name] != nil && g.isProtoConvertibleUnion(g.unions[name]):
+		return protoUnion
+	}
+	return protoUnsupported
+}
+
+// isProtoConvertibleUnion reports whether every option of a union is a type of the schema
+func (g *GoGenerator) isProtoConvertibleUnion(union *ast.Union) bool {
+	for _, option := range union.Options {
+		if g.types[g.cleanTypeName(option)] == nil || g.isExternal(option) {
+			return false
+		}
+	}
+	return len(union.Options) > 0
+}
+
+// protoElementType returns the protoc-gen-go type of a single value
+func (g *GoGenerator) protoElementType(kind protoKind, typeName string) string {
+	name := g.cleanTypeName(typeName)
+	switch kind {
+	case protoScalar:
+		return protoScalarTypes[typeName]
+	case protoEnum:
+		return g.protoQualifier() + name
+	case protoMessage:
+		return "*" + g.protoMessageType(g.types[name])
+	case protoTimestamp:
+		return "*timestamppb.Timestamp"
+	default:
+		return "*" + g.protoQualifier() + name
+	}
+}
+
+// toProtoValue converts a Go value expression to its protobuf representation
+func (g *GoGenerator) toProtoValue(kind protoKind, typeName, expr string) string {
+	switch kind {
+	case protoScalar:
+		if protoScalarTypes[typeName] != g.mapScalarTypeToGo(typeName) && typeName != "bytes" {
+			return fmt.Sprintf("%s(%s)", protoScalarTypes[typeName], expr)
+		}
+		return expr
+	case protoEnum, protoMessage:
+		if strings.HasPrefix(expr, "*") {
+			expr = "(" + expr + ")"
+		}
+		return expr + ".ToProto()"
+	case protoTimestamp:
+		g.imports[timestamppbPackage] = true
+		return fmt.Sprintf("timestamppb.New(%s)", expr)
+	default:
+		return fmt.Sprintf("%sToProto(%s)", g.cleanTypeName(typeName), expr)
+	}
+}
+
+// fromProtoValue converts a protobuf value expression to its Go representation
+func (g *GoGenerator) fromProtoValue(kind protoKind, typeName, expr string) string {
+	switch kind {
+	case protoScalar:
+		if protoScalarTypes[typeName] != g.mapScalarTypeToGo(typeName) && typeName != "bytes" {
+			return fmt.Sprintf("%s(%s)", g.mapScalarTypeToGo(typeName), expr)
+		}
+		return expr
+	case protoMessage:
+		g.protoHelpers = true
+		return fmt.Sprintf("protoValue(%sFromProto(%s))", g.cleanTypeName(typeName), expr)
+	case protoTimestamp:
+		return expr + ".AsTime()"
+	default:
+		return fmt.Sprintf("%sFromProto(%s)", g.cleanTypeName(typeName), expr)
+	}
+}
+
+// generateEnumProtoConversion generates the conversions between an enum and its protobuf enum.
+// Both use the same numbers, so the conversions are plain casts.
+func (g *GoGenerator) generateEnumProtoConversion(enum *ast.Enum) string {
+	protoType := g.protoQualifier() + enum.Name
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("// ToProto converts the %s to its protobuf enum.\n", enum.Name))
+	sb.WriteString(fmt.Sprintf("func (x %s) ToProto() %s {\n", enum.Name, protoType))
+	sb.WriteString(fmt.Sprintf("\treturn %s(x)\n", protoType))
+	sb.WriteString("}\n\n")
+	sb.WriteString(fmt.Sprintf("// %sFromProto converts a protobuf enum value to a %s.\n", enum.Name, enum.Name))
+	sb.WriteString(fmt.Sprintf("func %sFromProto(x %s) %s {\n", enum.Name, protoType, enum.Name))
+	sb.WriteString(fmt.Sprintf("\treturn %s(x)\n", enum.Name))
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// generateTypeProtoConversion generates the conversions between a type and its protobuf message
+func (g *GoGenerator) generateTypeProtoConversion(typ *ast.Type) string {
+	protoType := g.protoMessageType(typ)
+
+	var to, from strings.Builder
+	for _, field := range typ.Fields {
+		// Fields without a protobuf counterpart
+		if !field.ShouldIncludeInGenerator("proto") || len(field.Arguments) > 0 {
+			continue
+		}
+		toField, fromField := g.generateFieldProtoConversion(field)
+		to.WriteString(toField)
+		from.WriteString(fromField)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("// ToProto converts the %s to its protobuf message.\n", typ.Name))
+	sb.WriteString(fmt.Sprintf("func (m *%s) ToProto() *%s {\n", typ.Name, protoType))
+	sb.WriteString("\tif m == nil {\n\t\treturn nil\n\t}\n")
+	sb.WriteString(fmt.Sprintf("\tp := &%s{}\n", protoType))
+	sb.WriteString(to.String())
+	sb.WriteString("\treturn p\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(fmt.Sprintf("// %sFromProto converts a protobuf message to a %s.\n", typ.Name, typ.Name))
+	sb.WriteString(fmt.Sprintf("func %sFromProto(p *%s) *%s {\n", typ.Name, protoType, typ.Name))
+	sb.WriteString("\tif p == nil {\n\t\treturn nil\n\t}\n")
+	sb.WriteString(fmt.Sprintf("\tm := &%s{}\n", typ.Name))
+	sb.WriteString(from.String())
+	sb.WriteString("\treturn m\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// generateFieldProtoConversion returns the statements converting a field to and from protobuf
+func (g *GoGenerator) generateFieldProtoConversion(field *ast.Field) (string, string) {
+	goName := g.goFieldName(field)
+	protoName := protoGoName(field.NameFor("proto"))
+	goType := g.goFieldType(field)
+	goPtr := strings.HasPrefix(goType, "*")
+	goNull := strings.HasPrefix(goType, "Null[")
+	ft := field.Type
+	src := "m." + goName
+	dst := "p." + protoName
+
+	unsupported := fmt.Sprintf("\t// %s has no protobuf conversion\n", goName)
+
+	if ft.IsMap {
+		valueType := ft.GetMapValueType()
+		keyKind := g.protoKindOf(ft.MapKey)
+		valueKind := g.protoKindOf(valueType.Name)
+		if goPtr || keyKind != protoScalar || valueType.IsArray || valueType.IsMap || valueKind == protoUnsupported {
+			return unsupported, unsupported
+		}
+		protoType := fmt.Sprintf("map[%s]%s", protoScalarTypes[ft.MapKey], g.protoElementType(valueKind, valueType.Name))
+
+		to := fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tfor k, v := range %s {\n\t\t\t%s[%s] = %s\n\t\t}\n\t}\n",
+			src, dst, protoType, src, src, dst,
+			g.toProtoValue(keyKind, ft.MapKey, "k"), g.toProtoElement(valueKind, valueType.Name, "v"))
+		from := fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tfor k, v := range %s {\n\t\t\t%s[%s] = %s\n\t\t}\n\t}\n",
+			dst, src, goType, dst, dst, src,
+			g.fromProtoValue(keyKind, ft.MapKey, "k"), g.fromProtoElement(valueKind, valueType.Name, "v"))
+		return to, from
+	}
+
+	kind := g.protoKindOf(ft.Name)
+	if kind == protoUnsupported {
+		return unsupported, unsupported
+	}
+
+	if ft.IsArray {
+		if goPtr {
+			return unsupported, unsupported
+		}
+		protoType := "[]" + g.protoElementType(kind, ft.Name)
+
+		to := fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tfor i, v := range %s {\n\t\t\t%s[i] = %s\n\t\t}\n\t}\n",
+			src, dst, protoType, src, src, dst, g.toProtoElement(kind, ft.Name, "v"))
+		from := fmt.Sprintf("\tif %s != nil {\n\t\t%s = make(%s, len(%s))\n\t\tfor i, v := range %s {\n\t\t\t%s[i] = %s\n\t\t}\n\t}\n",
+			dst, src, goType, dst, dst, src, g.fromProtoElement(kind, ft.Name, "v"))
+		return to, from
+	}
+
+	switch kind {
+	case protoMessage:
+		if goPtr {
+			return fmt.Sprintf("\t%s = %s.ToProto()\n", dst, src),
+				fmt.Sprintf("\t%s = %sFromProto(%s)\n", src, g.cleanTypeName(ft.Name), dst)
+		}
+		return fmt.Sprintf("\t%s = %s.ToProto()\n", dst, src),
+			fmt.Sprintf("\t%s = %s\n", src, g.fromProtoValue(kind, ft.Name, dst))
+
+	case protoTimestamp:
+		if goNull {
+			return fmt.Sprintf("\tif %s.Valid {\n\t\t%s = %s\n\t}\n", src, dst, g.toProtoValue(kind, ft.Name, src+".Value")),
+				fmt.Sprintf("\tif %s != nil {\n\t\t%s = NullOf(%s)\n\t}\n", dst, src, g.fromProtoValue(kind, ft.Name, dst))
+		}
+		if goPtr {
+			g.protoHelpers = true
+			return fmt.Sprintf("\tif %s != nil {\n\t\t%s = %s\n\t}\n", src, dst, g.toProtoValue(kind, ft.Name, "*"+src)),
+				fmt.Sprintf("\tif %s != nil {\n\t\t%s = protoPtr(%s)\n\t}\n", dst, src, g.fromProtoValue(kind, ft.Name, dst))
+		}
+		return fmt.Sprintf("\tif !%s.IsZero() {\n\t\t%s = %s\n\t}\n", src, dst, g.toProtoValue(kind, ft.Name, src)),
+			fmt.Sprintf("\tif %s != nil {\n\t\t%s = %s\n\t}\n", dst, src, g.fromProtoValue(kind, ft.Name, dst))
+
+	case protoUnion:
+		if goPtr {
+			return unsupported, unsupported
+		}
+		return fmt.Sprintf("\t%s = %s\n", dst, g.toProtoValue(kind, ft.Name, src)),
+			fmt.Sprintf("\t%s = %s\n", src, g.fromProtoValue(kind, ft.Name, dst))
+	}
+
+	// Scalars and enums; optional values are pointers in protobuf, except bytes
+	protoPtr := ft.Optional && ft.Name != "bytes"
+	switch {
+	case goNull:
+		g.protoHelpers = true
+		return fmt.Sprintf("\tif %s.Valid {\n\t\t%s = protoPtr(%s)\n\t}\n", src, dst, g.toProtoValue(kind, ft.Name, src+".Value")),
+			fmt.Sprintf("\tif %s != nil {\n\t\t%s = NullOf(%s)\n\t}\n", dst, src, g.fromProtoValue(kind, ft.Name, "*"+dst))
+	case !goPtr && !protoPtr:
+		return fmt.Sprintf("\t%s = %s\n", dst, g.toProtoValue(kind, ft.Name, src)),
+			fmt.Sprintf("\t%s = %s\n", src, g.fromProtoValue(kind, ft.Name, dst))
+	case !goPtr && protoPtr:
+		g.protoHelpers = true
+		return fmt.Sprintf("\tif %s {\n\t\t%s = protoPtr(%s)\n\t}\n", g.nonZeroCondition(ft.Name, src), dst, g.toProtoValue(kind, ft.Name, src)),
+			fmt.Sprintf("\t%s = %s\n", src, g.fromProtoValue(kind, ft.Name, "p.Get"+protoName+"()"))
+	case goPtr && !protoPtr:
+		g.protoHelpers = true
+		return fmt.Sprintf("\tif %s != nil {\n\t\t%s = %s\n\t}\n", src, dst, g.toProtoValue(kind, ft.Name, "*"+src)),
+			fmt.Sprintf("\t%s = protoPtr(%s)\n", src, g.fromProtoValue(kind, ft.Name, dst))
+	default:
+		g.protoHelpers = true
+		return fmt.Sprintf("\tif %s != nil {\n\t\t%s = protoPtr(%s)\n\t}\n", src, dst, g.toProtoValue(kind, ft.Name, "*"+src)),
+			fmt.Sprintf("\tif %s != nil {\n\t\t%s = protoPtr(%s)\n\t}\n", dst, src, g.fromProtoValue(kind, ft.Name, "*"+dst))
+	}
+}
+
+// nonZeroCondition returns the condition under which an optional scalar is set
+func (g *GoGenerator) nonZeroCondition(typeName, access string) string {
+	switch typeName {
+	case "bool":
+		return access
+	case "string":
+		return access + ` != ""`
+	default:
+		return access + " != 0"
+	}
+}
+
+// generateUnionProtoConversion generates the conversions between a union and its
+// protobuf message, whose options form a oneof named value. Unions with options
+// that are not types of the schema are not converted.
+func (g *GoGenerator) generateUnionProtoConversion(union *ast.Union) string {
+	if !g.isProtoConvertibleUnion(union) {
+		return ""
+	}
+	g.protoHelpers = true
+	qualifier := g.protoQualifier()
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("// %sToProto converts a %s to its protobuf message.\n", union.Name, union.Name))
+	sb.WriteString(fmt.Sprintf("func %sToProto(u %s) *%s%s {\n", union.Name, union.Name, qualifier, union.Name))
+	sb.WriteString("\tswitch v := u.(type) {\n")
+	for _, option := range union.Options {
+		optionName := protoGoName(strings.ToLower(option[:1]) + option[1:])
+		sb.WriteString(fmt.Sprintf("\tcase %s%s:\n", union.Name, option))
+		sb.WriteString(fmt.Sprintf("\t\treturn &%s%s{Value: &%s%s_%s{%s: v.Value.ToProto()}}\n",
+			qualifier, union.Name, qualifier, union.Name, optionName, optionName))
+	}
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn nil\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(fmt.Sprintf("// %sFromProto converts a protobuf message to a %s.\n", union.Name, union.Name))
+	sb.WriteString(fmt.Sprintf("func %sFromProto(p *%s%s) %s {\n", union.Name, qualifier, union.Name, union.Name))
+	sb.WriteString("\tswitch v := p.GetValue().(type) {\n")
+	for _, option := range union.Options {
+		optionName := protoGoName(strings.ToLower(option[:1]) + option[1:])
+		sb.WriteString(fmt.Sprintf("\tcase *%s%s_%s:\n", qualifier, union.Name, optionName))
+		sb.WriteString(fmt.Sprintf("\t\treturn %s%s{Value: protoValue(%sFromProto(v.%s))}\n",
+			union.Name, option, g.cleanTypeName(option), optionName))
+	}
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn nil\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// generateProtoHelpers declares the pointer helpers used by protobuf conversions
+func (g *GoGenerator) generateProtoHelpers() string {
+	if !g.protoHelpers {
+		return ""
+	}
+	return `// protoPtr returns a pointer to a copy of v.
+func protoPtr[T any](v T) *T {
+	return &v
+}
+
+// protoValue returns the value v points to, or the zero value when v is nil.
+func protoValue[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
+`
+}
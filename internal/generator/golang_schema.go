@@ -0,0 +1,193 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"gopkg.in/yaml.v3"
+)
+
+// goSchemaHandler is the handler of the schema endpoint, which serves the
+// artifacts declared before it
+const goSchemaHandler = `// SchemaHandler serves the schema of this package to clients and tools that
+// discover it at run time, at these paths under prefix:
+//
+//	GET <prefix>/openapi.json    the OpenAPI document (SchemaOpenAPI)
+//	GET <prefix>/schema.graphql  the GraphQL SDL (SchemaGraphQL)
+//	GET <prefix>/descriptor.pb   the Protobuf FileDescriptorSet (SchemaDescriptorSet)
+//
+// For example, mux.Handle("/schema/", SchemaHandler("/schema")).
+func SchemaHandler(prefix string) http.Handler {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var contentType string
+		var body []byte
+		switch strings.TrimPrefix(r.URL.Path, prefix) {
+		case "/openapi.json":
+			contentType, body = "application/json", []byte(SchemaOpenAPI)
+		case "/schema.graphql":
+			contentType, body = "text/plain; charset=utf-8", []byte(SchemaGraphQL)
+		case "/descriptor.pb":
+			contentType, body = "application/x-protobuf", SchemaDescriptorSet
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+	})
+}
+`
+
+// generateGoSchemaEndpoint generates schema_endpoint.go, which embeds the
+// OpenAPI document, GraphQL SDL, and Protobuf descriptor set of a schema, as
+// the openapi, graphql, and protobuf formats generate them with the same
+// options, and serves them over HTTP
+func generateGoSchemaEndpoint(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	openapi, err := yamlToJSON([]byte(NewOpenAPIGeneratorWithOptions(openapiOptions(opts)).Generate(schema)))
+	if err != nil {
+		return nil, fmt.Errorf("converting the OpenAPI document to JSON: %w", err)
+	}
+	graphql := NewGraphQLGeneratorWithOptions(graphqlOptions(opts)).Generate(schema)
+	descriptorSet, err := DescriptorSet(ctx, schema, opts)
+	if err != nil {
+		return nil, fmt.Errorf("building the descriptor set: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by TypeMUX. DO NOT EDIT.\n\n")
+	sb.WriteString(fmt.Sprintf("package %s\n\n", NewGoGeneratorWithOptions(opts.Go).packageName(schema)))
+	sb.WriteString("import (\n\t\"bytes\"\n\t\"net/http\"\n\t\"strings\"\n\t\"time\"\n)\n\n")
+	sb.WriteString("// SchemaOpenAPI is the OpenAPI document of the schema, as JSON.\n")
+	sb.WriteString(fmt.Sprintf("const SchemaOpenAPI = %s\n\n", goStringLiteral(string(openapi))))
+	sb.WriteString("// SchemaGraphQL is the GraphQL SDL of the schema.\n")
+	sb.WriteString(fmt.Sprintf("const SchemaGraphQL = %s\n\n", goStringLiteral(graphql)))
+	sb.WriteString("// SchemaDescriptorSet is the serialized google.protobuf.FileDescriptorSet of the\n")
+	sb.WriteString("// Protobuf files of the schema, including the well-known files they import.\n")
+	sb.WriteString(fmt.Sprintf("var SchemaDescriptorSet = %s\n\n", goBytesLiteral(descriptorSet)))
+	sb.WriteString(goSchemaHandler)
+	return map[string][]byte{"schema_endpoint.go": []byte(sb.String())}, nil
+}
+
+// goStringLiteral returns a Go literal of a string: a raw string, unless it
+// holds characters a raw string cannot
+func goStringLiteral(s string) string {
+	if strings.ContainsAny(s, "`\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// goBytesLiteral returns a Go []byte literal of data, with 16 bytes per line
+func goBytesLiteral(data []byte) string {
+	var sb strings.Builder
+	sb.WriteString("[]byte{")
+	for i, b := range data {
+		if i%16 == 0 {
+			sb.WriteString("\n\t")
+		} else {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(fmt.Sprintf("0x%02x,", b))
+	}
+	sb.WriteString("\n}")
+	return sb.String()
+}
+
+// yamlToJSON converts a YAML document to indented JSON, keeping the order of
+// the keys of its mappings
+func yamlToJSON(doc []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(doc, &node); err != nil {
+		return nil, err
+	}
+	var compact bytes.Buffer
+	if err := writeJSONNode(&compact, &node); err != nil {
+		return nil, err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	return indented.Bytes(), nil
+}
+
+// writeJSONNode writes a YAML node as compact JSON
+func writeJSONNode(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSONNode(buf, node.Content[0])
+	case yaml.AliasNode:
+		return writeJSONNode(buf, node.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONValue(buf, node.Content[i].Value); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeJSONNode(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONNode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		switch node.ShortTag() {
+		case "!!null":
+			buf.WriteString("null")
+		case "!!bool", "!!int", "!!float":
+			var value interface{}
+			if err := node.Decode(&value); err != nil {
+				return err
+			}
+			return writeJSONValue(buf, value)
+		default:
+			return writeJSONValue(buf, node.Value)
+		}
+	}
+	return nil
+}
+
+// writeJSONValue writes a scalar as JSON, without escaping HTML characters
+func writeJSONValue(buf *bytes.Buffer, value interface{}) error {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+	return nil
+}
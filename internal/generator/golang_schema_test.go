@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestGenerateGoSchemaEndpoint(t *testing.T) {
+	files, err := generateGoSchemaEndpoint(context.Background(), grpcTestSchema(), Options{})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	output := string(files["schema_endpoint.go"])
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "schema_endpoint.go", output, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, output)
+	}
+
+	expected := []string{
+		"package users\n",
+		"const SchemaOpenAPI = `{\n  \"openapi\": \"3.0.0\",",
+		"const SchemaGraphQL = `",
+		"type User {",
+		"var SchemaDescriptorSet = []byte{\n\t0x0a,",
+		"func SchemaHandler(prefix string) http.Handler {",
+		"case \"/descriptor.pb\":",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q", exp)
+		}
+	}
+}
+
+func TestDescriptorSet(t *testing.T) {
+	data, err := DescriptorSet(context.Background(), grpcTestSchema(), Options{})
+	if err != nil {
+		t.Fatalf("DescriptorSet failed: %v", err)
+	}
+	for _, exp := range []string{"google/protobuf/empty.proto", "UserService", "GetUser"} {
+		if !strings.Contains(string(data), exp) {
+			t.Errorf("Expected the descriptor set to contain %q", exp)
+		}
+	}
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "key order",
+			input:    "zeta: 1\nalpha: true\nmid: null\n",
+			expected: "{\n  \"zeta\": 1,\n  \"alpha\": true,\n  \"mid\": null\n}",
+		},
+		{
+			name:     "quoted scalars stay strings",
+			input:    "version: \"1.0\"\nenabled: \"true\"\n",
+			expected: "{\n  \"version\": \"1.0\",\n  \"enabled\": \"true\"\n}",
+		},
+		{
+			name:     "sequences and HTML characters",
+			input:    "items:\n  - <a>\n  - b & c\n",
+			expected: "{\n  \"items\": [\n    \"<a>\",\n    \"b & c\"\n  ]\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yamlToJSON([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("yamlToJSON failed: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
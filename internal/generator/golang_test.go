@@ -1,8 +1,11 @@
 package generator
 
 import (
+	"go/parser"
+	"go/token"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/rasmartins/typemux/internal/ast"
 )
@@ -82,12 +85,15 @@ func TestGoGenerator_GenerateEnum(t *testing.T) {
 	output := gen.Generate(schema)
 
 	// Check enum type
-	if !strings.Contains(output, "type Status int") {
+	if !strings.Contains(output, "type Status int32") {
 		t.Errorf("Expected Status enum type definition")
 	}
 
-	// Check enum values
-	if !strings.Contains(output, "StatusActive Status = iota") {
+	// Check enum values, numbered like the protobuf output
+	if !strings.Contains(output, "StatusUnspecified Status = 0") {
+		t.Errorf("Expected StatusUnspecified zero value")
+	}
+	if !strings.Contains(output, "StatusActive Status = 1") {
 		t.Errorf("Expected StatusActive enum value")
 	}
 	if !strings.Contains(output, "StatusInactive") {
@@ -98,6 +104,106 @@ func TestGoGenerator_GenerateEnum(t *testing.T) {
 	}
 }
 
+func TestGoGenerator_EnumMethods(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "api",
+		Enums: []*ast.Enum{
+			{
+				Name: "Priority",
+				Values: []*ast.EnumValue{
+					{Name: "LOW", Number: 10, HasNumber: true},
+					{Name: "HIGH"},
+				},
+			},
+		},
+	}
+
+	output := NewGoGenerator().Generate(schema)
+
+	expected := []string{
+		"PriorityUnspecified Priority = 0",
+		"PriorityLOW Priority = 10",
+		"PriorityHIGH Priority = 11",
+		`PriorityUnspecified: "PRIORITY_UNSPECIFIED",`,
+		`"HIGH": PriorityHIGH,`,
+		"func (x Priority) String() string {",
+		"func ParsePriority(name string) (Priority, error) {",
+		"func (x Priority) MarshalJSON() ([]byte, error) {",
+		"func (x *Priority) UnmarshalJSON(data []byte) error {",
+		"value = PriorityUnspecified",
+		`"encoding/json"`,
+		`"fmt"`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "enums.go", output, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, output)
+	}
+}
+
+func TestGoGenerator_EnumDeclaredZeroValue(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{
+			{
+				Name: "Color",
+				Values: []*ast.EnumValue{
+					{Name: "NONE", Number: 0, HasNumber: true},
+					{Name: "RED", Number: 1, HasNumber: true},
+				},
+			},
+		},
+	}
+
+	output := NewGoGenerator().Generate(schema)
+
+	if strings.Contains(output, "ColorUnspecified") {
+		t.Error("Expected no generated zero value when one is declared")
+	}
+	if !strings.Contains(output, "return ColorNONE, fmt.Errorf") {
+		t.Error("Expected declared zero value to be used for unknown names")
+	}
+}
+
+func TestGoGenerator_FlagsEnum(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{
+			{
+				Name:  "Permission",
+				Flags: true,
+				Values: []*ast.EnumValue{
+					{Name: "READ"},
+					{Name: "WRITE"},
+					{Name: "ADMIN", Number: 16, HasNumber: true},
+				},
+			},
+		},
+	}
+
+	output := NewGoGenerator().Generate(schema)
+
+	for _, want := range []string{
+		"PermissionWRITE Permission = 2",
+		"PermissionADMIN Permission = 16",
+		"var permissionBits = []Permission{",
+		"func (x Permission) Has(flag Permission) bool {",
+		"func (x Permission) Set(flag Permission) Permission {",
+		"func (x Permission) Clear(flag Permission) Permission {",
+		"return strings.Join(names, \"|\")",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+	// Flags are combined, so they are encoded as numbers rather than names
+	if strings.Contains(output, "MarshalJSON") || strings.Contains(output, "ParsePermission") {
+		t.Error("Expected no name-based JSON or Parse methods for a flags enum")
+	}
+}
+
 func TestGoGenerator_GenerateWithTimestamp(t *testing.T) {
 	schema := &ast.Schema{
 		Namespace: "api",
@@ -216,6 +322,134 @@ func TestGoGenerator_GenerateOptionalField(t *testing.T) {
 	}
 }
 
+func TestGoGenerator_OptionalFields(t *testing.T) {
+	optional := func(name, typeName string) *ast.Field {
+		return &ast.Field{Name: name, Type: &ast.FieldType{Name: typeName, Optional: true}}
+	}
+	schema := &ast.Schema{
+		Namespace: "api",
+		Enums:     []*ast.Enum{{Name: "Status", Values: []*ast.EnumValue{{Name: "ACTIVE"}}}},
+		Types: []*ast.Type{
+			{Name: "Address", Fields: []*ast.Field{{Name: "city", Type: &ast.FieldType{Name: "string"}}}},
+			{Name: "Profile", Fields: []*ast.Field{
+				optional("bio", "string"),
+				optional("status", "Status"),
+				optional("since", "timestamp"),
+				optional("address", "Address"),
+				optional("avatar", "bytes"),
+			}},
+		},
+	}
+
+	tests := []struct {
+		style GoOptionalFields
+		want  []string
+	}{
+		{GoOptionalDefault, []string{"Bio string", "Status *Status", "Since *time.Time", "Address *Address"}},
+		{GoOptionalPointer, []string{"Bio *string", "Status *Status", "Since *time.Time", "Address *Address"}},
+		{GoOptionalValue, []string{"Bio string", "Status Status", "Since time.Time", "Address Address"}},
+		{GoOptionalWrapper, []string{"Bio Null[string]", "Status Null[Status]", "Since Null[time.Time]", "Address *Address", "type Null[T any] struct"}},
+	}
+	for _, tt := range tests {
+		output := NewGoGeneratorWithOptions(&GoOptions{OptionalFields: tt.style}).Generate(schema)
+		for _, want := range append(tt.want, "Avatar []byte") {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected %q with optional fields %q, got:\n%s", want, tt.style, output)
+			}
+		}
+		if hasNull := strings.Contains(output, "Null["); hasNull != (tt.style == GoOptionalWrapper) {
+			t.Errorf("Expected the Null wrapper only in the wrapper style, got it with %q", tt.style)
+		}
+	}
+}
+
+func TestGoGenerator_OptionalFieldsWrapperValidation(t *testing.T) {
+	minLength := 2
+	schema := &ast.Schema{
+		Namespace: "api",
+		Types: []*ast.Type{{Name: "Profile", Fields: []*ast.Field{{
+			Name:       "nickname",
+			Type:       &ast.FieldType{Name: "string", Optional: true},
+			Validation: &ast.ValidationRules{MinLength: &minLength},
+		}}}},
+	}
+
+	output := NewGoGeneratorWithOptions(&GoOptions{OptionalFields: GoOptionalWrapper}).Generate(schema)
+	for _, want := range []string{
+		"if m.Nickname.Valid {",
+		"utf8.RuneCountInString(m.Nickname.Value) < 2",
+		"\t\"encoding/json\"\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestGoGenerator_TypeMapper(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "api",
+		Enums:     []*ast.Enum{{Name: "Currency", Values: []*ast.EnumValue{{Name: "EUR"}}}},
+		Types: []*ast.Type{
+			{Name: "Decimal", Fields: []*ast.Field{{Name: "value", Type: &ast.FieldType{Name: "string"}, Required: true}}},
+			{Name: "Order", Fields: []*ast.Field{
+				{Name: "id", Type: &ast.FieldType{Name: "uuid"}},
+				{Name: "total", Type: &ast.FieldType{Name: "Decimal"}, Required: true},
+				{Name: "lines", Type: &ast.FieldType{Name: "Decimal", IsArray: true}},
+				{Name: "currency", Type: &ast.FieldType{Name: "Currency"}},
+				{Name: "createdAt", Type: &ast.FieldType{Name: "timestamp"}},
+			}},
+		},
+		Services: []*ast.Service{{Name: "OrderService", Methods: []*ast.Method{
+			{Name: "GetTotal", InputType: "Order", OutputType: "Decimal"},
+		}}},
+	}
+
+	output := NewGoGeneratorWithOptions(&GoOptions{TypeMapper: GoTypeMap{
+		"uuid":      "github.com/google/uuid.UUID",
+		"Decimal":   "github.com/shopspring/decimal.Decimal",
+		"Currency":  "string",
+		"timestamp": "github.com/example/civil/v2.DateTime",
+	}}).Generate(schema)
+
+	for _, want := range []string{
+		"\t\"github.com/google/uuid\"\n",
+		"\t\"github.com/shopspring/decimal\"\n",
+		"\tcivil \"github.com/example/civil/v2\"\n",
+		"Id uuid.UUID",
+		"Total decimal.Decimal",
+		"Lines []decimal.Decimal",
+		"Currency string",
+		"CreatedAt civil.DateTime",
+		"GetTotal(ctx context.Context, input *Order) (*decimal.Decimal, error)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	for _, unwanted := range []string{"type Decimal struct", "type Currency int32", "\"time\"", "Validate()"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("Expected no %q for mapped types in output:\n%s", unwanted, output)
+		}
+	}
+}
+
+func TestGoImportName(t *testing.T) {
+	tests := map[string]string{
+		"time":                          "time",
+		"github.com/google/uuid":        "uuid",
+		"github.com/example/civil/v2":   "civil",
+		"gopkg.in/yaml.v3":              "yaml",
+		"github.com/example/go-money":   "money",
+		"github.com/example/date-range": "date_range",
+	}
+	for path, want := range tests {
+		if got := goImportName(path); got != want {
+			t.Errorf("goImportName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
 func TestGoGenerator_GenerateUnion(t *testing.T) {
 	schema := &ast.Schema{
 		Namespace: "api",
@@ -281,12 +515,12 @@ func TestGoGenerator_GenerateService(t *testing.T) {
 	}
 
 	// Check regular method
-	if !strings.Contains(output, "GetUser(input *GetUserRequest) (*GetUserResponse, error)") {
+	if !strings.Contains(output, "GetUser(ctx context.Context, input *GetUserRequest) (*GetUserResponse, error)") {
 		t.Errorf("Expected GetUser method signature")
 	}
 
 	// Check streaming method
-	if !strings.Contains(output, "ListUsers(input *ListUsersRequest, stream chan *ListUsersResponse) error") {
+	if !strings.Contains(output, "ListUsers(ctx context.Context, input *ListUsersRequest, stream chan *ListUsersResponse) error") {
 		t.Errorf("Expected ListUsers streaming method signature")
 	}
 }
@@ -354,6 +588,11 @@ func TestGoGenerator_GetPackageName(t *testing.T) {
 			namespace: "user-service",
 			expected:  "userservice",
 		},
+		{
+			name:      "keyword namespace",
+			namespace: "com.example.go",
+			expected:  "go_",
+		},
 	}
 
 	gen := NewGoGenerator()
@@ -592,3 +831,408 @@ func TestGoGenerator_CombinedJSONAnnotations(t *testing.T) {
 		t.Errorf("Expected json:\"phone_number,omitempty\" tag, got: %s", output)
 	}
 }
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func goValidationTestSchema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "shop",
+		Enums: []*ast.Enum{
+			{Name: "Status", Values: []*ast.EnumValue{{Name: "ACTIVE", Number: 1, HasNumber: true}}},
+		},
+		Types: []*ast.Type{
+			{
+				Name: "Address",
+				Fields: []*ast.Field{
+					{Name: "city", Required: true, Type: &ast.FieldType{Name: "string"},
+						Validation: &ast.ValidationRules{MinLength: intPtr(2), MaxLength: intPtr(50)}},
+					{Name: "zip", Type: &ast.FieldType{Name: "string", Optional: true},
+						Validation: &ast.ValidationRules{Pattern: "^[0-9]{5}$"}},
+				},
+			},
+			{
+				Name: "User",
+				Fields: []*ast.Field{
+					{Name: "id", Required: true, Type: &ast.FieldType{Name: "string"},
+						Validation: &ast.ValidationRules{Format: "uuid"}},
+					{Name: "email", Type: &ast.FieldType{Name: "string"},
+						Validation: &ast.ValidationRules{Format: "email"}},
+					{Name: "age", Type: &ast.FieldType{Name: "int32"},
+						Validation: &ast.ValidationRules{Min: floatPtr(0), Max: floatPtr(150)}},
+					{Name: "role", Type: &ast.FieldType{Name: "string"},
+						Validation: &ast.ValidationRules{Enum: []string{"admin", "user"}}},
+					{Name: "tags", Type: &ast.FieldType{Name: "string", IsArray: true},
+						Validation: &ast.ValidationRules{MinItems: intPtr(1), UniqueItems: true}},
+					{Name: "statuses", Type: &ast.FieldType{Name: "Status", IsArray: true},
+						Validation: &ast.ValidationRules{UniqueItems: true}},
+					{Name: "address", Required: true, Type: &ast.FieldType{Name: "Address"}},
+					{Name: "previous", Type: &ast.FieldType{Name: "Address", IsArray: true}},
+					{Name: "backup", Type: &ast.FieldType{Name: "Address", Optional: true}},
+					{Name: "created", Required: true, Type: &ast.FieldType{Name: "timestamp"}},
+					{Name: "nick", JSONNullable: true, Type: &ast.FieldType{Name: "string"},
+						Validation: &ast.ValidationRules{MinLength: intPtr(3)}},
+				},
+			},
+			{
+				Name: "Plain",
+				Fields: []*ast.Field{
+					{Name: "count", Required: true, Type: &ast.FieldType{Name: "int32"}},
+				},
+			},
+		},
+	}
+}
+
+func TestGoGenerator_ValidateMethods(t *testing.T) {
+	output := NewGoGenerator().Generate(goValidationTestSchema())
+
+	expected := []string{
+		"func (m *Address) Validate() error {",
+		"if m.City == \"\" {\n\t\terrs = append(errs, errors.New(\"city: is required\"))",
+		"if utf8.RuneCountInString(m.City) < 2 {",
+		"if utf8.RuneCountInString(m.City) > 50 {",
+		"if m.Zip != \"\" {\n\t\tif !addressZipPattern.MatchString(m.Zip) {",
+		"var addressZipPattern = regexp.MustCompile(\"^[0-9]{5}$\")",
+		"if !uuidPattern.MatchString(m.Id) {",
+		"if !emailPattern.MatchString(m.Email) {",
+		"var emailPattern = regexp.MustCompile(",
+		"if float64(m.Age) < 0 {",
+		"if float64(m.Age) > 150 {",
+		"case \"admin\", \"user\":",
+		"if len(m.Tags) < 1 {",
+		"seen := make(map[string]bool, len(m.Tags))",
+		"seen := make(map[Status]bool, len(m.Statuses))",
+		"if err := m.Address.Validate(); err != nil {\n\t\terrs = append(errs, validationErrors(\"address\", err)...)",
+		"if err := m.Previous[i].Validate(); err != nil {",
+		"if m.Backup != nil {\n\t\tif err := m.Backup.Validate(); err != nil {",
+		"if m.Created.IsZero() {",
+		"if m.Nick != nil {\n\t\tif utf8.RuneCountInString(*m.Nick) < 3 {",
+		"return errors.Join(errs...)",
+		"func validationErrors(path string, err error) []error {",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain:\n%s\n\nGot:\n%s", want, output)
+		}
+	}
+
+	if strings.Contains(output, "func (m *Plain) Validate()") {
+		t.Error("Expected no Validate method for a type without checkable constraints")
+	}
+
+	for _, pkg := range []string{`"errors"`, `"fmt"`, `"regexp"`, `"time"`, `"unicode/utf8"`} {
+		if !strings.Contains(output, pkg) {
+			t.Errorf("Expected import %s", pkg)
+		}
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "types.go", output, 0); err != nil {
+		t.Errorf("Generated code is not valid Go: %v\n%s", err, output)
+	}
+}
+
+func TestGoGenerator_NoValidationImports(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{Name: "Point", Fields: []*ast.Field{{Name: "x", Type: &ast.FieldType{Name: "int32"}}}},
+		},
+	}
+
+	output := NewGoGenerator().Generate(schema)
+	if strings.Contains(output, "import") || strings.Contains(output, "Validate") {
+		t.Errorf("Expected no imports or Validate methods, got:\n%s", output)
+	}
+}
+
+func goProtoTestSchema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "api",
+		Enums: []*ast.Enum{
+			{Name: "Status", Values: []*ast.EnumValue{{Name: "ACTIVE"}}},
+		},
+		Types: []*ast.Type{
+			{
+				Name: "Address",
+				Fields: []*ast.Field{
+					{Name: "zip_code", Type: &ast.FieldType{Name: "uint16"}},
+				},
+			},
+			{
+				Name:        "User",
+				Annotations: &ast.FormatAnnotations{ProtoName: "UserMessage"},
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string"}},
+					{Name: "nick", Type: &ast.FieldType{Name: "string", Optional: true}},
+					{Name: "status", Type: &ast.FieldType{Name: "Status", Optional: true}},
+					{Name: "home", Type: &ast.FieldType{Name: "Address"}},
+					{Name: "created", Type: &ast.FieldType{Name: "timestamp"}},
+					{Name: "addresses", Type: &ast.FieldType{Name: "Address", IsArray: true}},
+					{Name: "places", Type: &ast.FieldType{Name: "Address", IsMap: true, MapKey: "string", MapValue: "Address"}},
+					{Name: "result", Type: &ast.FieldType{Name: "Result"}},
+					{Name: "extra", Type: &ast.FieldType{Name: "Other"}},
+				},
+			},
+		},
+		Unions: []*ast.Union{
+			{Name: "Result", Options: []string{"Address", "User"}},
+		},
+	}
+}
+
+func TestGoGenerator_ProtoConversions(t *testing.T) {
+	gen := NewGoGeneratorWithOptions(&GoOptions{ProtoPackage: "github.com/example/api/pb;apipb"})
+	output := gen.Generate(goProtoTestSchema())
+
+	expected := []string{
+		`apipb "github.com/example/api/pb"`,
+		`"google.golang.org/protobuf/types/known/timestamppb"`,
+		"func (x Status) ToProto() apipb.Status {",
+		"func StatusFromProto(x apipb.Status) Status {",
+		"func (m *User) ToProto() *apipb.UserMessage {",
+		"func UserFromProto(p *apipb.UserMessage) *User {",
+		"p.ZipCode = uint32(m.ZipCode)",
+		"m.ZipCode = uint16(p.ZipCode)",
+		"if m.Nick != \"\" {\n\t\tp.Nick = protoPtr(m.Nick)\n\t}",
+		"m.Nick = p.GetNick()",
+		"p.Status = protoPtr((*m.Status).ToProto())",
+		"m.Home = protoValue(AddressFromProto(p.Home))",
+		"p.Created = timestamppb.New(m.Created)",
+		"p.Addresses = make([]*apipb.Address, len(m.Addresses))",
+		"m.Places = make(map[string]Address, len(p.Places))",
+		"p.Result = ResultToProto(m.Result)",
+		"return &apipb.Result{Value: &apipb.Result_User{User: v.Value.ToProto()}}",
+		"case *apipb.Result_Address:\n\t\treturn ResultAddress{Value: protoValue(AddressFromProto(v.Address))}",
+		"// Extra has no protobuf conversion",
+		"func protoPtr[T any](v T) *T {",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain:\n%s\n\nGot:\n%s", want, output)
+		}
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "types.go", output, 0); err != nil {
+		t.Errorf("Generated code is not valid Go: %v\n%s", err, output)
+	}
+}
+
+func TestGoGenerator_NoProtoConversionsByDefault(t *testing.T) {
+	output := NewGoGenerator().Generate(goProtoTestSchema())
+	if strings.Contains(output, "ToProto") || strings.Contains(output, "protoPtr") {
+		t.Errorf("Expected no protobuf conversions without a proto package, got:\n%s", output)
+	}
+}
+
+func TestProtoGoName(t *testing.T) {
+	tests := map[string]string{
+		"id":         "Id",
+		"zip_code":   "ZipCode",
+		"createdAt":  "CreatedAt",
+		"field1name": "Field1Name",
+		"_private":   "XPrivate",
+	}
+	for name, want := range tests {
+		if got := protoGoName(name); got != want {
+			t.Errorf("protoGoName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGoGenerator_Extends(t *testing.T) {
+	output := NewGoGenerator().Generate(extendsTestSchema(t))
+
+	want := "type Admin struct {\n\tUser\n\tAuditable\n\tRole string `json:\"role\"`\n}"
+	if !strings.Contains(output, want) {
+		t.Errorf("Expected Admin to embed its base types and declare only its own fields, got:\n%s", output)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "types.go", output, 0); err != nil {
+		t.Errorf("Generated code does not parse: %v\n%s", err, output)
+	}
+}
+
+func TestGoGenerator_EmptyMethodTypes(t *testing.T) {
+	output := NewGoGenerator().Generate(emptyMethodTestSchema())
+
+	expected := []string{
+		"\tPing(ctx context.Context) error\n",
+		"\tReset(ctx context.Context, input *ResetRequest) error\n",
+		"\tGetStatus(ctx context.Context) (*Status, error)\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "types.go", output, 0); err != nil {
+		t.Errorf("Generated code does not parse: %v\n%s", err, output)
+	}
+}
+
+func TestGoGenerator_MethodPolicies(t *testing.T) {
+	schema := emptyMethodTestSchema()
+	methods := schema.Services[0].Methods
+	methods[0].Timeout = "500ms"
+	methods[2].Timeout = "1m30s"
+	methods[2].Idempotent = true
+	methods[2].RateLimit = &ast.RateLimit{Requests: 100, Per: "day"}
+
+	output := NewGoGenerator().Generate(schema)
+
+	expected := []string{
+		"\t\"time\"\n",
+		"var HealthServicePolicies = map[string]MethodPolicy{\n" +
+			"\t\"Ping\":      {Timeout: 500 * time.Millisecond},\n" +
+			"\t\"GetStatus\": {Timeout: 90 * time.Second, Idempotent: true, RateLimit: &MethodRateLimit{Requests: 100, Per: 24 * time.Hour}},\n}",
+		"func NewHealthServiceWithMiddleware(next HealthService, middleware MethodMiddleware) HealthService {",
+		"func (s *healthServiceWithMiddleware) Reset(ctx context.Context, input *ResetRequest) error {\n" +
+			"\ttyped := &HealthServiceResetCall{Input: input}\n" +
+			"\tcall := &HealthServiceCall{Method: HealthServiceReset, Policy: HealthServicePolicies[\"Reset\"], Reset: typed}\n" +
+			"\treturn s.run(ctx, call, func(ctx context.Context) error {\n" +
+			"\t\treturn s.next.Reset(ctx, input)\n\t})\n}",
+		"\t\tif output, err = s.next.GetStatus(ctx); err == nil {\n\t\t\ttyped.Output = output\n",
+		"type MethodMiddleware func(ctx context.Context, method string, policy MethodPolicy, call func(ctx context.Context) error) error",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "types.go", output, 0); err != nil {
+		t.Errorf("Generated code does not parse: %v\n%s", err, output)
+	}
+
+	if output := NewGoGenerator().Generate(emptyMethodTestSchema()); strings.Contains(output, "MethodPolicy") {
+		t.Error("Expected no policy types for services without policies")
+	}
+}
+
+func TestGoGenerator_MethodHooks(t *testing.T) {
+	schema := emptyMethodTestSchema()
+	methods := schema.Services[0].Methods
+	methods[0].Auth = &ast.Auth{}
+	methods[2].Auth = &ast.Auth{Scopes: []string{"status:read", "status:admin"}}
+
+	output := NewGoGenerator().Generate(schema)
+
+	expected := []string{
+		"\t\"Ping\":      {Auth: &MethodAuth{}},\n" +
+			"\t\"GetStatus\": {Auth: &MethodAuth{Scopes: []string{\"status:read\", \"status:admin\"}}},\n",
+		"type HealthServiceMethod string",
+		"\tHealthServiceGetStatus HealthServiceMethod = \"GetStatus\"\n",
+		"type HealthServiceCall struct {\n" +
+			"\tMethod    HealthServiceMethod\n" +
+			"\tPolicy    MethodPolicy\n" +
+			"\tReset     *HealthServiceResetCall\n" +
+			"\tGetStatus *HealthServiceGetStatusCall\n}",
+		"type HealthServiceResetCall struct {\n\tInput *ResetRequest // Request of the call\n}",
+		"type HealthServiceGetStatusCall struct {\n\tOutput *Status // Response of a successful call, once it returned\n}",
+		"type HealthServiceHooks struct {\n" +
+			"\tBefore []func(ctx context.Context, call *HealthServiceCall) error\n" +
+			"\tAfter  []func(ctx context.Context, call *HealthServiceCall, err error)\n}",
+		"func NewHealthServiceWithHooks(next HealthService, hooks HealthServiceHooks, middleware ...MethodMiddleware) HealthService {",
+		"\tcall := &HealthServiceCall{Method: HealthServicePing, Policy: HealthServicePolicies[\"Ping\"]}\n",
+		"func ChainMiddleware(middleware ...MethodMiddleware) MethodMiddleware {",
+		"\tScopes []string",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "types.go", output, 0); err != nil {
+		t.Errorf("Generated code does not parse: %v\n%s", err, output)
+	}
+}
+
+func TestGoDurationLiteral(t *testing.T) {
+	tests := map[time.Duration]string{
+		time.Second:             "time.Second",
+		90 * time.Second:        "90 * time.Second",
+		2 * time.Hour:           "2 * time.Hour",
+		1500 * time.Millisecond: "1500 * time.Millisecond",
+		7:                       "7 * time.Nanosecond",
+	}
+	for d, want := range tests {
+		if got := goDurationLiteral(d); got != want {
+			t.Errorf("goDurationLiteral(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func sensitiveTestSchema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "api",
+		Types: []*ast.Type{
+			{
+				Name:      "User",
+				Namespace: "api",
+				Fields: []*ast.Field{
+					{Name: "email", Type: &ast.FieldType{Name: "string"}, Number: 1, HasNumber: true, Sensitivity: "pii"},
+					{Name: "name", Type: &ast.FieldType{Name: "string"}, Number: 2, HasNumber: true},
+				},
+			},
+		},
+	}
+}
+
+func TestGoGenerator_SensitiveField(t *testing.T) {
+	output := NewGoGenerator().Generate(sensitiveTestSchema())
+
+	if !strings.Contains(output, "`json:\"email\" sensitive:\"pii\"`") {
+		t.Errorf("Expected a sensitive tag on email, got:\n%s", output)
+	}
+	if !strings.Contains(output, "`json:\"name\"`") {
+		t.Errorf("Expected name without a sensitive tag, got:\n%s", output)
+	}
+}
+
+func TestGoGenerator_BytesMapValues(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "files",
+		Types: []*ast.Type{{Name: "Bundle", Fields: []*ast.Field{
+			{Name: "files", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "bytes"}},
+		}}},
+	}
+
+	output := NewGoGenerator().Generate(schema)
+	if !strings.Contains(output, "Files map[string][]byte `json:\"files\"`") {
+		t.Errorf("Expected bytes map values to be []byte, got:\n%s", output)
+	}
+}
+
+func TestGoGenerator_MoneyHelpers(t *testing.T) {
+	output := NewGoGeneratorWithOptions(&GoOptions{ProtoPackage: "github.com/example/shop/pb;shoppb"}).Generate(moneyTestSchema())
+
+	expected := []string{
+		`"math/big"`,
+		`money "google.golang.org/genproto/googleapis/type/money"`,
+		"func ParseMoney(currencyCode, amount string) (Money, error) {",
+		"func (m Money) Add(other Money) (Money, error) {",
+		"func (m Money) Cmp(other Money) (int, error) {",
+		"func (m *Money) ToProto() *money.Money {",
+		"func MoneyFromProto(p *money.Money) *Money {",
+		"p.Discounts = make([]*money.Money, len(m.Discounts))",
+		"func (m *Product) ToProto() *shoppb.Product {",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain:\n%s\n\nGot:\n%s", want, output)
+		}
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "types.go", output, 0); err != nil {
+		t.Errorf("Generated code is not valid Go: %v\n%s", err, output)
+	}
+
+	// Types named Money in other namespaces get no helpers
+	schema := moneyTestSchema()
+	schema.Types[0].Namespace = "shop"
+	if output := NewGoGenerator().Generate(schema); strings.Contains(output, "ParseMoney") {
+		t.Errorf("Expected no money helpers for shop.Money, got:\n%s", output)
+	}
+}
@@ -0,0 +1,292 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// encodedUnion returns the union a field type refers to when the union sets a
+// JSON encoding with @json.union
+func (g *GoGenerator) encodedUnion(typeName string) *ast.Union {
+	if g.isExternal(typeName) {
+		return nil
+	}
+	union, ok := g.unions[g.cleanTypeName(typeName)]
+	if !ok || union.Encoding == nil {
+		return nil
+	}
+	return union
+}
+
+// generateUnionEncoding generates the MarshalJSON methods of the options of a
+// union and its Unmarshal function, in the JSON encoding of the union
+func (g *GoGenerator) generateUnionEncoding(union *ast.Union) string {
+	var sb strings.Builder
+	encoding := union.Encoding
+	g.imports["encoding/json"] = true
+	g.imports["fmt"] = true
+
+	for _, option := range union.Options {
+		name := g.cleanTypeName(option)
+		wrapper := union.Name + option
+		sb.WriteString(fmt.Sprintf("// MarshalJSON encodes the %s option of %s, %s.\n", name, union.Name, encoding.Strategy.Describe()))
+		sb.WriteString(fmt.Sprintf("func (x %s) MarshalJSON() ([]byte, error) {\n", wrapper))
+		switch encoding.Strategy {
+		case ast.UnionInternal:
+			g.taggedHelper = true
+			sb.WriteString(fmt.Sprintf("\treturn marshalInternallyTagged(%q, %q, x.Value)\n", encoding.TagProperty(), name))
+		case ast.UnionAdjacent:
+			sb.WriteString("\treturn json.Marshal(struct {\n")
+			sb.WriteString(fmt.Sprintf("\t\tTag     string `json:%q`\n", encoding.TagProperty()))
+			sb.WriteString(fmt.Sprintf("\t\tContent %s `json:%q`\n", g.messageType(option), encoding.ContentProperty()))
+			sb.WriteString(fmt.Sprintf("\t}{%q, x.Value})\n", name))
+		default:
+			sb.WriteString("\treturn json.Marshal(x.Value)\n")
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("// Unmarshal%[1]s decodes a %[1]s, %[2]s. null decodes to nil.\n", union.Name, encoding.Strategy.Describe()))
+	sb.WriteString(fmt.Sprintf("func Unmarshal%[1]s(data []byte) (%[1]s, error) {\n", union.Name))
+	sb.WriteString("\tif string(data) == \"null\" {\n\t\treturn nil, nil\n\t}\n")
+	if encoding.Strategy == ast.UnionUntagged {
+		g.untaggedHelper = true
+		g.imports["bytes"] = true
+		for _, option := range union.Options {
+			sb.WriteString(fmt.Sprintf("\tif value, err := decodeStrict[%s](data); err == nil {\n", g.messageType(option)))
+			sb.WriteString(fmt.Sprintf("\t\treturn %s%s{Value: value}, nil\n", union.Name, option))
+			sb.WriteString("\t}\n")
+		}
+		sb.WriteString(fmt.Sprintf("\treturn nil, fmt.Errorf(\"%s: the value matches none of the options\")\n", union.Name))
+		sb.WriteString("}\n")
+		return sb.String()
+	}
+
+	sb.WriteString("\tvar tagged struct {\n")
+	sb.WriteString(fmt.Sprintf("\t\tTag string `json:%q`\n", encoding.TagProperty()))
+	content := "data"
+	if encoding.Strategy == ast.UnionAdjacent {
+		sb.WriteString(fmt.Sprintf("\t\tContent json.RawMessage `json:%q`\n", encoding.ContentProperty()))
+		content = "tagged.Content"
+	}
+	sb.WriteString("\t}\n")
+	sb.WriteString("\tif err := json.Unmarshal(data, &tagged); err != nil {\n\t\treturn nil, err\n\t}\n")
+	sb.WriteString("\tswitch tagged.Tag {\n")
+	for _, option := range union.Options {
+		sb.WriteString(fmt.Sprintf("\tcase %q:\n", g.cleanTypeName(option)))
+		sb.WriteString(fmt.Sprintf("\t\tvar value %s\n", g.messageType(option)))
+		sb.WriteString(fmt.Sprintf("\t\tif err := json.Unmarshal(%s, &value); err != nil {\n\t\t\treturn nil, err\n\t\t}\n", content))
+		sb.WriteString(fmt.Sprintf("\t\treturn %s%s{Value: value}, nil\n", union.Name, option))
+	}
+	sb.WriteString("\tdefault:\n")
+	sb.WriteString(fmt.Sprintf("\t\treturn nil, fmt.Errorf(\"%s: unknown %s %%q\", tagged.Tag)\n", union.Name, encoding.TagProperty()))
+	sb.WriteString("\t}\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// generateUnionDecoding generates the Unmarshal function of a union without a
+// JSON encoding, which the service stubs decode messages of the union with. Its
+// options encode as {"value": ...}, so the value is matched against each option.
+func (g *GoGenerator) generateUnionDecoding(union *ast.Union) string {
+	var sb strings.Builder
+	g.untaggedHelper = true
+	g.imports["bytes"] = true
+	g.imports["encoding/json"] = true
+	g.imports["fmt"] = true
+
+	sb.WriteString(fmt.Sprintf("// Unmarshal%[1]s decodes a %[1]s from {\"value\": ...}, the first option\n", union.Name))
+	sb.WriteString("// the value matches. null decodes to nil.\n")
+	sb.WriteString(fmt.Sprintf("func Unmarshal%[1]s(data []byte) (%[1]s, error) {\n", union.Name))
+	sb.WriteString("\tif string(data) == \"null\" {\n\t\treturn nil, nil\n\t}\n")
+	sb.WriteString("\tvar wrapped struct {\n\t\tValue json.RawMessage `json:\"value\"`\n\t}\n")
+	sb.WriteString("\tif err := json.Unmarshal(data, &wrapped); err != nil {\n\t\treturn nil, err\n\t}\n")
+	for _, option := range union.Options {
+		sb.WriteString(fmt.Sprintf("\tif value, err := decodeStrict[%s](wrapped.Value); err == nil {\n", g.messageType(option)))
+		sb.WriteString(fmt.Sprintf("\t\treturn %s%s{Value: value}, nil\n", union.Name, option))
+		sb.WriteString("\t}\n")
+	}
+	sb.WriteString(fmt.Sprintf("\treturn nil, fmt.Errorf(\"%s: the value matches none of the options\")\n", union.Name))
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// isMethodMessage reports whether a union is the input or output of a service method
+func (g *GoGenerator) isMethodMessage(schema *ast.Schema, union *ast.Union) bool {
+	for _, service := range schema.Services {
+		for _, method := range service.Methods {
+			if g.messageUnion(schema, method.InputType) == union || g.messageUnion(schema, method.OutputType) == union {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// messageUnion returns the union a method input or output refers to, or nil when
+// it is not a union of this package. Messages of a union are decoded with its
+// Unmarshal function, since encoding/json cannot decode into an interface.
+func (g *GoGenerator) messageUnion(schema *ast.Schema, typeName string) *ast.Union {
+	if typeName == "" || g.isExternal(typeName) {
+		return nil
+	}
+	name := g.cleanTypeName(typeName)
+	for _, union := range schema.Unions {
+		if union.Name == name {
+			return union
+		}
+	}
+	return nil
+}
+
+// receiveMessage generates the statements of a service stub that receive a
+// message of type goType into a new variable named target, with recv given the
+// pointer to decode into. Messages of a union are received as raw JSON and
+// decoded with its Unmarshal function. failure runs when either step fails.
+func (g *GoGenerator) receiveMessage(schema *ast.Schema, typeName, goType, target, indent string, recv func(ptr string) string, failure string) string {
+	var sb strings.Builder
+	union := g.messageUnion(schema, typeName)
+	if union == nil {
+		sb.WriteString(fmt.Sprintf("%s%s := new(%s)\n", indent, target, goType))
+		sb.WriteString(fmt.Sprintf("%sif err := %s; err != nil {\n%s\t%s\n%s}\n", indent, recv(target), indent, failure, indent))
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("%sraw := new(json.RawMessage)\n", indent))
+	sb.WriteString(fmt.Sprintf("%sif err := %s; err != nil {\n%s\t%s\n%s}\n", indent, recv("raw"), indent, failure, indent))
+	sb.WriteString(fmt.Sprintf("%svalue, err := Unmarshal%s(*raw)\n", indent, union.Name))
+	sb.WriteString(fmt.Sprintf("%sif err != nil {\n%s\t%s\n%s}\n", indent, indent, failure, indent))
+	sb.WriteString(fmt.Sprintf("%s%s := &value\n", indent, target))
+	return sb.String()
+}
+
+// generateUnionFieldsDecoding generates the UnmarshalJSON method of a type with
+// fields of unions that set a JSON encoding, which decodes them with the
+// Unmarshal functions of the unions. Unions in nested lists and maps are left
+// to encoding/json.
+func (g *GoGenerator) generateUnionFieldsDecoding(typ *ast.Type) string {
+	type unionField struct {
+		name, jsonName, union string
+		list                  bool
+		mapKey                string
+	}
+	var fields []unionField
+	for _, field := range typ.Fields {
+		if field.InheritedFrom != "" || field.Type == nil {
+			continue
+		}
+		jsonName := strings.Split(g.getJSONTag(field), ",")[0]
+		switch {
+		case field.Type.IsMap:
+			if field.Type.MapValue != "" {
+				if union := g.encodedUnion(field.Type.MapValue); union != nil {
+					fields = append(fields, unionField{name: g.goFieldName(field), jsonName: jsonName, union: union.Name, mapKey: g.mapScalarTypeToGo(field.Type.MapKey)})
+				}
+			}
+		case field.Type.IsArray:
+			if union := g.encodedUnion(field.Type.Name); union != nil {
+				fields = append(fields, unionField{name: g.goFieldName(field), jsonName: jsonName, union: union.Name, list: true})
+			}
+		default:
+			if union := g.encodedUnion(field.Type.Name); union != nil && !field.JSONNullable && !g.recursive[field] {
+				fields = append(fields, unionField{name: g.goFieldName(field), jsonName: jsonName, union: union.Name})
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	g.imports["encoding/json"] = true
+	g.imports["fmt"] = true
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("// UnmarshalJSON decodes a %s, with the union values of its fields.\n", typ.Name))
+	sb.WriteString(fmt.Sprintf("func (x *%s) UnmarshalJSON(data []byte) error {\n", typ.Name))
+	sb.WriteString(fmt.Sprintf("\ttype plain %s\n", typ.Name))
+	sb.WriteString("\tvar raw struct {\n\t\tplain\n")
+	for _, field := range fields {
+		rawType := "json.RawMessage"
+		if field.list {
+			rawType = "[]json.RawMessage"
+		} else if field.mapKey != "" {
+			rawType = fmt.Sprintf("map[%s]json.RawMessage", field.mapKey)
+		}
+		sb.WriteString(fmt.Sprintf("\t\t%s %s `json:%q`\n", field.name, rawType, field.jsonName))
+	}
+	sb.WriteString("\t}\n")
+	sb.WriteString("\tif err := json.Unmarshal(data, &raw); err != nil {\n\t\treturn err\n\t}\n")
+	sb.WriteString(fmt.Sprintf("\t*x = %s(raw.plain)\n", typ.Name))
+	for _, field := range fields {
+		switch {
+		case field.list:
+			sb.WriteString(fmt.Sprintf("\tif raw.%s != nil {\n", field.name))
+			sb.WriteString(fmt.Sprintf("\t\tx.%s = make([]%s, len(raw.%s))\n", field.name, field.union, field.name))
+			sb.WriteString(fmt.Sprintf("\t\tfor i, item := range raw.%s {\n", field.name))
+			sb.WriteString(fmt.Sprintf("\t\t\tvalue, err := Unmarshal%s(item)\n", field.union))
+			sb.WriteString(fmt.Sprintf("\t\t\tif err != nil {\n\t\t\t\treturn fmt.Errorf(\"%s[%%d]: %%w\", i, err)\n\t\t\t}\n", field.jsonName))
+			sb.WriteString(fmt.Sprintf("\t\t\tx.%s[i] = value\n", field.name))
+			sb.WriteString("\t\t}\n\t}\n")
+		case field.mapKey != "":
+			sb.WriteString(fmt.Sprintf("\tif raw.%s != nil {\n", field.name))
+			sb.WriteString(fmt.Sprintf("\t\tx.%s = make(map[%s]%s, len(raw.%s))\n", field.name, field.mapKey, field.union, field.name))
+			sb.WriteString(fmt.Sprintf("\t\tfor key, item := range raw.%s {\n", field.name))
+			sb.WriteString(fmt.Sprintf("\t\t\tvalue, err := Unmarshal%s(item)\n", field.union))
+			sb.WriteString(fmt.Sprintf("\t\t\tif err != nil {\n\t\t\t\treturn fmt.Errorf(\"%s[%%v]: %%w\", key, err)\n\t\t\t}\n", field.jsonName))
+			sb.WriteString(fmt.Sprintf("\t\t\tx.%s[key] = value\n", field.name))
+			sb.WriteString("\t\t}\n\t}\n")
+		default:
+			sb.WriteString(fmt.Sprintf("\tif raw.%s != nil {\n", field.name))
+			sb.WriteString(fmt.Sprintf("\t\tvalue, err := Unmarshal%s(raw.%s)\n", field.union, field.name))
+			sb.WriteString(fmt.Sprintf("\t\tif err != nil {\n\t\t\treturn fmt.Errorf(\"%s: %%w\", err)\n\t\t}\n", field.jsonName))
+			sb.WriteString(fmt.Sprintf("\t\tx.%s = value\n", field.name))
+			sb.WriteString("\t}\n")
+		}
+	}
+	sb.WriteString("\treturn nil\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// generateUnionHelpers generates the helpers of the union encodings, if used
+func (g *GoGenerator) generateUnionHelpers() string {
+	var sb strings.Builder
+	if g.taggedHelper {
+		sb.WriteString(`// marshalInternallyTagged encodes value, which must encode as a JSON object,
+// with the tag property added first.
+func marshalInternallyTagged(property, tag string, value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2 || data[0] != '{' {
+		return nil, fmt.Errorf("%s: internally tagged union options must encode as JSON objects", tag)
+	}
+	tagged, err := json.Marshal(map[string]string{property: tag})
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "{}" {
+		return tagged, nil
+	}
+	return append(append(tagged[:len(tagged)-1], ','), data[1:]...), nil
+}
+`)
+	}
+	if g.untaggedHelper {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(`// decodeStrict decodes data as a T, rejecting unknown fields, to match the
+// options of untagged unions.
+func decodeStrict[T any](data []byte) (T, error) {
+	var value T
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&value)
+	return value, err
+}
+`)
+	}
+	return sb.String()
+}
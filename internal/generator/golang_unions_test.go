@@ -0,0 +1,142 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func encodedUnionSchema(encoding *ast.UnionEncoding) *ast.Schema {
+	return &ast.Schema{
+		Namespace: "shapes",
+		Types: []*ast.Type{
+			{Name: "Circle", Fields: []*ast.Field{{Name: "radius", Type: &ast.FieldType{Name: "float64", IsBuiltin: true}, Required: true}}},
+			{Name: "Square", Fields: []*ast.Field{{Name: "side", Type: &ast.FieldType{Name: "float64", IsBuiltin: true}, Required: true}}},
+			{Name: "Drawing", Fields: []*ast.Field{
+				{Name: "main", Type: &ast.FieldType{Name: "Shape"}, Required: true},
+				{Name: "layers", Type: &ast.FieldType{Name: "Shape", IsArray: true}},
+				{Name: "named", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "Shape"}},
+			}},
+		},
+		Unions: []*ast.Union{{Name: "Shape", Options: []string{"Circle", "Square"}, Encoding: encoding}},
+	}
+}
+
+func TestGoGenerator_UnionEncoding(t *testing.T) {
+	tests := []struct {
+		encoding *ast.UnionEncoding
+		want     []string
+	}{
+		{
+			&ast.UnionEncoding{Strategy: ast.UnionInternal, Tag: "kind"},
+			[]string{
+				`return marshalInternallyTagged("kind", "Circle", x.Value)`,
+				"Tag string `json:\"kind\"`",
+				"json.Unmarshal(data, &value)",
+				"func marshalInternallyTagged(",
+			},
+		},
+		{
+			&ast.UnionEncoding{Strategy: ast.UnionAdjacent, Content: "data"},
+			[]string{
+				"Content Square `json:\"data\"`",
+				`}{"Square", x.Value})`,
+				"Content json.RawMessage `json:\"data\"`",
+				"json.Unmarshal(tagged.Content, &value)",
+			},
+		},
+		{
+			&ast.UnionEncoding{Strategy: ast.UnionUntagged},
+			[]string{
+				"return json.Marshal(x.Value)",
+				"if value, err := decodeStrict[Circle](data); err == nil {",
+				"func decodeStrict[T any](data []byte) (T, error) {",
+				`"bytes"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		output := NewGoGenerator().Generate(encodedUnionSchema(tt.encoding))
+		want := append(tt.want,
+			"func UnmarshalShape(data []byte) (Shape, error) {",
+			"func (x *Drawing) UnmarshalJSON(data []byte) error {",
+			"Main json.RawMessage `json:\"main\"`",
+			"Layers []json.RawMessage `json:\"layers\"`",
+			"Named map[string]json.RawMessage `json:\"named\"`",
+		)
+		for _, s := range want {
+			if !strings.Contains(output, s) {
+				t.Errorf("%s: expected output to contain %q, got:\n%s", tt.encoding.Strategy, s, output)
+			}
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "types.go", output, parser.AllErrors); err != nil {
+			t.Errorf("%s: generated code does not parse: %v", tt.encoding.Strategy, err)
+		}
+	}
+}
+
+func TestGoGenerator_UnionWithoutEncoding(t *testing.T) {
+	output := NewGoGenerator().Generate(encodedUnionSchema(nil))
+	for _, s := range []string{"MarshalJSON", "UnmarshalShape", "UnmarshalJSON", "marshalInternallyTagged", "decodeStrict"} {
+		if strings.Contains(output, s) {
+			t.Errorf("Expected no %s without @json.union, got:\n%s", s, output)
+		}
+	}
+}
+
+func TestGoGenerator_UnionMessageWithoutEncoding(t *testing.T) {
+	schema := encodedUnionSchema(nil)
+	schema.Services = []*ast.Service{{Name: "DrawingService", Methods: []*ast.Method{{Name: "GetShape", OutputType: "Shape"}}}}
+	output := NewGoGenerator().Generate(schema)
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "types.go", output, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, output)
+	}
+	expected := []string{
+		"func UnmarshalShape(data []byte) (Shape, error) {",
+		"\tif err := json.Unmarshal(data, &wrapped); err != nil {",
+		"\tif value, err := decodeStrict[Circle](wrapped.Value); err == nil {\n\t\treturn ShapeCircle{Value: value}, nil\n\t}\n",
+		"func decodeStrict[T any](data []byte) (T, error) {",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+	if strings.Contains(output, "MarshalJSON") {
+		t.Errorf("Expected the encoding of the options to stay the same, got:\n%s", output)
+	}
+}
+
+func TestOpenAPIGenerator_UnionEncoding(t *testing.T) {
+	gen := NewOpenAPIGenerator()
+	union := &ast.Union{Name: "Shape", Options: []string{"Circle", "Square"}}
+
+	union.Encoding = &ast.UnionEncoding{Strategy: ast.UnionInternal, Tag: "kind"}
+	schema := gen.generateUnionSchema(union)
+	if schema.Discriminator == nil || schema.Discriminator.PropertyName != "kind" {
+		t.Errorf("Expected a discriminator on kind, got %+v", schema.Discriminator)
+	}
+
+	union.Encoding = &ast.UnionEncoding{Strategy: ast.UnionUntagged}
+	schema = gen.generateUnionSchema(union)
+	if schema.Discriminator != nil || len(schema.OneOf) != 2 || schema.OneOf[0].Ref != "#/components/schemas/Circle" {
+		t.Errorf("Expected plain references without a discriminator, got %+v", schema)
+	}
+
+	union.Encoding = &ast.UnionEncoding{Strategy: ast.UnionAdjacent}
+	schema = gen.generateUnionSchema(union)
+	if schema.Discriminator != nil || len(schema.OneOf) != 2 {
+		t.Fatalf("Expected an object per option without a discriminator, got %+v", schema)
+	}
+	square := schema.OneOf[1]
+	if square.Type != "object" || square.Properties["type"].Enum[0] != "Square" ||
+		square.Properties["value"].Ref != "#/components/schemas/Square" ||
+		strings.Join(square.Required, ",") != "type,value" {
+		t.Errorf("Unexpected adjacently tagged option: %+v", square)
+	}
+}
@@ -0,0 +1,453 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// goFormatPatterns maps @validate formats checked with a regular expression to the pattern used
+var goFormatPatterns = map[string]string{
+	"email": `^[^@\s]+@[^@\s]+\.[^@\s]+$`,
+	"uuid":  `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
+}
+
+// collectValidatedTypes finds the types that need a Validate method: types with
+// required fields or @validate rules, and types containing such types
+func (g *GoGenerator) collectValidatedTypes(schema *ast.Schema) map[string]bool {
+	validated := make(map[string]bool)
+	for _, typ := range schema.Types {
+		if g.isExternal(typ.Name) {
+			continue
+		}
+		for _, field := range typ.Fields {
+			if g.hasFieldRules(field) {
+				validated[typ.Name] = true
+				break
+			}
+		}
+	}
+
+	// Propagate to types that embed validated types
+	for changed := true; changed; {
+		changed = false
+		for _, typ := range schema.Types {
+			if validated[typ.Name] || g.isExternal(typ.Name) {
+				continue
+			}
+			for _, field := range typ.Fields {
+				if validated[g.validatedElementType(field.Type, validated)] {
+					validated[typ.Name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	return validated
+}
+
+// hasFieldRules reports whether a field has checks of its own
+func (g *GoGenerator) hasFieldRules(field *ast.Field) bool {
+	if g.isRequired(field) && g.requiredCondition(field, "v") != "" {
+		return true
+	}
+	// External types are checked by their own code
+	rules := field.Validation
+	if rules == nil || g.isExternal(field.Type.Name) {
+		return false
+	}
+	return rules.MinLength != nil || rules.MaxLength != nil || rules.Pattern != "" ||
+		g.isSupportedFormat(rules.Format) || len(rules.Enum) > 0 ||
+		rules.Min != nil || rules.Max != nil || rules.ExclusiveMin != nil || rules.ExclusiveMax != nil ||
+		rules.MultipleOf != nil || rules.MinItems != nil || rules.MaxItems != nil || rules.UniqueItems
+}
+
+// isRequired reports whether a field must be present
+func (g *GoGenerator) isRequired(field *ast.Field) bool {
+	return field.Presence() == ast.PresenceRequired
+}
+
+// validatedElementType returns the struct type name whose Validate method applies to a field, if any
+func (g *GoGenerator) validatedElementType(fieldType *ast.FieldType, validated map[string]bool) string {
+	name := fieldType.Name
+	if fieldType.IsMap {
+		valueType := fieldType.GetMapValueType()
+		if valueType == nil || valueType.IsArray || valueType.IsMap {
+			return ""
+		}
+		name = valueType.Name
+	}
+	name = g.cleanTypeName(name)
+	if validated[name] {
+		return name
+	}
+	return ""
+}
+
+// generateValidate generates the Validate method of a struct type
+func (g *GoGenerator) generateValidate(typ *ast.Type) string {
+	var checks strings.Builder
+	var vars strings.Builder
+
+	for _, field := range typ.Fields {
+		checks.WriteString(g.generateFieldValidation(typ, field, &vars))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("// Validate checks %s against the constraints declared in the schema.\n", typ.Name))
+	sb.WriteString(fmt.Sprintf("func (m *%s) Validate() error {\n", typ.Name))
+	sb.WriteString("\tvar errs []error\n")
+	sb.WriteString(checks.String())
+	sb.WriteString("\treturn errors.Join(errs...)\n")
+	sb.WriteString("}\n")
+	g.imports["errors"] = true
+
+	if vars.Len() > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(vars.String())
+	}
+
+	return sb.String()
+}
+
+// generateFieldValidation generates the checks of a single field
+func (g *GoGenerator) generateFieldValidation(typ *ast.Type, field *ast.Field, vars *strings.Builder) string {
+	var sb strings.Builder
+
+	name := field.Name
+	if field.JSONName != "" {
+		name = field.JSONName
+	}
+	access := "m." + g.goFieldName(field)
+
+	goType := g.goFieldType(field)
+	isPointer := strings.HasPrefix(goType, "*")
+	isNull := strings.HasPrefix(goType, "Null[")
+
+	if g.isRequired(field) {
+		sb.WriteString(g.requiredCheck(field, access))
+	}
+
+	// Checks on the value itself; pointers are only checked when set
+	value := access
+	switch {
+	case isPointer:
+		value = "*" + access
+	case isNull:
+		value = access + ".Value"
+	}
+
+	var body strings.Builder
+	rules := field.Validation
+	fieldType := field.Type
+
+	switch {
+	case fieldType.IsArray:
+		if rules != nil {
+			body.WriteString(g.arrayChecks(name, value, fieldType, rules))
+		}
+		if elem := g.validatedElementType(fieldType, g.validated); elem != "" && !fieldType.IsMap {
+			g.imports["fmt"] = true
+			g.nestedValidation = true
+			element := access + "[i]"
+			if isPointer {
+				element = "(*" + access + ")[i]"
+			}
+			body.WriteString(fmt.Sprintf("\tfor i := range %s {\n", value))
+			body.WriteString(fmt.Sprintf("\t\tif err := %s.Validate(); err != nil {\n", element))
+			body.WriteString(fmt.Sprintf("\t\t\terrs = append(errs, validationErrors(fmt.Sprintf(\"%s[%%d]\", i), err)...)\n", name))
+			body.WriteString("\t\t}\n")
+			body.WriteString("\t}\n")
+		}
+	case fieldType.IsMap:
+		if g.validatedElementType(fieldType, g.validated) != "" {
+			g.imports["fmt"] = true
+			g.nestedValidation = true
+			body.WriteString(fmt.Sprintf("\tfor key, item := range %s {\n", value))
+			body.WriteString("\t\tif err := item.Validate(); err != nil {\n")
+			body.WriteString(fmt.Sprintf("\t\t\terrs = append(errs, validationErrors(fmt.Sprintf(\"%s[%%v]\", key), err)...)\n", name))
+			body.WriteString("\t\t}\n")
+			body.WriteString("\t}\n")
+		}
+	case g.isExternal(fieldType.Name):
+		// External types are checked by their own code
+	case fieldType.Name == "string":
+		if rules != nil {
+			body.WriteString(g.stringChecks(typ, field, name, value, rules, vars))
+		}
+	case g.isNumericType(fieldType.Name):
+		if rules != nil {
+			body.WriteString(g.numericChecks(name, value, rules))
+		}
+	default:
+		if g.validatedElementType(fieldType, g.validated) != "" {
+			g.imports["fmt"] = true
+			g.nestedValidation = true
+			body.WriteString(fmt.Sprintf("\tif err := %s.Validate(); err != nil {\n", access))
+			body.WriteString(fmt.Sprintf("\t\terrs = append(errs, validationErrors(%s, err)...)\n", strconv.Quote(name)))
+			body.WriteString("\t}\n")
+		}
+	}
+
+	if body.Len() == 0 {
+		return sb.String()
+	}
+
+	// Optional values are only checked when set; Go has no separate "unset" state for plain scalars
+	guard := ""
+	switch {
+	case isPointer:
+		guard = access + " != nil"
+	case isNull:
+		guard = access + ".Valid"
+	case fieldType.Optional && !fieldType.IsArray && !fieldType.IsMap && fieldType.Name == "string":
+		guard = access + ` != ""`
+	case fieldType.Optional && !fieldType.IsArray && !fieldType.IsMap && g.isNumericType(fieldType.Name):
+		guard = access + " != 0"
+	}
+
+	if guard == "" {
+		sb.WriteString(body.String())
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("\tif %s {\n", guard))
+	for _, line := range strings.SplitAfter(body.String(), "\n") {
+		if line != "" {
+			sb.WriteString("\t" + line)
+		}
+	}
+	sb.WriteString("\t}\n")
+	return sb.String()
+}
+
+// requiredCheck generates the presence check of a required field
+func (g *GoGenerator) requiredCheck(field *ast.Field, access string) string {
+	condition := g.requiredCondition(field, access)
+	if condition == "" {
+		return ""
+	}
+
+	name := field.Name
+	if field.JSONName != "" {
+		name = field.JSONName
+	}
+	return g.check(condition, name+": is required")
+}
+
+// requiredCondition returns the condition under which a required field is missing,
+// or "" when Go cannot tell a missing value from a zero value
+func (g *GoGenerator) requiredCondition(field *ast.Field, access string) string {
+	goType := g.goFieldType(field)
+	switch {
+	case strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map["):
+		return access + " == nil"
+	case goType == "string":
+		return access + ` == ""`
+	case goType == "time.Time":
+		return access + ".IsZero()"
+	}
+	return ""
+}
+
+// stringChecks generates the length, pattern, format, and enum checks of a string value
+func (g *GoGenerator) stringChecks(typ *ast.Type, field *ast.Field, name, value string, rules *ast.ValidationRules, vars *strings.Builder) string {
+	var sb strings.Builder
+
+	if rules.MinLength != nil {
+		g.imports["unicode/utf8"] = true
+		sb.WriteString(g.check(fmt.Sprintf("utf8.RuneCountInString(%s) < %d", value, *rules.MinLength),
+			fmt.Sprintf("%s: length must be at least %d", name, *rules.MinLength)))
+	}
+	if rules.MaxLength != nil {
+		g.imports["unicode/utf8"] = true
+		sb.WriteString(g.check(fmt.Sprintf("utf8.RuneCountInString(%s) > %d", value, *rules.MaxLength),
+			fmt.Sprintf("%s: length must be at most %d", name, *rules.MaxLength)))
+	}
+	if rules.Pattern != "" {
+		g.imports["regexp"] = true
+		varName := strings.ToLower(typ.Name[:1]) + typ.Name[1:] + g.goFieldName(field) + "Pattern"
+		vars.WriteString(fmt.Sprintf("var %s = regexp.MustCompile(%s)\n", varName, strconv.Quote(rules.Pattern)))
+		sb.WriteString(g.check(fmt.Sprintf("!%s.MatchString(%s)", varName, value),
+			fmt.Sprintf("%s: must match pattern %s", name, rules.Pattern)))
+	}
+	sb.WriteString(g.formatCheck(name, rules.Format, value))
+	if len(rules.Enum) > 0 {
+		quoted := make([]string, len(rules.Enum))
+		for i, allowed := range rules.Enum {
+			quoted[i] = strconv.Quote(allowed)
+		}
+		g.imports["errors"] = true
+		sb.WriteString(fmt.Sprintf("\tswitch %s {\n", value))
+		sb.WriteString(fmt.Sprintf("\tcase %s:\n", strings.Join(quoted, ", ")))
+		sb.WriteString("\tdefault:\n")
+		sb.WriteString(fmt.Sprintf("\t\terrs = append(errs, errors.New(%s))\n",
+			strconv.Quote(fmt.Sprintf("%s: must be one of %s", name, strings.Join(rules.Enum, ", ")))))
+		sb.WriteString("\t}\n")
+	}
+
+	return sb.String()
+}
+
+// isSupportedFormat reports whether a @validate format is checked in generated Go code
+func (g *GoGenerator) isSupportedFormat(format string) bool {
+	switch format {
+	case "email", "uuid", "uri", "url", "date-time", "date", "ipv4", "ipv6":
+		return true
+	}
+	return false
+}
+
+// formatCheck generates the check of a string value against a @validate format
+func (g *GoGenerator) formatCheck(name, format, value string) string {
+	message := strconv.Quote(fmt.Sprintf("%s: must be a valid %s", name, format))
+
+	if _, ok := goFormatPatterns[format]; ok {
+		g.imports["regexp"] = true
+		g.formatPatterns[format] = true
+		return g.check(fmt.Sprintf("!%sPattern.MatchString(%s)", format, value), fmt.Sprintf("%s: must be a valid %s", name, format))
+	}
+
+	var statement string
+	switch format {
+	case "uri", "url":
+		g.imports["net/url"] = true
+		statement = fmt.Sprintf("_, err := url.ParseRequestURI(%s); err != nil", value)
+	case "date-time":
+		g.imports["time"] = true
+		statement = fmt.Sprintf("_, err := time.Parse(time.RFC3339, %s); err != nil", value)
+	case "date":
+		g.imports["time"] = true
+		statement = fmt.Sprintf("_, err := time.Parse(\"2006-01-02\", %s); err != nil", value)
+	case "ipv4":
+		g.imports["net"] = true
+		statement = fmt.Sprintf("ip := net.ParseIP(%s); ip == nil || ip.To4() == nil", value)
+	case "ipv6":
+		g.imports["net"] = true
+		statement = fmt.Sprintf("ip := net.ParseIP(%s); ip == nil || ip.To4() != nil", value)
+	default:
+		return ""
+	}
+
+	g.imports["errors"] = true
+	return fmt.Sprintf("\tif %s {\n\t\terrs = append(errs, errors.New(%s))\n\t}\n", statement, message)
+}
+
+// numericChecks generates the range and multiple-of checks of a numeric value
+func (g *GoGenerator) numericChecks(name, value string, rules *ast.ValidationRules) string {
+	var sb strings.Builder
+	bound := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+
+	if rules.Min != nil {
+		sb.WriteString(g.check(fmt.Sprintf("float64(%s) < %s", value, bound(*rules.Min)),
+			fmt.Sprintf("%s: must be at least %s", name, bound(*rules.Min))))
+	}
+	if rules.Max != nil {
+		sb.WriteString(g.check(fmt.Sprintf("float64(%s) > %s", value, bound(*rules.Max)),
+			fmt.Sprintf("%s: must be at most %s", name, bound(*rules.Max))))
+	}
+	if rules.ExclusiveMin != nil {
+		sb.WriteString(g.check(fmt.Sprintf("float64(%s) <= %s", value, bound(*rules.ExclusiveMin)),
+			fmt.Sprintf("%s: must be greater than %s", name, bound(*rules.ExclusiveMin))))
+	}
+	if rules.ExclusiveMax != nil {
+		sb.WriteString(g.check(fmt.Sprintf("float64(%s) >= %s", value, bound(*rules.ExclusiveMax)),
+			fmt.Sprintf("%s: must be less than %s", name, bound(*rules.ExclusiveMax))))
+	}
+	if rules.MultipleOf != nil {
+		g.imports["math"] = true
+		sb.WriteString(g.check(fmt.Sprintf("math.Mod(float64(%s), %s) != 0", value, bound(*rules.MultipleOf)),
+			fmt.Sprintf("%s: must be a multiple of %s", name, bound(*rules.MultipleOf))))
+	}
+
+	return sb.String()
+}
+
+// arrayChecks generates the item count and uniqueness checks of a slice value
+func (g *GoGenerator) arrayChecks(name, value string, fieldType *ast.FieldType, rules *ast.ValidationRules) string {
+	var sb strings.Builder
+
+	if rules.MinItems != nil {
+		sb.WriteString(g.check(fmt.Sprintf("len(%s) < %d", value, *rules.MinItems),
+			fmt.Sprintf("%s: must have at least %d items", name, *rules.MinItems)))
+	}
+	if rules.MaxItems != nil {
+		sb.WriteString(g.check(fmt.Sprintf("len(%s) > %d", value, *rules.MaxItems),
+			fmt.Sprintf("%s: must have at most %d items", name, *rules.MaxItems)))
+	}
+	if rules.UniqueItems && !fieldType.IsMap && (g.isComparableType(fieldType.Name)) {
+		g.imports["errors"] = true
+		elemType := g.mapScalarTypeToGo(fieldType.Name)
+		if elem := g.int64ElementType(&ast.FieldType{Name: fieldType.Name}); elem != "" {
+			elemType = elem
+		}
+		sb.WriteString("\t{\n")
+		sb.WriteString(fmt.Sprintf("\t\tseen := make(map[%s]bool, len(%s))\n", elemType, value))
+		sb.WriteString(fmt.Sprintf("\t\tfor _, item := range %s {\n", value))
+		sb.WriteString("\t\t\tif seen[item] {\n")
+		sb.WriteString(fmt.Sprintf("\t\t\t\terrs = append(errs, errors.New(%s))\n", strconv.Quote(name+": items must be unique")))
+		sb.WriteString("\t\t\t\tbreak\n")
+		sb.WriteString("\t\t\t}\n")
+		sb.WriteString("\t\t\tseen[item] = true\n")
+		sb.WriteString("\t\t}\n")
+		sb.WriteString("\t}\n")
+	}
+
+	return sb.String()
+}
+
+// check generates a condition that records an error message when true
+func (g *GoGenerator) check(condition, message string) string {
+	g.imports["errors"] = true
+	return fmt.Sprintf("\tif %s {\n\t\terrs = append(errs, errors.New(%s))\n\t}\n", condition, strconv.Quote(message))
+}
+
+// isNumericType checks if a type is a Go integer or floating-point type
+func (g *GoGenerator) isNumericType(typeName string) bool {
+	switch typeName {
+	case "int32", "int64", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return true
+	}
+	return false
+}
+
+// isComparableType checks if values of a type can be used as map keys
+func (g *GoGenerator) isComparableType(typeName string) bool {
+	return (g.isPrimitiveType(typeName) && typeName != "bytes") || g.enums[g.cleanTypeName(typeName)]
+}
+
+// generateFormatPatterns declares the regular expressions used by format checks
+func (g *GoGenerator) generateFormatPatterns() string {
+	var sb strings.Builder
+	for _, format := range []string{"email", "uuid"} {
+		if g.formatPatterns[format] {
+			sb.WriteString(fmt.Sprintf("var %sPattern = regexp.MustCompile(%s)\n", format, strconv.Quote(goFormatPatterns[format])))
+		}
+	}
+	return sb.String()
+}
+
+// generateValidationHelper declares the helper that prefixes errors of nested Validate calls with the field path
+func (g *GoGenerator) generateValidationHelper() string {
+	if !g.nestedValidation {
+		return ""
+	}
+	return `// validationErrors prefixes every error joined in err with the path of the invalid field.
+func validationErrors(path string, err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, validationErrors(path, e)...)
+		}
+		return errs
+	}
+	return []error{fmt.Errorf("%s: %w", path, err)}
+}
+`
+}
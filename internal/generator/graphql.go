@@ -2,37 +2,258 @@ package generator
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/rasmartins/typemux/internal/ast"
 )
 
+// GraphQLOptions configures the GraphQL generator.
+type GraphQLOptions struct {
+	// ScalarMappings maps TypeMUX builtin types to GraphQL custom scalars
+	// (e.g., "timestamp": "DateTime", "int64": "BigInt"). A scalar declaration
+	// is emitted for every mapped scalar used by the schema.
+	ScalarMappings map[string]string
+
+	// InputSuffix is appended to the name of input variants of types
+	// (default: "Input").
+	InputSuffix string
+
+	// SuffixAllInputs appends InputSuffix to every input type, not only to
+	// types that are also used as outputs.
+	SuffixAllInputs bool
+
+	// Metadata is stamped into the schema header comment when set.
+	Metadata *Metadata
+
+	// Relay implements the object identification of the Relay server
+	// specification: output types with an id field implement a Node interface
+	// and type it as ID!, and Query gains node(id: ID!): Node.
+	Relay bool
+
+	// TypePrefix is prepended to the name of every generated type, input,
+	// enum, union, and interface (e.g., "Billing_" turns User into
+	// Billing_User), so the schema can be stitched into a gateway schema
+	// without collisions. Custom scalars and the Relay Node interface are
+	// shared and keep their names.
+	TypePrefix string
+
+	// SkipRootTypes omits the Query, Mutation, and Subscription types, for
+	// schemas merged into a gateway that declares its own root types.
+	SkipRootTypes bool
+
+	// MapScalar renders maps as this scalar (e.g., "JSON") instead of lists of
+	// key-value entry types. A scalar declaration is emitted when a map uses it.
+	// Fields override it with @graphql.map(entries) and @graphql.map(json).
+	MapScalar string
+
+	// MapEntrySuffix is appended to the names of the key-value entry types of
+	// maps, such as StringIntEntry (default: "Entry"). Fields name their entry
+	// type with @graphql.map(entry="LabelEntry").
+	MapEntrySuffix string
+
+	// Int64 selects the scalar of int64 and uint64 fields: Int by default,
+	// String, or a custom scalar (BigInt unless ScalarMappings maps them).
+	Int64 Int64Encoding
+}
+
 // GraphQLGenerator generates GraphQL schema definitions from TypeMUX schemas.
-type GraphQLGenerator struct{}
+type GraphQLGenerator struct {
+	opts       GraphQLOptions
+	inputNames map[string]string   // Type name -> input type name for the current schema
+	interfaces map[string]string   // Base type name -> interface name for the current schema
+	implements map[string][]string // Type name -> interfaces of the types it extends
+	mixins     map[string]bool     // Base types emitted only as an interface
+	results    map[string]bool     // Unions only returned by methods, which have no input
+	nodes      map[string]bool     // Types that implement the Relay Node interface
+	flagEnums  map[string]bool     // Flags enums, whose fields are Int bitmasks
+}
 
 // NewGraphQLGenerator creates a new GraphQL schema generator.
 func NewGraphQLGenerator() *GraphQLGenerator {
 	return &GraphQLGenerator{}
 }
 
+// NewGraphQLGeneratorWithOptions creates a new GraphQL schema generator with the given options.
+func NewGraphQLGeneratorWithOptions(opts *GraphQLOptions) *GraphQLGenerator {
+	g := &GraphQLGenerator{}
+	if opts != nil {
+		g.opts = *opts
+	}
+	return g
+}
+
+// prefixed returns the GraphQL name of a declaration, with the configured type prefix
+func (g *GraphQLGenerator) prefixed(name string) string {
+	return g.opts.TypePrefix + name
+}
+
+// inputSuffix returns the suffix used for input type names
+func (g *GraphQLGenerator) inputSuffix() string {
+	if g.opts.InputSuffix == "" {
+		return "Input"
+	}
+	return g.opts.InputSuffix
+}
+
+// needsInputSuffix reports whether the input variant of a type gets the input suffix
+func (g *GraphQLGenerator) needsInputSuffix(typeName string, typeUsage map[string]string) bool {
+	return g.opts.SuffixAllInputs || typeUsage[typeName] == "both"
+}
+
+// inputTypeName returns the GraphQL name of the input variant of a type
+func (g *GraphQLGenerator) inputTypeName(typeName string, typeUsage map[string]string) string {
+	if name, ok := g.inputNames[typeName]; ok {
+		return name
+	}
+	if g.needsInputSuffix(typeName, typeUsage) {
+		return g.prefixed(typeName) + g.inputSuffix()
+	}
+	return g.prefixed(typeName)
+}
+
+// buildInputNames computes the input type name of every type used as an input
+func (g *GraphQLGenerator) buildInputNames(schema *ast.Schema, typeUsage map[string]string) map[string]string {
+	names := make(map[string]string)
+	for _, typ := range schema.Types {
+		usage := typeUsage[typ.Name]
+		if usage != "input" && usage != "both" {
+			continue
+		}
+		name := typ.Name
+		if typ.Annotations != nil && typ.Annotations.GraphQLName != "" {
+			name = typ.Annotations.GraphQLName
+		}
+		if g.needsInputSuffix(typ.Name, typeUsage) {
+			name += g.inputSuffix()
+		}
+		names[typ.Name] = g.prefixed(name)
+	}
+	return names
+}
+
+// graphqlBuiltinScalars lists the scalars every GraphQL server provides
+var graphqlBuiltinScalars = map[string]bool{
+	"String":  true,
+	"Int":     true,
+	"Float":   true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// customScalar returns the configured GraphQL scalar for a builtin type, if any:
+// its scalar mapping, or the scalar of the Int64 encoding for 64-bit integers
+func (g *GraphQLGenerator) customScalar(typeName string) (string, bool) {
+	if scalar := g.opts.ScalarMappings[typeName]; scalar != "" {
+		return scalar, true
+	}
+	if is64Bit(typeName) {
+		switch g.opts.Int64 {
+		case Int64String:
+			return "String", true
+		case Int64Scalar:
+			return defaultInt64Scalar, true
+		}
+	}
+	return "", false
+}
+
+// base64SpecURL specifies the encoding of scalars of bytes fields
+const base64SpecURL = "https://datatracker.ietf.org/doc/html/rfc4648#section-4"
+
+// scalarDeclaration declares a custom scalar, with a description of the
+// encoding of the scalars of bytes fields and quoted 64-bit integers
+func (g *GraphQLGenerator) scalarDeclaration(scalar string) string {
+	if bytesScalar, ok := g.customScalar("bytes"); ok && bytesScalar == scalar {
+		return g.description("Binary data encoded as a base64 string: RFC 4648, standard alphabet, with padding", "") +
+			fmt.Sprintf("scalar %s @specifiedBy(url: %q)\n", scalar, base64SpecURL)
+	}
+	if g.opts.Int64 == Int64Scalar {
+		int64Scalar, _ := g.customScalar("int64")
+		uint64Scalar, _ := g.customScalar("uint64")
+		if scalar == int64Scalar || scalar == uint64Scalar {
+			return g.description("64-bit integer encoded as a string of digits, since JSON numbers lose precision above 2^53", "") +
+				fmt.Sprintf("scalar %s\n", scalar)
+		}
+	}
+	return fmt.Sprintf("scalar %s\n", scalar)
+}
+
+// collectCustomScalars returns the sorted custom scalars referenced by the
+// schema, including the scalar of maps rendered as one
+func (g *GraphQLGenerator) collectCustomScalars(schema *ast.Schema) []string {
+
+	used := make(map[string]bool)
+	var visit func(ft *ast.FieldType)
+	visit = func(ft *ast.FieldType) {
+		if ft == nil {
+			return
+		}
+		if ft.IsMap {
+			if scalar, ok := g.customScalar(ft.MapKey); ok {
+				used[scalar] = true
+			}
+			visit(ft.GetMapValueType())
+			return
+		}
+		if ft.IsArray {
+			visit(ft.ElementType())
+			return
+		}
+		if scalar, ok := g.customScalar(ft.Name); ok {
+			used[scalar] = true
+		}
+	}
+
+	for _, typ := range schema.Types {
+		for _, field := range typ.Fields {
+			if scalar := g.mapScalar(field); scalar != "" {
+				used[scalar] = true
+			} else {
+				visit(field.Type)
+			}
+			for _, arg := range field.Arguments {
+				visit(arg.Type)
+			}
+		}
+	}
+
+	scalars := make([]string, 0, len(used))
+	for scalar := range used {
+		if !graphqlBuiltinScalars[scalar] {
+			scalars = append(scalars, scalar)
+		}
+	}
+	sort.Strings(scalars)
+	return scalars
+}
+
 // MapTypeKey represents a unique map type by its key and value types
 type MapTypeKey struct {
+	Name           string // Name of the entry type, when set with @graphql.map(entry=...)
 	KeyType        string
 	ValueType      string         // Simple value type name (for non-nested maps)
 	ValueIsMap     bool           // True if the value is itself a map
 	ValueFieldType *ast.FieldType // The full value type (for nested maps, arrays, etc.)
+	ValueList      string         // GraphQL type of list values in output entry types, such as [Item]
+	ValueListInput string         // GraphQL type of list values in input entry types, such as [ItemInput]
+	Input          bool           // True if an input type has the map, which then needs an input entry type
 }
 
 // WrapperType represents an auto-generated wrapper type for nested maps
 type WrapperType struct {
 	Name      string
 	FieldType *ast.FieldType
+	Input     bool // True if an input type has the nested map, which then needs an input wrapper
 }
 
-// collectMapTypesWithRegistry collects all unique map types and generates wrappers for nested maps
-func (g *GraphQLGenerator) collectMapTypesWithRegistry(schema *ast.Schema, registry *wrapperRegistry) ([]MapTypeKey, []WrapperType) {
-	mapTypesSet := make(map[string]bool)
+// collectMapTypesWithRegistry collects all unique map types and generates wrappers
+// for nested maps, and marks those that the types typeUsage reports as inputs have
+func (g *GraphQLGenerator) collectMapTypesWithRegistry(schema *ast.Schema, registry *wrapperRegistry, typeUsage map[string]string) ([]MapTypeKey, []WrapperType) {
+	mapTypesSet := make(map[string]int) // Index of each map type in mapTypes
 	var mapTypes []MapTypeKey
+	asInput := false // Whether the fields being processed belong to an input type
 
 	// Helper to get a signature for a field type (for deduplication)
 	var getFieldSignature func(ft *ast.FieldType) string
@@ -44,59 +265,95 @@ func (g *GraphQLGenerator) collectMapTypesWithRegistry(schema *ast.Schema, regis
 	}
 
 	// Helper to recursively process map types and generate wrappers as needed
-	var processMapType func(keyType string, valueType *ast.FieldType) (string, bool)
-	processMapType = func(keyType string, valueType *ast.FieldType) (string, bool) {
+	var processMapType func(keyType string, valueType *ast.FieldType, graphQLMap *ast.GraphQLMap) (string, bool)
+
+	// Helper to process the maps nested in lists, as in []map<string, int32>
+	var processNested func(ft *ast.FieldType)
+	processNested = func(ft *ast.FieldType) {
+		switch {
+		case ft.IsArray:
+			processNested(ft.ElementType())
+		case ft.IsMap:
+			processMapType(ft.MapKey, ft.GetMapValueType(), nil)
+		}
+	}
+
+	processMapType = func(keyType string, valueType *ast.FieldType, graphQLMap *ast.GraphQLMap) (string, bool) {
+		// Entry types are unique by name, so maps whose types differ in TypeMUX
+		// but not in GraphQL, such as map<string, int32> and map<string, int64>,
+		// share one
+		addMapType := func(mapKey MapTypeKey) {
+			if graphQLMap != nil && graphQLMap.Entry != "" {
+				mapKey.Name = g.prefixed(graphQLMap.Entry)
+			}
+			uniqueKey := g.entryName(mapKey) + "|" + g.mapScalarToGraphQLType(keyType) + "|" + g.entryValueType(mapKey)
+			index, ok := mapTypesSet[uniqueKey]
+			if !ok {
+				index = len(mapTypes)
+				mapTypesSet[uniqueKey] = index
+				mapTypes = append(mapTypes, mapKey)
+			}
+			if asInput {
+				mapTypes[index].Input = true
+			}
+		}
+
 		if valueType.IsMap {
 			// Nested map - we need to create a wrapper type
 			sig := getFieldSignature(valueType)
 
-			// Check if we already created a wrapper for this exact field type
-			if wrapperName, exists := registry.fieldToName[sig]; exists {
-				return wrapperName, true
+			// Reuse the wrapper of this exact field type, or create one
+			wrapperName, exists := registry.fieldToName[sig]
+			if !exists {
+				wrapperName = fmt.Sprintf("MapWrapper%d", registry.counter)
+				registry.counter++
+				registry.fieldToName[sig] = wrapperName
+				registry.wrappers = append(registry.wrappers, WrapperType{
+					Name:      wrapperName,
+					FieldType: valueType,
+				})
 			}
-
-			// Create a new wrapper
-			wrapperName := fmt.Sprintf("MapWrapper%d", registry.counter)
-			registry.counter++
-			registry.fieldToName[sig] = wrapperName
-
-			// Create wrapper type
-			wrapper := WrapperType{
-				Name:      wrapperName,
-				FieldType: valueType,
+			if asInput {
+				for i := range registry.wrappers {
+					if registry.wrappers[i].Name == wrapperName {
+						registry.wrappers[i].Input = true
+					}
+				}
 			}
-			registry.wrappers = append(registry.wrappers, wrapper)
 
 			// Recursively process the inner map to ensure its types are registered
-			processMapType(valueType.MapKey, valueType.GetMapValueType())
+			processMapType(valueType.MapKey, valueType.GetMapValueType(), nil)
 
 			// Register the outer map that uses this wrapper
-			mapKey := MapTypeKey{
+			addMapType(MapTypeKey{
 				KeyType:        keyType,
 				ValueType:      wrapperName,
+				ValueIsMap:     true,
 				ValueFieldType: valueType,
-			}
-			uniqueKey := fmt.Sprintf("%s->%s", keyType, wrapperName)
-			if !mapTypesSet[uniqueKey] {
-				mapTypesSet[uniqueKey] = true
-				mapTypes = append(mapTypes, mapKey)
-			}
+			})
 
 			return wrapperName, true
+		} else if valueType.IsArray {
+			// List values nest in the entry type, as in value: [Item]!
+			processNested(valueType)
+			valueTypeName := g.valueTypeName(valueType, registry)
+			addMapType(MapTypeKey{
+				KeyType:        keyType,
+				ValueType:      valueTypeName,
+				ValueFieldType: valueType,
+				ValueList:      g.listType(valueType, false, g.entryElementType(false), registry),
+				ValueListInput: g.listType(valueType, true, g.entryElementType(true), registry),
+			})
+
+			return valueTypeName, false
 		} else {
 			// Simple value type
 			valueTypeName := valueType.Name
-			mapKey := MapTypeKey{
+			addMapType(MapTypeKey{
 				KeyType:        keyType,
 				ValueType:      valueTypeName,
 				ValueFieldType: valueType,
-			}
-
-			uniqueKey := fmt.Sprintf("%s->%s", keyType, valueTypeName)
-			if !mapTypesSet[uniqueKey] {
-				mapTypesSet[uniqueKey] = true
-				mapTypes = append(mapTypes, mapKey)
-			}
+			})
 
 			return valueTypeName, false
 		}
@@ -105,20 +362,129 @@ func (g *GraphQLGenerator) collectMapTypesWithRegistry(schema *ast.Schema, regis
 	// Helper to process fields
 	processFields := func(fields []*ast.Field) {
 		for _, field := range fields {
-			if field.Type.IsMap {
-				processMapType(field.Type.MapKey, field.Type.GetMapValueType())
+			if field.Type.IsMap && g.mapScalar(field) == "" {
+				processMapType(field.Type.MapKey, field.Type.GetMapValueType(), graphQLMapOf(field))
+			} else if field.Type.IsArray {
+				processNested(field.Type)
 			}
 		}
 	}
 
-	// Collect from types
+	// Collect from types, then mark the maps of input types
 	for _, typ := range schema.Types {
 		processFields(typ.Fields)
 	}
+	asInput = true
+	for _, typ := range schema.Types {
+		if usage := typeUsage[typ.Name]; usage == "input" || usage == "both" {
+			processFields(typ.Fields)
+		}
+	}
 
 	return mapTypes, registry.wrappers
 }
 
+// defaultMapScalar is the scalar of maps rendered with @graphql.map(json) when
+// the MapScalar option is unset
+const defaultMapScalar = "JSON"
+
+// mapEntrySuffix returns the suffix of the names of map entry types
+func (g *GraphQLGenerator) mapEntrySuffix() string {
+	if g.opts.MapEntrySuffix == "" {
+		return "Entry"
+	}
+	return g.opts.MapEntrySuffix
+}
+
+// graphQLMapOf returns the @graphql.map settings of a field, if any
+func graphQLMapOf(field *ast.Field) *ast.GraphQLMap {
+	if field.Annotations == nil {
+		return nil
+	}
+	return field.Annotations.GraphQLMap
+}
+
+// mapScalar returns the scalar a map field renders as, or "" when it renders
+// as a list of entry types
+func (g *GraphQLGenerator) mapScalar(field *ast.Field) string {
+	if field.Type == nil || !field.Type.IsMap {
+		return ""
+	}
+	graphQLMap := graphQLMapOf(field)
+	switch {
+	case graphQLMap != nil && graphQLMap.As == ast.GraphQLMapEntries:
+		return ""
+	case graphQLMap != nil && graphQLMap.As == ast.GraphQLMapJSON && g.opts.MapScalar == "":
+		return defaultMapScalar
+	}
+	return g.opts.MapScalar
+}
+
+// mapEntryName returns the name of the entry type of a map field: the name set
+// with @graphql.map(entry=...), or one made of its key and value types
+func (g *GraphQLGenerator) mapEntryName(field *ast.Field, valueTypeName string) string {
+	if graphQLMap := graphQLMapOf(field); graphQLMap != nil && graphQLMap.Entry != "" {
+		return g.prefixed(graphQLMap.Entry)
+	}
+	return g.getKeyValueTypeName(field.Type.MapKey, valueTypeName)
+}
+
+// entryName returns the name of the entry type of a map type
+func (g *GraphQLGenerator) entryName(mapType MapTypeKey) string {
+	if mapType.Name != "" {
+		return mapType.Name
+	}
+	return g.getKeyValueTypeName(mapType.KeyType, mapType.ValueType)
+}
+
+// entryValueType returns the GraphQL type of the values of an entry type
+func (g *GraphQLGenerator) entryValueType(mapType MapTypeKey) string {
+	if mapType.ValueList != "" {
+		return mapType.ValueList
+	}
+	if g.flagEnums[mapType.ValueType] {
+		return "Int"
+	}
+	valueGQLType := g.mapScalarToGraphQLType(mapType.ValueType)
+	if !ast.IsBuiltinType(mapType.ValueType) {
+		valueGQLType = g.prefixed(valueGQLType)
+	}
+	return valueGQLType
+}
+
+// checkMapEntries reports entry types of maps whose names collide with each
+// other or with declarations of the schema
+func (g *GraphQLGenerator) checkMapEntries(schema *ast.Schema, mapTypes []MapTypeKey) error {
+	declared := make(map[string]string)
+	for _, typ := range schema.Types {
+		name := typ.Name
+		if typ.Annotations != nil && typ.Annotations.GraphQLName != "" {
+			name = typ.Annotations.GraphQLName
+		}
+		declared[g.prefixed(name)] = "type " + typ.Name
+	}
+	for _, enum := range schema.Enums {
+		declared[g.prefixed(enum.Name)] = "enum " + enum.Name
+	}
+	for _, union := range schema.Unions {
+		declared[g.prefixed(union.Name)] = "union " + union.Name
+	}
+
+	entries := make(map[string]string)
+	for _, mapType := range mapTypes {
+		name := g.entryName(mapType)
+		if declaration, ok := declared[name]; ok {
+			return fmt.Errorf("the entry type %s of map<%s, %s> has the name of %s; name it with @graphql.map(entry=...)", name, mapType.KeyType, mapType.ValueType, declaration)
+		}
+		signature := g.mapScalarToGraphQLType(mapType.KeyType) + ", " + g.entryValueType(mapType)
+		if other, ok := entries[name]; ok && other != signature {
+			return fmt.Errorf("the entry type %s is used by maps of both %s and %s; name one with @graphql.map(entry=...)", name, other, signature)
+		}
+		entries[name] = signature
+	}
+	return nil
+}
+
 // getFieldSignature returns a unique signature for a field type
 func (g *GraphQLGenerator) getFieldSignature(ft *ast.FieldType) string {
 	if ft.IsMap {
@@ -127,12 +493,75 @@ func (g *GraphQLGenerator) getFieldSignature(ft *ast.FieldType) string {
 	return ft.Name
 }
 
+// valueTypeName returns the name of the values of a map in the name of its
+// entry type: the wrapper of a nested map, ItemList for a list of Item values,
+// and otherwise the name of the value type
+func (g *GraphQLGenerator) valueTypeName(valueType *ast.FieldType, registry *wrapperRegistry) string {
+	switch {
+	case valueType.IsMap:
+		if wrapperName, exists := registry.fieldToName[g.getFieldSignature(valueType)]; exists {
+			return wrapperName
+		}
+		// Fallback - shouldn't happen if collection worked correctly
+		return "UnknownWrapper"
+	case valueType.IsArray:
+		element := valueType.ElementType()
+		var elementName string
+		if element.IsMap {
+			elementName = g.getKeyValueTypeName(element.MapKey, g.valueTypeName(element.GetMapValueType(), registry))
+		} else {
+			elementName = g.mapScalarToGraphQLType(g.valueTypeName(element, registry))
+		}
+		return g.capitalizeTypeName(elementName) + "List"
+	}
+	return valueType.Name
+}
+
+// listType returns the GraphQL type of a list, where lists nest, as in
+// [[String]], maps are lists of their entry types, as in [[StringIntEntry!]],
+// and namedType returns the type of scalars and declared types
+func (g *GraphQLGenerator) listType(ft *ast.FieldType, isInput bool, namedType func(typeName string) string, registry *wrapperRegistry) string {
+	element := ft.ElementType()
+	var elementType string
+	switch {
+	case element.IsArray:
+		elementType = g.listType(element, isInput, namedType, registry)
+	case element.IsMap:
+		entryTypeName := g.getKeyValueTypeName(element.MapKey, g.valueTypeName(element.GetMapValueType(), registry))
+		if isInput {
+			entryTypeName += g.inputSuffix()
+		}
+		elementType = fmt.Sprintf("[%s!]", entryTypeName)
+	default:
+		elementType = namedType(element.Name)
+	}
+
+	// Elements are nullable unless marked non-null, as in []string!
+	if ft.NonNullItems {
+		elementType += "!"
+	}
+	return fmt.Sprintf("[%s]", elementType)
+}
+
+// entryElementType returns the namedType of listType for lists in the output
+// or input variants of entry types
+func (g *GraphQLGenerator) entryElementType(isInput bool) func(typeName string) string {
+	return func(typeName string) string {
+		if isInput {
+			if inputName, ok := g.inputNames[typeName]; ok {
+				return inputName
+			}
+		}
+		return g.entryValueType(MapTypeKey{ValueType: typeName})
+	}
+}
+
 // getKeyValueTypeName generates a consistent name for a KeyValue type
 func (g *GraphQLGenerator) getKeyValueTypeName(keyType, valueType string) string {
 	// Capitalize the first letter of each type
 	keyTypeName := g.capitalizeTypeName(g.mapScalarToGraphQLType(keyType))
 	valueTypeName := g.capitalizeTypeName(g.mapScalarToGraphQLType(valueType))
-	return keyTypeName + valueTypeName + "Entry"
+	return g.prefixed(keyTypeName + valueTypeName + g.mapEntrySuffix())
 }
 
 // capitalizeTypeName capitalizes the first letter of a type name
@@ -145,6 +574,10 @@ func (g *GraphQLGenerator) capitalizeTypeName(typeName string) string {
 
 // mapScalarToGraphQLType maps scalar types to their GraphQL equivalents
 func (g *GraphQLGenerator) mapScalarToGraphQLType(typeName string) string {
+	if scalar, ok := g.customScalar(typeName); ok {
+		return scalar
+	}
+
 	typeMap := map[string]string{
 		"string":    "String",
 		"int32":     "Int",
@@ -169,13 +602,13 @@ func (g *GraphQLGenerator) mapScalarToGraphQLType(typeName string) string {
 }
 
 // generateWrapperType generates a wrapper type for nested maps
-func (g *GraphQLGenerator) generateWrapperType(wrapper WrapperType, isInput bool) string {
+func (g *GraphQLGenerator) generateWrapperType(wrapper WrapperType, isInput bool, registry *wrapperRegistry) string {
 	var sb strings.Builder
 
-	typeName := wrapper.Name
+	typeName := g.prefixed(wrapper.Name)
 	keyword := "type"
 	if isInput {
-		typeName += "Input"
+		typeName += g.inputSuffix()
 		keyword = "input"
 	}
 
@@ -190,18 +623,9 @@ func (g *GraphQLGenerator) generateWrapperType(wrapper WrapperType, isInput bool
 		innerKeyType := innerFieldType.MapKey
 		innerValueType := innerFieldType.GetMapValueType()
 
-		var valueTypeName string
-		if innerValueType.IsMap {
-			// Recursively nested - this should have been converted to a wrapper already
-			// Use the wrapper name
-			valueTypeName = fmt.Sprintf("MapWrapper%d", 0) // This needs better naming
-		} else {
-			valueTypeName = innerValueType.Name
-		}
-
-		entryTypeName := g.getKeyValueTypeName(innerKeyType, valueTypeName)
+		entryTypeName := g.getKeyValueTypeName(innerKeyType, g.valueTypeName(innerValueType, registry))
 		if isInput {
-			entryTypeName += "Input"
+			entryTypeName += g.inputSuffix()
 		}
 
 		sb.WriteString(fmt.Sprintf("  value: [%s!]!\n", entryTypeName))
@@ -215,17 +639,33 @@ func (g *GraphQLGenerator) generateWrapperType(wrapper WrapperType, isInput bool
 func (g *GraphQLGenerator) generateKeyValueType(mapType MapTypeKey, isInput bool) string {
 	var sb strings.Builder
 
-	typeName := g.getKeyValueTypeName(mapType.KeyType, mapType.ValueType)
+	typeName := g.entryName(mapType)
 	keyword := "type"
 	if isInput {
-		typeName += "Input"
+		typeName += g.inputSuffix()
 		keyword = "input"
 	}
 
 	keyGQLType := g.mapScalarToGraphQLType(mapType.KeyType)
-	valueGQLType := g.mapScalarToGraphQLType(mapType.ValueType)
+	valueGQLType := g.entryValueType(mapType)
+	valueTypeName := mapType.ValueType
+	if mapType.ValueList != "" {
+		valueTypeName = mapType.ValueFieldType.String()
+	}
+	switch {
+	case isInput && mapType.ValueList != "":
+		valueGQLType = mapType.ValueListInput
+	case isInput && mapType.ValueIsMap:
+		// Nested maps reference the input variant of their wrapper
+		valueGQLType += g.inputSuffix()
+	case isInput:
+		// Object values must reference their input variant
+		if inputName, ok := g.inputNames[ast.GetUnqualifiedName(mapType.ValueType)]; ok {
+			valueGQLType = inputName
+		}
+	}
 
-	sb.WriteString(fmt.Sprintf("\"%s represents a key-value pair for map<%s, %s>\"\n", typeName, mapType.KeyType, mapType.ValueType))
+	sb.WriteString(fmt.Sprintf("\"%s represents a key-value pair for map<%s, %s>\"\n", typeName, mapType.KeyType, valueTypeName))
 	sb.WriteString(fmt.Sprintf("%s %s {\n", keyword, typeName))
 	sb.WriteString(fmt.Sprintf("  key: %s!\n", keyGQLType))
 	sb.WriteString(fmt.Sprintf("  value: %s!\n", valueGQLType))
@@ -253,43 +693,74 @@ func (g *GraphQLGenerator) Generate(schema *ast.Schema) string {
 		return sb.String()
 	}
 
+	// Determine which types are used as inputs, outputs, or both
+	g.flagEnums = flagEnumNames(schema)
+	g.results = g.findResultUnions(schema)
+	typeUsage := g.analyzeTypeUsage(schema)
+	g.inputNames = g.buildInputNames(schema, typeUsage)
+	g.buildInterfaces(schema)
+
+	// With the Relay option, output types with an id are nodes
+	g.nodes = g.buildNodes(schema, typeUsage)
+	if err := g.checkRelay(schema); err != nil {
+		sb.WriteString(fmt.Sprintf("# ERROR: %s\n", err.Error()))
+		sb.WriteString("# Please rename it or generate the schema without the Relay option.\n")
+		return sb.String()
+	}
+
+	// Create a wrapper registry to track nested map wrappers
+	registry := &wrapperRegistry{
+		fieldToName: make(map[string]string),
+	}
+
+	// Collect all map types used in the schema and auto-generated wrappers
+	mapTypes, wrappers := g.collectMapTypesWithRegistry(schema, registry, typeUsage)
+	if err := g.checkMapEntries(schema, mapTypes); err != nil {
+		sb.WriteString(fmt.Sprintf("# ERROR: %s\n", err.Error()))
+		return sb.String()
+	}
+
 	sb.WriteString("# Generated GraphQL Schema\n")
 	if schema.Namespace != "" {
 		sb.WriteString(fmt.Sprintf("# Namespace: %s\n", schema.Namespace))
 	}
+	for _, line := range g.opts.Metadata.lines() {
+		sb.WriteString("# " + line + "\n")
+	}
 	sb.WriteString("\n")
 
 	// Add namespace-level GraphQL directives (e.g., federation directives)
 	if schema.NamespaceAnnotations != nil && len(schema.NamespaceAnnotations.GraphQL) > 0 {
 		for _, directive := range schema.NamespaceAnnotations.GraphQL {
-			sb.WriteString(fmt.Sprintf("extend schema %s\n", directive))
+			sb.WriteString(fmt.Sprintf("extend schema %s\n", g.normalizeDirective(directive)))
 		}
 		sb.WriteString("\n")
 	}
 
-	// Create a wrapper registry to track nested map wrappers
-	registry := &wrapperRegistry{
-		fieldToName: make(map[string]string),
+	// Declare custom scalars configured for builtin types
+	if scalars := g.collectCustomScalars(schema); len(scalars) > 0 {
+		for _, scalar := range scalars {
+			sb.WriteString(g.scalarDeclaration(scalar))
+		}
+		sb.WriteString("\n")
 	}
 
-	// Collect all map types used in the schema and auto-generated wrappers
-	mapTypes, wrappers := g.collectMapTypesWithRegistry(schema, registry)
-
-	// Generate wrapper types for nested maps first
-	if len(wrappers) > 0 {
-		for _, wrapper := range wrappers {
-			sb.WriteString(g.generateWrapperType(wrapper, false))
-			sb.WriteString("\n\n")
-			sb.WriteString(g.generateWrapperType(wrapper, true))
+	// Generate wrapper types for nested maps first, with input variants for
+	// the maps of input types
+	for _, wrapper := range wrappers {
+		sb.WriteString(g.generateWrapperType(wrapper, false, registry))
+		sb.WriteString("\n\n")
+		if wrapper.Input {
+			sb.WriteString(g.generateWrapperType(wrapper, true, registry))
 			sb.WriteString("\n\n")
 		}
 	}
 
 	// Generate KeyValue types for maps
-	if len(mapTypes) > 0 {
-		for _, mapType := range mapTypes {
-			sb.WriteString(g.generateKeyValueType(mapType, false))
-			sb.WriteString("\n\n")
+	for _, mapType := range mapTypes {
+		sb.WriteString(g.generateKeyValueType(mapType, false))
+		sb.WriteString("\n\n")
+		if mapType.Input {
 			sb.WriteString(g.generateKeyValueType(mapType, true))
 			sb.WriteString("\n\n")
 		}
@@ -298,6 +769,12 @@ func (g *GraphQLGenerator) Generate(schema *ast.Schema) string {
 	// Add @oneOf directive for union input types
 	sb.WriteString("directive @oneOf on INPUT_OBJECT\n\n")
 
+	// Declare the Relay Node interface implemented by types with an id
+	if len(g.nodes) > 0 {
+		sb.WriteString(g.description(relayNodeDescription, ""))
+		sb.WriteString("interface Node {\n  id: ID!\n}\n\n")
+	}
+
 	// Build a map of union names for quick lookup
 	unionNames := make(map[string]bool)
 	for _, union := range schema.Unions {
@@ -310,33 +787,31 @@ func (g *GraphQLGenerator) Generate(schema *ast.Schema) string {
 		sb.WriteString("\n\n")
 	}
 
-	// Determine which types are used as inputs, outputs, or both
-	typeUsage := g.analyzeTypeUsage(schema)
-
 	// Build a map of original type names to their custom GraphQL names
 	typeNameMap := make(map[string]string)
 	for _, typ := range schema.Types {
 		if typ.Annotations != nil && typ.Annotations.GraphQLName != "" {
-			typeNameMap[typ.Name] = typ.Annotations.GraphQLName
-		}
-	}
-
-	// Find all types that are union options - they need input versions
-	unionOptionTypes := make(map[string]bool)
-	for _, union := range schema.Unions {
-		for _, option := range union.Options {
-			unionOptionTypes[option] = true
+			typeNameMap[typ.Name] = g.prefixed(typ.Annotations.GraphQLName)
 		}
 	}
 
 	// Generate types
 	for _, typ := range schema.Types {
+		// Base types of other types are also emitted as interfaces
+		if _, ok := g.interfaces[typ.Name]; ok {
+			sb.WriteString(g.generateInterface(typ, unionNames, typeUsage, typeNameMap, registry))
+			sb.WriteString("\n\n")
+			if g.mixins[typ.Name] {
+				continue
+			}
+		}
+
 		usage := typeUsage[typ.Name]
-		isUnionOption := unionOptionTypes[typ.Name]
+		addInputSuffix := g.needsInputSuffix(typ.Name, typeUsage)
 
 		// If used as both input and output, generate both versions
-		if usage == "both" || isUnionOption {
-			// Generate input version with "Input" suffix
+		if usage == "both" {
+			// Generate input version with the input suffix
 			sb.WriteString(g.generateType(typ, true, true, unionNames, typeUsage, typeNameMap, registry))
 			sb.WriteString("\n\n")
 			// Generate output version (regular type)
@@ -344,7 +819,7 @@ func (g *GraphQLGenerator) Generate(schema *ast.Schema) string {
 			sb.WriteString("\n\n")
 		} else if usage == "input" {
 			// Only used as input
-			sb.WriteString(g.generateType(typ, true, false, unionNames, typeUsage, typeNameMap, registry))
+			sb.WriteString(g.generateType(typ, true, addInputSuffix, unionNames, typeUsage, typeNameMap, registry))
 			sb.WriteString("\n\n")
 		} else {
 			// Only used as output or not used in methods
@@ -358,9 +833,17 @@ func (g *GraphQLGenerator) Generate(schema *ast.Schema) string {
 		sb.WriteString(g.generateUnion(union))
 		sb.WriteString("\n\n")
 
-		// Also generate a @oneOf input type for this union
-		sb.WriteString(g.generateUnionInput(union))
-		sb.WriteString("\n\n")
+		// Also generate a @oneOf input type for this union, unless it only
+		// returns results, such as errors as data, and is never an input
+		if !g.results[union.Name] {
+			sb.WriteString(g.generateUnionInput(union))
+			sb.WriteString("\n\n")
+		}
+	}
+
+	// Leave the root types to the gateway the schema is merged into
+	if g.opts.SkipRootTypes {
+		return sb.String()
 	}
 
 	// Generate Query, Mutation, and Subscription types from services
@@ -370,7 +853,7 @@ func (g *GraphQLGenerator) Generate(schema *ast.Schema) string {
 
 	for _, service := range schema.Services {
 		for _, method := range service.Methods {
-			methodStr := g.generateServiceMethod(method, typeUsage)
+			methodStr := g.description(method.Doc.GetDoc("graphql"), "  ") + "  " + g.generateServiceMethod(method, typeUsage)
 			// Use GetGraphQLType which checks annotation or uses heuristics
 			graphqlType := method.GetGraphQLType()
 			if graphqlType == "query" {
@@ -383,10 +866,15 @@ func (g *GraphQLGenerator) Generate(schema *ast.Schema) string {
 		}
 	}
 
+	// Fetch any node by its global ID
+	if len(g.nodes) > 0 {
+		queryMethods = append(queryMethods, g.description("Fetches an object by its global ID.", "  ")+"  node(id: ID!): Node")
+	}
+
 	if len(queryMethods) > 0 {
 		sb.WriteString("type Query {\n")
 		for _, method := range queryMethods {
-			sb.WriteString("  " + method + "\n")
+			sb.WriteString(method + "\n")
 		}
 		sb.WriteString("}\n\n")
 	}
@@ -394,7 +882,7 @@ func (g *GraphQLGenerator) Generate(schema *ast.Schema) string {
 	if len(mutationMethods) > 0 {
 		sb.WriteString("type Mutation {\n")
 		for _, method := range mutationMethods {
-			sb.WriteString("  " + method + "\n")
+			sb.WriteString(method + "\n")
 		}
 		sb.WriteString("}\n\n")
 	}
@@ -402,7 +890,7 @@ func (g *GraphQLGenerator) Generate(schema *ast.Schema) string {
 	if len(subscriptionMethods) > 0 {
 		sb.WriteString("type Subscription {\n")
 		for _, method := range subscriptionMethods {
-			sb.WriteString("  " + method + "\n")
+			sb.WriteString(method + "\n")
 		}
 		sb.WriteString("}\n")
 	}
@@ -423,15 +911,60 @@ func (g *GraphQLGenerator) analyzeTypeUsage(schema *ast.Schema) map[string]strin
 	// Find all types used as input/output parameters in service methods
 	for _, service := range schema.Services {
 		for _, method := range service.Methods {
-			inputTypes[method.InputType] = true
-			outputTypes[method.OutputType] = true
+			if method.HasInput() {
+				inputTypes[method.InputType] = true
+			}
+			if method.HasOutput() {
+				outputTypes[method.OutputType] = true
+			}
 		}
 	}
 
-	// Recursively find all types referenced by input types
-	visited := make(map[string]bool)
+	// Union options are emitted as objects and as @oneOf input members, except
+	// options of result unions, which have no input
+	for _, union := range schema.Unions {
+		for _, option := range union.Options {
+			if !g.results[union.Name] {
+				inputTypes[option] = true
+			}
+			outputTypes[option] = true
+		}
+	}
+
+	// Recursively find all types referenced by input and output types. Inputs and
+	// outputs are tracked separately so a type reached both ways is fully expanded
+	// in both directions.
+	visitedInput := make(map[string]bool)
+	visitedOutput := make(map[string]bool)
 	var findReferencedTypes func(typeName string, asInput bool)
+	markReferencedType := func(fieldType *ast.FieldType, asInput bool) {
+		// Look through lists and maps, as in []map<string, Item>
+		for fieldType != nil && (fieldType.IsMap || fieldType.IsArray) {
+			if fieldType.IsMap {
+				fieldType = fieldType.GetMapValueType()
+			} else {
+				fieldType = fieldType.ElementType()
+			}
+		}
+		if fieldType == nil {
+			return
+		}
+		fieldTypeName := ast.GetUnqualifiedName(fieldType.Name)
+		if _, exists := typeMap[fieldTypeName]; !exists {
+			return
+		}
+		if asInput {
+			inputTypes[fieldTypeName] = true
+		} else {
+			outputTypes[fieldTypeName] = true
+		}
+		findReferencedTypes(fieldTypeName, asInput)
+	}
 	findReferencedTypes = func(typeName string, asInput bool) {
+		visited := visitedOutput
+		if asInput {
+			visited = visitedInput
+		}
 		if visited[typeName] {
 			return
 		}
@@ -449,15 +982,7 @@ func (g *GraphQLGenerator) analyzeTypeUsage(schema *ast.Schema) map[string]strin
 			}
 
 			// If this is a custom type (not a primitive), mark it and recurse
-			fieldTypeName := field.Type.Name
-			if _, exists := typeMap[fieldTypeName]; exists {
-				if asInput {
-					inputTypes[fieldTypeName] = true
-				} else {
-					outputTypes[fieldTypeName] = true
-				}
-				findReferencedTypes(fieldTypeName, asInput)
-			}
+			markReferencedType(field.Type, asInput)
 		}
 	}
 
@@ -479,6 +1004,22 @@ func (g *GraphQLGenerator) analyzeTypeUsage(schema *ast.Schema) map[string]strin
 		findReferencedTypes(typeName, false)
 	}
 
+	// Field arguments are always inputs. They are emitted on every object type,
+	// including types no method returns, such as a declared Query type.
+	for _, typ := range schema.Types {
+		if inputTypes[typ.Name] && !outputTypes[typ.Name] {
+			continue // Emitted only as an input, which has no arguments
+		}
+		for _, field := range typ.Fields {
+			if !field.ShouldIncludeInGenerator("graphql") {
+				continue
+			}
+			for _, arg := range field.Arguments {
+				markReferencedType(arg.Type, true)
+			}
+		}
+	}
+
 	// Categorize each type
 	usage := make(map[string]string)
 	allTypes := make(map[string]bool)
@@ -508,18 +1049,64 @@ func (g *GraphQLGenerator) analyzeTypeUsage(schema *ast.Schema) map[string]strin
 	return usage
 }
 
+// findResultUnions returns the unions that methods return and that neither
+// methods nor fields take, so the operations return the union directly and no
+// @oneOf input is generated for it
+func (g *GraphQLGenerator) findResultUnions(schema *ast.Schema) map[string]bool {
+	results := make(map[string]bool)
+	for _, service := range schema.Services {
+		for _, method := range service.Methods {
+			if method.HasOutput() {
+				results[ast.GetUnqualifiedName(method.OutputType)] = true
+			}
+		}
+	}
+
+	used := func(fieldType *ast.FieldType) {
+		for fieldType != nil && fieldType.IsMap {
+			fieldType = fieldType.GetMapValueType()
+		}
+		if fieldType != nil {
+			delete(results, ast.GetUnqualifiedName(fieldType.Name))
+		}
+	}
+	for _, service := range schema.Services {
+		for _, method := range service.Methods {
+			if method.HasInput() {
+				delete(results, ast.GetUnqualifiedName(method.InputType))
+			}
+		}
+	}
+	for _, typ := range schema.Types {
+		for _, field := range typ.Fields {
+			used(field.Type)
+			for _, arg := range field.Arguments {
+				used(arg.Type)
+			}
+		}
+	}
+
+	unions := make(map[string]bool)
+	for _, union := range schema.Unions {
+		if results[union.Name] {
+			unions[union.Name] = true
+		}
+	}
+	return unions
+}
+
 func (g *GraphQLGenerator) generateEnum(enum *ast.Enum) string {
 	var sb strings.Builder
 
-	// Add documentation - combine multi-line docs into a single string
-	if doc := enum.Doc.GetDoc("graphql"); doc != "" {
-		// Replace newlines with spaces to create a single-line description
-		singleLineDoc := strings.ReplaceAll(doc, "\n", " ")
-		sb.WriteString(fmt.Sprintf("%q\n", singleLineDoc))
+	doc := enum.Doc.GetDoc("graphql")
+	if enum.Flags {
+		// An enum value cannot hold a combination of flags, so the enum only documents the bits
+		doc = strings.TrimSpace(doc + "\nBit flags: fields of this type are Int bitmasks of these values.")
 	}
-
-	sb.WriteString(fmt.Sprintf("enum %s {\n", enum.Name))
+	sb.WriteString(g.description(doc, ""))
+	sb.WriteString(fmt.Sprintf("enum %s%s {\n", g.prefixed(enum.Name), g.formatDirectives(enum.Annotations)))
 	for _, value := range enum.Values {
+		sb.WriteString(g.description(value.Doc.GetDoc("graphql"), "  "))
 		sb.WriteString(fmt.Sprintf("  %s\n", value.Name))
 	}
 	sb.WriteString("}")
@@ -529,31 +1116,32 @@ func (g *GraphQLGenerator) generateEnum(enum *ast.Enum) string {
 func (g *GraphQLGenerator) generateUnion(union *ast.Union) string {
 	var sb strings.Builder
 
-	// Add documentation
-	if doc := union.Doc.GetDoc("graphql"); doc != "" {
-		singleLineDoc := strings.ReplaceAll(doc, "\n", " ")
-		sb.WriteString(fmt.Sprintf("%q\n", singleLineDoc))
+	sb.WriteString(g.description(union.Doc.GetDoc("graphql"), ""))
+	sb.WriteString(fmt.Sprintf("union %s%s = ", g.prefixed(union.Name), g.formatDirectives(union.Annotations)))
+	options := make([]string, len(union.Options))
+	for i, option := range union.Options {
+		options[i] = g.prefixed(option)
 	}
-
-	sb.WriteString(fmt.Sprintf("union %s = ", union.Name))
-	sb.WriteString(strings.Join(union.Options, " | "))
+	sb.WriteString(strings.Join(options, " | "))
 	return sb.String()
 }
 
 func (g *GraphQLGenerator) generateUnionInput(union *ast.Union) string {
 	var sb strings.Builder
 
-	// Add documentation
 	if doc := union.Doc.GetDoc("graphql"); doc != "" {
-		singleLineDoc := strings.ReplaceAll(doc, "\n", " ")
-		sb.WriteString(fmt.Sprintf("%q\n", singleLineDoc+" (Input variant with @oneOf)"))
+		sb.WriteString(g.description(doc+" (Input variant with @oneOf)", ""))
 	}
 
-	sb.WriteString(fmt.Sprintf("input %sInput @oneOf {\n", union.Name))
+	sb.WriteString(fmt.Sprintf("input %s%s @oneOf {\n", g.prefixed(union.Name), g.inputSuffix()))
 	for _, option := range union.Options {
 		// Create optional field for each option (oneOf requires exactly one field to be set)
 		fieldName := strings.ToLower(option[:1]) + option[1:] // camelCase
-		sb.WriteString(fmt.Sprintf("  %s: %sInput\n", fieldName, option))
+		optionInput := g.prefixed(option) + g.inputSuffix()
+		if name, ok := g.inputNames[option]; ok {
+			optionInput = name
+		}
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", fieldName, optionInput))
 	}
 	sb.WriteString("}")
 	return sb.String()
@@ -562,12 +1150,7 @@ func (g *GraphQLGenerator) generateUnionInput(union *ast.Union) string {
 func (g *GraphQLGenerator) generateType(typ *ast.Type, isInput bool, addInputSuffix bool, unionNames map[string]bool, typeUsage map[string]string, typeNameMap map[string]string, registry *wrapperRegistry) string {
 	var sb strings.Builder
 
-	// Add documentation - combine multi-line docs into a single string
-	if doc := typ.Doc.GetDoc("graphql"); doc != "" {
-		// Replace newlines with spaces to create a single-line description
-		singleLineDoc := strings.ReplaceAll(doc, "\n", " ")
-		sb.WriteString(fmt.Sprintf("%q\n", singleLineDoc))
-	}
+	sb.WriteString(g.description(typ.Doc.GetDoc("graphql"), ""))
 
 	// Use 'input' keyword for types used as input parameters
 	keyword := "type"
@@ -577,24 +1160,187 @@ func (g *GraphQLGenerator) generateType(typ *ast.Type, isInput bool, addInputSuf
 	if typ.Annotations != nil && typ.Annotations.GraphQLName != "" {
 		typeName = typ.Annotations.GraphQLName
 	}
+	typeName = g.prefixed(typeName)
 
 	if isInput {
 		keyword = "input"
 		if addInputSuffix {
-			// Only add Input suffix if we're not using a custom name
-			if typ.Annotations == nil || typ.Annotations.GraphQLName == "" {
-				typeName = typ.Name + "Input"
-			}
+			// Suffix custom names as well so input and output variants never collide
+			typeName += g.inputSuffix()
 		}
 	}
 
 	// Add GraphQL directives to type
 	directives := ""
-	if !isInput && typ.Annotations != nil && len(typ.Annotations.GraphQL) > 0 {
-		directives = " " + strings.Join(typ.Annotations.GraphQL, " ")
+	if !isInput {
+		directives = g.formatDirectives(typ.Annotations)
+	}
+
+	implements := ""
+	if !isInput {
+		var names []string
+		if g.nodes[typ.Name] {
+			names = append(names, "Node")
+		}
+		if iface, ok := g.interfaces[typ.Name]; ok && !g.mixins[typ.Name] {
+			names = append(names, iface)
+		}
+		names = append(names, g.implements[typ.Name]...)
+		if len(names) > 0 {
+			implements = " implements " + strings.Join(names, " & ")
+		}
 	}
 
-	sb.WriteString(fmt.Sprintf("%s %s%s {\n", keyword, typeName, directives))
+	sb.WriteString(fmt.Sprintf("%s %s%s%s {\n", keyword, typeName, implements, directives))
+	sb.WriteString(g.generateFields(typ, isInput, unionNames, typeUsage, typeNameMap, registry))
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// buildInterfaces decides how types that are extended by others map to GraphQL interfaces.
+// A base type that is never referenced directly becomes an interface of the same name;
+// otherwise it keeps its object type and gains an interface named with an Interface suffix.
+func (g *GraphQLGenerator) buildInterfaces(schema *ast.Schema) {
+	g.interfaces = make(map[string]string)
+	g.implements = make(map[string][]string)
+	g.mixins = make(map[string]bool)
+
+	referenced := make(map[string]bool)
+	for _, typ := range schema.Types {
+		for _, field := range typ.Fields {
+			fieldType := field.Type
+			for fieldType != nil && fieldType.IsMap {
+				fieldType = fieldType.GetMapValueType()
+			}
+			if fieldType != nil {
+				referenced[ast.GetUnqualifiedName(fieldType.Name)] = true
+			}
+		}
+	}
+	for _, union := range schema.Unions {
+		for _, option := range union.Options {
+			referenced[ast.GetUnqualifiedName(option)] = true
+		}
+	}
+	for _, service := range schema.Services {
+		for _, method := range service.Methods {
+			referenced[ast.GetUnqualifiedName(method.InputType)] = true
+			referenced[ast.GetUnqualifiedName(method.OutputType)] = true
+		}
+	}
+
+	for _, typ := range schema.Types {
+		for _, base := range schema.Ancestors(typ) {
+			if _, ok := g.interfaces[base.Name]; !ok {
+				name := base.Name
+				if base.Annotations != nil && base.Annotations.GraphQLName != "" {
+					name = base.Annotations.GraphQLName
+				}
+				if referenced[base.Name] {
+					name += "Interface"
+				} else {
+					g.mixins[base.Name] = true
+				}
+				g.interfaces[base.Name] = g.prefixed(name)
+			}
+			g.implements[typ.Name] = append(g.implements[typ.Name], g.interfaces[base.Name])
+		}
+	}
+}
+
+// relayNodeDescription documents the Relay Node interface and how its global IDs are made
+const relayNodeDescription = `An object with a globally unique ID, which node(id:) fetches.
+Global IDs are opaque to clients. Servers should encode the type name with
+the ID of the object, such as the base64 of "User:42", and decode them to
+resolve node(id:) and the id arguments of other fields.`
+
+// isRelayID reports whether a field is the id of a Relay node: a scalar field named id in GraphQL
+func isRelayID(field *ast.Field) bool {
+	return field.NameFor("graphql") == "id" && field.Type != nil &&
+		!field.Type.IsArray && !field.Type.IsMap && ast.IsBuiltinType(field.Type.Name)
+}
+
+// buildNodes returns the types that implement the Relay Node interface: with the
+// Relay option, the output types with an id field GraphQL includes
+func (g *GraphQLGenerator) buildNodes(schema *ast.Schema, typeUsage map[string]string) map[string]bool {
+	nodes := make(map[string]bool)
+	if !g.opts.Relay {
+		return nodes
+	}
+	for _, typ := range schema.Types {
+		if typeUsage[typ.Name] == "input" {
+			continue
+		}
+		for _, field := range typ.Fields {
+			if isRelayID(field) && field.ShouldIncludeInGenerator("graphql") {
+				nodes[typ.Name] = true
+				break
+			}
+		}
+	}
+	return nodes
+}
+
+// checkRelay reports declarations that clash with the Node interface or node query Relay adds
+func (g *GraphQLGenerator) checkRelay(schema *ast.Schema) error {
+	if len(g.nodes) == 0 {
+		return nil
+	}
+	for _, typ := range schema.Types {
+		name := ast.GetUnqualifiedName(typ.Name)
+		if typ.Annotations != nil && typ.Annotations.GraphQLName != "" {
+			name = typ.Annotations.GraphQLName
+		}
+		if g.prefixed(name) == "Node" {
+			return fmt.Errorf("type %s is named Node, which Relay reserves for its Node interface", typ.Name)
+		}
+	}
+	for _, enum := range schema.Enums {
+		if g.prefixed(ast.GetUnqualifiedName(enum.Name)) == "Node" {
+			return fmt.Errorf("enum %s is named Node, which Relay reserves for its Node interface", enum.Name)
+		}
+	}
+	for _, union := range schema.Unions {
+		if g.prefixed(ast.GetUnqualifiedName(union.Name)) == "Node" {
+			return fmt.Errorf("union %s is named Node, which Relay reserves for its Node interface", union.Name)
+		}
+	}
+	if g.opts.SkipRootTypes {
+		return nil
+	}
+	for _, service := range schema.Services {
+		for _, method := range service.Methods {
+			if method.GetGraphQLType() == "query" && strings.ToLower(method.Name[:1])+method.Name[1:] == "node" {
+				return fmt.Errorf("method %s.%s is the query node, which Relay reserves to fetch objects by global ID", service.Name, method.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// generateInterface generates the interface of a type that other types extend
+func (g *GraphQLGenerator) generateInterface(typ *ast.Type, unionNames map[string]bool, typeUsage map[string]string, typeNameMap map[string]string, registry *wrapperRegistry) string {
+	var sb strings.Builder
+	sb.WriteString(g.description(typ.Doc.GetDoc("graphql"), ""))
+
+	var names []string
+	if g.nodes[typ.Name] {
+		names = append(names, "Node")
+	}
+	names = append(names, g.implements[typ.Name]...)
+	implements := ""
+	if len(names) > 0 {
+		implements = " implements " + strings.Join(names, " & ")
+	}
+	sb.WriteString(fmt.Sprintf("interface %s%s {\n", g.interfaces[typ.Name], implements))
+	sb.WriteString(g.generateFields(typ, false, unionNames, typeUsage, typeNameMap, registry))
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// generateFields generates the field lines of an object, input, or interface type
+func (g *GraphQLGenerator) generateFields(typ *ast.Type, isInput bool, unionNames map[string]bool, typeUsage map[string]string, typeNameMap map[string]string, registry *wrapperRegistry) string {
+	var sb strings.Builder
 	for _, field := range typ.Fields {
 		// Skip excluded fields
 		if !field.ShouldIncludeInGenerator("graphql") {
@@ -617,8 +1363,10 @@ func (g *GraphQLGenerator) generateType(typ *ast.Type, isInput bool, addInputSuf
 		}
 
 		// Add custom GraphQL directives
-		if !isInput && field.Annotations != nil && len(field.Annotations.GraphQL) > 0 {
-			fieldDirectiveParts = append(fieldDirectiveParts, field.Annotations.GraphQL...)
+		if !isInput && field.Annotations != nil {
+			for _, directive := range field.Annotations.GraphQL {
+				fieldDirectiveParts = append(fieldDirectiveParts, g.normalizeDirective(directive))
+			}
 		}
 
 		fieldDirectives := ""
@@ -632,18 +1380,29 @@ func (g *GraphQLGenerator) generateType(typ *ast.Type, isInput bool, addInputSuf
 			fieldArgs = g.generateFieldArguments(field)
 		}
 
-		// Use UnionInput type for union fields in input types
-		if isInput && unionNames[field.Type.Name] {
-			gqlType := field.Type.Name + "Input"
-			if field.Required {
+		sb.WriteString(g.description(field.Doc.GetDoc("graphql"), "  "))
+
+		// The id of a Relay node is its global ID, which outputs always have
+		if g.nodes[typ.Name] && isRelayID(field) {
+			gqlType := "ID"
+			if !isInput || field.Presence() == ast.PresenceRequired {
 				gqlType += "!"
 			}
-			sb.WriteString(fmt.Sprintf("  %s%s: %s%s\n", field.Name, fieldArgs, gqlType, fieldDirectives))
+			sb.WriteString(fmt.Sprintf("  %s%s: %s%s\n", field.NameFor("graphql"), fieldArgs, gqlType, fieldDirectives))
+		} else if isInput && unionNames[field.Type.Name] {
+			// Use UnionInput type for union fields in input types
+			gqlType := g.prefixed(field.Type.Name) + g.inputSuffix()
+			if field.Type.IsArray {
+				gqlType = fmt.Sprintf("[%s!]", gqlType)
+			}
+			if field.Presence() == ast.PresenceRequired {
+				gqlType += "!"
+			}
+			sb.WriteString(fmt.Sprintf("  %s%s: %s%s\n", field.NameFor("graphql"), fieldArgs, gqlType, fieldDirectives))
 		} else {
-			sb.WriteString(fmt.Sprintf("  %s%s: %s%s\n", field.Name, fieldArgs, g.convertFieldType(field, isInput, typeUsage, typeNameMap, registry), fieldDirectives))
+			sb.WriteString(fmt.Sprintf("  %s%s: %s%s\n", field.NameFor("graphql"), fieldArgs, g.convertFieldType(field, isInput, typeUsage, typeNameMap, registry), fieldDirectives))
 		}
 	}
-	sb.WriteString("}")
 	return sb.String()
 }
 
@@ -656,14 +1415,23 @@ func (g *GraphQLGenerator) generateFieldArguments(field *ast.Field) string {
 	argParts := make([]string, 0, len(field.Arguments))
 	for _, arg := range field.Arguments {
 		argType := g.mapTypeToGraphQL(arg.Type)
+		if inputName, ok := g.inputNames[ast.GetUnqualifiedName(arg.Type.Name)]; ok && !arg.Type.IsMap {
+			argType = inputName
+		}
+		if arg.Type.IsArray {
+			argType = fmt.Sprintf("[%s!]", argType)
+		}
 
 		// Add ! for required arguments
 		if arg.Required {
 			argType += "!"
 		}
 
-		// Build argument string
+		// Build argument string, led by its description on the same line
 		argStr := fmt.Sprintf("%s: %s", arg.Name, argType)
+		if doc := arg.Doc.GetDoc("graphql"); doc != "" {
+			argStr = fmt.Sprintf("%q %s", strings.Join(strings.Fields(doc), " "), argStr)
+		}
 
 		// Add default value if present
 		if arg.Default != "" {
@@ -684,72 +1452,63 @@ func (g *GraphQLGenerator) generateFieldArguments(field *ast.Field) string {
 func (g *GraphQLGenerator) convertFieldType(field *ast.Field, isInput bool, typeUsage map[string]string, typeNameMap map[string]string, registry *wrapperRegistry) string {
 	gqlType := g.mapTypeToGraphQL(field.Type)
 
-	// For maps, we need to handle them as arrays of KeyValue types
-	if field.Type.IsMap {
-		valueType := field.Type.GetMapValueType()
-		var valueTypeName string
-
-		// Check if the value is a nested map (needs wrapper)
-		if valueType.IsMap {
-			// Get wrapper name from registry
-			sig := g.getFieldSignature(valueType)
-			if wrapperName, exists := registry.fieldToName[sig]; exists {
-				valueTypeName = wrapperName
-			} else {
-				// Fallback - shouldn't happen if collection worked correctly
-				valueTypeName = "UnknownWrapper"
-			}
-		} else {
-			valueTypeName = valueType.Name
+	// Maps render as the map scalar, or as arrays of KeyValue types
+	if scalar := g.mapScalar(field); scalar != "" {
+		if field.Presence() == ast.PresenceRequired {
+			scalar += "!"
 		}
+		return scalar
+	}
+	if field.Type.IsMap {
+		// Nested maps (which need a wrapper) and lists are named by the registry
+		valueTypeName := g.valueTypeName(field.Type.GetMapValueType(), registry)
 
 		// Get the appropriate KeyValue type name (input or output)
-		kvTypeName := g.getKeyValueTypeName(field.Type.MapKey, valueTypeName)
+		kvTypeName := g.mapEntryName(field, valueTypeName)
 		if isInput {
-			kvTypeName += "Input"
+			kvTypeName += g.inputSuffix()
 		}
 		gqlType = fmt.Sprintf("[%s!]", kvTypeName)
 
 		// Add non-null for the array itself if field is required
-		if field.Required && !field.Type.Optional {
+		if field.Presence() == ast.PresenceRequired {
 			gqlType += "!"
 		}
 		return gqlType
 	}
 
-	// Use unqualified name for lookups
-	fieldTypeName := ast.GetUnqualifiedName(field.Type.Name)
+	// namedType returns the GraphQL type of a scalar or declared type in this field
+	namedType := func(typeName string) string {
+		gqlType := g.mapTypeToGraphQL(&ast.FieldType{Name: typeName})
 
-	// Check if this type has a custom GraphQL name
-	if customName, ok := typeNameMap[fieldTypeName]; ok {
-		gqlType = customName
-	}
+		// Use unqualified name for lookups
+		fieldTypeName := ast.GetUnqualifiedName(typeName)
 
-	// If this is an input context and the field type is a custom type that has both/input usage,
-	// use the Input suffix
-	if isInput {
-		usage := typeUsage[fieldTypeName]
-		if usage == "both" || usage == "input" {
-			// If there's a custom name, don't add Input suffix (it's already the custom name)
-			// Otherwise add Input suffix to the original name
-			if _, hasCustomName := typeNameMap[fieldTypeName]; !hasCustomName {
-				gqlType = fieldTypeName + "Input"
+		// Check if this type has a custom GraphQL name
+		if customName, ok := typeNameMap[fieldTypeName]; ok {
+			gqlType = customName
+		}
+
+		// If this is an input context and the field type is a custom type that has both/input usage,
+		// reference its input variant
+		if isInput {
+			usage := typeUsage[fieldTypeName]
+			if usage == "both" || usage == "input" {
+				gqlType = g.inputTypeName(fieldTypeName, typeUsage)
 			}
 		}
+		return gqlType
 	}
 
 	if field.Type.IsArray {
-		gqlType = fmt.Sprintf("[%s]", gqlType)
+		gqlType = g.listType(field.Type, isInput, namedType, registry)
+	} else {
+		gqlType = namedType(field.Type.Name)
 	}
 
-	// In GraphQL, non-null (!) is the default for required fields
-	// If the field is explicitly optional (has ?), don't add !
-	// If the field is required (@required), add !
-	if field.Required && !field.Type.Optional {
+	// Only required fields are non-null; optional (?) and implicit fields stay nullable
+	if field.Presence() == ast.PresenceRequired {
 		gqlType += "!"
-	} else if !field.Type.Optional && !field.Required {
-		// By default, if not marked as optional and not explicitly required,
-		// GraphQL leaves it nullable (no ! suffix)
 	}
 
 	return gqlType
@@ -761,10 +1520,21 @@ func (g *GraphQLGenerator) mapTypeToGraphQL(fieldType *ast.FieldType) string {
 		return g.getKeyValueTypeName(fieldType.MapKey, fieldType.MapValue)
 	}
 
+	if scalar, ok := g.customScalar(fieldType.Name); ok {
+		return scalar
+	}
+	if g.flagEnums[fieldType.Name] {
+		return "Int"
+	}
+
 	typeMap := map[string]string{
 		"string":    "String",
 		"int32":     "Int",
 		"int64":     "Int",
+		"uint8":     "Int", // GraphQL has no unsigned types, use Int
+		"uint16":    "Int",
+		"uint32":    "Int",
+		"uint64":    "Int",
 		"float32":   "Float",
 		"float64":   "Float",
 		"bool":      "Boolean",
@@ -777,20 +1547,80 @@ func (g *GraphQLGenerator) mapTypeToGraphQL(fieldType *ast.FieldType) string {
 	}
 
 	// Custom type - use unqualified name for output
-	return ast.GetUnqualifiedName(fieldType.Name)
+	return g.prefixed(ast.GetUnqualifiedName(fieldType.Name))
 }
 
 func (g *GraphQLGenerator) generateServiceMethod(method *ast.Method, typeUsage map[string]string) string {
 	// Convert method name to camelCase
 	methodName := strings.ToLower(method.Name[:1]) + method.Name[1:]
 
-	// If the input type is used as both input and output, add "Input" suffix
-	inputTypeName := method.InputType
-	if typeUsage[method.InputType] == "both" {
-		inputTypeName = method.InputType + "Input"
+	// A method without input takes no arguments
+	arguments := ""
+	if method.HasInput() {
+		// If the input type is used as both input and output, reference its input variant
+		arguments = fmt.Sprintf("(input: %s)", g.inputTypeName(method.InputType, typeUsage))
 	}
 
-	return fmt.Sprintf("%s(input: %s): %s", methodName, inputTypeName, method.OutputType)
+	// A method without output reports success as a Boolean
+	outputType := g.prefixed(method.OutputType)
+	if !method.HasOutput() {
+		outputType = "Boolean"
+	}
+
+	return fmt.Sprintf("%s%s: %s%s", methodName, arguments, outputType, g.formatDirectives(method.Annotations))
+}
+
+// description renders a documentation string as a GraphQL description on its own
+// lines at an indentation: a quoted string for one line, or a block string
+func (g *GraphQLGenerator) description(doc, indent string) string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return ""
+	}
+	if !strings.Contains(doc, "\n") {
+		return fmt.Sprintf("%s%q\n", indent, doc)
+	}
+	var sb strings.Builder
+	sb.WriteString(indent + "\"\"\"\n")
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.ReplaceAll(strings.TrimSpace(line), `"""`, `\"""`)
+		if line == "" {
+			sb.WriteString("\n")
+			continue
+		}
+		sb.WriteString(indent + line + "\n")
+	}
+	sb.WriteString(indent + "\"\"\"\n")
+	return sb.String()
+}
+
+// formatDirectives renders the GraphQL directives of an element, prefixed with a space
+func (g *GraphQLGenerator) formatDirectives(annotations *ast.FormatAnnotations) string {
+	if annotations == nil || len(annotations.GraphQL) == 0 {
+		return ""
+	}
+
+	directives := make([]string, 0, len(annotations.GraphQL))
+	for _, directive := range annotations.GraphQL {
+		if directive = g.normalizeDirective(directive); directive != "" {
+			directives = append(directives, directive)
+		}
+	}
+	if len(directives) == 0 {
+		return ""
+	}
+	return " " + strings.Join(directives, " ")
+}
+
+// normalizeDirective unquotes directives written as strings, e.g. "@auth(requires: ADMIN)"
+func (g *GraphQLGenerator) normalizeDirective(directive string) string {
+	directive = strings.TrimSpace(directive)
+	if len(directive) >= 2 && directive[0] == '"' && directive[len(directive)-1] == '"' {
+		if unquoted, err := strconv.Unquote(directive); err == nil {
+			directive = strings.TrimSpace(unquoted)
+		}
+	}
+	return directive
 }
 
 // checkForDuplicates checks if there are multiple types/enums with the same unqualified name
@@ -843,6 +1673,7 @@ func (g *GraphQLGenerator) checkForDuplicates(schema *ast.Schema) error {
 				for ns := range nsSet {
 					nsList = append(nsList, ns)
 				}
+				sort.Strings(nsList)
 				return fmt.Errorf("duplicate type name '%s' found in namespaces: %s", name, strings.Join(nsList, ", "))
 			}
 		}
@@ -859,6 +1690,7 @@ func (g *GraphQLGenerator) checkForDuplicates(schema *ast.Schema) error {
 				for ns := range nsSet {
 					nsList = append(nsList, ns)
 				}
+				sort.Strings(nsList)
 				return fmt.Errorf("duplicate enum name '%s' found in namespaces: %s", name, strings.Join(nsList, ", "))
 			}
 		}
@@ -875,6 +1707,7 @@ func (g *GraphQLGenerator) checkForDuplicates(schema *ast.Schema) error {
 				for ns := range nsSet {
 					nsList = append(nsList, ns)
 				}
+				sort.Strings(nsList)
 				return fmt.Errorf("duplicate union name '%s' found in namespaces: %s", name, strings.Join(nsList, ", "))
 			}
 		}
@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/loader"
+	"github.com/vektah/gqlparser/v2"
+	gqlast "github.com/vektah/gqlparser/v2/ast"
 )
 
 // Helper function to create a wrapper registry for testing
@@ -576,6 +579,56 @@ func TestGraphQLGenerator_ArrayOfCustomTypes(t *testing.T) {
 	}
 }
 
+func TestGraphQLGenerator_ArrayNullability(t *testing.T) {
+	gen := NewGraphQLGenerator()
+	tests := []struct {
+		required     bool
+		nonNullItems bool
+		expected     string
+	}{
+		{true, true, "[String!]!"},
+		{true, false, "[String]!"},
+		{false, true, "[String!]"},
+		{false, false, "[String]"},
+	}
+
+	for _, tt := range tests {
+		field := &ast.Field{
+			Name:     "tags",
+			Type:     &ast.FieldType{Name: "string", IsBuiltin: true, IsArray: true, NonNullItems: tt.nonNullItems},
+			Required: tt.required,
+		}
+		registry := newWrapperRegistry()
+		result := gen.convertFieldType(field, false, make(map[string]string), make(map[string]string), registry)
+		if result != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, result)
+		}
+	}
+}
+
+func TestGraphQLGenerator_NestedContainers(t *testing.T) {
+	output := NewGraphQLGenerator().Generate(nestedContainersSchema())
+
+	expected := []string{
+		"  rows: [[StringIntEntry!]]\n",
+		"  groups: [StringItemListEntry!]\n",
+		"  matrix: [[Float]]\n",
+		"  tagged: [[StringItemEntry!]]\n",
+		"  rows: [[StringIntEntryInput!]]\n",
+		"  tagged: [[StringItemEntryInput!]]\n",
+		"type StringItemListEntry {\n  key: String!\n  value: [Item]!\n}",
+		"input StringItemListEntryInput {\n  key: String!\n  value: [ItemInput]!\n}",
+		"type StringIntEntry {",
+		"input StringItemEntryInput {\n  key: String!\n  value: ItemInput!\n}",
+		`"StringItemListEntry represents a key-value pair for map<string, []Item>"`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
 func TestGraphQLGenerator_TimestampType(t *testing.T) {
 	gen := NewGraphQLGenerator()
 	field := &ast.Field{
@@ -872,7 +925,7 @@ func TestGraphQLGenerator_CollectMapTypes(t *testing.T) {
 	}
 
 	registry := newWrapperRegistry()
-	mapTypes, _ := gen.collectMapTypesWithRegistry(schema, registry)
+	mapTypes, _ := gen.collectMapTypesWithRegistry(schema, registry, nil)
 
 	// Should collect unique map types
 	if len(mapTypes) != 2 {
@@ -1181,6 +1234,14 @@ func TestGraphQLGenerator_MapTypes(t *testing.T) {
 				},
 			},
 		},
+		Services: []*ast.Service{
+			{
+				Name: "ConfigurationService",
+				Methods: []*ast.Method{
+					{Name: "UpdateConfiguration", InputType: "Configuration", OutputType: "Configuration"},
+				},
+			},
+		},
 	}
 
 	gen := NewGraphQLGenerator()
@@ -1613,3 +1674,814 @@ func TestGraphQLGenerator_FieldArgumentsNotInInput(t *testing.T) {
 		t.Error("Expected input type to be generated")
 	}
 }
+
+func TestGraphQLGenerator_CustomScalarMappings(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{
+				Name: "Event",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "int64", IsBuiltin: true}, Required: true},
+					{Name: "createdAt", Type: &ast.FieldType{Name: "timestamp", IsBuiltin: true}},
+					{Name: "counters", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "int64"}},
+					{Name: "name", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+				},
+			},
+		},
+	}
+
+	gen := NewGraphQLGeneratorWithOptions(&GraphQLOptions{
+		ScalarMappings: map[string]string{
+			"timestamp": "DateTime",
+			"int64":     "BigInt",
+			"bytes":     "Base64",
+			"string":    "String",
+		},
+	})
+	output := gen.Generate(schema)
+
+	if !strings.Contains(output, "scalar BigInt\nscalar DateTime\n") {
+		t.Errorf("expected sorted scalar declarations, got:\n%s", output)
+	}
+
+	if strings.Contains(output, "scalar Base64") {
+		t.Error("expected unused scalar Base64 not to be declared")
+	}
+
+	if strings.Contains(output, "scalar String") {
+		t.Error("expected built-in GraphQL scalars not to be declared")
+	}
+
+	expected := []string{
+		"id: BigInt!",
+		"createdAt: DateTime",
+		"counters: [StringBigIntEntry!]",
+		"value: BigInt!",
+		"name: String",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+}
+
+func TestGraphQLGenerator_NoScalarMappings(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{
+				Name: "Event",
+				Fields: []*ast.Field{
+					{Name: "createdAt", Type: &ast.FieldType{Name: "timestamp", IsBuiltin: true}},
+				},
+			},
+		},
+	}
+
+	output := NewGraphQLGenerator().Generate(schema)
+
+	if strings.Contains(output, "scalar ") {
+		t.Error("expected no scalar declarations without mappings")
+	}
+
+	if !strings.Contains(output, "createdAt: String") {
+		t.Error("expected timestamp to default to String")
+	}
+}
+
+func TestGraphQLGenerator_NestedInputTypes(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{
+				Name: "Geo",
+				Fields: []*ast.Field{
+					{Name: "lat", Type: &ast.FieldType{Name: "float64", IsBuiltin: true}},
+				},
+			},
+			{
+				Name: "Address",
+				Fields: []*ast.Field{
+					{Name: "city", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+					{Name: "geo", Type: &ast.FieldType{Name: "Geo"}},
+				},
+			},
+			{
+				Name: "User",
+				Fields: []*ast.Field{
+					{Name: "address", Type: &ast.FieldType{Name: "Address"}},
+					{Name: "labels", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "Address"}},
+				},
+			},
+			{
+				Name: "CreateUserRequest",
+				Fields: []*ast.Field{
+					{Name: "address", Type: &ast.FieldType{Name: "Address"}, Required: true},
+					{Name: "labels", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "Address"}},
+				},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "UserService",
+				Methods: []*ast.Method{
+					{Name: "CreateUser", InputType: "CreateUserRequest", OutputType: "User"},
+				},
+			},
+		},
+	}
+
+	output := NewGraphQLGenerator().Generate(schema)
+
+	expected := []string{
+		"input GeoInput {",
+		"type Geo {",
+		"input AddressInput {",
+		"  geo: GeoInput\n",
+		"type Address {",
+		"  geo: Geo\n",
+		"input CreateUserRequest {",
+		"  address: AddressInput!\n",
+		"  labels: [StringAddressEntryInput!]\n",
+		"createUser(input: CreateUserRequest): User",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	// Map entry inputs must reference input variants of their values
+	entryInput := output[strings.Index(output, "input StringAddressEntryInput {"):]
+	entryInput = entryInput[:strings.Index(entryInput, "}")]
+	if !strings.Contains(entryInput, "value: AddressInput!") {
+		t.Errorf("expected map entry input to reference AddressInput, got:\n%s", entryInput)
+	}
+}
+
+func TestGraphQLGenerator_InputSuffixOptions(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{
+				Name: "Post",
+				Fields: []*ast.Field{
+					{Name: "title", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+				},
+				Annotations: &ast.FormatAnnotations{GraphQLName: "Article"},
+			},
+			{
+				Name: "GetPostRequest",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+				},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "PostService",
+				Methods: []*ast.Method{
+					{Name: "CreatePost", InputType: "Post", OutputType: "Post"},
+					{Name: "GetPost", InputType: "GetPostRequest", OutputType: "Post"},
+				},
+			},
+		},
+	}
+
+	gen := NewGraphQLGeneratorWithOptions(&GraphQLOptions{InputSuffix: "Payload", SuffixAllInputs: true})
+	output := gen.Generate(schema)
+
+	expected := []string{
+		"input ArticlePayload {",
+		"type Article {",
+		"input GetPostRequestPayload {",
+		"createPost(input: ArticlePayload)",
+		"getPost(input: GetPostRequestPayload)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestGraphQLGenerator_Relay(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{
+				Name: "User",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+					{Name: "name", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+				},
+			},
+			{
+				Name: "Tag",
+				Fields: []*ast.Field{
+					{Name: "label", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+				},
+			},
+			{
+				Name: "GetUserRequest",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}, Required: true},
+				},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "UserService",
+				Methods: []*ast.Method{
+					{Name: "GetUser", InputType: "GetUserRequest", OutputType: "User"},
+					{Name: "ListTags", InputType: "GetUserRequest", OutputType: "Tag"},
+					{Name: "CreateUser", InputType: "User", OutputType: "User"},
+				},
+			},
+		},
+	}
+
+	output := NewGraphQLGeneratorWithOptions(&GraphQLOptions{Relay: true}).Generate(schema)
+	expected := []string{
+		"interface Node {\n  id: ID!\n}",
+		"base64 of \"User:42\"",
+		"type User implements Node {\n  id: ID!\n",
+		"input UserInput {\n  id: ID\n",
+		"type Tag {",
+		"input GetUserRequest {\n  id: String!\n",
+		"  \"Fetches an object by its global ID.\"\n  node(id: ID!): Node\n}",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	// Without the option, ids keep their type
+	output = NewGraphQLGenerator().Generate(schema)
+	for _, unwanted := range []string{"interface Node", "implements Node", "node(id: ID!)"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("expected output without Relay not to contain %q", unwanted)
+		}
+	}
+
+	// A declaration named Node clashes with the interface
+	schema.Types = append(schema.Types, &ast.Type{Name: "Node"})
+	output = NewGraphQLGeneratorWithOptions(&GraphQLOptions{Relay: true}).Generate(schema)
+	if !strings.Contains(output, "# ERROR: type Node is named Node") {
+		t.Errorf("expected a Node clash error, got:\n%s", output)
+	}
+}
+
+func TestGraphQLGenerator_TypePrefix(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{
+			{Name: "Status", Values: []*ast.EnumValue{{Name: "ACTIVE"}}},
+		},
+		Types: []*ast.Type{
+			{
+				Name: "Invoice",
+				Fields: []*ast.Field{
+					{Name: "status", Type: &ast.FieldType{Name: "Status"}},
+					{Name: "lines", Type: &ast.FieldType{Name: "Line", IsArray: true}},
+					{Name: "labels", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "string"}},
+					{Name: "payment", Type: &ast.FieldType{Name: "Payment"}},
+				},
+			},
+			{
+				Name: "Line",
+				Fields: []*ast.Field{
+					{Name: "amount", Type: &ast.FieldType{Name: "int64", IsBuiltin: true}},
+				},
+			},
+			{Name: "Card", Fields: []*ast.Field{{Name: "last4", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+			{Name: "Transfer", Fields: []*ast.Field{{Name: "iban", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+		},
+		Unions: []*ast.Union{
+			{Name: "Payment", Options: []string{"Card", "Transfer"}},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "BillingService",
+				Methods: []*ast.Method{
+					{Name: "CreateInvoice", InputType: "Invoice", OutputType: "Invoice"},
+				},
+			},
+		},
+	}
+
+	output := NewGraphQLGeneratorWithOptions(&GraphQLOptions{TypePrefix: "Billing_"}).Generate(schema)
+	expected := []string{
+		"enum Billing_Status {",
+		"type Billing_Invoice {",
+		"input Billing_InvoiceInput {",
+		"  status: Billing_Status\n",
+		"  lines: [Billing_Line]\n",
+		"  labels: [Billing_StringStringEntry!]\n",
+		"  labels: [Billing_StringStringEntryInput!]\n",
+		"  payment: Billing_Payment\n",
+		"  payment: Billing_PaymentInput\n",
+		"union Billing_Payment = Billing_Card | Billing_Transfer",
+		"input Billing_PaymentInput @oneOf {\n  card: Billing_CardInput\n",
+		"createInvoice(input: Billing_InvoiceInput): Billing_Invoice",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	// Without root types, the schema only declares the types to merge
+	output = NewGraphQLGeneratorWithOptions(&GraphQLOptions{SkipRootTypes: true}).Generate(schema)
+	if !strings.Contains(output, "type Invoice {") {
+		t.Errorf("expected the types without root types, got:\n%s", output)
+	}
+	for _, unwanted := range []string{"type Query", "type Mutation", "createInvoice"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("expected output without root types not to contain %q", unwanted)
+		}
+	}
+}
+
+func TestGraphQLGenerator_FieldArgumentInputTypes(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{
+				Name: "Filter",
+				Fields: []*ast.Field{
+					{Name: "term", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+				},
+			},
+			{
+				Name: "User",
+				Fields: []*ast.Field{
+					{
+						Name: "posts",
+						Type: &ast.FieldType{Name: "string", IsBuiltin: true, IsArray: true},
+						Arguments: []*ast.FieldArgument{
+							{Name: "filter", Type: &ast.FieldType{Name: "Filter"}},
+							{Name: "ids", Type: &ast.FieldType{Name: "string", IsBuiltin: true, IsArray: true}},
+							{Name: "limit", Type: &ast.FieldType{Name: "int32", IsBuiltin: true}, Default: "10"},
+						},
+					},
+				},
+			},
+			{
+				Name:   "GetUserRequest",
+				Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "UserService",
+				Methods: []*ast.Method{
+					{Name: "GetUser", InputType: "GetUserRequest", OutputType: "User"},
+				},
+			},
+		},
+	}
+
+	output := NewGraphQLGenerator().Generate(schema)
+
+	if !strings.Contains(output, "input Filter {") {
+		t.Errorf("expected Filter to be generated as an input type, got:\n%s", output)
+	}
+
+	if !strings.Contains(output, "posts(filter: Filter, ids: [String!], limit: Int = 10): [String]") {
+		t.Errorf("expected field arguments with input types and defaults, got:\n%s", output)
+	}
+}
+
+func TestGraphQLGenerator_Directives(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{
+			{
+				Name:        "Role",
+				Values:      []*ast.EnumValue{{Name: "ADMIN"}},
+				Annotations: &ast.FormatAnnotations{GraphQL: []string{`"@auth(requires: ADMIN)"`}},
+			},
+		},
+		Types: []*ast.Type{
+			{
+				Name: "User",
+				Fields: []*ast.Field{
+					{
+						Name:        "id",
+						Type:        &ast.FieldType{Name: "string", IsBuiltin: true},
+						Annotations: &ast.FormatAnnotations{GraphQL: []string{`"@external"`}},
+					},
+				},
+				Annotations: &ast.FormatAnnotations{GraphQL: []string{`@key(fields: "id")`}},
+			},
+			{Name: "Photo", Fields: []*ast.Field{{Name: "url", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+		},
+		Unions: []*ast.Union{
+			{
+				Name:        "Media",
+				Options:     []string{"Photo"},
+				Annotations: &ast.FormatAnnotations{GraphQL: []string{"@cacheControl(maxAge: 60)"}},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "UserService",
+				Methods: []*ast.Method{
+					{
+						Name:        "GetUser",
+						InputType:   "User",
+						OutputType:  "User",
+						Annotations: &ast.FormatAnnotations{GraphQL: []string{"@auth(requires: ADMIN)"}},
+					},
+				},
+			},
+		},
+	}
+
+	output := NewGraphQLGenerator().Generate(schema)
+
+	expected := []string{
+		"enum Role @auth(requires: ADMIN) {",
+		`type User @key(fields: "id") {`,
+		"  id: String @external\n",
+		"union Media @cacheControl(maxAge: 60) = Photo",
+		"getUser(input: UserInput): User @auth(requires: ADMIN)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	// Directives are not valid on input objects unless declared for INPUT_OBJECT
+	if strings.Contains(output, "input UserInput @key") {
+		t.Error("expected type directives to be omitted on input types")
+	}
+}
+
+// extendsTestSchema returns a resolved schema where Admin extends User, which is
+// returned by a method, and Auditable, which is only used as a base type
+func extendsTestSchema(t *testing.T) *ast.Schema {
+	schema := &ast.Schema{
+		Namespace: "com.example",
+		Types: []*ast.Type{
+			{
+				Name:      "Auditable",
+				Namespace: "com.example",
+				Fields: []*ast.Field{
+					{Name: "createdAt", Type: &ast.FieldType{Name: "timestamp"}, Number: 10, HasNumber: true},
+				},
+			},
+			{
+				Name:      "User",
+				Namespace: "com.example",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string"}, Required: true, Number: 1, HasNumber: true},
+				},
+			},
+			{
+				Name:      "Admin",
+				Namespace: "com.example",
+				Extends:   []string{"User", "Auditable"},
+				Fields: []*ast.Field{
+					{Name: "role", Type: &ast.FieldType{Name: "string"}, Required: true, Number: 20, HasNumber: true},
+				},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name:      "UserService",
+				Namespace: "com.example",
+				Methods: []*ast.Method{
+					{Name: "GetUser", InputType: "User", OutputType: "User"},
+					{Name: "GetAdmin", InputType: "User", OutputType: "Admin"},
+				},
+			},
+		},
+	}
+	if err := schema.ResolveExtends(); err != nil {
+		t.Fatalf("ResolveExtends failed: %v", err)
+	}
+	return schema
+}
+
+func TestGraphQLGenerator_Extends(t *testing.T) {
+	output := NewGraphQLGenerator().Generate(extendsTestSchema(t))
+
+	expected := []string{
+		// Auditable is only a base type and becomes a plain interface
+		"interface Auditable {\n  createdAt: String\n}",
+		// User is also used directly, so it keeps its object type
+		"interface UserInterface {\n  id: String!\n}",
+		"type User implements UserInterface {",
+		"type Admin implements UserInterface & Auditable {\n  id: String!\n  createdAt: String\n  role: String!\n}",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "type Auditable") {
+		t.Error("expected a base type that is never referenced not to be emitted as an object type")
+	}
+	if strings.Contains(output, "input UserInput implements") {
+		t.Error("expected input types not to implement interfaces")
+	}
+}
+
+// emptyMethodTestSchema returns a service with methods that take or return nothing
+func emptyMethodTestSchema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "com.example",
+		Types: []*ast.Type{
+			{
+				Name:      "ResetRequest",
+				Namespace: "com.example",
+				Fields: []*ast.Field{
+					{Name: "scope", Type: &ast.FieldType{Name: "string"}, Required: true, Number: 1, HasNumber: true},
+				},
+			},
+			{
+				Name:      "Status",
+				Namespace: "com.example",
+				Fields: []*ast.Field{
+					{Name: "healthy", Type: &ast.FieldType{Name: "bool"}, Required: true, Number: 1, HasNumber: true},
+				},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name:      "HealthService",
+				Namespace: "com.example",
+				Methods: []*ast.Method{
+					{Name: "Ping", HTTPMethod: "GET"},
+					{Name: "Reset", InputType: "ResetRequest", HTTPMethod: "POST"},
+					{Name: "GetStatus", OutputType: "Status"},
+				},
+			},
+		},
+	}
+}
+
+func TestGraphQLGenerator_EmptyMethodTypes(t *testing.T) {
+	output := NewGraphQLGenerator().Generate(emptyMethodTestSchema())
+
+	expected := []string{
+		"ping: Boolean",
+		"reset(input: ResetRequest): Boolean",
+		"getStatus: Status",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "(input: )") {
+		t.Errorf("expected methods without input to take no arguments, got:\n%s", output)
+	}
+}
+
+func mapEntrySchema(fields ...*ast.Field) *ast.Schema {
+	return &ast.Schema{Types: []*ast.Type{{Name: "Stats", Fields: fields}}}
+}
+
+func mapField(name, key, value string, graphQLMap *ast.GraphQLMap) *ast.Field {
+	field := &ast.Field{Name: name, Type: &ast.FieldType{Name: "map", IsMap: true, MapKey: key, MapValue: value}}
+	if graphQLMap != nil {
+		field.Annotations = &ast.FormatAnnotations{GraphQLMap: graphQLMap}
+	}
+	return field
+}
+
+func TestGraphQLGenerator_MapEntriesShareGraphQLTypes(t *testing.T) {
+	output := NewGraphQLGenerator().Generate(mapEntrySchema(
+		mapField("counts", "string", "int32", nil),
+		mapField("totals", "string", "int64", nil),
+	))
+	if n := strings.Count(output, "type StringIntEntry {"); n != 1 {
+		t.Errorf("Expected one StringIntEntry for int32 and int64 values, got %d:\n%s", n, output)
+	}
+	if !strings.Contains(output, "counts: [StringIntEntry!]") || !strings.Contains(output, "totals: [StringIntEntry!]") {
+		t.Errorf("Expected both maps to use StringIntEntry, got:\n%s", output)
+	}
+}
+
+func TestGraphQLGenerator_MapEntryNames(t *testing.T) {
+	gen := NewGraphQLGeneratorWithOptions(&GraphQLOptions{MapEntrySuffix: "Pair", TypePrefix: "Billing_"})
+	schema := mapEntrySchema(
+		mapField("labels", "string", "string", &ast.GraphQLMap{Entry: "LabelEntry"}),
+		mapField("counts", "string", "int32", nil),
+	)
+	schema.Services = []*ast.Service{{Name: "StatsService", Methods: []*ast.Method{
+		{Name: "RecordStats", InputType: "Stats", OutputType: "Stats"},
+	}}}
+	output := gen.Generate(schema)
+	for _, want := range []string{
+		"type Billing_LabelEntry {",
+		"input Billing_LabelEntryInput {",
+		"labels: [Billing_LabelEntry!]",
+		"type Billing_StringIntPair {",
+		"counts: [Billing_StringIntPair!]",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestGraphQLGenerator_MapScalar(t *testing.T) {
+	output := NewGraphQLGenerator().Generate(mapEntrySchema(
+		mapField("metadata", "string", "string", &ast.GraphQLMap{As: ast.GraphQLMapJSON}),
+		mapField("counts", "string", "int32", nil),
+	))
+	if !strings.Contains(output, "scalar JSON\n") || !strings.Contains(output, "metadata: JSON\n") {
+		t.Errorf("Expected metadata to render as the JSON scalar, got:\n%s", output)
+	}
+	if strings.Contains(output, "StringStringEntry") || !strings.Contains(output, "counts: [StringIntEntry!]") {
+		t.Errorf("Expected entries for counts only, got:\n%s", output)
+	}
+
+	gen := NewGraphQLGeneratorWithOptions(&GraphQLOptions{MapScalar: "Map"})
+	output = gen.Generate(mapEntrySchema(
+		mapField("metadata", "string", "string", nil),
+		mapField("counts", "string", "int32", &ast.GraphQLMap{As: ast.GraphQLMapEntries}),
+	))
+	if !strings.Contains(output, "scalar Map\n") || !strings.Contains(output, "metadata: Map\n") || !strings.Contains(output, "counts: [StringIntEntry!]") {
+		t.Errorf("Expected maps to render as Map unless they set entries, got:\n%s", output)
+	}
+}
+
+func TestGraphQLGenerator_MapEntryCollisions(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *ast.Schema
+		want   string
+	}{
+		{
+			"entry name used by different maps",
+			mapEntrySchema(
+				mapField("labels", "string", "string", &ast.GraphQLMap{Entry: "Entry"}),
+				mapField("counts", "string", "int32", &ast.GraphQLMap{Entry: "Entry"}),
+			),
+			"the entry type Entry is used by maps of both String, String and String, Int",
+		},
+		{
+			"entry name of a declaration",
+			&ast.Schema{Types: []*ast.Type{
+				{Name: "StringIntEntry"},
+				{Name: "Stats", Fields: []*ast.Field{mapField("counts", "string", "int32", nil)}},
+			}},
+			"the entry type StringIntEntry of map<string, int32> has the name of type StringIntEntry",
+		},
+	}
+	for _, tt := range tests {
+		output := NewGraphQLGenerator().Generate(tt.schema)
+		if !strings.HasPrefix(output, "# ERROR: ") || !strings.Contains(output, tt.want) {
+			t.Errorf("%s: expected an error containing %q, got:\n%s", tt.name, tt.want, output)
+		}
+	}
+}
+
+func TestGraphQLGenerator_BytesScalar(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{{Name: "File", Fields: []*ast.Field{
+			{Name: "content", Type: &ast.FieldType{Name: "bytes", IsBuiltin: true}, Required: true},
+		}}},
+	}
+
+	output := NewGraphQLGenerator().Generate(schema)
+	if !strings.Contains(output, "content: String!") || strings.Contains(output, "scalar") {
+		t.Errorf("Expected bytes to be strings without a scalar by default, got:\n%s", output)
+	}
+
+	gen := NewGraphQLGeneratorWithOptions(&GraphQLOptions{ScalarMappings: map[string]string{"bytes": "Base64"}})
+	output = gen.Generate(schema)
+	for _, s := range []string{
+		`"Binary data encoded as a base64 string: RFC 4648, standard alphabet, with padding"`,
+		`scalar Base64 @specifiedBy(url: "https://datatracker.ietf.org/doc/html/rfc4648#section-4")`,
+		"content: Base64!",
+	} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Expected output to contain %q, got:\n%s", s, output)
+		}
+	}
+}
+
+// generateSDL generates the GraphQL schema of a TypeMUX source and checks that
+// it is valid SDL
+func generateSDL(t *testing.T, source string) string {
+	t.Helper()
+	schema, err := (&loader.Loader{}).LoadContent("schema.typemux", []byte(source))
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	output := NewGraphQLGenerator().Generate(schema)
+	if _, err := gqlparser.LoadSchema(&gqlast.Source{Name: "schema.graphql", Input: output}); err != nil {
+		t.Fatalf("Expected valid SDL, got %v:\n%s", err, output)
+	}
+	return output
+}
+
+func TestGraphQLGenerator_FieldArgumentTypesAreInputs(t *testing.T) {
+	output := generateSDL(t, `
+type Post {
+  id: string @required
+}
+
+type PostFilter {
+  published: bool?
+  authorId: string?
+}
+
+type Query {
+  searchPosts(query: string @required, filter: PostFilter?): []Post
+}
+
+type AdminQuery {
+  posts(filter: PostFilter?): []Post
+}
+`)
+	for _, want := range []string{
+		"input PostFilter {",
+		"searchPosts(query: String!, filter: PostFilter): [Post]",
+		"posts(filter: PostFilter): [Post]",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "type PostFilter") {
+		t.Errorf("Expected PostFilter to be emitted only as an input, got:\n%s", output)
+	}
+}
+
+func TestGraphQLGenerator_MapEntryInputsOnlyForInputs(t *testing.T) {
+	output := generateSDL(t, `
+type User {
+  id: string @required
+}
+
+type Product {
+  id: string @required
+}
+
+type Inventory {
+  friends: map<string, User>
+  products: map<string, Product>
+}
+
+type UpdateCartRequest {
+  items: map<string, Product> @required
+  nested: map<string, map<string, int32>>
+}
+
+service InventoryService {
+  rpc GetInventory(UpdateCartRequest) returns (Inventory)
+  rpc UpdateCart(UpdateCartRequest) returns (Product)
+}
+`)
+	for _, want := range []string{
+		"input StringProductEntryInput {\n  key: String!\n  value: ProductInput!\n}",
+		"items: [StringProductEntryInput!]!",
+		"input MapWrapper0Input {",
+		"input StringIntEntryInput {",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "StringUserEntryInput") {
+		t.Errorf("Expected no input entry type for a map only outputs have, got:\n%s", output)
+	}
+}
+
+const flagsTestSchema = `
+namespace acme
+
+/// Access rights
+enum Permission @flags {
+  NONE = 0
+  READ
+  WRITE
+}
+
+type Grant {
+  permissions: Permission @required
+  history: []Permission
+  byUser: map<string, Permission>
+}
+
+service GrantService {
+  rpc Update(Grant) returns (Grant)
+}
+`
+
+func TestGraphQLGenerator_FlagsAreInts(t *testing.T) {
+	output := generateSDL(t, flagsTestSchema)
+	for _, want := range []string{
+		"Bit flags: fields of this type are Int bitmasks of these values.",
+		"enum Permission {",
+		"  permissions: Int!\n  history: [Int]\n",
+		"input StringPermissionEntryInput {\n  key: String!\n  value: Int!\n}",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// commentStyle is how a file format writes comments
+type commentStyle struct {
+	line  string // Prefix of a line comment, when the format has them
+	open  string // Start of a block comment, for formats without line comments
+	close string // End of a block comment
+}
+
+// commentStyles are the comment styles of generated files by extension; files of
+// other extensions, such as JSON, get no header
+var commentStyles = map[string]commentStyle{
+	".go":      {line: "//"},
+	".java":    {line: "//"},
+	".cs":      {line: "//"},
+	".proto":   {line: "//"},
+	".ts":      {line: "//"},
+	".js":      {line: "//"},
+	".graphql": {line: "#"},
+	".yaml":    {line: "#"},
+	".yml":     {line: "#"},
+	".md":      {open: "<!--", close: "-->"},
+	".html":    {open: "<!--", close: "-->"},
+}
+
+// Wrap applies the template overrides and the header of the options to the files
+// of a format's generator. Lookup returns wrapped generators.
+func Wrap(format string, gen Generator) Generator {
+	return WithHeader(Templated(format, gen))
+}
+
+// WithHeader wraps a generator so that Options.Header is prepended to every file
+// as a comment in the syntax of the file. ${name} in the header is replaced by
+// the variable of that name: date (YYYY-MM-DD), schema_version, file, and those
+// of Options.HeaderVariables.
+func WithHeader(gen Generator) Generator {
+	return GeneratorFunc(func(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error) {
+		files, err := gen.Generate(ctx, schema, opts)
+		if err != nil || strings.TrimSpace(opts.Header) == "" {
+			return files, err
+		}
+
+		variables := map[string]string{
+			"date":           time.Now().Format("2006-01-02"),
+			"schema_version": schema.Version,
+		}
+		for name, value := range opts.HeaderVariables {
+			variables[name] = value
+		}
+
+		for filePath, content := range files {
+			style, ok := commentStyles[strings.ToLower(path.Ext(filePath))]
+			if !ok {
+				continue
+			}
+			variables["file"] = filePath
+			header := os.Expand(opts.Header, func(name string) string {
+				if value, ok := variables[name]; ok {
+					return value
+				}
+				return "${" + name + "}"
+			})
+			files[filePath] = prependComment(content, header, style)
+		}
+		return files, nil
+	})
+}
+
+// prependComment adds text as a comment at the start of a file, after an HTML
+// doctype, separated from the content by a blank line
+func prependComment(content []byte, text string, style commentStyle) []byte {
+	var sb strings.Builder
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if style.line != "" {
+		for _, line := range lines {
+			sb.WriteString(strings.TrimRight(style.line+" "+line, " "))
+			sb.WriteString("\n")
+		}
+	} else {
+		sb.WriteString(style.open + "\n")
+		for _, line := range lines {
+			// The block must not end early
+			sb.WriteString(strings.ReplaceAll(line, style.close, ""))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(style.close + "\n")
+	}
+	sb.WriteString("\n")
+
+	body := string(content)
+	if strings.HasPrefix(strings.ToLower(body), "<!doctype") {
+		end := strings.Index(body, "\n") + 1
+		if end == 0 {
+			end = len(body)
+		}
+		return []byte(body[:end] + sb.String() + body[end:])
+	}
+	return []byte(sb.String() + body)
+}
@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func TestWithHeader(t *testing.T) {
+	files := map[string]string{
+		"types.go":      "package api\n",
+		"schema.proto":  "syntax = \"proto3\";\n",
+		"openapi.yaml":  "openapi: 3.0.0\n",
+		"API.md":        "# API\n",
+		"html/api.html": "<!DOCTYPE html>\n<html></html>\n",
+		"schema.json":   "{}\n",
+	}
+	gen := WithHeader(GeneratorFunc(func(context.Context, *ast.Schema, Options) (map[string][]byte, error) {
+		result := make(map[string][]byte)
+		for path, content := range files {
+			result[path] = []byte(content)
+		}
+		return result, nil
+	}))
+
+	schema := &ast.Schema{Version: "2.1.0"}
+	opts := Options{
+		Header:          "Copyright ${date} Acme\n\n${file} from schema ${schema_version} by ${typemux_version} ${unknown}\n",
+		HeaderVariables: map[string]string{"typemux_version": "1.0.0"},
+	}
+	generated, err := gen.Generate(context.Background(), schema, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Now().Format("2006-01-02")
+	tests := map[string]string{
+		"types.go":      "// Copyright " + date + " Acme\n//\n// types.go from schema 2.1.0 by 1.0.0 ${unknown}\n\npackage api\n",
+		"schema.proto":  "// Copyright " + date + " Acme\n//\n// schema.proto from schema 2.1.0 by 1.0.0 ${unknown}\n\nsyntax = \"proto3\";\n",
+		"openapi.yaml":  "# Copyright " + date + " Acme\n#\n# openapi.yaml from schema 2.1.0 by 1.0.0 ${unknown}\n\nopenapi: 3.0.0\n",
+		"API.md":        "<!--\nCopyright " + date + " Acme\n\nAPI.md from schema 2.1.0 by 1.0.0 ${unknown}\n-->\n\n# API\n",
+		"html/api.html": "<!DOCTYPE html>\n<!--\nCopyright " + date + " Acme\n\nhtml/api.html from schema 2.1.0 by 1.0.0 ${unknown}\n-->\n\n<html></html>\n",
+		"schema.json":   "{}\n",
+	}
+	for path, want := range tests {
+		if got := string(generated[path]); got != want {
+			t.Errorf("%s:\ngot:\n%s\nwant:\n%s", path, got, want)
+		}
+	}
+}
+
+func TestWithHeader_Lookup(t *testing.T) {
+	gen, err := Lookup("graphql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := gen.Generate(context.Background(), generatorTestSchema("api"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(files["schema.graphql"]), "SPDX") {
+		t.Error("Expected no header without Options.Header")
+	}
+
+	files, err = gen.Generate(context.Background(), generatorTestSchema("api"), Options{Header: "SPDX-License-Identifier: MIT\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(files["schema.graphql"]), "# SPDX-License-Identifier: MIT\n\n") {
+		t.Errorf("Expected a GraphQL comment header, got:\n%s", files["schema.graphql"])
+	}
+}
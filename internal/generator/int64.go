@@ -0,0 +1,31 @@
+package generator
+
+// Int64Encoding selects how int64 and uint64 values appear in the JSON-facing
+// outputs: the OpenAPI schemas, the GraphQL scalars, and the Go struct tags.
+// JSON numbers are read as doubles by JavaScript and many other clients, which
+// silently lose precision above 2^53.
+type Int64Encoding string
+
+const (
+	// Int64Number encodes 64-bit integers as JSON numbers, and as GraphQL Int.
+	Int64Number Int64Encoding = "number"
+	// Int64String encodes 64-bit integers as JSON strings, as proto3 JSON does,
+	// and as GraphQL String.
+	Int64String Int64Encoding = "string"
+	// Int64Scalar encodes 64-bit integers as a GraphQL custom scalar (BigInt, or
+	// the scalar mapped to int64 and uint64), and as JSON strings elsewhere.
+	Int64Scalar Int64Encoding = "scalar"
+)
+
+// defaultInt64Scalar is the GraphQL scalar of 64-bit integers under the scalar policy
+const defaultInt64Scalar = "BigInt"
+
+// quoted reports whether 64-bit integers are encoded as JSON strings
+func (e Int64Encoding) quoted() bool {
+	return e == Int64String || e == Int64Scalar
+}
+
+// is64Bit reports whether a type name is a 64-bit integer type
+func is64Bit(typeName string) bool {
+	return typeName == "int64" || typeName == "uint64"
+}
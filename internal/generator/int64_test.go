@@ -0,0 +1,156 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func int64Schema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "ledger",
+		Types: []*ast.Type{
+			{Name: "Account", Fields: []*ast.Field{
+				{Name: "id", Type: &ast.FieldType{Name: "int64", IsBuiltin: true}, Required: true},
+				{Name: "balance", Type: &ast.FieldType{Name: "uint64", IsBuiltin: true}, Default: "0"},
+				{Name: "limit", Type: &ast.FieldType{Name: "int64", IsBuiltin: true, Optional: true}},
+				{Name: "history", Type: &ast.FieldType{Name: "int64", IsBuiltin: true, IsArray: true}},
+				{Name: "totals", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "uint64"}},
+				{Name: "count", Type: &ast.FieldType{Name: "int32", IsBuiltin: true}},
+			}},
+		},
+	}
+}
+
+func TestGraphQLGenerator_Int64Encoding(t *testing.T) {
+	tests := []struct {
+		opts GraphQLOptions
+		want []string
+		not  []string
+	}{
+		{GraphQLOptions{}, []string{"id: Int!", "balance: Int"}, []string{"scalar"}},
+		{GraphQLOptions{Int64: Int64String}, []string{"id: String!", "balance: String"}, []string{"scalar"}},
+		{GraphQLOptions{Int64: Int64Scalar}, []string{"scalar BigInt", "id: BigInt!", "balance: BigInt", "count: Int"}, nil},
+		{
+			GraphQLOptions{Int64: Int64Scalar, ScalarMappings: map[string]string{"int64": "Long"}},
+			[]string{"scalar Long", "id: Long!", "balance: BigInt"}, nil,
+		},
+	}
+
+	for _, tt := range tests {
+		opts := tt.opts
+		output := NewGraphQLGeneratorWithOptions(&opts).Generate(int64Schema())
+		for _, s := range tt.want {
+			if !strings.Contains(output, s) {
+				t.Errorf("%q: expected output to contain %q, got:\n%s", tt.opts.Int64, s, output)
+			}
+		}
+		for _, s := range tt.not {
+			if strings.Contains(output, s) {
+				t.Errorf("%q: expected output not to contain %q, got:\n%s", tt.opts.Int64, s, output)
+			}
+		}
+	}
+}
+
+func TestOpenAPIGenerator_Int64Encoding(t *testing.T) {
+	account := int64Schema().Types[0]
+
+	gen := NewOpenAPIGenerator()
+	id := gen.convertFieldToProperty(account.Fields[0], nil)
+	if id.Type != "integer" || id.Format != "int64" {
+		t.Errorf("Expected an int64 integer by default, got %s/%s", id.Type, id.Format)
+	}
+
+	gen = NewOpenAPIGeneratorWithOptions(&OpenAPIOptions{Int64: Int64String})
+	id = gen.convertFieldToProperty(account.Fields[0], nil)
+	if id.Type != "string" || id.Format != "int64" {
+		t.Errorf("Expected an int64 string, got %s/%s", id.Type, id.Format)
+	}
+	balance := gen.convertFieldToProperty(account.Fields[1], nil)
+	if balance.Type != "string" || balance.Format != "uint64" || balance.Minimum != nil || balance.Default != "0" {
+		t.Errorf("Expected a uint64 string without a minimum and with a string default, got %+v", balance)
+	}
+	history := gen.convertFieldToProperty(account.Fields[3], nil)
+	if history.Items == nil || history.Items.Type != "string" || history.Items.Format != "int64" {
+		t.Errorf("Expected list items to be int64 strings, got %+v", history.Items)
+	}
+	totals := gen.convertFieldToProperty(account.Fields[4], nil)
+	if totals.AdditionalProperties == nil || totals.AdditionalProperties.Type != "string" {
+		t.Errorf("Expected map values to be strings, got %+v", totals.AdditionalProperties)
+	}
+	count := gen.convertFieldToProperty(account.Fields[5], nil)
+	if count.Type != "integer" {
+		t.Errorf("Expected int32 to stay an integer, got %s", count.Type)
+	}
+}
+
+func TestGoGenerator_Int64Encoding(t *testing.T) {
+	tests := []struct {
+		opts GoOptions
+		want []string
+		not  []string
+	}{
+		{
+			GoOptions{},
+			[]string{"Id int64 `json:\"id\"`", "History []int64 `json:\"history\"`", "Totals map[string]uint64 `json:\"totals\"`"},
+			[]string{",string", "StringInt64"},
+		},
+		{
+			GoOptions{Int64: Int64String},
+			[]string{
+				"Id int64 `json:\"id,string\"`",
+				"Balance uint64 `json:\"balance,string\"`",
+				"Limit int64 `json:\"limit,omitempty,string\"`",
+				"History []StringInt64 `json:\"history\"`",
+				"Totals map[string]StringUint64 `json:\"totals\"`",
+				"Count int32 `json:\"count\"`",
+				"type StringInt64 int64",
+				"type StringUint64 uint64",
+				"strconv.ParseUint(s, 10, 64)",
+				`"strconv"`,
+			},
+			nil,
+		},
+		{
+			GoOptions{Int64: Int64Scalar, OptionalFields: GoOptionalWrapper},
+			[]string{"Limit *int64 `json:\"limit,omitempty,string\"`"},
+			[]string{"Null["},
+		},
+	}
+
+	for _, tt := range tests {
+		opts := tt.opts
+		output := NewGoGeneratorWithOptions(&opts).Generate(int64Schema())
+		for _, s := range tt.want {
+			if !strings.Contains(output, s) {
+				t.Errorf("%q: expected output to contain %q, got:\n%s", tt.opts.Int64, s, output)
+			}
+		}
+		for _, s := range tt.not {
+			if strings.Contains(output, s) {
+				t.Errorf("%q: expected output not to contain %q, got:\n%s", tt.opts.Int64, s, output)
+			}
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "types.go", output, parser.AllErrors); err != nil {
+			t.Errorf("%q: generated code does not parse: %v", tt.opts.Int64, err)
+		}
+	}
+}
+
+func TestGoGenerator_Int64ProtoConversion(t *testing.T) {
+	output := NewGoGeneratorWithOptions(&GoOptions{ProtoPackage: "example.com/ledger/pb", Int64: Int64String}).Generate(int64Schema())
+	for _, s := range []string{
+		"p.History[i] = int64(v)",
+		"m.History[i] = StringInt64(v)",
+		"p.Totals[k] = uint64(v)",
+		"m.Totals[k] = StringUint64(v)",
+	} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Expected output to contain %q, got:\n%s", s, output)
+		}
+	}
+}
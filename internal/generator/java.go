@@ -0,0 +1,543 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// JavaOptions configures the Java generator.
+type JavaOptions struct {
+	// Style selects the class style: "record" (default) emits Java 16+ records,
+	// "pojo" emits classes with getters, setters, and a builder.
+	Style string
+
+	// PackagePrefix is prepended to every namespace-derived package name
+	// (e.g., "org.acme" turns namespace "users" into "org.acme.users").
+	PackagePrefix string
+}
+
+// JavaGenerator generates Java source files (records or POJOs) with Jackson
+// annotations from TypeMUX schemas.
+type JavaGenerator struct {
+	opts      JavaOptions
+	flagEnums map[string]bool // Flags enums, whose fields are int bitmasks
+}
+
+// NewJavaGenerator creates a new Java code generator using records.
+func NewJavaGenerator() *JavaGenerator {
+	return NewJavaGeneratorWithOptions(nil)
+}
+
+// NewJavaGeneratorWithOptions creates a new Java code generator with the given options.
+func NewJavaGeneratorWithOptions(opts *JavaOptions) *JavaGenerator {
+	g := &JavaGenerator{}
+	if opts != nil {
+		g.opts = *opts
+	}
+	if g.opts.Style == "" {
+		g.opts.Style = "record"
+	}
+	return g
+}
+
+// javaImports tracks the imports needed by a single compilation unit.
+type javaImports map[string]bool
+
+func (imports javaImports) add(path string) {
+	imports[path] = true
+}
+
+func (imports javaImports) String() string {
+	if len(imports) == 0 {
+		return ""
+	}
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var sb strings.Builder
+	for _, path := range paths {
+		sb.WriteString(fmt.Sprintf("import %s;\n", path))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// GenerateFiles creates one Java source file per enum, type, union, and service.
+// Returns a map of relative file path (e.g., "com/example/api/User.java") to file content.
+func (g *JavaGenerator) GenerateFiles(schema *ast.Schema) map[string]string {
+	files := make(map[string]string)
+	g.flagEnums = flagEnumNames(schema)
+
+	for _, enum := range schema.Enums {
+		pkg := g.packageName(g.namespaceOf(enum.Namespace, schema))
+		if enum.Flags {
+			files[g.filePath(pkg, enum.Name)] = g.generateFlags(pkg, enum)
+			continue
+		}
+		files[g.filePath(pkg, enum.Name)] = g.generateEnum(pkg, enum)
+	}
+
+	for _, typ := range schema.Types {
+		pkg := g.packageName(g.namespaceOf(typ.Namespace, schema))
+		files[g.filePath(pkg, g.typeName(typ))] = g.generateType(pkg, typ)
+	}
+
+	for _, union := range schema.Unions {
+		pkg := g.packageName(g.namespaceOf(union.Namespace, schema))
+		files[g.filePath(pkg, union.Name)] = g.generateUnion(pkg, union)
+	}
+
+	for _, service := range schema.Services {
+		pkg := g.packageName(g.namespaceOf(service.Namespace, schema))
+		files[g.filePath(pkg, service.Name)] = g.generateService(pkg, service)
+	}
+
+	return files
+}
+
+// namespaceOf returns the element namespace, falling back to the schema namespace
+func (g *JavaGenerator) namespaceOf(namespace string, schema *ast.Schema) string {
+	if namespace != "" {
+		return namespace
+	}
+	if schema.Namespace != "" {
+		return schema.Namespace
+	}
+	return "api"
+}
+
+// packageName converts a namespace into a Java package name
+func (g *JavaGenerator) packageName(namespace string) string {
+	pkg := strings.ToLower(namespace)
+	pkg = strings.ReplaceAll(pkg, "-", "_")
+	if g.opts.PackagePrefix != "" {
+		pkg = g.opts.PackagePrefix + "." + pkg
+	}
+	return pkg
+}
+
+// filePath returns the relative source path for a top-level Java class
+func (g *JavaGenerator) filePath(pkg, className string) string {
+	return strings.ReplaceAll(pkg, ".", "/") + "/" + className + ".java"
+}
+
+// typeName returns the Java class name for a type
+func (g *JavaGenerator) typeName(typ *ast.Type) string {
+	return typ.Name
+}
+
+// header writes the common file preamble
+func (g *JavaGenerator) header(pkg string, imports javaImports) string {
+	var sb strings.Builder
+	sb.WriteString("// Code generated by TypeMUX. DO NOT EDIT.\n")
+	sb.WriteString(fmt.Sprintf("package %s;\n\n", pkg))
+	sb.WriteString(imports.String())
+	return sb.String()
+}
+
+// generateEnum generates a Java enum
+func (g *JavaGenerator) generateEnum(pkg string, enum *ast.Enum) string {
+	imports := javaImports{}
+	imports.add("com.fasterxml.jackson.annotation.JsonProperty")
+
+	var body strings.Builder
+	body.WriteString(g.formatJavadoc(enum.Doc.GetDoc("java"), ""))
+	body.WriteString(fmt.Sprintf("public enum %s {\n", enum.Name))
+	for i, value := range enum.Values {
+		body.WriteString(g.formatJavadoc(value.Doc.GetDoc("java"), "    "))
+		body.WriteString(fmt.Sprintf("    @JsonProperty(\"%s\")\n", value.Name))
+		body.WriteString(fmt.Sprintf("    %s", value.Name))
+		if i < len(enum.Values)-1 {
+			body.WriteString(",\n")
+		} else {
+			body.WriteString(";\n")
+		}
+	}
+	body.WriteString("}\n")
+
+	return g.header(pkg, imports) + body.String()
+}
+
+// generateFlags generates the int constants of a flags enum. A Java enum value
+// cannot hold a combination of flags, so fields of the enum are int bitmasks.
+func (g *JavaGenerator) generateFlags(pkg string, enum *ast.Enum) string {
+	var body strings.Builder
+	doc := enum.Doc.GetDoc("java")
+	if doc != "" {
+		doc += "\n\n"
+	}
+	body.WriteString(g.formatJavadoc(doc+"Bit flags: fields of this type are int bitmasks of these values.", ""))
+	body.WriteString(fmt.Sprintf("public final class %s {\n", enum.Name))
+	numbers := enum.ValueNumbers()
+	for i, value := range enum.Values {
+		body.WriteString(g.formatJavadoc(value.Doc.GetDoc("java"), "    "))
+		body.WriteString(fmt.Sprintf("    public static final int %s = %d;\n", value.Name, numbers[i]))
+	}
+	body.WriteString(fmt.Sprintf("\n    private %s() {\n    }\n\n", enum.Name))
+	body.WriteString("    /** Returns whether all the flags of flag are set in value. */\n")
+	body.WriteString("    public static boolean has(int value, int flag) {\n")
+	body.WriteString("        return (value & flag) == flag;\n")
+	body.WriteString("    }\n")
+	body.WriteString("}\n")
+
+	return g.header(pkg, javaImports{}) + body.String()
+}
+
+// javaField holds the resolved details of a single generated field
+type javaField struct {
+	field       *ast.Field
+	name        string
+	javaType    string
+	annotations []string
+}
+
+// resolveFields maps the schema fields of a type into Java fields
+func (g *JavaGenerator) resolveFields(typ *ast.Type, imports javaImports) []javaField {
+	var fields []javaField
+	for _, field := range typ.Fields {
+		if !field.ShouldIncludeInGenerator("java") {
+			continue
+		}
+		// Fields with arguments are resolvers, not data
+		if len(field.Arguments) > 0 {
+			continue
+		}
+
+		jsonName := field.Name
+		if field.JSONName != "" {
+			jsonName = field.JSONName
+		}
+
+		var annotations []string
+		imports.add("com.fasterxml.jackson.annotation.JsonProperty")
+		if field.Presence() == ast.PresenceRequired {
+			annotations = append(annotations, fmt.Sprintf("@JsonProperty(value = \"%s\", required = true)", jsonName))
+		} else {
+			annotations = append(annotations, fmt.Sprintf("@JsonProperty(\"%s\")", jsonName))
+		}
+
+		if field.JSONOmitEmpty {
+			imports.add("com.fasterxml.jackson.annotation.JsonInclude")
+			annotations = append(annotations, "@JsonInclude(JsonInclude.Include.NON_EMPTY)")
+		} else if field.JSONNullable {
+			imports.add("com.fasterxml.jackson.annotation.JsonInclude")
+			annotations = append(annotations, "@JsonInclude(JsonInclude.Include.ALWAYS)")
+		}
+
+		if field.Deprecated != nil {
+			annotations = append(annotations, "@Deprecated")
+		}
+
+		// Optional and nullable fields must be able to hold null, so use boxed types
+		boxed := field.Type.Optional || field.JSONNullable
+		fields = append(fields, javaField{
+			field:       field,
+			name:        g.fieldName(field.Name),
+			javaType:    g.mapTypeToJava(field.Type, boxed, imports),
+			annotations: annotations,
+		})
+	}
+	return fields
+}
+
+// generateType generates a Java record or POJO for a type
+func (g *JavaGenerator) generateType(pkg string, typ *ast.Type) string {
+	if g.opts.Style == "pojo" {
+		return g.generatePOJO(pkg, typ)
+	}
+	return g.generateRecord(pkg, typ)
+}
+
+// generateRecord generates a Java record for a type
+func (g *JavaGenerator) generateRecord(pkg string, typ *ast.Type) string {
+	imports := javaImports{}
+	fields := g.resolveFields(typ, imports)
+	className := g.typeName(typ)
+
+	var body strings.Builder
+	body.WriteString(g.formatJavadoc(typ.Doc.GetDoc("java"), ""))
+	if len(fields) == 0 {
+		body.WriteString(fmt.Sprintf("public record %s() {\n}\n", className))
+		return g.header(pkg, imports) + body.String()
+	}
+
+	body.WriteString(fmt.Sprintf("public record %s(\n", className))
+	for i, f := range fields {
+		body.WriteString(g.formatJavadoc(f.field.Doc.GetDoc("java"), "    "))
+		body.WriteString(fmt.Sprintf("    %s %s %s", strings.Join(f.annotations, " "), f.javaType, f.name))
+		if i < len(fields)-1 {
+			body.WriteString(",")
+		}
+		body.WriteString("\n")
+	}
+	body.WriteString(") {\n}\n")
+
+	return g.header(pkg, imports) + body.String()
+}
+
+// generatePOJO generates a Java class with getters, setters, and a builder
+func (g *JavaGenerator) generatePOJO(pkg string, typ *ast.Type) string {
+	imports := javaImports{}
+	fields := g.resolveFields(typ, imports)
+	className := g.typeName(typ)
+
+	var body strings.Builder
+	body.WriteString(g.formatJavadoc(typ.Doc.GetDoc("java"), ""))
+	body.WriteString(fmt.Sprintf("public class %s {\n", className))
+
+	// Fields
+	for _, f := range fields {
+		body.WriteString(g.formatJavadoc(f.field.Doc.GetDoc("java"), "    "))
+		for _, annotation := range f.annotations {
+			body.WriteString(fmt.Sprintf("    %s\n", annotation))
+		}
+		body.WriteString(fmt.Sprintf("    private %s %s;\n\n", f.javaType, f.name))
+	}
+
+	// Constructors
+	body.WriteString(fmt.Sprintf("    public %s() {\n    }\n\n", className))
+	body.WriteString(fmt.Sprintf("    private %s(Builder builder) {\n", className))
+	for _, f := range fields {
+		body.WriteString(fmt.Sprintf("        this.%s = builder.%s;\n", f.name, f.name))
+	}
+	body.WriteString("    }\n\n")
+
+	// Accessors
+	for _, f := range fields {
+		accessor := g.capitalize(f.name)
+		body.WriteString(fmt.Sprintf("    public %s get%s() {\n        return %s;\n    }\n\n", f.javaType, accessor, f.name))
+		body.WriteString(fmt.Sprintf("    public void set%s(%s %s) {\n        this.%s = %s;\n    }\n\n", accessor, f.javaType, f.name, f.name, f.name))
+	}
+
+	// Builder
+	body.WriteString("    public static Builder builder() {\n        return new Builder();\n    }\n\n")
+	body.WriteString("    public static final class Builder {\n")
+	for _, f := range fields {
+		body.WriteString(fmt.Sprintf("        private %s %s;\n", f.javaType, f.name))
+	}
+	if len(fields) > 0 {
+		body.WriteString("\n")
+	}
+	body.WriteString("        private Builder() {\n        }\n\n")
+	for _, f := range fields {
+		body.WriteString(fmt.Sprintf("        public Builder %s(%s %s) {\n            this.%s = %s;\n            return this;\n        }\n\n", f.name, f.javaType, f.name, f.name, f.name))
+	}
+	body.WriteString(fmt.Sprintf("        public %s build() {\n            return new %s(this);\n        }\n", className, className))
+	body.WriteString("    }\n")
+	body.WriteString("}\n")
+
+	return g.header(pkg, imports) + body.String()
+}
+
+// generateUnion generates a sealed interface with one record per union option
+func (g *JavaGenerator) generateUnion(pkg string, union *ast.Union) string {
+	imports := javaImports{}
+	imports.add("com.fasterxml.jackson.annotation.JsonProperty")
+	imports.add("com.fasterxml.jackson.annotation.JsonSubTypes")
+	imports.add("com.fasterxml.jackson.annotation.JsonTypeInfo")
+
+	var body strings.Builder
+	body.WriteString(g.formatJavadoc(union.Doc.GetDoc("java"), ""))
+	body.WriteString("@JsonTypeInfo(use = JsonTypeInfo.Id.NAME, include = JsonTypeInfo.As.PROPERTY, property = \"type\")\n")
+	body.WriteString("@JsonSubTypes({\n")
+	for i, option := range union.Options {
+		optionName := ast.GetUnqualifiedName(option)
+		body.WriteString(fmt.Sprintf("    @JsonSubTypes.Type(value = %s.%sValue.class, name = \"%s\")", union.Name, optionName, optionName))
+		if i < len(union.Options)-1 {
+			body.WriteString(",")
+		}
+		body.WriteString("\n")
+	}
+	body.WriteString("})\n")
+	body.WriteString(fmt.Sprintf("public sealed interface %s {\n", union.Name))
+	for _, option := range union.Options {
+		optionName := ast.GetUnqualifiedName(option)
+		body.WriteString(fmt.Sprintf("    record %sValue(@JsonProperty(\"value\") %s value) implements %s {\n    }\n",
+			optionName, g.classReference(option), union.Name))
+	}
+	body.WriteString("}\n")
+
+	return g.header(pkg, imports) + body.String()
+}
+
+// generateService generates a Java interface for a service
+func (g *JavaGenerator) generateService(pkg string, service *ast.Service) string {
+	imports := javaImports{}
+
+	var body strings.Builder
+	body.WriteString(g.formatJavadoc(service.Doc.GetDoc("java"), ""))
+	body.WriteString(fmt.Sprintf("public interface %s {\n", service.Name))
+	for i, method := range service.Methods {
+		if i > 0 {
+			body.WriteString("\n")
+		}
+		body.WriteString(g.formatJavadoc(method.Doc.GetDoc("java"), "    "))
+
+		inputType := g.classReference(method.InputType)
+		outputType := g.classReference(method.OutputType)
+		methodName := g.fieldName(method.Name)
+
+		// Methods without input take no parameter and methods without output return void
+		var params []string
+		if method.InputStream {
+			imports.add("java.util.Iterator")
+			params = append(params, fmt.Sprintf("Iterator<%s> input", inputType))
+		} else if method.HasInput() {
+			params = append(params, fmt.Sprintf("%s input", inputType))
+		}
+
+		if method.OutputStream {
+			imports.add("java.util.function.Consumer")
+			params = append(params, fmt.Sprintf("Consumer<%s> stream", outputType))
+			outputType = "void"
+		} else if !method.HasOutput() {
+			outputType = "void"
+		}
+		body.WriteString(fmt.Sprintf("    %s %s(%s);\n", outputType, methodName, strings.Join(params, ", ")))
+	}
+	body.WriteString("}\n")
+
+	return g.header(pkg, imports) + body.String()
+}
+
+// classReference returns the Java reference for a (possibly qualified) TypeMUX type name.
+// Qualified names map to fully-qualified Java class names since packages mirror namespaces.
+func (g *JavaGenerator) classReference(typeName string) string {
+	if strings.Contains(typeName, ".") {
+		parts := strings.Split(typeName, ".")
+		return g.packageName(strings.Join(parts[:len(parts)-1], ".")) + "." + parts[len(parts)-1]
+	}
+	return typeName
+}
+
+// mapTypeToJava maps a TypeMUX field type to a Java type
+func (g *JavaGenerator) mapTypeToJava(fieldType *ast.FieldType, boxed bool, imports javaImports) string {
+	if fieldType.IsMap {
+		imports.add("java.util.Map")
+		keyType := g.mapScalarToJava(fieldType.MapKey, true, imports)
+		valueType := "Object"
+		if valueFieldType := fieldType.GetMapValueType(); valueFieldType != nil {
+			valueType = g.mapTypeToJava(valueFieldType, true, imports)
+		}
+		javaType := fmt.Sprintf("Map<%s, %s>", keyType, valueType)
+		if fieldType.IsArray {
+			imports.add("java.util.List")
+			javaType = fmt.Sprintf("List<%s>", javaType)
+		}
+		return javaType
+	}
+
+	if fieldType.IsArray {
+		// Elements may be lists or maps themselves, as in [][]string or []map<string, int32>
+		imports.add("java.util.List")
+		return fmt.Sprintf("List<%s>", g.mapTypeToJava(fieldType.ElementType(), true, imports))
+	}
+
+	return g.mapScalarToJava(fieldType.Name, boxed, imports)
+}
+
+// mapScalarToJava maps a scalar or named TypeMUX type to a Java type
+func (g *JavaGenerator) mapScalarToJava(typeName string, boxed bool, imports javaImports) string {
+	primitives := map[string][2]string{
+		"string":  {"String", "String"},
+		"int32":   {"int", "Integer"},
+		"int64":   {"long", "Long"},
+		"uint8":   {"short", "Short"}, // Java has no unsigned types, widen to fit
+		"uint16":  {"int", "Integer"},
+		"uint32":  {"long", "Long"},
+		"uint64":  {"long", "Long"}, // Values above Long.MAX_VALUE wrap; use Long.toUnsignedString
+		"float32": {"float", "Float"},
+		"float64": {"double", "Double"},
+		"bool":    {"boolean", "Boolean"},
+		"bytes":   {"byte[]", "byte[]"},
+	}
+
+	if typeName == "timestamp" {
+		imports.add("java.time.Instant")
+		return "Instant"
+	}
+
+	if g.flagEnums[typeName] {
+		typeName = "int32"
+	}
+	if mapped, ok := primitives[typeName]; ok {
+		if boxed {
+			return mapped[1]
+		}
+		return mapped[0]
+	}
+
+	return g.classReference(typeName)
+}
+
+// fieldName converts a TypeMUX field name into a Java identifier in lowerCamelCase
+func (g *JavaGenerator) fieldName(name string) string {
+	if name == "" {
+		return name
+	}
+	parts := strings.Split(name, "_")
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		if i == 0 {
+			parts[i] = strings.ToLower(part[:1]) + part[1:]
+		} else {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	result := strings.Join(parts, "")
+	if javaReservedWords[result] {
+		result += "_"
+	}
+	return result
+}
+
+// capitalize capitalizes the first letter of a string
+func (g *JavaGenerator) capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// formatJavadoc formats documentation as a Javadoc comment with the given indentation
+func (g *JavaGenerator) formatJavadoc(doc, indent string) string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(indent + "/**\n")
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			sb.WriteString(indent + " *\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("%s * %s\n", indent, line))
+		}
+	}
+	sb.WriteString(indent + " */\n")
+	return sb.String()
+}
+
+// javaReservedWords lists Java keywords that cannot be used as identifiers
+var javaReservedWords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true,
+	"case": true, "catch": true, "char": true, "class": true, "const": true,
+	"continue": true, "default": true, "do": true, "double": true, "else": true,
+	"enum": true, "extends": true, "final": true, "finally": true, "float": true,
+	"for": true, "goto": true, "if": true, "implements": true, "import": true,
+	"instanceof": true, "int": true, "interface": true, "long": true, "native": true,
+	"new": true, "package": true, "private": true, "protected": true, "public": true,
+	"return": true, "short": true, "static": true, "strictfp": true, "super": true,
+	"switch": true, "synchronized": true, "this": true, "throw": true, "throws": true,
+	"transient": true, "try": true, "void": true, "volatile": true, "while": true,
+	"record": true, "sealed": true, "permits": true, "var": true, "yield": true,
+}
@@ -0,0 +1,247 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func javaTestSchema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "com.example.users",
+		Enums: []*ast.Enum{
+			{
+				Name:      "Status",
+				Namespace: "com.example.users",
+				Values: []*ast.EnumValue{
+					{Name: "ACTIVE", Number: 0},
+					{Name: "DISABLED", Number: 1},
+				},
+			},
+		},
+		Types: []*ast.Type{
+			{
+				Name:      "User",
+				Namespace: "com.example.users",
+				Doc:       &ast.Documentation{General: "A registered user"},
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string"}, Required: true},
+					{Name: "display_name", Type: &ast.FieldType{Name: "string"}, JSONName: "displayName", JSONOmitEmpty: true},
+					{Name: "age", Type: &ast.FieldType{Name: "int32", Optional: true}},
+					{Name: "login_count", Type: &ast.FieldType{Name: "int64"}},
+					{Name: "created_at", Type: &ast.FieldType{Name: "timestamp"}},
+					{Name: "tags", Type: &ast.FieldType{Name: "string", IsArray: true}},
+					{Name: "metadata", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "int32"}},
+					{Name: "status", Type: &ast.FieldType{Name: "Status"}},
+					{Name: "secret", Type: &ast.FieldType{Name: "string"}, ExcludeFrom: []string{"java"}},
+				},
+			},
+		},
+		Unions: []*ast.Union{
+			{
+				Name:      "Result",
+				Namespace: "com.example.users",
+				Options:   []string{"User", "Status"},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name:      "UserService",
+				Namespace: "com.example.users",
+				Methods: []*ast.Method{
+					{Name: "GetUser", InputType: "GetUserRequest", OutputType: "User"},
+					{Name: "WatchUsers", InputType: "WatchRequest", OutputType: "User", OutputStream: true},
+				},
+			},
+		},
+	}
+}
+
+func TestJavaGenerator_Record(t *testing.T) {
+	gen := NewJavaGenerator()
+	files := gen.GenerateFiles(javaTestSchema())
+
+	output, ok := files["com/example/users/User.java"]
+	if !ok {
+		t.Fatalf("Expected User.java in package path, got files: %v", files)
+	}
+
+	expected := []string{
+		"package com.example.users;",
+		"import com.fasterxml.jackson.annotation.JsonProperty;",
+		"import java.time.Instant;",
+		"import java.util.List;",
+		"import java.util.Map;",
+		"/**\n * A registered user\n */",
+		"public record User(",
+		`@JsonProperty(value = "id", required = true) String id`,
+		`@JsonProperty("displayName") @JsonInclude(JsonInclude.Include.NON_EMPTY) String displayName`,
+		`Integer age`,
+		`long loginCount`,
+		`Instant createdAt`,
+		`List<String> tags`,
+		`Map<String, Integer> metadata`,
+		`Status status`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+
+	if strings.Contains(output, "secret") {
+		t.Errorf("Expected excluded field to be omitted, got:\n%s", output)
+	}
+}
+
+func TestJavaGenerator_POJO(t *testing.T) {
+	gen := NewJavaGeneratorWithOptions(&JavaOptions{Style: "pojo", PackagePrefix: "org.acme"})
+	files := gen.GenerateFiles(javaTestSchema())
+
+	output, ok := files["org/acme/com/example/users/User.java"]
+	if !ok {
+		t.Fatalf("Expected User.java under prefixed package, got files: %v", files)
+	}
+
+	expected := []string{
+		"package org.acme.com.example.users;",
+		"public class User {",
+		"    private String displayName;",
+		"public String getDisplayName()",
+		"public void setDisplayName(String displayName)",
+		"public static Builder builder()",
+		"public Builder displayName(String displayName)",
+		"public User build()",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+}
+
+func TestJavaGenerator_EnumUnionService(t *testing.T) {
+	gen := NewJavaGenerator()
+	files := gen.GenerateFiles(javaTestSchema())
+
+	enum := files["com/example/users/Status.java"]
+	if !strings.Contains(enum, "public enum Status {") || !strings.Contains(enum, "@JsonProperty(\"ACTIVE\")\n    ACTIVE,") {
+		t.Errorf("Unexpected enum output:\n%s", enum)
+	}
+
+	union := files["com/example/users/Result.java"]
+	for _, exp := range []string{
+		"public sealed interface Result {",
+		`@JsonSubTypes.Type(value = Result.UserValue.class, name = "User")`,
+		"record UserValue(@JsonProperty(\"value\") User value) implements Result {",
+	} {
+		if !strings.Contains(union, exp) {
+			t.Errorf("Expected union to contain %q, got:\n%s", exp, union)
+		}
+	}
+
+	service := files["com/example/users/UserService.java"]
+	for _, exp := range []string{
+		"public interface UserService {",
+		"User getUser(GetUserRequest input);",
+		"void watchUsers(WatchRequest input, Consumer<User> stream);",
+		"import java.util.function.Consumer;",
+	} {
+		if !strings.Contains(service, exp) {
+			t.Errorf("Expected service to contain %q, got:\n%s", exp, service)
+		}
+	}
+}
+
+func TestJavaGenerator_QualifiedReferences(t *testing.T) {
+	gen := NewJavaGenerator()
+	if got := gen.classReference("com.example.orders.Order"); got != "com.example.orders.Order" {
+		t.Errorf("Expected fully-qualified class, got %s", got)
+	}
+	if got := gen.fieldName("default"); got != "default_" {
+		t.Errorf("Expected reserved word to be escaped, got %s", got)
+	}
+}
+
+func TestJavaGenerator_EmptyMethodTypes(t *testing.T) {
+	files := NewJavaGenerator().GenerateFiles(emptyMethodTestSchema())
+
+	service := files["com/example/HealthService.java"]
+	for _, exp := range []string{
+		"void ping();",
+		"void reset(ResetRequest input);",
+		"Status getStatus();",
+	} {
+		if !strings.Contains(service, exp) {
+			t.Errorf("Expected service to contain %q, got:\n%s", exp, service)
+		}
+	}
+}
+
+func TestJavaGenerator_NestedCollections(t *testing.T) {
+	schema := &ast.Schema{Types: []*ast.Type{{
+		Name:      "Report",
+		Namespace: "api",
+		Fields: []*ast.Field{
+			{Name: "rows", Type: &ast.FieldType{Name: "map", IsArray: true, MapKey: "string", MapValue: "int32"}},
+			{Name: "matrix", Type: &ast.FieldType{Name: "[]float64", IsArray: true}},
+		},
+	}}}
+	output := NewJavaGenerator().GenerateFiles(schema)["api/Report.java"]
+
+	expected := []string{
+		"import java.util.List;",
+		"import java.util.Map;",
+		"List<Map<String, Integer>> rows",
+		"List<List<Double>> matrix",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+}
+
+func TestJavaGenerator_Flags(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{{
+			Name:      "Permission",
+			Namespace: "acme",
+			Flags:     true,
+			Values:    []*ast.EnumValue{{Name: "NONE", HasNumber: true}, {Name: "READ"}, {Name: "WRITE"}},
+		}},
+		Types: []*ast.Type{{
+			Name:      "Grant",
+			Namespace: "acme",
+			Fields: []*ast.Field{
+				{Name: "permissions", Type: &ast.FieldType{Name: "Permission"}, Required: true},
+				{Name: "history", Type: &ast.FieldType{Name: "acme.Permission", IsArray: true}},
+			},
+		}},
+	}
+	files := NewJavaGenerator().GenerateFiles(schema)
+
+	expected := []string{
+		"public final class Permission {",
+		"    public static final int NONE = 0;\n    public static final int READ = 1;\n    public static final int WRITE = 2;",
+		"public static boolean has(int value, int flag) {",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(files["acme/Permission.java"], exp) {
+			t.Errorf("Expected Permission.java to contain %q, got:\n%s", exp, files["acme/Permission.java"])
+		}
+	}
+	grant := files["acme/Grant.java"]
+	if !strings.Contains(grant, "int permissions") || !strings.Contains(grant, "List<Integer> history") {
+		t.Errorf("Expected flags fields to be int bitmasks, got:\n%s", grant)
+	}
+}
+
+func TestGenerateJavaFiles_UnknownStyle(t *testing.T) {
+	_, err := generators["java"].Generate(context.Background(), javaTestSchema(), Options{Java: &JavaOptions{Style: "bean"}})
+	if err == nil || !strings.Contains(err.Error(), `unknown Java style "bean"`) {
+		t.Errorf("Expected an error for an unknown Java style, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// Metadata describes where generated files come from, so that consumers can
+// trace their provenance. The GraphQL, Protobuf, OpenAPI, and Go generators
+// stamp it into their output when it is set.
+type Metadata struct {
+	SchemaVersion  string // @version of the schema
+	TypeMUXVersion string // @typemux version of the schema
+	Commit         string // Git commit of the schema file, when it is in a repository
+	Hash           string // Content hash of the schema, see SchemaHash
+
+	// Source is the schema file relative to the output directory. When set, Go
+	// code gets a //go:generate directive that regenerates it from the schema.
+	Source string
+}
+
+// NewMetadata returns the metadata of a schema, without a Git commit or source.
+func NewMetadata(schema *ast.Schema) (*Metadata, error) {
+	hash, err := SchemaHash(schema)
+	if err != nil {
+		return nil, err
+	}
+	return &Metadata{
+		SchemaVersion:  schema.Version,
+		TypeMUXVersion: schema.TypeMUXVersion,
+		Hash:           hash,
+	}, nil
+}
+
+// SchemaHash returns the SHA-256 hash of the JSON interchange form of a schema,
+// as "sha256:<hex>". It changes whenever the generated output could change,
+// including through YAML annotations, and not when only formatting does.
+func SchemaHash(schema *ast.Schema) (string, error) {
+	data, err := ast.MarshalSchemaJSON(schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash schema: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// lines returns the metadata as "Name: value" lines for comments, skipping unset values
+func (m *Metadata) lines() []string {
+	if m == nil {
+		return nil
+	}
+	var lines []string
+	for _, entry := range []struct{ name, value string }{
+		{"Schema version", m.SchemaVersion},
+		{"TypeMUX version", m.TypeMUXVersion},
+		{"Git commit", m.Commit},
+		{"Content hash", m.Hash},
+	} {
+		if entry.value != "" {
+			lines = append(lines, entry.name+": "+entry.value)
+		}
+	}
+	return lines
+}
+
+// extension returns the metadata as the value of an x-typemux OpenAPI extension, skipping unset values
+func (m *Metadata) extension() map[string]string {
+	extension := make(map[string]string)
+	for key, value := range map[string]string{
+		"schemaVersion":  m.SchemaVersion,
+		"typemuxVersion": m.TypeMUXVersion,
+		"commit":         m.Commit,
+		"hash":           m.Hash,
+	} {
+		if value != "" {
+			extension[key] = value
+		}
+	}
+	return extension
+}
+
+// optionsOf returns a copy of format options, or their zero value when nil
+func optionsOf[T any](opts *T) T {
+	var o T
+	if opts != nil {
+		o = *opts
+	}
+	return o
+}
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSchemaHash(t *testing.T) {
+	hash, err := SchemaHash(generatorTestSchema("api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "sha256:") || len(hash) != len("sha256:")+64 {
+		t.Errorf("Expected a sha256 hash, got %s", hash)
+	}
+
+	same, err := SchemaHash(generatorTestSchema("api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same != hash {
+		t.Errorf("Expected equal schemas to hash the same, got %s and %s", hash, same)
+	}
+
+	changed := generatorTestSchema("api")
+	changed.Types[0].Fields[0].Name = "userId"
+	other, err := SchemaHash(changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == hash {
+		t.Error("Expected a changed schema to hash differently")
+	}
+}
+
+func TestNewMetadata(t *testing.T) {
+	schema := generatorTestSchema("api")
+	schema.Version = "2.1.0"
+	schema.TypeMUXVersion = "1.0.0"
+
+	metadata, err := NewMetadata(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, _ := SchemaHash(schema)
+	if metadata.SchemaVersion != "2.1.0" || metadata.TypeMUXVersion != "1.0.0" || metadata.Hash != hash {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+	if metadata.Commit != "" || metadata.Source != "" {
+		t.Errorf("Expected no commit or source, got %+v", metadata)
+	}
+}
+
+func TestMetadata_Stamped(t *testing.T) {
+	metadata := &Metadata{
+		SchemaVersion:  "2.1.0",
+		TypeMUXVersion: "1.0.0",
+		Commit:         "0123abc",
+		Hash:           "sha256:feed",
+		Source:         "../api schema.typemux",
+	}
+	tests := []struct {
+		format string
+		path   string
+		want   []string
+	}{
+		{"graphql", "schema.graphql", []string{"# Schema version: 2.1.0\n# TypeMUX version: 1.0.0\n# Git commit: 0123abc\n# Content hash: sha256:feed\n"}},
+		{"protobuf", "schema.proto", []string{"// Generated Protobuf Schema\n// Schema version: 2.1.0\n// TypeMUX version: 1.0.0\n// Git commit: 0123abc\n// Content hash: sha256:feed\nsyntax"}},
+		{"openapi", "openapi.yaml", []string{
+			"version: 2.1.0",
+			"x-typemux:\n        commit: 0123abc\n        hash: sha256:feed\n        schemaVersion: 2.1.0\n        typemuxVersion: 1.0.0\n",
+		}},
+		{"go", "types.go", []string{
+			"// Code generated by TypeMUX. DO NOT EDIT.\n// Schema version: 2.1.0\n",
+			"//go:generate typemux -input \"../api schema.typemux\" -format go -output . -stamp\n\npackage api\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			gen, err := Lookup(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			files, err := gen.Generate(context.Background(), generatorTestSchema("api"), Options{Metadata: metadata})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(files[tt.path]), want) {
+					t.Errorf("Expected %s to contain:\n%s\ngot:\n%s", tt.path, want, files[tt.path])
+				}
+			}
+
+			files, err = gen.Generate(context.Background(), generatorTestSchema("api"), Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(files[tt.path]), "sha256:feed") {
+				t.Errorf("Expected no metadata without Options.Metadata, got:\n%s", files[tt.path])
+			}
+		})
+	}
+}
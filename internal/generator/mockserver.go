@@ -0,0 +1,270 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// MockServerGenerator generates a runnable Go net/http mock server that serves
+// example payloads for every service method.
+type MockServerGenerator struct {
+	examples *exampleBuilder
+}
+
+// NewMockServerGenerator creates a new mock server generator.
+func NewMockServerGenerator() *MockServerGenerator {
+	return &MockServerGenerator{}
+}
+
+// mockRoute describes a single generated route
+type mockRoute struct {
+	method  string
+	path    string
+	status  int
+	body    string
+	errors  []int
+	service string
+	rpc     string
+}
+
+// Generate produces the source of a standalone Go program (package main).
+func (g *MockServerGenerator) Generate(schema *ast.Schema) string {
+	g.examples = newExampleBuilder(schema)
+
+	var routes []mockRoute
+	for _, service := range schema.Services {
+		for _, method := range service.Methods {
+			// The API sends webhooks rather than serving them
+			if !method.IsWebhook() {
+				routes = append(routes, g.buildRoute(service, method))
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by TypeMUX. DO NOT EDIT.\n\n")
+	sb.WriteString("// Command mockserver serves example responses for every service method in the schema.\n")
+	sb.WriteString("//\n")
+	sb.WriteString("// Latency and errors can be injected globally with flags, or per request with the\n")
+	sb.WriteString("// X-Mock-Delay (duration, e.g. \"250ms\") and X-Mock-Status (HTTP status code) headers.\n")
+	sb.WriteString("package main\n\n")
+	sb.WriteString(mockServerImports)
+
+	sb.WriteString("// routes lists every mocked endpoint.\n")
+	sb.WriteString("var routes = []*route{\n")
+	for _, r := range routes {
+		errorsLiteral := ""
+		if len(r.errors) > 0 {
+			codes := make([]string, len(r.errors))
+			for i, code := range r.errors {
+				codes[i] = strconv.Itoa(code)
+			}
+			errorsLiteral = fmt.Sprintf(", errors: []int{%s}", strings.Join(codes, ", "))
+		}
+		sb.WriteString(fmt.Sprintf("\t{method: %q, pattern: %q, status: %d, operation: %q%s, body: %s},\n",
+			r.method, r.path, r.status, r.service+"."+r.rpc, errorsLiteral, strconv.Quote(r.body)))
+	}
+	sb.WriteString("}\n")
+
+	sb.WriteString(mockServerRuntime)
+	return sb.String()
+}
+
+// buildRoute derives the HTTP route and example response for a method
+func (g *MockServerGenerator) buildRoute(service *ast.Service, method *ast.Method) mockRoute {
+	path := method.HTTPPath(service)
+
+	// Methods without output answer with no content; long-running ones accept the work
+	status := 200
+	if method.LongRunning != nil {
+		status = 202
+	} else if !method.HasOutput() {
+		status = 204
+	}
+	for _, code := range method.SuccessCodes {
+		if n, err := strconv.Atoi(code); err == nil {
+			status = n
+			break
+		}
+	}
+
+	var errors []int
+	for _, code := range method.ErrorCodes {
+		if n, err := strconv.Atoi(code); err == nil {
+			errors = append(errors, n)
+		}
+	}
+
+	body := ""
+	if status != 204 {
+		example := g.examples.forType(method.OutputType, 0)
+		encoded, err := json.MarshalIndent(example, "", "  ")
+		if err == nil {
+			body = string(encoded)
+		}
+	}
+
+	return mockRoute{
+		method:  strings.ToUpper(method.GetHTTPMethod()),
+		path:    path,
+		status:  status,
+		body:    body,
+		errors:  errors,
+		service: service.Name,
+		rpc:     method.Name,
+	}
+}
+
+const mockServerImports = `import (
+	"encoding/json"
+	"flag"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+`
+
+const mockServerRuntime = `
+// route is a mocked endpoint. Pattern segments in braces match any value.
+type route struct {
+	method    string
+	pattern   string
+	status    int
+	operation string
+	errors    []int
+	body      string
+}
+
+func (r *route) matches(method, path string) bool {
+	if r.method != method {
+		return false
+	}
+	want := strings.Split(strings.Trim(r.pattern, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if strings.HasPrefix(want[i], "{") && strings.HasSuffix(want[i], "}") {
+			continue
+		}
+		if want[i] != got[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type mockServer struct {
+	latency   time.Duration
+	jitter    time.Duration
+	errorRate float64
+	errorCode int
+}
+
+func (s *mockServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+	if req.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var matched *route
+	for _, r := range routes {
+		if r.matches(req.Method, req.URL.Path) {
+			matched = r
+			break
+		}
+	}
+	if matched == nil {
+		writeError(w, http.StatusNotFound, "no mock route for "+req.Method+" "+req.URL.Path)
+		return
+	}
+
+	delay := s.latency
+	if s.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(s.jitter)))
+	}
+	if header := req.Header.Get("X-Mock-Delay"); header != "" {
+		if d, err := time.ParseDuration(header); err == nil {
+			delay = d
+		}
+	}
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
+	status := matched.status
+	if header := req.Header.Get("X-Mock-Status"); header != "" {
+		if code, err := strconv.Atoi(header); err == nil {
+			status = code
+		}
+	} else if s.errorRate > 0 && rand.Float64() < s.errorRate {
+		status = s.errorCode
+		if len(matched.errors) > 0 {
+			status = matched.errors[rand.Intn(len(matched.errors))]
+		}
+	}
+
+	log.Printf("%s %s -> %s (%d)", req.Method, req.URL.Path, matched.operation, status)
+
+	if status >= 400 {
+		writeError(w, status, http.StatusText(status))
+		return
+	}
+	if matched.body == "" || status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(matched.body))
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"error": message,
+		"code":  strconv.Itoa(status),
+	})
+}
+
+func main() {
+	addr := flag.String("addr", ":8080", "Listen address")
+	latency := flag.Duration("latency", 0, "Fixed delay added to every response")
+	jitter := flag.Duration("jitter", 0, "Random extra delay up to this duration")
+	errorRate := flag.Float64("error-rate", 0, "Fraction of requests (0-1) that fail with an error response")
+	errorCode := flag.Int("error-status", http.StatusInternalServerError, "Status used for injected errors when a method declares none")
+	flag.Parse()
+
+	server := &mockServer{
+		latency:   *latency,
+		jitter:    *jitter,
+		errorRate: *errorRate,
+		errorCode: *errorCode,
+	}
+
+	for _, r := range routes {
+		log.Printf("%-6s %s (%s)", r.method, r.pattern, r.operation)
+	}
+	log.Printf("Mock server listening on %s", *addr)
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Fatal(httpServer.ListenAndServe())
+}
+`
@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func mockServerTestSchema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "api",
+		Enums: []*ast.Enum{
+			{Name: "Role", Values: []*ast.EnumValue{{Name: "ADMIN"}, {Name: "USER"}}},
+		},
+		Types: []*ast.Type{
+			{
+				Name: "User",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string"}},
+					{Name: "age", Type: &ast.FieldType{Name: "int32"}, Default: "30"},
+					{Name: "role", Type: &ast.FieldType{Name: "Role"}},
+					{Name: "friends", Type: &ast.FieldType{Name: "User", IsArray: true}},
+					{Name: "secret", Type: &ast.FieldType{Name: "string"}, ExcludeFrom: []string{"openapi"}},
+				},
+			},
+			{Name: "GetUserRequest", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string"}}}},
+			{Name: "Empty"},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "UserService",
+				Methods: []*ast.Method{
+					{Name: "GetUser", InputType: "GetUserRequest", OutputType: "User", HTTPMethod: "GET", PathTemplate: "/users/{id}", ErrorCodes: []string{"404"}},
+					{Name: "DeleteUser", InputType: "GetUserRequest", OutputType: "Empty", SuccessCodes: []string{"204"}},
+				},
+			},
+		},
+	}
+}
+
+func TestMockServerGenerator_Routes(t *testing.T) {
+	gen := NewMockServerGenerator()
+	output := gen.Generate(mockServerTestSchema())
+
+	expected := []string{
+		"package main",
+		`{method: "GET", pattern: "/users/{id}", status: 200, operation: "UserService.GetUser", errors: []int{404}, body: `,
+		`{method: "POST", pattern: "/userservice/deleteuser", status: 204, operation: "UserService.DeleteUser", body: ""}`,
+		`\"age\": 30`,
+		`\"role\": \"ADMIN\"`,
+		`flag.Float64("error-rate"`,
+		`X-Mock-Status`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, output)
+		}
+	}
+
+	if strings.Contains(output, "secret") {
+		t.Error("Expected fields excluded from OpenAPI to be omitted from examples")
+	}
+}
+
+func TestMockServerGenerator_OutputIsFormattedGo(t *testing.T) {
+	gen := NewMockServerGenerator()
+	output := gen.Generate(mockServerTestSchema())
+
+	formatted, err := format.Source([]byte(output))
+	if err != nil {
+		t.Fatalf("Generated mock server is not valid Go: %v\n%s", err, output)
+	}
+	if string(formatted) != output {
+		t.Error("Generated mock server is not gofmt-formatted")
+	}
+}
@@ -0,0 +1,219 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// ElementNames is the effective name of a schema element in each output format,
+// after @proto.name, @graphql.name, @openapi.name, @json.name, @go.name, and the
+// naming policy. Formats the element does not appear in have no name.
+type ElementNames struct {
+	Kind    string `json:"kind"`    // enum, type, union, field, or method
+	Element string `json:"element"` // Qualified schema name, such as shop.User.id
+	Proto   string `json:"proto,omitempty"`
+	GraphQL string `json:"graphql,omitempty"`
+	OpenAPI string `json:"openapi,omitempty"`
+	JSON    string `json:"json,omitempty"`
+	Go      string `json:"go,omitempty"`
+}
+
+// nameFormats are the formats of a name report, in column order
+var nameFormats = []string{"proto", "graphql", "openapi", "json", "go"}
+
+// name returns the name of the element in a format
+func (e ElementNames) name(format string) string {
+	switch format {
+	case "proto":
+		return e.Proto
+	case "graphql":
+		return e.GraphQL
+	case "openapi":
+		return e.OpenAPI
+	case "json":
+		return e.JSON
+	default:
+		return e.Go
+	}
+}
+
+// NameReport lists the names every enum, type, union, field, and method of a
+// schema gets in each output format, in schema order. Apply the naming policy
+// to the schema first for the report to reflect it.
+func NameReport(schema *ast.Schema) []ElementNames {
+	var report []ElementNames
+	for _, enum := range schema.Enums {
+		report = append(report, ElementNames{
+			Kind:    "enum",
+			Element: qualifiedName(enum.Namespace, enum.Name),
+			Proto:   enum.Name,
+			GraphQL: enum.Name,
+			OpenAPI: enum.Name,
+			Go:      enum.Name,
+		})
+	}
+
+	for _, typ := range schema.Types {
+		typeName := qualifiedName(typ.Namespace, typ.Name)
+		names := ElementNames{
+			Kind:    "type",
+			Element: typeName,
+			Proto:   typ.Name,
+			GraphQL: typ.Name,
+			OpenAPI: typ.Name,
+			Go:      typ.Name,
+		}
+		if a := typ.Annotations; a != nil {
+			if a.ProtoName != "" {
+				names.Proto = a.ProtoName
+			}
+			if a.GraphQLName != "" {
+				names.GraphQL = a.GraphQLName
+			}
+			if a.OpenAPIName != "" {
+				names.OpenAPI = a.OpenAPIName
+			}
+		}
+		report = append(report, names)
+
+		for _, field := range typ.Fields {
+			names := ElementNames{Kind: "field", Element: typeName + "." + field.Name}
+			if field.ShouldIncludeInGenerator("proto") {
+				names.Proto = field.NameFor("proto")
+			}
+			if field.ShouldIncludeInGenerator("graphql") {
+				names.GraphQL = field.NameFor("graphql")
+			}
+			if field.ShouldIncludeInGenerator("openapi") {
+				names.OpenAPI = field.NameFor("openapi")
+				names.JSON = field.Name
+				if field.JSONName != "" {
+					names.JSON = field.JSONName
+				}
+			}
+			// The Go generator keeps every field
+			names.Go = (&GoGenerator{}).goFieldName(field)
+			report = append(report, names)
+		}
+	}
+
+	for _, union := range schema.Unions {
+		report = append(report, ElementNames{
+			Kind:    "union",
+			Element: qualifiedName(union.Namespace, union.Name),
+			Proto:   union.Name,
+			GraphQL: union.Name,
+			OpenAPI: union.Name,
+			Go:      union.Name,
+		})
+	}
+
+	for _, service := range schema.Services {
+		serviceName := qualifiedName(service.Namespace, service.Name)
+		for _, method := range service.Methods {
+			names := ElementNames{Kind: "method", Element: serviceName + "." + method.Name, Proto: method.Name, OpenAPI: method.Name, Go: method.Name}
+			if !method.IsWebhook() {
+				names.GraphQL = strings.ToLower(method.Name[:1]) + method.Name[1:]
+			}
+			report = append(report, names)
+		}
+	}
+	return report
+}
+
+// qualifiedName prefixes a name with its namespace, if any
+func qualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// NameCollisions returns the names a report gives several elements of the same
+// scope in a format: enums, types, and unions share the package of their
+// namespace in Protobuf and Go, and a single namespace in GraphQL and OpenAPI;
+// fields share their type; methods share their service, except that GraphQL
+// operations share the root types and OpenAPI operation IDs the whole API.
+func NameCollisions(report []ElementNames) []string {
+	var collisions []string
+	for _, format := range nameFormats {
+		byName := make(map[string][]string)
+		var keys []string
+		for _, entry := range report {
+			name := entry.name(format)
+			if name == "" {
+				continue
+			}
+			key := nameScope(entry, format) + "\x00" + name
+			if byName[key] == nil {
+				keys = append(keys, key)
+			}
+			byName[key] = append(byName[key], entry.Element)
+		}
+		for _, key := range keys {
+			if elements := byName[key]; len(elements) > 1 {
+				name := key[strings.IndexByte(key, 0)+1:]
+				collisions = append(collisions, fmt.Sprintf("%s have the same %s name %s", strings.Join(elements, ", "), format, name))
+			}
+		}
+	}
+	return collisions
+}
+
+// nameScope returns the scope the name of an element must be unique in for a format
+func nameScope(entry ElementNames, format string) string {
+	parent := entry.Element[:max(strings.LastIndex(entry.Element, "."), 0)]
+	switch entry.Kind {
+	case "field":
+		return "field " + parent
+	case "method":
+		if format == "graphql" || format == "openapi" {
+			return "operation"
+		}
+		return "method " + parent
+	default:
+		if format == "proto" || format == "go" {
+			return "package " + parent
+		}
+		return "schema"
+	}
+}
+
+// FormatNameReport renders a name report as an aligned text table. Formats an
+// element does not appear in show a dash.
+func FormatNameReport(report []ElementNames) string {
+	rows := [][]string{{"KIND", "ELEMENT", "PROTO", "GRAPHQL", "OPENAPI", "JSON", "GO"}}
+	for _, entry := range report {
+		row := []string{entry.Kind, entry.Element}
+		for _, format := range nameFormats {
+			name := entry.name(format)
+			if name == "" {
+				name = "-"
+			}
+			row = append(row, name)
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	var sb strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(row)-1 {
+				sb.WriteString(cell)
+			} else {
+				sb.WriteString(fmt.Sprintf("%-*s  ", widths[i], cell))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func TestNameReport(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{{Name: "Status", Namespace: "shop"}},
+		Types: []*ast.Type{
+			{
+				Name:        "User",
+				Namespace:   "shop",
+				Annotations: &ast.FormatAnnotations{ProtoName: "UserV2", GraphQLName: "Account"},
+				Fields: []*ast.Field{
+					{Name: "user_id", Type: &ast.FieldType{Name: "string"}, Annotations: &ast.FormatAnnotations{GraphQLName: "id"}},
+					{Name: "email", Type: &ast.FieldType{Name: "string"}, JSONName: "mail", Annotations: &ast.FormatAnnotations{GoName: "EMail"}},
+					{Name: "secret", Type: &ast.FieldType{Name: "string"}, ExcludeFrom: []string{"graphql", "openapi"}},
+				},
+			},
+		},
+		Services: []*ast.Service{{Name: "UserService", Namespace: "shop", Methods: []*ast.Method{
+			{Name: "GetUser", InputType: "User", OutputType: "User"},
+			{Name: "UserCreated", InputType: "User", Webhook: &ast.Webhook{Name: "userCreated"}},
+		}}},
+	}
+
+	expected := []ElementNames{
+		{"enum", "shop.Status", "Status", "Status", "Status", "", "Status"},
+		{"type", "shop.User", "UserV2", "Account", "User", "", "User"},
+		{"field", "shop.User.user_id", "user_id", "id", "user_id", "user_id", "UserId"},
+		{"field", "shop.User.email", "email", "email", "mail", "mail", "EMail"},
+		{"field", "shop.User.secret", "secret", "", "", "", "Secret"},
+		{"method", "shop.UserService.GetUser", "GetUser", "getUser", "GetUser", "", "GetUser"},
+		{"method", "shop.UserService.UserCreated", "UserCreated", "", "UserCreated", "", "UserCreated"},
+	}
+
+	report := NameReport(schema)
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("Unexpected report:\n got: %+v\nwant: %+v", report, expected)
+	}
+	if collisions := NameCollisions(report); len(collisions) != 0 {
+		t.Errorf("Expected no collisions, got %v", collisions)
+	}
+
+	text := FormatNameReport(report)
+	if !strings.HasPrefix(text, "KIND") || !strings.Contains(text, "shop.User.secret") || !strings.Contains(text, " - ") {
+		t.Errorf("Unexpected text report:\n%s", text)
+	}
+}
+
+func TestNameCollisions(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{Name: "User", Namespace: "users", Fields: []*ast.Field{
+				{Name: "name", Type: &ast.FieldType{Name: "string"}},
+				{Name: "fullName", Type: &ast.FieldType{Name: "string"}, JSONName: "name"},
+			}},
+			{Name: "User", Namespace: "products"},
+			{Name: "Account", Namespace: "users", Annotations: &ast.FormatAnnotations{ProtoName: "User"}},
+		},
+		Services: []*ast.Service{
+			{Name: "UserService", Namespace: "users", Methods: []*ast.Method{{Name: "List"}}},
+			{Name: "ProductService", Namespace: "products", Methods: []*ast.Method{{Name: "List"}}},
+		},
+	}
+
+	expected := []string{
+		"users.User, users.Account have the same proto name User",
+		"users.User, products.User have the same graphql name User",
+		"users.UserService.List, products.ProductService.List have the same graphql name list",
+		"users.User, products.User have the same openapi name User",
+		"users.User.name, users.User.fullName have the same openapi name name",
+		"users.UserService.List, products.ProductService.List have the same openapi name List",
+		"users.User.name, users.User.fullName have the same json name name",
+	}
+	if collisions := NameCollisions(NameReport(schema)); !reflect.DeepEqual(collisions, expected) {
+		t.Errorf("Unexpected collisions:\n got: %q\nwant: %q", collisions, expected)
+	}
+}
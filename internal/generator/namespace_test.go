@@ -271,3 +271,81 @@ func TestProtobufGenerator_ByNamespace_WithOptions(t *testing.T) {
 		t.Error("expected go_package option in namespace-based output")
 	}
 }
+
+func TestProtobufGenerator_NamespaceOptions_LastDeclarationWins(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "com.example.users",
+		NamespaceAnnotations: &ast.FormatAnnotations{
+			Proto: []string{
+				"go_package = \"github.com/example/old\"",
+				"java_package = \"com.example.users\"",
+				"go_package=\"github.com/example/new\"",
+			},
+		},
+	}
+
+	gen := NewProtobufGenerator()
+	output := gen.Generate(schema)
+
+	if strings.Contains(output, "github.com/example/old") {
+		t.Error("expected overridden go_package option to be dropped")
+	}
+
+	if strings.Count(output, "option go_package") != 1 {
+		t.Errorf("expected exactly one go_package option, got:\n%s", output)
+	}
+
+	if !strings.Contains(output, "option go_package=\"github.com/example/new\";") {
+		t.Error("expected last go_package option in output")
+	}
+
+	if !strings.Contains(output, "option java_package = \"com.example.users\";") {
+		t.Error("expected java_package option in output")
+	}
+}
+
+func TestProtobufGenerator_ByNamespace_ImportedNamespaceOptions(t *testing.T) {
+	registry := ast.NewTypeRegistry()
+	schema := &ast.Schema{
+		Namespace: "com.example.users",
+		NamespaceAnnotations: &ast.FormatAnnotations{
+			Proto: []string{"go_package=\"github.com/example/users\""},
+		},
+		ImportedNamespaceAnnotations: map[string]*ast.FormatAnnotations{
+			"com.example.common": {
+				Proto: []string{"go_package=\"github.com/example/common\""},
+			},
+		},
+		Types: []*ast.Type{
+			{
+				Name:      "Address",
+				Namespace: "com.example.common",
+				Fields: []*ast.Field{
+					{Name: "city", Type: &ast.FieldType{Name: "string", IsBuiltin: true}, Number: 1, HasNumber: true},
+				},
+			},
+			{
+				Name:      "User",
+				Namespace: "com.example.users",
+				Fields: []*ast.Field{
+					{Name: "address", Type: &ast.FieldType{Name: "Address"}, Number: 1, HasNumber: true},
+				},
+			},
+		},
+		TypeRegistry: registry,
+	}
+	for _, typ := range schema.Types {
+		registry.RegisterType(typ)
+	}
+
+	gen := NewProtobufGenerator()
+	outputs := gen.GenerateByNamespace(schema)
+
+	if !strings.Contains(outputs["com.example.users"], "option go_package=\"github.com/example/users\";") {
+		t.Error("expected users go_package option in users output")
+	}
+
+	if !strings.Contains(outputs["com.example.common"], "option go_package=\"github.com/example/common\";") {
+		t.Error("expected common go_package option in common output")
+	}
+}
@@ -3,58 +3,180 @@ package generator
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/rasmartins/typemux/internal/ast"
 	"gopkg.in/yaml.v3"
 )
 
+// OpenAPIOptions configures the OpenAPI generator.
+type OpenAPIOptions struct {
+	// ProblemDetails describes error responses with an RFC 7807 Problem schema
+	// served as application/problem+json instead of the default Error schema.
+	ProblemDetails bool
+
+	// ErrorSchemaName overrides the name of the shared error schema component
+	// (default: "Error", or "Problem" with ProblemDetails). If the schema already
+	// defines a type with this name, error responses reference it as-is.
+	ErrorSchemaName string
+
+	// Metadata is stamped into the info section when set: its schema version
+	// becomes info.version, and all of it info.x-typemux.
+	Metadata *Metadata
+
+	// Servers are listed in the servers section of the spec.
+	Servers []OpenAPIServer
+
+	// SecuritySchemes are added to components.securitySchemes by name, and
+	// Security lists the requirements of every operation: scheme names mapped
+	// to the scopes they need.
+	SecuritySchemes map[string]OpenAPISecurityScheme
+	Security        []map[string][]string
+
+	// Parameters are added to components.parameters by name and referenced by
+	// every operation, such as a shared X-Request-ID header.
+	Parameters map[string]OpenAPIParameter
+
+	// Int64 selects the schema of int64 and uint64 values: integers by
+	// default, or strings holding the digits with the string and scalar
+	// encodings.
+	Int64 Int64Encoding
+
+	// BytesFormat is the format of bytes fields: byte, base64 strings as in
+	// JSON (the default), or binary, raw octets as in file uploads.
+	BytesFormat OpenAPIBytesFormat
+
+	// ProtoJSON describes the proto3 JSON mapping of the generated Protobuf
+	// messages, as served by grpc-gateway and other REST gateways, so that
+	// their responses validate against the spec: properties take the
+	// lowerCamelCase JSON names of the Protobuf fields (or their json_name
+	// option), 64-bit integers are strings, and enums list the UNSPECIFIED
+	// zero value the Protobuf generator adds.
+	ProtoJSON bool
+}
+
+// OpenAPIBytesFormat is the OpenAPI format of bytes fields.
+type OpenAPIBytesFormat string
+
+const (
+	// OpenAPIBytesBase64 describes bytes as base64 strings, format: byte.
+	OpenAPIBytesBase64 OpenAPIBytesFormat = "byte"
+	// OpenAPIBytesBinary describes bytes as raw octets, format: binary.
+	OpenAPIBytesBinary OpenAPIBytesFormat = "binary"
+)
+
 // OpenAPIGenerator generates OpenAPI 3.0 specifications from TypeMUX schemas.
-type OpenAPIGenerator struct{}
+type OpenAPIGenerator struct {
+	opts   OpenAPIOptions
+	schema *ast.Schema           // Schema being generated
+	types  map[string]*ast.Type  // Types of the schema being generated, by name
+	unions map[string]*ast.Union // Unions of the schema being generated, by name
+}
 
 // NewOpenAPIGenerator creates a new OpenAPI specification generator.
 func NewOpenAPIGenerator() *OpenAPIGenerator {
 	return &OpenAPIGenerator{}
 }
 
+// NewOpenAPIGeneratorWithOptions creates a new OpenAPI specification generator with the given options.
+func NewOpenAPIGeneratorWithOptions(opts *OpenAPIOptions) *OpenAPIGenerator {
+	g := &OpenAPIGenerator{}
+	if opts != nil {
+		g.opts = *opts
+	}
+	// proto3 JSON encodes 64-bit integers as strings
+	if g.opts.ProtoJSON && !g.opts.Int64.quoted() {
+		g.opts.Int64 = Int64String
+	}
+	return g
+}
+
 // OpenAPISpec represents the root OpenAPI 3.0 specification structure.
 type OpenAPISpec struct {
 	OpenAPI    string                                 `json:"openapi" yaml:"openapi"`
 	Info       OpenAPIInfo                            `json:"info" yaml:"info"`
+	Servers    []OpenAPIServer                        `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Security   []map[string][]string                  `json:"security,omitempty" yaml:"security,omitempty"`
 	Paths      map[string]map[string]OpenAPIOperation `json:"paths" yaml:"paths"`
+	Webhooks   map[string]map[string]OpenAPIOperation `json:"x-webhooks,omitempty" yaml:"x-webhooks,omitempty"` // Requests the API sends, by webhook name
 	Components OpenAPIComponents                      `json:"components" yaml:"components"`
 }
 
 // OpenAPIInfo contains metadata about the API.
 type OpenAPIInfo struct {
-	Title       string `json:"title" yaml:"title"`
-	Version     string `json:"version" yaml:"version"`
+	Title       string                 `json:"title" yaml:"title"`
+	Version     string                 `json:"version" yaml:"version"`
+	Description string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Extensions  map[string]interface{} `json:",inline" yaml:",inline"` // x- prefixed extensions
+}
+
+// OpenAPIServer is a server that hosts the API.
+type OpenAPIServer struct {
+	URL         string `json:"url" yaml:"url"`
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 }
 
+// OpenAPISecurityScheme describes a way of authenticating to the API.
+type OpenAPISecurityScheme struct {
+	Type             string                 `json:"type" yaml:"type"` // "apiKey", "http", "oauth2", "openIdConnect"
+	Description      string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Name             string                 `json:"name,omitempty" yaml:"name,omitempty"`     // Header, query, or cookie name of an apiKey
+	In               string                 `json:"in,omitempty" yaml:"in,omitempty"`         // "header", "query", or "cookie" for an apiKey
+	Scheme           string                 `json:"scheme,omitempty" yaml:"scheme,omitempty"` // HTTP authorization scheme, e.g. "bearer"
+	BearerFormat     string                 `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
+	Flows            map[string]interface{} `json:"flows,omitempty" yaml:"flows,omitempty"` // OAuth2 flows, as in the spec
+	OpenIDConnectURL string                 `json:"openIdConnectUrl,omitempty" yaml:"openIdConnectUrl,omitempty"`
+}
+
 // OpenAPIOperation describes a single API operation on a path.
 type OpenAPIOperation struct {
 	Summary     string                     `json:"summary" yaml:"summary"`
+	Description string                     `json:"description,omitempty" yaml:"description,omitempty"`
 	OperationID string                     `json:"operationId" yaml:"operationId"`
 	Parameters  []OpenAPIParameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
 	Responses   map[string]OpenAPIResponse `json:"responses" yaml:"responses"`
+	Callbacks   map[string]OpenAPICallback `json:"callbacks,omitempty" yaml:"callbacks,omitempty"`
+	Extensions  map[string]interface{}     `json:",inline" yaml:",inline"` // x- prefixed extensions
 }
 
-// OpenAPIParameter describes a single operation parameter.
+// OpenAPICallback maps the runtime expressions of callback URLs, such as
+// {$request.body#/callbackUrl}, to the operations the API sends to them.
+type OpenAPICallback map[string]map[string]OpenAPIOperation
+
+// OpenAPIParameter describes a single operation parameter, or references a
+// shared parameter of the components with Ref.
 type OpenAPIParameter struct {
-	Name        string                 `json:"name" yaml:"name"`
-	In          string                 `json:"in" yaml:"in"` // "path", "query", "header", "cookie"
-	Required    bool                   `json:"required,omitempty" yaml:"required,omitempty"`
-	Description string                 `json:"description,omitempty" yaml:"description,omitempty"`
-	Schema      OpenAPIParameterSchema `json:"schema" yaml:"schema"`
+	Ref         string                    `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Name        string                    `json:"name,omitempty" yaml:"name,omitempty"`
+	In          string                    `json:"in,omitempty" yaml:"in,omitempty"` // "path", "query", "header", "cookie"
+	Required    bool                      `json:"required,omitempty" yaml:"required,omitempty"`
+	Description string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	Deprecated  bool                      `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Schema      *OpenAPIParameterSchema   `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Examples    map[string]OpenAPIExample `json:"examples,omitempty" yaml:"examples,omitempty"`
 }
 
 // OpenAPIParameterSchema describes the schema of a parameter.
 type OpenAPIParameterSchema struct {
-	Type    string      `json:"type" yaml:"type"`
-	Format  string      `json:"format,omitempty" yaml:"format,omitempty"`
-	Default interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+	Type             string                `json:"type,omitempty" yaml:"type,omitempty"`
+	Format           string                `json:"format,omitempty" yaml:"format,omitempty"`
+	Ref              string                `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Items            *OpenAPIPropertyItems `json:"items,omitempty" yaml:"items,omitempty"`
+	Default          interface{}           `json:"default,omitempty" yaml:"default,omitempty"`
+	Enum             []string              `json:"enum,omitempty" yaml:"enum,omitempty"`
+	MinLength        *int                  `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength        *int                  `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	Pattern          string                `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Minimum          *float64              `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum          *float64              `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMinimum *float64              `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64              `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64              `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+	MinItems         *int                  `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems         *int                  `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	UniqueItems      bool                  `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
 }
 
 // OpenAPIRequestBody describes a request body.
@@ -65,13 +187,36 @@ type OpenAPIRequestBody struct {
 
 // OpenAPIMediaType describes the media type of a request or response body.
 type OpenAPIMediaType struct {
-	Schema OpenAPISchemaRef `json:"schema" yaml:"schema"`
+	Schema   OpenAPISchemaRef          `json:"schema" yaml:"schema"`
+	Examples map[string]OpenAPIExample `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// OpenAPIExample is a named example of a request body, parameter, or response.
+type OpenAPIExample struct {
+	Summary string      `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Value   interface{} `json:"value" yaml:"value"`
+}
+
+// OpenAPICodeSample is an entry of the x-codeSamples extension of an operation,
+// which documentation portals such as Redoc display.
+type OpenAPICodeSample struct {
+	Lang   string `json:"lang" yaml:"lang"`
+	Label  string `json:"label,omitempty" yaml:"label,omitempty"`
+	Source string `json:"source" yaml:"source"`
 }
 
 // OpenAPIResponse describes a single response from an API operation.
 type OpenAPIResponse struct {
 	Description string                      `json:"description" yaml:"description"`
 	Content     map[string]OpenAPIMediaType `json:"content,omitempty" yaml:"content,omitempty"`
+	Links       map[string]OpenAPILink      `json:"links,omitempty" yaml:"links,omitempty"`
+}
+
+// OpenAPILink describes how values of a response fill the parameters of another operation.
+type OpenAPILink struct {
+	OperationID string            `json:"operationId" yaml:"operationId"`
+	Parameters  map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
 }
 
 // OpenAPISchemaRef is a reference to a schema or an inline schema definition.
@@ -80,13 +225,18 @@ type OpenAPISchemaRef struct {
 	Type                 string                     `json:"type,omitempty" yaml:"type,omitempty"`
 	Format               string                     `json:"format,omitempty" yaml:"format,omitempty"`
 	Properties           map[string]OpenAPIProperty `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required             []string                   `json:"required,omitempty" yaml:"required,omitempty"`
 	Items                *OpenAPISchemaRef          `json:"items,omitempty" yaml:"items,omitempty"`
 	AdditionalProperties interface{}                `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	OneOf                []OpenAPISchemaRef         `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	Discriminator        *OpenAPIDiscriminator      `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
 }
 
-// OpenAPIComponents holds reusable schema definitions.
+// OpenAPIComponents holds reusable schema, parameter, and security scheme definitions.
 type OpenAPIComponents struct {
-	Schemas map[string]OpenAPISchema `json:"schemas" yaml:"schemas"`
+	Schemas         map[string]OpenAPISchema         `json:"schemas" yaml:"schemas"`
+	Parameters      map[string]OpenAPIParameter      `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
 }
 
 // OpenAPIDiscriminator specifies the discriminator for polymorphic types.
@@ -97,12 +247,15 @@ type OpenAPIDiscriminator struct {
 
 // OpenAPISchema describes the structure of request/response bodies or schema components.
 type OpenAPISchema struct {
+	Ref           string                     `json:"$ref,omitempty" yaml:"$ref,omitempty"`
 	Type          string                     `json:"type,omitempty" yaml:"type,omitempty"`
+	Format        string                     `json:"format,omitempty" yaml:"format,omitempty"`
 	Description   string                     `json:"description,omitempty" yaml:"description,omitempty"`
 	Properties    map[string]OpenAPIProperty `json:"properties,omitempty" yaml:"properties,omitempty"`
 	Required      []string                   `json:"required,omitempty" yaml:"required,omitempty"`
 	Enum          []string                   `json:"enum,omitempty" yaml:"enum,omitempty"`
 	OneOf         []OpenAPISchemaRef         `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	AllOf         []OpenAPISchema            `json:"allOf,omitempty" yaml:"allOf,omitempty"`
 	Discriminator *OpenAPIDiscriminator      `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
 	Extensions    map[string]interface{}     `json:",inline" yaml:",inline"` // x- prefixed extensions
 }
@@ -138,6 +291,7 @@ type OpenAPIPropertyItems struct {
 	Type                 string                `json:"type,omitempty" yaml:"type,omitempty"`
 	Format               string                `json:"format,omitempty" yaml:"format,omitempty"`
 	Ref                  string                `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Items                *OpenAPIPropertyItems `json:"items,omitempty" yaml:"items,omitempty"`
 	AdditionalProperties *OpenAPIPropertyItems `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
 }
 
@@ -151,6 +305,9 @@ func (g *OpenAPIGenerator) Generate(schema *ast.Schema) string {
 	if schema.Namespace != "" {
 		title = schema.Namespace + " API"
 	}
+	if g.opts.Metadata != nil && g.opts.Metadata.SchemaVersion != "" {
+		version = g.opts.Metadata.SchemaVersion
+	}
 
 	// Apply namespace-level OpenAPI info from annotations
 	if schema.NamespaceAnnotations != nil && len(schema.NamespaceAnnotations.OpenAPI) > 0 {
@@ -183,31 +340,55 @@ func (g *OpenAPIGenerator) Generate(schema *ast.Schema) string {
 			Version:     version,
 			Description: description,
 		},
-		Paths: make(map[string]map[string]OpenAPIOperation),
+		Paths:    make(map[string]map[string]OpenAPIOperation),
+		Servers:  g.opts.Servers,
+		Security: g.opts.Security,
 		Components: OpenAPIComponents{
-			Schemas: make(map[string]OpenAPISchema),
+			Schemas:         make(map[string]OpenAPISchema),
+			Parameters:      g.opts.Parameters,
+			SecuritySchemes: g.opts.SecuritySchemes,
 		},
 	}
+	if g.opts.Metadata != nil {
+		spec.Info.Extensions = map[string]interface{}{"x-typemux": g.opts.Metadata.extension()}
+	}
+	if schema.NamespaceAnnotations != nil {
+		spec.Info.Extensions = ownershipExtensions(spec.Info.Extensions, schema.NamespaceAnnotations.Ownership)
+	}
 
 	// Build a map of original type names to their custom OpenAPI names
 	typeNameMap := make(map[string]string)
+	g.schema = schema
+	g.types = make(map[string]*ast.Type)
 	for _, typ := range schema.Types {
+		g.types[typ.Name] = typ
 		if typ.Annotations != nil && typ.Annotations.OpenAPIName != "" {
 			typeNameMap[typ.Name] = typ.Annotations.OpenAPIName
 		}
 	}
+	g.unions = make(map[string]*ast.Union)
+	for _, union := range schema.Unions {
+		g.unions[union.Name] = union
+	}
 
 	// Generate schemas for enums
 	for _, enum := range schema.Enums {
-		enumValues := make([]string, len(enum.Values))
-		for i, val := range enum.Values {
-			enumValues[i] = val.Name
+		if enum.Flags {
+			spec.Components.Schemas[enum.Name] = g.flagsSchema(enum)
+			continue
+		}
+		var enumValues []string
+		if unspecified := protoUnspecifiedValue(enum); g.opts.ProtoJSON && unspecified != "" {
+			enumValues = append(enumValues, unspecified)
+		}
+		for _, val := range enum.Values {
+			enumValues = append(enumValues, val.Name)
 		}
 		enumSchema := OpenAPISchema{
 			Type: "string",
 			Enum: enumValues,
 		}
-		if doc := enum.Doc.GetDoc("openapi"); doc != "" {
+		if doc := enum.Doc.GetLongDoc("openapi"); doc != "" {
 			enumSchema.Description = doc
 		}
 		spec.Components.Schemas[enum.Name] = enumSchema
@@ -220,7 +401,11 @@ func (g *OpenAPIGenerator) Generate(schema *ast.Schema) string {
 		if typ.Annotations != nil && typ.Annotations.OpenAPIName != "" {
 			schemaName = typ.Annotations.OpenAPIName
 		}
-		spec.Components.Schemas[schemaName] = g.generateSchema(typ, typeNameMap)
+		typeSchema := g.generateSchema(typ, typeNameMap)
+		if bases := schema.Bases(typ); len(bases) > 0 {
+			typeSchema = g.composeSchema(typeSchema, bases, typeNameMap)
+		}
+		spec.Components.Schemas[schemaName] = typeSchema
 	}
 
 	// Generate schemas for unions
@@ -231,7 +416,18 @@ func (g *OpenAPIGenerator) Generate(schema *ast.Schema) string {
 	// Generate paths from services
 	for _, service := range schema.Services {
 		for _, method := range service.Methods {
-			g.addServiceMethod(&spec, service, method, typeNameMap)
+			if !method.IsWebhook() {
+				g.addServiceMethod(&spec, service, method, typeNameMap)
+			}
+		}
+	}
+
+	// Webhooks go after the paths, since callbacks attach to their operations
+	for _, service := range schema.Services {
+		for _, method := range service.Methods {
+			if method.IsWebhook() {
+				g.addWebhook(&spec, service, method, typeNameMap)
+			}
 		}
 	}
 
@@ -240,6 +436,8 @@ func (g *OpenAPIGenerator) Generate(schema *ast.Schema) string {
 		g.addFieldArgumentPaths(&spec, typ, typeNameMap)
 	}
 
+	g.addSharedParameters(&spec)
+
 	yamlBytes, err := yaml.Marshal(spec)
 	if err != nil {
 		return fmt.Sprintf("Error generating OpenAPI spec: %v", err)
@@ -248,6 +446,24 @@ func (g *OpenAPIGenerator) Generate(schema *ast.Schema) string {
 	return string(yamlBytes)
 }
 
+// addSharedParameters references the shared parameters of the options from every operation
+func (g *OpenAPIGenerator) addSharedParameters(spec *OpenAPISpec) {
+	names := make([]string, 0, len(g.opts.Parameters))
+	for name := range g.opts.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, operations := range spec.Paths {
+		for method, operation := range operations {
+			for _, name := range names {
+				operation.Parameters = append(operation.Parameters, OpenAPIParameter{Ref: "#/components/parameters/" + name})
+			}
+			operations[method] = operation
+		}
+	}
+}
+
 func (g *OpenAPIGenerator) generateSchema(typ *ast.Type, typeNameMap map[string]string) OpenAPISchema {
 	schema := OpenAPISchema{
 		Type:       "object",
@@ -256,7 +472,7 @@ func (g *OpenAPIGenerator) generateSchema(typ *ast.Type, typeNameMap map[string]
 		Extensions: make(map[string]interface{}),
 	}
 
-	if doc := typ.Doc.GetDoc("openapi"); doc != "" {
+	if doc := typ.Doc.GetLongDoc("openapi"); doc != "" {
 		schema.Description = doc
 	}
 
@@ -269,6 +485,7 @@ func (g *OpenAPIGenerator) generateSchema(typ *ast.Type, typeNameMap map[string]
 			}
 		}
 	}
+	schema.Extensions = ownershipExtensions(schema.Extensions, g.ownershipOf(typ.Namespace, typ.Annotations))
 
 	for _, field := range typ.Fields {
 		// Skip excluded fields
@@ -276,6 +493,11 @@ func (g *OpenAPIGenerator) generateSchema(typ *ast.Type, typeNameMap map[string]
 			continue
 		}
 
+		// Inherited fields come from the base schemas through allOf
+		if field.InheritedFrom != "" {
+			continue
+		}
+
 		// Skip fields with arguments - they become sub-resource endpoints
 		if len(field.Arguments) > 0 {
 			continue
@@ -283,17 +505,13 @@ func (g *OpenAPIGenerator) generateSchema(typ *ast.Type, typeNameMap map[string]
 
 		property := g.convertFieldToProperty(field, typeNameMap)
 
-		// Use JSONName if specified, otherwise use field name
-		propertyName := field.Name
-		if field.JSONName != "" {
-			propertyName = field.JSONName
-		}
+		propertyName := g.propertyName(field)
 
 		schema.Properties[propertyName] = property
 
 		// Fields are required if explicitly marked with @required annotation
 		// Fields marked with ? are explicitly optional
-		if field.Required && !field.Type.Optional {
+		if field.Presence() == ast.PresenceRequired {
 			schema.Required = append(schema.Required, propertyName)
 		}
 	}
@@ -301,20 +519,79 @@ func (g *OpenAPIGenerator) generateSchema(typ *ast.Type, typeNameMap map[string]
 	return schema
 }
 
+// flagsSchema returns the schema of a flags enum: an integer bitmask whose
+// description lists the bit of each value
+func (g *OpenAPIGenerator) flagsSchema(enum *ast.Enum) OpenAPISchema {
+	var description strings.Builder
+	if doc := enum.Doc.GetLongDoc("openapi"); doc != "" {
+		description.WriteString(doc + "\n\n")
+	}
+	description.WriteString("Bit flags, combined with bitwise OR:")
+	numbers := enum.ValueNumbers()
+	for i, value := range enum.Values {
+		description.WriteString(fmt.Sprintf("\n- %d: %s", numbers[i], value.Name))
+		if doc := value.Doc.GetDoc("openapi"); doc != "" {
+			description.WriteString(" - " + strings.ReplaceAll(doc, "\n", " "))
+		}
+	}
+	return OpenAPISchema{
+		Type:        "integer",
+		Format:      "int32",
+		Description: description.String(),
+	}
+}
+
+// composeSchema turns the schema of a type that extends others into an allOf of
+// references to its base schemas and an object with its own properties
+func (g *OpenAPIGenerator) composeSchema(own OpenAPISchema, bases []*ast.Type, typeNameMap map[string]string) OpenAPISchema {
+	composed := OpenAPISchema{
+		Description: own.Description,
+		Extensions:  own.Extensions,
+	}
+	for _, base := range bases {
+		baseName := base.Name
+		if customName, ok := typeNameMap[base.Name]; ok {
+			baseName = customName
+		}
+		composed.AllOf = append(composed.AllOf, OpenAPISchema{Ref: fmt.Sprintf("#/components/schemas/%s", baseName)})
+	}
+	if len(own.Properties) > 0 {
+		composed.AllOf = append(composed.AllOf, OpenAPISchema{
+			Type:       own.Type,
+			Properties: own.Properties,
+			Required:   own.Required,
+		})
+	}
+	return composed
+}
+
+// generateUnionSchema describes a union as oneOf its options, in the JSON
+// encoding of @json.union: internally tagged options are discriminated by the
+// tag property, adjacently tagged options are objects holding the tag and the
+// option, and untagged options are matched by their schemas alone. Unions
+// without an encoding are discriminated by a type property.
 func (g *OpenAPIGenerator) generateUnionSchema(union *ast.Union) OpenAPISchema {
 	schema := OpenAPISchema{
 		OneOf: []OpenAPISchemaRef{},
 	}
 
-	if doc := union.Doc.GetDoc("openapi"); doc != "" {
+	if doc := union.Doc.GetLongDoc("openapi"); doc != "" {
 		schema.Description = doc
 	}
 
-	// Add discriminator for better client generation
-	// Uses "type" as the discriminator property
-	discriminator := &OpenAPIDiscriminator{
-		PropertyName: "type",
-		Mapping:      make(map[string]string),
+	encoding := union.Encoding
+	if encoding != nil && encoding.Strategy == ast.UnionAdjacent {
+		for _, option := range union.Options {
+			schema.OneOf = append(schema.OneOf, OpenAPISchemaRef{
+				Type: "object",
+				Properties: map[string]OpenAPIProperty{
+					encoding.TagProperty():     {Type: "string", Enum: []string{ast.GetUnqualifiedName(option)}},
+					encoding.ContentProperty(): {Ref: fmt.Sprintf("#/components/schemas/%s", option)},
+				},
+				Required: []string{encoding.TagProperty(), encoding.ContentProperty()},
+			})
+		}
+		return schema
 	}
 
 	// Add each union option as a oneOf reference
@@ -322,15 +599,40 @@ func (g *OpenAPIGenerator) generateUnionSchema(union *ast.Union) OpenAPISchema {
 		schema.OneOf = append(schema.OneOf, OpenAPISchemaRef{
 			Ref: fmt.Sprintf("#/components/schemas/%s", option),
 		})
-		// Map the type name to the schema reference
-		discriminator.Mapping[option] = fmt.Sprintf("#/components/schemas/%s", option)
+	}
+	if encoding != nil && encoding.Strategy == ast.UnionUntagged {
+		return schema
 	}
 
+	// Add discriminator for better client generation, mapping the tag of each
+	// option to its schema
+	discriminator := &OpenAPIDiscriminator{
+		PropertyName: "type",
+		Mapping:      make(map[string]string),
+	}
+	if encoding != nil {
+		discriminator.PropertyName = encoding.TagProperty()
+	}
+	for _, option := range union.Options {
+		discriminator.Mapping[option] = fmt.Sprintf("#/components/schemas/%s", option)
+	}
 	schema.Discriminator = discriminator
 
 	return schema
 }
 
+// responseSchema returns the schema of a method's response. A union is described
+// inline as oneOf its options, so the response itself carries the discriminator
+// of errors returned as data instead of only referencing the union component.
+func (g *OpenAPIGenerator) responseSchema(typeName string) OpenAPISchemaRef {
+	union, ok := g.unions[ast.GetUnqualifiedName(typeName)]
+	if !ok {
+		return OpenAPISchemaRef{Ref: fmt.Sprintf("#/components/schemas/%s", typeName)}
+	}
+	unionSchema := g.generateUnionSchema(union)
+	return OpenAPISchemaRef{OneOf: unionSchema.OneOf, Discriminator: unionSchema.Discriminator}
+}
+
 // generateMapDescription creates a human-readable description for map types
 func (g *OpenAPIGenerator) generateMapDescription(fieldType *ast.FieldType) string {
 	if !fieldType.IsMap {
@@ -347,14 +649,26 @@ func (g *OpenAPIGenerator) generateMapDescription(fieldType *ast.FieldType) stri
 		// Recursively describe nested maps
 		valueDesc = g.generateMapDescription(valueFieldType)
 	} else {
-		valueDesc = valueFieldType.Name
+		valueDesc = valueFieldType.String()
 	}
 
 	return fmt.Sprintf("Map of %s to %s", fieldType.MapKey, valueDesc)
 }
 
-// generateAdditionalProperties recursively generates OpenAPI additionalProperties for map value types
+// generateAdditionalProperties recursively generates OpenAPI additionalProperties for map value types,
+// and the items of arrays nested in arrays or maps
 func (g *OpenAPIGenerator) generateAdditionalProperties(valueFieldType *ast.FieldType, typeNameMap map[string]string) *OpenAPIPropertyItems {
+	if valueFieldType.IsArray {
+		// Array case: map<string, []Item> and [][]Item have values and items of
+		// type: array
+		// items:
+		//   $ref: '#/components/schemas/Item'
+		return &OpenAPIPropertyItems{
+			Type:  "array",
+			Items: g.generateAdditionalProperties(valueFieldType.ElementType(), typeNameMap),
+		}
+	}
+
 	if valueFieldType.IsMap {
 		// Nested map case: recursively generate additionalProperties structure
 		// Example: map<string, map<string, int32>> becomes:
@@ -406,7 +720,7 @@ func (g *OpenAPIGenerator) convertFieldToProperty(field *ast.Field, typeNameMap
 	}
 
 	// Add field documentation
-	if doc := field.Doc.GetDoc("openapi"); doc != "" {
+	if doc := field.Doc.GetLongDoc("openapi"); doc != "" {
 		property.Description = doc
 	}
 
@@ -477,6 +791,11 @@ func (g *OpenAPIGenerator) convertFieldToProperty(field *ast.Field, typeNameMap
 		}
 	}
 
+	// Mark sensitive data; an explicit @openapi.extension(x-sensitive: ...) wins
+	if field.Sensitivity != "" {
+		property.Extensions["x-sensitive"] = field.Sensitivity
+	}
+
 	// Add OpenAPI extensions from field annotations
 	if field.Annotations != nil && len(field.Annotations.OpenAPI) > 0 {
 		for _, ext := range field.Annotations.OpenAPI {
@@ -507,6 +826,13 @@ func (g *OpenAPIGenerator) convertFieldToProperty(field *ast.Field, typeNameMap
 
 	if field.Type.IsArray {
 		property.Type = "array"
+
+		// Arrays of arrays and of maps nest their items
+		if element := field.Type.ElementType(); element.IsArray || element.IsMap {
+			property.Items = g.generateAdditionalProperties(element, typeNameMap)
+			return property
+		}
+
 		property.Items = &OpenAPIPropertyItems{}
 
 		baseType := g.mapTypeToOpenAPI(field.Type.Name)
@@ -538,13 +864,15 @@ func (g *OpenAPIGenerator) convertFieldToProperty(field *ast.Field, typeNameMap
 		}
 
 		// Set minimum: 0 for unsigned integer types
-		if field.Type.Name == "uint8" || field.Type.Name == "uint16" || field.Type.Name == "uint32" || field.Type.Name == "uint64" {
+		if oaType == "integer" && (field.Type.Name == "uint8" || field.Type.Name == "uint16" || field.Type.Name == "uint32" || field.Type.Name == "uint64") {
 			zero := float64(0)
 			property.Minimum = &zero
 		}
 
-		// Set properly typed default values
-		if field.Default != "" {
+		// Set properly typed default values; 64-bit integers encoded as strings keep string defaults
+		if field.Default != "" && oaType == "string" {
+			property.Default = field.Default
+		} else if field.Default != "" {
 			property.Default = g.convertDefaultValue(field.Default, field.Type.Name)
 		}
 	} else {
@@ -564,6 +892,10 @@ func (g *OpenAPIGenerator) convertFieldToProperty(field *ast.Field, typeNameMap
 }
 
 func (g *OpenAPIGenerator) mapTypeToOpenAPI(typeName string) string {
+	if is64Bit(typeName) && g.opts.Int64.quoted() {
+		return "string"
+	}
+
 	typeMap := map[string]string{
 		"string":    "string",
 		"int32":     "integer",
@@ -587,6 +919,14 @@ func (g *OpenAPIGenerator) mapTypeToOpenAPI(typeName string) string {
 }
 
 func (g *OpenAPIGenerator) getFormatForType(typeName string) string {
+	// Strings holding the digits of unsigned 64-bit integers, as in proto3 JSON
+	if typeName == "uint64" && g.opts.Int64.quoted() {
+		return "uint64"
+	}
+	if typeName == "bytes" {
+		return g.bytesFormat()
+	}
+
 	formatMap := map[string]string{
 		"int32":     "int32",
 		"int64":     "int64",
@@ -603,6 +943,14 @@ func (g *OpenAPIGenerator) getFormatForType(typeName string) string {
 	return formatMap[typeName]
 }
 
+// bytesFormat returns the format of bytes fields
+func (g *OpenAPIGenerator) bytesFormat() string {
+	if g.opts.BytesFormat == OpenAPIBytesBinary {
+		return string(OpenAPIBytesBinary)
+	}
+	return string(OpenAPIBytesBase64)
+}
+
 func (g *OpenAPIGenerator) convertDefaultValue(defaultStr string, typeName string) interface{} {
 	// Convert string default values to proper types for YAML/JSON
 	switch typeName {
@@ -630,29 +978,89 @@ func (g *OpenAPIGenerator) convertDefaultValue(defaultStr string, typeName strin
 }
 
 func (g *OpenAPIGenerator) addServiceMethod(spec *OpenAPISpec, service *ast.Service, method *ast.Method, typeNameMap map[string]string) {
-	// Use custom path template if provided, otherwise generate from service/method name
-	var path string
-	if method.PathTemplate != "" {
-		path = method.PathTemplate
-	} else {
-		path = fmt.Sprintf("/%s/%s", strings.ToLower(service.Name), strings.ToLower(method.Name))
-	}
+	path := method.HTTPPath(service)
 
 	// Use GetHTTPMethod which checks annotation or uses heuristics
 	httpMethod := method.GetHTTPMethod()
 
+	if spec.Paths[path] == nil {
+		spec.Paths[path] = make(map[string]OpenAPIOperation)
+	}
+	operation := g.methodOperation(spec, method, path, httpMethod, typeNameMap)
+	operation.Extensions = ownershipExtensions(operation.Extensions, g.ownershipOf(service.Namespace, service.Annotations))
+	spec.Paths[path][httpMethod] = operation
+}
+
+// addWebhook adds a webhook to the x-webhooks of the spec, or a callback to the
+// operation of the method whose calls register it
+func (g *OpenAPIGenerator) addWebhook(spec *OpenAPISpec, service *ast.Service, method *ast.Method, typeNameMap map[string]string) {
+	httpMethod := method.WebhookHTTPMethod()
+	operation := g.methodOperation(spec, method, "", httpMethod, typeNameMap)
+	operation.Extensions = ownershipExtensions(operation.Extensions, g.ownershipOf(service.Namespace, service.Annotations))
+	webhook := method.Webhook
+
+	if !webhook.IsCallback() {
+		if spec.Webhooks == nil {
+			spec.Webhooks = make(map[string]map[string]OpenAPIOperation)
+		}
+		if spec.Webhooks[webhook.Name] == nil {
+			spec.Webhooks[webhook.Name] = make(map[string]OpenAPIOperation)
+		}
+		spec.Webhooks[webhook.Name][httpMethod] = operation
+		return
+	}
+
+	for _, on := range service.Methods {
+		if on.Name != webhook.On || on.IsWebhook() {
+			continue
+		}
+		path, onMethod := on.HTTPPath(service), on.GetHTTPMethod()
+		parent, ok := spec.Paths[path][onMethod]
+		if !ok {
+			return
+		}
+		if parent.Callbacks == nil {
+			parent.Callbacks = make(map[string]OpenAPICallback)
+		}
+		if parent.Callbacks[webhook.Name] == nil {
+			parent.Callbacks[webhook.Name] = make(OpenAPICallback)
+		}
+		if parent.Callbacks[webhook.Name][webhook.URL] == nil {
+			parent.Callbacks[webhook.Name][webhook.URL] = make(map[string]OpenAPIOperation)
+		}
+		parent.Callbacks[webhook.Name][webhook.URL][httpMethod] = operation
+		spec.Paths[path][onMethod] = parent
+		return
+	}
+}
+
+// methodOperation builds the operation of a method sent with an HTTP method to a
+// path; webhooks have no path of their own
+func (g *OpenAPIGenerator) methodOperation(spec *OpenAPISpec, method *ast.Method, path, httpMethod string, typeNameMap map[string]string) OpenAPIOperation {
 	operation := OpenAPIOperation{
 		Summary:     fmt.Sprintf("%s operation", method.Name),
+		Description: method.Doc.GetLongDoc("openapi"),
 		OperationID: method.Name,
 		Responses:   make(map[string]OpenAPIResponse),
 	}
 
+	// Describe the call policies as extensions
+	if method.HasPolicy() {
+		operation.Extensions = g.policyExtensions(method)
+	}
+
 	// Extract and add path parameters
-	pathParams := g.extractPathParameters(path)
+	inputType := g.types[ast.GetUnqualifiedName(method.InputType)]
+	pathParams := g.extractPathParameters(path, inputType, typeNameMap)
 	if len(pathParams) > 0 {
 		operation.Parameters = pathParams
 	}
 
+	// GET and DELETE requests carry no body, so expand the request fields into parameters
+	if inputType != nil && (httpMethod == "get" || httpMethod == "delete") {
+		operation.Parameters = g.addRequestParameters(operation.Parameters, inputType, typeNameMap)
+	}
+
 	// Resolve input type name (check for custom name)
 	inputTypeName := method.InputType
 	if customName, ok := typeNameMap[method.InputType]; ok {
@@ -665,8 +1073,8 @@ func (g *OpenAPIGenerator) addServiceMethod(spec *OpenAPISpec, service *ast.Serv
 		outputTypeName = customName
 	}
 
-	// Add request body for POST/PUT/PATCH methods
-	if httpMethod == "post" || httpMethod == "put" || httpMethod == "patch" {
+	// Add request body for POST/PUT/PATCH methods that take input
+	if method.HasInput() && (httpMethod == "post" || httpMethod == "put" || httpMethod == "patch") {
 		operation.RequestBody = &OpenAPIRequestBody{
 			Required: true,
 			Content: map[string]OpenAPIMediaType{
@@ -679,60 +1087,380 @@ func (g *OpenAPIGenerator) addServiceMethod(spec *OpenAPISpec, service *ast.Serv
 		}
 	}
 
-	// Add default 200 response
-	operation.Responses["200"] = OpenAPIResponse{
-		Description: "Successful response",
-		Content: map[string]OpenAPIMediaType{
-			"application/json": {
-				Schema: OpenAPISchemaRef{
-					Ref: fmt.Sprintf("#/components/schemas/%s", outputTypeName),
+	if method.LongRunning != nil {
+		// A long-running method accepts the work and links the operation to its polling endpoint
+		poll := ast.PollMethodName(method.Name)
+		operation.Responses["202"] = OpenAPIResponse{
+			Description: g.getSuccessDescription("202"),
+			Content: map[string]OpenAPIMediaType{
+				"application/json": {
+					Schema: g.responseSchema(outputTypeName),
 				},
 			},
-		},
+			Links: map[string]OpenAPILink{
+				poll: {
+					OperationID: poll,
+					Parameters:  map[string]string{"name": "$response.body#/name"},
+					Description: "Poll the operation until it is done",
+				},
+			},
+		}
+	} else if method.HasOutput() {
+		// Add default 200 response
+		operation.Responses["200"] = OpenAPIResponse{
+			Description: "Successful response",
+			Content: map[string]OpenAPIMediaType{
+				"application/json": {
+					Schema: g.responseSchema(outputTypeName),
+				},
+			},
+		}
+	} else {
+		// A method without output answers 204 with no body
+		operation.Responses["204"] = OpenAPIResponse{Description: g.getSuccessDescription("204")}
 	}
 
 	// Add additional success responses
 	for _, code := range method.SuccessCodes {
-		operation.Responses[code] = OpenAPIResponse{
-			Description: g.getSuccessDescription(code),
-			Content: map[string]OpenAPIMediaType{
+		response := OpenAPIResponse{Description: g.getSuccessDescription(code)}
+		if method.HasOutput() {
+			response.Content = map[string]OpenAPIMediaType{
 				"application/json": {
-					Schema: OpenAPISchemaRef{
-						Ref: fmt.Sprintf("#/components/schemas/%s", outputTypeName),
-					},
+					Schema: g.responseSchema(outputTypeName),
 				},
-			},
+			}
 		}
+		operation.Responses[code] = response
 	}
 
-	// Add error responses
-	for _, code := range method.ErrorCodes {
-		operation.Responses[code] = OpenAPIResponse{
-			Description: g.getErrorDescription(code),
-			Content: map[string]OpenAPIMediaType{
-				"application/json": {
-					Schema: OpenAPISchemaRef{
-						Type: "object",
-						Properties: map[string]OpenAPIProperty{
-							"error": {
-								Type:        "string",
-								Description: "Error message",
-							},
-							"code": {
-								Type:        "string",
-								Description: "Error code",
-							},
-						},
+	// Add error responses referencing the shared error schema
+	if len(method.ErrorCodes) > 0 {
+		errorRef := g.ensureErrorSchema(spec)
+		for _, code := range method.ErrorCodes {
+			operation.Responses[code] = OpenAPIResponse{
+				Description: g.getErrorDescription(code),
+				Content: map[string]OpenAPIMediaType{
+					g.errorContentType(): {
+						Schema: OpenAPISchemaRef{Ref: errorRef},
 					},
 				},
+			}
+		}
+	}
+
+	g.addExamples(&operation, method)
+	if len(method.CodeSamples) > 0 {
+		if operation.Extensions == nil {
+			operation.Extensions = make(map[string]interface{})
+		}
+		samples := make([]OpenAPICodeSample, len(method.CodeSamples))
+		for i, sample := range method.CodeSamples {
+			samples[i] = OpenAPICodeSample{Lang: sample.Lang, Label: sample.Label, Source: sample.Source}
+		}
+		operation.Extensions["x-codeSamples"] = samples
+	}
+
+	return operation
+}
+
+// addExamples adds the examples of a method to its operation: requests to the
+// request body, or to the parameters named by their fields when there is no
+// body, and responses to the success responses with content
+func (g *OpenAPIGenerator) addExamples(operation *OpenAPIOperation, method *ast.Method) {
+	for _, example := range method.Examples {
+		if example.Request != nil {
+			var request interface{}
+			if err := json.Unmarshal(example.Request, &request); err != nil {
+				continue
+			}
+			if operation.RequestBody != nil {
+				addMediaExample(operation.RequestBody.Content, example.Name, OpenAPIExample{Summary: example.Summary, Value: request})
+			} else if fields, ok := request.(map[string]interface{}); ok {
+				for i, param := range operation.Parameters {
+					value, ok := fields[param.Name]
+					if !ok || param.Ref != "" {
+						continue
+					}
+					if param.Examples == nil {
+						param.Examples = make(map[string]OpenAPIExample)
+					}
+					param.Examples[example.Name] = OpenAPIExample{Summary: example.Summary, Value: value}
+					operation.Parameters[i] = param
+				}
+			}
+		}
+		if example.Response != nil {
+			var response interface{}
+			if err := json.Unmarshal(example.Response, &response); err != nil {
+				continue
+			}
+			for code, resp := range operation.Responses {
+				if strings.HasPrefix(code, "2") {
+					addMediaExample(resp.Content, example.Name, OpenAPIExample{Summary: example.Summary, Value: response})
+				}
+			}
+		}
+	}
+}
+
+// addMediaExample adds a named example to the JSON media type of a content map
+func addMediaExample(content map[string]OpenAPIMediaType, name string, example OpenAPIExample) {
+	media, ok := content["application/json"]
+	if !ok {
+		return
+	}
+	if media.Examples == nil {
+		media.Examples = make(map[string]OpenAPIExample)
+	}
+	media.Examples[name] = example
+	content["application/json"] = media
+}
+
+// policyExtensions describes the timeout, idempotency, and rate limit of a method
+// as x-timeout, x-idempotent, and x-ratelimit operation extensions
+func (g *OpenAPIGenerator) policyExtensions(method *ast.Method) map[string]interface{} {
+	extensions := make(map[string]interface{})
+	if method.Timeout != "" {
+		extensions["x-timeout"] = method.Timeout
+	}
+	if method.Idempotent {
+		extensions["x-idempotent"] = true
+	}
+	if method.RateLimit != nil {
+		extensions["x-ratelimit"] = map[string]interface{}{
+			"requests": method.RateLimit.Requests,
+			"per":      method.RateLimit.Per,
+		}
+	}
+	if method.Auth != nil {
+		scopes := method.Auth.Scopes
+		if scopes == nil {
+			scopes = []string{}
+		}
+		extensions["x-auth"] = map[string]interface{}{"scopes": scopes}
+	}
+	return extensions
+}
+
+// propertyName returns the name of the property of a field: its proto3 JSON
+// name in the ProtoJSON mode, or else its @openapi.name, @json.name, or name
+func (g *OpenAPIGenerator) propertyName(field *ast.Field) string {
+	if g.opts.ProtoJSON {
+		return protoJSONName(field)
+	}
+	return field.NameFor("openapi")
+}
+
+// protoJSONName returns the proto3 JSON name of a field: the value of its
+// json_name option, or else its Protobuf name in lowerCamelCase as protoc
+// derives it, removing underscores and capitalizing the letters after them
+func protoJSONName(field *ast.Field) string {
+	if field.Annotations != nil {
+		for _, option := range field.Annotations.Proto {
+			name, value, ok := strings.Cut(option, "=")
+			if ok && strings.TrimSpace(name) == "json_name" {
+				return strings.Trim(strings.TrimSpace(value), `"`)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	capitalize := false
+	for _, r := range field.NameFor("proto") {
+		switch {
+		case r == '_':
+			capitalize = true
+		case capitalize:
+			sb.WriteString(strings.ToUpper(string(r)))
+			capitalize = false
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// ownershipOf returns the effective ownership of an element of a namespace,
+// falling back to its namespace when generating a schema
+func (g *OpenAPIGenerator) ownershipOf(namespace string, annotations *ast.FormatAnnotations) *ast.Ownership {
+	if g.schema != nil {
+		return g.schema.OwnershipOf(namespace, annotations)
+	}
+	if annotations != nil {
+		return annotations.Ownership
+	}
+	return nil
+}
+
+// ownershipExtensions adds the owner, SLA, and tier of an element to its
+// extensions as x-owner, x-sla, and x-tier, creating them if needed; an
+// explicit @openapi.extension of the same name wins
+func ownershipExtensions(extensions map[string]interface{}, ownership *ast.Ownership) map[string]interface{} {
+	if ownership == nil {
+		return extensions
+	}
+	for name, value := range map[string]string{"x-owner": ownership.Owner, "x-sla": ownership.SLA, "x-tier": ownership.Tier} {
+		if value == "" {
+			continue
+		}
+		if extensions == nil {
+			extensions = make(map[string]interface{})
+		}
+		if _, ok := extensions[name]; !ok {
+			extensions[name] = value
+		}
+	}
+	return extensions
+}
+
+// errorSchemaName returns the component name of the shared error schema
+func (g *OpenAPIGenerator) errorSchemaName() string {
+	if g.opts.ErrorSchemaName != "" {
+		return g.opts.ErrorSchemaName
+	}
+	if g.opts.ProblemDetails {
+		return "Problem"
+	}
+	return "Error"
+}
+
+// errorContentType returns the media type used for error responses
+func (g *OpenAPIGenerator) errorContentType() string {
+	if g.opts.ProblemDetails {
+		return "application/problem+json"
+	}
+	return "application/json"
+}
+
+// ensureErrorSchema adds the shared error schema to the components if it is not
+// defined yet and returns a reference to it
+func (g *OpenAPIGenerator) ensureErrorSchema(spec *OpenAPISpec) string {
+	name := g.errorSchemaName()
+	if spec.Components.Schemas == nil {
+		spec.Components.Schemas = make(map[string]OpenAPISchema)
+	}
+	if _, exists := spec.Components.Schemas[name]; !exists {
+		if g.opts.ProblemDetails {
+			spec.Components.Schemas[name] = g.problemSchema()
+		} else {
+			spec.Components.Schemas[name] = g.errorSchema()
+		}
+	}
+	return fmt.Sprintf("#/components/schemas/%s", name)
+}
+
+// errorSchema returns the default error response schema
+func (g *OpenAPIGenerator) errorSchema() OpenAPISchema {
+	return OpenAPISchema{
+		Type:        "object",
+		Description: "Error response",
+		Properties: map[string]OpenAPIProperty{
+			"error": {
+				Type:        "string",
+				Description: "Error message",
+			},
+			"code": {
+				Type:        "string",
+				Description: "Error code",
 			},
+		},
+		Required: []string{"error"},
+	}
+}
+
+// problemSchema returns an RFC 7807 problem details schema
+func (g *OpenAPIGenerator) problemSchema() OpenAPISchema {
+	return OpenAPISchema{
+		Type:        "object",
+		Description: "Problem details for HTTP APIs (RFC 7807)",
+		Properties: map[string]OpenAPIProperty{
+			"type": {
+				Type:        "string",
+				Format:      "uri",
+				Description: "URI reference that identifies the problem type",
+				Default:     "about:blank",
+			},
+			"title": {
+				Type:        "string",
+				Description: "Short, human-readable summary of the problem type",
+			},
+			"status": {
+				Type:        "integer",
+				Format:      "int32",
+				Description: "HTTP status code generated by the origin server",
+			},
+			"detail": {
+				Type:        "string",
+				Description: "Human-readable explanation specific to this occurrence of the problem",
+			},
+			"instance": {
+				Type:        "string",
+				Format:      "uri",
+				Description: "URI reference that identifies the specific occurrence of the problem",
+			},
+		},
+	}
+}
+
+// addRequestParameters turns the scalar, enum, and array fields of a request that
+// fill no path parameter into query parameters. Map and message fields cannot be
+// expressed as simple query parameters and are skipped.
+func (g *OpenAPIGenerator) addRequestParameters(params []OpenAPIParameter, typ *ast.Type, typeNameMap map[string]string) []OpenAPIParameter {
+	pathFields := make(map[*ast.Field]bool)
+	for _, param := range params {
+		if param.In == "path" {
+			pathFields[ast.PathField(typ, param.Name)] = true
 		}
 	}
 
-	if spec.Paths[path] == nil {
-		spec.Paths[path] = make(map[string]OpenAPIOperation)
+	for _, field := range typ.Fields {
+		if pathFields[field] || !field.ShouldIncludeInGenerator("openapi") || len(field.Arguments) > 0 || field.Type.IsMap {
+			continue
+		}
+		if !ast.IsBuiltinType(field.Type.Name) {
+			if _, isMessage := g.types[ast.GetUnqualifiedName(field.Type.Name)]; isMessage {
+				continue
+			}
+		}
+
+		name := g.propertyName(field)
+
+		property := g.convertFieldToProperty(field, typeNameMap)
+		param := OpenAPIParameter{
+			Name:        name,
+			In:          "query",
+			Required:    field.Presence() == ast.PresenceRequired,
+			Description: property.Description,
+			Deprecated:  property.Deprecated,
+			Schema:      g.parameterSchemaFromProperty(property),
+		}
+		params = append(params, param)
+	}
+
+	return params
+}
+
+// parameterSchemaFromProperty converts a schema property to a parameter schema
+func (g *OpenAPIGenerator) parameterSchemaFromProperty(property OpenAPIProperty) *OpenAPIParameterSchema {
+	return &OpenAPIParameterSchema{
+		Type:             property.Type,
+		Format:           property.Format,
+		Ref:              property.Ref,
+		Items:            property.Items,
+		Default:          property.Default,
+		Enum:             property.Enum,
+		MinLength:        property.MinLength,
+		MaxLength:        property.MaxLength,
+		Pattern:          property.Pattern,
+		Minimum:          property.Minimum,
+		Maximum:          property.Maximum,
+		ExclusiveMinimum: property.ExclusiveMinimum,
+		ExclusiveMaximum: property.ExclusiveMaximum,
+		MultipleOf:       property.MultipleOf,
+		MinItems:         property.MinItems,
+		MaxItems:         property.MaxItems,
+		UniqueItems:      property.UniqueItems,
 	}
-	spec.Paths[path][httpMethod] = operation
 }
 
 // getSuccessDescription returns a description for common HTTP success codes
@@ -825,6 +1553,7 @@ func (g *OpenAPIGenerator) addFieldArgumentPaths(spec *OpenAPISpec, typ *ast.Typ
 		// Create operation
 		operation := OpenAPIOperation{
 			Summary:     fmt.Sprintf("Get %s for %s", field.Name, typ.Name),
+			Description: field.Doc.GetLongDoc("openapi"),
 			OperationID: fmt.Sprintf("Get%s%s", typ.Name, g.capitalize(field.Name)),
 			Responses:   make(map[string]OpenAPIResponse),
 			Parameters:  []OpenAPIParameter{},
@@ -836,7 +1565,7 @@ func (g *OpenAPIGenerator) addFieldArgumentPaths(spec *OpenAPISpec, typ *ast.Typ
 				Name:     idFieldName,
 				In:       "path",
 				Required: true,
-				Schema: OpenAPIParameterSchema{
+				Schema: &OpenAPIParameterSchema{
 					Type: "string",
 				},
 			})
@@ -851,7 +1580,7 @@ func (g *OpenAPIGenerator) addFieldArgumentPaths(spec *OpenAPISpec, typ *ast.Typ
 				Schema:   g.convertFieldTypeToParameterSchema(arg.Type, arg.Default),
 			}
 			if arg.Doc != nil {
-				param.Description = arg.Doc.GetDoc("openapi")
+				param.Description = arg.Doc.GetLongDoc("openapi")
 			}
 			operation.Parameters = append(operation.Parameters, param)
 		}
@@ -913,8 +1642,8 @@ func (g *OpenAPIGenerator) capitalize(s string) string {
 }
 
 // convertFieldTypeToParameterSchema converts a field type to OpenAPI parameter schema
-func (g *OpenAPIGenerator) convertFieldTypeToParameterSchema(fieldType *ast.FieldType, defaultValue string) OpenAPIParameterSchema {
-	schema := OpenAPIParameterSchema{}
+func (g *OpenAPIGenerator) convertFieldTypeToParameterSchema(fieldType *ast.FieldType, defaultValue string) *OpenAPIParameterSchema {
+	schema := &OpenAPIParameterSchema{}
 
 	switch fieldType.Name {
 	case "string":
@@ -958,7 +1687,12 @@ func (g *OpenAPIGenerator) convertFieldTypeToParameterSchema(fieldType *ast.Fiel
 func (g *OpenAPIGenerator) convertFieldTypeToSchema(fieldType *ast.FieldType, typeNameMap map[string]string) OpenAPISchemaRef {
 	schema := OpenAPISchemaRef{}
 
-	if fieldType.IsArray {
+	if element := fieldType.ElementType(); element != nil && (element.IsArray || element.IsMap) {
+		// Array of arrays or of maps
+		items := g.convertFieldTypeToSchema(element, typeNameMap)
+		schema.Type = "array"
+		schema.Items = &items
+	} else if fieldType.IsArray {
 		// Array type
 		elementTypeName := fieldType.Name
 		if customName, ok := typeNameMap[elementTypeName]; ok {
@@ -982,7 +1716,9 @@ func (g *OpenAPIGenerator) convertFieldTypeToSchema(fieldType *ast.FieldType, ty
 		// Map type - represented as object with additionalProperties
 		schema.Type = "object"
 		valueType := fieldType.GetMapValueType()
-		if ast.IsBuiltinType(valueType.Name) {
+		if valueType.IsArray || valueType.IsMap {
+			schema.AdditionalProperties = g.convertFieldTypeToSchema(valueType, typeNameMap)
+		} else if ast.IsBuiltinType(valueType.Name) {
 			schema.AdditionalProperties = map[string]interface{}{
 				"type": g.mapBuiltinTypeToOpenAPI(valueType.Name),
 			}
@@ -1001,7 +1737,7 @@ func (g *OpenAPIGenerator) convertFieldTypeToSchema(fieldType *ast.FieldType, ty
 		if fieldType.Name == "timestamp" {
 			schema.Format = "date-time"
 		} else if fieldType.Name == "bytes" {
-			schema.Format = "byte"
+			schema.Format = g.bytesFormat()
 		}
 	} else {
 		// Custom type
@@ -1017,6 +1753,9 @@ func (g *OpenAPIGenerator) convertFieldTypeToSchema(fieldType *ast.FieldType, ty
 
 // mapBuiltinTypeToOpenAPI maps TypeMUX builtin types to OpenAPI types
 func (g *OpenAPIGenerator) mapBuiltinTypeToOpenAPI(typeName string) string {
+	if is64Bit(typeName) && g.opts.Int64.quoted() {
+		return "string"
+	}
 	switch typeName {
 	case "string", "timestamp", "bytes":
 		return "string"
@@ -1031,28 +1770,29 @@ func (g *OpenAPIGenerator) mapBuiltinTypeToOpenAPI(typeName string) string {
 	}
 }
 
-func (g *OpenAPIGenerator) extractPathParameters(path string) []OpenAPIParameter {
+// extractPathParameters describes the {name} parameters of a path, typed after
+// the request fields that fill them; parameters without one are strings
+func (g *OpenAPIGenerator) extractPathParameters(path string, input *ast.Type, typeNameMap map[string]string) []OpenAPIParameter {
 	var params []OpenAPIParameter
-
-	// Find all {paramName} patterns in the path
-	start := -1
-	for i := 0; i < len(path); i++ {
-		if path[i] == '{' {
-			start = i + 1
-		} else if path[i] == '}' && start != -1 {
-			paramName := path[start:i]
-			params = append(params, OpenAPIParameter{
-				Name:     paramName,
-				In:       "path",
-				Required: true,
-				Schema: OpenAPIParameterSchema{
-					Type: "string",
-				},
-			})
-			start = -1
+	for _, name := range ast.PathParameters(path) {
+		param := OpenAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema: &OpenAPIParameterSchema{
+				Type: "string",
+			},
+		}
+		if input != nil {
+			if field := ast.PathField(input, name); field != nil && field.Type != nil && !field.Type.IsArray && !field.Type.IsMap {
+				property := g.convertFieldToProperty(field, typeNameMap)
+				param.Description = property.Description
+				param.Deprecated = property.Deprecated
+				param.Schema = g.parameterSchemaFromProperty(property)
+			}
 		}
+		params = append(params, param)
 	}
-
 	return params
 }
 
@@ -364,6 +364,36 @@ func TestOpenAPIGenerator_HTTPMethodSelection(t *testing.T) {
 	}
 }
 
+func TestOpenAPIGenerator_FlagsEnumSchema(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{
+			{
+				Name:  "Permission",
+				Flags: true,
+				Values: []*ast.EnumValue{
+					{Name: "NONE", Number: 0, HasNumber: true},
+					{Name: "READ", Doc: &ast.Documentation{General: "May read"}},
+					{Name: "WRITE"},
+				},
+			},
+		},
+	}
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(schema)), &spec); err != nil {
+		t.Fatalf("Failed to parse YAML: %v", err)
+	}
+
+	permission := spec.Components.Schemas["Permission"]
+	if permission.Type != "integer" || permission.Format != "int32" || len(permission.Enum) != 0 {
+		t.Errorf("Expected an int32 schema without enum values, got %+v", permission)
+	}
+	expected := "Bit flags, combined with bitwise OR:\n- 0: NONE\n- 1: READ - May read\n- 2: WRITE"
+	if permission.Description != expected {
+		t.Errorf("Unexpected description:\n%s", permission.Description)
+	}
+}
+
 func TestOpenAPIGenerator_EnumSchema(t *testing.T) {
 	schema := &ast.Schema{
 		Enums: []*ast.Enum{
@@ -1478,6 +1508,38 @@ func TestOpenAPIGenerator_MapTypes_Integration(t *testing.T) {
 	}
 }
 
+func TestOpenAPIGenerator_NestedContainers(t *testing.T) {
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(nestedContainersSchema())), &spec); err != nil {
+		t.Fatalf("Failed to parse OpenAPI YAML: %v", err)
+	}
+	holder := spec.Components.Schemas["Holder"]
+
+	rows := holder.Properties["rows"]
+	if rows.Type != "array" || rows.Items == nil || rows.Items.Type != "object" || rows.Items.AdditionalProperties == nil || rows.Items.AdditionalProperties.Type != "integer" {
+		t.Errorf("Expected rows to be an array of objects of integers, got %+v", rows.Items)
+	}
+
+	groups := holder.Properties["groups"]
+	values := groups.AdditionalProperties
+	if groups.Type != "object" || values == nil || values.Type != "array" || values.Items == nil || values.Items.Ref != "#/components/schemas/Item" {
+		t.Errorf("Expected groups to be an object of arrays of Item, got %+v", values)
+	}
+	if groups.Description != "Map of string to []Item" {
+		t.Errorf("Unexpected description %q", groups.Description)
+	}
+
+	matrix := holder.Properties["matrix"]
+	if matrix.Items == nil || matrix.Items.Type != "array" || matrix.Items.Items == nil || matrix.Items.Items.Type != "number" {
+		t.Errorf("Expected matrix to be an array of arrays of numbers, got %+v", matrix.Items)
+	}
+
+	tagged := holder.Properties["tagged"]
+	if tagged.Items == nil || tagged.Items.AdditionalProperties == nil || tagged.Items.AdditionalProperties.Ref != "#/components/schemas/Item" {
+		t.Errorf("Expected tagged to be an array of objects of Item, got %+v", tagged.Items)
+	}
+}
+
 func TestOpenAPIGenerator_NestedMaps(t *testing.T) {
 	gen := NewOpenAPIGenerator()
 
@@ -1770,3 +1832,734 @@ func TestOpenAPIGenerator_CombinedJSONAnnotations(t *testing.T) {
 		t.Error("Expected original name 'phoneNumber' to not be present when @json.name is used")
 	}
 }
+
+func TestOpenAPIGenerator_SharedErrorSchema(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{Name: "GetUserRequest", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+			{Name: "User", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "UserService",
+				Methods: []*ast.Method{
+					{Name: "GetUser", InputType: "GetUserRequest", OutputType: "User", ErrorCodes: []string{"404", "500"}},
+					{Name: "DeleteUser", InputType: "GetUserRequest", OutputType: "User", ErrorCodes: []string{"404"}},
+				},
+			},
+		},
+	}
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(schema)), &spec); err != nil {
+		t.Fatalf("failed to parse generated spec: %v", err)
+	}
+
+	errorSchema, ok := spec.Components.Schemas["Error"]
+	if !ok {
+		t.Fatal("expected shared Error schema in components")
+	}
+	if _, ok := errorSchema.Properties["error"]; !ok {
+		t.Error("expected Error schema to have an error property")
+	}
+
+	for path, op := range map[string]string{"/userservice/getuser": "get", "/userservice/deleteuser": "post"} {
+		response := spec.Paths[path][op].Responses["404"]
+		content, ok := response.Content["application/json"]
+		if !ok {
+			t.Fatalf("expected application/json error content for %s", path)
+		}
+		if content.Schema.Ref != "#/components/schemas/Error" {
+			t.Errorf("expected %s 404 to reference Error schema, got %q", path, content.Schema.Ref)
+		}
+	}
+}
+
+func TestOpenAPIGenerator_ProblemDetails(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{Name: "User", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "UserService",
+				Methods: []*ast.Method{
+					{Name: "CreateUser", InputType: "User", OutputType: "User", ErrorCodes: []string{"409"}},
+				},
+			},
+		},
+	}
+
+	output := NewOpenAPIGeneratorWithOptions(&OpenAPIOptions{ProblemDetails: true}).Generate(schema)
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(output), &spec); err != nil {
+		t.Fatalf("failed to parse generated spec: %v", err)
+	}
+
+	problem, ok := spec.Components.Schemas["Problem"]
+	if !ok {
+		t.Fatal("expected Problem schema in components")
+	}
+	for _, prop := range []string{"type", "title", "status", "detail", "instance"} {
+		if _, ok := problem.Properties[prop]; !ok {
+			t.Errorf("expected Problem schema to have %s property", prop)
+		}
+	}
+	if _, ok := spec.Components.Schemas["Error"]; ok {
+		t.Error("expected no Error schema when using problem details")
+	}
+
+	content, ok := spec.Paths["/userservice/createuser"]["post"].Responses["409"].Content["application/problem+json"]
+	if !ok {
+		t.Fatal("expected application/problem+json error content")
+	}
+	if content.Schema.Ref != "#/components/schemas/Problem" {
+		t.Errorf("expected 409 to reference Problem schema, got %q", content.Schema.Ref)
+	}
+}
+
+func TestOpenAPIGenerator_UserDefinedErrorSchema(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{Name: "Error", Fields: []*ast.Field{{Name: "reason", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+			{Name: "User", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+		},
+		Services: []*ast.Service{
+			{
+				Name:    "UserService",
+				Methods: []*ast.Method{{Name: "GetUser", InputType: "User", OutputType: "User", ErrorCodes: []string{"404"}}},
+			},
+		},
+	}
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(schema)), &spec); err != nil {
+		t.Fatalf("failed to parse generated spec: %v", err)
+	}
+
+	if _, ok := spec.Components.Schemas["Error"].Properties["reason"]; !ok {
+		t.Error("expected user-defined Error schema to be preserved")
+	}
+}
+
+func TestOpenAPIGenerator_NoErrorSchemaWithoutErrors(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{Name: "User", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+		},
+		Services: []*ast.Service{
+			{Name: "UserService", Methods: []*ast.Method{{Name: "GetUser", InputType: "User", OutputType: "User"}}},
+		},
+	}
+
+	output := NewOpenAPIGenerator().Generate(schema)
+	if strings.Contains(output, "Error:") {
+		t.Error("expected no shared error schema when no method declares errors")
+	}
+}
+
+func TestOpenAPIGenerator_GetQueryParameters(t *testing.T) {
+	minLen := 3
+	maxLimit := float64(100)
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{
+			{Name: "Status", Values: []*ast.EnumValue{{Name: "ACTIVE"}, {Name: "INACTIVE"}}},
+		},
+		Types: []*ast.Type{
+			{Name: "Filter", Fields: []*ast.Field{{Name: "term", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+			{
+				Name: "ListUsersRequest",
+				Fields: []*ast.Field{
+					{Name: "orgId", Type: &ast.FieldType{Name: "string", IsBuiltin: true}, Required: true},
+					{Name: "query", Type: &ast.FieldType{Name: "string", IsBuiltin: true}, Required: true, Validation: &ast.ValidationRules{MinLength: &minLen}},
+					{Name: "limit", Type: &ast.FieldType{Name: "int32", IsBuiltin: true}, Default: "20", Validation: &ast.ValidationRules{Max: &maxLimit}},
+					{Name: "status", Type: &ast.FieldType{Name: "Status"}},
+					{Name: "tags", Type: &ast.FieldType{Name: "string", IsBuiltin: true, IsArray: true}},
+					{Name: "filter", Type: &ast.FieldType{Name: "Filter"}},
+					{Name: "labels", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "string"}},
+				},
+			},
+			{Name: "ListUsersResponse", Fields: []*ast.Field{{Name: "total", Type: &ast.FieldType{Name: "int32", IsBuiltin: true}}}},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "UserService",
+				Methods: []*ast.Method{
+					{Name: "ListUsers", InputType: "ListUsersRequest", OutputType: "ListUsersResponse", HTTPMethod: "GET", PathTemplate: "/orgs/{orgId}/users"},
+					{Name: "CreateUser", InputType: "ListUsersRequest", OutputType: "ListUsersResponse", HTTPMethod: "POST", PathTemplate: "/orgs/{orgId}/users"},
+				},
+			},
+		},
+	}
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(schema)), &spec); err != nil {
+		t.Fatalf("failed to parse generated spec: %v", err)
+	}
+
+	params := make(map[string]OpenAPIParameter)
+	for _, param := range spec.Paths["/orgs/{orgId}/users"]["get"].Parameters {
+		params[param.Name] = param
+	}
+
+	if len(params) != 5 {
+		t.Errorf("expected 5 parameters (1 path, 4 query), got %d: %+v", len(params), params)
+	}
+
+	if p := params["orgId"]; p.In != "path" || !p.Required {
+		t.Errorf("expected orgId to remain a required path parameter, got %+v", p)
+	}
+
+	if p := params["query"]; p.In != "query" || !p.Required || p.Schema.MinLength == nil || *p.Schema.MinLength != 3 {
+		t.Errorf("expected required query parameter with minLength, got %+v", p)
+	}
+
+	if p := params["limit"]; p.Required || p.Schema.Type != "integer" || p.Schema.Default != 20 || p.Schema.Maximum == nil {
+		t.Errorf("expected optional integer limit with default and maximum, got %+v", p.Schema)
+	}
+
+	if p := params["status"]; p.Schema.Ref != "#/components/schemas/Status" {
+		t.Errorf("expected status to reference the Status enum, got %+v", p.Schema)
+	}
+
+	if p := params["tags"]; p.Schema.Type != "array" || p.Schema.Items == nil || p.Schema.Items.Type != "string" {
+		t.Errorf("expected tags to be an array of strings, got %+v", p.Schema)
+	}
+
+	if _, ok := params["filter"]; ok {
+		t.Error("expected message fields to be skipped")
+	}
+	if _, ok := params["labels"]; ok {
+		t.Error("expected map fields to be skipped")
+	}
+
+	// POST keeps the request body and only has the path parameter
+	post := spec.Paths["/orgs/{orgId}/users"]["post"]
+	if post.RequestBody == nil || len(post.Parameters) != 1 {
+		t.Errorf("expected POST to keep request body with only path parameters, got %+v", post.Parameters)
+	}
+}
+
+func TestOpenAPIGenerator_Extends(t *testing.T) {
+	output := NewOpenAPIGenerator().Generate(extendsTestSchema(t))
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(output), &spec); err != nil {
+		t.Fatalf("Failed to parse OpenAPI YAML: %v", err)
+	}
+
+	admin := spec.Components.Schemas["Admin"]
+	if admin.Type != "" || len(admin.Properties) != 0 {
+		t.Errorf("Expected Admin to be composed with allOf, got type %q with %d properties", admin.Type, len(admin.Properties))
+	}
+	if len(admin.AllOf) != 3 {
+		t.Fatalf("Expected allOf with two base references and the own fields, got %d entries", len(admin.AllOf))
+	}
+	if admin.AllOf[0].Ref != "#/components/schemas/User" || admin.AllOf[1].Ref != "#/components/schemas/Auditable" {
+		t.Errorf("Unexpected base references: %q, %q", admin.AllOf[0].Ref, admin.AllOf[1].Ref)
+	}
+	own := admin.AllOf[2]
+	if _, ok := own.Properties["role"]; !ok || len(own.Properties) != 1 {
+		t.Errorf("Expected only the declared property role, got %v", own.Properties)
+	}
+	if strings.Join(own.Required, ",") != "role" {
+		t.Errorf("Expected role to be required, got %v", own.Required)
+	}
+
+	if user := spec.Components.Schemas["User"]; user.Type != "object" || len(user.AllOf) != 0 {
+		t.Error("Expected base type User to remain a plain object schema")
+	}
+}
+
+func TestOpenAPIGenerator_EmptyMethodTypes(t *testing.T) {
+	output := NewOpenAPIGenerator().Generate(emptyMethodTestSchema())
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(output), &spec); err != nil {
+		t.Fatalf("Failed to parse OpenAPI YAML: %v", err)
+	}
+
+	ping := spec.Paths["/healthservice/ping"]["get"]
+	if len(ping.Parameters) != 0 || ping.RequestBody != nil {
+		t.Errorf("Expected Ping to take no parameters or body, got %+v", ping)
+	}
+	if response, ok := ping.Responses["204"]; !ok || len(response.Content) != 0 {
+		t.Errorf("Expected Ping to answer 204 without content, got %+v", ping.Responses)
+	}
+	if _, ok := ping.Responses["200"]; ok {
+		t.Error("Expected no 200 response for a method without output")
+	}
+
+	reset := spec.Paths["/healthservice/reset"]["post"]
+	if reset.RequestBody == nil {
+		t.Error("Expected Reset to keep its request body")
+	}
+	if _, ok := reset.Responses["204"]; !ok {
+		t.Errorf("Expected Reset to answer 204, got %+v", reset.Responses)
+	}
+
+	status := spec.Paths["/healthservice/getstatus"]["get"]
+	if _, ok := status.Responses["200"]; !ok {
+		t.Errorf("Expected GetStatus to answer 200, got %+v", status.Responses)
+	}
+}
+
+func TestOpenAPIGenerator_MethodPolicies(t *testing.T) {
+	schema := emptyMethodTestSchema()
+	status := schema.Services[0].Methods[2]
+	status.Timeout = "5s"
+	status.Idempotent = true
+	status.RateLimit = &ast.RateLimit{Requests: 100, Per: "minute"}
+	status.Auth = &ast.Auth{Scopes: []string{"status:read"}}
+
+	output := NewOpenAPIGenerator().Generate(schema)
+
+	var spec map[string]interface{}
+	if err := yaml.Unmarshal([]byte(output), &spec); err != nil {
+		t.Fatalf("Failed to parse OpenAPI YAML: %v", err)
+	}
+	paths := spec["paths"].(map[string]interface{})
+	operation := paths["/healthservice/getstatus"].(map[string]interface{})["get"].(map[string]interface{})
+
+	if operation["x-timeout"] != "5s" || operation["x-idempotent"] != true {
+		t.Errorf("Expected x-timeout and x-idempotent extensions, got %v", operation)
+	}
+	limit, ok := operation["x-ratelimit"].(map[string]interface{})
+	if !ok || limit["requests"] != 100 || limit["per"] != "minute" {
+		t.Errorf("Expected x-ratelimit with 100 requests per minute, got %v", operation["x-ratelimit"])
+	}
+	auth, ok := operation["x-auth"].(map[string]interface{})
+	if scopes, _ := auth["scopes"].([]interface{}); !ok || len(scopes) != 1 || scopes[0] != "status:read" {
+		t.Errorf("Expected x-auth with the status:read scope, got %v", operation["x-auth"])
+	}
+
+	ping := paths["/healthservice/ping"].(map[string]interface{})["get"].(map[string]interface{})
+	for key := range ping {
+		if strings.HasPrefix(key, "x-") {
+			t.Errorf("Expected no policy extensions on Ping, got %s", key)
+		}
+	}
+}
+
+func TestOpenAPIGenerator_Webhooks(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{Name: "Payment", Fields: []*ast.Field{{Name: "callbackUrl", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+			{Name: "PaymentEvent", Fields: []*ast.Field{{Name: "paymentId", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+		},
+		Services: []*ast.Service{{Name: "PaymentService", Methods: []*ast.Method{
+			{Name: "PaymentSettled", InputType: "PaymentEvent", Webhook: &ast.Webhook{Name: "paymentSettled", On: "CreatePayment", URL: "{$request.body#/callbackUrl}"}},
+			{Name: "CreatePayment", InputType: "Payment", OutputType: "Payment", HTTPMethod: "POST", PathTemplate: "/payments"},
+			{Name: "RefundIssued", InputType: "PaymentEvent", Webhook: &ast.Webhook{Name: "refundIssued"}},
+		}}},
+	}
+
+	output := NewOpenAPIGenerator().Generate(schema)
+
+	var spec map[string]interface{}
+	if err := yaml.Unmarshal([]byte(output), &spec); err != nil {
+		t.Fatalf("Failed to parse OpenAPI YAML: %v", err)
+	}
+	paths := spec["paths"].(map[string]interface{})
+	if len(paths) != 1 {
+		t.Errorf("Expected webhooks to have no paths, got %v", paths)
+	}
+
+	// The callback is declared before its method, and still attaches to its operation
+	create := paths["/payments"].(map[string]interface{})["post"].(map[string]interface{})
+	callbacks, ok := create["callbacks"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected callbacks on CreatePayment, got %v", create)
+	}
+	settled := callbacks["paymentSettled"].(map[string]interface{})["{$request.body#/callbackUrl}"].(map[string]interface{})["post"].(map[string]interface{})
+	if settled["operationId"] != "PaymentSettled" {
+		t.Errorf("Expected the PaymentSettled operation in the callback, got %v", settled)
+	}
+	if _, ok := settled["responses"].(map[string]interface{})["204"]; !ok {
+		t.Errorf("Expected a webhook without output to answer 204, got %v", settled["responses"])
+	}
+
+	webhooks, ok := spec["x-webhooks"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected x-webhooks in output:\n%s", output)
+	}
+	refund := webhooks["refundIssued"].(map[string]interface{})["post"].(map[string]interface{})
+	if refund["operationId"] != "RefundIssued" || refund["requestBody"] == nil {
+		t.Errorf("Expected the RefundIssued operation with its payload, got %v", refund)
+	}
+}
+
+func TestOpenAPIGenerator_SpecOptions(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{Name: "User", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+		},
+		Services: []*ast.Service{
+			{
+				Name:    "UserService",
+				Methods: []*ast.Method{{Name: "GetUser", InputType: "User", OutputType: "User"}},
+			},
+		},
+	}
+
+	output := NewOpenAPIGeneratorWithOptions(&OpenAPIOptions{
+		Servers: []OpenAPIServer{{URL: "https://api.example.com", Description: "Production"}},
+		SecuritySchemes: map[string]OpenAPISecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		},
+		Security: []map[string][]string{{"bearerAuth": {}}},
+		Parameters: map[string]OpenAPIParameter{
+			"RequestId": {Name: "X-Request-ID", In: "header", Schema: &OpenAPIParameterSchema{Type: "string"}},
+		},
+	}).Generate(schema)
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(output), &spec); err != nil {
+		t.Fatalf("failed to parse generated spec: %v", err)
+	}
+
+	if len(spec.Servers) != 1 || spec.Servers[0].URL != "https://api.example.com" {
+		t.Errorf("expected one server, got %+v", spec.Servers)
+	}
+	if len(spec.Security) != 1 || spec.Security[0]["bearerAuth"] == nil {
+		t.Errorf("expected a bearerAuth security requirement, got %+v", spec.Security)
+	}
+	if scheme := spec.Components.SecuritySchemes["bearerAuth"]; scheme.Scheme != "bearer" || scheme.BearerFormat != "JWT" {
+		t.Errorf("expected a bearer security scheme, got %+v", scheme)
+	}
+	if param := spec.Components.Parameters["RequestId"]; param.Name != "X-Request-ID" || param.In != "header" {
+		t.Errorf("expected a RequestId parameter in components, got %+v", param)
+	}
+
+	params := spec.Paths["/userservice/getuser"]["get"].Parameters
+	if len(params) == 0 || params[len(params)-1].Ref != "#/components/parameters/RequestId" {
+		t.Errorf("expected the operation to reference RequestId, got %+v", params)
+	}
+}
+
+func TestOpenAPIGenerator_SensitiveField(t *testing.T) {
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(sensitiveTestSchema())), &spec); err != nil {
+		t.Fatalf("Failed to parse YAML: %v", err)
+	}
+
+	properties := spec.Components.Schemas["User"].Properties
+	if got := properties["email"].Extensions["x-sensitive"]; got != "pii" {
+		t.Errorf("Expected x-sensitive: pii on email, got %v", got)
+	}
+	if _, ok := properties["name"].Extensions["x-sensitive"]; ok {
+		t.Errorf("Expected no x-sensitive on name")
+	}
+}
+
+func TestOpenAPIGenerator_TypedPathParameters(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{
+			{Name: "Region", Values: []*ast.EnumValue{{Name: "EU"}, {Name: "US"}}},
+		},
+		Types: []*ast.Type{
+			{
+				Name: "UpdateOrderRequest",
+				Fields: []*ast.Field{
+					{Name: "order_id", Type: &ast.FieldType{Name: "int64", IsBuiltin: true}, Required: true},
+					{Name: "region", Type: &ast.FieldType{Name: "Region"}},
+					{Name: "note", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+				},
+			},
+			{Name: "Order", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "int64", IsBuiltin: true}}}},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "OrderService",
+				Methods: []*ast.Method{
+					{Name: "UpdateOrder", InputType: "UpdateOrderRequest", OutputType: "Order", HTTPMethod: "POST", PathTemplate: "/regions/{region}/orders/{order_id}"},
+					{Name: "GetOrder", InputType: "UpdateOrderRequest", OutputType: "Order", HTTPMethod: "GET", PathTemplate: "/regions/{region}/orders/{order_id}"},
+				},
+			},
+		},
+	}
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(schema)), &spec); err != nil {
+		t.Fatalf("failed to parse generated spec: %v", err)
+	}
+
+	for _, httpMethod := range []string{"post", "get"} {
+		params := make(map[string]OpenAPIParameter)
+		for _, param := range spec.Paths["/regions/{region}/orders/{order_id}"][httpMethod].Parameters {
+			params[param.Name] = param
+		}
+		if p := params["order_id"]; p.In != "path" || p.Schema.Type != "integer" || p.Schema.Format != "int64" {
+			t.Errorf("%s: expected order_id to be an int64 path parameter, got %+v", httpMethod, p.Schema)
+		}
+		if p := params["region"]; p.In != "path" || p.Schema.Ref != "#/components/schemas/Region" {
+			t.Errorf("%s: expected region to reference the Region enum, got %+v", httpMethod, p.Schema)
+		}
+	}
+
+	// Path fields are not repeated as query parameters
+	if got := len(spec.Paths["/regions/{region}/orders/{order_id}"]["get"].Parameters); got != 3 {
+		t.Errorf("expected 2 path parameters and 1 query parameter, got %d", got)
+	}
+}
+
+func TestOpenAPIGenerator_ExamplesAndCodeSamples(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{Name: "User", Fields: []*ast.Field{
+				{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+				{Name: "name", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+			}},
+			{Name: "GetUserRequest", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}, Required: true}}},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "UserService",
+				Methods: []*ast.Method{
+					{
+						Name: "CreateUser", InputType: "User", OutputType: "User", HTTPMethod: "POST", PathTemplate: "/users",
+						SuccessCodes: []string{"201"}, ErrorCodes: []string{"409"},
+						Examples: []*ast.Example{
+							{Name: "ada", Summary: "Creates Ada", Request: []byte(`{"name":"Ada"}`), Response: []byte(`{"id":"u1","name":"Ada"}`)},
+						},
+						CodeSamples: []*ast.CodeSample{
+							{Lang: "curl", Source: "curl -X POST https://api.example.com/users"},
+							{Lang: "Go", Label: "Go SDK", Source: "client.CreateUser(ctx, user)"},
+						},
+					},
+					{
+						Name: "GetUser", InputType: "GetUserRequest", OutputType: "User", HTTPMethod: "GET", PathTemplate: "/users/{id}",
+						Examples: []*ast.Example{{Name: "default", Request: []byte(`{"id":"u1"}`)}},
+					},
+				},
+			},
+		},
+	}
+
+	output := NewOpenAPIGenerator().Generate(schema)
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(output), &spec); err != nil {
+		t.Fatalf("failed to parse generated spec: %v", err)
+	}
+
+	create := spec.Paths["/users"]["post"]
+	if example := create.RequestBody.Content["application/json"].Examples["ada"]; example.Summary != "Creates Ada" || example.Value.(map[string]interface{})["name"] != "Ada" {
+		t.Errorf("expected the request example on the body, got %+v", example)
+	}
+	for _, code := range []string{"200", "201"} {
+		if example, ok := create.Responses[code].Content["application/json"].Examples["ada"]; !ok || example.Value.(map[string]interface{})["id"] != "u1" {
+			t.Errorf("expected the response example on %s, got %+v", code, create.Responses[code])
+		}
+	}
+	if examples := create.Responses["409"].Content["application/json"].Examples; len(examples) != 0 {
+		t.Errorf("expected no examples on error responses, got %+v", examples)
+	}
+
+	get := spec.Paths["/users/{id}"]["get"]
+	if len(get.Parameters) != 1 || get.Parameters[0].Examples["default"].Value != "u1" {
+		t.Errorf("expected the request example on the id parameter, got %+v", get.Parameters)
+	}
+
+	for _, want := range []string{
+		"x-codeSamples:",
+		"- lang: curl\n                  source: curl -X POST https://api.example.com/users",
+		"- lang: Go\n                  label: Go SDK\n                  source: client.CreateUser(ctx, user)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestOpenAPIGenerator_LongRunning(t *testing.T) {
+	schema := &ast.Schema{
+		Types: []*ast.Type{
+			{Name: "ImportRequest", Fields: []*ast.Field{{Name: "source", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+			{Name: "ImportResult", Fields: []*ast.Field{{Name: "imported", Type: &ast.FieldType{Name: "int32", IsBuiltin: true}}}},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "BatchService",
+				Methods: []*ast.Method{
+					{Name: "ImportUsers", InputType: "ImportRequest", OutputType: "ImportResult", HTTPMethod: "POST", PathTemplate: "/v1/imports", LongRunning: &ast.LongRunning{}},
+				},
+			},
+		},
+	}
+	if err := schema.ResolveLongRunning(); err != nil {
+		t.Fatalf("ResolveLongRunning failed: %v", err)
+	}
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(schema)), &spec); err != nil {
+		t.Fatalf("failed to parse generated spec: %v", err)
+	}
+
+	responses := spec.Paths["/v1/imports"]["post"].Responses
+	if _, ok := responses["200"]; ok {
+		t.Error("expected a long-running method not to answer 200")
+	}
+	accepted, ok := responses["202"]
+	if !ok || accepted.Content["application/json"].Schema.Ref != "#/components/schemas/ImportUsersOperation" {
+		t.Fatalf("expected 202 with the operation, got %+v", responses)
+	}
+	link := accepted.Links["GetImportUsersOperation"]
+	if link.OperationID != "GetImportUsersOperation" || link.Parameters["name"] != "$response.body#/name" {
+		t.Errorf("expected a link to the polling operation, got %+v", link)
+	}
+
+	poll, ok := spec.Paths["/v1/imports/operations/{name}"]["get"]
+	if !ok || poll.OperationID != "GetImportUsersOperation" || poll.Responses["200"].Content["application/json"].Schema.Ref != "#/components/schemas/ImportUsersOperation" {
+		t.Errorf("expected a polling endpoint returning the operation, got %+v", poll)
+	}
+	if _, ok := spec.Components.Schemas["ImportUsersOperation"].Properties["done"]; !ok {
+		t.Error("expected the operation schema to have a done property")
+	}
+}
+
+func TestOpenAPIGenerator_BytesFormat(t *testing.T) {
+	field := &ast.Field{Name: "avatar", Type: &ast.FieldType{Name: "bytes", IsBuiltin: true}}
+	chunks := &ast.Field{Name: "chunks", Type: &ast.FieldType{Name: "bytes", IsBuiltin: true, IsArray: true}}
+
+	gen := NewOpenAPIGenerator()
+	if property := gen.convertFieldToProperty(field, nil); property.Type != "string" || property.Format != "byte" {
+		t.Errorf("Expected base64 strings by default, got %s/%s", property.Type, property.Format)
+	}
+
+	gen = NewOpenAPIGeneratorWithOptions(&OpenAPIOptions{BytesFormat: OpenAPIBytesBinary})
+	if property := gen.convertFieldToProperty(field, nil); property.Type != "string" || property.Format != "binary" {
+		t.Errorf("Expected binary strings, got %s/%s", property.Type, property.Format)
+	}
+	if property := gen.convertFieldToProperty(chunks, nil); property.Items == nil || property.Items.Format != "binary" {
+		t.Errorf("Expected binary list items, got %+v", property.Items)
+	}
+}
+
+func TestOpenAPIGenerator_DocFiles(t *testing.T) {
+	field := &ast.Field{
+		Name: "email",
+		Type: &ast.FieldType{Name: "string", IsBuiltin: true},
+		Doc:  &ast.Documentation{General: "Email address", File: "docs/email.md", Extended: "Must be **verified**.\n"},
+	}
+	typ := &ast.Type{
+		Name:   "User",
+		Doc:    &ast.Documentation{Extended: "# User\n\nLong-form notes."},
+		Fields: []*ast.Field{field},
+	}
+
+	gen := NewOpenAPIGenerator()
+	if property := gen.convertFieldToProperty(field, nil); property.Description != "Email address\n\nMust be **verified**." {
+		t.Errorf("Expected the doc file after the doc comment, got %q", property.Description)
+	}
+	if schema := gen.generateSchema(typ, map[string]string{}); schema.Description != "# User\n\nLong-form notes." {
+		t.Errorf("Expected the doc file as the description, got %q", schema.Description)
+	}
+}
+
+func TestOpenAPIGenerator_Ownership(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace:            "payments",
+		NamespaceAnnotations: &ast.FormatAnnotations{Ownership: &ast.Ownership{Owner: "team-payments", Tier: "2"}},
+		Types: []*ast.Type{
+			{Name: "Invoice", Namespace: "payments", Annotations: &ast.FormatAnnotations{
+				Ownership: &ast.Ownership{SLA: "99.9%"},
+				OpenAPI:   []string{`{"x-owner": "team-billing"}`},
+			}},
+		},
+		Services: []*ast.Service{
+			{Name: "Refunds", Namespace: "payments", Annotations: &ast.FormatAnnotations{Ownership: &ast.Ownership{Tier: "1"}}, Methods: []*ast.Method{
+				{Name: "Refund", InputType: "Invoice", OutputType: "Invoice", HTTPMethod: "POST", PathTemplate: "/refunds"},
+			}},
+		},
+	}
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(schema)), &spec); err != nil {
+		t.Fatalf("Failed to parse YAML: %v", err)
+	}
+
+	tests := []struct {
+		element    string
+		extensions map[string]interface{}
+		want       map[string]interface{}
+	}{
+		{"info", spec.Info.Extensions, map[string]interface{}{"x-owner": "team-payments", "x-tier": "2"}},
+		{"Invoice", spec.Components.Schemas["Invoice"].Extensions, map[string]interface{}{"x-owner": "team-billing", "x-sla": "99.9%", "x-tier": "2"}},
+		{"Refund", spec.Paths["/refunds"]["post"].Extensions, map[string]interface{}{"x-owner": "team-payments", "x-tier": "1"}},
+	}
+	for _, tt := range tests {
+		for name, want := range tt.want {
+			if got := tt.extensions[name]; got != want {
+				t.Errorf("%s: expected %s: %v, got %v", tt.element, name, want, got)
+			}
+		}
+	}
+}
+
+func TestOpenAPIGenerator_ProtoJSON(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{
+			{Name: "Status", Values: []*ast.EnumValue{{Name: "ACTIVE"}, {Name: "CLOSED"}}},
+			{Name: "Level", Values: []*ast.EnumValue{{Name: "NONE", Number: 0, HasNumber: true}, {Name: "HIGH"}}},
+		},
+		Types: []*ast.Type{
+			{Name: "Account", Fields: []*ast.Field{
+				{Name: "account_id", Type: &ast.FieldType{Name: "int64", IsBuiltin: true}, Required: true},
+				{Name: "display_name", Type: &ast.FieldType{Name: "string", IsBuiltin: true}, JSONName: "name"},
+				{Name: "created_at", Type: &ast.FieldType{Name: "timestamp", IsBuiltin: true}},
+				{Name: "owner", Type: &ast.FieldType{Name: "string", IsBuiltin: true}, Annotations: &ast.FormatAnnotations{ProtoName: "owner_email"}},
+				{Name: "legacy", Type: &ast.FieldType{Name: "string", IsBuiltin: true}, Annotations: &ast.FormatAnnotations{Proto: []string{`json_name = "legacyCode"`}}},
+				{Name: "status", Type: &ast.FieldType{Name: "Status"}},
+			}},
+		},
+	}
+
+	var spec OpenAPISpec
+	output := NewOpenAPIGeneratorWithOptions(&OpenAPIOptions{ProtoJSON: true}).Generate(schema)
+	if err := yaml.Unmarshal([]byte(output), &spec); err != nil {
+		t.Fatalf("Failed to parse YAML: %v", err)
+	}
+
+	account := spec.Components.Schemas["Account"]
+	tests := []struct {
+		name   string
+		typ    string
+		format string
+	}{
+		{"accountId", "string", "int64"},
+		{"displayName", "string", ""},
+		{"createdAt", "string", "date-time"},
+		{"ownerEmail", "string", ""},
+		{"legacyCode", "string", ""},
+	}
+	for _, tt := range tests {
+		property, ok := account.Properties[tt.name]
+		if !ok {
+			t.Errorf("Expected property %s, got %v", tt.name, account.Properties)
+			continue
+		}
+		if property.Type != tt.typ || property.Format != tt.format {
+			t.Errorf("%s: expected %s/%s, got %s/%s", tt.name, tt.typ, tt.format, property.Type, property.Format)
+		}
+	}
+	if len(account.Required) != 1 || account.Required[0] != "accountId" {
+		t.Errorf("Expected accountId to be required, got %v", account.Required)
+	}
+
+	if got := strings.Join(spec.Components.Schemas["Status"].Enum, ","); got != "STATUS_UNSPECIFIED,ACTIVE,CLOSED" {
+		t.Errorf("Expected the UNSPECIFIED value first, got %v", got)
+	}
+	if got := strings.Join(spec.Components.Schemas["Level"].Enum, ","); got != "NONE,HIGH" {
+		t.Errorf("Expected no UNSPECIFIED value for an enum with a zero value, got %v", got)
+	}
+
+	// Without the mode, the names and the int64 encoding are unchanged
+	spec = OpenAPISpec{}
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(schema)), &spec); err != nil {
+		t.Fatalf("Failed to parse YAML: %v", err)
+	}
+	if property, ok := spec.Components.Schemas["Account"].Properties["account_id"]; !ok || property.Type != "integer" {
+		t.Errorf("Expected an account_id integer, got %v", spec.Components.Schemas["Account"].Properties)
+	}
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// FieldPresence describes how the presence of a field is expressed in each output format.
+type FieldPresence struct {
+	Type     string `json:"type"`     // Qualified type name
+	Field    string `json:"field"`    // Field name
+	Presence string `json:"presence"` // required!, optional?, or implicit
+	Proto    string `json:"proto"`
+	GraphQL  string `json:"graphql"`
+	OpenAPI  string `json:"openapi"`
+	Conflict bool   `json:"conflict,omitempty"` // @required on an optional (?) field
+}
+
+// PresenceReport lists the presence semantics of every field, in schema order, as the
+// Protobuf, GraphQL, and OpenAPI generators emit them.
+func PresenceReport(schema *ast.Schema) []FieldPresence {
+	enums := make(map[string]bool)
+	for _, enum := range schema.Enums {
+		enums[enum.Name] = true
+		enums[enum.Namespace+"."+enum.Name] = true
+	}
+
+	var report []FieldPresence
+	for _, typ := range schema.Types {
+		typeName := typ.Name
+		if typ.Namespace != "" {
+			typeName = typ.Namespace + "." + typ.Name
+		}
+		for _, field := range typ.Fields {
+			presence := field.Presence()
+			report = append(report, FieldPresence{
+				Type:     typeName,
+				Field:    field.Name,
+				Presence: presence.String(),
+				Proto:    protoPresence(field, presence, enums),
+				GraphQL:  graphQLPresence(field, presence),
+				OpenAPI:  openAPIPresence(field, presence),
+				Conflict: field.Required && presence == ast.PresenceOptional,
+			})
+		}
+	}
+	return report
+}
+
+// protoPresence describes a field as emitted by ProtobufGenerator
+func protoPresence(field *ast.Field, presence ast.Presence, enums map[string]bool) string {
+	switch {
+	case !field.ShouldIncludeInGenerator("proto"):
+		return "excluded"
+	case len(field.Arguments) > 0:
+		return "rpc (field has arguments)"
+	case field.Type.IsArray:
+		return "repeated (no presence)"
+	case field.Type.IsMap:
+		return "map (no presence)"
+	case presence == ast.PresenceOptional:
+		return "optional (explicit presence)"
+	case !ast.IsBuiltinType(field.Type.Name) && !enums[field.Type.Name]:
+		return "message (explicit presence)"
+	case presence == ast.PresenceRequired:
+		return "implicit (required is not enforced)"
+	default:
+		return "implicit (zero value when unset)"
+	}
+}
+
+// graphQLPresence describes a field as emitted by GraphQLGenerator
+func graphQLPresence(field *ast.Field, presence ast.Presence) string {
+	switch {
+	case !field.ShouldIncludeInGenerator("graphql"):
+		return "excluded"
+	case presence == ast.PresenceRequired:
+		return "non-null (!)"
+	default:
+		return "nullable"
+	}
+}
+
+// openAPIPresence describes a field as emitted by OpenAPIGenerator
+func openAPIPresence(field *ast.Field, presence ast.Presence) string {
+	if !field.ShouldIncludeInGenerator("openapi") {
+		return "excluded"
+	}
+	text := "optional"
+	if presence == ast.PresenceRequired {
+		text = "required"
+	}
+	if field.JSONNullable {
+		text += ", nullable"
+	}
+	return text
+}
+
+// FormatPresenceReport renders a presence report as an aligned text table.
+// Fields marked @required with an optional (?) type are flagged with a trailing note.
+func FormatPresenceReport(report []FieldPresence) string {
+	rows := [][]string{{"FIELD", "PRESENCE", "PROTO", "GRAPHQL", "OPENAPI"}}
+	for _, entry := range report {
+		rows = append(rows, []string{entry.Type + "." + entry.Field, entry.Presence, entry.Proto, entry.GraphQL, entry.OpenAPI})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	var sb strings.Builder
+	for r, row := range rows {
+		for i, cell := range row {
+			if i == len(row)-1 {
+				sb.WriteString(cell)
+			} else {
+				sb.WriteString(fmt.Sprintf("%-*s  ", widths[i], cell))
+			}
+		}
+		if r > 0 && report[r-1].Conflict {
+			sb.WriteString("  (@required ignored: type is optional)")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
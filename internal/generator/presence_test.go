@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func TestPresenceReport(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{{Name: "Status", Namespace: "shop"}},
+		Types: []*ast.Type{
+			{
+				Name:      "User",
+				Namespace: "shop",
+				Fields: []*ast.Field{
+					{Name: "id", Required: true, Type: &ast.FieldType{Name: "string"}},
+					{Name: "nick", Type: &ast.FieldType{Name: "string", Optional: true}},
+					{Name: "status", Type: &ast.FieldType{Name: "Status"}},
+					{Name: "address", Type: &ast.FieldType{Name: "Address"}},
+					{Name: "tags", Type: &ast.FieldType{Name: "string", IsArray: true}},
+					{Name: "email", Required: true, Type: &ast.FieldType{Name: "string", Optional: true}},
+					{Name: "secret", JSONNullable: true, ExcludeFrom: []string{"graphql"}, Type: &ast.FieldType{Name: "string"}},
+				},
+			},
+		},
+	}
+
+	expected := []FieldPresence{
+		{"shop.User", "id", "required!", "implicit (required is not enforced)", "non-null (!)", "required", false},
+		{"shop.User", "nick", "optional?", "optional (explicit presence)", "nullable", "optional", false},
+		{"shop.User", "status", "implicit", "implicit (zero value when unset)", "nullable", "optional", false},
+		{"shop.User", "address", "implicit", "message (explicit presence)", "nullable", "optional", false},
+		{"shop.User", "tags", "implicit", "repeated (no presence)", "nullable", "optional", false},
+		{"shop.User", "email", "optional?", "optional (explicit presence)", "nullable", "optional", true},
+		{"shop.User", "secret", "implicit", "implicit (zero value when unset)", "excluded", "optional, nullable", false},
+	}
+
+	report := PresenceReport(schema)
+	if len(report) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(report))
+	}
+	for i, want := range expected {
+		if report[i] != want {
+			t.Errorf("Entry %d:\n got: %+v\nwant: %+v", i, report[i], want)
+		}
+	}
+
+	text := FormatPresenceReport(report)
+	if !strings.HasPrefix(text, "FIELD") || !strings.Contains(text, "shop.User.email") || !strings.Contains(text, "(@required ignored: type is optional)") {
+		t.Errorf("Unexpected text report:\n%s", text)
+	}
+}
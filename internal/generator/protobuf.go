@@ -2,19 +2,111 @@ package generator
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/rasmartins/typemux/internal/ast"
 )
 
+// ProtobufOptions configures the Protobuf generator.
+type ProtobufOptions struct {
+	// UseWrapperTypes maps optional scalars (e.g., int32?, string?) to
+	// google.protobuf wrapper messages (Int32Value, StringValue, ...) instead
+	// of the proto3 optional keyword.
+	UseWrapperTypes bool
+
+	// Metadata is stamped into the file header comment when set.
+	Metadata *Metadata
+
+	// Layout is how the declarations of a schema are laid out in files:
+	// ProtobufLayoutNamespace by default.
+	Layout ProtobufLayout
+}
+
 // ProtobufGenerator generates Protocol Buffers (proto3) schemas from TypeMUX schemas.
-type ProtobufGenerator struct{}
+type ProtobufGenerator struct {
+	opts ProtobufOptions
+
+	// flagEnums holds the flags enums of the schema, by name and qualified name,
+	// whose fields are int32 bitmasks rather than enum values
+	flagEnums map[string]bool
+
+	// nestedWrappers holds the wrapper messages of the lists and maps nested in
+	// the fields of the message being generated, by name
+	nestedWrappers map[string]string
+}
 
 // NewProtobufGenerator creates a new Protobuf schema generator.
 func NewProtobufGenerator() *ProtobufGenerator {
 	return &ProtobufGenerator{}
 }
 
+// NewProtobufGeneratorWithOptions creates a new Protobuf schema generator with the given options.
+func NewProtobufGeneratorWithOptions(opts *ProtobufOptions) *ProtobufGenerator {
+	g := &ProtobufGenerator{}
+	if opts != nil {
+		g.opts = *opts
+	}
+	return g
+}
+
+// protoWrapperTypes maps TypeMUX scalars to their google.protobuf wrapper messages
+var protoWrapperTypes = map[string]string{
+	"string":  "google.protobuf.StringValue",
+	"int32":   "google.protobuf.Int32Value",
+	"int64":   "google.protobuf.Int64Value",
+	"uint8":   "google.protobuf.UInt32Value",
+	"uint16":  "google.protobuf.UInt32Value",
+	"uint32":  "google.protobuf.UInt32Value",
+	"uint64":  "google.protobuf.UInt64Value",
+	"float32": "google.protobuf.FloatValue",
+	"float64": "google.protobuf.DoubleValue",
+	"bool":    "google.protobuf.BoolValue",
+	"bytes":   "google.protobuf.BytesValue",
+}
+
+// wrapperTypeFor returns the wrapper message for an optional scalar, or "" when
+// wrappers are disabled or the type has no wrapper (messages, enums, timestamps)
+func (g *ProtobufGenerator) wrapperTypeFor(fieldType *ast.FieldType, optional bool) string {
+	if !g.opts.UseWrapperTypes || !optional || fieldType.IsArray || fieldType.IsMap {
+		return ""
+	}
+	return protoWrapperTypes[fieldType.Name]
+}
+
+// usesWrapperTypes reports whether any field in the schema is emitted as a wrapper type
+func (g *ProtobufGenerator) usesWrapperTypes(schema *ast.Schema) bool {
+	if !g.opts.UseWrapperTypes {
+		return false
+	}
+	for _, typ := range schema.Types {
+		for _, field := range typ.Fields {
+			if len(field.Arguments) == 0 && g.wrapperTypeFor(field.Type, field.Type.Optional) != "" {
+				return true
+			}
+			for _, arg := range field.Arguments {
+				if g.wrapperTypeFor(arg.Type, !arg.Required) != "" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// usesEmptyType reports whether a service method takes or returns nothing, which
+// maps to google.protobuf.Empty
+func (g *ProtobufGenerator) usesEmptyType(schema *ast.Schema) bool {
+	for _, service := range schema.Services {
+		for _, method := range service.Methods {
+			if !method.HasInput() || !method.HasOutput() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Note on nested maps:
 // Protobuf supports maps natively using the map<K,V> syntax.
 // For nested maps (e.g., map<string, map<string, string>>), users should
@@ -42,6 +134,7 @@ func NewProtobufGenerator() *ProtobufGenerator {
 // Returns a map of namespace -> proto file content
 func (g *ProtobufGenerator) GenerateByNamespace(schema *ast.Schema) map[string]string {
 	result := make(map[string]string)
+	g.collectFlagEnums(schema)
 
 	// Helper function to create namespace schema with annotations
 	createNamespaceSchema := func(ns string) *ast.Schema {
@@ -52,10 +145,8 @@ func (g *ProtobufGenerator) GenerateByNamespace(schema *ast.Schema) map[string]s
 			Unions:    []*ast.Union{},
 			Services:  []*ast.Service{},
 		}
-		// Copy namespace annotations if this is the main namespace
-		if ns == schema.Namespace && schema.NamespaceAnnotations != nil {
-			nsSchema.NamespaceAnnotations = schema.NamespaceAnnotations
-		}
+		// Copy namespace annotations (main or imported namespace)
+		nsSchema.NamespaceAnnotations = schema.GetNamespaceAnnotations(ns)
 		return nsSchema
 	}
 
@@ -73,7 +164,7 @@ func (g *ProtobufGenerator) GenerateByNamespace(schema *ast.Schema) map[string]s
 		namespaceData[ns].Enums = append(namespaceData[ns].Enums, enum)
 	}
 
-	for _, typ := range schema.Types {
+	for _, typ := range withoutWellKnownTypes(schema.Types) {
 		ns := typ.Namespace
 		if ns == "" {
 			ns = "api"
@@ -106,44 +197,54 @@ func (g *ProtobufGenerator) GenerateByNamespace(schema *ast.Schema) map[string]s
 		namespaceData[ns].Services = append(namespaceData[ns].Services, service)
 	}
 
-	// Generate a proto file for each namespace
+	// Generate a proto file for each namespace, importing the files of the namespaces it uses
 	for ns, nsSchema := range namespaceData {
-		result[ns] = g.generateForNamespace(nsSchema)
+		var imports []string
+		for _, reqNs := range g.findRequiredNamespaces(nsSchema) {
+			if reqNs != ns {
+				// Convert namespace to file path (e.g., com.example.users -> com/example/users.proto)
+				imports = append(imports, strings.ReplaceAll(reqNs, ".", "/")+".proto")
+			}
+		}
+		sort.Strings(imports)
+		result[ns] = g.generateForNamespace(nsSchema, imports)
 	}
 
 	return result
 }
 
-// generateForNamespace generates a single proto file for a specific namespace
-func (g *ProtobufGenerator) generateForNamespace(nsSchema *ast.Schema) string {
+// collectFlagEnums records the flags enums of a schema in flagEnums
+func (g *ProtobufGenerator) collectFlagEnums(schema *ast.Schema) {
+	g.flagEnums = flagEnumNames(schema)
+}
+
+// generateForNamespace generates a single proto file for a specific namespace,
+// importing the proto files of the declarations of other files it uses
+func (g *ProtobufGenerator) generateForNamespace(nsSchema *ast.Schema, imports []string) string {
 	var sb strings.Builder
 
 	sb.WriteString("// Generated Protobuf Schema\n")
+	for _, line := range g.opts.Metadata.lines() {
+		sb.WriteString("// " + line + "\n")
+	}
 	sb.WriteString("syntax = \"proto3\";\n\n")
 	sb.WriteString(fmt.Sprintf("package %s;\n\n", nsSchema.Namespace))
 
 	// Add namespace-level protobuf options
-	if nsSchema.NamespaceAnnotations != nil && len(nsSchema.NamespaceAnnotations.Proto) > 0 {
-		for _, option := range nsSchema.NamespaceAnnotations.Proto {
-			// Options should be in format: go_package="value" or option_name="value"
-			sb.WriteString(fmt.Sprintf("option %s;\n", option))
-		}
-		sb.WriteString("\n")
-	}
+	sb.WriteString(g.generateFileOptions(nsSchema.NamespaceAnnotations))
 
-	// Collect required imports from other namespaces
-	requiredNamespaces := g.findRequiredNamespaces(nsSchema)
-
-	// Add imports for other namespace proto files
-	for _, reqNs := range requiredNamespaces {
-		if reqNs != nsSchema.Namespace {
-			// Convert namespace to file path (e.g., com.example.users -> com/example/users.proto)
-			protoPath := strings.ReplaceAll(reqNs, ".", "/") + ".proto"
-			sb.WriteString(fmt.Sprintf("import \"%s\";\n", protoPath))
-		}
+	for _, protoPath := range append(imports, wellKnownImports(nsSchema)...) {
+		sb.WriteString(fmt.Sprintf("import \"%s\";\n", protoPath))
 	}
 
-	sb.WriteString("import \"google/protobuf/timestamp.proto\";\n\n")
+	sb.WriteString("import \"google/protobuf/timestamp.proto\";\n")
+	if g.usesWrapperTypes(nsSchema) {
+		sb.WriteString("import \"google/protobuf/wrappers.proto\";\n")
+	}
+	if g.usesEmptyType(nsSchema) {
+		sb.WriteString("import \"google/protobuf/empty.proto\";\n")
+	}
+	sb.WriteString("\n")
 
 	// Generate enums
 	for _, enum := range nsSchema.Enums {
@@ -152,7 +253,7 @@ func (g *ProtobufGenerator) generateForNamespace(nsSchema *ast.Schema) string {
 	}
 
 	// Generate message types
-	for _, typ := range nsSchema.Types {
+	for _, typ := range withoutWellKnownTypes(nsSchema.Types) {
 		sb.WriteString(g.generateMessageWithNamespace(typ, nsSchema.Namespace))
 		sb.WriteString("\n\n")
 	}
@@ -186,6 +287,62 @@ func (g *ProtobufGenerator) generateForNamespace(nsSchema *ast.Schema) string {
 	return sb.String()
 }
 
+// generateFileOptions generates file-level option statements from namespace annotations.
+// When the same option is declared more than once (e.g., inline and in YAML), the last
+// declaration wins, since protoc rejects duplicate options.
+func (g *ProtobufGenerator) generateFileOptions(annotations *ast.FormatAnnotations) string {
+	if annotations == nil || len(annotations.Proto) == 0 {
+		return ""
+	}
+
+	lastIndex := make(map[string]int)
+	for i, option := range annotations.Proto {
+		lastIndex[g.optionName(option)] = i
+	}
+
+	var sb strings.Builder
+	for i, option := range annotations.Proto {
+		if lastIndex[g.optionName(option)] != i {
+			continue
+		}
+		// Options should be in format: go_package="value" or option_name="value"
+		sb.WriteString(fmt.Sprintf("option %s;\n", option))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// generateOptionStatements generates indented option statements for a message, enum, or service
+func (g *ProtobufGenerator) generateOptionStatements(annotations *ast.FormatAnnotations) string {
+	if annotations == nil || len(annotations.Proto) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, option := range annotations.Proto {
+		option = trimOption(option)
+		if option == "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  option %s;\n", option))
+	}
+	return sb.String()
+}
+
+// trimOption accepts both bare options and the bracketed field-option form,
+// returning the option without brackets
+func trimOption(option string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(option), "["), "]"))
+}
+
+// optionName extracts the option name from a "name = value" declaration
+func (g *ProtobufGenerator) optionName(option string) string {
+	if idx := strings.Index(option, "="); idx >= 0 {
+		return strings.TrimSpace(option[:idx])
+	}
+	return strings.TrimSpace(option)
+}
+
 // findRequiredNamespaces finds all namespaces that are referenced by types in the given schema
 func (g *ProtobufGenerator) findRequiredNamespaces(nsSchema *ast.Schema) []string {
 	required := make(map[string]bool)
@@ -193,13 +350,18 @@ func (g *ProtobufGenerator) findRequiredNamespaces(nsSchema *ast.Schema) []strin
 	// Check all field types in messages
 	for _, typ := range nsSchema.Types {
 		for _, field := range typ.Fields {
-			if strings.Contains(field.Type.Name, ".") {
-				// This is a qualified name, extract the namespace
-				parts := strings.Split(field.Type.Name, ".")
-				if len(parts) > 1 {
-					// Namespace is everything except the last part
-					ns := strings.Join(parts[:len(parts)-1], ".")
-					required[ns] = true
+			for _, name := range fieldTypeNames(field.Type) {
+				if _, ok := protoWellKnownMessages[name]; ok {
+					continue // Imported from the proto file of the message
+				}
+				if strings.Contains(name, ".") {
+					// This is a qualified name, extract the namespace
+					parts := strings.Split(name, ".")
+					if len(parts) > 1 {
+						// Namespace is everything except the last part
+						ns := strings.Join(parts[:len(parts)-1], ".")
+						required[ns] = true
+					}
 				}
 			}
 		}
@@ -208,14 +370,14 @@ func (g *ProtobufGenerator) findRequiredNamespaces(nsSchema *ast.Schema) []strin
 	// Check service method types
 	for _, service := range nsSchema.Services {
 		for _, method := range service.Methods {
-			if strings.Contains(method.InputType, ".") {
+			if protoMethodType(method.InputType) == method.InputType && strings.Contains(method.InputType, ".") {
 				parts := strings.Split(method.InputType, ".")
 				if len(parts) > 1 {
 					ns := strings.Join(parts[:len(parts)-1], ".")
 					required[ns] = true
 				}
 			}
-			if strings.Contains(method.OutputType, ".") {
+			if protoMethodType(method.OutputType) == method.OutputType && strings.Contains(method.OutputType, ".") {
 				parts := strings.Split(method.OutputType, ".")
 				if len(parts) > 1 {
 					ns := strings.Join(parts[:len(parts)-1], ".")
@@ -236,8 +398,15 @@ func (g *ProtobufGenerator) findRequiredNamespaces(nsSchema *ast.Schema) []strin
 // Generate creates a Protocol Buffers (proto3) schema string from the given schema.
 func (g *ProtobufGenerator) Generate(schema *ast.Schema) string {
 	var sb strings.Builder
+	if g.opts.Layout == ProtobufLayoutSingle {
+		schema = singlePackageSchema(schema)
+	}
+	g.collectFlagEnums(schema)
 
 	sb.WriteString("// Generated Protobuf Schema\n")
+	for _, line := range g.opts.Metadata.lines() {
+		sb.WriteString("// " + line + "\n")
+	}
 	sb.WriteString("syntax = \"proto3\";\n\n")
 
 	// Use namespace from schema, default to "api" if empty
@@ -248,15 +417,19 @@ func (g *ProtobufGenerator) Generate(schema *ast.Schema) string {
 	sb.WriteString(fmt.Sprintf("package %s;\n\n", namespace))
 
 	// Add namespace-level protobuf options
-	if schema.NamespaceAnnotations != nil && len(schema.NamespaceAnnotations.Proto) > 0 {
-		for _, option := range schema.NamespaceAnnotations.Proto {
-			// Options should be in format: go_package="value" or option_name="value"
-			sb.WriteString(fmt.Sprintf("option %s;\n", option))
-		}
-		sb.WriteString("\n")
-	}
+	sb.WriteString(g.generateFileOptions(schema.NamespaceAnnotations))
 
-	sb.WriteString("import \"google/protobuf/timestamp.proto\";\n\n")
+	for _, protoPath := range wellKnownImports(schema) {
+		sb.WriteString(fmt.Sprintf("import \"%s\";\n", protoPath))
+	}
+	sb.WriteString("import \"google/protobuf/timestamp.proto\";\n")
+	if g.usesWrapperTypes(schema) {
+		sb.WriteString("import \"google/protobuf/wrappers.proto\";\n")
+	}
+	if g.usesEmptyType(schema) {
+		sb.WriteString("import \"google/protobuf/empty.proto\";\n")
+	}
+	sb.WriteString("\n")
 
 	// Build a map of original type names to their custom Protobuf names
 	typeNameMap := make(map[string]string)
@@ -273,7 +446,7 @@ func (g *ProtobufGenerator) Generate(schema *ast.Schema) string {
 	}
 
 	// Generate message types
-	for _, typ := range schema.Types {
+	for _, typ := range withoutWellKnownTypes(schema.Types) {
 		sb.WriteString(g.generateMessageWithMap(typ, typeNameMap))
 		sb.WriteString("\n\n")
 	}
@@ -299,6 +472,17 @@ func (g *ProtobufGenerator) Generate(schema *ast.Schema) string {
 	return sb.String()
 }
 
+// protoUnspecifiedValue returns the name of the zero value added to an enum
+// without a value numbered 0, such as STATUS_UNSPECIFIED, or "" when it has one
+func protoUnspecifiedValue(enum *ast.Enum) string {
+	for _, value := range enum.Values {
+		if value.HasNumber && value.Number == 0 {
+			return ""
+		}
+	}
+	return strings.ToUpper(enum.Name) + "_UNSPECIFIED"
+}
+
 func (g *ProtobufGenerator) generateEnum(enum *ast.Enum) string {
 	var sb strings.Builder
 
@@ -309,24 +493,19 @@ func (g *ProtobufGenerator) generateEnum(enum *ast.Enum) string {
 		}
 	}
 
-	sb.WriteString(fmt.Sprintf("enum %s {\n", enum.Name))
-
-	// Check if there's already a value with number 0
-	hasZeroValue := false
-	for _, value := range enum.Values {
-		if value.HasNumber && value.Number == 0 {
-			hasZeroValue = true
-			break
-		}
+	if enum.Flags {
+		sb.WriteString("// Bit flags: fields of this type are int32 bitmasks of these values.\n")
 	}
 
-	// Only add UNSPECIFIED if there's no value with number 0
-	if !hasZeroValue {
-		sb.WriteString(fmt.Sprintf("  %s_UNSPECIFIED = 0;\n", strings.ToUpper(enum.Name)))
+	sb.WriteString(fmt.Sprintf("enum %s {\n", enum.Name))
+	sb.WriteString(g.generateOptionStatements(enum.Annotations))
+
+	if unspecified := protoUnspecifiedValue(enum); unspecified != "" {
+		sb.WriteString(fmt.Sprintf("  %s = 0;\n", unspecified))
 	}
 
-	nextAutoNumber := 1
-	for _, value := range enum.Values {
+	numbers := enum.ValueNumbers()
+	for i, value := range enum.Values {
 		// Add enum value documentation
 		if doc := value.Doc.GetDoc("proto"); doc != "" {
 			for _, line := range strings.Split(doc, "\n") {
@@ -334,18 +513,7 @@ func (g *ProtobufGenerator) generateEnum(enum *ast.Enum) string {
 			}
 		}
 
-		var number int
-		if value.HasNumber {
-			number = value.Number
-			// Update nextAutoNumber to be after this custom number
-			if value.Number >= nextAutoNumber {
-				nextAutoNumber = value.Number + 1
-			}
-		} else {
-			number = nextAutoNumber
-			nextAutoNumber++
-		}
-		sb.WriteString(fmt.Sprintf("  %s = %d;\n", value.Name, number))
+		sb.WriteString(fmt.Sprintf("  %s = %d;\n", value.Name, numbers[i]))
 	}
 	sb.WriteString("}")
 	return sb.String()
@@ -365,6 +533,7 @@ func (g *ProtobufGenerator) generateMessageWithNamespace(typ *ast.Type, currentN
 
 func (g *ProtobufGenerator) generateMessageWithNamespaceAndMap(typ *ast.Type, currentNamespace string, typeNameMap map[string]string) string {
 	var sb strings.Builder
+	g.nestedWrappers = nil
 
 	// Add type documentation
 	if doc := typ.Doc.GetDoc("proto"); doc != "" {
@@ -380,6 +549,7 @@ func (g *ProtobufGenerator) generateMessageWithNamespaceAndMap(typ *ast.Type, cu
 	}
 
 	sb.WriteString(fmt.Sprintf("message %s {\n", messageName))
+	sb.WriteString(g.generateOptionStatements(typ.Annotations))
 	nextAutoNumber := 1
 	for _, field := range typ.Fields {
 		// Skip excluded fields
@@ -435,6 +605,7 @@ func (g *ProtobufGenerator) generateMessageWithNamespaceAndMap(typ *ast.Type, cu
 		fieldStr := g.generateMessageFieldWithNamespaceAndMap(field, fieldNum, currentNamespace, typeNameMap)
 		sb.WriteString(fmt.Sprintf("  %s\n", fieldStr))
 	}
+	sb.WriteString(g.nestedWrapperMessages())
 	sb.WriteString("}")
 	return sb.String()
 }
@@ -450,6 +621,7 @@ func (g *ProtobufGenerator) generateUnion(union *ast.Union) string {
 	}
 
 	sb.WriteString(fmt.Sprintf("message %s {\n", union.Name))
+	sb.WriteString(g.generateOptionStatements(union.Annotations))
 	sb.WriteString("  oneof value {\n")
 
 	// Generate oneof options
@@ -476,6 +648,16 @@ func (g *ProtobufGenerator) generateMessageFieldWithNamespace(field *ast.Field,
 }
 
 func (g *ProtobufGenerator) generateMessageFieldWithNamespaceAndMap(field *ast.Field, fieldNum int, currentNamespace string, typeNameMap map[string]string) string {
+	declaration := g.fieldDeclaration(field, fieldNum, currentNamespace, typeNameMap)
+	// Protobuf has no standard option for sensitive data, so it is kept as a comment
+	if field.Sensitivity != "" {
+		declaration += " // sensitive: " + field.Sensitivity
+	}
+	return declaration
+}
+
+// fieldDeclaration generates the declaration of a message field, with its options
+func (g *ProtobufGenerator) fieldDeclaration(field *ast.Field, fieldNum int, currentNamespace string, typeNameMap map[string]string) string {
 	var protoType string
 	if currentNamespace != "" {
 		protoType = g.mapTypeToProtobufWithNamespaceAndMap(field.Type, currentNamespace, typeNameMap)
@@ -498,8 +680,12 @@ func (g *ProtobufGenerator) generateMessageFieldWithNamespaceAndMap(field *ast.F
 	}
 
 	// Add format-specific annotations
-	if field.Annotations != nil && len(field.Annotations.Proto) > 0 {
-		optionParts = append(optionParts, field.Annotations.Proto...)
+	if field.Annotations != nil {
+		for _, option := range field.Annotations.Proto {
+			if option = trimOption(option); option != "" {
+				optionParts = append(optionParts, option)
+			}
+		}
 	}
 
 	var options string
@@ -507,67 +693,27 @@ func (g *ProtobufGenerator) generateMessageFieldWithNamespaceAndMap(field *ast.F
 		options = " [" + strings.Join(optionParts, ", ") + "]"
 	}
 
+	name := field.NameFor("proto")
 	if field.Type.IsMap {
-		var keyType, valueType string
-		keyType = g.mapScalarTypeWithPackageAndMap(field.Type.MapKey, currentNamespace, typeNameMap)
-
-		// Handle the value type - it can be a nested map or simple type
-		valueFieldType := field.Type.GetMapValueType()
-		if valueFieldType.IsMap {
-			// Recursively handle nested map
-			valueType = g.generateMapTypeString(valueFieldType, currentNamespace, typeNameMap)
-		} else if currentNamespace != "" {
-			valueType = g.mapScalarTypeWithPackageAndMap(valueFieldType.Name, currentNamespace, typeNameMap)
-		} else {
-			valueType = g.mapScalarTypeWithMap(valueFieldType.Name, typeNameMap)
-		}
-
-		return fmt.Sprintf("map<%s, %s> %s = %d%s;",
-			keyType,
-			valueType,
-			field.Name,
-			fieldNum,
-			options)
+		return fmt.Sprintf("%s %s = %d%s;", g.protoMapType(field.Type, currentNamespace, typeNameMap), name, fieldNum, options)
 	}
 
 	if field.Type.IsArray {
-		return fmt.Sprintf("repeated %s %s = %d%s;", protoType, field.Name, fieldNum, options)
+		return fmt.Sprintf("repeated %s %s = %d%s;", g.protoRepeatedType(field.Type, currentNamespace, typeNameMap), name, fieldNum, options)
+	}
+
+	// Optional scalars can use wrapper messages instead of the optional keyword
+	if wrapperType := g.wrapperTypeFor(field.Type, field.Type.Optional); wrapperType != "" {
+		return fmt.Sprintf("%s %s = %d%s;", wrapperType, name, fieldNum, options)
 	}
 
 	// Handle optional fields (proto3 optional keyword)
 	if field.Type.Optional {
-		return fmt.Sprintf("optional %s %s = %d%s;", protoType, field.Name, fieldNum, options)
+		return fmt.Sprintf("optional %s %s = %d%s;", protoType, name, fieldNum, options)
 	}
 
 	// Proto3 doesn't have required keyword, all fields are optional by default
-	return fmt.Sprintf("%s %s = %d%s;", protoType, field.Name, fieldNum, options)
-}
-
-// generateMapTypeString recursively generates the protobuf type string for a map (including nested maps)
-func (g *ProtobufGenerator) generateMapTypeString(fieldType *ast.FieldType, currentNamespace string, typeNameMap map[string]string) string {
-	if !fieldType.IsMap {
-		// Base case: not a map, just return the type name
-		if currentNamespace != "" {
-			return g.mapScalarTypeWithPackageAndMap(fieldType.Name, currentNamespace, typeNameMap)
-		}
-		return g.mapScalarTypeWithMap(fieldType.Name, typeNameMap)
-	}
-
-	// Recursive case: this is a map
-	keyType := g.mapScalarTypeWithPackageAndMap(fieldType.MapKey, currentNamespace, typeNameMap)
-
-	valueFieldType := fieldType.GetMapValueType()
-	var valueType string
-	if valueFieldType.IsMap {
-		// Recursively handle nested map
-		valueType = g.generateMapTypeString(valueFieldType, currentNamespace, typeNameMap)
-	} else if currentNamespace != "" {
-		valueType = g.mapScalarTypeWithPackageAndMap(valueFieldType.Name, currentNamespace, typeNameMap)
-	} else {
-		valueType = g.mapScalarTypeWithMap(valueFieldType.Name, typeNameMap)
-	}
-
-	return fmt.Sprintf("map<%s, %s>", keyType, valueType)
+	return fmt.Sprintf("%s %s = %d%s;", protoType, name, fieldNum, options)
 }
 
 func (g *ProtobufGenerator) mapScalarType(typeName string) string {
@@ -589,6 +735,12 @@ func (g *ProtobufGenerator) mapScalarType(typeName string) string {
 	if protoType, ok := typeMap[typeName]; ok {
 		return protoType
 	}
+	if message, ok := protoWellKnownMessages[typeName]; ok {
+		return message.Name
+	}
+	if g.flagEnums[typeName] {
+		return "int32"
+	}
 
 	// Custom type - use unqualified name for output
 	return ast.GetUnqualifiedName(typeName)
@@ -634,6 +786,12 @@ func (g *ProtobufGenerator) mapScalarTypeWithMap(typeName string, typeNameMap ma
 	if protoType, ok := typeMap[typeName]; ok {
 		return protoType
 	}
+	if message, ok := protoWellKnownMessages[typeName]; ok {
+		return message.Name
+	}
+	if g.flagEnums[typeName] {
+		return "int32"
+	}
 
 	// Get unqualified name for lookup
 	unqualifiedName := ast.GetUnqualifiedName(typeName)
@@ -666,6 +824,12 @@ func (g *ProtobufGenerator) mapScalarTypeWithPackageAndMap(typeName string, curr
 	if protoType, ok := typeMap[typeName]; ok {
 		return protoType
 	}
+	if message, ok := protoWellKnownMessages[typeName]; ok {
+		return message.Name
+	}
+	if g.flagEnums[typeName] {
+		return "int32"
+	}
 
 	// Check if this is a qualified type name (contains dots)
 	if strings.Contains(typeName, ".") {
@@ -709,6 +873,7 @@ func (g *ProtobufGenerator) generateService(service *ast.Service) string {
 	}
 
 	sb.WriteString(fmt.Sprintf("service %s {\n", service.Name))
+	sb.WriteString(g.generateOptionStatements(service.Annotations))
 	for _, method := range service.Methods {
 		// Add method documentation
 		if doc := method.Doc.GetDoc("proto"); doc != "" {
@@ -718,17 +883,41 @@ func (g *ProtobufGenerator) generateService(service *ast.Service) string {
 		}
 
 		// Build input type with optional stream prefix
-		inputType := method.InputType
+		inputType := protoMethodType(method.InputType)
+		if !method.HasInput() {
+			inputType = "google.protobuf.Empty"
+		}
 		if method.InputStream {
 			inputType = "stream " + inputType
 		}
 
 		// Build output type with optional stream prefix
-		outputType := method.OutputType
+		outputType := protoMethodType(method.OutputType)
+		if !method.HasOutput() {
+			outputType = "google.protobuf.Empty"
+		}
 		if method.OutputStream {
 			outputType = "stream " + outputType
 		}
 
+		// Protobuf has no options for timeouts, rate limits, and authentication, so describe them in comments
+		if method.Timeout != "" {
+			sb.WriteString(fmt.Sprintf("  // Timeout: %s\n", method.Timeout))
+		}
+		if method.RateLimit != nil {
+			sb.WriteString(fmt.Sprintf("  // Rate limit: %s\n", method.RateLimit))
+		}
+		if method.Auth != nil {
+			sb.WriteString(fmt.Sprintf("  // Auth: %s\n", method.Auth))
+		}
+
+		if method.Idempotent {
+			sb.WriteString(fmt.Sprintf("  rpc %s(%s) returns (%s) {\n    option idempotency_level = IDEMPOTENT;\n  }\n",
+				method.Name,
+				inputType,
+				outputType))
+			continue
+		}
 		sb.WriteString(fmt.Sprintf("  rpc %s(%s) returns (%s);\n",
 			method.Name,
 			inputType,
@@ -793,6 +982,8 @@ func (g *ProtobufGenerator) generateRequestMessage(typ *ast.Type, field *ast.Fie
 		// Handle arrays
 		if arg.Type.IsArray {
 			sb.WriteString(fmt.Sprintf("  repeated %s %s = %d;\n", protoType, arg.Name, fieldNum))
+		} else if wrapperType := g.wrapperTypeFor(arg.Type, !arg.Required); wrapperType != "" {
+			sb.WriteString(fmt.Sprintf("  %s %s = %d;\n", wrapperType, arg.Name, fieldNum))
 		} else {
 			// Handle optional arguments - in proto3, optional means the field can be explicitly unset
 			optional := ""
@@ -0,0 +1,277 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/naming"
+)
+
+// ProtobufLayout is how the Protobuf generator lays out the declarations of a
+// schema in files.
+type ProtobufLayout string
+
+const (
+	// ProtobufLayoutNamespace writes schema.proto for a schema with one
+	// namespace, and otherwise a file per namespace, such as
+	// com/example/users.proto, that imports the files of the namespaces it uses.
+	ProtobufLayoutNamespace ProtobufLayout = "namespace"
+	// ProtobufLayoutType writes a file per enum, message, union, and service in
+	// the directory of its namespace, such as com/example/users/user.proto,
+	// that imports the files of the declarations it uses.
+	ProtobufLayoutType ProtobufLayout = "type"
+	// ProtobufLayoutSingle writes every namespace to schema.proto, in the
+	// package of the schema. The declarations of other namespaces are prefixed
+	// with the last segments of their namespace that tell it apart: User of
+	// com.example.users becomes UsersUser.
+	ProtobufLayoutSingle ProtobufLayout = "single"
+)
+
+// protoNamespace returns the namespace of a declaration, with the default "api"
+// for declarations without one
+func protoNamespace(namespace string) string {
+	if namespace == "" {
+		return "api"
+	}
+	return namespace
+}
+
+// declarationRegistry returns a registry of the declarations of a schema
+func declarationRegistry(schema *ast.Schema) *ast.TypeRegistry {
+	registry := ast.NewTypeRegistry()
+	for _, typ := range schema.Types {
+		registry.RegisterType(typ)
+	}
+	for _, enum := range schema.Enums {
+		registry.RegisterEnum(enum)
+	}
+	for _, union := range schema.Unions {
+		registry.RegisterUnion(union)
+	}
+	return registry
+}
+
+// fieldTypeNames returns the names of the declarations a field type uses
+func fieldTypeNames(fieldType *ast.FieldType) []string {
+	if fieldType == nil {
+		return nil
+	}
+	if fieldType.IsArray {
+		return fieldTypeNames(fieldType.ElementType())
+	}
+	if fieldType.IsMap {
+		if fieldType.MapValueType != nil {
+			return fieldTypeNames(fieldType.MapValueType)
+		}
+		return []string{fieldType.MapValue}
+	}
+	return []string{fieldType.Name}
+}
+
+// GenerateByType generates a proto file per enum, message, union, and service.
+// Returns a map of file path -> proto file content.
+func (g *ProtobufGenerator) GenerateByType(schema *ast.Schema) map[string]string {
+	g.collectFlagEnums(schema)
+	registry := declarationRegistry(schema)
+
+	// path returns the file of a declaration: its snake_case name in the directory of its namespace
+	path := func(namespace, name string) string {
+		return strings.ReplaceAll(protoNamespace(namespace), ".", "/") + "/" + naming.SnakeCase.Apply(name) + ".proto"
+	}
+
+	result := make(map[string]string)
+	generate := func(namespace, name string, uses []string, file *ast.Schema) {
+		own := path(namespace, name)
+		seen := make(map[string]bool)
+		var imports []string
+		for _, used := range uses {
+			qualifiedName, ok := registry.ResolveType(used, namespace)
+			if !ok {
+				continue // Built-in types
+			}
+			if _, ok := protoWellKnownMessages[qualifiedName]; ok {
+				continue // Imported by generateForNamespace
+			}
+			i := strings.LastIndex(qualifiedName, ".")
+			if importPath := path(qualifiedName[:i], qualifiedName[i+1:]); importPath != own && !seen[importPath] {
+				seen[importPath] = true
+				imports = append(imports, importPath)
+			}
+		}
+		sort.Strings(imports)
+
+		file.Namespace = protoNamespace(namespace)
+		file.NamespaceAnnotations = schema.GetNamespaceAnnotations(namespace)
+		result[own] = g.generateForNamespace(file, imports)
+	}
+
+	for _, enum := range schema.Enums {
+		generate(enum.Namespace, enum.Name, nil, &ast.Schema{Enums: []*ast.Enum{enum}})
+	}
+	for _, typ := range withoutWellKnownTypes(schema.Types) {
+		var uses []string
+		for _, field := range typ.Fields {
+			uses = append(uses, fieldTypeNames(field.Type)...)
+			for _, arg := range field.Arguments {
+				uses = append(uses, fieldTypeNames(arg.Type)...)
+			}
+		}
+		generate(typ.Namespace, typ.Name, uses, &ast.Schema{Types: []*ast.Type{typ}})
+	}
+	for _, union := range schema.Unions {
+		generate(union.Namespace, union.Name, union.Options, &ast.Schema{Unions: []*ast.Union{union}})
+	}
+	for _, service := range schema.Services {
+		var uses []string
+		for _, method := range service.Methods {
+			uses = append(uses, method.InputType, method.OutputType)
+		}
+		generate(service.Namespace, service.Name, uses, &ast.Schema{Services: []*ast.Service{service}})
+	}
+	return result
+}
+
+// namespacePrefixes returns the prefix of the declarations of every namespace
+// but root in a single file: the PascalCase of the fewest last segments of the
+// namespace that no other namespace ends with
+func namespacePrefixes(namespaces []string, root string) map[string]string {
+	prefixes := make(map[string]string)
+	for _, namespace := range namespaces {
+		if namespace == root {
+			continue
+		}
+		segments := strings.Split(namespace, ".")
+		for k := 1; k <= len(segments); k++ {
+			suffix := "." + strings.Join(segments[len(segments)-k:], ".")
+			shared := false
+			for _, other := range namespaces {
+				if other != namespace && strings.HasSuffix("."+other, suffix) {
+					shared = true
+					break
+				}
+			}
+			if !shared || k == len(segments) {
+				prefixes[namespace] = naming.PascalCase.Apply(strings.Join(segments[len(segments)-k:], "_"))
+				break
+			}
+		}
+	}
+	return prefixes
+}
+
+// singlePackageSchema returns a copy of a schema with every declaration in the
+// namespace of the schema, where the declarations of other namespaces are
+// renamed with the prefix of their namespace and references follow them
+func singlePackageSchema(schema *ast.Schema) *ast.Schema {
+	root := protoNamespace(schema.Namespace)
+	prefixes := namespacePrefixes(collectNamespaces(schema), root)
+	if len(prefixes) == 0 {
+		return schema
+	}
+	registry := declarationRegistry(schema)
+
+	// rename returns the name of a declaration in the single file
+	rename := func(namespace, name string) string {
+		return prefixes[protoNamespace(namespace)] + name
+	}
+	// reference returns the name in the single file of a declaration used from a namespace
+	reference := func(name, namespace string) string {
+		qualifiedName, ok := registry.ResolveType(name, namespace)
+		if !ok {
+			return name
+		}
+		if _, ok := protoWellKnownMessages[qualifiedName]; ok {
+			return qualifiedName
+		}
+		i := strings.LastIndex(qualifiedName, ".")
+		return rename(qualifiedName[:i], qualifiedName[i+1:])
+	}
+	var referenceType func(fieldType *ast.FieldType, namespace string) *ast.FieldType
+	referenceType = func(fieldType *ast.FieldType, namespace string) *ast.FieldType {
+		if fieldType == nil {
+			return nil
+		}
+		copied := *fieldType
+		if copied.IsMap || copied.MapKey != "" {
+			// Maps, and arrays of maps
+			if copied.MapValue != "" {
+				copied.MapValue = reference(copied.MapValue, namespace)
+			}
+			copied.MapValueType = referenceType(copied.MapValueType, namespace)
+		} else {
+			// Nested arrays keep their [] prefix, as in []User
+			base := strings.TrimLeft(copied.Name, "[]")
+			copied.Name = copied.Name[:len(copied.Name)-len(base)] + reference(base, namespace)
+		}
+		return &copied
+	}
+	// renameAnnotations prefixes a @proto.name override like the declaration it names
+	renameAnnotations := func(annotations *ast.FormatAnnotations, namespace string) *ast.FormatAnnotations {
+		if annotations == nil || annotations.ProtoName == "" {
+			return annotations
+		}
+		copied := *annotations
+		copied.ProtoName = rename(namespace, copied.ProtoName)
+		return &copied
+	}
+
+	single := *schema
+	single.Namespace = root
+	single.Enums, single.Types, single.Unions, single.Services = nil, nil, nil, nil
+	for _, enum := range schema.Enums {
+		copied := *enum
+		copied.Name = rename(enum.Namespace, enum.Name)
+		copied.Namespace = root
+		copied.Annotations = renameAnnotations(enum.Annotations, enum.Namespace)
+		single.Enums = append(single.Enums, &copied)
+	}
+	for _, typ := range withoutWellKnownTypes(schema.Types) {
+		copied := *typ
+		copied.Name = rename(typ.Namespace, typ.Name)
+		copied.Namespace = root
+		copied.Annotations = renameAnnotations(typ.Annotations, typ.Namespace)
+		copied.Fields = nil
+		for _, field := range typ.Fields {
+			copiedField := *field
+			copiedField.Type = referenceType(field.Type, typ.Namespace)
+			copiedField.Arguments = nil
+			for _, arg := range field.Arguments {
+				copiedArg := *arg
+				copiedArg.Type = referenceType(arg.Type, typ.Namespace)
+				copiedField.Arguments = append(copiedField.Arguments, &copiedArg)
+			}
+			copied.Fields = append(copied.Fields, &copiedField)
+		}
+		single.Types = append(single.Types, &copied)
+	}
+	for _, union := range schema.Unions {
+		copied := *union
+		copied.Name = rename(union.Namespace, union.Name)
+		copied.Namespace = root
+		copied.Annotations = renameAnnotations(union.Annotations, union.Namespace)
+		copied.Options = nil
+		for _, option := range union.Options {
+			copied.Options = append(copied.Options, reference(option, union.Namespace))
+		}
+		single.Unions = append(single.Unions, &copied)
+	}
+	for _, service := range schema.Services {
+		copied := *service
+		copied.Name = rename(service.Namespace, service.Name)
+		copied.Namespace = root
+		copied.Methods = nil
+		for _, method := range service.Methods {
+			copiedMethod := *method
+			if method.HasInput() {
+				copiedMethod.InputType = reference(method.InputType, service.Namespace)
+			}
+			if method.HasOutput() {
+				copiedMethod.OutputType = reference(method.OutputType, service.Namespace)
+			}
+			copied.Methods = append(copied.Methods, &copiedMethod)
+		}
+		single.Services = append(single.Services, &copied)
+	}
+	return &single
+}
@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// layoutTestSchema returns a schema where orders.Order uses users.User and orders.User
+func layoutTestSchema() *ast.Schema {
+	stringType := func() *ast.FieldType { return &ast.FieldType{Name: "string", IsBuiltin: true} }
+	return &ast.Schema{
+		Namespace: "com.example.orders",
+		Enums: []*ast.Enum{
+			{Name: "UserStatus", Namespace: "com.example.users", Values: []*ast.EnumValue{{Name: "ACTIVE", Number: 1, HasNumber: true}}},
+		},
+		Types: []*ast.Type{
+			{
+				Name:      "User",
+				Namespace: "com.example.users",
+				Fields: []*ast.Field{
+					{Name: "id", Number: 1, HasNumber: true, Type: stringType()},
+					{Name: "status", Number: 2, HasNumber: true, Type: &ast.FieldType{Name: "UserStatus"}},
+				},
+			},
+			{Name: "User", Namespace: "com.example.orders", Fields: []*ast.Field{{Name: "id", Number: 1, HasNumber: true, Type: stringType()}}},
+			{
+				Name:      "Order",
+				Namespace: "com.example.orders",
+				Fields: []*ast.Field{
+					{Name: "customer", Number: 1, HasNumber: true, Type: &ast.FieldType{Name: "com.example.users.User"}},
+					{Name: "processedBy", Number: 2, HasNumber: true, Type: &ast.FieldType{Name: "User"}},
+				},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name:      "UserService",
+				Namespace: "com.example.users",
+				Methods:   []*ast.Method{{Name: "GetUser", InputType: "User", OutputType: "User"}},
+			},
+		},
+	}
+}
+
+func TestProtobufGenerator_GenerateByType(t *testing.T) {
+	files := NewProtobufGenerator().GenerateByType(layoutTestSchema())
+
+	contents := make(map[string][]byte)
+	for path, content := range files {
+		contents[path] = []byte(content)
+	}
+	want := "com/example/orders/order.proto,com/example/orders/user.proto,com/example/users/user.proto,com/example/users/user_service.proto,com/example/users/user_status.proto"
+	if got := filePaths(contents); got != want {
+		t.Fatalf("Expected a file per declaration, got %s", got)
+	}
+
+	order := files["com/example/orders/order.proto"]
+	for _, expected := range []string{
+		"package com.example.orders;",
+		`import "com/example/orders/user.proto";`,
+		`import "com/example/users/user.proto";`,
+		"com.example.users.User customer = 1;",
+		"User processedBy = 2;",
+	} {
+		if !strings.Contains(order, expected) {
+			t.Errorf("Expected order.proto to contain %q:\n%s", expected, order)
+		}
+	}
+	if strings.Contains(order, "message User") {
+		t.Error("Expected order.proto to hold only Order")
+	}
+
+	if user := files["com/example/users/user.proto"]; !strings.Contains(user, `import "com/example/users/user_status.proto";`) {
+		t.Errorf("Expected users/user.proto to import the UserStatus file:\n%s", user)
+	}
+	if service := files["com/example/users/user_service.proto"]; !strings.Contains(service, `import "com/example/users/user.proto";`) || !strings.Contains(service, "service UserService") {
+		t.Errorf("Expected user_service.proto to import User:\n%s", service)
+	}
+}
+
+func TestProtobufGenerator_SingleLayout(t *testing.T) {
+	schema := layoutTestSchema()
+	output := NewProtobufGeneratorWithOptions(&ProtobufOptions{Layout: ProtobufLayoutSingle}).Generate(schema)
+
+	for _, expected := range []string{
+		"package com.example.orders;",
+		"enum UsersUserStatus {",
+		"message UsersUser {",
+		"UsersUserStatus status = 2;",
+		"message User {",
+		"UsersUser customer = 1;",
+		"User processedBy = 2;",
+		"service UsersUserService {",
+		"rpc GetUser(UsersUser) returns (UsersUser);",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected single file to contain %q:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "import \"com/example") {
+		t.Error("Expected a single file to import no namespace files")
+	}
+
+	// The schema itself is left alone
+	if schema.Types[0].Name != "User" || schema.Types[2].Fields[0].Type.Name != "com.example.users.User" {
+		t.Error("Expected the single layout not to modify the schema")
+	}
+}
+
+func TestNamespacePrefixes(t *testing.T) {
+	prefixes := namespacePrefixes([]string{"com.shop", "com.a.users", "com.b.users", "com.a.billing_v2"}, "com.shop")
+	want := map[string]string{"com.a.users": "AUsers", "com.b.users": "BUsers", "com.a.billing_v2": "BillingV2"}
+	if len(prefixes) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, prefixes)
+	}
+	for namespace, prefix := range want {
+		if prefixes[namespace] != prefix {
+			t.Errorf("%s: expected prefix %s, got %s", namespace, prefix, prefixes[namespace])
+		}
+	}
+}
+
+func TestGenerate_ProtobufLayouts(t *testing.T) {
+	gen, err := Lookup("protobuf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		layout ProtobufLayout
+		paths  string
+	}{
+		{layout: "", paths: "com/example/orders.proto,com/example/users.proto"},
+		{layout: ProtobufLayoutSingle, paths: "schema.proto"},
+		{layout: ProtobufLayoutType, paths: "com/example/orders/order.proto,com/example/orders/user.proto,com/example/users/user.proto,com/example/users/user_service.proto,com/example/users/user_status.proto"},
+	}
+	for _, tt := range tests {
+		files, err := gen.Generate(context.Background(), layoutTestSchema(), Options{Protobuf: &ProtobufOptions{Layout: tt.layout}})
+		if err != nil {
+			t.Fatalf("%q: Generate failed: %v", tt.layout, err)
+		}
+		if paths := filePaths(files); paths != tt.paths {
+			t.Errorf("%q: expected files %s, got %s", tt.layout, tt.paths, paths)
+		}
+	}
+
+	if _, err := gen.Generate(context.Background(), layoutTestSchema(), Options{Protobuf: &ProtobufOptions{Layout: "nested"}}); err == nil || !strings.Contains(err.Error(), `unknown protobuf layout "nested"`) {
+		t.Errorf("Expected an unknown layout error, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// Protobuf has no repeated fields of lists or maps, and no maps of lists, so a
+// list or map nested in a field is wrapped in a message generated inside the
+// message of the field, named after its contents:
+//
+//	message Holder {
+//	  repeated StringInt32Map rows = 1;
+//	  map<string, ItemList> groups = 2;
+//
+//	  message ItemList {
+//	    repeated Item values = 1;
+//	  }
+//
+//	  message StringInt32Map {
+//	    map<string, int32> entries = 1;
+//	  }
+//	}
+//
+// Maps of maps keep the map<K, map<K, V>> syntax.
+
+// protoRepeatedType returns the Protobuf type of the elements of an array field
+func (g *ProtobufGenerator) protoRepeatedType(fieldType *ast.FieldType, currentNamespace string, typeNameMap map[string]string) string {
+	return g.protoNestedType(fieldType.ElementType(), true, currentNamespace, typeNameMap)
+}
+
+// protoMapType returns the Protobuf type of a map, such as map<string, ItemList>
+func (g *ProtobufGenerator) protoMapType(fieldType *ast.FieldType, currentNamespace string, typeNameMap map[string]string) string {
+	keyType := g.mapScalarTypeWithPackageAndMap(fieldType.MapKey, currentNamespace, typeNameMap)
+	valueType := g.protoNestedType(fieldType.GetMapValueType(), false, currentNamespace, typeNameMap)
+	return fmt.Sprintf("map<%s, %s>", keyType, valueType)
+}
+
+// protoNestedType returns the Protobuf type of a list element, or of a map value
+// when repeated is false, adding the wrapper messages it needs to nestedWrappers
+func (g *ProtobufGenerator) protoNestedType(fieldType *ast.FieldType, repeated bool, currentNamespace string, typeNameMap map[string]string) string {
+	switch {
+	case fieldType.IsArray:
+		name := protoWrapperName(fieldType)
+		g.addNestedWrapper(name, fmt.Sprintf("repeated %s values = 1;", g.protoRepeatedType(fieldType, currentNamespace, typeNameMap)))
+		return name
+	case fieldType.IsMap && repeated:
+		name := protoWrapperName(fieldType)
+		g.addNestedWrapper(name, fmt.Sprintf("%s entries = 1;", g.protoMapType(fieldType, currentNamespace, typeNameMap)))
+		return name
+	case fieldType.IsMap:
+		return g.protoMapType(fieldType, currentNamespace, typeNameMap)
+	case currentNamespace != "":
+		return g.mapScalarTypeWithPackageAndMap(fieldType.Name, currentNamespace, typeNameMap)
+	default:
+		return g.mapScalarTypeWithMap(fieldType.Name, typeNameMap)
+	}
+}
+
+// addNestedWrapper records a wrapper message with the declaration of its field
+func (g *ProtobufGenerator) addNestedWrapper(name, field string) {
+	if g.nestedWrappers == nil {
+		g.nestedWrappers = make(map[string]string)
+	}
+	g.nestedWrappers[name] = field
+}
+
+// nestedWrapperMessages returns the wrapper messages recorded since the last
+// call, sorted by name and indented to nest in a message, or "" if there are none
+func (g *ProtobufGenerator) nestedWrapperMessages() string {
+	names := make([]string, 0, len(g.nestedWrappers))
+	for name := range g.nestedWrappers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("\n  message %s {\n    %s\n  }\n", name, g.nestedWrappers[name]))
+	}
+	g.nestedWrappers = nil
+	return sb.String()
+}
+
+// protoWrapperName returns the name of the wrapper message of a list or map,
+// or the name part of another type: ItemList for []Item, StringInt32Map for
+// map<string, int32>, and StringItemListMap for map<string, []Item>
+func protoWrapperName(fieldType *ast.FieldType) string {
+	switch {
+	case fieldType.IsArray:
+		return protoWrapperName(fieldType.ElementType()) + "List"
+	case fieldType.IsMap:
+		key := &ast.FieldType{Name: fieldType.MapKey}
+		return protoWrapperName(key) + protoWrapperName(fieldType.GetMapValueType()) + "Map"
+	default:
+		name := ast.GetUnqualifiedName(fieldType.Name)
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+}
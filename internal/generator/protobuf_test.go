@@ -116,6 +116,42 @@ func TestProtobufGenerator_GenerateEnum(t *testing.T) {
 	}
 }
 
+func TestProtobufGenerator_FlagsEnum(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "api",
+		Enums: []*ast.Enum{
+			{
+				Name:      "Permission",
+				Namespace: "api",
+				Flags:     true,
+				Values:    []*ast.EnumValue{{Name: "READ"}, {Name: "WRITE"}, {Name: "ADMIN"}},
+			},
+		},
+		Types: []*ast.Type{
+			{
+				Name:      "User",
+				Namespace: "api",
+				Fields: []*ast.Field{
+					{Name: "permissions", Type: &ast.FieldType{Name: "Permission"}, Number: 1, HasNumber: true},
+					{Name: "history", Type: &ast.FieldType{Name: "Permission", IsArray: true}, Number: 2, HasNumber: true},
+				},
+			},
+		},
+	}
+
+	output := NewProtobufGenerator().Generate(schema)
+
+	for _, want := range []string{
+		"  PERMISSION_UNSPECIFIED = 0;\n  READ = 1;\n  WRITE = 2;\n  ADMIN = 4;\n",
+		"  int32 permissions = 1;",
+		"  repeated int32 history = 2;",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
 func TestProtobufGenerator_GenerateEnumWithCustomNumbers(t *testing.T) {
 	gen := NewProtobufGenerator()
 
@@ -1417,6 +1453,52 @@ func TestGenerateOptionalFields(t *testing.T) {
 	}
 }
 
+func TestGenerateOptionalFieldsWithWrapperTypes(t *testing.T) {
+	schema := &ast.Schema{
+		Namespace: "test",
+		Types: []*ast.Type{
+			{
+				Name: "User",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string"}},
+					{Name: "name", Type: &ast.FieldType{Name: "string", Optional: true}},
+					{Name: "age", Type: &ast.FieldType{Name: "int32", Optional: true}},
+					{Name: "score", Type: &ast.FieldType{Name: "uint16", Optional: true}},
+					{Name: "created_at", Type: &ast.FieldType{Name: "timestamp", Optional: true}},
+					{Name: "tags", Type: &ast.FieldType{Name: "string", IsArray: true, Optional: true}},
+				},
+			},
+		},
+	}
+
+	gen := NewProtobufGeneratorWithOptions(&ProtobufOptions{UseWrapperTypes: true})
+	output := gen.Generate(schema)
+
+	expected := []string{
+		"import \"google/protobuf/wrappers.proto\";",
+		"string id = 1;",
+		"google.protobuf.StringValue name = 2;",
+		"google.protobuf.Int32Value age = 3;",
+		"google.protobuf.UInt32Value score = 4;",
+		"optional google.protobuf.Timestamp created_at = 5;",
+		"repeated string tags = 6;",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected %q in output, got:\n%s", exp, output)
+		}
+	}
+	if strings.Contains(output, "optional string") || strings.Contains(output, "optional int32") {
+		t.Errorf("Optional scalars should use wrapper types, got:\n%s", output)
+	}
+
+	// Without the option the wrappers import must not be added
+	output = NewProtobufGenerator().Generate(schema)
+	if strings.Contains(output, "wrappers.proto") {
+		t.Error("Did not expect wrappers.proto import when wrapper types are disabled")
+	}
+}
+
 func TestProtobufGenerator_NestedMaps(t *testing.T) {
 	gen := NewProtobufGenerator()
 
@@ -1497,6 +1579,57 @@ func TestProtobufGenerator_NestedMaps(t *testing.T) {
 	}
 }
 
+// nestedContainersSchema returns a schema with arrays of maps and maps of arrays
+func nestedContainersSchema() *ast.Schema {
+	item := &ast.FieldType{Name: "Item"}
+	return &ast.Schema{
+		Namespace: "test",
+		Types: []*ast.Type{
+			{Name: "Item", Fields: []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}}}},
+			{
+				Name: "Holder",
+				Fields: []*ast.Field{
+					{Name: "rows", Type: &ast.FieldType{Name: "map", IsArray: true, MapKey: "string", MapValueType: &ast.FieldType{Name: "int32", IsBuiltin: true}}},
+					{Name: "groups", Type: &ast.FieldType{Name: "map", IsMap: true, MapKey: "string", MapValueType: &ast.FieldType{Name: "Item", IsArray: true}}},
+					{Name: "matrix", Type: &ast.FieldType{Name: "[]float64", IsBuiltin: true, IsArray: true}},
+					{Name: "tagged", Type: &ast.FieldType{Name: "map", IsArray: true, MapKey: "string", MapValueType: item}},
+				},
+			},
+		},
+		Services: []*ast.Service{
+			{Name: "HolderService", Methods: []*ast.Method{
+				{Name: "GetHolder", InputType: "Item", OutputType: "Holder"},
+				{Name: "PutHolder", InputType: "Holder", OutputType: "Item"},
+			}},
+		},
+	}
+}
+
+func TestProtobufGenerator_NestedContainers(t *testing.T) {
+	output := NewProtobufGenerator().Generate(nestedContainersSchema())
+
+	expected := []string{
+		"repeated StringInt32Map rows = 1;",
+		"map<string, ItemList> groups = 2;",
+		"repeated Float64List matrix = 3;",
+		"repeated StringItemMap tagged = 4;",
+		"  message Float64List {\n    repeated double values = 1;\n  }",
+		"  message ItemList {\n    repeated Item values = 1;\n  }",
+		"  message StringInt32Map {\n    map<string, int32> entries = 1;\n  }",
+		"  message StringItemMap {\n    map<string, Item> entries = 1;\n  }",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+
+	// Wrappers are nested in the message that uses them
+	if strings.Count(output, "message ItemList") != 1 || strings.Index(output, "message ItemList") < strings.Index(output, "message Holder") {
+		t.Errorf("Expected ItemList to be nested in Holder once:\n%s", output)
+	}
+}
+
 func TestProtobufGenerator_FieldArguments(t *testing.T) {
 	schema := &ast.Schema{
 		Types: []*ast.Type{
@@ -1700,3 +1833,151 @@ func extractMessage(output, messageName string) string {
 
 	return msg.String()
 }
+
+func TestProtobufGenerator_ElementOptions(t *testing.T) {
+	schema := &ast.Schema{
+		Enums: []*ast.Enum{
+			{
+				Name:        "Status",
+				Values:      []*ast.EnumValue{{Name: "ACTIVE"}},
+				Annotations: &ast.FormatAnnotations{Proto: []string{"allow_alias = true"}},
+			},
+		},
+		Types: []*ast.Type{
+			{
+				Name: "User",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string", IsBuiltin: true}},
+					{
+						Name:        "scores",
+						Type:        &ast.FieldType{Name: "int32", IsBuiltin: true, IsArray: true},
+						Annotations: &ast.FormatAnnotations{Proto: []string{"[packed = false]", "json_name = \"s\""}},
+					},
+				},
+				Annotations: &ast.FormatAnnotations{Proto: []string{"[deprecated = true]"}},
+			},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "UserService",
+				Methods: []*ast.Method{
+					{Name: "GetUser", InputType: "User", OutputType: "User"},
+				},
+				Annotations: &ast.FormatAnnotations{Proto: []string{"(my.service_opt) = \"x\""}},
+			},
+		},
+	}
+
+	gen := NewProtobufGenerator()
+	output := gen.Generate(schema)
+
+	expected := []string{
+		"enum Status {\n  option allow_alias = true;\n",
+		"message User {\n  option deprecated = true;\n",
+		"repeated int32 scores = 2 [packed = false, json_name = \"s\"];",
+		"service UserService {\n  option (my.service_opt) = \"x\";\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestProtobufGenerator_EmptyMethodTypes(t *testing.T) {
+	output := NewProtobufGenerator().Generate(emptyMethodTestSchema())
+
+	expected := []string{
+		`import "google/protobuf/empty.proto";`,
+		"rpc Ping(google.protobuf.Empty) returns (google.protobuf.Empty);",
+		"rpc Reset(ResetRequest) returns (google.protobuf.Empty);",
+		"rpc GetStatus(google.protobuf.Empty) returns (Status);",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	schema := emptyMethodTestSchema()
+	schema.Services[0].Methods = schema.Services[0].Methods[:0]
+	if output := NewProtobufGenerator().Generate(schema); strings.Contains(output, "empty.proto") {
+		t.Error("Expected empty.proto to be imported only when a method needs it")
+	}
+}
+
+func TestProtobufGenerator_MethodPolicies(t *testing.T) {
+	schema := emptyMethodTestSchema()
+	status := schema.Services[0].Methods[2]
+	status.Timeout = "5s"
+	status.Idempotent = true
+	status.RateLimit = &ast.RateLimit{Requests: 100, Per: "minute"}
+	status.Auth = &ast.Auth{}
+
+	output := NewProtobufGenerator().Generate(schema)
+
+	want := "  // Timeout: 5s\n  // Rate limit: 100 requests per minute\n  // Auth: authenticated caller\n" +
+		"  rpc GetStatus(google.protobuf.Empty) returns (Status) {\n    option idempotency_level = IDEMPOTENT;\n  }\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+	}
+	if !strings.Contains(output, "rpc Ping(google.protobuf.Empty) returns (google.protobuf.Empty);") {
+		t.Error("Expected methods without policies to keep their plain declaration")
+	}
+}
+
+func TestProtobufGenerator_SensitiveField(t *testing.T) {
+	output := NewProtobufGenerator().Generate(sensitiveTestSchema())
+
+	if !strings.Contains(output, "  string email = 1; // sensitive: pii\n") {
+		t.Errorf("Expected a sensitive comment on email, got:\n%s", output)
+	}
+	if !strings.Contains(output, "  string name = 2;\n") {
+		t.Errorf("Expected name without a comment, got:\n%s", output)
+	}
+}
+
+// moneyTestSchema returns a schema where Product uses the standard library Money type
+func moneyTestSchema() *ast.Schema {
+	money := &ast.Type{Name: "Money", Namespace: "typemux.std", Fields: []*ast.Field{
+		{Name: "currencyCode", Type: &ast.FieldType{Name: "string", IsBuiltin: true}, Number: 1, HasNumber: true},
+		{Name: "units", Type: &ast.FieldType{Name: "int64", IsBuiltin: true}, Number: 2, HasNumber: true},
+		{Name: "nanos", Type: &ast.FieldType{Name: "int32", IsBuiltin: true}, Number: 3, HasNumber: true},
+	}}
+	product := &ast.Type{Name: "Product", Namespace: "shop", Fields: []*ast.Field{
+		{Name: "price", Type: &ast.FieldType{Name: "typemux.std.Money"}, Number: 1, HasNumber: true},
+		{Name: "discounts", Type: &ast.FieldType{Name: "typemux.std.Money", IsArray: true}, Number: 2, HasNumber: true},
+	}}
+	return &ast.Schema{Namespace: "shop", Types: []*ast.Type{money, product}}
+}
+
+func TestProtobufGenerator_WellKnownMoney(t *testing.T) {
+	gen := NewProtobufGenerator()
+
+	output := gen.Generate(moneyTestSchema())
+	for _, want := range []string{
+		"import \"google/type/money.proto\";\n",
+		"  google.type.Money price = 1;\n",
+		"  repeated google.type.Money discounts = 2;\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "message Money") {
+		t.Errorf("Expected no Money message, got:\n%s", output)
+	}
+
+	files := gen.GenerateByNamespace(moneyTestSchema())
+	if _, ok := files["typemux.std"]; ok || len(files) != 1 {
+		t.Errorf("Expected only a shop file, got %d files", len(files))
+	}
+	if shop := files["shop"]; !strings.Contains(shop, "import \"google/type/money.proto\";") || strings.Contains(shop, "typemux/std.proto") {
+		t.Errorf("Expected shop to import money.proto only, got:\n%s", shop)
+	}
+
+	single := NewProtobufGeneratorWithOptions(&ProtobufOptions{Layout: ProtobufLayoutSingle}).Generate(moneyTestSchema())
+	if !strings.Contains(single, "  google.type.Money price = 1;\n") || strings.Contains(single, "StdMoney") {
+		t.Errorf("Expected the single layout to use google.type.Money, got:\n%s", single)
+	}
+}
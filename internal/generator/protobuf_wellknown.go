@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"sort"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// protoWellKnownMessage is a published Protobuf message that a standard library
+// type is generated as, instead of a message of its own. Both have the same
+// fields and numbers, so they are wire compatible.
+type protoWellKnownMessage struct {
+	Name      string // Fully qualified message name
+	Import    string // Proto file declaring the message
+	GoPackage string // Import path of the protoc-gen-go types of the message
+	GoName    string // Package name of the protoc-gen-go types
+}
+
+// protoWellKnownMessages maps the qualified names of standard library types to
+// their well-known messages
+var protoWellKnownMessages = map[string]protoWellKnownMessage{
+	stdMoneyType: {
+		Name:      "google.type.Money",
+		Import:    "google/type/money.proto",
+		GoPackage: "google.golang.org/genproto/googleapis/type/money",
+		GoName:    "money",
+	},
+}
+
+// wellKnownType returns the well-known message a type is generated as, if any
+func wellKnownType(typ *ast.Type) (protoWellKnownMessage, bool) {
+	message, ok := protoWellKnownMessages[typ.Namespace+"."+typ.Name]
+	return message, ok
+}
+
+// wellKnownImports returns the proto files of the well-known messages the
+// fields and methods of a schema use, sorted
+func wellKnownImports(schema *ast.Schema) []string {
+	seen := make(map[string]bool)
+	var imports []string
+	use := func(typeName string) {
+		if message, ok := protoWellKnownMessages[typeName]; ok && !seen[message.Import] {
+			seen[message.Import] = true
+			imports = append(imports, message.Import)
+		}
+	}
+
+	for _, typ := range schema.Types {
+		for _, field := range typ.Fields {
+			for _, name := range fieldTypeNames(field.Type) {
+				use(name)
+			}
+		}
+	}
+	for _, service := range schema.Services {
+		for _, method := range service.Methods {
+			use(method.InputType)
+			use(method.OutputType)
+		}
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+// withoutWellKnownTypes returns the types of a list that are not generated as
+// well-known messages
+func withoutWellKnownTypes(types []*ast.Type) []*ast.Type {
+	var kept []*ast.Type
+	for _, typ := range types {
+		if _, ok := wellKnownType(typ); !ok {
+			kept = append(kept, typ)
+		}
+	}
+	return kept
+}
+
+// protoMethodType returns the Protobuf type of the input or output of a method
+func protoMethodType(typeName string) string {
+	if message, ok := protoWellKnownMessages[typeName]; ok {
+		return message.Name
+	}
+	return typeName
+}
@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"context"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"gopkg.in/yaml.v3"
+)
+
+// recursiveSchema returns a self-referencing Tree and the mutually recursive
+// Employee and Department
+func recursiveSchema() *ast.Schema {
+	field := func(name string, fieldType *ast.FieldType, required bool) *ast.Field {
+		return &ast.Field{Name: name, Type: fieldType, Required: required}
+	}
+	named := func(name string) *ast.FieldType { return &ast.FieldType{Name: name} }
+	return &ast.Schema{
+		Namespace: "api",
+		Types: []*ast.Type{
+			{Name: "Tree", Namespace: "api", Fields: []*ast.Field{
+				field("value", &ast.FieldType{Name: "string", IsBuiltin: true}, true),
+				field("children", &ast.FieldType{Name: "Tree", IsArray: true}, false),
+				field("parent", named("Tree"), false),
+				field("index", &ast.FieldType{Name: "map", IsMap: true, MapKey: "string", MapValueType: named("Tree")}, false),
+			}},
+			{Name: "Employee", Namespace: "api", Fields: []*ast.Field{
+				field("manager", named("Employee"), false),
+				field("department", named("Department"), false),
+			}},
+			{Name: "Department", Namespace: "api", Fields: []*ast.Field{
+				field("head", named("Employee"), true),
+				field("staff", &ast.FieldType{Name: "Employee", IsArray: true}, false),
+			}},
+		},
+		Services: []*ast.Service{{Name: "TreeService", Namespace: "api", Methods: []*ast.Method{
+			{Name: "SaveTree", InputType: "Tree", OutputType: "Tree"},
+			{Name: "GetDepartment", InputType: "Employee", OutputType: "Department", HTTPMethod: "GET"},
+		}}},
+	}
+}
+
+func TestRecursiveTypes_Go(t *testing.T) {
+	output := NewGoGenerator().Generate(recursiveSchema())
+	formatted, err := format.Source([]byte(output))
+	if err != nil {
+		t.Fatalf("Generated Go does not parse: %v\n%s", err, output)
+	}
+	output = string(formatted)
+
+	// Only fields that would embed their own type become pointers
+	for _, want := range []string{
+		"Children []Tree",
+		"Parent   *Tree",
+		"Index    map[string]Tree",
+		"Manager    *Employee",
+		"Department *Department",
+		"Head  *Employee",
+		"Staff []Employee",
+		"if m.Head == nil {",
+		"if m.Parent != nil {",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestRecursiveTypes_GraphQL(t *testing.T) {
+	output := NewGraphQLGenerator().Generate(recursiveSchema())
+	for _, want := range []string{
+		"  children: [Tree]\n  parent: Tree\n",
+		"  children: [TreeInput]\n  parent: TreeInput\n",
+		"  head: Employee!\n",
+		"  manager: EmployeeInput\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestRecursiveTypes_OpenAPI(t *testing.T) {
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(recursiveSchema())), &spec); err != nil {
+		t.Fatalf("failed to parse generated spec: %v", err)
+	}
+
+	tree := spec.Components.Schemas["Tree"]
+	if ref := tree.Properties["parent"].Ref; ref != "#/components/schemas/Tree" {
+		t.Errorf("Expected parent to reference Tree, got %q", ref)
+	}
+	if items := tree.Properties["children"].Items; items == nil || items.Ref != "#/components/schemas/Tree" {
+		t.Errorf("Expected children to be an array of Tree, got %+v", items)
+	}
+	if ref := spec.Components.Schemas["Department"].Properties["head"].Ref; ref != "#/components/schemas/Employee" {
+		t.Errorf("Expected head to reference Employee, got %q", ref)
+	}
+}
+
+// TestRecursiveTypes_AllFormats checks that every generator terminates on recursive types
+func TestRecursiveTypes_AllFormats(t *testing.T) {
+	for format := range generators {
+		t.Run(format, func(t *testing.T) {
+			gen, err := Lookup(format)
+			if err != nil {
+				t.Fatalf("Lookup failed: %v", err)
+			}
+			if _, err := gen.Generate(context.Background(), recursiveSchema(), Options{}); err != nil {
+				t.Errorf("Generate failed: %v", err)
+			}
+		})
+	}
+}
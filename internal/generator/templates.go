@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// DefaultTemplate is the name of the template that applies to every file of a
+// format without a template of its own, e.g. to add a license banner.
+const DefaultTemplate = "_default.tmpl"
+
+// TemplateData is the context of a template override.
+type TemplateData struct {
+	// Format is the name of the output format, such as "graphql" or "java"
+	Format string
+	// Path is the slash-separated path of the file relative to the output directory
+	Path string
+	// Output is the built-in output of the file, empty for files only the template creates
+	Output string
+	// Schema is the schema the files are generated from
+	Schema *ast.Schema
+}
+
+// templateFuncs are the functions available to templates in addition to the text/template builtins
+var templateFuncs = template.FuncMap{
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"title":      capitalize,
+	"join":       strings.Join,
+	"replace":    strings.ReplaceAll,
+	"trimPrefix": strings.TrimPrefix,
+	"trimSuffix": strings.TrimSuffix,
+	"hasPrefix":  strings.HasPrefix,
+	"hasSuffix":  strings.HasSuffix,
+	"contains":   strings.Contains,
+	"lines":      func(s string) []string { return strings.Split(strings.TrimSuffix(s, "\n"), "\n") },
+	"doc": func(doc *ast.Documentation, format string) string {
+		if doc == nil {
+			return ""
+		}
+		return doc.GetDoc(format)
+	},
+}
+
+// capitalize upper-cases the first letter of a name
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// uncapitalize lower-cases the first letter of a name, such as for unexported identifiers
+func uncapitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// Templated wraps the generator of a format so that the template overrides in
+// Options.TemplatesFS or Options.Templates replace its built-in output.
+//
+// Overrides are text/template files in the format's subdirectory, named after
+// the file they produce: <templates>/graphql/schema.graphql.tmpl renders
+// schema.graphql, and <templates>/java/com/acme/User.java.tmpl renders that Java
+// file. <templates>/<format>/_default.tmpl renders every other file of the
+// format. Templates for files the generator does not produce add those files.
+// Each template is executed with a TemplateData.
+func Templated(format string, gen Generator) Generator {
+	return GeneratorFunc(func(ctx context.Context, schema *ast.Schema, opts Options) (map[string][]byte, error) {
+		files, err := gen.Generate(ctx, schema, opts)
+		if err != nil || (opts.TemplatesFS == nil && opts.Templates == "") {
+			return files, err
+		}
+		fsys := opts.TemplatesFS
+		if fsys == nil {
+			fsys = os.DirFS(opts.Templates)
+		}
+		return applyTemplates(fsys, format, schema, files)
+	})
+}
+
+// applyTemplates renders the template overrides of the directory of a format
+// over the generated files
+func applyTemplates(fsys fs.FS, format string, schema *ast.Schema, files map[string][]byte) (map[string][]byte, error) {
+	templates, err := findTemplates(fsys, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return files, nil
+	}
+
+	defaultTemplate := templates[DefaultTemplate]
+	delete(templates, DefaultTemplate)
+
+	result := make(map[string][]byte, len(files))
+	for filePath, content := range files {
+		templatePath, ok := templates[filePath]
+		if !ok {
+			templatePath = defaultTemplate
+		}
+		if templatePath == "" {
+			result[filePath] = content
+			continue
+		}
+		rendered, err := renderTemplate(fsys, templatePath, TemplateData{Format: format, Path: filePath, Output: string(content), Schema: schema})
+		if err != nil {
+			return nil, err
+		}
+		result[filePath] = rendered
+	}
+
+	// Templates for files the generator does not produce add them
+	for filePath, templatePath := range templates {
+		if _, ok := files[filePath]; ok {
+			continue
+		}
+		rendered, err := renderTemplate(fsys, templatePath, TemplateData{Format: format, Path: filePath, Schema: schema})
+		if err != nil {
+			return nil, err
+		}
+		result[filePath] = rendered
+	}
+
+	return result, nil
+}
+
+// findTemplates returns the template files of a format directory by the path of
+// the file they render; a missing directory has none
+func findTemplates(fsys fs.FS, dir string) (map[string]string, error) {
+	templates := make(map[string]string)
+	err := fs.WalkDir(fsys, dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(file, ".tmpl") {
+			return nil
+		}
+		rel := strings.TrimPrefix(file, dir+"/")
+		if rel != DefaultTemplate {
+			rel = strings.TrimSuffix(rel, ".tmpl")
+		}
+		templates[rel] = file
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates in %s: %w", dir, err)
+	}
+	return templates, nil
+}
+
+// renderTemplate executes a template file
+func renderTemplate(fsys fs.FS, file string, data TemplateData) ([]byte, error) {
+	content, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := template.New(path.Base(file)).Funcs(templateFuncs).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", file, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s with template %s: %w", data.Path, file, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// writeTemplates writes template files into a temporary directory and returns it
+func writeTemplates(t *testing.T, templates map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range templates {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// generateWithTemplates generates a format with template overrides
+func generateWithTemplates(t *testing.T, format string, schema *ast.Schema, templates string) (map[string][]byte, error) {
+	t.Helper()
+	gen, err := Lookup(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gen.Generate(context.Background(), schema, Options{Templates: templates})
+}
+
+func TestTemplated(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"graphql/schema.graphql.tmpl": "# Copyright Acme\n{{ .Output }}",
+		"graphql/TYPES.md.tmpl":       "{{ range .Schema.Types }}{{ upper .Name }} in {{ $.Format }}\n{{ end }}",
+		"java/_default.tmpl":          "// {{ .Path }}\n{{ .Output }}",
+		"protobuf/README.md":          "not a template",
+	})
+	schema := generatorTestSchema("api")
+
+	files, err := generateWithTemplates(t, "graphql", schema, dir)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if output := string(files["schema.graphql"]); !strings.HasPrefix(output, "# Copyright Acme\n") || !strings.Contains(output, "type User") {
+		t.Errorf("Expected the banner before the built-in output, got:\n%s", output)
+	}
+	if types := string(files["TYPES.md"]); types != "USER in graphql\n" {
+		t.Errorf("Expected a file created by a template, got %q", types)
+	}
+
+	files, err = generateWithTemplates(t, "java", schema, dir)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if output := string(files["java/api/User.java"]); !strings.HasPrefix(output, "// java/api/User.java\n") {
+		t.Errorf("Expected the default template to apply, got:\n%s", output)
+	}
+
+	// Formats without templates, and files that are not templates, are left alone
+	files, err = generateWithTemplates(t, "protobuf", schema, dir)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if paths := filePaths(files); paths != "schema.proto" {
+		t.Errorf("Expected the built-in files only, got %s", paths)
+	}
+}
+
+func TestTemplated_FS(t *testing.T) {
+	gen, err := Lookup("graphql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	templates := fstest.MapFS{
+		"graphql/schema.graphql.tmpl": {Data: []byte("# In memory\n{{ .Output }}")},
+	}
+
+	files, err := gen.Generate(context.Background(), generatorTestSchema("api"), Options{Templates: "ignored", TemplatesFS: templates})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if output := string(files["schema.graphql"]); !strings.HasPrefix(output, "# In memory\n") {
+		t.Errorf("Expected the template of the file system to apply, got:\n%s", output)
+	}
+}
+
+func TestTemplated_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expect   string
+	}{
+		{name: "parse", template: "{{ .Output", expect: "failed to parse template"},
+		{name: "unknown field", template: "{{ .Nope }}", expect: "failed to render schema.graphql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeTemplates(t, map[string]string{"graphql/schema.graphql.tmpl": tt.template})
+			_, err := generateWithTemplates(t, "graphql", generatorTestSchema("api"), dir)
+			if err == nil || !strings.Contains(err.Error(), tt.expect) {
+				t.Errorf("Expected error containing %q, got %v", tt.expect, err)
+			}
+		})
+	}
+}
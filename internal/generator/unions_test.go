@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/rasmartins/typemux/internal/ast"
+	"gopkg.in/yaml.v3"
 )
 
 func TestProtobufGenerator_GenerateUnion(t *testing.T) {
@@ -407,3 +408,69 @@ func TestGraphQLGenerator_UnionAsInputType(t *testing.T) {
 		t.Error("Expected DateFilterInput")
 	}
 }
+
+// resultUnionSchema returns a service whose GetUser returns a union of the user
+// and its errors
+func resultUnionSchema() *ast.Schema {
+	stringField := func(name string) *ast.Field {
+		return &ast.Field{Name: name, Type: &ast.FieldType{Name: "string", IsBuiltin: true}, Required: true}
+	}
+	return &ast.Schema{
+		Types: []*ast.Type{
+			{Name: "GetUserRequest", Fields: []*ast.Field{stringField("id")}},
+			{Name: "User", Fields: []*ast.Field{stringField("id")}},
+			{Name: "NotFoundError", Fields: []*ast.Field{stringField("message")}},
+		},
+		Unions: []*ast.Union{
+			{Name: "UserResult", Options: []string{"User", "NotFoundError"}},
+		},
+		Services: []*ast.Service{
+			{
+				Name: "UserService",
+				Methods: []*ast.Method{
+					{Name: "GetUser", InputType: "GetUserRequest", OutputType: "UserResult", HTTPMethod: "GET"},
+				},
+			},
+		},
+	}
+}
+
+func TestGraphQLGenerator_ResultUnion(t *testing.T) {
+	output := NewGraphQLGenerator().Generate(resultUnionSchema())
+
+	for _, want := range []string{
+		"union UserResult = User | NotFoundError",
+		"getUser(input: GetUserRequest): UserResult",
+		"type NotFoundError {",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	for _, unwanted := range []string{"input UserResultInput", "input UserInput", "input NotFoundErrorInput"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("Expected no %q for a union only returned by methods", unwanted)
+		}
+	}
+}
+
+func TestOpenAPIGenerator_ResultUnion(t *testing.T) {
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(NewOpenAPIGenerator().Generate(resultUnionSchema())), &spec); err != nil {
+		t.Fatalf("failed to parse generated spec: %v", err)
+	}
+
+	var schema OpenAPISchemaRef
+	for _, item := range spec.Paths {
+		schema = item["get"].Responses["200"].Content["application/json"].Schema
+	}
+	if schema.Ref != "" || len(schema.OneOf) != 2 || schema.OneOf[1].Ref != "#/components/schemas/NotFoundError" {
+		t.Errorf("Expected the response to be oneOf the union options, got %+v", schema)
+	}
+	if schema.Discriminator == nil || schema.Discriminator.Mapping["User"] != "#/components/schemas/User" {
+		t.Errorf("Expected a discriminator mapping the union options, got %+v", schema.Discriminator)
+	}
+	if _, ok := spec.Components.Schemas["UserResult"]; !ok {
+		t.Error("Expected the union to stay in components")
+	}
+}
@@ -0,0 +1,414 @@
+// Package graph builds dependency graphs of TypeMUX schemas: which types reference
+// which, which types belong to which unions, and which types services use.
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// NodeKind identifies the kind of schema element a node represents.
+type NodeKind string
+
+// Node kinds.
+const (
+	KindType    NodeKind = "type"
+	KindEnum    NodeKind = "enum"
+	KindUnion   NodeKind = "union"
+	KindService NodeKind = "service"
+)
+
+// EdgeKind identifies how one schema element depends on another.
+type EdgeKind string
+
+// Edge kinds.
+const (
+	EdgeField   EdgeKind = "field"   // A type field references another element
+	EdgeExtends EdgeKind = "extends" // A type inherits the fields of a base type
+	EdgeOption  EdgeKind = "option"  // A union includes a type as one of its options
+	EdgeInput   EdgeKind = "input"   // A service method takes the type as request
+	EdgeOutput  EdgeKind = "output"  // A service method returns the type as response
+)
+
+// Node is a schema element in the dependency graph.
+type Node struct {
+	ID        string   `json:"id"` // Qualified name (namespace.Name), or Name without a namespace
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Kind      NodeKind `json:"kind"`
+}
+
+// Edge is a dependency from one node to another. Labels name the fields or
+// methods that create the dependency.
+type Edge struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Kind   EdgeKind `json:"kind"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// Graph is the dependency graph of a schema.
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+
+	nodes    map[string]*Node
+	edges    map[string]*Edge
+	registry *ast.TypeRegistry
+}
+
+// Build creates the dependency graph of a schema. References to builtin types
+// and to elements that are not part of the schema are ignored.
+func Build(schema *ast.Schema) *Graph {
+	g := &Graph{
+		nodes:    make(map[string]*Node),
+		edges:    make(map[string]*Edge),
+		registry: ast.NewTypeRegistry(),
+	}
+
+	for _, typ := range schema.Types {
+		g.registry.RegisterType(typ)
+		g.addNode(typ.Name, typ.Namespace, KindType)
+	}
+	for _, enum := range schema.Enums {
+		g.registry.RegisterEnum(enum)
+		g.addNode(enum.Name, enum.Namespace, KindEnum)
+	}
+	for _, union := range schema.Unions {
+		g.registry.RegisterUnion(union)
+		g.addNode(union.Name, union.Namespace, KindUnion)
+	}
+	for _, service := range schema.Services {
+		g.addNode(service.Name, service.Namespace, KindService)
+	}
+
+	for _, typ := range schema.Types {
+		from := nodeID(typ.Namespace, typ.Name)
+		for _, base := range typ.Extends {
+			g.addEdge(from, base, typ.Namespace, EdgeExtends, "")
+		}
+		for _, field := range typ.Fields {
+			for _, name := range referencedTypes(field.Type) {
+				g.addEdge(from, name, typ.Namespace, EdgeField, field.Name)
+			}
+		}
+	}
+	for _, union := range schema.Unions {
+		from := nodeID(union.Namespace, union.Name)
+		for _, option := range union.Options {
+			g.addEdge(from, option, union.Namespace, EdgeOption, "")
+		}
+	}
+	for _, service := range schema.Services {
+		from := nodeID(service.Namespace, service.Name)
+		for _, method := range service.Methods {
+			g.addEdge(from, method.InputType, service.Namespace, EdgeInput, method.Name)
+			g.addEdge(from, method.OutputType, service.Namespace, EdgeOutput, method.Name)
+		}
+	}
+
+	return g
+}
+
+// Node returns the node with the given ID.
+func (g *Graph) Node(id string) (*Node, bool) {
+	node, ok := g.nodes[id]
+	return node, ok
+}
+
+// nodeID returns the ID of a schema element
+func nodeID(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// addNode adds a node for a schema element
+func (g *Graph) addNode(name, namespace string, kind NodeKind) {
+	id := nodeID(namespace, name)
+	if _, exists := g.nodes[id]; exists {
+		return
+	}
+	node := &Node{ID: id, Name: name, Namespace: namespace, Kind: kind}
+	g.nodes[id] = node
+	g.Nodes = append(g.Nodes, node)
+}
+
+// addEdge adds a dependency, merging labels of repeated dependencies
+func (g *Graph) addEdge(from, typeName, namespace string, kind EdgeKind, label string) {
+	to, ok := g.resolve(typeName, namespace)
+	if !ok {
+		return
+	}
+
+	key := from + "\x00" + to + "\x00" + string(kind)
+	edge, exists := g.edges[key]
+	if !exists {
+		edge = &Edge{From: from, To: to, Kind: kind}
+		g.edges[key] = edge
+		g.Edges = append(g.Edges, edge)
+	}
+	if label != "" {
+		edge.Labels = append(edge.Labels, label)
+	}
+}
+
+// resolve finds the node ID of a referenced type name
+func (g *Graph) resolve(typeName, namespace string) (string, bool) {
+	if typeName == "" || ast.BuiltinTypes[typeName] {
+		return "", false
+	}
+
+	qualified, ok := g.registry.ResolveType(typeName, namespace)
+	if !ok {
+		return "", false
+	}
+	id := strings.TrimPrefix(qualified, ".")
+	if _, exists := g.nodes[id]; !exists {
+		return "", false
+	}
+	return id, true
+}
+
+// referencedTypes returns the type names a field type refers to, including map values
+func referencedTypes(fieldType *ast.FieldType) []string {
+	if fieldType == nil {
+		return nil
+	}
+	if !fieldType.IsMap {
+		return []string{fieldType.Name}
+	}
+
+	names := []string{fieldType.MapKey}
+	if valueType := fieldType.GetMapValueType(); valueType != nil {
+		names = append(names, referencedTypes(valueType)...)
+	} else {
+		names = append(names, fieldType.MapValue)
+	}
+	return names
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+
+	for _, node := range g.Nodes {
+		id := mermaidID(node.ID)
+		label := strings.ReplaceAll(node.Name, "\"", "'")
+		switch node.Kind {
+		case KindEnum:
+			sb.WriteString(fmt.Sprintf("  %s([\"%s\"])\n", id, label))
+		case KindUnion:
+			sb.WriteString(fmt.Sprintf("  %s{{\"%s\"}}\n", id, label))
+		case KindService:
+			sb.WriteString(fmt.Sprintf("  %s[[\"%s\"]]\n", id, label))
+		default:
+			sb.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", id, label))
+		}
+	}
+
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		switch edge.Kind {
+		case EdgeOption:
+			arrow = "-.->"
+		case EdgeExtends:
+			arrow = "==>"
+		}
+		label := ""
+		if len(edge.Labels) > 0 {
+			label = fmt.Sprintf("|%s|", strings.Join(edge.Labels, ", "))
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s%s %s\n", mermaidID(edge.From), arrow, label, mermaidID(edge.To)))
+	}
+
+	return sb.String()
+}
+
+// mermaidID converts a node ID into a valid Mermaid identifier
+func mermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+// DOT renders the graph in Graphviz DOT format.
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph schema {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+
+	for _, node := range g.Nodes {
+		shape := "box"
+		switch node.Kind {
+		case KindEnum:
+			shape = "ellipse"
+		case KindUnion:
+			shape = "hexagon"
+		case KindService:
+			shape = "component"
+		}
+		sb.WriteString(fmt.Sprintf("  %q [label=%q, shape=%s];\n", node.ID, node.Name, shape))
+	}
+
+	for _, edge := range g.Edges {
+		var attrs []string
+		if len(edge.Labels) > 0 {
+			attrs = append(attrs, fmt.Sprintf("label=%q", strings.Join(edge.Labels, ", ")))
+		}
+		switch edge.Kind {
+		case EdgeOption:
+			attrs = append(attrs, "style=dashed")
+		case EdgeExtends:
+			attrs = append(attrs, "arrowhead=empty")
+		}
+		attrList := ""
+		if len(attrs) > 0 {
+			attrList = " [" + strings.Join(attrs, ", ") + "]"
+		}
+		sb.WriteString(fmt.Sprintf("  %q -> %q%s;\n", edge.From, edge.To, attrList))
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Lookup finds a node by ID, or by unqualified name when that name is unique.
+func (g *Graph) Lookup(name string) (*Node, bool) {
+	if node, ok := g.nodes[name]; ok {
+		return node, true
+	}
+
+	var match *Node
+	for _, node := range g.Nodes {
+		if node.Name == name {
+			if match != nil {
+				return nil, false
+			}
+			match = node
+		}
+	}
+	return match, match != nil
+}
+
+// Roots returns the IDs of all service nodes.
+func (g *Graph) Roots() []string {
+	var roots []string
+	for _, node := range g.Nodes {
+		if node.Kind == KindService {
+			roots = append(roots, node.ID)
+		}
+	}
+	return roots
+}
+
+// Reachable returns the IDs of all nodes reachable from the given roots, including the roots.
+func (g *Graph) Reachable(roots ...string) map[string]bool {
+	reachable := make(map[string]bool)
+	queue := append([]string(nil), roots...)
+	for _, root := range roots {
+		reachable[root] = true
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.Edges {
+			if edge.From == current && !reachable[edge.To] {
+				reachable[edge.To] = true
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	return reachable
+}
+
+// Unused returns the types, enums, and unions that no service uses, directly or indirectly.
+func (g *Graph) Unused() []*Node {
+	reachable := g.Reachable(g.Roots()...)
+
+	var unused []*Node
+	for _, node := range g.Nodes {
+		if node.Kind != KindService && !reachable[node.ID] {
+			unused = append(unused, node)
+		}
+	}
+	return unused
+}
+
+// Incoming returns the edges that point to the given node.
+func (g *Graph) Incoming(id string) []*Edge {
+	var incoming []*Edge
+	for _, edge := range g.Edges {
+		if edge.To == id {
+			incoming = append(incoming, edge)
+		}
+	}
+	return incoming
+}
+
+// Why returns the shortest chain of edges from a service to the given node,
+// explaining why the node is part of the API. It returns nil when no service
+// uses the node.
+func (g *Graph) Why(id string) []*Edge {
+	via := make(map[string]*Edge)
+	visited := make(map[string]bool)
+	queue := g.Roots()
+	for _, root := range queue {
+		visited[root] = true
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == id {
+			break
+		}
+		for _, edge := range g.Edges {
+			if edge.From == current && !visited[edge.To] {
+				visited[edge.To] = true
+				via[edge.To] = edge
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	var path []*Edge
+	for edge := via[id]; edge != nil; edge = via[edge.From] {
+		path = append([]*Edge{edge}, path...)
+	}
+	return path
+}
+
+// ImportDOT renders an import graph, mapping each file to the files it imports,
+// in Graphviz DOT format.
+func ImportDOT(imports map[string][]string) string {
+	files := make([]string, 0, len(imports))
+	for file := range imports {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var sb strings.Builder
+	sb.WriteString("digraph imports {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=note];\n")
+	for _, file := range files {
+		sb.WriteString(fmt.Sprintf("  %q;\n", file))
+		for _, imported := range imports[file] {
+			sb.WriteString(fmt.Sprintf("  %q -> %q;\n", file, imported))
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,243 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func testSchema() *ast.Schema {
+	return &ast.Schema{
+		Namespace: "shop",
+		Types: []*ast.Type{
+			{
+				Name:      "Order",
+				Namespace: "shop",
+				Fields: []*ast.Field{
+					{Name: "id", Type: &ast.FieldType{Name: "string"}},
+					{Name: "customer", Type: &ast.FieldType{Name: "common.Customer"}},
+					{Name: "billing", Type: &ast.FieldType{Name: "Address"}},
+					{Name: "shipping", Type: &ast.FieldType{Name: "Address"}},
+					{Name: "status", Type: &ast.FieldType{Name: "Status"}},
+					{Name: "lines", Type: &ast.FieldType{Name: "LineItem", IsArray: true}},
+					{Name: "payment", Type: &ast.FieldType{Name: "Payment"}},
+				},
+			},
+			{
+				Name:      "LineItem",
+				Namespace: "shop",
+				Fields: []*ast.Field{
+					{Name: "attributes", Type: &ast.FieldType{IsMap: true, MapKey: "string", MapValue: "Address"}},
+				},
+			},
+			{Name: "Address", Namespace: "shop"},
+			{Name: "Card", Namespace: "shop"},
+			{Name: "Customer", Namespace: "common"},
+			{Name: "GetOrderRequest", Namespace: "shop"},
+		},
+		Enums: []*ast.Enum{
+			{Name: "Status", Namespace: "shop"},
+		},
+		Unions: []*ast.Union{
+			{Name: "Payment", Namespace: "shop", Options: []string{"Card"}},
+		},
+		Services: []*ast.Service{
+			{
+				Name:      "OrderService",
+				Namespace: "shop",
+				Methods: []*ast.Method{
+					{Name: "GetOrder", InputType: "GetOrderRequest", OutputType: "Order"},
+					{Name: "UpdateOrder", InputType: "Order", OutputType: "Order"},
+				},
+			},
+		},
+	}
+}
+
+func findEdge(g *Graph, from, to string, kind EdgeKind) *Edge {
+	for _, edge := range g.Edges {
+		if edge.From == from && edge.To == to && edge.Kind == kind {
+			return edge
+		}
+	}
+	return nil
+}
+
+func TestBuild_Edges(t *testing.T) {
+	g := Build(testSchema())
+
+	if len(g.Nodes) != 9 {
+		t.Errorf("Expected 9 nodes, got %d", len(g.Nodes))
+	}
+
+	edge := findEdge(g, "shop.Order", "shop.Address", EdgeField)
+	if edge == nil {
+		t.Fatal("Expected field edge from Order to Address")
+	}
+	if strings.Join(edge.Labels, ",") != "billing,shipping" {
+		t.Errorf("Expected merged labels billing,shipping, got %v", edge.Labels)
+	}
+
+	expected := []struct {
+		from, to string
+		kind     EdgeKind
+	}{
+		{"shop.Order", "common.Customer", EdgeField},
+		{"shop.Order", "shop.Status", EdgeField},
+		{"shop.Order", "shop.LineItem", EdgeField},
+		{"shop.Order", "shop.Payment", EdgeField},
+		{"shop.LineItem", "shop.Address", EdgeField},
+		{"shop.Payment", "shop.Card", EdgeOption},
+		{"shop.OrderService", "shop.GetOrderRequest", EdgeInput},
+		{"shop.OrderService", "shop.Order", EdgeOutput},
+	}
+	for _, e := range expected {
+		if findEdge(g, e.from, e.to, e.kind) == nil {
+			t.Errorf("Expected %s edge from %s to %s", e.kind, e.from, e.to)
+		}
+	}
+
+	for _, edge := range g.Edges {
+		if edge.To == "string" {
+			t.Error("Expected builtin types to be ignored")
+		}
+	}
+}
+
+func TestGraph_Mermaid(t *testing.T) {
+	output := Build(testSchema()).Mermaid()
+
+	expected := []string{
+		"flowchart LR\n",
+		`shop_Order["Order"]`,
+		`shop_Status(["Status"])`,
+		`shop_Payment{{"Payment"}}`,
+		`shop_OrderService[["OrderService"]]`,
+		"shop_Order -->|billing, shipping| shop_Address",
+		"shop_Payment -.-> shop_Card",
+		"shop_OrderService -->|UpdateOrder| shop_Order",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected Mermaid output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestGraph_DOT(t *testing.T) {
+	output := Build(testSchema()).DOT()
+
+	expected := []string{
+		"digraph schema {",
+		`"shop.Order" [label="Order", shape=box];`,
+		`"shop.Status" [label="Status", shape=ellipse];`,
+		`"shop.Order" -> "shop.Address" [label="billing, shipping"];`,
+		`"shop.Payment" -> "shop.Card" [style=dashed];`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected DOT output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestGraph_Lookup(t *testing.T) {
+	g := Build(testSchema())
+
+	if node, ok := g.Lookup("Address"); !ok || node.ID != "shop.Address" {
+		t.Errorf("Expected unqualified lookup to find shop.Address, got %v", node)
+	}
+	if node, ok := g.Lookup("common.Customer"); !ok || node.Kind != KindType {
+		t.Errorf("Expected qualified lookup to find common.Customer, got %v", node)
+	}
+	if _, ok := g.Lookup("Missing"); ok {
+		t.Error("Expected lookup of unknown name to fail")
+	}
+
+	schema := testSchema()
+	schema.Types = append(schema.Types, &ast.Type{Name: "Address", Namespace: "common"})
+	if _, ok := Build(schema).Lookup("Address"); ok {
+		t.Error("Expected ambiguous unqualified lookup to fail")
+	}
+}
+
+func TestGraph_Unused(t *testing.T) {
+	schema := testSchema()
+	schema.Types = append(schema.Types,
+		&ast.Type{Name: "Legacy", Namespace: "shop", Fields: []*ast.Field{
+			{Name: "old", Type: &ast.FieldType{Name: "LegacyDetail"}},
+		}},
+		&ast.Type{Name: "LegacyDetail", Namespace: "shop"},
+	)
+
+	unused := Build(schema).Unused()
+	var ids []string
+	for _, node := range unused {
+		ids = append(ids, node.ID)
+	}
+	if strings.Join(ids, ",") != "shop.Legacy,shop.LegacyDetail" {
+		t.Errorf("Expected Legacy and LegacyDetail to be unused, got %v", ids)
+	}
+}
+
+func TestBuild_ExtendsEdge(t *testing.T) {
+	schema := testSchema()
+	schema.Types = append(schema.Types, &ast.Type{Name: "Entity", Namespace: "shop"})
+	schema.Types[0].Extends = []string{"shop.Entity"}
+
+	g := Build(schema)
+	if findEdge(g, "shop.Order", "shop.Entity", EdgeExtends) == nil {
+		t.Fatal("Expected extends edge from Order to Entity")
+	}
+	for _, node := range g.Unused() {
+		if node.ID == "shop.Entity" {
+			t.Error("Expected base type of a used type to be reachable")
+		}
+	}
+	if !strings.Contains(g.Mermaid(), "shop_Order ==> shop_Entity") {
+		t.Errorf("Expected thick Mermaid arrow for extends, got:\n%s", g.Mermaid())
+	}
+	if !strings.Contains(g.DOT(), `"shop.Order" -> "shop.Entity" [arrowhead=empty];`) {
+		t.Errorf("Expected hollow DOT arrowhead for extends, got:\n%s", g.DOT())
+	}
+}
+
+func TestGraph_Why(t *testing.T) {
+	g := Build(testSchema())
+
+	path := g.Why("shop.Card")
+	var steps []string
+	for _, edge := range path {
+		steps = append(steps, edge.From+"->"+edge.To)
+	}
+	expected := "shop.OrderService->shop.Order,shop.Order->shop.Payment,shop.Payment->shop.Card"
+	if strings.Join(steps, ",") != expected {
+		t.Errorf("Expected path %s, got %s", expected, strings.Join(steps, ","))
+	}
+
+	incoming := g.Incoming("shop.Address")
+	if len(incoming) != 2 {
+		t.Errorf("Expected Address to be referenced by 2 elements, got %d", len(incoming))
+	}
+
+	schema := testSchema()
+	schema.Types = append(schema.Types, &ast.Type{Name: "Orphan", Namespace: "shop"})
+	if path := Build(schema).Why("shop.Orphan"); path != nil {
+		t.Errorf("Expected no path for unreachable type, got %v", path)
+	}
+}
+
+func TestImportDOT(t *testing.T) {
+	output := ImportDOT(map[string][]string{
+		"main.typemux":   {"common.typemux"},
+		"common.typemux": {},
+	})
+
+	if !strings.Contains(output, "digraph imports {") {
+		t.Error("Expected imports digraph")
+	}
+	if !strings.Contains(output, `"main.typemux" -> "common.typemux";`) {
+		t.Errorf("Expected import edge, got:\n%s", output)
+	}
+}
@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// OwnedElement is a namespace, type, or service and its effective ownership.
+type OwnedElement struct {
+	Kind      string `json:"kind"`                // namespace, type, or service
+	Name      string `json:"name"`                // Qualified name
+	Owner     string `json:"owner,omitempty"`     // Owning team or person, empty when unowned
+	SLA       string `json:"sla,omitempty"`       // Service level objective
+	Tier      string `json:"tier,omitempty"`      // Criticality tier
+	Inherited bool   `json:"inherited,omitempty"` // Whether the owner comes from the namespace
+}
+
+// OwnershipReport lists the namespaces with @owner, @sla, or @tier annotations,
+// sorted by name, followed by every type and service in schema order with the
+// ownership it declares or inherits from its namespace.
+func OwnershipReport(schema *ast.Schema) []OwnedElement {
+	namespaces := make(map[string]*ast.FormatAnnotations)
+	if schema.NamespaceAnnotations != nil {
+		namespaces[schema.Namespace] = schema.NamespaceAnnotations
+	}
+	for namespace, annotations := range schema.ImportedNamespaceAnnotations {
+		namespaces[namespace] = annotations
+	}
+	names := make([]string, 0, len(namespaces))
+	for namespace, annotations := range namespaces {
+		if namespace != "" && annotations != nil && annotations.Ownership != nil {
+			names = append(names, namespace)
+		}
+	}
+	sort.Strings(names)
+
+	var report []OwnedElement
+	for _, namespace := range names {
+		report = append(report, ownedElement(schema, "namespace", namespace, namespace, nil))
+	}
+	for _, typ := range schema.Types {
+		report = append(report, ownedElement(schema, "type", nodeID(typ.Namespace, typ.Name), typ.Namespace, typ.Annotations))
+	}
+	for _, service := range schema.Services {
+		report = append(report, ownedElement(schema, "service", nodeID(service.Namespace, service.Name), service.Namespace, service.Annotations))
+	}
+	return report
+}
+
+// ownedElement describes the effective ownership of an element of a namespace
+// with the given annotations; a namespace has no annotations of its own here
+func ownedElement(schema *ast.Schema, kind, name, namespace string, annotations *ast.FormatAnnotations) OwnedElement {
+	element := OwnedElement{Kind: kind, Name: name}
+	ownership := schema.OwnershipOf(namespace, annotations)
+	if ownership == nil {
+		return element
+	}
+	element.Owner, element.SLA, element.Tier = ownership.Owner, ownership.SLA, ownership.Tier
+	if kind != "namespace" && element.Owner != "" {
+		element.Inherited = annotations == nil || annotations.Ownership == nil || annotations.Ownership.Owner == ""
+	}
+	return element
+}
+
+// FormatOwnershipReport renders an ownership report as text in the style of a
+// CODEOWNERS file: one line per owned element with its owner, its SLA and tier
+// as a comment, followed by the unowned types and services.
+func FormatOwnershipReport(report []OwnedElement) string {
+	if len(report) == 0 {
+		return "No namespaces, types, or services to report.\n"
+	}
+
+	width := 0
+	for _, element := range report {
+		width = max(width, len(element.Name))
+	}
+
+	var sb strings.Builder
+	var unowned []string
+	owners := make(map[string]bool)
+	for _, element := range report {
+		if element.Owner == "" {
+			unowned = append(unowned, element.Name)
+			continue
+		}
+		owners[element.Owner] = true
+
+		var notes []string
+		if element.SLA != "" {
+			notes = append(notes, "sla "+element.SLA)
+		}
+		if element.Tier != "" {
+			notes = append(notes, "tier "+element.Tier)
+		}
+		if element.Inherited {
+			notes = append(notes, "from namespace")
+		}
+		line := fmt.Sprintf("%-*s  %s", width, element.Name, element.Owner)
+		if len(notes) > 0 {
+			line += "  # " + strings.Join(notes, ", ")
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	if len(unowned) > 0 {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("# Unowned\n")
+		for _, name := range unowned {
+			sb.WriteString(name + "\n")
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\n%d element(s) owned by %d owner(s), %d unowned\n", len(report)-len(unowned), len(owners), len(unowned)))
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func TestOwnershipReport(t *testing.T) {
+	schema := testSchema()
+	schema.NamespaceAnnotations = &ast.FormatAnnotations{Ownership: &ast.Ownership{Owner: "team-shop", Tier: "2"}}
+	schema.ImportedNamespaceAnnotations = map[string]*ast.FormatAnnotations{
+		"common": {Ownership: &ast.Ownership{SLA: "99%"}},
+	}
+	for _, typ := range schema.Types {
+		if typ.Name == "Card" {
+			typ.Annotations = &ast.FormatAnnotations{Ownership: &ast.Ownership{Owner: "team-payments", SLA: "99.9%"}}
+		}
+	}
+	schema.Services[0].Annotations = &ast.FormatAnnotations{Ownership: &ast.Ownership{Tier: "1"}}
+
+	report := OwnershipReport(schema)
+	byName := make(map[string]OwnedElement)
+	for _, element := range report {
+		byName[element.Name] = element
+	}
+
+	if report[0].Kind != "namespace" || report[0].Name != "common" || report[1].Name != "shop" {
+		t.Errorf("Expected the namespaces first, sorted, got %+v", report[:2])
+	}
+	tests := []OwnedElement{
+		{Kind: "namespace", Name: "shop", Owner: "team-shop", Tier: "2"},
+		{Kind: "type", Name: "shop.Order", Owner: "team-shop", Tier: "2", Inherited: true},
+		{Kind: "type", Name: "shop.Card", Owner: "team-payments", SLA: "99.9%", Tier: "2"},
+		{Kind: "type", Name: "common.Customer", SLA: "99%"},
+		{Kind: "service", Name: "shop.OrderService", Owner: "team-shop", Tier: "1", Inherited: true},
+	}
+	for _, want := range tests {
+		if got := byName[want.Name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+	}
+
+	text := FormatOwnershipReport(report)
+	for _, want := range []string{
+		"shop.Card             team-payments  # sla 99.9%, tier 2\n",
+		"shop.OrderService     team-shop  # tier 1, from namespace\n",
+		"# Unowned\ncommon\ncommon.Customer\n",
+		"7 element(s) owned by 2 owner(s), 2 unowned",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in report:\n%s", want, text)
+		}
+	}
+}
+
+func TestFormatOwnershipReport_Empty(t *testing.T) {
+	if text := FormatOwnershipReport(nil); !strings.Contains(text, "No namespaces") {
+		t.Errorf("Expected an empty report message, got %q", text)
+	}
+}
@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// Exposure is a method whose request or response carries a sensitive field.
+type Exposure struct {
+	Service   string `json:"service"`        // Qualified service name
+	Method    string `json:"method"`         // Method name
+	Direction string `json:"direction"`      // request or response
+	HTTP      string `json:"http,omitempty"` // HTTP method and path, or the webhook name
+}
+
+// SensitiveField is a field marked @sensitive and the methods that expose it.
+type SensitiveField struct {
+	Type      string     `json:"type"`  // Qualified name of the declaring type
+	Field     string     `json:"field"` // Field name
+	Kind      string     `json:"kind"`  // pii, secret, or phi
+	Exposures []Exposure `json:"exposures,omitempty"`
+}
+
+// SensitiveReport lists the fields marked @sensitive, in schema order, with the
+// methods whose requests or responses contain them, directly or through nested
+// types, unions, and base types. Inherited fields are reported on the type that
+// declares them.
+func SensitiveReport(schema *ast.Schema) []SensitiveField {
+	g := Build(schema)
+
+	// reaches caches the nodes reachable from the request or response type of methods
+	reaches := make(map[string]map[string]bool)
+	reachable := func(typeName, namespace string) map[string]bool {
+		id, ok := g.resolve(typeName, namespace)
+		if !ok {
+			return nil
+		}
+		if _, cached := reaches[id]; !cached {
+			reaches[id] = g.Reachable(id)
+		}
+		return reaches[id]
+	}
+
+	var report []SensitiveField
+	for _, typ := range schema.Types {
+		typeID := nodeID(typ.Namespace, typ.Name)
+		for _, field := range typ.Fields {
+			if field.Sensitivity == "" || field.InheritedFrom != "" {
+				continue
+			}
+
+			entry := SensitiveField{Type: typeID, Field: field.Name, Kind: field.Sensitivity}
+			for _, service := range schema.Services {
+				serviceID := nodeID(service.Namespace, service.Name)
+				for _, method := range service.Methods {
+					if reachable(method.InputType, service.Namespace)[typeID] {
+						entry.Exposures = append(entry.Exposures, exposure(serviceID, service, method, "request"))
+					}
+					if reachable(method.OutputType, service.Namespace)[typeID] {
+						entry.Exposures = append(entry.Exposures, exposure(serviceID, service, method, "response"))
+					}
+				}
+			}
+			report = append(report, entry)
+		}
+	}
+	return report
+}
+
+// exposure describes a method that carries a sensitive field in one direction
+func exposure(serviceID string, service *ast.Service, method *ast.Method, direction string) Exposure {
+	e := Exposure{Service: serviceID, Method: method.Name, Direction: direction}
+	switch {
+	case method.IsWebhook():
+		e.HTTP = "webhook " + method.Webhook.Name
+	case method.PathTemplate != "":
+		e.HTTP = strings.ToUpper(method.GetHTTPMethod()) + " " + method.HTTPPath(service)
+	}
+	return e
+}
+
+// FormatSensitiveReport renders a sensitive field report as text, grouped by type.
+func FormatSensitiveReport(report []SensitiveField) string {
+	if len(report) == 0 {
+		return "No fields are marked @sensitive.\n"
+	}
+
+	var sb strings.Builder
+	types := 0
+	exposed := make(map[string]bool)
+	for i, entry := range report {
+		if i == 0 || report[i-1].Type != entry.Type {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(entry.Type + "\n")
+			types++
+		}
+		sb.WriteString(fmt.Sprintf("  %s (%s)\n", entry.Field, entry.Kind))
+		if len(entry.Exposures) == 0 {
+			sb.WriteString("    not exposed by any method\n")
+		}
+		for _, e := range entry.Exposures {
+			method := e.Service + "." + e.Method
+			exposed[method] = true
+			line := fmt.Sprintf("    %-8s %s", e.Direction, method)
+			if e.HTTP != "" {
+				line += " (" + e.HTTP + ")"
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\n%d sensitive field(s) in %d type(s), exposed by %d method(s)\n", len(report), types, len(exposed)))
+	return sb.String()
+}
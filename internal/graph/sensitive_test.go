@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func TestSensitiveReport(t *testing.T) {
+	schema := testSchema()
+	schema.Types = append(schema.Types, &ast.Type{Name: "Secret", Namespace: "shop", Fields: []*ast.Field{
+		{Name: "token", Type: &ast.FieldType{Name: "string"}, Sensitivity: "secret"},
+	}})
+	for _, typ := range schema.Types {
+		switch typ.Name {
+		case "Card":
+			typ.Fields = []*ast.Field{{Name: "number", Type: &ast.FieldType{Name: "string"}, Sensitivity: "pii"}}
+		case "GetOrderRequest":
+			typ.Fields = []*ast.Field{{Name: "id", Type: &ast.FieldType{Name: "string"}}}
+		}
+	}
+	schema.Services[0].Methods[0].HTTPMethod = "GET"
+	schema.Services[0].Methods[0].PathTemplate = "/orders/{id}"
+
+	report := SensitiveReport(schema)
+	if len(report) != 2 {
+		t.Fatalf("Expected 2 sensitive fields, got %+v", report)
+	}
+
+	// Card is reached through the Payment union of Order
+	card := report[0]
+	if card.Type != "shop.Card" || card.Field != "number" || card.Kind != "pii" {
+		t.Errorf("Unexpected entry %+v", card)
+	}
+	expected := []Exposure{
+		{Service: "shop.OrderService", Method: "GetOrder", Direction: "response", HTTP: "GET /orders/{id}"},
+		{Service: "shop.OrderService", Method: "UpdateOrder", Direction: "request"},
+		{Service: "shop.OrderService", Method: "UpdateOrder", Direction: "response"},
+	}
+	if !reflect.DeepEqual(card.Exposures, expected) {
+		t.Errorf("Unexpected exposures %+v", card.Exposures)
+	}
+
+	if secret := report[1]; secret.Type != "shop.Secret" || len(secret.Exposures) != 0 {
+		t.Errorf("Expected an unexposed secret, got %+v", secret)
+	}
+
+	text := FormatSensitiveReport(report)
+	for _, want := range []string{
+		"shop.Card\n  number (pii)\n",
+		"    response shop.OrderService.GetOrder (GET /orders/{id})\n",
+		"shop.Secret\n  token (secret)\n    not exposed by any method\n",
+		"2 sensitive field(s) in 2 type(s), exposed by 2 method(s)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in report:\n%s", want, text)
+		}
+	}
+}
+
+func TestSensitiveReport_Empty(t *testing.T) {
+	if report := SensitiveReport(testSchema()); len(report) != 0 {
+		t.Errorf("Expected no sensitive fields, got %+v", report)
+	}
+	if text := FormatSensitiveReport(nil); text != "No fields are marked @sensitive.\n" {
+		t.Errorf("Unexpected text %q", text)
+	}
+}
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// Trim returns a copy of the schema that only contains the given services and
+// root types, plus every type, enum, and union they reference directly or
+// indirectly. Names may be qualified (namespace.Name) or unqualified when unique.
+func Trim(schema *ast.Schema, services, rootTypes []string) (*ast.Schema, error) {
+	g := Build(schema)
+
+	var roots []string
+	for _, name := range services {
+		node, ok := g.Lookup(name)
+		if !ok || node.Kind != KindService {
+			return nil, fmt.Errorf("unknown service: %s", name)
+		}
+		roots = append(roots, node.ID)
+	}
+	for _, name := range rootTypes {
+		node, ok := g.Lookup(name)
+		if !ok || node.Kind == KindService {
+			return nil, fmt.Errorf("unknown type: %s", name)
+		}
+		roots = append(roots, node.ID)
+	}
+
+	return subset(schema, g.Reachable(roots...)), nil
+}
+
+// Exclude returns a copy of the schema without the given services and types,
+// and without the types, enums, and unions that only they reference. Names may
+// be qualified (namespace.Name) or unqualified when unique. Excluding a type
+// that a remaining element still references is an error.
+func Exclude(schema *ast.Schema, services, types []string) (*ast.Schema, error) {
+	g := Build(schema)
+
+	var excluded []string
+	for _, name := range services {
+		node, ok := g.Lookup(name)
+		if !ok || node.Kind != KindService {
+			return nil, fmt.Errorf("unknown service: %s", name)
+		}
+		excluded = append(excluded, node.ID)
+	}
+	for _, name := range types {
+		node, ok := g.Lookup(name)
+		if !ok || node.Kind == KindService {
+			return nil, fmt.Errorf("unknown type: %s", name)
+		}
+		excluded = append(excluded, node.ID)
+	}
+
+	// Everything the excluded elements use goes too, unless the rest still uses it
+	dropped := g.Reachable(excluded...)
+	var roots []string
+	for _, node := range g.Nodes {
+		if !dropped[node.ID] {
+			roots = append(roots, node.ID)
+		}
+	}
+	keep := g.Reachable(roots...)
+
+	for _, id := range excluded {
+		if !keep[id] {
+			continue
+		}
+		for _, edge := range g.Incoming(id) {
+			if keep[edge.From] {
+				return nil, fmt.Errorf("cannot exclude %s: %s still references it", id, edge.From)
+			}
+		}
+	}
+
+	return subset(schema, keep), nil
+}
+
+// subset returns a copy of the schema with the declarations whose node IDs are kept
+func subset(schema *ast.Schema, keep map[string]bool) *ast.Schema {
+	trimmed := *schema
+	trimmed.Types = nil
+	trimmed.Enums = nil
+	trimmed.Unions = nil
+	trimmed.Services = nil
+
+	for _, typ := range schema.Types {
+		if keep[nodeID(typ.Namespace, typ.Name)] {
+			trimmed.Types = append(trimmed.Types, typ)
+		}
+	}
+	for _, enum := range schema.Enums {
+		if keep[nodeID(enum.Namespace, enum.Name)] {
+			trimmed.Enums = append(trimmed.Enums, enum)
+		}
+	}
+	for _, union := range schema.Unions {
+		if keep[nodeID(union.Namespace, union.Name)] {
+			trimmed.Unions = append(trimmed.Unions, union)
+		}
+	}
+	for _, service := range schema.Services {
+		if keep[nodeID(service.Namespace, service.Name)] {
+			trimmed.Services = append(trimmed.Services, service)
+		}
+	}
+
+	if schema.TypeRegistry != nil {
+		trimmed.TypeRegistry = ast.NewTypeRegistry()
+		for _, typ := range trimmed.Types {
+			trimmed.TypeRegistry.RegisterType(typ)
+		}
+		for _, enum := range trimmed.Enums {
+			trimmed.TypeRegistry.RegisterEnum(enum)
+		}
+		for _, union := range trimmed.Unions {
+			trimmed.TypeRegistry.RegisterUnion(union)
+		}
+	}
+
+	return &trimmed
+}
@@ -0,0 +1,137 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func trimTestSchema() *ast.Schema {
+	schema := testSchema()
+	schema.TypeRegistry = ast.NewTypeRegistry()
+	schema.Types = append(schema.Types,
+		&ast.Type{Name: "Invoice", Namespace: "shop", Fields: []*ast.Field{
+			{Name: "order", Type: &ast.FieldType{Name: "Order"}},
+		}},
+		&ast.Type{Name: "GetInvoiceRequest", Namespace: "shop"},
+	)
+	schema.Enums = append(schema.Enums, &ast.Enum{Name: "Currency", Namespace: "shop"})
+	schema.Services = append(schema.Services, &ast.Service{
+		Name:      "BillingService",
+		Namespace: "shop",
+		Methods: []*ast.Method{
+			{Name: "GetInvoice", InputType: "GetInvoiceRequest", OutputType: "Invoice"},
+		},
+	})
+	return schema
+}
+
+func names(schema *ast.Schema) map[string]bool {
+	result := make(map[string]bool)
+	for _, typ := range schema.Types {
+		result[typ.Name] = true
+	}
+	for _, enum := range schema.Enums {
+		result[enum.Name] = true
+	}
+	for _, union := range schema.Unions {
+		result[union.Name] = true
+	}
+	for _, service := range schema.Services {
+		result[service.Name] = true
+	}
+	return result
+}
+
+func TestTrim_OnlyService(t *testing.T) {
+	schema := trimTestSchema()
+
+	trimmed, err := Trim(schema, []string{"OrderService"}, nil)
+	if err != nil {
+		t.Fatalf("Trim failed: %v", err)
+	}
+
+	kept := names(trimmed)
+	for _, name := range []string{"OrderService", "Order", "GetOrderRequest", "Customer", "Address", "LineItem", "Status", "Payment", "Card"} {
+		if !kept[name] {
+			t.Errorf("Expected %s to be kept", name)
+		}
+	}
+	for _, name := range []string{"BillingService", "Invoice", "GetInvoiceRequest", "Currency"} {
+		if kept[name] {
+			t.Errorf("Expected %s to be pruned", name)
+		}
+	}
+
+	if _, ok := trimmed.TypeRegistry.Types["shop.Invoice"]; ok {
+		t.Error("Expected pruned type to be removed from the type registry")
+	}
+	if len(schema.Services) != 2 {
+		t.Error("Expected original schema to be left unchanged")
+	}
+}
+
+func TestTrim_RootType(t *testing.T) {
+	trimmed, err := Trim(trimTestSchema(), nil, []string{"shop.LineItem", "Currency"})
+	if err != nil {
+		t.Fatalf("Trim failed: %v", err)
+	}
+
+	kept := names(trimmed)
+	if len(kept) != 3 || !kept["LineItem"] || !kept["Address"] || !kept["Currency"] {
+		t.Errorf("Expected only LineItem, Address, and Currency, got %v", kept)
+	}
+}
+
+func TestTrim_UnknownNames(t *testing.T) {
+	if _, err := Trim(trimTestSchema(), []string{"Order"}, nil); err == nil {
+		t.Error("Expected error when a type is passed as service")
+	}
+	if _, err := Trim(trimTestSchema(), nil, []string{"Missing"}); err == nil {
+		t.Error("Expected error for unknown root type")
+	}
+}
+
+func TestExclude(t *testing.T) {
+	schema := trimTestSchema()
+	schema.Types = append(schema.Types, &ast.Type{Name: "Standalone", Namespace: "shop"})
+
+	excluded, err := Exclude(schema, []string{"BillingService"}, nil)
+	if err != nil {
+		t.Fatalf("Exclude failed: %v", err)
+	}
+
+	kept := names(excluded)
+	for _, name := range []string{"OrderService", "Order", "Customer", "Standalone", "Currency"} {
+		if !kept[name] {
+			t.Errorf("Expected %s to be kept", name)
+		}
+	}
+	for _, name := range []string{"BillingService", "Invoice", "GetInvoiceRequest"} {
+		if kept[name] {
+			t.Errorf("Expected %s to be excluded", name)
+		}
+	}
+	if len(schema.Services) != 2 {
+		t.Error("Expected original schema to be left unchanged")
+	}
+}
+
+func TestExclude_ReferencedType(t *testing.T) {
+	excluded, err := Exclude(trimTestSchema(), nil, []string{"Currency"})
+	if err != nil {
+		t.Fatalf("Exclude failed: %v", err)
+	}
+	if names(excluded)["Currency"] {
+		t.Error("Expected Currency to be excluded")
+	}
+
+	_, err = Exclude(trimTestSchema(), nil, []string{"Address"})
+	if err == nil || !strings.Contains(err.Error(), "cannot exclude shop.Address") {
+		t.Errorf("Expected an error for a referenced type, got %v", err)
+	}
+	if _, err := Exclude(trimTestSchema(), []string{"Missing"}, nil); err == nil {
+		t.Error("Expected error for unknown service")
+	}
+}
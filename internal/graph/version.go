@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// AtVersion returns a copy of the schema with only the types, enums, unions,
+// services, methods, and fields that are part of an API version: those whose
+// @since is the version or older, and whose @until is the version or newer.
+// Fields keep the Protobuf numbers they have in the whole schema, so every
+// version is wire compatible with the others. An element of the version that
+// references a type, enum, or union outside of it is an error.
+func AtVersion(schema *ast.Schema, version string) (*ast.Schema, error) {
+	// The candidate keeps every declaration, so references to the ones outside
+	// the version still resolve and can be reported
+	candidate := *schema
+	candidate.Types = nil
+	candidate.Services = nil
+	dropped := make(map[string]bool)
+
+	for _, typ := range schema.Types {
+		if !annotatedIn(typ.Annotations, version) {
+			dropped[nodeID(typ.Namespace, typ.Name)] = true
+			candidate.Types = append(candidate.Types, typ)
+			continue
+		}
+		versioned := *typ
+		versioned.Fields = versionedFields(typ, version)
+		candidate.Types = append(candidate.Types, &versioned)
+	}
+	for _, enum := range schema.Enums {
+		if !annotatedIn(enum.Annotations, version) {
+			dropped[nodeID(enum.Namespace, enum.Name)] = true
+		}
+	}
+	for _, union := range schema.Unions {
+		if !annotatedIn(union.Annotations, version) {
+			dropped[nodeID(union.Namespace, union.Name)] = true
+		}
+	}
+	for _, service := range schema.Services {
+		if !annotatedIn(service.Annotations, version) {
+			dropped[nodeID(service.Namespace, service.Name)] = true
+			candidate.Services = append(candidate.Services, service)
+			continue
+		}
+		versioned := *service
+		versioned.Methods = nil
+		for _, method := range service.Methods {
+			if annotatedIn(method.Annotations, version) {
+				versioned.Methods = append(versioned.Methods, method)
+			}
+		}
+		candidate.Services = append(candidate.Services, &versioned)
+	}
+
+	g := Build(&candidate)
+	keep := make(map[string]bool)
+	for _, node := range g.Nodes {
+		if !dropped[node.ID] {
+			keep[node.ID] = true
+		}
+	}
+	for _, node := range g.Nodes {
+		if keep[node.ID] {
+			continue
+		}
+		for _, edge := range g.Incoming(node.ID) {
+			if !keep[edge.From] {
+				continue
+			}
+			from := edge.From
+			if len(edge.Labels) > 0 {
+				from += " (" + strings.Join(edge.Labels, ", ") + ")"
+			}
+			return nil, fmt.Errorf("%s is not part of version %s, but %s references it", node.ID, version, from)
+		}
+	}
+
+	return subset(&candidate, keep), nil
+}
+
+// annotatedIn reports whether the @since and @until of a declaration or method
+// include a version
+func annotatedIn(annotations *ast.FormatAnnotations, version string) bool {
+	if annotations == nil {
+		return true
+	}
+	return ast.ActiveIn(annotations.Since, annotations.Until, version)
+}
+
+// versionedFields returns copies of the fields of a type that are part of a
+// version, numbered as the Protobuf generator numbers the fields of the whole
+// type
+func versionedFields(typ *ast.Type, version string) []*ast.Field {
+	numbers := make(map[*ast.Field]int)
+	next := 1
+	for _, field := range typ.Fields {
+		if !field.ShouldIncludeInGenerator("proto") || len(field.Arguments) > 0 {
+			continue
+		}
+		if field.HasNumber {
+			next = max(next, field.Number+1)
+			continue
+		}
+		numbers[field] = next
+		next++
+	}
+
+	var fields []*ast.Field
+	for _, field := range typ.Fields {
+		if !ast.ActiveIn(field.Since, field.Until, version) {
+			continue
+		}
+		versioned := *field
+		if number, ok := numbers[field]; ok {
+			versioned.Number = number
+			versioned.HasNumber = true
+		}
+		fields = append(fields, &versioned)
+	}
+	return fields
+}
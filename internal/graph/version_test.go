@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func versionTestSchema() *ast.Schema {
+	return &ast.Schema{
+		TypeRegistry: ast.NewTypeRegistry(),
+		Types: []*ast.Type{
+			{Name: "User", Namespace: "api", Fields: []*ast.Field{
+				{Name: "id", Type: &ast.FieldType{Name: "string"}},
+				{Name: "name", Type: &ast.FieldType{Name: "string"}, Until: "v1"},
+				{Name: "displayName", Type: &ast.FieldType{Name: "string"}, Since: "v2"},
+				{Name: "profile", Type: &ast.FieldType{Name: "Profile"}, Since: "v2"},
+				{Name: "email", Type: &ast.FieldType{Name: "string"}, Number: 10, HasNumber: true},
+			}},
+			{Name: "Profile", Namespace: "api", Annotations: &ast.FormatAnnotations{Since: "v2"}},
+			{Name: "GetUserRequest", Namespace: "api"},
+		},
+		Enums: []*ast.Enum{
+			{Name: "LegacyRole", Namespace: "api", Annotations: &ast.FormatAnnotations{Until: "v1"}},
+		},
+		Services: []*ast.Service{
+			{Name: "UserService", Namespace: "api", Methods: []*ast.Method{
+				{Name: "GetUser", InputType: "GetUserRequest", OutputType: "User"},
+				{Name: "SearchUsers", InputType: "GetUserRequest", OutputType: "User", Annotations: &ast.FormatAnnotations{Since: "v2"}},
+			}},
+		},
+	}
+}
+
+func TestAtVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		kept    []string
+		dropped []string
+		fields  string
+		methods string
+	}{
+		{
+			version: "v1",
+			kept:    []string{"User", "GetUserRequest", "LegacyRole", "UserService"},
+			dropped: []string{"Profile"},
+			fields:  "id=1,name=2,email=10",
+			methods: "GetUser",
+		},
+		{
+			version: "v2",
+			kept:    []string{"User", "Profile", "GetUserRequest", "UserService"},
+			dropped: []string{"LegacyRole"},
+			fields:  "id=1,displayName=3,profile=4,email=10",
+			methods: "GetUser,SearchUsers",
+		},
+		{
+			version: "2.1.0",
+			kept:    []string{"User", "Profile"},
+			dropped: []string{"LegacyRole"},
+			fields:  "id=1,displayName=3,profile=4,email=10",
+			methods: "GetUser,SearchUsers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			schema := versionTestSchema()
+			versioned, err := AtVersion(schema, tt.version)
+			if err != nil {
+				t.Fatalf("AtVersion failed: %v", err)
+			}
+
+			kept := names(versioned)
+			for _, name := range tt.kept {
+				if !kept[name] {
+					t.Errorf("Expected %s to be kept", name)
+				}
+			}
+			for _, name := range tt.dropped {
+				if kept[name] {
+					t.Errorf("Expected %s to be dropped", name)
+				}
+			}
+
+			var fields []string
+			for _, field := range versioned.Types[0].Fields {
+				fields = append(fields, field.Name+"="+strconv.Itoa(field.Number))
+			}
+			if got := strings.Join(fields, ","); got != tt.fields {
+				t.Errorf("Expected fields %s, got %s", tt.fields, got)
+			}
+
+			var methods []string
+			for _, method := range versioned.Services[0].Methods {
+				methods = append(methods, method.Name)
+			}
+			if got := strings.Join(methods, ","); got != tt.methods {
+				t.Errorf("Expected methods %s, got %s", tt.methods, got)
+			}
+
+			// The schema itself is left as it is
+			if len(schema.Types[0].Fields) != 5 || schema.Types[0].Fields[0].HasNumber {
+				t.Error("Expected the schema to be unchanged")
+			}
+		})
+	}
+}
+
+func TestAtVersion_DanglingReference(t *testing.T) {
+	schema := versionTestSchema()
+	schema.Types[0].Fields[3].Since = ""
+
+	_, err := AtVersion(schema, "v1")
+	if err == nil {
+		t.Fatal("Expected an error for a field referencing a type of a later version")
+	}
+	expected := "api.Profile is not part of version v1, but api.User (profile) references it"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
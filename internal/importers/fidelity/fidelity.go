@@ -0,0 +1,182 @@
+// Package fidelity describes what a schema loses on a round trip through TypeMUX:
+// it is imported, generated again in its own format and compared with the original.
+//
+// The importer packages compare their parsed schemas into a Report. Elements of
+// the original schema that are missing or different afterwards are losses;
+// elements that only exist afterwards, such as synthesized request types, are
+// reported as additions but do not lower the fidelity.
+package fidelity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of a difference between the original and the regenerated schema.
+type Kind string
+
+const (
+	// Removed means an element of the original schema is missing afterwards.
+	Removed Kind = "removed"
+	// Changed means an attribute of an element has a different value afterwards.
+	Changed Kind = "changed"
+	// Added means an element only exists in the regenerated schema.
+	Added Kind = "added"
+)
+
+// Difference is a single difference between the original and the regenerated schema.
+type Difference struct {
+	Kind   Kind   `json:"kind"`
+	Path   string `json:"path"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Report collects the differences of a round trip.
+type Report struct {
+	// Compared counts the elements and attributes of the original schema that were compared
+	Compared    int          `json:"compared"`
+	Differences []Difference `json:"differences"`
+}
+
+// NewReport creates an empty report.
+func NewReport() *Report {
+	return &Report{Differences: []Difference{}}
+}
+
+// Attribute compares an attribute of an element present in both schemas.
+// Attributes that are empty in the original schema are not compared.
+func (r *Report) Attribute(path, before, after string) {
+	if before == "" {
+		return
+	}
+	r.Compared++
+	if before != after {
+		r.Differences = append(r.Differences, Difference{Kind: Changed, Path: path, Before: before, After: after})
+	}
+}
+
+// Match compares two lists of elements by key. Each element of the original
+// list counts as compared; elements found in both lists are passed to compare.
+func Match[T any](r *Report, path string, before, after []T, key func(T) string, compare func(path string, before, after T)) {
+	afterByKey := make(map[string]T, len(after))
+	for _, element := range after {
+		afterByKey[key(element)] = element
+	}
+
+	seen := make(map[string]bool, len(before))
+	for _, element := range before {
+		k := key(element)
+		seen[k] = true
+		r.Compared++
+		other, ok := afterByKey[k]
+		if !ok {
+			r.Differences = append(r.Differences, Difference{Kind: Removed, Path: join(path, k)})
+			continue
+		}
+		if compare != nil {
+			compare(join(path, k), element, other)
+		}
+	}
+
+	for _, element := range after {
+		if k := key(element); !seen[k] {
+			seen[k] = true
+			r.Differences = append(r.Differences, Difference{Kind: Added, Path: join(path, k)})
+		}
+	}
+}
+
+// join appends a name to a path
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// Lost returns the removed and changed elements, sorted by path.
+func (r *Report) Lost() []Difference {
+	var lost []Difference
+	for _, difference := range r.Differences {
+		if difference.Kind != Added {
+			lost = append(lost, difference)
+		}
+	}
+	sortDifferences(lost)
+	return lost
+}
+
+// AddedElements returns the elements that only exist in the regenerated schema, sorted by path.
+func (r *Report) AddedElements() []Difference {
+	var added []Difference
+	for _, difference := range r.Differences {
+		if difference.Kind == Added {
+			added = append(added, difference)
+		}
+	}
+	sortDifferences(added)
+	return added
+}
+
+// Fidelity returns the share of compared elements and attributes that came
+// through unchanged, between 0 and 1.
+func (r *Report) Fidelity() float64 {
+	if r.Compared == 0 {
+		return 1
+	}
+	return float64(r.Compared-len(r.Lost())) / float64(r.Compared)
+}
+
+// sortDifferences sorts differences by path, then by kind
+func sortDifferences(differences []Difference) {
+	sort.SliceStable(differences, func(i, j int) bool {
+		if differences[i].Path != differences[j].Path {
+			return differences[i].Path < differences[j].Path
+		}
+		return differences[i].Kind < differences[j].Kind
+	})
+}
+
+// Format renders a report as text.
+func Format(r *Report) string {
+	var sb strings.Builder
+
+	lost := r.Lost()
+	fmt.Fprintf(&sb, "Fidelity: %.1f%% (%d of %d compared elements preserved)\n",
+		r.Fidelity()*100, r.Compared-len(lost), r.Compared)
+
+	if len(lost) > 0 {
+		sb.WriteString("\nLost:\n")
+		for _, difference := range lost {
+			writeDifference(&sb, difference)
+		}
+	}
+
+	if added := r.AddedElements(); len(added) > 0 {
+		sb.WriteString("\nAdded:\n")
+		for _, difference := range added {
+			writeDifference(&sb, difference)
+		}
+	}
+
+	return sb.String()
+}
+
+// writeDifference writes a difference as a line of the text report
+func writeDifference(sb *strings.Builder, difference Difference) {
+	fmt.Fprintf(sb, "  %-8s %s", difference.Kind, difference.Path)
+	if difference.Kind == Changed {
+		fmt.Fprintf(sb, ": %s -> %s", quote(difference.Before), quote(difference.After))
+	}
+	sb.WriteString("\n")
+}
+
+// quote shows an empty value as such
+func quote(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
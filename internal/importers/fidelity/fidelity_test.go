@@ -0,0 +1,81 @@
+package fidelity
+
+import (
+	"strings"
+	"testing"
+)
+
+type element struct {
+	name  string
+	value string
+}
+
+func TestMatch(t *testing.T) {
+	report := NewReport()
+	before := []element{{"a", "1"}, {"b", "2"}, {"c", "3"}}
+	after := []element{{"a", "1"}, {"c", "4"}, {"d", "5"}}
+
+	Match(report, "root", before, after,
+		func(e element) string { return e.name },
+		func(path string, b, a element) {
+			report.Attribute(path+".value", b.value, a.value)
+		})
+
+	// Three elements and the values of the two matched ones
+	if report.Compared != 5 {
+		t.Errorf("Expected 5 compared elements, got %d", report.Compared)
+	}
+
+	lost := report.Lost()
+	if len(lost) != 2 {
+		t.Fatalf("Expected 2 lost elements, got %v", lost)
+	}
+	if lost[0] != (Difference{Kind: Removed, Path: "root.b"}) {
+		t.Errorf("Expected root.b to be removed, got %v", lost[0])
+	}
+	if lost[1] != (Difference{Kind: Changed, Path: "root.c.value", Before: "3", After: "4"}) {
+		t.Errorf("Expected root.c.value to change, got %v", lost[1])
+	}
+
+	added := report.AddedElements()
+	if len(added) != 1 || added[0].Path != "root.d" {
+		t.Errorf("Expected root.d to be added, got %v", added)
+	}
+
+	if fidelity := report.Fidelity(); fidelity != 0.6 {
+		t.Errorf("Expected fidelity 0.6, got %v", fidelity)
+	}
+}
+
+func TestAttribute_SkipsEmptyOriginal(t *testing.T) {
+	report := NewReport()
+	report.Attribute("a.description", "", "added later")
+
+	if report.Compared != 0 || len(report.Differences) != 0 {
+		t.Errorf("Expected empty attributes to be skipped, got %+v", report)
+	}
+	if report.Fidelity() != 1 {
+		t.Errorf("Expected fidelity 1 for an empty report, got %v", report.Fidelity())
+	}
+}
+
+func TestFormat(t *testing.T) {
+	report := NewReport()
+	Match(report, "", []string{"User", "Order"}, []string{"User", "Empty"},
+		func(name string) string { return name },
+		func(path, _, _ string) {
+			report.Attribute(path+".comment", "A user", "")
+		})
+
+	text := Format(report)
+	expected := []string{
+		"Fidelity: 33.3% (1 of 3 compared elements preserved)",
+		"Lost:\n  removed  Order\n  changed  User.comment: A user -> (none)\n",
+		"Added:\n  added    Empty\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, text)
+		}
+	}
+}
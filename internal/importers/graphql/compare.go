@@ -0,0 +1,85 @@
+package graphql
+
+import (
+	"github.com/rasmartins/typemux/internal/importers/fidelity"
+)
+
+// Compare compares a GraphQL schema with the schema generated from its import,
+// element by element: object, input, interface, enum, union and scalar types,
+// and the fields of the Query, Mutation and Subscription types.
+func Compare(before, after *GraphQLSchema) *fidelity.Report {
+	report := fidelity.NewReport()
+
+	fidelity.Match(report, "type", before.Types, after.Types,
+		func(t *GraphQLType) string { return t.Name },
+		func(path string, b, a *GraphQLType) {
+			report.Attribute(path+".description", b.Description, a.Description)
+			compareFields(report, path, b.Fields, a.Fields)
+		})
+
+	fidelity.Match(report, "input", before.Inputs, after.Inputs,
+		func(i *GraphQLInput) string { return i.Name },
+		func(path string, b, a *GraphQLInput) {
+			report.Attribute(path+".description", b.Description, a.Description)
+			compareFields(report, path, b.Fields, a.Fields)
+		})
+
+	fidelity.Match(report, "interface", before.Interfaces, after.Interfaces,
+		func(i *GraphQLInterface) string { return i.Name },
+		func(path string, b, a *GraphQLInterface) {
+			report.Attribute(path+".description", b.Description, a.Description)
+			compareFields(report, path, b.Fields, a.Fields)
+		})
+
+	fidelity.Match(report, "enum", before.Enums, after.Enums,
+		func(e *GraphQLEnum) string { return e.Name },
+		func(path string, b, a *GraphQLEnum) {
+			report.Attribute(path+".description", b.Description, a.Description)
+			fidelity.Match(report, path, b.Values, a.Values,
+				func(v *GraphQLEnumValue) string { return v.Name },
+				func(path string, b, a *GraphQLEnumValue) {
+					report.Attribute(path+".description", b.Description, a.Description)
+					report.Attribute(path+".deprecated", deprecationReason(b.Directives), deprecationReason(a.Directives))
+				})
+		})
+
+	fidelity.Match(report, "union", before.Unions, after.Unions,
+		func(u *GraphQLUnion) string { return u.Name },
+		func(path string, b, a *GraphQLUnion) {
+			fidelity.Match(report, path, b.Types, a.Types, func(name string) string { return name }, nil)
+		})
+
+	fidelity.Match(report, "scalar", before.Scalars, after.Scalars,
+		func(s *GraphQLScalar) string { return s.Name }, nil)
+
+	compareFields(report, "Query", before.Queries, after.Queries)
+	compareFields(report, "Mutation", before.Mutations, after.Mutations)
+	compareFields(report, "Subscription", before.Subscriptions, after.Subscriptions)
+
+	return report
+}
+
+// compareFields compares fields with their types, arguments and deprecations
+func compareFields(report *fidelity.Report, path string, before, after []*GraphQLField) {
+	fidelity.Match(report, path, before, after,
+		func(f *GraphQLField) string { return f.Name },
+		func(path string, b, a *GraphQLField) {
+			report.Attribute(path+".type", b.Type, a.Type)
+			report.Attribute(path+".default", b.DefaultValue, a.DefaultValue)
+			report.Attribute(path+".description", b.Description, a.Description)
+			report.Attribute(path+".deprecated", deprecationReason(b.Directives), deprecationReason(a.Directives))
+			fidelity.Match(report, path, b.Arguments, a.Arguments,
+				func(arg *GraphQLArgument) string { return "(" + arg.Name + ")" },
+				func(path string, b, a *GraphQLArgument) {
+					report.Attribute(path+".type", b.Type, a.Type)
+					report.Attribute(path+".default", b.DefaultValue, a.DefaultValue)
+					report.Attribute(path+".description", b.Description, a.Description)
+				})
+		})
+}
+
+// deprecationReason returns the reason of a @deprecated directive, or nothing if there is none
+func deprecationReason(directives []*GraphQLDirective) string {
+	reason, _ := deprecation(directives)
+	return reason
+}
@@ -0,0 +1,62 @@
+package graphql
+
+import (
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	before, err := NewParser(`"A user"
+type User {
+  id: ID!
+  name: String @deprecated(reason: "Use fullName")
+}
+
+type Query {
+  user(id: ID!): User
+}
+`).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := NewParser(`"A user"
+type User {
+  id: String
+  name: String @deprecated(reason: "Use fullName")
+}
+
+input UserRequest {
+  id: String
+}
+
+type Query {
+  user(input: UserRequest): User
+}
+`).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := Compare(before, after)
+
+	lost := make(map[string]bool)
+	for _, difference := range report.Lost() {
+		lost[difference.Path] = true
+	}
+	for _, path := range []string{"type.User.id.type", "Query.user.(id)"} {
+		if !lost[path] {
+			t.Errorf("Expected %s to be lost, got %v", path, report.Lost())
+		}
+	}
+	if len(lost) != 2 {
+		t.Errorf("Expected 2 lost elements, got %v", report.Lost())
+	}
+
+	added := make(map[string]bool)
+	for _, difference := range report.AddedElements() {
+		added[difference.Path] = true
+	}
+	if !added["input.UserRequest"] || !added["Query.user.(input)"] {
+		t.Errorf("Expected the request input to be added, got %v", report.AddedElements())
+	}
+}
@@ -2,15 +2,17 @@ package graphql
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
 type Converter struct {
-	schema *GraphQLSchema
+	schema    *GraphQLSchema
+	typeNames map[string]bool
 }
 
 func NewConverter() *Converter {
-	return &Converter{}
+	return &Converter{typeNames: make(map[string]bool)}
 }
 
 // isReservedKeyword checks if a field name is a TypeMUX reserved keyword
@@ -39,6 +41,18 @@ func escapeFieldName(name string) string {
 
 func (c *Converter) Convert(schema *GraphQLSchema) string {
 	c.schema = schema
+	for _, typ := range schema.Types {
+		c.typeNames[typ.Name] = true
+	}
+	for _, input := range schema.Inputs {
+		c.typeNames[input.Name] = true
+	}
+	for _, enum := range schema.Enums {
+		c.typeNames[enum.Name] = true
+	}
+	for _, union := range schema.Unions {
+		c.typeNames[union.Name] = true
+	}
 
 	var sb strings.Builder
 
@@ -70,9 +84,15 @@ func (c *Converter) Convert(schema *GraphQLSchema) string {
 		sb.WriteString("\n\n")
 	}
 
-	// Convert queries and mutations to a service
-	if len(schema.Queries) > 0 || len(schema.Mutations) > 0 {
-		c.writeService(&sb, schema)
+	// Convert queries, mutations and subscriptions to a service, preceded by
+	// the request and response types its methods need
+	if len(schema.Queries) > 0 || len(schema.Mutations) > 0 || len(schema.Subscriptions) > 0 {
+		methods, types := c.synthesizeMethods(schema)
+		for _, typ := range types {
+			c.writeSynthType(&sb, typ)
+			sb.WriteString("\n\n")
+		}
+		c.writeService(&sb, methods)
 	}
 
 	return sb.String()
@@ -93,11 +113,7 @@ func (c *Converter) writeEnum(sb *strings.Builder, enum *GraphQLEnum) {
 
 	for i, value := range enum.Values {
 		if value.Description != "" {
-			// Indent description
-			lines := strings.Split(value.Description, "\n")
-			for _, line := range lines {
-				sb.WriteString(fmt.Sprintf("  // %s\n", line))
-			}
+			writeDocComment(sb, "  ", value.Description)
 		}
 		sb.WriteString(fmt.Sprintf("  %s = %d", value.Name, i))
 		if i < len(enum.Values)-1 {
@@ -113,8 +129,11 @@ func (c *Converter) writeScalar(sb *strings.Builder, scalar *GraphQLScalar) {
 	typemuxType := c.mapScalarType(scalar.Name)
 	if typemuxType == scalar.Name {
 		// Custom scalar, create a type alias
-		if scalar.Description != "" {
-			c.writeDocumentation(sb, scalar.Description)
+		// Scalars are not declared, so their description stays a plain comment
+		for _, line := range strings.Split(scalar.Description, "\n") {
+			if line != "" {
+				sb.WriteString(fmt.Sprintf("// %s\n", line))
+			}
 		}
 		// For now, map custom scalars to string
 		sb.WriteString(fmt.Sprintf("// Custom scalar %s (mapped to string)\n", scalar.Name))
@@ -136,11 +155,7 @@ func (c *Converter) writeInput(sb *strings.Builder, input *GraphQLInput) {
 
 	for i, field := range input.Fields {
 		if field.Description != "" {
-			// Indent description
-			lines := strings.Split(field.Description, "\n")
-			for _, line := range lines {
-				sb.WriteString(fmt.Sprintf("  // %s\n", line))
-			}
+			writeDocComment(sb, "  ", field.Description)
 		}
 
 		typemuxType := c.convertGraphQLType(field.Type)
@@ -151,6 +166,10 @@ func (c *Converter) writeInput(sb *strings.Builder, input *GraphQLInput) {
 			sb.WriteString(fmt.Sprintf(" @graphql.default(%s)", field.DefaultValue))
 		}
 
+		if reason, ok := deprecation(field.Directives); ok {
+			sb.WriteString(fmt.Sprintf(" @deprecated(%q)", reason))
+		}
+
 		sb.WriteString("\n")
 	}
 
@@ -172,11 +191,7 @@ func (c *Converter) writeType(sb *strings.Builder, typ *GraphQLType) {
 
 	for i, field := range typ.Fields {
 		if field.Description != "" {
-			// Indent description
-			lines := strings.Split(field.Description, "\n")
-			for _, line := range lines {
-				sb.WriteString(fmt.Sprintf("  // %s\n", line))
-			}
+			writeDocComment(sb, "  ", field.Description)
 		}
 
 		typemuxType := c.convertGraphQLType(field.Type)
@@ -187,81 +202,240 @@ func (c *Converter) writeType(sb *strings.Builder, typ *GraphQLType) {
 			sb.WriteString(fmt.Sprintf(" @graphql.default(%s)", field.DefaultValue))
 		}
 
+		if reason, ok := deprecation(field.Directives); ok {
+			sb.WriteString(fmt.Sprintf(" @deprecated(%q)", reason))
+		}
+
 		sb.WriteString("\n")
 	}
 
 	sb.WriteString("}")
 }
 
-func (c *Converter) writeService(sb *strings.Builder, schema *GraphQLSchema) {
-	if len(schema.Queries) == 0 && len(schema.Mutations) == 0 && len(schema.Subscriptions) == 0 {
-		return
+// synthField is a field of a request or response type synthesized from a root field
+type synthField struct {
+	name         string
+	typ          string
+	doc          string
+	required     bool
+	defaultValue string
+	deprecation  string
+}
+
+// synthType is a request or response type synthesized from a root field
+type synthType struct {
+	name   string
+	fields []synthField
+}
+
+// rpcMethod is a service method synthesized from a root field
+type rpcMethod struct {
+	name        string
+	doc         string
+	input       string
+	output      string
+	stream      bool
+	annotations []string
+}
+
+// synthesizeMethods converts the fields of the Query, Mutation and Subscription
+// types to methods, along with the request and response types they need
+func (c *Converter) synthesizeMethods(schema *GraphQLSchema) ([]*rpcMethod, []*synthType) {
+	var methods []*rpcMethod
+	var types []*synthType
+
+	operations := []struct {
+		name   string
+		fields []*GraphQLField
+	}{
+		{"query", schema.Queries},
+		{"mutation", schema.Mutations},
+		{"subscription", schema.Subscriptions},
+	}
+	for _, operation := range operations {
+		for _, field := range operation.fields {
+			method, synthesized := c.synthesizeMethod(field, operation.name)
+			methods = append(methods, method)
+			types = append(types, synthesized...)
+		}
 	}
 
-	sb.WriteString("service GraphQLService {\n")
+	return methods, types
+}
 
-	// Write queries
-	for _, query := range schema.Queries {
-		c.writeMethod(sb, query, "query", false)
+// synthesizeMethod converts a root field to a method. The arguments are folded
+// into a request type, unless the only argument is an input object named input,
+// which is how the GraphQL generator writes methods. Lists and scalars are
+// wrapped in a response type, since methods return named types.
+func (c *Converter) synthesizeMethod(field *GraphQLField, operation string) (*rpcMethod, []*synthType) {
+	method := &rpcMethod{
+		name:   strings.Title(field.Name),
+		doc:    field.Description,
+		stream: operation == "subscription",
+	}
+	var types []*synthType
+
+	// Input
+	if len(field.Arguments) == 1 && field.Arguments[0].Name == "input" && c.isInput(UnwrapType(field.Arguments[0].Type)) && !IsList(field.Arguments[0].Type) {
+		method.input = UnwrapType(field.Arguments[0].Type)
+	} else if len(field.Arguments) > 0 {
+		request := &synthType{name: c.uniqueTypeName(method.name + "Request")}
+		for _, arg := range field.Arguments {
+			reason, deprecated := deprecation(arg.Directives)
+			if !deprecated {
+				reason = ""
+			}
+			request.fields = append(request.fields, synthField{
+				name:         escapeFieldName(arg.Name),
+				typ:          c.convertGraphQLType(arg.Type),
+				doc:          arg.Description,
+				required:     IsNonNull(arg.Type),
+				defaultValue: defaultAnnotationValue(arg.DefaultValue),
+				deprecation:  reason,
+			})
+		}
+		method.input = request.name
+		types = append(types, request)
 	}
 
-	// Write mutations
-	for _, mutation := range schema.Mutations {
-		c.writeMethod(sb, mutation, "mutation", false)
+	// Output
+	output := UnwrapType(field.Type)
+	if IsList(field.Type) || c.isScalar(output) {
+		name := "value"
+		if IsList(field.Type) {
+			name = "items"
+		}
+		response := &synthType{
+			name:   c.uniqueTypeName(method.name + "Response"),
+			fields: []synthField{{name: name, typ: c.convertGraphQLType(field.Type)}},
+		}
+		method.output = response.name
+		types = append(types, response)
+	} else {
+		method.output = output
 	}
 
-	// Write subscriptions (as streaming RPCs)
-	for _, subscription := range schema.Subscriptions {
-		c.writeMethod(sb, subscription, "subscription", true)
+	method.annotations = append(method.annotations, fmt.Sprintf("@graphql(%s)", operation))
+	if reason, ok := deprecation(field.Directives); ok {
+		method.annotations = append(method.annotations, fmt.Sprintf("@deprecated(%q)", reason))
 	}
 
-	sb.WriteString("}")
+	return method, types
 }
 
-func (c *Converter) writeMethod(sb *strings.Builder, field *GraphQLField, methodType string, isStreaming bool) {
-	if field.Description != "" {
-		// Indent description
-		lines := strings.Split(field.Description, "\n")
-		for _, line := range lines {
-			sb.WriteString(fmt.Sprintf("  // %s\n", line))
+// isInput reports whether name is an input object of the schema
+func (c *Converter) isInput(name string) bool {
+	for _, input := range c.schema.Inputs {
+		if input.Name == name {
+			return true
 		}
 	}
+	return false
+}
 
-	// For GraphQL queries/mutations/subscriptions, we need to create request/response types
-	// For now, we'll use the field arguments as input and the return type as output
+// isScalar reports whether name maps to a TypeMUX primitive rather than a named type
+func (c *Converter) isScalar(name string) bool {
+	switch c.mapType(name) {
+	case "string", "int32", "int64", "float", "double", "bool", "timestamp", "bytes":
+		return true
+	}
+	for _, scalar := range c.schema.Scalars {
+		if scalar.Name == name {
+			return true
+		}
+	}
+	return false
+}
 
-	inputType := "Empty"
-	if len(field.Arguments) > 0 {
-		// We'd ideally generate an input type, but for simplicity, we'll inline
-		inputType = fmt.Sprintf("%sRequest", strings.Title(field.Name))
+// uniqueTypeName returns name, or name with a numeric suffix if a type of that name already exists
+func (c *Converter) uniqueTypeName(name string) string {
+	unique := name
+	for i := 2; c.typeNames[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
 	}
+	c.typeNames[unique] = true
+	return unique
+}
 
-	// Wrap non-null types in a response type
-	outputTypeName := UnwrapType(field.Type)
+// deprecation returns the reason of a @deprecated directive and whether there is one
+func deprecation(directives []*GraphQLDirective) (string, bool) {
+	for _, directive := range directives {
+		if directive.Name == "deprecated" {
+			if reason := directive.Arguments["reason"]; reason != "" {
+				return reason, true
+			}
+			// The default reason of the GraphQL specification
+			return "No longer supported", true
+		}
+	}
+	return "", false
+}
 
-	// Note: Annotations on RPC methods are not yet supported in TypeMUX parser
-	// We document the method type in comments instead
-	sb.WriteString(fmt.Sprintf("  // GraphQL %s\n", methodType))
+// defaultAnnotationValue returns a GraphQL default value as a @default value, or
+// an empty string for lists, objects and enum values, which @default cannot hold
+func defaultAnnotationValue(value string) string {
+	if value == "true" || value == "false" || strings.HasPrefix(value, `"`) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return ""
+}
 
-	// For subscriptions, add stream prefix to output
-	if isStreaming {
-		sb.WriteString(fmt.Sprintf("  rpc %s(%s) returns (stream %s)\n",
-			strings.Title(field.Name),
-			inputType,
-			outputTypeName))
-	} else {
-		sb.WriteString(fmt.Sprintf("  rpc %s(%s) returns (%s)\n",
-			strings.Title(field.Name),
-			inputType,
-			outputTypeName))
+// writeSynthType writes a request or response type synthesized from a root field
+func (c *Converter) writeSynthType(sb *strings.Builder, typ *synthType) {
+	sb.WriteString(fmt.Sprintf("type %s {\n", typ.name))
+	for i, field := range typ.fields {
+		writeDocComment(sb, "  ", field.doc)
+		sb.WriteString(fmt.Sprintf("  %s: %s = %d", field.name, field.typ, i+1))
+		if field.required {
+			sb.WriteString(" @required")
+		}
+		if field.defaultValue != "" {
+			sb.WriteString(fmt.Sprintf(" @default(%s)", field.defaultValue))
+		}
+		if field.deprecation != "" {
+			sb.WriteString(fmt.Sprintf(" @deprecated(%q)", field.deprecation))
+		}
+		sb.WriteString("\n")
 	}
+	sb.WriteString("}")
 }
 
-func (c *Converter) writeDocumentation(sb *strings.Builder, doc string) {
-	lines := strings.Split(doc, "\n")
-	for _, line := range lines {
-		sb.WriteString(fmt.Sprintf("// %s\n", line))
+func (c *Converter) writeService(sb *strings.Builder, methods []*rpcMethod) {
+	sb.WriteString("service GraphQLService {\n")
+
+	for i, method := range methods {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		writeDocComment(sb, "  ", method.doc)
+
+		output := method.output
+		if method.stream {
+			output = "stream " + output
+		}
+		sb.WriteString(fmt.Sprintf("  rpc %s(%s) returns (%s)\n", method.name, method.input, output))
+		for _, annotation := range method.annotations {
+			sb.WriteString(fmt.Sprintf("    %s\n", annotation))
+		}
 	}
+
+	sb.WriteString("}")
+}
+
+// writeDocComment writes a documentation comment with the given indentation
+func writeDocComment(sb *strings.Builder, indent, doc string) {
+	for _, line := range strings.Split(doc, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			sb.WriteString(fmt.Sprintf("%s/// %s\n", indent, trimmed))
+		}
+	}
+}
+
+func (c *Converter) writeDocumentation(sb *strings.Builder, doc string) {
+	writeDocComment(sb, "", doc)
 }
 
 func (c *Converter) convertGraphQLType(graphqlType string) string {
@@ -276,14 +450,16 @@ func (c *Converter) convertGraphQLType(graphqlType string) string {
 	if strings.HasPrefix(graphqlType, "[") && strings.HasSuffix(graphqlType, "]") {
 		// Extract inner type
 		innerType := graphqlType[1 : len(graphqlType)-1]
+		nonNullItems := strings.HasSuffix(innerType, "!")
 		innerType = strings.TrimSuffix(innerType, "!")
 		typemuxInnerType := c.mapType(innerType)
 
-		// In TypeMUX, arrays are represented as []Type
+		// In TypeMUX, arrays are represented as []Type, and []Type! when their
+		// elements are non-null; non-null lists use the required annotation
 		result := "[]" + typemuxInnerType
-
-		// Note: TypeMUX doesn't have a direct way to express non-null lists vs nullable lists
-		// We'll use the required annotation if needed
+		if nonNullItems {
+			result += "!"
+		}
 		return result
 	}
 
@@ -118,12 +118,12 @@ func TestConvertListType(t *testing.T) {
 	converter := NewConverter()
 	result := converter.Convert(schema)
 
-	if !strings.Contains(result, "friends: []User") {
-		t.Error("expected friends as list of User")
+	if !strings.Contains(result, "friends: []User! ") {
+		t.Error("expected friends as list of non-null User")
 	}
 
-	if !strings.Contains(result, "tags: []string") {
-		t.Error("expected tags as list of string")
+	if !strings.Contains(result, "tags: []string ") {
+		t.Error("expected tags as list of nullable string")
 	}
 }
 
@@ -307,3 +307,106 @@ func TestConvertSubscriptionsInService(t *testing.T) {
 		t.Error("expected stream keyword for subscription")
 	}
 }
+
+func TestConvertRootFieldsToMethods(t *testing.T) {
+	schema := &GraphQLSchema{
+		Types: []*GraphQLType{
+			{Name: "User", Fields: []*GraphQLField{{Name: "id", Type: "ID!"}}},
+			{Name: "ListUsersResponse", Fields: []*GraphQLField{{Name: "total", Type: "Int"}}},
+		},
+		Inputs: []*GraphQLInput{
+			{Name: "CreateUserInput", Fields: []*GraphQLField{{Name: "name", Type: "String!"}}},
+		},
+		Queries: []*GraphQLField{
+			{
+				Name:        "user",
+				Type:        "User",
+				Description: "Get a user by ID",
+				Arguments: []*GraphQLArgument{
+					{Name: "id", Type: "ID!", Description: "The user ID"},
+				},
+			},
+			{
+				Name: "listUsers",
+				Type: "[User!]!",
+				Arguments: []*GraphQLArgument{
+					{Name: "first", Type: "Int", DefaultValue: "10"},
+					{Name: "status", Type: "Status", DefaultValue: "ACTIVE"},
+					{Name: "type", Type: "String", Directives: []*GraphQLDirective{{Name: "deprecated", Arguments: map[string]string{}}}},
+				},
+			},
+			{Name: "version", Type: "String!", Directives: []*GraphQLDirective{{Name: "deprecated", Arguments: map[string]string{"reason": "Use info"}}}},
+		},
+		Mutations: []*GraphQLField{
+			{
+				Name:      "createUser",
+				Type:      "User!",
+				Arguments: []*GraphQLArgument{{Name: "input", Type: "CreateUserInput!"}},
+			},
+		},
+	}
+
+	result := NewConverter().Convert(schema)
+
+	expected := []string{
+		"type UserRequest {\n  /// The user ID\n  id: string = 1 @required\n}",
+		"type ListUsersRequest {\n" +
+			"  first: int32 = 1 @default(10)\n" +
+			"  status: Status = 2\n" +
+			"  type_: string = 3 @deprecated(\"No longer supported\")\n" +
+			"}",
+		// The existing type of the same name keeps its name
+		"type ListUsersResponse2 {\n  items: []User! = 1\n}",
+		"type VersionResponse {\n  value: string = 1\n}",
+		"  /// Get a user by ID\n  rpc User(UserRequest) returns (User)\n    @graphql(query)\n",
+		"rpc ListUsers(ListUsersRequest) returns (ListUsersResponse2)\n    @graphql(query)\n",
+		"rpc Version() returns (VersionResponse)\n    @graphql(query)\n    @deprecated(\"Use info\")\n",
+		// A single input argument is the request itself
+		"rpc CreateUser(CreateUserInput) returns (User)\n    @graphql(mutation)\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected output to contain:\n%s\ngot:\n%s", want, result)
+		}
+	}
+
+	if strings.Contains(result, "CreateUserRequest") {
+		t.Errorf("expected no request type for createUser, got:\n%s", result)
+	}
+}
+
+func TestConvertSubscriptionsOnly(t *testing.T) {
+	schema := &GraphQLSchema{
+		Types: []*GraphQLType{
+			{Name: "Message", Fields: []*GraphQLField{{Name: "text", Type: "String!"}}},
+		},
+		Subscriptions: []*GraphQLField{
+			{Name: "messageReceived", Type: "Message!"},
+		},
+	}
+
+	result := NewConverter().Convert(schema)
+
+	if !strings.Contains(result, "service GraphQLService {\n  rpc MessageReceived() returns (stream Message)\n    @graphql(subscription)\n}") {
+		t.Errorf("expected subscription service, got:\n%s", result)
+	}
+}
+
+func TestConvertDeprecatedField(t *testing.T) {
+	schema := &GraphQLSchema{
+		Types: []*GraphQLType{
+			{
+				Name: "User",
+				Fields: []*GraphQLField{
+					{Name: "name", Type: "String", Directives: []*GraphQLDirective{{Name: "deprecated", Arguments: map[string]string{"reason": "Use fullName"}}}},
+				},
+			},
+		},
+	}
+
+	result := NewConverter().Convert(schema)
+
+	if !strings.Contains(result, `name: string = 1 @deprecated("Use fullName")`) {
+		t.Errorf("expected deprecated field, got:\n%s", result)
+	}
+}
@@ -3,6 +3,7 @@ package graphql
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -34,6 +35,13 @@ func (p *Parser) Parse() (*GraphQLSchema, error) {
 		Unions:        []*GraphQLUnion{},
 	}
 
+	// Root operation types, renamed by a schema definition
+	roots := map[string]string{
+		"query":        "Query",
+		"mutation":     "Mutation",
+		"subscription": "Subscription",
+	}
+
 	for p.pos < len(p.lines) {
 		line := strings.TrimSpace(p.lines[p.pos])
 
@@ -112,14 +120,60 @@ func (p *Parser) Parse() (*GraphQLSchema, error) {
 			}
 			schema.Subscriptions = append(schema.Subscriptions, subscriptions...)
 			continue
+		} else if strings.HasPrefix(line, "schema") && strings.Contains(line, "{") {
+			for operation, typeName := range p.parseSchemaDefinition() {
+				roots[operation] = typeName
+			}
+			continue
 		}
 
 		p.pos++
 	}
 
+	// Fields of the root types are the operations of the schema
+	types := schema.Types[:0]
+	for _, typ := range schema.Types {
+		switch typ.Name {
+		case roots["query"]:
+			schema.Queries = append(typ.Fields, schema.Queries...)
+		case roots["mutation"]:
+			schema.Mutations = append(typ.Fields, schema.Mutations...)
+		case roots["subscription"]:
+			schema.Subscriptions = append(typ.Fields, schema.Subscriptions...)
+		default:
+			types = append(types, typ)
+		}
+	}
+	schema.Types = types
+
 	return schema, nil
 }
 
+// parseSchemaDefinition parses a schema { query: Q mutation: M } block and
+// returns the root type of each operation
+func (p *Parser) parseSchemaDefinition() map[string]string {
+	var body strings.Builder
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+		body.WriteString(line)
+		body.WriteString(" ")
+		p.pos++
+		if strings.Contains(line, "}") {
+			break
+		}
+	}
+
+	roots := make(map[string]string)
+	re := regexp.MustCompile(`(query|mutation|subscription)\s*:\s*(\w+)`)
+	for _, match := range re.FindAllStringSubmatch(body.String(), -1) {
+		roots[match[1]] = match[2]
+	}
+	return roots
+}
+
 func (p *Parser) parseDescription() string {
 	var description strings.Builder
 	startPos := p.pos
@@ -147,6 +201,15 @@ func (p *Parser) parseDescription() string {
 			}
 			return strings.TrimSpace(description.String())
 		}
+
+		// Single-line description with "
+		if strings.HasPrefix(line, `"`) {
+			if quoted, err := strconv.QuotedPrefix(line); err == nil && strings.TrimSpace(line[len(quoted):]) == "" {
+				p.pos++
+				text, _ := strconv.Unquote(quoted)
+				return text
+			}
+		}
 	}
 
 	// No description found, reset position
@@ -215,11 +278,11 @@ func (p *Parser) parseType(description string) (*GraphQLType, error) {
 			break
 		}
 
-		field := p.parseField(line, fieldDescription)
+		// Fields with arguments, such as those of root types, may span several lines
+		field := p.parseField(p.collectMultilineField(), fieldDescription)
 		if field != nil {
 			typ.Fields = append(typ.Fields, field)
 		}
-		p.pos++
 	}
 
 	return typ, nil
@@ -290,7 +353,7 @@ func (p *Parser) parseField(line string, description string) *GraphQLField {
 	// field: Type!
 	// field: [Type!]!
 	// field(arg: Type): Type
-	// field(arg1: Type1, arg2: Type2 = "default"): Type
+	// field(arg1: Type1, arg2: Type2 = "default"): Type @deprecated(reason: "...")
 
 	// Remove trailing comments
 	if idx := strings.Index(line, "#"); idx != -1 {
@@ -304,49 +367,35 @@ func (p *Parser) parseField(line string, description string) *GraphQLField {
 		Directives:  []*GraphQLDirective{},
 	}
 
-	// Check for arguments
-	if strings.Contains(line, "(") {
-		// field(args): Type
-		nameEnd := strings.Index(line, "(")
-		field.Name = strings.TrimSpace(line[:nameEnd])
+	nameEnd := strings.IndexAny(line, "(:")
+	if nameEnd == -1 {
+		return nil
+	}
+	field.Name = strings.TrimSpace(line[:nameEnd])
+	rest := line[nameEnd:]
 
-		argsEnd := strings.Index(line, ")")
+	// Check for arguments
+	if rest[0] == '(' {
+		argsEnd := closingParen(rest)
 		if argsEnd == -1 {
 			return nil
 		}
+		field.Arguments = p.parseArguments(rest[1:argsEnd])
+		rest = strings.TrimSpace(rest[argsEnd+1:])
+	}
 
-		argsStr := line[nameEnd+1 : argsEnd]
-		field.Arguments = p.parseArguments(argsStr)
-
-		// Parse return type after colon
-		rest := strings.TrimSpace(line[argsEnd+1:])
-		if strings.HasPrefix(rest, ":") {
-			rest = strings.TrimSpace(rest[1:])
-			// Remove default value if present
-			if idx := strings.Index(rest, "="); idx != -1 {
-				field.DefaultValue = strings.TrimSpace(rest[idx+1:])
-				rest = strings.TrimSpace(rest[:idx])
-			}
-			field.Type = rest
-		}
-	} else {
-		// Simple field: name: Type
-		parts := strings.Split(line, ":")
-		if len(parts) < 2 {
-			return nil
-		}
-
-		field.Name = strings.TrimSpace(parts[0])
-		typeAndDefault := strings.TrimSpace(parts[1])
+	// Parse return type after colon
+	if !strings.HasPrefix(rest, ":") {
+		return nil
+	}
+	rest, field.Directives = splitDirectives(strings.TrimSpace(rest[1:]))
 
-		// Check for default value
-		if idx := strings.Index(typeAndDefault, "="); idx != -1 {
-			field.Type = strings.TrimSpace(typeAndDefault[:idx])
-			field.DefaultValue = strings.TrimSpace(typeAndDefault[idx+1:])
-		} else {
-			field.Type = typeAndDefault
-		}
+	// Check for default value
+	if idx := strings.Index(rest, "="); idx != -1 {
+		field.DefaultValue = strings.TrimSpace(rest[idx+1:])
+		rest = strings.TrimSpace(rest[:idx])
 	}
+	field.Type = rest
 
 	return field
 }
@@ -354,16 +403,36 @@ func (p *Parser) parseField(line string, description string) *GraphQLField {
 func (p *Parser) parseArguments(argsStr string) []*GraphQLArgument {
 	var args []*GraphQLArgument
 
-	// Split by comma, but be careful with nested types like [Type!]
+	// Split by comma, but be careful with nested types like [Type!],
+	// directive arguments and string values
 	var currentArg strings.Builder
 	depth := 0
+	inString := false
+	startsArgument := regexp.MustCompile(`^\s*("|\w+\s*:)`)
+
+	for i, ch := range argsStr {
+		// Commas are optional, so a name followed by a colon or a description
+		// also starts the next argument once the current one has a type
+		if ch == ' ' && depth == 0 && !inString {
+			current := strings.TrimSpace(currentArg.String())
+			if strings.Contains(current, ":") && !strings.HasSuffix(current, "=") && !strings.HasSuffix(current, ":") && startsArgument.MatchString(argsStr[i:]) {
+				if arg := p.parseArgument(current); arg != nil {
+					args = append(args, arg)
+				}
+				currentArg.Reset()
+				continue
+			}
+		}
 
-	for _, ch := range argsStr {
-		if ch == '[' {
+		switch {
+		case ch == '"':
+			inString = !inString
+		case inString:
+		case ch == '[' || ch == '(' || ch == '{':
 			depth++
-		} else if ch == ']' {
+		case ch == ']' || ch == ')' || ch == '}':
 			depth--
-		} else if ch == ',' && depth == 0 {
+		case ch == ',' && depth == 0:
 			// Process current argument
 			if arg := p.parseArgument(currentArg.String()); arg != nil {
 				args = append(args, arg)
@@ -394,14 +463,21 @@ func (p *Parser) parseArgument(argStr string) *GraphQLArgument {
 		Directives: []*GraphQLDirective{},
 	}
 
-	// arg: Type = "default"
-	parts := strings.Split(argStr, ":")
+	// "description" arg: Type = "default" @deprecated
+	if strings.HasPrefix(argStr, `"`) {
+		if quoted, err := strconv.QuotedPrefix(argStr); err == nil {
+			arg.Description, _ = strconv.Unquote(quoted)
+			argStr = strings.TrimSpace(argStr[len(quoted):])
+		}
+	}
+	parts := strings.SplitN(argStr, ":", 2)
 	if len(parts) < 2 {
 		return nil
 	}
 
 	arg.Name = strings.TrimSpace(parts[0])
-	typeAndDefault := strings.TrimSpace(parts[1])
+	typeAndDefault, directives := splitDirectives(strings.TrimSpace(parts[1]))
+	arg.Directives = directives
 
 	// Check for default value
 	if idx := strings.Index(typeAndDefault, "="); idx != -1 {
@@ -414,6 +490,87 @@ func (p *Parser) parseArgument(argStr string) *GraphQLArgument {
 	return arg
 }
 
+// closingParen returns the index of the parenthesis closing the one s starts
+// with, or -1 if it is not closed
+func closingParen(s string) int {
+	depth := 0
+	inString := false
+	for i, ch := range s {
+		switch {
+		case ch == '"':
+			inString = !inString
+		case inString:
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitDirectives splits the directives off the end of a definition, such as
+// `String @deprecated(reason: "Use name")`
+func splitDirectives(s string) (string, []*GraphQLDirective) {
+	directives := []*GraphQLDirective{}
+
+	start := -1
+	inString := false
+	for i, ch := range s {
+		if ch == '"' {
+			inString = !inString
+		} else if ch == '@' && !inString {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return strings.TrimSpace(s), directives
+	}
+
+	head := strings.TrimSpace(s[:start])
+	rest := s[start:]
+	nameRe := regexp.MustCompile(`^@(\w+)\s*`)
+	for {
+		match := nameRe.FindStringSubmatch(rest)
+		if match == nil {
+			break
+		}
+		directive := &GraphQLDirective{Name: match[1], Arguments: map[string]string{}}
+		rest = rest[len(match[0]):]
+
+		if strings.HasPrefix(rest, "(") {
+			end := closingParen(rest)
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			directive.Arguments = parseDirectiveArguments(rest[1:end])
+			rest = rest[end+1:]
+		}
+		directives = append(directives, directive)
+		rest = strings.TrimSpace(rest)
+	}
+
+	return head, directives
+}
+
+// parseDirectiveArguments parses `name: value` pairs; string values are unquoted
+func parseDirectiveArguments(s string) map[string]string {
+	args := make(map[string]string)
+	re := regexp.MustCompile(`(\w+)\s*:\s*("(?:[^"\\]|\\.)*"|[^,\s]+)`)
+	for _, match := range re.FindAllStringSubmatch(s, -1) {
+		value := match[2]
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		args[match[1]] = value
+	}
+	return args
+}
+
 func (p *Parser) parseEnum(description string) (*GraphQLEnum, error) {
 	line := strings.TrimSpace(p.lines[p.pos])
 
@@ -470,10 +627,11 @@ func (p *Parser) parseEnum(description string) (*GraphQLEnum, error) {
 		}
 
 		if line != "" {
+			name, directives := splitDirectives(line)
 			enum.Values = append(enum.Values, &GraphQLEnumValue{
-				Name:        line,
+				Name:        name,
 				Description: valueDescription,
-				Directives:  []*GraphQLDirective{},
+				Directives:  directives,
 			})
 		}
 		p.pos++
@@ -740,6 +898,7 @@ func (p *Parser) collectMultilineField() string {
 	// Collect lines until we find a complete field definition
 	// A field is complete when we have a colon with the return type
 	var lines []string
+	var description strings.Builder
 	startPos := p.pos
 	inDescription := false
 
@@ -747,21 +906,38 @@ func (p *Parser) collectMultilineField() string {
 		line := p.lines[p.pos]
 		trimmed := strings.TrimSpace(line)
 
-		// Handle description blocks - skip them
-		if strings.HasPrefix(trimmed, `"""`) {
-			if inDescription {
+		// Handle description blocks; descriptions of arguments are kept as
+		// string literals in front of the argument
+		if strings.HasPrefix(trimmed, `"""`) || (inDescription && strings.Contains(trimmed, `"""`)) {
+			text := strings.TrimPrefix(trimmed, `"""`)
+			if !inDescription && strings.Contains(text, `"""`) {
+				// Single-line description
+				description.WriteString(text[:strings.Index(text, `"""`)])
+			} else if inDescription {
 				// End of description
 				inDescription = false
+				description.WriteString(strings.TrimSpace(trimmed[:strings.Index(trimmed, `"""`)]))
 			} else {
 				// Start of description
 				inDescription = true
+				description.WriteString(text)
+				p.pos++
+				continue
+			}
+			if len(lines) > 0 {
+				lines = append(lines, strconv.Quote(strings.TrimSpace(description.String())))
 			}
+			description.Reset()
 			p.pos++
 			continue
 		}
 
-		// Skip lines inside descriptions
+		// Collect lines inside descriptions
 		if inDescription {
+			if description.Len() > 0 {
+				description.WriteString("\n")
+			}
+			description.WriteString(trimmed)
 			p.pos++
 			continue
 		}
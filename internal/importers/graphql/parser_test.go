@@ -367,3 +367,153 @@ extend type Mutation {
 		t.Errorf("expected 1 mutation, got %d", len(schema.Mutations))
 	}
 }
+
+func TestParseRootTypes(t *testing.T) {
+	input := `schema {
+  query: RootQuery
+  mutation: RootMutation
+}
+
+type RootQuery {
+  user(id: ID!): User
+}
+
+type RootMutation {
+  deleteUser(id: ID!): Boolean!
+}
+
+type Subscription {
+  userChanged: User!
+}
+
+type User {
+  id: ID!
+}`
+
+	schema, err := NewParser(input).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(schema.Types) != 1 || schema.Types[0].Name != "User" {
+		t.Fatalf("expected only the User type, got %d types", len(schema.Types))
+	}
+
+	if len(schema.Queries) != 1 || schema.Queries[0].Name != "user" {
+		t.Errorf("expected query user, got %v", schema.Queries)
+	}
+
+	if len(schema.Mutations) != 1 || schema.Mutations[0].Name != "deleteUser" {
+		t.Errorf("expected mutation deleteUser, got %v", schema.Mutations)
+	}
+
+	if len(schema.Subscriptions) != 1 || schema.Subscriptions[0].Name != "userChanged" {
+		t.Errorf("expected subscription userChanged, got %v", schema.Subscriptions)
+	}
+}
+
+func TestParseDirectives(t *testing.T) {
+	input := `type User {
+  name: String @deprecated(reason: "Use fullName, or displayName")
+  fullName: String!
+}
+
+enum Status {
+  ACTIVE
+  LEGACY @deprecated
+}
+
+type Query {
+  users(first: Int = 10, filter: String @deprecated(reason: "Unused")): [User!]! @deprecated(reason: "Use search")
+}`
+
+	schema, err := NewParser(input).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	name := schema.Types[0].Fields[0]
+	if name.Name != "name" || name.Type != "String" {
+		t.Errorf("expected field name: String, got %s: %s", name.Name, name.Type)
+	}
+	if len(name.Directives) != 1 || name.Directives[0].Arguments["reason"] != "Use fullName, or displayName" {
+		t.Errorf("expected deprecated directive with reason, got %v", name.Directives)
+	}
+
+	legacy := schema.Enums[0].Values[1]
+	if legacy.Name != "LEGACY" || len(legacy.Directives) != 1 || legacy.Directives[0].Name != "deprecated" {
+		t.Errorf("expected deprecated enum value LEGACY, got %q with %v", legacy.Name, legacy.Directives)
+	}
+
+	users := schema.Queries[0]
+	if users.Type != "[User!]!" || len(users.Directives) != 1 {
+		t.Errorf("expected deprecated [User!]! query, got %s with %v", users.Type, users.Directives)
+	}
+	if len(users.Arguments) != 2 {
+		t.Fatalf("expected 2 arguments, got %d", len(users.Arguments))
+	}
+	if users.Arguments[0].DefaultValue != "10" {
+		t.Errorf("expected default value 10, got %q", users.Arguments[0].DefaultValue)
+	}
+	if users.Arguments[1].Type != "String" || len(users.Arguments[1].Directives) != 1 {
+		t.Errorf("expected deprecated String argument, got %s with %v", users.Arguments[1].Type, users.Arguments[1].Directives)
+	}
+}
+
+func TestParseMultilineArgumentsWithoutCommas(t *testing.T) {
+	input := `type Query {
+  listUsers(
+    """
+    Maximum number of users to return
+    """
+    limit: Int = 10
+    "Filter by status"
+    status: String
+  ): [User!]!
+}`
+
+	schema, err := NewParser(input).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(schema.Queries) != 1 {
+		t.Fatalf("expected 1 query, got %d", len(schema.Queries))
+	}
+
+	args := schema.Queries[0].Arguments
+	if len(args) != 2 {
+		t.Fatalf("expected 2 arguments, got %d", len(args))
+	}
+
+	if args[0].Name != "limit" || args[0].Type != "Int" || args[0].DefaultValue != "10" {
+		t.Errorf("expected limit: Int = 10, got %s: %s = %s", args[0].Name, args[0].Type, args[0].DefaultValue)
+	}
+	if args[0].Description != "Maximum number of users to return" {
+		t.Errorf("expected limit description, got %q", args[0].Description)
+	}
+
+	if args[1].Name != "status" || args[1].Type != "String" || args[1].Description != "Filter by status" {
+		t.Errorf("expected described status: String, got %s: %s (%q)", args[1].Name, args[1].Type, args[1].Description)
+	}
+}
+
+func TestParseSingleLineDescriptions(t *testing.T) {
+	input := `"A user"
+type User {
+  "The user ID"
+  id: ID!
+}`
+
+	schema, err := NewParser(input).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if schema.Types[0].Description != "A user" {
+		t.Errorf("expected type description %q, got %q", "A user", schema.Types[0].Description)
+	}
+	if schema.Types[0].Fields[0].Description != "The user ID" {
+		t.Errorf("expected field description %q, got %q", "The user ID", schema.Types[0].Fields[0].Description)
+	}
+}
@@ -6,6 +6,7 @@ type OpenAPISpec struct {
 	Info       *Info
 	Servers    []*Server
 	Paths      map[string]*PathItem
+	Webhooks   map[string]*PathItem // From webhooks (3.1) or x-webhooks, by webhook name
 	Components *Components
 	Security   []map[string][]string
 	Tags       []*Tag
@@ -45,6 +46,7 @@ type PathItem struct {
 	Options     *Operation
 	Head        *Operation
 	Trace       *Operation
+	Parameters  []*Parameter // Shared by every operation of the path
 }
 
 // Operation represents a single API operation
@@ -57,10 +59,13 @@ type Operation struct {
 	RequestBody *RequestBody
 	Responses   map[string]*Response
 	Security    []map[string][]string
+	Deprecated  bool
+	Callbacks   map[string]map[string]*PathItem // Callback name -> URL expression -> operations
 }
 
 // Parameter represents a parameter in an operation
 type Parameter struct {
+	Ref         string // Reference to components/parameters
 	Name        string
 	In          string // query, path, header, cookie
 	Description string
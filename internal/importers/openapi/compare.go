@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/importers/fidelity"
+)
+
+// Compare compares an OpenAPI document with the document generated from its
+// import, element by element: component schemas with their properties, and
+// operations with their parameters, request bodies and responses.
+func Compare(before, after *OpenAPISpec) *fidelity.Report {
+	report := fidelity.NewReport()
+
+	beforeSchemas, afterSchemas := componentSchemas(before), componentSchemas(after)
+	fidelity.Match(report, "schemas", sortedKeys(beforeSchemas), sortedKeys(afterSchemas),
+		func(name string) string { return name },
+		func(path, name, _ string) {
+			compareSchemas(report, path, beforeSchemas[name], afterSchemas[name])
+		})
+
+	fidelity.Match(report, "paths", sortedKeys(before.Paths), sortedKeys(after.Paths),
+		func(path string) string { return path },
+		func(reportPath, path, _ string) {
+			comparePathItems(report, reportPath, before, after, before.Paths[path], after.Paths[path])
+		})
+
+	return report
+}
+
+// compareSchemas compares a component schema and its properties
+func compareSchemas(report *fidelity.Report, path string, before, after *Schema) {
+	report.Attribute(path+".type", schemaSignature(before), schemaSignature(after))
+	report.Attribute(path+".description", before.Description, after.Description)
+	report.Attribute(path+".enum", enumValues(before), enumValues(after))
+
+	fidelity.Match(report, path+".required", before.Required, after.Required,
+		func(name string) string { return name }, nil)
+
+	fidelity.Match(report, path, sortedKeys(before.Properties), sortedKeys(after.Properties),
+		func(name string) string { return name },
+		func(path, name, _ string) {
+			b, a := before.Properties[name], after.Properties[name]
+			report.Attribute(path+".type", schemaSignature(b), schemaSignature(a))
+			report.Attribute(path+".description", b.Description, a.Description)
+			report.Attribute(path+".enum", enumValues(b), enumValues(a))
+		})
+}
+
+// comparePathItems compares the operations of a path
+func comparePathItems(report *fidelity.Report, path string, beforeSpec, afterSpec *OpenAPISpec, before, after *PathItem) {
+	fidelity.Match(report, path, presentMethods(before), presentMethods(after),
+		func(method string) string { return method },
+		func(path, method, _ string) {
+			b, a := pathOperation(before, method), pathOperation(after, method)
+			report.Attribute(path+".operationId", b.OperationID, a.OperationID)
+			report.Attribute(path+".summary", b.Summary, a.Summary)
+			report.Attribute(path+".description", b.Description, a.Description)
+			report.Attribute(path+".deprecated", flag(b.Deprecated), flag(a.Deprecated))
+
+			beforeParams := operationParameters(beforeSpec.Components, before.Parameters, b.Parameters)
+			afterParams := operationParameters(afterSpec.Components, after.Parameters, a.Parameters)
+			fidelity.Match(report, path+".parameters", beforeParams, afterParams,
+				func(p *Parameter) string { return fmt.Sprintf("%s(%s)", p.Name, p.In) },
+				func(path string, b, a *Parameter) {
+					report.Attribute(path+".required", flag(b.Required), flag(a.Required))
+					report.Attribute(path+".type", schemaSignature(b.Schema), schemaSignature(a.Schema))
+					report.Attribute(path+".description", b.Description, a.Description)
+				})
+
+			if b.RequestBody != nil {
+				var afterBody string
+				if a.RequestBody != nil {
+					afterBody = schemaSignature(mediaTypeSchema(a.RequestBody.Content))
+				}
+				report.Attribute(path+".requestBody", schemaSignature(mediaTypeSchema(b.RequestBody.Content)), afterBody)
+			}
+
+			fidelity.Match(report, path+".responses", sortedKeys(b.Responses), sortedKeys(a.Responses),
+				func(code string) string { return code },
+				func(path, code, _ string) {
+					report.Attribute(path+".schema",
+						schemaSignature(mediaTypeSchema(b.Responses[code].Content)),
+						schemaSignature(mediaTypeSchema(a.Responses[code].Content)))
+				})
+		})
+}
+
+// componentSchemas returns the component schemas of a document, which may have none
+func componentSchemas(spec *OpenAPISpec) map[string]*Schema {
+	if spec.Components == nil {
+		return nil
+	}
+	return spec.Components.Schemas
+}
+
+// presentMethods returns the HTTP methods a path item has operations for
+func presentMethods(item *PathItem) []string {
+	var methods []string
+	for _, method := range httpMethods {
+		if method.operation(item) != nil {
+			methods = append(methods, method.name)
+		}
+	}
+	return methods
+}
+
+// pathOperation returns the operation of a path item for an HTTP method
+func pathOperation(item *PathItem, name string) *Operation {
+	for _, method := range httpMethods {
+		if method.name == name {
+			return method.operation(item)
+		}
+	}
+	return nil
+}
+
+// schemaSignature summarizes the type of a schema, such as "[]Pet" or "integer(int64)"
+func schemaSignature(schema *Schema) string {
+	switch {
+	case schema == nil:
+		return ""
+	case schema.Ref != "":
+		return ResolveRef(schema.Ref)
+	case schema.Type == "array":
+		return "[]" + schemaSignature(schema.Items)
+	case len(schema.OneOf) > 0 || len(schema.AnyOf) > 0:
+		variants := make([]string, 0, len(schema.OneOf)+len(schema.AnyOf))
+		for _, variant := range append(append([]*Schema(nil), schema.OneOf...), schema.AnyOf...) {
+			variants = append(variants, schemaSignature(variant))
+		}
+		return strings.Join(variants, "|")
+	case schema.Type == "" && len(schema.Properties) > 0:
+		return "object"
+	case schema.Format != "":
+		return fmt.Sprintf("%s(%s)", schema.Type, schema.Format)
+	default:
+		return schema.Type
+	}
+}
+
+// enumValues lists the values of an enum schema
+func enumValues(schema *Schema) string {
+	values := make([]string, 0, len(schema.Enum))
+	for _, value := range schema.Enum {
+		values = append(values, fmt.Sprint(value))
+	}
+	return strings.Join(values, ",")
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flag returns "true" for set flags and nothing otherwise, so unset flags are not compared
+func flag(value bool) string {
+	if value {
+		return "true"
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package openapi
+
+import (
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	before, err := NewParser([]byte(`
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        "404":
+          description: Not found
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        age:
+          type: integer
+          format: int32
+`)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := NewParser([]byte(`
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    get:
+      operationId: GetPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        age:
+          type: integer
+          format: int64
+`)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := Compare(before, after)
+
+	lost := make(map[string]bool)
+	for _, difference := range report.Lost() {
+		lost[difference.Path] = true
+	}
+	expected := []string{
+		"paths./pets/{id}.GET.operationId",
+		"paths./pets/{id}.GET.responses.404",
+		"schemas.Pet.required.name",
+		"schemas.Pet.age.type",
+	}
+	for _, path := range expected {
+		if !lost[path] {
+			t.Errorf("Expected %s to be lost, got %v", path, report.Lost())
+		}
+	}
+	// The path parameter moved to the operation, which is not a loss
+	if len(lost) != len(expected) {
+		t.Errorf("Expected %d lost elements, got %v", len(expected), report.Lost())
+	}
+}
@@ -2,6 +2,7 @@ package openapi
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -9,12 +10,14 @@ type Converter struct {
 	spec            *OpenAPISpec
 	resolvedSchemas map[string]*Schema
 	fieldCounter    map[string]int
+	typeNames       map[string]bool
 }
 
 func NewConverter() *Converter {
 	return &Converter{
 		resolvedSchemas: make(map[string]*Schema),
 		fieldCounter:    make(map[string]int),
+		typeNames:       make(map[string]bool),
 	}
 }
 
@@ -46,7 +49,15 @@ func (c *Converter) Convert(spec *OpenAPISpec) string {
 		var unions []*Schema
 		var types []*Schema
 
-		for name, schema := range spec.Components.Schemas {
+		names := make([]string, 0, len(spec.Components.Schemas))
+		for name := range spec.Components.Schemas {
+			names = append(names, name)
+			c.typeNames[name] = true
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			schema := spec.Components.Schemas[name]
 			resolvedSchema := c.resolveSchema(schema)
 			resolvedSchema.Title = name
 
@@ -78,9 +89,17 @@ func (c *Converter) Convert(spec *OpenAPISpec) string {
 		}
 	}
 
-	// Convert paths to service methods
-	if len(spec.Paths) > 0 {
-		c.writeService(&sb, spec)
+	// Convert paths to services, preceded by the request and response types they need
+	services, synthesized := c.synthesizeServices(spec)
+	for _, typ := range synthesized {
+		c.writeSynthType(&sb, typ)
+		sb.WriteString("\n\n")
+	}
+	for i, service := range services {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		c.writeService(&sb, service)
 	}
 
 	return sb.String()
@@ -202,8 +221,15 @@ func (c *Converter) writeType(sb *strings.Builder, schema *Schema) {
 	sb.WriteString(fmt.Sprintf("type %s {\n", typeName))
 
 	// Write properties
+	propNames := make([]string, 0, len(schema.Properties))
+	for propName := range schema.Properties {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+
 	fieldNum := 1
-	for propName, propSchema := range schema.Properties {
+	for _, propName := range propNames {
+		propSchema := schema.Properties[propName]
 		resolvedSchema := c.resolveSchema(propSchema)
 
 		if resolvedSchema.Description != "" {
@@ -223,90 +249,424 @@ func (c *Converter) writeType(sb *strings.Builder, schema *Schema) {
 	sb.WriteString("}")
 }
 
-func (c *Converter) writeService(sb *strings.Builder, spec *OpenAPISpec) {
-	serviceName := "APIService"
+// httpMethods lists the operations of a path item in the order they are written
+var httpMethods = []struct {
+	name      string
+	operation func(*PathItem) *Operation
+}{
+	{"GET", func(p *PathItem) *Operation { return p.Get }},
+	{"POST", func(p *PathItem) *Operation { return p.Post }},
+	{"PUT", func(p *PathItem) *Operation { return p.Put }},
+	{"PATCH", func(p *PathItem) *Operation { return p.Patch }},
+	{"DELETE", func(p *PathItem) *Operation { return p.Delete }},
+}
+
+// synthField is a field of a request or response type synthesized from an operation
+type synthField struct {
+	name     string
+	jsonName string
+	typ      string
+	doc      string
+	required bool
+}
+
+// synthType is a request or response type synthesized from an operation
+type synthType struct {
+	name   string
+	fields []synthField
+}
+
+// rpcMethod is a service method synthesized from an operation
+type rpcMethod struct {
+	name        string
+	doc         string
+	input       string
+	output      string
+	annotations []string
+}
+
+// synthService collects the methods of one service
+type synthService struct {
+	name    string
+	doc     string
+	methods []*rpcMethod
+}
+
+// synthesizeServices turns the paths of the spec into services and the request
+// and response types their methods need. Operations are grouped by their first
+// tag; untagged operations go to a service named after the API title.
+func (c *Converter) synthesizeServices(spec *OpenAPISpec) ([]*synthService, []*synthType) {
+	defaultService := "APIService"
 	if spec.Info != nil && spec.Info.Title != "" {
-		serviceName = sanitizeName(spec.Info.Title) + "Service"
+		defaultService = sanitizeName(spec.Info.Title) + "Service"
+	}
+
+	tagDocs := make(map[string]string)
+	for _, tag := range spec.Tags {
+		tagDocs[tag.Name] = tag.Description
+	}
+
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
 	}
+	sort.Strings(paths)
 
-	sb.WriteString(fmt.Sprintf("service %s {\n", serviceName))
+	var services []*synthService
+	byName := make(map[string]*synthService)
+	var types []*synthType
 
-	// Process each path
-	for path, pathItem := range spec.Paths {
-		// Process each operation
-		operations := map[string]*Operation{
-			"GET":    pathItem.Get,
-			"POST":   pathItem.Post,
-			"PUT":    pathItem.Put,
-			"DELETE": pathItem.Delete,
-			"PATCH":  pathItem.Patch,
+	// serviceFor returns the service of an operation, creating it on first use
+	serviceFor := func(operation *Operation) *synthService {
+		serviceName, doc := defaultService, ""
+		if len(operation.Tags) > 0 {
+			serviceName = strings.Title(sanitizeName(operation.Tags[0])) + "Service"
+			doc = tagDocs[operation.Tags[0]]
+		}
+		service, ok := byName[serviceName]
+		if !ok {
+			service = &synthService{name: serviceName, doc: doc}
+			byName[serviceName] = service
+			services = append(services, service)
+		}
+		return service
+	}
+
+	for _, path := range paths {
+		pathItem := spec.Paths[path]
+		for _, method := range httpMethods {
+			operation := method.operation(pathItem)
+			if operation == nil {
+				continue
+			}
+
+			service := serviceFor(operation)
+			rpc, synthesized := c.synthesizeMethod(path, method.name, operation, pathItem.Parameters)
+			service.methods = append(service.methods, rpc)
+			types = append(types, synthesized...)
+
+			// Callbacks of the operation join its service as webhooks sent on its calls
+			for _, name := range sortedKeys(operation.Callbacks) {
+				for _, expression := range sortedKeys(operation.Callbacks[name]) {
+					callbackItem := operation.Callbacks[name][expression]
+					for _, callbackMethod := range httpMethods {
+						if callback := callbackMethod.operation(callbackItem); callback != nil {
+							options := fmt.Sprintf("on=%s, url=%q", rpc.name, expression)
+							webhook, synthesized := c.synthesizeWebhook(name, callbackMethod.name, callback, callbackItem.Parameters, options)
+							service.methods = append(service.methods, webhook)
+							types = append(types, synthesized...)
+						}
+					}
+				}
+			}
 		}
+	}
 
-		for method, operation := range operations {
-			if operation != nil {
-				c.writeMethod(sb, path, method, operation)
+	for _, name := range sortedKeys(spec.Webhooks) {
+		pathItem := spec.Webhooks[name]
+		for _, method := range httpMethods {
+			if operation := method.operation(pathItem); operation != nil {
+				webhook, synthesized := c.synthesizeWebhook(name, method.name, operation, pathItem.Parameters, "")
+				service := serviceFor(operation)
+				service.methods = append(service.methods, webhook)
+				types = append(types, synthesized...)
 			}
 		}
 	}
 
-	sb.WriteString("}")
+	sort.Slice(services, func(i, j int) bool { return services[i].name < services[j].name })
+	return services, types
 }
 
-func (c *Converter) writeMethod(sb *strings.Builder, path string, method string, operation *Operation) {
-	if operation.Description != "" {
-		lines := strings.Split(operation.Description, "\n")
-		for _, line := range lines {
-			trimmed := strings.TrimSpace(line)
-			if trimmed != "" {
-				sb.WriteString(fmt.Sprintf("  // %s\n", trimmed))
+// synthesizeMethod converts an operation to a method. Path and query parameters
+// become fields of a request type; a request body that references a component
+// is used as the input directly when there are no parameters.
+func (c *Converter) synthesizeMethod(path, method string, operation *Operation, shared []*Parameter) (*rpcMethod, []*synthType) {
+	name := operation.OperationID
+	if name == "" {
+		name = generateMethodName(path, method)
+	}
+	name = strings.Title(sanitizeName(name))
+
+	rpc := &rpcMethod{name: name, doc: operation.Description}
+	if rpc.doc == "" {
+		rpc.doc = operation.Summary
+	}
+	var types []*synthType
+
+	// Input
+	var fields []synthField
+	for _, param := range operationParameters(c.spec.Components, shared, operation.Parameters) {
+		if param.In != "path" && param.In != "query" {
+			continue
+		}
+		typ := "string"
+		if param.Schema != nil {
+			typ = c.convertSchemaType(c.resolveSchema(param.Schema))
+		}
+		fields = append(fields, synthField{
+			name:     fieldIdentifier(param.Name),
+			jsonName: param.Name,
+			typ:      typ,
+			doc:      param.Description,
+			required: param.Required || param.In == "path",
+		})
+	}
+
+	if operation.RequestBody != nil {
+		if body := mediaTypeSchema(operation.RequestBody.Content); body != nil {
+			required := operation.RequestBody.Required
+			resolved := c.resolveSchema(body)
+			switch {
+			case body.Ref != "" && len(fields) == 0:
+				rpc.input = ResolveRef(body.Ref)
+			case body.Ref == "" && resolved.Type == "object" && len(resolved.Properties) > 0:
+				fields = append(fields, c.propertyFields(resolved)...)
+			default:
+				fields = append(fields, synthField{name: "body", typ: c.convertSchemaType(body), required: required})
 			}
 		}
-	} else if operation.Summary != "" {
-		sb.WriteString(fmt.Sprintf("  // %s\n", operation.Summary))
-	}
-
-	// Add HTTP method and path as comment
-	sb.WriteString(fmt.Sprintf("  // %s %s\n", method, path))
-
-	// Determine method name
-	methodName := operation.OperationID
-	if methodName == "" {
-		// Generate from path and method
-		methodName = generateMethodName(path, method)
-	}
-	methodName = strings.Title(sanitizeName(methodName))
-
-	// Determine input type
-	inputType := "Empty"
-	if len(operation.Parameters) > 0 || operation.RequestBody != nil {
-		inputType = methodName + "Request"
-	}
-
-	// Determine output type
-	outputType := "Empty"
-	// Try to find 200/201 response
-	for statusCode, response := range operation.Responses {
-		if statusCode == "200" || statusCode == "201" {
-			if response.Content != nil {
-				for _, mediaType := range response.Content {
-					if mediaType.Schema != nil {
-						resolvedSchema := c.resolveSchema(mediaType.Schema)
-						if resolvedSchema.Ref != "" {
-							outputType = ResolveRef(resolvedSchema.Ref)
-						} else if resolvedSchema.Title != "" {
-							outputType = resolvedSchema.Title
-						} else {
-							outputType = methodName + "Response"
-						}
-						break
-					}
+	}
+
+	if rpc.input == "" && len(fields) > 0 {
+		rpc.input = c.uniqueTypeName(name + "Request")
+		types = append(types, &synthType{name: rpc.input, fields: fields})
+	}
+
+	// Output
+	var successCodes, errorCodes []string
+	for code := range operation.Responses {
+		switch {
+		case strings.HasPrefix(code, "2"):
+			successCodes = append(successCodes, code)
+		case isErrorStatus(code):
+			errorCodes = append(errorCodes, code)
+		}
+	}
+	sort.Strings(successCodes)
+	sort.Strings(errorCodes)
+
+	if len(successCodes) > 0 {
+		if schema := mediaTypeSchema(operation.Responses[successCodes[0]].Content); schema != nil {
+			resolved := c.resolveSchema(schema)
+			switch {
+			case schema.Ref != "":
+				rpc.output = ResolveRef(schema.Ref)
+			case resolved.Type == "object" && len(resolved.Properties) > 0:
+				fields := c.propertyFields(resolved)
+				rpc.output = c.uniqueTypeName(name + "Response")
+				types = append(types, &synthType{name: rpc.output, fields: fields})
+			default:
+				field := synthField{name: "value", typ: c.convertSchemaType(schema)}
+				if schema.Type == "array" {
+					field.name = "items"
 				}
+				rpc.output = c.uniqueTypeName(name + "Response")
+				types = append(types, &synthType{name: rpc.output, fields: []synthField{field}})
+			}
+		}
+	}
+
+	// Annotations
+	rpc.annotations = append(rpc.annotations,
+		fmt.Sprintf("@http.method(%s)", method),
+		fmt.Sprintf("@http.path(%q)", path))
+
+	// The generators answer 200 for methods with an output and 204 without one
+	defaultSuccess := "200"
+	if rpc.output == "" {
+		defaultSuccess = "204"
+	}
+	if len(successCodes) > 1 || (len(successCodes) == 1 && successCodes[0] != defaultSuccess) {
+		rpc.annotations = append(rpc.annotations, fmt.Sprintf("@http.success(%s)", strings.Join(successCodes, ",")))
+	}
+	if len(errorCodes) > 0 {
+		rpc.annotations = append(rpc.annotations, fmt.Sprintf("@http.errors(%s)", strings.Join(errorCodes, ",")))
+	}
+	if operation.Deprecated {
+		rpc.annotations = append(rpc.annotations, "@deprecated")
+	}
+
+	return rpc, types
+}
+
+// synthesizeWebhook converts a webhook, or a callback with the on= and url= options
+// of @webhook, to a method named after its operation or the webhook
+func (c *Converter) synthesizeWebhook(name, method string, operation *Operation, shared []*Parameter, options string) (*rpcMethod, []*synthType) {
+	named := *operation
+	if named.OperationID == "" {
+		named.OperationID = name
+	}
+	rpc, types := c.synthesizeMethod("", method, &named, shared)
+
+	annotations := []string{fmt.Sprintf("@webhook(%q)", name)}
+	if options != "" {
+		annotations[0] = fmt.Sprintf("@webhook(%q, %s)", name, options)
+	}
+	for _, annotation := range rpc.annotations {
+		// Webhooks have no path, and are sent with POST unless declared otherwise
+		if strings.HasPrefix(annotation, "@http.path(") || annotation == "@http.method(POST)" {
+			continue
+		}
+		annotations = append(annotations, annotation)
+	}
+	rpc.annotations = annotations
+	return rpc, types
+}
+
+// operationParameters resolves parameter references and lets the parameters of
+// an operation override the ones shared by its path
+func operationParameters(components *Components, shared, own []*Parameter) []*Parameter {
+	var result []*Parameter
+	index := make(map[string]int)
+	for _, param := range append(append([]*Parameter(nil), shared...), own...) {
+		if param.Ref != "" {
+			if components == nil || components.Parameters[ResolveRef(param.Ref)] == nil {
+				continue
+			}
+			param = components.Parameters[ResolveRef(param.Ref)]
+		}
+		key := param.In + ":" + param.Name
+		if i, ok := index[key]; ok {
+			result[i] = param
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, param)
+	}
+	return result
+}
+
+// propertyFields converts the properties of an object schema to fields, sorted by name
+func (c *Converter) propertyFields(schema *Schema) []synthField {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]synthField, 0, len(names))
+	for _, name := range names {
+		property := schema.Properties[name]
+		fields = append(fields, synthField{
+			name:     fieldIdentifier(name),
+			jsonName: name,
+			typ:      c.convertSchemaType(property),
+			doc:      c.resolveSchema(property).Description,
+			required: schema.IsRequired(name),
+		})
+	}
+	return fields
+}
+
+// uniqueTypeName returns name, or name with a numeric suffix if a type of that name already exists
+func (c *Converter) uniqueTypeName(name string) string {
+	unique := name
+	for i := 2; c.typeNames[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	c.typeNames[unique] = true
+	return unique
+}
+
+// writeSynthType writes a request or response type synthesized from an operation
+func (c *Converter) writeSynthType(sb *strings.Builder, typ *synthType) {
+	sb.WriteString(fmt.Sprintf("type %s {\n", typ.name))
+	for i, field := range typ.fields {
+		writeDocComment(sb, "  ", field.doc)
+		sb.WriteString(fmt.Sprintf("  %s: %s = %d", field.name, field.typ, i+1))
+		if field.required {
+			sb.WriteString(" @required")
+		}
+		if field.jsonName != "" && field.jsonName != field.name {
+			sb.WriteString(fmt.Sprintf(" @json.name(%q)", field.jsonName))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("}")
+}
+
+// writeService writes a service synthesized from the paths of the spec
+func (c *Converter) writeService(sb *strings.Builder, service *synthService) {
+	writeDocComment(sb, "", service.doc)
+	sb.WriteString(fmt.Sprintf("service %s {\n", service.name))
+	for i, rpc := range service.methods {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		writeDocComment(sb, "  ", rpc.doc)
+		sb.WriteString(fmt.Sprintf("  rpc %s(%s) returns (%s)\n", rpc.name, rpc.input, rpc.output))
+		for _, annotation := range rpc.annotations {
+			sb.WriteString(fmt.Sprintf("    %s\n", annotation))
+		}
+	}
+	sb.WriteString("}")
+}
+
+// mediaTypeSchema returns the schema of the JSON content, or of the first content type
+func mediaTypeSchema(content map[string]*MediaType) *Schema {
+	if mediaType, ok := content["application/json"]; ok && mediaType.Schema != nil {
+		return mediaType.Schema
+	}
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+	sort.Strings(types)
+	for _, contentType := range types {
+		if schema := content[contentType].Schema; schema != nil {
+			return schema
+		}
+	}
+	return nil
+}
+
+// isErrorStatus reports whether a response key is a numeric 4xx or 5xx status code
+func isErrorStatus(code string) bool {
+	if len(code) != 3 || (code[0] != '4' && code[0] != '5') {
+		return false
+	}
+	for _, ch := range code {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldIdentifier converts a parameter or property name to a field name, camel
+// casing names that contain characters identifiers cannot hold
+func fieldIdentifier(name string) string {
+	var result strings.Builder
+	upper := false
+	for _, ch := range name {
+		switch {
+		case (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') || ch == '_':
+			if upper && result.Len() > 0 {
+				ch = []rune(strings.ToUpper(string(ch)))[0]
 			}
-			break
+			result.WriteRune(ch)
+			upper = false
+		default:
+			upper = true
 		}
 	}
+	identifier := result.String()
+	if identifier == "" {
+		identifier = "field"
+	} else if identifier[0] >= '0' && identifier[0] <= '9' {
+		identifier = "_" + identifier
+	}
+	return escapeFieldName(identifier)
+}
 
-	sb.WriteString(fmt.Sprintf("  rpc %s(%s) returns (%s)\n", methodName, inputType, outputType))
+// writeDocComment writes a documentation comment with the given indentation
+func writeDocComment(sb *strings.Builder, indent, doc string) {
+	for _, line := range strings.Split(doc, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			sb.WriteString(fmt.Sprintf("%s/// %s\n", indent, trimmed))
+		}
+	}
 }
 
 func (c *Converter) convertSchemaType(schema *Schema) string {
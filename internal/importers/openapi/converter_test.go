@@ -520,13 +520,18 @@ func TestConvertService(t *testing.T) {
 		t.Error("expected GetUser method")
 	}
 
-	// Should have HTTP method and path comments
-	if !strings.Contains(result, "// GET /users") {
-		t.Error("expected GET /users comment")
+	// Should have HTTP method and path annotations
+	if !strings.Contains(result, "rpc ListUsers() returns ()\n    @http.method(GET)\n    @http.path(\"/users\")\n    @http.success(200)") {
+		t.Errorf("expected GET /users annotations, got:\n%s", result)
 	}
 
-	if !strings.Contains(result, "// POST /users") {
-		t.Error("expected POST /users comment")
+	if !strings.Contains(result, "rpc CreateUser() returns ()\n    @http.method(POST)\n    @http.path(\"/users\")\n    @http.success(201)") {
+		t.Errorf("expected POST /users annotations, got:\n%s", result)
+	}
+
+	// Paths are written in order
+	if strings.Index(result, "rpc ListUsers") > strings.Index(result, "rpc GetUser") {
+		t.Errorf("expected /users before /users/{id}, got:\n%s", result)
 	}
 }
 
@@ -582,15 +587,228 @@ func TestConvertMethodWithRequestBody(t *testing.T) {
 	converter := NewConverter()
 	result := converter.Convert(spec)
 
-	// Should use request/response types
-	if !strings.Contains(result, "rpc CreateUser(CreateUserRequest) returns (User)") {
+	// A referenced body without parameters is the input
+	if !strings.Contains(result, "rpc CreateUser(User) returns (User)") {
 		t.Errorf("expected CreateUser method with request body, got:\n%s", result)
 	}
 
 	// Should have description comment
-	if !strings.Contains(result, "// Creates a new user in the system") {
+	if !strings.Contains(result, "/// Creates a new user in the system") {
 		t.Error("expected method description comment")
 	}
+
+	if strings.Contains(result, "CreateUserRequest") {
+		t.Errorf("expected no synthesized request type, got:\n%s", result)
+	}
+}
+
+func TestConvertOperationParameters(t *testing.T) {
+	spec := &OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Info:    &Info{Title: "API", Version: "1.0.0"},
+		Components: &Components{
+			Schemas: map[string]*Schema{
+				"User": {Type: "object", Properties: map[string]*Schema{"name": {Type: "string"}}},
+			},
+			Parameters: map[string]*Parameter{
+				"PageSize": {Name: "page-size", In: "query", Schema: &Schema{Type: "integer"}},
+			},
+		},
+		Paths: map[string]*PathItem{
+			"/users/{id}": {
+				Parameters: []*Parameter{
+					{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+				},
+				Get: &Operation{
+					OperationID: "getUser",
+					Parameters: []*Parameter{
+						{Name: "X-Trace", In: "header", Schema: &Schema{Type: "string"}},
+						{Name: "fields", In: "query", Description: "Fields to return", Schema: &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+						{Ref: "#/components/parameters/PageSize"},
+					},
+					Responses: map[string]*Response{
+						"200":     {Content: map[string]*MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/User"}}}},
+						"404":     {Description: "Not found"},
+						"500":     {Description: "Server error"},
+						"default": {Description: "Error"},
+					},
+				},
+				Put: &Operation{
+					OperationID: "updateUser",
+					Deprecated:  true,
+					RequestBody: &RequestBody{
+						Required: true,
+						Content:  map[string]*MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/User"}}},
+					},
+					Responses: map[string]*Response{"204": {Description: "Updated"}},
+				},
+			},
+		},
+	}
+
+	result := NewConverter().Convert(spec)
+
+	expected := []string{
+		"type GetUserRequest {\n" +
+			"  id: string = 1 @required\n" +
+			"  /// Fields to return\n" +
+			"  fields: []string = 2\n" +
+			"  pageSize: int32 = 3 @json.name(\"page-size\")\n" +
+			"}",
+		"type UpdateUserRequest {\n" +
+			"  id: string = 1 @required\n" +
+			"  body: User = 2 @required\n" +
+			"}",
+		"rpc GetUser(GetUserRequest) returns (User)\n" +
+			"    @http.method(GET)\n" +
+			"    @http.path(\"/users/{id}\")\n" +
+			"    @http.errors(404,500)\n",
+		"rpc UpdateUser(UpdateUserRequest) returns ()\n" +
+			"    @http.method(PUT)\n" +
+			"    @http.path(\"/users/{id}\")\n" +
+			"    @deprecated\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected output to contain:\n%s\ngot:\n%s", want, result)
+		}
+	}
+
+	if strings.Contains(result, "Trace") {
+		t.Errorf("expected header parameters to be skipped, got:\n%s", result)
+	}
+}
+
+func TestConvertInlineSchemasAndTags(t *testing.T) {
+	spec := &OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Info:    &Info{Title: "Shop", Version: "1.0.0"},
+		Tags:    []*Tag{{Name: "orders", Description: "Order management"}},
+		Components: &Components{
+			Schemas: map[string]*Schema{
+				"CreateOrderResponse": {Type: "object", Properties: map[string]*Schema{"id": {Type: "string"}}},
+			},
+		},
+		Paths: map[string]*PathItem{
+			"/orders": {
+				Get: &Operation{
+					OperationID: "listOrders",
+					Tags:        []string{"orders"},
+					Responses: map[string]*Response{
+						"200": {Content: map[string]*MediaType{"application/json": {Schema: &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/CreateOrderResponse"}}}}},
+					},
+				},
+				Post: &Operation{
+					OperationID: "createOrder",
+					Tags:        []string{"orders"},
+					RequestBody: &RequestBody{
+						Content: map[string]*MediaType{"application/json": {Schema: &Schema{
+							Type:       "object",
+							Required:   []string{"sku"},
+							Properties: map[string]*Schema{"sku": {Type: "string"}, "quantity": {Type: "integer"}},
+						}}},
+					},
+					Responses: map[string]*Response{
+						"201": {Content: map[string]*MediaType{"application/json": {Schema: &Schema{
+							Type:       "object",
+							Properties: map[string]*Schema{"orderId": {Type: "string"}},
+						}}}},
+					},
+				},
+			},
+			"/health": {
+				Get: &Operation{Summary: "Health check", Responses: map[string]*Response{"200": {Description: "OK"}}},
+			},
+		},
+	}
+
+	result := NewConverter().Convert(spec)
+
+	expected := []string{
+		"type ListOrdersResponse {\n  items: []CreateOrderResponse = 1\n}",
+		"type CreateOrderRequest {\n  quantity: int32 = 1\n  sku: string = 2 @required\n}",
+		// The component of the same name keeps its name
+		"type CreateOrderResponse2 {\n  orderId: string = 1\n}",
+		"rpc CreateOrder(CreateOrderRequest) returns (CreateOrderResponse2)",
+		"/// Order management\nservice OrdersService {",
+		"service ShopService {\n  /// Health check\n  rpc GetHealth() returns ()",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected output to contain:\n%s\ngot:\n%s", want, result)
+		}
+	}
+
+	if strings.Index(result, "service OrdersService") > strings.Index(result, "service ShopService") {
+		t.Errorf("expected services sorted by name, got:\n%s", result)
+	}
+}
+
+func TestConvertWebhooksAndCallbacks(t *testing.T) {
+	event := &RequestBody{Required: true, Content: map[string]*MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/PaymentEvent"}}}}
+	spec := &OpenAPISpec{
+		OpenAPI: "3.1.0",
+		Info:    &Info{Title: "Payments", Version: "1.0.0"},
+		Components: &Components{
+			Schemas: map[string]*Schema{
+				"Payment":      {Type: "object", Properties: map[string]*Schema{"callbackUrl": {Type: "string"}}},
+				"PaymentEvent": {Type: "object", Properties: map[string]*Schema{"paymentId": {Type: "string"}}},
+			},
+		},
+		Paths: map[string]*PathItem{
+			"/payments": {
+				Post: &Operation{
+					OperationID: "createPayment",
+					RequestBody: &RequestBody{Content: map[string]*MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Payment"}}}},
+					Responses:   map[string]*Response{"200": {Content: map[string]*MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Payment"}}}}},
+					Callbacks: map[string]map[string]*PathItem{
+						"paymentSettled": {"{$request.body#/callbackUrl}": {Post: &Operation{
+							RequestBody: event,
+							Responses:   map[string]*Response{"204": {Description: "Received"}},
+						}}},
+					},
+				},
+			},
+		},
+		Webhooks: map[string]*PathItem{
+			"refundIssued": {Put: &Operation{
+				OperationID: "notifyRefund",
+				RequestBody: event,
+				Responses:   map[string]*Response{"204": {Description: "Received"}},
+			}},
+		},
+	}
+
+	result := NewConverter().Convert(spec)
+
+	expected := []string{
+		"  rpc PaymentSettled(PaymentEvent) returns ()\n    @webhook(\"paymentSettled\", on=CreatePayment, url=\"{$request.body#/callbackUrl}\")\n",
+		"  rpc NotifyRefund(PaymentEvent) returns ()\n    @webhook(\"refundIssued\")\n    @http.method(PUT)\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected output to contain:\n%s\ngot:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "@http.path(\"\")") || strings.Contains(result, "@http.path(\"{$") {
+		t.Errorf("expected webhooks without paths, got:\n%s", result)
+	}
+}
+
+func TestFieldIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"id":           "id",
+		"page-size":    "pageSize",
+		"filter[name]": "filterName",
+		"user_id":      "user_id",
+		"2fa":          "_2fa",
+		"type":         "type_",
+	}
+	for input, expected := range tests {
+		if got := fieldIdentifier(input); got != expected {
+			t.Errorf("fieldIdentifier(%q) = %q, want %q", input, got, expected)
+		}
+	}
 }
 
 func TestGenerateMethodName(t *testing.T) {
@@ -51,6 +51,20 @@ func (p *Parser) Parse() (*OpenAPISpec, error) {
 		}
 	}
 
+	// Parse webhooks, written as x-webhooks before OpenAPI 3.1
+	for _, key := range []string{"x-webhooks", "webhooks"} {
+		if webhooks, ok := raw[key].(map[string]interface{}); ok {
+			if spec.Webhooks == nil {
+				spec.Webhooks = make(map[string]*PathItem)
+			}
+			for name, pathItem := range webhooks {
+				if pathItemMap, ok := pathItem.(map[string]interface{}); ok {
+					spec.Webhooks[name] = p.parsePathItem(pathItemMap)
+				}
+			}
+		}
+	}
+
 	// Parse components
 	if components, ok := raw["components"].(map[string]interface{}); ok {
 		spec.Components = p.parseComponents(components)
@@ -148,6 +162,11 @@ func (p *Parser) parsePathItem(pathItem map[string]interface{}) *PathItem {
 		pi.Patch = p.parseOperation(patch)
 	}
 
+	// Parse parameters shared by all operations
+	if parameters, ok := pathItem["parameters"].([]interface{}); ok {
+		pi.Parameters = p.parseParameters(parameters)
+	}
+
 	return pi
 }
 
@@ -165,6 +184,9 @@ func (p *Parser) parseOperation(operation map[string]interface{}) *Operation {
 	if description, ok := operation["description"].(string); ok {
 		op.Description = description
 	}
+	if deprecated, ok := operation["deprecated"].(bool); ok {
+		op.Deprecated = deprecated
+	}
 
 	// Parse tags
 	if tags, ok := operation["tags"].([]interface{}); ok {
@@ -194,6 +216,23 @@ func (p *Parser) parseOperation(operation map[string]interface{}) *Operation {
 		}
 	}
 
+	// Parse callbacks
+	if callbacks, ok := operation["callbacks"].(map[string]interface{}); ok {
+		op.Callbacks = make(map[string]map[string]*PathItem)
+		for name, callback := range callbacks {
+			callbackMap, ok := callback.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			op.Callbacks[name] = make(map[string]*PathItem)
+			for expression, pathItem := range callbackMap {
+				if pathItemMap, ok := pathItem.(map[string]interface{}); ok {
+					op.Callbacks[name][expression] = p.parsePathItem(pathItemMap)
+				}
+			}
+		}
+	}
+
 	return op
 }
 
@@ -204,6 +243,9 @@ func (p *Parser) parseParameters(parameters []interface{}) []*Parameter {
 		if paramMap, ok := param.(map[string]interface{}); ok {
 			par := &Parameter{}
 
+			if ref, ok := paramMap["$ref"].(string); ok {
+				par.Ref = ref
+			}
 			if name, ok := paramMap["name"].(string); ok {
 				par.Name = name
 			}
@@ -440,6 +482,15 @@ func (p *Parser) parseComponents(components map[string]interface{}) *Components
 		}
 	}
 
+	// Parse parameters
+	if parameters, ok := components["parameters"].(map[string]interface{}); ok {
+		for name, parameter := range parameters {
+			if parsed := p.parseParameters([]interface{}{parameter}); len(parsed) == 1 {
+				c.Parameters[name] = parsed[0]
+			}
+		}
+	}
+
 	return c
 }
 
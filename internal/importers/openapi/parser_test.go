@@ -478,6 +478,64 @@ func TestParseParameters(t *testing.T) {
 	}
 }
 
+func TestParseSharedAndReferencedParameters(t *testing.T) {
+	input := `
+openapi: 3.0.0
+info:
+  title: Test
+  version: 1.0.0
+paths:
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: getUser
+      deprecated: true
+      parameters:
+        - $ref: '#/components/parameters/Limit'
+      responses:
+        "200":
+          description: Success
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+`
+
+	spec, err := NewParser([]byte(input)).Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	userPath := spec.Paths["/users/{id}"]
+	if len(userPath.Parameters) != 1 || userPath.Parameters[0].Name != "id" {
+		t.Fatalf("expected shared id parameter, got %v", userPath.Parameters)
+	}
+
+	if !userPath.Get.Deprecated {
+		t.Error("expected operation to be deprecated")
+	}
+
+	if len(userPath.Get.Parameters) != 1 || userPath.Get.Parameters[0].Ref != "#/components/parameters/Limit" {
+		t.Fatalf("expected referenced parameter, got %v", userPath.Get.Parameters)
+	}
+
+	limit, ok := spec.Components.Parameters["Limit"]
+	if !ok {
+		t.Fatal("expected Limit component parameter")
+	}
+	if limit.Name != "limit" || limit.In != "query" {
+		t.Errorf("expected query parameter limit, got %s in %s", limit.Name, limit.In)
+	}
+}
+
 func TestParseRequestBody(t *testing.T) {
 	input := `{
   "openapi": "3.0.0",
@@ -740,6 +798,54 @@ func TestParseTags(t *testing.T) {
 	}
 }
 
+func TestParseWebhooksAndCallbacks(t *testing.T) {
+	content := []byte(`openapi: 3.0.0
+info:
+  title: Payments
+  version: 1.0.0
+paths:
+  /payments:
+    post:
+      operationId: createPayment
+      responses:
+        "200":
+          description: OK
+      callbacks:
+        paymentSettled:
+          '{$request.body#/callbackUrl}':
+            post:
+              responses:
+                "204":
+                  description: Received
+x-webhooks:
+  refundIssued:
+    post:
+      operationId: notifyRefund
+      responses:
+        "204":
+          description: Received
+webhooks:
+  paymentFailed:
+    post:
+      responses:
+        "204":
+          description: Received
+`)
+
+	spec, err := NewParser(content).Parse()
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	callback := spec.Paths["/payments"].Post.Callbacks["paymentSettled"]["{$request.body#/callbackUrl}"]
+	if callback == nil || callback.Post == nil {
+		t.Fatalf("Expected the paymentSettled callback, got %+v", spec.Paths["/payments"].Post.Callbacks)
+	}
+	if len(spec.Webhooks) != 2 || spec.Webhooks["refundIssued"].Post.OperationID != "notifyRefund" || spec.Webhooks["paymentFailed"].Post == nil {
+		t.Errorf("Expected webhooks from both webhooks and x-webhooks, got %+v", spec.Webhooks)
+	}
+}
+
 func TestIsRequired(t *testing.T) {
 	schema := &Schema{
 		Type: "object",
@@ -14,6 +14,7 @@ type ProtoSchema struct {
 // ProtoMessage represents a protobuf message
 type ProtoMessage struct {
 	Name     string
+	Comment  string // Leading comment
 	Fields   []*ProtoField
 	Enums    []*ProtoEnum
 	Messages []*ProtoMessage // nested messages
@@ -30,25 +31,31 @@ type ProtoField struct {
 	Repeated   bool
 	Optional   bool
 	Deprecated bool
-	Comment    string
+	Comment    string            // Leading comment
+	Options    map[string]string // Options in brackets after the number, such as packed
 }
 
 // ProtoEnum represents an enum
 type ProtoEnum struct {
-	Name   string
-	Values []*ProtoEnumValue
+	Name    string
+	Comment string // Leading comment
+	Values  []*ProtoEnumValue
+	Options map[string]string
 }
 
 // ProtoEnumValue represents an enum value
 type ProtoEnumValue struct {
-	Name   string
-	Number int
+	Name    string
+	Number  int
+	Comment string // Leading comment
 }
 
 // ProtoService represents a gRPC service
 type ProtoService struct {
 	Name    string
+	Comment string // Leading comment
 	Methods []*ProtoMethod
+	Options map[string]string
 }
 
 // ProtoMethod represents a service method
@@ -58,6 +65,8 @@ type ProtoMethod struct {
 	OutputType   string
 	ClientStream bool
 	ServerStream bool
+	Comment      string // Leading comment
+	Options      map[string]string
 }
 
 // ProtoOneOf represents a oneof field
@@ -0,0 +1,89 @@
+package protobuf
+
+import (
+	"strconv"
+
+	"github.com/rasmartins/typemux/internal/importers/fidelity"
+)
+
+// Compare compares a Protobuf schema with the schema generated from its import,
+// element by element: messages with their fields and oneofs, enums with their
+// values, and services with their methods.
+func Compare(before, after *ProtoSchema) *fidelity.Report {
+	report := fidelity.NewReport()
+	report.Attribute("package", before.Package, after.Package)
+	compareMessages(report, "", before.Messages, after.Messages)
+	compareEnums(report, "", before.Enums, after.Enums)
+
+	fidelity.Match(report, "", before.Services, after.Services,
+		func(s *ProtoService) string { return s.Name },
+		func(path string, b, a *ProtoService) {
+			report.Attribute(path+".comment", b.Comment, a.Comment)
+			fidelity.Match(report, path, b.Methods, a.Methods,
+				func(m *ProtoMethod) string { return m.Name },
+				func(path string, b, a *ProtoMethod) {
+					report.Attribute(path+".input", b.InputType, a.InputType)
+					report.Attribute(path+".output", b.OutputType, a.OutputType)
+					report.Attribute(path+".client_stream", flag(b.ClientStream), flag(a.ClientStream))
+					report.Attribute(path+".server_stream", flag(b.ServerStream), flag(a.ServerStream))
+					report.Attribute(path+".deprecated", b.Options["deprecated"], a.Options["deprecated"])
+					report.Attribute(path+".comment", b.Comment, a.Comment)
+				})
+		})
+
+	return report
+}
+
+// compareMessages compares messages and their nested messages and enums
+func compareMessages(report *fidelity.Report, path string, before, after []*ProtoMessage) {
+	fidelity.Match(report, path, before, after,
+		func(m *ProtoMessage) string { return m.Name },
+		func(path string, b, a *ProtoMessage) {
+			report.Attribute(path+".comment", b.Comment, a.Comment)
+			report.Attribute(path+".deprecated", b.Options["deprecated"], a.Options["deprecated"])
+			compareFields(report, path, b.Fields, a.Fields)
+			fidelity.Match(report, path, b.OneOfs, a.OneOfs,
+				func(o *ProtoOneOf) string { return o.Name },
+				func(path string, b, a *ProtoOneOf) {
+					compareFields(report, path, b.Fields, a.Fields)
+				})
+			compareMessages(report, path, b.Messages, a.Messages)
+			compareEnums(report, path, b.Enums, a.Enums)
+		})
+}
+
+// compareFields compares the fields of a message or oneof
+func compareFields(report *fidelity.Report, path string, before, after []*ProtoField) {
+	fidelity.Match(report, path, before, after,
+		func(f *ProtoField) string { return f.Name },
+		func(path string, b, a *ProtoField) {
+			report.Attribute(path+".type", b.Type, a.Type)
+			report.Attribute(path+".number", strconv.Itoa(b.Number), strconv.Itoa(a.Number))
+			report.Attribute(path+".repeated", flag(b.Repeated), flag(a.Repeated))
+			report.Attribute(path+".optional", flag(b.Optional), flag(a.Optional))
+			report.Attribute(path+".deprecated", flag(b.Deprecated), flag(a.Deprecated))
+			report.Attribute(path+".comment", b.Comment, a.Comment)
+		})
+}
+
+// compareEnums compares enums and their values
+func compareEnums(report *fidelity.Report, path string, before, after []*ProtoEnum) {
+	fidelity.Match(report, path, before, after,
+		func(e *ProtoEnum) string { return e.Name },
+		func(path string, b, a *ProtoEnum) {
+			report.Attribute(path+".comment", b.Comment, a.Comment)
+			fidelity.Match(report, path, b.Values, a.Values,
+				func(v *ProtoEnumValue) string { return v.Name },
+				func(path string, b, a *ProtoEnumValue) {
+					report.Attribute(path+".number", strconv.Itoa(b.Number), strconv.Itoa(a.Number))
+				})
+		})
+}
+
+// flag returns "true" for set flags and nothing otherwise, so unset flags are not compared
+func flag(value bool) string {
+	if value {
+		return "true"
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package protobuf
+
+import (
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	before, err := NewParser(`syntax = "proto3";
+package shop;
+
+// An order
+message Order {
+  string id = 1;
+  repeated string items = 2;
+  optional string note = 3;
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_OPEN = 1;
+}
+
+service OrderService {
+  rpc Watch(Order) returns (stream Order);
+}
+`).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := NewParser(`syntax = "proto3";
+package shop;
+
+// An order
+message Order {
+  string id = 1;
+  repeated string items = 3;
+  string note = 4;
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+}
+
+message Empty {
+}
+
+service OrderService {
+  rpc Watch(Order) returns (stream Order);
+}
+`).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := Compare(before, after)
+
+	lost := make(map[string]bool)
+	for _, difference := range report.Lost() {
+		lost[difference.Path] = true
+	}
+	for _, path := range []string{"Order.items.number", "Order.note.number", "Order.note.optional", "Status.STATUS_OPEN"} {
+		if !lost[path] {
+			t.Errorf("Expected %s to be lost, got %v", path, report.Lost())
+		}
+	}
+	if len(lost) != 4 {
+		t.Errorf("Expected 4 lost elements, got %v", report.Lost())
+	}
+
+	added := report.AddedElements()
+	if len(added) != 1 || added[0].Path != "Empty" {
+		t.Errorf("Expected Empty to be added, got %v", added)
+	}
+}
@@ -30,6 +30,7 @@ func (c *Converter) Convert(schema *ProtoSchema) string {
 	}
 
 	// Write imports (skip google and common imports for now)
+	imports := 0
 	for _, imp := range schema.Imports {
 		if !strings.Contains(imp, "google/") && !strings.Contains(imp, "commonwatcherproto") {
 			// Convert .proto to .typemux
@@ -44,9 +45,10 @@ func (c *Converter) Convert(schema *ProtoSchema) string {
 				}
 			}
 			sb.WriteString(fmt.Sprintf("import \"%s.typemux\"\n", impName))
+			imports++
 		}
 	}
-	if len(schema.Imports) > 0 {
+	if imports > 0 {
 		sb.WriteString("\n")
 	}
 
@@ -74,9 +76,11 @@ func (c *Converter) Convert(schema *ProtoSchema) string {
 func (c *Converter) writeEnum(sb *strings.Builder, enum *ProtoEnum, indent int) {
 	indentStr := strings.Repeat("  ", indent)
 
-	sb.WriteString(fmt.Sprintf("%senum %s {\n", indentStr, enum.Name))
+	c.writeDoc(sb, enum.Comment, indentStr)
+	sb.WriteString(fmt.Sprintf("%senum %s%s {\n", indentStr, enum.Name, deprecatedAnnotation(enum.Options)))
 
 	for _, value := range enum.Values {
+		c.writeDoc(sb, value.Comment, indentStr+"  ")
 		sb.WriteString(fmt.Sprintf("%s  %s = %d\n", indentStr, value.Name, value.Number))
 	}
 
@@ -98,7 +102,8 @@ func (c *Converter) writeMessage(sb *strings.Builder, msg *ProtoMessage, indent
 		sb.WriteString("\n")
 	}
 
-	sb.WriteString(fmt.Sprintf("%stype %s {\n", indentStr, msg.Name))
+	c.writeDoc(sb, msg.Comment, indentStr)
+	sb.WriteString(fmt.Sprintf("%stype %s%s {\n", indentStr, msg.Name, deprecatedAnnotation(msg.Options)))
 
 	// Write fields
 	for _, field := range msg.Fields {
@@ -131,6 +136,8 @@ func (c *Converter) writeField(sb *strings.Builder, field *ProtoField, indent in
 	// In proto3, all fields are optional by default, so we don't need the ? marker
 	// TypeMUX will treat them as optional unless marked @required
 
+	c.writeDoc(sb, field.Comment, indentStr)
+
 	// Build field line
 	fieldLine := fmt.Sprintf("%s%s: %s = %d",
 		indentStr,
@@ -214,25 +221,63 @@ func (c *Converter) convertType(protoType string) string {
 }
 
 func (c *Converter) writeService(sb *strings.Builder, service *ProtoService) {
+	c.writeDoc(sb, service.Comment, "")
 	sb.WriteString(fmt.Sprintf("service %s {\n", service.Name))
 
 	for _, method := range service.Methods {
+		c.writeDoc(sb, method.Comment, "  ")
+
 		// Build method signature with streaming support
-		inputType := method.InputType
+		inputType := c.convertMethodType(method.InputType, method.ClientStream)
 		if method.ClientStream {
 			inputType = "stream " + inputType
 		}
 
-		outputType := method.OutputType
+		outputType := c.convertMethodType(method.OutputType, method.ServerStream)
 		if method.ServerStream {
 			outputType = "stream " + outputType
 		}
 
-		sb.WriteString(fmt.Sprintf("  rpc %s(%s) returns (%s)\n",
+		// Services cannot be deprecated in TypeMUX, so a deprecated service deprecates its methods
+		deprecated := deprecatedAnnotation(method.Options)
+		if deprecated == "" {
+			deprecated = deprecatedAnnotation(service.Options)
+		}
+
+		sb.WriteString(fmt.Sprintf("  rpc %s(%s) returns (%s)%s\n",
 			method.Name,
 			inputType,
-			outputType))
+			outputType,
+			deprecated))
 	}
 
 	sb.WriteString("}\n")
 }
+
+// convertMethodType converts a method input or output type. google.protobuf.Empty
+// becomes an empty type, so the method is declared with (), unless it is streamed.
+func (c *Converter) convertMethodType(protoType string, stream bool) string {
+	protoType = strings.TrimPrefix(protoType, ".")
+	if !stream && protoType == "google.protobuf.Empty" {
+		return ""
+	}
+	return c.convertType(protoType)
+}
+
+// writeDoc writes a leading proto comment as TypeMUX documentation
+func (c *Converter) writeDoc(sb *strings.Builder, comment string, indentStr string) {
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		sb.WriteString(strings.TrimRight(fmt.Sprintf("%s/// %s", indentStr, line), " ") + "\n")
+	}
+}
+
+// deprecatedAnnotation returns the annotation for `option deprecated = true`, with a leading space
+func deprecatedAnnotation(options map[string]string) string {
+	if options["deprecated"] == "true" {
+		return " @deprecated"
+	}
+	return ""
+}
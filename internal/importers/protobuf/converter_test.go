@@ -160,6 +160,41 @@ func TestConvertService(t *testing.T) {
 	}
 }
 
+func TestConvertEmptyMethodTypes(t *testing.T) {
+	schema := &ProtoSchema{
+		Syntax:  "proto3",
+		Package: "example",
+		Services: []*ProtoService{
+			{
+				Name: "HealthService",
+				Methods: []*ProtoMethod{
+					{
+						Name:       "Ping",
+						InputType:  "google.protobuf.Empty",
+						OutputType: "google.protobuf.Empty",
+					},
+					{
+						Name:       "Reset",
+						InputType:  "ResetRequest",
+						OutputType: ".google.protobuf.Empty",
+					},
+				},
+			},
+		},
+	}
+
+	converter := NewConverter()
+	result := converter.Convert(schema)
+
+	if !strings.Contains(result, "rpc Ping() returns ()") {
+		t.Errorf("expected Ping without input or output, got:\n%s", result)
+	}
+
+	if !strings.Contains(result, "rpc Reset(ResetRequest) returns ()") {
+		t.Errorf("expected Reset without output, got:\n%s", result)
+	}
+}
+
 func TestConvertStreamingService(t *testing.T) {
 	schema := &ProtoSchema{
 		Syntax:  "proto3",
@@ -448,3 +483,63 @@ func TestConvertDeprecatedField(t *testing.T) {
 		t.Error("expected @deprecated annotation for deprecated field")
 	}
 }
+
+func TestConvertCommentsAndDeprecation(t *testing.T) {
+	schema := &ProtoSchema{
+		Syntax:  "proto3",
+		Package: "example",
+		Enums: []*ProtoEnum{
+			{
+				Name:    "Role",
+				Comment: "Role of a user.",
+				Options: map[string]string{"deprecated": "true"},
+				Values: []*ProtoEnumValue{
+					{Name: "ROLE_USER", Number: 0, Comment: "Default role"},
+				},
+			},
+		},
+		Messages: []*ProtoMessage{
+			{
+				Name:    "User",
+				Comment: "A user account.\n\nStored in the users table.",
+				Options: map[string]string{"deprecated": "true"},
+				Fields: []*ProtoField{
+					{Name: "id", Type: "string", Number: 1, Comment: "Unique identifier"},
+				},
+			},
+		},
+		Services: []*ProtoService{
+			{
+				Name:    "UserService",
+				Comment: "Manages users.",
+				Methods: []*ProtoMethod{
+					{Name: "GetUser", InputType: "GetUserRequest", OutputType: "User", Comment: "Returns a user."},
+					{Name: "ListUsers", InputType: "google.protobuf.Empty", OutputType: "User", ServerStream: true, Options: map[string]string{"deprecated": "true"}},
+				},
+			},
+			{
+				Name:    "LegacyService",
+				Options: map[string]string{"deprecated": "true"},
+				Methods: []*ProtoMethod{
+					{Name: "Ping", InputType: "google.protobuf.Empty", OutputType: "google.protobuf.Empty"},
+				},
+			},
+		},
+	}
+
+	converter := NewConverter()
+	result := converter.Convert(schema)
+
+	expected := []string{
+		"/// Role of a user.\nenum Role @deprecated {\n  /// Default role\n  ROLE_USER = 0\n",
+		"/// A user account.\n///\n/// Stored in the users table.\ntype User @deprecated {\n  /// Unique identifier\n  id: string = 1\n",
+		"/// Manages users.\nservice UserService {\n  /// Returns a user.\n  rpc GetUser(GetUserRequest) returns (User)\n",
+		"rpc ListUsers() returns (stream User) @deprecated\n",
+		"rpc Ping() returns () @deprecated\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected output to contain:\n%s\ngot:\n%s", want, result)
+		}
+	}
+}
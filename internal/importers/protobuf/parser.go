@@ -16,6 +16,7 @@ type Parser struct {
 	fileName    string
 	importPaths []string
 	processed   map[string]bool
+	comments    []string // Comment lines since the last declaration or blank line
 }
 
 func NewParser(content string) *Parser {
@@ -52,8 +53,8 @@ func (p *Parser) Parse() (*ProtoSchema, error) {
 	for p.pos < len(p.lines) {
 		line := strings.TrimSpace(p.lines[p.pos])
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "//") {
+		// Skip empty lines and comments, keeping comments for the next declaration
+		if p.skipLine(line) {
 			p.pos++
 			continue
 		}
@@ -64,7 +65,7 @@ func (p *Parser) Parse() (*ProtoSchema, error) {
 			schema.Package = p.parsePackage(line)
 		} else if strings.HasPrefix(line, "import") {
 			schema.Imports = append(schema.Imports, p.parseImport(line))
-		} else if strings.HasPrefix(line, "option") {
+		} else if isOption(line) {
 			key, value := p.parseOption(line)
 			schema.Options[key] = value
 		} else if strings.HasPrefix(line, "message") {
@@ -90,6 +91,7 @@ func (p *Parser) Parse() (*ProtoSchema, error) {
 			continue // parseService advances pos
 		}
 
+		p.comments = nil
 		p.pos++
 	}
 
@@ -191,6 +193,33 @@ func (p *Parser) resolveAndParseImport(importPath string) (map[string]*ProtoSche
 	return nil, fmt.Errorf("import not found in any search path: %s", importPath)
 }
 
+// skipLine reports whether a line holds no declaration. Comment lines are kept
+// for the declaration that follows them, and a blank line discards them.
+func (p *Parser) skipLine(line string) bool {
+	switch {
+	case line == "":
+		p.comments = nil
+	case strings.HasPrefix(line, "//"):
+		text := strings.TrimLeft(line, "/")
+		p.comments = append(p.comments, strings.TrimPrefix(text, " "))
+	default:
+		return false
+	}
+	return true
+}
+
+// isOption reports whether a line declares an option, unlike an optional field
+func isOption(line string) bool {
+	return strings.HasPrefix(line, "option ") || strings.HasPrefix(line, "option(")
+}
+
+// takeComment returns the comment lines before the current declaration and forgets them
+func (p *Parser) takeComment() string {
+	comment := strings.Join(p.comments, "\n")
+	p.comments = nil
+	return comment
+}
+
 func (p *Parser) parseSyntax(line string) string {
 	// syntax = "proto3";
 	re := regexp.MustCompile(`syntax\s*=\s*"([^"]+)"`)
@@ -223,10 +252,11 @@ func (p *Parser) parseImport(line string) string {
 
 func (p *Parser) parseOption(line string) (string, string) {
 	// option go_package = "...";
-	re := regexp.MustCompile(`option\s+([^\s=]+)\s*=\s*"?([^";]+)"?`)
+	// option deprecated = true;
+	re := regexp.MustCompile(`option\s+([^\s=]+)\s*=\s*(?:"([^"]*)"|([^;\s]+))`)
 	matches := re.FindStringSubmatch(line)
-	if len(matches) > 2 {
-		return matches[1], strings.Trim(matches[2], `"`)
+	if len(matches) > 3 {
+		return matches[1], matches[2] + matches[3]
 	}
 	return "", ""
 }
@@ -243,6 +273,7 @@ func (p *Parser) parseMessage(indent int) (*ProtoMessage, error) {
 
 	msg := &ProtoMessage{
 		Name:     matches[1],
+		Comment:  p.takeComment(),
 		Fields:   []*ProtoField{},
 		Enums:    []*ProtoEnum{},
 		Messages: []*ProtoMessage{},
@@ -257,14 +288,15 @@ func (p *Parser) parseMessage(indent int) (*ProtoMessage, error) {
 	for p.pos < len(p.lines) {
 		line := strings.TrimSpace(p.lines[p.pos])
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "//") {
+		// Skip empty lines and comments, keeping comments for the next declaration
+		if p.skipLine(line) {
 			p.pos++
 			continue
 		}
 
 		// End of message
 		if line == "}" {
+			p.comments = nil
 			p.pos++
 			break
 		}
@@ -293,6 +325,16 @@ func (p *Parser) parseMessage(indent int) (*ProtoMessage, error) {
 		if strings.HasPrefix(line, "reserved") {
 			reserved := p.parseReserved(line)
 			msg.Reserved = append(msg.Reserved, reserved...)
+			p.comments = nil
+			p.pos++
+			continue
+		}
+
+		// Message options (e.g., option deprecated = true;)
+		if isOption(line) {
+			key, value := p.parseOption(line)
+			msg.Options[key] = value
+			p.comments = nil
 			p.pos++
 			continue
 		}
@@ -311,9 +353,11 @@ func (p *Parser) parseMessage(indent int) (*ProtoMessage, error) {
 		field, err := p.parseField(line)
 		if err != nil {
 			// Skip lines we can't parse
+			p.comments = nil
 			p.pos++
 			continue
 		}
+		field.Comment = p.takeComment()
 		msg.Fields = append(msg.Fields, field)
 		p.pos++
 	}
@@ -325,9 +369,10 @@ func (p *Parser) parseField(line string) (*ProtoField, error) {
 	field := &ProtoField{}
 
 	// Check for deprecated
-	if strings.Contains(line, "[deprecated = true]") {
+	if regexp.MustCompile(`[\[,]\s*deprecated\s*=\s*true\s*[\],]`).MatchString(line) {
 		field.Deprecated = true
 	}
+	field.Options = parseFieldOptions(line)
 
 	// Check for optional/repeated
 	if strings.HasPrefix(line, "optional ") {
@@ -367,6 +412,22 @@ func (p *Parser) parseField(line string) (*ProtoField, error) {
 	return field, nil
 }
 
+// parseFieldOptions parses the options in brackets after a field number, such
+// as [packed = false, json_name = "s"]. String values are unquoted, and options
+// whose values are aggregates are skipped.
+func parseFieldOptions(line string) map[string]string {
+	options := make(map[string]string)
+	start, end := strings.Index(line, "["), strings.LastIndex(line, "]")
+	if start < 0 || end < start {
+		return options
+	}
+	re := regexp.MustCompile(`(?:^|,)\s*([^\s=,]+)\s*=\s*(?:"([^"]*)"|([^,\s{}]+))`)
+	for _, option := range re.FindAllStringSubmatch(line[start+1:end], -1) {
+		options[option[1]] = option[2] + option[3]
+	}
+	return options
+}
+
 func (p *Parser) parseEnum(indent int) (*ProtoEnum, error) {
 	line := strings.TrimSpace(p.lines[p.pos])
 
@@ -378,8 +439,10 @@ func (p *Parser) parseEnum(indent int) (*ProtoEnum, error) {
 	}
 
 	enum := &ProtoEnum{
-		Name:   matches[1],
-		Values: []*ProtoEnumValue{},
+		Name:    matches[1],
+		Comment: p.takeComment(),
+		Values:  []*ProtoEnumValue{},
+		Options: make(map[string]string),
 	}
 
 	p.pos++ // Move past enum declaration
@@ -388,29 +451,41 @@ func (p *Parser) parseEnum(indent int) (*ProtoEnum, error) {
 	for p.pos < len(p.lines) {
 		line := strings.TrimSpace(p.lines[p.pos])
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "//") {
+		// Skip empty lines and comments, keeping comments for the next declaration
+		if p.skipLine(line) {
 			p.pos++
 			continue
 		}
 
 		// End of enum
 		if line == "}" {
+			p.comments = nil
 			p.pos++
 			break
 		}
 
+		// Enum options (e.g., option allow_alias = true;)
+		if isOption(line) {
+			key, value := p.parseOption(line)
+			enum.Options[key] = value
+			p.comments = nil
+			p.pos++
+			continue
+		}
+
 		// Parse enum value: NAME = number;
 		re := regexp.MustCompile(`^(\w+)\s*=\s*(\d+)`)
 		matches := re.FindStringSubmatch(line)
 		if len(matches) >= 3 {
 			number, _ := strconv.Atoi(matches[2])
 			enum.Values = append(enum.Values, &ProtoEnumValue{
-				Name:   matches[1],
-				Number: number,
+				Name:    matches[1],
+				Number:  number,
+				Comment: p.takeComment(),
 			})
 		}
 
+		p.comments = nil
 		p.pos++
 	}
 
@@ -429,7 +504,9 @@ func (p *Parser) parseService() (*ProtoService, error) {
 
 	service := &ProtoService{
 		Name:    matches[1],
+		Comment: p.takeComment(),
 		Methods: []*ProtoMethod{},
+		Options: make(map[string]string),
 	}
 
 	p.pos++ // Move past service declaration
@@ -438,18 +515,28 @@ func (p *Parser) parseService() (*ProtoService, error) {
 	for p.pos < len(p.lines) {
 		line := strings.TrimSpace(p.lines[p.pos])
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "//") {
+		// Skip empty lines and comments, keeping comments for the next declaration
+		if p.skipLine(line) {
 			p.pos++
 			continue
 		}
 
 		// End of service
 		if line == "}" {
+			p.comments = nil
 			p.pos++
 			break
 		}
 
+		// Service options (e.g., option deprecated = true;)
+		if isOption(line) {
+			key, value := p.parseOption(line)
+			service.Options[key] = value
+			p.comments = nil
+			p.pos++
+			continue
+		}
+
 		// Parse RPC method (may span multiple lines)
 		if strings.HasPrefix(line, "rpc") {
 			method := p.parseMultilineMethod()
@@ -459,6 +546,7 @@ func (p *Parser) parseService() (*ProtoService, error) {
 			continue
 		}
 
+		p.comments = nil
 		p.pos++
 	}
 
@@ -468,33 +556,47 @@ func (p *Parser) parseService() (*ProtoService, error) {
 func (p *Parser) parseMethod(line string) *ProtoMethod {
 	// rpc MethodName(RequestType) returns (ResponseType);
 	// rpc MethodName(stream RequestType) returns (stream ResponseType);
-	re := regexp.MustCompile(`rpc\s+(\w+)\s*\(\s*(stream\s+)?(\w+)\s*\)\s*returns\s*\(\s*(stream\s+)?(\w+)\s*\)`)
+	// rpc MethodName(pkg.RequestType) returns (ResponseType) { option deprecated = true; }
+	re := regexp.MustCompile(`rpc\s+(\w+)\s*\(\s*(stream\s+)?([\w.]+)\s*\)\s*returns\s*\(\s*(stream\s+)?([\w.]+)\s*\)`)
 	matches := re.FindStringSubmatch(line)
 	if len(matches) < 6 {
 		return nil
 	}
 
-	return &ProtoMethod{
+	method := &ProtoMethod{
 		Name:         matches[1],
 		InputType:    matches[3],
 		OutputType:   matches[5],
 		ClientStream: matches[2] != "",
 		ServerStream: matches[4] != "",
+		Options:      make(map[string]string),
+	}
+
+	// Options in the method body
+	optionRe := regexp.MustCompile(`option\s+([^\s=]+)\s*=\s*(?:"([^"]*)"|([^;\s]+))`)
+	for _, option := range optionRe.FindAllStringSubmatch(line, -1) {
+		method.Options[option[1]] = option[2] + option[3]
 	}
+
+	return method
 }
 
 func (p *Parser) parseMultilineMethod() *ProtoMethod {
-	// Collect lines until we find the semicolon or closing brace
+	comment := p.takeComment()
+
+	// Collect lines until the semicolon that ends the method, or the brace that
+	// closes its option block
 	var methodLines []string
 	startPos := p.pos
+	depth := 0
 
 	for p.pos < len(p.lines) {
 		line := p.lines[p.pos]
 		methodLines = append(methodLines, line)
 		p.pos++
 
-		// Check if this line completes the method (has ; or })
-		if strings.Contains(line, ";") || strings.Contains(line, "}") {
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth <= 0 && (strings.Contains(line, ";") || strings.Contains(line, "}")) {
 			break
 		}
 	}
@@ -506,8 +608,10 @@ func (p *Parser) parseMultilineMethod() *ProtoMethod {
 	// If parsing failed, reset position
 	if method == nil {
 		p.pos = startPos + 1
+		return nil
 	}
 
+	method.Comment = comment
 	return method
 }
 
@@ -542,6 +646,7 @@ func (p *Parser) parseOneOf() (*ProtoOneOf, error) {
 		Name:   matches[1],
 		Fields: []*ProtoField{},
 	}
+	p.comments = nil
 
 	p.pos++ // Move past oneof declaration
 
@@ -549,14 +654,15 @@ func (p *Parser) parseOneOf() (*ProtoOneOf, error) {
 	for p.pos < len(p.lines) {
 		line := strings.TrimSpace(p.lines[p.pos])
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "//") {
+		// Skip empty lines and comments, keeping comments for the next declaration
+		if p.skipLine(line) {
 			p.pos++
 			continue
 		}
 
 		// End of oneof
 		if line == "}" {
+			p.comments = nil
 			p.pos++
 			break
 		}
@@ -564,9 +670,11 @@ func (p *Parser) parseOneOf() (*ProtoOneOf, error) {
 		// Parse field
 		field, err := p.parseField(line)
 		if err == nil {
+			field.Comment = p.takeComment()
 			oneof.Fields = append(oneof.Fields, field)
 		}
 
+		p.comments = nil
 		p.pos++
 	}
 
@@ -125,6 +125,18 @@ func TestParseOption(t *testing.T) {
 			expectedKey:   "java_package",
 			expectedValue: "com.example",
 		},
+		{
+			name:          "go_package with package name",
+			input:         `option go_package = "github.com/example/proto;proto";`,
+			expectedKey:   "go_package",
+			expectedValue: "github.com/example/proto;proto",
+		},
+		{
+			name:          "unquoted option",
+			input:         "option deprecated = true;",
+			expectedKey:   "deprecated",
+			expectedValue: "true",
+		},
 		{
 			name:          "invalid option",
 			input:         "invalid",
@@ -204,6 +216,19 @@ func TestParseField(t *testing.T) {
 				Name:       "old_field",
 				Number:     5,
 				Deprecated: true,
+				Options:    map[string]string{"deprecated": "true"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "field options",
+			input: `repeated int32 counts = 6 [packed = false, json_name = "n,m", (buf.validate.field).repeated = {min_items: 1, max_items: 2}];`,
+			expected: &ProtoField{
+				Type:     "int32",
+				Name:     "counts",
+				Number:   6,
+				Repeated: true,
+				Options:  map[string]string{"packed": "false", "json_name": "n,m"},
 			},
 			wantErr: false,
 		},
@@ -245,6 +270,14 @@ func TestParseField(t *testing.T) {
 			if result.Deprecated != tt.expected.Deprecated {
 				t.Errorf("expected deprecated %v, got %v", tt.expected.Deprecated, result.Deprecated)
 			}
+			if len(result.Options) != len(tt.expected.Options) {
+				t.Errorf("expected options %v, got %v", tt.expected.Options, result.Options)
+			}
+			for name, value := range tt.expected.Options {
+				if result.Options[name] != value {
+					t.Errorf("expected option %s = %q, got %q", name, value, result.Options[name])
+				}
+			}
 		})
 	}
 }
@@ -457,6 +490,117 @@ service UserService {
 	}
 }
 
+func TestParseMethodOptionsAndQualifiedTypes(t *testing.T) {
+	input := `syntax = "proto3";
+
+service UserService {
+  rpc ListUsers(google.protobuf.Empty) returns (stream User) {
+    option deprecated = true;
+  }
+  rpc Upload(stream .users.User) returns (google.protobuf.Empty) {}
+  rpc Chat(stream ChatMessage)
+      returns (stream ChatMessage);
+}`
+
+	p := NewParser(input)
+	schema, err := p.Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(schema.Services) != 1 {
+		t.Fatalf("expected 1 service, got %d", len(schema.Services))
+	}
+	methods := schema.Services[0].Methods
+	if len(methods) != 3 {
+		t.Fatalf("expected 3 methods, got %d", len(methods))
+	}
+
+	if methods[0].InputType != "google.protobuf.Empty" || !methods[0].ServerStream {
+		t.Errorf("expected ListUsers(google.protobuf.Empty) returns (stream User), got %+v", methods[0])
+	}
+	if methods[0].Options["deprecated"] != "true" {
+		t.Errorf("expected deprecated option on ListUsers, got %v", methods[0].Options)
+	}
+	if methods[1].InputType != ".users.User" || !methods[1].ClientStream {
+		t.Errorf("expected Upload(stream .users.User), got %+v", methods[1])
+	}
+	if methods[2].Name != "Chat" || !methods[2].ClientStream || !methods[2].ServerStream {
+		t.Errorf("expected bidirectional Chat, got %+v", methods[2])
+	}
+}
+
+func TestParseComments(t *testing.T) {
+	input := `// License header, not a doc comment.
+
+syntax = "proto3";
+
+// Role of a user.
+// Controls access.
+enum Role {
+  option allow_alias = true;
+  // Default role
+  ROLE_USER = 0;
+  ROLE_ADMIN = 1;
+}
+
+// A user account.
+message User {
+  option deprecated = true;
+  // Unique identifier
+  string id = 1;
+
+  string email = 2;
+  optional string nickname = 3;
+}
+
+// Manages users.
+service UserService {
+  option deprecated = true;
+  // Returns a user.
+  rpc GetUser(GetUserRequest) returns (User);
+}`
+
+	p := NewParser(input)
+	schema, err := p.Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	enum := schema.Enums[0]
+	if enum.Comment != "Role of a user.\nControls access." {
+		t.Errorf("unexpected enum comment %q", enum.Comment)
+	}
+	if enum.Options["allow_alias"] != "true" {
+		t.Errorf("expected allow_alias option, got %v", enum.Options)
+	}
+	if enum.Values[0].Comment != "Default role" || enum.Values[1].Comment != "" {
+		t.Errorf("unexpected enum value comments %q, %q", enum.Values[0].Comment, enum.Values[1].Comment)
+	}
+
+	msg := schema.Messages[0]
+	if msg.Comment != "A user account." {
+		t.Errorf("unexpected message comment %q", msg.Comment)
+	}
+	if msg.Options["deprecated"] != "true" {
+		t.Errorf("expected deprecated option, got %v", msg.Options)
+	}
+	if len(msg.Fields) != 3 {
+		t.Fatalf("expected 3 fields, got %d", len(msg.Fields))
+	}
+	if msg.Fields[0].Comment != "Unique identifier" || msg.Fields[1].Comment != "" {
+		t.Errorf("unexpected field comments %q, %q", msg.Fields[0].Comment, msg.Fields[1].Comment)
+	}
+
+	service := schema.Services[0]
+	if service.Comment != "Manages users." || service.Options["deprecated"] != "true" {
+		t.Errorf("unexpected service comment %q or options %v", service.Comment, service.Options)
+	}
+	if service.Methods[0].Comment != "Returns a user." {
+		t.Errorf("unexpected method comment %q", service.Methods[0].Comment)
+	}
+}
+
 func TestParseOneOf(t *testing.T) {
 	input := `syntax = "proto3";
 
@@ -2,8 +2,11 @@ package lexer
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 // TokenType represents the type of a lexical token in the TypeMUX language.
@@ -39,6 +42,7 @@ const (
 	TOKEN_NUMBER
 	TOKEN_DOC_COMMENT
 	TOKEN_QUESTION
+	TOKEN_BANG
 )
 
 // Token represents a single lexical token with its type, value, and location.
@@ -59,13 +63,31 @@ type Lexer struct {
 	column       int
 }
 
-// New creates a new lexer for the given input string.
+// New creates a new lexer for the given input string, normalized with Normalize.
 func New(input string) *Lexer {
-	l := &Lexer{input: input, line: 1, column: 0}
+	l := &Lexer{input: Normalize(input), line: 1, column: 0}
 	l.readChar()
 	return l
 }
 
+// byteOrderMark is the UTF-8 encoding of U+FEFF, which Windows editors often
+// write at the start of UTF-8 files
+const byteOrderMark = "\uFEFF"
+
+// Normalize returns source text as editors display it: without a leading
+// UTF-8 byte order mark, and with CRLF and lone CR line endings turned into
+// LF. Token lines and columns of the normalized text then match the lines and
+// columns editors show, and doc comments and strings carry no stray CR.
+// Columns count characters, so a tab is one column as in editor positions.
+func Normalize(input string) string {
+	input = strings.TrimPrefix(input, byteOrderMark)
+	if !strings.Contains(input, "\r") {
+		return input
+	}
+	input = strings.ReplaceAll(input, "\r\n", "\n")
+	return strings.ReplaceAll(input, "\r", "\n")
+}
+
 func (l *Lexer) readChar() {
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
@@ -74,7 +96,10 @@ func (l *Lexer) readChar() {
 	}
 	l.position = l.readPosition
 	l.readPosition++
-	l.column++
+	// Columns count characters, not the continuation bytes of UTF-8 sequences
+	if utf8.RuneStart(l.ch) {
+		l.column++
+	}
 	if l.ch == '\n' {
 		l.line++
 		l.column = 0
@@ -148,26 +173,123 @@ func (l *Lexer) readNumber() string {
 	return l.input[position:l.position]
 }
 
+// readString reads a double-quoted string literal and returns its decoded value.
+// The escape sequences \", \\, \n, \r, \t, and \uXXXX are decoded (surrogate
+// pairs included). Other backslashes are kept as written, so regular expressions
+// like "^\d+\b" need no double escaping.
 func (l *Lexer) readString() string {
 	// Skip opening quote
 	l.readChar()
-	position := l.position
 
+	var sb strings.Builder
 	for l.ch != '"' && l.ch != 0 {
-		// Handle escape sequences
-		if l.ch == '\\' {
-			l.readChar() // skip backslash
+		if l.ch != '\\' {
+			sb.WriteByte(l.ch)
+			l.readChar()
+			continue
+		}
+
+		l.readChar() // skip backslash
+		switch l.ch {
+		case '"', '\\':
+			sb.WriteByte(l.ch)
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'u':
+			if r, ok := l.readUnicodeEscape(); ok {
+				sb.WriteRune(r)
+				continue
+			}
+			sb.WriteString("\\u")
+		case 0:
+			sb.WriteByte('\\')
+			continue
+		default:
+			sb.WriteByte('\\')
+			sb.WriteByte(l.ch)
 		}
 		l.readChar()
 	}
 
-	str := l.input[position:l.position]
 	// Skip closing quote if present
 	if l.ch == '"' {
 		l.readChar()
 	}
 
-	return str
+	return sb.String()
+}
+
+// readUnicodeEscape reads the hex digits of a \uXXXX escape, with the lexer on the u.
+// A high surrogate followed by a \uXXXX low surrogate is combined into one rune.
+// When the digits are invalid, nothing past the u is consumed.
+func (l *Lexer) readUnicodeEscape() (rune, bool) {
+	r, ok := l.hexAt(l.readPosition)
+	if !ok {
+		return 0, false
+	}
+	for i := 0; i < 5; i++ {
+		l.readChar() // u and four hex digits
+	}
+
+	if utf16.IsSurrogate(r) && l.ch == '\\' && l.peekChar() == 'u' {
+		if low, ok := l.hexAt(l.readPosition + 1); ok {
+			if combined := utf16.DecodeRune(r, low); combined != unicode.ReplacementChar {
+				for i := 0; i < 6; i++ {
+					l.readChar() // backslash, u, and four hex digits
+				}
+				return combined, true
+			}
+		}
+	}
+	if utf16.IsSurrogate(r) {
+		return unicode.ReplacementChar, true
+	}
+	return r, true
+}
+
+// hexAt parses four hex digits starting at the given input offset
+func (l *Lexer) hexAt(offset int) (rune, bool) {
+	if offset+4 > len(l.input) {
+		return 0, false
+	}
+	value, err := strconv.ParseUint(l.input[offset:offset+4], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return rune(value), true
+}
+
+// QuoteString returns a string as a double-quoted literal that the lexer decodes
+// back to the same value. Non-ASCII characters are written as they are.
+func QuoteString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				sb.WriteString(fmt.Sprintf(`\u%04x`, r))
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
 }
 
 // NextToken returns the next token from the input stream.
@@ -228,6 +350,8 @@ func (l *Lexer) NextToken() Token {
 		tok = Token{Type: TOKEN_EQUALS, Literal: string(l.ch), Line: l.line, Column: l.column}
 	case '?':
 		tok = Token{Type: TOKEN_QUESTION, Literal: string(l.ch), Line: l.line, Column: l.column}
+	case '!':
+		tok = Token{Type: TOKEN_BANG, Literal: string(l.ch), Line: l.line, Column: l.column}
 	case '"':
 		tok.Type = TOKEN_STRING
 		tok.Literal = l.readString()
@@ -246,7 +370,12 @@ func (l *Lexer) NextToken() Token {
 			tok.Literal = l.readNumber()
 			return tok
 		} else {
-			tok = Token{Type: TOKEN_EOF, Literal: string(l.ch), Line: l.line, Column: l.column}
+			// Report a character outside ASCII whole, not its first byte
+			r, size := utf8.DecodeRuneInString(l.input[l.position:])
+			tok = Token{Type: TOKEN_EOF, Literal: string(r), Line: l.line, Column: l.column}
+			for i := 1; i < size; i++ {
+				l.readChar()
+			}
 		}
 	}
 
@@ -273,8 +402,10 @@ func lookupIdent(ident string) TokenType {
 	return TOKEN_IDENT
 }
 
+// isLetter reports whether a byte is an ASCII letter or underscore; the bytes
+// of UTF-8 sequences are not letters on their own
 func isLetter(ch byte) bool {
-	return unicode.IsLetter(rune(ch)) || ch == '_'
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
 }
 
 func isDigit(ch byte) bool {
@@ -310,6 +441,7 @@ func (t TokenType) String() string {
 		TOKEN_NUMBER:      "NUMBER",
 		TOKEN_DOC_COMMENT: "DOC_COMMENT",
 		TOKEN_QUESTION:    "?",
+		TOKEN_BANG:        "!",
 	}
 	if name, ok := names[t]; ok {
 		return name
@@ -575,6 +575,58 @@ func TestNextToken_Strings(t *testing.T) {
 				{TOKEN_RPAREN, ")"},
 			},
 		},
+		{
+			name:  "escaped quotes",
+			input: `"/files/\"report\".pdf" next`,
+			expected: []struct {
+				typ     TokenType
+				literal string
+			}{
+				{TOKEN_STRING, `/files/"report".pdf`},
+				{TOKEN_IDENT, "next"},
+			},
+		},
+		{
+			name:  "escape sequences",
+			input: `"a\nb\tc\r\\d"`,
+			expected: []struct {
+				typ     TokenType
+				literal string
+			}{
+				{TOKEN_STRING, "a\nb\tc\r\\d"},
+			},
+		},
+		{
+			name:  "unicode escapes",
+			input: `"caf\u00e9 \ud83d\ude00"`,
+			expected: []struct {
+				typ     TokenType
+				literal string
+			}{
+				{TOKEN_STRING, "café 😀"},
+			},
+		},
+		{
+			name:  "unknown escapes are kept",
+			input: `"^\d+\b" "\uZZ"`,
+			expected: []struct {
+				typ     TokenType
+				literal string
+			}{
+				{TOKEN_STRING, `^\d+\b`},
+				{TOKEN_STRING, `\uZZ`},
+			},
+		},
+		{
+			name:  "non-ASCII characters",
+			input: `"Überprüfung — 検証"`,
+			expected: []struct {
+				typ     TokenType
+				literal string
+			}{
+				{TOKEN_STRING, "Überprüfung — 検証"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -592,6 +644,27 @@ func TestNextToken_Strings(t *testing.T) {
 		})
 	}
 }
+
+func TestNextToken_ColumnsAfterNonASCII(t *testing.T) {
+	l := New(`"héllo" name`)
+	l.NextToken()
+	tok := l.NextToken()
+	if tok.Literal != "name" || tok.Column != 9 {
+		t.Errorf("Expected name at column 9, got %q at column %d", tok.Literal, tok.Column)
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	values := []string{"", "plain", `say "hi"`, `C:\path`, "line\nbreak\ttab", "café 😀", "bell\x07"}
+	for _, value := range values {
+		quoted := QuoteString(value)
+		tok := New(quoted).NextToken()
+		if tok.Type != TOKEN_STRING || tok.Literal != value {
+			t.Errorf("QuoteString(%q) = %s, lexed back as %q", value, quoted, tok.Literal)
+		}
+	}
+}
+
 func TestNextToken_Namespace(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -708,3 +781,76 @@ func TestTokenTypeQuestionString(t *testing.T) {
 		t.Errorf("Expected TOKEN_QUESTION.String() to be '?', got '%s'", TOKEN_QUESTION.String())
 	}
 }
+
+func TestTokenizeNonNullElements(t *testing.T) {
+	l := New("[]string!?")
+
+	for i, expected := range []TokenType{TOKEN_LBRACKET, TOKEN_RBRACKET, TOKEN_IDENT, TOKEN_BANG, TOKEN_QUESTION, TOKEN_EOF} {
+		tok := l.NextToken()
+		if tok.Type != expected {
+			t.Errorf("Token %d: expected type %s, got %s (literal: %q)", i, expected, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"type A {}\n", "type A {}\n"},
+		{"type A {\r\n}\r\n", "type A {\n}\n"},
+		{"type A {\r}\r", "type A {\n}\n"},
+		{"\uFEFFnamespace a\r\n", "namespace a\n"},
+		{"a\uFEFFb", "a\uFEFFb"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.input); got != tt.want {
+			t.Errorf("Normalize(%q): expected %q, got %q", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestNextToken_LineEndingsAndBOM(t *testing.T) {
+	type position struct {
+		literal      string
+		line, column int
+	}
+	expected := []position{
+		{"namespace", 1, 1}, {"shop", 1, 11},
+		{"Order header", 3, 1},
+		{"type", 4, 1}, {"Order", 4, 6}, {"{", 4, 12},
+		{"id", 5, 2}, {":", 5, 4}, {"string", 5, 6},
+		{"}", 6, 1},
+	}
+
+	for _, input := range []string{
+		"namespace shop\n\n/// Order header\ntype Order {\n\tid: string\n}\n",
+		"\uFEFFnamespace shop\r\n\r\n/// Order header\r\ntype Order {\r\n\tid: string\r\n}\r\n",
+		"namespace shop\r\r/// Order header\rtype Order {\r\tid: string\r}\r",
+	} {
+		l := New(input)
+		for i, want := range expected {
+			tok := l.NextToken()
+			if tok.Literal != want.literal || tok.Line != want.line || tok.Column != want.column {
+				t.Errorf("%q token %d: expected %q at %d:%d, got %q at %d:%d",
+					input, i, want.literal, want.line, want.column, tok.Literal, tok.Line, tok.Column)
+			}
+		}
+		if tok := l.NextToken(); tok.Type != TOKEN_EOF || tok.Literal != "" {
+			t.Errorf("%q: expected EOF, got %v", input, tok)
+		}
+	}
+}
+
+func TestNextToken_UnexpectedNonASCII(t *testing.T) {
+	l := New("a € b")
+	l.NextToken()
+	tok := l.NextToken()
+	if tok.Type != TOKEN_EOF || tok.Literal != "€" || tok.Column != 3 {
+		t.Errorf("Expected an unexpected € at column 3, got %q at column %d", tok.Literal, tok.Column)
+	}
+	if tok = l.NextToken(); tok.Literal != "b" || tok.Column != 5 {
+		t.Errorf("Expected b at column 5, got %q at column %d", tok.Literal, tok.Column)
+	}
+}
@@ -0,0 +1,37 @@
+package loader
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// readDocFiles reads the Markdown files referenced by the @doc-file lines of
+// the documentation of a parsed file, relative to its directory, into the
+// extended documentation
+func (s *loadState) readDocFiles(filePath, absPath string, schema *ast.Schema) error {
+	read := s.loader.Read
+	if read == nil {
+		read = s.loader.Limits.readFile
+	}
+
+	contents := make(map[string]string)
+	for _, doc := range schema.Docs() {
+		if doc.File == "" {
+			continue
+		}
+		docPath := filepath.Join(filepath.Dir(absPath), doc.File)
+		content, ok := contents[docPath]
+		if !ok {
+			data, err := read(docPath)
+			if err != nil {
+				return fmt.Errorf("%s: @doc-file %s: %w", filePath, doc.File, err)
+			}
+			content = string(data)
+			contents[docPath] = content
+		}
+		doc.Extended = content
+	}
+	return nil
+}
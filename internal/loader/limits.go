@@ -0,0 +1,171 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/diagnostic"
+	"github.com/rasmartins/typemux/internal/stdlib"
+)
+
+// ErrLimitExceeded is wrapped by the errors of schemas that exceed a limit.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits bounds the size and complexity of the schemas a Loader accepts, so that
+// untrusted input cannot exhaust memory or time. A zero limit is unlimited.
+type Limits struct {
+	// MaxFileSize is the largest schema file, in bytes. Larger files are not read.
+	MaxFileSize int64
+
+	// MaxImportDepth is the longest chain of imports from the root file; 1 allows
+	// the files the root file imports, but not their imports.
+	MaxImportDepth int
+
+	// MaxTypes is the most types, enums, unions, and services of a schema,
+	// including those of the files it imports.
+	MaxTypes int
+
+	// MaxFields is the most fields of a type.
+	MaxFields int
+
+	// MaxAnnotationLength is the longest content of an annotation, such as the
+	// arguments of @openapi.extension or the JSON of @openapi.example, in bytes.
+	MaxAnnotationLength int
+}
+
+// limitError returns a parse error of a file that exceeds a limit
+func limitError(path, format string, args ...interface{}) error {
+	message := fmt.Sprintf(format, args...)
+	return diagnostic.New(diagnostic.Parse, fmt.Errorf("%s: %w: %s", path, ErrLimitExceeded, message),
+		diagnostic.Diagnostic{File: path, Severity: diagnostic.SeverityError, Message: message})
+}
+
+// CheckSize reports a file larger than MaxFileSize.
+func (l Limits) CheckSize(path string, size int64) error {
+	if l.MaxFileSize > 0 && size > l.MaxFileSize {
+		return limitError(path, "the file has more than %d bytes", l.MaxFileSize)
+	}
+	return nil
+}
+
+// CheckSchema reports a parsed file with more declarations than MaxTypes, a
+// type with more fields than MaxFields, or an annotation longer than
+// MaxAnnotationLength.
+func (l Limits) CheckSchema(path string, schema *ast.Schema) error {
+	if err := l.checkTypes(path, Declarations(schema)); err != nil {
+		return err
+	}
+	if l.MaxFields > 0 {
+		for _, typ := range schema.Types {
+			if len(typ.Fields) > l.MaxFields {
+				return limitError(path, "type %s has %d fields, more than %d", typ.Name, len(typ.Fields), l.MaxFields)
+			}
+		}
+	}
+	if l.MaxAnnotationLength > 0 {
+		if name, length := longestAnnotation(schema); length > l.MaxAnnotationLength {
+			return limitError(path, "an annotation of %s has %d bytes, more than %d", name, length, l.MaxAnnotationLength)
+		}
+	}
+	return nil
+}
+
+// checkTypes reports more declarations than MaxTypes
+func (l Limits) checkTypes(path string, declarations int) error {
+	if l.MaxTypes > 0 && declarations > l.MaxTypes {
+		return limitError(path, "the schema has %d types, enums, unions, and services, more than %d", declarations, l.MaxTypes)
+	}
+	return nil
+}
+
+// checkImportDepth reports an import chain longer than MaxImportDepth
+func (l Limits) checkImportDepth(path string, depth int) error {
+	if l.MaxImportDepth > 0 && depth > l.MaxImportDepth {
+		return limitError(path, "the file is imported through %d files, more than %d", depth, l.MaxImportDepth)
+	}
+	return nil
+}
+
+// Declarations returns the number of types, enums, unions, and services of a schema.
+func Declarations(schema *ast.Schema) int {
+	return len(schema.Types) + len(schema.Enums) + len(schema.Unions) + len(schema.Services)
+}
+
+// longestAnnotation returns the declaration with the longest annotation content
+// and its length in bytes
+func longestAnnotation(schema *ast.Schema) (string, int) {
+	longest, name := 0, ""
+	check := func(owner string, annotations *ast.FormatAnnotations) {
+		if annotations == nil {
+			return
+		}
+		for _, list := range [][]string{annotations.Proto, annotations.GraphQL, annotations.OpenAPI, annotations.Go} {
+			for _, content := range list {
+				if len(content) > longest {
+					longest, name = len(content), owner
+				}
+			}
+		}
+	}
+
+	check("namespace "+schema.Namespace, schema.NamespaceAnnotations)
+	for _, enum := range schema.Enums {
+		check(enum.Name, enum.Annotations)
+	}
+	for _, union := range schema.Unions {
+		check(union.Name, union.Annotations)
+	}
+	for _, typ := range schema.Types {
+		check(typ.Name, typ.Annotations)
+		for _, field := range typ.Fields {
+			check(typ.Name+"."+field.Name, field.Annotations)
+			for _, value := range field.Attributes {
+				if len(value) > longest {
+					longest, name = len(value), typ.Name+"."+field.Name
+				}
+			}
+		}
+	}
+	for _, service := range schema.Services {
+		check(service.Name, service.Annotations)
+		for _, method := range service.Methods {
+			owner := service.Name + "." + method.Name
+			check(owner, method.Annotations)
+			for _, example := range method.Examples {
+				if length := len(example.Request) + len(example.Response); length > longest {
+					longest, name = length, owner
+				}
+			}
+			for _, sample := range method.CodeSamples {
+				if len(sample.Source) > longest {
+					longest, name = len(sample.Source), owner
+				}
+			}
+		}
+	}
+	return name, longest
+}
+
+// readFile reads a schema file, without reading past MaxFileSize
+func (l Limits) readFile(path string) ([]byte, error) {
+	if l.MaxFileSize <= 0 || stdlib.IsImport(path) {
+		return ReadFile(path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %v", path, err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, l.MaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %v", path, err)
+	}
+	if err := l.CheckSize(path, int64(len(content))); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
@@ -0,0 +1,106 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/diagnostic"
+)
+
+func TestLoad_Limits(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"api.typemux": `namespace api
+import "common.typemux"
+
+type User {
+  id: string = 1
+  name: string = 2 @openapi.extension({"x-note": "a long note about the name"})
+}
+
+service UserService {
+  rpc GetUser(User) returns (User)
+}`,
+		"common.typemux": `namespace common
+import "audit.typemux"
+
+type Entity {
+  id: string = 1
+}`,
+		"audit.typemux": `namespace common
+
+enum Action {
+  CREATE
+}`,
+	})
+
+	tests := []struct {
+		name   string
+		limits Limits
+		want   string
+	}{
+		{"unlimited", Limits{}, ""},
+		{"within limits", Limits{MaxFileSize: 1000, MaxImportDepth: 2, MaxTypes: 4, MaxFields: 2, MaxAnnotationLength: 100}, ""},
+		{"file size", Limits{MaxFileSize: 100}, "api.typemux: limit exceeded: the file has more than 100 bytes"},
+		{"import depth", Limits{MaxImportDepth: 1}, "audit.typemux: limit exceeded: the file is imported through 2 files, more than 1"},
+		{"types", Limits{MaxTypes: 3}, "audit.typemux: limit exceeded: the schema has 4 types, enums, unions, and services, more than 3"},
+		{"fields", Limits{MaxFields: 1}, "type User has 2 fields, more than 1"},
+		{"annotation", Limits{MaxAnnotationLength: 20}, "an annotation of User.name has"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&Loader{Limits: tt.limits}).Load(filepath.Join(dir, "api.typemux"))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Load failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.want, err)
+			}
+			if !errors.Is(err, ErrLimitExceeded) {
+				t.Errorf("Expected the error to wrap ErrLimitExceeded")
+			}
+			if diagnostic.KindOf(err) != diagnostic.Parse {
+				t.Errorf("Expected a parse error, got kind %d", diagnostic.KindOf(err))
+			}
+		})
+	}
+}
+
+func TestLoadContent_MaxFileSize(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte(fmt.Sprintf("namespace api\n\ntype User {\n  bio: string = 1 @openapi.extension(%q)\n}\n", strings.Repeat("x", 200)))
+
+	loader := &Loader{Limits: Limits{MaxFileSize: 100}}
+	if _, err := loader.LoadContent(filepath.Join(dir, "api.typemux"), content); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected the content to exceed the file size limit, got %v", err)
+	}
+}
+
+func TestLimits_CheckSchema_Examples(t *testing.T) {
+	schema, _, err := ParseFile("api.typemux", []byte(`namespace api
+
+type User {
+  id: string = 1
+}
+
+service UserService {
+  rpc GetUser(User) returns (User)
+    @openapi.example("found", response={"id": "user-1234567890"})
+}`))
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+
+	if err := (Limits{MaxAnnotationLength: 100}).CheckSchema("api.typemux", schema); err != nil {
+		t.Errorf("Expected the example to be within the limit, got %v", err)
+	}
+	err = (Limits{MaxAnnotationLength: 10}).CheckSchema("api.typemux", schema)
+	if err == nil || !strings.Contains(err.Error(), "UserService.GetUser") {
+		t.Errorf("Expected the example to exceed the limit, got %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+// Package loader parses TypeMUX schema files together with the files they import,
+// merging their declarations into a single schema.
+package loader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/diagnostic"
+	"github.com/rasmartins/typemux/internal/lexer"
+	"github.com/rasmartins/typemux/internal/parser"
+	"github.com/rasmartins/typemux/internal/stdlib"
+)
+
+// Loader loads a schema file and every file it imports, directly or indirectly.
+// Each file is parsed once, even when several files import it, and its
+// declarations are merged into the loaded schema once.
+type Loader struct {
+	// Sources holds the content of the files read so far, keyed by absolute path
+	// or standard library import path. Files found here are not read again, and
+	// Check is not called for them, so a map shared by several loads reports the
+	// warnings of a common import once. Optional.
+	Sources map[string][]byte
+
+	// Read reads a file by absolute path or standard library import path, for
+	// schemas that are not on disk. Defaults to ReadFile, reading no further than
+	// Limits.MaxFileSize.
+	Read func(path string) ([]byte, error)
+
+	// Parse parses the content of a single file. Defaults to ParseFile.
+	Parse func(path string, content []byte) (*ast.Schema, []string, error)
+
+	// Check is called with the parse warnings of each file read by the loader,
+	// and fails the load when it returns an error. Optional.
+	Check func(path string, schema *ast.Schema, warnings []string) error
+
+	// Limits bounds the size and complexity of the loaded files, for schemas
+	// from untrusted sources. Optional.
+	Limits Limits
+}
+
+// Load parses the schema file at path with its imports.
+func (l *Loader) Load(path string) (*ast.Schema, error) {
+	return l.load(path, nil)
+}
+
+// LoadContent parses schema content as if it were the file at path, so that its
+// imports are resolved relative to the directory of path.
+func (l *Loader) LoadContent(path string, content []byte) (*ast.Schema, error) {
+	return l.load(path, content)
+}
+
+// load parses the root file, given by its content or read from path, and its imports
+func (l *Loader) load(path string, content []byte) (*ast.Schema, error) {
+	if l.Sources == nil {
+		l.Sources = make(map[string][]byte)
+	}
+	if l.Parse == nil {
+		l.Parse = ParseFile
+	}
+
+	state := &loadState{
+		loader:  l,
+		content: content,
+		loading: make(map[string]bool),
+		loaded:  make(map[string]bool),
+	}
+	if err := state.load(path, 0); err != nil {
+		return nil, err
+	}
+	schema := state.merge()
+
+	// Copy inherited fields now that base types from imports are available, then
+	// derive view types, batch methods, and the operations of long-running methods
+	for _, resolve := range []func() error{schema.ResolveExtends, schema.ResolveViews, schema.ResolveResources, schema.ResolveBatches, schema.ResolveLongRunning} {
+		if err := resolve(); err != nil {
+			return nil, diagnostic.New(diagnostic.Validation, fmt.Errorf("%s: %w", state.rootPath, err),
+				diagnostic.Diagnostic{File: state.rootPath, Severity: diagnostic.SeverityError, Message: err.Error()})
+		}
+	}
+
+	return schema, nil
+}
+
+// loadState collects a schema file and the files it imports
+type loadState struct {
+	loader   *Loader
+	content  []byte          // Content of the root file, when not read from disk
+	rootPath string          // Absolute path of the file being loaded
+	files    []*ast.Schema   // Parsed files, each before the files it imports
+	loading  map[string]bool // Files whose imports are being loaded, to detect cycles
+	loaded   map[string]bool // Files already parsed
+
+	declarations int // Types, enums, unions, and services of the files parsed so far
+}
+
+// load parses a schema file, imported through depth files, then the files it imports
+func (s *loadState) load(filePath string, depth int) error {
+	// Get absolute path to handle relative imports correctly; standard library
+	// files are identified by their import path
+	absPath := filePath
+	if !stdlib.IsImport(filePath) {
+		var err error
+		if absPath, err = filepath.Abs(filePath); err != nil {
+			return fmt.Errorf("failed to resolve path %s: %v", filePath, err)
+		}
+	}
+
+	// Check for circular imports; a file imported through several paths is loaded once
+	if s.loading[absPath] {
+		return diagnostic.New(diagnostic.Parse, fmt.Errorf("circular import detected: %s", absPath))
+	}
+	if s.loaded[absPath] {
+		return nil
+	}
+	if err := s.loader.Limits.checkImportDepth(filePath, depth); err != nil {
+		return err
+	}
+	root := s.rootPath == ""
+	if root {
+		s.rootPath = absPath
+	}
+	s.loading[absPath] = true
+	s.loaded[absPath] = true
+	defer delete(s.loading, absPath)
+
+	// Read the file, unless an earlier load with the same sources read it
+	var content []byte
+	seen := false
+	if root && s.content != nil {
+		content = s.content
+	} else if content, seen = s.loader.Sources[absPath]; !seen {
+		var err error
+		read := s.loader.Read
+		if read == nil {
+			read = s.loader.Limits.readFile
+		}
+		if content, err = read(absPath); err != nil {
+			return err
+		}
+		s.loader.Sources[absPath] = content
+	}
+	if err := s.loader.Limits.CheckSize(filePath, int64(len(content))); err != nil {
+		return err
+	}
+
+	// Parse the file
+	schema, warnings, err := s.loader.Parse(absPath, content)
+	if err != nil {
+		return err
+	}
+	if err := s.loader.Limits.CheckSchema(filePath, schema); err != nil {
+		return err
+	}
+	if err := s.readDocFiles(filePath, absPath, schema); err != nil {
+		return err
+	}
+	s.declarations += Declarations(schema)
+	if err := s.loader.Limits.checkTypes(filePath, s.declarations); err != nil {
+		return err
+	}
+	if !seen && s.loader.Check != nil {
+		if err := s.loader.Check(filePath, schema, warnings); err != nil {
+			return err
+		}
+	}
+	s.files = append(s.files, schema)
+
+	// Load imports relative to the current file
+	baseDir := filepath.Dir(absPath)
+	for _, importPath := range schema.Imports {
+		if err := s.load(ResolveImport(baseDir, importPath), depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// merge adds the declarations of every imported file to the root file, preserving
+// their namespaces, and registers all of them in the type registry of the root file
+func (s *loadState) merge() *ast.Schema {
+	schema, imported := s.files[0], s.files[1:]
+
+	// Size the declaration lists once instead of growing them for each import
+	var enums, types, unions, services int
+	for _, file := range imported {
+		enums += len(file.Enums)
+		types += len(file.Types)
+		unions += len(file.Unions)
+		services += len(file.Services)
+	}
+	schema.Enums = slices.Grow(schema.Enums, enums)
+	schema.Types = slices.Grow(schema.Types, types)
+	schema.Unions = slices.Grow(schema.Unions, unions)
+	schema.Services = slices.Grow(schema.Services, services)
+
+	for _, file := range imported {
+		schema.Enums = append(schema.Enums, file.Enums...)
+		schema.Types = append(schema.Types, file.Types...)
+		schema.Unions = append(schema.Unions, file.Unions...)
+		schema.Services = append(schema.Services, file.Services...)
+
+		// Keep namespace-level annotations (e.g., go_package) of imported namespaces
+		if file.NamespaceAnnotations == nil || file.Namespace == "" || file.Namespace == schema.Namespace {
+			continue
+		}
+		if schema.ImportedNamespaceAnnotations == nil {
+			schema.ImportedNamespaceAnnotations = make(map[string]*ast.FormatAnnotations)
+		}
+		if _, exists := schema.ImportedNamespaceAnnotations[file.Namespace]; !exists {
+			schema.ImportedNamespaceAnnotations[file.Namespace] = file.NamespaceAnnotations
+		}
+	}
+
+	schema.TypeRegistry = ast.NewTypeRegistry()
+	for _, enum := range schema.Enums {
+		schema.TypeRegistry.RegisterEnum(enum)
+	}
+	for _, typ := range schema.Types {
+		schema.TypeRegistry.RegisterType(typ)
+	}
+	for _, union := range schema.Unions {
+		schema.TypeRegistry.RegisterUnion(union)
+	}
+
+	return schema
+}
+
+// ParseFile parses the content of a single schema file without its imports and
+// returns the parse warnings.
+func ParseFile(path string, content []byte) (*ast.Schema, []string, error) {
+	p := parser.New(lexer.New(string(content)))
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 {
+		diagnostics := make([]diagnostic.Diagnostic, len(p.Errors()))
+		for i, msg := range p.Errors() {
+			diagnostics[i] = diagnostic.FromText(path, msg, diagnostic.SeverityError)
+		}
+		return nil, nil, diagnostic.New(diagnostic.Parse, fmt.Errorf("parser errors in %s:\n%s", path, p.PrintErrors()), diagnostics...)
+	}
+	return schema, p.Warnings(), nil
+}
+
+// ReadFile reads a schema file, or a standard library file by its import path.
+func ReadFile(path string) ([]byte, error) {
+	if stdlib.IsImport(path) {
+		return stdlib.Read(path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %v", path, err)
+	}
+	return content, nil
+}
+
+// ResolveImport resolves an import relative to the directory of the importing
+// file, keeping standard library imports as they are.
+func ResolveImport(baseDir, importPath string) string {
+	if stdlib.IsImport(importPath) {
+		return importPath
+	}
+	return filepath.Join(baseDir, importPath)
+}
@@ -0,0 +1,195 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/diagnostic"
+)
+
+// writeFiles writes schema files into a temporary directory and returns it
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"api.typemux": `namespace api
+import "common/base.typemux"
+import "common/audit.typemux"
+
+type User extends Entity {
+  name: string = 2
+}`,
+		"common/base.typemux": `namespace common
+import "audit.typemux"
+
+type Entity {
+  id: string = 1
+}`,
+		"common/audit.typemux": `namespace common
+
+enum Action {
+  CREATE
+}`,
+	})
+
+	var checked []string
+	loader := &Loader{Check: func(path string, schema *ast.Schema, warnings []string) error {
+		checked = append(checked, filepath.Base(path))
+		return nil
+	}}
+	schema, err := loader.Load(filepath.Join(dir, "api.typemux"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(schema.Types) != 2 || len(schema.Enums) != 1 {
+		t.Errorf("Expected 2 types and 1 enum, got %d and %d", len(schema.Types), len(schema.Enums))
+	}
+	if len(schema.Types[0].Fields) != 2 {
+		t.Errorf("Expected User to inherit the id field, got %d fields", len(schema.Types[0].Fields))
+	}
+	if _, ok := schema.TypeRegistry.ResolveType("Action", "api"); !ok {
+		t.Error("Expected imported enum to be registered")
+	}
+	if strings.Join(checked, ",") != "api.typemux,base.typemux,audit.typemux" {
+		t.Errorf("Expected each file to be checked once, got %v", checked)
+	}
+
+	// Files read by an earlier load with the same sources are not checked again
+	checked = nil
+	if _, err := loader.Load(filepath.Join(dir, "common", "base.typemux")); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(checked) != 0 {
+		t.Errorf("Expected no checks for files read before, got %v", checked)
+	}
+}
+
+func TestLoadContent(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"common.typemux": `type Entity {
+  id: string
+}`,
+	})
+
+	content := []byte(`import "common.typemux"
+
+type User {
+  entity: Entity
+}`)
+	schema, err := (&Loader{}).LoadContent(filepath.Join(dir, "inline.typemux"), content)
+	if err != nil {
+		t.Fatalf("LoadContent failed: %v", err)
+	}
+	if len(schema.Types) != 2 {
+		t.Errorf("Expected 2 types, got %d", len(schema.Types))
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.typemux":      `import "b.typemux"`,
+		"b.typemux":      `import "a.typemux"`,
+		"broken.typemux": `type {`,
+		"check.typemux":  `type User {}`,
+	})
+
+	tests := []struct {
+		file   string
+		check  func(string, *ast.Schema, []string) error
+		expect string
+		kind   diagnostic.Kind
+	}{
+		{file: "a.typemux", expect: "circular import detected", kind: diagnostic.Parse},
+		{file: "broken.typemux", expect: "parser errors in", kind: diagnostic.Parse},
+		{file: "missing.typemux", expect: "error reading file", kind: diagnostic.Failure},
+		{
+			file:   "check.typemux",
+			check:  func(string, *ast.Schema, []string) error { return os.ErrInvalid },
+			expect: os.ErrInvalid.Error(),
+			kind:   diagnostic.Failure,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			_, err := (&Loader{Check: tt.check}).Load(filepath.Join(dir, tt.file))
+			if err == nil || !strings.Contains(err.Error(), tt.expect) {
+				t.Errorf("Expected error containing %q, got %v", tt.expect, err)
+			}
+			if kind := diagnostic.KindOf(err); kind != tt.kind {
+				t.Errorf("Expected error kind %d, got %d", tt.kind, kind)
+			}
+		})
+	}
+}
+
+func TestLoad_ParseDiagnostics(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"broken.typemux": "type User {\n  id string\n}\n"})
+	path := filepath.Join(dir, "broken.typemux")
+
+	_, err := (&Loader{}).Load(path)
+	found := diagnostic.Of(err)
+	if len(found) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %+v", found)
+	}
+	if d := found[0]; d.File != path || d.Line != 2 || d.Column == 0 || d.Severity != diagnostic.SeverityError || strings.HasPrefix(d.Message, "Line") {
+		t.Errorf("Unexpected diagnostic %+v", d)
+	}
+}
+
+func TestLoad_DocFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"api.typemux": `import "common/base.typemux"
+
+/// A user.
+/// @doc-file docs/user.md
+type User {
+  /// @doc-file docs/user.md
+  name: string
+}`,
+		"docs/user.md": "# User\n\nLong-form notes.\n",
+		"common/base.typemux": `/// @doc-file entity.md
+type Entity {
+  id: string
+}`,
+		"common/entity.md": "Entity notes.",
+		"missing.typemux": `/// @doc-file docs/missing.md
+type Missing {}`,
+	})
+
+	schema, err := (&Loader{}).Load(filepath.Join(dir, "api.typemux"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	user, entity := schema.Types[0], schema.Types[1]
+	if user.Doc.General != "A user." || user.Doc.Extended != "# User\n\nLong-form notes.\n" {
+		t.Errorf("Expected the doc and the content of docs/user.md, got %+v", user.Doc)
+	}
+	if user.Fields[0].Doc.Extended != user.Doc.Extended {
+		t.Errorf("Expected the field to read docs/user.md too, got %q", user.Fields[0].Doc.Extended)
+	}
+	if entity.Doc.Extended != "Entity notes." {
+		t.Errorf("Expected docs relative to the imported file, got %q", entity.Doc.Extended)
+	}
+
+	_, err = (&Loader{}).Load(filepath.Join(dir, "missing.typemux"))
+	if err == nil || !strings.Contains(err.Error(), "@doc-file docs/missing.md") {
+		t.Errorf("Expected an error for the missing doc file, got %v", err)
+	}
+}
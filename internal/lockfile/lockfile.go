@@ -0,0 +1,170 @@
+// Package lockfile keeps Protobuf field numbers stable across schema changes.
+//
+// Fields without an explicit number are numbered by declaration order, so
+// reordering or removing fields silently changes the wire format. A lock file
+// (typemux.lock) records the number assigned to every field. Later runs reuse
+// the recorded numbers, and numbers of removed fields are never reused.
+package lockfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is the conventional name of the lock file.
+const DefaultPath = "typemux.lock"
+
+// header is written at the top of every lock file
+const header = "# Field numbers assigned by TypeMUX. Commit this file and do not edit it by hand.\n"
+
+// LockFile records the Protobuf field numbers of every type, keyed by the
+// qualified type name and the field name.
+type LockFile struct {
+	Version int                       `yaml:"version"`
+	Types   map[string]map[string]int `yaml:"types"`
+
+	changed bool
+}
+
+// New creates an empty lock file.
+func New() *LockFile {
+	return &LockFile{
+		Version: 1,
+		Types:   make(map[string]map[string]int),
+	}
+}
+
+// Load reads a lock file. A missing file yields an empty lock file.
+func Load(path string) (*LockFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	lock := New()
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file %s: %w", path, err)
+	}
+	if lock.Types == nil {
+		lock.Types = make(map[string]map[string]int)
+	}
+	return lock, nil
+}
+
+// Save writes the lock file.
+func (l *LockFile) Save(path string) error {
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(l); err != nil {
+		return fmt.Errorf("failed to encode lock file: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+	l.changed = false
+	return nil
+}
+
+// Changed reports whether Apply recorded new numbers since the lock file was loaded or saved.
+func (l *LockFile) Changed() bool {
+	return l.changed
+}
+
+// Apply numbers the unnumbered fields of the schema from the lock file and
+// records the numbers of all fields. Fields that are not in the lock file yet
+// get the number the Protobuf generator would give them, or the next number
+// after all numbers used or recorded for the type when the type is already locked.
+func (l *LockFile) Apply(schema *ast.Schema) error {
+	var errs []error
+	for _, typ := range schema.Types {
+		if err := l.applyType(typ); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applyType numbers and records the fields of a single type
+func (l *LockFile) applyType(typ *ast.Type) error {
+	name := typ.Name
+	if typ.Namespace != "" {
+		name = typ.Namespace + "." + typ.Name
+	}
+
+	locked, exists := l.Types[name]
+	if !exists {
+		locked = make(map[string]int)
+	}
+
+	// Numbers in use: explicit numbers, and every number recorded for the type
+	next := 1
+	for _, number := range locked {
+		next = max(next, number+1)
+	}
+	for _, field := range protoFields(typ) {
+		if field.HasNumber {
+			next = max(next, field.Number+1)
+		}
+	}
+
+	// Unlocked types are numbered like the Protobuf generator, so adding a lock file keeps the wire format
+	autoNumber := 1
+	for _, field := range protoFields(typ) {
+		switch {
+		case field.HasNumber:
+			autoNumber = max(autoNumber, field.Number+1)
+		case !exists:
+			field.Number = autoNumber
+			autoNumber++
+		default:
+			if number, ok := locked[field.Name]; ok {
+				field.Number = number
+			} else {
+				field.Number = next
+				next++
+			}
+		}
+		field.HasNumber = true
+
+		if locked[field.Name] != field.Number {
+			locked[field.Name] = field.Number
+			l.changed = true
+		}
+	}
+	if len(locked) > 0 {
+		l.Types[name] = locked
+	}
+
+	// A field may hold a locked number that an explicit number now also uses
+	owners := make(map[int]string)
+	var errs []error
+	for _, field := range protoFields(typ) {
+		if owner, ok := owners[field.Number]; ok {
+			errs = append(errs, fmt.Errorf("%s: fields %s and %s both use field number %d", name, owner, field.Name, field.Number))
+			continue
+		}
+		owners[field.Number] = field.Name
+	}
+	return errors.Join(errs...)
+}
+
+// protoFields returns the fields of a type that become Protobuf message fields
+func protoFields(typ *ast.Type) []*ast.Field {
+	var fields []*ast.Field
+	for _, field := range typ.Fields {
+		if field.ShouldIncludeInGenerator("proto") && len(field.Arguments) == 0 {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
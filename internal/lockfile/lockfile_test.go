@@ -0,0 +1,151 @@
+package lockfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func userType(fields ...*ast.Field) *ast.Schema {
+	return &ast.Schema{
+		Types: []*ast.Type{{Name: "User", Namespace: "com.example", Fields: fields}},
+	}
+}
+
+func fieldNumbers(schema *ast.Schema) map[string]int {
+	numbers := make(map[string]int)
+	for _, field := range schema.Types[0].Fields {
+		if field.HasNumber {
+			numbers[field.Name] = field.Number
+		}
+	}
+	return numbers
+}
+
+func TestApply_FirstRunMatchesDeclarationOrder(t *testing.T) {
+	schema := userType(
+		&ast.Field{Name: "id"},
+		&ast.Field{Name: "email", Number: 5, HasNumber: true},
+		&ast.Field{Name: "name"},
+		&ast.Field{Name: "password", ExcludeFrom: []string{"proto"}},
+	)
+
+	lock := New()
+	if err := lock.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !lock.Changed() {
+		t.Error("Expected lock file to change")
+	}
+
+	expected := map[string]int{"id": 1, "email": 5, "name": 6}
+	got := fieldNumbers(schema)
+	for name, number := range expected {
+		if got[name] != number {
+			t.Errorf("Expected %s = %d, got %d", name, number, got[name])
+		}
+		if lock.Types["com.example.User"][name] != number {
+			t.Errorf("Expected %s locked to %d, got %d", name, number, lock.Types["com.example.User"][name])
+		}
+	}
+	if _, ok := lock.Types["com.example.User"]["password"]; ok {
+		t.Error("Fields excluded from protobuf should not be locked")
+	}
+}
+
+func TestApply_KeepsNumbersWhenFieldsChange(t *testing.T) {
+	lock := New()
+	lock.Types["com.example.User"] = map[string]int{"id": 1, "email": 2, "name": 3}
+
+	// email removed, name moved first, phone added
+	schema := userType(
+		&ast.Field{Name: "name"},
+		&ast.Field{Name: "id"},
+		&ast.Field{Name: "phone"},
+	)
+	if err := lock.Apply(schema); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := map[string]int{"name": 3, "id": 1, "phone": 4}
+	got := fieldNumbers(schema)
+	for name, number := range expected {
+		if got[name] != number {
+			t.Errorf("Expected %s = %d, got %d", name, number, got[name])
+		}
+	}
+	if lock.Types["com.example.User"]["email"] != 2 {
+		t.Error("Removed fields should stay locked so their numbers are not reused")
+	}
+}
+
+func TestApply_UnchangedSchema(t *testing.T) {
+	lock := New()
+	lock.Types["com.example.User"] = map[string]int{"id": 1, "name": 2}
+
+	if err := lock.Apply(userType(&ast.Field{Name: "id"}, &ast.Field{Name: "name"})); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if lock.Changed() {
+		t.Error("Expected lock file to be unchanged")
+	}
+}
+
+func TestApply_ExplicitNumberConflict(t *testing.T) {
+	lock := New()
+	lock.Types["com.example.User"] = map[string]int{"id": 1, "name": 2}
+
+	schema := userType(
+		&ast.Field{Name: "id"},
+		&ast.Field{Name: "name"},
+		&ast.Field{Name: "email", Number: 2, HasNumber: true},
+	)
+	err := lock.Apply(schema)
+	if err == nil {
+		t.Fatal("Expected a conflict error")
+	}
+	if !strings.Contains(err.Error(), "fields name and email both use field number 2") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultPath)
+
+	lock, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load of a missing file failed: %v", err)
+	}
+	if len(lock.Types) != 0 {
+		t.Errorf("Expected an empty lock file, got %v", lock.Types)
+	}
+
+	if err := lock.Apply(userType(&ast.Field{Name: "id"}, &ast.Field{Name: "name"})); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := lock.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if lock.Changed() {
+		t.Error("Expected Save to reset the changed state")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# Field numbers assigned by TypeMUX.") {
+		t.Errorf("Expected header comment, got:\n%s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Types["com.example.User"]["name"] != 2 {
+		t.Errorf("Expected name locked to 2, got %v", loaded.Types)
+	}
+}
@@ -0,0 +1,90 @@
+// Package logging prints the progress messages of the compiler at a verbosity
+// level, apart from the results commands write to stdout, so that piping the
+// output of a command is not disturbed by what it reports along the way.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Level is how much a logger prints.
+type Level int
+
+const (
+	// Quiet prints no progress messages; errors and warnings are reported regardless.
+	Quiet Level = iota
+	// Normal prints the main steps, such as the files each format generated.
+	Normal
+	// Verbose adds how long parsing and each generator took.
+	Verbose
+	// Debug adds details such as parse cache hits and every file written.
+	Debug
+)
+
+// Logger prints messages up to its level.
+type Logger struct {
+	out   io.Writer
+	level Level
+}
+
+// New returns a logger that prints messages up to a level to out.
+func New(out io.Writer, level Level) *Logger {
+	return &Logger{out: out, level: level}
+}
+
+// Level returns the level of the logger.
+func (l *Logger) Level() Level {
+	return l.level
+}
+
+// SetLevel changes the level of the logger.
+func (l *Logger) SetLevel(level Level) {
+	l.level = level
+}
+
+// Enabled reports whether the logger prints messages of a level.
+func (l *Logger) Enabled(level Level) bool {
+	return level <= l.level
+}
+
+// Infof prints a progress message at the Normal level.
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(Normal, format, args...)
+}
+
+// Verbosef prints a message at the Verbose level.
+func (l *Logger) Verbosef(format string, args ...interface{}) {
+	l.logf(Verbose, format, args...)
+}
+
+// Debugf prints a message at the Debug level.
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.logf(Debug, format, args...)
+}
+
+// logf prints a message, ending it with a newline, if the level is enabled
+func (l *Logger) logf(level Level, format string, args ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+	if !strings.HasSuffix(message, "\n") {
+		message += "\n"
+	}
+	_, _ = io.WriteString(l.out, message) //nolint:errcheck // nothing left to report to
+}
+
+// Duration formats a duration for timing messages, rounded to a readable precision.
+func Duration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
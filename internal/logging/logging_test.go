@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLogger_Levels(t *testing.T) {
+	tests := []struct {
+		level    Level
+		expected string
+	}{
+		{Quiet, ""},
+		{Normal, "info 1\n"},
+		{Verbose, "info 1\nverbose 2\n"},
+		{Debug, "info 1\nverbose 2\ndebug 3\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		logger := New(&buf, tt.level)
+		logger.Infof("info %d", 1)
+		logger.Verbosef("verbose %d\n", 2)
+		logger.Debugf("debug %d", 3)
+		if buf.String() != tt.expected {
+			t.Errorf("Level %d: got %q, want %q", tt.level, buf.String(), tt.expected)
+		}
+	}
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, Normal)
+	logger.SetLevel(Quiet)
+	logger.Infof("hidden")
+	if buf.Len() != 0 || logger.Level() != Quiet || logger.Enabled(Normal) {
+		t.Errorf("Expected a quiet logger, got %q", buf.String())
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		1234567 * time.Nanosecond:    "1.23ms",
+		2345678901 * time.Nanosecond: "2.346s",
+		4567 * time.Nanosecond:       "5µs",
+	}
+	for d, expected := range tests {
+		if got := Duration(d); got != expected {
+			t.Errorf("Duration(%d) = %q, want %q", d, got, expected)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/lexer"
+	"github.com/rasmartins/typemux/internal/parser"
+	"github.com/rasmartins/typemux/internal/stdlib"
+)
+
+// parserMessage matches the "Line L:C - message" form of parser errors and warnings
+var parserMessage = regexp.MustCompile(`^Line (\d+):(\d+) - (.*)$`)
+
+// name is an identifier in a document, with the dotted parts of qualified names
+// and annotation names (com.example.User, http.method) joined
+type name struct {
+	text  string
+	rng   Range
+	prev  lexer.TokenType // Token before the name
+	next  lexer.TokenType // Token after the name
+	depth int             // Parenthesis depth, non-zero inside annotation arguments and rpc signatures
+}
+
+// isAnnotation reports whether the name follows an @
+func (n *name) isAnnotation() bool {
+	return n.prev == lexer.TOKEN_AT
+}
+
+// symbol is a type, enum, union, or service definition
+type symbol struct {
+	name      string
+	namespace string
+	kind      string // "type", "enum", "union", or "service"
+	uri       string
+	rng       Range
+}
+
+// qualifiedName returns the name of the symbol prefixed with its namespace
+func (s *symbol) qualifiedName() string {
+	if s.namespace == "" {
+		return s.name
+	}
+	return s.namespace + "." + s.name
+}
+
+// importRef is an import statement and the range of its path
+type importRef struct {
+	path string
+	rng  Range
+}
+
+// document is the analysis of a single schema file
+type document struct {
+	uri         string
+	text        string
+	schema      *ast.Schema
+	names       []*name
+	symbols     []*symbol
+	imports     []importRef
+	diagnostics []Diagnostic // Parser errors and warnings
+}
+
+// analyze parses a schema file and indexes its names and definitions
+func analyze(uri, text string) *document {
+	doc := &document{uri: uri, text: text}
+
+	p := parser.New(lexer.New(text))
+	doc.schema = p.Parse()
+	doc.tokenize()
+
+	for _, msg := range p.Errors() {
+		doc.diagnostics = append(doc.diagnostics, doc.parserDiagnostic(msg, SeverityError))
+	}
+	for _, msg := range p.Warnings() {
+		doc.diagnostics = append(doc.diagnostics, doc.parserDiagnostic(msg, SeverityWarning))
+	}
+
+	return doc
+}
+
+// tokenize collects the names, definitions, and imports of the document
+func (d *document) tokenize() {
+	var tokens []lexer.Token
+	l := lexer.New(d.text)
+	for {
+		tok := l.NextToken()
+		if tok.Type == lexer.TOKEN_EOF {
+			if tok.Literal == "" {
+				break
+			}
+			continue // Unexpected character
+		}
+		tokens = append(tokens, tok)
+	}
+
+	tokenAt := func(i int) lexer.TokenType {
+		if i < 0 || i >= len(tokens) {
+			return lexer.TOKEN_EOF
+		}
+		return tokens[i].Type
+	}
+
+	depth := 0
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.Type {
+		case lexer.TOKEN_LPAREN:
+			depth++
+		case lexer.TOKEN_RPAREN:
+			depth = max(depth-1, 0)
+		case lexer.TOKEN_STRING:
+			if tokenAt(i-1) == lexer.TOKEN_IMPORT {
+				d.imports = append(d.imports, importRef{path: tok.Literal, rng: tokenRange(tok, len(tok.Literal)+2)})
+			}
+		case lexer.TOKEN_IDENT:
+			// Join IDENT (DOT IDENT)* written without spaces
+			start, end := i, i
+			text := tok.Literal
+			for end+2 < len(tokens) && tokens[end+1].Type == lexer.TOKEN_DOT && tokens[end+2].Type == lexer.TOKEN_IDENT &&
+				adjacent(tokens[end], tokens[end+1]) && adjacent(tokens[end+1], tokens[end+2]) {
+				text += "." + tokens[end+2].Literal
+				end += 2
+			}
+			last := tokens[end]
+			n := &name{
+				text:  text,
+				rng:   Range{Start: tokenRange(tokens[start], 0).Start, End: tokenRange(last, len(last.Literal)).End},
+				prev:  tokenAt(start - 1),
+				next:  tokenAt(end + 1),
+				depth: depth,
+			}
+			d.names = append(d.names, n)
+
+			switch n.prev {
+			case lexer.TOKEN_TYPE, lexer.TOKEN_ENUM, lexer.TOKEN_UNION, lexer.TOKEN_SERVICE:
+				if depth == 0 {
+					d.symbols = append(d.symbols, &symbol{
+						name:      n.text,
+						namespace: d.schema.Namespace,
+						kind:      tokens[start-1].Literal,
+						uri:       d.uri,
+						rng:       n.rng,
+					})
+				}
+			}
+			i = end
+		}
+	}
+}
+
+// adjacent reports whether b directly follows a without whitespace
+func adjacent(a, b lexer.Token) bool {
+	return a.Line == b.Line && a.Column+len(a.Literal) == b.Column
+}
+
+// tokenRange returns the range of a token of the given length
+func tokenRange(tok lexer.Token, length int) Range {
+	start := Position{Line: tok.Line - 1, Character: tok.Column - 1}
+	return Range{Start: start, End: Position{Line: start.Line, Character: start.Character + length}}
+}
+
+// parserDiagnostic converts a parser error or warning into a diagnostic covering the name at its position
+func (d *document) parserDiagnostic(msg string, severity int) Diagnostic {
+	diag := Diagnostic{Severity: severity, Source: "typemux", Message: msg}
+	match := parserMessage.FindStringSubmatch(msg)
+	if match == nil {
+		return diag
+	}
+
+	line, _ := strconv.Atoi(match[1])   //nolint:errcheck // matched digits
+	column, _ := strconv.Atoi(match[2]) //nolint:errcheck // matched digits
+	pos := Position{Line: max(line-1, 0), Character: max(column-1, 0)}
+	diag.Message = match[3]
+	diag.Range = Range{Start: pos, End: Position{Line: pos.Line, Character: pos.Character + 1}}
+	if n := d.nameAt(pos); n != nil {
+		diag.Range = n.rng
+	}
+	return diag
+}
+
+// nameAt returns the name at a position, if any
+func (d *document) nameAt(pos Position) *name {
+	for _, n := range d.names {
+		if n.rng.contains(pos) {
+			return n
+		}
+	}
+	return nil
+}
+
+// typeReferences returns the type names the schema refers to in base types, fields, arguments, unions, and methods
+func (d *document) typeReferences() map[string]bool {
+	refs := make(map[string]bool)
+	var addFieldType func(ft *ast.FieldType)
+	addFieldType = func(ft *ast.FieldType) {
+		if ft == nil {
+			return
+		}
+		if ft.IsMap {
+			addFieldType(ft.GetMapValueType())
+			return
+		}
+		refs[ft.Name] = true
+	}
+
+	for _, typ := range d.schema.Types {
+		for _, base := range typ.Extends {
+			refs[base] = true
+		}
+		for _, field := range typ.Fields {
+			addFieldType(field.Type)
+			for _, arg := range field.Arguments {
+				addFieldType(arg.Type)
+			}
+		}
+	}
+	for _, union := range d.schema.Unions {
+		for _, option := range union.Options {
+			refs[option] = true
+		}
+	}
+	for _, service := range d.schema.Services {
+		for _, method := range service.Methods {
+			refs[method.InputType] = true
+			refs[method.OutputType] = true
+		}
+	}
+
+	delete(refs, "")
+	for ref := range refs {
+		if ast.IsBuiltinType(ref) {
+			delete(refs, ref)
+		}
+	}
+	return refs
+}
+
+// uriToPath converts a file URI to a file system path
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// pathToURI converts a file system path to a file URI
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// stdlibScheme is the URI scheme of standard library files, whose URIs are the
+// scheme followed by the import path (typemux:typemux/std/money.typemux)
+const stdlibScheme = "typemux"
+
+// resolveImport returns the URI of a file imported by the document
+func (d *document) resolveImport(importPath string) string {
+	if stdlib.IsImport(importPath) {
+		return stdlibScheme + ":" + importPath
+	}
+	if strings.HasPrefix(importPath, "/") {
+		return pathToURI(importPath)
+	}
+	return pathToURI(filepath.Join(filepath.Dir(uriToPath(d.uri)), importPath))
+}
@@ -0,0 +1,66 @@
+package lsp
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalyze_Symbols(t *testing.T) {
+	doc := analyze("file:///schemas/main.typemux", `namespace com.example
+
+type User @proto.name("UserV2") {
+  id: string = 1
+  role: Role = 2
+}
+
+enum Role {
+  ADMIN = 1
+}
+`)
+
+	if len(doc.symbols) != 2 {
+		t.Fatalf("symbols = %d, want 2", len(doc.symbols))
+	}
+	user := doc.symbols[0]
+	if user.qualifiedName() != "com.example.User" || user.kind != "type" {
+		t.Errorf("symbol = %s %s, want type com.example.User", user.kind, user.qualifiedName())
+	}
+	if user.rng != (Range{Start: Position{Line: 2, Character: 5}, End: Position{Line: 2, Character: 9}}) {
+		t.Errorf("symbol range = %+v", user.rng)
+	}
+
+	n := doc.nameAt(Position{Line: 2, Character: 14})
+	if n == nil || n.text != "proto.name" || !n.isAnnotation() {
+		t.Errorf("nameAt(annotation) = %+v, want proto.name annotation", n)
+	}
+
+	refs := doc.typeReferences()
+	if !refs["Role"] || refs["string"] || len(refs) != 1 {
+		t.Errorf("typeReferences() = %v, want only Role", refs)
+	}
+}
+
+func TestAnalyze_ParserDiagnostics(t *testing.T) {
+	doc := analyze("file:///schemas/main.typemux", "type User {\n  id: string = 1 @unknwn\n}\n")
+
+	if len(doc.diagnostics) != 1 {
+		t.Fatalf("diagnostics = %v, want 1", doc.diagnostics)
+	}
+	diag := doc.diagnostics[0]
+	if diag.Severity != SeverityWarning {
+		t.Errorf("severity = %d, want warning", diag.Severity)
+	}
+	if diag.Range.Start != (Position{Line: 1, Character: 18}) {
+		t.Errorf("range = %+v, want the annotation name", diag.Range)
+	}
+}
+
+func TestDocument_ResolveImport(t *testing.T) {
+	dir := t.TempDir()
+	doc := &document{uri: pathToURI(filepath.Join(dir, "api", "main.typemux"))}
+
+	got := uriToPath(doc.resolveImport("../common/types.typemux"))
+	if want := filepath.Join(dir, "common", "types.typemux"); got != want {
+		t.Errorf("resolveImport() = %s, want %s", got, want)
+	}
+}
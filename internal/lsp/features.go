@@ -0,0 +1,308 @@
+package lsp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/annotations"
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/lexer"
+)
+
+// builtinAnnotations is the registry used for annotation hover and completion
+var builtinAnnotations = annotations.GetBuiltinAnnotations()
+
+// annotationPrefix matches an annotation being typed at the end of a line
+var annotationPrefix = regexp.MustCompile(`@([A-Za-z_][A-Za-z0-9_.]*)?$`)
+
+// keywords are the reserved words offered by completion
+var keywords = []string{"namespace", "import", "type", "extends", "enum", "union", "service", "rpc", "returns", "stream", "map"}
+
+// diagnostics returns the problems of a document: parser errors and warnings,
+// missing imports, and type references that resolve to no definition
+func (s *Server) diagnostics(doc *document) []Diagnostic {
+	diags := append([]Diagnostic{}, doc.diagnostics...)
+
+	for _, imp := range doc.imports {
+		if s.load(doc.resolveImport(imp.path)) == nil {
+			diags = append(diags, Diagnostic{Range: imp.rng, Severity: SeverityError, Source: "typemux",
+				Message: fmt.Sprintf("imported file not found: %s", imp.path)})
+		}
+	}
+
+	scope := s.scope(doc)
+	unresolved := make(map[string]bool)
+	for ref := range doc.typeReferences() {
+		if len(resolve(scope, doc, ref)) == 0 {
+			unresolved[ref] = true
+		}
+	}
+	for _, n := range doc.names {
+		if unresolved[n.text] && n.isTypePosition() {
+			diags = append(diags, Diagnostic{Range: n.rng, Severity: SeverityError, Source: "typemux",
+				Message: fmt.Sprintf("unknown type %s", n.text)})
+		}
+	}
+
+	return diags
+}
+
+// isTypePosition reports whether a name can be a type reference: not a definition,
+// field name, annotation, namespace, or annotation argument name or value
+func (n *name) isTypePosition() bool {
+	switch n.prev {
+	case lexer.TOKEN_AT, lexer.TOKEN_NAMESPACE, lexer.TOKEN_TYPE, lexer.TOKEN_ENUM,
+		lexer.TOKEN_UNION, lexer.TOKEN_SERVICE, lexer.TOKEN_RPC, lexer.TOKEN_EQUALS:
+		return false
+	}
+	if n.depth > 0 && n.next == lexer.TOKEN_EQUALS {
+		return false
+	}
+	return n.next != lexer.TOKEN_COLON
+}
+
+// resolve finds the definitions a type name refers to. Qualified names match the
+// namespace; unqualified names prefer the document's namespace.
+func resolve(scope []*document, doc *document, typeName string) []*symbol {
+	var local, all []*symbol
+	for _, d := range scope {
+		for _, sym := range d.symbols {
+			switch {
+			case sym.qualifiedName() == typeName:
+				return []*symbol{sym}
+			case sym.name == typeName:
+				all = append(all, sym)
+				if sym.namespace == doc.schema.Namespace {
+					local = append(local, sym)
+				}
+			}
+		}
+	}
+	if len(local) > 0 {
+		return local
+	}
+	return all
+}
+
+// definition returns the definitions of the type name at a position
+func (s *Server) definition(params textDocumentPositionParams) []Location {
+	doc := s.documents[params.TextDocument.URI]
+	if doc == nil {
+		return nil
+	}
+	n := doc.nameAt(params.Position)
+	if n == nil || n.isAnnotation() {
+		return nil
+	}
+
+	locations := []Location{}
+	for _, sym := range resolve(s.scope(doc), doc, n.text) {
+		locations = append(locations, Location{URI: sym.uri, Range: sym.rng})
+	}
+	return locations
+}
+
+// hover describes the annotation, builtin type, or definition at a position
+func (s *Server) hover(params textDocumentPositionParams) *Hover {
+	doc := s.documents[params.TextDocument.URI]
+	if doc == nil {
+		return nil
+	}
+	n := doc.nameAt(params.Position)
+	if n == nil {
+		return nil
+	}
+
+	var text string
+	switch {
+	case n.isAnnotation():
+		meta, ok := builtinAnnotations.Get("@" + n.text)
+		if !ok {
+			return nil
+		}
+		text = describeAnnotation(meta)
+	case ast.IsBuiltinType(n.text):
+		text = fmt.Sprintf("```typemux\n%s\n```\nBuiltin type", n.text)
+	default:
+		symbols := resolve(s.scope(doc), doc, n.text)
+		if len(symbols) == 0 {
+			return nil
+		}
+		text = s.describeSymbol(symbols[0])
+	}
+
+	rng := n.rng
+	return &Hover{Contents: markupContent{Kind: "markdown", Value: text}, Range: &rng}
+}
+
+// describeAnnotation renders the registry entry of an annotation
+func describeAnnotation(meta *annotations.AnnotationMetadata) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%s**\n\n%s\n\nApplies to: %s", meta.Name, meta.Description, strings.Join(meta.Scope, ", ")))
+	if len(meta.Examples) > 0 {
+		sb.WriteString("\n\n```typemux\n")
+		sb.WriteString(strings.Join(meta.Examples, "\n"))
+		sb.WriteString("\n```")
+	}
+	return sb.String()
+}
+
+// describeSymbol renders a definition with its qualified name, members, and documentation
+func (s *Server) describeSymbol(sym *symbol) string {
+	doc := s.load(sym.uri)
+	if doc == nil {
+		return fmt.Sprintf("```typemux\n%s %s\n```", sym.kind, sym.qualifiedName())
+	}
+
+	var header, body strings.Builder
+	var docs *ast.Documentation
+	switch sym.kind {
+	case "type":
+		for _, typ := range doc.schema.Types {
+			if typ.Name == sym.name {
+				docs = typ.Doc
+				if len(typ.Extends) > 0 {
+					header.WriteString(" extends " + strings.Join(typ.Extends, ", "))
+				}
+				for _, field := range typ.Fields {
+					body.WriteString(fmt.Sprintf("  %s: %s\n", field.Name, formatFieldType(field.Type)))
+				}
+				break
+			}
+		}
+	case "enum":
+		for _, enum := range doc.schema.Enums {
+			if enum.Name == sym.name {
+				docs = enum.Doc
+				for _, value := range enum.Values {
+					if value.HasNumber {
+						body.WriteString(fmt.Sprintf("  %s = %d\n", value.Name, value.Number))
+					} else {
+						body.WriteString(fmt.Sprintf("  %s\n", value.Name))
+					}
+				}
+				break
+			}
+		}
+	case "union":
+		for _, union := range doc.schema.Unions {
+			if union.Name == sym.name {
+				docs = union.Doc
+				for _, option := range union.Options {
+					body.WriteString(fmt.Sprintf("  %s\n", option))
+				}
+				break
+			}
+		}
+	case "service":
+		for _, service := range doc.schema.Services {
+			if service.Name == sym.name {
+				docs = service.Doc
+				for _, method := range service.Methods {
+					input, output := method.InputType, method.OutputType
+					if method.InputStream {
+						input = "stream " + input
+					}
+					if method.OutputStream {
+						output = "stream " + output
+					}
+					body.WriteString(fmt.Sprintf("  rpc %s(%s) returns (%s)\n", method.Name, input, output))
+				}
+				break
+			}
+		}
+	}
+
+	text := fmt.Sprintf("```typemux\n%s %s%s {\n%s}\n```", sym.kind, sym.qualifiedName(), header.String(), body.String())
+	if general := docs.GetDoc(""); general != "" {
+		text += "\n" + general
+	}
+	return text
+}
+
+// formatFieldType renders a field type in schema syntax
+func formatFieldType(ft *ast.FieldType) string {
+	if ft == nil {
+		return ""
+	}
+	text := ft.String()
+	if ft.Optional {
+		text += "?"
+	}
+	return text
+}
+
+// completion proposes annotations after @, and otherwise builtin types, keywords, and defined types
+func (s *Server) completion(params textDocumentPositionParams) []CompletionItem {
+	doc := s.documents[params.TextDocument.URI]
+	if doc == nil {
+		return nil
+	}
+
+	items := []CompletionItem{}
+	if match := annotationPrefix.FindStringSubmatch(linePrefix(doc.text, params.Position)); match != nil {
+		typed := len(match[1])
+		rng := Range{Start: Position{Line: params.Position.Line, Character: params.Position.Character - typed}, End: params.Position}
+		for _, meta := range builtinAnnotations.GetAll() {
+			if !strings.HasPrefix(meta.Name[1:], match[1]) {
+				continue
+			}
+			items = append(items, CompletionItem{
+				Label:         meta.Name,
+				Kind:          completionKindProperty,
+				Detail:        strings.Join(meta.Scope, ", "),
+				Documentation: meta.Description,
+				TextEdit:      &textEdit{Range: rng, NewText: meta.Name[1:]},
+			})
+		}
+		return items
+	}
+
+	builtins := make([]string, 0, len(ast.BuiltinTypes))
+	for builtin := range ast.BuiltinTypes {
+		builtins = append(builtins, builtin)
+	}
+	sort.Strings(builtins)
+	for _, builtin := range builtins {
+		items = append(items, CompletionItem{Label: builtin, Kind: completionKindStruct, Detail: "builtin type"})
+	}
+	for _, keyword := range keywords {
+		items = append(items, CompletionItem{Label: keyword, Kind: completionKindKeyword})
+	}
+
+	seen := make(map[string]bool)
+	for _, d := range s.scope(doc) {
+		for _, sym := range d.symbols {
+			if seen[sym.qualifiedName()] {
+				continue
+			}
+			seen[sym.qualifiedName()] = true
+			kind := completionKindClass
+			switch sym.kind {
+			case "enum":
+				kind = completionKindEnum
+			case "union":
+				kind = completionKindInterface
+			case "service":
+				kind = completionKindModule
+			}
+			items = append(items, CompletionItem{Label: sym.name, Kind: kind, Detail: sym.kind + " " + sym.qualifiedName()})
+		}
+	}
+	return items
+}
+
+// linePrefix returns the text of a line before a position
+func linePrefix(text string, pos Position) string {
+	lines := strings.Split(text, "\n")
+	if pos.Line >= len(lines) {
+		return ""
+	}
+	line := lines[pos.Line]
+	if pos.Character < len(line) {
+		return line[:pos.Character]
+	}
+	return line
+}
@@ -0,0 +1,199 @@
+// Package lsp implements a Language Server Protocol server for TypeMUX schema files.
+//
+// It reports parser errors, annotation warnings, unresolved type references, and
+// missing imports as diagnostics, and provides go-to-definition across imports,
+// hover with resolved types, annotations and documentation, and completion for
+// builtin types, keywords, defined types, and annotations.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/stdlib"
+)
+
+// Server is a TypeMUX language server speaking JSON-RPC over a reader and a writer
+type Server struct {
+	out       io.Writer
+	logger    *log.Logger
+	documents map[string]*document // Open documents by URI
+	shutdown  bool
+}
+
+// NewServer creates a language server that logs to the given writer.
+func NewServer(logOutput io.Writer) *Server {
+	return &Server{
+		logger:    log.New(logOutput, "typemux-lsp: ", log.LstdFlags),
+		documents: make(map[string]*document),
+	}
+}
+
+// Run serves requests read from in until the client sends exit or closes the stream.
+// It returns an error when exit arrives without a prior shutdown request.
+func (s *Server) Run(in io.Reader, out io.Writer) error {
+	s.out = out
+	reader := bufio.NewReader(in)
+	for {
+		body, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			s.replyError(nil, codeParseError, err.Error())
+			continue
+		}
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit without shutdown")
+			}
+			return nil
+		}
+		s.handle(&req)
+	}
+}
+
+// handle dispatches a request or notification
+func (s *Server) handle(req *request) {
+	var result interface{}
+	var err error
+
+	switch req.Method {
+	case "initialize":
+		result = map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":   1, // Full document sync
+				"definitionProvider": true,
+				"hoverProvider":      true,
+				"completionProvider": map[string]interface{}{
+					"triggerCharacters": []string{"@", "."},
+				},
+			},
+			"serverInfo": map[string]string{"name": "typemux-lsp"},
+		}
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			s.update(params.TextDocument.URI, params.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err = json.Unmarshal(req.Params, &params); err == nil && len(params.ContentChanges) > 0 {
+			s.update(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			delete(s.documents, params.TextDocument.URI)
+			s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []Diagnostic{}})
+		}
+	case "textDocument/definition":
+		var params textDocumentPositionParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			result = s.definition(params)
+		}
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			if hover := s.hover(params); hover != nil {
+				result = hover
+			}
+		}
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			result = s.completion(params)
+		}
+	default:
+		if req.ID != nil {
+			s.replyError(req.ID, codeMethodNotFound, fmt.Sprintf("method not supported: %s", req.Method))
+		}
+		return
+	}
+
+	if req.ID == nil {
+		if err != nil {
+			s.logger.Printf("%s: %v", req.Method, err)
+		}
+		return
+	}
+	if err != nil {
+		s.replyError(req.ID, codeInvalidParams, err.Error())
+		return
+	}
+	s.send(response{JSONRPC: "2.0", ID: req.ID, Result: result})
+}
+
+// update re-analyzes an open document and publishes its diagnostics
+func (s *Server) update(uri, text string) {
+	doc := analyze(uri, text)
+	s.documents[uri] = doc
+	s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: s.diagnostics(doc)})
+}
+
+// load returns an open document, or analyzes the file or standard library file behind a URI
+func (s *Server) load(uri string) *document {
+	if doc, ok := s.documents[uri]; ok {
+		return doc
+	}
+	var content []byte
+	var err error
+	if importPath, ok := strings.CutPrefix(uri, stdlibScheme+":"); ok {
+		content, err = stdlib.Read(importPath)
+	} else {
+		content, err = os.ReadFile(uriToPath(uri))
+	}
+	if err != nil {
+		return nil
+	}
+	return analyze(uri, string(content))
+}
+
+// scope returns the document and every document it imports, directly or indirectly
+func (s *Server) scope(doc *document) []*document {
+	docs := []*document{doc}
+	seen := map[string]bool{doc.uri: true}
+	for i := 0; i < len(docs); i++ {
+		for _, imp := range docs[i].imports {
+			uri := docs[i].resolveImport(imp.path)
+			if seen[uri] {
+				continue
+			}
+			seen[uri] = true
+			if imported := s.load(uri); imported != nil {
+				docs = append(docs, imported)
+			}
+		}
+	}
+	return docs
+}
+
+// send writes a message to the client
+func (s *Server) send(msg interface{}) {
+	if err := writeMessage(s.out, msg); err != nil {
+		s.logger.Printf("write failed: %v", err)
+	}
+}
+
+// notify sends a notification to the client
+func (s *Server) notify(method string, params interface{}) {
+	s.send(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// replyError sends an error response
+func (s *Server) replyError(id *json.RawMessage, code int, message string) {
+	s.send(errorResponse{JSONRPC: "2.0", ID: id, Error: responseError{Code: code, Message: message}})
+}
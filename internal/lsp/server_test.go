@@ -0,0 +1,221 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// session runs the server over the given messages and returns the messages it sent
+func session(t *testing.T, messages ...string) []map[string]interface{} {
+	t.Helper()
+	var in bytes.Buffer
+	for _, msg := range messages {
+		in.WriteString("Content-Length: ")
+		in.WriteString(strconv.Itoa(len(msg)))
+		in.WriteString("\r\n\r\n")
+		in.WriteString(msg)
+	}
+
+	var out, logs bytes.Buffer
+	if err := NewServer(&logs).Run(&in, &out); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var replies []map[string]interface{}
+	reader := bufio.NewReader(&out)
+	for {
+		body, err := readMessage(reader)
+		if err != nil {
+			break
+		}
+		var reply map[string]interface{}
+		if err := json.Unmarshal(body, &reply); err != nil {
+			t.Fatalf("invalid reply %s: %v", body, err)
+		}
+		replies = append(replies, reply)
+	}
+	return replies
+}
+
+// reply returns the response to the request with the given ID
+func reply(t *testing.T, replies []map[string]interface{}, id float64) map[string]interface{} {
+	t.Helper()
+	for _, r := range replies {
+		if r["id"] == id {
+			return r
+		}
+	}
+	t.Fatalf("no reply to request %v", id)
+	return nil
+}
+
+func didOpen(uri, text string) string {
+	params, _ := json.Marshal(map[string]interface{}{ //nolint:errcheck // static values
+		"textDocument": map[string]string{"uri": uri, "languageId": "typemux", "text": text},
+	})
+	return `{"jsonrpc":"2.0","method":"textDocument/didOpen","params":` + string(params) + `}`
+}
+
+func didChange(uri, text string) string {
+	params, _ := json.Marshal(map[string]interface{}{ //nolint:errcheck // static values
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+		"contentChanges": []map[string]string{{"text": text}},
+	})
+	return `{"jsonrpc":"2.0","method":"textDocument/didChange","params":` + string(params) + `}`
+}
+
+func positionRequest(id int, method, uri string, line, character int) string {
+	params, _ := json.Marshal(map[string]interface{}{ //nolint:errcheck // static values
+		"textDocument": map[string]string{"uri": uri},
+		"position":     Position{Line: line, Character: character},
+	})
+	return `{"jsonrpc":"2.0","id":` + strconv.Itoa(id) + `,"method":"` + method + `","params":` + string(params) + `}`
+}
+
+const (
+	initialize = `{"jsonrpc":"2.0","id":0,"method":"initialize","params":{}}`
+	shutdown   = `{"jsonrpc":"2.0","id":99,"method":"shutdown"}`
+	exit       = `{"jsonrpc":"2.0","method":"exit"}`
+)
+
+func TestServer_Session(t *testing.T) {
+	dir := t.TempDir()
+	common := "namespace com.example.common\n\n/// A postal address\ntype Address {\n  street: string = 1\n  city: string = 2\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "common.typemux"), []byte(common), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	uri := pathToURI(filepath.Join(dir, "main.typemux"))
+	text := strings.Join([]string{
+		`import "common.typemux"`,
+		`namespace com.example.users`,
+		``,
+		`type User {`,
+		`  id: string = 1 @required`,
+		`  address: Address = 2`,
+		`  manager: Missing = 3`,
+		`}`,
+	}, "\n")
+	typing := strings.Replace(text, "= 3\n", "= 3\n  @\n", 1)
+
+	replies := session(t,
+		initialize,
+		didOpen(uri, text),
+		positionRequest(1, "textDocument/definition", uri, 5, 12),
+		positionRequest(2, "textDocument/hover", uri, 5, 12),
+		positionRequest(3, "textDocument/hover", uri, 4, 20),
+		positionRequest(5, "textDocument/completion", uri, 6, 11),
+		didChange(uri, typing),
+		positionRequest(4, "textDocument/completion", uri, 7, 3),
+		`{"jsonrpc":"2.0","id":6,"method":"workspace/symbol","params":{}}`,
+		shutdown,
+		exit,
+	)
+
+	caps := reply(t, replies, 0)["result"].(map[string]interface{})["capabilities"].(map[string]interface{})
+	if caps["definitionProvider"] != true || caps["hoverProvider"] != true {
+		t.Errorf("capabilities = %v", caps)
+	}
+
+	var diagnostics []interface{}
+	for _, r := range replies {
+		if r["method"] == "textDocument/publishDiagnostics" {
+			diagnostics = r["params"].(map[string]interface{})["diagnostics"].([]interface{})
+			break
+		}
+	}
+	if len(diagnostics) != 1 || !strings.Contains(diagnostics[0].(map[string]interface{})["message"].(string), "unknown type Missing") {
+		t.Errorf("diagnostics = %v, want one unknown type Missing", diagnostics)
+	}
+
+	locations := reply(t, replies, 1)["result"].([]interface{})
+	if len(locations) != 1 {
+		t.Fatalf("definition = %v, want one location", locations)
+	}
+	location := locations[0].(map[string]interface{})
+	if !strings.HasSuffix(location["uri"].(string), "/common.typemux") {
+		t.Errorf("definition uri = %v, want common.typemux", location["uri"])
+	}
+	if line := location["range"].(map[string]interface{})["start"].(map[string]interface{})["line"]; line != float64(3) {
+		t.Errorf("definition line = %v, want 3", line)
+	}
+
+	hover := reply(t, replies, 2)["result"].(map[string]interface{})["contents"].(map[string]interface{})["value"].(string)
+	for _, want := range []string{"type com.example.common.Address", "street: string", "A postal address"} {
+		if !strings.Contains(hover, want) {
+			t.Errorf("hover = %q, want it to contain %q", hover, want)
+		}
+	}
+
+	annotationHover := reply(t, replies, 3)["result"].(map[string]interface{})["contents"].(map[string]interface{})["value"].(string)
+	if !strings.Contains(annotationHover, "@required") {
+		t.Errorf("annotation hover = %q, want @required", annotationHover)
+	}
+
+	labels := func(id float64) map[string]bool {
+		found := make(map[string]bool)
+		for _, item := range reply(t, replies, id)["result"].([]interface{}) {
+			found[item.(map[string]interface{})["label"].(string)] = true
+		}
+		return found
+	}
+	if annotations := labels(4); !annotations["@required"] || annotations["string"] {
+		t.Errorf("annotation completion = %v", annotations)
+	}
+	if types := labels(5); !types["string"] || !types["Address"] || !types["User"] || types["@required"] {
+		t.Errorf("type completion = %v", types)
+	}
+
+	if code := reply(t, replies, 6)["error"].(map[string]interface{})["code"]; code != float64(codeMethodNotFound) {
+		t.Errorf("unknown method error code = %v, want %d", code, codeMethodNotFound)
+	}
+}
+
+func TestServer_MissingImport(t *testing.T) {
+	uri := pathToURI(filepath.Join(t.TempDir(), "main.typemux"))
+	replies := session(t, initialize, didOpen(uri, "import \"missing.typemux\"\n\ntype User {\n  id: string = 1\n}\n"), shutdown, exit)
+
+	for _, r := range replies {
+		if r["method"] != "textDocument/publishDiagnostics" {
+			continue
+		}
+		diagnostics := r["params"].(map[string]interface{})["diagnostics"].([]interface{})
+		if len(diagnostics) != 1 || !strings.Contains(diagnostics[0].(map[string]interface{})["message"].(string), "missing.typemux") {
+			t.Errorf("diagnostics = %v, want missing import", diagnostics)
+		}
+		return
+	}
+	t.Error("no diagnostics published")
+}
+
+func TestServer_StandardLibraryImport(t *testing.T) {
+	uri := pathToURI(filepath.Join(t.TempDir(), "main.typemux"))
+	text := "namespace shop\n\nimport \"typemux/std/money.typemux\"\n\ntype Product {\n  price: typemux.std.Money = 1\n}\n"
+	replies := session(t, initialize, didOpen(uri, text), positionRequest(2, "textDocument/hover", uri, 5, 22), shutdown, exit)
+
+	for _, r := range replies {
+		if r["method"] == "textDocument/publishDiagnostics" {
+			if diagnostics := r["params"].(map[string]interface{})["diagnostics"].([]interface{}); len(diagnostics) != 0 {
+				t.Errorf("diagnostics = %v, want none", diagnostics)
+			}
+		}
+	}
+	hover := reply(t, replies, 2)["result"].(map[string]interface{})["contents"].(map[string]interface{})["value"].(string)
+	if !strings.Contains(hover, "type typemux.std.Money {") || !strings.Contains(hover, "currencyCode: string") {
+		t.Errorf("hover = %q, want the standard library Money type", hover)
+	}
+}
+
+func TestServer_ExitWithoutShutdown(t *testing.T) {
+	in := "Content-Length: " + strconv.Itoa(len(exit)) + "\r\n\r\n" + exit
+	var out, logs bytes.Buffer
+	if err := NewServer(&logs).Run(strings.NewReader(in), &out); err == nil {
+		t.Error("Run() error = nil, want error for exit without shutdown")
+	}
+}
@@ -0,0 +1,312 @@
+// Package merge flattens TypeMUX schema files and the files they import into a
+// single schema file, for publishing one self-contained contract.
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/diagnostic"
+	"github.com/rasmartins/typemux/internal/lexer"
+	"github.com/rasmartins/typemux/internal/loader"
+	"github.com/rasmartins/typemux/internal/stdlib"
+)
+
+// Result is a merged schema file.
+type Result struct {
+	// Content is the source of the merged schema file.
+	Content []byte
+
+	// Files are the schema files that were merged, imports included, in the
+	// order they were read.
+	Files []string
+
+	// Duplicates are the qualified names of the declarations that several
+	// files declare identically, and that the merged file declares once.
+	Duplicates []string
+}
+
+// Merge merges schema files, and the .typemux files of directories, with the
+// files they import into one schema file. Declarations keep their namespace,
+// doc comments, and annotations as written; imports are dropped. A declaration
+// several files declare identically is written once, and one they declare
+// differently is a conflict, reported with every other conflict in the error.
+// The merged file is checked to load on its own.
+func Merge(paths []string) (*Result, error) {
+	m := &merger{
+		seen:     make(map[string]bool),
+		declared: make(map[string]*declaration),
+		nsText:   make(map[string]namespaceText),
+	}
+	files, err := schemaFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no schema files in %s", strings.Join(paths, ", "))
+	}
+	for _, file := range files {
+		if err := m.add(file); err != nil {
+			return nil, err
+		}
+	}
+	if len(m.conflicts) > 0 {
+		return nil, diagnostic.New(diagnostic.Validation, fmt.Errorf("conflicting declarations:\n%s", strings.Join(m.conflicts, "\n")))
+	}
+
+	content := m.render()
+	if _, err := (&loader.Loader{}).LoadContent("merged.typemux", content); err != nil {
+		return nil, fmt.Errorf("the merged schema does not load: %w", err)
+	}
+
+	sort.Strings(m.duplicates)
+	return &Result{Content: content, Files: m.files, Duplicates: m.duplicates}, nil
+}
+
+// schemaFiles expands the directories of paths to the .typemux files under
+// them, in lexical order
+func schemaFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() && strings.EqualFold(filepath.Ext(file), ".typemux") {
+				files = append(files, file)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// declaration is a type, enum, union, or service of a schema file
+type declaration struct {
+	kind      string // type, enum, union, or service
+	namespace string
+	name      string
+	source    string // The declaration as written, from its doc comments to its closing brace
+	file      string
+	ast       string // JSON of the parsed declaration, to compare declarations of several files
+}
+
+// namespaceText is the namespace statement of a file that annotates the namespace
+type namespaceText struct {
+	source string
+	file   string
+}
+
+// merger collects the declarations of schema files
+type merger struct {
+	files      []string
+	seen       map[string]bool // Files read so far, by absolute path or standard library import path
+	order      []*declaration
+	declared   map[string]*declaration // Declarations by qualified name
+	namespaces []string                // Namespaces in the order they were first declared in
+	nsText     map[string]namespaceText
+	version    string // @typemux version of the first file that has one
+	schemaVer  string // @version of the first file that has one
+	duplicates []string
+	conflicts  []string
+}
+
+// add reads a schema file and the files it imports, unless it was read already
+func (m *merger) add(path string) error {
+	if !stdlib.IsImport(path) {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve path %s: %v", path, err)
+		}
+		path = abs
+	}
+	if m.seen[path] {
+		return nil
+	}
+	m.seen[path] = true
+
+	content, err := loader.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	schema, _, err := loader.ParseFile(path, content)
+	if err != nil {
+		return err
+	}
+	name := m.displayName(path)
+	m.files = append(m.files, name)
+	if m.version == "" {
+		m.version = schema.TypeMUXVersion
+	}
+	if m.schemaVer == "" {
+		m.schemaVer = schema.Version
+	}
+
+	parsed := parsedDeclarations(schema)
+	for _, decl := range split(lexer.Normalize(string(content))) {
+		if decl.kind == "namespace" {
+			m.addNamespace(decl.namespace, decl.source, name)
+			continue
+		}
+		decl.file = name
+		decl.ast = parsed[decl.kind+" "+qualified(decl.namespace, decl.name)]
+		m.addDeclaration(decl)
+	}
+
+	for _, importPath := range schema.Imports {
+		if err := m.add(loader.ResolveImport(filepath.Dir(path), importPath)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// displayName returns the path of a file relative to the working directory
+// when it is below it, for messages
+func (m *merger) displayName(path string) string {
+	if wd, err := os.Getwd(); err == nil && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// addNamespace records a namespace, and the statement of the first file that
+// annotates it
+func (m *merger) addNamespace(namespace, source, file string) {
+	m.useNamespace(namespace)
+	if strings.Join(strings.Fields(source), " ") == "namespace "+namespace {
+		return
+	}
+	existing, ok := m.nsText[namespace]
+	if !ok {
+		m.nsText[namespace] = namespaceText{source: source, file: file}
+		return
+	}
+	if strings.Join(strings.Fields(existing.source), " ") != strings.Join(strings.Fields(source), " ") {
+		m.conflicts = append(m.conflicts, fmt.Sprintf("namespace %s is annotated differently in %s and %s", namespace, existing.file, file))
+	}
+}
+
+// addDeclaration records a declaration, or reports it as a duplicate or a conflict
+func (m *merger) addDeclaration(decl *declaration) {
+	m.useNamespace(decl.namespace)
+	name := qualified(decl.namespace, decl.name)
+	existing, ok := m.declared[name]
+	switch {
+	case !ok:
+		m.declared[name] = decl
+		m.order = append(m.order, decl)
+	case existing.kind != decl.kind:
+		m.conflicts = append(m.conflicts, fmt.Sprintf("%s is declared as %s in %s and as %s in %s", name, article(existing.kind), existing.file, article(decl.kind), decl.file))
+	case existing.ast != decl.ast:
+		m.conflicts = append(m.conflicts, fmt.Sprintf("%s %s differs between %s and %s", decl.kind, name, existing.file, decl.file))
+	case existing.file != decl.file:
+		m.duplicates = append(m.duplicates, name)
+	}
+}
+
+// useNamespace records the first use of a namespace
+func (m *merger) useNamespace(namespace string) {
+	for _, known := range m.namespaces {
+		if known == namespace {
+			return
+		}
+	}
+	m.namespaces = append(m.namespaces, namespace)
+}
+
+// render writes the merged schema file: the schema annotations, then each
+// namespace statement followed by the declarations of the namespace
+func (m *merger) render() []byte {
+	var sb strings.Builder
+	if m.version != "" {
+		sb.WriteString(fmt.Sprintf("@typemux(%q)\n", m.version))
+	}
+	if m.schemaVer != "" {
+		sb.WriteString(fmt.Sprintf("@version(%q)\n", m.schemaVer))
+	}
+	sb.WriteString("\n// Merged from " + strings.Join(m.files, ", ") + "\n")
+
+	for _, namespace := range m.namespaces {
+		var decls []*declaration
+		for _, decl := range m.order {
+			if decl.namespace == namespace {
+				decls = append(decls, decl)
+			}
+		}
+		_, annotated := m.nsText[namespace]
+		if len(decls) == 0 && !annotated {
+			continue
+		}
+
+		sb.WriteString("\n")
+		if text, ok := m.nsText[namespace]; ok {
+			sb.WriteString(text.source + "\n")
+		} else {
+			sb.WriteString("namespace " + namespace + "\n")
+		}
+		for _, decl := range decls {
+			sb.WriteString("\n" + decl.source + "\n")
+		}
+	}
+	return []byte(sb.String())
+}
+
+// article prefixes a kind of declaration with its indefinite article
+func article(kind string) string {
+	if kind == "enum" {
+		return "an enum"
+	}
+	return "a " + kind
+}
+
+// qualified returns the qualified name of a declaration
+func qualified(namespace, name string) string {
+	return namespace + "." + name
+}
+
+// parsedDeclarations returns the JSON of the declarations of a parsed file, by
+// kind and qualified name
+func parsedDeclarations(schema *ast.Schema) map[string]string {
+	parsed := make(map[string]string)
+	add := func(kind, namespace, name string, decl interface{}) {
+		key := kind + " " + qualified(namespace, name)
+		if _, ok := parsed[key]; ok {
+			return // A declaration repeated in one file, which Validate reports
+		}
+		data, err := json.Marshal(decl)
+		if err != nil {
+			data = []byte(err.Error())
+		}
+		parsed[key] = string(data)
+	}
+	for _, typ := range schema.Types {
+		add("type", typ.Namespace, typ.Name, typ)
+	}
+	for _, enum := range schema.Enums {
+		add("enum", enum.Namespace, enum.Name, enum)
+	}
+	for _, union := range schema.Unions {
+		add("union", union.Namespace, union.Name, union)
+	}
+	for _, service := range schema.Services {
+		add("service", service.Namespace, service.Name, service)
+	}
+	return parsed
+}
@@ -0,0 +1,175 @@
+package merge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes schema files into a temporary directory and returns it
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const commonSchema = `@typemux("1.0.0")
+namespace com.example.common @proto.option(go_package = "example.com/common")
+
+/// A page of results
+type Page {
+    token: string = 1
+    size: int32 = 2 @default(20)
+}
+`
+
+func TestMerge(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"common.typemux": commonSchema,
+		"users.typemux": `@typemux("1.0.0")
+import "common.typemux"
+
+namespace com.example.users
+
+/// A user
+@graphql.directive(@key(fields: "id"))
+type User {
+    id: string = 1 @required
+    page: com.example.common.Page = 2
+}
+
+enum Role { ADMIN USER }
+
+service UserService @http.base_path("/v1/users") {
+    rpc GetUser(User) returns (User) @http.method(GET) @http.path("/{id}")
+}
+`,
+		"orders/orders.typemux": `import "../common.typemux"
+namespace com.example.orders
+
+type Customer {
+    id: string
+}
+
+union Owner {
+    Customer
+}
+
+// Repeated from users.typemux, as in a copied contract
+namespace com.example.users
+
+enum Role { ADMIN USER }
+`,
+	})
+
+	result, err := Merge([]string{filepath.Join(dir, "users.typemux"), filepath.Join(dir, "orders")})
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	content := string(result.Content)
+
+	if len(result.Files) != 3 {
+		t.Errorf("Expected 3 merged files, got %v", result.Files)
+	}
+	if strings.Join(result.Duplicates, ",") != "com.example.users.Role" {
+		t.Errorf("Expected com.example.users.Role to be deduplicated, got %v", result.Duplicates)
+	}
+
+	for _, want := range []string{
+		"@typemux(\"1.0.0\")\n",
+		"namespace com.example.users\n\n/// A user\n@graphql.directive(@key(fields: \"id\"))\ntype User {",
+		"namespace com.example.common @proto.option(go_package = \"example.com/common\")\n\n/// A page of results\ntype Page {\n    token: string = 1\n    size: int32 = 2 @default(20)\n}\n",
+		"service UserService @http.base_path(\"/v1/users\") {",
+		"namespace com.example.orders\n\ntype Customer {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected the merged schema to contain %q, got:\n%s", want, content)
+		}
+	}
+	for _, unwanted := range []string{"import", "Repeated from"} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("Expected no %q in the merged schema, got:\n%s", unwanted, content)
+		}
+	}
+	if strings.Count(content, "enum Role") != 1 || strings.Count(content, "type Page") != 1 || strings.Count(content, "@typemux") != 1 {
+		t.Errorf("Expected every declaration once, got:\n%s", content)
+	}
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.typemux": `namespace shop
+@proto.option(go_package = "example.com/a")
+namespace shop
+
+type Item {
+    id: string
+}
+
+enum Status { ACTIVE }
+`,
+		"b.typemux": `namespace shop
+
+type Item {
+    id: string
+    name: string
+}
+
+type Status {
+    id: string
+}
+`,
+	})
+
+	_, err := Merge([]string{dir})
+	if err == nil {
+		t.Fatal("Expected an error for conflicting declarations")
+	}
+	for _, want := range []string{
+		"conflicting declarations:",
+		"type shop.Item differs between",
+		"shop.Status is declared as an enum in",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected the error to contain %q, got %v", want, err)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	source := `@typemux("1.0.0")
+@version("2.0.0")
+import "typemux/std/money.typemux"
+
+// A plain comment
+/// Ünïcode docs
+@deprecated("use Other") type Thing @proto.name("Thing2") {
+    id: string @openapi.example({"a": "b"})
+}
+namespace other @go.package("other")
+@json.union(tagged) union U { Thing }
+`
+	decls := split(source)
+	var got []string
+	for _, decl := range decls {
+		got = append(got, decl.kind+" "+decl.namespace+"."+decl.name+": "+decl.source)
+	}
+	want := []string{
+		"type api.Thing: /// Ünïcode docs\n@deprecated(\"use Other\") type Thing @proto.name(\"Thing2\") {\n    id: string @openapi.example({\"a\": \"b\"})\n}",
+		"namespace other.: namespace other @go.package(\"other\")",
+		"union other.U: @json.union(tagged) union U { Thing }",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
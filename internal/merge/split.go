@@ -0,0 +1,163 @@
+package merge
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rasmartins/typemux/internal/lexer"
+)
+
+// declarationKinds are the keywords of the declarations of a schema file
+var declarationKinds = map[lexer.TokenType]string{
+	lexer.TOKEN_TYPE:    "type",
+	lexer.TOKEN_ENUM:    "enum",
+	lexer.TOKEN_UNION:   "union",
+	lexer.TOKEN_SERVICE: "service",
+}
+
+// split returns the namespace statements and declarations of the source of a
+// schema file that parses, as written. Namespace statements have the kind
+// namespace. Schema annotations, imports, and plain comments are left out.
+func split(source string) []*declaration {
+	var tokens []lexer.Token
+	l := lexer.New(source)
+	for {
+		tok := l.NextToken()
+		tokens = append(tokens, tok)
+		if tok.Type == lexer.TOKEN_EOF {
+			break
+		}
+	}
+	offsets := newOffsets(source)
+
+	var decls []*declaration
+	namespace := "api"
+	for i := 0; tokens[i].Type != lexer.TOKEN_EOF; {
+		start := i
+		// Doc comments and leading annotations belong to the declaration after them
+		for tokens[i].Type == lexer.TOKEN_DOC_COMMENT || tokens[i].Type == lexer.TOKEN_AT {
+			if tokens[i].Type == lexer.TOKEN_DOC_COMMENT {
+				i++
+				continue
+			}
+			if name := tokens[i+1].Literal; i == start && (name == "typemux" || name == "version") {
+				// Schema annotations are written once at the top of the merged file
+				i = skipAnnotation(tokens, i)
+				start = i
+				continue
+			}
+			i = skipAnnotation(tokens, i)
+		}
+
+		switch tok := tokens[i]; tok.Type {
+		case lexer.TOKEN_NAMESPACE:
+			line := tok.Line
+			var parts []string
+			for i++; tokens[i].Type == lexer.TOKEN_IDENT || tokens[i].Type == lexer.TOKEN_DOT; i++ {
+				if tokens[i].Type == lexer.TOKEN_IDENT {
+					parts = append(parts, tokens[i].Literal)
+				}
+			}
+			// Annotations on the line of the statement annotate the namespace
+			for tokens[i].Type == lexer.TOKEN_AT && tokens[i].Line == line {
+				i = skipAnnotation(tokens, i)
+			}
+			namespace = strings.Join(parts, ".")
+			decls = append(decls, &declaration{
+				kind:      "namespace",
+				namespace: namespace,
+				source:    offsets.slice(tokens[start], tokens[i-1]),
+			})
+		case lexer.TOKEN_IMPORT:
+			i += 2
+		case lexer.TOKEN_TYPE, lexer.TOKEN_ENUM, lexer.TOKEN_UNION, lexer.TOKEN_SERVICE:
+			name := tokens[i+1].Literal
+			// The body starts at the first brace outside the arguments of annotations
+			depth := 0
+			for ; tokens[i].Type != lexer.TOKEN_EOF && (depth > 0 || tokens[i].Type != lexer.TOKEN_LBRACE); i++ {
+				switch tokens[i].Type {
+				case lexer.TOKEN_LPAREN:
+					depth++
+				case lexer.TOKEN_RPAREN:
+					depth--
+				}
+			}
+			i = skipBalanced(tokens, i, lexer.TOKEN_LBRACE, lexer.TOKEN_RBRACE)
+			decls = append(decls, &declaration{
+				kind:      declarationKinds[tok.Type],
+				namespace: namespace,
+				name:      name,
+				source:    offsets.slice(tokens[start], tokens[i-1]),
+			})
+		case lexer.TOKEN_EOF:
+		default:
+			i++
+		}
+	}
+	return decls
+}
+
+// skipAnnotation returns the index of the token after the annotation at i,
+// such as @deprecated, @json.union(tagged), or @graphql.directive(@key(fields: "id"))
+func skipAnnotation(tokens []lexer.Token, i int) int {
+	i += 2 // @ and the name
+	for tokens[i].Type == lexer.TOKEN_DOT && tokens[i+1].Type != lexer.TOKEN_EOF {
+		i += 2
+	}
+	if tokens[i].Type == lexer.TOKEN_LPAREN {
+		i = skipBalanced(tokens, i, lexer.TOKEN_LPAREN, lexer.TOKEN_RPAREN)
+	}
+	return i
+}
+
+// skipBalanced returns the index of the token after the close token matching
+// the open token at i
+func skipBalanced(tokens []lexer.Token, i int, open, close lexer.TokenType) int {
+	depth := 0
+	for ; tokens[i].Type != lexer.TOKEN_EOF; i++ {
+		switch tokens[i].Type {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// offsets converts the lines and columns of tokens to byte offsets in source
+type offsets struct {
+	source string
+	lines  []int // Offset of the start of each line
+}
+
+// newOffsets indexes the lines of source
+func newOffsets(source string) *offsets {
+	o := &offsets{source: source, lines: []int{0}}
+	for i := 0; i < len(source); i++ {
+		if source[i] == '\n' {
+			o.lines = append(o.lines, i+1)
+		}
+	}
+	return o
+}
+
+// offset returns the byte offset of the start of a token, whose column counts
+// characters from 1
+func (o *offsets) offset(tok lexer.Token) int {
+	offset := o.lines[tok.Line-1]
+	for column := 1; column < tok.Column && offset < len(o.source); column++ {
+		_, size := utf8.DecodeRuneInString(o.source[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// slice returns the source from the start of the first token to the end of
+// the last one, which is a brace, a parenthesis, or a name
+func (o *offsets) slice(first, last lexer.Token) string {
+	return o.source[o.offset(first) : o.offset(last)+len(last.Literal)]
+}
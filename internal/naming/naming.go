@@ -0,0 +1,259 @@
+// Package naming converts field names between naming conventions and applies a
+// naming policy to the fields of a schema, so that every output format gets its
+// idiomatic names from one schema.
+package naming
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// Convention is a naming convention of field names.
+type Convention string
+
+const (
+	// Keep leaves names as they are in the schema.
+	Keep Convention = ""
+	// SnakeCase is lower-case words joined by underscores: user_id.
+	SnakeCase Convention = "snake_case"
+	// CamelCase is capitalized words after a lower-case first word: userId.
+	CamelCase Convention = "camelCase"
+	// PascalCase is capitalized words: UserId.
+	PascalCase Convention = "PascalCase"
+)
+
+// ParseConvention returns the convention of a name such as "snake_case";
+// an empty name is Keep.
+func ParseConvention(name string) (Convention, error) {
+	switch c := Convention(name); c {
+	case Keep, SnakeCase, CamelCase, PascalCase:
+		return c, nil
+	}
+	return Keep, fmt.Errorf("unknown naming convention %q (valid: snake_case, camelCase, PascalCase)", name)
+}
+
+// Words splits a name into words at underscores, hyphens, and case changes.
+// Initialisms stay one word: "userID" and "HTTPServer" are "user", "ID" and
+// "HTTP", "Server". Digits belong to the word before them.
+func Words(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = end
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush(i)
+			}
+		}
+	}
+	flush(len(runes))
+	return words
+}
+
+// Apply converts a name to the convention. Capitalized words keep the case of
+// their other letters, so initialisms survive: "user_ID" is "userID" in camelCase.
+func (c Convention) Apply(name string) string {
+	if c == Keep {
+		return name
+	}
+	words := Words(name)
+	for i, word := range words {
+		switch {
+		case c == SnakeCase || (c == CamelCase && i == 0):
+			words[i] = strings.ToLower(word)
+		default:
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	if c == SnakeCase {
+		return strings.Join(words, "_")
+	}
+	return strings.Join(words, "")
+}
+
+// Matches reports whether a name follows the convention.
+func (c Convention) Matches(name string) bool {
+	return c.Apply(name) == name
+}
+
+// Policy is the naming convention of field names in each output format. Keep
+// conventions leave the names of that format alone.
+type Policy struct {
+	Proto   Convention
+	GraphQL Convention
+	JSON    Convention
+	Go      Convention
+}
+
+// Standard is the idiomatic policy: snake_case Protobuf fields, camelCase
+// GraphQL and JSON fields, and PascalCase Go fields.
+var Standard = Policy{Proto: SnakeCase, GraphQL: CamelCase, JSON: CamelCase, Go: PascalCase}
+
+// IsZero reports whether the policy keeps all names.
+func (p Policy) IsZero() bool {
+	return p == Policy{}
+}
+
+// Apply sets the Protobuf, GraphQL, JSON, and Go names of the fields of a
+// schema to their name in the convention of each format. Fields with an
+// explicit @proto.name, @graphql.name, @json.name, or @go.name keep it.
+func (p Policy) Apply(schema *ast.Schema) {
+	for _, typ := range schema.Types {
+		for _, field := range typ.Fields {
+			if field.Annotations == nil {
+				field.Annotations = ast.NewFormatAnnotations()
+			}
+			a := field.Annotations
+			if a.ProtoName == "" && p.Proto != Keep {
+				a.ProtoName = p.Proto.Apply(field.Name)
+			}
+			if a.GraphQLName == "" && p.GraphQL != Keep {
+				a.GraphQLName = p.GraphQL.Apply(field.Name)
+			}
+			if field.JSONName == "" && p.JSON != Keep {
+				field.JSONName = p.JSON.Apply(field.Name)
+			}
+			if a.GoName == "" && p.Go != Keep {
+				a.GoName = p.Go.Apply(field.Name)
+			}
+		}
+	}
+}
+
+// target is an output format of a policy
+type target struct {
+	format     string
+	convention Convention
+	override   func(field *ast.Field) string // Explicit name of a field in the format, if any
+}
+
+// targets returns the formats of the policy that convert names
+func (p Policy) targets() []target {
+	var targets []target
+	for _, t := range []target{
+		{"proto", p.Proto, func(f *ast.Field) string { return annotations(f).ProtoName }},
+		{"graphql", p.GraphQL, func(f *ast.Field) string { return annotations(f).GraphQLName }},
+		{"json", p.JSON, func(f *ast.Field) string { return f.JSONName }},
+		{"go", p.Go, func(f *ast.Field) string { return annotations(f).GoName }},
+	} {
+		if t.convention != Keep {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+// annotations returns the format annotations of a field, empty when it has none
+func annotations(field *ast.Field) *ast.FormatAnnotations {
+	if field.Annotations == nil {
+		return &ast.FormatAnnotations{}
+	}
+	return field.Annotations
+}
+
+// Lint returns the naming style problems of a schema under the policy; run it
+// before Apply. It reports field names that follow no convention, fields in the
+// minority convention of a schema that mixes several, and explicit names that
+// break the convention of their format. Names that collide are left to
+// Collisions.
+func (p Policy) Lint(schema *ast.Schema) []string {
+	var warnings []string
+
+	// Single words such as "id" follow both snake_case and camelCase, so only
+	// names of several words count towards the convention of the schema
+	counts := make(map[Convention]int)
+	for _, typ := range schema.Types {
+		for _, field := range typ.Fields {
+			if field.InheritedFrom == "" && len(Words(field.Name)) > 1 {
+				counts[sourceConvention(field.Name)]++
+			}
+		}
+	}
+	majority, conventions := Keep, 0
+	for _, convention := range []Convention{CamelCase, SnakeCase, PascalCase} {
+		if counts[convention] > 0 {
+			conventions++
+		}
+		if counts[convention] > counts[majority] {
+			majority = convention
+		}
+	}
+
+	for _, typ := range schema.Types {
+		for _, field := range typ.Fields {
+			// Inherited fields are reported on their declaring type
+			if field.InheritedFrom != "" {
+				continue
+			}
+			switch convention := sourceConvention(field.Name); {
+			case convention == Keep:
+				warnings = append(warnings, fmt.Sprintf("field %s.%s mixes naming conventions", typ.Name, field.Name))
+			case conventions > 1 && convention != majority && len(Words(field.Name)) > 1:
+				warnings = append(warnings, fmt.Sprintf("field %s.%s is %s, but most fields are %s", typ.Name, field.Name, convention, majority))
+			}
+
+			for _, t := range p.targets() {
+				if name := t.override(field); name != "" && !t.convention.Matches(name) {
+					warnings = append(warnings, fmt.Sprintf("field %s.%s is named %s in %s, which is not %s", typ.Name, field.Name, name, t.format, t.convention))
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// Collisions returns the fields of a type whose names collide in a format once
+// converted under the policy, such as totalAmount and total_amount; run it
+// before Apply. Outputs with colliding fields do not compile, so these are
+// errors rather than style problems.
+func (p Policy) Collisions(schema *ast.Schema) []string {
+	var errs []string
+	for _, typ := range schema.Types {
+		for _, t := range p.targets() {
+			byName := make(map[string][]string)
+			var names []string
+			for _, field := range typ.Fields {
+				name := t.override(field)
+				if name == "" {
+					name = t.convention.Apply(field.Name)
+				}
+				if byName[name] == nil {
+					names = append(names, name)
+				}
+				byName[name] = append(byName[name], typ.Name+"."+field.Name)
+			}
+			for _, name := range names {
+				if fields := byName[name]; len(fields) > 1 {
+					errs = append(errs, fmt.Sprintf("fields %s have the same %s name %s", strings.Join(fields, ", "), t.format, name))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// sourceConvention returns the convention a schema field name follows, or Keep
+// when it follows none
+func sourceConvention(name string) Convention {
+	for _, convention := range []Convention{CamelCase, SnakeCase, PascalCase} {
+		if convention.Matches(name) {
+			return convention
+		}
+	}
+	return Keep
+}
@@ -0,0 +1,162 @@
+package naming
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+func TestWords(t *testing.T) {
+	tests := map[string][]string{
+		"id":          {"id"},
+		"userId":      {"user", "Id"},
+		"user_id":     {"user", "id"},
+		"userID":      {"user", "ID"},
+		"HTTPServer":  {"HTTP", "Server"},
+		"line2Text":   {"line2", "Text"},
+		"address2":    {"address2"},
+		"_private":    {"private"},
+		"created-at":  {"created", "at"},
+		"line_Items":  {"line", "Items"},
+		"UserAccount": {"User", "Account"},
+	}
+	for name, want := range tests {
+		if got := Words(name); !reflect.DeepEqual(got, want) {
+			t.Errorf("Words(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestConvention_Apply(t *testing.T) {
+	tests := []struct {
+		name                       string
+		snake, camel, pascal       string
+		matchesSnake, matchesCamel bool
+	}{
+		{"id", "id", "id", "Id", true, true},
+		{"userId", "user_id", "userId", "UserId", false, true},
+		{"user_id", "user_id", "userId", "UserId", true, false},
+		{"userID", "user_id", "userID", "UserID", false, true},
+		{"HTTPServer", "http_server", "httpServer", "HTTPServer", false, false},
+		{"line_Items", "line_items", "lineItems", "LineItems", false, false},
+	}
+	for _, tt := range tests {
+		if got := SnakeCase.Apply(tt.name); got != tt.snake {
+			t.Errorf("snake_case of %s = %s, want %s", tt.name, got, tt.snake)
+		}
+		if got := CamelCase.Apply(tt.name); got != tt.camel {
+			t.Errorf("camelCase of %s = %s, want %s", tt.name, got, tt.camel)
+		}
+		if got := PascalCase.Apply(tt.name); got != tt.pascal {
+			t.Errorf("PascalCase of %s = %s, want %s", tt.name, got, tt.pascal)
+		}
+		if got := Keep.Apply(tt.name); got != tt.name {
+			t.Errorf("Keep changed %s to %s", tt.name, got)
+		}
+		if SnakeCase.Matches(tt.name) != tt.matchesSnake || CamelCase.Matches(tt.name) != tt.matchesCamel {
+			t.Errorf("Unexpected matches for %s", tt.name)
+		}
+	}
+}
+
+func TestParseConvention(t *testing.T) {
+	for _, name := range []string{"", "snake_case", "camelCase", "PascalCase"} {
+		if c, err := ParseConvention(name); err != nil || string(c) != name {
+			t.Errorf("ParseConvention(%q) = %q, %v", name, c, err)
+		}
+	}
+	if _, err := ParseConvention("kebab-case"); err == nil {
+		t.Error("Expected an error for an unknown convention")
+	}
+}
+
+// namingTestSchema returns an Order type with fields of the given names
+func namingTestSchema(names ...string) *ast.Schema {
+	typ := &ast.Type{Name: "Order"}
+	for _, name := range names {
+		typ.Fields = append(typ.Fields, &ast.Field{Name: name, Type: &ast.FieldType{Name: "string", IsBuiltin: true}})
+	}
+	return &ast.Schema{Types: []*ast.Type{typ}}
+}
+
+func TestPolicy_Apply(t *testing.T) {
+	schema := namingTestSchema("orderId", "created_at", "customerID")
+	schema.Types[0].Fields[2].Annotations = &ast.FormatAnnotations{ProtoName: "customer", GoName: "Customer"}
+	schema.Types[0].Fields[2].JSONName = "customer"
+
+	Standard.Apply(schema)
+
+	tests := []struct {
+		field                        int
+		proto, graphql, json, goName string
+	}{
+		{0, "order_id", "orderId", "orderId", "OrderId"},
+		{1, "created_at", "createdAt", "createdAt", "CreatedAt"},
+		{2, "customer", "customerID", "customer", "Customer"},
+	}
+	for _, tt := range tests {
+		field := schema.Types[0].Fields[tt.field]
+		got := []string{field.NameFor("proto"), field.NameFor("graphql"), field.JSONName, field.NameFor("go")}
+		want := []string{tt.proto, tt.graphql, tt.json, tt.goName}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Field %s: got %q, want %q", field.Name, got, want)
+		}
+	}
+	if names := []string{schema.Types[0].Fields[0].Name, schema.Types[0].Fields[1].Name}; !reflect.DeepEqual(names, []string{"orderId", "created_at"}) {
+		t.Errorf("Expected the schema names to stay, got %q", names)
+	}
+
+	// Formats the policy keeps are left alone
+	schema = namingTestSchema("created_at")
+	Policy{Proto: SnakeCase}.Apply(schema)
+	if field := schema.Types[0].Fields[0]; field.JSONName != "" || field.NameFor("graphql") != "created_at" || field.NameFor("go") != "created_at" {
+		t.Errorf("Expected only the proto name to be set, got %+v %+v", field, field.Annotations)
+	}
+}
+
+func TestPolicy_Lint(t *testing.T) {
+	schema := namingTestSchema("id", "orderId", "customerId", "created_at", "line_Items", "totalAmount", "total_amount", "status")
+	schema.Types[0].Fields[7].Annotations = &ast.FormatAnnotations{ProtoName: "orderStatus"}
+	schema.Types = append(schema.Types, &ast.Type{
+		Name:   "SpecialOrder",
+		Fields: []*ast.Field{{Name: "created_at", InheritedFrom: "Order"}},
+	})
+
+	got := Standard.Lint(schema)
+	want := []string{
+		"field Order.created_at is snake_case, but most fields are camelCase",
+		"field Order.line_Items mixes naming conventions",
+		"field Order.total_amount is snake_case, but most fields are camelCase",
+		"field Order.status is named orderStatus in proto, which is not snake_case",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lint() =\n%q\nwant\n%q", got, want)
+	}
+
+	// Consistent schemas have nothing to report
+	if warnings := Standard.Lint(namingTestSchema("id", "order_id", "created_at")); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %q", warnings)
+	}
+}
+
+func TestPolicy_Collisions(t *testing.T) {
+	schema := namingTestSchema("id", "totalAmount", "total_amount", "userId", "status")
+	schema.Types[0].Fields[4].Annotations = &ast.FormatAnnotations{GoName: "UserId"}
+
+	got := Standard.Collisions(schema)
+	want := []string{
+		"fields Order.totalAmount, Order.total_amount have the same proto name total_amount",
+		"fields Order.totalAmount, Order.total_amount have the same graphql name totalAmount",
+		"fields Order.totalAmount, Order.total_amount have the same json name totalAmount",
+		"fields Order.totalAmount, Order.total_amount have the same go name TotalAmount",
+		"fields Order.userId, Order.status have the same go name UserId",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Collisions() =\n%q\nwant\n%q", got, want)
+	}
+
+	if errs := Standard.Collisions(namingTestSchema("id", "order_id", "orderStatus")); len(errs) != 0 {
+		t.Errorf("Expected no collisions, got %q", errs)
+	}
+}
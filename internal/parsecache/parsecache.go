@@ -0,0 +1,110 @@
+// Package parsecache stores parsed schema files on disk, keyed by a hash of their
+// content, so files that did not change since an earlier run are not lexed and
+// parsed again. This matters for deep trees of shared imports in monorepos.
+//
+// The cache only holds what the parser produces for a single file, before its
+// imports are merged, so a cached file is valid wherever it is imported from.
+package parsecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rasmartins/typemux/internal/ast"
+)
+
+// formatVersion changes whenever the layout of cache entries changes, which
+// invalidates every entry written before
+const formatVersion = 1
+
+// Entry is the parse result of a schema file.
+type Entry struct {
+	Schema   *ast.Schema `json:"schema"`
+	Warnings []string    `json:"warnings,omitempty"`
+}
+
+// Cache is a directory of parse results.
+type Cache struct {
+	dir  string
+	salt string
+}
+
+// New creates a cache in dir. The salt identifies the compiler build, so that
+// results of a different parser are never reused; entries of other salts are ignored.
+func New(dir, salt string) *Cache {
+	return &Cache{dir: dir, salt: salt}
+}
+
+// DefaultDir returns the default cache directory inside the user cache directory.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "typemux", "parse"), nil
+}
+
+// Dir returns the directory of the cache.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// key returns the name of the entry for a file content
+func (c *Cache) key(content []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d\x00%s\x00", formatVersion, c.salt)
+	hash.Write(content)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// path returns the file of the entry for a file content
+func (c *Cache) path(content []byte) string {
+	return filepath.Join(c.dir, c.key(content)+".json")
+}
+
+// Get returns the parse result of a file content, if it is cached. Every call
+// decodes a new schema, so callers may modify it. Unreadable entries count as misses.
+func (c *Cache) Get(content []byte) (*Entry, bool) {
+	data, err := os.ReadFile(c.path(content))
+	if err != nil {
+		return nil, false
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Schema == nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Put stores the parse result of a file content. The entry is written to a
+// temporary file first, so concurrent runs never read a partial entry.
+func (c *Cache) Put(content []byte, entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode parse cache entry: %w", err)
+	}
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create parse cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write parse cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write parse cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write parse cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(content)); err != nil {
+		return fmt.Errorf("failed to write parse cache entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package parsecache
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/lexer"
+	"github.com/rasmartins/typemux/internal/parser"
+)
+
+const testSchema = `@typemux("1.0.0")
+namespace com.example
+
+import "common.typemux"
+
+/// A user account
+type User @graphql.directive(@key(fields: "id")) {
+  id: string = 1 @required
+  email: string? = 2
+  tags: map<string, string> = 3
+  role: Role = 4
+  age: int32 = 5 @default(18)
+}
+
+enum Role {
+  USER = 0
+  ADMIN = 1
+}
+
+union Principal {
+  User
+}
+
+service UserService {
+  rpc GetUser(GetUserRequest) returns (User) @http.method(GET) @timeout("5s")
+  rpc Ping() returns ()
+}
+`
+
+func parse(t *testing.T, content string) *Entry {
+	t.Helper()
+	p := parser.New(lexer.New(content))
+	schema := p.Parse()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("parser errors: %s", p.PrintErrors())
+	}
+	return &Entry{Schema: schema, Warnings: p.Warnings()}
+}
+
+func TestPutGet_RoundTrip(t *testing.T) {
+	cache := New(t.TempDir(), "test")
+	content := []byte(testSchema)
+	entry := parse(t, testSchema)
+	entry.Warnings = []string{"Line 1:1 - a warning"}
+
+	if _, ok := cache.Get(content); ok {
+		t.Fatal("Expected a miss before Put")
+	}
+	if err := cache.Put(content, entry); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	cached, ok := cache.Get(content)
+	if !ok {
+		t.Fatal("Expected a hit after Put")
+	}
+
+	// Empty slices and maps of the parser come back as nil, so compare the encoded schemas
+	got, err := ast.MarshalSchemaJSON(cached.Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ast.MarshalSchemaJSON(entry.Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Cached schema differs from the parsed schema:\ngot  %s\nwant %s", got, want)
+	}
+	if !reflect.DeepEqual(cached.Warnings, entry.Warnings) {
+		t.Errorf("Expected warnings %v, got %v", entry.Warnings, cached.Warnings)
+	}
+}
+
+func TestGet_ReturnsIndependentCopies(t *testing.T) {
+	cache := New(t.TempDir(), "test")
+	content := []byte(testSchema)
+	if err := cache.Put(content, parse(t, testSchema)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	first, _ := cache.Get(content)
+	first.Schema.Types[0].Name = "Changed"
+
+	second, _ := cache.Get(content)
+	if second.Schema.Types[0].Name != "User" {
+		t.Errorf("Expected an unmodified schema, got type %s", second.Schema.Types[0].Name)
+	}
+}
+
+func TestGet_Misses(t *testing.T) {
+	dir := t.TempDir()
+	cache := New(dir, "test")
+	content := []byte(testSchema)
+	if err := cache.Put(content, parse(t, testSchema)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if _, ok := cache.Get([]byte(testSchema + "\n")); ok {
+		t.Error("Expected a miss for changed content")
+	}
+	if _, ok := New(dir, "other").Get(content); ok {
+		t.Error("Expected a miss for another salt")
+	}
+
+	// Corrupt entries are ignored
+	if err := os.WriteFile(cache.path(content), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get(content); ok {
+		t.Error("Expected a miss for a corrupt entry")
+	}
+}
+
+func TestPut_LeavesNoTemporaryFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "cache")
+	cache := New(dir, "test")
+	if err := cache.Put([]byte(testSchema), parse(t, testSchema)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 1 || filepath.Ext(entries[0].Name()) != ".json" {
+		t.Errorf("Expected a single .json entry, got %v", entries)
+	}
+}
@@ -1,20 +1,51 @@
 package parser
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
 
+	"github.com/rasmartins/typemux/internal/annotations"
 	"github.com/rasmartins/typemux/internal/ast"
 	"github.com/rasmartins/typemux/internal/lexer"
 )
 
+// builtinAnnotations is the registry unknown or misplaced annotations are reported against
+var builtinAnnotations = annotations.GetBuiltinAnnotations()
+
 // Parser transforms a stream of tokens from the lexer into an abstract syntax tree (AST).
 type Parser struct {
-	lexer   *lexer.Lexer
-	curTok  lexer.Token
-	peekTok lexer.Token
-	errors  []string
+	lexer    *lexer.Lexer
+	prevTok  lexer.Token
+	curTok   lexer.Token
+	peekTok  lexer.Token
+	errors   []string
+	warnings []string
+
+	errorPositions []errorPosition // Position of each error, parallel to errors
+
+	pendingAnnotations []annotationUse // Annotations not yet checked against the registry
+}
+
+// annotationUse is an annotation found while parsing. It is checked against the
+// registry once the kind of element it applies to is known.
+type annotationUse struct {
+	name   string // Name without @ (e.g., "required", "proto.name")
+	line   int
+	column int
+}
+
+// errorPosition is the line and column an error was reported at
+type errorPosition struct {
+	line   int
+	column int
+}
+
+// before reports whether the position comes before another
+func (e errorPosition) before(other errorPosition) bool {
+	return e.line < other.line || (e.line == other.line && e.column < other.column)
 }
 
 // New creates a new parser for the given lexer.
@@ -26,17 +57,154 @@ func New(l *lexer.Lexer) *Parser {
 }
 
 func (p *Parser) nextToken() {
+	p.prevTok = p.curTok
 	p.curTok = p.peekTok
 	p.peekTok = p.lexer.NextToken()
+
+	// The lexer reports characters it does not recognize as EOF with a literal
+	for p.peekTok.Type == lexer.TOKEN_EOF && p.peekTok.Literal != "" {
+		p.addErrorAt(p.peekTok, fmt.Sprintf("unexpected character %q", p.peekTok.Literal))
+		p.peekTok = p.lexer.NextToken()
+	}
 }
 
-// Errors returns all parsing errors encountered during parsing.
+// Errors returns all parsing errors encountered during parsing, ordered by position.
 func (p *Parser) Errors() []string {
 	return p.errors
 }
 
 func (p *Parser) addError(msg string) {
-	p.errors = append(p.errors, fmt.Sprintf("Line %d:%d - %s", p.curTok.Line, p.curTok.Column, msg))
+	p.addErrorAt(p.curTok, msg)
+}
+
+// addErrorAt records an error at a token, keeping errors ordered by position.
+// Only the first error at a position is kept, since later ones follow from it.
+func (p *Parser) addErrorAt(tok lexer.Token, msg string) {
+	pos := errorPosition{line: tok.Line, column: tok.Column}
+	i := len(p.errorPositions)
+	for i > 0 && pos.before(p.errorPositions[i-1]) {
+		i--
+	}
+	if i > 0 && p.errorPositions[i-1] == pos {
+		return
+	}
+
+	p.errors = append(p.errors, "")
+	copy(p.errors[i+1:], p.errors[i:])
+	p.errors[i] = fmt.Sprintf("Line %d:%d - %s", tok.Line, tok.Column, msg)
+	p.errorPositions = append(p.errorPositions, errorPosition{})
+	copy(p.errorPositions[i+1:], p.errorPositions[i:])
+	p.errorPositions[i] = pos
+}
+
+// atDeclarationStart reports whether the current token is a top-level keyword
+// (namespace, import, enum, type, union, service) that begins a line
+func (p *Parser) atDeclarationStart() bool {
+	switch p.curTok.Type {
+	case lexer.TOKEN_NAMESPACE, lexer.TOKEN_IMPORT, lexer.TOKEN_ENUM, lexer.TOKEN_TYPE, lexer.TOKEN_UNION, lexer.TOKEN_SERVICE:
+		return p.prevTok.Line == 0 || p.curTok.Line > p.prevTok.Line
+	}
+	return false
+}
+
+// synchronize skips the rest of a malformed declaration so parsing resumes at
+// the next top-level declaration instead of reporting errors for every token
+func (p *Parser) synchronize() {
+	p.pendingAnnotations = nil
+	for p.curTok.Type != lexer.TOKEN_EOF && !p.atDeclarationStart() {
+		p.nextToken()
+	}
+}
+
+// skipMember skips the rest of a malformed field, enum value, union option, or
+// method that started at the given token. Parsing resumes at the first token of a
+// later line outside of parentheses, at the closing brace of the declaration, or
+// at the next top-level declaration.
+func (p *Parser) skipMember(start lexer.Token) {
+	p.pendingAnnotations = nil
+	if p.curTok == start {
+		p.nextToken() // Always make progress
+	}
+
+	depth := 0
+	for p.curTok.Type != lexer.TOKEN_EOF {
+		if depth == 0 {
+			if p.curTok.Type == lexer.TOKEN_RBRACE || p.atDeclarationStart() {
+				return
+			}
+			if p.curTok.Line > start.Line && p.curTok.Line > p.prevTok.Line {
+				return
+			}
+		}
+		switch p.curTok.Type {
+		case lexer.TOKEN_LPAREN, lexer.TOKEN_LBRACE:
+			depth++
+		case lexer.TOKEN_RPAREN, lexer.TOKEN_RBRACE:
+			depth = max(depth-1, 0)
+		}
+		p.nextToken()
+	}
+}
+
+// expectClosingBrace consumes the } that ends the body of a declaration
+func (p *Parser) expectClosingBrace(kind, name string) {
+	if p.curTok.Type == lexer.TOKEN_RBRACE {
+		p.nextToken()
+		return
+	}
+	p.addError(fmt.Sprintf("expected } to close %s %s, got %s", kind, name, p.curTok.Type))
+}
+
+// Warnings returns problems that do not prevent parsing, such as unknown annotations.
+func (p *Parser) Warnings() []string {
+	return p.warnings
+}
+
+// recordAnnotation remembers an annotation used at the given token for checkAnnotations
+func (p *Parser) recordAnnotation(name string, tok lexer.Token) {
+	p.pendingAnnotations = append(p.pendingAnnotations, annotationUse{name: name, line: tok.Line, column: tok.Column})
+}
+
+// hasPendingAnnotation reports whether an annotation was recorded for the
+// element being parsed
+func (p *Parser) hasPendingAnnotation(name string) bool {
+	for _, use := range p.pendingAnnotations {
+		if use.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAnnotations warns about recorded annotations that are unknown or not
+// allowed on the given kind of element, suggesting the closest known annotation
+func (p *Parser) checkAnnotations(scope string) {
+	for _, use := range p.pendingAnnotations {
+		name := "@" + use.name
+		var msg string
+		meta, ok := builtinAnnotations.Get(name)
+		if !ok {
+			meta, ok = annotations.GetExtension(name)
+		}
+		if !ok {
+			msg = fmt.Sprintf("unknown annotation %s on %s", name, scope)
+			suggestion := builtinAnnotations.Suggest(name, scope)
+			if namespace, _, _ := strings.Cut(use.name, "."); annotations.IsExtensionNamespace(namespace) {
+				suggestion = annotations.SuggestExtension(name, scope)
+			}
+			if suggestion != "" {
+				msg += fmt.Sprintf(" (did you mean %s?)", suggestion)
+			}
+		} else if meta.HasScope("schema") {
+			msg = fmt.Sprintf("annotation %s must appear at the top of the file, before documentation comments and declarations", name)
+		} else if !meta.HasScope(scope) {
+			msg = fmt.Sprintf("annotation %s is not supported on %s (allowed on: %s)", name, scope, strings.Join(meta.Scope, ", "))
+		} else {
+			continue
+		}
+		p.warnings = append(p.warnings, fmt.Sprintf("Line %d:%d - %s", use.line, use.column, msg))
+	}
+	p.pendingAnnotations = nil
 }
 
 func (p *Parser) expectToken(t lexer.TokenType) bool {
@@ -72,7 +240,7 @@ func (p *Parser) Parse() *ast.Schema {
 				if p.curTok.Type == lexer.TOKEN_LPAREN {
 					p.nextToken()
 					if p.curTok.Type == lexer.TOKEN_STRING || p.curTok.Type == lexer.TOKEN_IDENT {
-						value := strings.Trim(p.curTok.Literal, "\"'")
+						value := p.curTok.Literal
 						if attrName == "typemux" {
 							schema.TypeMUXVersion = value
 						} else {
@@ -101,17 +269,25 @@ func (p *Parser) Parse() *ast.Schema {
 
 		switch p.curTok.Type {
 		case lexer.TOKEN_NAMESPACE:
+			namespaceLine := p.curTok.Line
 			namespace := p.parseNamespace()
 			if namespace != "" {
 				schema.Namespace = namespace
 
-				// Only store leading annotations if they exist (these would be annotations before the namespace keyword)
-				if leadingAnnotations != nil && (len(leadingAnnotations.Proto) > 0 || len(leadingAnnotations.GraphQL) > 0 || len(leadingAnnotations.OpenAPI) > 0 || len(leadingAnnotations.Go) > 0) {
-					schema.NamespaceAnnotations = leadingAnnotations
+				// Annotations on the same line as the namespace declaration belong to the namespace
+				// (e.g., namespace api @proto.option(go_package = "...")). Annotations on later lines
+				// are leading annotations for the next declaration (type, enum, etc.).
+				trailingAnnotations := ast.NewFormatAnnotations()
+				for p.curTok.Type == lexer.TOKEN_AT && p.curTok.Line == namespaceLine {
+					p.parseSingleAnnotation(trailingAnnotations)
+				}
+				annotations := p.mergeAnnotations(leadingAnnotations, trailingAnnotations)
+				p.checkAnnotations("namespace")
+
+				// Only store annotations if they exist
+				if annotations != nil && (len(annotations.Proto) > 0 || len(annotations.GraphQL) > 0 || len(annotations.OpenAPI) > 0 || len(annotations.Go) > 0 || annotations.Ownership != nil || annotations.Crud != nil) {
+					schema.NamespaceAnnotations = annotations
 				}
-				// Note: We do NOT parse trailing annotations here because annotations that appear
-				// after the namespace declaration should be treated as leading annotations for
-				// the next declaration (type, enum, etc.), not as namespace annotations.
 			}
 		case lexer.TOKEN_AT:
 			// Handle special schema-level annotations before anything else
@@ -124,7 +300,7 @@ func (p *Parser) Parse() *ast.Schema {
 					if p.curTok.Type == lexer.TOKEN_LPAREN {
 						p.nextToken()
 						if p.curTok.Type == lexer.TOKEN_STRING || p.curTok.Type == lexer.TOKEN_IDENT {
-							schema.TypeMUXVersion = strings.Trim(p.curTok.Literal, "\"'")
+							schema.TypeMUXVersion = p.curTok.Literal
 							p.nextToken()
 							p.expectToken(lexer.TOKEN_RPAREN)
 						}
@@ -133,7 +309,7 @@ func (p *Parser) Parse() *ast.Schema {
 					if p.curTok.Type == lexer.TOKEN_LPAREN {
 						p.nextToken()
 						if p.curTok.Type == lexer.TOKEN_STRING || p.curTok.Type == lexer.TOKEN_IDENT {
-							schema.Version = strings.Trim(p.curTok.Literal, "\"'")
+							schema.Version = p.curTok.Literal
 							p.nextToken()
 							p.expectToken(lexer.TOKEN_RPAREN)
 						}
@@ -144,29 +320,43 @@ func (p *Parser) Parse() *ast.Schema {
 			importPath := p.parseImport()
 			if importPath != "" {
 				schema.Imports = append(schema.Imports, importPath)
+			} else {
+				p.synchronize()
 			}
 		case lexer.TOKEN_ENUM:
 			enum := p.parseEnumWithDocAndAnnotations(doc, leadingAnnotations, schema.Namespace)
 			if enum != nil {
 				schema.Enums = append(schema.Enums, enum)
+			} else {
+				p.synchronize()
 			}
 		case lexer.TOKEN_TYPE:
 			typ := p.parseTypeWithDocAndAnnotations(doc, leadingAnnotations, schema.Namespace)
 			if typ != nil {
 				schema.Types = append(schema.Types, typ)
+			} else {
+				p.synchronize()
 			}
 		case lexer.TOKEN_UNION:
 			union := p.parseUnionWithDocAndAnnotations(doc, leadingAnnotations, schema.Namespace)
 			if union != nil {
 				schema.Unions = append(schema.Unions, union)
+			} else {
+				p.synchronize()
 			}
 		case lexer.TOKEN_SERVICE:
 			service := p.parseServiceWithDocAndAnnotations(doc, leadingAnnotations, schema.Namespace)
 			if service != nil {
 				schema.Services = append(schema.Services, service)
+			} else {
+				p.synchronize()
 			}
+		case lexer.TOKEN_EOF:
+			// Documentation or annotations at the end of the file
 		default:
+			p.addError(fmt.Sprintf("unexpected %s, expected a declaration", p.curTok.Type))
 			p.nextToken()
+			p.synchronize()
 		}
 	}
 
@@ -195,18 +385,25 @@ func (p *Parser) parseEnumWithDocAndAnnotations(doc *ast.Documentation, leadingA
 
 	// Merge leading and trailing annotations
 	enum.Annotations = p.mergeAnnotations(leadingAnnotations, trailingAnnotations)
+	enum.Flags = p.hasPendingAnnotation("flags")
+	p.checkAnnotations("enum")
 
 	if !p.expectToken(lexer.TOKEN_LBRACE) {
 		return nil
 	}
 
-	for p.curTok.Type == lexer.TOKEN_IDENT || p.curTok.Type == lexer.TOKEN_DOC_COMMENT {
+	for p.curTok.Type != lexer.TOKEN_RBRACE && p.curTok.Type != lexer.TOKEN_EOF && !p.atDeclarationStart() {
 		// Parse documentation for enum value
 		valueDoc := p.parseDocumentation()
 
+		start := p.curTok
 		if p.curTok.Type != lexer.TOKEN_IDENT {
+			if p.curTok.Type == lexer.TOKEN_RBRACE || p.atDeclarationStart() {
+				break
+			}
 			p.addError("expected enum value name")
-			return nil
+			p.skipMember(start)
+			continue
 		}
 
 		enumValue := &ast.EnumValue{
@@ -225,19 +422,22 @@ func (p *Parser) parseEnumWithDocAndAnnotations(doc *ast.Documentation, leadingA
 					enumValue.Number = num
 					enumValue.HasNumber = true
 				}
+				if enum.Flags && !ast.ValidFlag(num) {
+					p.addError(fmt.Sprintf("flags enum value %s.%s must be 0 or a power of two up to %d, got %s",
+						enum.Name, enumValue.Name, ast.MaxFlag, p.curTok.Literal))
+				}
 				p.nextToken()
 			} else {
 				p.addError("expected number after =")
-				return nil
+				p.skipMember(start)
+				continue
 			}
 		}
 
 		enum.Values = append(enum.Values, enumValue)
 	}
 
-	if !p.expectToken(lexer.TOKEN_RBRACE) {
-		return nil
-	}
+	p.expectClosingBrace("enum", enum.Name)
 
 	return enum
 }
@@ -259,19 +459,47 @@ func (p *Parser) parseTypeWithDocAndAnnotations(doc *ast.Documentation, leadingA
 
 	p.nextToken()
 
+	// Parse base types: extends User, audit.Auditable
+	if p.curTok.Type == lexer.TOKEN_IDENT && p.curTok.Literal == "extends" {
+		p.nextToken()
+		for {
+			if p.curTok.Type != lexer.TOKEN_IDENT {
+				p.addError(fmt.Sprintf("expected base type name after extends, got %s", p.curTok.Type))
+				break
+			}
+			baseName := p.curTok.Literal
+			p.nextToken()
+			for p.curTok.Type == lexer.TOKEN_DOT && p.peekTok.Type == lexer.TOKEN_IDENT {
+				p.nextToken()
+				baseName += "." + p.curTok.Literal
+				p.nextToken()
+			}
+			typ.Extends = append(typ.Extends, baseName)
+			if p.curTok.Type != lexer.TOKEN_COMMA {
+				break
+			}
+			p.nextToken()
+		}
+	}
+
 	// Parse trailing type-level annotations like @graphql.directive(...) @openapi.extension(...)
 	trailingAnnotations := p.parseLeadingAnnotations() // reuse the same method
 
 	// Merge leading and trailing annotations
 	typ.Annotations = p.mergeAnnotations(leadingAnnotations, trailingAnnotations)
+	p.checkAnnotations("type")
 
 	if !p.expectToken(lexer.TOKEN_LBRACE) {
 		return nil
 	}
 
-	for p.curTok.Type == lexer.TOKEN_IDENT || p.curTok.Type == lexer.TOKEN_DOC_COMMENT || p.curTok.Type == lexer.TOKEN_AT {
+	for p.curTok.Type != lexer.TOKEN_RBRACE && p.curTok.Type != lexer.TOKEN_EOF && !p.atDeclarationStart() {
 		// Collect field documentation
 		fieldDoc := p.parseDocumentation()
+		if p.curTok.Type == lexer.TOKEN_RBRACE || p.atDeclarationStart() {
+			break
+		}
+		start := p.curTok
 
 		// Collect field leading annotations and attributes
 		fieldLeadingAnnotations := ast.NewFormatAnnotations()
@@ -286,16 +514,23 @@ func (p *Parser) parseTypeWithDocAndAnnotations(doc *ast.Documentation, leadingA
 				break
 			}
 
+			attrTok := p.curTok
 			attrName := p.curTok.Literal
 			p.nextToken()
 
 			// Check if this is a format-specific annotation (has a dot)
-			if p.curTok.Type == lexer.TOKEN_DOT && (attrName == "proto" || attrName == "graphql" || attrName == "openapi") {
+			if p.curTok.Type == lexer.TOKEN_DOT && (attrName == "proto" || attrName == "graphql" || attrName == "openapi" || attrName == "go") {
 				// This is a format annotation like @proto.name("foo")
-				// Back up and let parseSingleAnnotation handle it
-				p.curTok = lexer.Token{Type: lexer.TOKEN_AT, Literal: "@"}
-				p.parseSingleAnnotation(fieldLeadingAnnotations)
-			} else if p.curTok.Type == lexer.TOKEN_LPAREN {
+				p.parseFormatAnnotation(attrName, attrTok, fieldLeadingAnnotations)
+				continue
+			}
+			if p.curTok.Type == lexer.TOKEN_DOT {
+				p.skipUnhandledAnnotation(attrName, attrTok, fieldLeadingAnnotations)
+				continue
+			}
+
+			p.recordAnnotation(attrName, attrTok)
+			if p.curTok.Type == lexer.TOKEN_LPAREN {
 				// This is an attribute with parameters - store it for later parsing
 				// Mark that it has parameters by storing a special value
 				leadingAttributes[attrName] = "NEEDS_PARSING"
@@ -308,12 +543,12 @@ func (p *Parser) parseTypeWithDocAndAnnotations(doc *ast.Documentation, leadingA
 		field := p.parseFieldWithLeadingAnnotations(fieldDoc, fieldLeadingAnnotations, leadingAttributes)
 		if field != nil {
 			typ.Fields = append(typ.Fields, field)
+		} else {
+			p.skipMember(start)
 		}
 	}
 
-	if !p.expectToken(lexer.TOKEN_RBRACE) {
-		return nil
-	}
+	p.expectClosingBrace("type", typ.Name)
 
 	return typ
 }
@@ -340,25 +575,28 @@ func (p *Parser) parseUnionWithDocAndAnnotations(doc *ast.Documentation, leading
 
 	// Merge leading and trailing annotations
 	union.Annotations = p.mergeAnnotations(leadingAnnotations, trailingAnnotations)
+	p.checkAnnotations("union")
+	if union.Annotations != nil && union.Annotations.UnionEncoding != nil {
+		union.Encoding = union.Annotations.UnionEncoding
+		union.Annotations.UnionEncoding = nil
+	}
 
 	if !p.expectToken(lexer.TOKEN_LBRACE) {
 		return nil
 	}
 
 	// Parse union options (list of type names)
-	for p.curTok.Type != lexer.TOKEN_RBRACE && p.curTok.Type != lexer.TOKEN_EOF {
+	for p.curTok.Type != lexer.TOKEN_RBRACE && p.curTok.Type != lexer.TOKEN_EOF && !p.atDeclarationStart() {
 		if p.curTok.Type == lexer.TOKEN_IDENT {
 			union.Options = append(union.Options, p.curTok.Literal)
 			p.nextToken()
 		} else {
 			p.addError("expected type name in union")
-			p.nextToken()
+			p.skipMember(p.curTok)
 		}
 	}
 
-	if !p.expectToken(lexer.TOKEN_RBRACE) {
-		return nil
-	}
+	p.expectClosingBrace("union", union.Name)
 
 	return union
 }
@@ -369,12 +607,15 @@ func (p *Parser) parseFieldWithLeadingAnnotations(doc *ast.Documentation, leadin
 		return nil
 	}
 
+	nameTok := p.curTok
 	field := &ast.Field{
 		Name:       p.curTok.Literal,
 		Attributes: make(map[string]string),
 		Doc:        doc,
 	}
 
+	p.checkAnnotations("field")
+
 	// Apply leading attributes (like @required)
 	for k, v := range leadingAttributes {
 		field.Attributes[k] = v
@@ -387,8 +628,9 @@ func (p *Parser) parseFieldWithLeadingAnnotations(doc *ast.Documentation, leadin
 
 	// Check for field arguments (like GraphQL field arguments)
 	if p.curTok.Type == lexer.TOKEN_LPAREN {
+		errorCount := len(p.errors)
 		field.Arguments = p.parseFieldArguments()
-		if field.Arguments == nil && len(p.errors) > 0 {
+		if field.Arguments == nil && len(p.errors) > errorCount {
 			return nil
 		}
 	}
@@ -432,8 +674,16 @@ func (p *Parser) parseFieldWithLeadingAnnotations(doc *ast.Documentation, leadin
 			return nil
 		}
 
+		attrTok := p.curTok
 		attrName := p.curTok.Literal
 		p.nextToken()
+		if attrName != "proto" && attrName != "graphql" && attrName != "openapi" && attrName != "go" && attrName != "json" {
+			if p.curTok.Type == lexer.TOKEN_DOT {
+				p.skipUnhandledAnnotation(attrName, attrTok, trailingFieldAnnotations)
+				continue
+			}
+			p.recordAnnotation(attrName, attrTok)
+		}
 
 		if attrName == "required" {
 			field.Required = true
@@ -478,14 +728,45 @@ func (p *Parser) parseFieldWithLeadingAnnotations(doc *ast.Documentation, leadin
 			}
 		} else if attrName == "since" {
 			// Parse @since("2.0.0")
-			if p.curTok.Type == lexer.TOKEN_LPAREN {
+			field.Since = p.parseVersionArgument(attrName)
+		} else if attrName == "until" {
+			// Parse @until(v2)
+			field.Until = p.parseVersionArgument(attrName)
+		} else if attrName == "sensitive" {
+			// Parse @sensitive(pii)
+			if p.curTok.Type != lexer.TOKEN_LPAREN {
+				p.addError("expected (pii), (secret), or (phi) after @sensitive")
+				continue
+			}
+			p.nextToken()
+			if p.curTok.Type == lexer.TOKEN_IDENT || p.curTok.Type == lexer.TOKEN_STRING {
+				kind := strings.Trim(p.curTok.Literal, "\"'")
+				if !ast.IsSensitivityKind(kind) {
+					p.addError(fmt.Sprintf("unknown @sensitive kind %q (expected %s)", kind, strings.Join(ast.SensitivityKinds, ", ")))
+				}
+				field.Sensitivity = kind
 				p.nextToken()
-				if p.curTok.Type == lexer.TOKEN_STRING || p.curTok.Type == lexer.TOKEN_IDENT {
-					field.Since = strings.Trim(p.curTok.Literal, "\"'")
-					p.nextToken()
-					p.expectToken(lexer.TOKEN_RPAREN)
+			}
+			p.expectToken(lexer.TOKEN_RPAREN)
+		} else if attrName == "view" {
+			// Parse @view(summary, detail)
+			if p.curTok.Type != lexer.TOKEN_LPAREN {
+				p.addError("expected (view, ...) after @view")
+				continue
+			}
+			p.nextToken()
+			for p.curTok.Type == lexer.TOKEN_IDENT {
+				field.Views = append(field.Views, p.curTok.Literal)
+				p.nextToken()
+				if p.curTok.Type != lexer.TOKEN_COMMA {
+					break
 				}
+				p.nextToken()
 			}
+			if len(field.Views) == 0 {
+				p.addError(fmt.Sprintf("expected view name in @view, got %s", p.curTok.Type))
+			}
+			p.expectToken(lexer.TOKEN_RPAREN)
 		} else if attrName == "validate" {
 			// Parse @validate(format="email", min=0, max=100, etc.)
 			if field.Validation == nil {
@@ -496,7 +777,7 @@ func (p *Parser) parseFieldWithLeadingAnnotations(doc *ast.Documentation, leadin
 				p.parseValidationRules(field.Validation)
 				p.expectToken(lexer.TOKEN_RPAREN)
 			}
-		} else if attrName == "proto" || attrName == "graphql" || attrName == "openapi" || attrName == "json" {
+		} else if attrName == "proto" || attrName == "graphql" || attrName == "openapi" || attrName == "go" || attrName == "json" {
 			// Parse format-specific annotations like @proto.option([packed = false]), @proto.name("TypeName"), or @json.name("field_name")
 			// Expect a dot
 			if p.curTok.Type != lexer.TOKEN_DOT {
@@ -511,8 +792,14 @@ func (p *Parser) parseFieldWithLeadingAnnotations(doc *ast.Documentation, leadin
 				return nil
 			}
 			subtype := p.curTok.Literal
+			p.recordAnnotation(attrName+"."+subtype, attrTok)
 			p.nextToken()
 
+			if attrName == "graphql" && subtype == "map" {
+				p.parseGraphQLMap(attrTok, trailingFieldAnnotations)
+				continue
+			}
+
 			// Handle JSON annotations specially (some don't require parentheses)
 			if attrName == "json" {
 				if subtype == "nullable" {
@@ -548,6 +835,8 @@ func (p *Parser) parseFieldWithLeadingAnnotations(doc *ast.Documentation, leadin
 						trailingFieldAnnotations.GraphQLName = name
 					} else if attrName == "openapi" {
 						trailingFieldAnnotations.OpenAPIName = name
+					} else if attrName == "go" {
+						trailingFieldAnnotations.GoName = name
 					}
 				} else {
 					// Store in appropriate list for other subtypes
@@ -557,50 +846,63 @@ func (p *Parser) parseFieldWithLeadingAnnotations(doc *ast.Documentation, leadin
 						trailingFieldAnnotations.GraphQL = append(trailingFieldAnnotations.GraphQL, content)
 					} else if attrName == "openapi" {
 						trailingFieldAnnotations.OpenAPI = append(trailingFieldAnnotations.OpenAPI, content)
+					} else if attrName == "go" {
+						trailingFieldAnnotations.Go = append(trailingFieldAnnotations.Go, content)
 					}
 				}
 			}
 		} else {
 			field.Attributes[attrName] = ""
+			p.skipAnnotationArguments()
 		}
 	}
 
 	// Merge leading and trailing field annotations
 	field.Annotations = p.mergeAnnotations(leadingAnnotations, trailingFieldAnnotations)
+	p.checkAnnotations("field")
+
+	if field.Required && field.Type.Optional {
+		p.warnings = append(p.warnings, fmt.Sprintf("Line %d:%d - field %s is marked @required but its type is optional (?); it is treated as optional",
+			nameTok.Line, nameTok.Column, field.Name))
+	}
+	if field.Annotations != nil && field.Annotations.GraphQLMap != nil && !field.Type.IsMap {
+		p.warnings = append(p.warnings, fmt.Sprintf("Line %d:%d - @graphql.map on field %s has no effect: the field is not a map",
+			nameTok.Line, nameTok.Column, field.Name))
+	}
 
 	return field
 }
 
 // parseAnnotationContent reads everything inside annotation parentheses as a string
 func (p *Parser) parseAnnotationContent() string {
-	var content string
+	var content strings.Builder
 	depth := 1 // We're already inside the first (
 
 	for depth > 0 && p.curTok.Type != lexer.TOKEN_EOF {
 		if p.curTok.Type == lexer.TOKEN_LPAREN {
 			depth++
-			content += "("
+			content.WriteString("(")
 		} else if p.curTok.Type == lexer.TOKEN_RPAREN {
 			depth--
 			if depth > 0 {
-				content += ")"
+				content.WriteString(")")
 			}
 		} else if p.curTok.Type == lexer.TOKEN_LBRACKET {
-			content += "["
+			content.WriteString("[")
 		} else if p.curTok.Type == lexer.TOKEN_RBRACKET {
-			content += "]"
+			content.WriteString("]")
 		} else if p.curTok.Type == lexer.TOKEN_COLON {
-			content += ":"
+			content.WriteString(":")
 		} else if p.curTok.Type == lexer.TOKEN_COMMA {
-			content += ", "
+			content.WriteString(", ")
 		} else if p.curTok.Type == lexer.TOKEN_EQUALS {
-			content += " = "
+			content.WriteString(" = ")
 		} else if p.curTok.Type == lexer.TOKEN_AT {
-			content += "@"
+			content.WriteString("@")
 		} else if p.curTok.Type == lexer.TOKEN_STRING {
-			content += "\"" + p.curTok.Literal + "\""
+			content.WriteString(lexer.QuoteString(p.curTok.Literal))
 		} else {
-			content += p.curTok.Literal
+			content.WriteString(p.curTok.Literal)
 		}
 
 		if depth > 0 {
@@ -608,7 +910,101 @@ func (p *Parser) parseAnnotationContent() string {
 		}
 	}
 
-	return content
+	return content.String()
+}
+
+// skipAnnotationArguments skips the parenthesized arguments of an annotation that has no effect
+func (p *Parser) skipAnnotationArguments() {
+	if p.curTok.Type == lexer.TOKEN_LPAREN {
+		p.nextToken()
+		p.parseAnnotationContent()
+		p.expectToken(lexer.TOKEN_RPAREN)
+	}
+}
+
+// skipUnhandledAnnotation records an annotation the parser does not handle in
+// this position, with the current token after its first name part, and skips
+// the rest of its name and its arguments. The arguments of an annotation of a
+// namespace registered by a plugin are parsed into the extensions of target.
+func (p *Parser) skipUnhandledAnnotation(name string, nameTok lexer.Token, target *ast.FormatAnnotations) {
+	for p.curTok.Type == lexer.TOKEN_DOT && p.peekTok.Type == lexer.TOKEN_IDENT {
+		p.nextToken()
+		name += "." + p.curTok.Literal
+		p.nextToken()
+	}
+	p.recordAnnotation(name, nameTok)
+	if meta, ok := annotations.GetExtension("@" + name); ok {
+		p.parseExtensionAnnotation(meta, nameTok, target)
+		return
+	}
+	p.skipAnnotationArguments()
+}
+
+// parseExtensionAnnotation parses the optional arguments of an annotation of a
+// namespace registered by a plugin, positional in the order of its parameters
+// or name=value, and checks them against its parameters
+func (p *Parser) parseExtensionAnnotation(meta *annotations.AnnotationMetadata, nameTok lexer.Token, target *ast.FormatAnnotations) {
+	args := make(map[string]string)
+	kinds := make(map[string]string)
+
+	if p.curTok.Type == lexer.TOKEN_LPAREN {
+		p.nextToken()
+		for position := 0; p.curTok.Type != lexer.TOKEN_RPAREN && p.curTok.Type != lexer.TOKEN_EOF; position++ {
+			if position > 0 && !p.expectToken(lexer.TOKEN_COMMA) {
+				p.parseAnnotationContent()
+				break
+			}
+
+			var param string
+			if p.curTok.Type == lexer.TOKEN_IDENT && p.peekTok.Type == lexer.TOKEN_EQUALS {
+				param = p.curTok.Literal
+				p.nextToken() // consume name
+				p.nextToken() // consume '='
+			} else if position < len(meta.Parameters) {
+				param = meta.Parameters[position].Name
+			} else {
+				p.addError(fmt.Sprintf("too many arguments in %s, expected at most %d", meta.Name, len(meta.Parameters)))
+				p.parseAnnotationContent()
+				break
+			}
+
+			switch {
+			case p.curTok.Type == lexer.TOKEN_STRING:
+				kinds[param] = "string"
+			case p.curTok.Type == lexer.TOKEN_NUMBER:
+				kinds[param] = "number"
+			case p.curTok.Type == lexer.TOKEN_IDENT && (p.curTok.Literal == "true" || p.curTok.Literal == "false"):
+				kinds[param] = "boolean"
+			case p.curTok.Type == lexer.TOKEN_IDENT:
+				kinds[param] = "identifier"
+			default:
+				p.addError(fmt.Sprintf("expected value of %s in %s, got %s", param, meta.Name, p.curTok.Type))
+				p.parseAnnotationContent()
+				p.expectToken(lexer.TOKEN_RPAREN)
+				return
+			}
+			if _, ok := args[param]; ok {
+				p.addError(fmt.Sprintf("duplicate argument %s in %s", param, meta.Name))
+			}
+			args[param] = p.curTok.Literal
+			p.nextToken()
+		}
+		if !p.expectToken(lexer.TOKEN_RPAREN) {
+			return
+		}
+	}
+
+	problems := meta.CheckArguments(args, kinds)
+	for _, problem := range problems {
+		p.addErrorAt(nameTok, problem)
+	}
+	if len(problems) > 0 || target == nil {
+		return
+	}
+	if target.Extensions == nil {
+		target.Extensions = make(map[string]map[string]string)
+	}
+	target.Extensions[strings.TrimPrefix(meta.Name, "@")] = args
 }
 
 // parseGeneratorList parses a comma-separated list of generator names
@@ -668,7 +1064,11 @@ func (p *Parser) parseFieldTypeInternal(allowOptional bool) *ast.FieldType {
 				fieldType.MapValue = elementType.MapValue
 			}
 
-			// Check for optional marker (?) only if allowed
+			// Check for the non-null element marker (!) and then the optional marker (?), only if allowed
+			if allowOptional && p.curTok.Type == lexer.TOKEN_BANG {
+				fieldType.NonNullItems = true
+				p.nextToken()
+			}
 			if allowOptional && p.curTok.Type == lexer.TOKEN_QUESTION {
 				fieldType.Optional = true
 				p.nextToken()
@@ -750,6 +1150,12 @@ func (p *Parser) parseFieldTypeInternal(allowOptional bool) *ast.FieldType {
 	fieldType.Name = strings.Join(nameParts, ".")
 	fieldType.IsBuiltin = ast.IsBuiltinType(fieldType.Name)
 
+	// Only the elements of arrays are marked non-null; fields use @required
+	if allowOptional && p.curTok.Type == lexer.TOKEN_BANG {
+		p.addError(fmt.Sprintf("! marks the elements of an array as non-null, as in []%s!; mark a required field with @required", fieldType.Name))
+		p.nextToken()
+	}
+
 	// Check for optional marker (?) only if allowed at this level
 	if allowOptional && p.curTok.Type == lexer.TOKEN_QUESTION {
 		fieldType.Optional = true
@@ -804,8 +1210,16 @@ func (p *Parser) parseFieldArguments() []*ast.FieldArgument {
 				return nil
 			}
 
+			attrTok := p.curTok
 			attrName := p.curTok.Literal
 			p.nextToken()
+			if attrName != "proto" && attrName != "graphql" && attrName != "openapi" {
+				if p.curTok.Type == lexer.TOKEN_DOT {
+					p.skipUnhandledAnnotation(attrName, attrTok, annotations)
+					continue
+				}
+				p.recordAnnotation(attrName, attrTok)
+			}
 
 			if attrName == "required" {
 				arg.Required = true
@@ -814,7 +1228,7 @@ func (p *Parser) parseFieldArguments() []*ast.FieldArgument {
 				if p.curTok.Type == lexer.TOKEN_LPAREN {
 					p.nextToken()
 					if p.curTok.Type == lexer.TOKEN_IDENT || p.curTok.Type == lexer.TOKEN_NUMBER || p.curTok.Type == lexer.TOKEN_STRING {
-						arg.Default = strings.Trim(p.curTok.Literal, "\"'")
+						arg.Default = p.curTok.Literal
 						p.nextToken()
 						p.expectToken(lexer.TOKEN_RPAREN)
 					}
@@ -843,6 +1257,7 @@ func (p *Parser) parseFieldArguments() []*ast.FieldArgument {
 					return nil
 				}
 				subtype := p.curTok.Literal
+				p.recordAnnotation(attrName+"."+subtype, attrTok)
 				p.nextToken()
 
 				// Parse the content in parentheses
@@ -874,10 +1289,12 @@ func (p *Parser) parseFieldArguments() []*ast.FieldArgument {
 				}
 			} else {
 				arg.Attributes[attrName] = ""
+				p.skipAnnotationArguments()
 			}
 		}
 
 		arg.Annotations = annotations
+		p.checkAnnotations("argument")
 		arguments = append(arguments, arg)
 
 		// Check for comma (more arguments) or closing paren
@@ -919,20 +1336,35 @@ func (p *Parser) parseServiceWithDocAndAnnotations(doc *ast.Documentation, leadi
 
 	// Merge leading and trailing annotations
 	service.Annotations = p.mergeAnnotations(leadingAnnotations, trailingAnnotations)
+	p.checkAnnotations("service")
+	if service.Annotations != nil && service.Annotations.BasePath != "" {
+		service.BasePath = service.Annotations.BasePath
+		service.Annotations.BasePath = ""
+	}
 
 	if !p.expectToken(lexer.TOKEN_LBRACE) {
 		return nil
 	}
 
-	for p.curTok.Type == lexer.TOKEN_RPC || p.curTok.Type == lexer.TOKEN_DOC_COMMENT {
+	for p.curTok.Type != lexer.TOKEN_RBRACE && p.curTok.Type != lexer.TOKEN_EOF && !p.atDeclarationStart() {
+		start := p.curTok
 		method := p.parseMethod()
 		if method != nil {
 			service.Methods = append(service.Methods, method)
+		} else {
+			p.skipMember(start)
 		}
 	}
 
-	if !p.expectToken(lexer.TOKEN_RBRACE) {
-		return nil
+	p.expectClosingBrace("service", service.Name)
+
+	// Methods without their own @auth require that of their service
+	if service.Annotations != nil && service.Annotations.Auth != nil {
+		for _, method := range service.Methods {
+			if method.Auth == nil {
+				method.Auth = service.Annotations.Auth
+			}
+		}
 	}
 
 	return service
@@ -942,6 +1374,12 @@ func (p *Parser) parseMethod() *ast.Method {
 	// Collect documentation before 'rpc' keyword
 	doc := p.parseDocumentation()
 
+	if p.curTok.Type != lexer.TOKEN_RPC {
+		if p.curTok.Type != lexer.TOKEN_RBRACE && !p.atDeclarationStart() {
+			p.addError(fmt.Sprintf("expected rpc, got %s", p.curTok.Type))
+		}
+		return nil
+	}
 	p.nextToken() // consume 'rpc'
 
 	if p.curTok.Type != lexer.TOKEN_IDENT {
@@ -966,14 +1404,15 @@ func (p *Parser) parseMethod() *ast.Method {
 		p.nextToken()
 	}
 
-	if p.curTok.Type != lexer.TOKEN_IDENT {
+	// An empty input, rpc Ping(), takes no request message
+	if p.curTok.Type == lexer.TOKEN_IDENT {
+		method.InputType = p.curTok.Literal
+		p.nextToken()
+	} else if method.InputStream || p.curTok.Type != lexer.TOKEN_RPAREN {
 		p.addError("expected input type")
 		return nil
 	}
 
-	method.InputType = p.curTok.Literal
-	p.nextToken()
-
 	if !p.expectToken(lexer.TOKEN_RPAREN) {
 		return nil
 	}
@@ -992,14 +1431,15 @@ func (p *Parser) parseMethod() *ast.Method {
 		p.nextToken()
 	}
 
-	if p.curTok.Type != lexer.TOKEN_IDENT {
+	// An empty output, returns (), has no response message
+	if p.curTok.Type == lexer.TOKEN_IDENT {
+		method.OutputType = p.curTok.Literal
+		p.nextToken()
+	} else if method.OutputStream || p.curTok.Type != lexer.TOKEN_RPAREN {
 		p.addError("expected output type")
 		return nil
 	}
 
-	method.OutputType = p.curTok.Literal
-	p.nextToken()
-
 	if !p.expectToken(lexer.TOKEN_RPAREN) {
 		return nil
 	}
@@ -1012,6 +1452,7 @@ func (p *Parser) parseMethod() *ast.Method {
 			return nil
 		}
 
+		attrTok := p.curTok
 		attrName := p.curTok.Literal
 		p.nextToken()
 
@@ -1022,6 +1463,7 @@ func (p *Parser) parseMethod() *ast.Method {
 				p.nextToken()
 				if p.curTok.Type == lexer.TOKEN_IDENT {
 					subtype := p.curTok.Literal
+					p.recordAnnotation(attrName+"."+subtype, attrTok)
 					p.nextToken()
 
 					if p.curTok.Type == lexer.TOKEN_LPAREN {
@@ -1048,14 +1490,35 @@ func (p *Parser) parseMethod() *ast.Method {
 							// Parse @http.errors(400,404,500)
 							errorCodes := p.parseStatusCodeList()
 							method.ErrorCodes = errorCodes
+						default:
+							p.parseAnnotationContent()
 						}
 
 						p.expectToken(lexer.TOKEN_RPAREN)
 					}
 				}
 			}
+		} else if attrName == "openapi" && p.curTok.Type == lexer.TOKEN_DOT && p.peekTok.Literal == "example" {
+			// Parse @openapi.example("created", request={"name": "Ada"}, response={"id": "1"})
+			p.nextToken()
+			p.nextToken()
+			p.recordAnnotation("openapi.example", attrTok)
+			p.parseExample(method, attrTok)
+		} else if attrName == "openapi" && p.curTok.Type == lexer.TOKEN_DOT && p.peekTok.Literal == "code_sample" {
+			// Parse @openapi.code_sample(lang="curl", source="curl https://api.example.com/users")
+			p.nextToken()
+			p.nextToken()
+			p.recordAnnotation("openapi.code_sample", attrTok)
+			p.parseCodeSample(method, attrTok)
+		} else if p.curTok.Type == lexer.TOKEN_DOT && (attrName == "proto" || attrName == "graphql" || attrName == "openapi") {
+			// Parse format annotations like @graphql.directive(@auth(requires: ADMIN))
+			if method.Annotations == nil {
+				method.Annotations = ast.NewFormatAnnotations()
+			}
+			p.parseFormatAnnotation(attrName, attrTok, method.Annotations)
 		} else if attrName == "graphql" {
 			// Parse @graphql(query) or @graphql(mutation)
+			p.recordAnnotation(attrName, attrTok)
 			if p.curTok.Type == lexer.TOKEN_LPAREN {
 				p.nextToken()
 				if p.curTok.Type == lexer.TOKEN_IDENT {
@@ -1064,8 +1527,58 @@ func (p *Parser) parseMethod() *ast.Method {
 					p.expectToken(lexer.TOKEN_RPAREN)
 				}
 			}
+		} else if attrName == "timeout" {
+			// Parse @timeout("5s")
+			p.recordAnnotation(attrName, attrTok)
+			p.parseTimeout(method)
+		} else if attrName == "idempotent" {
+			p.recordAnnotation(attrName, attrTok)
+			method.Idempotent = true
+		} else if attrName == "longrunning" {
+			p.recordAnnotation(attrName, attrTok)
+			method.LongRunning = &ast.LongRunning{}
+		} else if attrName == "batch" {
+			p.recordAnnotation(attrName, attrTok)
+			method.Batch = true
+		} else if attrName == "ratelimit" {
+			// Parse @ratelimit(100, per="minute")
+			p.recordAnnotation(attrName, attrTok)
+			p.parseRateLimit(method)
+		} else if attrName == "auth" {
+			// Parse @auth or @auth("orders:read", "orders:write")
+			p.recordAnnotation(attrName, attrTok)
+			method.Auth = p.parseAuth()
+		} else if attrName == "webhook" {
+			// Parse @webhook("paymentCompleted", on=CreatePayment, url="{$request.body#/callbackUrl}")
+			p.recordAnnotation(attrName, attrTok)
+			p.parseWebhook(method, attrTok)
+		} else if attrName == "since" || attrName == "until" {
+			// Parse @since(v2) or @until(v3)
+			if method.Annotations == nil {
+				method.Annotations = ast.NewFormatAnnotations()
+			}
+			p.parseVersionBound(attrName, attrTok, method.Annotations)
+		} else if attrName == "view" {
+			// Parse @view(summary)
+			p.recordAnnotation(attrName, attrTok)
+			if p.expectToken(lexer.TOKEN_LPAREN) {
+				if p.curTok.Type == lexer.TOKEN_IDENT {
+					method.View = p.curTok.Literal
+					p.nextToken()
+				} else {
+					p.addError(fmt.Sprintf("expected view name in @view, got %s", p.curTok.Type))
+				}
+				p.expectToken(lexer.TOKEN_RPAREN)
+			}
+		} else {
+			extensions := ast.NewFormatAnnotations()
+			p.skipUnhandledAnnotation(attrName, attrTok, extensions)
+			if len(extensions.Extensions) > 0 {
+				method.Annotations = p.mergeAnnotations(method.Annotations, extensions)
+			}
 		}
 	}
+	p.checkAnnotations("method")
 
 	return method
 }
@@ -1090,47 +1603,354 @@ func (p *Parser) parseStatusCodeList() []string {
 	return codes
 }
 
-// PrintErrors returns all parsing errors as a single formatted string.
-func (p *Parser) PrintErrors() string {
-	return strings.Join(p.errors, "\n")
-}
-
-// parseImport parses an import statement: import "path/to/file.typemux"
-func (p *Parser) parseImport() string {
-	p.nextToken() // consume 'import'
-
+// parseTimeout parses the duration of @timeout("5s")
+func (p *Parser) parseTimeout(method *ast.Method) {
+	if !p.expectToken(lexer.TOKEN_LPAREN) {
+		return
+	}
 	if p.curTok.Type != lexer.TOKEN_STRING {
-		p.addError("expected string after import")
-		return ""
+		p.addError(fmt.Sprintf("expected duration string in @timeout, got %s", p.curTok.Type))
+		p.parseAnnotationContent()
+		p.expectToken(lexer.TOKEN_RPAREN)
+		return
+	}
+	if _, err := ast.ParseTimeout(p.curTok.Literal); err != nil {
+		p.addError(err.Error())
+	} else {
+		method.Timeout = p.curTok.Literal
 	}
-
-	importPath := p.curTok.Literal
 	p.nextToken()
-
-	return importPath
+	p.expectToken(lexer.TOKEN_RPAREN)
 }
 
-func (p *Parser) parseNamespace() string {
-	p.nextToken() // consume 'namespace'
-
-	if p.curTok.Type != lexer.TOKEN_IDENT {
-		p.addError("expected namespace identifier")
-		return ""
+// parseAuth parses @auth or @auth("scope", ...), with the current token after
+// auth, and returns the authentication it declares
+func (p *Parser) parseAuth() *ast.Auth {
+	auth := &ast.Auth{}
+	if p.curTok.Type != lexer.TOKEN_LPAREN {
+		return auth
 	}
-
-	// Build the namespace, supporting dotted notation (e.g., com.example.api)
-	var parts []string
-	parts = append(parts, p.curTok.Literal)
 	p.nextToken()
-
-	// Continue reading dots and identifiers for dotted namespaces
-	for p.curTok.Type == lexer.TOKEN_DOT {
-		p.nextToken() // consume '.'
-		if p.curTok.Type != lexer.TOKEN_IDENT {
-			p.addError("expected identifier after '.' in namespace")
-			return strings.Join(parts, ".")
+	for p.curTok.Type != lexer.TOKEN_RPAREN {
+		if p.curTok.Type != lexer.TOKEN_STRING && p.curTok.Type != lexer.TOKEN_IDENT {
+			p.addError(fmt.Sprintf("expected scope in @auth, got %s", p.curTok.Type))
+			p.parseAnnotationContent()
+			break
+		}
+		if p.curTok.Literal == "" {
+			p.addError("@auth scopes cannot be empty")
+		} else {
+			auth.Scopes = append(auth.Scopes, p.curTok.Literal)
+		}
+		p.nextToken()
+		if p.curTok.Type != lexer.TOKEN_COMMA {
+			break
 		}
-		parts = append(parts, p.curTok.Literal)
+		p.nextToken()
+	}
+	p.expectToken(lexer.TOKEN_RPAREN)
+	return auth
+}
+
+// parseRateLimit parses @ratelimit(100, per="minute"); the window defaults to a second
+func (p *Parser) parseRateLimit(method *ast.Method) {
+	startTok := p.curTok
+	if !p.expectToken(lexer.TOKEN_LPAREN) {
+		return
+	}
+	if p.curTok.Type != lexer.TOKEN_NUMBER {
+		p.addError(fmt.Sprintf("expected request count in @ratelimit, got %s", p.curTok.Type))
+		p.parseAnnotationContent()
+		p.expectToken(lexer.TOKEN_RPAREN)
+		return
+	}
+
+	limit := &ast.RateLimit{Per: "second"}
+	requests, err := parseInt(p.curTok.Literal)
+	if err != nil {
+		p.addError(fmt.Sprintf("invalid request count %s in @ratelimit", p.curTok.Literal))
+	}
+	limit.Requests = requests
+	p.nextToken()
+
+	for p.curTok.Type == lexer.TOKEN_COMMA {
+		p.nextToken()
+		if p.curTok.Type != lexer.TOKEN_IDENT || p.curTok.Literal != "per" || p.peekTok.Type != lexer.TOKEN_EQUALS {
+			p.addError(fmt.Sprintf("expected per= in @ratelimit, got %s", p.curTok.Type))
+			p.parseAnnotationContent()
+			p.expectToken(lexer.TOKEN_RPAREN)
+			return
+		}
+		p.nextToken() // consume 'per'
+		p.nextToken() // consume '='
+		if p.curTok.Type != lexer.TOKEN_STRING && p.curTok.Type != lexer.TOKEN_IDENT {
+			p.addError("expected window after per= in @ratelimit")
+			p.parseAnnotationContent()
+			p.expectToken(lexer.TOKEN_RPAREN)
+			return
+		}
+		limit.Per = p.curTok.Literal
+		p.nextToken()
+	}
+
+	if !p.expectToken(lexer.TOKEN_RPAREN) {
+		return
+	}
+	if err := limit.Validate(); err != nil {
+		p.addErrorAt(startTok, err.Error())
+		return
+	}
+	method.RateLimit = limit
+}
+
+// parseWebhook parses @webhook, @webhook("name"), and the on= and url= options of
+// a callback; the name defaults to the method name in lower camel case
+func (p *Parser) parseWebhook(method *ast.Method, startTok lexer.Token) {
+	webhook := &ast.Webhook{Name: strings.ToLower(method.Name[:1]) + method.Name[1:]}
+	if p.curTok.Type == lexer.TOKEN_LPAREN {
+		p.nextToken()
+		if p.curTok.Type == lexer.TOKEN_STRING {
+			webhook.Name = p.curTok.Literal
+			p.nextToken()
+			if p.curTok.Type == lexer.TOKEN_COMMA {
+				p.nextToken()
+			}
+		}
+		for p.curTok.Type == lexer.TOKEN_IDENT && p.peekTok.Type == lexer.TOKEN_EQUALS {
+			option := p.curTok.Literal
+			p.nextToken() // consume the option name
+			p.nextToken() // consume '='
+			if p.curTok.Type != lexer.TOKEN_STRING && p.curTok.Type != lexer.TOKEN_IDENT {
+				p.addError(fmt.Sprintf("expected value after %s= in @webhook", option))
+				break
+			}
+			switch option {
+			case "on":
+				webhook.On = p.curTok.Literal
+			case "url":
+				webhook.URL = p.curTok.Literal
+			default:
+				p.addError(fmt.Sprintf("unknown option %s in @webhook (expected on or url)", option))
+			}
+			p.nextToken()
+			if p.curTok.Type == lexer.TOKEN_COMMA {
+				p.nextToken()
+			}
+		}
+		if p.curTok.Type != lexer.TOKEN_RPAREN {
+			p.addError(fmt.Sprintf("expected webhook name, on=, or url= in @webhook, got %s", p.curTok.Type))
+			p.parseAnnotationContent()
+		}
+		if !p.expectToken(lexer.TOKEN_RPAREN) {
+			return
+		}
+	}
+
+	if webhook.Name == "" {
+		p.addErrorAt(startTok, "webhook name must not be empty")
+		return
+	}
+	if (webhook.On == "") != (webhook.URL == "") {
+		p.addErrorAt(startTok, "a callback @webhook needs both on= and url=")
+		return
+	}
+	method.Webhook = webhook
+}
+
+// parseExample parses @openapi.example with an optional name, which defaults to
+// "default", and the request=, response=, and summary= options
+func (p *Parser) parseExample(method *ast.Method, startTok lexer.Token) {
+	if !p.expectToken(lexer.TOKEN_LPAREN) {
+		return
+	}
+	example := &ast.Example{Name: "default"}
+	if p.curTok.Type == lexer.TOKEN_STRING {
+		example.Name = p.curTok.Literal
+		p.nextToken()
+		if p.curTok.Type == lexer.TOKEN_COMMA {
+			p.nextToken()
+		}
+	}
+	for p.curTok.Type == lexer.TOKEN_IDENT && p.peekTok.Type == lexer.TOKEN_EQUALS {
+		option := p.curTok.Literal
+		p.nextToken() // consume the option name
+		p.nextToken() // consume '='
+		switch option {
+		case "request", "response":
+			value, ok := p.parseJSONValue("@openapi.example " + option)
+			if !ok {
+				p.parseAnnotationContent()
+				p.expectToken(lexer.TOKEN_RPAREN)
+				return
+			}
+			if option == "request" {
+				example.Request = value
+			} else {
+				example.Response = value
+			}
+		case "summary":
+			if p.curTok.Type != lexer.TOKEN_STRING {
+				p.addError("expected string after summary= in @openapi.example")
+			}
+			example.Summary = p.curTok.Literal
+			p.nextToken()
+		default:
+			p.addError(fmt.Sprintf("unknown option %s in @openapi.example (expected request, response, or summary)", option))
+			p.nextToken()
+		}
+		if p.curTok.Type == lexer.TOKEN_COMMA {
+			p.nextToken()
+		}
+	}
+	if p.curTok.Type != lexer.TOKEN_RPAREN {
+		p.addError(fmt.Sprintf("expected example name, request=, response=, or summary= in @openapi.example, got %s", p.curTok.Type))
+		p.parseAnnotationContent()
+	}
+	if !p.expectToken(lexer.TOKEN_RPAREN) {
+		return
+	}
+
+	switch {
+	case example.Request == nil && example.Response == nil:
+		p.addErrorAt(startTok, "@openapi.example needs a request= or response=")
+	case example.Request != nil && !method.HasInput():
+		p.addErrorAt(startTok, fmt.Sprintf("@openapi.example %q has a request, but method %s takes no input", example.Name, method.Name))
+	case example.Response != nil && !method.HasOutput():
+		p.addErrorAt(startTok, fmt.Sprintf("@openapi.example %q has a response, but method %s returns nothing", example.Name, method.Name))
+	case method.Example(example.Name) != nil:
+		p.addErrorAt(startTok, fmt.Sprintf("method %s has two examples named %q", method.Name, example.Name))
+	default:
+		method.Examples = append(method.Examples, example)
+	}
+}
+
+// parseJSONValue parses the JSON value of an annotation option, such as
+// {"name": "Ada", "tags": ["admin"]}, and returns it compacted
+func (p *Parser) parseJSONValue(context string) (json.RawMessage, bool) {
+	var sb strings.Builder
+	depth := 0
+	for {
+		switch p.curTok.Type {
+		case lexer.TOKEN_LBRACE, lexer.TOKEN_LBRACKET:
+			depth++
+			sb.WriteString(p.curTok.Literal)
+		case lexer.TOKEN_RBRACE, lexer.TOKEN_RBRACKET:
+			depth--
+			sb.WriteString(p.curTok.Literal)
+		case lexer.TOKEN_STRING:
+			sb.WriteString(lexer.QuoteString(p.curTok.Literal))
+		case lexer.TOKEN_EOF:
+			p.addError(fmt.Sprintf("unterminated JSON value in %s", context))
+			return nil, false
+		case lexer.TOKEN_RPAREN, lexer.TOKEN_COMMA:
+			if depth == 0 {
+				p.addError(fmt.Sprintf("expected JSON value in %s, got %s", context, p.curTok.Type))
+				return nil, false
+			}
+			sb.WriteString(p.curTok.Literal)
+		default:
+			sb.WriteString(p.curTok.Literal)
+		}
+		p.nextToken()
+		if depth == 0 {
+			break
+		}
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, []byte(sb.String())); err != nil {
+		p.addError(fmt.Sprintf("invalid JSON in %s: %v", context, err))
+		return nil, false
+	}
+	return json.RawMessage(compacted.Bytes()), true
+}
+
+// parseCodeSample parses the lang=, source=, and optional label= options of
+// @openapi.code_sample
+func (p *Parser) parseCodeSample(method *ast.Method, startTok lexer.Token) {
+	if !p.expectToken(lexer.TOKEN_LPAREN) {
+		return
+	}
+	sample := &ast.CodeSample{}
+	for p.curTok.Type == lexer.TOKEN_IDENT && p.peekTok.Type == lexer.TOKEN_EQUALS {
+		option := p.curTok.Literal
+		p.nextToken() // consume the option name
+		p.nextToken() // consume '='
+		if p.curTok.Type != lexer.TOKEN_STRING {
+			p.addError(fmt.Sprintf("expected string after %s= in @openapi.code_sample", option))
+			break
+		}
+		switch option {
+		case "lang":
+			sample.Lang = p.curTok.Literal
+		case "label":
+			sample.Label = p.curTok.Literal
+		case "source":
+			sample.Source = p.curTok.Literal
+		default:
+			p.addError(fmt.Sprintf("unknown option %s in @openapi.code_sample (expected lang, label, or source)", option))
+		}
+		p.nextToken()
+		if p.curTok.Type == lexer.TOKEN_COMMA {
+			p.nextToken()
+		}
+	}
+	if p.curTok.Type != lexer.TOKEN_RPAREN {
+		p.addError(fmt.Sprintf("expected lang=, label=, or source= in @openapi.code_sample, got %s", p.curTok.Type))
+		p.parseAnnotationContent()
+	}
+	if !p.expectToken(lexer.TOKEN_RPAREN) {
+		return
+	}
+
+	if sample.Lang == "" || sample.Source == "" {
+		p.addErrorAt(startTok, "@openapi.code_sample needs lang= and source=")
+		return
+	}
+	method.CodeSamples = append(method.CodeSamples, sample)
+}
+
+// PrintErrors returns all parsing errors as a single formatted string.
+func (p *Parser) PrintErrors() string {
+	return strings.Join(p.errors, "\n")
+}
+
+// parseImport parses an import statement: import "path/to/file.typemux"
+func (p *Parser) parseImport() string {
+	p.nextToken() // consume 'import'
+
+	if p.curTok.Type != lexer.TOKEN_STRING {
+		p.addError("expected string after import")
+		return ""
+	}
+
+	importPath := p.curTok.Literal
+	p.nextToken()
+
+	return importPath
+}
+
+func (p *Parser) parseNamespace() string {
+	p.nextToken() // consume 'namespace'
+
+	if p.curTok.Type != lexer.TOKEN_IDENT {
+		p.addError("expected namespace identifier")
+		return ""
+	}
+
+	// Build the namespace, supporting dotted notation (e.g., com.example.api)
+	var parts []string
+	parts = append(parts, p.curTok.Literal)
+	p.nextToken()
+
+	// Continue reading dots and identifiers for dotted namespaces
+	for p.curTok.Type == lexer.TOKEN_DOT {
+		p.nextToken() // consume '.'
+		if p.curTok.Type != lexer.TOKEN_IDENT {
+			p.addError("expected identifier after '.' in namespace")
+			return strings.Join(parts, ".")
+		}
+		parts = append(parts, p.curTok.Literal)
 		p.nextToken()
 	}
 
@@ -1157,11 +1977,35 @@ func (p *Parser) parseDocumentation() *ast.Documentation {
 
 	// Regex to match language-specific comments: @proto, @graphql, @openapi
 	langRegex := regexp.MustCompile(`^@(proto|graphql|openapi)\s+(.*)$`)
+	// Regex to match translated comments: @lang(es), @lang(pt-BR)
+	localeRegex := regexp.MustCompile(`^@lang\(\s*([A-Za-z]+(?:[-_][A-Za-z0-9]+)*)\s*\)\s?(.*)$`)
+
+	// Regex to match a reference to a Markdown file: @doc-file docs/user.md
+	fileRegex := regexp.MustCompile(`^@doc-file(?:\s+(.*?))?\s*$`)
 
 	var generalLines []string
 
 	for _, line := range docLines {
-		if matches := langRegex.FindStringSubmatch(line); matches != nil {
+		if matches := fileRegex.FindStringSubmatch(line); matches != nil {
+			file := strings.Trim(matches[1], `"`)
+			if file == "" {
+				p.addError("expected a file path after @doc-file")
+			} else if doc.File != "" {
+				p.addError(fmt.Sprintf("duplicate @doc-file %s, documentation already references %s", file, doc.File))
+			} else {
+				doc.File = file
+			}
+		} else if matches := localeRegex.FindStringSubmatch(line); matches != nil {
+			if doc.Locales == nil {
+				doc.Locales = make(map[string]string)
+			}
+			locale := ast.NormalizeLocale(matches[1])
+			if existing, ok := doc.Locales[locale]; ok {
+				doc.Locales[locale] = existing + "\n" + matches[2]
+			} else {
+				doc.Locales[locale] = matches[2]
+			}
+		} else if matches := langRegex.FindStringSubmatch(line); matches != nil {
 			lang := matches[1]
 			text := matches[2]
 			// Append to existing doc for this language
@@ -1179,6 +2023,9 @@ func (p *Parser) parseDocumentation() *ast.Documentation {
 	if len(generalLines) > 0 {
 		doc.General = strings.Join(generalLines, "\n")
 	}
+	for locale, text := range doc.Locales {
+		doc.Locales[locale] = strings.TrimSpace(text)
+	}
 
 	return doc
 }
@@ -1195,6 +2042,15 @@ func (p *Parser) parseLeadingAnnotations() *ast.FormatAnnotations {
 	return annotations
 }
 
+// protoFileOptionShorthands lists the string-valued protobuf file options that can be
+// written as @proto.<option>("value") instead of @proto.option(<option> = "value")
+var protoFileOptionShorthands = map[string]bool{
+	"go_package":        true,
+	"java_package":      true,
+	"csharp_namespace":  true,
+	"objc_class_prefix": true,
+}
+
 // parseSingleAnnotation parses a single @format.subtype(...) annotation
 // and adds it to the provided FormatAnnotations object
 func (p *Parser) parseSingleAnnotation(annotations *ast.FormatAnnotations) {
@@ -1205,60 +2061,347 @@ func (p *Parser) parseSingleAnnotation(annotations *ast.FormatAnnotations) {
 		return
 	}
 
+	nameTok := p.curTok
 	formatName := p.curTok.Literal
 	p.nextToken()
 
 	// Check for dot notation: @format.subtype(...)
 	if formatName == "proto" || formatName == "graphql" || formatName == "openapi" || formatName == "go" {
-		// Expect a dot
-		if p.curTok.Type != lexer.TOKEN_DOT {
-			p.addError(fmt.Sprintf("expected . after @%s", formatName))
+		p.parseFormatAnnotation(formatName, nameTok, annotations)
+	} else if formatName == "json" && p.curTok.Type == lexer.TOKEN_DOT && p.peekTok.Type == lexer.TOKEN_UNION {
+		p.parseUnionEncoding(nameTok, annotations)
+	} else if formatName == "http" && p.curTok.Type == lexer.TOKEN_DOT && p.peekTok.Literal == "base_path" {
+		p.parseBasePath(nameTok, annotations)
+	} else if (formatName == "owner" || formatName == "sla" || formatName == "tier") && p.curTok.Type != lexer.TOKEN_DOT {
+		p.parseOwnership(formatName, nameTok, annotations)
+	} else if formatName == "crud" && p.curTok.Type != lexer.TOKEN_DOT {
+		p.parseCrud(nameTok, annotations)
+	} else if (formatName == "since" || formatName == "until") && p.curTok.Type != lexer.TOKEN_DOT {
+		p.parseVersionBound(formatName, nameTok, annotations)
+	} else if formatName == "auth" && p.curTok.Type != lexer.TOKEN_DOT {
+		p.recordAnnotation("auth", nameTok)
+		annotations.Auth = p.parseAuth()
+	} else {
+		p.skipUnhandledAnnotation(formatName, nameTok, annotations)
+	}
+}
+
+// parseOwnership parses @owner("team"), @sla("99.9%"), or @tier(1), with the
+// current token after the annotation name
+func (p *Parser) parseOwnership(name string, nameTok lexer.Token, annotations *ast.FormatAnnotations) {
+	p.recordAnnotation(name, nameTok)
+	if !p.expectToken(lexer.TOKEN_LPAREN) {
+		return
+	}
+	if p.curTok.Type != lexer.TOKEN_STRING && p.curTok.Type != lexer.TOKEN_IDENT && p.curTok.Type != lexer.TOKEN_NUMBER {
+		p.addError(fmt.Sprintf("expected a value in @%s, got %s", name, p.curTok.Type))
+		p.parseAnnotationContent()
+		p.expectToken(lexer.TOKEN_RPAREN)
+		return
+	}
+	value := strings.TrimSpace(p.curTok.Literal)
+	if value == "" {
+		p.addErrorAt(nameTok, fmt.Sprintf("@%s requires a non-empty value", name))
+	}
+	p.nextToken()
+	if !p.expectToken(lexer.TOKEN_RPAREN) || value == "" {
+		return
+	}
+
+	if annotations.Ownership == nil {
+		annotations.Ownership = &ast.Ownership{}
+	}
+	switch name {
+	case "owner":
+		annotations.Ownership.Owner = value
+	case "sla":
+		annotations.Ownership.SLA = value
+	case "tier":
+		annotations.Ownership.Tier = value
+	}
+}
+
+// parseBasePath parses @http.base_path("/v1/users"), with the current token at
+// the dot and nameTok at http
+func (p *Parser) parseBasePath(nameTok lexer.Token, annotations *ast.FormatAnnotations) {
+	p.nextToken() // consume '.'
+	p.nextToken() // consume base_path
+	p.recordAnnotation("http.base_path", nameTok)
+	if !p.expectToken(lexer.TOKEN_LPAREN) {
+		return
+	}
+	if p.curTok.Type != lexer.TOKEN_STRING {
+		p.addError(fmt.Sprintf("expected a path string in @http.base_path, got %s", p.curTok.Type))
+		p.parseAnnotationContent()
+		p.expectToken(lexer.TOKEN_RPAREN)
+		return
+	}
+	path := p.curTok.Literal
+	if !strings.HasPrefix(path, "/") {
+		p.addErrorAt(nameTok, fmt.Sprintf("@http.base_path must start with /, got %q", path))
+	}
+	p.nextToken()
+	if p.expectToken(lexer.TOKEN_RPAREN) && strings.HasPrefix(path, "/") {
+		annotations.BasePath = path
+	}
+}
+
+// parseVersionBound parses @since(v2) or @until("2.1.0") into the annotations
+// of a declaration or method, with the current token after the annotation name
+func (p *Parser) parseVersionBound(name string, nameTok lexer.Token, annotations *ast.FormatAnnotations) {
+	p.recordAnnotation(name, nameTok)
+	version := p.parseVersionArgument(name)
+	switch name {
+	case "since":
+		annotations.Since = version
+	case "until":
+		annotations.Until = version
+	}
+}
+
+// parseVersionArgument parses the (version) of @since or @until, with the
+// current token after the annotation name, and returns the version
+func (p *Parser) parseVersionArgument(name string) string {
+	if !p.expectToken(lexer.TOKEN_LPAREN) {
+		return ""
+	}
+	if p.curTok.Type != lexer.TOKEN_STRING && p.curTok.Type != lexer.TOKEN_IDENT && p.curTok.Type != lexer.TOKEN_NUMBER {
+		p.addError(fmt.Sprintf("expected a version in @%s, got %s", name, p.curTok.Type))
+		p.parseAnnotationContent()
+		p.expectToken(lexer.TOKEN_RPAREN)
+		return ""
+	}
+	version := strings.TrimSpace(p.curTok.Literal)
+	p.nextToken()
+	p.expectToken(lexer.TOKEN_RPAREN)
+	return version
+}
+
+// parseCrud parses @crud, @crud(get, list), and the path=, service=, and id=
+// options, as in @crud(path="/v1/users", id="userId"), with the current token
+// after crud
+func (p *Parser) parseCrud(nameTok lexer.Token, annotations *ast.FormatAnnotations) {
+	p.recordAnnotation("crud", nameTok)
+	crud := &ast.Crud{}
+	if p.curTok.Type == lexer.TOKEN_LPAREN {
+		p.nextToken()
+		for p.curTok.Type != lexer.TOKEN_RPAREN {
+			// service is a keyword, so the option is matched by its literal
+			option := p.curTok.Literal
+			switch {
+			case (option == "path" || option == "service" || option == "id") && p.peekTok.Type == lexer.TOKEN_EQUALS:
+				p.nextToken() // consume option name
+				p.nextToken() // consume '='
+				if p.curTok.Type != lexer.TOKEN_STRING && p.curTok.Type != lexer.TOKEN_IDENT {
+					p.addError(fmt.Sprintf("expected a value after %s= in @crud, got %s", option, p.curTok.Type))
+					p.parseAnnotationContent()
+					p.expectToken(lexer.TOKEN_RPAREN)
+					return
+				}
+				switch option {
+				case "path":
+					crud.Path = p.curTok.Literal
+				case "service":
+					crud.Service = p.curTok.Literal
+				case "id":
+					crud.ID = p.curTok.Literal
+				}
+			case p.curTok.Type == lexer.TOKEN_IDENT:
+				crud.Methods = append(crud.Methods, option)
+			default:
+				p.addError(fmt.Sprintf("expected a method, path=, service=, or id= in @crud, got %s", p.curTok.Type))
+				p.parseAnnotationContent()
+				p.expectToken(lexer.TOKEN_RPAREN)
+				return
+			}
+			p.nextToken()
+			if p.curTok.Type == lexer.TOKEN_COMMA {
+				p.nextToken()
+			} else if p.curTok.Type != lexer.TOKEN_RPAREN {
+				break
+			}
+		}
+		if !p.expectToken(lexer.TOKEN_RPAREN) {
 			return
 		}
-		p.nextToken()
+	}
 
-		// Expect subtype identifier (option, directive, extension, name)
-		if p.curTok.Type != lexer.TOKEN_IDENT {
-			p.addError(fmt.Sprintf("expected subtype after @%s.", formatName))
+	if err := crud.Validate(); err != nil {
+		p.addErrorAt(nameTok, fmt.Sprintf("@crud: %v", err))
+		return
+	}
+	annotations.Crud = crud
+}
+
+// parseUnionEncoding parses @json.union(strategy, tag="...", content="..."),
+// with the current token at the dot and nameTok at json
+func (p *Parser) parseUnionEncoding(nameTok lexer.Token, annotations *ast.FormatAnnotations) {
+	p.nextToken() // consume '.'
+	p.nextToken() // consume 'union'
+	p.recordAnnotation("json.union", nameTok)
+	if !p.expectToken(lexer.TOKEN_LPAREN) {
+		return
+	}
+	if p.curTok.Type != lexer.TOKEN_IDENT && p.curTok.Type != lexer.TOKEN_STRING {
+		p.addError(fmt.Sprintf("expected internal, adjacent, or untagged in @json.union, got %s", p.curTok.Type))
+		p.parseAnnotationContent()
+		p.expectToken(lexer.TOKEN_RPAREN)
+		return
+	}
+
+	encoding := &ast.UnionEncoding{Strategy: ast.UnionStrategy(p.curTok.Literal)}
+	p.nextToken()
+	for p.curTok.Type == lexer.TOKEN_COMMA {
+		p.nextToken()
+		option := p.curTok.Literal
+		if p.curTok.Type != lexer.TOKEN_IDENT || (option != "tag" && option != "content") || p.peekTok.Type != lexer.TOKEN_EQUALS {
+			p.addError(fmt.Sprintf("expected tag= or content= in @json.union, got %s", p.curTok.Type))
+			p.parseAnnotationContent()
+			p.expectToken(lexer.TOKEN_RPAREN)
 			return
 		}
-		subtype := p.curTok.Literal
+		p.nextToken() // consume option name
+		p.nextToken() // consume '='
+		if p.curTok.Type != lexer.TOKEN_STRING && p.curTok.Type != lexer.TOKEN_IDENT {
+			p.addError(fmt.Sprintf("expected property name after %s= in @json.union", option))
+			p.parseAnnotationContent()
+			p.expectToken(lexer.TOKEN_RPAREN)
+			return
+		}
+		if option == "tag" {
+			encoding.Tag = p.curTok.Literal
+		} else {
+			encoding.Content = p.curTok.Literal
+		}
 		p.nextToken()
+	}
 
-		// Parse the content in parentheses
-		if p.curTok.Type == lexer.TOKEN_LPAREN {
-			p.nextToken()
-			content := p.parseAnnotationContent()
+	if !p.expectToken(lexer.TOKEN_RPAREN) {
+		return
+	}
+	if err := encoding.Validate(); err != nil {
+		p.addErrorAt(nameTok, fmt.Sprintf("@json.union: %v", err))
+		return
+	}
+	annotations.UnionEncoding = encoding
+}
+
+// graphQLNamePattern matches the names GraphQL allows
+var graphQLNamePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// parseGraphQLMap parses @graphql.map(json), @graphql.map(entries), and the
+// entry= option naming the entry type, as in @graphql.map(entry="LabelEntry"),
+// with the current token after map
+func (p *Parser) parseGraphQLMap(nameTok lexer.Token, annotations *ast.FormatAnnotations) {
+	if !p.expectToken(lexer.TOKEN_LPAREN) {
+		return
+	}
+
+	graphQLMap := &ast.GraphQLMap{}
+	for p.curTok.Type != lexer.TOKEN_RPAREN {
+		switch {
+		case p.curTok.Type == lexer.TOKEN_IDENT && p.curTok.Literal == "entry" && p.peekTok.Type == lexer.TOKEN_EQUALS:
+			p.nextToken() // consume 'entry'
+			p.nextToken() // consume '='
+			if p.curTok.Type != lexer.TOKEN_STRING && p.curTok.Type != lexer.TOKEN_IDENT {
+				p.addError(fmt.Sprintf("expected type name after entry= in @graphql.map, got %s", p.curTok.Type))
+				p.parseAnnotationContent()
+				p.expectToken(lexer.TOKEN_RPAREN)
+				return
+			}
+			graphQLMap.Entry = p.curTok.Literal
+		case p.curTok.Type == lexer.TOKEN_IDENT && (p.curTok.Literal == ast.GraphQLMapJSON || p.curTok.Literal == ast.GraphQLMapEntries):
+			graphQLMap.As = p.curTok.Literal
+		default:
+			p.addError(fmt.Sprintf("expected json, entries, or entry= in @graphql.map, got %s", p.curTok.Type))
+			p.parseAnnotationContent()
 			p.expectToken(lexer.TOKEN_RPAREN)
+			return
+		}
+		p.nextToken()
+		if p.curTok.Type == lexer.TOKEN_COMMA {
+			p.nextToken()
+		} else if p.curTok.Type != lexer.TOKEN_RPAREN {
+			break
+		}
+	}
+	if !p.expectToken(lexer.TOKEN_RPAREN) {
+		return
+	}
 
-			// Handle name annotation specially
-			if subtype == "name" {
-				// Extract the name from quotes
-				name := strings.Trim(content, "\"'")
-				if formatName == "proto" {
-					annotations.ProtoName = name
-				} else if formatName == "graphql" {
-					annotations.GraphQLName = name
-				} else if formatName == "openapi" {
-					annotations.OpenAPIName = name
-				} else if formatName == "go" {
-					annotations.GoName = name
-				}
-			} else if subtype == "package" && formatName == "go" {
-				// Handle @go.package("packagename") for namespace-level annotations
-				packageName := strings.Trim(content, "\"'")
-				annotations.Go = append(annotations.Go, fmt.Sprintf("package = \"%s\"", packageName))
-			} else {
-				// Store in appropriate list for other subtypes
-				if formatName == "proto" {
-					annotations.Proto = append(annotations.Proto, content)
-				} else if formatName == "graphql" {
-					annotations.GraphQL = append(annotations.GraphQL, content)
-				} else if formatName == "openapi" {
-					annotations.OpenAPI = append(annotations.OpenAPI, content)
-				} else if formatName == "go" {
-					annotations.Go = append(annotations.Go, content)
-				}
+	switch {
+	case graphQLMap.Entry != "" && graphQLMap.As == ast.GraphQLMapJSON:
+		p.addErrorAt(nameTok, "@graphql.map: a map rendered as json has no entry type")
+	case graphQLMap.Entry != "" && !graphQLNamePattern.MatchString(graphQLMap.Entry):
+		p.addErrorAt(nameTok, fmt.Sprintf("@graphql.map: invalid GraphQL type name %q", graphQLMap.Entry))
+	case graphQLMap.As == "" && graphQLMap.Entry == "":
+		p.addErrorAt(nameTok, "@graphql.map requires json, entries, or entry=")
+	default:
+		annotations.GraphQLMap = graphQLMap
+	}
+}
+
+// parseFormatAnnotation parses the .subtype(...) part of a @format.subtype(...)
+// annotation, with the current token at the dot and nameTok at the format name
+func (p *Parser) parseFormatAnnotation(formatName string, nameTok lexer.Token, annotations *ast.FormatAnnotations) {
+	// Expect a dot
+	if p.curTok.Type != lexer.TOKEN_DOT {
+		p.addError(fmt.Sprintf("expected . after @%s", formatName))
+		return
+	}
+	p.nextToken()
+
+	// Expect subtype identifier (option, directive, extension, name)
+	if p.curTok.Type != lexer.TOKEN_IDENT {
+		p.addError(fmt.Sprintf("expected subtype after @%s.", formatName))
+		return
+	}
+	subtype := p.curTok.Literal
+	p.recordAnnotation(formatName+"."+subtype, nameTok)
+	p.nextToken()
+
+	if formatName == "graphql" && subtype == "map" {
+		p.parseGraphQLMap(nameTok, annotations)
+		return
+	}
+
+	// Parse the content in parentheses
+	if p.curTok.Type == lexer.TOKEN_LPAREN {
+		p.nextToken()
+		content := p.parseAnnotationContent()
+		p.expectToken(lexer.TOKEN_RPAREN)
+
+		// Handle name annotation specially
+		if subtype == "name" {
+			// Extract the name from quotes
+			name := strings.Trim(content, "\"'")
+			if formatName == "proto" {
+				annotations.ProtoName = name
+			} else if formatName == "graphql" {
+				annotations.GraphQLName = name
+			} else if formatName == "openapi" {
+				annotations.OpenAPIName = name
+			} else if formatName == "go" {
+				annotations.GoName = name
+			}
+		} else if subtype == "package" && formatName == "go" {
+			// Handle @go.package("packagename") for namespace-level annotations
+			packageName := strings.Trim(content, "\"'")
+			annotations.Go = append(annotations.Go, fmt.Sprintf("package = \"%s\"", packageName))
+		} else if formatName == "proto" && protoFileOptionShorthands[subtype] {
+			// Handle @proto.go_package("..."), @proto.java_package("...") etc. as file-level options
+			value := strings.Trim(content, "\"'")
+			annotations.Proto = append(annotations.Proto, fmt.Sprintf("%s = \"%s\"", subtype, value))
+		} else {
+			// Store in appropriate list for other subtypes
+			if formatName == "proto" {
+				annotations.Proto = append(annotations.Proto, content)
+			} else if formatName == "graphql" {
+				annotations.GraphQL = append(annotations.GraphQL, content)
+			} else if formatName == "openapi" {
+				annotations.OpenAPI = append(annotations.OpenAPI, content)
+			} else if formatName == "go" {
+				annotations.Go = append(annotations.Go, content)
 			}
 		}
 	}
@@ -1320,6 +2463,56 @@ func (p *Parser) mergeAnnotations(leading, trailing *ast.FormatAnnotations) *ast
 		merged.GoName = leading.GoName
 	}
 
+	if trailing.UnionEncoding != nil {
+		merged.UnionEncoding = trailing.UnionEncoding
+	} else {
+		merged.UnionEncoding = leading.UnionEncoding
+	}
+
+	merged.BasePath = leading.BasePath
+	if trailing.BasePath != "" {
+		merged.BasePath = trailing.BasePath
+	}
+
+	if trailing.GraphQLMap != nil {
+		merged.GraphQLMap = trailing.GraphQLMap
+	} else {
+		merged.GraphQLMap = leading.GraphQLMap
+	}
+
+	if trailing.Crud != nil {
+		merged.Crud = trailing.Crud
+	} else {
+		merged.Crud = leading.Crud
+	}
+
+	if trailing.Auth != nil {
+		merged.Auth = trailing.Auth
+	} else {
+		merged.Auth = leading.Auth
+	}
+
+	merged.Ownership = leading.Ownership.Merge(trailing.Ownership)
+
+	merged.Since = leading.Since
+	if trailing.Since != "" {
+		merged.Since = trailing.Since
+	}
+	merged.Until = leading.Until
+	if trailing.Until != "" {
+		merged.Until = trailing.Until
+	}
+
+	// For plugin annotations, trailing takes precedence
+	for _, extensions := range []map[string]map[string]string{leading.Extensions, trailing.Extensions} {
+		for name, args := range extensions {
+			if merged.Extensions == nil {
+				merged.Extensions = make(map[string]map[string]string)
+			}
+			merged.Extensions[name] = args
+		}
+	}
+
 	return merged
 }
 
@@ -1328,7 +2521,7 @@ func (p *Parser) mergeAnnotations(leading, trailing *ast.FormatAnnotations) *ast
 func (p *Parser) parseDeprecationInfo(info *ast.DeprecationInfo) {
 	// First parameter can be a reason string (optional)
 	if p.curTok.Type == lexer.TOKEN_STRING {
-		info.Reason = strings.Trim(p.curTok.Literal, "\"'")
+		info.Reason = p.curTok.Literal
 		p.nextToken()
 
 		// If there's a comma, parse named parameters
@@ -1357,7 +2550,7 @@ func (p *Parser) parseDeprecationInfo(info *ast.DeprecationInfo) {
 			return
 		}
 
-		value := strings.Trim(p.curTok.Literal, "\"'")
+		value := p.curTok.Literal
 
 		switch paramName {
 		case "since":
@@ -1398,7 +2591,7 @@ func (p *Parser) parseValidationRules(rules *ast.ValidationRules) {
 		// Get the value
 		paramValue := ""
 		if p.curTok.Type == lexer.TOKEN_STRING {
-			paramValue = strings.Trim(p.curTok.Literal, "\"'")
+			paramValue = p.curTok.Literal
 		} else if p.curTok.Type == lexer.TOKEN_NUMBER {
 			paramValue = p.curTok.Literal
 		} else if p.curTok.Type == lexer.TOKEN_IDENT {
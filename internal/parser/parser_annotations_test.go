@@ -1,9 +1,11 @@
 package parser
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/rasmartins/typemux/internal/annotations"
 	"github.com/rasmartins/typemux/internal/ast"
 	"github.com/rasmartins/typemux/internal/lexer"
 )
@@ -103,6 +105,42 @@ type User @graphql.directive(@key(fields: "id")) {
 	}
 }
 
+func TestParseMethodGraphQLDirective(t *testing.T) {
+	input := `
+service UserService {
+	rpc GetUser(GetUserRequest) returns (User) @graphql(query) @graphql.directive(@auth(requires: ADMIN))
+	rpc DeleteUser(GetUserRequest) returns (User)
+}
+`
+	l := lexer.New(input)
+	p := New(l)
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Parser errors: %v", p.Errors())
+	}
+
+	methods := schema.Services[0].Methods
+	if len(methods) != 2 {
+		t.Fatalf("Expected 2 methods, got %d", len(methods))
+	}
+
+	getUser := methods[0]
+	if getUser.GraphQLType != "query" {
+		t.Errorf("Expected GraphQL type 'query', got %s", getUser.GraphQLType)
+	}
+	if getUser.Annotations == nil || len(getUser.Annotations.GraphQL) != 1 {
+		t.Fatalf("Expected 1 GraphQL directive on GetUser, got %+v", getUser.Annotations)
+	}
+	if !strings.HasPrefix(getUser.Annotations.GraphQL[0], "@auth(") {
+		t.Errorf("Expected @auth directive, got %s", getUser.Annotations.GraphQL[0])
+	}
+
+	if methods[1].Annotations != nil {
+		t.Errorf("Expected no annotations on DeleteUser, got %+v", methods[1].Annotations)
+	}
+}
+
 func TestParseOpenAPIExtension(t *testing.T) {
 	input := `
 type Product @openapi.extension({"x-internal-id": "prod-v1", "x-category": "commerce"}) {
@@ -346,6 +384,31 @@ type User @proto.name("UserV2") @graphql.name("UserAccount") @openapi.name("User
 	}
 }
 
+func TestParseFieldNameAnnotations(t *testing.T) {
+	input := `
+type User {
+	userId: string @proto.name("user_id") @graphql.name("uid") @openapi.name("user-id") @go.name("UserID")
+}
+`
+	l := lexer.New(input)
+	p := New(l)
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Parser errors: %v", p.Errors())
+	}
+	if len(p.Warnings()) > 0 {
+		t.Errorf("Unexpected warnings: %v", p.Warnings())
+	}
+
+	field := schema.Types[0].Fields[0]
+	for format, want := range map[string]string{"proto": "user_id", "graphql": "uid", "openapi": "user-id", "go": "UserID"} {
+		if got := field.NameFor(format); got != want {
+			t.Errorf("Expected %s name %s, got %s", format, want, got)
+		}
+	}
+}
+
 // TestParser_MergeAnnotations tests the mergeAnnotations function
 func TestParser_MergeAnnotations(t *testing.T) {
 	p := &Parser{}
@@ -646,3 +709,861 @@ func ptrFloatValue(ptr *float64) interface{} {
 	}
 	return *ptr
 }
+
+func TestParser_UnknownAnnotationWarnings(t *testing.T) {
+	input := `namespace api
+
+@graphql.directiv(@key)
+type User {
+	id: string @requird
+	name: string @defualt("x") @validate(minLength=1)
+	age: int32 @json.nulable
+	other: string @custom
+}
+
+service UserService {
+	rpc GetUser(User) returns (User) @http.mehtod(GET)
+}
+`
+	p := New(lexer.New(input))
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Expected unknown annotations not to be errors, got %v", p.Errors())
+	}
+	if len(schema.Types) != 1 || len(schema.Types[0].Fields) != 4 {
+		t.Fatalf("Expected the type and its fields to be parsed")
+	}
+
+	expected := []string{
+		"Line 3:2 - unknown annotation @graphql.directiv on type (did you mean @graphql.directive?)",
+		"Line 5:14 - unknown annotation @requird on field (did you mean @required?)",
+		"Line 6:16 - unknown annotation @defualt on field (did you mean @default?)",
+		"Line 7:14 - unknown annotation @json.nulable on field (did you mean @json.nullable?)",
+		"Line 8:17 - unknown annotation @custom on field",
+		"Line 12:36 - unknown annotation @http.mehtod on method (did you mean @http.method?)",
+	}
+	warnings := p.Warnings()
+	if len(warnings) != len(expected) {
+		t.Fatalf("Expected %d warnings, got %d: %v", len(expected), len(warnings), warnings)
+	}
+	for i, want := range expected {
+		if warnings[i] != want {
+			t.Errorf("Warning %d:\n got: %s\nwant: %s", i, warnings[i], want)
+		}
+	}
+}
+
+func TestParser_MisplacedAnnotationWarnings(t *testing.T) {
+	input := `namespace api
+/// Users
+@typemux("1.0.0")
+type User @required {
+	id: string @http.path("/users")
+}
+`
+	p := New(lexer.New(input))
+	p.Parse()
+
+	warnings := strings.Join(p.Warnings(), "\n")
+	for _, want := range []string{
+		"annotation @typemux must appear at the top of the file",
+		"annotation @required is not supported on type (allowed on: field, argument)",
+		"annotation @http.path is not supported on field (allowed on: method)",
+	} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Expected warning %q, got:\n%s", want, warnings)
+		}
+	}
+}
+
+func TestParser_KnownAnnotationsHaveNoWarnings(t *testing.T) {
+	input := `@typemux("1.0.0")
+namespace api @go.package("api")
+
+@proto.name("UserV2")
+type User @graphql.directive(@key(fields: "id")) {
+	@proto.name("user_id")
+	id: string @required @json.name("user_id")
+	tags: []string @proto.option([packed = false])
+	posts(limit: int32 @default(10) @validate(min=1)): []string
+}
+
+service UserService {
+	rpc GetUser(User) returns (User) @http.method(GET) @http.path("/users/{id}") @graphql(query)
+}
+`
+	p := New(lexer.New(input))
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Parser errors: %v", p.Errors())
+	}
+	if len(p.Warnings()) > 0 {
+		t.Errorf("Expected no warnings, got %v", p.Warnings())
+	}
+	if id := schema.Types[0].Fields[0]; id.Annotations == nil || id.Annotations.ProtoName != "user_id" {
+		t.Error("Expected leading @proto.name to apply to the field")
+	}
+}
+
+func TestParser_RequiredOptionalWarning(t *testing.T) {
+	input := `type User {
+	id: string @required
+	email: string? @required
+	@required
+	nick: string?
+}
+`
+	p := New(lexer.New(input))
+	p.Parse()
+
+	expected := []string{
+		"Line 3:2 - field email is marked @required but its type is optional (?); it is treated as optional",
+		"Line 5:2 - field nick is marked @required but its type is optional (?); it is treated as optional",
+	}
+	warnings := p.Warnings()
+	if len(warnings) != len(expected) {
+		t.Fatalf("Expected %d warnings, got %v", len(expected), warnings)
+	}
+	for i, want := range expected {
+		if warnings[i] != want {
+			t.Errorf("Warning %d:\n got: %s\nwant: %s", i, warnings[i], want)
+		}
+	}
+}
+
+func TestParser_FlagsEnum(t *testing.T) {
+	input := `@flags
+enum Permission {
+	NONE = 0
+	READ
+	WRITE
+}
+
+enum Status @flags { ACTIVE = 1 }
+
+enum Color { RED }
+`
+	p := New(lexer.New(input))
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 || len(p.Warnings()) > 0 {
+		t.Fatalf("Unexpected errors %v and warnings %v", p.Errors(), p.Warnings())
+	}
+	for i, flags := range []bool{true, true, false} {
+		if schema.Enums[i].Flags != flags {
+			t.Errorf("Enum %s: expected Flags %v", schema.Enums[i].Name, flags)
+		}
+	}
+}
+
+func TestParser_FlagsEnumNumbers(t *testing.T) {
+	p := New(lexer.New("enum Permission @flags {\n\tREAD = 1\n\tALL = 3\n}\n"))
+	p.Parse()
+
+	expected := "Line 3:8 - flags enum value Permission.ALL must be 0 or a power of two up to 1073741824, got 3"
+	if errs := p.Errors(); len(errs) != 1 || errs[0] != expected {
+		t.Errorf("Expected error %q, got %v", expected, errs)
+	}
+}
+
+func TestParser_SensitiveField(t *testing.T) {
+	input := `type User {
+	email: string = 1 @sensitive(pii) @required
+	apiKey: string = 2 @sensitive("secret")
+	name: string = 3
+}
+`
+	p := New(lexer.New(input))
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 || len(p.Warnings()) > 0 {
+		t.Fatalf("Unexpected errors %v and warnings %v", p.Errors(), p.Warnings())
+	}
+	fields := schema.Types[0].Fields
+	for i, kind := range []string{"pii", "secret", ""} {
+		if fields[i].Sensitivity != kind {
+			t.Errorf("Field %s: expected sensitivity %q, got %q", fields[i].Name, kind, fields[i].Sensitivity)
+		}
+	}
+	if !fields[0].Required {
+		t.Errorf("Expected email to stay required")
+	}
+}
+
+func TestParser_SensitiveFieldUnknownKind(t *testing.T) {
+	p := New(lexer.New("type User {\n\tssn: string @sensitive(private)\n}\n"))
+	p.Parse()
+
+	errs := p.Errors()
+	if len(errs) != 1 || !strings.Contains(errs[0], `unknown @sensitive kind "private" (expected pii, secret, phi)`) {
+		t.Errorf("Expected an unknown kind error, got %v", errs)
+	}
+}
+
+func TestParser_Views(t *testing.T) {
+	input := `type User {
+	id: string = 1 @required @view(summary)
+	name: string = 2 @view(summary, card)
+	bio: string = 3
+}
+
+service UserService {
+	rpc ListUsers(ListUsersRequest) returns (User) @view(summary)
+}
+`
+	p := New(lexer.New(input))
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 || len(p.Warnings()) > 0 {
+		t.Fatalf("Unexpected errors %v and warnings %v", p.Errors(), p.Warnings())
+	}
+	fields := schema.Types[0].Fields
+	for i, views := range []string{"summary", "summary,card", ""} {
+		if got := strings.Join(fields[i].Views, ","); got != views {
+			t.Errorf("Field %s: expected views %q, got %q", fields[i].Name, views, got)
+		}
+	}
+	if view := schema.Services[0].Methods[0].View; view != "summary" {
+		t.Errorf("Expected ListUsers to select the summary view, got %q", view)
+	}
+}
+
+func TestParser_ViewWithoutName(t *testing.T) {
+	p := New(lexer.New("type User {\n\tid: string @view()\n}\n"))
+	p.Parse()
+
+	errs := p.Errors()
+	if len(errs) != 1 || !strings.Contains(errs[0], "expected view name in @view") {
+		t.Errorf("Expected a missing view name error, got %v", errs)
+	}
+}
+
+func TestParser_LongRunning(t *testing.T) {
+	input := `service BatchService {
+	rpc ImportUsers(ImportUsersRequest) returns (ImportUsersResponse) @http.path("/v1/imports") @longrunning
+	rpc GetImport(GetImportRequest) returns (ImportUsersResponse)
+}
+`
+	p := New(lexer.New(input))
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 || len(p.Warnings()) > 0 {
+		t.Fatalf("Unexpected errors %v and warnings %v", p.Errors(), p.Warnings())
+	}
+	methods := schema.Services[0].Methods
+	if methods[0].LongRunning == nil || methods[0].PathTemplate != "/v1/imports" {
+		t.Errorf("Expected ImportUsers to be long-running at /v1/imports, got %+v", methods[0])
+	}
+	if methods[1].LongRunning != nil {
+		t.Error("Expected GetImport not to be long-running")
+	}
+}
+
+func TestParser_Batch(t *testing.T) {
+	input := `service UserService {
+	rpc CreateUser(CreateUserRequest) returns (User) @http.path("/v1/users") @batch
+	rpc GetUser(GetUserRequest) returns (User)
+}
+`
+	p := New(lexer.New(input))
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 || len(p.Warnings()) > 0 {
+		t.Fatalf("Unexpected errors %v and warnings %v", p.Errors(), p.Warnings())
+	}
+	methods := schema.Services[0].Methods
+	if !methods[0].Batch || methods[0].PathTemplate != "/v1/users" {
+		t.Errorf("Expected CreateUser to be batched at /v1/users, got %+v", methods[0])
+	}
+	if methods[1].Batch {
+		t.Error("Expected GetUser not to be batched")
+	}
+}
+
+func TestParser_ExamplesAndCodeSamples(t *testing.T) {
+	input := `service UserService {
+	rpc CreateUser(User) returns (User)
+		@openapi.example("ada", request={"name": "Ada", "score": 1.5}, response={"id": "u1", "tags": ["admin"]}, summary="Creates Ada")
+		@openapi.example(response={"id": "u2"})
+		@openapi.code_sample(lang="curl", source="curl -d '{\"name\": \"Ada\"}' https://api.example.com/users")
+		@openapi.code_sample(lang="Go", label="Go SDK", source="client.CreateUser(ctx, user)")
+}
+`
+	p := New(lexer.New(input))
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 || len(p.Warnings()) > 0 {
+		t.Fatalf("Unexpected errors %v and warnings %v", p.Errors(), p.Warnings())
+	}
+	method := schema.Services[0].Methods[0]
+	if len(method.Examples) != 2 {
+		t.Fatalf("Expected 2 examples, got %d", len(method.Examples))
+	}
+	ada := method.Example("ada")
+	if ada == nil || ada.Summary != "Creates Ada" || string(ada.Request) != `{"name":"Ada","score":1.5}` || string(ada.Response) != `{"id":"u1","tags":["admin"]}` {
+		t.Errorf("Unexpected example ada: %+v", ada)
+	}
+	if example := method.Example("default"); example == nil || example.Request != nil || string(example.Response) != `{"id":"u2"}` {
+		t.Errorf("Expected an unnamed example to be named default, got %+v", example)
+	}
+	if len(method.CodeSamples) != 2 {
+		t.Fatalf("Expected 2 code samples, got %d", len(method.CodeSamples))
+	}
+	if sample := method.CodeSamples[0]; sample.Lang != "curl" || sample.Source != `curl -d '{"name": "Ada"}' https://api.example.com/users` {
+		t.Errorf("Unexpected curl sample: %+v", sample)
+	}
+	if sample := method.CodeSamples[1]; sample.Label != "Go SDK" {
+		t.Errorf("Expected the Go sample to be labelled, got %+v", sample)
+	}
+}
+
+func TestParser_ExampleErrors(t *testing.T) {
+	tests := []struct {
+		annotation string
+		want       string
+	}{
+		{`@openapi.example("a")`, "needs a request= or response="},
+		{`@openapi.example(request={"name": })`, "invalid JSON in @openapi.example request"},
+		{`@openapi.example(request=)`, "expected JSON value in @openapi.example request"},
+		{`@openapi.example(request={}) @openapi.example(request={})`, `two examples named "default"`},
+		{`@openapi.example(response={})`, "returns nothing"},
+		{`@openapi.example(sample={})`, "unknown option sample"},
+		{`@openapi.code_sample(lang="curl")`, "needs lang= and source="},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New("service S {\n\trpc Ping(PingRequest) returns () " + tt.annotation + "\n}\n"))
+		p.Parse()
+		if errs := p.Errors(); len(errs) == 0 || !strings.Contains(strings.Join(errs, "\n"), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.annotation, tt.want, errs)
+		}
+	}
+}
+
+func TestParser_UnionEncoding(t *testing.T) {
+	p := New(lexer.New(`
+@json.union(internal)
+union Shape {
+	Circle
+	Square
+}
+
+union Event @json.union(adjacent, tag="kind", content="data") {
+	Created
+}
+
+union Plain {
+	Circle
+}
+`))
+	schema := p.Parse()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+	if warnings := p.Warnings(); len(warnings) > 0 {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+
+	want := []*ast.UnionEncoding{
+		{Strategy: ast.UnionInternal},
+		{Strategy: ast.UnionAdjacent, Tag: "kind", Content: "data"},
+		nil,
+	}
+	for i, union := range schema.Unions {
+		got := union.Encoding
+		if (got == nil) != (want[i] == nil) || (got != nil && *got != *want[i]) {
+			t.Errorf("%s: expected encoding %+v, got %+v", union.Name, want[i], got)
+		}
+	}
+}
+
+func TestParser_UnionEncodingErrors(t *testing.T) {
+	tests := []struct {
+		annotation string
+		want       string
+	}{
+		{`@json.union(flat)`, `unknown union encoding "flat"`},
+		{`@json.union(untagged, tag="kind")`, "untagged unions have no tag property"},
+		{`@json.union(internal, content="data")`, "only adjacently tagged unions have a content property"},
+		{`@json.union(adjacent, name="x")`, "expected tag= or content="},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New(tt.annotation + "\nunion Shape {\n\tCircle\n}\n"))
+		p.Parse()
+		if errs := p.Errors(); len(errs) == 0 || !strings.Contains(strings.Join(errs, "\n"), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.annotation, tt.want, errs)
+		}
+	}
+
+	p := New(lexer.New("@json.union(internal)\ntype Circle {\n\tradius: float64 = 1\n}\n"))
+	p.Parse()
+	if warnings := strings.Join(p.Warnings(), "\n"); !strings.Contains(warnings, "@json.union is not supported on type") {
+		t.Errorf("Expected a warning for @json.union on a type, got %q", warnings)
+	}
+}
+
+func TestParser_GraphQLMap(t *testing.T) {
+	p := New(lexer.New(`
+type Stats {
+	labels: map<string, string> @graphql.map(entry="LabelEntry")
+	@graphql.map(json)
+	metadata: map<string, string>
+	counts: map<string, int32> @graphql.map(entries, entry=CountEntry)
+	name: string @graphql.map(json)
+}
+`))
+	schema := p.Parse()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+
+	want := []ast.GraphQLMap{
+		{Entry: "LabelEntry"},
+		{As: ast.GraphQLMapJSON},
+		{As: ast.GraphQLMapEntries, Entry: "CountEntry"},
+	}
+	for i, field := range schema.Types[0].Fields[:3] {
+		if field.Annotations == nil || field.Annotations.GraphQLMap == nil || *field.Annotations.GraphQLMap != want[i] {
+			t.Errorf("%s: expected %+v, got %+v", field.Name, want[i], field.Annotations)
+		}
+	}
+	if warnings := strings.Join(p.Warnings(), "\n"); !strings.Contains(warnings, "@graphql.map on field name has no effect") {
+		t.Errorf("Expected a warning for @graphql.map on a field that is not a map, got %q", warnings)
+	}
+}
+
+func TestParser_GraphQLMapErrors(t *testing.T) {
+	tests := []struct {
+		annotation string
+		want       string
+	}{
+		{`@graphql.map()`, "requires json, entries, or entry="},
+		{`@graphql.map(list)`, "expected json, entries, or entry="},
+		{`@graphql.map(json, entry="LabelEntry")`, "a map rendered as json has no entry type"},
+		{`@graphql.map(entry="Label-Entry")`, `invalid GraphQL type name "Label-Entry"`},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New("type Stats {\n\tlabels: map<string, string> " + tt.annotation + "\n}\n"))
+		p.Parse()
+		if errs := p.Errors(); len(errs) == 0 || !strings.Contains(strings.Join(errs, "\n"), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.annotation, tt.want, errs)
+		}
+	}
+}
+
+func TestParser_Ownership(t *testing.T) {
+	p := New(lexer.New(`
+namespace payments @owner("team-payments") @tier(2)
+
+@sla("99.9%")
+type Invoice @owner(billing) {
+	id: string @required
+}
+
+service Refunds @tier(critical) {
+	rpc Refund(Invoice) returns (Invoice)
+}
+`))
+	schema := p.Parse()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+	if warnings := p.Warnings(); len(warnings) > 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	tests := []struct {
+		element string
+		got     *ast.FormatAnnotations
+		want    ast.Ownership
+	}{
+		{"payments", schema.NamespaceAnnotations, ast.Ownership{Owner: "team-payments", Tier: "2"}},
+		{"Invoice", schema.Types[0].Annotations, ast.Ownership{Owner: "billing", SLA: "99.9%"}},
+		{"Refunds", schema.Services[0].Annotations, ast.Ownership{Tier: "critical"}},
+	}
+	for _, tt := range tests {
+		if tt.got == nil || tt.got.Ownership == nil || *tt.got.Ownership != tt.want {
+			t.Errorf("%s: expected ownership %+v, got %+v", tt.element, tt.want, tt.got)
+		}
+	}
+}
+
+func TestParser_OwnershipErrors(t *testing.T) {
+	tests := []struct {
+		annotation string
+		want       string
+	}{
+		{`@owner()`, "expected a value in @owner"},
+		{`@sla("")`, "@sla requires a non-empty value"},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New("type Invoice " + tt.annotation + " {\n\tid: string\n}\n"))
+		p.Parse()
+		if errs := p.Errors(); len(errs) == 0 || !strings.Contains(strings.Join(errs, "\n"), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.annotation, tt.want, errs)
+		}
+	}
+}
+
+func TestParser_Crud(t *testing.T) {
+	p := New(lexer.New(`
+namespace shop @crud(path="/v1/{types}", service="ShopService")
+
+type User @crud {
+	id: string @required
+}
+
+@crud(get, list, id=sku)
+type Product {
+	sku: string @required
+}
+`))
+	schema := p.Parse()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+	if warnings := p.Warnings(); len(warnings) > 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	tests := []struct {
+		element string
+		got     *ast.FormatAnnotations
+		want    string
+	}{
+		{"shop", schema.NamespaceAnnotations, "[] ShopService /v1/{types} "},
+		{"User", schema.Types[0].Annotations, "[]   "},
+		{"Product", schema.Types[1].Annotations, "[get list]   sku"},
+	}
+	for _, tt := range tests {
+		if tt.got == nil || tt.got.Crud == nil {
+			t.Errorf("%s: expected @crud, got %+v", tt.element, tt.got)
+			continue
+		}
+		crud := tt.got.Crud
+		if got := fmt.Sprintf("%v %s %s %s", crud.Methods, crud.Service, crud.Path, crud.ID); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.element, tt.want, got)
+		}
+	}
+}
+
+func TestParser_CrudErrors(t *testing.T) {
+	tests := []struct {
+		annotation string
+		want       string
+	}{
+		{`@crud(patch)`, `@crud: unknown method "patch"`},
+		{`@crud(get, get)`, `@crud: duplicate method "get"`},
+		{`@crud(path=1)`, "expected a value after path= in @crud"},
+		{`@crud(1)`, "expected a method, path=, service=, or id= in @crud"},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New("type User " + tt.annotation + " {\n\tid: string\n}\n"))
+		p.Parse()
+		if errs := p.Errors(); len(errs) == 0 || !strings.Contains(strings.Join(errs, "\n"), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.annotation, tt.want, errs)
+		}
+	}
+}
+
+func TestParser_Auth(t *testing.T) {
+	p := New(lexer.New(`
+type Order {
+	id: string @required
+}
+
+service OrderService @auth("orders:read") {
+	rpc GetOrder(Order) returns (Order)
+	rpc CancelOrder(Order) returns () @auth("orders:write", orders_admin)
+}
+
+service HealthService {
+	rpc Check() returns () @auth
+	rpc Ping() returns ()
+}
+`))
+	schema := p.Parse()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+	if warnings := p.Warnings(); len(warnings) > 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	tests := []struct {
+		method *ast.Method
+		want   string
+	}{
+		{schema.Services[0].Methods[0], "scopes orders:read"},
+		{schema.Services[0].Methods[1], "scopes orders:write, orders_admin"},
+		{schema.Services[1].Methods[0], "authenticated caller"},
+		{schema.Services[1].Methods[1], ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if tt.method.Auth != nil {
+			got = tt.method.Auth.String()
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected auth %q, got %q", tt.method.Name, tt.want, got)
+		}
+	}
+
+	p = New(lexer.New("service S {\n\trpc Ping() returns () @auth(1)\n}\n"))
+	p.Parse()
+	if errs := p.Errors(); len(errs) == 0 || !strings.Contains(errs[0], "expected scope in @auth") {
+		t.Errorf("Expected an error about the scope, got %v", errs)
+	}
+}
+
+// registerMycorp registers the @mycorp annotations of the plugin annotation tests
+func registerMycorp(t *testing.T) {
+	t.Helper()
+	err := annotations.RegisterNamespace("mycorp", []*annotations.AnnotationMetadata{
+		{Name: "@mycorp.audit", Scope: []string{"type", "method"}, Parameters: []annotations.ParameterMetadata{
+			{Name: "level", Type: "string", Required: true, ValidValues: []string{"low", "high"}},
+			{Name: "retain", Type: "number"},
+		}},
+		{Name: "@mycorp.pii", Scope: []string{"field", "argument"}, Parameters: []annotations.ParameterMetadata{
+			{Name: "kind", Type: "string"},
+			{Name: "masked", Type: "boolean"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("RegisterNamespace failed: %v", err)
+	}
+	t.Cleanup(func() { annotations.UnregisterNamespace("mycorp") })
+}
+
+func TestParser_PluginAnnotations(t *testing.T) {
+	registerMycorp(t)
+
+	p := New(lexer.New(`
+@mycorp.audit(level="high", retain=30)
+type User {
+	@mycorp.pii(kind="email")
+	email: string @required
+	phone: string @mycorp.pii("phone", masked=true)
+	name: string
+}
+
+service Users {
+	rpc Get(User) returns (User) @mycorp.audit(low)
+	rpc Find(User) returns (User)
+}
+`))
+	schema := p.Parse()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+	if warnings := p.Warnings(); len(warnings) > 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	tests := []struct {
+		element string
+		got     *ast.FormatAnnotations
+		name    string
+		want    map[string]string
+	}{
+		{"User", schema.Types[0].Annotations, "mycorp.audit", map[string]string{"level": "high", "retain": "30"}},
+		{"email", schema.Types[0].Fields[0].Annotations, "mycorp.pii", map[string]string{"kind": "email"}},
+		{"phone", schema.Types[0].Fields[1].Annotations, "mycorp.pii", map[string]string{"kind": "phone", "masked": "true"}},
+		{"Get", schema.Services[0].Methods[0].Annotations, "@mycorp.audit", map[string]string{"level": "low"}},
+	}
+	for _, tt := range tests {
+		args, ok := tt.got.Extension(tt.name)
+		if !ok {
+			t.Errorf("%s: expected %s, got %+v", tt.element, tt.name, tt.got)
+			continue
+		}
+		if len(args) != len(tt.want) {
+			t.Errorf("%s: expected arguments %v, got %v", tt.element, tt.want, args)
+		}
+		for name, value := range tt.want {
+			if args[name] != value {
+				t.Errorf("%s: expected %s=%s, got %v", tt.element, name, value, args)
+			}
+		}
+	}
+
+	if _, ok := schema.Types[0].Fields[2].Annotations.Extension("mycorp.pii"); ok {
+		t.Errorf("Expected no plugin annotation on name")
+	}
+	if schema.Services[0].Methods[1].Annotations != nil {
+		t.Errorf("Expected no annotations on Find, got %+v", schema.Services[0].Methods[1].Annotations)
+	}
+}
+
+func TestParser_PluginAnnotationErrors(t *testing.T) {
+	registerMycorp(t)
+
+	tests := []struct {
+		annotation string
+		want       string
+	}{
+		{`@mycorp.audit`, "@mycorp.audit requires level"},
+		{`@mycorp.audit(level="medium")`, "level must be one of low, high, got medium"},
+		{`@mycorp.audit(level="low", retain="30")`, "retain must be a number, got 30"},
+		{`@mycorp.audit(level="low", owner="ops")`, "@mycorp.audit has no parameter owner"},
+		{`@mycorp.audit(low, 30, 40)`, "too many arguments in @mycorp.audit"},
+		{`@mycorp.audit(level="low", level="high")`, "duplicate argument level"},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New(tt.annotation + "\ntype User {\n\tid: string\n}\n"))
+		p.Parse()
+		if errs := p.Errors(); len(errs) == 0 || !strings.Contains(strings.Join(errs, "\n"), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.annotation, tt.want, errs)
+		}
+	}
+
+	p := New(lexer.New("type User {\n\tid: string @mycorp.pii(masked=1)\n}\n"))
+	p.Parse()
+	if errs := p.Errors(); len(errs) == 0 || !strings.Contains(errs[0], "masked must be true or false") {
+		t.Errorf("Expected a boolean error, got %v", errs)
+	}
+}
+
+func TestParser_PluginAnnotationWarnings(t *testing.T) {
+	registerMycorp(t)
+
+	p := New(lexer.New(`
+type User {
+	id: string @mycorp.audit(level="low") @mycorp.pi
+	name: string @other.thing(1)
+}
+`))
+	p.Parse()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+
+	warnings := strings.Join(p.Warnings(), "\n")
+	for _, want := range []string{
+		"annotation @mycorp.audit is not supported on field (allowed on: type, method)",
+		"unknown annotation @mycorp.pi on field (did you mean @mycorp.pii?)",
+		"unknown annotation @other.thing on field",
+	} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Expected a warning containing %q, got %q", want, warnings)
+		}
+	}
+}
+
+func TestParser_VersionBounds(t *testing.T) {
+	p := New(lexer.New(`
+@since(v2)
+type Profile {
+	bio: string
+}
+
+type User @until("1.9.0") {
+	id: string
+	name: string @until(v1)
+	displayName: string @since(v2)
+}
+
+enum Role @since(2) {
+	ADMIN
+}
+
+union Owner @until(v3) {
+	User
+}
+
+service UserService @since(v1) {
+	rpc GetUser(User) returns (User) @since(v2) @until(v4)
+}
+`))
+	schema := p.Parse()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+	if warnings := p.Warnings(); len(warnings) > 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	tests := []struct {
+		element      string
+		since, until string
+		want         string
+	}{
+		{"Profile", schema.Types[0].Annotations.Since, schema.Types[0].Annotations.Until, "v2-"},
+		{"User", schema.Types[1].Annotations.Since, schema.Types[1].Annotations.Until, "-1.9.0"},
+		{"User.name", schema.Types[1].Fields[1].Since, schema.Types[1].Fields[1].Until, "-v1"},
+		{"User.displayName", schema.Types[1].Fields[2].Since, schema.Types[1].Fields[2].Until, "v2-"},
+		{"Role", schema.Enums[0].Annotations.Since, schema.Enums[0].Annotations.Until, "2-"},
+		{"Owner", schema.Unions[0].Annotations.Since, schema.Unions[0].Annotations.Until, "-v3"},
+		{"UserService", schema.Services[0].Annotations.Since, schema.Services[0].Annotations.Until, "v1-"},
+		{"GetUser", schema.Services[0].Methods[0].Annotations.Since, schema.Services[0].Methods[0].Annotations.Until, "v2-v4"},
+	}
+	for _, tt := range tests {
+		if got := tt.since + "-" + tt.until; got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.element, tt.want, got)
+		}
+	}
+
+	p = New(lexer.New("type User @since() {\n\tid: string\n}\n"))
+	p.Parse()
+	if errs := p.Errors(); len(errs) == 0 || !strings.Contains(strings.Join(errs, "\n"), "expected a version in @since") {
+		t.Errorf("Expected an error for @since without a version, got %v", errs)
+	}
+}
+
+func TestParser_HTTPBasePath(t *testing.T) {
+	p := New(lexer.New(`
+type User {
+	id: string
+}
+
+@http.base_path("/v1/users")
+service UserService {
+	rpc GetUser(User) returns (User) @http.path("/{id}")
+	rpc ListUsers(User) returns (User)
+}
+
+service AdminService @http.base_path("/admin") {
+	rpc Ping(User) returns (User)
+}
+`))
+	schema := p.Parse()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("Parse failed: %v", errs)
+	}
+	if warnings := p.Warnings(); len(warnings) > 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	users, admin := schema.Services[0], schema.Services[1]
+	if users.BasePath != "/v1/users" || admin.BasePath != "/admin" {
+		t.Fatalf("Expected base paths /v1/users and /admin, got %q and %q", users.BasePath, admin.BasePath)
+	}
+	if path := users.Methods[0].HTTPPath(users); path != "/v1/users/{id}" {
+		t.Errorf("Expected /v1/users/{id}, got %s", path)
+	}
+	if path := users.Methods[1].HTTPPath(users); path != "/v1/users/listusers" {
+		t.Errorf("Expected /v1/users/listusers, got %s", path)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"service S @http.base_path(\"v1\") {\n}\n", "@http.base_path must start with /"},
+		{"service S @http.base_path(v1) {\n}\n", "expected a path string in @http.base_path"},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.Parse()
+		if errs := p.Errors(); !strings.Contains(strings.Join(errs, "\n"), tt.expected) {
+			t.Errorf("Expected an error containing %q, got %v", tt.expected, errs)
+		}
+	}
+
+	p = New(lexer.New("@http.base_path(\"/v1\")\ntype T {\n\tid: string\n}\n"))
+	p.Parse()
+	if warnings := p.Warnings(); !strings.Contains(strings.Join(warnings, "\n"), "annotation @http.base_path is not supported on type") {
+		t.Errorf("Expected a warning for @http.base_path on a type, got %v", warnings)
+	}
+}
@@ -229,3 +229,72 @@ type User {
 		t.Errorf("expected go annotation '%s', got '%s'", expected, schema.NamespaceAnnotations.Go[0])
 	}
 }
+
+func TestNamespaceAnnotations_TrailingSameLine(t *testing.T) {
+	input := `
+namespace com.example.users @proto.option(go_package="github.com/example/users")
+
+@proto.option(deprecated = true)
+type User {
+	id: string = 1
+}
+`
+	l := lexer.New(input)
+	p := New(l)
+	schema := p.Parse()
+
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser had errors: %v", p.Errors())
+	}
+
+	if schema.NamespaceAnnotations == nil || len(schema.NamespaceAnnotations.Proto) != 1 {
+		t.Fatalf("expected 1 namespace proto annotation, got %+v", schema.NamespaceAnnotations)
+	}
+
+	if schema.NamespaceAnnotations.Proto[0] != `go_package = "github.com/example/users"` {
+		t.Errorf("unexpected namespace proto annotation: %s", schema.NamespaceAnnotations.Proto[0])
+	}
+
+	user := schema.Types[0]
+	if user.Annotations == nil || len(user.Annotations.Proto) != 1 {
+		t.Fatalf("expected User to keep only its own annotation, got %+v", user.Annotations)
+	}
+}
+
+func TestNamespaceAnnotations_FileOptionShorthands(t *testing.T) {
+	input := `
+@proto.go_package("github.com/example/users;users")
+@proto.java_package("com.example.users")
+@proto.csharp_namespace("Example.Users")
+@proto.objc_class_prefix("EXU")
+namespace com.example.users
+
+type User {
+	id: string = 1
+}
+`
+	l := lexer.New(input)
+	p := New(l)
+	schema := p.Parse()
+
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser had errors: %v", p.Errors())
+	}
+
+	expected := []string{
+		`go_package = "github.com/example/users;users"`,
+		`java_package = "com.example.users"`,
+		`csharp_namespace = "Example.Users"`,
+		`objc_class_prefix = "EXU"`,
+	}
+
+	if schema.NamespaceAnnotations == nil || len(schema.NamespaceAnnotations.Proto) != len(expected) {
+		t.Fatalf("expected %d proto annotations, got %+v", len(expected), schema.NamespaceAnnotations)
+	}
+
+	for i, want := range expected {
+		if schema.NamespaceAnnotations.Proto[i] != want {
+			t.Errorf("annotation %d: expected %s, got %s", i, want, schema.NamespaceAnnotations.Proto[i])
+		}
+	}
+}
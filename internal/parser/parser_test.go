@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/rasmartins/typemux/internal/ast"
@@ -1272,6 +1273,69 @@ service UserService {
 	}
 }
 
+func TestParseLocalizedDocumentation(t *testing.T) {
+	input := `/// A registered user
+/// @lang(es) Un usuario registrado
+/// @lang(pt_BR) Um usuário registrado
+/// @lang(es) con una cuenta
+type User {
+  id: string
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Unexpected errors: %s", p.PrintErrors())
+	}
+
+	doc := schema.Types[0].Doc
+	if doc.General != "A registered user" {
+		t.Errorf("Expected general doc without translations, got %q", doc.General)
+	}
+	if es := doc.Locales["es"]; es != "Un usuario registrado\ncon una cuenta" {
+		t.Errorf("Expected consecutive es lines to be joined, got %q", es)
+	}
+	if ptBR := doc.Locales["pt-br"]; ptBR != "Um usuário registrado" {
+		t.Errorf("Expected pt_BR to be stored as pt-br, got %q", ptBR)
+	}
+}
+
+func TestParseDocFile(t *testing.T) {
+	input := `/// A registered user
+/// @doc-file docs/user.md
+type User {
+  /// @doc-file "docs/id.md"
+  id: string
+}`
+
+	p := New(lexer.New(input))
+	schema := p.Parse()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Unexpected errors: %s", p.PrintErrors())
+	}
+
+	doc := schema.Types[0].Doc
+	if doc.General != "A registered user" || doc.File != "docs/user.md" {
+		t.Errorf("Expected the general doc and docs/user.md, got %+v", doc)
+	}
+	if file := schema.Types[0].Fields[0].Doc.File; file != "docs/id.md" {
+		t.Errorf("Expected quotes to be removed from the path, got %q", file)
+	}
+
+	for _, input := range []string{
+		"/// @doc-file a.md\n/// @doc-file b.md\ntype User {}",
+		"/// @doc-file\ntype User {}",
+	} {
+		p := New(lexer.New(input))
+		p.Parse()
+		if len(p.Errors()) == 0 || !strings.Contains(p.Errors()[0], "@doc-file") {
+			t.Errorf("%q: expected an @doc-file error, got %v", input, p.Errors())
+		}
+	}
+}
+
 func TestParsePathTemplate(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1903,6 +1967,44 @@ func TestParseUnion(t *testing.T) {
 	}
 }
 
+func TestParseEmptyMethodTypes(t *testing.T) {
+	input := `service HealthService {
+		rpc Ping() returns ()
+		rpc Status() returns (StatusResponse)
+		rpc Reset(ResetRequest) returns () @http.method(DELETE)
+	}`
+
+	p := New(lexer.New(input))
+	schema := p.Parse()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Unexpected errors: %s", p.PrintErrors())
+	}
+
+	methods := schema.Services[0].Methods
+	if len(methods) != 3 {
+		t.Fatalf("Expected 3 methods, got %d", len(methods))
+	}
+
+	expected := []struct{ input, output string }{
+		{"", ""},
+		{"", "StatusResponse"},
+		{"ResetRequest", ""},
+	}
+	for i, want := range expected {
+		method := methods[i]
+		if method.InputType != want.input || method.OutputType != want.output {
+			t.Errorf("%s: expected (%q) returns (%q), got (%q) returns (%q)",
+				method.Name, want.input, want.output, method.InputType, method.OutputType)
+		}
+		if method.HasInput() != (want.input != "") || method.HasOutput() != (want.output != "") {
+			t.Errorf("%s: HasInput/HasOutput disagree with the declared types", method.Name)
+		}
+	}
+	if methods[2].HTTPMethod != "DELETE" {
+		t.Errorf("Expected annotations after returns () to parse, got HTTP method %q", methods[2].HTTPMethod)
+	}
+}
+
 func TestParseStreamingMethods(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -2061,7 +2163,11 @@ func TestParseEdgeCaseErrors(t *testing.T) {
 		},
 		{
 			name:  "missing input type",
-			input: "service API { rpc Get() returns (Res) }",
+			input: "service API { rpc Get(123) returns (Res) }",
+		},
+		{
+			name:  "missing stream input type",
+			input: "service API { rpc Get(stream) returns (Res) }",
 		},
 		{
 			name:  "invalid field separator",
@@ -2821,6 +2927,124 @@ enum Status {
 	}
 }
 
+func TestParseMethodPolicies(t *testing.T) {
+	input := `service OrderService {
+		rpc GetOrder(GetOrderRequest) returns (Order)
+			@timeout("1m30s")
+			@idempotent
+			@ratelimit(100, per="minute")
+		rpc WatchOrders(GetOrderRequest) returns (stream Order) @ratelimit(5)
+		rpc CreateOrder(CreateOrderRequest) returns (Order)
+	}`
+
+	p := New(lexer.New(input))
+	schema := p.Parse()
+	if len(p.Errors()) > 0 || len(p.Warnings()) > 0 {
+		t.Fatalf("Unexpected problems: %s %v", p.PrintErrors(), p.Warnings())
+	}
+
+	methods := schema.Services[0].Methods
+	if methods[0].Timeout != "1m30s" || !methods[0].Idempotent {
+		t.Errorf("Expected timeout 1m30s and idempotent, got %q and %v", methods[0].Timeout, methods[0].Idempotent)
+	}
+	if limit := methods[0].RateLimit; limit == nil || limit.Requests != 100 || limit.Per != "minute" {
+		t.Errorf("Expected 100 requests per minute, got %+v", limit)
+	}
+	if limit := methods[1].RateLimit; limit == nil || limit.Requests != 5 || limit.Per != "second" {
+		t.Errorf("Expected the window to default to a second, got %+v", limit)
+	}
+	if methods[2].HasPolicy() {
+		t.Errorf("Expected CreateOrder to declare no policy")
+	}
+}
+
+func TestParseMethodPolicyErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		expected string
+	}{
+		{"invalid duration", `@timeout("soon")`, `invalid timeout "soon"`},
+		{"zero duration", `@timeout("0s")`, `invalid timeout "0s"`},
+		{"unquoted duration", `@timeout(5)`, "expected duration string in @timeout"},
+		{"missing request count", `@ratelimit(per="minute")`, "expected request count in @ratelimit"},
+		{"zero requests", `@ratelimit(0)`, "rate limit must allow at least one request"},
+		{"unknown window", `@ratelimit(10, per="week")`, `unknown rate limit window "week"`},
+		{"unknown parameter", `@ratelimit(10, every="minute")`, "expected per= in @ratelimit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := "service S {\n  rpc Get(Req) returns (Res) " + tt.policy + "\n  rpc Next(Req) returns (Res)\n}"
+			p := New(lexer.New(input))
+			schema := p.Parse()
+			if !strings.Contains(p.PrintErrors(), tt.expected) {
+				t.Errorf("Expected error containing %q, got: %s", tt.expected, p.PrintErrors())
+			}
+			if len(schema.Services) != 1 || len(schema.Services[0].Methods) != 2 {
+				t.Errorf("Expected parsing to continue with the next method")
+			}
+		})
+	}
+}
+
+func TestParseWebhook(t *testing.T) {
+	input := `service PaymentService {
+		rpc CreatePayment(Payment) returns (Payment)
+		rpc PaymentSettled(PaymentEvent) returns ()
+			@webhook("paymentSettled", on=CreatePayment, url="{$request.body#/callbackUrl}")
+		rpc RefundIssued(PaymentEvent) returns () @webhook @http.method(PUT)
+	}`
+
+	p := New(lexer.New(input))
+	schema := p.Parse()
+	if len(p.Errors()) > 0 || len(p.Warnings()) > 0 {
+		t.Fatalf("Unexpected problems: %s %v", p.PrintErrors(), p.Warnings())
+	}
+
+	methods := schema.Services[0].Methods
+	if methods[0].IsWebhook() {
+		t.Error("Expected CreatePayment to be served by the API")
+	}
+	want := ast.Webhook{Name: "paymentSettled", On: "CreatePayment", URL: "{$request.body#/callbackUrl}"}
+	if webhook := methods[1].Webhook; webhook == nil || *webhook != want || !webhook.IsCallback() {
+		t.Errorf("Expected callback %+v, got %+v", want, webhook)
+	}
+	if webhook := methods[2].Webhook; webhook == nil || webhook.Name != "refundIssued" || webhook.IsCallback() {
+		t.Errorf("Expected webhook named after the method, got %+v", webhook)
+	}
+	if method := methods[2].WebhookHTTPMethod(); method != "put" {
+		t.Errorf("Expected the webhook to be sent with PUT, got %q", method)
+	}
+}
+
+func TestParseWebhookErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		webhook  string
+		expected string
+	}{
+		{"callback without url", `@webhook("settled", on=Create)`, "a callback @webhook needs both on= and url="},
+		{"url without callback", `@webhook("settled", url="{$request.body#/url}")`, "a callback @webhook needs both on= and url="},
+		{"empty name", `@webhook("")`, "webhook name must not be empty"},
+		{"unknown option", `@webhook("settled", every="minute")`, "unknown option every in @webhook"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := "service S {\n  rpc Get(Req) returns (Res) " + tt.webhook + "\n  rpc Next(Req) returns (Res)\n}"
+			p := New(lexer.New(input))
+			schema := p.Parse()
+			if !strings.Contains(p.PrintErrors(), tt.expected) {
+				t.Errorf("Expected error containing %q, got: %s", tt.expected, p.PrintErrors())
+			}
+			if len(schema.Services) != 1 || len(schema.Services[0].Methods) != 2 {
+				t.Errorf("Expected parsing to continue with the next method")
+			}
+		})
+	}
+}
+
 func TestParseServiceWithMultipleHTTPMethods(t *testing.T) {
 	input := `
 service UserService {
@@ -2935,3 +3159,288 @@ type ComplexType {
 		}
 	}
 }
+
+func TestParseNonNullArrayItems(t *testing.T) {
+	input := `
+type Post {
+	tags: []string! = 1
+	labels: []string!? = 2
+	authors: []User = 3
+}
+`
+
+	l := lexer.New(input)
+	p := New(l)
+	schema := p.Parse()
+
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Unexpected errors: %s", p.PrintErrors())
+	}
+
+	tests := []struct {
+		nonNullItems bool
+		optional     bool
+	}{
+		{true, false},
+		{true, true},
+		{false, false},
+	}
+	for i, tt := range tests {
+		field := schema.Types[0].Fields[i]
+		if field.Type.NonNullItems != tt.nonNullItems || field.Type.Optional != tt.optional {
+			t.Errorf("Field %q: expected nonNullItems=%v optional=%v, got %v %v", field.Name, tt.nonNullItems, tt.optional, field.Type.NonNullItems, field.Type.Optional)
+		}
+	}
+
+	p = New(lexer.New("type Post {\n\ttitle: string! = 1\n}\n"))
+	p.Parse()
+	if len(p.Errors()) == 0 || !strings.Contains(p.Errors()[0], "[]string!") {
+		t.Errorf("Expected an error for a non-null scalar, got %v", p.Errors())
+	}
+}
+
+func TestParseErrorRecovery(t *testing.T) {
+	input := `type User {
+  id: string = 1
+  name string = 2
+  email: string = 3 $
+  role: Role = 4
+}
+
+enum Role {
+  ADMIN = 1
+  USER = x
+  GUEST = 3
+}
+
+type Broken {
+  a: string = 1
+
+type Other {
+  b: string = 1
+}
+
+service Api {
+  rpc Get(User returns (User)
+  rpc List(User) returns (User)
+}
+
+%%%
+
+union Any {
+  User
+  42
+  Other
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	schema := p.Parse()
+
+	expected := []string{
+		"Line 3:8 - expected :, got IDENT",
+		`Line 4:21 - unexpected character "$"`,
+		"Line 10:10 - expected number after =",
+		"Line 17:1 - expected } to close type Broken, got TYPE",
+		"Line 22:16 - expected ), got RETURNS",
+		`Line 26:1 - unexpected character "%"`,
+		`Line 26:2 - unexpected character "%"`,
+		`Line 26:3 - unexpected character "%"`,
+		"Line 30:3 - expected type name in union",
+	}
+	errors := p.Errors()
+	if len(errors) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d:\n%s", len(expected), len(errors), p.PrintErrors())
+	}
+	for i, want := range expected {
+		if errors[i] != want {
+			t.Errorf("Error %d: expected %q, got %q", i, want, errors[i])
+		}
+	}
+
+	// Declarations and members around the errors are still parsed
+	var typeNames []string
+	for _, typ := range schema.Types {
+		typeNames = append(typeNames, typ.Name)
+	}
+	if strings.Join(typeNames, ",") != "User,Broken,Other" {
+		t.Errorf("Expected types User,Broken,Other, got %v", typeNames)
+	}
+	if len(schema.Types[0].Fields) != 3 {
+		t.Errorf("Expected User to keep 3 valid fields, got %d", len(schema.Types[0].Fields))
+	}
+	if len(schema.Enums) != 1 || len(schema.Enums[0].Values) != 2 {
+		t.Errorf("Expected enum Role with 2 valid values, got %+v", schema.Enums)
+	}
+	if len(schema.Services) != 1 || len(schema.Services[0].Methods) != 1 || schema.Services[0].Methods[0].Name != "List" {
+		t.Errorf("Expected service Api with method List, got %+v", schema.Services)
+	}
+	if len(schema.Unions) != 1 || strings.Join(schema.Unions[0].Options, ",") != "User,Other" {
+		t.Errorf("Expected union Any with options User,Other, got %+v", schema.Unions)
+	}
+}
+
+func TestParseErrorPositions_WindowsLineEndings(t *testing.T) {
+	input := "/// A user\ntype User {\n\tid: string = 1\n\tname string = 2\n}\n\nenum Role {\n\tADMIN = x\n}\n"
+	want := []string{
+		"Line 4:7 - expected :, got IDENT",
+		"Line 8:10 - expected number after =",
+	}
+
+	for _, source := range []string{input, "\uFEFF" + strings.ReplaceAll(input, "\n", "\r\n")} {
+		p := New(lexer.New(source))
+		schema := p.Parse()
+		if got := p.Errors(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("%q: expected errors %q, got %q", source, want, got)
+		}
+		if len(schema.Types) != 1 || schema.Types[0].Doc == nil || schema.Types[0].Doc.General != "A user" {
+			t.Errorf("%q: expected the doc comment without a carriage return, got %+v", source, schema.Types)
+		}
+	}
+}
+
+func TestParseErrorRecoveryAtTopLevel(t *testing.T) {
+	input := `type {
+  a: string = 1
+}
+
+type Valid {
+  id: string = 1
+}
+
+garbage here
+
+enum {
+}
+
+service Api {
+  rpc Get(Valid) returns (Valid)
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	schema := p.Parse()
+
+	expected := []string{
+		"Line 1:6 - expected type name",
+		"Line 9:1 - unexpected IDENT, expected a declaration",
+		"Line 11:6 - expected enum name",
+	}
+	if p.PrintErrors() != strings.Join(expected, "\n") {
+		t.Errorf("Expected errors:\n%s\ngot:\n%s", strings.Join(expected, "\n"), p.PrintErrors())
+	}
+	if len(schema.Types) != 1 || schema.Types[0].Name != "Valid" {
+		t.Errorf("Expected only type Valid, got %+v", schema.Types)
+	}
+	if len(schema.Services) != 1 {
+		t.Errorf("Expected service Api to be parsed, got %+v", schema.Services)
+	}
+}
+
+func TestParseEscapedStrings(t *testing.T) {
+	input := `@graphql.directive("@link(url: \"https://example.com/über\")")
+namespace com.example
+
+type User {
+  name: string = 1 @deprecated("use \"fullName\" instead", since="2.0")
+  bio: string = 2 @validate(pattern="^\w+\b")
+}
+
+service UserService {
+  rpc GetUser(User) returns (User) @http.path("/users/{id}/\"raw\"")
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	schema := p.Parse()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Unexpected errors: %s", p.PrintErrors())
+	}
+
+	if got := schema.Types[0].Fields[0].Deprecated.Reason; got != `use "fullName" instead` {
+		t.Errorf("Expected decoded deprecation reason, got %q", got)
+	}
+	if got := schema.Types[0].Fields[1].Validation.Pattern; got != `^\w+\b` {
+		t.Errorf("Expected pattern with backslashes kept, got %q", got)
+	}
+	if got := schema.Services[0].Methods[0].PathTemplate; got != `/users/{id}/"raw"` {
+		t.Errorf("Expected decoded path, got %q", got)
+	}
+	if got := schema.NamespaceAnnotations.GraphQL[0]; got != `"@link(url: \"https://example.com/über\")"` {
+		t.Errorf("Expected annotation content re-quoted, got %q", got)
+	}
+}
+
+func TestParseTypeExtends(t *testing.T) {
+	input := `namespace com.example
+
+type Admin extends User, audit.Auditable @graphql.name("AdminUser") {
+  role: string = 20
+}
+
+type extends {
+  extends: string = 1
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	schema := p.Parse()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Unexpected errors: %s", p.PrintErrors())
+	}
+
+	admin := schema.Types[0]
+	if strings.Join(admin.Extends, ",") != "User,audit.Auditable" {
+		t.Errorf("Expected bases User and audit.Auditable, got %v", admin.Extends)
+	}
+	if admin.Annotations == nil || admin.Annotations.GraphQLName != "AdminUser" {
+		t.Error("Expected annotation after the extends list to be parsed")
+	}
+	if len(admin.Fields) != 1 || admin.Fields[0].Name != "role" {
+		t.Errorf("Expected only the declared field, got %d fields", len(admin.Fields))
+	}
+	if len(schema.Types) != 2 || schema.Types[1].Fields[0].Name != "extends" {
+		t.Error("Expected extends to remain usable as a name")
+	}
+
+	p = New(lexer.New("namespace com.example\n\ntype Admin extends {\n  role: string\n}"))
+	p.Parse()
+	if !strings.Contains(p.PrintErrors(), "expected base type name after extends") {
+		t.Errorf("Expected missing base error, got: %s", p.PrintErrors())
+	}
+}
+
+func TestParseRecursiveTypes(t *testing.T) {
+	input := `
+type Tree {
+    value: string
+    children: []Tree
+    parent: Tree
+}
+
+type Employee {
+    manager: Employee
+    department: Department
+}
+
+type Department {
+    head: Employee @required
+}
+`
+	p := New(lexer.New(input))
+	schema := p.Parse()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("Unexpected errors: %s", p.PrintErrors())
+	}
+	if errs := schema.Validate(); len(errs) != 0 {
+		t.Errorf("Expected recursive types to be valid, got %v", errs)
+	}
+
+	children := schema.Types[0].Fields[1].Type
+	if children.Name != "Tree" || !children.IsArray {
+		t.Errorf("Expected children to be []Tree, got %+v", children)
+	}
+	if head := schema.Types[2].Fields[0].Type; head.Name != "Employee" {
+		t.Errorf("Expected head to be Employee, got %+v", head)
+	}
+}
@@ -0,0 +1,238 @@
+// Package roundtrip imports a Protobuf, GraphQL, or OpenAPI schema into TypeMUX,
+// generates the same format from the result and compares it with the original,
+// to show what the importers and generators lose on the way.
+package roundtrip
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/generator"
+	"github.com/rasmartins/typemux/internal/importers/fidelity"
+	"github.com/rasmartins/typemux/internal/importers/graphql"
+	"github.com/rasmartins/typemux/internal/importers/openapi"
+	"github.com/rasmartins/typemux/internal/importers/protobuf"
+)
+
+// Format is a schema format with an importer and a generator.
+type Format string
+
+const (
+	// Protobuf is a .proto file.
+	Protobuf Format = "protobuf"
+	// GraphQL is a GraphQL SDL file.
+	GraphQL Format = "graphql"
+	// OpenAPI is an OpenAPI 3 document in YAML or JSON.
+	OpenAPI Format = "openapi"
+)
+
+// ParseFormat returns the format of a name such as "proto" or "graphql".
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "protobuf", "proto":
+		return Protobuf, nil
+	case "graphql", "gql":
+		return GraphQL, nil
+	case "openapi":
+		return OpenAPI, nil
+	default:
+		return "", fmt.Errorf("unknown format %q (expected protobuf, graphql, or openapi)", name)
+	}
+}
+
+// DetectFormat returns the format of a file from its extension.
+func DetectFormat(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".proto":
+		return Protobuf, nil
+	case ".graphql", ".graphqls", ".gql":
+		return GraphQL, nil
+	case ".yaml", ".yml", ".json":
+		return OpenAPI, nil
+	default:
+		return "", fmt.Errorf("cannot tell the format of %s from its extension; use -format", path)
+	}
+}
+
+// Loader parses a TypeMUX file together with its imports.
+type Loader func(path string) (*ast.Schema, error)
+
+// Result is the outcome of a round trip.
+type Result struct {
+	Format Format
+	// Imported is the TypeMUX file the input was imported into
+	Imported string
+	// Generated is the regenerated schema in the input format
+	Generated string
+	Report    *fidelity.Report
+}
+
+// Run imports the schema at path into TypeMUX files in dir, loads them with
+// load, generates the input format again and compares it with the original.
+func Run(path string, format Format, dir string, load Loader) (*Result, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case Protobuf:
+		return runProtobuf(path, content, dir, load)
+	case GraphQL:
+		return runGraphQL(path, content, dir, load)
+	case OpenAPI:
+		return runOpenAPI(path, content, dir, load)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// runProtobuf round-trips a .proto file. Its imports are converted next to it,
+// keeping their paths relative to the directory of the file.
+func runProtobuf(path string, content []byte, dir string, load Loader) (*Result, error) {
+	root := filepath.Dir(path)
+	schemas, err := protobuf.NewParserWithImports(string(content), path, []string{root}).ParseWithImports()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	converter := protobuf.NewConverter()
+	imported := ""
+	for protoPath, schema := range schemas {
+		rel, err := filepath.Rel(root, protoPath)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			rel = filepath.Base(protoPath)
+		}
+		target := filepath.Join(dir, strings.TrimSuffix(rel, ".proto")+".typemux")
+		if err := writeFile(target, converter.Convert(schema)); err != nil {
+			return nil, err
+		}
+		if protoPath == path {
+			imported = target
+		}
+	}
+
+	schema, err := load(imported)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load imported schema: %w", err)
+	}
+
+	// Schemas of several namespaces come out as one file per namespace
+	original := schemas[path]
+	files, err := generate("protobuf", schema)
+	if err != nil {
+		return nil, err
+	}
+	generated := string(files["schema.proto"])
+	if file, ok := files[strings.ReplaceAll(original.Package, ".", "/")+".proto"]; ok && len(files) > 1 {
+		generated = string(file)
+	}
+
+	regenerated, err := protobuf.NewParser(generated).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated Protobuf: %w", err)
+	}
+
+	return &Result{
+		Format:    Protobuf,
+		Imported:  imported,
+		Generated: generated,
+		Report:    protobuf.Compare(original, regenerated),
+	}, nil
+}
+
+// runGraphQL round-trips a GraphQL schema
+func runGraphQL(path string, content []byte, dir string, load Loader) (*Result, error) {
+	original, err := graphql.NewParser(string(content)).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	schema, imported, err := importInto(dir, path, graphql.NewConverter().Convert(original), load)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := generate("graphql", schema)
+	if err != nil {
+		return nil, err
+	}
+	generated := string(files["schema.graphql"])
+	regenerated, err := graphql.NewParser(generated).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated GraphQL: %w", err)
+	}
+
+	return &Result{
+		Format:    GraphQL,
+		Imported:  imported,
+		Generated: generated,
+		Report:    graphql.Compare(original, regenerated),
+	}, nil
+}
+
+// runOpenAPI round-trips an OpenAPI document
+func runOpenAPI(path string, content []byte, dir string, load Loader) (*Result, error) {
+	original, err := openapi.NewParser(content).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	schema, imported, err := importInto(dir, path, openapi.NewConverter().Convert(original), load)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := generate("openapi", schema)
+	if err != nil {
+		return nil, err
+	}
+	generated := string(files["openapi.yaml"])
+	regenerated, err := openapi.NewParser([]byte(generated)).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated OpenAPI: %w", err)
+	}
+
+	return &Result{
+		Format:    OpenAPI,
+		Imported:  imported,
+		Generated: generated,
+		Report:    openapi.Compare(original, regenerated),
+	}, nil
+}
+
+// generate generates the files of a format with the default options
+func generate(format string, schema *ast.Schema) (map[string][]byte, error) {
+	gen, err := generator.Lookup(format)
+	if err != nil {
+		return nil, err
+	}
+	return gen.Generate(context.Background(), schema, generator.Options{})
+}
+
+// importInto writes the TypeMUX file imported from path into dir and loads it
+func importInto(dir, path, idl string, load Loader) (*ast.Schema, string, error) {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	imported := filepath.Join(dir, base+".typemux")
+	if err := writeFile(imported, idl); err != nil {
+		return nil, "", err
+	}
+
+	schema, err := load(imported)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load imported schema: %w", err)
+	}
+	return schema, imported, nil
+}
+
+// writeFile writes a file, creating its directory
+func writeFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o600)
+}
@@ -0,0 +1,183 @@
+package roundtrip
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/lexer"
+	"github.com/rasmartins/typemux/internal/parser"
+)
+
+// loadFile parses a single TypeMUX file, which is all the tests need
+func loadFile(path string) (*ast.Schema, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := parser.New(lexer.New(string(content)))
+	schema := p.Parse()
+	if len(p.Errors()) > 0 {
+		return nil, fmt.Errorf("%s", p.PrintErrors())
+	}
+	return schema, nil
+}
+
+// writeInput writes an input schema into a temporary directory
+func writeInput(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		imported string
+		lost     string
+	}{
+		{
+			name: "protobuf",
+			file: "shop.proto",
+			content: `syntax = "proto3";
+package shop;
+
+// An order
+message Order {
+  string id = 1;
+  repeated string items = 2;
+}
+
+service OrderService {
+  rpc GetOrder(Order) returns (Order);
+}
+`,
+			imported: "shop.typemux",
+		},
+		{
+			name: "graphql",
+			file: "shop.graphqls",
+			content: `type Order {
+  id: ID!
+}
+
+type Query {
+  order(id: ID!): Order
+}
+`,
+			imported: "shop.typemux",
+			lost:     "type.Order.id.type",
+		},
+		{
+			name: "openapi",
+			file: "shop.yaml",
+			content: `openapi: 3.0.0
+info:
+  title: Shop
+  version: 1.0.0
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Order'
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        id:
+          type: string
+`,
+			imported: "shop.typemux",
+			lost:     "paths./orders.GET.operationId",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := writeInput(t, tt.file, tt.content)
+			format, err := DetectFormat(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dir := t.TempDir()
+			result, err := Run(input, format, dir, loadFile)
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if result.Imported != filepath.Join(dir, tt.imported) {
+				t.Errorf("Expected imported file %s, got %s", filepath.Join(dir, tt.imported), result.Imported)
+			}
+			if result.Generated == "" {
+				t.Error("Expected a regenerated schema")
+			}
+			if result.Report.Compared == 0 {
+				t.Error("Expected compared elements")
+			}
+
+			lost := result.Report.Lost()
+			if tt.lost == "" {
+				if len(lost) != 0 {
+					t.Errorf("Expected a lossless round trip, got %v", lost)
+				}
+				return
+			}
+			found := false
+			for _, difference := range lost {
+				found = found || difference.Path == tt.lost
+			}
+			if !found {
+				t.Errorf("Expected %s to be lost, got %v", tt.lost, lost)
+			}
+		})
+	}
+}
+
+func TestRun_ParseErrors(t *testing.T) {
+	input := writeInput(t, "broken.yaml", "openapi: [")
+	_, err := Run(input, OpenAPI, t.TempDir(), loadFile)
+	if err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	detected := map[string]Format{
+		"a.proto":    Protobuf,
+		"a.graphqls": GraphQL,
+		"a.GRAPHQL":  GraphQL,
+		"a.yml":      OpenAPI,
+		"a.json":     OpenAPI,
+	}
+	for path, expected := range detected {
+		if format, err := DetectFormat(path); err != nil || format != expected {
+			t.Errorf("DetectFormat(%q) = %q, %v; want %q", path, format, err, expected)
+		}
+	}
+	if _, err := DetectFormat("a.txt"); err == nil {
+		t.Error("Expected an error for an unknown extension")
+	}
+
+	if format, err := ParseFormat("proto"); err != nil || format != Protobuf {
+		t.Errorf("ParseFormat(proto) = %q, %v", format, err)
+	}
+	if _, err := ParseFormat("thrift"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,213 @@
+// Package server serves the TypeMUX compiler over HTTP, for web playgrounds and
+// CI jobs that generate code without installing the binary.
+//
+// POST /v1/generate compiles a schema sent as JSON and responds with the
+// generated files, as JSON or as a zip archive. Schemas are compiled by package
+// compile, so the server never reads its own file system on behalf of a request.
+package server
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rasmartins/typemux/internal/compile"
+	"github.com/rasmartins/typemux/internal/diagnostic"
+	"github.com/rasmartins/typemux/internal/loader"
+)
+
+// Defaults of the options of a server
+const (
+	DefaultMaxRequestSize = 10 << 20
+	DefaultTimeout        = 30 * time.Second
+)
+
+// Options configures a server.
+type Options struct {
+	// Limits bounds the size and complexity of the schemas of requests
+	Limits loader.Limits
+
+	// MaxRequestSize is the largest request body, in bytes
+	// (default: DefaultMaxRequestSize)
+	MaxRequestSize int64
+
+	// Timeout bounds the generation of a request (default: DefaultTimeout)
+	Timeout time.Duration
+
+	// Version is reported by /healthz
+	Version string
+
+	// Logger logs failed requests (default: no logging)
+	Logger *log.Logger
+}
+
+// Server is an http.Handler serving the compiler.
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+}
+
+// New creates a server with the given options.
+func New(opts Options) *Server {
+	if opts.MaxRequestSize <= 0 {
+		opts.MaxRequestSize = DefaultMaxRequestSize
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	s := &Server{opts: opts, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.mux.HandleFunc("/v1/generate", s.handleGenerate)
+	return s
+}
+
+// ServeHTTP dispatches a request to its endpoint.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// GenerateRequest is the body of POST /v1/generate: the schema to compile and
+// the options of compile.Compile, such as files, annotations, and formats.
+type GenerateRequest struct {
+	// Schema is the content of the schema to compile (required)
+	Schema string `json:"schema"`
+
+	compile.Options
+}
+
+// GenerateResponse is the JSON response of POST /v1/generate.
+type GenerateResponse = compile.Result
+
+// ErrorResponse is the response of a failed request.
+type ErrorResponse struct {
+	Error       string                  `json:"error"`
+	Diagnostics []diagnostic.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// handleHealth reports that the server is up
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		s.writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": s.opts.Version})
+}
+
+// handleGenerate compiles the schema of a request and responds with the files
+func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		s.writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	var req GenerateRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.opts.MaxRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			s.writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body is larger than %d bytes", tooLarge.Limit))
+			return
+		}
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	if strings.TrimSpace(req.Schema) == "" {
+		s.writeError(w, http.StatusBadRequest, errors.New("invalid request: schema is required"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.opts.Timeout)
+	defer cancel()
+
+	response, err := s.Generate(ctx, &req)
+	if err != nil {
+		s.writeError(w, statusOf(err), err)
+		return
+	}
+
+	if wantsZip(r) {
+		writeZip(w, response.Files)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Generate compiles the schema of a request within the limits of the server
+// and returns the generated files.
+func (s *Server) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	opts := req.Options
+	opts.Limits = s.opts.Limits
+	return compile.Compile(ctx, req.Schema, opts)
+}
+
+// statusOf returns the HTTP status of a failed generation
+func statusOf(err error) int {
+	if errors.Is(err, loader.ErrLimitExceeded) {
+		return http.StatusRequestEntityTooLarge
+	}
+	switch diagnostic.KindOf(err) {
+	case diagnostic.Parse, diagnostic.Validation:
+		return http.StatusUnprocessableEntity
+	case diagnostic.Generation:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// wantsZip reports whether a request asks for a zip archive, with
+// ?archive=zip or an Accept header of application/zip
+func wantsZip(r *http.Request) bool {
+	return r.URL.Query().Get("archive") == "zip" || strings.Contains(r.Header.Get("Accept"), "application/zip")
+}
+
+// writeError writes an error response with the diagnostics of err
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	var response ErrorResponse
+	response.Error, response.Diagnostics = compile.Failure(err)
+	if s.opts.Logger != nil {
+		s.opts.Logger.Printf("%d: %s", status, response.Error)
+	}
+	writeJSON(w, status, response)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(value) //nolint:errcheck // the client is gone
+}
+
+// writeZip writes the generated files as a zip archive, in path order
+func writeZip(w http.ResponseWriter, files map[string]string) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="typemux.zip"`)
+	archive := zip.NewWriter(w)
+	for _, name := range names {
+		file, err := archive.Create(name)
+		if err != nil {
+			return
+		}
+		if _, err := file.Write([]byte(files[name])); err != nil {
+			return
+		}
+	}
+	_ = archive.Close() //nolint:errcheck // the client is gone
+}
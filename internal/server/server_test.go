@@ -0,0 +1,131 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/compile"
+	"github.com/rasmartins/typemux/internal/loader"
+)
+
+// post sends a generate request to a server and returns the recorded response
+func post(t *testing.T, s *Server, target string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, bytes.NewReader(data)))
+	return rec
+}
+
+func TestGenerate(t *testing.T) {
+	s := New(Options{})
+	rec := post(t, s, "/v1/generate", GenerateRequest{
+		Schema: `namespace api
+import "common/audit.typemux"
+
+type User {
+  user_id: string = 1 @required
+  audit: common.Audit = 2
+}`,
+		Options: compile.Options{
+			Files:       map[string]string{"common/audit.typemux": "namespace common\n\ntype Audit {\n  at: timestamp = 1\n}\n"},
+			Annotations: []string{"types:\n  User:\n    graphql:\n      name: Account\n"},
+			Formats:     []string{"graphql", "proto"},
+			Naming:      "standard",
+		},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var response GenerateResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	graphql := response.Files["schema.graphql"]
+	if !strings.Contains(graphql, "type Account {") || !strings.Contains(graphql, "userId: String!") || !strings.Contains(graphql, "type Audit {") {
+		t.Errorf("Expected the annotated and renamed GraphQL schema with the imported type, got:\n%s", graphql)
+	}
+	if len(response.Files) < 2 {
+		t.Errorf("Expected the GraphQL and Protobuf files, got %d file(s)", len(response.Files))
+	}
+}
+
+func TestGenerate_Zip(t *testing.T) {
+	rec := post(t, New(Options{}), "/v1/generate?archive=zip", GenerateRequest{Schema: "type User {\n  id: string = 1\n}\n"})
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/zip" {
+		t.Fatalf("Expected a zip archive, got %d %s: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	if got := strings.Join(names, ","); got != "openapi.yaml,schema.graphql,schema.proto,types.go" {
+		t.Errorf("Expected the files of the default formats, got %s", got)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	s := New(Options{Limits: loader.Limits{MaxFields: 2}, MaxRequestSize: 2048})
+	tests := []struct {
+		name   string
+		body   interface{}
+		status int
+		want   string
+	}{
+		{"missing schema", GenerateRequest{}, http.StatusBadRequest, "schema is required"},
+		{"unknown field", map[string]string{"schema": "type A {}", "output": "/tmp"}, http.StatusBadRequest, "unknown field"},
+		{"unknown format", GenerateRequest{Schema: "type A {}", Options: compile.Options{Formats: []string{"cobol"}}}, http.StatusBadRequest, "unknown format"},
+		{"unknown naming", GenerateRequest{Schema: "type A {}", Options: compile.Options{Naming: "kebab"}}, http.StatusBadRequest, "unknown naming policy"},
+		{"parse error", GenerateRequest{Schema: "type A {"}, http.StatusUnprocessableEntity, "schema.typemux"},
+		{"validation error", GenerateRequest{Schema: "type A {\n  b: Missing = 1\n}"}, http.StatusUnprocessableEntity, "Missing"},
+		{"import outside the request", GenerateRequest{Schema: "import \"../secrets.typemux\"\ntype A {}"}, http.StatusUnprocessableEntity, "was not passed with the schema"},
+		{"limit", GenerateRequest{Schema: "type A {\n  a: string = 1\n  b: string = 2\n  c: string = 3\n}"}, http.StatusRequestEntityTooLarge, "limit exceeded"},
+		{"request size", GenerateRequest{Schema: "type A {}\n" + strings.Repeat("// padding\n", 300)}, http.StatusRequestEntityTooLarge, "larger than 2048 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := post(t, s, "/v1/generate", tt.body)
+			if rec.Code != tt.status {
+				t.Errorf("Expected %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			var response ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(response.Error, tt.want) {
+				t.Errorf("Expected an error containing %q, got %q", tt.want, response.Error)
+			}
+			if strings.Contains(rec.Body.String(), "/typemux/") {
+				t.Errorf("Expected paths relative to the schema, got %s", rec.Body)
+			}
+		})
+	}
+}
+
+func TestHealth(t *testing.T) {
+	s := New(Options{Version: "1.0.0"})
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"version": "1.0.0"`) {
+		t.Errorf("Expected the health status, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/generate", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET /v1/generate, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,48 @@
+// Package stdlib provides the TypeMUX standard library: schema files for common
+// types that are embedded in the compiler and imported with a "typemux/std/" path
+// instead of a path relative to the importing file.
+package stdlib
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// Prefix starts the import path of every standard library file.
+const Prefix = "typemux/std/"
+
+// Namespace is the namespace of the standard library types.
+const Namespace = "typemux.std"
+
+//go:embed std/*.typemux
+var files embed.FS
+
+// IsImport reports whether an import path refers to the standard library.
+func IsImport(importPath string) bool {
+	return strings.HasPrefix(importPath, Prefix)
+}
+
+// Read returns the content of the standard library file behind an import path,
+// such as "typemux/std/money.typemux".
+func Read(importPath string) ([]byte, error) {
+	name := strings.TrimPrefix(importPath, Prefix)
+	content, err := fs.ReadFile(files, "std/"+name)
+	if err != nil || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("unknown standard library file %s (available: %s)", importPath, strings.Join(Files(), ", "))
+	}
+	return content, nil
+}
+
+// Files returns the import paths of all standard library files, sorted.
+func Files() []string {
+	entries, _ := fs.ReadDir(files, "std")
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, Prefix+entry.Name())
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,68 @@
+package stdlib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux/internal/lexer"
+	"github.com/rasmartins/typemux/internal/parser"
+)
+
+func TestFiles_Parse(t *testing.T) {
+	paths := Files()
+	if len(paths) == 0 {
+		t.Fatal("Expected embedded standard library files")
+	}
+
+	defined := make(map[string]bool)
+	for _, path := range paths {
+		content, err := Read(path)
+		if err != nil {
+			t.Fatalf("Read(%s) failed: %v", path, err)
+		}
+		p := parser.New(lexer.New(string(content)))
+		schema := p.Parse()
+		if len(p.Errors()) > 0 || len(p.Warnings()) > 0 {
+			t.Errorf("%s: unexpected problems:\n%s%v", path, p.PrintErrors(), p.Warnings())
+		}
+		if schema.Namespace != Namespace {
+			t.Errorf("%s: expected namespace %s, got %s", path, Namespace, schema.Namespace)
+		}
+		if len(schema.Imports) > 0 {
+			t.Errorf("%s: expected no imports, got %v", path, schema.Imports)
+		}
+		if warnings := schema.HygieneWarnings(); len(warnings) > 0 {
+			t.Errorf("%s: unexpected hygiene warnings: %v", path, warnings)
+		}
+		for _, typ := range schema.Types {
+			if typ.Doc == nil || typ.Doc.General == "" {
+				t.Errorf("%s: type %s has no documentation", path, typ.Name)
+			}
+			defined[typ.Name] = true
+		}
+	}
+
+	for _, name := range []string{"Money", "PageInfo", "Address", "Error", "Empty", "FieldMask"} {
+		if !defined[name] {
+			t.Errorf("Expected the standard library to define %s", name)
+		}
+	}
+}
+
+func TestRead_Unknown(t *testing.T) {
+	for _, path := range []string{"typemux/std/missing.typemux", "typemux/std/../stdlib.go"} {
+		_, err := Read(path)
+		if err == nil || !strings.Contains(err.Error(), "unknown standard library file") {
+			t.Errorf("Read(%s): expected unknown file error, got %v", path, err)
+		}
+	}
+}
+
+func TestIsImport(t *testing.T) {
+	if !IsImport("typemux/std/money.typemux") {
+		t.Error("Expected typemux/std/ path to be a standard library import")
+	}
+	if IsImport("common.typemux") || IsImport("../typemux/std/money.typemux") {
+		t.Error("Expected relative paths not to be standard library imports")
+	}
+}
@@ -0,0 +1,224 @@
+// Package textdiff compares text files line by line and renders the
+// differences in the unified format of diff -u and git diff.
+package textdiff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Context is the number of unchanged lines shown around each change.
+const Context = 3
+
+// maxEdits bounds the work of the line diff: past it, the differing middle of
+// two files is shown as replaced as a whole, which is correct but less precise
+const maxEdits = 2000
+
+// op is the kind of an edit
+type op byte
+
+const (
+	kept    op = ' '
+	removed op = '-'
+	added   op = '+'
+)
+
+// edit is one line of a diff
+type edit struct {
+	op   op
+	line string
+}
+
+// Unified returns the differences between two texts in the unified format,
+// labelled with oldName and newName, or an empty string when they are equal.
+// Label a missing file /dev/null. Binary files, which hold a NUL byte, are
+// reported as differing without showing the changes, as diff does.
+func Unified(oldName, newName string, oldText, newText []byte) string {
+	if string(oldText) == string(newText) {
+		return ""
+	}
+	if bytes.IndexByte(oldText, 0) >= 0 || bytes.IndexByte(newText, 0) >= 0 {
+		return fmt.Sprintf("Binary files %s and %s differ\n", oldName, newName)
+	}
+	edits := lineEdits(splitLines(string(oldText)), splitLines(string(newText)))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for start := 0; start < len(edits); {
+		// Find the next change and the end of its hunk, merging changes that
+		// are separated by fewer than twice the context lines
+		for start < len(edits) && edits[start].op == kept {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		end := start
+		for i := start; i < len(edits); i++ {
+			if edits[i].op != kept {
+				end = i + 1
+			} else if i-end >= 2*Context {
+				break
+			}
+		}
+		from, to := max(start-Context, 0), min(end+Context, len(edits))
+		writeHunk(&sb, edits, from, to)
+		start = to
+	}
+	return sb.String()
+}
+
+// writeHunk writes the edits in [from, to) as a hunk with its line ranges
+func writeHunk(sb *strings.Builder, edits []edit, from, to int) {
+	oldLine, newLine := 1, 1
+	for _, e := range edits[:from] {
+		if e.op != added {
+			oldLine++
+		}
+		if e.op != removed {
+			newLine++
+		}
+	}
+	oldCount, newCount := 0, 0
+	for _, e := range edits[from:to] {
+		if e.op != added {
+			oldCount++
+		}
+		if e.op != removed {
+			newCount++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+	for _, e := range edits[from:to] {
+		sb.WriteByte(byte(e.op))
+		sb.WriteString(e.line)
+		if !strings.HasSuffix(e.line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats the start and length of a hunk range; an empty range
+// starts at the line before it
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, count)
+	}
+}
+
+// splitLines splits text into lines that keep their line endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineEdits returns the edits that turn a into b: the common prefix and
+// suffix, and the shortest edit script of the lines between them
+func lineEdits(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []edit
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{kept, line})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{kept, line})
+	}
+	return edits
+}
+
+// myers returns the shortest edit script of a and b (Myers, "An O(ND)
+// Difference Algorithm and Its Variations"), or replaces a with b when they
+// differ in more than maxEdits lines
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEdits)
+	// v[offset+k] is the furthest x reached on diagonal k = x - y, and trace[d]
+	// is v before step d
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+
+	edits := make([]edit, 0, n+m)
+	for _, line := range a {
+		edits = append(edits, edit{removed, line})
+	}
+	for _, line := range b {
+		edits = append(edits, edit{added, line})
+	}
+	return edits
+}
+
+// backtrack walks the trace of myers back from the end of both texts
+func backtrack(a, b []string, trace [][]int) []edit {
+	var edits []edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		// trace[d] holds the diagonals -d-1..d+1
+		v := func(k int) int { return trace[d][k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v(k-1) < v(k+1)) {
+			prevK = k + 1
+		}
+		prevX := v(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{kept, a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{added, b[y-1]})
+			} else {
+				edits = append(edits, edit{removed, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
@@ -0,0 +1,93 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		expected string
+	}{
+		{
+			name:     "equal",
+			old:      "a\nb\n",
+			new:      "a\nb\n",
+			expected: "",
+		},
+		{
+			name: "changed line",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n",
+			new:  "1\n2\n3\n4\nfive\n6\n7\n8\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			new:  "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n",
+		},
+		{
+			name:     "new file",
+			old:      "",
+			new:      "a\nb\n",
+			expected: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:     "missing newline",
+			old:      "a\nb",
+			new:      "a\nb\n",
+			expected: "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			name:     "binary",
+			old:      "a\x00\x01\n",
+			new:      "a\x00\x02\n",
+			expected: "Binary files old and new differ\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified("old", "new", []byte(tt.old), []byte(tt.new)); got != tt.expected {
+				t.Errorf("Unexpected diff:\n got: %q\nwant: %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnified_ShortestEdit(t *testing.T) {
+	old := "a\nb\nc\na\nb\nb\na\n"
+	new := "c\nb\na\nb\na\nc\n"
+	diff := Unified("old", "new", []byte(old), []byte(new))
+
+	// The shortest edit script of these sequences has 5 edits
+	changes := 0
+	for _, line := range strings.Split(diff, "\n")[2:] {
+		if strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+") {
+			changes++
+		}
+	}
+	if changes != 5 {
+		t.Errorf("Expected 5 changed lines, got %d:\n%s", changes, diff)
+	}
+}
+
+func TestUnified_LargeChange(t *testing.T) {
+	var old, new strings.Builder
+	for i := 0; i < maxEdits+10; i++ {
+		old.WriteString("old\n")
+		new.WriteString("new\n")
+	}
+	diff := Unified("old", "new", []byte(old.String()), []byte(new.String()))
+	header := fmt.Sprintf("--- old\n+++ new\n@@ -1,%d +1,%d @@\n-old\n", maxEdits+10, maxEdits+10)
+	if !strings.HasPrefix(diff, header) {
+		t.Errorf("Expected the file to be replaced as a whole:\n%.100s", diff)
+	}
+}
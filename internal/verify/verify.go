@@ -0,0 +1,236 @@
+// Package verify runs the compilers and linters of each output format on
+// generated files, so that invalid output is found when it is generated rather
+// than in downstream builds.
+package verify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FilesPlaceholder is the argument of a check command that expands to the files
+// the check verifies.
+const FilesPlaceholder = "{files}"
+
+// Check is a command that verifies the generated files matching a pattern.
+type Check struct {
+	// Name of the check in reports, such as the format it verifies
+	Name string `json:"name"`
+
+	// Files is a glob of the files to verify, relative to the output
+	// directory. A pattern without a slash, such as *.proto, matches file
+	// names in every directory.
+	Files string `json:"files"`
+
+	// Command is the program and arguments to run. An argument equal to
+	// {files} expands to the matched files.
+	Command []string `json:"command"`
+
+	// PerDirectory runs the command once per directory holding matched files,
+	// in that directory, for tools such as go vet that take the files of one
+	// package. Otherwise the command runs once in the output directory.
+	PerDirectory bool `json:"perDirectory,omitempty"`
+}
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// Passed means the command succeeded on every run.
+	Passed Status = "passed"
+	// Failed means the command failed or could not be started.
+	Failed Status = "failed"
+	// Skipped means no file matched, or the program is not installed.
+	Skipped Status = "skipped"
+)
+
+// Result is the outcome of running a check.
+type Result struct {
+	Check    Check         `json:"check"`
+	Status   Status        `json:"status"`
+	Files    []string      `json:"files,omitempty"`
+	Reason   string        `json:"reason,omitempty"` // Why the check was skipped or failed
+	Output   string        `json:"output,omitempty"` // Combined output of the failed runs
+	Duration time.Duration `json:"duration"`
+}
+
+// DefaultChecks returns the checks used when none are configured: go vet on Go
+// files, protoc on Protobuf files, graphql-schema-linter on GraphQL SDL, and
+// Spectral on OpenAPI documents. Checks whose program is not installed are
+// skipped.
+func DefaultChecks() []Check {
+	return []Check{
+		{Name: "go", Files: "*.go", Command: []string{"go", "vet", FilesPlaceholder}, PerDirectory: true},
+		{Name: "protobuf", Files: "*.proto", Command: []string{"protoc", "--proto_path=.", "--descriptor_set_out=" + os.DevNull, FilesPlaceholder}},
+		{Name: "graphql", Files: "*.graphql", Command: []string{"graphql-schema-linter", FilesPlaceholder}},
+		{Name: "openapi", Files: "openapi*.yaml", Command: []string{"spectral", "lint", FilesPlaceholder}},
+	}
+}
+
+// Validate reports a check without a name, files, or command.
+func (c Check) Validate() error {
+	switch {
+	case c.Name == "":
+		return fmt.Errorf("check has no name")
+	case c.Files == "":
+		return fmt.Errorf("check %s has no files pattern", c.Name)
+	case len(c.Command) == 0:
+		return fmt.Errorf("check %s has no command", c.Name)
+	}
+	if _, err := path.Match(c.Files, ""); err != nil {
+		return fmt.Errorf("check %s has an invalid files pattern %q: %w", c.Name, c.Files, err)
+	}
+	return nil
+}
+
+// Run runs checks on the files of a directory, in order.
+func Run(ctx context.Context, dir string, checks []Check) ([]Result, error) {
+	files, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		results = append(results, run(ctx, dir, check, files))
+	}
+	return results, nil
+}
+
+// Failing reports whether a check failed, or was skipped because its program is
+// missing when strict is set.
+func Failing(results []Result, strict bool) bool {
+	for _, result := range results {
+		if result.Status == Failed || (strict && result.Status == Skipped && len(result.Files) > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// listFiles returns the slash-separated paths of the files of a directory, relative to it
+func listFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			rel, err := filepath.Rel(dir, file)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing generated files: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// matches reports whether a relative file path matches the files pattern of a check
+func matches(pattern, file string) bool {
+	if !strings.Contains(pattern, "/") {
+		file = path.Base(file)
+	}
+	ok, _ := path.Match(pattern, file)
+	return ok
+}
+
+// run runs a check on the files it matches
+func run(ctx context.Context, dir string, check Check, files []string) Result {
+	result := Result{Check: check, Status: Passed}
+	for _, file := range files {
+		if matches(check.Files, file) {
+			result.Files = append(result.Files, file)
+		}
+	}
+	if len(result.Files) == 0 {
+		result.Status = Skipped
+		result.Reason = fmt.Sprintf("no files match %s", check.Files)
+		return result
+	}
+	if _, err := exec.LookPath(check.Command[0]); err != nil {
+		result.Status = Skipped
+		result.Reason = fmt.Sprintf("%s is not installed", check.Command[0])
+		return result
+	}
+
+	// Group the files by the directory the command runs in
+	groups := map[string][]string{".": result.Files}
+	if check.PerDirectory {
+		groups = make(map[string][]string)
+		for _, file := range result.Files {
+			groups[path.Dir(file)] = append(groups[path.Dir(file)], path.Base(file))
+		}
+	}
+	dirs := make([]string, 0, len(groups))
+	for groupDir := range groups {
+		dirs = append(dirs, groupDir)
+	}
+	sort.Strings(dirs)
+
+	start := time.Now()
+	var output strings.Builder
+	var reasons []string
+	for _, groupDir := range dirs {
+		var args []string
+		for _, arg := range check.Command[1:] {
+			if arg == FilesPlaceholder {
+				args = append(args, groups[groupDir]...)
+			} else {
+				args = append(args, arg)
+			}
+		}
+		cmd := exec.CommandContext(ctx, check.Command[0], args...)
+		cmd.Dir = filepath.Join(dir, filepath.FromSlash(groupDir))
+		var combined bytes.Buffer
+		cmd.Stdout = &combined
+		cmd.Stderr = &combined
+		if err := cmd.Run(); err != nil {
+			result.Status = Failed
+			reason := strings.Join(append(check.Command[:1:1], args...), " ")
+			if groupDir != "." {
+				reason += fmt.Sprintf(" (in %s)", groupDir)
+			}
+			reasons = append(reasons, fmt.Sprintf("%s: %v", reason, err))
+			output.Write(combined.Bytes())
+		}
+	}
+	result.Duration = time.Since(start)
+	result.Output = output.String()
+	result.Reason = strings.Join(reasons, "; ")
+	return result
+}
+
+// Format returns a text report of results: a line per check, followed by the
+// output of the failed ones.
+func Format(results []Result) string {
+	var sb strings.Builder
+	for _, result := range results {
+		fmt.Fprintf(&sb, "%-8s %s", strings.ToUpper(string(result.Status)), result.Check.Name)
+		switch result.Status {
+		case Passed:
+			fmt.Fprintf(&sb, " (%d file(s), %s)\n", len(result.Files), result.Duration.Round(time.Millisecond))
+		default:
+			fmt.Fprintf(&sb, ": %s\n", result.Reason)
+		}
+		if output := strings.TrimSpace(result.Output); output != "" {
+			for _, line := range strings.Split(output, "\n") {
+				sb.WriteString("    " + line + "\n")
+			}
+		}
+	}
+	return sb.String()
+}
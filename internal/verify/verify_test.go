@@ -0,0 +1,117 @@
+package verify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes files, by slash-separated path, into a temporary directory
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"types.go":          "package api\n\ntype User struct{ ID string }\n",
+		"billing/types.go":  "package billing\n\nfunc Broken() int { return \"\" }\n",
+		"schema.graphql":    "type Query { ok: Boolean }\n",
+		"users/users.proto": "syntax = \"proto3\";\n",
+	})
+
+	results, err := Run(context.Background(), dir, []Check{
+		{Name: "go", Files: "*.go", Command: []string{"go", "vet", FilesPlaceholder}, PerDirectory: true},
+		{Name: "graphql", Files: "*.graphql", Command: []string{"go", "version"}},
+		{Name: "missing", Files: "*.graphql", Command: []string{"typemux-no-such-linter", FilesPlaceholder}},
+		{Name: "openapi", Files: "openapi*.yaml", Command: []string{"go", "version"}},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+
+	goResult := results[0]
+	if goResult.Status != Failed || len(goResult.Files) != 2 {
+		t.Errorf("Expected the go check to fail on both files, got %s with %v", goResult.Status, goResult.Files)
+	}
+	if !strings.Contains(goResult.Output, "Broken") && !strings.Contains(goResult.Output, "cannot use") {
+		t.Errorf("Expected the go vet output of the broken package, got:\n%s", goResult.Output)
+	}
+	if results[1].Status != Passed || len(results[1].Files) != 1 {
+		t.Errorf("Expected the graphql check to pass on schema.graphql, got %s with %v", results[1].Status, results[1].Files)
+	}
+	if results[2].Status != Skipped || !strings.Contains(results[2].Reason, "not installed") {
+		t.Errorf("Expected the check of a missing program to be skipped, got %s: %s", results[2].Status, results[2].Reason)
+	}
+	if results[3].Status != Skipped || !strings.Contains(results[3].Reason, "no files match") {
+		t.Errorf("Expected the check without files to be skipped, got %s: %s", results[3].Status, results[3].Reason)
+	}
+
+	if !Failing(results, false) {
+		t.Error("Expected the results to fail")
+	}
+	if Failing(results[1:], false) {
+		t.Error("Expected skipped checks not to fail without strict")
+	}
+	if !Failing(results[1:], true) {
+		t.Error("Expected a check of a missing program to fail with strict")
+	}
+	if Failing(results[3:], true) {
+		t.Error("Expected a check without files not to fail with strict")
+	}
+
+	report := Format(results)
+	for _, want := range []string{"FAILED   go: go vet types.go (in billing)", "PASSED   graphql (1 file(s)", "SKIPPED  missing: typemux-no-such-linter is not installed"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected %q in the report:\n%s", want, report)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"*.proto", "schema.proto", true},
+		{"*.proto", "com/example/users.proto", true},
+		{"com/*/*.proto", "com/example/users.proto", true},
+		{"com/*.proto", "com/example/users.proto", false},
+		{"openapi*.yaml", "openapi.yaml", true},
+		{"openapi*.yaml", "annotations.yaml", false},
+	}
+	for _, tt := range tests {
+		if got := matches(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("matches(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestCheckValidate(t *testing.T) {
+	for _, check := range DefaultChecks() {
+		if err := check.Validate(); err != nil {
+			t.Errorf("Default check %s is invalid: %v", check.Name, err)
+		}
+	}
+	if err := (Check{Name: "lint", Files: "[", Command: []string{"lint"}}).Validate(); err == nil {
+		t.Error("Expected an error for an invalid files pattern")
+	}
+	if err := (Check{Name: "lint", Files: "*.go"}).Validate(); err == nil {
+		t.Error("Expected an error for a check without command")
+	}
+}
@@ -53,6 +53,8 @@ function formatFormats(formats) {
         if (f === 'graphql') return '`GraphQL`';
         if (f === 'openapi') return '`OpenAPI`';
         if (f === 'go') return '`Go`';
+        if (f === 'csharp') return '`C#`';
+        if (f === 'java') return '`Java`';
         return `\`${f}\``;
     });
     return `**Applies to:** ${formatBadges.join(', ')}`;
@@ -0,0 +1,342 @@
+// Code generated by TypeMUX. DO NOT EDIT.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// User role enumeration
+// Defines the different roles a user can have in the system
+type UserRole int32
+
+const (
+	// UserRoleUnspecified is the zero value, used for unset and unknown values.
+	UserRoleUnspecified UserRole = 0
+	// Administrator with full access
+	UserRoleADMIN UserRole = 10
+	// Regular user with limited access
+	UserRoleUSER UserRole = 20
+	// Guest user with read-only access
+	UserRoleGUEST UserRole = 30
+)
+
+// userRoleNames maps UserRole values to their names.
+var userRoleNames = map[UserRole]string{
+	UserRoleUnspecified: "USERROLE_UNSPECIFIED",
+	UserRoleADMIN: "ADMIN",
+	UserRoleUSER: "USER",
+	UserRoleGUEST: "GUEST",
+}
+
+// userRoleValues maps names to UserRole values.
+var userRoleValues = map[string]UserRole{
+	"USERROLE_UNSPECIFIED": UserRoleUnspecified,
+	"ADMIN": UserRoleADMIN,
+	"USER": UserRoleUSER,
+	"GUEST": UserRoleGUEST,
+}
+
+// String returns the name of the value, or UserRole(n) for unknown values.
+func (x UserRole) String() string {
+	if name, ok := userRoleNames[x]; ok {
+		return name
+	}
+	return fmt.Sprintf("UserRole(%d)", int32(x))
+}
+
+// ParseUserRole returns the UserRole with the given name.
+func ParseUserRole(name string) (UserRole, error) {
+	if value, ok := userRoleValues[name]; ok {
+		return value, nil
+	}
+	return UserRoleUnspecified, fmt.Errorf("unknown UserRole %q", name)
+}
+
+// MarshalJSON encodes the value as its name, or as a number for unknown values.
+func (x UserRole) MarshalJSON() ([]byte, error) {
+	if name, ok := userRoleNames[x]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int32(x))
+}
+
+// UnmarshalJSON decodes a name or a number. Unknown names decode to UserRoleUnspecified.
+func (x *UserRole) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		value, ok := userRoleValues[name]
+		if !ok {
+			value = UserRoleUnspecified
+		}
+		*x = value
+		return nil
+	}
+
+	var number int32
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid UserRole: %s", data)
+	}
+	*x = UserRole(number)
+	return nil
+}
+
+// Status enumeration for various entities
+type Status int32
+
+const (
+	// StatusUnspecified is the zero value, used for unset and unknown values.
+	StatusUnspecified Status = 0
+	StatusACTIVE Status = 1
+	StatusINACTIVE Status = 2
+	StatusPENDING Status = 3
+)
+
+// statusNames maps Status values to their names.
+var statusNames = map[Status]string{
+	StatusUnspecified: "STATUS_UNSPECIFIED",
+	StatusACTIVE: "ACTIVE",
+	StatusINACTIVE: "INACTIVE",
+	StatusPENDING: "PENDING",
+}
+
+// statusValues maps names to Status values.
+var statusValues = map[string]Status{
+	"STATUS_UNSPECIFIED": StatusUnspecified,
+	"ACTIVE": StatusACTIVE,
+	"INACTIVE": StatusINACTIVE,
+	"PENDING": StatusPENDING,
+}
+
+// String returns the name of the value, or Status(n) for unknown values.
+func (x Status) String() string {
+	if name, ok := statusNames[x]; ok {
+		return name
+	}
+	return fmt.Sprintf("Status(%d)", int32(x))
+}
+
+// ParseStatus returns the Status with the given name.
+func ParseStatus(name string) (Status, error) {
+	if value, ok := statusValues[name]; ok {
+		return value, nil
+	}
+	return StatusUnspecified, fmt.Errorf("unknown Status %q", name)
+}
+
+// MarshalJSON encodes the value as its name, or as a number for unknown values.
+func (x Status) MarshalJSON() ([]byte, error) {
+	if name, ok := statusNames[x]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int32(x))
+}
+
+// UnmarshalJSON decodes a name or a number. Unknown names decode to StatusUnspecified.
+func (x *Status) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		value, ok := statusValues[name]
+		if !ok {
+			value = StatusUnspecified
+		}
+		*x = value
+		return nil
+	}
+
+	var number int32
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid Status: %s", data)
+	}
+	*x = Status(number)
+	return nil
+}
+
+// User entity representing a system user
+type User struct {
+	// Unique identifier for the user
+	Id string `json:"id"`
+	// Full name of the user
+	Name string `json:"name"`
+	// Email address for contact
+	Email string `json:"email"`
+	// User's age in years
+	Age int32 `json:"age"`
+	// Role assigned to the user
+	Role UserRole `json:"role"`
+	// Whether the user account is active
+	IsActive bool `json:"isActive"`
+	// Timestamp when the user was created
+	CreatedAt time.Time `json:"createdAt"`
+	// Custom tags for categorization
+	Tags []string `json:"tags"`
+	// Additional metadata key-value pairs
+	Metadata map[string]string `json:"metadata"`
+	// Internal database version (excluded from GraphQL and OpenAPI)
+	DbVersion int32 `json:"dbVersion"`
+	// Password hash (only in Protobuf for internal services)
+	PasswordHash string `json:"passwordHash"`
+}
+
+// Validate checks User against the constraints declared in the schema.
+func (m *User) Validate() error {
+	var errs []error
+	if m.Id == "" {
+		errs = append(errs, errors.New("id: is required"))
+	}
+	if m.Name == "" {
+		errs = append(errs, errors.New("name: is required"))
+	}
+	if m.Email == "" {
+		errs = append(errs, errors.New("email: is required"))
+	}
+	if m.CreatedAt.IsZero() {
+		errs = append(errs, errors.New("createdAt: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type Post struct {
+	Id string `json:"id"`
+	Title string `json:"title"`
+	Content string `json:"content"`
+	AuthorId string `json:"authorId"`
+	Status Status `json:"status"`
+	PublishedAt time.Time `json:"publishedAt"`
+	ViewCount int64 `json:"viewCount"`
+	Tags []string `json:"tags"`
+}
+
+// Validate checks Post against the constraints declared in the schema.
+func (m *Post) Validate() error {
+	var errs []error
+	if m.Id == "" {
+		errs = append(errs, errors.New("id: is required"))
+	}
+	if m.Title == "" {
+		errs = append(errs, errors.New("title: is required"))
+	}
+	if m.AuthorId == "" {
+		errs = append(errs, errors.New("authorId: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type CreateUserRequest struct {
+	Name string `json:"name"`
+	Email string `json:"email"`
+	Role UserRole `json:"role"`
+}
+
+// Validate checks CreateUserRequest against the constraints declared in the schema.
+func (m *CreateUserRequest) Validate() error {
+	var errs []error
+	if m.Name == "" {
+		errs = append(errs, errors.New("name: is required"))
+	}
+	if m.Email == "" {
+		errs = append(errs, errors.New("email: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type CreateUserResponse struct {
+	User User `json:"user"`
+	Success bool `json:"success"`
+}
+
+// Validate checks CreateUserResponse against the constraints declared in the schema.
+func (m *CreateUserResponse) Validate() error {
+	var errs []error
+	if err := m.User.Validate(); err != nil {
+		errs = append(errs, validationErrors("user", err)...)
+	}
+	return errors.Join(errs...)
+}
+
+type GetUserRequest struct {
+	Id string `json:"id"`
+}
+
+// Validate checks GetUserRequest against the constraints declared in the schema.
+func (m *GetUserRequest) Validate() error {
+	var errs []error
+	if m.Id == "" {
+		errs = append(errs, errors.New("id: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type GetUserResponse struct {
+	User User `json:"user"`
+}
+
+// Validate checks GetUserResponse against the constraints declared in the schema.
+func (m *GetUserResponse) Validate() error {
+	var errs []error
+	if err := m.User.Validate(); err != nil {
+		errs = append(errs, validationErrors("user", err)...)
+	}
+	return errors.Join(errs...)
+}
+
+type ListUsersRequest struct {
+	Limit int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+	Role UserRole `json:"role"`
+}
+
+type ListUsersResponse struct {
+	Users []User `json:"users"`
+	Total int32 `json:"total"`
+}
+
+// Validate checks ListUsersResponse against the constraints declared in the schema.
+func (m *ListUsersResponse) Validate() error {
+	var errs []error
+	if m.Users == nil {
+		errs = append(errs, errors.New("users: is required"))
+	}
+	for i := range m.Users {
+		if err := m.Users[i].Validate(); err != nil {
+			errs = append(errs, validationErrors(fmt.Sprintf("users[%d]", i), err)...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// User service for managing users
+type UserService interface {
+	// Create a new user
+	CreateUser(ctx context.Context, input *CreateUserRequest) (*CreateUserResponse, error)
+	// Get a user by ID
+	GetUser(ctx context.Context, input *GetUserRequest) (*GetUserResponse, error)
+	// List all users with pagination
+	ListUsers(ctx context.Context, input *ListUsersRequest) (*ListUsersResponse, error)
+	// Delete a user
+	DeleteUser(ctx context.Context, input *GetUserRequest) (*GetUserResponse, error)
+}
+
+// Post service for managing blog posts
+type PostService interface {
+	// Create a new post
+	CreatePost(ctx context.Context, input *Post) (*Post, error)
+	// Get a post by ID
+	GetPost(ctx context.Context, input *GetUserRequest) (*Post, error)
+}
+
+// validationErrors prefixes every error joined in err with the path of the invalid field.
+func validationErrors(path string, err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, validationErrors(path, e)...)
+		}
+		return errs
+	}
+	return []error{fmt.Errorf("%s: %w", path, err)}
+}
+
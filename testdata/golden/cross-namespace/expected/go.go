@@ -0,0 +1,383 @@
+// Code generated by TypeMUX. DO NOT EDIT.
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type OrderStatus int32
+
+const (
+	// OrderStatusUnspecified is the zero value, used for unset and unknown values.
+	OrderStatusUnspecified OrderStatus = 0
+	OrderStatusPENDING OrderStatus = 1
+	OrderStatusCONFIRMED OrderStatus = 2
+	OrderStatusSHIPPED OrderStatus = 3
+	OrderStatusDELIVERED OrderStatus = 4
+	OrderStatusCANCELLED OrderStatus = 5
+)
+
+// orderStatusNames maps OrderStatus values to their names.
+var orderStatusNames = map[OrderStatus]string{
+	OrderStatusUnspecified: "ORDERSTATUS_UNSPECIFIED",
+	OrderStatusPENDING: "PENDING",
+	OrderStatusCONFIRMED: "CONFIRMED",
+	OrderStatusSHIPPED: "SHIPPED",
+	OrderStatusDELIVERED: "DELIVERED",
+	OrderStatusCANCELLED: "CANCELLED",
+}
+
+// orderStatusValues maps names to OrderStatus values.
+var orderStatusValues = map[string]OrderStatus{
+	"ORDERSTATUS_UNSPECIFIED": OrderStatusUnspecified,
+	"PENDING": OrderStatusPENDING,
+	"CONFIRMED": OrderStatusCONFIRMED,
+	"SHIPPED": OrderStatusSHIPPED,
+	"DELIVERED": OrderStatusDELIVERED,
+	"CANCELLED": OrderStatusCANCELLED,
+}
+
+// String returns the name of the value, or OrderStatus(n) for unknown values.
+func (x OrderStatus) String() string {
+	if name, ok := orderStatusNames[x]; ok {
+		return name
+	}
+	return fmt.Sprintf("OrderStatus(%d)", int32(x))
+}
+
+// ParseOrderStatus returns the OrderStatus with the given name.
+func ParseOrderStatus(name string) (OrderStatus, error) {
+	if value, ok := orderStatusValues[name]; ok {
+		return value, nil
+	}
+	return OrderStatusUnspecified, fmt.Errorf("unknown OrderStatus %q", name)
+}
+
+// MarshalJSON encodes the value as its name, or as a number for unknown values.
+func (x OrderStatus) MarshalJSON() ([]byte, error) {
+	if name, ok := orderStatusNames[x]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int32(x))
+}
+
+// UnmarshalJSON decodes a name or a number. Unknown names decode to OrderStatusUnspecified.
+func (x *OrderStatus) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		value, ok := orderStatusValues[name]
+		if !ok {
+			value = OrderStatusUnspecified
+		}
+		*x = value
+		return nil
+	}
+
+	var number int32
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid OrderStatus: %s", data)
+	}
+	*x = OrderStatus(number)
+	return nil
+}
+
+type UserStatus int32
+
+const (
+	// UserStatusUnspecified is the zero value, used for unset and unknown values.
+	UserStatusUnspecified UserStatus = 0
+	UserStatusACTIVE UserStatus = 1
+	UserStatusINACTIVE UserStatus = 2
+	UserStatusSUSPENDED UserStatus = 3
+)
+
+// userStatusNames maps UserStatus values to their names.
+var userStatusNames = map[UserStatus]string{
+	UserStatusUnspecified: "USERSTATUS_UNSPECIFIED",
+	UserStatusACTIVE: "ACTIVE",
+	UserStatusINACTIVE: "INACTIVE",
+	UserStatusSUSPENDED: "SUSPENDED",
+}
+
+// userStatusValues maps names to UserStatus values.
+var userStatusValues = map[string]UserStatus{
+	"USERSTATUS_UNSPECIFIED": UserStatusUnspecified,
+	"ACTIVE": UserStatusACTIVE,
+	"INACTIVE": UserStatusINACTIVE,
+	"SUSPENDED": UserStatusSUSPENDED,
+}
+
+// String returns the name of the value, or UserStatus(n) for unknown values.
+func (x UserStatus) String() string {
+	if name, ok := userStatusNames[x]; ok {
+		return name
+	}
+	return fmt.Sprintf("UserStatus(%d)", int32(x))
+}
+
+// ParseUserStatus returns the UserStatus with the given name.
+func ParseUserStatus(name string) (UserStatus, error) {
+	if value, ok := userStatusValues[name]; ok {
+		return value, nil
+	}
+	return UserStatusUnspecified, fmt.Errorf("unknown UserStatus %q", name)
+}
+
+// MarshalJSON encodes the value as its name, or as a number for unknown values.
+func (x UserStatus) MarshalJSON() ([]byte, error) {
+	if name, ok := userStatusNames[x]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int32(x))
+}
+
+// UnmarshalJSON decodes a name or a number. Unknown names decode to UserStatusUnspecified.
+func (x *UserStatus) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		value, ok := userStatusValues[name]
+		if !ok {
+			value = UserStatusUnspecified
+		}
+		*x = value
+		return nil
+	}
+
+	var number int32
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid UserStatus: %s", data)
+	}
+	*x = UserStatus(number)
+	return nil
+}
+
+type UserRole int32
+
+const (
+	// UserRoleUnspecified is the zero value, used for unset and unknown values.
+	UserRoleUnspecified UserRole = 0
+	UserRoleCUSTOMER UserRole = 1
+	UserRoleADMIN UserRole = 2
+	UserRoleMODERATOR UserRole = 3
+)
+
+// userRoleNames maps UserRole values to their names.
+var userRoleNames = map[UserRole]string{
+	UserRoleUnspecified: "USERROLE_UNSPECIFIED",
+	UserRoleCUSTOMER: "CUSTOMER",
+	UserRoleADMIN: "ADMIN",
+	UserRoleMODERATOR: "MODERATOR",
+}
+
+// userRoleValues maps names to UserRole values.
+var userRoleValues = map[string]UserRole{
+	"USERROLE_UNSPECIFIED": UserRoleUnspecified,
+	"CUSTOMER": UserRoleCUSTOMER,
+	"ADMIN": UserRoleADMIN,
+	"MODERATOR": UserRoleMODERATOR,
+}
+
+// String returns the name of the value, or UserRole(n) for unknown values.
+func (x UserRole) String() string {
+	if name, ok := userRoleNames[x]; ok {
+		return name
+	}
+	return fmt.Sprintf("UserRole(%d)", int32(x))
+}
+
+// ParseUserRole returns the UserRole with the given name.
+func ParseUserRole(name string) (UserRole, error) {
+	if value, ok := userRoleValues[name]; ok {
+		return value, nil
+	}
+	return UserRoleUnspecified, fmt.Errorf("unknown UserRole %q", name)
+}
+
+// MarshalJSON encodes the value as its name, or as a number for unknown values.
+func (x UserRole) MarshalJSON() ([]byte, error) {
+	if name, ok := userRoleNames[x]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int32(x))
+}
+
+// UnmarshalJSON decodes a name or a number. Unknown names decode to UserRoleUnspecified.
+func (x *UserRole) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		value, ok := userRoleValues[name]
+		if !ok {
+			value = UserRoleUnspecified
+		}
+		*x = value
+		return nil
+	}
+
+	var number int32
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid UserRole: %s", data)
+	}
+	*x = UserRole(number)
+	return nil
+}
+
+// User entity in orders namespace (different from com.example.users.User)
+// This represents a minimal user info stored with the order
+type User struct {
+	Id string `json:"id"`
+	DisplayName string `json:"displayName"`
+}
+
+// Validate checks User against the constraints declared in the schema.
+func (m *User) Validate() error {
+	var errs []error
+	if m.Id == "" {
+		errs = append(errs, errors.New("id: is required"))
+	}
+	if m.DisplayName == "" {
+		errs = append(errs, errors.New("displayName: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+// Order entity that references User from another namespace
+type Order struct {
+	Id string `json:"id"`
+	OrderNumber string `json:"orderNumber"`
+	Customer User `json:"customer"`
+	ProcessedBy User `json:"processedBy"`
+	Status OrderStatus `json:"status"`
+	TotalAmount float64 `json:"totalAmount"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Validate checks Order against the constraints declared in the schema.
+func (m *Order) Validate() error {
+	var errs []error
+	if m.Id == "" {
+		errs = append(errs, errors.New("id: is required"))
+	}
+	if m.OrderNumber == "" {
+		errs = append(errs, errors.New("orderNumber: is required"))
+	}
+	if err := m.Customer.Validate(); err != nil {
+		errs = append(errs, validationErrors("customer", err)...)
+	}
+	if err := m.ProcessedBy.Validate(); err != nil {
+		errs = append(errs, validationErrors("processedBy", err)...)
+	}
+	if m.CreatedAt.IsZero() {
+		errs = append(errs, errors.New("createdAt: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type CreateOrderRequest struct {
+	UserId string `json:"userId"`
+	Items []string `json:"items"`
+}
+
+// Validate checks CreateOrderRequest against the constraints declared in the schema.
+func (m *CreateOrderRequest) Validate() error {
+	var errs []error
+	if m.UserId == "" {
+		errs = append(errs, errors.New("userId: is required"))
+	}
+	if m.Items == nil {
+		errs = append(errs, errors.New("items: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type CreateOrderResponse struct {
+	Order Order `json:"order"`
+	Success bool `json:"success"`
+}
+
+// Validate checks CreateOrderResponse against the constraints declared in the schema.
+func (m *CreateOrderResponse) Validate() error {
+	var errs []error
+	if err := m.Order.Validate(); err != nil {
+		errs = append(errs, validationErrors("order", err)...)
+	}
+	return errors.Join(errs...)
+}
+
+type GetOrderRequest struct {
+	OrderId string `json:"orderId"`
+}
+
+// Validate checks GetOrderRequest against the constraints declared in the schema.
+func (m *GetOrderRequest) Validate() error {
+	var errs []error
+	if m.OrderId == "" {
+		errs = append(errs, errors.New("orderId: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type GetOrderResponse struct {
+	Order Order `json:"order"`
+}
+
+// Validate checks GetOrderResponse against the constraints declared in the schema.
+func (m *GetOrderResponse) Validate() error {
+	var errs []error
+	if err := m.Order.Validate(); err != nil {
+		errs = append(errs, validationErrors("order", err)...)
+	}
+	return errors.Join(errs...)
+}
+
+// User entity for the users service
+type User struct {
+	Id string `json:"id"`
+	Username string `json:"username"`
+	Email string `json:"email"`
+	Status UserStatus `json:"status"`
+	Role UserRole `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Validate checks User against the constraints declared in the schema.
+func (m *User) Validate() error {
+	var errs []error
+	if m.Id == "" {
+		errs = append(errs, errors.New("id: is required"))
+	}
+	if m.Username == "" {
+		errs = append(errs, errors.New("username: is required"))
+	}
+	if m.Email == "" {
+		errs = append(errs, errors.New("email: is required"))
+	}
+	if m.CreatedAt.IsZero() {
+		errs = append(errs, errors.New("createdAt: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+// Order management service
+type OrderService interface {
+	// Create a new order
+	CreateOrder(ctx context.Context, input *CreateOrderRequest) (*CreateOrderResponse, error)
+	// Get an order by ID
+	GetOrder(ctx context.Context, input *GetOrderRequest) (*GetOrderResponse, error)
+}
+
+// validationErrors prefixes every error joined in err with the path of the invalid field.
+func validationErrors(path string, err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, validationErrors(path, e)...)
+		}
+		return errs
+	}
+	return []error{fmt.Errorf("%s: %w", path, err)}
+}
+
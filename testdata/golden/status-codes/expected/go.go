@@ -0,0 +1,136 @@
+// Code generated by TypeMUX. DO NOT EDIT.
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Example demonstrating @success and @errors annotations
+type User struct {
+	Id string `json:"id"`
+	Name string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Validate checks User against the constraints declared in the schema.
+func (m *User) Validate() error {
+	var errs []error
+	if m.Id == "" {
+		errs = append(errs, errors.New("id: is required"))
+	}
+	if m.Name == "" {
+		errs = append(errs, errors.New("name: is required"))
+	}
+	if m.Email == "" {
+		errs = append(errs, errors.New("email: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type CreateUserRequest struct {
+	Name string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Validate checks CreateUserRequest against the constraints declared in the schema.
+func (m *CreateUserRequest) Validate() error {
+	var errs []error
+	if m.Name == "" {
+		errs = append(errs, errors.New("name: is required"))
+	}
+	if m.Email == "" {
+		errs = append(errs, errors.New("email: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type CreateUserResponse struct {
+	User User `json:"user"`
+}
+
+// Validate checks CreateUserResponse against the constraints declared in the schema.
+func (m *CreateUserResponse) Validate() error {
+	var errs []error
+	if err := m.User.Validate(); err != nil {
+		errs = append(errs, validationErrors("user", err)...)
+	}
+	return errors.Join(errs...)
+}
+
+type GetUserRequest struct {
+	UserId string `json:"userId"`
+}
+
+// Validate checks GetUserRequest against the constraints declared in the schema.
+func (m *GetUserRequest) Validate() error {
+	var errs []error
+	if m.UserId == "" {
+		errs = append(errs, errors.New("userId: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type GetUserResponse struct {
+	User User `json:"user"`
+}
+
+// Validate checks GetUserResponse against the constraints declared in the schema.
+func (m *GetUserResponse) Validate() error {
+	var errs []error
+	if err := m.User.Validate(); err != nil {
+		errs = append(errs, validationErrors("user", err)...)
+	}
+	return errors.Join(errs...)
+}
+
+type UpdateUserRequest struct {
+	UserId string `json:"userId"`
+	Name string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Validate checks UpdateUserRequest against the constraints declared in the schema.
+func (m *UpdateUserRequest) Validate() error {
+	var errs []error
+	if m.UserId == "" {
+		errs = append(errs, errors.New("userId: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type UpdateUserResponse struct {
+	User User `json:"user"`
+}
+
+// Validate checks UpdateUserResponse against the constraints declared in the schema.
+func (m *UpdateUserResponse) Validate() error {
+	var errs []error
+	if err := m.User.Validate(); err != nil {
+		errs = append(errs, validationErrors("user", err)...)
+	}
+	return errors.Join(errs...)
+}
+
+type UserService interface {
+	// Create a new user - returns 201 Created
+	CreateUser(ctx context.Context, input *CreateUserRequest) (*CreateUserResponse, error)
+	// Get a user by ID - standard 200 response
+	GetUser(ctx context.Context, input *GetUserRequest) (*GetUserResponse, error)
+	// Update a user - can return 200 or 204
+	UpdateUser(ctx context.Context, input *UpdateUserRequest) (*UpdateUserResponse, error)
+}
+
+// validationErrors prefixes every error joined in err with the path of the invalid field.
+func validationErrors(path string, err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, validationErrors(path, e)...)
+		}
+		return errs
+	}
+	return []error{fmt.Errorf("%s: %w", path, err)}
+}
+
@@ -0,0 +1,143 @@
+// Code generated by TypeMUX. DO NOT EDIT.
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Example demonstrating union/oneOf types
+type TextMessage struct {
+	Content string `json:"content"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Validate checks TextMessage against the constraints declared in the schema.
+func (m *TextMessage) Validate() error {
+	var errs []error
+	if m.Content == "" {
+		errs = append(errs, errors.New("content: is required"))
+	}
+	if m.Timestamp.IsZero() {
+		errs = append(errs, errors.New("timestamp: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type ImageMessage struct {
+	ImageUrl string `json:"imageUrl"`
+	Thumbnail string `json:"thumbnail"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Validate checks ImageMessage against the constraints declared in the schema.
+func (m *ImageMessage) Validate() error {
+	var errs []error
+	if m.ImageUrl == "" {
+		errs = append(errs, errors.New("imageUrl: is required"))
+	}
+	if m.Timestamp.IsZero() {
+		errs = append(errs, errors.New("timestamp: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type VideoMessage struct {
+	VideoUrl string `json:"videoUrl"`
+	Duration int32 `json:"duration"`
+	Thumbnail string `json:"thumbnail"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Validate checks VideoMessage against the constraints declared in the schema.
+func (m *VideoMessage) Validate() error {
+	var errs []error
+	if m.VideoUrl == "" {
+		errs = append(errs, errors.New("videoUrl: is required"))
+	}
+	if m.Timestamp.IsZero() {
+		errs = append(errs, errors.New("timestamp: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type SendMessageRequest struct {
+	ChatId string `json:"chatId"`
+	Message Message `json:"message"`
+}
+
+// Validate checks SendMessageRequest against the constraints declared in the schema.
+func (m *SendMessageRequest) Validate() error {
+	var errs []error
+	if m.ChatId == "" {
+		errs = append(errs, errors.New("chatId: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type SendMessageResponse struct {
+	MessageId string `json:"messageId"`
+	Success bool `json:"success"`
+}
+
+// Validate checks SendMessageResponse against the constraints declared in the schema.
+func (m *SendMessageResponse) Validate() error {
+	var errs []error
+	if m.MessageId == "" {
+		errs = append(errs, errors.New("messageId: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type GetMessageRequest struct {
+	MessageId string `json:"messageId"`
+}
+
+// Validate checks GetMessageRequest against the constraints declared in the schema.
+func (m *GetMessageRequest) Validate() error {
+	var errs []error
+	if m.MessageId == "" {
+		errs = append(errs, errors.New("messageId: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type GetMessageResponse struct {
+	Message Message `json:"message"`
+}
+
+// A message can be text, image, or video
+type Message interface {
+	isMessage()
+}
+
+// MessageTextMessage holds the TextMessage option of Message.
+type MessageTextMessage struct {
+	Value TextMessage `json:"value"`
+}
+
+func (MessageTextMessage) isMessage() {}
+
+// MessageImageMessage holds the ImageMessage option of Message.
+type MessageImageMessage struct {
+	Value ImageMessage `json:"value"`
+}
+
+func (MessageImageMessage) isMessage() {}
+
+// MessageVideoMessage holds the VideoMessage option of Message.
+type MessageVideoMessage struct {
+	Value VideoMessage `json:"value"`
+}
+
+func (MessageVideoMessage) isMessage() {}
+
+
+type MessageService interface {
+	// Send a message (text, image, or video)
+	SendMessage(ctx context.Context, input *SendMessageRequest) (*SendMessageResponse, error)
+	// Get a message by ID
+	GetMessage(ctx context.Context, input *GetMessageRequest) (*GetMessageResponse, error)
+}
+
@@ -0,0 +1,190 @@
+// Code generated by TypeMUX. DO NOT EDIT.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Status int32
+
+const (
+	// StatusUnspecified is the zero value, used for unset and unknown values.
+	StatusUnspecified Status = 0
+	StatusACTIVE Status = 1
+	StatusINACTIVE Status = 2
+	StatusDELETED Status = 3
+)
+
+// statusNames maps Status values to their names.
+var statusNames = map[Status]string{
+	StatusUnspecified: "STATUS_UNSPECIFIED",
+	StatusACTIVE: "ACTIVE",
+	StatusINACTIVE: "INACTIVE",
+	StatusDELETED: "DELETED",
+}
+
+// statusValues maps names to Status values.
+var statusValues = map[string]Status{
+	"STATUS_UNSPECIFIED": StatusUnspecified,
+	"ACTIVE": StatusACTIVE,
+	"INACTIVE": StatusINACTIVE,
+	"DELETED": StatusDELETED,
+}
+
+// String returns the name of the value, or Status(n) for unknown values.
+func (x Status) String() string {
+	if name, ok := statusNames[x]; ok {
+		return name
+	}
+	return fmt.Sprintf("Status(%d)", int32(x))
+}
+
+// ParseStatus returns the Status with the given name.
+func ParseStatus(name string) (Status, error) {
+	if value, ok := statusValues[name]; ok {
+		return value, nil
+	}
+	return StatusUnspecified, fmt.Errorf("unknown Status %q", name)
+}
+
+// MarshalJSON encodes the value as its name, or as a number for unknown values.
+func (x Status) MarshalJSON() ([]byte, error) {
+	if name, ok := statusNames[x]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int32(x))
+}
+
+// UnmarshalJSON decodes a name or a number. Unknown names decode to StatusUnspecified.
+func (x *Status) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		value, ok := statusValues[name]
+		if !ok {
+			value = StatusUnspecified
+		}
+		*x = value
+		return nil
+	}
+
+	var number int32
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid Status: %s", data)
+	}
+	*x = Status(number)
+	return nil
+}
+
+type User struct {
+	Id string `json:"id"`
+	Username string `json:"username"`
+	Email string `json:"email"`
+	Status Status `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Validate checks User against the constraints declared in the schema.
+func (m *User) Validate() error {
+	var errs []error
+	if m.Username == "" {
+		errs = append(errs, errors.New("username: is required"))
+	}
+	if m.Email == "" {
+		errs = append(errs, errors.New("email: is required"))
+	}
+	if m.CreatedAt.IsZero() {
+		errs = append(errs, errors.New("createdAt: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type Product struct {
+	Id string `json:"id"`
+	Name string `json:"name"`
+	Price float64 `json:"price"`
+}
+
+// Validate checks Product against the constraints declared in the schema.
+func (m *Product) Validate() error {
+	var errs []error
+	if m.Name == "" {
+		errs = append(errs, errors.New("name: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type GetUserRequest struct {
+	UserId string `json:"userId"`
+}
+
+// Validate checks GetUserRequest against the constraints declared in the schema.
+func (m *GetUserRequest) Validate() error {
+	var errs []error
+	if m.UserId == "" {
+		errs = append(errs, errors.New("userId: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type GetUserResponse struct {
+	User User `json:"user"`
+	Success bool `json:"success"`
+}
+
+// Validate checks GetUserResponse against the constraints declared in the schema.
+func (m *GetUserResponse) Validate() error {
+	var errs []error
+	if err := m.User.Validate(); err != nil {
+		errs = append(errs, validationErrors("user", err)...)
+	}
+	return errors.Join(errs...)
+}
+
+type CreateProductRequest struct {
+	Name string `json:"name"`
+	Price float64 `json:"price"`
+}
+
+// Validate checks CreateProductRequest against the constraints declared in the schema.
+func (m *CreateProductRequest) Validate() error {
+	var errs []error
+	if m.Name == "" {
+		errs = append(errs, errors.New("name: is required"))
+	}
+	return errors.Join(errs...)
+}
+
+type CreateProductResponse struct {
+	Product Product `json:"product"`
+}
+
+// Validate checks CreateProductResponse against the constraints declared in the schema.
+func (m *CreateProductResponse) Validate() error {
+	var errs []error
+	if err := m.Product.Validate(); err != nil {
+		errs = append(errs, validationErrors("product", err)...)
+	}
+	return errors.Join(errs...)
+}
+
+type UserService interface {
+	GetUser(ctx context.Context, input *GetUserRequest) (*GetUserResponse, error)
+	CreateProduct(ctx context.Context, input *CreateProductRequest) (*CreateProductResponse, error)
+}
+
+// validationErrors prefixes every error joined in err with the path of the invalid field.
+func validationErrors(path string, err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, validationErrors(path, e)...)
+		}
+		return errs
+	}
+	return []error{fmt.Errorf("%s: %w", path, err)}
+}
+
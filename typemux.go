@@ -23,6 +23,14 @@
 //
 //	schema, err := typemux.ParseWithAnnotations(idlContent, yamlAnnotation1, yamlAnnotation2)
 //
+// From a file, with its imports:
+//
+//	schema, err := typemux.ParseFile("api/schema.typemux")
+//	if err := typemux.Validate(schema); err != nil {
+//	    log.Fatal(err)
+//	}
+//	proto, err := typemux.Generate("protobuf", schema)
+//
 // Using configuration:
 //
 //	config := typemux.NewConfigBuilder().
@@ -36,11 +44,17 @@
 package typemux
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/rasmartins/typemux/internal/annotations"
 	"github.com/rasmartins/typemux/internal/ast"
+	"github.com/rasmartins/typemux/internal/generator"
+	"github.com/rasmartins/typemux/internal/graph"
 	"github.com/rasmartins/typemux/internal/lexer"
+	"github.com/rasmartins/typemux/internal/loader"
 	"github.com/rasmartins/typemux/internal/parser"
 )
 
@@ -48,6 +62,36 @@ import (
 // It contains all types, enums, unions, and services defined in the schema.
 type Schema = ast.Schema
 
+// AST types of a schema, for programs that inspect or modify schemas.
+type (
+	// Type is a type declaration with its fields.
+	Type = ast.Type
+	// Field is a field of a type.
+	Field = ast.Field
+	// FieldType is the type of a field or field argument.
+	FieldType = ast.FieldType
+	// FieldArgument is an argument of a field, such as a GraphQL field argument.
+	FieldArgument = ast.FieldArgument
+	// Enum is an enum declaration.
+	Enum = ast.Enum
+	// EnumValue is a value of an enum.
+	EnumValue = ast.EnumValue
+	// Union is a union declaration.
+	Union = ast.Union
+	// Service is a service declaration with its methods.
+	Service = ast.Service
+	// Method is a method of a service.
+	Method = ast.Method
+	// Documentation is the documentation comment of a declaration.
+	Documentation = ast.Documentation
+	// FormatAnnotations holds format-specific annotations of a declaration.
+	FormatAnnotations = ast.FormatAnnotations
+	// DeprecationInfo describes the deprecation of a field.
+	DeprecationInfo = ast.DeprecationInfo
+	// ValidationRules holds the validation rules of a field.
+	ValidationRules = ast.ValidationRules
+)
+
 // ParseSchema parses a TypeMUX IDL schema from a string.
 // It returns the parsed schema or an error if parsing fails.
 //
@@ -69,6 +113,21 @@ func ParseSchema(content string) (*Schema, error) {
 	if len(p.Errors()) > 0 {
 		return nil, fmt.Errorf("parse errors:\n%s", p.PrintErrors())
 	}
+	if err := schema.ResolveExtends(); err != nil {
+		return nil, err
+	}
+	if err := schema.ResolveViews(); err != nil {
+		return nil, err
+	}
+	if err := schema.ResolveResources(); err != nil {
+		return nil, err
+	}
+	if err := schema.ResolveBatches(); err != nil {
+		return nil, err
+	}
+	if err := schema.ResolveLongRunning(); err != nil {
+		return nil, err
+	}
 
 	return schema, nil
 }
@@ -87,29 +146,100 @@ func ParseWithAnnotations(schemaContent string, yamlAnnotations ...string) (*Sch
 		return nil, err
 	}
 
-	// If no annotations provided, return schema as-is
+	if err := mergeAnnotations(schema, yamlAnnotations); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// mergeAnnotations validates YAML annotations against a schema and merges them into it
+func mergeAnnotations(schema *Schema, yamlAnnotations []string) error {
+	// If no annotations provided, keep the schema as-is
 	if len(yamlAnnotations) == 0 {
-		return schema, nil
+		return nil
 	}
 
 	// Merge YAML annotations
 	mergedAnnotations, err := annotations.MergeYAMLAnnotationsFromContent(yamlAnnotations)
 	if err != nil {
-		return nil, fmt.Errorf("failed to merge annotations: %w", err)
+		return fmt.Errorf("failed to merge annotations: %w", err)
 	}
 
 	// Validate annotations against schema
 	validator := annotations.NewValidator(schema)
 	validationErrors := validator.Validate(mergedAnnotations)
 	if len(validationErrors) > 0 {
-		return nil, fmt.Errorf("annotation validation failed:\n%s", validator.FormatErrors())
+		return fmt.Errorf("annotation validation failed:\n%s", validator.FormatErrors())
 	}
 
 	// Merge annotations into schema
 	merger := annotations.NewMerger(mergedAnnotations)
 	merger.Merge(schema)
+	if err := schema.ResolveExtends(); err != nil {
+		return err
+	}
+	if err := schema.ResolveViews(); err != nil {
+		return err
+	}
+	if err := schema.ResolveResources(); err != nil {
+		return err
+	}
+	if err := schema.ResolveBatches(); err != nil {
+		return err
+	}
+	return schema.ResolveLongRunning()
+}
 
-	return schema, nil
+// ParseFile parses a TypeMUX schema file together with the files it imports,
+// directly or indirectly, including standard library imports. The declarations
+// of imported files are merged into the returned schema.
+//
+// Example:
+//
+//	schema, err := typemux.ParseFile("api/orders.typemux")
+func ParseFile(path string) (*Schema, error) {
+	return (&loader.Loader{}).Load(path)
+}
+
+// Validate checks a schema for semantic errors that parsing alone does not catch,
+// such as references to unknown types and names or field numbers used twice.
+// It is useful for schemas built or modified in code before generating from them.
+//
+// Example:
+//
+//	schema.Types = append(schema.Types, &typemux.Type{Name: "Audit", Fields: fields})
+//	if err := typemux.Validate(schema); err != nil {
+//	    log.Fatal(err)
+//	}
+func Validate(schema *Schema) error {
+	if errs := schema.Validate(); len(errs) > 0 {
+		return fmt.Errorf("validation errors:\n%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// Generate generates a schema in one of the built-in formats: graphql, protobuf,
+// openapi, or go. Use a GeneratorFactory for custom generators.
+//
+// Example:
+//
+//	openapi, err := typemux.Generate("openapi", schema)
+func Generate(format string, schema *Schema) (string, error) {
+	return NewGeneratorFactory().Generate(format, schema)
+}
+
+// DescriptorSet compiles the Protobuf output of a schema to a serialized
+// google.protobuf.FileDescriptorSet, including the well-known files it imports,
+// without protoc. gRPC server reflection, the Buf Schema Registry, and dynamic
+// clients read descriptor sets.
+//
+// Example:
+//
+//	data, err := typemux.DescriptorSet(schema)
+//	var set descriptorpb.FileDescriptorSet
+//	err = proto.Unmarshal(data, &set)
+func DescriptorSet(schema *Schema) ([]byte, error) {
+	return generator.DescriptorSet(context.Background(), schema, generator.Options{})
 }
 
 // ParseOptions provides options for parsing schemas.
@@ -120,11 +250,22 @@ type ParseOptions struct {
 	// Annotations are optional YAML annotation strings
 	Annotations []string
 
-	// BaseDir is the base directory for resolving relative imports
-	// Currently not used but reserved for future import resolution
+	// BaseDir is the directory that imports of the schema are resolved against.
+	// Imports are not loaded when it is empty.
 	BaseDir string
+
+	// Limits bounds the size and complexity of the schema and its imports, for
+	// schemas from untrusted sources. The zero value is unlimited.
+	Limits Limits
 }
 
+// Limits bounds the size, import depth, number of declarations and fields, and
+// annotation length of schemas. A zero limit is unlimited.
+type Limits = loader.Limits
+
+// ErrLimitExceeded is wrapped by the errors of schemas that exceed a limit.
+var ErrLimitExceeded = loader.ErrLimitExceeded
+
 // Parse parses a TypeMUX schema with the given options.
 //
 // Example:
@@ -134,7 +275,59 @@ type ParseOptions struct {
 //	    Annotations: []string{yaml1, yaml2},
 //	})
 func Parse(opts ParseOptions) (*Schema, error) {
-	return ParseWithAnnotations(opts.Schema, opts.Annotations...)
+	if opts.BaseDir == "" {
+		if err := opts.Limits.CheckSize("schema", int64(len(opts.Schema))); err != nil {
+			return nil, err
+		}
+		schema, err := ParseSchema(opts.Schema)
+		if err != nil {
+			return nil, err
+		}
+		if err := opts.Limits.CheckSchema("schema", schema); err != nil {
+			return nil, err
+		}
+		if err := mergeAnnotations(schema, opts.Annotations); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	}
+
+	schema, err := (&loader.Loader{Limits: opts.Limits}).LoadContent(filepath.Join(opts.BaseDir, "schema.typemux"), []byte(opts.Schema))
+	if err != nil {
+		return nil, err
+	}
+	if err := mergeAnnotations(schema, opts.Annotations); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// TrimSchema returns a copy of the schema pruned to the given services and root
+// types, plus every type, enum, and union they reference directly or indirectly.
+// Names may be qualified (namespace.Name) or unqualified when unique.
+//
+// Example:
+//
+//	trimmed, err := typemux.TrimSchema(schema, []string{"UserService"}, nil)
+func TrimSchema(schema *Schema, services, rootTypes []string) (*Schema, error) {
+	return graph.Trim(schema, services, rootTypes)
+}
+
+// MarshalSchemaJSON encodes a schema in the versioned JSON AST format written by
+// typemux compile, for use by external tools and generators.
+func MarshalSchemaJSON(schema *Schema) ([]byte, error) {
+	return ast.MarshalSchemaJSON(schema)
+}
+
+// ParseSchemaJSON decodes a schema in the JSON AST format, as written by
+// MarshalSchemaJSON or typemux compile.
+//
+// Example:
+//
+//	data, _ := os.ReadFile("schema.json")
+//	schema, err := typemux.ParseSchemaJSON(data)
+func ParseSchemaJSON(data []byte) (*Schema, error) {
+	return ast.UnmarshalSchemaJSON(data)
 }
 
 // Version returns the TypeMUX version supported by this library.
@@ -1,6 +1,9 @@
 package typemux_test
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -213,6 +216,252 @@ type User { id: string @required }`
 	}
 }
 
+func TestGenerateWithConfig_OnlyServices(t *testing.T) {
+	idl := `namespace myapi
+type User { id: string @required }
+type GetUserRequest { id: string @required }
+type Invoice { total: int32 }
+type GetInvoiceRequest { id: string @required }
+service UserService {
+  rpc GetUser(GetUserRequest) returns (User)
+}
+service BillingService {
+  rpc GetInvoice(GetInvoiceRequest) returns (Invoice)
+}`
+
+	config, err := typemux.NewConfigBuilder().
+		WithSchema(idl).
+		WithFormats("protobuf").
+		WithOnlyServices("UserService").
+		Build()
+	if err != nil {
+		t.Fatalf("Build config failed: %v", err)
+	}
+
+	outputs, err := typemux.NewGeneratorFactory().GenerateWithConfig(config)
+	if err != nil {
+		t.Fatalf("GenerateWithConfig failed: %v", err)
+	}
+
+	proto := outputs["protobuf"]
+	if !strings.Contains(proto, "service UserService") || !strings.Contains(proto, "message User ") {
+		t.Error("Expected UserService and its types in output")
+	}
+	if strings.Contains(proto, "BillingService") || strings.Contains(proto, "Invoice") {
+		t.Error("Expected BillingService and its types to be pruned")
+	}
+}
+
+func TestTrimSchema_UnknownService(t *testing.T) {
+	schema, err := typemux.ParseSchema(`namespace myapi
+type User { id: string }`)
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+
+	if _, err := typemux.TrimSchema(schema, []string{"MissingService"}, nil); err == nil {
+		t.Error("Expected error for unknown service")
+	}
+}
+
+func TestSchemaJSONRoundTrip(t *testing.T) {
+	schema, err := typemux.ParseSchema(`namespace myapi
+
+/// A registered user
+type User {
+  id: string @required
+  email: string = 5 @validate(format="email")
+  tags: []string
+  scores: map<string, int32?>
+  status: Status
+}
+
+enum Status {
+  ACTIVE = 1
+  INACTIVE = 2
+}
+
+union Result { User }
+
+service UserService {
+  rpc GetUser(User) returns (Result) @http.method(GET) @http.path("/users/{id}")
+}`)
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+
+	data, err := typemux.MarshalSchemaJSON(schema)
+	if err != nil {
+		t.Fatalf("MarshalSchemaJSON failed: %v", err)
+	}
+	decoded, err := typemux.ParseSchemaJSON(data)
+	if err != nil {
+		t.Fatalf("ParseSchemaJSON failed: %v", err)
+	}
+
+	factory := typemux.NewGeneratorFactory()
+	for _, format := range []string{"graphql", "protobuf", "openapi", "go"} {
+		want, err := factory.Generate(format, schema)
+		if err != nil {
+			t.Fatalf("Generate %s failed: %v", format, err)
+		}
+		got, err := factory.Generate(format, decoded)
+		if err != nil {
+			t.Fatalf("Generate %s from JSON failed: %v", format, err)
+		}
+		if got != want {
+			t.Errorf("%s output differs after a JSON round trip", format)
+		}
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"api.typemux": `namespace api
+import "common.typemux"
+
+type User {
+  id: string @required
+  audit: common.Audit
+}`,
+		"common.typemux": `namespace common
+
+type Audit {
+  createdAt: timestamp
+}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	schema, err := typemux.ParseFile(filepath.Join(dir, "api.typemux"))
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if len(schema.Types) != 2 {
+		t.Fatalf("Expected 2 types, got %d", len(schema.Types))
+	}
+	if err := typemux.Validate(schema); err != nil {
+		t.Errorf("Expected a valid schema, got %v", err)
+	}
+
+	// Imports of inline content resolve against BaseDir
+	inline, err := typemux.Parse(typemux.ParseOptions{
+		Schema:  "import \"common.typemux\"\ntype Event { audit: common.Audit }",
+		BaseDir: dir,
+	})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(inline.Types) != 2 {
+		t.Errorf("Expected the imported type, got %d types", len(inline.Types))
+	}
+}
+
+func TestParse_Limits(t *testing.T) {
+	idl := `namespace api
+
+type User {
+  id: string @required
+  name: string
+}`
+
+	if _, err := typemux.Parse(typemux.ParseOptions{Schema: idl, Limits: typemux.Limits{MaxFields: 2, MaxTypes: 1}}); err != nil {
+		t.Fatalf("Expected the schema to be within the limits, got %v", err)
+	}
+	for _, limits := range []typemux.Limits{{MaxFileSize: 10}, {MaxFields: 1}} {
+		_, err := typemux.Parse(typemux.ParseOptions{Schema: idl, Limits: limits})
+		if !errors.Is(err, typemux.ErrLimitExceeded) {
+			t.Errorf("Expected %+v to be exceeded, got %v", limits, err)
+		}
+	}
+
+	// Limits also apply to imports resolved against BaseDir
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "common.typemux"), []byte("namespace common\ntype Audit { at: timestamp }\ntype Actor { id: string }"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := typemux.Parse(typemux.ParseOptions{
+		Schema:  "import \"common.typemux\"\n" + idl,
+		BaseDir: dir,
+		Limits:  typemux.Limits{MaxTypes: 2},
+	})
+	if !errors.Is(err, typemux.ErrLimitExceeded) {
+		t.Errorf("Expected the imported types to exceed the limit, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := typemux.ParseSchema(`
+type User {
+  id: string @required
+}
+`)
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+
+	// Schemas modified in code can refer to types that do not exist
+	schema.Types = append(schema.Types, &typemux.Type{
+		Name: "Team",
+		Fields: []*typemux.Field{
+			{Name: "owner", Type: &typemux.FieldType{Name: "Member"}},
+		},
+	})
+	err = typemux.Validate(schema)
+	if err == nil || !strings.Contains(err.Error(), "field Team.owner refers to unknown type Member") {
+		t.Errorf("Expected an unknown type error, got %v", err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	schema, err := typemux.ParseSchema(`
+type User {
+  id: string @required
+}
+`)
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+
+	output, err := typemux.Generate("protobuf", schema)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(output, "message User") {
+		t.Errorf("Expected a User message, got:\n%s", output)
+	}
+
+	if _, err := typemux.Generate("thrift", schema); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestDescriptorSet(t *testing.T) {
+	schema, err := typemux.ParseSchema(`
+type User {
+  id: string @required
+  createdAt: timestamp
+}
+`)
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+
+	data, err := typemux.DescriptorSet(schema)
+	if err != nil {
+		t.Fatalf("DescriptorSet failed: %v", err)
+	}
+	for _, expected := range []string{"schema.proto", "google/protobuf/timestamp.proto", "User", "createdAt"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected the descriptor set to contain %q", expected)
+		}
+	}
+}
+
 func TestImporterFactory(t *testing.T) {
 	factory := typemux.NewImporterFactory()
 
@@ -335,6 +584,38 @@ func TestGetAnnotation(t *testing.T) {
 	}
 }
 
+func TestRegisterAnnotationNamespace(t *testing.T) {
+	err := typemux.RegisterAnnotationNamespace("mycorp", []*typemux.AnnotationMetadata{{
+		Name:       "@mycorp.audit",
+		Scope:      []string{"type"},
+		Parameters: []typemux.AnnotationParameter{{Name: "level", Type: "string", Required: true}},
+	}})
+	if err != nil {
+		t.Fatalf("RegisterAnnotationNamespace failed: %v", err)
+	}
+	defer typemux.UnregisterAnnotationNamespace("mycorp")
+
+	if _, found := typemux.GetAnnotation("@mycorp.audit"); !found {
+		t.Error("Expected @mycorp.audit annotation to be found")
+	}
+
+	schema, err := typemux.ParseSchema("@mycorp.audit(level=\"high\")\ntype User {\n\tid: string\n}\n")
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+	if args, ok := schema.Types[0].Annotations.Extension("mycorp.audit"); !ok || args["level"] != "high" {
+		t.Errorf("Expected level=high, got %v", args)
+	}
+
+	if _, err := typemux.ParseSchema("@mycorp.audit\ntype User {\n\tid: string\n}\n"); err == nil {
+		t.Error("Expected an error for a missing required parameter")
+	}
+
+	if err := typemux.RegisterAnnotationNamespace("graphql", nil); err == nil {
+		t.Error("Expected an error for a builtin namespace")
+	}
+}
+
 // customGenerator is a test implementation of the Generator interface
 type customGenerator struct{}
 
@@ -0,0 +1,231 @@
+// Package typemuxtest provides snapshot tests of generated code: a schema is
+// generated in each format and compared with expected files kept next to it,
+// which can be rewritten with the current output after a deliberate change.
+//
+// A test directory holds one case per subdirectory. Each case has a
+// schema.typemux, optional YAML annotation files, and an expected directory
+// with a file per format, named after the format and its extension:
+//
+//	testdata/golden/
+//	    users/
+//	        schema.typemux
+//	        annotations.yaml
+//	        expected/
+//	            graphql.graphql
+//	            protobuf.proto
+//	            openapi.yaml
+//	            go.go
+//
+// Usage:
+//
+//	var update = flag.Bool("update", false, "Rewrite the expected files")
+//
+//	func TestSchemas(t *testing.T) {
+//	    typemuxtest.Run(t, "testdata/golden", typemuxtest.Options{Update: *update})
+//	}
+//
+// Running go test -update, or setting TYPEMUX_UPDATE_SNAPSHOTS=1, writes the
+// expected files of new cases and rewrites those that changed.
+package typemuxtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux"
+	"github.com/rasmartins/typemux/internal/textdiff"
+)
+
+// SchemaFile is the name of the schema of a case.
+const SchemaFile = "schema.typemux"
+
+// ExpectedDir is the name of the directory of the expected outputs of a case.
+const ExpectedDir = "expected"
+
+// UpdateEnv is the environment variable that rewrites the expected files when
+// set to a non-empty value, for test binaries without an -update flag.
+const UpdateEnv = "TYPEMUX_UPDATE_SNAPSHOTS"
+
+// Options configures snapshot tests.
+type Options struct {
+	// Formats to generate (default: every format of the factory)
+	Formats []string
+
+	// Factory generates the formats, for custom generators
+	// (default: typemux.NewGeneratorFactory())
+	Factory *typemux.GeneratorFactory
+
+	// Update writes the generated outputs to the expected files instead of
+	// comparing them, and removes expected files of formats no longer generated
+	Update bool
+}
+
+// Run runs a subtest named after each case directory of dir that holds a
+// schema.typemux, in name order. It fails when dir has no case.
+func Run(t *testing.T, dir string, opts Options) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("reading snapshot cases: %v", err)
+	}
+	found := false
+	for _, entry := range entries {
+		caseDir := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(caseDir, SchemaFile)); err != nil {
+			continue
+		}
+		found = true
+		t.Run(entry.Name(), func(t *testing.T) {
+			Snapshot(t, caseDir, opts)
+		})
+	}
+	if !found {
+		t.Fatalf("no snapshot cases in %s: expected directories with a %s", dir, SchemaFile)
+	}
+}
+
+// Snapshot generates the schema of a case directory in each format and
+// compares the outputs with its expected files, reporting a unified diff per
+// file that differs.
+func Snapshot(t testing.TB, caseDir string, opts Options) {
+	t.Helper()
+
+	outputs, err := generate(caseDir, opts)
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+	expectedDir := filepath.Join(caseDir, ExpectedDir)
+	update := opts.Update || os.Getenv(UpdateEnv) != ""
+
+	if update {
+		if err := os.MkdirAll(expectedDir, 0o755); err != nil {
+			t.Errorf("creating expected directory: %v", err)
+			return
+		}
+	}
+
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(expectedDir, name)
+		got := outputs[name]
+		want, err := os.ReadFile(path) //nolint:gosec // Expected files of the test case
+		if update {
+			if err != nil || string(want) != string(got) {
+				if err := os.WriteFile(path, got, 0o600); err != nil {
+					t.Errorf("writing %s: %v", path, err)
+				}
+			}
+			continue
+		}
+		if os.IsNotExist(err) {
+			t.Errorf("%s is missing; run the test with -update or %s=1 to create it", path, UpdateEnv)
+			continue
+		}
+		if err != nil {
+			t.Errorf("reading %s: %v", path, err)
+			continue
+		}
+		if string(want) != string(got) {
+			t.Errorf("%s does not match the generated output; run the test with -update or %s=1 to accept it:\n%s",
+				path, UpdateEnv, textdiff.Unified(path, name+" (generated)", want, got))
+		}
+	}
+
+	// Expected files that no format generates any more
+	entries, err := os.ReadDir(expectedDir)
+	if err != nil && !os.IsNotExist(err) {
+		t.Errorf("reading %s: %v", expectedDir, err)
+		return
+	}
+	for _, entry := range entries {
+		if _, ok := outputs[entry.Name()]; ok || entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(expectedDir, entry.Name())
+		if update {
+			if err := os.Remove(path); err != nil {
+				t.Errorf("removing %s: %v", path, err)
+			}
+			continue
+		}
+		t.Errorf("%s is not generated by any format; run the test with -update or %s=1 to remove it", path, UpdateEnv)
+	}
+}
+
+// generate parses the schema of a case with its annotations and returns the
+// output of each format by expected file name
+func generate(caseDir string, opts Options) (map[string][]byte, error) {
+	schemaPath := filepath.Join(caseDir, SchemaFile)
+	content, err := os.ReadFile(schemaPath) //nolint:gosec // Schema of the test case
+	if err != nil {
+		return nil, fmt.Errorf("reading schema: %w", err)
+	}
+
+	annotationFiles, err := filepath.Glob(filepath.Join(caseDir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	var annotations []string
+	for _, file := range annotationFiles {
+		data, err := os.ReadFile(file) //nolint:gosec // Annotations of the test case
+		if err != nil {
+			return nil, fmt.Errorf("reading annotations: %w", err)
+		}
+		annotations = append(annotations, string(data))
+	}
+
+	schema, err := typemux.Parse(typemux.ParseOptions{
+		Schema:      string(content),
+		Annotations: annotations,
+		BaseDir:     caseDir,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", schemaPath, err)
+	}
+	if err := typemux.Validate(schema); err != nil {
+		return nil, fmt.Errorf("%s: %w", schemaPath, err)
+	}
+
+	factory := opts.Factory
+	if factory == nil {
+		factory = typemux.NewGeneratorFactory()
+	}
+	formats := opts.Formats
+	if len(formats) == 0 {
+		formats = factory.GetFormats()
+	}
+
+	outputs := make(map[string][]byte, len(formats))
+	for _, format := range formats {
+		gen, err := factory.Get(format)
+		if err != nil {
+			return nil, err
+		}
+		output, err := gen.Generate(schema)
+		if err != nil {
+			return nil, fmt.Errorf("generating %s: %w", format, err)
+		}
+		outputs[FileName(gen)] = []byte(output)
+	}
+	return outputs, nil
+}
+
+// FileName returns the name of the expected file of a generator: its format
+// followed by its file extension, such as protobuf.proto.
+func FileName(gen typemux.Generator) string {
+	return gen.Format() + "." + strings.TrimPrefix(gen.FileExtension(), ".")
+}
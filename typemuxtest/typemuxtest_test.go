@@ -0,0 +1,166 @@
+package typemuxtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rasmartins/typemux"
+)
+
+// recorder is a testing.TB that records errors instead of failing the test
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// writeCase writes a case directory with a schema and optional expected files
+func writeCase(t *testing.T, schema string, expected map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, SchemaFile), []byte(schema), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if len(expected) > 0 {
+		if err := os.MkdirAll(filepath.Join(dir, ExpectedDir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range expected {
+		if err := os.WriteFile(filepath.Join(dir, ExpectedDir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const userSchema = `@typemux("1.0.0")
+namespace api
+
+type User {
+    id: string = 1 @required
+    name: string = 2
+}
+`
+
+func TestSnapshot_UpdateAndCompare(t *testing.T) {
+	dir := writeCase(t, userSchema, map[string]string{"csharp.cs": "stale"})
+
+	rec := &recorder{TB: t}
+	Snapshot(rec, dir, Options{Update: true})
+	if len(rec.errors) > 0 {
+		t.Fatalf("Unexpected errors while updating: %v", rec.errors)
+	}
+	for _, name := range []string{"graphql.graphql", "protobuf.proto", "openapi.yaml", "go.go"} {
+		if _, err := os.Stat(filepath.Join(dir, ExpectedDir, name)); err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ExpectedDir, "csharp.cs")); !os.IsNotExist(err) {
+		t.Error("Expected the stale csharp.cs to be removed")
+	}
+
+	rec = &recorder{TB: t}
+	Snapshot(rec, dir, Options{})
+	if len(rec.errors) > 0 {
+		t.Errorf("Expected the updated snapshots to match, got: %v", rec.errors)
+	}
+}
+
+func TestSnapshot_Mismatch(t *testing.T) {
+	dir := writeCase(t, userSchema, map[string]string{
+		"graphql.graphql": "type User {\n  id: String!\n}\n",
+		"notes.txt":       "stale",
+	})
+
+	rec := &recorder{TB: t}
+	Snapshot(rec, dir, Options{Formats: []string{"graphql", "protobuf"}})
+	if len(rec.errors) != 3 {
+		t.Fatalf("Expected 3 errors, got %d: %v", len(rec.errors), rec.errors)
+	}
+	if !strings.Contains(rec.errors[0], "graphql.graphql does not match") || !strings.Contains(rec.errors[0], "+  name: String") {
+		t.Errorf("Expected a diff of graphql.graphql, got:\n%s", rec.errors[0])
+	}
+	if !strings.Contains(rec.errors[1], "protobuf.proto is missing") {
+		t.Errorf("Expected protobuf.proto to be missing, got: %s", rec.errors[1])
+	}
+	if !strings.Contains(rec.errors[2], "notes.txt is not generated") {
+		t.Errorf("Expected notes.txt to be reported as stale, got: %s", rec.errors[2])
+	}
+}
+
+func TestSnapshot_Annotations(t *testing.T) {
+	dir := writeCase(t, userSchema, nil)
+	annotations := "types:\n  User:\n    fields:\n      name:\n        required: true\n"
+	if err := os.WriteFile(filepath.Join(dir, "annotations.yaml"), []byte(annotations), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	outputs, err := generate(dir, Options{Formats: []string{"graphql"}})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if graphql := string(outputs["graphql.graphql"]); !strings.Contains(graphql, "name: String!") {
+		t.Errorf("Expected the annotations to make name required, got:\n%s", graphql)
+	}
+}
+
+func TestSnapshot_InvalidSchema(t *testing.T) {
+	dir := writeCase(t, "type User {\n    owner: Missing = 1\n}\n", nil)
+
+	rec := &recorder{TB: t}
+	Snapshot(rec, dir, Options{})
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "Missing") {
+		t.Errorf("Expected a validation error, got: %v", rec.errors)
+	}
+}
+
+// upperGenerator is a custom generator that upper-cases type names
+type upperGenerator struct{}
+
+func (g *upperGenerator) Generate(schema *typemux.Schema) (string, error) {
+	var sb strings.Builder
+	for _, typ := range schema.Types {
+		sb.WriteString(strings.ToUpper(typ.Name) + "\n")
+	}
+	return sb.String(), nil
+}
+
+func (g *upperGenerator) Format() string        { return "upper" }
+func (g *upperGenerator) FileExtension() string { return ".txt" }
+
+func TestRun_CustomFactory(t *testing.T) {
+	dir := t.TempDir()
+	caseDir := filepath.Join(dir, "users")
+	if err := os.MkdirAll(filepath.Join(caseDir, ExpectedDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(caseDir, SchemaFile), []byte(userSchema), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(caseDir, ExpectedDir, "upper.txt"), []byte("USER\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Directories without a schema are not cases
+	if err := os.MkdirAll(filepath.Join(dir, "shared"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	factory := typemux.NewGeneratorFactory()
+	factory.Register(&upperGenerator{})
+	Run(t, dir, Options{Factory: factory, Formats: []string{"upper"}})
+}
+
+func TestFileName(t *testing.T) {
+	if got := FileName(&upperGenerator{}); got != "upper.txt" {
+		t.Errorf("FileName() = %q, want upper.txt", got)
+	}
+}